@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/llm"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// streamer is implemented by clients that can stream completions
+type streamer interface {
+	StreamComplete(ctx context.Context, req ports.CompletionRequest) (<-chan ports.CompletionChunk, error)
+}
+
+// benchConfig describes a load test
+type benchConfig struct {
+	// RPS is the target request rate. Zero runs closed-loop: each of the
+	// Concurrency workers sends its next request as soon as the previous
+	// one completes.
+	RPS         float64
+	Concurrency int
+	Duration    time.Duration
+	Requests    int // Stop after this many requests, if set
+	Timeout     time.Duration
+	Stream      bool
+	Request     *domain.LLMRequest
+}
+
+// result is the outcome of a single request
+type result struct {
+	Latency          time.Duration
+	TTFT             time.Duration // Time to the first chunk, for streams
+	PromptTokens     int
+	CompletionTokens int
+	Err              error
+}
+
+// run drives client with the load described by cfg until the duration
+// elapses, the request count is reached or ctx is cancelled, and returns
+// every request's result. Requests in flight when the duration elapses are
+// waited for; cancelling ctx cuts them. In open-loop mode, ticks finding all
+// workers busy are counted as dropped: the provider can't keep up with the
+// target rate at this concurrency.
+func run(ctx context.Context, client ports.LLMClient, cfg benchConfig) (results []result, dropped int, elapsed time.Duration) {
+	stop := ctx
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		stop, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		mu   sync.Mutex
+		sent int
+	)
+	// next reserves a request, reporting false once the count is reached
+	next := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if cfg.Requests > 0 && sent >= cfg.Requests {
+			return false
+		}
+		sent++
+		return true
+	}
+	record := func(r result) {
+		mu.Lock()
+		results = append(results, r)
+		mu.Unlock()
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+
+	if cfg.RPS <= 0 {
+		for i := 0; i < cfg.Concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for stop.Err() == nil && next() {
+					record(do(ctx, client, cfg))
+				}
+			}()
+		}
+		wg.Wait()
+		return results, 0, time.Since(start)
+	}
+
+	slots := make(chan struct{}, cfg.Concurrency)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.RPS))
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-stop.Done():
+			break loop
+		case <-ticker.C:
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			mu.Lock()
+			dropped++
+			mu.Unlock()
+			continue
+		}
+		if !next() {
+			<-slots
+			break loop
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			record(do(ctx, client, cfg))
+		}()
+	}
+	wg.Wait()
+
+	return results, dropped, time.Since(start)
+}
+
+// do sends a single request, bounded by cfg.Timeout. Completions go through
+// llm.Generate, which every adapter supports; streams through
+// StreamComplete.
+func do(ctx context.Context, client ports.LLMClient, cfg benchConfig) result {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	if !cfg.Stream {
+		resp, err := llm.Generate(ctx, client, cfg.Request)
+		r := result{Latency: time.Since(start), Err: err}
+		if err != nil {
+			return r
+		}
+		r.TTFT = r.Latency
+		r.PromptTokens = resp.Usage.InputTokens
+		r.CompletionTokens = resp.Usage.OutputTokens
+		return r
+	}
+
+	chunks, err := client.(streamer).StreamComplete(ctx, completionRequest(cfg.Request))
+	if err != nil {
+		return result{Latency: time.Since(start), Err: err}
+	}
+
+	var r result
+	final := false
+	for chunk := range chunks {
+		if r.TTFT == 0 {
+			r.TTFT = time.Since(start)
+		}
+		final = final || chunk.IsFinal
+	}
+	r.Latency = time.Since(start)
+	if !final {
+		r.Err = errStreamCut
+		if ctx.Err() != nil {
+			r.Err = ctx.Err()
+		}
+	}
+	return r
+}
+
+// completionRequest converts req for StreamComplete, the system prompt
+// first
+func completionRequest(req *domain.LLMRequest) ports.CompletionRequest {
+	converted := ports.CompletionRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}
+	if req.System != "" {
+		converted.Messages = append(converted.Messages, ports.Message{Role: "system", Content: req.System})
+	}
+	for _, msg := range req.Messages {
+		converted.Messages = append(converted.Messages, ports.Message{Role: msg.Role, Content: msg.Content})
+	}
+	return converted
+}
+
+// errStreamCut reports a stream closed without a final chunk
+var errStreamCut = errors.New("stream ended without a final chunk")
+
+// errorClass groups errors for the report: timeouts, rate limits and
+// everything else by message
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, errStreamCut):
+		return "stream cut"
+	case strings.Contains(err.Error(), "429"), strings.Contains(strings.ToLower(err.Error()), "rate limit"):
+		return "rate limited"
+	default:
+		msg := err.Error()
+		if len(msg) > 80 {
+			msg = msg[:80] + "..."
+		}
+		return msg
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/chaos"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// sleepyClient answers after a delay, streaming three chunks
+type sleepyClient struct {
+	ports.LLMClient
+	delay time.Duration
+}
+
+func (c *sleepyClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &domain.LLMResponse{
+		Content: "ok",
+		Usage:   domain.Usage{InputTokens: 10, OutputTokens: 5},
+	}, nil
+}
+
+func (c *sleepyClient) StreamComplete(ctx context.Context, req ports.CompletionRequest) (<-chan ports.CompletionChunk, error) {
+	chunks := make(chan ports.CompletionChunk)
+	go func() {
+		defer close(chunks)
+		for _, chunk := range []ports.CompletionChunk{{Delta: "o"}, {Delta: "k"}, {IsFinal: true}} {
+			time.Sleep(c.delay / 3)
+			chunks <- chunk
+		}
+	}()
+	return chunks, nil
+}
+
+var benchReq = &domain.LLMRequest{Model: "test-model", Messages: []domain.Message{{Role: "user", Content: "hi"}}}
+
+func TestRunClosedLoop(t *testing.T) {
+	client := &sleepyClient{delay: time.Millisecond}
+
+	results, dropped, _ := run(context.Background(), client, benchConfig{
+		Concurrency: 4,
+		Requests:    20,
+		Request:     benchReq,
+	})
+	if len(results) != 20 || dropped != 0 {
+		t.Fatalf("run() = %d results, %d dropped, want 20 and 0", len(results), dropped)
+	}
+
+	r := summarize(results, dropped, time.Second)
+	if r.Errors != 0 || r.CompletionTokens != 100 || r.PromptTokens != 200 {
+		t.Errorf("summarize() = %+v, want no errors, 200 prompt and 100 completion tokens", r)
+	}
+	if r.Latency.P50 < time.Millisecond {
+		t.Errorf("p50 latency = %v, want at least 1ms", r.Latency.P50)
+	}
+}
+
+func TestRunOpenLoop(t *testing.T) {
+	client := &sleepyClient{delay: time.Millisecond}
+
+	results, _, elapsed := run(context.Background(), client, benchConfig{
+		RPS:         100,
+		Concurrency: 2,
+		Duration:    200 * time.Millisecond,
+		Request:     benchReq,
+	})
+	// The ticker sends about 20 requests; allow for slow CI machines
+	if len(results) < 5 || len(results) > 21 {
+		t.Errorf("run() sent %d requests in %v at 100 rps, want about 20", len(results), elapsed)
+	}
+}
+
+func TestRunOpenLoopDropsWhenSaturated(t *testing.T) {
+	client := &sleepyClient{delay: 100 * time.Millisecond}
+
+	_, dropped, _ := run(context.Background(), client, benchConfig{
+		RPS:         200,
+		Concurrency: 1,
+		Duration:    100 * time.Millisecond,
+		Request:     benchReq,
+	})
+	if dropped == 0 {
+		t.Error("run() dropped no ticks, want drops with a single busy worker")
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	client := &sleepyClient{delay: 30 * time.Millisecond}
+
+	results, _, elapsed := run(context.Background(), client, benchConfig{
+		Concurrency: 1,
+		Requests:    3,
+		Stream:      true,
+		Request:     benchReq,
+	})
+
+	r := summarize(results, 0, elapsed)
+	if r.Errors != 0 {
+		t.Fatalf("summarize() errors = %v", r.ErrorsByClass)
+	}
+	if r.TTFT.P50 <= 0 || r.TTFT.P50 >= r.Latency.P50 {
+		t.Errorf("p50 TTFT = %v, latency = %v, want 0 < TTFT < latency", r.TTFT.P50, r.Latency.P50)
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	tests := []struct {
+		faults chaos.Faults
+		stream bool
+		class  string
+	}{
+		{chaos.Faults{RateLimitRate: 1}, false, "rate limited"},
+		{chaos.Faults{TimeoutRate: 1, Timeout: time.Millisecond}, false, "timeout"},
+		{chaos.Faults{PartialStreamRate: 1}, true, "stream cut"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			client := chaos.NewLLMClient(&sleepyClient{}, tt.faults)
+
+			results, _, elapsed := run(context.Background(), client, benchConfig{
+				Concurrency: 1,
+				Requests:    3,
+				Stream:      tt.stream,
+				Request:     benchReq,
+			})
+
+			r := summarize(results, 0, elapsed)
+			if r.ErrorRate != 1 || r.ErrorsByClass[tt.class] != 3 {
+				t.Errorf("errors = %v (rate %v), want 3 %q", r.ErrorsByClass, r.ErrorRate, tt.class)
+			}
+		})
+	}
+}
+
+func TestComputePercentiles(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	p := computePercentiles(durations)
+	want := percentiles{
+		Min: time.Millisecond, Mean: 50500 * time.Microsecond,
+		P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P95: 95 * time.Millisecond,
+		P99: 99 * time.Millisecond, Max: 100 * time.Millisecond,
+	}
+	if p != want {
+		t.Errorf("computePercentiles() = %+v, want %+v", p, want)
+	}
+
+	if p := computePercentiles(nil); p != (percentiles{}) {
+		t.Errorf("computePercentiles(nil) = %+v, want zero", p)
+	}
+}
+
+func TestRunMain(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+
+	var out bytes.Buffer
+	err := runMain([]string{
+		"-provider", "openai", "-base-url", srv.BaseURL(), "-api-key", "test-key",
+		"-model", "gpt-4o-mini", "-concurrency", "2", "-requests", "6", "-json",
+	}, &out)
+	if err != nil {
+		t.Fatalf("runMain() error = %v", err)
+	}
+
+	var r report
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+		t.Fatalf("invalid JSON report: %v\n%s", err, out.String())
+	}
+	if r.Requests != 6 || r.Errors != 0 || r.Model != "gpt-4o-mini" {
+		t.Errorf("report = %+v, want 6 successful gpt-4o-mini requests", r)
+	}
+	if len(srv.Requests()) != 6 {
+		t.Errorf("server received %d requests, want 6", len(srv.Requests()))
+	}
+}
+
+func TestRunMainRejectsUnboundedRuns(t *testing.T) {
+	err := runMain([]string{"-provider", "ollama", "-duration", "0"}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "-requests") {
+		t.Errorf("runMain() error = %v, want a request about -duration or -requests", err)
+	}
+}
@@ -0,0 +1,149 @@
+// Command dago-llm-bench load-tests an LLM provider through the adapters of
+// this module, at a target request rate or closed-loop at a fixed
+// concurrency, and reports latency percentiles, time to first token, error
+// rates and token throughput. Use it to compare providers and models, and to
+// check timeouts, rate limits and proxies before a rollout.
+//
+// Usage:
+//
+//	dago-llm-bench -provider openai -model gpt-4o-mini -rps 5 -duration 1m
+//	dago-llm-bench -provider ollama -concurrency 8 -requests 200 -stream
+//
+// API keys are read from -api-key or the provider's environment variable,
+// e.g. ANTHROPIC_API_KEY.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/llm"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	"go.uber.org/zap"
+)
+
+// Environment variables holding API keys, by provider
+var apiKeyEnv = map[string]string{
+	"anthropic": "ANTHROPIC_API_KEY", "claude": "ANTHROPIC_API_KEY",
+	"openai": "OPENAI_API_KEY", "gpt": "OPENAI_API_KEY",
+	"azure":  "AZURE_OPENAI_API_KEY",
+	"gemini": "GEMINI_API_KEY", "google": "GEMINI_API_KEY",
+	"mistral":  "MISTRAL_API_KEY",
+	"cohere":   "COHERE_API_KEY",
+	"groq":     "GROQ_API_KEY",
+	"deepseek": "DEEPSEEK_API_KEY",
+}
+
+func main() {
+	if err := runMain(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "dago-llm-bench: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runMain(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("dago-llm-bench", flag.ContinueOnError)
+	provider := flags.String("provider", "openai", "LLM provider ("+strings.Join(llm.ListSupportedProviders(), ", ")+")")
+	model := flags.String("model", "", "model (default: the provider's default model)")
+	baseURL := flags.String("base-url", "", "provider base URL, e.g. for Ollama, proxies or OpenAI-compatible servers")
+	apiKey := flags.String("api-key", "", "API key (default: the provider's environment variable)")
+	rps := flags.Float64("rps", 0, "target requests per second; 0 runs closed-loop at -concurrency")
+	concurrency := flags.Int("concurrency", 1, "maximum requests in flight")
+	duration := flags.Duration("duration", 30*time.Second, "how long to send requests; 0 runs until -requests are sent")
+	requests := flags.Int("requests", 0, "stop after this many requests; 0 means no limit")
+	timeout := flags.Duration("timeout", time.Minute, "timeout of each request")
+	prompt := flags.String("prompt", "Write a haiku about load testing.", "user message sent in every request")
+	system := flags.String("system", "", "system message sent in every request")
+	maxTokens := flags.Int("max-tokens", 128, "maximum completion tokens")
+	temperature := flags.Float64("temperature", 0, "sampling temperature")
+	stream := flags.Bool("stream", false, "stream completions, measuring time to first token")
+	jsonOutput := flags.Bool("json", false, "print the report as JSON")
+	verbose := flags.Bool("v", false, "log adapter activity")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *concurrency < 1 {
+		return fmt.Errorf("-concurrency must be at least 1")
+	}
+	if *duration == 0 && *requests == 0 {
+		return fmt.Errorf("set -duration or -requests")
+	}
+
+	logger := zap.NewNop()
+	if *verbose {
+		var err error
+		if logger, err = zap.NewDevelopment(); err != nil {
+			return err
+		}
+		defer func() { _ = logger.Sync() }()
+	}
+
+	key := *apiKey
+	if key == "" {
+		key = os.Getenv(apiKeyEnv[*provider])
+	}
+	url := *baseURL
+	if url == "" && (*provider == "ollama" || *provider == "local") {
+		url = os.Getenv("OLLAMA_BASE_URL")
+	}
+
+	client, err := llm.NewClient(&llm.Config{
+		Provider: *provider,
+		APIKey:   key,
+		BaseURL:  url,
+		Logger:   logger,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if *stream {
+		if _, ok := client.(streamer); !ok {
+			return fmt.Errorf("provider %s doesn't support streaming", *provider)
+		}
+	}
+
+	if *model == "" {
+		*model = llm.GetDefaultModel(*provider)
+	}
+	req := &domain.LLMRequest{
+		Model:       *model,
+		System:      *system,
+		Messages:    []domain.Message{{Role: "user", Content: *prompt}},
+		MaxTokens:   *maxTokens,
+		Temperature: *temperature,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results, dropped, elapsed := run(ctx, client, benchConfig{
+		RPS:         *rps,
+		Concurrency: *concurrency,
+		Duration:    *duration,
+		Requests:    *requests,
+		Timeout:     *timeout,
+		Stream:      *stream,
+		Request:     req,
+	})
+
+	r := summarize(results, dropped, elapsed)
+	r.Provider = *provider
+	r.Model = *model
+	r.Stream = *stream
+	r.TargetRPS = *rps
+	r.Concurrency = *concurrency
+
+	if *jsonOutput {
+		return r.writeJSON(stdout)
+	}
+	r.writeText(stdout)
+	return nil
+}
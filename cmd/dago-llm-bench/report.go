@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// report summarizes a benchmark run
+type report struct {
+	Provider    string  `json:"provider"`
+	Model       string  `json:"model"`
+	Stream      bool    `json:"stream"`
+	TargetRPS   float64 `json:"target_rps,omitempty"`
+	Concurrency int     `json:"concurrency"`
+
+	Elapsed    time.Duration `json:"elapsed_ns"`
+	Requests   int           `json:"requests"`
+	Errors     int           `json:"errors"`
+	Dropped    int           `json:"dropped,omitempty"`
+	ErrorRate  float64       `json:"error_rate"`
+	Throughput float64       `json:"throughput_rps"`
+
+	// Latency and TTFT percentiles of successful requests
+	Latency percentiles `json:"latency"`
+	TTFT    percentiles `json:"ttft"`
+
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TokensPerSecond  float64 `json:"completion_tokens_per_second"`
+
+	ErrorsByClass map[string]int `json:"errors_by_class,omitempty"`
+}
+
+// percentiles of a duration distribution
+type percentiles struct {
+	Min  time.Duration `json:"min_ns"`
+	Mean time.Duration `json:"mean_ns"`
+	P50  time.Duration `json:"p50_ns"`
+	P90  time.Duration `json:"p90_ns"`
+	P95  time.Duration `json:"p95_ns"`
+	P99  time.Duration `json:"p99_ns"`
+	Max  time.Duration `json:"max_ns"`
+}
+
+// summarize builds the report of a run
+func summarize(results []result, dropped int, elapsed time.Duration) report {
+	r := report{
+		Elapsed:  elapsed,
+		Requests: len(results),
+		Dropped:  dropped,
+	}
+
+	var latencies, ttfts []time.Duration
+	for _, res := range results {
+		if res.Err != nil {
+			r.Errors++
+			if r.ErrorsByClass == nil {
+				r.ErrorsByClass = make(map[string]int)
+			}
+			r.ErrorsByClass[errorClass(res.Err)]++
+			continue
+		}
+		latencies = append(latencies, res.Latency)
+		if res.TTFT > 0 {
+			ttfts = append(ttfts, res.TTFT)
+		}
+		r.PromptTokens += res.PromptTokens
+		r.CompletionTokens += res.CompletionTokens
+	}
+
+	if r.Requests > 0 {
+		r.ErrorRate = float64(r.Errors) / float64(r.Requests)
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		r.Throughput = float64(len(latencies)) / seconds
+		r.TokensPerSecond = float64(r.CompletionTokens) / seconds
+	}
+	r.Latency = computePercentiles(latencies)
+	r.TTFT = computePercentiles(ttfts)
+
+	return r
+}
+
+// computePercentiles returns the nearest-rank percentiles of durations
+func computePercentiles(durations []time.Duration) percentiles {
+	if len(durations) == 0 {
+		return percentiles{}
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	rank := func(p float64) time.Duration {
+		i := int(p*float64(len(sorted))+0.5) - 1
+		return sorted[min(max(i, 0), len(sorted)-1)]
+	}
+
+	return percentiles{
+		Min:  sorted[0],
+		Mean: total / time.Duration(len(sorted)),
+		P50:  rank(0.50),
+		P90:  rank(0.90),
+		P95:  rank(0.95),
+		P99:  rank(0.99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// writeJSON writes the report as indented JSON
+func (r report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// writeText writes the report for humans
+func (r report) writeText(w io.Writer) {
+	mode := "closed-loop"
+	if r.TargetRPS > 0 {
+		mode = fmt.Sprintf("%.1f rps target", r.TargetRPS)
+	}
+	fmt.Fprintf(w, "%s / %s, %s, concurrency %d", r.Provider, r.Model, mode, r.Concurrency)
+	if r.Stream {
+		fmt.Fprint(w, ", streaming")
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "\nrequests:    %d in %s (%.2f rps successful)\n", r.Requests, r.Elapsed.Round(time.Millisecond), r.Throughput)
+	fmt.Fprintf(w, "errors:      %d (%.1f%%)\n", r.Errors, 100*r.ErrorRate)
+	if r.Dropped > 0 {
+		fmt.Fprintf(w, "dropped:     %d ticks with all %d workers busy\n", r.Dropped, r.Concurrency)
+	}
+
+	printPercentiles(w, "latency:", r.Latency)
+	if r.Stream {
+		printPercentiles(w, "ttft:", r.TTFT)
+	}
+
+	if r.PromptTokens > 0 || r.CompletionTokens > 0 {
+		fmt.Fprintf(w, "tokens:      %d prompt, %d completion (%.1f completion tokens/s)\n",
+			r.PromptTokens, r.CompletionTokens, r.TokensPerSecond)
+	}
+
+	if len(r.ErrorsByClass) > 0 {
+		classes := make([]string, 0, len(r.ErrorsByClass))
+		for class := range r.ErrorsByClass {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		sort.SliceStable(classes, func(i, j int) bool {
+			return r.ErrorsByClass[classes[i]] > r.ErrorsByClass[classes[j]]
+		})
+
+		fmt.Fprintln(w, "\nerrors by class:")
+		for _, class := range classes {
+			fmt.Fprintf(w, "  %6d  %s\n", r.ErrorsByClass[class], class)
+		}
+	}
+}
+
+func printPercentiles(w io.Writer, label string, p percentiles) {
+	round := func(d time.Duration) time.Duration { return d.Round(time.Millisecond) }
+	fmt.Fprintf(w, "%-12s min %s  mean %s  p50 %s  p90 %s  p95 %s  p99 %s  max %s\n", label,
+		round(p.Min), round(p.Mean), round(p.P50), round(p.P90), round(p.P95), round(p.P99), round(p.Max))
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/embeddings"
+	"github.com/aescanero/dago-adapters/pkg/llm"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// streamer is implemented by clients that can stream completions
+type streamer interface {
+	StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error)
+}
+
+// Number of vector components printed by embed without -json
+const previewDimensions = 4
+
+// llmSession is the LLM client of a command and its settings
+type llmSession struct {
+	client  libports.LLMClient
+	cfg     clientConfig
+	timeout time.Duration
+	stream  bool
+}
+
+// newLLMSession loads the config file and creates the LLM client. The
+// returned function flushes the logger.
+func newLLMSession(opts *options, stream bool) (*llmSession, func(), error) {
+	file, err := loadConfig(opts.config)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger, flush, err := opts.logger()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, cfg, err := newLLMClient(file, opts, logger)
+	if err != nil {
+		flush()
+		return nil, nil, err
+	}
+	if _, ok := client.(streamer); stream && !ok {
+		flush()
+		return nil, nil, fmt.Errorf("provider %s doesn't support streaming", cfg.Provider)
+	}
+	return &llmSession{client: client, cfg: cfg, timeout: opts.callTimeout(cfg), stream: stream}, flush, nil
+}
+
+// generate writes the reply to req to w, streamed if the session streams,
+// and returns it. Usage is only reported without streaming.
+func (s *llmSession) generate(ctx context.Context, req *domain.LLMRequest, w io.Writer) (*domain.LLMResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	req.Model = s.cfg.Model
+
+	if s.stream {
+		chunks, err := s.client.(streamer).StreamComplete(ctx, completionRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		var content strings.Builder
+		_, err = llm.WriteStream(ctx, io.MultiWriter(w, &content), chunks)
+		fmt.Fprintln(w)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LLMResponse{Content: content.String(), Model: req.Model}, nil
+	}
+
+	resp, err := llm.Generate(ctx, s.client, req)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(w, resp.Content)
+	return resp, nil
+}
+
+// completionRequest converts req for StreamComplete, the system prompt
+// first
+func completionRequest(req *domain.LLMRequest) libports.CompletionRequest {
+	converted := libports.CompletionRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}
+	if req.System != "" {
+		converted.Messages = append(converted.Messages, libports.Message{Role: "system", Content: req.System})
+	}
+	for _, msg := range req.Messages {
+		converted.Messages = append(converted.Messages, libports.Message{Role: msg.Role, Content: msg.Content})
+	}
+	return converted
+}
+
+// interruptible returns a context cancelled on Ctrl-C
+func interruptible() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+func runComplete(env *environment, args []string) error {
+	var opts options
+	flags := newFlagSet(env, "complete", &opts)
+	system := flags.String("system", "", "system prompt")
+	maxTokens := flags.Int("max-tokens", 1024, "maximum completion tokens")
+	temperature := flags.Float64("temperature", 0, "sampling temperature")
+	stream := flags.Bool("stream", false, "stream the completion, for providers supporting it")
+	jsonOutput := flags.Bool("json", false, "print the response as JSON, with its token usage")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *stream && *jsonOutput {
+		return fmt.Errorf("-stream and -json can't be combined")
+	}
+
+	prompt := strings.Join(flags.Args(), " ")
+	if prompt == "" {
+		data, err := io.ReadAll(env.stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read the prompt: %w", err)
+		}
+		prompt = strings.TrimSpace(string(data))
+	}
+	if prompt == "" {
+		return fmt.Errorf("no prompt given, as arguments or on stdin")
+	}
+
+	session, flush, err := newLLMSession(&opts, *stream)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	ctx, stop := interruptible()
+	defer stop()
+
+	req := &domain.LLMRequest{
+		System:      *system,
+		Messages:    []domain.Message{{Role: "user", Content: prompt}},
+		MaxTokens:   *maxTokens,
+		Temperature: *temperature,
+	}
+	out := env.stdout
+	if *jsonOutput {
+		out = io.Discard
+	}
+	resp, err := session.generate(ctx, req, out)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		return writeJSON(env.stdout, resp)
+	}
+	return nil
+}
+
+func runChat(env *environment, args []string) error {
+	var opts options
+	flags := newFlagSet(env, "chat", &opts)
+	system := flags.String("system", "", "system prompt")
+	maxTokens := flags.Int("max-tokens", 1024, "maximum completion tokens per reply")
+	temperature := flags.Float64("temperature", 0.7, "sampling temperature")
+	stream := flags.Bool("stream", false, "stream replies, for providers supporting it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("chat takes no arguments, type messages on stdin")
+	}
+
+	session, flush, err := newLLMSession(&opts, *stream)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	ctx, stop := interruptible()
+	defer stop()
+
+	fmt.Fprintf(env.stderr, "Chatting with %s. /reset starts over, /exit or Ctrl-D ends.\n", session.cfg.name())
+	var history []domain.Message
+	lines := bufio.NewScanner(env.stdin)
+	lines.Buffer(make([]byte, 64*1024), 1024*1024)
+	for {
+		fmt.Fprint(env.stderr, "> ")
+		if !lines.Scan() {
+			fmt.Fprintln(env.stderr)
+			return lines.Err()
+		}
+
+		line := strings.TrimSpace(lines.Text())
+		switch line {
+		case "":
+			continue
+		case "/exit", "/quit":
+			return nil
+		case "/reset":
+			history = nil
+			fmt.Fprintln(env.stderr, "Conversation cleared.")
+			continue
+		}
+
+		req := &domain.LLMRequest{
+			System:      *system,
+			Messages:    append(history, domain.Message{Role: "user", Content: line}),
+			MaxTokens:   *maxTokens,
+			Temperature: *temperature,
+		}
+		resp, err := session.generate(ctx, req, env.stdout)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// The message is dropped, so it can be sent again
+			fmt.Fprintf(env.stderr, "error: %v\n", err)
+			continue
+		}
+		history = append(req.Messages, domain.Message{Role: "assistant", Content: resp.Content})
+	}
+}
+
+func runEmbed(env *environment, args []string) error {
+	var opts options
+	flags := newFlagSet(env, "embed", &opts)
+	inputType := flags.String("input-type", "", "purpose of the texts for retrieval models: document or query")
+	jsonOutput := flags.Bool("json", false, "print the full vectors as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch ports.EmbeddingInputType(*inputType) {
+	case ports.EmbeddingInputNone, ports.EmbeddingInputDocument, ports.EmbeddingInputQuery:
+	default:
+		return fmt.Errorf("-input-type must be document or query")
+	}
+
+	texts := flags.Args()
+	if len(texts) == 0 {
+		lines := bufio.NewScanner(env.stdin)
+		lines.Buffer(make([]byte, 64*1024), 1024*1024)
+		for lines.Scan() {
+			if text := strings.TrimSpace(lines.Text()); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		if err := lines.Err(); err != nil {
+			return fmt.Errorf("failed to read texts: %w", err)
+		}
+	}
+	if len(texts) == 0 {
+		return fmt.Errorf("no texts given, as arguments or stdin lines")
+	}
+
+	file, err := loadConfig(opts.config)
+	if err != nil {
+		return err
+	}
+	logger, flush, err := opts.logger()
+	if err != nil {
+		return err
+	}
+	defer flush()
+	embedder, cfg, err := newEmbedder(file, &opts, logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptible()
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.callTimeout(cfg))
+	defer cancel()
+
+	resp, err := embedder.Embed(ctx, ports.EmbeddingRequest{
+		Texts:     texts,
+		Model:     cfg.Model,
+		InputType: ports.EmbeddingInputType(*inputType),
+	})
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		return writeJSON(env.stdout, resp)
+	}
+
+	for i, vector := range resp.Embeddings {
+		preview := vector
+		if len(preview) > previewDimensions {
+			preview = preview[:previewDimensions]
+		}
+		values := make([]string, len(preview))
+		for j, v := range preview {
+			values[j] = fmt.Sprintf("%.4f", v)
+		}
+		more := ""
+		if len(vector) > len(preview) {
+			more = " ..."
+		}
+		fmt.Fprintf(env.stdout, "%d\t%d dimensions\t[%s%s]\n", i, len(vector), strings.Join(values, " "), more)
+	}
+	fmt.Fprintf(env.stderr, "%s, %d tokens\n", resp.Model, resp.Usage.TotalTokens)
+	return nil
+}
+
+func runModels(env *environment, args []string) error {
+	var opts options
+	flags := newFlagSet(env, "models", &opts)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	file, err := loadConfig(opts.config)
+	if err != nil {
+		return err
+	}
+
+	printProviders := func(title string, providers []string, defaultModel func(string) string, configured clientConfig) {
+		fmt.Fprintln(env.stdout, title)
+		for _, provider := range providers {
+			line := fmt.Sprintf("  %-12s %s", provider, defaultModel(provider))
+			if provider == configured.Provider {
+				line += "  (configured"
+				if configured.Model != "" {
+					line += ": " + configured.Model
+				}
+				line += ")"
+			}
+			fmt.Fprintln(env.stdout, strings.TrimRight(line, " "))
+		}
+	}
+	printProviders("LLM providers and default models:", llm.ListSupportedProviders(), llm.GetDefaultModel, file.LLM)
+	fmt.Fprintln(env.stdout)
+	printProviders("Embeddings providers and default models:", embeddings.ListSupportedProviders(), embeddings.GetDefaultModel, file.Embeddings)
+	return nil
+}
+
+func runPing(env *environment, args []string) error {
+	var opts options
+	flags := newFlagSet(env, "ping", &opts)
+	withEmbeddings := flags.Bool("embeddings", false, "also ping the embeddings provider (default: when the config file has an embeddings section)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	file, err := loadConfig(opts.config)
+	if err != nil {
+		return err
+	}
+	logger, flush, err := opts.logger()
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	ctx, stop := interruptible()
+	defer stop()
+
+	failed, total := 0, 0
+	report := func(kind string, cfg clientConfig, elapsed time.Duration, detail string, err error) {
+		total++
+		if err != nil {
+			failed++
+			fmt.Fprintf(env.stdout, "%-10s %s\tFAILED\t%v\n", kind, cfg.name(), err)
+			return
+		}
+		fmt.Fprintf(env.stdout, "%-10s %s\tok\t%s\t%s\n", kind, cfg.name(), elapsed.Round(time.Millisecond), detail)
+	}
+
+	client, cfg, err := newLLMClient(file, &opts, logger)
+	if err != nil {
+		report("llm", cfg, 0, "", err)
+	} else {
+		callCtx, cancel := context.WithTimeout(ctx, opts.callTimeout(cfg))
+		start := time.Now()
+		resp, err := llm.Generate(callCtx, client, &domain.LLMRequest{
+			Model:     cfg.Model,
+			Messages:  []domain.Message{{Role: "user", Content: "Reply with the single word: pong"}},
+			MaxTokens: 16,
+		})
+		elapsed := time.Since(start)
+		cancel()
+
+		detail := ""
+		if err == nil {
+			detail = fmt.Sprintf("%d tokens", resp.Usage.InputTokens+resp.Usage.OutputTokens)
+		}
+		report("llm", cfg, elapsed, detail, err)
+	}
+
+	if *withEmbeddings || file.Embeddings.Provider != "" {
+		// Provider flags are for the LLM, the embeddings client is the
+		// config file's
+		embeddingsOpts := options{timeout: opts.timeout}
+		embedder, cfg, err := newEmbedder(file, &embeddingsOpts, logger)
+		if err != nil {
+			report("embeddings", cfg, 0, "", err)
+		} else {
+			callCtx, cancel := context.WithTimeout(ctx, embeddingsOpts.callTimeout(cfg))
+			start := time.Now()
+			resp, err := embedder.Embed(callCtx, ports.EmbeddingRequest{Texts: []string{"ping"}, Model: cfg.Model})
+			elapsed := time.Since(start)
+			cancel()
+
+			detail := ""
+			if err == nil && len(resp.Embeddings) > 0 {
+				detail = fmt.Sprintf("%d dimensions", len(resp.Embeddings[0]))
+			}
+			report("embeddings", cfg, elapsed, detail, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, total)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
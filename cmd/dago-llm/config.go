@@ -0,0 +1,214 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/embeddings"
+	"github.com/aescanero/dago-adapters/pkg/llm"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+)
+
+// Environment variable naming the config file when -config isn't set
+const configEnv = "DAGO_LLM_CONFIG"
+
+// Environment variables holding API keys, by provider
+var apiKeyEnv = map[string]string{
+	"anthropic": "ANTHROPIC_API_KEY", "claude": "ANTHROPIC_API_KEY",
+	"openai": "OPENAI_API_KEY", "gpt": "OPENAI_API_KEY",
+	"azure":  "AZURE_OPENAI_API_KEY",
+	"gemini": "GEMINI_API_KEY", "google": "GEMINI_API_KEY",
+	"mistral":  "MISTRAL_API_KEY",
+	"cohere":   "COHERE_API_KEY",
+	"groq":     "GROQ_API_KEY",
+	"deepseek": "DEEPSEEK_API_KEY",
+	"voyage":   "VOYAGE_API_KEY", "voyageai": "VOYAGE_API_KEY",
+}
+
+// fileConfig is the config file, e.g.
+//
+//	llm:
+//	  provider: anthropic
+//	  model: claude-sonnet-4-20250514
+//	  timeout: 60
+//	embeddings:
+//	  provider: voyage
+//	  api_key_env: VOYAGE_KEY_STAGING
+//
+// The llm section has the keys of llm.ReloadConfig, the settings NewClient
+// reads from a config store. API keys don't belong in the file: they are
+// read from api_key_env, or the provider's usual environment variable.
+type fileConfig struct {
+	LLM        clientConfig `json:"llm"`
+	Embeddings clientConfig `json:"embeddings"`
+}
+
+// clientConfig configures an LLM or embeddings client
+type clientConfig struct {
+	llm.ReloadConfig
+
+	// Model defaults to the provider's default model
+	Model string `json:"model,omitempty"`
+
+	// APIKeyEnv names the environment variable holding the API key
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+
+	apiKey string
+}
+
+// loadConfig reads the config file at path, or returns an empty config if
+// path is empty
+func loadConfig(path string) (*fileConfig, error) {
+	cfg := &fileConfig{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// options are the flags shared by all commands, overriding the config file
+type options struct {
+	config   string
+	provider string
+	model    string
+	baseURL  string
+	apiKey   string
+	timeout  time.Duration
+	verbose  bool
+}
+
+func (o *options) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.config, "config", os.Getenv(configEnv), "config file (default $"+configEnv+")")
+	flags.StringVar(&o.provider, "provider", "", "provider, overriding the config file")
+	flags.StringVar(&o.model, "model", "", "model, overriding the config file (default: the provider's default model)")
+	flags.StringVar(&o.baseURL, "base-url", "", "provider base URL, e.g. for Ollama, proxies or OpenAI-compatible servers")
+	flags.StringVar(&o.apiKey, "api-key", "", "API key (default: api_key_env or the provider's environment variable)")
+	flags.DurationVar(&o.timeout, "timeout", 0, "timeout of each call (default: the config file's, or 2m)")
+	flags.BoolVar(&o.verbose, "v", false, "log adapter activity")
+}
+
+// logger returns the logger of the adapters, and a function to flush it
+func (o *options) logger() (*zap.Logger, func(), error) {
+	if !o.verbose {
+		return zap.NewNop(), func() {}, nil
+	}
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		return nil, nil, err
+	}
+	return logger, func() { _ = logger.Sync() }, nil
+}
+
+// resolve applies the flags to section of the config file, falling back to
+// defaultProvider, and looks up the API key
+func (o *options) resolve(section clientConfig, defaultProvider string) (clientConfig, error) {
+	cfg := section
+	if o.provider != "" && o.provider != cfg.Provider {
+		// Settings of another provider don't apply
+		cfg = clientConfig{ReloadConfig: llm.ReloadConfig{Provider: o.provider}}
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = defaultProvider
+	}
+	if o.model != "" {
+		cfg.Model = o.model
+	}
+	if o.baseURL != "" {
+		cfg.BaseURL = o.baseURL
+	}
+	if cfg.BaseURL == "" && (cfg.Provider == "ollama" || cfg.Provider == "local") {
+		cfg.BaseURL = os.Getenv("OLLAMA_BASE_URL")
+	}
+	if cfg.APIKeySecret != "" {
+		return cfg, fmt.Errorf("api_key_secret needs a secret provider, which the CLI doesn't have; set api_key_env instead")
+	}
+
+	cfg.apiKey = o.apiKey
+	if cfg.apiKey == "" {
+		env := cfg.APIKeyEnv
+		if env == "" {
+			env = apiKeyEnv[cfg.Provider]
+		}
+		if env != "" {
+			cfg.apiKey = os.Getenv(env)
+		}
+	}
+	return cfg, nil
+}
+
+// callTimeout is the timeout of each call to cfg's provider
+func (o *options) callTimeout(cfg clientConfig) time.Duration {
+	switch {
+	case o.timeout > 0:
+		return o.timeout
+	case cfg.Timeout > 0:
+		return time.Duration(cfg.Timeout) * time.Second
+	default:
+		return 2 * time.Minute
+	}
+}
+
+// newLLMClient creates the LLM client of the config file and flags
+func newLLMClient(file *fileConfig, o *options, logger *zap.Logger) (libports.LLMClient, clientConfig, error) {
+	cfg, err := o.resolve(file.LLM, "openai")
+	if err != nil {
+		return nil, cfg, err
+	}
+	if cfg.Model == "" {
+		cfg.Model = llm.GetDefaultModel(cfg.Provider)
+	}
+
+	client, err := llm.NewClient(&llm.Config{
+		Provider: cfg.Provider,
+		APIKey:   cfg.apiKey,
+		BaseURL:  cfg.BaseURL,
+		Region:   cfg.Region,
+		Timeout:  cfg.Timeout,
+		Logger:   logger,
+	})
+	if err != nil {
+		return nil, cfg, fmt.Errorf("failed to create LLM client: %w", err)
+	}
+	return client, cfg, nil
+}
+
+// newEmbedder creates the embeddings client of the config file and flags
+func newEmbedder(file *fileConfig, o *options, logger *zap.Logger) (ports.Embedder, clientConfig, error) {
+	cfg, err := o.resolve(file.Embeddings, "openai")
+	if err != nil {
+		return nil, cfg, err
+	}
+	if cfg.Model == "" {
+		cfg.Model = embeddings.GetDefaultModel(cfg.Provider)
+	}
+
+	embedder, err := embeddings.NewClient(&embeddings.Config{
+		Provider: cfg.Provider,
+		APIKey:   cfg.apiKey,
+		BaseURL:  cfg.BaseURL,
+		Timeout:  cfg.Timeout,
+		Logger:   logger,
+	})
+	if err != nil {
+		return nil, cfg, fmt.Errorf("failed to create embeddings client: %w", err)
+	}
+	return embedder, cfg, nil
+}
+
+// name identifies cfg's provider and model in messages
+func (cfg clientConfig) name() string {
+	return strings.TrimSuffix(cfg.Provider+"/"+cfg.Model, "/")
+}
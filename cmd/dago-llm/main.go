@@ -0,0 +1,106 @@
+// Command dago-llm exercises the LLM and embeddings adapters of this module
+// from the command line, so a new provider, model or API key can be
+// smoke-tested without writing Go code.
+//
+// Usage:
+//
+//	dago-llm ping                                 check the configured providers answer
+//	dago-llm complete "Summarize RFC 2119"        one completion, prompt from args or stdin
+//	dago-llm chat -system "You are terse."        interactive conversation
+//	dago-llm embed "first text" "second text"     embeddings, texts from args or stdin lines
+//	dago-llm models                               providers and their default models
+//
+// Providers, models and timeouts are read from the YAML file given with
+// -config or $DAGO_LLM_CONFIG, and can be overridden with flags:
+//
+//	llm:
+//	  provider: anthropic
+//	  model: claude-sonnet-4-20250514
+//	embeddings:
+//	  provider: ollama
+//	  base_url: http://localhost:11434
+//
+// API keys are read from -api-key, the section's api_key_env, or the
+// provider's environment variable, e.g. ANTHROPIC_API_KEY.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand, run with its arguments
+type command struct {
+	summary string
+	run     func(env *environment, args []string) error
+}
+
+var commands = map[string]command{
+	"chat":     {"start an interactive conversation", runChat},
+	"complete": {"print the completion of a prompt", runComplete},
+	"embed":    {"print the embeddings of texts", runEmbed},
+	"models":   {"list providers and their default models", runModels},
+	"ping":     {"check the configured providers answer", runPing},
+}
+
+// environment holds the standard streams of a command, replaced in tests
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	env := &environment{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	if err := runMain(env, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "dago-llm: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func runMain(env *environment, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(env.stderr)
+		if len(args) == 0 {
+			return fmt.Errorf("no command given")
+		}
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		usage(env.stderr)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	return cmd.run(env, args[1:])
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: dago-llm <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run dago-llm <command> -h for the flags of a command.")
+}
+
+// newFlagSet returns the flags of command name, with the shared options
+func newFlagSet(env *environment, name string, opts *options) *flag.FlagSet {
+	flags := flag.NewFlagSet("dago-llm "+name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	opts.register(flags)
+	return flags
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/ollama/ollama/api"
+)
+
+// run runs the command line args with stdin, returning stdout, stderr and
+// the error
+func run(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	t.Setenv(configEnv, "")
+	var stdout, stderr bytes.Buffer
+	env := &environment{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}
+	err := runMain(env, args)
+	return stdout.String(), stderr.String(), err
+}
+
+// writeConfig writes a config file and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dago-llm.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// chatRequests decodes the chat requests received by srv
+func chatRequests(t *testing.T, srv *testutil.OllamaServer) []api.ChatRequest {
+	t.Helper()
+	var requests []api.ChatRequest
+	for _, r := range srv.Requests() {
+		if r.Path != "/api/chat" {
+			continue
+		}
+		var req api.ChatRequest
+		if err := json.Unmarshal(r.Body, &req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+func TestComplete(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	srv.ReplyChat(testutil.OllamaText("Paris"))
+
+	stdout, _, err := run(t, "", "complete", "-provider", "ollama", "-base-url", srv.URL, "-model", "qwen3", "-system", "Be brief.", "Capital", "of", "France?")
+	if err != nil {
+		t.Fatalf("complete error = %v", err)
+	}
+	if stdout != "Paris\n" {
+		t.Errorf("stdout = %q, want Paris", stdout)
+	}
+
+	req := srv.LastChatRequest()
+	if req.Model != "qwen3" {
+		t.Errorf("model = %q, want qwen3", req.Model)
+	}
+	if last := req.Messages[len(req.Messages)-1]; last.Content != "Capital of France?" {
+		t.Errorf("last message = %+v, want the prompt", last)
+	}
+	if req.Messages[0].Role != "system" || req.Messages[0].Content != "Be brief." {
+		t.Errorf("first message = %+v, want the system prompt", req.Messages[0])
+	}
+}
+
+func TestComplete_StdinAndJSON(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	srv.ReplyChat(testutil.OllamaReply{Chunks: []string{"Hello"}, PromptTokens: 7, OutputTokens: 1})
+	config := writeConfig(t, "llm:\n  provider: ollama\n  base_url: "+srv.URL+"\n")
+
+	stdout, _, err := run(t, "  Say hello\n", "complete", "-config", config, "-json")
+	if err != nil {
+		t.Fatalf("complete error = %v", err)
+	}
+	var resp struct {
+		Content string `json:"content"`
+		Usage   struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("stdout %q isn't JSON: %v", stdout, err)
+	}
+	if resp.Content != "Hello" || resp.Usage.InputTokens != 7 || resp.Usage.OutputTokens != 1 {
+		t.Errorf("response = %+v, want the content and usage", resp)
+	}
+	if got := srv.LastChatRequest().Messages[0].Content; got != "Say hello" {
+		t.Errorf("prompt = %q, want the trimmed stdin", got)
+	}
+
+	if _, _, err := run(t, "", "complete", "-config", config); err == nil {
+		t.Error("complete without a prompt succeeded")
+	}
+}
+
+func TestComplete_Stream(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	srv.ReplyChat(testutil.OllamaReply{Chunks: []string{"Hel", "lo"}})
+	stdout, _, err := run(t, "", "complete", "-provider", "ollama", "-base-url", srv.URL, "-stream", "Hi")
+	if err != nil {
+		t.Fatalf("complete -stream error = %v", err)
+	}
+	if stdout != "Hello\n" {
+		t.Errorf("stdout = %q, want the streamed reply", stdout)
+	}
+}
+
+func TestChat(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	srv.ReplyChat(
+		testutil.OllamaText("Hi there"),
+		testutil.OllamaReply{Status: 500, Error: "overloaded"},
+		testutil.OllamaText("Fine, thanks"),
+		testutil.OllamaText("Hello again"),
+	)
+	stdin := "Hello\nHow are you?\nHow are you?\n\n/reset\nHello\n/exit\nignored\n"
+
+	stdout, stderr, err := run(t, stdin, "chat", "-provider", "ollama", "-base-url", srv.URL)
+	if err != nil {
+		t.Fatalf("chat error = %v", err)
+	}
+	if stdout != "Hi there\nFine, thanks\nHello again\n" {
+		t.Errorf("stdout = %q, want the replies", stdout)
+	}
+	if !strings.Contains(stderr, "overloaded") {
+		t.Errorf("stderr = %q, want the failed reply's error", stderr)
+	}
+
+	requests := chatRequests(t, srv)
+	if len(requests) != 4 {
+		t.Fatalf("got %d chat requests, want 4", len(requests))
+	}
+	// The failed message isn't kept in the history
+	var roles []string
+	for _, msg := range requests[2].Messages {
+		roles = append(roles, msg.Role+":"+msg.Content)
+	}
+	want := "user:Hello assistant:Hi there user:How are you?"
+	if got := strings.Join(roles, " "); got != want {
+		t.Errorf("third request messages = %s, want %s", got, want)
+	}
+	if n := len(requests[3].Messages); n != 1 {
+		t.Errorf("request after /reset has %d messages, want 1", n)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+
+	stdout, _, err := run(t, "first text\n\nsecond text\n", "embed", "-provider", "ollama", "-base-url", srv.URL)
+	if err != nil {
+		t.Fatalf("embed error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("stdout = %q, want one line per text", stdout)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, []string{"0\t", "1\t"}[i]) || !strings.Contains(line, "8 dimensions") || !strings.HasSuffix(line, " ...]") {
+			t.Errorf("line %d = %q, want the index, dimensions and a preview", i, line)
+		}
+	}
+
+	stdout, _, err = run(t, "", "embed", "-provider", "ollama", "-base-url", srv.URL, "-json", "-input-type", "query", "a query")
+	if err != nil {
+		t.Fatalf("embed -json error = %v", err)
+	}
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil || len(resp.Embeddings) != 1 || len(resp.Embeddings[0]) != testutil.DefaultEmbeddingDimensions {
+		t.Errorf("stdout = %q, want the vector as JSON (%v)", stdout, err)
+	}
+
+	if _, _, err := run(t, "", "embed", "-input-type", "passage", "text"); err == nil {
+		t.Error("embed with an unknown input type succeeded")
+	}
+}
+
+func TestPing(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	config := writeConfig(t, `
+llm:
+  provider: ollama
+  base_url: `+srv.URL+`
+embeddings:
+  provider: ollama
+  base_url: `+srv.URL+`
+  model: nomic-embed-text
+`)
+
+	stdout, _, err := run(t, "", "ping", "-config", config)
+	if err != nil {
+		t.Fatalf("ping error = %v\n%s", err, stdout)
+	}
+	if !strings.Contains(stdout, "llm        ollama/llama3.1\tok") || !strings.Contains(stdout, "embeddings ollama/nomic-embed-text\tok") {
+		t.Errorf("stdout = %q, want both checks ok", stdout)
+	}
+
+	srv.ReplyChat(testutil.OllamaReply{Status: 401, Error: "bad key"})
+	stdout, _, err = run(t, "", "ping", "-config", config)
+	if err == nil || err.Error() != "1 of 2 checks failed" {
+		t.Errorf("ping error = %v, want 1 of 2 checks failed", err)
+	}
+	if !strings.Contains(stdout, "FAILED") || !strings.Contains(stdout, "bad key") {
+		t.Errorf("stdout = %q, want the failure", stdout)
+	}
+}
+
+func TestModels(t *testing.T) {
+	config := writeConfig(t, "llm:\n  provider: anthropic\n  model: claude-opus-4-20250514\n")
+
+	stdout, _, err := run(t, "", "models", "-config", config)
+	if err != nil {
+		t.Fatalf("models error = %v", err)
+	}
+	if !strings.Contains(stdout, "anthropic    claude-sonnet-4-20250514  (configured: claude-opus-4-20250514)") {
+		t.Errorf("stdout = %q, want the configured model marked", stdout)
+	}
+	if !strings.Contains(stdout, "voyage") {
+		t.Errorf("stdout = %q, want embeddings providers", stdout)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"unknown key", "llm:\n  provider: openai\n  api_key: sk-123\n", "unknown field"},
+		{"api key secret", "llm:\n  provider: openai\n  api_key_secret: dago/llm#openai\n", "api_key_secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := run(t, "", "complete", "-config", writeConfig(t, tt.config), "Hi")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyEnv(t *testing.T) {
+	var opts options
+	t.Setenv("STAGING_KEY", "sk-staging")
+	t.Setenv("OPENAI_API_KEY", "sk-default")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	cfg, err := opts.resolve(clientConfig{APIKeyEnv: "STAGING_KEY"}, "openai")
+	if err != nil || cfg.apiKey != "sk-staging" {
+		t.Errorf("resolve(api_key_env) = %q, %v, want sk-staging", cfg.apiKey, err)
+	}
+	cfg, _ = opts.resolve(clientConfig{}, "openai")
+	if cfg.apiKey != "sk-default" {
+		t.Errorf("resolve() = %q, want the provider's variable", cfg.apiKey)
+	}
+
+	// Settings of the configured provider don't apply to another one
+	opts.provider = "anthropic"
+	cfg, _ = opts.resolve(clientConfig{APIKeyEnv: "STAGING_KEY"}, "openai")
+	if cfg.Provider != "anthropic" || cfg.apiKey != "" {
+		t.Errorf("resolve(-provider) = %+v, want anthropic without a key", cfg)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	_, stderr, err := run(t, "", "translate")
+	if err == nil || !strings.Contains(stderr, "Commands:") {
+		t.Errorf("error = %v, stderr = %q, want the usage", err, stderr)
+	}
+}
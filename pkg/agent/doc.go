@@ -0,0 +1,44 @@
+// Package agent provides helpers to run agents on top of the LLM adapters.
+//
+// RunToolLoop runs the tool calling loop every tool-using agent needs: it
+// calls CompleteWithTools with the tools of a ports.ToolExecutor (pkg/ports
+// in this repository), runs the tool calls the model returns, sends their
+// results back and repeats until the model gives a final answer or an
+// iteration or token limit is reached. Tools implemented in Go are provided
+// by a tools.Funcs executor.
+//
+// Usage:
+//
+//	executor, _ := rest.NewExecutor(endpoints, logger)
+//	result, err := agent.RunToolLoop(ctx, client, executor, libports.CompletionRequest{
+//		Model:    "claude-sonnet-4-20250514",
+//		Messages: []libports.Message{{Role: "user", Content: "What's the weather in Paris?"}},
+//	}, agent.ToolLoopOptions{
+//		MaxIterations: 5,
+//		TokenBudget:   20000,
+//		OnTurn: func(ctx context.Context, turn agent.Turn) {
+//			logger.Info("agent turn",
+//				zap.Int("iteration", turn.Iteration),
+//				zap.Int("tool_calls", len(turn.Response.ToolCalls)),
+//				zap.Int("total_tokens", turn.Usage.TotalTokens))
+//		},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(result.Response.Message.Content)
+//
+// Executor builds an agent runtime on the loop: a system prompt, tools
+// (combined with a tools.Registry), memory recalled into the prompt and
+// appended after each answer, and guardrails on the input and the answer.
+// It implements graph.Node, reading its input from the state and writing
+// the answer back, so it can be an executor node of a DAG:
+//
+//	registry, _ := tools.NewRegistry(restExecutor, codeExecutor)
+//	node := agent.NewExecutor("support-agent", client, registry, logger)
+//	node.SetModel("gpt-4o")
+//	node.SetSystemPrompt("You are a support agent for ACME.")
+//	node.SetMemory(store)
+//	node.SetGuardrails(blockPromptInjection)
+//	out, err := node.Execute(ctx, state.State{"input": message, agent.ScopeKey: userID})
+package agent
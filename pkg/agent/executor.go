@@ -0,0 +1,299 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain/graph"
+	"github.com/aescanero/dago-libs/pkg/domain/state"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultInputKey is the state key of the agent's input
+	DefaultInputKey = "input"
+
+	// DefaultOutputKey is the state key the agent's answer is written to
+	DefaultOutputKey = "output"
+
+	// ScopeKey is the state key of the memory scope, e.g. a user or
+	// conversation ID. Without it, the node ID is used.
+	ScopeKey = "memory_scope"
+
+	// DefaultRecallLimit is the number of memories recalled into the system
+	// prompt
+	DefaultRecallLimit = 5
+)
+
+// GuardrailStage is the point of a run a guardrail checks
+type GuardrailStage string
+
+const (
+	// GuardrailInput checks the input before the model sees it
+	GuardrailInput GuardrailStage = "input"
+
+	// GuardrailOutput checks the final answer before it is returned
+	GuardrailOutput GuardrailStage = "output"
+)
+
+// ErrGuardrailBlocked is returned when a guardrail rejects the input or
+// the answer
+var ErrGuardrailBlocked = errors.New("blocked by guardrail")
+
+// Guardrail checks the text of a stage, e.g. for prompt injection, PII or
+// policy violations, returning an error to stop the run
+type Guardrail func(ctx context.Context, stage GuardrailStage, text string) error
+
+// Executor is a configurable agent runtime: it answers an input with an
+// LLM, running tools through RunToolLoop, with long-term memory and
+// guardrails. It implements graph.Node, to be used as an executor node of
+// a DAG.
+type Executor struct {
+	id           string
+	client       libports.LLMClient
+	tools        ports.ToolExecutor
+	model        string
+	systemPrompt string
+	maxTokens    int
+	temperature  float64
+	memory       ports.Memory
+	recallLimit  int
+	guardrails   []Guardrail
+	loopOptions  ToolLoopOptions
+	inputKey     string
+	outputKey    string
+	logger       *zap.Logger
+}
+
+// NewExecutor creates an agent node. tools may be nil for an agent without
+// tools; use a tools.Registry to combine several executors.
+func NewExecutor(id string, client libports.LLMClient, tools ports.ToolExecutor, logger *zap.Logger) *Executor {
+	if tools == nil {
+		tools = noTools{}
+	}
+	return &Executor{
+		id:          id,
+		client:      client,
+		tools:       tools,
+		recallLimit: DefaultRecallLimit,
+		inputKey:    DefaultInputKey,
+		outputKey:   DefaultOutputKey,
+		logger:      logger,
+	}
+}
+
+// SetModel sets the model of completions; the client's default if unset
+func (e *Executor) SetModel(model string) {
+	e.model = model
+}
+
+// SetSystemPrompt sets the agent's instructions
+func (e *Executor) SetSystemPrompt(prompt string) {
+	e.systemPrompt = prompt
+}
+
+// SetSampling sets the max tokens and temperature of each completion
+func (e *Executor) SetSampling(maxTokens int, temperature float64) {
+	e.maxTokens = maxTokens
+	e.temperature = temperature
+}
+
+// SetMemory enables memory: the scope's long-term summary and the memories
+// most relevant to the input are added to the system prompt, and each
+// exchange is appended as a new memory
+func (e *Executor) SetMemory(memory ports.Memory) {
+	e.memory = memory
+}
+
+// SetRecallLimit sets the number of memories recalled for each input
+func (e *Executor) SetRecallLimit(n int) {
+	e.recallLimit = n
+}
+
+// SetGuardrails sets the guardrails run on the input and the answer, in
+// order
+func (e *Executor) SetGuardrails(guardrails ...Guardrail) {
+	e.guardrails = guardrails
+}
+
+// SetLimits sets the iteration and token limits of the tool loop
+func (e *Executor) SetLimits(maxIterations, tokenBudget int) {
+	e.loopOptions.MaxIterations = maxIterations
+	e.loopOptions.TokenBudget = tokenBudget
+}
+
+// SetStateKeys sets the state keys of the input and the answer
+func (e *Executor) SetStateKeys(input, output string) {
+	e.inputKey = input
+	e.outputKey = output
+}
+
+// Run answers input, with the memories of scope if memory is enabled
+func (e *Executor) Run(ctx context.Context, scope, input string) (*ToolLoopResult, error) {
+	if err := e.check(ctx, GuardrailInput, input); err != nil {
+		return nil, err
+	}
+
+	system, err := e.system(ctx, scope, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []libports.Message
+	if system != "" {
+		messages = append(messages, libports.Message{Role: "system", Content: system})
+	}
+	messages = append(messages, libports.Message{Role: "user", Content: input})
+
+	opts := e.loopOptions
+	opts.OnTurn = func(ctx context.Context, turn Turn) {
+		e.logger.Debug("agent turn",
+			zap.String("node", e.id),
+			zap.Int("iteration", turn.Iteration),
+			zap.Int("tool_calls", len(turn.Response.ToolCalls)),
+			zap.Int("total_tokens", turn.Usage.TotalTokens))
+	}
+
+	result, err := RunToolLoop(ctx, e.client, e.tools, libports.CompletionRequest{
+		Model:       e.model,
+		Messages:    messages,
+		MaxTokens:   e.maxTokens,
+		Temperature: e.temperature,
+	}, opts)
+	if err != nil {
+		return result, err
+	}
+
+	answer := result.Response.Message.Content
+	if err := e.check(ctx, GuardrailOutput, answer); err != nil {
+		return result, err
+	}
+
+	if e.memory != nil && scope != "" {
+		_, err := e.memory.Append(ctx, ports.MemoryEntry{
+			Scope:    scope,
+			Content:  "User: " + input + "\nAssistant: " + answer,
+			Metadata: map[string]string{"node": e.id},
+		})
+		if err != nil {
+			// The answer stands; the exchange is only missing from memory
+			e.logger.Warn("failed to append memory",
+				zap.String("node", e.id),
+				zap.String("scope", scope),
+				zap.Error(err))
+		}
+	}
+
+	return result, nil
+}
+
+// check runs the guardrails of a stage
+func (e *Executor) check(ctx context.Context, stage GuardrailStage, text string) error {
+	for _, guardrail := range e.guardrails {
+		if err := guardrail(ctx, stage, text); err != nil {
+			e.logger.Info("guardrail blocked agent",
+				zap.String("node", e.id),
+				zap.String("stage", string(stage)),
+				zap.Error(err))
+			return fmt.Errorf("%w: %s: %w", ErrGuardrailBlocked, stage, err)
+		}
+	}
+	return nil
+}
+
+// system returns the system prompt with the scope's memories
+func (e *Executor) system(ctx context.Context, scope, input string) (string, error) {
+	if e.memory == nil || scope == "" {
+		return e.systemPrompt, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(e.systemPrompt)
+
+	summary, err := e.memory.Summary(ctx, scope)
+	if err != nil && !errors.Is(err, ports.ErrMemoryNotFound) {
+		return "", fmt.Errorf("failed to read memory summary: %w", err)
+	}
+	if summary != nil && summary.Content != "" {
+		b.WriteString("\n\nWhat you remember:\n")
+		b.WriteString(summary.Content)
+	}
+
+	recalled, err := e.memory.Recall(ctx, ports.MemoryQuery{Scope: scope, Text: input, Limit: e.recallLimit})
+	if err != nil {
+		return "", fmt.Errorf("failed to recall memories: %w", err)
+	}
+	if len(recalled) > 0 {
+		b.WriteString("\n\nRelevant memories:")
+		for _, memory := range recalled {
+			b.WriteString("\n- ")
+			b.WriteString(memory.Content)
+		}
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
+
+// GetID returns the node ID (graph.Node interface)
+func (e *Executor) GetID() string {
+	return e.id
+}
+
+// GetType returns graph.NodeTypeExecutor (graph.Node interface)
+func (e *Executor) GetType() graph.NodeType {
+	return graph.NodeTypeExecutor
+}
+
+// Execute answers the input in the state, returning a copy of the state
+// with the answer (graph.Node interface)
+func (e *Executor) Execute(ctx context.Context, s state.State) (state.State, error) {
+	input, ok := s.GetString(e.inputKey)
+	if !ok {
+		return nil, fmt.Errorf("state has no %q string", e.inputKey)
+	}
+	scope, ok := s.GetString(ScopeKey)
+	if !ok {
+		scope = e.id
+	}
+
+	result, err := e.Run(ctx, scope, input)
+	if err != nil {
+		return nil, fmt.Errorf("agent %s: %w", e.id, err)
+	}
+
+	out, err := s.Copy()
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy state: %w", err)
+	}
+	out.Set(e.outputKey, result.Response.Message.Content)
+	return out, nil
+}
+
+// Validate checks the node configuration (graph.Node interface)
+func (e *Executor) Validate() error {
+	if e.id == "" {
+		return &graph.ValidationError{Field: "id", Message: "agent node ID cannot be empty"}
+	}
+	if e.client == nil {
+		return &graph.ValidationError{Field: "client", Message: "agent node requires an LLM client"}
+	}
+	if e.inputKey == "" || e.outputKey == "" {
+		return &graph.ValidationError{Field: "state_keys", Message: "agent node input and output keys cannot be empty"}
+	}
+	return nil
+}
+
+// noTools is the tool executor of agents without tools
+type noTools struct{}
+
+func (noTools) Tools() []libports.Tool {
+	return nil
+}
+
+func (noTools) Execute(ctx context.Context, call libports.ToolCall) (*ports.ToolResult, error) {
+	return nil, fmt.Errorf("%w: %s", ports.ErrToolNotFound, call.Name)
+}
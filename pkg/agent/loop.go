@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// DefaultMaxIterations is the number of model calls of a tool loop when
+// ToolLoopOptions.MaxIterations is unset
+const DefaultMaxIterations = 10
+
+var (
+	// ErrMaxIterations is returned when the model still requested tool
+	// calls on the last allowed iteration
+	ErrMaxIterations = errors.New("tool loop reached max iterations")
+
+	// ErrTokenBudget is returned when the model still requested tool calls
+	// after the loop used up its token budget
+	ErrTokenBudget = errors.New("tool loop exceeded token budget")
+)
+
+// ToolLoopOptions configures RunToolLoop.
+type ToolLoopOptions struct {
+	// MaxIterations bounds the number of model calls. Defaults to
+	// DefaultMaxIterations.
+	MaxIterations int
+
+	// TokenBudget bounds the total tokens of all model calls. It is checked
+	// after each call, so the last one may overrun it. Zero is unlimited.
+	TokenBudget int
+
+	// OnTurn is called after each model call and the tool calls it
+	// requested, e.g. to log or trace the loop.
+	OnTurn func(ctx context.Context, turn Turn)
+}
+
+// Turn is one model call of a tool loop and the results of its tool calls.
+type Turn struct {
+	// Iteration is the number of the model call, starting at 1
+	Iteration int
+
+	// Response is the model's response
+	Response *libports.CompletionResponse
+
+	// Results are the results of the tool calls of Response, in order. It
+	// is empty for the final answer and a turn stopped by a limit.
+	Results []*ports.ToolResult
+
+	// Usage is the total usage of the loop so far
+	Usage libports.UsageInfo
+}
+
+// ToolLoopResult is the outcome of RunToolLoop.
+type ToolLoopResult struct {
+	// Response is the last model response: the final answer, or the tool
+	// calls left unexecuted when a limit stopped the loop
+	Response *libports.CompletionResponse
+
+	// Messages is the conversation, from the request messages to the last
+	// response, with tool calls and results encoded by ports.ToolCallsMessage
+	// and ports.ToolResultMessage
+	Messages []libports.Message
+
+	// Iterations is the number of model calls
+	Iterations int
+
+	// Usage is the total usage of all model calls
+	Usage libports.UsageInfo
+}
+
+// RunToolLoop calls CompleteWithTools with the tools of executor until the
+// model answers without tool calls. The tool calls of each response are run
+// in order and their results appended to the conversation for the next call.
+//
+// Tool calls the executor can't run, e.g. for an unknown tool or invalid
+// arguments, are sent back to the model as error results so it can correct
+// them. The loop stops with an error when a model call fails, ctx is
+// cancelled, or the iteration or token limit is reached; the result so far
+// is returned with it.
+func RunToolLoop(ctx context.Context, client libports.LLMClient, executor ports.ToolExecutor, req libports.CompletionRequest, opts ToolLoopOptions) (*ToolLoopResult, error) {
+	maxIterations := opts.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxIterations
+	}
+
+	tools := executor.Tools()
+	// Don't append to the caller's backing array
+	req.Messages = append([]libports.Message(nil), req.Messages...)
+	result := &ToolLoopResult{}
+
+	onTurn := func(turn Turn) {
+		if opts.OnTurn != nil {
+			opts.OnTurn(ctx, turn)
+		}
+	}
+
+	for iteration := 1; ; iteration++ {
+		resp, err := client.CompleteWithTools(ctx, req, tools)
+		if err != nil {
+			result.Messages = req.Messages
+			return result, fmt.Errorf("tool loop iteration %d failed: %w", iteration, err)
+		}
+
+		result.Response = resp
+		result.Iterations = iteration
+		result.Usage.PromptTokens += resp.Usage.PromptTokens
+		result.Usage.CompletionTokens += resp.Usage.CompletionTokens
+		result.Usage.TotalTokens += resp.Usage.TotalTokens
+		turn := Turn{Iteration: iteration, Response: resp, Usage: result.Usage}
+
+		if len(resp.ToolCalls) == 0 {
+			answer := resp.Message
+			if answer.Role == "" {
+				answer.Role = "assistant"
+			}
+			result.Messages = append(req.Messages, answer)
+			onTurn(turn)
+			return result, nil
+		}
+
+		req.Messages = append(req.Messages, ports.ToolCallsMessage(resp))
+
+		var limitErr error
+		switch {
+		case iteration >= maxIterations:
+			limitErr = fmt.Errorf("%w: %d", ErrMaxIterations, maxIterations)
+		case opts.TokenBudget > 0 && result.Usage.TotalTokens >= opts.TokenBudget:
+			limitErr = fmt.Errorf("%w: %d of %d tokens", ErrTokenBudget, result.Usage.TotalTokens, opts.TokenBudget)
+		}
+		if limitErr != nil {
+			result.Messages = req.Messages
+			onTurn(turn)
+			return result, limitErr
+		}
+
+		for _, call := range resp.ToolCalls {
+			toolResult, err := executor.Execute(ctx, call)
+			if err != nil {
+				if ctx.Err() != nil {
+					result.Messages = req.Messages
+					return result, ctx.Err()
+				}
+				toolResult = &ports.ToolResult{ToolCallID: call.ID, Name: call.Name, Content: err.Error(), IsError: true}
+			}
+			turn.Results = append(turn.Results, toolResult)
+			req.Messages = append(req.Messages, ports.ToolResultMessage(toolResult))
+		}
+		onTurn(turn)
+	}
+}
@@ -0,0 +1,204 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/tools"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// scriptedClient returns its responses in order, recording the requests
+type scriptedClient struct {
+	libports.LLMClient
+	responses []*libports.CompletionResponse
+	requests  []libports.CompletionRequest
+}
+
+func (c *scriptedClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	c.requests = append(c.requests, req)
+	if len(c.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+// weatherExecutor provides a get_weather tool
+type weatherExecutor struct {
+	calls int
+}
+
+func (e *weatherExecutor) Tools() []libports.Tool {
+	return []libports.Tool{{Name: "get_weather", Description: "Get the weather of a city"}}
+}
+
+func (e *weatherExecutor) Execute(ctx context.Context, call libports.ToolCall) (*ports.ToolResult, error) {
+	e.calls++
+	if call.Name != "get_weather" {
+		return nil, fmt.Errorf("%w: %s", ports.ErrToolNotFound, call.Name)
+	}
+	return &ports.ToolResult{ToolCallID: call.ID, Name: call.Name, Content: fmt.Sprintf("21°C in %v", call.Arguments["city"])}, nil
+}
+
+func toolCallResponse(calls ...libports.ToolCall) *libports.CompletionResponse {
+	return &libports.CompletionResponse{
+		Message:   libports.Message{Role: "assistant"},
+		ToolCalls: calls,
+		Usage:     libports.UsageInfo{PromptTokens: 80, CompletionTokens: 20, TotalTokens: 100},
+	}
+}
+
+func weatherCall(id, city string) libports.ToolCall {
+	return libports.ToolCall{ID: id, Name: "get_weather", Arguments: map[string]interface{}{"city": city}}
+}
+
+var loopRequest = libports.CompletionRequest{
+	Model:    "test-model",
+	Messages: []libports.Message{{Role: "user", Content: "Compare the weather in Paris and Madrid"}},
+}
+
+func TestRunToolLoop(t *testing.T) {
+	client := &scriptedClient{responses: []*libports.CompletionResponse{
+		toolCallResponse(weatherCall("call_1", "Paris"), weatherCall("call_2", "Madrid")),
+		{Message: libports.Message{Content: "Same weather in both."}, Usage: libports.UsageInfo{TotalTokens: 150}},
+	}}
+	executor := &weatherExecutor{}
+
+	var turns []Turn
+	result, err := RunToolLoop(context.Background(), client, executor, loopRequest, ToolLoopOptions{
+		OnTurn: func(ctx context.Context, turn Turn) { turns = append(turns, turn) },
+	})
+	if err != nil {
+		t.Fatalf("RunToolLoop() error = %v", err)
+	}
+
+	if result.Response.Message.Content != "Same weather in both." || result.Iterations != 2 || result.Usage.TotalTokens != 250 {
+		t.Errorf("result = %+v, want the final answer after 2 iterations and 250 tokens", result)
+	}
+	if executor.calls != 2 {
+		t.Errorf("tool calls executed = %d, want 2", executor.calls)
+	}
+
+	// The second call sees the tool calls and both results
+	second := client.requests[1].Messages
+	if len(second) != 4 {
+		t.Fatalf("second request messages = %+v, want user, tool calls and 2 results", second)
+	}
+	if _, calls, ok := ports.ParseToolCallsMessage(second[1]); !ok || len(calls) != 2 {
+		t.Errorf("message 1 = %+v, want the 2 tool calls", second[1])
+	}
+	for i, want := range []string{"21°C in Paris", "21°C in Madrid"} {
+		toolResult, ok := ports.ParseToolResultMessage(second[2+i])
+		if !ok || toolResult.Content != want || toolResult.ToolCallID != fmt.Sprintf("call_%d", i+1) {
+			t.Errorf("message %d = %+v, want result %q", 2+i, second[2+i], want)
+		}
+	}
+
+	if last := result.Messages[len(result.Messages)-1]; len(result.Messages) != 5 || last.Role != "assistant" || last.Content != "Same weather in both." {
+		t.Errorf("messages = %+v, want the conversation ending with the answer", result.Messages)
+	}
+	if len(loopRequest.Messages) != 1 {
+		t.Error("RunToolLoop() modified the request messages")
+	}
+
+	if len(turns) != 2 || len(turns[0].Results) != 2 || turns[0].Usage.TotalTokens != 100 || len(turns[1].Results) != 0 || turns[1].Usage.TotalTokens != 250 {
+		t.Errorf("turns = %+v, want a tool turn and a final turn with running usage", turns)
+	}
+}
+
+func TestRunToolLoopToolErrors(t *testing.T) {
+	client := &scriptedClient{responses: []*libports.CompletionResponse{
+		toolCallResponse(libports.ToolCall{ID: "call_1", Name: "get_forecast"}),
+		{Message: libports.Message{Role: "assistant", Content: "I can't forecast."}},
+	}}
+
+	if _, err := RunToolLoop(context.Background(), client, &weatherExecutor{}, loopRequest, ToolLoopOptions{}); err != nil {
+		t.Fatalf("RunToolLoop() error = %v", err)
+	}
+
+	toolResult, ok := ports.ParseToolResultMessage(client.requests[1].Messages[2])
+	if !ok || !toolResult.IsError || toolResult.Name != "get_forecast" {
+		t.Errorf("result = %+v, want an error result for the unknown tool", toolResult)
+	}
+}
+
+func TestRunToolLoopFuncs(t *testing.T) {
+	client := &scriptedClient{responses: []*libports.CompletionResponse{
+		toolCallResponse(libports.ToolCall{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{}}),
+		toolCallResponse(weatherCall("call_2", "Paris")),
+		{Message: libports.Message{Role: "assistant", Content: "It's 21°C in Paris."}},
+	}}
+	funcs := tools.NewFuncs()
+	_ = tools.AddFunc(funcs, "get_weather", "Get the weather of a city", func(ctx context.Context, args struct {
+		City string `json:"city"`
+	}) (string, error) {
+		return "21°C in " + args.City, nil
+	})
+
+	result, err := RunToolLoop(context.Background(), client, funcs, loopRequest, ToolLoopOptions{})
+	if err != nil {
+		t.Fatalf("RunToolLoop() error = %v", err)
+	}
+	if result.Iterations != 3 {
+		t.Errorf("iterations = %d, want 3", result.Iterations)
+	}
+
+	// Invalid arguments are sent back for the model to correct
+	invalid, _ := ports.ParseToolResultMessage(client.requests[1].Messages[2])
+	weather, _ := ports.ParseToolResultMessage(client.requests[2].Messages[4])
+	if invalid == nil || !invalid.IsError || weather == nil || weather.Content != "21°C in Paris" {
+		t.Errorf("results = %+v, %+v, want an error, then the weather", invalid, weather)
+	}
+}
+
+func TestRunToolLoopLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ToolLoopOptions
+		want      error
+		wantCalls int
+	}{
+		{"max iterations", ToolLoopOptions{MaxIterations: 3}, ErrMaxIterations, 3},
+		{"default max iterations", ToolLoopOptions{}, ErrMaxIterations, DefaultMaxIterations},
+		{"token budget", ToolLoopOptions{TokenBudget: 250}, ErrTokenBudget, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &scriptedClient{}
+			for i := 0; i < 20; i++ {
+				client.responses = append(client.responses, toolCallResponse(weatherCall(fmt.Sprintf("call_%d", i), "Paris")))
+			}
+			executor := &weatherExecutor{}
+
+			result, err := RunToolLoop(context.Background(), client, executor, loopRequest, tt.opts)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("RunToolLoop() error = %v, want %v", err, tt.want)
+			}
+			if len(client.requests) != tt.wantCalls || result.Iterations != tt.wantCalls {
+				t.Errorf("model calls = %d, want %d", len(client.requests), tt.wantCalls)
+			}
+			// The tool calls of the last response aren't run
+			if executor.calls != tt.wantCalls-1 || len(result.Response.ToolCalls) != 1 {
+				t.Errorf("tool calls executed = %d, want %d", executor.calls, tt.wantCalls-1)
+			}
+		})
+	}
+}
+
+func TestRunToolLoopClientError(t *testing.T) {
+	client := &scriptedClient{responses: []*libports.CompletionResponse{toolCallResponse(weatherCall("call_1", "Paris"))}}
+
+	result, err := RunToolLoop(context.Background(), client, &weatherExecutor{}, loopRequest, ToolLoopOptions{})
+	if err == nil {
+		t.Fatal("RunToolLoop() error = nil, want the failed second call")
+	}
+	if result.Iterations != 1 || len(result.Messages) != 3 {
+		t.Errorf("result = %+v, want the first iteration and its messages", result)
+	}
+}
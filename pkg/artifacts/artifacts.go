@@ -0,0 +1,218 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxSize is the largest artifact stored or read, 64 MiB
+	DefaultMaxSize = 64 << 20
+
+	// Blob metadata keys, lower case as S3 returns them
+	metaKind   = "artifact-kind"
+	metaSHA256 = "sha256"
+)
+
+var (
+	// ErrTooLarge is returned for artifacts over the size limit
+	ErrTooLarge = errors.New("artifact too large")
+
+	// ErrChecksumMismatch is returned when an artifact's content doesn't
+	// match the checksum of its reference, e.g. because it was overwritten
+	ErrChecksumMismatch = errors.New("artifact checksum mismatch")
+
+	// ErrKindMismatch is returned when an artifact is read as another kind
+	ErrKindMismatch = errors.New("artifact kind mismatch")
+)
+
+// Kind is how an artifact's content is encoded
+type Kind string
+
+const (
+	KindJSON   Kind = "json"
+	KindText   Kind = "text"
+	KindBinary Kind = "binary"
+)
+
+// Ref references a stored artifact. It is small enough to pass in task
+// payloads and run state instead of the content.
+type Ref struct {
+	Key         string `json:"key"`
+	Kind        Kind   `json:"kind"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+
+	// SHA256 is the hex digest of the content, verified on every read
+	SHA256 string `json:"sha256"`
+}
+
+// Key returns the conventional key of an artifact produced by a step of a
+// run, "artifacts/<run ID>/<step ID>/<name>", with each part path-escaped
+func Key(runID, stepID, name string) string {
+	return "artifacts/" + url.PathEscape(runID) + "/" + url.PathEscape(stepID) + "/" + url.PathEscape(name)
+}
+
+// Store puts and gets typed artifacts in a ports.BlobStore
+type Store struct {
+	blobs   ports.BlobStore
+	logger  *zap.Logger
+	maxSize int64
+}
+
+// NewStore creates a new artifact store on blobs
+func NewStore(blobs ports.BlobStore, logger *zap.Logger) *Store {
+	return &Store{
+		blobs:   blobs,
+		logger:  logger,
+		maxSize: DefaultMaxSize,
+	}
+}
+
+// SetMaxSize sets the largest artifact stored or read, in bytes
+func (s *Store) SetMaxSize(size int64) {
+	s.maxSize = size
+}
+
+// PutJSON stores v encoded as JSON under key
+func (s *Store) PutJSON(ctx context.Context, key string, v any) (*Ref, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact: %w", err)
+	}
+	return s.put(ctx, key, KindJSON, "application/json", data)
+}
+
+// PutText stores text under key
+func (s *Store) PutText(ctx context.Context, key, text string) (*Ref, error) {
+	return s.put(ctx, key, KindText, "text/plain; charset=utf-8", []byte(text))
+}
+
+// PutBinary stores data under key. contentType is optional and defaults
+// to application/octet-stream.
+func (s *Store) PutBinary(ctx context.Context, key string, data []byte, contentType string) (*Ref, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return s.put(ctx, key, KindBinary, contentType, data)
+}
+
+func (s *Store) put(ctx context.Context, key string, kind Kind, contentType string, data []byte) (*Ref, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: %s is %d bytes, limit is %d", ErrTooLarge, key, len(data), s.maxSize)
+	}
+
+	sum := sha256.Sum256(data)
+	ref := &Ref{
+		Key:         key,
+		Kind:        kind,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+
+	_, err := s.blobs.Put(ctx, key, bytes.NewReader(data), ports.PutOptions{
+		ContentType: contentType,
+		Metadata: map[string]string{
+			metaKind:   string(kind),
+			metaSHA256: ref.SHA256,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store artifact: %w", err)
+	}
+
+	s.logger.Debug("artifact stored",
+		zap.String("key", key),
+		zap.String("kind", string(kind)),
+		zap.Int64("size", ref.Size))
+	return ref, nil
+}
+
+// Ref returns the reference of an artifact stored under key, for artifacts
+// whose key is known but whose reference wasn't passed along
+func (s *Store) Ref(ctx context.Context, key string) (*Ref, error) {
+	info, err := s.blobs.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	kind := Kind(info.Metadata[metaKind])
+	if kind == "" {
+		kind = KindBinary
+	}
+	return &Ref{
+		Key:         key,
+		Kind:        kind,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+		SHA256:      info.Metadata[metaSHA256],
+	}, nil
+}
+
+// GetJSON decodes the JSON artifact of ref into v
+func (s *Store) GetJSON(ctx context.Context, ref Ref, v any) error {
+	data, err := s.get(ctx, ref, KindJSON)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal artifact: %w", err)
+	}
+	return nil
+}
+
+// GetText returns the text artifact of ref
+func (s *Store) GetText(ctx context.Context, ref Ref) (string, error) {
+	data, err := s.get(ctx, ref, KindText)
+	return string(data), err
+}
+
+// GetBinary returns the content of the artifact of ref, of any kind
+func (s *Store) GetBinary(ctx context.Context, ref Ref) ([]byte, error) {
+	return s.get(ctx, ref, "")
+}
+
+// get reads and verifies an artifact; kind is checked unless empty
+func (s *Store) get(ctx context.Context, ref Ref, kind Kind) ([]byte, error) {
+	if kind != "" && ref.Kind != kind {
+		return nil, fmt.Errorf("%w: %s is %s, not %s", ErrKindMismatch, ref.Key, ref.Kind, kind)
+	}
+	if ref.Size > s.maxSize {
+		return nil, fmt.Errorf("%w: %s is %d bytes, limit is %d", ErrTooLarge, ref.Key, ref.Size, s.maxSize)
+	}
+
+	r, _, err := s.blobs.Get(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: %s is over the limit of %d bytes", ErrTooLarge, ref.Key, s.maxSize)
+	}
+
+	sum := sha256.Sum256(data)
+	if ref.SHA256 != "" && hex.EncodeToString(sum[:]) != ref.SHA256 {
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, ref.Key)
+	}
+	return data, nil
+}
+
+// Delete removes the artifact of ref
+func (s *Store) Delete(ctx context.Context, ref Ref) error {
+	return s.blobs.Delete(ctx, ref.Key)
+}
@@ -0,0 +1,145 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/blob"
+	"github.com/aescanero/dago-adapters/pkg/blob/local"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func newTestStore(t *testing.T) (*Store, ports.BlobStore) {
+	t.Helper()
+	blobs, err := local.NewStore(t.TempDir(), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(blobs, zap.NewNop()), blobs
+}
+
+type report struct {
+	Title string   `json:"title"`
+	Pages []string `json:"pages"`
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	ref, err := store.PutJSON(ctx, Key("run-1", "draft", "report.json"), report{Title: "Q3", Pages: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("PutJSON() error = %v", err)
+	}
+	if ref.Key != "artifacts/run-1/draft/report.json" || ref.Kind != KindJSON || ref.Size == 0 || len(ref.SHA256) != 64 {
+		t.Errorf("PutJSON() ref = %+v", ref)
+	}
+	var got report
+	if err := store.GetJSON(ctx, *ref, &got); err != nil || got.Title != "Q3" || len(got.Pages) != 2 {
+		t.Errorf("GetJSON() = %+v, %v", got, err)
+	}
+
+	textRef, err := store.PutText(ctx, Key("run-1", "draft", "summary.txt"), "short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := store.GetText(ctx, *textRef); err != nil || text != "short" {
+		t.Errorf("GetText() = %q, %v", text, err)
+	}
+	if err := store.GetJSON(ctx, *textRef, &got); !errors.Is(err, ErrKindMismatch) {
+		t.Errorf("GetJSON() of text error = %v, want kind mismatch", err)
+	}
+
+	binRef, err := store.PutBinary(ctx, "image.png", []byte{0x89, 'P', 'N', 'G'}, "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := store.GetBinary(ctx, *binRef); err != nil || len(data) != 4 {
+		t.Errorf("GetBinary() = %v, %v", data, err)
+	}
+
+	// The reference can be rebuilt from the key
+	rebuilt, err := store.Ref(ctx, binRef.Key)
+	if err != nil || *rebuilt != *binRef {
+		t.Errorf("Ref() = %+v, %v; want %+v", rebuilt, err, binRef)
+	}
+}
+
+func TestStore_Checksum(t *testing.T) {
+	ctx := context.Background()
+	store, blobs := newTestStore(t)
+
+	ref, err := store.PutText(ctx, "a.txt", "original")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := blob.PutBytes(ctx, blobs, "a.txt", []byte("tampered"), ports.PutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetText(ctx, *ref); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("GetText() of an overwritten artifact error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestStore_MaxSize(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	ref, err := store.PutText(ctx, "big.txt", strings.Repeat("x", 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetMaxSize(10)
+	if _, err := store.PutText(ctx, "big2.txt", strings.Repeat("x", 11)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("PutText() over the limit error = %v, want too large", err)
+	}
+	if _, err := store.GetText(ctx, *ref); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("GetText() over the limit error = %v, want too large", err)
+	}
+
+	// References without a size are still bounded while reading
+	ref.Size = 0
+	if _, err := store.GetText(ctx, *ref); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("GetText() of an unsized reference error = %v, want too large", err)
+	}
+}
+
+func TestPayloadRefs(t *testing.T) {
+	ref := Ref{Key: "artifacts/run-1/draft/report.json", Kind: KindJSON, Size: 10, SHA256: "abc"}
+
+	payload, err := WithRefs([]byte(`{"graph_id":"report","artifacts":{"old":{"key":"k","kind":"text"}}}`), map[string]Ref{"report": ref})
+	if err != nil {
+		t.Fatalf("WithRefs() error = %v", err)
+	}
+	if !strings.Contains(string(payload), `"graph_id":"report"`) {
+		t.Errorf("WithRefs() dropped payload fields: %s", payload)
+	}
+
+	refs, err := RefsFrom(payload)
+	if err != nil {
+		t.Fatalf("RefsFrom() error = %v", err)
+	}
+	if len(refs) != 2 || refs["report"] != ref || refs["old"].Key != "k" {
+		t.Errorf("RefsFrom() = %+v", refs)
+	}
+
+	if payload, err := WithRefs(nil, map[string]Ref{"report": ref}); err != nil || !strings.HasPrefix(string(payload), `{"artifacts":`) {
+		t.Errorf("WithRefs(nil) = %s, %v", payload, err)
+	}
+	if _, err := WithRefs([]byte(`"a string"`), nil); err == nil {
+		t.Error("WithRefs() of a non-object payload succeeded")
+	}
+	if refs, err := RefsFrom(nil); refs != nil || err != nil {
+		t.Errorf("RefsFrom(nil) = %v, %v", refs, err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key("run/1", "fetch page", "out.json"); got != "artifacts/run%2F1/fetch%20page/out.json" {
+		t.Errorf("Key() = %q", got)
+	}
+}
@@ -0,0 +1,25 @@
+// Package artifacts standardizes how step outputs flow between workers. It
+// wraps a ports.BlobStore (pkg/blob) with typed puts and gets of JSON, text
+// and binary artifacts, and passes them downstream as references in task
+// payloads.
+//
+// Every artifact is stored with its SHA-256 digest, and reads verify it
+// against the reference, so a consumer never acts on content that was
+// overwritten after the reference was issued. Writes and reads are bounded
+// by a size limit (DefaultMaxSize unless set with SetMaxSize), as artifacts
+// are held in memory.
+//
+// Usage:
+//
+//	store := artifacts.NewStore(blobs, logger)
+//
+//	// Producer
+//	ref, err := store.PutJSON(ctx, artifacts.Key(runID, "extract", "invoice.json"), invoice)
+//	payload, err := artifacts.WithRefs(task.Payload, map[string]artifacts.Ref{"invoice": *ref})
+//	_, err = queue.Publish(ctx, "review", ports.Task{Payload: payload})
+//
+//	// Consumer
+//	refs, err := artifacts.RefsFrom(task.Payload)
+//	var invoice Invoice
+//	err = store.GetJSON(ctx, refs["invoice"], &invoice)
+package artifacts
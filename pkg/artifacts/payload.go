@@ -0,0 +1,57 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PayloadField is the field of a JSON task payload holding artifact
+// references by name
+const PayloadField = "artifacts"
+
+// WithRefs returns a JSON object payload with refs added to its
+// PayloadField, keeping references already there unless refs names them
+// too. An empty payload becomes an object holding only the references.
+func WithRefs(payload []byte, refs map[string]Ref) ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return nil, fmt.Errorf("task payload is not a JSON object: %w", err)
+		}
+	}
+
+	merged, err := RefsFrom(payload)
+	if err != nil {
+		return nil, err
+	}
+	if merged == nil {
+		merged = make(map[string]Ref, len(refs))
+	}
+	for name, ref := range refs {
+		merged[name] = ref
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact references: %w", err)
+	}
+	fields[PayloadField] = data
+
+	return json.Marshal(fields)
+}
+
+// RefsFrom returns the artifact references of a JSON task payload, or nil
+// if it has none
+func RefsFrom(payload []byte) (map[string]Ref, error) {
+	if len(payload) == 0 {
+		return nil, nil
+	}
+
+	var envelope struct {
+		Artifacts map[string]Ref `json:"artifacts"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artifact references: %w", err)
+	}
+	return envelope.Artifacts, nil
+}
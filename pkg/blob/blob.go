@@ -0,0 +1,90 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// ContentPrefix prefixes the keys of content-addressed blobs
+const ContentPrefix = "sha256/"
+
+// ContentKey returns the content-addressed key of data, e.g.
+// "sha256/9f86d081884c7d65..."
+func ContentKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return ContentPrefix + hex.EncodeToString(sum[:])
+}
+
+// IsContentKey reports whether key is a content-addressed key
+func IsContentKey(key string) bool {
+	digest, ok := strings.CutPrefix(key, ContentPrefix)
+	if !ok || len(digest) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(digest)
+	return err == nil
+}
+
+// PutContentAddressed stores the content read from r under its
+// content-addressed key and returns its description. Content already in the
+// store is not uploaded again, so storing the same document from several
+// steps costs one copy. The content is spooled to a temporary file to
+// compute the key before uploading.
+func PutContentAddressed(ctx context.Context, store ports.BlobStore, r io.Reader, opts ports.PutOptions) (*ports.BlobInfo, error) {
+	spool, err := os.CreateTemp("", "dago-blob-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool file: %w", err)
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(spool, hash), r); err != nil {
+		return nil, fmt.Errorf("failed to spool blob: %w", err)
+	}
+	key := ContentPrefix + hex.EncodeToString(hash.Sum(nil))
+
+	info, err := store.Stat(ctx, key)
+	if err == nil {
+		return info, nil
+	}
+	if !errors.Is(err, ports.ErrBlobNotFound) {
+		return nil, err
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind spool file: %w", err)
+	}
+	return store.Put(ctx, key, spool, opts)
+}
+
+// PutBytes stores data under key
+func PutBytes(ctx context.Context, store ports.BlobStore, key string, data []byte, opts ports.PutOptions) (*ports.BlobInfo, error) {
+	return store.Put(ctx, key, bytes.NewReader(data), opts)
+}
+
+// ReadAll returns the content of the blob stored under key
+func ReadAll(ctx context.Context, store ports.BlobStore, key string) ([]byte, error) {
+	r, _, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
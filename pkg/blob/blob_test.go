@@ -0,0 +1,57 @@
+package blob_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/blob"
+	"github.com/aescanero/dago-adapters/pkg/blob/local"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestPutContentAddressed(t *testing.T) {
+	ctx := context.Background()
+	store, err := local.NewStore(t.TempDir(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	document := "a long document passed between steps"
+	info, err := blob.PutContentAddressed(ctx, store, strings.NewReader(document), ports.PutOptions{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutContentAddressed() error = %v", err)
+	}
+	if info.Key != blob.ContentKey([]byte(document)) || !blob.IsContentKey(info.Key) {
+		t.Errorf("PutContentAddressed() key = %q, want %q", info.Key, blob.ContentKey([]byte(document)))
+	}
+
+	// Storing it again keeps the existing blob
+	again, err := blob.PutContentAddressed(ctx, store, strings.NewReader(document), ports.PutOptions{})
+	if err != nil {
+		t.Fatalf("PutContentAddressed() error = %v", err)
+	}
+	if again.Key != info.Key || again.ContentType != "text/plain" {
+		t.Errorf("PutContentAddressed() again = %+v, want the existing blob", again)
+	}
+
+	data, err := blob.ReadAll(ctx, store, info.Key)
+	if err != nil || string(data) != document {
+		t.Errorf("ReadAll() = %q, %v", data, err)
+	}
+}
+
+func TestIsContentKey(t *testing.T) {
+	tests := map[string]bool{
+		blob.ContentKey([]byte("x")):        true,
+		"sha256/abc":                        false,
+		"executions/exec-1/report":          false,
+		"sha256/" + strings.Repeat("z", 64): false,
+	}
+	for key, want := range tests {
+		if got := blob.IsContentKey(key); got != want {
+			t.Errorf("IsContentKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Package blob provides adapters for the ports.BlobStore interface (pkg/ports
+// in this repository), which holds large payloads such as prompts, documents
+// and generated files. Workers store them once and pass their keys between
+// DAG steps instead of copying the content through the task queue and state.
+//
+// Available implementations:
+//   - s3: Amazon S3 and S3-compatible stores such as MinIO
+//   - gcs: Google Cloud Storage
+//   - local: A directory on the local filesystem, for development and
+//     single-node installs
+//
+// The package also provides helpers that work with any store, such as
+// PutContentAddressed, which stores content under a key derived from its
+// SHA-256 digest so identical content is stored once.
+package blob
@@ -0,0 +1,24 @@
+// Package gcs implements ports.BlobStore (pkg/ports in this repository) using
+// a Google Cloud Storage bucket.
+//
+// Blobs are objects named after their keys, with the content type and user
+// metadata stored as object properties. Uploads are resumable and streamed,
+// so content of any size is uploaded without buffering it whole.
+//
+// Presigned URLs are V4 signed URLs. Signing uses the private key of
+// service account credentials, or the IAM Credentials signBlob API when
+// running with workload identity, which requires the
+// iam.serviceAccounts.signBlob permission.
+//
+// Usage:
+//
+//	client, err := storage.NewClient(ctx)
+//	store := gcs.NewStore(client, "dago-artifacts", logger)
+//
+//	info, err := store.Put(ctx, "executions/exec-1/report.pdf", file, ports.PutOptions{
+//		ContentType: "application/pdf",
+//	})
+//	url, err := store.PresignGet(ctx, info.Key, 15*time.Minute)
+//
+// Set STORAGE_EMULATOR_HOST to use an emulator such as fake-gcs-server.
+package gcs
@@ -0,0 +1,159 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+)
+
+// Store implements ports.BlobStore using a Google Cloud Storage bucket
+type Store struct {
+	client *storage.Client
+	bucket *storage.BucketHandle
+	name   string
+	logger *zap.Logger
+}
+
+// NewStore creates a new GCS blob store for the bucket
+func NewStore(client *storage.Client, bucket string, logger *zap.Logger) *Store {
+	return &Store{
+		client: client,
+		bucket: client.Bucket(bucket),
+		name:   bucket,
+		logger: logger,
+	}
+}
+
+// Put streams the blob to the bucket (ports.BlobStore interface). The
+// upload is cancelled if reading r fails, leaving any previous object in
+// place.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, opts ports.PutOptions) (*ports.BlobInfo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = opts.ContentType
+	w.Metadata = opts.Metadata
+
+	if _, err := io.Copy(w, r); err != nil {
+		cancel()
+		_ = w.Close()
+		return nil, fmt.Errorf("failed to put blob: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to put blob: %w", err)
+	}
+
+	s.logger.Debug("blob stored",
+		zap.String("bucket", s.name),
+		zap.String("key", key))
+
+	return blobInfo(w.Attrs()), nil
+}
+
+// Get opens the object for streaming (ports.BlobStore interface). The
+// content read is that of the generation described, even if the object is
+// replaced meanwhile.
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, *ports.BlobInfo, error) {
+	obj := s.bucket.Object(key)
+
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return nil, nil, s.objectError("get", key, err)
+	}
+
+	r, err := obj.Generation(attrs.Generation).NewReader(ctx)
+	if err != nil {
+		return nil, nil, s.objectError("get", key, err)
+	}
+
+	return r, blobInfo(attrs), nil
+}
+
+// Stat returns the object's description (ports.BlobStore interface)
+func (s *Store) Stat(ctx context.Context, key string) (*ports.BlobInfo, error) {
+	attrs, err := s.bucket.Object(key).Attrs(ctx)
+	if err != nil {
+		return nil, s.objectError("stat", key, err)
+	}
+	return blobInfo(attrs), nil
+}
+
+// Delete removes the object (ports.BlobStore interface)
+func (s *Store) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// List returns the objects whose names start with prefix (ports.BlobStore
+// interface)
+func (s *Store) List(ctx context.Context, prefix string) ([]ports.BlobInfo, error) {
+	var blobs []ports.BlobInfo
+
+	it := s.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blobs: %w", err)
+		}
+		blobs = append(blobs, *blobInfo(attrs))
+	}
+
+	return blobs, nil
+}
+
+// PresignGet returns a V4 signed download URL (ports.BlobStore interface).
+// Signing needs credentials with a private key, or the IAM signBlob
+// permission when running with workload identity.
+func (s *Store) PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.signedURL(key, http.MethodGet, expiry)
+}
+
+// PresignPut returns a V4 signed upload URL (ports.BlobStore interface)
+func (s *Store) PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.signedURL(key, http.MethodPut, expiry)
+}
+
+func (s *Store) signedURL(key, method string, expiry time.Duration) (string, error) {
+	url, err := s.bucket.SignedURL(key, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  method,
+		Expires: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign blob URL: %w", err)
+	}
+	return url, nil
+}
+
+func (s *Store) objectError(op, key string, err error) error {
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("%w: %s", ports.ErrBlobNotFound, key)
+	}
+	return fmt.Errorf("failed to %s blob: %w", op, err)
+}
+
+func blobInfo(attrs *storage.ObjectAttrs) *ports.BlobInfo {
+	return &ports.BlobInfo{
+		Key:         attrs.Name,
+		Size:        attrs.Size,
+		ContentType: attrs.ContentType,
+		ETag:        attrs.Etag,
+		Metadata:    attrs.Metadata,
+		ModifiedAt:  attrs.Updated,
+	}
+}
@@ -0,0 +1,70 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestStore_Integration(t *testing.T) {
+	if os.Getenv("STORAGE_EMULATOR_HOST") == "" {
+		t.Skip("STORAGE_EMULATOR_HOST not set, skipping integration test")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("storage.NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	bucket := "dago-blob-integration"
+	if err := client.Bucket(bucket).Create(ctx, "test-project", nil); err != nil && !strings.Contains(err.Error(), "409") {
+		t.Fatalf("Create() error = %v", err)
+	}
+	store := NewStore(client, bucket, zap.NewNop())
+
+	key := "executions/exec-1/report.txt"
+	info, err := store.Put(ctx, key, strings.NewReader("report"), ports.PutOptions{
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"node": "report"},
+	})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if info.Size != 6 || info.ContentType != "text/plain" {
+		t.Errorf("Put() info = %+v", info)
+	}
+
+	r, got, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	_ = r.Close()
+	if string(data) != "report" || got.Metadata["node"] != "report" {
+		t.Errorf("Get() = %q, %+v", data, got)
+	}
+
+	blobs, err := store.List(ctx, "executions/exec-1/")
+	if err != nil || len(blobs) != 1 || blobs[0].Key != key {
+		t.Errorf("List() = %+v, %v", blobs, err)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Stat(ctx, key); !errors.Is(err, ports.ErrBlobNotFound) {
+		t.Errorf("Stat() after delete error = %v, want ErrBlobNotFound", err)
+	}
+}
@@ -0,0 +1,22 @@
+// Package local implements ports.BlobStore (pkg/ports in this repository) in
+// a directory of the local filesystem, for development, tests and
+// single-node installs.
+//
+// Directory structure:
+//   - Each blob is a file at its key below the root, e.g.
+//     <root>/executions/exec-1/report.pdf
+//   - Its content type, metadata and SHA-256 ETag are stored as JSON in
+//     <root>/.meta/<key>.json
+//
+// Key segments may not start with a dot, which keeps the store's own files
+// out of reach. The local store can't issue presigned URLs.
+//
+// Usage:
+//
+//	store, err := local.NewStore("/var/lib/dago/blobs", logger)
+//
+//	info, err := blob.PutContentAddressed(ctx, store, file, ports.PutOptions{
+//		ContentType: "application/pdf",
+//	})
+//	// Pass info.Key to the next step
+package local
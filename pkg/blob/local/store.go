@@ -0,0 +1,257 @@
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// Directory under the root holding blob descriptions
+	metaDir = ".meta"
+
+	// Prefix of files being written
+	tempPrefix = ".tmp-"
+)
+
+// meta is the description of a blob stored next to it
+type meta struct {
+	ContentType string            `json:"content_type,omitempty"`
+	ETag        string            `json:"etag"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Store implements ports.BlobStore in a directory of the local filesystem.
+// Keys are slash-separated paths below the root; blobs are written to a
+// temporary file and renamed into place, so readers never see partial
+// content.
+type Store struct {
+	root   string
+	logger *zap.Logger
+}
+
+// NewStore creates a store rooted at dir, creating it if needed
+func NewStore(dir string, logger *zap.Logger) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, metaDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	return &Store{
+		root:   dir,
+		logger: logger,
+	}, nil
+}
+
+// Put writes the blob and its description (ports.BlobStore interface)
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, opts ports.PutOptions) (*ports.BlobInfo, error) {
+	path, metaPath, err := s.paths(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	hash := sha256.New()
+	if err := writeAtomic(path, io.TeeReader(r, hash)); err != nil {
+		return nil, fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	m := meta{
+		ContentType: opts.ContentType,
+		ETag:        hex.EncodeToString(hash.Sum(nil)),
+		Metadata:    opts.Metadata,
+	}
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blob metadata: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(metaPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	if err := writeAtomic(metaPath, strings.NewReader(string(encoded))); err != nil {
+		return nil, fmt.Errorf("failed to write blob metadata: %w", err)
+	}
+
+	s.logger.Debug("blob stored", zap.String("key", key))
+
+	return s.Stat(ctx, key)
+}
+
+// Get opens the blob's file (ports.BlobStore interface)
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, *ports.BlobInfo, error) {
+	path, _, err := s.paths(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%w: %s", ports.ErrBlobNotFound, key)
+		}
+		return nil, nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	return file, info, nil
+}
+
+// Stat returns the blob's description (ports.BlobStore interface)
+func (s *Store) Stat(ctx context.Context, key string) (*ports.BlobInfo, error) {
+	path, metaPath, err := s.paths(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrBlobNotFound, key)
+		}
+		return nil, fmt.Errorf("failed to stat blob: %w", err)
+	}
+
+	info := &ports.BlobInfo{
+		Key:        key,
+		Size:       fi.Size(),
+		ModifiedAt: fi.ModTime(),
+	}
+
+	// Files placed in the directory by other means have no description
+	if data, err := os.ReadFile(metaPath); err == nil {
+		var m meta
+		if err := json.Unmarshal(data, &m); err == nil {
+			info.ContentType = m.ContentType
+			info.ETag = m.ETag
+			info.Metadata = m.Metadata
+		}
+	}
+
+	return info, nil
+}
+
+// Delete removes the blob and its description (ports.BlobStore interface)
+func (s *Store) Delete(ctx context.Context, key string) error {
+	path, metaPath, err := s.paths(key)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range []string{path, metaPath} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete blob: %w", err)
+		}
+	}
+	return nil
+}
+
+// List walks the directory for blobs whose keys start with prefix
+// (ports.BlobStore interface)
+func (s *Store) List(ctx context.Context, prefix string) ([]ports.BlobInfo, error) {
+	var blobs []ports.BlobInfo
+
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != s.root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := s.Stat(ctx, key)
+		if err != nil {
+			// Deleted while walking
+			if errors.Is(err, ports.ErrBlobNotFound) {
+				return nil
+			}
+			return err
+		}
+		blobs = append(blobs, *info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Key < blobs[j].Key })
+	return blobs, nil
+}
+
+// PresignGet is not supported by the local store (ports.BlobStore interface)
+func (s *Store) PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "", ports.ErrPresignNotSupported
+}
+
+// PresignPut is not supported by the local store (ports.BlobStore interface)
+func (s *Store) PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "", ports.ErrPresignNotSupported
+}
+
+// paths returns the file paths of the blob and its description, rejecting
+// keys that would escape the root or collide with the store's own files
+func (s *Store) paths(key string) (string, string, error) {
+	rel := filepath.FromSlash(key)
+	if key == "" || !filepath.IsLocal(rel) {
+		return "", "", fmt.Errorf("invalid blob key: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if strings.HasPrefix(part, ".") {
+			return "", "", fmt.Errorf("invalid blob key: %q", key)
+		}
+	}
+
+	return filepath.Join(s.root, rel), filepath.Join(s.root, metaDir, rel+".json"), nil
+}
+
+// writeAtomic writes the content of r to path through a temporary file in
+// the same directory
+func writeAtomic(path string, r io.Reader) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
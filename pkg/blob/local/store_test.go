@@ -0,0 +1,79 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStore(t.TempDir(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	info, err := store.Put(ctx, "executions/exec-1/summary.txt", strings.NewReader("summary"), ports.PutOptions{
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"node": "summarize"},
+	})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if info.Size != 7 || info.ContentType != "text/plain" || info.Metadata["node"] != "summarize" || info.ETag == "" {
+		t.Errorf("Put() info = %+v", info)
+	}
+
+	r, got, err := store.Get(ctx, "executions/exec-1/summary.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	_ = r.Close()
+	if string(data) != "summary" || got.ETag != info.ETag {
+		t.Errorf("Get() = %q, %+v", data, got)
+	}
+
+	if _, err := store.Put(ctx, "executions/exec-2/summary.txt", strings.NewReader("other"), ports.PutOptions{}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	blobs, err := store.List(ctx, "executions/exec-1/")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(blobs) != 1 || blobs[0].Key != "executions/exec-1/summary.txt" {
+		t.Errorf("List() = %+v, want the exec-1 blob", blobs)
+	}
+
+	if err := store.Delete(ctx, "executions/exec-1/summary.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Stat(ctx, "executions/exec-1/summary.txt"); !errors.Is(err, ports.ErrBlobNotFound) {
+		t.Errorf("Stat() after delete error = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, "executions/exec-1/summary.txt"); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v", err)
+	}
+
+	if _, err := store.PresignGet(ctx, "executions/exec-2/summary.txt", 0); !errors.Is(err, ports.ErrPresignNotSupported) {
+		t.Errorf("PresignGet() error = %v, want ErrPresignNotSupported", err)
+	}
+}
+
+func TestStore_InvalidKeys(t *testing.T) {
+	store, err := NewStore(t.TempDir(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	for _, key := range []string{"", "../escape", "/etc/passwd", "a/../../b", ".meta/x.json", "dir/.hidden"} {
+		if _, err := store.Put(context.Background(), key, strings.NewReader("x"), ports.PutOptions{}); err == nil {
+			t.Errorf("Put(%q) succeeded, want invalid key", key)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Package s3 implements ports.BlobStore (pkg/ports in this repository) using
+// an Amazon S3 bucket or an S3-compatible store such as MinIO.
+//
+// Blobs are objects keyed as given, with the content type and user metadata
+// stored as object properties. Content that fits in one part (8 MiB by
+// default, see SetPartSize) is uploaded with a single PutObject; larger
+// content is streamed as a multipart upload without knowing its length in
+// advance, holding one part in memory at a time. Failed multipart uploads
+// are aborted; add a lifecycle rule removing incomplete uploads to clean up
+// after crashes.
+//
+// Presigned URLs use SigV4 and are issued without a call to S3.
+//
+// Usage with Amazon S3:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	store := s3.NewStore(awss3.NewFromConfig(cfg), "dago-artifacts", logger)
+//
+// Usage with MinIO:
+//
+//	client := awss3.NewFromConfig(cfg, func(o *awss3.Options) {
+//		o.BaseEndpoint = aws.String("http://minio:9000")
+//		o.UsePathStyle = true
+//	})
+//	store := s3.NewStore(client, "dago-artifacts", logger)
+//
+//	url, err := store.PresignGet(ctx, "executions/exec-1/report.pdf", 15*time.Minute)
+package s3
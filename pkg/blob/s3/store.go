@@ -0,0 +1,338 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default size of multipart upload parts; S3 requires at least 5 MiB
+	defaultPartSize = 8 << 20
+
+	minPartSize = 5 << 20
+)
+
+// Client is the subset of the S3 API used by the store.
+// It is satisfied by *s3.Client.
+type Client interface {
+	PutObject(ctx context.Context, params *awss3.PutObjectInput, optFns ...func(*awss3.Options)) (*awss3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *awss3.GetObjectInput, optFns ...func(*awss3.Options)) (*awss3.GetObjectOutput, error)
+	HeadObject(ctx context.Context, params *awss3.HeadObjectInput, optFns ...func(*awss3.Options)) (*awss3.HeadObjectOutput, error)
+	DeleteObject(ctx context.Context, params *awss3.DeleteObjectInput, optFns ...func(*awss3.Options)) (*awss3.DeleteObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *awss3.ListObjectsV2Input, optFns ...func(*awss3.Options)) (*awss3.ListObjectsV2Output, error)
+	CreateMultipartUpload(ctx context.Context, params *awss3.CreateMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *awss3.UploadPartInput, optFns ...func(*awss3.Options)) (*awss3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *awss3.CompleteMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *awss3.AbortMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.AbortMultipartUploadOutput, error)
+}
+
+// Presigner is the subset of the S3 presign API used by the store.
+// It is satisfied by *s3.PresignClient.
+type Presigner interface {
+	PresignGetObject(ctx context.Context, params *awss3.GetObjectInput, optFns ...func(*awss3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+	PresignPutObject(ctx context.Context, params *awss3.PutObjectInput, optFns ...func(*awss3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+}
+
+// Store implements ports.BlobStore using an S3 bucket. It works with Amazon
+// S3 and S3-compatible stores such as MinIO, Ceph or Cloudflare R2.
+type Store struct {
+	client    Client
+	presigner Presigner
+	bucket    string
+	logger    *zap.Logger
+
+	// Size of multipart upload parts, see SetPartSize
+	partSize int
+}
+
+// NewStore creates a new S3 blob store for the bucket. Presigned URLs are
+// available when client is an *s3.Client, or after SetPresigner.
+func NewStore(client Client, bucket string, logger *zap.Logger) *Store {
+	s := &Store{
+		client:   client,
+		bucket:   bucket,
+		logger:   logger,
+		partSize: defaultPartSize,
+	}
+	if c, ok := client.(*awss3.Client); ok {
+		s.presigner = awss3.NewPresignClient(c)
+	}
+	return s
+}
+
+// SetPresigner sets the client issuing presigned URLs
+func (s *Store) SetPresigner(presigner Presigner) {
+	s.presigner = presigner
+}
+
+// SetPartSize sets the size of the parts that content larger than one part
+// is uploaded in, at least 5 MiB. Each upload buffers one part in memory.
+// Call it before the store is in use.
+func (s *Store) SetPartSize(size int) {
+	s.partSize = max(size, minPartSize)
+}
+
+// Put uploads the blob (ports.BlobStore interface). Content that fits in one
+// part is uploaded with a single PutObject, larger content is streamed as a
+// multipart upload.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, opts ports.PutOptions) (*ports.BlobInfo, error) {
+	first := make([]byte, s.partSize)
+	n, err := io.ReadFull(r, first)
+	switch {
+	case err == nil:
+		err = s.putMultipart(ctx, key, first, r, opts)
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		_, err = s.client.PutObject(ctx, &awss3.PutObjectInput{
+			Bucket:        aws.String(s.bucket),
+			Key:           aws.String(key),
+			Body:          bytes.NewReader(first[:n]),
+			ContentLength: aws.Int64(int64(n)),
+			ContentType:   optionalString(opts.ContentType),
+			Metadata:      opts.Metadata,
+		})
+	default:
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to put blob: %w", err)
+	}
+
+	s.logger.Debug("blob stored",
+		zap.String("bucket", s.bucket),
+		zap.String("key", key))
+
+	return s.Stat(ctx, key)
+}
+
+// Get opens the object for streaming (ports.BlobStore interface)
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, *ports.BlobInfo, error) {
+	out, err := s.client.GetObject(ctx, &awss3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil, fmt.Errorf("%w: %s", ports.ErrBlobNotFound, key)
+		}
+		return nil, nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+
+	info := &ports.BlobInfo{
+		Key:         key,
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+		ETag:        strings.Trim(aws.ToString(out.ETag), `"`),
+		Metadata:    out.Metadata,
+		ModifiedAt:  aws.ToTime(out.LastModified),
+	}
+	return out.Body, info, nil
+}
+
+// Stat returns the object's description (ports.BlobStore interface)
+func (s *Store) Stat(ctx context.Context, key string) (*ports.BlobInfo, error) {
+	out, err := s.client.HeadObject(ctx, &awss3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrBlobNotFound, key)
+		}
+		return nil, fmt.Errorf("failed to stat blob: %w", err)
+	}
+
+	return &ports.BlobInfo{
+		Key:         key,
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+		ETag:        strings.Trim(aws.ToString(out.ETag), `"`),
+		Metadata:    out.Metadata,
+		ModifiedAt:  aws.ToTime(out.LastModified),
+	}, nil
+}
+
+// Delete removes the object (ports.BlobStore interface)
+func (s *Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &awss3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// List returns the objects whose keys start with prefix (ports.BlobStore
+// interface). Listings don't include content types or metadata.
+func (s *Store) List(ctx context.Context, prefix string) ([]ports.BlobInfo, error) {
+	var blobs []ports.BlobInfo
+
+	input := &awss3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	for {
+		out, err := s.client.ListObjectsV2(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blobs: %w", err)
+		}
+
+		for _, obj := range out.Contents {
+			blobs = append(blobs, ports.BlobInfo{
+				Key:        aws.ToString(obj.Key),
+				Size:       aws.ToInt64(obj.Size),
+				ETag:       strings.Trim(aws.ToString(obj.ETag), `"`),
+				ModifiedAt: aws.ToTime(obj.LastModified),
+			})
+		}
+
+		if !aws.ToBool(out.IsTruncated) {
+			break
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+
+	return blobs, nil
+}
+
+// PresignGet returns a presigned GetObject URL (ports.BlobStore interface)
+func (s *Store) PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if s.presigner == nil {
+		return "", ports.ErrPresignNotSupported
+	}
+
+	req, err := s.presigner.PresignGetObject(ctx, &awss3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, awss3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign blob download: %w", err)
+	}
+	return req.URL, nil
+}
+
+// PresignPut returns a presigned PutObject URL (ports.BlobStore interface)
+func (s *Store) PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if s.presigner == nil {
+		return "", ports.ErrPresignNotSupported
+	}
+
+	req, err := s.presigner.PresignPutObject(ctx, &awss3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, awss3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign blob upload: %w", err)
+	}
+	return req.URL, nil
+}
+
+// putMultipart uploads first and the rest of r part by part, aborting the
+// upload on failure so no orphaned parts are left behind
+func (s *Store) putMultipart(ctx context.Context, key string, first []byte, r io.Reader, opts ports.PutOptions) error {
+	created, err := s.client.CreateMultipartUpload(ctx, &awss3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: optionalString(opts.ContentType),
+		Metadata:    opts.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	var parts []types.CompletedPart
+	upload := func(part []byte) error {
+		number := int32(len(parts) + 1)
+		out, err := s.client.UploadPart(ctx, &awss3.UploadPartInput{
+			Bucket:        aws.String(s.bucket),
+			Key:           aws.String(key),
+			UploadId:      created.UploadId,
+			PartNumber:    aws.Int32(number),
+			Body:          bytes.NewReader(part),
+			ContentLength: aws.Int64(int64(len(part))),
+		})
+		if err != nil {
+			return err
+		}
+		parts = append(parts, types.CompletedPart{ETag: out.ETag, PartNumber: aws.Int32(number)})
+		return nil
+	}
+
+	err = upload(first)
+	buf := first
+	for err == nil {
+		var n int
+		n, err = io.ReadFull(r, buf)
+		if n > 0 {
+			if uploadErr := upload(buf[:n]); uploadErr != nil {
+				err = uploadErr
+				break
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = nil
+			break
+		}
+	}
+
+	if err == nil {
+		_, err = s.client.CompleteMultipartUpload(ctx, &awss3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(s.bucket),
+			Key:             aws.String(key),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+	if err != nil {
+		// Use a fresh context: ctx may be the reason the upload failed
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if _, abortErr := s.client.AbortMultipartUpload(abortCtx, &awss3.AbortMultipartUploadInput{
+			Bucket:   aws.String(s.bucket),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		}); abortErr != nil {
+			s.logger.Warn("failed to abort multipart upload",
+				zap.String("bucket", s.bucket),
+				zap.String("key", key),
+				zap.Error(abortErr))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// isNotFound recognizes missing objects, which S3 reports as NoSuchKey on
+// GetObject and as NotFound on HeadObject
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchKey", "NotFound":
+			return true
+		}
+	}
+	return false
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
@@ -0,0 +1,241 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"go.uber.org/zap"
+)
+
+var (
+	_ Client    = (*awss3.Client)(nil)
+	_ Presigner = (*awss3.PresignClient)(nil)
+)
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+	metadata    map[string]string
+	modified    time.Time
+}
+
+// fakeClient is an in-memory bucket
+type fakeClient struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	uploads map[string][][]byte
+	aborted int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{objects: make(map[string]fakeObject), uploads: make(map[string][][]byte)}
+}
+
+var errNoSuchKey = &smithy.GenericAPIError{Code: "NoSuchKey", Message: "The specified key does not exist."}
+
+func (f *fakeClient) PutObject(ctx context.Context, params *awss3.PutObjectInput, optFns ...func(*awss3.Options)) (*awss3.PutObjectOutput, error) {
+	data, _ := io.ReadAll(params.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[aws.ToString(params.Key)] = fakeObject{data: data, contentType: aws.ToString(params.ContentType), metadata: params.Metadata, modified: time.Now()}
+	return &awss3.PutObjectOutput{}, nil
+}
+
+func (f *fakeClient) GetObject(ctx context.Context, params *awss3.GetObjectInput, optFns ...func(*awss3.Options)) (*awss3.GetObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[aws.ToString(params.Key)]
+	if !ok {
+		return nil, errNoSuchKey
+	}
+	return &awss3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewReader(obj.data)),
+		ContentLength: aws.Int64(int64(len(obj.data))),
+		ContentType:   aws.String(obj.contentType),
+		Metadata:      obj.metadata,
+		LastModified:  aws.Time(obj.modified),
+	}, nil
+}
+
+func (f *fakeClient) HeadObject(ctx context.Context, params *awss3.HeadObjectInput, optFns ...func(*awss3.Options)) (*awss3.HeadObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[aws.ToString(params.Key)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "NotFound", Message: "Not Found"}
+	}
+	return &awss3.HeadObjectOutput{
+		ContentLength: aws.Int64(int64(len(obj.data))),
+		ContentType:   aws.String(obj.contentType),
+		ETag:          aws.String(`"etag-` + strconv.Itoa(len(obj.data)) + `"`),
+		Metadata:      obj.metadata,
+		LastModified:  aws.Time(obj.modified),
+	}, nil
+}
+
+func (f *fakeClient) DeleteObject(ctx context.Context, params *awss3.DeleteObjectInput, optFns ...func(*awss3.Options)) (*awss3.DeleteObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, aws.ToString(params.Key))
+	return &awss3.DeleteObjectOutput{}, nil
+}
+
+// ListObjectsV2 returns one object per page to exercise pagination
+func (f *fakeClient) ListObjectsV2(ctx context.Context, params *awss3.ListObjectsV2Input, optFns ...func(*awss3.Options)) (*awss3.ListObjectsV2Output, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, aws.ToString(params.Prefix)) && key > aws.ToString(params.ContinuationToken) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return &awss3.ListObjectsV2Output{IsTruncated: aws.Bool(false)}, nil
+	}
+
+	obj := f.objects[keys[0]]
+	return &awss3.ListObjectsV2Output{
+		Contents:              []types.Object{{Key: aws.String(keys[0]), Size: aws.Int64(int64(len(obj.data)))}},
+		IsTruncated:           aws.Bool(len(keys) > 1),
+		NextContinuationToken: aws.String(keys[0]),
+	}, nil
+}
+
+func (f *fakeClient) CreateMultipartUpload(ctx context.Context, params *awss3.CreateMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CreateMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := "upload-" + aws.ToString(params.Key)
+	f.uploads[id] = nil
+	return &awss3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (f *fakeClient) UploadPart(ctx context.Context, params *awss3.UploadPartInput, optFns ...func(*awss3.Options)) (*awss3.UploadPartOutput, error) {
+	data, _ := io.ReadAll(params.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(params.UploadId)
+	f.uploads[id] = append(f.uploads[id], data)
+	return &awss3.UploadPartOutput{ETag: aws.String(strconv.Itoa(int(aws.ToInt32(params.PartNumber))))}, nil
+}
+
+func (f *fakeClient) CompleteMultipartUpload(ctx context.Context, params *awss3.CompleteMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(params.UploadId)
+	f.objects[aws.ToString(params.Key)] = fakeObject{data: bytes.Join(f.uploads[id], nil), modified: time.Now()}
+	delete(f.uploads, id)
+	return &awss3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (f *fakeClient) AbortMultipartUpload(ctx context.Context, params *awss3.AbortMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.AbortMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.uploads, aws.ToString(params.UploadId))
+	f.aborted++
+	return &awss3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(newFakeClient(), "dago-artifacts", zap.NewNop())
+
+	info, err := store.Put(ctx, "executions/exec-1/prompt.txt", strings.NewReader("prompt"), ports.PutOptions{
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"node": "plan"},
+	})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if info.Size != 6 || info.ContentType != "text/plain" || info.Metadata["node"] != "plan" || info.ETag != "etag-6" {
+		t.Errorf("Put() info = %+v", info)
+	}
+
+	r, _, err := store.Get(ctx, "executions/exec-1/prompt.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	_ = r.Close()
+	if string(data) != "prompt" {
+		t.Errorf("Get() = %q, want prompt", data)
+	}
+
+	if _, _, err := store.Get(ctx, "missing"); !errors.Is(err, ports.ErrBlobNotFound) {
+		t.Errorf("Get() of a missing blob error = %v, want ErrBlobNotFound", err)
+	}
+	if _, err := store.Stat(ctx, "missing"); !errors.Is(err, ports.ErrBlobNotFound) {
+		t.Errorf("Stat() of a missing blob error = %v, want ErrBlobNotFound", err)
+	}
+
+	for _, key := range []string{"executions/exec-1/a", "executions/exec-2/b"} {
+		if _, err := store.Put(ctx, key, strings.NewReader(key), ports.PutOptions{}); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	blobs, err := store.List(ctx, "executions/exec-1/")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(blobs) != 2 || blobs[0].Key != "executions/exec-1/a" || blobs[1].Key != "executions/exec-1/prompt.txt" {
+		t.Errorf("List() = %+v, want both exec-1 blobs", blobs)
+	}
+
+	if _, err := store.PresignGet(ctx, "executions/exec-1/a", time.Minute); !errors.Is(err, ports.ErrPresignNotSupported) {
+		t.Errorf("PresignGet() without presigner error = %v", err)
+	}
+}
+
+func TestStore_Multipart(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	store := NewStore(client, "dago-artifacts", zap.NewNop())
+	store.SetPartSize(minPartSize)
+
+	content := bytes.Repeat([]byte("0123456789"), (2*minPartSize+1000)/10)
+	if _, err := store.Put(ctx, "documents/large.bin", bytes.NewReader(content), ports.PutOptions{}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if got := client.objects["documents/large.bin"].data; !bytes.Equal(got, content) {
+		t.Errorf("multipart upload stored %d bytes, want %d", len(got), len(content))
+	}
+	if len(client.uploads) != 0 || client.aborted != 0 {
+		t.Errorf("uploads left = %d, aborted = %d", len(client.uploads), client.aborted)
+	}
+}
+
+func TestStore_Presign(t *testing.T) {
+	client := awss3.New(awss3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String("http://minio:9000"),
+		UsePathStyle: true,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "minio", SecretAccessKey: "minio123"}, nil
+		}),
+	})
+	store := NewStore(client, "dago-artifacts", zap.NewNop())
+
+	url, err := store.PresignGet(context.Background(), "documents/report.pdf", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet() error = %v", err)
+	}
+	if !strings.HasPrefix(url, "http://minio:9000/dago-artifacts/documents/report.pdf?") || !strings.Contains(url, "X-Amz-Expires=900") {
+		t.Errorf("PresignGet() = %s", url)
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"golang.org/x/sync/singleflight"
+)
+
+// Loader implements GetOrLoad for caches: it deduplicates concurrent loads
+// of a key in the process. Embed it in a cache and call Load.
+type Loader struct {
+	group singleflight.Group
+}
+
+// Load returns the value of key in c, loading and storing it on a miss. A
+// cache that can't be read or written counts as a miss, so an unavailable
+// cache slows callers down rather than failing them.
+func (l *Loader) Load(ctx context.Context, c ports.Cache, key string, ttl time.Duration, load ports.CacheLoader) ([]byte, error) {
+	if value, err := c.Get(ctx, key); err == nil {
+		return value, nil
+	}
+
+	v, err, _ := l.group.Do(key, func() (interface{}, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		_ = c.Set(ctx, key, value, ttl)
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// GetOrLoadJSON is GetOrLoad for values stored as JSON
+func GetOrLoadJSON[T any](ctx context.Context, c ports.Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	data, err := c.GetOrLoad(ctx, key, ttl, func(ctx context.Context) ([]byte, error) {
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(loaded)
+	})
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("failed to unmarshal cached value of %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Key returns a cache key for a value too long or sensitive to be a key
+// itself, such as a request: the SHA-256 of its JSON encoding, after prefix
+func Key(prefix string, value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cache key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return prefix + hex.EncodeToString(sum[:]), nil
+}
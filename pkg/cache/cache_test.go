@@ -0,0 +1,55 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/cache"
+	"github.com/aescanero/dago-adapters/pkg/cache/lru"
+)
+
+type model struct {
+	Name    string `json:"name"`
+	Context int    `json:"context"`
+}
+
+func TestGetOrLoadJSON(t *testing.T) {
+	ctx := context.Background()
+	c := lru.NewCache(10)
+
+	loads := 0
+	load := func(ctx context.Context) (model, error) {
+		loads++
+		return model{Name: "llama3.1", Context: 131072}, nil
+	}
+	for i := 0; i < 2; i++ {
+		got, err := cache.GetOrLoadJSON(ctx, c, "model:llama3.1", 0, load)
+		if err != nil || got.Context != 131072 {
+			t.Fatalf("GetOrLoadJSON() = %+v, %v", got, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loaded %d times, want 1", loads)
+	}
+
+	_, err := cache.GetOrLoadJSON(ctx, c, "model:missing", 0, func(ctx context.Context) (model, error) {
+		return model{}, errors.New("not found")
+	})
+	if err == nil || err.Error() != "not found" {
+		t.Errorf("GetOrLoadJSON() error = %v", err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	a, err := cache.Key("llm:", map[string]string{"prompt": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := cache.Key("llm:", map[string]string{"prompt": "hi"})
+	c, _ := cache.Key("llm:", map[string]string{"prompt": "hello"})
+	if a != b || a == c || !strings.HasPrefix(a, "llm:") || len(a) != len("llm:")+64 {
+		t.Errorf("Key() = %q, %q, %q", a, b, c)
+	}
+}
@@ -0,0 +1,23 @@
+// Package cache provides adapters for the ports.Cache interface (pkg/ports
+// in this repository), and helpers shared by the features that cache
+// through it: LLM responses (llm.NewCachedClient), embeddings
+// (embeddings.WithSharedCache) and worker listings
+// (worker_registry.CachedRegistry.SetCache).
+//
+// Values are bytes; GetOrLoadJSON stores any JSON-encodable value, and Key
+// derives short keys from requests. Implementations embed Loader for
+// GetOrLoad, which deduplicates concurrent loads of a key and treats an
+// unavailable cache as a miss.
+//
+// Available implementations:
+//   - lru: In process, bounded by entry count
+//   - redis: Shared by every process using the same Redis
+//
+// Usage:
+//
+//	c := lru.NewCache(1000)
+//
+//	info, err := cache.GetOrLoadJSON(ctx, c, "model:"+name, time.Hour, func(ctx context.Context) (ModelInfo, error) {
+//		return fetchModelInfo(ctx, name)
+//	})
+package cache
@@ -0,0 +1,113 @@
+package lru
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/cache"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// Cache implements ports.Cache in process memory. It holds up to a fixed
+// number of entries, evicting the least recently used first; expired
+// entries are dropped when they are read or evicted. Values are shared with
+// the cache and must not be modified.
+type Cache struct {
+	cache.Loader
+
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+// NewCache creates a cache of up to size entries
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// Get returns the value of key (ports.Cache interface)
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrCacheMiss, key)
+	}
+	e := elem.Value.(*entry)
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		c.remove(elem)
+		return nil, fmt.Errorf("%w: %s", ports.ErrCacheMiss, key)
+	}
+	c.order.MoveToFront(elem)
+	return e.value, nil
+}
+
+// Set stores value under key (ports.Cache interface)
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Delete removes key (ports.Cache interface)
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+// GetOrLoad returns the value of key, loading it on a miss (ports.Cache
+// interface)
+func (c *Cache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load ports.CacheLoader) ([]byte, error) {
+	return c.Load(ctx, c, key, ttl, load)
+}
+
+// Len returns the number of entries, including expired ones not yet dropped
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
@@ -0,0 +1,100 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+var _ ports.Cache = (*Cache)(nil)
+
+func TestCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache(2)
+
+	_ = c.Set(ctx, "a", []byte("1"), 0)
+	_ = c.Set(ctx, "b", []byte("2"), 0)
+	// Reading a makes b the least recently used
+	if v, err := c.Get(ctx, "a"); err != nil || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v", v, err)
+	}
+	_ = c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get(b) error = %v, want miss", err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	_ = c.Delete(ctx, "a")
+	_ = c.Delete(ctx, "missing")
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get(a) after Delete() error = %v, want miss", err)
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewCache(10)
+	c.now = func() time.Time { return now }
+
+	_ = c.Set(ctx, "k", []byte("v"), time.Minute)
+	now = now.Add(59 * time.Second)
+	if _, err := c.Get(ctx, "k"); err != nil {
+		t.Errorf("Get() before expiry error = %v", err)
+	}
+	now = now.Add(time.Second)
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get() after expiry error = %v, want miss", err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want the expired entry dropped", c.Len())
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache(10)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) ([]byte, error) {
+		loads.Add(1)
+		<-release
+		return []byte("loaded"), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.GetOrLoad(ctx, "k", 0, load); err != nil || string(v) != "loaded" {
+				t.Errorf("GetOrLoad() = %q, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("loaded %d times, want 1", loads.Load())
+	}
+
+	// Errors are not cached
+	fail := errors.New("unavailable")
+	if _, err := c.GetOrLoad(ctx, "bad", 0, func(ctx context.Context) ([]byte, error) { return nil, fail }); !errors.Is(err, fail) {
+		t.Errorf("GetOrLoad() error = %v", err)
+	}
+	if _, err := c.Get(ctx, "bad"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get() of a failed load error = %v, want miss", err)
+	}
+}
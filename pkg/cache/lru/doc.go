@@ -0,0 +1,9 @@
+// Package lru provides an in-process implementation of the Cache interface
+// (pkg/ports in this repository), holding a fixed number of entries and
+// evicting the least recently used first.
+//
+// Usage:
+//
+//	c := lru.NewCache(10000)
+//	value, err := c.GetOrLoad(ctx, key, time.Minute, load)
+package lru
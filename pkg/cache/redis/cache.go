@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/cache"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Default prefix of cache keys
+const defaultPrefix = "dago:cache:"
+
+// Cache implements ports.Cache using Redis strings, expired by Redis. It is
+// shared by every process using the same Redis, so a value loaded by one
+// replica serves the others; concurrent misses are only deduplicated within
+// a process.
+type Cache struct {
+	cache.Loader
+
+	client redis.UniversalClient
+	prefix string
+	logger *zap.Logger
+}
+
+// NewCache creates a new Redis cache
+func NewCache(client redis.UniversalClient, logger *zap.Logger) *Cache {
+	return &Cache{
+		client: client,
+		prefix: defaultPrefix,
+		logger: logger,
+	}
+}
+
+// SetPrefix sets the prefix of cache keys, e.g. to isolate tenants
+func (c *Cache) SetPrefix(prefix string) {
+	c.prefix = prefix
+}
+
+// Get returns the value of key (ports.Cache interface)
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: %s", ports.ErrCacheMiss, key)
+	}
+	if err != nil {
+		c.logger.Warn("failed to read cache", zap.String("key", key), zap.Error(err))
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	return value, nil
+}
+
+// Set stores value under key (ports.Cache interface)
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := c.client.Set(ctx, c.prefix+key, value, ttl).Err(); err != nil {
+		c.logger.Warn("failed to write cache", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// Delete removes key (ports.Cache interface)
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to delete from cache: %w", err)
+	}
+	return nil
+}
+
+// GetOrLoad returns the value of key, loading it on a miss (ports.Cache
+// interface). If Redis is unavailable, values are loaded without caching.
+func (c *Cache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load ports.CacheLoader) ([]byte, error) {
+	return c.Load(ctx, c, key, ttl, load)
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ ports.Cache = (*Cache)(nil)
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewCache(client, zap.NewNop()), mr
+}
+
+func TestCache_SetGetDelete(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t)
+
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get() error = %v, want miss", err)
+	}
+
+	if err := c.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if v, err := c.Get(ctx, "k"); err != nil || string(v) != "v" {
+		t.Errorf("Get() = %q, %v", v, err)
+	}
+	if !mr.Exists("dago:cache:k") {
+		t.Error("key not stored under the default prefix")
+	}
+
+	mr.FastForward(time.Minute)
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get() after expiry error = %v, want miss", err)
+	}
+
+	_ = c.Set(ctx, "k", []byte("v"), 0)
+	if err := c.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Errorf("Get() after Delete() error = %v, want miss", err)
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t)
+	c.SetPrefix("tenant-a:")
+
+	loads := 0
+	load := func(ctx context.Context) ([]byte, error) {
+		loads++
+		return []byte("loaded"), nil
+	}
+	for i := 0; i < 2; i++ {
+		if v, err := c.GetOrLoad(ctx, "k", time.Minute, load); err != nil || string(v) != "loaded" {
+			t.Fatalf("GetOrLoad() = %q, %v", v, err)
+		}
+	}
+	if loads != 1 || !mr.Exists("tenant-a:k") {
+		t.Errorf("loads = %d, keys = %v", loads, mr.Keys())
+	}
+
+	// Without Redis, values are still loaded
+	mr.Close()
+	if v, err := c.GetOrLoad(ctx, "k", time.Minute, load); err != nil || string(v) != "loaded" || loads != 2 {
+		t.Errorf("GetOrLoad() without Redis = %q, %v, loads %d", v, err, loads)
+	}
+}
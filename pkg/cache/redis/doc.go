@@ -0,0 +1,14 @@
+// Package redis provides a Redis implementation of the Cache interface
+// (pkg/ports in this repository).
+//
+// Values are Redis strings under a key prefix ("dago:cache:" by default, see
+// SetPrefix), expired by Redis. Read and write failures are logged, and
+// GetOrLoad falls back to loading values directly, so an outage of the cache
+// doesn't fail its callers.
+//
+// Usage:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	c := rediscache.NewCache(client, logger)
+//	value, err := c.GetOrLoad(ctx, key, time.Hour, load)
+package redis
@@ -0,0 +1,190 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInjected is wrapped by every error the chaos wrappers inject
+	ErrInjected = errors.New("injected fault")
+
+	// ErrRateLimited mimics a provider rejecting a call with HTTP 429
+	ErrRateLimited = fmt.Errorf("%w: status 429: rate limit exceeded", ErrInjected)
+
+	// ErrUnavailable mimics a backend or provider failing with HTTP 503
+	ErrUnavailable = fmt.Errorf("%w: status 503: service unavailable", ErrInjected)
+
+	// ErrTimeout is returned by calls that hung until Faults.Timeout. It
+	// wraps context.DeadlineExceeded, like a client-side timeout would.
+	ErrTimeout = fmt.Errorf("%w: %w", ErrInjected, context.DeadlineExceeded)
+)
+
+// Fault is a kind of injected fault
+type Fault string
+
+const (
+	FaultLatency       Fault = "latency"
+	FaultTimeout       Fault = "timeout"
+	FaultRateLimit     Fault = "rate_limit"
+	FaultError         Fault = "error"
+	FaultMalformed     Fault = "malformed"
+	FaultPartialStream Fault = "partial_stream"
+)
+
+// Faults configures what the chaos wrappers inject. Rates are probabilities
+// between 0 and 1, drawn independently for each call; the zero value injects
+// nothing.
+type Faults struct {
+	// Latency delays every call, plus a random duration up to LatencyJitter
+	Latency       time.Duration
+	LatencyJitter time.Duration
+
+	// TimeoutRate makes calls hang until their context ends or Timeout
+	// elapses, whichever comes first, then fail with ErrTimeout (or the
+	// context's error). Timeout defaults to 30s.
+	TimeoutRate float64
+	Timeout     time.Duration
+
+	// RateLimitRate fails LLM calls with ErrRateLimited before they reach the
+	// provider
+	RateLimitRate float64
+
+	// ErrorRate fails calls with ErrUnavailable before they reach the
+	// wrapped client or registry
+	ErrorRate float64
+
+	// MalformedRate corrupts successful responses: LLM text is truncated
+	// into invalid UTF-8, tool call arguments and structured data are
+	// dropped, and workers lose their type, status and heartbeat
+	MalformedRate float64
+
+	// PartialStreamRate cuts LLM streams after a random number of chunks,
+	// without a final chunk, and ends registry watches early
+	PartialStreamRate float64
+}
+
+// Default bound of hung calls
+const defaultTimeout = 30 * time.Second
+
+// injector draws and counts the faults of a wrapper
+type injector struct {
+	mu     sync.Mutex
+	faults Faults
+	rand   *rand.Rand
+	counts map[Fault]int
+}
+
+func newInjector(faults Faults) *injector {
+	return &injector{
+		faults: faults,
+		rand:   rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		counts: make(map[Fault]int),
+	}
+}
+
+// SetFaults replaces the injected faults, e.g. to start or stop a chaos
+// phase of a test. Calls in progress keep their faults.
+func (i *injector) SetFaults(faults Faults) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = faults
+}
+
+// SetSeed makes fault draws deterministic, so that a failing chaos test can
+// be replayed. Concurrent calls still draw in scheduling order.
+func (i *injector) SetSeed(seed uint64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rand = rand.New(rand.NewPCG(seed, seed))
+}
+
+// Counts returns how many faults of each kind were injected so far
+func (i *injector) Counts() map[Fault]int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	counts := make(map[Fault]int, len(i.counts))
+	for fault, n := range i.counts {
+		counts[fault] = n
+	}
+	return counts
+}
+
+// draw reports whether a fault occurring at rate is injected, counting it
+func (i *injector) draw(fault Fault, rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.rand.Float64() >= rate {
+		return false
+	}
+	i.counts[fault]++
+	return true
+}
+
+// current returns the faults in effect
+func (i *injector) current() Faults {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.faults
+}
+
+// intn returns a random number in [0, n)
+func (i *injector) intn(n int) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.IntN(n)
+}
+
+// before injects the faults happening before a call reaches the wrapped
+// client: latency, timeouts and errors. rateLimit enables 429s.
+func (i *injector) before(ctx context.Context, faults Faults, rateLimit bool) error {
+	if faults.Latency > 0 || faults.LatencyJitter > 0 {
+		delay := faults.Latency
+		i.mu.Lock()
+		if faults.LatencyJitter > 0 {
+			delay += time.Duration(i.rand.Int64N(int64(faults.LatencyJitter)))
+		}
+		i.counts[FaultLatency]++
+		i.mu.Unlock()
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+
+	if i.draw(FaultTimeout, faults.TimeoutRate) {
+		timeout := faults.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+		if err := sleep(ctx, timeout); err != nil {
+			return err
+		}
+		return ErrTimeout
+	}
+	if rateLimit && i.draw(FaultRateLimit, faults.RateLimitRate) {
+		return ErrRateLimited
+	}
+	if i.draw(FaultError, faults.ErrorRate) {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// sleep waits for d or until ctx ends
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
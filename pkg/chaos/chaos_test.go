@@ -0,0 +1,321 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/memory"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// echoClient answers with the last message and streams it word by word
+type echoClient struct {
+	libports.LLMClient
+	calls int
+}
+
+func (c *echoClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	c.calls++
+	return &libports.CompletionResponse{
+		Message: libports.Message{Role: "assistant", Content: req.Messages[len(req.Messages)-1].Content},
+		ToolCalls: []libports.ToolCall{
+			{ID: "call-1", Name: "search", Arguments: map[string]interface{}{"q": "go"}},
+		},
+	}, nil
+}
+
+func (c *echoClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	c.calls++
+	return &libports.StructuredResponse{Data: map[string]interface{}{"answer": 42}}, nil
+}
+
+func (c *echoClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks := make(chan libports.CompletionChunk)
+	go func() {
+		defer close(chunks)
+		for i := 0; i < 8; i++ {
+			chunks <- libports.CompletionChunk{Delta: fmt.Sprintf("chunk %d ", i)}
+		}
+		chunks <- libports.CompletionChunk{IsFinal: true}
+	}()
+	return chunks, nil
+}
+
+var testRequest = libports.CompletionRequest{
+	Model:    "test-model",
+	Messages: []libports.Message{{Role: "user", Content: "Hello, World!"}},
+}
+
+func TestLLMClientPassthrough(t *testing.T) {
+	inner := &echoClient{}
+	client := NewLLMClient(inner, Faults{})
+
+	resp, err := client.Complete(context.Background(), testRequest)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Message.Content != "Hello, World!" {
+		t.Errorf("Complete() content = %q, want %q", resp.Message.Content, "Hello, World!")
+	}
+	if len(client.Counts()) != 0 {
+		t.Errorf("Counts() = %v, want no faults", client.Counts())
+	}
+}
+
+func TestLLMClientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults Faults
+		want   error
+		fault  Fault
+	}{
+		{"rate limit", Faults{RateLimitRate: 1}, ErrRateLimited, FaultRateLimit},
+		{"error", Faults{ErrorRate: 1}, ErrUnavailable, FaultError},
+		{"timeout", Faults{TimeoutRate: 1, Timeout: time.Millisecond}, context.DeadlineExceeded, FaultTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &echoClient{}
+			client := NewLLMClient(inner, tt.faults)
+
+			_, err := client.Complete(context.Background(), testRequest)
+			if !errors.Is(err, tt.want) || !errors.Is(err, ErrInjected) {
+				t.Fatalf("Complete() error = %v, want %v", err, tt.want)
+			}
+			if inner.calls != 0 {
+				t.Errorf("wrapped client called %d times, want 0", inner.calls)
+			}
+			if got := client.Counts()[tt.fault]; got != 1 {
+				t.Errorf("Counts()[%s] = %d, want 1", tt.fault, got)
+			}
+		})
+	}
+}
+
+func TestLLMClientTimeoutHonorsContext(t *testing.T) {
+	client := NewLLMClient(&echoClient{}, Faults{TimeoutRate: 1, Timeout: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Complete(ctx, testRequest)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Complete() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Complete() took %v, want it to end with the context", elapsed)
+	}
+}
+
+func TestLLMClientLatency(t *testing.T) {
+	client := NewLLMClient(&echoClient{}, Faults{Latency: 20 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := client.Complete(context.Background(), testRequest); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Complete() took %v, want at least 20ms", elapsed)
+	}
+}
+
+func TestLLMClientMalformed(t *testing.T) {
+	client := NewLLMClient(&echoClient{}, Faults{MalformedRate: 1})
+	ctx := context.Background()
+
+	resp, err := client.Complete(ctx, testRequest)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if utf8.ValidString(resp.Message.Content) {
+		t.Errorf("Complete() content = %q, want invalid UTF-8", resp.Message.Content)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Arguments != nil {
+		t.Errorf("Complete() tool calls = %+v, want one without arguments", resp.ToolCalls)
+	}
+
+	structured, err := client.CompleteStructured(ctx, testRequest, libports.JSONSchema{"type": "object"})
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if structured.Data != nil {
+		t.Errorf("CompleteStructured() data = %v, want nil", structured.Data)
+	}
+}
+
+func TestLLMClientStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		faults    Faults
+		wantFinal bool
+	}{
+		{"no faults", Faults{}, true},
+		{"partial", Faults{PartialStreamRate: 1}, false},
+		{"malformed", Faults{MalformedRate: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewLLMClient(&echoClient{}, tt.faults)
+
+			chunks, err := client.StreamComplete(context.Background(), testRequest)
+			if err != nil {
+				t.Fatalf("StreamComplete() error = %v", err)
+			}
+
+			var final bool
+			var n int
+			for chunk := range chunks {
+				n++
+				final = final || chunk.IsFinal
+				if tt.faults.MalformedRate > 0 && chunk.Delta != "" && utf8.ValidString(chunk.Delta) {
+					t.Errorf("chunk %q, want invalid UTF-8", chunk.Delta)
+				}
+			}
+			if final != tt.wantFinal {
+				t.Errorf("final chunk received = %v, want %v (%d chunks)", final, tt.wantFinal, n)
+			}
+		})
+	}
+}
+
+func TestLLMClientStreamNotSupported(t *testing.T) {
+	client := NewLLMClient(&struct{ libports.LLMClient }{}, Faults{})
+
+	if _, err := client.StreamComplete(context.Background(), testRequest); !errors.Is(err, ports.ErrNotImplemented) {
+		t.Errorf("StreamComplete() error = %v, want ErrNotImplemented", err)
+	}
+}
+
+func TestSetSeedReplaysFaults(t *testing.T) {
+	run := func() []bool {
+		client := NewLLMClient(&echoClient{}, Faults{ErrorRate: 0.5})
+		client.SetSeed(7)
+
+		var failures []bool
+		for i := 0; i < 20; i++ {
+			_, err := client.Complete(context.Background(), testRequest)
+			failures = append(failures, err != nil)
+		}
+		return failures
+	}
+
+	first, second := run(), run()
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("faults differ between runs with the same seed:\n%v\n%v", first, second)
+	}
+}
+
+func TestSetFaults(t *testing.T) {
+	client := NewLLMClient(&echoClient{}, Faults{ErrorRate: 1})
+	ctx := context.Background()
+
+	if _, err := client.Complete(ctx, testRequest); err == nil {
+		t.Fatal("Complete() error = nil, want injected error")
+	}
+
+	client.SetFaults(Faults{})
+	if _, err := client.Complete(ctx, testRequest); err != nil {
+		t.Errorf("Complete() after SetFaults error = %v", err)
+	}
+}
+
+func TestWorkerRegistry(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewRegistry(zap.NewNop())
+	r := NewWorkerRegistry(inner, Faults{RateLimitRate: 1})
+
+	worker := libports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          libports.WorkerTypeExecutor,
+		Status:        libports.WorkerStatusIdle,
+		LastHeartbeat: time.Now(),
+	}
+	// Rate limiting doesn't apply to registries
+	if err := r.Register(ctx, worker); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	r.SetFaults(Faults{ErrorRate: 1})
+	if _, err := r.ListWorkers(ctx, libports.WorkerFilter{}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("ListWorkers() error = %v, want ErrUnavailable", err)
+	}
+	if err := r.Heartbeat(ctx, worker.ID, libports.WorkerStatusBusy, "task-1"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Heartbeat() error = %v, want ErrUnavailable", err)
+	}
+
+	r.SetFaults(Faults{MalformedRate: 1})
+	got, err := r.GetWorker(ctx, worker.ID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if got.Type != "" || got.Status != "" || !got.LastHeartbeat.IsZero() {
+		t.Errorf("GetWorker() = %+v, want a malformed worker", got)
+	}
+	workers, err := r.ListWorkers(ctx, libports.WorkerFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if len(workers) != 1 || workers[0].Type != "" {
+		t.Errorf("ListWorkers() = %+v, want one malformed worker", workers)
+	}
+
+	// The wrapped registry is untouched
+	stored, err := inner.GetWorker(ctx, worker.ID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if stored.Type != libports.WorkerTypeExecutor {
+		t.Errorf("stored worker type = %q, want %q", stored.Type, libports.WorkerTypeExecutor)
+	}
+}
+
+func TestWorkerRegistryPartialWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inner := memory.NewRegistry(zap.NewNop())
+	r := NewWorkerRegistry(inner, Faults{PartialStreamRate: 1})
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	const registered = 5
+	for i := 0; i < registered; i++ {
+		worker := libports.WorkerInfo{
+			ID:     fmt.Sprintf("executor-%d", i),
+			Type:   libports.WorkerTypeExecutor,
+			Status: libports.WorkerStatusIdle,
+		}
+		if err := inner.Register(ctx, worker); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	var n int
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				if n >= registered {
+					t.Errorf("watch delivered %d events before ending, want fewer than %d", n, registered)
+				}
+				return
+			}
+			n++
+		case <-timeout:
+			t.Fatalf("watch still open after %d events, want it cut", n)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Package chaos wraps LLM clients and worker registries with fault
+// injection, to test how agents, routers and retry logic behave when
+// providers and backends misbehave: latency, calls hanging until they time
+// out, 429 rate limits, 503 errors, malformed responses and streams or
+// watches cut short.
+//
+// Faults are drawn independently for each call at the configured rates.
+// Injected errors wrap ErrInjected, and timeouts also wrap
+// context.DeadlineExceeded, so callers' retry checks see them as real ones.
+//
+// Usage:
+//
+//	client := chaos.NewLLMClient(provider, chaos.Faults{
+//		Latency:       200 * time.Millisecond,
+//		LatencyJitter: 300 * time.Millisecond,
+//		RateLimitRate: 0.1,
+//		MalformedRate: 0.05,
+//	})
+//	client.SetSeed(42) // replay the same faults
+//	...
+//	client.SetFaults(chaos.Faults{}) // recovery phase
+//	t.Logf("injected: %v", client.Counts())
+//
+// The wrappers are for tests and staging; they add no overhead beyond a
+// mutex when no fault is configured, but shouldn't be left in production
+// wiring.
+package chaos
@@ -0,0 +1,169 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// LLMClient wraps an LLM client, injecting faults into its calls, to test
+// how callers cope with slow, failing and misbehaving providers
+type LLMClient struct {
+	*injector
+	client libports.LLMClient
+}
+
+// NewLLMClient wraps client, injecting faults
+func NewLLMClient(client libports.LLMClient, faults Faults) *LLMClient {
+	return &LLMClient{
+		injector: newInjector(faults),
+		client:   client,
+	}
+}
+
+// Complete generates a completion, subject to faults
+func (c *LLMClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	faults := c.current()
+	if err := c.before(ctx, faults, true); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Complete(ctx, req)
+	if err != nil || !c.draw(FaultMalformed, faults.MalformedRate) {
+		return resp, err
+	}
+	return malformResponse(resp), nil
+}
+
+// CompleteWithTools generates a completion with tools, subject to faults
+func (c *LLMClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	faults := c.current()
+	if err := c.before(ctx, faults, true); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.CompleteWithTools(ctx, req, tools)
+	if err != nil || !c.draw(FaultMalformed, faults.MalformedRate) {
+		return resp, err
+	}
+	return malformResponse(resp), nil
+}
+
+// CompleteStructured generates a structured completion, subject to faults.
+// Malformed responses have no data.
+func (c *LLMClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	faults := c.current()
+	if err := c.before(ctx, faults, true); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.CompleteStructured(ctx, req, schema)
+	if err != nil || resp == nil || !c.draw(FaultMalformed, faults.MalformedRate) {
+		return resp, err
+	}
+	malformed := *resp
+	malformed.Data = nil
+	return &malformed, nil
+}
+
+// GenerateCompletion generates a completion, subject to latency, timeouts
+// and errors. Its responses are opaque, so they are never malformed.
+func (c *LLMClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	if err := c.before(ctx, c.current(), true); err != nil {
+		return nil, err
+	}
+	return c.client.GenerateCompletion(ctx, req)
+}
+
+// StreamComplete streams a completion from the wrapped client, subject to
+// faults. Faults before the call fail it like Complete; partial streams are
+// closed after a random number of chunks, without a final chunk; malformed
+// chunks are cut into invalid UTF-8.
+func (c *LLMClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	streamer, ok := c.client.(interface {
+		StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("%w: streaming", ports.ErrNotImplemented)
+	}
+
+	faults := c.current()
+	if err := c.before(ctx, faults, true); err != nil {
+		return nil, err
+	}
+
+	chunks, err := streamer.StreamComplete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	cutAfter := -1
+	if c.draw(FaultPartialStream, faults.PartialStreamRate) {
+		cutAfter = c.intn(4)
+	}
+	malformed := c.draw(FaultMalformed, faults.MalformedRate)
+	if cutAfter < 0 && !malformed {
+		return chunks, nil
+	}
+
+	out := make(chan libports.CompletionChunk)
+	go func() {
+		// Drain the wrapped stream once cut, so its producer can finish
+		defer func() {
+			for range chunks {
+			}
+		}()
+		defer close(out)
+
+		for sent := 0; sent != cutAfter; {
+			chunk, ok := <-chunks
+			if !ok || (chunk.IsFinal && cutAfter >= 0) {
+				return
+			}
+			if malformed && chunk.Delta != "" {
+				chunk.Delta = malformText(chunk.Delta)
+			}
+			select {
+			case out <- chunk:
+				sent++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Close closes the wrapped client if it has a Close method
+func (c *LLMClient) Close() error {
+	if closer, ok := c.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// malformResponse returns a copy of resp with its text cut into invalid
+// UTF-8 and its tool calls stripped of their arguments
+func malformResponse(resp *libports.CompletionResponse) *libports.CompletionResponse {
+	if resp == nil {
+		return nil
+	}
+	malformed := *resp
+	malformed.Message.Content = malformText(resp.Message.Content)
+	malformed.ToolCalls = nil
+	for _, call := range resp.ToolCalls {
+		call.Arguments = nil
+		malformed.ToolCalls = append(malformed.ToolCalls, call)
+	}
+	return &malformed
+}
+
+// malformText cuts s in half and appends a byte that is never valid UTF-8,
+// like a response truncated mid-character
+func malformText(s string) string {
+	return s[:len(s)/2] + "\xff"
+}
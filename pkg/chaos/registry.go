@@ -0,0 +1,168 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	localports "github.com/aescanero/dago-adapters/pkg/ports"
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// WorkerRegistry wraps a worker registry, injecting faults into its calls,
+// to test how routers and workers cope with a slow or flaky backend. Rate
+// limiting doesn't apply to registries.
+type WorkerRegistry struct {
+	*injector
+	registry ports.WorkerRegistry
+}
+
+// NewWorkerRegistry wraps registry, injecting faults
+func NewWorkerRegistry(registry ports.WorkerRegistry, faults Faults) *WorkerRegistry {
+	return &WorkerRegistry{
+		injector: newInjector(faults),
+		registry: registry,
+	}
+}
+
+// Register registers a worker, subject to faults
+func (r *WorkerRegistry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	if err := r.before(ctx, r.current(), false); err != nil {
+		return err
+	}
+	return r.registry.Register(ctx, worker)
+}
+
+// Unregister removes a worker, subject to faults
+func (r *WorkerRegistry) Unregister(ctx context.Context, workerID string) error {
+	if err := r.before(ctx, r.current(), false); err != nil {
+		return err
+	}
+	return r.registry.Unregister(ctx, workerID)
+}
+
+// Heartbeat updates a worker's status, subject to faults
+func (r *WorkerRegistry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	if err := r.before(ctx, r.current(), false); err != nil {
+		return err
+	}
+	return r.registry.Heartbeat(ctx, workerID, status, currentTask)
+}
+
+// GetWorker returns a worker, subject to faults
+func (r *WorkerRegistry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	faults := r.current()
+	if err := r.before(ctx, faults, false); err != nil {
+		return nil, err
+	}
+
+	worker, err := r.registry.GetWorker(ctx, workerID)
+	if err != nil || worker == nil || !r.draw(FaultMalformed, faults.MalformedRate) {
+		return worker, err
+	}
+	malformed := malformWorker(*worker)
+	return &malformed, nil
+}
+
+// ListWorkers lists workers, subject to faults. Malformed listings have one
+// corrupted worker.
+func (r *WorkerRegistry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	faults := r.current()
+	if err := r.before(ctx, faults, false); err != nil {
+		return nil, err
+	}
+
+	workers, err := r.registry.ListWorkers(ctx, filter)
+	if err != nil || len(workers) == 0 || !r.draw(FaultMalformed, faults.MalformedRate) {
+		return workers, err
+	}
+	malformed := append([]ports.WorkerInfo(nil), workers...)
+	i := r.intn(len(malformed))
+	malformed[i] = malformWorker(malformed[i])
+	return malformed, nil
+}
+
+// GetWorkerStats returns worker statistics, subject to latency, timeouts
+// and errors
+func (r *WorkerRegistry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	if err := r.before(ctx, r.current(), false); err != nil {
+		return nil, err
+	}
+	return r.registry.GetWorkerStats(ctx, workerType)
+}
+
+// CleanupStaleWorkers removes stale workers, subject to latency, timeouts
+// and errors
+func (r *WorkerRegistry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	if err := r.before(ctx, r.current(), false); err != nil {
+		return 0, err
+	}
+	return r.registry.CleanupStaleWorkers(ctx, timeout)
+}
+
+// Watch streams worker changes from the wrapped registry, subject to faults.
+// Partial watches end after a random number of events, as if the backend
+// connection dropped; malformed events carry corrupted workers.
+func (r *WorkerRegistry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	watcher, ok := r.registry.(registry.Watcher)
+	if !ok {
+		return nil, fmt.Errorf("%w: watch", localports.ErrNotImplemented)
+	}
+
+	faults := r.current()
+	if err := r.before(ctx, faults, false); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events, err := watcher.Watch(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	cutAfter := -1
+	if r.draw(FaultPartialStream, faults.PartialStreamRate) {
+		cutAfter = r.intn(4)
+	}
+
+	out := make(chan registry.WatchEvent)
+	go func() {
+		// Stop the wrapped watch once cut, and drain it so it can close
+		defer func() {
+			cancel()
+			for range events {
+			}
+		}()
+		defer close(out)
+
+		for sent := 0; sent != cutAfter; {
+			event, ok := <-events
+			if !ok {
+				return
+			}
+			if event.Worker != nil && r.draw(FaultMalformed, r.current().MalformedRate) {
+				malformed := malformWorker(*event.Worker)
+				event.Worker = &malformed
+			}
+			select {
+			case out <- event:
+				sent++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// malformWorker returns worker without the fields routers rely on, like a
+// record written by an incompatible version or truncated in storage
+func malformWorker(worker ports.WorkerInfo) ports.WorkerInfo {
+	worker.Type = ""
+	worker.Status = ""
+	worker.LastHeartbeat = time.Time{}
+	return worker
+}
@@ -0,0 +1,16 @@
+// Package blob provides an implementation of the Checkpointer interface
+// (pkg/ports in this repository) on any ports.BlobStore, for deployments
+// that keep step outputs next to their other payloads in S3, GCS or a local
+// directory.
+//
+// Each checkpoint is a blob under "checkpoints/<run ID>/<step ID>" (IDs are
+// path-escaped) with the input hash and creation time in its metadata.
+// Blob stores have no conditional create, so Save checks for an existing
+// checkpoint first; executors racing on the same step may both write it.
+// Use the postgres implementation where that matters.
+//
+// Usage:
+//
+//	blobs := s3.NewStore(s3Client, "dago-artifacts", logger)
+//	checkpointer := blob.NewStore(blobs, logger)
+package blob
@@ -0,0 +1,171 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/blob"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default prefix of checkpoint keys
+	defaultPrefix = "checkpoints/"
+
+	// Blob metadata keys, lower case as S3 returns them
+	metaInputHash = "input-hash"
+	metaCreatedAt = "created-at"
+)
+
+// Store implements ports.Checkpointer on a ports.BlobStore. Each checkpoint
+// is a blob under "<prefix><run ID>/<step ID>" holding the output, with the
+// input hash and creation time in its metadata.
+type Store struct {
+	blobs  ports.BlobStore
+	logger *zap.Logger
+	prefix string
+}
+
+// NewStore creates a new checkpoint store keeping checkpoints in blobs
+func NewStore(blobs ports.BlobStore, logger *zap.Logger) *Store {
+	return &Store{
+		blobs:  blobs,
+		logger: logger,
+		prefix: defaultPrefix,
+	}
+}
+
+// SetPrefix sets the prefix of checkpoint keys
+func (s *Store) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+func (s *Store) runPrefix(runID string) string {
+	return s.prefix + url.PathEscape(runID) + "/"
+}
+
+func (s *Store) key(runID, stepID string) string {
+	return s.runPrefix(runID) + url.PathEscape(stepID)
+}
+
+// Save stores the checkpoint unless the step already has one
+// (ports.Checkpointer interface). Blob stores can't create a blob only if
+// it is missing, so two executors saving the same step at once may both
+// write it; the last output is kept.
+func (s *Store) Save(ctx context.Context, checkpoint ports.Checkpoint) (*ports.Checkpoint, error) {
+	existing, err := s.Load(ctx, checkpoint.RunID, checkpoint.StepID)
+	switch {
+	case err == nil:
+		if existing.InputHash != checkpoint.InputHash {
+			return nil, fmt.Errorf("%w: step %s of run %s", ports.ErrCheckpointMismatch, checkpoint.StepID, checkpoint.RunID)
+		}
+		return existing, nil
+	case !errors.Is(err, ports.ErrCheckpointNotFound):
+		return nil, err
+	}
+
+	if checkpoint.CreatedAt.IsZero() {
+		checkpoint.CreatedAt = time.Now()
+	}
+	_, err = blob.PutBytes(ctx, s.blobs, s.key(checkpoint.RunID, checkpoint.StepID), checkpoint.Output, ports.PutOptions{
+		ContentType: "application/octet-stream",
+		Metadata: map[string]string{
+			metaInputHash: checkpoint.InputHash,
+			metaCreatedAt: checkpoint.CreatedAt.UTC().Format(time.RFC3339Nano),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	s.logger.Debug("checkpoint saved",
+		zap.String("run_id", checkpoint.RunID),
+		zap.String("step_id", checkpoint.StepID),
+		zap.Int("size", len(checkpoint.Output)))
+	return &checkpoint, nil
+}
+
+// Load returns a step's checkpoint (ports.Checkpointer interface)
+func (s *Store) Load(ctx context.Context, runID, stepID string) (*ports.Checkpoint, error) {
+	return s.load(ctx, s.key(runID, stepID), runID, stepID)
+}
+
+func (s *Store) load(ctx context.Context, key, runID, stepID string) (*ports.Checkpoint, error) {
+	r, info, err := s.blobs.Get(ctx, key)
+	if errors.Is(err, ports.ErrBlobNotFound) {
+		return nil, fmt.Errorf("%w: step %s of run %s", ports.ErrCheckpointNotFound, stepID, runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	output, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, info.Metadata[metaCreatedAt])
+	if err != nil {
+		createdAt = info.ModifiedAt
+	}
+
+	return &ports.Checkpoint{
+		RunID:     runID,
+		StepID:    stepID,
+		InputHash: info.Metadata[metaInputHash],
+		Output:    output,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// List returns the run's checkpoints, oldest first (ports.Checkpointer
+// interface). Each checkpoint is a separate read.
+func (s *Store) List(ctx context.Context, runID string) ([]ports.Checkpoint, error) {
+	prefix := s.runPrefix(runID)
+	blobs, err := s.blobs.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	checkpoints := make([]ports.Checkpoint, 0, len(blobs))
+	for _, info := range blobs {
+		stepID, err := url.PathUnescape(info.Key[len(prefix):])
+		if err != nil {
+			continue
+		}
+		checkpoint, err := s.load(ctx, info.Key, runID, stepID)
+		if errors.Is(err, ports.ErrCheckpointNotFound) {
+			// Deleted since it was listed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, *checkpoint)
+	}
+
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreatedAt.Before(checkpoints[j].CreatedAt)
+	})
+	return checkpoints, nil
+}
+
+// Delete removes the run's checkpoints (ports.Checkpointer interface)
+func (s *Store) Delete(ctx context.Context, runID string) error {
+	blobs, err := s.blobs.List(ctx, s.runPrefix(runID))
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	for _, info := range blobs {
+		if err := s.blobs.Delete(ctx, info.Key); err != nil {
+			return fmt.Errorf("failed to delete checkpoint: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/blob/local"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.Checkpointer = (*Store)(nil)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	blobs, err := local.NewStore(t.TempDir(), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(blobs, zap.NewNop())
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	created := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	if _, err := store.Load(ctx, "run-1", "fetch"); !errors.Is(err, ports.ErrCheckpointNotFound) {
+		t.Errorf("Load() error = %v, want not found", err)
+	}
+
+	steps := []ports.Checkpoint{
+		{RunID: "run-1", StepID: "fetch/page 1", InputHash: "h1", Output: []byte("page"), CreatedAt: created},
+		{RunID: "run-1", StepID: "summarize", InputHash: "h2", Output: []byte("summary"), CreatedAt: created.Add(time.Second)},
+		{RunID: "run-2", StepID: "fetch/page 1", InputHash: "h1", Output: []byte("other"), CreatedAt: created},
+	}
+	for _, cp := range steps {
+		if _, err := store.Save(ctx, cp); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	got, err := store.Load(ctx, "run-1", "fetch/page 1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if string(got.Output) != "page" || got.InputHash != "h1" || !got.CreatedAt.Equal(created) {
+		t.Errorf("Load() = %+v", got)
+	}
+
+	// Saving again keeps the first output; another input is a mismatch
+	again, err := store.Save(ctx, ports.Checkpoint{RunID: "run-1", StepID: "summarize", InputHash: "h2", Output: []byte("second")})
+	if err != nil || string(again.Output) != "summary" {
+		t.Errorf("Save() again = %+v, %v; want the first checkpoint", again, err)
+	}
+	if _, err := store.Save(ctx, ports.Checkpoint{RunID: "run-1", StepID: "summarize", InputHash: "h3"}); !errors.Is(err, ports.ErrCheckpointMismatch) {
+		t.Errorf("Save() with another input error = %v, want mismatch", err)
+	}
+
+	list, err := store.List(ctx, "run-1")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 || list[0].StepID != "fetch/page 1" || list[1].StepID != "summarize" {
+		t.Errorf("List() = %+v, want run-1's steps oldest first", list)
+	}
+
+	if err := store.Delete(ctx, "run-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if list, _ := store.List(ctx, "run-1"); len(list) != 0 {
+		t.Errorf("List() after Delete() = %+v", list)
+	}
+	if _, err := store.Load(ctx, "run-2", "fetch/page 1"); err != nil {
+		t.Errorf("Delete() removed another run's checkpoint: %v", err)
+	}
+}
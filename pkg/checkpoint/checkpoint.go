@@ -0,0 +1,94 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// Hash returns the input hash of a step input: the hex SHA-256 of its JSON
+func Hash(input any) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal step input: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Check returns the step's checkpoint if it can be replayed for an input
+// hash, nil if the step has none, or ErrCheckpointMismatch if it was
+// checkpointed with another input.
+func Check(ctx context.Context, checkpointer ports.Checkpointer, runID, stepID, inputHash string) (*ports.Checkpoint, error) {
+	cp, err := checkpointer.Load(ctx, runID, stepID)
+	if errors.Is(err, ports.ErrCheckpointNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cp.InputHash != inputHash {
+		return nil, fmt.Errorf("%w: step %s of run %s", ports.ErrCheckpointMismatch, stepID, runID)
+	}
+	return cp, nil
+}
+
+// Step runs a step once per run: if it was checkpointed with the same
+// input, its stored output is returned and replayed is true; otherwise run
+// is called and its output checkpointed. T is stored as JSON.
+//
+// If another executor checkpoints the step while run is executing, its
+// output wins and is returned, so every executor continues with the same
+// result.
+func Step[T any](ctx context.Context, checkpointer ports.Checkpointer, runID, stepID string, input any, run func(ctx context.Context) (T, error)) (output T, replayed bool, err error) {
+	inputHash, err := Hash(input)
+	if err != nil {
+		return output, false, err
+	}
+
+	cp, err := Check(ctx, checkpointer, runID, stepID, inputHash)
+	if err != nil {
+		return output, false, err
+	}
+	if cp != nil {
+		if err := json.Unmarshal(cp.Output, &output); err != nil {
+			return output, false, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+		}
+		return output, true, nil
+	}
+
+	output, err = run(ctx)
+	if err != nil {
+		return output, false, err
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return output, false, fmt.Errorf("failed to marshal step output: %w", err)
+	}
+
+	stored, err := checkpointer.Save(ctx, ports.Checkpoint{
+		RunID:     runID,
+		StepID:    stepID,
+		InputHash: inputHash,
+		Output:    data,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return output, false, err
+	}
+	if !bytes.Equal(stored.Output, data) {
+		var winner T
+		if err := json.Unmarshal(stored.Output, &winner); err != nil {
+			return output, false, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+		}
+		return winner, false, nil
+	}
+	return output, false, nil
+}
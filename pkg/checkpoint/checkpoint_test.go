@@ -0,0 +1,130 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// memoryCheckpointer keeps checkpoints in a map, first write wins
+type memoryCheckpointer struct {
+	mu          sync.Mutex
+	checkpoints map[string]ports.Checkpoint
+}
+
+func newMemoryCheckpointer() *memoryCheckpointer {
+	return &memoryCheckpointer{checkpoints: make(map[string]ports.Checkpoint)}
+}
+
+func (m *memoryCheckpointer) Save(ctx context.Context, cp ports.Checkpoint) (*ports.Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := cp.RunID + "/" + cp.StepID
+	if existing, ok := m.checkpoints[key]; ok {
+		if existing.InputHash != cp.InputHash {
+			return nil, ports.ErrCheckpointMismatch
+		}
+		return &existing, nil
+	}
+	m.checkpoints[key] = cp
+	return &cp, nil
+}
+
+func (m *memoryCheckpointer) Load(ctx context.Context, runID, stepID string) (*ports.Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp, ok := m.checkpoints[runID+"/"+stepID]
+	if !ok {
+		return nil, ports.ErrCheckpointNotFound
+	}
+	return &cp, nil
+}
+
+func (m *memoryCheckpointer) List(ctx context.Context, runID string) ([]ports.Checkpoint, error) {
+	return nil, nil
+}
+
+func (m *memoryCheckpointer) Delete(ctx context.Context, runID string) error {
+	return nil
+}
+
+type summary struct {
+	Text   string `json:"text"`
+	Tokens int    `json:"tokens"`
+}
+
+func TestStep_Replays(t *testing.T) {
+	ctx := context.Background()
+	cp := newMemoryCheckpointer()
+	calls := 0
+	run := func(ctx context.Context) (summary, error) {
+		calls++
+		return summary{Text: "short", Tokens: 42}, nil
+	}
+
+	out, replayed, err := Step(ctx, cp, "run-1", "summarize", "long document", run)
+	if err != nil || replayed || out.Tokens != 42 {
+		t.Fatalf("Step() = %+v, %v, %v", out, replayed, err)
+	}
+
+	// A resumed executor gets the stored output without running the step
+	out, replayed, err = Step(ctx, cp, "run-1", "summarize", "long document", run)
+	if err != nil || !replayed || out.Text != "short" || out.Tokens != 42 {
+		t.Fatalf("Step() on resume = %+v, %v, %v", out, replayed, err)
+	}
+	if calls != 1 {
+		t.Errorf("step ran %d times, want 1", calls)
+	}
+
+	// Another run doesn't share checkpoints
+	if _, replayed, _ := Step(ctx, cp, "run-2", "summarize", "long document", run); replayed {
+		t.Error("Step() replayed another run's checkpoint")
+	}
+}
+
+func TestStep_InputMismatch(t *testing.T) {
+	ctx := context.Background()
+	cp := newMemoryCheckpointer()
+	run := func(ctx context.Context) (string, error) { return "out", nil }
+
+	if _, _, err := Step(ctx, cp, "run-1", "s", map[string]int{"n": 1}, run); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := Step(ctx, cp, "run-1", "s", map[string]int{"n": 2}, run)
+	if !errors.Is(err, ports.ErrCheckpointMismatch) {
+		t.Errorf("Step() with another input error = %v, want mismatch", err)
+	}
+}
+
+func TestStep_ConcurrentExecutorWins(t *testing.T) {
+	ctx := context.Background()
+	cp := newMemoryCheckpointer()
+	hash, _ := Hash("in")
+
+	// Another executor finishes the step while this one is running it
+	run := func(ctx context.Context) (string, error) {
+		_, _ = cp.Save(ctx, ports.Checkpoint{RunID: "run-1", StepID: "s", InputHash: hash, Output: []byte(`"theirs"`)})
+		return "ours", nil
+	}
+	out, _, err := Step(ctx, cp, "run-1", "s", "in", run)
+	if err != nil || out != "theirs" {
+		t.Errorf("Step() = %q, %v; want the stored output", out, err)
+	}
+}
+
+func TestStep_ErrorNotCheckpointed(t *testing.T) {
+	ctx := context.Background()
+	cp := newMemoryCheckpointer()
+	_, _, err := Step(ctx, cp, "run-1", "s", "in", func(ctx context.Context) (string, error) {
+		return "", errors.New("rate limited")
+	})
+	if err == nil {
+		t.Fatal("Step() error = nil")
+	}
+	if _, err := cp.Load(ctx, "run-1", "s"); !errors.Is(err, ports.ErrCheckpointNotFound) {
+		t.Error("failed step was checkpointed")
+	}
+}
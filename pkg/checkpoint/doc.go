@@ -0,0 +1,28 @@
+// Package checkpoint provides adapters for the ports.Checkpointer interface
+// (pkg/ports in this repository), which persists the outputs of workflow
+// steps keyed by run and step, and Step, which runs a step at most once per
+// run.
+//
+// An executor resuming a crashed run calls Step for every step again. Steps
+// checkpointed with the same input (compared by Hash of the input) return
+// their stored output without running, so expensive model calls are not
+// repeated; a step whose input changed fails with ErrCheckpointMismatch
+// rather than replaying an output for another input.
+//
+// Available implementations:
+//   - postgres: One row per step, written once with a conditional insert
+//   - blob: One blob per step in any ports.BlobStore (S3, GCS, local)
+//
+// Usage:
+//
+//	checkpointer := postgres.NewStore(pool, logger)
+//
+//	answer, replayed, err := checkpoint.Step(ctx, checkpointer, runID, "draft", prompt,
+//		func(ctx context.Context) (string, error) {
+//			resp, err := llm.Complete(ctx, req)
+//			if err != nil {
+//				return "", err
+//			}
+//			return resp.Content, nil
+//		})
+package checkpoint
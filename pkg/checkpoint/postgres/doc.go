@@ -0,0 +1,20 @@
+// Package postgres provides a PostgreSQL implementation of the Checkpointer
+// interface (pkg/ports in this repository).
+//
+// Key Design:
+//   - Checkpoints are stored in the dago_checkpoints table, keyed by
+//     (run_id, step_id)
+//   - Save is an INSERT ... ON CONFLICT DO NOTHING, so concurrent executors
+//     agree on the first output saved for a step
+//   - The schema is managed by embedded migrations applied with Migrate
+//
+// Usage:
+//
+//	store := postgres.NewStore(pool, logger)
+//	if err := store.Migrate(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Drop a run's checkpoints once it completed
+//	err := store.Delete(ctx, runID)
+package postgres
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS dago_checkpoints (
+    run_id     TEXT NOT NULL,
+    step_id    TEXT NOT NULL,
+    input_hash TEXT NOT NULL,
+    output     BYTEA,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (run_id, step_id)
+);
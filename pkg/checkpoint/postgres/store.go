@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/internal/pgmigrate"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// Columns selected for every checkpoint read, in scanCheckpoint order
+	checkpointColumns = `run_id, step_id, input_hash, output, created_at`
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Store implements ports.Checkpointer using PostgreSQL
+type Store struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+}
+
+// NewStore creates a new PostgreSQL checkpoint store
+func NewStore(pool *pgxpool.Pool, logger *zap.Logger) *Store {
+	return &Store{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+// Migrate applies any pending schema migrations.
+// It is safe to call concurrently from several processes.
+func (s *Store) Migrate(ctx context.Context) error {
+	return pgmigrate.Migrate(ctx, s.pool, migrationFS, "migrations/", s.logger)
+}
+
+// Save stores the checkpoint unless the step already has one
+// (ports.Checkpointer interface). The insert is conditional on the primary
+// key, so concurrent executors agree on the first checkpoint.
+func (s *Store) Save(ctx context.Context, checkpoint ports.Checkpoint) (*ports.Checkpoint, error) {
+	if checkpoint.CreatedAt.IsZero() {
+		checkpoint.CreatedAt = time.Now()
+	}
+
+	tag, err := s.pool.Exec(ctx, `
+		INSERT INTO dago_checkpoints (run_id, step_id, input_hash, output, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (run_id, step_id) DO NOTHING`,
+		checkpoint.RunID, checkpoint.StepID, checkpoint.InputHash, checkpoint.Output, checkpoint.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		s.logger.Debug("checkpoint saved",
+			zap.String("run_id", checkpoint.RunID),
+			zap.String("step_id", checkpoint.StepID),
+			zap.Int("size", len(checkpoint.Output)))
+		return &checkpoint, nil
+	}
+
+	existing, err := s.Load(ctx, checkpoint.RunID, checkpoint.StepID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.InputHash != checkpoint.InputHash {
+		return nil, fmt.Errorf("%w: step %s of run %s", ports.ErrCheckpointMismatch, checkpoint.StepID, checkpoint.RunID)
+	}
+	if !bytes.Equal(existing.Output, checkpoint.Output) {
+		s.logger.Debug("step already checkpointed, keeping the first output",
+			zap.String("run_id", checkpoint.RunID),
+			zap.String("step_id", checkpoint.StepID))
+	}
+	return existing, nil
+}
+
+// Load returns a step's checkpoint (ports.Checkpointer interface)
+func (s *Store) Load(ctx context.Context, runID, stepID string) (*ports.Checkpoint, error) {
+	row := s.pool.QueryRow(ctx,
+		`SELECT `+checkpointColumns+` FROM dago_checkpoints WHERE run_id = $1 AND step_id = $2`,
+		runID, stepID)
+
+	checkpoint, err := scanCheckpoint(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: step %s of run %s", ports.ErrCheckpointNotFound, stepID, runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// List returns the run's checkpoints, oldest first (ports.Checkpointer
+// interface)
+func (s *Store) List(ctx context.Context, runID string) ([]ports.Checkpoint, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+checkpointColumns+` FROM dago_checkpoints
+		WHERE run_id = $1
+		ORDER BY created_at, step_id`,
+		runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []ports.Checkpoint
+	for rows.Next() {
+		checkpoint, err := scanCheckpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, *checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// Delete removes the run's checkpoints (ports.Checkpointer interface)
+func (s *Store) Delete(ctx context.Context, runID string) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM dago_checkpoints WHERE run_id = $1`, runID); err != nil {
+		return fmt.Errorf("failed to delete checkpoints: %w", err)
+	}
+	return nil
+}
+
+// scanCheckpoint scans a row selected with checkpointColumns
+func scanCheckpoint(row pgx.Row) (*ports.Checkpoint, error) {
+	var checkpoint ports.Checkpoint
+	if err := row.Scan(&checkpoint.RunID, &checkpoint.StepID, &checkpoint.InputHash,
+		&checkpoint.Output, &checkpoint.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
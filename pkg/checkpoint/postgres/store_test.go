@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+var _ ports.Checkpointer = (*Store)(nil)
+
+// Integration test - only runs with POSTGRES_DSN environment variable
+func TestStore_Integration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN not set, skipping integration test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer pool.Close()
+
+	store := NewStore(pool, zap.NewNop())
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	runID := "run-pg-checkpoint-integration"
+	_ = store.Delete(ctx, runID)
+	defer func() { _ = store.Delete(ctx, runID) }()
+
+	if _, err := store.Save(ctx, ports.Checkpoint{RunID: runID, StepID: "fetch", InputHash: "h1", Output: []byte("page")}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := store.Save(ctx, ports.Checkpoint{RunID: runID, StepID: "summarize", InputHash: "h2", Output: []byte("summary")}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	again, err := store.Save(ctx, ports.Checkpoint{RunID: runID, StepID: "fetch", InputHash: "h1", Output: []byte("second")})
+	if err != nil || string(again.Output) != "page" {
+		t.Errorf("Save() again = %+v, %v; want the first checkpoint", again, err)
+	}
+	if _, err := store.Save(ctx, ports.Checkpoint{RunID: runID, StepID: "fetch", InputHash: "h3"}); !errors.Is(err, ports.ErrCheckpointMismatch) {
+		t.Errorf("Save() with another input error = %v, want mismatch", err)
+	}
+
+	got, err := store.Load(ctx, runID, "summarize")
+	if err != nil || string(got.Output) != "summary" || got.InputHash != "h2" {
+		t.Errorf("Load() = %+v, %v", got, err)
+	}
+
+	list, err := store.List(ctx, runID)
+	if err != nil || len(list) != 2 || list[0].StepID != "fetch" {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+
+	if err := store.Delete(ctx, runID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Load(ctx, runID, "fetch"); !errors.Is(err, ports.ErrCheckpointNotFound) {
+		t.Errorf("Load() after Delete() error = %v, want not found", err)
+	}
+}
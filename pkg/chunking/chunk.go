@@ -0,0 +1,65 @@
+package chunking
+
+import (
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// Chunk is a piece of a text sized for embedding or a model's context
+type Chunk struct {
+	// Index is the position of the chunk in the text, from 0.
+	Index int `json:"index"`
+
+	// Text is the chunk text, a substring of the source without leading or
+	// trailing whitespace.
+	Text string `json:"text"`
+
+	// Start and End are the byte offsets of Text in the source.
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// Heading is the heading of the document section the chunk starts in.
+	Heading string `json:"heading,omitempty"`
+
+	// Page is the page the chunk starts on, for paginated documents.
+	Page int `json:"page,omitempty"`
+}
+
+// Splitter splits texts into chunks
+type Splitter interface {
+	// Split returns the chunks of text in order.
+	Split(text string) []Chunk
+}
+
+// SplitDocument splits a loaded document's content, setting the heading and
+// page of each chunk from the document sections
+func SplitDocument(splitter Splitter, doc *ports.Document) []Chunk {
+	chunks := splitter.Split(doc.Content)
+
+	var heading string
+	var page int
+	next := 0
+	for i := range chunks {
+		for next < len(doc.Sections) && doc.Sections[next].Offset <= chunks[i].Start {
+			section := doc.Sections[next]
+			if section.Heading != "" {
+				heading = section.Heading
+			}
+			if section.Page != 0 {
+				page = section.Page
+			}
+			next++
+		}
+		chunks[i].Heading = heading
+		chunks[i].Page = page
+	}
+	return chunks
+}
+
+// Texts returns the text of each chunk, e.g. for a ports.EmbeddingRequest
+func Texts(chunks []Chunk) []string {
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+	return texts
+}
@@ -0,0 +1,42 @@
+package chunking
+
+import (
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+func TestSplitDocument(t *testing.T) {
+	doc := &ports.Document{
+		Content: "Page one text.\n\nIntro\n\nPage two text.",
+		Sections: []ports.DocumentSection{
+			{Page: 1, Offset: 0},
+			{Heading: "Intro", Level: 1, Page: 1, Offset: 16},
+			{Page: 2, Offset: 23},
+		},
+	}
+
+	s, err := NewRecursiveSplitter(15, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := SplitDocument(s, doc)
+
+	want := []Chunk{
+		{Index: 0, Text: "Page one text.", Start: 0, End: 14, Page: 1},
+		{Index: 1, Text: "Intro", Start: 16, End: 21, Heading: "Intro", Page: 1},
+		{Index: 2, Text: "Page two text.", Start: 23, End: 37, Heading: "Intro", Page: 2},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("SplitDocument() = %+v", chunks)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunks[%d] = %+v, want %+v", i, chunks[i], want[i])
+		}
+	}
+
+	if texts := Texts(chunks); len(texts) != 3 || texts[1] != "Intro" {
+		t.Errorf("Texts() = %v", texts)
+	}
+}
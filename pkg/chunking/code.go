@@ -0,0 +1,62 @@
+package chunking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// CodeSeparators are the separators of each language supported by
+// NewCodeSplitter: top-level declarations first, then blank lines, lines,
+// words and characters
+var CodeSeparators = map[string][]string{
+	"go": {
+		"\nfunc ", "\ntype ", "\nvar ", "\nconst ",
+		"\n\n", "\n", " ", "",
+	},
+	"python": {
+		"\nclass ", "\ndef ", "\nasync def ", "\n    def ", "\n    async def ",
+		"\n\n", "\n", " ", "",
+	},
+	"javascript": {
+		"\nexport ", "\nfunction ", "\nasync function ", "\nclass ", "\nconst ", "\nlet ",
+		"\n\n", "\n", " ", "",
+	},
+	"typescript": {
+		"\nexport ", "\ninterface ", "\ntype ", "\nenum ", "\nfunction ", "\nasync function ", "\nclass ", "\nconst ", "\nlet ",
+		"\n\n", "\n", " ", "",
+	},
+	"java": {
+		"\npublic ", "\nprotected ", "\nprivate ", "\nclass ", "\ninterface ", "\nenum ",
+		"\n    public ", "\n    protected ", "\n    private ",
+		"\n\n", "\n", " ", "",
+	},
+	"rust": {
+		"\npub fn ", "\nfn ", "\npub struct ", "\nstruct ", "\npub enum ", "\nenum ", "\nimpl ", "\ntrait ", "\nmod ",
+		"\n\n", "\n", " ", "",
+	},
+}
+
+// NewCodeSplitter creates a splitter of source code in language (a key of
+// CodeSeparators) into chunks of up to chunkSize characters, overlapping by
+// up to overlap characters, that start at declarations where possible
+func NewCodeSplitter(language string, chunkSize, overlap int) (*RecursiveSplitter, error) {
+	separators, ok := CodeSeparators[strings.ToLower(language)]
+	if !ok {
+		languages := make([]string, 0, len(CodeSeparators))
+		for name := range CodeSeparators {
+			languages = append(languages, name)
+		}
+		sort.Strings(languages)
+		return nil, fmt.Errorf("%w: %s (supported: %s)", ports.ErrUnsupportedLanguage, language, strings.Join(languages, ", "))
+	}
+
+	s, err := NewRecursiveSplitter(chunkSize, overlap)
+	if err != nil {
+		return nil, err
+	}
+	s.separators = separators
+	return s, nil
+}
@@ -0,0 +1,34 @@
+package chunking
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+func TestCodeSplitter(t *testing.T) {
+	text := "package main\n\nimport \"fmt\"\n\nfunc a() {\n\tfmt.Println(1)\n}\n\nfunc b() {\n\tfmt.Println(2)\n}\n"
+
+	s, err := NewCodeSplitter("Go", 40, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := s.Split(text)
+	checkChunks(t, text, chunks, 40, CharacterLength)
+
+	var funcs int
+	for _, chunk := range chunks {
+		if strings.HasPrefix(chunk.Text, "func ") {
+			funcs++
+		}
+	}
+	if funcs != 2 {
+		t.Errorf("chunks = %+v, want one per function", chunks)
+	}
+
+	if _, err := NewCodeSplitter("cobol", 40, 0); !errors.Is(err, ports.ErrUnsupportedLanguage) {
+		t.Errorf("NewCodeSplitter(cobol) error = %v, want ErrUnsupportedLanguage", err)
+	}
+}
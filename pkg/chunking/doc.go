@@ -0,0 +1,27 @@
+// Package chunking splits texts into chunks sized for embedding and model
+// context windows.
+//
+// RecursiveSplitter splits on the most structural separator that makes
+// pieces fit (paragraphs, then lines, words and characters) and merges the
+// pieces back into chunks of up to a size, with overlap between consecutive
+// chunks so that no passage loses its context. Variants measure size in
+// tokens (NewTokenSplitter) or split on Markdown headings without breaking
+// code blocks (NewMarkdownSplitter) and on source code declarations
+// (NewCodeSplitter).
+//
+// Chunks record their byte offsets in the source. SplitDocument splits a
+// document from a ports.DocumentLoader (see pkg/documents) and sets the
+// heading and page each chunk starts in.
+//
+// Usage:
+//
+//	splitter, err := chunking.NewTokenSplitter(chunking.ApproximateTokenizer, 512, 64)
+//
+//	doc, err := loader.LoadFile(ctx, "handbook.pdf")
+//	chunks := chunking.SplitDocument(splitter, doc)
+//
+//	resp, err := embedder.Embed(ctx, ports.EmbeddingRequest{
+//		Texts:     chunking.Texts(chunks),
+//		InputType: ports.EmbeddingInputDocument,
+//	})
+package chunking
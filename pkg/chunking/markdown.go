@@ -0,0 +1,59 @@
+package chunking
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MarkdownSeparators split on headings from the top level down, then on
+// code blocks, paragraphs, lines, words and characters
+var MarkdownSeparators = []string{
+	"\n# ", "\n## ", "\n### ", "\n#### ", "\n##### ", "\n###### ",
+	"\n```", "\n~~~", "\n\n", "\n", " ", "",
+}
+
+// fence matches the opening or closing line of a fenced code block
+var fence = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+
+// NewMarkdownSplitter creates a splitter of Markdown into chunks of up to
+// chunkSize characters, overlapping by up to overlap characters. Chunks
+// start at headings where possible, and fenced code blocks are only split
+// when a block is larger than a chunk, at line breaks.
+func NewMarkdownSplitter(chunkSize, overlap int) (*RecursiveSplitter, error) {
+	s, err := NewRecursiveSplitter(chunkSize, overlap)
+	if err != nil {
+		return nil, err
+	}
+	s.separators = MarkdownSeparators
+	s.protected = fencedBlocks
+	return s, nil
+}
+
+// fencedBlocks returns the ranges of fenced code blocks, from the opening
+// fence to the end of the closing one
+func fencedBlocks(text string) []span {
+	var blocks []span
+	var open string
+	start := 0
+
+	offset := 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineStart := offset
+		offset += len(line)
+
+		m := fence.FindStringSubmatch(line)
+		switch {
+		case m == nil:
+		case open == "":
+			open, start = m[1], lineStart
+		case strings.HasPrefix(m[1], open):
+			blocks = append(blocks, span{start, offset})
+			open = ""
+		}
+	}
+	if open != "" {
+		// An unclosed block runs to the end
+		blocks = append(blocks, span{start, len(text)})
+	}
+	return blocks
+}
@@ -0,0 +1,52 @@
+package chunking
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownSplitter(t *testing.T) {
+	code := "```go\nfunc main() {\n\n\tprintln(\"hi\")\n}\n```"
+	text := "# Guide\n\nIntro text.\n\n## Install\n\nRun this:\n\n" + code + "\n\n## Usage\n\nCall it."
+
+	s, err := NewMarkdownSplitter(60, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := s.Split(text)
+	checkChunks(t, text, chunks, 60, CharacterLength)
+
+	for _, chunk := range chunks {
+		if strings.Contains(chunk.Text, "```") && !strings.Contains(chunk.Text, code) {
+			t.Errorf("code block split: %q", chunk.Text)
+		}
+	}
+	if !strings.HasPrefix(chunks[len(chunks)-1].Text, "## Usage") {
+		t.Errorf("last chunk = %q, want to start at its heading", chunks[len(chunks)-1].Text)
+	}
+}
+
+func TestMarkdownSplitterLargeCodeBlock(t *testing.T) {
+	text := "Intro\n\n```\n" + strings.Repeat("line of code\n", 20) + "```\n"
+
+	s, err := NewMarkdownSplitter(50, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := s.Split(text)
+	checkChunks(t, text, chunks, 50, CharacterLength)
+}
+
+func TestFencedBlocks(t *testing.T) {
+	text := "a\n```\nb\n```\nc\n~~~\nunclosed"
+	blocks := fencedBlocks(text)
+	if len(blocks) != 2 {
+		t.Fatalf("fencedBlocks() = %v", blocks)
+	}
+	if got := text[blocks[0].start:blocks[0].end]; got != "```\nb\n```\n" {
+		t.Errorf("first block = %q", got)
+	}
+	if blocks[1].end != len(text) {
+		t.Errorf("unclosed block = %v, want to run to the end", blocks[1])
+	}
+}
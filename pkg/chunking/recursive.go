@@ -0,0 +1,253 @@
+package chunking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSeparators split on paragraphs, then lines, words and characters
+var DefaultSeparators = []string{"\n\n", "\n", " ", ""}
+
+// span is a byte range of the text being split
+type span struct {
+	start, end int
+}
+
+// RecursiveSplitter splits texts on the first separator found, merges
+// adjacent pieces into chunks of up to the chunk size that overlap by up to
+// the overlap, and splits oversized pieces further with the next
+// separators. Sizes are measured by the length
+// function, in characters by default.
+//
+// Separators start the piece that follows them, so a chunk never begins
+// with the end of a sentence or a heading's trailing newline. The empty
+// separator splits anywhere between characters.
+type RecursiveSplitter struct {
+	chunkSize  int
+	overlap    int
+	separators []string
+	length     func(string) int
+
+	// protected returns byte ranges, such as code blocks, that are not
+	// split on separators other than newlines, spaces and characters
+	protected func(text string) []span
+}
+
+// NewRecursiveSplitter creates a splitter of chunks of up to chunkSize
+// characters, overlapping by up to overlap characters, with
+// DefaultSeparators
+func NewRecursiveSplitter(chunkSize, overlap int) (*RecursiveSplitter, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+	if overlap < 0 || overlap >= chunkSize {
+		return nil, fmt.Errorf("overlap must be between 0 and the chunk size %d, got %d", chunkSize, overlap)
+	}
+	return &RecursiveSplitter{
+		chunkSize:  chunkSize,
+		overlap:    overlap,
+		separators: DefaultSeparators,
+		length:     CharacterLength,
+	}, nil
+}
+
+// NewTokenSplitter creates a splitter of chunks of up to chunkSize tokens,
+// overlapping by up to overlap tokens, as counted by tokenizer
+func NewTokenSplitter(tokenizer Tokenizer, chunkSize, overlap int) (*RecursiveSplitter, error) {
+	s, err := NewRecursiveSplitter(chunkSize, overlap)
+	if err != nil {
+		return nil, err
+	}
+	s.length = tokenizer.CountTokens
+	return s, nil
+}
+
+// SetSeparators sets the separators, tried in order. End them with "" to
+// guarantee chunks fit the chunk size.
+func (s *RecursiveSplitter) SetSeparators(separators []string) {
+	s.separators = separators
+}
+
+// SetLengthFunc sets how chunk and overlap sizes are measured, e.g. in
+// bytes with func(s string) int { return len(s) }
+func (s *RecursiveSplitter) SetLengthFunc(length func(string) int) {
+	s.length = length
+}
+
+// Split returns the chunks of text (Splitter interface). Chunks are
+// substrings of text without surrounding whitespace; whitespace-only
+// chunks are dropped.
+func (s *RecursiveSplitter) Split(text string) []Chunk {
+	var protected []span
+	if s.protected != nil {
+		protected = s.protected(text)
+	}
+
+	var chunks []Chunk
+	for _, window := range s.split(text, span{0, len(text)}, s.separators, protected) {
+		start, end := trimSpan(text, window)
+		if start == end {
+			continue
+		}
+		chunks = append(chunks, Chunk{
+			Index: len(chunks),
+			Text:  text[start:end],
+			Start: start,
+			End:   end,
+		})
+	}
+	return chunks
+}
+
+// split splits a range on the first separator found in it, merging pieces
+// that fit the chunk size and splitting the others with the next separators
+func (s *RecursiveSplitter) split(text string, r span, separators []string, protected []span) []span {
+	if s.length(text[r.start:r.end]) <= s.chunkSize {
+		return []span{r}
+	}
+
+	for i, separator := range separators {
+		if separator == "" {
+			return s.characters(text, r)
+		}
+
+		var honored []span
+		if separator != "\n" && separator != " " {
+			honored = protected
+		}
+		parts := splitBefore(text, r, separator, honored)
+		if len(parts) == 1 {
+			continue
+		}
+
+		// An oversized piece ends the run of pieces merged before it
+		var chunks, fitting []span
+		for _, part := range parts {
+			if s.length(text[part.start:part.end]) <= s.chunkSize {
+				fitting = append(fitting, part)
+				continue
+			}
+			chunks = append(chunks, s.merge(text, fitting)...)
+			fitting = nil
+			chunks = append(chunks, s.split(text, part, separators[i+1:], protected)...)
+		}
+		return append(chunks, s.merge(text, fitting)...)
+	}
+
+	// Nothing left to split on
+	return []span{r}
+}
+
+// characters splits a range into the longest runs of characters that fit
+// the chunk size
+func (s *RecursiveSplitter) characters(text string, r span) []span {
+	var out []span
+	for start := r.start; start < r.end; {
+		// Rune boundaries after start, collected until a run is too long
+		var bounds []int
+		next := start
+		for want := s.chunkSize; ; want *= 2 {
+			for len(bounds) < want && next < r.end {
+				_, size := utf8.DecodeRuneInString(text[next:r.end])
+				next += size
+				bounds = append(bounds, next)
+			}
+			if next == r.end || s.length(text[start:next]) > s.chunkSize {
+				break
+			}
+		}
+
+		// Longest run that fits, at least one character
+		n := sort.Search(len(bounds), func(i int) bool {
+			return s.length(text[start:bounds[i]]) > s.chunkSize
+		})
+		if n == 0 {
+			n = 1
+		}
+		end := bounds[n-1]
+		out = append(out, span{start, end})
+		start = end
+	}
+	return out
+}
+
+// merge combines consecutive pieces into chunk ranges, carrying up to the
+// overlap of each chunk's last pieces into the next
+func (s *RecursiveSplitter) merge(text string, pieces []span) []span {
+	var chunks []span
+	var window []span
+	var lengths []int
+	total := 0
+
+	for _, piece := range pieces {
+		length := s.length(text[piece.start:piece.end])
+		if len(window) > 0 && total+length > s.chunkSize {
+			chunks = append(chunks, span{window[0].start, window[len(window)-1].end})
+			for len(window) > 0 && (total > s.overlap || total+length > s.chunkSize) {
+				total -= lengths[0]
+				window, lengths = window[1:], lengths[1:]
+			}
+		}
+		window = append(window, piece)
+		lengths = append(lengths, length)
+		total += length
+	}
+	if len(window) > 0 {
+		chunks = append(chunks, span{window[0].start, window[len(window)-1].end})
+	}
+	return chunks
+}
+
+// splitBefore splits a range before each occurrence of separator, except
+// at the start of the range and inside protected ranges
+func splitBefore(text string, r span, separator string, protected []span) []span {
+	var parts []span
+	start := r.start
+	for i := r.start + 1; i < r.end; {
+		idx := strings.Index(text[i:r.end], separator)
+		if idx < 0 {
+			break
+		}
+		pos := i + idx
+		i = pos + len(separator)
+		if inside(pos, protected) {
+			continue
+		}
+		parts = append(parts, span{start, pos})
+		start = pos
+	}
+	return append(parts, span{start, r.end})
+}
+
+// inside reports whether pos is strictly inside one of the ranges
+func inside(pos int, ranges []span) bool {
+	for _, r := range ranges {
+		if pos > r.start && pos < r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// trimSpan narrows a range to exclude surrounding whitespace
+func trimSpan(text string, r span) (int, int) {
+	start, end := r.start, r.end
+	for start < end {
+		c, size := utf8.DecodeRuneInString(text[start:end])
+		if !unicode.IsSpace(c) {
+			break
+		}
+		start += size
+	}
+	for end > start {
+		c, size := utf8.DecodeLastRuneInString(text[start:end])
+		if !unicode.IsSpace(c) {
+			break
+		}
+		end -= size
+	}
+	return start, end
+}
@@ -0,0 +1,123 @@
+package chunking
+
+import (
+	"strings"
+	"testing"
+)
+
+// checkChunks verifies invariants every splitter must keep
+func checkChunks(t *testing.T, text string, chunks []Chunk, size int, length func(string) int) {
+	t.Helper()
+	for i, chunk := range chunks {
+		if chunk.Index != i {
+			t.Errorf("chunks[%d].Index = %d", i, chunk.Index)
+		}
+		if text[chunk.Start:chunk.End] != chunk.Text {
+			t.Errorf("chunks[%d] offsets %d:%d don't match its text", i, chunk.Start, chunk.End)
+		}
+		if chunk.Text != strings.TrimSpace(chunk.Text) || chunk.Text == "" {
+			t.Errorf("chunks[%d].Text = %q, want trimmed and non-empty", i, chunk.Text)
+		}
+		if length(chunk.Text) > size {
+			t.Errorf("chunks[%d] has length %d, want <= %d: %q", i, length(chunk.Text), size, chunk.Text)
+		}
+		if i > 0 && chunk.Start < chunks[i-1].Start {
+			t.Errorf("chunks[%d] starts before the previous chunk", i)
+		}
+	}
+}
+
+func TestRecursiveSplitter(t *testing.T) {
+	text := "First paragraph is short.\n\n" +
+		"Second paragraph is quite a bit longer and has to be split on words.\n\n" +
+		"Third."
+
+	s, err := NewRecursiveSplitter(40, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := s.Split(text)
+	checkChunks(t, text, chunks, 40, CharacterLength)
+
+	want := []string{
+		"First paragraph is short.",
+		"Second paragraph is quite a bit longer",
+		"and has to be split on words.",
+		"Third.",
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks: %+v", len(chunks), chunks)
+	}
+	for i := range want {
+		if chunks[i].Text != want[i] {
+			t.Errorf("chunks[%d] = %q, want %q", i, chunks[i].Text, want[i])
+		}
+	}
+}
+
+func TestRecursiveSplitterOverlap(t *testing.T) {
+	text := "one two three four five six seven eight nine ten"
+
+	s, err := NewRecursiveSplitter(20, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := s.Split(text)
+	checkChunks(t, text, chunks, 20, CharacterLength)
+
+	if len(chunks) < 3 {
+		t.Fatalf("got %d chunks: %+v", len(chunks), chunks)
+	}
+	for i := 1; i < len(chunks); i++ {
+		if chunks[i].Start >= chunks[i-1].End {
+			t.Errorf("chunks[%d] %q doesn't overlap %q", i, chunks[i].Text, chunks[i-1].Text)
+		}
+	}
+	if last := chunks[len(chunks)-1]; last.End != len(text) {
+		t.Errorf("last chunk ends at %d, want %d", last.End, len(text))
+	}
+}
+
+func TestRecursiveSplitterCharacters(t *testing.T) {
+	text := strings.Repeat("日本語", 10)
+
+	s, err := NewRecursiveSplitter(7, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := s.Split(text)
+	checkChunks(t, text, chunks, 7, CharacterLength)
+
+	if len(chunks) != 5 {
+		t.Errorf("got %d chunks, want 5", len(chunks))
+	}
+}
+
+func TestTokenSplitter(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+
+	s, err := NewTokenSplitter(ApproximateTokenizer, 16, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := s.Split(text)
+	checkChunks(t, text, chunks, 16, ApproximateTokenizer.CountTokens)
+
+	if len(chunks) < 10 {
+		t.Errorf("got %d chunks, want at least 10", len(chunks))
+	}
+}
+
+func TestNewRecursiveSplitterInvalid(t *testing.T) {
+	for _, tt := range []struct{ size, overlap int }{{0, 0}, {10, 10}, {10, -1}} {
+		if _, err := NewRecursiveSplitter(tt.size, tt.overlap); err == nil {
+			t.Errorf("NewRecursiveSplitter(%d, %d) error = nil", tt.size, tt.overlap)
+		}
+	}
+}
+
+func TestApproximateTokenizer(t *testing.T) {
+	if got := ApproximateTokenizer.CountTokens("a tokenization  example"); got != 1+3+2 {
+		t.Errorf("CountTokens() = %d, want 6", got)
+	}
+}
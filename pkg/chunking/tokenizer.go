@@ -0,0 +1,39 @@
+package chunking
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Tokenizer counts the tokens of a text the way a model does, so chunks can
+// be sized against embedding and context limits
+type Tokenizer interface {
+	// CountTokens returns the number of tokens in text.
+	CountTokens(text string) int
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface
+type TokenizerFunc func(text string) int
+
+// CountTokens calls f(text)
+func (f TokenizerFunc) CountTokens(text string) int {
+	return f(text)
+}
+
+// ApproximateTokenizer estimates token counts without a vocabulary: a token
+// per 4 characters of each word, the ratio of BPE tokenizers on English
+// text. Use a model's tokenizer when limits are tight.
+var ApproximateTokenizer Tokenizer = TokenizerFunc(approximateTokens)
+
+func approximateTokens(text string) int {
+	tokens := 0
+	for _, word := range strings.Fields(text) {
+		tokens += (utf8.RuneCountInString(word) + 3) / 4
+	}
+	return tokens
+}
+
+// CharacterLength measures texts in characters (runes)
+func CharacterLength(text string) int {
+	return utf8.RuneCountInString(text)
+}
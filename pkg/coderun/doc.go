@@ -0,0 +1,20 @@
+// Package coderun provides adapters for the ports.CodeRunner interface
+// (pkg/ports in this repository), which runs untrusted programs such as
+// code interpreter tool calls in a sandbox, and ToolExecutor, which exposes
+// a runner to models as a run_code tool.
+//
+// Available implementations:
+//   - docker: A new container per run, optionally under gVisor
+//
+// Usage:
+//
+//	runner := docker.NewRunner(logger)
+//	runner.SetRuntime(docker.RuntimeGVisor)
+//
+//	tools := coderun.NewToolExecutor(runner, ports.CodeLimits{Timeout: 10 * time.Second}, logger)
+//	resp, err := client.CompleteWithTools(ctx, req, tools.Tools())
+//	for _, call := range resp.ToolCalls {
+//		result, err := tools.Execute(ctx, call)
+//		// Append result.Content to the conversation
+//	}
+package coderun
@@ -0,0 +1,31 @@
+// Package docker implements ports.CodeRunner (pkg/ports in this repository)
+// by running each program in a new container with the docker CLI.
+//
+// The code and request files are written to a temporary directory mounted
+// read-only as the working directory, /workspace. Containers run:
+//   - without network (--network none)
+//   - with a read-only root filesystem and a 64 MiB tmpfs at /tmp
+//   - as nobody, without capabilities or privilege escalation
+//   - with CPU, memory (no swap) and process limits
+//
+// Timed-out containers are force-removed. For stronger isolation than
+// namespaces, register gVisor with the Docker daemon and call
+// SetRuntime(RuntimeGVisor). SetBinary("podman") runs containers with
+// Podman instead.
+//
+// Images are not pulled by Run; pull the images of the configured languages
+// when the worker starts, or the first run of each pays for it.
+//
+// Usage:
+//
+//	runner := docker.NewRunner(logger)
+//	runner.SetRuntime(docker.RuntimeGVisor)
+//	runner.SetLanguage("r", docker.Language{Image: "r-base:4.4.1", Filename: "main.R", Command: []string{"Rscript", "main.R"}})
+//
+//	result, err := runner.Run(ctx, ports.CodeRequest{
+//		Language: "python",
+//		Code:     code,
+//		Files:    []ports.CodeFile{{Path: "data.csv", Content: csv}},
+//		Limits:   ports.CodeLimits{MemoryBytes: 512 << 20, Timeout: 10 * time.Second},
+//	})
+package docker
@@ -0,0 +1,329 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default limits of runs that don't set their own
+	DefaultCPUs           = 1.0
+	DefaultMemoryBytes    = 256 << 20
+	DefaultTimeout        = 30 * time.Second
+	DefaultMaxOutputBytes = 64 << 10
+
+	// RuntimeGVisor is the Docker runtime name gVisor is usually installed as
+	RuntimeGVisor = "runsc"
+
+	// Working directory of the program inside the container
+	workDir = "/workspace"
+
+	// Exit status of docker run when the container couldn't be created
+	dockerRunFailed = 125
+)
+
+// Language is how programs of one language are run
+type Language struct {
+	// Image is the container image, e.g. python:3.12-slim.
+	Image string
+
+	// Filename is the name the code is written to in the working directory.
+	Filename string
+
+	// Command runs the code file.
+	Command []string
+}
+
+// DefaultLanguages are the languages NewRunner supports
+var DefaultLanguages = map[string]Language{
+	"python":     {Image: "python:3.12-slim", Filename: "main.py", Command: []string{"python", "-B", "main.py"}},
+	"javascript": {Image: "node:22-slim", Filename: "main.js", Command: []string{"node", "main.js"}},
+	"bash":       {Image: "bash:5", Filename: "main.sh", Command: []string{"bash", "main.sh"}},
+}
+
+// Runner implements the ports.CodeRunner interface by running each program
+// in a new container with the docker CLI
+type Runner struct {
+	binary    string
+	runtime   string
+	languages map[string]Language
+	limits    ports.CodeLimits
+	logger    *zap.Logger
+
+	// run executes the docker CLI; replaced in tests
+	run func(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+// NewRunner creates a new Docker code runner with DefaultLanguages and the
+// default limits, using the docker binary from PATH and the daemon's
+// default runtime
+func NewRunner(logger *zap.Logger) *Runner {
+	languages := make(map[string]Language, len(DefaultLanguages))
+	for name, language := range DefaultLanguages {
+		languages[name] = language
+	}
+
+	r := &Runner{
+		binary:    "docker",
+		languages: languages,
+		limits: ports.CodeLimits{
+			CPUs:           DefaultCPUs,
+			MemoryBytes:    DefaultMemoryBytes,
+			Timeout:        DefaultTimeout,
+			MaxOutputBytes: DefaultMaxOutputBytes,
+		},
+		logger: logger,
+	}
+	r.run = r.exec
+	return r
+}
+
+// SetBinary sets the container CLI, e.g. a full path or "podman"
+func (r *Runner) SetBinary(binary string) {
+	r.binary = binary
+}
+
+// SetRuntime sets the OCI runtime containers run with, e.g. RuntimeGVisor.
+// The runtime must be registered with the Docker daemon.
+func (r *Runner) SetRuntime(runtime string) {
+	r.runtime = runtime
+}
+
+// SetLanguage adds a language or replaces how one is run
+func (r *Runner) SetLanguage(name string, language Language) {
+	r.languages[name] = language
+}
+
+// SetDefaultLimits sets the limits of runs that don't set their own. Zero
+// fields keep the current default.
+func (r *Runner) SetDefaultLimits(limits ports.CodeLimits) {
+	r.limits = mergeLimits(limits, r.limits)
+}
+
+// Languages returns the supported languages (ports.CodeRunner interface)
+func (r *Runner) Languages() []string {
+	names := make([]string, 0, len(r.languages))
+	for name := range r.languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run executes the program in a new container (ports.CodeRunner interface).
+// The container has no network, a read-only root filesystem and working
+// directory, a writable /tmp, no capabilities and runs as nobody.
+func (r *Runner) Run(ctx context.Context, req ports.CodeRequest) (*ports.CodeResult, error) {
+	language, ok := r.languages[req.Language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (supported: %s)", ports.ErrUnsupportedLanguage, req.Language, strings.Join(r.Languages(), ", "))
+	}
+	limits := mergeLimits(req.Limits, r.limits)
+
+	dir, err := writeWorkspace(language, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	name := "dago-code-" + randomSuffix()
+	args := r.runArgs(name, dir, language, limits, req.Env)
+
+	stdout := &limitedBuffer{limit: limits.MaxOutputBytes}
+	stderr := &limitedBuffer{limit: limits.MaxOutputBytes}
+
+	runCtx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err = r.run(runCtx, args, strings.NewReader(req.Stdin), stdout, stderr)
+	result := &ports.CodeResult{
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+		Duration:  time.Since(start),
+	}
+
+	if runCtx.Err() != nil {
+		// Killing the CLI doesn't stop the container
+		r.remove(name)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		result.TimedOut = true
+		result.ExitCode = -1
+		return result, nil
+	}
+
+	if err != nil {
+		var exitErr interface{ ExitCode() int }
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to run container: %w", err)
+		}
+		if exitErr.ExitCode() == dockerRunFailed {
+			return nil, fmt.Errorf("failed to run container: %s", strings.TrimSpace(result.Stderr))
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+
+	r.logger.Debug("code run finished",
+		zap.String("language", req.Language),
+		zap.Int("exit_code", result.ExitCode),
+		zap.Duration("duration", result.Duration))
+
+	return result, nil
+}
+
+// runArgs returns the docker run arguments of a program
+func (r *Runner) runArgs(name, dir string, language Language, limits ports.CodeLimits, env map[string]string) []string {
+	args := []string{
+		"run", "--rm", "--interactive",
+		"--name", name,
+		"--network", "none",
+		"--read-only",
+		"--tmpfs", "/tmp:rw,size=64m",
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+		"--user", "65534:65534",
+		"--pids-limit", "128",
+		"--cpus", strconv.FormatFloat(limits.CPUs, 'f', -1, 64),
+		"--memory", strconv.FormatInt(limits.MemoryBytes, 10),
+		"--memory-swap", strconv.FormatInt(limits.MemoryBytes, 10),
+		"--volume", dir + ":" + workDir + ":ro",
+		"--workdir", workDir,
+		"--env", "HOME=/tmp",
+	}
+	if r.runtime != "" {
+		args = append(args, "--runtime", r.runtime)
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--env", key+"="+env[key])
+	}
+
+	args = append(args, language.Image)
+	return append(args, language.Command...)
+}
+
+// exec runs the docker CLI
+func (r *Runner) exec(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// remove force-removes a container left running by a cancelled run
+func (r *Runner) remove(name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	if err := r.run(ctx, []string{"rm", "--force", name}, nil, io.Discard, &stderr); err != nil {
+		r.logger.Warn("failed to remove container",
+			zap.String("container", name),
+			zap.String("stderr", strings.TrimSpace(stderr.String())),
+			zap.Error(err))
+	}
+}
+
+// writeWorkspace writes the code and files to a new directory readable by
+// the container user
+func writeWorkspace(language Language, req ports.CodeRequest) (string, error) {
+	dir, err := os.MkdirTemp("", "dago-code-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	files := append([]ports.CodeFile{{Path: language.Filename, Content: []byte(req.Code)}}, req.Files...)
+	for _, file := range files {
+		path := filepath.Clean(filepath.FromSlash(file.Path))
+		if path == "." || filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("invalid file path %q", file.Path)
+		}
+
+		target := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+		if err := os.WriteFile(target, file.Content, 0o644); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+
+	// MkdirTemp creates the directory 0700
+	if err := os.Chmod(dir, 0o755); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return dir, nil
+}
+
+// mergeLimits fills the zero fields of limits from defaults
+func mergeLimits(limits, defaults ports.CodeLimits) ports.CodeLimits {
+	if limits.CPUs <= 0 {
+		limits.CPUs = defaults.CPUs
+	}
+	if limits.MemoryBytes <= 0 {
+		limits.MemoryBytes = defaults.MemoryBytes
+	}
+	if limits.Timeout <= 0 {
+		limits.Timeout = defaults.Timeout
+	}
+	if limits.MaxOutputBytes <= 0 {
+		limits.MaxOutputBytes = defaults.MaxOutputBytes
+	}
+	return limits
+}
+
+func randomSuffix() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - int64(b.buf.Len()); remaining < int64(len(p)) {
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
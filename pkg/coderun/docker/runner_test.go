@@ -0,0 +1,150 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.CodeRunner = (*Runner)(nil)
+
+type exitError int
+
+func (e exitError) Error() string { return "exit status" }
+func (e exitError) ExitCode() int { return int(e) }
+
+func TestRunner_Run(t *testing.T) {
+	runner := NewRunner(zap.NewNop())
+	runner.SetRuntime(RuntimeGVisor)
+
+	var gotArgs []string
+	var workspace map[string]string
+	runner.run = func(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+		gotArgs = args
+		workspace = map[string]string{}
+		for i, arg := range args {
+			if arg == "--volume" {
+				dir := strings.TrimSuffix(args[i+1], ":"+workDir+":ro")
+				_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+					if err == nil && !info.IsDir() {
+						data, _ := os.ReadFile(path)
+						rel, _ := filepath.Rel(dir, path)
+						workspace[filepath.ToSlash(rel)] = string(data)
+					}
+					return nil
+				})
+			}
+		}
+		input, _ := io.ReadAll(stdin)
+		_, _ = stdout.Write([]byte("hello " + string(input)))
+		_, _ = stderr.Write([]byte(strings.Repeat("w", 100)))
+		return exitError(3)
+	}
+
+	result, err := runner.Run(context.Background(), ports.CodeRequest{
+		Language: "python",
+		Code:     "print(open('data/in.txt').read())",
+		Stdin:    "world",
+		Files:    []ports.CodeFile{{Path: "data/in.txt", Content: []byte("input")}},
+		Env:      map[string]string{"MODE": "test"},
+		Limits:   ports.CodeLimits{MemoryBytes: 64 << 20, MaxOutputBytes: 50},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Stdout != "hello world" || len(result.Stderr) != 50 || !result.Truncated || result.ExitCode != 3 {
+		t.Errorf("Run() = %+v", result)
+	}
+
+	args := strings.Join(gotArgs, " ")
+	for _, want := range []string{
+		"--network none", "--read-only", "--runtime runsc", "--memory 67108864", "--cpus 1",
+		"--env MODE=test", "python:3.12-slim python -B main.py",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("docker args missing %q: %s", want, args)
+		}
+	}
+	if workspace["main.py"] == "" || workspace["data/in.txt"] != "input" {
+		t.Errorf("workspace = %v", workspace)
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	runner := NewRunner(zap.NewNop())
+
+	var removed string
+	runner.run = func(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+		if args[0] == "rm" {
+			removed = args[2]
+			return nil
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	result, err := runner.Run(context.Background(), ports.CodeRequest{
+		Language: "bash",
+		Code:     "sleep 60",
+		Limits:   ports.CodeLimits{Timeout: 20 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.TimedOut || !strings.HasPrefix(removed, "dago-code-") {
+		t.Errorf("Run() = %+v, removed %q, want timed out and container removed", result, removed)
+	}
+}
+
+func TestRunner_Errors(t *testing.T) {
+	runner := NewRunner(zap.NewNop())
+	runner.run = func(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+		_, _ = stderr.Write([]byte("Unable to find image"))
+		return exitError(dockerRunFailed)
+	}
+	ctx := context.Background()
+
+	if _, err := runner.Run(ctx, ports.CodeRequest{Language: "cobol", Code: "x"}); !errors.Is(err, ports.ErrUnsupportedLanguage) {
+		t.Errorf("Run() of an unknown language error = %v, want ErrUnsupportedLanguage", err)
+	}
+	if _, err := runner.Run(ctx, ports.CodeRequest{Language: "python", Code: "x"}); err == nil || !strings.Contains(err.Error(), "Unable to find image") {
+		t.Errorf("Run() when docker fails error = %v", err)
+	}
+	for _, path := range []string{"../escape", "/etc/passwd", "."} {
+		files := []ports.CodeFile{{Path: path}}
+		if _, err := runner.Run(ctx, ports.CodeRequest{Language: "python", Code: "x", Files: files}); err == nil {
+			t.Errorf("Run() with file %q expected error", path)
+		}
+	}
+}
+
+// Integration test - only runs with DOCKER_INTEGRATION environment variable
+func TestRunner_Integration(t *testing.T) {
+	if os.Getenv("DOCKER_INTEGRATION") == "" {
+		t.Skip("DOCKER_INTEGRATION not set, skipping integration test")
+	}
+
+	runner := NewRunner(zap.NewNop())
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	result, err := runner.Run(ctx, ports.CodeRequest{
+		Language: "python",
+		Code:     "import sys\nprint(sys.stdin.read().upper())\nsys.exit(2)",
+		Stdin:    "dago",
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if strings.TrimSpace(result.Stdout) != "DAGO" || result.ExitCode != 2 {
+		t.Errorf("Run() = %+v", result)
+	}
+}
@@ -0,0 +1,108 @@
+package coderun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// ToolName is the name of the code interpreter tool
+const ToolName = "run_code"
+
+// ToolExecutor implements the ports.ToolExecutor interface with a single
+// code interpreter tool backed by a ports.CodeRunner
+type ToolExecutor struct {
+	runner ports.CodeRunner
+	limits ports.CodeLimits
+	logger *zap.Logger
+}
+
+// NewToolExecutor creates a code interpreter tool executor. limits applies
+// to every run; the model can't change it.
+func NewToolExecutor(runner ports.CodeRunner, limits ports.CodeLimits, logger *zap.Logger) *ToolExecutor {
+	return &ToolExecutor{
+		runner: runner,
+		limits: limits,
+		logger: logger,
+	}
+}
+
+// Tools returns the run_code tool definition (ports.ToolExecutor interface)
+func (t *ToolExecutor) Tools() []libports.Tool {
+	languages := make([]interface{}, 0)
+	for _, language := range t.runner.Languages() {
+		languages = append(languages, language)
+	}
+
+	return []libports.Tool{{
+		Name: ToolName,
+		Description: "Runs a program in an isolated sandbox without network access and returns its " +
+			"standard output, standard error and exit code. Print the results you need.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"language": map[string]interface{}{
+					"type":        "string",
+					"enum":        languages,
+					"description": "Programming language of the code",
+				},
+				"code": map[string]interface{}{
+					"type":        "string",
+					"description": "Complete program source",
+				},
+				"stdin": map[string]interface{}{
+					"type":        "string",
+					"description": "Standard input of the program",
+				},
+			},
+			"required": []string{"language", "code"},
+		},
+	}}
+}
+
+// Execute runs the code of a run_code call (ports.ToolExecutor interface).
+// The result content is the JSON-encoded ports.CodeResult; it is an error
+// result if the program failed or timed out.
+func (t *ToolExecutor) Execute(ctx context.Context, call libports.ToolCall) (*ports.ToolResult, error) {
+	if call.Name != ToolName {
+		return nil, fmt.Errorf("%w: %s", ports.ErrToolNotFound, call.Name)
+	}
+
+	language, _ := call.Arguments["language"].(string)
+	code, _ := call.Arguments["code"].(string)
+	stdin, _ := call.Arguments["stdin"].(string)
+	if code == "" {
+		return nil, fmt.Errorf("tool %s: code is required", call.Name)
+	}
+
+	result := &ports.ToolResult{ToolCallID: call.ID, Name: call.Name}
+
+	run, err := t.runner.Run(ctx, ports.CodeRequest{
+		Language: language,
+		Code:     code,
+		Stdin:    stdin,
+		Limits:   t.limits,
+	})
+	if errors.Is(err, ports.ErrUnsupportedLanguage) {
+		// Let the model pick another language
+		result.Content = err.Error()
+		result.IsError = true
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", call.Name, err)
+	}
+
+	content, err := json.Marshal(run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	result.Content = string(content)
+	result.IsError = run.ExitCode != 0 || run.TimedOut
+	return result, nil
+}
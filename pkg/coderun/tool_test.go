@@ -0,0 +1,64 @@
+package coderun
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.ToolExecutor = (*ToolExecutor)(nil)
+
+type fakeRunner struct {
+	req ports.CodeRequest
+}
+
+func (f *fakeRunner) Run(ctx context.Context, req ports.CodeRequest) (*ports.CodeResult, error) {
+	f.req = req
+	if req.Language != "python" {
+		return nil, ports.ErrUnsupportedLanguage
+	}
+	return &ports.CodeResult{Stdout: "4\n"}, nil
+}
+
+func (f *fakeRunner) Languages() []string { return []string{"python"} }
+
+func TestToolExecutor(t *testing.T) {
+	runner := &fakeRunner{}
+	limits := ports.CodeLimits{CPUs: 0.5}
+	executor := NewToolExecutor(runner, limits, zap.NewNop())
+
+	tools := executor.Tools()
+	if len(tools) != 1 || tools[0].Name != ToolName {
+		t.Fatalf("Tools() = %+v", tools)
+	}
+
+	ctx := context.Background()
+	result, err := executor.Execute(ctx, libports.ToolCall{
+		ID:        "call-1",
+		Name:      ToolName,
+		Arguments: map[string]interface{}{"language": "python", "code": "print(2+2)"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.IsError || !strings.Contains(result.Content, `"stdout":"4\n"`) || runner.req.Limits != limits {
+		t.Errorf("Execute() = %+v, request %+v", result, runner.req)
+	}
+
+	result, err = executor.Execute(ctx, libports.ToolCall{
+		Name:      ToolName,
+		Arguments: map[string]interface{}{"language": "cobol", "code": "DISPLAY 4"},
+	})
+	if err != nil || !result.IsError {
+		t.Errorf("Execute() of an unsupported language = %+v, %v, want an error result", result, err)
+	}
+
+	if _, err := executor.Execute(ctx, libports.ToolCall{Name: "other"}); !errors.Is(err, ports.ErrToolNotFound) {
+		t.Errorf("Execute() of another tool error = %v, want ErrToolNotFound", err)
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Wait before watching again after a watch ended or failed to start
+const rewatchDelay = time.Second
+
+// GetJSON reads a key and decodes its JSON value
+func GetJSON[T any](ctx context.Context, store ports.ConfigStore, key string) (T, error) {
+	var value T
+	cv, err := store.Get(ctx, key)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(cv.Value, &value); err != nil {
+		return value, fmt.Errorf("failed to decode config %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// PutJSON encodes value as JSON and writes it to a key
+func PutJSON(ctx context.Context, store ports.ConfigStore, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode config %s: %w", key, err)
+	}
+	return store.Put(ctx, key, data)
+}
+
+// Value is a typed config key kept up to date by watching the store. Until
+// the key exists, and after it is deleted, it holds the default; values
+// that don't decode are logged and ignored.
+type Value[T any] struct {
+	store  ports.ConfigStore
+	key    string
+	def    T
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	value     T
+	revision  int64
+	listeners []func(T)
+}
+
+// NewValue reads key and watches it until ctx is cancelled. It fails only if
+// the key can't be read; a missing key yields def.
+func NewValue[T any](ctx context.Context, store ports.ConfigStore, key string, def T, logger *zap.Logger) (*Value[T], error) {
+	v := &Value[T]{
+		store:  store,
+		key:    key,
+		def:    def,
+		logger: logger,
+		value:  def,
+	}
+
+	// Watch before reading, so that no change made in between is missed
+	events, err := store.Watch(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	go v.watch(ctx, events)
+	return v, nil
+}
+
+// Load returns the current value
+func (v *Value[T]) Load() T {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.value
+}
+
+// OnChange registers fn to be called with every new value. Calls are made
+// one at a time from the watching goroutine.
+func (v *Value[T]) OnChange(fn func(T)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.listeners = append(v.listeners, fn)
+}
+
+// refresh reads the key, catching up on changes missed between watches
+func (v *Value[T]) refresh(ctx context.Context) error {
+	cv, err := v.store.Get(ctx, v.key)
+	if errors.Is(err, ports.ErrConfigNotFound) {
+		v.apply(ports.ConfigEvent{ConfigValue: ports.ConfigValue{Key: v.key}, Deleted: true})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	v.apply(ports.ConfigEvent{ConfigValue: *cv})
+	return nil
+}
+
+// watch applies events, watching again whenever the store ends the watch
+func (v *Value[T]) watch(ctx context.Context, events <-chan ports.ConfigEvent) {
+	for {
+		for event := range events {
+			// The prefix watch also reports longer keys, e.g. "llm" and "llm2"
+			if event.Key == v.key {
+				v.apply(event)
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(rewatchDelay):
+			}
+
+			var err error
+			if events, err = v.store.Watch(ctx, v.key); err == nil {
+				if err = v.refresh(ctx); err == nil {
+					break
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			v.logger.Warn("failed to watch config, retrying",
+				zap.String("key", v.key),
+				zap.Error(err))
+		}
+	}
+}
+
+// apply stores the event's value unless it is older than the current one
+func (v *Value[T]) apply(event ports.ConfigEvent) {
+	value := v.def
+	if !event.Deleted {
+		var decoded T
+		if err := json.Unmarshal(event.Value, &decoded); err != nil {
+			v.logger.Warn("ignoring invalid config value",
+				zap.String("key", v.key),
+				zap.Int64("revision", event.Revision),
+				zap.Error(err))
+			return
+		}
+		value = decoded
+	}
+
+	v.mu.Lock()
+	if event.Revision != 0 && event.Revision <= v.revision {
+		v.mu.Unlock()
+		return
+	}
+	if event.Revision != 0 {
+		v.revision = event.Revision
+	}
+	v.value = value
+	listeners := v.listeners
+	v.mu.Unlock()
+
+	v.logger.Debug("config updated",
+		zap.String("key", v.key),
+		zap.Int64("revision", event.Revision),
+		zap.Bool("deleted", event.Deleted))
+	for _, fn := range listeners {
+		fn(value)
+	}
+}
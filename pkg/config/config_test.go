@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// memoryStore is an in-memory ports.ConfigStore
+type memoryStore struct {
+	mu       sync.Mutex
+	values   map[string]ports.ConfigValue
+	revision int64
+	watches  map[chan ports.ConfigEvent]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		values:  map[string]ports.ConfigValue{},
+		watches: map[chan ports.ConfigEvent]string{},
+	}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (*ports.ConfigValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrConfigNotFound, key)
+	}
+	return &value, nil
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revision++
+	cv := ports.ConfigValue{Key: key, Value: value, Revision: s.revision}
+	s.values[key] = cv
+	s.notify(ports.ConfigEvent{ConfigValue: cv})
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; !ok {
+		return nil
+	}
+	s.revision++
+	delete(s.values, key)
+	s.notify(ports.ConfigEvent{ConfigValue: ports.ConfigValue{Key: key, Revision: s.revision}, Deleted: true})
+	return nil
+}
+
+func (s *memoryStore) List(ctx context.Context, prefix string) ([]ports.ConfigValue, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *memoryStore) Watch(ctx context.Context, prefix string) (<-chan ports.ConfigEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make(chan ports.ConfigEvent, 16)
+	s.watches[events] = prefix
+	return events, nil
+}
+
+func (s *memoryStore) notify(event ports.ConfigEvent) {
+	for events, prefix := range s.watches {
+		if strings.HasPrefix(event.Key, prefix) {
+			events <- event
+		}
+	}
+}
+
+// endWatches closes all watches, like a store losing its connection
+func (s *memoryStore) endWatches() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for events := range s.watches {
+		close(events)
+		delete(s.watches, events)
+	}
+}
+
+type rules struct {
+	Default string `json:"default"`
+}
+
+func TestGetPutJSON(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+
+	if _, err := GetJSON[rules](ctx, store, "routing"); !errors.Is(err, ports.ErrConfigNotFound) {
+		t.Errorf("GetJSON() of a missing key error = %v, want not found", err)
+	}
+
+	if err := PutJSON(ctx, store, "routing", rules{Default: "executor"}); err != nil {
+		t.Fatalf("PutJSON() error = %v", err)
+	}
+	got, err := GetJSON[rules](ctx, store, "routing")
+	if err != nil || got.Default != "executor" {
+		t.Errorf("GetJSON() = %+v, %v", got, err)
+	}
+
+	_ = store.Put(ctx, "routing", []byte("{"))
+	if _, err := GetJSON[rules](ctx, store, "routing"); err == nil {
+		t.Error("GetJSON() of invalid JSON expected error")
+	}
+}
+
+func TestValue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := newMemoryStore()
+	def := rules{Default: "fallback"}
+
+	v, err := NewValue(ctx, store, "routing", def, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewValue() error = %v", err)
+	}
+	if got := v.Load(); got != def {
+		t.Errorf("Load() of a missing key = %+v, want the default", got)
+	}
+
+	changes := make(chan rules, 16)
+	v.OnChange(func(r rules) { changes <- r })
+
+	_ = PutJSON(ctx, store, "routing", rules{Default: "executor"})
+	if got := next(t, changes); got.Default != "executor" {
+		t.Errorf("change = %+v, want executor", got)
+	}
+
+	// Longer keys sharing the prefix and invalid values are ignored
+	_ = PutJSON(ctx, store, "routing2", rules{Default: "other"})
+	_ = store.Put(ctx, "routing", []byte("{"))
+	if got := v.Load(); got.Default != "executor" {
+		t.Errorf("Load() = %+v, want executor", got)
+	}
+
+	_ = store.Delete(ctx, "routing")
+	if got := next(t, changes); got != def {
+		t.Errorf("change after Delete() = %+v, want the default", got)
+	}
+
+	// Changes made while the watch is down are caught up on
+	store.endWatches()
+	_ = PutJSON(ctx, store, "routing", rules{Default: "planner"})
+	if got := next(t, changes); got.Default != "planner" {
+		t.Errorf("change after rewatch = %+v, want planner", got)
+	}
+	if got := v.Load(); got.Default != "planner" {
+		t.Errorf("Load() = %+v, want planner", got)
+	}
+}
+
+func next(t *testing.T, changes <-chan rules) rules {
+	t.Helper()
+	select {
+	case r := <-changes:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change")
+		return rules{}
+	}
+}
@@ -0,0 +1,14 @@
+// Package consul implements ports.ConfigStore (pkg/ports in this repository)
+// with the Consul KV store.
+//
+// Keys are stored under dago/config/ (see SetPrefix) and revisions are the
+// Consul modify indexes. Watch polls the prefix with blocking queries and
+// reports the keys that were written or removed since the previous result.
+//
+// Usage:
+//
+//	client, _ := api.NewClient(api.DefaultConfig())
+//	store := consul.NewStore(client, logger)
+//
+//	events, err := store.Watch(ctx, "routing/")
+package consul
@@ -0,0 +1,182 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/hashicorp/consul/api"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default prefix of config keys
+	defaultPrefix = "dago/config/"
+
+	// How long a blocking query waits for changes before returning
+	watchWaitTime = 5 * time.Minute
+
+	// Wait before retrying a failed blocking query
+	watchRetryDelay = time.Second
+)
+
+// Store implements ports.ConfigStore using the Consul KV store
+type Store struct {
+	client *api.Client
+	logger *zap.Logger
+	prefix string
+}
+
+// NewStore creates a new Consul config store
+func NewStore(client *api.Client, logger *zap.Logger) *Store {
+	return &Store{
+		client: client,
+		logger: logger,
+		prefix: defaultPrefix,
+	}
+}
+
+// SetPrefix sets the prefix of config keys, e.g. to isolate environments
+func (s *Store) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+// Get returns a key's value (ports.ConfigStore interface)
+func (s *Store) Get(ctx context.Context, key string) (*ports.ConfigValue, error) {
+	pair, _, err := s.client.KV().Get(s.prefix+key, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	if pair == nil {
+		return nil, fmt.Errorf("%w: %s", ports.ErrConfigNotFound, key)
+	}
+
+	value := s.value(pair)
+	return &value, nil
+}
+
+// Put writes a key's value (ports.ConfigStore interface)
+func (s *Store) Put(ctx context.Context, key string, value []byte) error {
+	pair := &api.KVPair{Key: s.prefix + key, Value: value}
+	if _, err := s.client.KV().Put(pair, (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to put config: %w", err)
+	}
+
+	s.logger.Debug("config written", zap.String("key", key))
+	return nil
+}
+
+// Delete removes a key (ports.ConfigStore interface)
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if _, err := s.client.KV().Delete(s.prefix+key, (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to delete config: %w", err)
+	}
+	return nil
+}
+
+// List returns the keys starting with prefix (ports.ConfigStore interface)
+func (s *Store) List(ctx context.Context, prefix string) ([]ports.ConfigValue, error) {
+	pairs, _, err := s.client.KV().List(s.prefix+prefix, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config: %w", err)
+	}
+
+	values := make([]ports.ConfigValue, 0, len(pairs))
+	for _, pair := range pairs {
+		values = append(values, s.value(pair))
+	}
+	return values, nil
+}
+
+// Watch streams changes to the keys starting with prefix until ctx is
+// cancelled (ports.ConfigStore interface). It polls the prefix with
+// blocking queries and compares each result with the previous one; failed
+// queries are retried.
+func (s *Store) Watch(ctx context.Context, prefix string) (<-chan ports.ConfigEvent, error) {
+	pairs, meta, err := s.client.KV().List(s.prefix+prefix, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config: %w", err)
+	}
+	known := s.snapshot(pairs)
+	index := meta.LastIndex
+	events := make(chan ports.ConfigEvent)
+
+	go func() {
+		defer close(events)
+
+		for {
+			opts := (&api.QueryOptions{WaitIndex: index, WaitTime: watchWaitTime}).WithContext(ctx)
+			pairs, meta, err := s.client.KV().List(s.prefix+prefix, opts)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				s.logger.Warn("config watch failed, retrying", zap.Error(err))
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(watchRetryDelay):
+				}
+				continue
+			}
+
+			if meta.LastIndex < index {
+				// The index went backwards, e.g. after a snapshot restore
+				index = 0
+				continue
+			}
+			index = meta.LastIndex
+
+			current := s.snapshot(pairs)
+			for _, event := range diff(known, current, int64(meta.LastIndex)) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			known = current
+		}
+	}()
+
+	return events, nil
+}
+
+func (s *Store) value(pair *api.KVPair) ports.ConfigValue {
+	return ports.ConfigValue{
+		Key:      strings.TrimPrefix(pair.Key, s.prefix),
+		Value:    pair.Value,
+		Revision: int64(pair.ModifyIndex),
+	}
+}
+
+func (s *Store) snapshot(pairs api.KVPairs) map[string]ports.ConfigValue {
+	values := make(map[string]ports.ConfigValue, len(pairs))
+	for _, pair := range pairs {
+		value := s.value(pair)
+		values[value.Key] = value
+	}
+	return values
+}
+
+// diff returns the events turning known into current; deletions get the
+// query's index as revision
+func diff(known, current map[string]ports.ConfigValue, index int64) []ports.ConfigEvent {
+	var events []ports.ConfigEvent
+	for key, value := range current {
+		if previous, ok := known[key]; !ok || previous.Revision != value.Revision {
+			events = append(events, ports.ConfigEvent{ConfigValue: value})
+		}
+	}
+	for key := range known {
+		if _, ok := current[key]; !ok {
+			events = append(events, ports.ConfigEvent{
+				ConfigValue: ports.ConfigValue{Key: key, Revision: index},
+				Deleted:     true,
+			})
+		}
+	}
+	return events
+}
@@ -0,0 +1,97 @@
+package consul
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/hashicorp/consul/api"
+	"go.uber.org/zap"
+)
+
+var _ ports.ConfigStore = (*Store)(nil)
+
+func TestDiff(t *testing.T) {
+	known := map[string]ports.ConfigValue{
+		"a": {Key: "a", Value: []byte("1"), Revision: 10},
+		"b": {Key: "b", Value: []byte("2"), Revision: 11},
+		"c": {Key: "c", Value: []byte("3"), Revision: 12},
+	}
+	current := map[string]ports.ConfigValue{
+		"a": {Key: "a", Value: []byte("1"), Revision: 10},
+		"b": {Key: "b", Value: []byte("20"), Revision: 15},
+		"d": {Key: "d", Value: []byte("4"), Revision: 16},
+	}
+
+	got := map[string]ports.ConfigEvent{}
+	for _, event := range diff(known, current, 16) {
+		got[event.Key] = event
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("diff() = %+v, want events for b, c and d", got)
+	}
+	if e := got["b"]; e.Deleted || string(e.Value) != "20" || e.Revision != 15 {
+		t.Errorf("event b = %+v, want the update", e)
+	}
+	if e := got["c"]; !e.Deleted || e.Revision != 16 {
+		t.Errorf("event c = %+v, want a deletion at 16", e)
+	}
+	if e := got["d"]; e.Deleted || string(e.Value) != "4" {
+		t.Errorf("event d = %+v, want the creation", e)
+	}
+}
+
+// Integration test - only runs with CONSUL_HTTP_ADDR environment variable
+func TestStore_Integration(t *testing.T) {
+	if os.Getenv("CONSUL_HTTP_ADDR") == "" {
+		t.Skip("CONSUL_HTTP_ADDR not set, skipping integration test")
+	}
+
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s := NewStore(client, zap.NewNop())
+	s.SetPrefix("dago/test/config/")
+	defer func() { _, _ = client.KV().DeleteTree("dago/test/config/", nil) }()
+
+	events, err := s.Watch(ctx, "routing/")
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if err := s.Put(ctx, "routing/rules", []byte(`{"default":"executor"}`)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, err := s.Get(ctx, "routing/rules")
+	if err != nil || string(got.Value) != `{"default":"executor"}` || got.Revision == 0 {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+
+	list, err := s.List(ctx, "routing/")
+	if err != nil || len(list) != 1 || list[0].Key != "routing/rules" {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+
+	if put := <-events; put.Key != "routing/rules" || put.Deleted {
+		t.Errorf("first event = %+v, want the put", put)
+	}
+
+	if err := s.Delete(ctx, "routing/rules"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, "routing/rules"); !errors.Is(err, ports.ErrConfigNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want not found", err)
+	}
+	if deleted := <-events; deleted.Key != "routing/rules" || !deleted.Deleted {
+		t.Errorf("second event = %+v, want the delete", deleted)
+	}
+}
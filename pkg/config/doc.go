@@ -0,0 +1,23 @@
+// Package config provides adapters for the ports.ConfigStore interface
+// (pkg/ports in this repository), which holds configuration shared by all
+// instances, and helpers to read it as typed values.
+//
+// Value keeps a JSON key decoded and up to date by watching the store, so
+// that changes, such as new routing rules or LLM settings, are applied
+// without restarting workers. llm.NewReloadingClient recreates an LLM client
+// whenever its key changes.
+//
+// Available implementations:
+//   - etcd: Keys under /dago/config/, watched with the etcd watch API
+//   - consul: Keys under dago/config/ in the Consul KV store, watched with
+//     blocking queries
+//
+// Usage:
+//
+//	store := etcd.NewStore(client, logger)
+//	err := config.PutJSON(ctx, store, "routing/rules", rules)
+//
+//	routing, err := config.NewValue(ctx, store, "routing/rules", defaultRules, logger)
+//	routing.OnChange(func(r Rules) { logger.Info("routing rules changed") })
+//	target := routing.Load().Route(task)
+package config
@@ -0,0 +1,14 @@
+// Package etcd implements ports.ConfigStore (pkg/ports in this repository)
+// with etcd.
+//
+// Keys are stored under /dago/config/ (see SetPrefix) and revisions are the
+// etcd mod revisions. Watch uses the etcd watch API and requires a leader,
+// so a watch on a partitioned member ends instead of going silent.
+//
+// Usage:
+//
+//	client, _ := clientv3.New(clientv3.Config{Endpoints: []string{"localhost:2379"}})
+//	store := etcd.NewStore(client, logger)
+//
+//	events, err := store.Watch(ctx, "routing/")
+package etcd
@@ -0,0 +1,124 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// Default prefix of config keys
+const defaultPrefix = "/dago/config/"
+
+// Store implements ports.ConfigStore using etcd
+type Store struct {
+	client *clientv3.Client
+	logger *zap.Logger
+	prefix string
+}
+
+// NewStore creates a new etcd config store
+func NewStore(client *clientv3.Client, logger *zap.Logger) *Store {
+	return &Store{
+		client: client,
+		logger: logger,
+		prefix: defaultPrefix,
+	}
+}
+
+// SetPrefix sets the prefix of config keys, e.g. to isolate environments
+func (s *Store) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+// Get returns a key's value (ports.ConfigStore interface)
+func (s *Store) Get(ctx context.Context, key string) (*ports.ConfigValue, error) {
+	resp, err := s.client.Get(ctx, s.prefix+key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ports.ErrConfigNotFound, key)
+	}
+
+	kv := resp.Kvs[0]
+	return &ports.ConfigValue{Key: key, Value: kv.Value, Revision: kv.ModRevision}, nil
+}
+
+// Put writes a key's value (ports.ConfigStore interface)
+func (s *Store) Put(ctx context.Context, key string, value []byte) error {
+	if _, err := s.client.Put(ctx, s.prefix+key, string(value)); err != nil {
+		return fmt.Errorf("failed to put config: %w", err)
+	}
+
+	s.logger.Debug("config written", zap.String("key", key))
+	return nil
+}
+
+// Delete removes a key (ports.ConfigStore interface)
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if _, err := s.client.Delete(ctx, s.prefix+key); err != nil {
+		return fmt.Errorf("failed to delete config: %w", err)
+	}
+	return nil
+}
+
+// List returns the keys starting with prefix (ports.ConfigStore interface)
+func (s *Store) List(ctx context.Context, prefix string) ([]ports.ConfigValue, error) {
+	resp, err := s.client.Get(ctx, s.prefix+prefix, clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config: %w", err)
+	}
+
+	values := make([]ports.ConfigValue, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		values = append(values, ports.ConfigValue{
+			Key:      strings.TrimPrefix(string(kv.Key), s.prefix),
+			Value:    kv.Value,
+			Revision: kv.ModRevision,
+		})
+	}
+	return values, nil
+}
+
+// Watch streams changes to the keys starting with prefix until ctx is
+// cancelled (ports.ConfigStore interface)
+func (s *Store) Watch(ctx context.Context, prefix string) (<-chan ports.ConfigEvent, error) {
+	watchChan := s.client.Watch(clientv3.WithRequireLeader(ctx), s.prefix+prefix, clientv3.WithPrefix())
+	events := make(chan ports.ConfigEvent)
+
+	go func() {
+		defer close(events)
+
+		for resp := range watchChan {
+			if err := resp.Err(); err != nil {
+				s.logger.Warn("config watch failed", zap.Error(err))
+				return
+			}
+
+			for _, ev := range resp.Events {
+				event := ports.ConfigEvent{ConfigValue: ports.ConfigValue{
+					Key:      strings.TrimPrefix(string(ev.Kv.Key), s.prefix),
+					Revision: ev.Kv.ModRevision,
+				}}
+				if ev.Type == clientv3.EventTypeDelete {
+					event.Deleted = true
+				} else {
+					event.Value = ev.Kv.Value
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
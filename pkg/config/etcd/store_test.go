@@ -0,0 +1,73 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+var _ ports.ConfigStore = (*Store)(nil)
+
+// Integration test - only runs with ETCD_ENDPOINTS environment variable
+func TestStore_Integration(t *testing.T) {
+	endpoints := os.Getenv("ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Skip("ETCD_ENDPOINTS not set, skipping integration test")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s := NewStore(client, zap.NewNop())
+	s.SetPrefix("/dago/test/config/")
+	defer func() { _, _ = client.Delete(context.Background(), "/dago/test/config/", clientv3.WithPrefix()) }()
+
+	events, err := s.Watch(ctx, "routing/")
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if err := s.Put(ctx, "routing/rules", []byte(`{"default":"executor"}`)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, err := s.Get(ctx, "routing/rules")
+	if err != nil || string(got.Value) != `{"default":"executor"}` || got.Revision == 0 {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+
+	list, err := s.List(ctx, "routing/")
+	if err != nil || len(list) != 1 || list[0].Key != "routing/rules" {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+
+	if err := s.Delete(ctx, "routing/rules"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, "routing/rules"); !errors.Is(err, ports.ErrConfigNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want not found", err)
+	}
+
+	put, deleted := <-events, <-events
+	if put.Key != "routing/rules" || put.Deleted || put.Revision != got.Revision {
+		t.Errorf("first event = %+v, want the put", put)
+	}
+	if !deleted.Deleted || deleted.Revision <= put.Revision {
+		t.Errorf("second event = %+v, want the delete", deleted)
+	}
+}
@@ -0,0 +1,28 @@
+// Package documents provides adapters for the ports.DocumentLoader
+// interface (pkg/ports in this repository), which extracts normalized text
+// with sections and metadata from files for ingestion.
+//
+// Available implementations:
+//   - pdf: Text layer of PDF files, a section per page
+//   - html: Main content of web pages, without scripts, menus and other boilerplate
+//   - docx: Word documents, with heading styles as sections
+//   - markdown: Markdown and plain text, with YAML front matter as metadata
+//
+// Router picks the loader of each document by its extension, or its content
+// when the extension is unknown. The Builder and Normalize helpers are
+// shared by the loaders.
+//
+// Usage:
+//
+//	loader := documents.NewRouter(
+//		pdf.NewLoader(logger),
+//		html.NewLoader(logger),
+//		docx.NewLoader(logger),
+//		markdown.NewLoader(logger),
+//	)
+//
+//	doc, err := loader.LoadFile(ctx, "handbook.pdf")
+//	for _, section := range doc.Sections {
+//		// section.Page, section.Offset into doc.Content
+//	}
+package documents
@@ -0,0 +1,14 @@
+// Package docx implements ports.DocumentLoader (pkg/ports in this
+// repository) for Word documents in Office Open XML format (.docx).
+//
+// Paragraphs of the document body become paragraphs of the content, list
+// items are prefixed with "- ", and paragraphs with the title, a heading
+// style or an outline level start sections. Styles are recognized by name,
+// so localized style IDs work. Metadata comes from docProps/core.xml.
+// Legacy .doc files are not supported.
+//
+// Usage:
+//
+//	loader := docx.NewLoader(logger)
+//	doc, err := loader.Load(ctx, file, "onboarding.docx")
+package docx
@@ -0,0 +1,299 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/documents"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Namespace of WordprocessingML elements
+const wordNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// Loader implements the ports.DocumentLoader interface for Word documents
+// (Office Open XML, .docx)
+type Loader struct {
+	logger *zap.Logger
+}
+
+// NewLoader creates a new DOCX loader
+func NewLoader(logger *zap.Logger) *Loader {
+	return &Loader{logger: logger}
+}
+
+// MIMETypes returns the DOCX MIME type (ports.DocumentLoader interface)
+func (l *Loader) MIMETypes() []string {
+	return []string{documents.MIMETypeDOCX}
+}
+
+// Load extracts the paragraphs of the document body and its core properties
+// (ports.DocumentLoader interface). Paragraphs with a heading or title style
+// start sections; list items are prefixed with "- ".
+func (l *Loader) Load(ctx context.Context, r io.Reader, source string) (*ports.Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DOCX: %w", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX %s: %w", source, err)
+	}
+
+	styles, err := readStyles(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DOCX styles %s: %w", source, err)
+	}
+
+	body, err := archive.Open("word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX body %s: %w", source, err)
+	}
+	defer func() { _ = body.Close() }()
+
+	var builder documents.Builder
+	var title string
+	err = readParagraphs(ctx, body, func(p paragraph) {
+		level := styles.headingLevel(p.style, p.outlineLevel)
+		switch {
+		case level == 0 && title == "":
+			title = documents.NormalizeSpace(p.text.String())
+			builder.Heading(title, 1)
+		case level > 0:
+			builder.Heading(p.text.String(), level)
+		case p.list:
+			builder.Paragraph("- " + p.text.String())
+		default:
+			builder.Paragraph(p.text.String())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DOCX body %s: %w", source, err)
+	}
+
+	doc := &ports.Document{
+		Source:   source,
+		MIMEType: documents.MIMETypeDOCX,
+		Content:  builder.Content(),
+		Sections: builder.Sections(),
+		Metadata: map[string]string{},
+	}
+	if err := readCoreProperties(archive, doc); err != nil {
+		l.logger.Warn("failed to read DOCX properties", zap.String("source", source), zap.Error(err))
+	}
+	if doc.Title == "" {
+		doc.Title = title
+	}
+	if doc.Title == "" && len(doc.Sections) > 0 {
+		doc.Title = doc.Sections[0].Heading
+	}
+
+	l.logger.Debug("DOCX loaded",
+		zap.String("source", source),
+		zap.Int("length", len(doc.Content)))
+
+	return doc, nil
+}
+
+// paragraph is a w:p element of the body
+type paragraph struct {
+	text         strings.Builder
+	style        string
+	outlineLevel int // 1-based, 0 when unset
+	list         bool
+}
+
+// readParagraphs streams the body, calling emit for each paragraph
+func readParagraphs(ctx context.Context, r io.Reader, emit func(paragraph)) error {
+	decoder := xml.NewDecoder(r)
+	var current *paragraph
+	inText := false
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				current = &paragraph{}
+			case "pStyle":
+				if current != nil {
+					current.style = wordAttr(t, "val")
+				}
+			case "outlineLvl":
+				if current != nil {
+					if level, err := strconv.Atoi(wordAttr(t, "val")); err == nil {
+						current.outlineLevel = level + 1
+					}
+				}
+			case "numPr":
+				if current != nil {
+					current.list = true
+				}
+			case "t":
+				inText = true
+			case "tab":
+				if current != nil {
+					current.text.WriteByte('\t')
+				}
+			case "br", "cr":
+				if current != nil {
+					current.text.WriteByte(' ')
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if current != nil {
+					emit(*current)
+				}
+				current = nil
+			}
+		case xml.CharData:
+			if inText && current != nil {
+				current.text.Write(t)
+			}
+		}
+	}
+}
+
+// styleSheet holds what identifies heading styles in word/styles.xml
+type styleSheet struct {
+	names         map[string]string
+	outlineLevels map[string]int
+}
+
+// headingLevel returns 0 for the title style, 1-9 for headings, or -1 for
+// other paragraphs
+func (s styleSheet) headingLevel(style string, outlineLevel int) int {
+	name := strings.ToLower(strings.ReplaceAll(style, " ", ""))
+	if styleName, ok := s.names[style]; ok {
+		name = strings.ToLower(strings.ReplaceAll(styleName, " ", ""))
+	}
+	if name == "title" {
+		return 0
+	}
+	if level, err := strconv.Atoi(strings.TrimPrefix(name, "heading")); err == nil && strings.HasPrefix(name, "heading") && level >= 1 && level <= 9 {
+		return level
+	}
+	if outlineLevel == 0 {
+		outlineLevel = s.outlineLevels[style]
+	}
+	if outlineLevel >= 1 && outlineLevel <= 9 {
+		return outlineLevel
+	}
+	return -1
+}
+
+// readStyles reads the style names and outline levels. Style IDs are
+// localized (e.g. "Titre1" in French), but names of built-in styles are not.
+func readStyles(archive *zip.Reader) (styleSheet, error) {
+	sheet := styleSheet{names: map[string]string{}, outlineLevels: map[string]int{}}
+
+	file, err := archive.Open("word/styles.xml")
+	if err != nil {
+		// Styles are optional
+		return sheet, nil
+	}
+	defer func() { _ = file.Close() }()
+
+	var styles struct {
+		Styles []struct {
+			ID   string `xml:"styleId,attr"`
+			Name struct {
+				Val string `xml:"val,attr"`
+			} `xml:"name"`
+			Paragraph struct {
+				OutlineLevel *struct {
+					Val int `xml:"val,attr"`
+				} `xml:"outlineLvl"`
+			} `xml:"pPr"`
+		} `xml:"style"`
+	}
+	if err := xml.NewDecoder(file).Decode(&styles); err != nil {
+		return sheet, err
+	}
+
+	for _, style := range styles.Styles {
+		sheet.names[style.ID] = style.Name.Val
+		if style.Paragraph.OutlineLevel != nil {
+			sheet.outlineLevels[style.ID] = style.Paragraph.OutlineLevel.Val + 1
+		}
+	}
+	return sheet, nil
+}
+
+// readCoreProperties reads docProps/core.xml into the document metadata
+func readCoreProperties(archive *zip.Reader, doc *ports.Document) error {
+	file, err := archive.Open("docProps/core.xml")
+	if err != nil {
+		// Properties are optional
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+
+	var props struct {
+		Title       string `xml:"title"`
+		Subject     string `xml:"subject"`
+		Creator     string `xml:"creator"`
+		Description string `xml:"description"`
+		Language    string `xml:"language"`
+		Created     string `xml:"created"`
+		Modified    string `xml:"modified"`
+	}
+	if err := xml.NewDecoder(file).Decode(&props); err != nil {
+		return err
+	}
+
+	doc.Title = documents.NormalizeSpace(props.Title)
+	description := props.Description
+	if description == "" {
+		description = props.Subject
+	}
+	setMeta(doc.Metadata, ports.DocumentMetaAuthor, documents.NormalizeSpace(props.Creator))
+	setMeta(doc.Metadata, ports.DocumentMetaDescription, documents.NormalizeSpace(description))
+	setMeta(doc.Metadata, ports.DocumentMetaLanguage, strings.TrimSpace(props.Language))
+	setMeta(doc.Metadata, ports.DocumentMetaCreated, strings.TrimSpace(props.Created))
+	setMeta(doc.Metadata, ports.DocumentMetaModified, strings.TrimSpace(props.Modified))
+	return nil
+}
+
+func wordAttr(element xml.StartElement, local string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == local && (a.Name.Space == wordNamespace || a.Name.Space == "") {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func setMeta(metadata map[string]string, key, value string) {
+	if value != "" {
+		metadata[key] = value
+	}
+}
@@ -0,0 +1,108 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.DocumentLoader = (*Loader)(nil)
+
+const documentXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:body>
+    <w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Onboarding</w:t></w:r></w:p>
+    <w:p><w:pPr><w:pStyle w:val="Titre1"/></w:pPr><w:r><w:t>First day</w:t></w:r></w:p>
+    <w:p><w:r><w:t xml:space="preserve">Pick up your </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>badge</w:t></w:r><w:r><w:tab/><w:t>at reception.</w:t></w:r></w:p>
+    <w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Laptop</w:t></w:r></w:p>
+    <w:p></w:p>
+    <w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Access</w:t></w:r></w:p>
+    <w:tbl><w:tr><w:tc><w:p><w:r><w:t>VPN</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+  </w:body>
+</w:document>`
+
+const stylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:style w:type="paragraph" w:styleId="Titre1"><w:name w:val="heading 1"/><w:pPr><w:outlineLvl w:val="0"/></w:pPr></w:style>
+</w:styles>`
+
+const coreXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+  xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">
+  <dc:creator>HR Team</dc:creator>
+  <dc:language>fr-FR</dc:language>
+  <dcterms:created>2024-05-02T08:00:00Z</dcterms:created>
+</cp:coreProperties>`
+
+func buildDOCX(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoader(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	data := buildDOCX(t, map[string]string{
+		"word/document.xml": documentXML,
+		"word/styles.xml":   stylesXML,
+		"docProps/core.xml": coreXML,
+	})
+
+	doc, err := loader.Load(context.Background(), bytes.NewReader(data), "onboarding.docx")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := "Onboarding\n\nFirst day\n\nPick up your badge at reception.\n\n- Laptop\n\nAccess\n\nVPN"
+	if doc.Content != want {
+		t.Errorf("Content = %q, want %q", doc.Content, want)
+	}
+	if doc.Title != "Onboarding" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	if doc.Metadata[ports.DocumentMetaAuthor] != "HR Team" ||
+		doc.Metadata[ports.DocumentMetaLanguage] != "fr-FR" ||
+		doc.Metadata[ports.DocumentMetaCreated] != "2024-05-02T08:00:00Z" {
+		t.Errorf("Metadata = %v", doc.Metadata)
+	}
+
+	levels := []int{1, 1, 2}
+	if len(doc.Sections) != len(levels) {
+		t.Fatalf("Sections = %+v", doc.Sections)
+	}
+	for i, section := range doc.Sections {
+		if section.Level != levels[i] || !strings.HasPrefix(doc.Content[section.Offset:], section.Heading) {
+			t.Errorf("Sections[%d] = %+v", i, section)
+		}
+	}
+}
+
+func TestLoaderInvalid(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	if _, err := loader.Load(context.Background(), strings.NewReader("not a zip"), "broken.docx"); err == nil {
+		t.Error("Load() error = nil, want error")
+	}
+
+	data := buildDOCX(t, map[string]string{"other.xml": "<x/>"})
+	if _, err := loader.Load(context.Background(), bytes.NewReader(data), "empty.docx"); err == nil {
+		t.Error("Load() without word/document.xml error = nil, want error")
+	}
+}
@@ -0,0 +1,18 @@
+// Package html implements ports.DocumentLoader (pkg/ports in this
+// repository) for HTML pages.
+//
+// Like browser reader modes, the loader keeps the main content of the page:
+// the first <article> or <main> element, or else <body> without its header
+// and footer. Scripts, styles, navigation, forms, sidebars, hidden elements
+// and elements whose class or id names page furniture (menus, cookie
+// banners, share buttons, comments) are dropped. Headings start sections;
+// the title, description, author and language come from the head.
+//
+// Usage:
+//
+//	resp, err := http.Get(url)
+//	defer resp.Body.Close()
+//
+//	loader := html.NewLoader(logger)
+//	doc, err := loader.Load(ctx, resp.Body, url)
+package html
@@ -0,0 +1,299 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/documents"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// boilerplateNames matches class and id values of page furniture, such as
+// menus, sidebars, cookie banners and share buttons
+var boilerplateNames = regexp.MustCompile(`(?i)(^|[-_\s])(nav|navbar|menu|sidebar|footer|cookies?|banner|ads?|advert\w*|share|social|comments?|breadcrumbs?|related|popup|modal|newsletter|skip-link)([-_\s]|$)`)
+
+// Elements that never hold content
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Aside: true, atom.Form: true, atom.Button: true,
+	atom.Select: true, atom.Iframe: true, atom.Svg: true, atom.Canvas: true,
+	atom.Object: true, atom.Embed: true, atom.Dialog: true,
+}
+
+// Elements that end the current paragraph
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Main: true, atom.Header: true, atom.Footer: true, atom.Blockquote: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Dl: true, atom.Dt: true,
+	atom.Dd: true, atom.Table: true, atom.Tr: true, atom.Figure: true,
+	atom.Figcaption: true, atom.Hr: true, atom.Br: true, atom.Address: true,
+	atom.Details: true, atom.Summary: true, atom.Caption: true,
+}
+
+// Loader implements the ports.DocumentLoader interface for HTML pages. Like
+// reader modes, it keeps the main content of the page and drops scripts,
+// navigation and other boilerplate.
+type Loader struct {
+	logger *zap.Logger
+}
+
+// NewLoader creates a new HTML loader
+func NewLoader(logger *zap.Logger) *Loader {
+	return &Loader{logger: logger}
+}
+
+// MIMETypes returns the HTML MIME types (ports.DocumentLoader interface)
+func (l *Loader) MIMETypes() []string {
+	return []string{documents.MIMETypeHTML, "application/xhtml+xml"}
+}
+
+// Load extracts the main content of a page (ports.DocumentLoader interface).
+// The content is read from the first <article> or <main> element, or else
+// from <body> without its header and footer. Headings start sections.
+func (l *Loader) Load(ctx context.Context, r io.Reader, source string) (*ports.Document, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML %s: %w", source, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	doc := &ports.Document{
+		Source:   source,
+		MIMEType: documents.MIMETypeHTML,
+		Metadata: map[string]string{},
+	}
+	readHead(root, doc)
+
+	content, isBody := mainContent(root)
+	e := &extractor{root: content, skipPageChrome: isBody}
+	if content != nil {
+		e.walk(content)
+	}
+	e.flush()
+
+	doc.Content = e.builder.Content()
+	doc.Sections = e.builder.Sections()
+	if doc.Title == "" {
+		for _, section := range doc.Sections {
+			if section.Level == 1 {
+				doc.Title = section.Heading
+				break
+			}
+		}
+	}
+
+	l.logger.Debug("HTML loaded",
+		zap.String("source", source),
+		zap.Int("length", len(doc.Content)))
+
+	return doc, nil
+}
+
+// readHead reads the title, language and meta tags of the page
+func readHead(root *html.Node, doc *ports.Document) {
+	if n := find(root, func(n *html.Node) bool { return n.DataAtom == atom.Html }); n != nil {
+		if lang := attr(n, "lang"); lang != "" {
+			doc.Metadata[ports.DocumentMetaLanguage] = lang
+		}
+	}
+	if n := find(root, func(n *html.Node) bool { return n.DataAtom == atom.Title }); n != nil {
+		doc.Title = documents.NormalizeSpace(text(n))
+	}
+
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Meta {
+			value := documents.NormalizeSpace(attr(n, "content"))
+			key := strings.ToLower(attr(n, "name"))
+			if key == "" {
+				key = strings.ToLower(attr(n, "property"))
+			}
+			switch key {
+			case "description", "og:description":
+				setMeta(doc.Metadata, ports.DocumentMetaDescription, value)
+			case "author", "article:author":
+				setMeta(doc.Metadata, ports.DocumentMetaAuthor, value)
+			case "article:published_time", "date":
+				setMeta(doc.Metadata, ports.DocumentMetaCreated, value)
+			case "article:modified_time", "last-modified":
+				setMeta(doc.Metadata, ports.DocumentMetaModified, value)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(root)
+}
+
+// mainContent returns the element holding the page's main content, and
+// whether it fell back to <body>
+func mainContent(root *html.Node) (*html.Node, bool) {
+	for _, match := range []func(*html.Node) bool{
+		func(n *html.Node) bool { return n.DataAtom == atom.Article },
+		func(n *html.Node) bool { return n.DataAtom == atom.Main || attr(n, "role") == "main" },
+	} {
+		if n := find(root, func(n *html.Node) bool { return n.Type == html.ElementNode && match(n) && !skipped(n) }); n != nil {
+			return n, false
+		}
+	}
+	return find(root, func(n *html.Node) bool { return n.DataAtom == atom.Body }), true
+}
+
+// extractor walks the content tree, building paragraphs from inline text
+type extractor struct {
+	builder   documents.Builder
+	paragraph strings.Builder
+	prefix    string
+
+	// root is the content element, always walked even if its class looks
+	// like boilerplate, e.g. <body class="has-sidebar">
+	root           *html.Node
+	skipPageChrome bool
+}
+
+func (e *extractor) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		e.paragraph.WriteString(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			e.walk(c)
+		}
+		return
+	}
+
+	if (n != e.root && skipped(n)) || (e.skipPageChrome && (n.DataAtom == atom.Header || n.DataAtom == atom.Footer)) {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		e.flush()
+		e.builder.Heading(text(n), int(n.Data[1]-'0'))
+		return
+	case atom.Pre:
+		e.flush()
+		e.builder.Preformatted(text(n))
+		return
+	case atom.Td, atom.Th:
+		e.paragraph.WriteByte(' ')
+	case atom.Img:
+		if alt := attr(n, "alt"); alt != "" {
+			e.paragraph.WriteString(" " + alt + " ")
+		}
+		return
+	}
+
+	block := blockElements[n.DataAtom]
+	if block {
+		e.flush()
+	}
+	if n.DataAtom == atom.Li {
+		e.prefix = "- "
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		e.walk(c)
+	}
+	if block {
+		e.flush()
+	}
+}
+
+// flush ends the current paragraph
+func (e *extractor) flush() {
+	text := documents.NormalizeSpace(e.paragraph.String())
+	e.paragraph.Reset()
+	if text == "" {
+		return
+	}
+	e.builder.Paragraph(e.prefix + text)
+	e.prefix = ""
+}
+
+// skipped reports whether an element is boilerplate or hidden
+func skipped(n *html.Node) bool {
+	if skippedElements[n.DataAtom] {
+		return true
+	}
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "hidden":
+			return true
+		case "aria-hidden":
+			if a.Val == "true" {
+				return true
+			}
+		case "role":
+			if a.Val == "navigation" || a.Val == "banner" || a.Val == "contentinfo" || a.Val == "complementary" {
+				return true
+			}
+		case "style":
+			if strings.Contains(strings.ReplaceAll(a.Val, " ", ""), "display:none") {
+				return true
+			}
+		case "class", "id":
+			if boilerplateNames.MatchString(a.Val) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// find returns the first node in document order matching match
+func find(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// text returns the text of a node and its descendants
+func text(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (c.DataAtom == atom.Script || c.DataAtom == atom.Style) {
+			continue
+		}
+		if c.Type == html.ElementNode && c.DataAtom == atom.Br {
+			b.WriteByte('\n')
+			continue
+		}
+		b.WriteString(text(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setMeta(metadata map[string]string, key, value string) {
+	if value != "" {
+		metadata[key] = value
+	}
+}
@@ -0,0 +1,96 @@
+package html
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.DocumentLoader = (*Loader)(nil)
+
+const page = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <title>Release notes | Example</title>
+  <meta name="description" content="What changed in  v2">
+  <meta name="author" content="Jane Doe">
+  <style>body { color: red }</style>
+  <script>trackVisit()</script>
+</head>
+<body>
+  <header><a href="/">Home</a></header>
+  <nav><ul><li>Docs</li><li>Blog</li></ul></nav>
+  <article>
+    <h1>Version 2</h1>
+    <p>This   release adds <b>streaming</b>
+       and fixes bugs.</p>
+    <div class="share-buttons">Share on social</div>
+    <h2>Upgrading</h2>
+    <ul><li>Update the client</li><li>Restart workers</li></ul>
+    <pre>go get example.com/v2
+go mod tidy</pre>
+    <p hidden>Hidden text</p>
+  </article>
+  <aside>Related posts</aside>
+  <footer>Copyright</footer>
+</body>
+</html>`
+
+func TestLoader(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+
+	doc, err := loader.Load(context.Background(), strings.NewReader(page), "https://example.com/notes")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := "Version 2\n\n" +
+		"This release adds streaming and fixes bugs.\n\n" +
+		"Upgrading\n\n" +
+		"- Update the client\n\n" +
+		"- Restart workers\n\n" +
+		"go get example.com/v2\ngo mod tidy"
+	if doc.Content != want {
+		t.Errorf("Content = %q, want %q", doc.Content, want)
+	}
+
+	if doc.Title != "Release notes | Example" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	if doc.Metadata[ports.DocumentMetaDescription] != "What changed in v2" ||
+		doc.Metadata[ports.DocumentMetaAuthor] != "Jane Doe" ||
+		doc.Metadata[ports.DocumentMetaLanguage] != "en" {
+		t.Errorf("Metadata = %v", doc.Metadata)
+	}
+
+	if len(doc.Sections) != 2 {
+		t.Fatalf("Sections = %+v", doc.Sections)
+	}
+	if s := doc.Sections[1]; s.Heading != "Upgrading" || s.Level != 2 || !strings.HasPrefix(doc.Content[s.Offset:], "Upgrading") {
+		t.Errorf("Sections[1] = %+v", s)
+	}
+}
+
+func TestLoaderBody(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	input := `<html><body class="has-sidebar">
+		<header>Site name</header>
+		<div id="sidebar">Links</div>
+		<h1>Welcome</h1><p>Main text<br>next line</p>
+		<footer>Contact</footer>
+	</body></html>`
+
+	doc, err := loader.Load(context.Background(), strings.NewReader(input), "index.html")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if doc.Content != "Welcome\n\nMain text\n\nnext line" {
+		t.Errorf("Content = %q", doc.Content)
+	}
+	if doc.Title != "Welcome" {
+		t.Errorf("Title = %q, want first h1", doc.Title)
+	}
+}
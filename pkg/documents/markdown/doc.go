@@ -0,0 +1,14 @@
+// Package markdown implements ports.DocumentLoader (pkg/ports in this
+// repository) for Markdown and plain text files.
+//
+// The content keeps its Markdown syntax, which chunkers and models read
+// well, with LF line endings and without trailing spaces or repeated blank
+// lines. ATX ("# Title") and setext headings outside fenced code blocks
+// start sections. YAML front matter is removed from the content; its title
+// sets the document title and its other scalar values the metadata.
+//
+// Usage:
+//
+//	loader := markdown.NewLoader(logger)
+//	doc, err := loader.Load(ctx, file, "README.md")
+package markdown
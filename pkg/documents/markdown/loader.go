@@ -0,0 +1,224 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/documents"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// atxHeading matches "# Heading" lines, with optional closing hashes
+	atxHeading = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+
+	// setextUnderline matches the line under a "Heading\n=======" heading
+	setextUnderline = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+
+	// codeFence matches the opening or closing line of a fenced code block
+	codeFence = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+)
+
+// Front matter keys read into the document metadata
+var frontMatterKeys = map[string]string{
+	"author":      ports.DocumentMetaAuthor,
+	"description": ports.DocumentMetaDescription,
+	"summary":     ports.DocumentMetaDescription,
+	"lang":        ports.DocumentMetaLanguage,
+	"language":    ports.DocumentMetaLanguage,
+	"date":        ports.DocumentMetaCreated,
+	"created":     ports.DocumentMetaCreated,
+	"updated":     ports.DocumentMetaModified,
+	"lastmod":     ports.DocumentMetaModified,
+	"modified":    ports.DocumentMetaModified,
+}
+
+// Loader implements the ports.DocumentLoader interface for Markdown and
+// plain text files
+type Loader struct {
+	logger *zap.Logger
+}
+
+// NewLoader creates a new Markdown loader
+func NewLoader(logger *zap.Logger) *Loader {
+	return &Loader{logger: logger}
+}
+
+// MIMETypes returns the Markdown and plain text MIME types
+// (ports.DocumentLoader interface)
+func (l *Loader) MIMETypes() []string {
+	return []string{documents.MIMETypeMarkdown, "text/x-markdown", "text/plain"}
+}
+
+// Load reads a Markdown document (ports.DocumentLoader interface). The
+// content keeps its Markdown syntax, with normalized line endings and blank
+// lines; headings outside code blocks start sections. YAML front matter is
+// read into the title and metadata and removed from the content.
+func (l *Loader) Load(ctx context.Context, r io.Reader, source string) (*ports.Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Markdown: %w", err)
+	}
+
+	doc := &ports.Document{
+		Source:   source,
+		MIMEType: documents.MIMETypeMarkdown,
+		Metadata: map[string]string{},
+	}
+
+	body, frontMatter := splitFrontMatter(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	if frontMatter != "" {
+		if err := readFrontMatter(frontMatter, doc); err != nil {
+			return nil, fmt.Errorf("invalid front matter in %s: %w", source, err)
+		}
+	}
+
+	doc.Content = documents.NormalizeText(body)
+	doc.Sections = headings(doc.Content)
+	if doc.Title == "" {
+		for _, section := range doc.Sections {
+			if section.Level == 1 {
+				doc.Title = section.Heading
+				break
+			}
+		}
+	}
+
+	l.logger.Debug("Markdown loaded",
+		zap.String("source", source),
+		zap.Int("length", len(doc.Content)))
+
+	return doc, nil
+}
+
+// splitFrontMatter separates a leading "---" delimited YAML block
+func splitFrontMatter(text string) (body, frontMatter string) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	if !strings.HasPrefix(text, "---\n") {
+		return text, ""
+	}
+	rest := text[len("---\n"):]
+	for offset := 0; offset < len(rest); {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		if trimmed := strings.TrimRight(line, " \t"); trimmed == "---" || trimmed == "..." {
+			if end < 0 {
+				return "", rest[:offset]
+			}
+			return rest[offset+end+1:], rest[:offset]
+		}
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+	// Unterminated, e.g. a document starting with a horizontal rule
+	return text, ""
+}
+
+// readFrontMatter sets the title and metadata from YAML front matter
+func readFrontMatter(frontMatter string, doc *ports.Document) error {
+	var values map[string]interface{}
+	if err := yaml.Unmarshal([]byte(frontMatter), &values); err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		text := scalar(value)
+		if text == "" {
+			continue
+		}
+		key = strings.ToLower(key)
+		if key == "title" {
+			doc.Title = documents.NormalizeSpace(text)
+			continue
+		}
+		if metaKey, ok := frontMatterKeys[key]; ok {
+			if _, set := doc.Metadata[metaKey]; !set {
+				doc.Metadata[metaKey] = text
+			}
+			continue
+		}
+		doc.Metadata[key] = text
+	}
+	return nil
+}
+
+// scalar formats a front matter value, or returns "" for lists and maps
+func scalar(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case bool, float64:
+		return fmt.Sprint(v)
+	default:
+		return ""
+	}
+}
+
+// headings returns the ATX and setext headings outside fenced code blocks
+func headings(content string) []ports.DocumentSection {
+	var sections []ports.DocumentSection
+	var fence string
+
+	// The paragraph being read, a setext heading if it has one line
+	var paragraph string
+	paragraphLines, paragraphOffset := 0, 0
+
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		lineOffset := offset
+		offset += len(line)
+		line = strings.TrimSuffix(line, "\n")
+
+		if m := codeFence.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+			case strings.HasPrefix(m[1], fence):
+				fence = ""
+			}
+			paragraphLines = 0
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if m := atxHeading.FindStringSubmatch(line); m != nil {
+			if heading := documents.NormalizeSpace(m[2]); heading != "" {
+				sections = append(sections, ports.DocumentSection{Heading: heading, Level: len(m[1]), Offset: lineOffset})
+			}
+			paragraphLines = 0
+			continue
+		}
+
+		if m := setextUnderline.FindStringSubmatch(line); m != nil && paragraphLines == 1 {
+			level := 1
+			if m[1][0] == '-' {
+				level = 2
+			}
+			sections = append(sections, ports.DocumentSection{Heading: documents.NormalizeSpace(paragraph), Level: level, Offset: paragraphOffset})
+			paragraphLines = 0
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			paragraphLines = 0
+			continue
+		}
+		if paragraphLines == 0 {
+			paragraph, paragraphOffset = line, lineOffset
+		}
+		paragraphLines++
+	}
+	return sections
+}
@@ -0,0 +1,95 @@
+package markdown
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.DocumentLoader = (*Loader)(nil)
+
+func TestLoader(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	input := "---\r\n" +
+		"title: Deploy guide\r\n" +
+		"author: Ops\r\n" +
+		"date: 2024-06-01\r\n" +
+		"version: 3\r\n" +
+		"tags: [k8s]\r\n" +
+		"---\r\n" +
+		"# Deploying\r\n" +
+		"\r\n\r\n\r\n" +
+		"Run the chart.   \r\n" +
+		"\r\n" +
+		"```sh\r\n" +
+		"# not a heading\r\n" +
+		"helm install dago\r\n" +
+		"```\r\n" +
+		"\r\n" +
+		"Rollback\r\n" +
+		"--------\r\n" +
+		"\r\n" +
+		"### Notes ###\r\n"
+
+	doc, err := loader.Load(context.Background(), strings.NewReader(input), "docs/deploy.md")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := "# Deploying\n\nRun the chart.\n\n```sh\n# not a heading\nhelm install dago\n```\n\nRollback\n--------\n\n### Notes ###"
+	if doc.Content != want {
+		t.Errorf("Content = %q, want %q", doc.Content, want)
+	}
+	if doc.Title != "Deploy guide" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	if doc.Metadata[ports.DocumentMetaAuthor] != "Ops" ||
+		doc.Metadata[ports.DocumentMetaCreated] != "2024-06-01" ||
+		doc.Metadata["version"] != "3" {
+		t.Errorf("Metadata = %v", doc.Metadata)
+	}
+	if _, ok := doc.Metadata["tags"]; ok {
+		t.Error("list front matter values should be skipped")
+	}
+
+	want2 := []ports.DocumentSection{
+		{Heading: "Deploying", Level: 1, Offset: 0},
+		{Heading: "Rollback", Level: 2, Offset: strings.Index(want, "Rollback")},
+		{Heading: "Notes", Level: 3, Offset: strings.Index(want, "### Notes")},
+	}
+	if len(doc.Sections) != len(want2) {
+		t.Fatalf("Sections = %+v", doc.Sections)
+	}
+	for i := range want2 {
+		if doc.Sections[i] != want2[i] {
+			t.Errorf("Sections[%d] = %+v, want %+v", i, doc.Sections[i], want2[i])
+		}
+	}
+}
+
+func TestLoaderWithoutFrontMatter(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	input := "---\n\nIntro after a rule\n\n# Title\n"
+
+	doc, err := loader.Load(context.Background(), strings.NewReader(input), "notes.md")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if doc.Content != "---\n\nIntro after a rule\n\n# Title" {
+		t.Errorf("Content = %q", doc.Content)
+	}
+	if doc.Title != "Title" {
+		t.Errorf("Title = %q, want first heading", doc.Title)
+	}
+}
+
+func TestLoaderInvalidFrontMatter(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	input := "---\ntitle: [unclosed\n---\nBody\n"
+	if _, err := loader.Load(context.Background(), strings.NewReader(input), "bad.md"); err == nil {
+		t.Error("Load() error = nil, want error")
+	}
+}
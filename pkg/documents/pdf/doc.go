@@ -0,0 +1,13 @@
+// Package pdf implements ports.DocumentLoader (pkg/ports in this
+// repository) for PDF files.
+//
+// Text is extracted from the text layer of each page, one line per text
+// object, and each page starts a section. Title, author, subject and dates
+// are read from the document information dictionary. Scanned pages have no
+// text layer and need OCR.
+//
+// Usage:
+//
+//	loader := pdf.NewLoader(logger)
+//	doc, err := loader.Load(ctx, file, "report.pdf")
+package pdf
@@ -0,0 +1,174 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/documents"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/ledongthuc/pdf"
+	"go.uber.org/zap"
+)
+
+// Loader implements the ports.DocumentLoader interface for PDF files with
+// a text layer
+type Loader struct {
+	logger *zap.Logger
+}
+
+// NewLoader creates a new PDF loader
+func NewLoader(logger *zap.Logger) *Loader {
+	return &Loader{logger: logger}
+}
+
+// MIMETypes returns the PDF MIME type (ports.DocumentLoader interface)
+func (l *Loader) MIMETypes() []string {
+	return []string{documents.MIMETypePDF}
+}
+
+// Load extracts the text of each page, starting a section per page, and the
+// document information dictionary (ports.DocumentLoader interface). Pages
+// whose text can't be extracted are skipped; scanned pages have no text.
+func (l *Loader) Load(ctx context.Context, r io.Reader, source string) (doc *ports.Document, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	// The parser panics on some malformed files
+	defer func() {
+		if p := recover(); p != nil {
+			doc, err = nil, fmt.Errorf("failed to parse PDF %s: %v", source, p)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF %s: %w", source, err)
+	}
+
+	var builder documents.Builder
+	fonts := make(map[string]*pdf.Font)
+	pages := reader.NumPage()
+	for i := 1; i <= pages; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		// Fonts are shared by pages, parse each once
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				font := page.Font(name)
+				fonts[name] = &font
+			}
+		}
+
+		text, err := page.GetPlainText(fonts)
+		if err != nil {
+			l.logger.Warn("failed to extract PDF page text",
+				zap.String("source", source),
+				zap.Int("page", i),
+				zap.Error(err))
+			continue
+		}
+
+		builder.Page(i)
+		builder.Preformatted(normalizeLines(text))
+	}
+
+	doc = &ports.Document{
+		Source:   source,
+		MIMEType: documents.MIMETypePDF,
+		Content:  builder.Content(),
+		Sections: builder.Sections(),
+		Metadata: map[string]string{ports.DocumentMetaPages: strconv.Itoa(pages)},
+	}
+
+	info := reader.Trailer().Key("Info")
+	doc.Title = documents.NormalizeSpace(info.Key("Title").Text())
+	setMeta(doc.Metadata, ports.DocumentMetaAuthor, documents.NormalizeSpace(info.Key("Author").Text()))
+	setMeta(doc.Metadata, ports.DocumentMetaDescription, documents.NormalizeSpace(info.Key("Subject").Text()))
+	setMeta(doc.Metadata, ports.DocumentMetaCreated, parseDate(info.Key("CreationDate").Text()))
+	setMeta(doc.Metadata, ports.DocumentMetaModified, parseDate(info.Key("ModDate").Text()))
+	if language := reader.Trailer().Key("Root").Key("Lang").Text(); language != "" {
+		doc.Metadata[ports.DocumentMetaLanguage] = language
+	}
+
+	l.logger.Debug("PDF loaded",
+		zap.String("source", source),
+		zap.Int("pages", pages),
+		zap.Int("length", len(doc.Content)))
+
+	return doc, nil
+}
+
+// normalizeLines collapses the whitespace within each line of page text
+func normalizeLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = documents.NormalizeSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseDate converts a PDF date, D:YYYYMMDDHHmmSSOHH'mm', to RFC 3339. It
+// returns the date unchanged when it doesn't parse.
+func parseDate(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	s := strings.TrimPrefix(value, "D:")
+	s = strings.ReplaceAll(s, "'", "")
+
+	digits := len(s)
+	for i, c := range s {
+		if c < '0' || c > '9' {
+			digits = i
+			break
+		}
+	}
+	if digits < 4 {
+		return value
+	}
+	// Missing fields default to the start of the period
+	stamp := s[:digits] + "0101000000"[max(digits-4, 0):]
+	if len(stamp) > 14 {
+		stamp = stamp[:14]
+	}
+
+	zone := s[digits:]
+	layout := "20060102150405"
+	switch {
+	case zone == "" || zone == "Z" || strings.HasPrefix(zone, "Z"):
+		zone = "Z"
+		layout += "Z07"
+	case len(zone) == 5:
+		layout += "-0700"
+	case len(zone) == 3:
+		layout += "-07"
+	default:
+		return value
+	}
+
+	t, err := time.Parse(layout, stamp+zone)
+	if err != nil {
+		return value
+	}
+	return t.Format(time.RFC3339)
+}
+
+func setMeta(metadata map[string]string, key, value string) {
+	if value != "" {
+		metadata[key] = value
+	}
+}
@@ -0,0 +1,111 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.DocumentLoader = (*Loader)(nil)
+
+// buildPDF writes a minimal PDF with a page per text and an Info dictionary
+func buildPDF(pages []string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R /Lang (en-GB) >>",
+		"", // page tree, filled in below
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Title (Quarterly  Report) /Author (Ada Lovelace) /CreationDate (D:20240315093000+01'00') >>",
+	}
+
+	var kids []string
+	for _, text := range pages {
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", len(objects)))
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objects)))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestLoader(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	data := buildPDF([]string{"Revenue   grew 12%", "Outlook is stable"})
+
+	doc, err := loader.Load(context.Background(), bytes.NewReader(data), "report.pdf")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if doc.Title != "Quarterly Report" || doc.MIMEType != "application/pdf" || doc.Source != "report.pdf" {
+		t.Errorf("doc = %+v", doc)
+	}
+	if doc.Content != "Revenue grew 12%\n\nOutlook is stable" {
+		t.Errorf("Content = %q", doc.Content)
+	}
+
+	want := map[string]string{
+		ports.DocumentMetaAuthor:   "Ada Lovelace",
+		ports.DocumentMetaCreated:  "2024-03-15T09:30:00+01:00",
+		ports.DocumentMetaLanguage: "en-GB",
+		ports.DocumentMetaPages:    "2",
+	}
+	for key, value := range want {
+		if doc.Metadata[key] != value {
+			t.Errorf("Metadata[%s] = %q, want %q", key, doc.Metadata[key], value)
+		}
+	}
+
+	if len(doc.Sections) != 2 {
+		t.Fatalf("Sections = %+v", doc.Sections)
+	}
+	for i, section := range doc.Sections {
+		if section.Page != i+1 {
+			t.Errorf("Sections[%d].Page = %d", i, section.Page)
+		}
+	}
+	if got := doc.Content[doc.Sections[1].Offset:]; got != "Outlook is stable" {
+		t.Errorf("second page starts at %q", got)
+	}
+}
+
+func TestLoaderInvalid(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+	if _, err := loader.Load(context.Background(), strings.NewReader("not a pdf"), "broken.pdf"); err == nil {
+		t.Error("Load() error = nil, want error")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := map[string]string{
+		"D:20240315093000Z":       "2024-03-15T09:30:00Z",
+		"D:20240315093000-05'00'": "2024-03-15T09:30:00-05:00",
+		"D:2024":                  "2024-01-01T00:00:00Z",
+		"yesterday":               "yesterday",
+		"":                        "",
+	}
+	for in, want := range tests {
+		if got := parseDate(in); got != want {
+			t.Errorf("parseDate(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package documents
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// MIME types of the formats loaded by the subpackages
+const (
+	MIMETypePDF      = "application/pdf"
+	MIMETypeHTML     = "text/html"
+	MIMETypeDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMETypeMarkdown = "text/markdown"
+)
+
+// extensionTypes maps file extensions to MIME types, as the system MIME
+// tables don't always know Markdown and DOCX
+var extensionTypes = map[string]string{
+	".pdf":      MIMETypePDF,
+	".html":     MIMETypeHTML,
+	".htm":      MIMETypeHTML,
+	".xhtml":    MIMETypeHTML,
+	".docx":     MIMETypeDOCX,
+	".md":       MIMETypeMarkdown,
+	".markdown": MIMETypeMarkdown,
+}
+
+// Router implements the ports.DocumentLoader interface by passing each
+// document to the loader of its format
+type Router struct {
+	loaders map[string]ports.DocumentLoader
+	types   []string
+}
+
+// NewRouter creates a router over loaders. A later loader replaces an
+// earlier one for the same MIME type.
+func NewRouter(loaders ...ports.DocumentLoader) *Router {
+	r := &Router{loaders: make(map[string]ports.DocumentLoader)}
+	for _, loader := range loaders {
+		for _, mimeType := range loader.MIMETypes() {
+			if _, ok := r.loaders[mimeType]; !ok {
+				r.types = append(r.types, mimeType)
+			}
+			r.loaders[mimeType] = loader
+		}
+	}
+	return r
+}
+
+// MIMETypes returns the formats of all loaders (ports.DocumentLoader interface)
+func (r *Router) MIMETypes() []string {
+	return append([]string(nil), r.types...)
+}
+
+// Load detects the format from the source's extension, or the content when
+// the extension is unknown, and loads the document with its loader
+// (ports.DocumentLoader interface)
+func (r *Router) Load(ctx context.Context, reader io.Reader, source string) (*ports.Document, error) {
+	buffered := bufio.NewReader(reader)
+
+	mimeType := DetectMIMEType(source, nil)
+	if mimeType == "" {
+		head, err := buffered.Peek(512)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read document: %w", err)
+		}
+		mimeType = DetectMIMEType("", head)
+	}
+
+	loader, ok := r.loaders[mimeType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (%s)", ports.ErrUnsupportedFormat, mimeType, source)
+	}
+	return loader.Load(ctx, buffered, source)
+}
+
+// LoadFile loads the document at path
+func (r *Router) LoadFile(ctx context.Context, path string) (*ports.Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return r.Load(ctx, file, path)
+}
+
+// DetectMIMEType returns the MIME type of a document from the extension of
+// source, a path or URL, or else from the first bytes of its content. It
+// returns "" when neither is known.
+func DetectMIMEType(source string, head []byte) string {
+	name := source
+	if u, err := url.Parse(source); err == nil && u.Scheme != "" && u.Host != "" {
+		name = u.Path
+	}
+	ext := strings.ToLower(path.Ext(name))
+	if mimeType, ok := extensionTypes[ext]; ok {
+		return mimeType
+	}
+	if ext != "" {
+		if mimeType, _, err := mime.ParseMediaType(mime.TypeByExtension(ext)); err == nil {
+			return mimeType
+		}
+	}
+
+	if len(head) == 0 {
+		return ""
+	}
+	if bytes.HasPrefix(head, []byte("%PDF-")) {
+		return MIMETypePDF
+	}
+	// DOCX files are sniffed as application/zip and need an extension
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil || mimeType == "application/octet-stream" {
+		return ""
+	}
+	return mimeType
+}
@@ -0,0 +1,105 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+var _ ports.DocumentLoader = (*Router)(nil)
+
+type fakeLoader struct {
+	types []string
+	body  string
+}
+
+func (f *fakeLoader) Load(ctx context.Context, r io.Reader, source string) (*ports.Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f.body = string(data)
+	return &ports.Document{Source: source, MIMEType: f.types[0], Content: f.body}, nil
+}
+
+func (f *fakeLoader) MIMETypes() []string { return f.types }
+
+func TestDetectMIMEType(t *testing.T) {
+	tests := []struct {
+		source string
+		head   string
+		want   string
+	}{
+		{"report.PDF", "", MIMETypePDF},
+		{"https://example.com/docs/guide.md?raw=1", "", MIMETypeMarkdown},
+		{"onboarding.docx", "PK\x03\x04", MIMETypeDOCX},
+		{"https://example.com/page", "<!DOCTYPE html><html>", MIMETypeHTML},
+		{"upload", "%PDF-1.7\n", MIMETypePDF},
+		{"notes", "just some text", "text/plain"},
+		{"upload", "", ""},
+		{"archive", "PK\x03\x04", "application/zip"},
+	}
+	for _, tt := range tests {
+		if got := DetectMIMEType(tt.source, []byte(tt.head)); got != tt.want {
+			t.Errorf("DetectMIMEType(%q, %q) = %q, want %q", tt.source, tt.head, got, tt.want)
+		}
+	}
+}
+
+func TestRouter(t *testing.T) {
+	pdfLoader := &fakeLoader{types: []string{MIMETypePDF}}
+	htmlLoader := &fakeLoader{types: []string{MIMETypeHTML}}
+	router := NewRouter(pdfLoader, htmlLoader)
+
+	if got := router.MIMETypes(); len(got) != 2 {
+		t.Errorf("MIMETypes() = %v", got)
+	}
+
+	ctx := context.Background()
+
+	doc, err := router.Load(ctx, strings.NewReader("%PDF-1.4 body"), "upload-123")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	// Sniffing must not consume the content
+	if doc.MIMEType != MIMETypePDF || pdfLoader.body != "%PDF-1.4 body" {
+		t.Errorf("sniffed document = %+v, loader read %q", doc, pdfLoader.body)
+	}
+
+	if _, err := router.Load(ctx, strings.NewReader("<html></html>"), "page.html"); err != nil || htmlLoader.body == "" {
+		t.Errorf("Load(page.html) error = %v", err)
+	}
+
+	_, err = router.Load(ctx, strings.NewReader("x"), "notes.docx")
+	if !errors.Is(err, ports.ErrUnsupportedFormat) {
+		t.Errorf("Load(notes.docx) error = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestRouterLoadFile(t *testing.T) {
+	loader := &fakeLoader{types: []string{MIMETypeMarkdown}}
+	router := NewRouter(loader)
+
+	path := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(path, []byte("# Hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := router.LoadFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if doc.Source != path || doc.Content != "# Hello" {
+		t.Errorf("doc = %+v", doc)
+	}
+
+	if _, err := router.LoadFile(context.Background(), filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Error("LoadFile(missing) error = nil, want error")
+	}
+}
@@ -0,0 +1,138 @@
+package documents
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// NormalizeSpace collapses whitespace runs to single spaces, drops control
+// characters and trims the result, for text from a single block
+func NormalizeSpace(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		case unicode.IsControl(r) || r == '\ufffd' || r == '\u200b':
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NormalizeText normalizes multi-line text: LF line endings, no trailing
+// spaces or control characters, and at most one blank line in a row
+func NormalizeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+
+	lines := strings.Split(s, "\n")
+	var b strings.Builder
+	b.Grow(len(s))
+	blank := 0
+	for _, line := range lines {
+		line = strings.TrimRightFunc(strings.Map(dropControl, line), unicode.IsSpace)
+		if line == "" {
+			blank++
+			continue
+		}
+		if b.Len() > 0 {
+			if blank > 0 {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteByte('\n')
+			}
+		}
+		blank = 0
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+func dropControl(r rune) rune {
+	if r == '\t' {
+		return r
+	}
+	if unicode.IsControl(r) || r == '\ufffd' || r == '\u200b' {
+		return -1
+	}
+	return r
+}
+
+// Builder assembles a document's content from blocks, separating them with
+// blank lines and recording sections at their offsets
+type Builder struct {
+	content  strings.Builder
+	sections []ports.DocumentSection
+	page     int
+}
+
+// Paragraph appends a block of text, normalizing its whitespace. Empty
+// blocks are skipped.
+func (b *Builder) Paragraph(text string) {
+	b.write(NormalizeSpace(text))
+}
+
+// Preformatted appends a block keeping its line breaks, e.g. code
+func (b *Builder) Preformatted(text string) {
+	b.write(NormalizeText(text))
+}
+
+// Heading appends a heading and starts a section at it
+func (b *Builder) Heading(text string, level int) {
+	text = NormalizeSpace(text)
+	if text == "" {
+		return
+	}
+	b.sections = append(b.sections, ports.DocumentSection{
+		Heading: text,
+		Level:   level,
+		Page:    b.page,
+		Offset:  b.next(),
+	})
+	b.write(text)
+}
+
+// Page starts a section for page n; the following blocks are on it
+func (b *Builder) Page(n int) {
+	b.page = n
+	b.sections = append(b.sections, ports.DocumentSection{Page: n, Offset: b.next()})
+}
+
+// Content returns the text appended so far
+func (b *Builder) Content() string {
+	return b.content.String()
+}
+
+// Sections returns the sections recorded so far
+func (b *Builder) Sections() []ports.DocumentSection {
+	return b.sections
+}
+
+// next returns the offset the next block will be written at
+func (b *Builder) next() int {
+	if b.content.Len() == 0 {
+		return 0
+	}
+	return b.content.Len() + 2
+}
+
+func (b *Builder) write(text string) {
+	if text == "" {
+		return
+	}
+	if b.content.Len() > 0 {
+		b.content.WriteString("\n\n")
+	}
+	b.content.WriteString(text)
+}
@@ -0,0 +1,57 @@
+package documents
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSpace(t *testing.T) {
+	tests := map[string]string{
+		"  a \t b\n\nc  ":     "a b c",
+		"no\u00a0break":       "no break",
+		"zero\u200bwidth\x00": "zerowidth",
+		"":                    "",
+	}
+	for in, want := range tests {
+		if got := NormalizeSpace(in); got != want {
+			t.Errorf("NormalizeSpace(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	in := "\r\n\r\nfirst  \r\n\tindented\r\n\r\n\r\n\r\nsecond\x07\n\n"
+	want := "first\n\tindented\n\nsecond"
+	if got := NormalizeText(in); got != want {
+		t.Errorf("NormalizeText() = %q, want %q", got, want)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	b.Page(1)
+	b.Heading("  Intro ", 1)
+	b.Paragraph("some   text")
+	b.Paragraph("   ")
+	b.Page(2)
+	b.Preformatted("line 1\r\nline 2")
+
+	content := b.Content()
+	if content != "Intro\n\nsome text\n\nline 1\nline 2" {
+		t.Fatalf("Content() = %q", content)
+	}
+
+	sections := b.Sections()
+	if len(sections) != 3 {
+		t.Fatalf("Sections() = %+v", sections)
+	}
+	if sections[0].Offset != 0 || sections[0].Page != 1 {
+		t.Errorf("page 1 section = %+v", sections[0])
+	}
+	if s := sections[1]; s.Heading != "Intro" || s.Level != 1 || s.Page != 1 || s.Offset != 0 {
+		t.Errorf("heading section = %+v", s)
+	}
+	if s := sections[2]; s.Page != 2 || !strings.HasPrefix(content[s.Offset:], "line 1") {
+		t.Errorf("page 2 section = %+v", s)
+	}
+}
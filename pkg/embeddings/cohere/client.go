@@ -0,0 +1,155 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Cohere API endpoint
+	DefaultBaseURL = "https://api.cohere.com/v2"
+
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = "embed-english-v3.0"
+
+	// Cohere accepts at most 96 texts per request
+	maxBatchSize = 96
+)
+
+// Client implements the ports.Embedder interface for Cohere
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Cohere client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+type embedRequest struct {
+	Texts          []string `json:"texts"`
+	Model          string   `json:"model"`
+	InputType      string   `json:"input_type"`
+	EmbeddingTypes []string `json:"embedding_types"`
+}
+
+type embedResponse struct {
+	Embeddings struct {
+		Float [][]float32 `json:"float"`
+	} `json:"embeddings"`
+	Meta struct {
+		BilledUnits struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+// Embed embeds the texts (ports.Embedder interface)
+func (c *Client) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+
+	resp := &ports.EmbeddingResponse{
+		Embeddings: make([][]float32, 0, len(req.Texts)),
+		Model:      model,
+	}
+
+	for start := 0; start < len(req.Texts); start += maxBatchSize {
+		batch := req.Texts[start:min(start+maxBatchSize, len(req.Texts))]
+
+		result, err := c.embedBatch(ctx, embedRequest{
+			Texts:          batch,
+			Model:          model,
+			InputType:      cohereInputType(req.InputType),
+			EmbeddingTypes: []string{"float"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Embeddings.Float) != len(batch) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(batch), len(result.Embeddings.Float))
+		}
+
+		resp.Embeddings = append(resp.Embeddings, result.Embeddings.Float...)
+		resp.Usage.PromptTokens += result.Meta.BilledUnits.InputTokens
+		resp.Usage.TotalTokens += result.Meta.BilledUnits.InputTokens
+	}
+
+	c.logger.Debug("texts embedded",
+		zap.String("model", resp.Model),
+		zap.Int("text_count", len(req.Texts)),
+		zap.Int("tokens", resp.Usage.TotalTokens))
+
+	return resp, nil
+}
+
+// cohereInputType maps the input type onto Cohere's. v3 models require one,
+// so unspecified input is embedded as documents.
+func cohereInputType(inputType ports.EmbeddingInputType) string {
+	if inputType == ports.EmbeddingInputQuery {
+		return "search_query"
+	}
+	return "search_document"
+}
+
+func (c *Client) embedBatch(ctx context.Context, body embedRequest) (*embedResponse, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/embed", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API call failed: %w", &ports.StatusError{StatusCode: httpResp.StatusCode, Message: strings.TrimSpace(string(respBody))})
+	}
+
+	var result embedResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
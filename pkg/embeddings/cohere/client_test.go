@@ -0,0 +1,103 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.baseURL != DefaultBaseURL {
+		t.Errorf("NewClient() baseURL = %s, want %s", client.baseURL, DefaultBaseURL)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	var requests []embedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embed" || r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		var req embedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+
+		vectors := make([][]float32, len(req.Texts))
+		for i, text := range req.Texts {
+			vectors[i] = []float32{float32(len(text))}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"embeddings": map[string]interface{}{"float": vectors},
+			"meta":       map[string]interface{}{"billed_units": map[string]int{"input_tokens": len(req.Texts)}},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("test-key", srv.URL, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// More texts than fit in one request
+	texts := make([]string, maxBatchSize+4)
+	for i := range texts {
+		texts[i] = string(make([]byte, i%7))
+	}
+
+	resp, err := client.Embed(context.Background(), ports.EmbeddingRequest{Texts: texts})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(requests))
+	}
+	for _, req := range requests {
+		if req.InputType != "search_document" || req.Model != DefaultModel {
+			t.Errorf("request input_type = %s, model = %s, want search_document with %s", req.InputType, req.Model, DefaultModel)
+		}
+	}
+
+	if len(resp.Embeddings) != len(texts) {
+		t.Fatalf("Embed() returned %d embeddings, want %d", len(resp.Embeddings), len(texts))
+	}
+	for i, vector := range resp.Embeddings {
+		if vector[0] != float32(i%7) {
+			t.Fatalf("Embeddings[%d] = %v, want [%d]", i, vector, i%7)
+		}
+	}
+	if resp.Usage.TotalTokens != len(texts) {
+		t.Errorf("Usage.TotalTokens = %d, want %d", resp.Usage.TotalTokens, len(texts))
+	}
+}
+
+func TestCohereInputType(t *testing.T) {
+	tests := map[ports.EmbeddingInputType]string{
+		ports.EmbeddingInputNone:     "search_document",
+		ports.EmbeddingInputDocument: "search_document",
+		ports.EmbeddingInputQuery:    "search_query",
+	}
+	for inputType, want := range tests {
+		if got := cohereInputType(inputType); got != want {
+			t.Errorf("cohereInputType(%q) = %s, want %s", inputType, got, want)
+		}
+	}
+}
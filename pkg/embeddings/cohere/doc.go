@@ -0,0 +1,28 @@
+// Package cohere implements the embeddings adapter for Cohere.
+//
+// This adapter implements the ports.Embedder interface using Cohere's
+// v2 embed API with float embeddings.
+//
+// Supported models (examples):
+//   - embed-english-v3.0 (default)
+//   - embed-multilingual-v3.0
+//   - embed-english-light-v3.0
+//   - embed-multilingual-light-v3.0
+//
+// v3 models require an input type: ports.EmbeddingInputQuery is sent as
+// search_query, anything else as search_document.
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/embeddings/cohere"
+//
+//	client, err := cohere.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Embed(ctx, ports.EmbeddingRequest{
+//		Texts:     []string{"Which workers have a GPU?"},
+//		InputType: ports.EmbeddingInputQuery,
+//	})
+package cohere
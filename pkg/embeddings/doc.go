@@ -0,0 +1,34 @@
+// Package embeddings provides text embedding adapters.
+//
+// This package contains implementations of the ports.Embedder interface for
+// OpenAI, Gemini, Ollama, Cohere and Voyage AI. dago-libs doesn't define an
+// embedding port yet, so the interface lives in pkg/ports of this repository.
+//
+// All adapters implement the same interface, making them interchangeable, and
+// NewClient selects one by provider name, like the LLM factory. Middleware
+// (WithRetry, WithMetrics, WithCache, WithSharedCache, WithTimeout) wraps
+// any of them, as the LLM factory's (llm.WithRetry, llm.WithMetrics,
+// llm.WithCache) wraps LLM clients.
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/embeddings"
+//
+//	// Create a client using the factory
+//	embedder, err := embeddings.NewClient(&embeddings.Config{
+//		Provider: "voyage",
+//		APIKey:   "your-api-key",
+//		Logger:   logger,
+//		Middleware: []embeddings.Middleware{
+//			embeddings.WithMetrics("voyage"),
+//			embeddings.WithRetry(3, time.Second),
+//			embeddings.WithCache(10000),
+//		},
+//	})
+//
+//	// Use the client
+//	resp, err := embedder.Embed(ctx, ports.EmbeddingRequest{
+//		Texts:     []string{"What is DAGO?"},
+//		InputType: ports.EmbeddingInputQuery,
+//	})
+package embeddings
@@ -0,0 +1,98 @@
+package embeddings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/embeddings/cohere"
+	"github.com/aescanero/dago-adapters/pkg/embeddings/gemini"
+	"github.com/aescanero/dago-adapters/pkg/embeddings/ollama"
+	"github.com/aescanero/dago-adapters/pkg/embeddings/openai"
+	"github.com/aescanero/dago-adapters/pkg/embeddings/voyage"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Config holds embeddings client configuration
+type Config struct {
+	Provider string
+	APIKey   string
+	BaseURL  string // For Ollama and OpenAI-compatible endpoints
+	Timeout  int    // Timeout in seconds
+	Logger   *zap.Logger
+
+	// Middleware wraps the client, first entry outermost (e.g. WithMetrics,
+	// WithRetry, WithCache)
+	Middleware []Middleware
+}
+
+// NewClient creates a new embeddings client based on provider
+func NewClient(cfg *Config) (ports.Embedder, error) {
+	if cfg.Logger == nil {
+		cfg.Logger = zap.NewNop()
+	}
+
+	var embedder ports.Embedder
+	var err error
+
+	switch cfg.Provider {
+	case "openai", "gpt":
+		embedder, err = openai.NewClient(cfg.APIKey, cfg.BaseURL, cfg.Logger)
+
+	case "gemini", "google":
+		embedder, err = gemini.NewClient(cfg.APIKey, cfg.Logger)
+
+	case "ollama", "local":
+		endpoint := cfg.BaseURL
+		if endpoint == "" {
+			endpoint = "http://localhost:11434"
+		}
+		embedder, err = ollama.NewClient(endpoint, cfg.Logger)
+
+	case "cohere":
+		embedder, err = cohere.NewClient(cfg.APIKey, cfg.BaseURL, cfg.Logger)
+
+	case "voyage", "voyageai":
+		embedder, err = voyage.NewClient(cfg.APIKey, cfg.BaseURL, cfg.Logger)
+
+	default:
+		return nil, fmt.Errorf("unsupported embeddings provider: %s (supported: openai, gemini, ollama, cohere, voyage)", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Timeout > 0 {
+		embedder = WithTimeout(time.Duration(cfg.Timeout) * time.Second)(embedder)
+	}
+	return Chain(embedder, cfg.Middleware...), nil
+}
+
+// GetDefaultModel returns the default embedding model for a provider
+func GetDefaultModel(provider string) string {
+	switch provider {
+	case "openai", "gpt":
+		return openai.DefaultModel
+	case "gemini", "google":
+		return gemini.DefaultModel
+	case "ollama", "local":
+		return ollama.DefaultModel
+	case "cohere":
+		return cohere.DefaultModel
+	case "voyage", "voyageai":
+		return voyage.DefaultModel
+	default:
+		return ""
+	}
+}
+
+// ListSupportedProviders returns a list of supported embeddings providers
+func ListSupportedProviders() []string {
+	return []string{
+		"openai",
+		"gemini",
+		"ollama",
+		"cohere",
+		"voyage",
+	}
+}
@@ -0,0 +1,77 @@
+package embeddings
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name     string
+		provider string
+		apiKey   string
+		wantErr  bool
+	}{
+		{name: "openai with api key", provider: "openai", apiKey: "test-key"},
+		{name: "openai without api key", provider: "openai", wantErr: true},
+		{name: "gemini with api key", provider: "gemini", apiKey: "test-key"},
+		{name: "ollama without api key", provider: "ollama"},
+		{name: "cohere with api key", provider: "cohere", apiKey: "test-key"},
+		{name: "voyage with api key", provider: "voyage", apiKey: "test-key"},
+		{name: "voyage without api key", provider: "voyage", wantErr: true},
+		{name: "unsupported provider", provider: "unsupported", apiKey: "test-key", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(&Config{
+				Provider:   tt.provider,
+				APIKey:     tt.apiKey,
+				Timeout:    30,
+				Logger:     logger,
+				Middleware: []Middleware{WithRetry(3, 0), WithCache(10)},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && client == nil {
+				t.Error("NewClient() returned nil client without error")
+			}
+		})
+	}
+}
+
+func TestGetDefaultModel(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     string
+	}{
+		{"openai", "text-embedding-3-small"},
+		{"gemini", "text-embedding-004"},
+		{"ollama", "nomic-embed-text"},
+		{"cohere", "embed-english-v3.0"},
+		{"voyage", "voyage-3"},
+		{"unknown", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			if got := GetDefaultModel(tt.provider); got != tt.want {
+				t.Errorf("GetDefaultModel(%s) = %s, want %s", tt.provider, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListSupportedProviders(t *testing.T) {
+	for _, provider := range ListSupportedProviders() {
+		if GetDefaultModel(provider) == "" {
+			t.Errorf("provider %s has no default model", provider)
+		}
+	}
+}
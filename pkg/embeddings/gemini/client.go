@@ -0,0 +1,104 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/google/generative-ai-go/genai"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+)
+
+const (
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = "text-embedding-004"
+
+	// Gemini accepts at most 100 contents per batch
+	maxBatchSize = 100
+)
+
+// Client implements the ports.Embedder interface for Google Gemini embedding models
+type Client struct {
+	client *genai.Client
+	logger *zap.Logger
+}
+
+// NewClient creates a new Gemini embeddings client
+func NewClient(apiKey string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	return &Client{
+		client: client,
+		logger: logger,
+	}, nil
+}
+
+// Close closes the Gemini client
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Embed embeds the texts (ports.Embedder interface). Gemini doesn't report
+// token usage for embeddings, so Usage is left empty.
+func (c *Client) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	modelName := req.Model
+	if modelName == "" {
+		modelName = DefaultModel
+	}
+
+	model := c.client.EmbeddingModel(modelName)
+	model.TaskType = taskType(req.InputType)
+
+	resp := &ports.EmbeddingResponse{
+		Embeddings: make([][]float32, 0, len(req.Texts)),
+		Model:      modelName,
+	}
+
+	for start := 0; start < len(req.Texts); start += maxBatchSize {
+		batch := model.NewBatch()
+		end := min(start+maxBatchSize, len(req.Texts))
+		for _, text := range req.Texts[start:end] {
+			batch.AddContent(genai.Text(text))
+		}
+
+		result, err := model.BatchEmbedContents(ctx, batch)
+		if err != nil {
+			c.logger.Error("API call failed", zap.Error(err))
+			return nil, fmt.Errorf("API call failed: %w", err)
+		}
+		if len(result.Embeddings) != end-start {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", end-start, len(result.Embeddings))
+		}
+
+		for _, embedding := range result.Embeddings {
+			resp.Embeddings = append(resp.Embeddings, embedding.Values)
+		}
+	}
+
+	c.logger.Debug("texts embedded",
+		zap.String("model", resp.Model),
+		zap.Int("text_count", len(req.Texts)))
+
+	return resp, nil
+}
+
+// taskType maps the input type onto Gemini's retrieval task types
+func taskType(inputType ports.EmbeddingInputType) genai.TaskType {
+	switch inputType {
+	case ports.EmbeddingInputQuery:
+		return genai.TaskTypeRetrievalQuery
+	case ports.EmbeddingInputDocument:
+		return genai.TaskTypeRetrievalDocument
+	default:
+		return genai.TaskTypeUnspecified
+	}
+}
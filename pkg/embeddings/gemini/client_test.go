@@ -0,0 +1,34 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/google/generative-ai-go/genai"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	_ = client.Close()
+}
+
+func TestTaskType(t *testing.T) {
+	tests := map[ports.EmbeddingInputType]genai.TaskType{
+		ports.EmbeddingInputNone:     genai.TaskTypeUnspecified,
+		ports.EmbeddingInputDocument: genai.TaskTypeRetrievalDocument,
+		ports.EmbeddingInputQuery:    genai.TaskTypeRetrievalQuery,
+	}
+	for inputType, want := range tests {
+		if got := taskType(inputType); got != want {
+			t.Errorf("taskType(%q) = %v, want %v", inputType, got, want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Package gemini implements the embeddings adapter for Google Gemini.
+//
+// This adapter implements the ports.Embedder interface (pkg/ports in this
+// repository), mapping input types onto Gemini's retrieval query and
+// retrieval document task types.
+//
+// Supported models:
+//   - text-embedding-004 (default)
+//   - embedding-001
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/embeddings/gemini"
+//
+//	client, err := gemini.NewClient(apiKey, logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer client.Close()
+//
+//	resp, err := client.Embed(ctx, ports.EmbeddingRequest{
+//		Texts:     []string{"Which workers have a GPU?"},
+//		InputType: ports.EmbeddingInputQuery,
+//	})
+package gemini
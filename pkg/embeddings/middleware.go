@@ -0,0 +1,206 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/cache"
+	"github.com/aescanero/dago-adapters/pkg/cache/lru"
+	"github.com/aescanero/dago-adapters/pkg/internal/retry"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "github.com/aescanero/dago-adapters/pkg/embeddings"
+
+// Middleware wraps an Embedder with cross-cutting behavior
+type Middleware func(ports.Embedder) ports.Embedder
+
+// EmbedderFunc adapts a function to the Embedder interface
+type EmbedderFunc func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error)
+
+// Embed calls f(ctx, req)
+func (f EmbedderFunc) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	return f(ctx, req)
+}
+
+// Chain wraps embedder with the middleware, the first one outermost
+func Chain(embedder ports.Embedder, middleware ...Middleware) ports.Embedder {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		embedder = middleware[i](embedder)
+	}
+	return embedder
+}
+
+// WithTimeout bounds every Embed call
+func WithTimeout(timeout time.Duration) Middleware {
+	return func(next ports.Embedder) ports.Embedder {
+		return EmbedderFunc(func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next.Embed(ctx, req)
+		})
+	}
+}
+
+// WithRetry retries calls that failed transiently (rate limits, server
+// errors and network failures) up to maxAttempts in total, doubling the wait
+// from backoff between attempts. Other errors, e.g. ports.ErrInvalidRequest
+// or an authentication failure, are returned at once. Calls rejected with a
+// *ports.RateLimitError are retried after its RetryAfter instead, when the
+// provider said how long to wait, or returned at once when that is over a
+// minute. It gives up early when ctx ends.
+func WithRetry(maxAttempts int, backoff time.Duration) Middleware {
+	return func(next ports.Embedder) ports.Embedder {
+		return EmbedderFunc(func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+			wait := backoff
+
+			for attempt := 1; ; attempt++ {
+				resp, err := next.Embed(ctx, req)
+				if err == nil || !retry.Retryable(err) || attempt >= maxAttempts || ctx.Err() != nil {
+					return resp, err
+				}
+
+				delay, ok := retry.Delay(err, wait)
+				if !ok {
+					return nil, err
+				}
+
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, err
+				case <-timer.C:
+				}
+				wait *= 2
+			}
+		})
+	}
+}
+
+// WithMetrics reports calls through the global OpenTelemetry MeterProvider:
+// dago.embeddings.requests (by provider and result), .duration, .texts and
+// .tokens.
+func WithMetrics(provider string) Middleware {
+	meter := otel.Meter(instrumentationName)
+
+	// Instruments are still usable (no-op) when creation fails
+	requests, _ := meter.Int64Counter("dago.embeddings.requests",
+		metric.WithDescription("Embedding requests"))
+	duration, _ := meter.Float64Histogram("dago.embeddings.duration",
+		metric.WithDescription("Duration of embedding requests"),
+		metric.WithUnit("s"))
+	texts, _ := meter.Int64Counter("dago.embeddings.texts",
+		metric.WithDescription("Texts embedded"))
+	tokens, _ := meter.Int64Counter("dago.embeddings.tokens",
+		metric.WithDescription("Input tokens billed for embeddings"))
+
+	return func(next ports.Embedder) ports.Embedder {
+		return EmbedderFunc(func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+			start := time.Now()
+			resp, err := next.Embed(ctx, req)
+
+			result := "ok"
+			if err != nil {
+				result = "error"
+			}
+			attrs := metric.WithAttributes(
+				attribute.String("provider", provider),
+				attribute.String("result", result))
+
+			requests.Add(ctx, 1, attrs)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+			if err == nil {
+				providerAttr := metric.WithAttributes(attribute.String("provider", provider))
+				texts.Add(ctx, int64(len(req.Texts)), providerAttr)
+				tokens.Add(ctx, int64(resp.Usage.TotalTokens), providerAttr)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// WithCache keeps the embeddings of up to size texts in memory, least
+// recently used first out, and only sends the texts it misses. Entries are
+// keyed by model, input type and text. Cached texts report no token usage.
+func WithCache(size int) Middleware {
+	return WithSharedCache(lru.NewCache(size), 0)
+}
+
+// WithSharedCache is WithCache on any ports.Cache, e.g. Redis to share
+// embeddings between replicas. A positive ttl expires them after that long.
+// Cache errors count as misses.
+func WithSharedCache(c ports.Cache, ttl time.Duration) Middleware {
+	return func(next ports.Embedder) ports.Embedder {
+		return EmbedderFunc(func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+			resp := &ports.EmbeddingResponse{
+				Embeddings: make([][]float32, len(req.Texts)),
+				Model:      req.Model,
+			}
+
+			keys := make([]string, len(req.Texts))
+			var missing []string
+			var missingIdx []int
+			for i, text := range req.Texts {
+				key, err := cache.Key("embedding:", []string{req.Model, string(req.InputType), text})
+				if err != nil {
+					return nil, err
+				}
+				keys[i] = key
+				if data, err := c.Get(ctx, key); err == nil {
+					resp.Embeddings[i] = decodeVector(data)
+					continue
+				}
+				missing = append(missing, text)
+				missingIdx = append(missingIdx, i)
+			}
+
+			if len(missing) == 0 {
+				return resp, nil
+			}
+
+			missReq := req
+			missReq.Texts = missing
+			fetched, err := next.Embed(ctx, missReq)
+			if err != nil {
+				return nil, err
+			}
+			if len(fetched.Embeddings) != len(missing) {
+				return nil, fmt.Errorf("embedder returned %d embeddings for %d texts", len(fetched.Embeddings), len(missing))
+			}
+
+			for j, i := range missingIdx {
+				resp.Embeddings[i] = fetched.Embeddings[j]
+				_ = c.Set(ctx, keys[i], encodeVector(fetched.Embeddings[j]), ttl)
+			}
+			resp.Model = fetched.Model
+			resp.Usage = fetched.Usage
+
+			return resp, nil
+		})
+	}
+}
+
+// encodeVector encodes a vector as little-endian float32s
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector
+}
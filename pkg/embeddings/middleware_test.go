@@ -0,0 +1,154 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// lengthEmbedder embeds each text as its length and records the calls. Its
+// first calls fail, with err or else a 503.
+type lengthEmbedder struct {
+	calls [][]string
+	fail  int
+	err   error
+}
+
+func (e *lengthEmbedder) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	e.calls = append(e.calls, req.Texts)
+	if e.fail > 0 {
+		e.fail--
+		if e.err != nil {
+			return nil, e.err
+		}
+		return nil, &ports.StatusError{StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+	}
+
+	resp := &ports.EmbeddingResponse{Model: "length"}
+	for _, text := range req.Texts {
+		resp.Embeddings = append(resp.Embeddings, []float32{float32(len(text))})
+	}
+	return resp, nil
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	req := ports.EmbeddingRequest{Texts: []string{"a"}}
+
+	inner := &lengthEmbedder{fail: 2}
+	if _, err := WithRetry(3, 0)(inner).Embed(ctx, req); err != nil {
+		t.Errorf("Embed() error = %v, want success on third attempt", err)
+	}
+
+	inner = &lengthEmbedder{fail: 3}
+	if _, err := WithRetry(3, 0)(inner).Embed(ctx, req); err == nil {
+		t.Error("Embed() expected error after exhausting attempts")
+	}
+	if len(inner.calls) != 3 {
+		t.Errorf("made %d attempts, want 3", len(inner.calls))
+	}
+}
+
+func TestWithRetry_NotRetryable(t *testing.T) {
+	for _, err := range []error{
+		fmt.Errorf("API call failed: %w", &ports.StatusError{StatusCode: http.StatusBadRequest, Message: "input too long"}),
+		fmt.Errorf("API call failed: %w", &ports.StatusError{StatusCode: http.StatusForbidden, Message: "forbidden"}),
+		errors.New("unexpected response"),
+	} {
+		inner := &lengthEmbedder{fail: 3, err: err}
+		if _, got := WithRetry(3, time.Hour)(inner).Embed(context.Background(), ports.EmbeddingRequest{Texts: []string{"a"}}); !errors.Is(got, err) {
+			t.Errorf("Embed() error = %v, want %v", got, err)
+		}
+		if len(inner.calls) != 1 {
+			t.Errorf("made %d attempts for %v, want 1", len(inner.calls), err)
+		}
+	}
+}
+
+func TestWithRetry_RetryAfter(t *testing.T) {
+	calls := 0
+	limited := EmbedderFunc(func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+		calls++
+		if calls == 1 {
+			return nil, fmt.Errorf("embed: %w", &ports.RateLimitError{RetryAfter: time.Millisecond, Message: "slow down"})
+		}
+		return &ports.EmbeddingResponse{Embeddings: [][]float32{{1}}}, nil
+	})
+
+	// The backoff would outlast the test; the RetryAfter is waited instead
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := WithRetry(2, time.Hour)(limited).Embed(ctx, ports.EmbeddingRequest{Texts: []string{"a"}}); err != nil {
+		t.Errorf("Embed() error = %v, want success after RetryAfter", err)
+	}
+}
+
+func TestWithCache(t *testing.T) {
+	ctx := context.Background()
+	inner := &lengthEmbedder{}
+	embedder := WithCache(2)(inner)
+
+	embed := func(texts ...string) []float32 {
+		t.Helper()
+		resp, err := embedder.Embed(ctx, ports.EmbeddingRequest{Texts: texts})
+		if err != nil {
+			t.Fatalf("Embed() error = %v", err)
+		}
+		var got []float32
+		for _, vector := range resp.Embeddings {
+			got = append(got, vector[0])
+		}
+		return got
+	}
+
+	embed("a", "bb")
+	if got := embed("bb", "ccc", "a"); !slices.Equal(got, []float32{2, 3, 1}) {
+		t.Errorf("Embed() = %v, want [2 3 1]", got)
+	}
+
+	// Only the miss was sent; "bb" was then evicted as least recently used
+	if !slices.Equal(inner.calls[1], []string{"ccc"}) {
+		t.Errorf("second call sent %v, want [ccc]", inner.calls[1])
+	}
+	embed("bb")
+	if len(inner.calls) != 3 {
+		t.Errorf("made %d calls, want evicted text fetched again", len(inner.calls))
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next ports.Embedder) ports.Embedder {
+			return EmbedderFunc(func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+				order = append(order, name)
+				return next.Embed(ctx, req)
+			})
+		}
+	}
+
+	embedder := Chain(&lengthEmbedder{}, trace("outer"), trace("inner"))
+	if _, err := embedder.Embed(context.Background(), ports.EmbeddingRequest{}); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if !slices.Equal(order, []string{"outer", "inner"}) {
+		t.Errorf("middleware ran in order %v, want [outer inner]", order)
+	}
+}
+
+func TestWithCache_ShortReply(t *testing.T) {
+	short := EmbedderFunc(func(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+		return &ports.EmbeddingResponse{Embeddings: [][]float32{{1}}}, nil
+	})
+
+	_, err := WithCache(10)(short).Embed(context.Background(), ports.EmbeddingRequest{Texts: []string{"a", "b"}})
+	if err == nil {
+		t.Error("Embed() expected error for fewer embeddings than texts")
+	}
+}
@@ -0,0 +1,75 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/ollama/ollama/api"
+	"go.uber.org/zap"
+)
+
+// DefaultModel is used when the request doesn't name one
+const DefaultModel = "nomic-embed-text"
+
+// Client implements the ports.Embedder interface for Ollama local models
+type Client struct {
+	client *api.Client
+	logger *zap.Logger
+}
+
+// NewClient creates a new Ollama embeddings client
+// endpoint is the Ollama server URL (e.g., "http://localhost:11434")
+func NewClient(endpoint string, logger *zap.Logger) (*Client, error) {
+	if endpoint == "" {
+		endpoint = "http://localhost:11434"
+	}
+
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Ollama endpoint: %w", err)
+	}
+
+	return &Client{
+		client: api.NewClient(base, http.DefaultClient),
+		logger: logger,
+	}, nil
+}
+
+// Embed embeds the texts (ports.Embedder interface). The input type is
+// ignored; models that expect task prefixes (e.g. "search_query: ") need
+// them in the texts.
+func (c *Client) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+
+	result, err := c.client.Embed(ctx, &api.EmbedRequest{
+		Model: model,
+		Input: req.Texts,
+	})
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	if len(result.Embeddings) != len(req.Texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(req.Texts), len(result.Embeddings))
+	}
+
+	resp := &ports.EmbeddingResponse{
+		Embeddings: result.Embeddings,
+		Model:      model,
+	}
+	resp.Usage.PromptTokens = result.PromptEvalCount
+	resp.Usage.TotalTokens = result.PromptEvalCount
+
+	c.logger.Debug("texts embedded",
+		zap.String("model", resp.Model),
+		zap.Int("text_count", len(req.Texts)),
+		zap.Int("tokens", resp.Usage.TotalTokens))
+
+	return resp, nil
+}
@@ -0,0 +1,65 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("://bad", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for invalid endpoint")
+	}
+	if client, err := NewClient("", zap.NewNop()); err != nil || client == nil {
+		t.Errorf("NewClient() = %v, %v, want client for default endpoint", client, err)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if r.URL.Path != "/api/embed" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		embeddings := make([][]float32, len(req.Input))
+		for i, text := range req.Input {
+			embeddings[i] = []float32{float32(len(text))}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":             req.Model,
+			"embeddings":        embeddings,
+			"prompt_eval_count": 4,
+		})
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	resp, err := client.Embed(context.Background(), ports.EmbeddingRequest{Texts: []string{"a", "bbb"}})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if resp.Model != DefaultModel || len(resp.Embeddings) != 2 || resp.Embeddings[1][0] != 3 {
+		t.Errorf("Embed() = %+v, want two %s embeddings", resp, DefaultModel)
+	}
+	if resp.Usage.TotalTokens != 4 {
+		t.Errorf("Usage.TotalTokens = %d, want 4", resp.Usage.TotalTokens)
+	}
+}
@@ -0,0 +1,27 @@
+// Package ollama implements the embeddings adapter for Ollama local models.
+//
+// This adapter implements the ports.Embedder interface (pkg/ports in this
+// repository) using Ollama's /api/embed endpoint.
+//
+// Supported models (examples):
+//   - nomic-embed-text (default)
+//   - mxbai-embed-large
+//   - all-minilm
+//   - Any embedding model available in your local Ollama installation
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/embeddings/ollama"
+//
+//	client, err := ollama.NewClient("http://localhost:11434", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Embed(ctx, ports.EmbeddingRequest{
+//		Texts: []string{"DAGO runs agent graphs on workers."},
+//	})
+//
+// Note: Ollama must be running locally or accessible at the specified endpoint.
+// The default endpoint is http://localhost:11434
+package ollama
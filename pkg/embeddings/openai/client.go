@@ -0,0 +1,93 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	openai "github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = string(openai.SmallEmbedding3)
+
+	// OpenAI accepts at most 2048 inputs per request
+	maxBatchSize = 2048
+)
+
+// Client implements the ports.Embedder interface for OpenAI embedding models
+type Client struct {
+	client *openai.Client
+	logger *zap.Logger
+}
+
+// NewClient creates a new OpenAI embeddings client
+// baseURL is optional and defaults to OpenAI's official API endpoint
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	config := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		// Use custom base URL for OpenAI-compatible endpoints
+		config.BaseURL = baseURL
+	}
+
+	return &Client{
+		client: openai.NewClientWithConfig(config),
+		logger: logger,
+	}, nil
+}
+
+// Embed embeds the texts (ports.Embedder interface). OpenAI models have no
+// input type; it is ignored.
+func (c *Client) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+
+	resp := &ports.EmbeddingResponse{
+		Embeddings: make([][]float32, 0, len(req.Texts)),
+		Model:      model,
+	}
+
+	for start := 0; start < len(req.Texts); start += maxBatchSize {
+		batch := req.Texts[start:min(start+maxBatchSize, len(req.Texts))]
+
+		result, err := c.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+			Input:          batch,
+			Model:          openai.EmbeddingModel(model),
+			EncodingFormat: openai.EmbeddingEncodingFormatFloat,
+		})
+		if err != nil {
+			c.logger.Error("API call failed", zap.Error(err))
+			return nil, fmt.Errorf("API call failed: %w", err)
+		}
+		if len(result.Data) != len(batch) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(batch), len(result.Data))
+		}
+
+		vectors := make([][]float32, len(batch))
+		for _, d := range result.Data {
+			if d.Index < 0 || d.Index >= len(batch) {
+				return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+			}
+			vectors[d.Index] = d.Embedding
+		}
+
+		resp.Embeddings = append(resp.Embeddings, vectors...)
+		resp.Usage.PromptTokens += result.Usage.PromptTokens
+		resp.Usage.TotalTokens += result.Usage.TotalTokens
+	}
+
+	c.logger.Debug("texts embedded",
+		zap.String("model", resp.Model),
+		zap.Int("text_count", len(req.Texts)),
+		zap.Int("tokens", resp.Usage.TotalTokens))
+
+	return resp, nil
+}
@@ -0,0 +1,62 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+	if client, err := NewClient("test-key", "", zap.NewNop()); err != nil || client == nil {
+		t.Errorf("NewClient() = %v, %v, want client", client, err)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+			Model string   `json:"model"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != DefaultModel {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		data := make([]map[string]interface{}, len(req.Input))
+		for i, text := range req.Input {
+			data[i] = map[string]interface{}{"object": "embedding", "index": i, "embedding": []float32{float32(len(text))}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "list",
+			"data":   data,
+			"model":  req.Model,
+			"usage":  map[string]int{"prompt_tokens": 3, "total_tokens": 3},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("test-key", srv.URL, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	resp, err := client.Embed(context.Background(), ports.EmbeddingRequest{Texts: []string{"a", "bb"}})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1][0] != 2 {
+		t.Errorf("Embed() embeddings = %v, want [[1] [2]]", resp.Embeddings)
+	}
+	if resp.Usage.TotalTokens != 3 {
+		t.Errorf("Usage.TotalTokens = %d, want 3", resp.Usage.TotalTokens)
+	}
+}
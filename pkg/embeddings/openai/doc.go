@@ -0,0 +1,23 @@
+// Package openai implements the embeddings adapter for OpenAI.
+//
+// This adapter implements the ports.Embedder interface (pkg/ports in this
+// repository) and works with OpenAI-compatible endpoints through baseURL.
+//
+// Supported models:
+//   - text-embedding-3-small (default)
+//   - text-embedding-3-large
+//   - text-embedding-ada-002
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/embeddings/openai"
+//
+//	client, err := openai.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Embed(ctx, ports.EmbeddingRequest{
+//		Texts: []string{"DAGO runs agent graphs on workers."},
+//	})
+package openai
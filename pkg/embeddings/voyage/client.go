@@ -0,0 +1,155 @@
+package voyage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Voyage AI API endpoint
+	DefaultBaseURL = "https://api.voyageai.com/v1"
+
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = "voyage-3"
+
+	// Voyage accepts at most 1000 texts per request
+	maxBatchSize = 1000
+)
+
+// Client implements the ports.Embedder interface for Voyage AI
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Voyage AI client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+type embedRequest struct {
+	Input     []string `json:"input"`
+	Model     string   `json:"model"`
+	InputType string   `json:"input_type,omitempty"`
+}
+
+type embedResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+	Model string `json:"model"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Embed embeds the texts (ports.Embedder interface)
+func (c *Client) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+
+	resp := &ports.EmbeddingResponse{
+		Embeddings: make([][]float32, 0, len(req.Texts)),
+		Model:      model,
+	}
+
+	for start := 0; start < len(req.Texts); start += maxBatchSize {
+		batch := req.Texts[start:min(start+maxBatchSize, len(req.Texts))]
+
+		result, err := c.embedBatch(ctx, embedRequest{
+			Input:     batch,
+			Model:     model,
+			InputType: string(req.InputType),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Data) != len(batch) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(batch), len(result.Data))
+		}
+
+		vectors := make([][]float32, len(batch))
+		for _, d := range result.Data {
+			if d.Index < 0 || d.Index >= len(batch) {
+				return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+			}
+			vectors[d.Index] = d.Embedding
+		}
+
+		resp.Embeddings = append(resp.Embeddings, vectors...)
+		resp.Usage.PromptTokens += result.Usage.TotalTokens
+		resp.Usage.TotalTokens += result.Usage.TotalTokens
+		if result.Model != "" {
+			resp.Model = result.Model
+		}
+	}
+
+	c.logger.Debug("texts embedded",
+		zap.String("model", resp.Model),
+		zap.Int("text_count", len(req.Texts)),
+		zap.Int("tokens", resp.Usage.TotalTokens))
+
+	return resp, nil
+}
+
+func (c *Client) embedBatch(ctx context.Context, body embedRequest) (*embedResponse, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/embeddings", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API call failed: %w", &ports.StatusError{StatusCode: httpResp.StatusCode, Message: strings.TrimSpace(string(respBody))})
+	}
+
+	var result embedResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,93 @@
+package voyage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.baseURL != DefaultBaseURL {
+		t.Errorf("NewClient() baseURL = %s, want %s", client.baseURL, DefaultBaseURL)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	var requests []embedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		var req embedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+
+		// Answer out of order, as the index field allows
+		data := make([]map[string]interface{}, len(req.Input))
+		for i := range req.Input {
+			j := len(req.Input) - 1 - i
+			data[i] = map[string]interface{}{"index": j, "embedding": []float32{float32(len(req.Input[j]))}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":  data,
+			"model": req.Model,
+			"usage": map[string]int{"total_tokens": 2 * len(req.Input)},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("test-key", srv.URL, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	resp, err := client.Embed(context.Background(), ports.EmbeddingRequest{
+		Texts:     []string{"a", "bb", "ccc"},
+		InputType: ports.EmbeddingInputQuery,
+	})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	if len(requests) != 1 || requests[0].Model != DefaultModel || requests[0].InputType != "query" {
+		t.Errorf("requests = %+v, want one %s query request", requests, DefaultModel)
+	}
+	for i, want := range []float32{1, 2, 3} {
+		if resp.Embeddings[i][0] != want {
+			t.Errorf("Embeddings[%d] = %v, want [%v]", i, resp.Embeddings[i], want)
+		}
+	}
+	if resp.Usage.TotalTokens != 6 {
+		t.Errorf("Usage.TotalTokens = %d, want 6", resp.Usage.TotalTokens)
+	}
+}
+
+func TestEmbed_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail":"invalid api key"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient("bad-key", srv.URL, zap.NewNop())
+	if _, err := client.Embed(context.Background(), ports.EmbeddingRequest{Texts: []string{"a"}}); err == nil {
+		t.Error("Embed() expected error for unauthorized request")
+	}
+}
@@ -0,0 +1,28 @@
+// Package voyage implements the embeddings adapter for Voyage AI.
+//
+// This adapter implements the ports.Embedder interface. Voyage is the
+// embedding provider Anthropic recommends alongside Claude.
+//
+// Supported models (examples):
+//   - voyage-3 (default)
+//   - voyage-3-large
+//   - voyage-3-lite
+//   - voyage-code-3
+//
+// The request's InputType is passed through as Voyage's input_type ("query"
+// or "document"), which prepends a retrieval prompt to the texts.
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/embeddings/voyage"
+//
+//	client, err := voyage.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Embed(ctx, ports.EmbeddingRequest{
+//		Texts:     []string{"DAGO runs agent graphs on workers."},
+//		InputType: ports.EmbeddingInputDocument,
+//	})
+package voyage
@@ -0,0 +1,21 @@
+// Package events holds what the event bus adapters share: topic pattern
+// matching and validation, and event defaults. The buses implement
+// libports.EventBus (dago-libs/pkg/ports); the task and worker lifecycle
+// event types are in pkg/ports of this repository.
+//
+// Topics are dot-separated tokens. Subscribe accepts patterns where "*"
+// matches one token and a trailing ">" matches the rest, as in NATS:
+//
+//	workflow.summarize.task.failed   one topic
+//	workflow.*.task.failed           failed tasks of any workflow
+//	workflow.summarize.>             everything about one workflow
+//	worker.*                         worker joined/left
+//
+// Available implementations:
+//   - redis: Redis Pub/Sub (PSUBSCRIBE for patterns)
+//   - nats: Core NATS subjects
+//
+// Both deliver at most once, to subscribers connected when an event is
+// published. They are meant for UIs and alerting; use a task queue for work
+// that must not be lost.
+package events
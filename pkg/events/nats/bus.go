@@ -0,0 +1,198 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aescanero/dago-adapters/pkg/events"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// Default prefix of event subjects
+const defaultSubjectPrefix = "dago.events."
+
+// ErrClosed is returned by Publish and Subscribe after Close
+var ErrClosed = errors.New("event bus closed")
+
+// EventBus implements libports.EventBus using core NATS subjects. Topic
+// patterns are NATS wildcards, so filtering happens on the server. Delivery
+// is at most once, like Redis Pub/Sub.
+type EventBus struct {
+	conn   *nats.Conn
+	logger *zap.Logger
+	prefix string
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+	closed        bool
+}
+
+// subscription is a running Subscribe call
+type subscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewEventBus creates a new NATS event bus
+func NewEventBus(conn *nats.Conn, logger *zap.Logger) *EventBus {
+	return &EventBus{
+		conn:          conn,
+		logger:        logger,
+		prefix:        defaultSubjectPrefix,
+		subscriptions: make(map[string]*subscription),
+	}
+}
+
+// NewEventBusWithNamespace creates a NATS event bus whose subjects are
+// isolated under namespace, e.g. a tenant or environment name
+func NewEventBusWithNamespace(conn *nats.Conn, namespace string, logger *zap.Logger) *EventBus {
+	bus := NewEventBus(conn, logger)
+	bus.prefix = "dago.ns." + namespace + ".events."
+	return bus
+}
+
+// Publish sends an event to a topic (libports.EventBus interface)
+func (b *EventBus) Publish(ctx context.Context, topic string, event libports.Event) error {
+	if err := events.ValidateTopic(topic); err != nil {
+		return err
+	}
+	if b.isClosed() {
+		return ErrClosed
+	}
+
+	data, err := json.Marshal(events.WithDefaults(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := b.conn.Publish(b.prefix+topic, data); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe calls handler for every event published to a topic matching
+// pattern, until Unsubscribe, Close or ctx is cancelled (libports.EventBus
+// interface). Events of one subscription are handled in order.
+func (b *EventBus) Subscribe(ctx context.Context, pattern string, handler libports.EventHandler) error {
+	if err := events.ValidatePattern(pattern); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	if _, ok := b.subscriptions[pattern]; ok {
+		return fmt.Errorf("already subscribed to %q", pattern)
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	natsSub, err := b.conn.Subscribe(b.prefix+pattern, func(msg *nats.Msg) {
+		b.handle(subCtx, msg, handler)
+	})
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to subscribe to %q: %w", pattern, err)
+	}
+	// Make sure the server knows the subscription, so that events published
+	// once Subscribe returns are received
+	if err := b.conn.FlushWithContext(ctx); err != nil {
+		cancel()
+		_ = natsSub.Unsubscribe()
+		return fmt.Errorf("failed to subscribe to %q: %w", pattern, err)
+	}
+
+	sub := &subscription{cancel: cancel, done: make(chan struct{})}
+	b.subscriptions[pattern] = sub
+
+	go func() {
+		defer close(sub.done)
+		<-subCtx.Done()
+
+		if err := natsSub.Unsubscribe(); err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+			b.logger.Warn("failed to unsubscribe",
+				zap.String("pattern", pattern),
+				zap.Error(err))
+		}
+
+		b.mu.Lock()
+		if b.subscriptions[pattern] == sub {
+			delete(b.subscriptions, pattern)
+		}
+		b.mu.Unlock()
+	}()
+
+	b.logger.Debug("subscribed to events", zap.String("pattern", pattern))
+	return nil
+}
+
+// Unsubscribe stops the subscription to pattern (libports.EventBus interface)
+func (b *EventBus) Unsubscribe(ctx context.Context, pattern string) error {
+	b.mu.Lock()
+	sub, ok := b.subscriptions[pattern]
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	sub.cancel()
+	select {
+	case <-sub.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops all subscriptions (libports.EventBus interface). The NATS
+// connection is left open.
+func (b *EventBus) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	subs := make([]*subscription, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.cancel()
+		<-sub.done
+	}
+	return nil
+}
+
+// handle decodes a message and passes it to handler
+func (b *EventBus) handle(ctx context.Context, msg *nats.Msg, handler libports.EventHandler) {
+	if ctx.Err() != nil {
+		return
+	}
+	topic := strings.TrimPrefix(msg.Subject, b.prefix)
+
+	var event libports.Event
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		b.logger.Warn("dropping malformed event",
+			zap.String("topic", topic),
+			zap.Error(err))
+		return
+	}
+	if err := handler(ctx, event); err != nil {
+		b.logger.Warn("event handler failed",
+			zap.String("topic", topic),
+			zap.String("event_id", event.ID),
+			zap.Error(err))
+	}
+}
+
+func (b *EventBus) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
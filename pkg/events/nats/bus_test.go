@@ -0,0 +1,64 @@
+package nats
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+var _ libports.EventBus = (*EventBus)(nil)
+
+// Integration test - only runs with NATS_URL environment variable
+func TestEventBus_Integration(t *testing.T) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		t.Skip("NATS_URL not set, skipping integration test")
+	}
+
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer nc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := NewEventBusWithNamespace(nc, "integration", zap.NewNop())
+	defer bus.Close()
+
+	received := make(chan libports.Event, 10)
+	err = bus.Subscribe(ctx, "worker.*", func(ctx context.Context, event libports.Event) error {
+		received <- event
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	if err := bus.Publish(ctx, "task.started", libports.Event{Type: ports.EventTypeTaskStarted}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := bus.Publish(ctx, "worker.left", libports.Event{Type: ports.EventTypeWorkerLeft}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case event := <-received:
+		if event.Type != ports.EventTypeWorkerLeft {
+			t.Errorf("received %+v, want the worker.left event", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("no event received")
+	}
+
+	if err := bus.Unsubscribe(ctx, "worker.*"); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+}
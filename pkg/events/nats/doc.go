@@ -0,0 +1,23 @@
+// Package nats implements libports.EventBus (dago-libs/pkg/ports) using core
+// NATS.
+//
+// Each topic is a subject, e.g. dago.events.worker.joined
+// (dago.ns.{namespace}.events. with NewEventBusWithNamespace), carrying the
+// JSON-encoded event. Topic patterns use the NATS wildcard syntax, so they
+// are passed to the server as is.
+//
+// The bus doesn't own the connection: Close stops its subscriptions and
+// leaves the connection open.
+//
+// Usage:
+//
+//	nc, _ := nats.Connect("nats://localhost:4222")
+//	bus := nats.NewEventBus(nc, logger)
+//	defer bus.Close()
+//
+//	err := bus.Subscribe(ctx, "worker.*", func(ctx context.Context, event libports.Event) error {
+//		return dashboard.Update(event)
+//	})
+//
+//	err = bus.Publish(ctx, "worker.joined", libports.Event{Type: ports.EventTypeWorkerJoined})
+package nats
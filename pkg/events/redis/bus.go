@@ -0,0 +1,223 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aescanero/dago-adapters/pkg/events"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Default prefix of event channels
+const defaultChannelPrefix = "dago:events:"
+
+// ErrClosed is returned by Publish and Subscribe after Close
+var ErrClosed = errors.New("event bus closed")
+
+// EventBus implements libports.EventBus using Redis Pub/Sub. Delivery is at
+// most once: subscribers only receive events published while they are
+// connected, which suits UIs and alerting but not work distribution.
+type EventBus struct {
+	client redis.UniversalClient
+	logger *zap.Logger
+	prefix string
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+	closed        bool
+}
+
+// subscription is a running Subscribe call
+type subscription struct {
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewEventBus creates a new Redis Pub/Sub event bus
+func NewEventBus(client redis.UniversalClient, logger *zap.Logger) *EventBus {
+	return &EventBus{
+		client:        client,
+		logger:        logger,
+		prefix:        defaultChannelPrefix,
+		subscriptions: make(map[string]*subscription),
+	}
+}
+
+// NewEventBusWithNamespace creates a Redis Pub/Sub event bus whose channels
+// are isolated under namespace, e.g. a tenant or environment name
+func NewEventBusWithNamespace(client redis.UniversalClient, namespace string, logger *zap.Logger) *EventBus {
+	bus := NewEventBus(client, logger)
+	bus.prefix = "dago:ns:" + namespace + ":events:"
+	return bus
+}
+
+// Publish sends an event to a topic (libports.EventBus interface)
+func (b *EventBus) Publish(ctx context.Context, topic string, event libports.Event) error {
+	if err := events.ValidateTopic(topic); err != nil {
+		return err
+	}
+	if b.isClosed() {
+		return ErrClosed
+	}
+
+	data, err := json.Marshal(events.WithDefaults(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := b.client.Publish(ctx, b.prefix+topic, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe calls handler for every event published to a topic matching
+// pattern, until Unsubscribe, Close or ctx is cancelled (libports.EventBus
+// interface). Events of one subscription are handled in order.
+func (b *EventBus) Subscribe(ctx context.Context, pattern string, handler libports.EventHandler) error {
+	if err := events.ValidatePattern(pattern); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	if _, ok := b.subscriptions[pattern]; ok {
+		return fmt.Errorf("already subscribed to %q", pattern)
+	}
+
+	var pubsub *redis.PubSub
+	if events.IsPattern(pattern) {
+		pubsub = b.client.PSubscribe(ctx, b.prefix+globPattern(pattern))
+	} else {
+		pubsub = b.client.Subscribe(ctx, b.prefix+pattern)
+	}
+	// Wait for the confirmation so that events published once Subscribe
+	// returns are received
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return fmt.Errorf("failed to subscribe to %q: %w", pattern, err)
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	sub := &subscription{pubsub: pubsub, cancel: cancel, done: make(chan struct{})}
+	b.subscriptions[pattern] = sub
+
+	go b.run(subCtx, pattern, sub, handler)
+
+	b.logger.Debug("subscribed to events", zap.String("pattern", pattern))
+	return nil
+}
+
+// Unsubscribe stops the subscription to pattern (libports.EventBus interface)
+func (b *EventBus) Unsubscribe(ctx context.Context, pattern string) error {
+	b.mu.Lock()
+	sub, ok := b.subscriptions[pattern]
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	sub.cancel()
+	select {
+	case <-sub.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops all subscriptions (libports.EventBus interface). The Redis
+// client is left open.
+func (b *EventBus) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	subs := make([]*subscription, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.cancel()
+		<-sub.done
+	}
+	return nil
+}
+
+// run delivers messages to handler until ctx is cancelled
+func (b *EventBus) run(ctx context.Context, pattern string, sub *subscription, handler libports.EventHandler) {
+	defer close(sub.done)
+	defer func() {
+		b.mu.Lock()
+		if b.subscriptions[pattern] == sub {
+			delete(b.subscriptions, pattern)
+		}
+		b.mu.Unlock()
+	}()
+	defer func() { _ = sub.pubsub.Close() }()
+
+	messages := sub.pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			topic := strings.TrimPrefix(msg.Channel, b.prefix)
+			// Redis globs are broader than topic patterns
+			if !events.MatchTopic(pattern, topic) {
+				continue
+			}
+
+			var event libports.Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				b.logger.Warn("dropping malformed event",
+					zap.String("topic", topic),
+					zap.Error(err))
+				continue
+			}
+			if err := handler(ctx, event); err != nil {
+				b.logger.Warn("event handler failed",
+					zap.String("topic", topic),
+					zap.String("event_id", event.ID),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+func (b *EventBus) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// globPattern converts a topic pattern to a Redis glob matching a superset
+// of its topics; run filters the rest out
+func globPattern(pattern string) string {
+	tokens := strings.Split(pattern, ".")
+	for i, token := range tokens {
+		switch token {
+		case ports.TopicWildcardToken, ports.TopicTailToken:
+			tokens[i] = "*"
+		default:
+			tokens[i] = globEscaper.Replace(token)
+		}
+	}
+	return strings.Join(tokens, ".")
+}
+
+var globEscaper = strings.NewReplacer(`\`, `\\`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
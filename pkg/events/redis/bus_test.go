@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/events"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ libports.EventBus = (*EventBus)(nil)
+
+func TestEventBus_PublishSubscribe(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	bus := NewEventBus(client, zap.NewNop())
+	defer bus.Close()
+
+	received := make(chan libports.Event, 10)
+	handler := func(ctx context.Context, event libports.Event) error {
+		received <- event
+		return nil
+	}
+	if err := bus.Subscribe(ctx, "workflow.*.task.>", handler); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := bus.Subscribe(ctx, "workflow.*.task.>", handler); err == nil {
+		t.Error("Subscribe() twice to the same pattern expected error")
+	}
+
+	publish := func(topic string, eventType libports.EventType) {
+		t.Helper()
+		if err := bus.Publish(ctx, topic, libports.Event{Type: eventType, ExecutionID: "exec-1"}); err != nil {
+			t.Fatalf("Publish(%s) error = %v", topic, err)
+		}
+	}
+	// Matches the Redis glob but not the pattern: "*" is a single token
+	publish("workflow.a.b.task.started", ports.EventTypeTaskStarted)
+	publish("worker.joined", ports.EventTypeWorkerJoined)
+	publish("workflow.summarize.task.failed", ports.EventTypeTaskFailed)
+
+	select {
+	case event := <-received:
+		if event.Type != ports.EventTypeTaskFailed || event.ExecutionID != "exec-1" || event.ID == "" {
+			t.Errorf("received %+v, want the task.failed event", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	if err := bus.Unsubscribe(ctx, "workflow.*.task.>"); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	publish("workflow.summarize.task.finished", ports.EventTypeTaskFinished)
+
+	select {
+	case event := <-received:
+		t.Errorf("received %+v after Unsubscribe", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := bus.Publish(ctx, "worker.*", libports.Event{}); !errors.Is(err, events.ErrInvalidTopic) {
+		t.Errorf("Publish() to a pattern error = %v, want ErrInvalidTopic", err)
+	}
+}
+
+func TestGlobPattern(t *testing.T) {
+	tests := map[string]string{
+		"worker.joined":     "worker.joined",
+		"workflow.*.task.>": "workflow.*.task.*",
+		"odd[1].name?":      `odd\[1\].name\?`,
+	}
+	for pattern, want := range tests {
+		if got := globPattern(pattern); got != want {
+			t.Errorf("globPattern(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
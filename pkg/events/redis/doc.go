@@ -0,0 +1,29 @@
+// Package redis implements libports.EventBus (dago-libs/pkg/ports) using
+// Redis Pub/Sub.
+//
+// Each topic is a channel, e.g. dago:events:workflow.summarize.task.failed
+// (dago:ns:{namespace}:events: with NewEventBusWithNamespace), carrying the
+// JSON-encoded event. Topics are SUBSCRIBEd, patterns PSUBSCRIBEd with the
+// equivalent Redis glob; as a glob "*" also matches dots, received events
+// are filtered again with events.MatchTopic.
+//
+// Each subscription uses its own connection from the client's pool and
+// calls its handler from one goroutine. Handler errors are logged; they
+// don't stop the subscription.
+//
+// Usage:
+//
+//	bus := redis.NewEventBus(client, logger)
+//	defer bus.Close()
+//
+//	err := bus.Subscribe(ctx, "workflow.*.task.failed", func(ctx context.Context, event libports.Event) error {
+//		return alert(event)
+//	})
+//
+//	err = bus.Publish(ctx, "workflow.summarize.task.failed", libports.Event{
+//		Type:        ports.EventTypeTaskFailed,
+//		ExecutionID: executionID,
+//		NodeID:      nodeID,
+//		Data:        map[string]interface{}{"error": err.Error()},
+//	})
+package redis
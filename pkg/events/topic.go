@@ -0,0 +1,95 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// ErrInvalidTopic is returned for topics or patterns that aren't well formed,
+// and for publishing to a pattern.
+var ErrInvalidTopic = errors.New("invalid event topic")
+
+// ValidateTopic checks that topic is a concrete topic that can be published to
+func ValidateTopic(topic string) error {
+	if err := ValidatePattern(topic); err != nil {
+		return err
+	}
+	if IsPattern(topic) {
+		return fmt.Errorf("%w: cannot publish to pattern %q", ErrInvalidTopic, topic)
+	}
+	return nil
+}
+
+// ValidatePattern checks that pattern is a topic or a topic pattern: non-empty
+// tokens without whitespace, wildcards only as whole tokens and ">" only last
+func ValidatePattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("%w: empty topic", ErrInvalidTopic)
+	}
+
+	tokens := strings.Split(pattern, ".")
+	for i, token := range tokens {
+		switch {
+		case token == "":
+			return fmt.Errorf("%w: empty token in %q", ErrInvalidTopic, pattern)
+		case strings.ContainsAny(token, " \t\r\n"):
+			return fmt.Errorf("%w: whitespace in %q", ErrInvalidTopic, pattern)
+		case token == ports.TopicTailToken && i != len(tokens)-1:
+			return fmt.Errorf("%w: %q must be the last token of %q", ErrInvalidTopic, ports.TopicTailToken, pattern)
+		case token != ports.TopicWildcardToken && token != ports.TopicTailToken &&
+			strings.ContainsAny(token, ports.TopicWildcardToken+ports.TopicTailToken):
+			return fmt.Errorf("%w: wildcard inside token %q of %q", ErrInvalidTopic, token, pattern)
+		}
+	}
+	return nil
+}
+
+// IsPattern reports whether pattern contains wildcard tokens
+func IsPattern(pattern string) bool {
+	for _, token := range strings.Split(pattern, ".") {
+		if token == ports.TopicWildcardToken || token == ports.TopicTailToken {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchTopic reports whether topic matches pattern
+func MatchTopic(pattern, topic string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	topicTokens := strings.Split(topic, ".")
+
+	for i, token := range patternTokens {
+		if token == ports.TopicTailToken {
+			return len(topicTokens) > i
+		}
+		if i >= len(topicTokens) {
+			return false
+		}
+		if token != ports.TopicWildcardToken && token != topicTokens[i] {
+			return false
+		}
+	}
+	return len(topicTokens) == len(patternTokens)
+}
+
+// WithDefaults returns event with a random ID and the current time filled in
+// if they are unset. Buses call it on Publish.
+func WithDefaults(event libports.Event) libports.Event {
+	if event.ID == "" {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		event.ID = hex.EncodeToString(b)
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	return event
+}
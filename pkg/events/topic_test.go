@@ -0,0 +1,63 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{"workflow.summarize.task.failed", "workflow.summarize.task.failed", true},
+		{"workflow.summarize.task.failed", "workflow.summarize.task.started", false},
+		{"workflow.*.task.failed", "workflow.translate.task.failed", true},
+		{"workflow.*.task.failed", "workflow.task.failed", false},
+		{"workflow.*", "workflow.summarize.task.failed", false},
+		{"workflow.>", "workflow.summarize.task.failed", true},
+		{"workflow.>", "workflow", false},
+		{"workflow.*.task.>", "workflow.summarize.task.started", true},
+		{"worker.*", "worker.joined", true},
+		{">", "worker.left", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchTopic(tt.pattern, tt.topic); got != tt.want {
+			t.Errorf("MatchTopic(%q, %q) = %v, want %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := []string{"worker.joined", "workflow.*.task.>", ">", "*"}
+	for _, pattern := range valid {
+		if err := ValidatePattern(pattern); err != nil {
+			t.Errorf("ValidatePattern(%q) error = %v", pattern, err)
+		}
+	}
+
+	invalid := []string{"", "worker..joined", "worker.", "workflow.>.failed", "task.fail*", "task failed"}
+	for _, pattern := range invalid {
+		if err := ValidatePattern(pattern); !errors.Is(err, ErrInvalidTopic) {
+			t.Errorf("ValidatePattern(%q) error = %v, want ErrInvalidTopic", pattern, err)
+		}
+	}
+
+	if err := ValidateTopic("worker.*"); !errors.Is(err, ErrInvalidTopic) {
+		t.Errorf("ValidateTopic() of a pattern error = %v, want ErrInvalidTopic", err)
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	event := WithDefaults(libports.Event{Type: libports.EventTypeNodeStarted})
+	if event.ID == "" || event.Timestamp.IsZero() {
+		t.Errorf("WithDefaults() = %+v, want ID and timestamp set", event)
+	}
+	if again := WithDefaults(event); again.ID != event.ID || !again.Timestamp.Equal(event.Timestamp) {
+		t.Errorf("WithDefaults() overwrote %+v with %+v", event, again)
+	}
+}
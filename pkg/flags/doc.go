@@ -0,0 +1,29 @@
+// Package flags provides adapters for the ports.FeatureFlags interface
+// (pkg/ports in this repository), so that risky adapter behaviors, such as
+// a new streaming path or the semantic cache, can be rolled out gradually
+// per tenant.
+//
+// The env and redis adapters store each flag as a plain value ("true",
+// "b", "20") or a JSON Definition with per-tenant overrides and a
+// percentage rollout:
+//
+//	{"value": "false", "tenants": {"acme": "true"}, "rollout": 10}
+//
+// Tenants are placed in rollout buckets by a hash of the flag and tenant ID,
+// so raising the percentage only adds tenants. Evaluation never fails:
+// missing or invalid flags and unreachable backends yield the default.
+//
+// Available implementations:
+//   - env: Environment variables (DAGO_FLAG_<NAME>)
+//   - redis: A Redis hash, cached in the process
+//   - openfeature: Any OpenFeature provider, with the tenant as targeting key
+//
+// Usage:
+//
+//	ff := redis.NewFlags(client, logger)
+//	err := ff.Set(ctx, "semantic_cache", flags.Definition{Value: "false", Rollout: 10})
+//
+//	if ff.Bool(ctx, "semantic_cache", false, ports.FlagContext{TenantID: tenantID}) {
+//		client = llm.NewCachedClient(client, cache, time.Hour, logger)
+//	}
+package flags
@@ -0,0 +1,13 @@
+// Package env implements ports.FeatureFlags (pkg/ports in this repository)
+// with environment variables, for deployments configured through their
+// manifests.
+//
+// Each flag is read from the variable named by Variable, e.g.
+// DAGO_FLAG_STREAMING_V2 for "streaming.v2", on every evaluation.
+//
+// Usage:
+//
+//	// DAGO_FLAG_STREAMING_V2='{"value":"false","rollout":25}'
+//	ff := env.NewFlags("", logger)
+//	enabled := ff.Bool(ctx, "streaming.v2", false, ports.FlagContext{TenantID: tenantID})
+package env
@@ -0,0 +1,54 @@
+package env
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/flags"
+	"go.uber.org/zap"
+)
+
+// DefaultPrefix prefixes the variables of flags
+const DefaultPrefix = "DAGO_FLAG_"
+
+// Flags implements ports.FeatureFlags with environment variables. Each flag
+// is a variable holding a plain value or a JSON flags.Definition, read on
+// every evaluation.
+type Flags struct {
+	*flags.Evaluator
+	prefix string
+}
+
+// NewFlags creates feature flags read from environment variables
+// prefix is optional and defaults to DefaultPrefix
+func NewFlags(prefix string, logger *zap.Logger) *Flags {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	f := &Flags{prefix: prefix}
+	f.Evaluator = flags.NewEvaluator(f.lookup, logger)
+	return f
+}
+
+// Variable returns the environment variable of a flag: the prefix and the
+// flag name upper-cased, with other characters than letters and digits
+// replaced by underscores, e.g. DAGO_FLAG_STREAMING_V2 for "streaming.v2"
+func (f *Flags) Variable(flag string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, flag)
+	return f.prefix + name
+}
+
+func (f *Flags) lookup(ctx context.Context, flag string) (string, bool, error) {
+	value, ok := os.LookupEnv(f.Variable(flag))
+	return value, ok, nil
+}
@@ -0,0 +1,41 @@
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.FeatureFlags = (*Flags)(nil)
+
+func TestFlags(t *testing.T) {
+	f := NewFlags("", zap.NewNop())
+	if got := f.Variable("streaming.v2-path"); got != "DAGO_FLAG_STREAMING_V2_PATH" {
+		t.Errorf("Variable() = %q", got)
+	}
+
+	t.Setenv("DAGO_FLAG_STREAMING_V2", `{"value":"false","tenants":{"acme":"true"}}`)
+	t.Setenv("DAGO_FLAG_MAX_STEPS", "20")
+	ctx := context.Background()
+
+	if !f.Bool(ctx, "streaming.v2", false, ports.FlagContext{TenantID: "acme"}) {
+		t.Error("tenant override not applied")
+	}
+	if f.Bool(ctx, "streaming.v2", true, ports.FlagContext{}) {
+		t.Error("flag default not applied")
+	}
+	if f.Int(ctx, "max_steps", 10, ports.FlagContext{}) != 20 {
+		t.Error("Int() didn't read the variable")
+	}
+	if f.String(ctx, "unset", "x", ports.FlagContext{}) != "x" {
+		t.Error("unset flag didn't return the default")
+	}
+
+	custom := NewFlags("APP_", zap.NewNop())
+	t.Setenv("APP_STREAMING_V2", "true")
+	if !custom.Bool(ctx, "streaming.v2", false, ports.FlagContext{}) {
+		t.Error("custom prefix not applied")
+	}
+}
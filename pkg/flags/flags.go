@@ -0,0 +1,148 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Definition is how the env and redis adapters store a flag: either a plain
+// value such as "true", or this struct as JSON.
+type Definition struct {
+	// Value is the flag's value for tenants without an override outside the
+	// rollout.
+	Value string `json:"value"`
+
+	// Tenants override the value for specific tenants.
+	Tenants map[string]string `json:"tenants,omitempty"`
+
+	// Rollout gives RolloutValue to this percentage (0-100) of tenants.
+	Rollout int `json:"rollout,omitempty"`
+
+	// RolloutValue is the value of tenants in the rollout; empty is "true".
+	RolloutValue string `json:"rollout_value,omitempty"`
+}
+
+// Parse reads a flag definition, plain or JSON
+func Parse(raw string) (*Definition, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "{") {
+		return &Definition{Value: raw}, nil
+	}
+
+	var def Definition
+	if err := json.Unmarshal([]byte(raw), &def); err != nil {
+		return nil, fmt.Errorf("invalid flag definition: %w", err)
+	}
+	if def.Rollout < 0 || def.Rollout > 100 {
+		return nil, fmt.Errorf("invalid flag definition: rollout %d is not a percentage", def.Rollout)
+	}
+	return &def, nil
+}
+
+// Evaluate returns the flag's value for a tenant
+func (d *Definition) Evaluate(flag, tenantID string) string {
+	if value, ok := d.Tenants[tenantID]; ok && tenantID != "" {
+		return value
+	}
+	if tenantID != "" && Bucket(flag, tenantID) < d.Rollout {
+		if d.RolloutValue == "" {
+			return "true"
+		}
+		return d.RolloutValue
+	}
+	return d.Value
+}
+
+// Bucket places a tenant in one of 100 buckets for a flag's rollout. It is
+// stable, so raising the percentage only adds tenants, and independent per
+// flag, so the same tenants aren't always first.
+func Bucket(flag, tenantID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(flag))
+	h.Write([]byte{0})
+	h.Write([]byte(tenantID))
+	return int(h.Sum32() % 100)
+}
+
+// Lookup returns the raw definition of a flag, and false if it isn't set
+type Lookup func(ctx context.Context, flag string) (string, bool, error)
+
+// Evaluator implements ports.FeatureFlags over the definitions returned by
+// a lookup. Failed lookups and invalid values are logged and evaluate to
+// the default.
+type Evaluator struct {
+	lookup Lookup
+	logger *zap.Logger
+}
+
+// NewEvaluator creates a new evaluator of the flags returned by lookup
+func NewEvaluator(lookup Lookup, logger *zap.Logger) *Evaluator {
+	return &Evaluator{
+		lookup: lookup,
+		logger: logger,
+	}
+}
+
+// value returns the flag's raw value for fc, or false to use the default
+func (e *Evaluator) value(ctx context.Context, flag string, fc ports.FlagContext) (string, bool) {
+	raw, ok, err := e.lookup(ctx, flag)
+	if err != nil {
+		e.logger.Warn("failed to look up feature flag, using the default", zap.String("flag", flag), zap.Error(err))
+		return "", false
+	}
+	if !ok {
+		return "", false
+	}
+
+	def, err := Parse(raw)
+	if err != nil {
+		e.logger.Warn("invalid feature flag, using the default", zap.String("flag", flag), zap.Error(err))
+		return "", false
+	}
+	value := def.Evaluate(flag, fc.TenantID)
+	return value, value != ""
+}
+
+// Bool evaluates a boolean flag (ports.FeatureFlags interface)
+func (e *Evaluator) Bool(ctx context.Context, flag string, defaultValue bool, fc ports.FlagContext) bool {
+	raw, ok := e.value(ctx, flag, fc)
+	if !ok {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		e.logger.Warn("feature flag is not a boolean, using the default", zap.String("flag", flag), zap.String("value", raw))
+		return defaultValue
+	}
+	return value
+}
+
+// String evaluates a string flag (ports.FeatureFlags interface)
+func (e *Evaluator) String(ctx context.Context, flag string, defaultValue string, fc ports.FlagContext) string {
+	raw, ok := e.value(ctx, flag, fc)
+	if !ok {
+		return defaultValue
+	}
+	return raw
+}
+
+// Int evaluates an integer flag (ports.FeatureFlags interface)
+func (e *Evaluator) Int(ctx context.Context, flag string, defaultValue int64, fc ports.FlagContext) int64 {
+	raw, ok := e.value(ctx, flag, fc)
+	if !ok {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		e.logger.Warn("feature flag is not an integer, using the default", zap.String("flag", flag), zap.String("value", raw))
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,101 @@
+package flags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.FeatureFlags = (*Evaluator)(nil)
+
+func TestParse(t *testing.T) {
+	def, err := Parse(" true ")
+	if err != nil || def.Value != "true" {
+		t.Errorf("Parse(plain) = %+v, %v", def, err)
+	}
+
+	def, err = Parse(`{"value":"false","tenants":{"acme":"true"},"rollout":25}`)
+	if err != nil || def.Value != "false" || def.Tenants["acme"] != "true" || def.Rollout != 25 {
+		t.Errorf("Parse(JSON) = %+v, %v", def, err)
+	}
+
+	for _, raw := range []string{`{"value":`, `{"rollout":150}`} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) succeeded", raw)
+		}
+	}
+}
+
+func TestDefinition_Rollout(t *testing.T) {
+	def := &Definition{Value: "false", Rollout: 30, Tenants: map[string]string{"acme": "false"}}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		tenant := fmt.Sprintf("tenant-%d", i)
+		if def.Evaluate("streaming.v2", tenant) == "true" {
+			enabled++
+		}
+		// Stable for a tenant
+		if def.Evaluate("streaming.v2", tenant) != def.Evaluate("streaming.v2", tenant) {
+			t.Fatal("rollout isn't stable")
+		}
+	}
+	if enabled < 250 || enabled > 350 {
+		t.Errorf("%d of 1000 tenants in a 30%% rollout", enabled)
+	}
+
+	if def.Evaluate("streaming.v2", "acme") != "false" {
+		t.Error("tenant override didn't win over the rollout")
+	}
+	if def.Evaluate("streaming.v2", "") != "false" {
+		t.Error("flag without tenant isn't the default value")
+	}
+
+	// Raising the percentage keeps the tenants already in
+	wider := &Definition{Value: "false", Rollout: 60}
+	for i := 0; i < 100; i++ {
+		tenant := fmt.Sprintf("tenant-%d", i)
+		if def.Evaluate("streaming.v2", tenant) == "true" && wider.Evaluate("streaming.v2", tenant) != "true" {
+			t.Fatalf("%s left the rollout when it grew", tenant)
+		}
+	}
+}
+
+func TestEvaluator(t *testing.T) {
+	values := map[string]string{
+		"semantic_cache": `{"value":"false","tenants":{"acme":"true"}}`,
+		"variant":        "b",
+		"max_steps":      "12",
+		"broken":         "{",
+		"not_bool":       "maybe",
+	}
+	lookup := func(ctx context.Context, flag string) (string, bool, error) {
+		if flag == "unreachable" {
+			return "", false, errors.New("connection refused")
+		}
+		v, ok := values[flag]
+		return v, ok, nil
+	}
+	e := NewEvaluator(lookup, zap.NewNop())
+	ctx := context.Background()
+	acme := ports.FlagContext{TenantID: "acme"}
+
+	if !e.Bool(ctx, "semantic_cache", false, acme) || e.Bool(ctx, "semantic_cache", true, ports.FlagContext{TenantID: "other"}) {
+		t.Error("tenant override not applied")
+	}
+	if e.String(ctx, "variant", "a", acme) != "b" || e.Int(ctx, "max_steps", 5, acme) != 12 {
+		t.Error("String() or Int() didn't return the flag value")
+	}
+	for _, flag := range []string{"missing", "unreachable", "broken", "not_bool"} {
+		if !e.Bool(ctx, flag, true, acme) {
+			t.Errorf("Bool(%q) didn't return the default", flag)
+		}
+	}
+	if e.Int(ctx, "variant", 5, acme) != 5 {
+		t.Error("Int() of a non-integer didn't return the default")
+	}
+}
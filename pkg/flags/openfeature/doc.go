@@ -0,0 +1,15 @@
+// Package openfeature implements ports.FeatureFlags (pkg/ports in this
+// repository) with an OpenFeature client, for deployments that manage flags
+// in flagd, LaunchDarkly or another OpenFeature provider.
+//
+// The tenant ID is the targeting key and the "tenant" attribute of the
+// evaluation context, next to the FlagContext attributes.
+//
+// Usage:
+//
+//	if err := openfeature.SetProviderAndWait(provider); err != nil {
+//		log.Fatal(err)
+//	}
+//	ff := flagsof.NewFlags(openfeature.NewDefaultClient(), logger)
+//	enabled := ff.Bool(ctx, "streaming.v2", false, ports.FlagContext{TenantID: tenantID})
+package openfeature
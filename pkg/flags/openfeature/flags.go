@@ -0,0 +1,80 @@
+package openfeature
+
+import (
+	"context"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/open-feature/go-sdk/openfeature"
+	"go.uber.org/zap"
+)
+
+// TenantAttribute is the evaluation context attribute holding the tenant ID,
+// which is also the targeting key
+const TenantAttribute = "tenant"
+
+// Flags implements ports.FeatureFlags with an OpenFeature client, so flags
+// can be served by any OpenFeature provider (flagd, LaunchDarkly, ...)
+type Flags struct {
+	client *openfeature.Client
+	logger *zap.Logger
+}
+
+// NewFlags creates feature flags evaluated by an OpenFeature client, e.g.
+// openfeature.NewDefaultClient() after openfeature.SetProviderAndWait
+func NewFlags(client *openfeature.Client, logger *zap.Logger) *Flags {
+	return &Flags{
+		client: client,
+		logger: logger,
+	}
+}
+
+// evaluationContext converts fc; the tenant ID is the targeting key
+func evaluationContext(fc ports.FlagContext) openfeature.EvaluationContext {
+	attributes := make(map[string]any, len(fc.Attributes)+1)
+	for k, v := range fc.Attributes {
+		attributes[k] = v
+	}
+	if fc.TenantID != "" {
+		attributes[TenantAttribute] = fc.TenantID
+	}
+	return openfeature.NewEvaluationContext(fc.TenantID, attributes)
+}
+
+// evaluated logs a failed evaluation, except for flags that don't exist,
+// and reports whether the evaluation succeeded
+func (f *Flags) evaluated(flag string, details openfeature.EvaluationDetails, err error) bool {
+	if err == nil {
+		return true
+	}
+	if details.ErrorCode != openfeature.FlagNotFoundCode {
+		f.logger.Warn("failed to evaluate feature flag, using the default", zap.String("flag", flag), zap.Error(err))
+	}
+	return false
+}
+
+// Bool evaluates a boolean flag (ports.FeatureFlags interface)
+func (f *Flags) Bool(ctx context.Context, flag string, defaultValue bool, fc ports.FlagContext) bool {
+	details, err := f.client.BooleanValueDetails(ctx, flag, defaultValue, evaluationContext(fc))
+	if !f.evaluated(flag, details.EvaluationDetails, err) {
+		return defaultValue
+	}
+	return details.Value
+}
+
+// String evaluates a string flag (ports.FeatureFlags interface)
+func (f *Flags) String(ctx context.Context, flag string, defaultValue string, fc ports.FlagContext) string {
+	details, err := f.client.StringValueDetails(ctx, flag, defaultValue, evaluationContext(fc))
+	if !f.evaluated(flag, details.EvaluationDetails, err) {
+		return defaultValue
+	}
+	return details.Value
+}
+
+// Int evaluates an integer flag (ports.FeatureFlags interface)
+func (f *Flags) Int(ctx context.Context, flag string, defaultValue int64, fc ports.FlagContext) int64 {
+	details, err := f.client.IntValueDetails(ctx, flag, defaultValue, evaluationContext(fc))
+	if !f.evaluated(flag, details.EvaluationDetails, err) {
+		return defaultValue
+	}
+	return details.Value
+}
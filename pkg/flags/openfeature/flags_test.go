@@ -0,0 +1,56 @@
+package openfeature
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/open-feature/go-sdk/openfeature/memprovider"
+	"go.uber.org/zap"
+)
+
+var _ ports.FeatureFlags = (*Flags)(nil)
+
+func TestFlags(t *testing.T) {
+	byTenant := func(flag memprovider.InMemoryFlag, flatCtx openfeature.FlattenedContext) (any, openfeature.ProviderResolutionDetail) {
+		if flatCtx[openfeature.TargetingKey] == "acme" && flatCtx[TenantAttribute] == "acme" && flatCtx["plan"] == "pro" {
+			return true, openfeature.ProviderResolutionDetail{Reason: openfeature.TargetingMatchReason, Variant: "on"}
+		}
+		return false, openfeature.ProviderResolutionDetail{Reason: openfeature.DefaultReason, Variant: "off"}
+	}
+	provider := memprovider.NewInMemoryProvider(map[string]memprovider.InMemoryFlag{
+		"streaming.v2": {
+			Key:              "streaming.v2",
+			State:            memprovider.Enabled,
+			DefaultVariant:   "off",
+			Variants:         map[string]any{"on": true, "off": false},
+			ContextEvaluator: &byTenant,
+		},
+		"max_steps": {
+			Key:            "max_steps",
+			State:          memprovider.Enabled,
+			DefaultVariant: "default",
+			Variants:       map[string]any{"default": int64(20)},
+		},
+	})
+	if err := openfeature.SetNamedProviderAndWait("dago-flags-test", provider); err != nil {
+		t.Fatal(err)
+	}
+	f := NewFlags(openfeature.NewClient("dago-flags-test"), zap.NewNop())
+	ctx := context.Background()
+
+	pro := ports.FlagContext{TenantID: "acme", Attributes: map[string]any{"plan": "pro"}}
+	if !f.Bool(ctx, "streaming.v2", false, pro) {
+		t.Error("targeting by tenant and attributes not applied")
+	}
+	if f.Bool(ctx, "streaming.v2", true, ports.FlagContext{TenantID: "other"}) {
+		t.Error("other tenant got the targeted value")
+	}
+	if f.Int(ctx, "max_steps", 5, pro) != 20 {
+		t.Error("Int() didn't return the flag value")
+	}
+	if f.String(ctx, "missing", "x", pro) != "x" || f.String(ctx, "max_steps", "x", pro) != "x" {
+		t.Error("missing or mistyped flag didn't return the default")
+	}
+}
@@ -0,0 +1,14 @@
+// Package redis implements ports.FeatureFlags (pkg/ports in this repository)
+// with a Redis hash ("dago:flags" by default) of flag name to definition.
+//
+// Each process caches the whole hash and refreshes it every
+// DefaultRefreshInterval (see SetRefreshInterval), so flags changed with Set
+// or redis-cli reach every replica within that interval. If Redis is
+// unreachable, the last flags read keep being used.
+//
+// Usage:
+//
+//	ff := redis.NewFlags(client, logger)
+//	err := ff.Set(ctx, "streaming.v2", flags.Definition{Value: "false", Tenants: map[string]string{"acme": "true"}})
+//	enabled := ff.Bool(ctx, "streaming.v2", false, ports.FlagContext{TenantID: tenantID})
+package redis
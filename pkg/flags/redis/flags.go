@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/flags"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default key of the flags hash
+	defaultKey = "dago:flags"
+
+	// DefaultRefreshInterval is how long flags are cached in the process
+	DefaultRefreshInterval = 10 * time.Second
+)
+
+// Flags implements ports.FeatureFlags with a Redis hash of flag name to a
+// plain value or a JSON flags.Definition. The whole hash is cached in the
+// process and refreshed periodically, so evaluations rarely reach Redis.
+type Flags struct {
+	*flags.Evaluator
+	client  redis.UniversalClient
+	logger  *zap.Logger
+	key     string
+	refresh time.Duration
+	now     func() time.Time
+
+	mu        sync.Mutex
+	snapshot  map[string]string
+	fetchedAt time.Time
+}
+
+// NewFlags creates feature flags read from a Redis hash
+func NewFlags(client redis.UniversalClient, logger *zap.Logger) *Flags {
+	f := &Flags{
+		client:  client,
+		logger:  logger,
+		key:     defaultKey,
+		refresh: DefaultRefreshInterval,
+		now:     time.Now,
+	}
+	f.Evaluator = flags.NewEvaluator(f.lookup, logger)
+	return f
+}
+
+// SetKey sets the key of the flags hash, e.g. to isolate environments
+func (f *Flags) SetKey(key string) {
+	f.key = key
+}
+
+// SetRefreshInterval sets how long flags are cached; zero reads Redis on
+// every evaluation
+func (f *Flags) SetRefreshInterval(interval time.Duration) {
+	f.refresh = interval
+}
+
+// Set stores a flag definition. Other processes see it within their refresh
+// interval.
+func (f *Flags) Set(ctx context.Context, flag string, def flags.Definition) error {
+	data, err := json.Marshal(def)
+	if err != nil {
+		return fmt.Errorf("failed to marshal flag: %w", err)
+	}
+	if err := f.client.HSet(ctx, f.key, flag, data).Err(); err != nil {
+		return fmt.Errorf("failed to set flag: %w", err)
+	}
+	f.invalidate()
+	return nil
+}
+
+// Delete removes a flag, which then evaluates to its default
+func (f *Flags) Delete(ctx context.Context, flag string) error {
+	if err := f.client.HDel(ctx, f.key, flag).Err(); err != nil {
+		return fmt.Errorf("failed to delete flag: %w", err)
+	}
+	f.invalidate()
+	return nil
+}
+
+func (f *Flags) invalidate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetchedAt = time.Time{}
+}
+
+// lookup reads a flag from the snapshot, refreshing it when stale. If Redis
+// can't be read, the previous snapshot keeps being used.
+func (f *Flags) lookup(ctx context.Context, flag string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.snapshot == nil || f.now().Sub(f.fetchedAt) >= f.refresh {
+		snapshot, err := f.client.HGetAll(ctx, f.key).Result()
+		switch {
+		case err == nil:
+			f.snapshot = snapshot
+			f.fetchedAt = f.now()
+		case f.snapshot == nil:
+			return "", false, fmt.Errorf("failed to read flags: %w", err)
+		default:
+			f.logger.Warn("failed to refresh feature flags, using the previous ones", zap.Error(err))
+		}
+	}
+
+	value, ok := f.snapshot[flag]
+	return value, ok, nil
+}
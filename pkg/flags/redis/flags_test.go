@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/flags"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ ports.FeatureFlags = (*Flags)(nil)
+
+func newTestFlags(t *testing.T) (*Flags, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewFlags(client, zap.NewNop()), mr
+}
+
+func TestFlags(t *testing.T) {
+	ctx := context.Background()
+	f, mr := newTestFlags(t)
+	acme := ports.FlagContext{TenantID: "acme"}
+
+	if err := f.Set(ctx, "semantic_cache", flags.Definition{Value: "false", Tenants: map[string]string{"acme": "true"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	mr.HSet("dago:flags", "variant", "b")
+
+	if !f.Bool(ctx, "semantic_cache", false, acme) || f.Bool(ctx, "semantic_cache", true, ports.FlagContext{}) {
+		t.Error("definition not applied")
+	}
+	if f.String(ctx, "variant", "a", acme) != "b" {
+		t.Error("plain value not read")
+	}
+
+	if err := f.Delete(ctx, "semantic_cache"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if f.Bool(ctx, "semantic_cache", false, acme) {
+		t.Error("deleted flag didn't return the default")
+	}
+}
+
+func TestFlags_Refresh(t *testing.T) {
+	ctx := context.Background()
+	f, mr := newTestFlags(t)
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+
+	mr.HSet("dago:flags", "variant", "a")
+	if f.String(ctx, "variant", "", ports.FlagContext{}) != "a" {
+		t.Fatal("flag not read")
+	}
+
+	// Changes made elsewhere are seen after the refresh interval
+	mr.HSet("dago:flags", "variant", "b")
+	if f.String(ctx, "variant", "", ports.FlagContext{}) != "a" {
+		t.Error("flags read before the refresh interval")
+	}
+	now = now.Add(DefaultRefreshInterval)
+	if f.String(ctx, "variant", "", ports.FlagContext{}) != "b" {
+		t.Error("flags not refreshed")
+	}
+
+	// An outage keeps the last flags
+	mr.Close()
+	now = now.Add(DefaultRefreshInterval)
+	if f.String(ctx, "variant", "", ports.FlagContext{}) != "b" {
+		t.Error("flags lost when Redis went away")
+	}
+}
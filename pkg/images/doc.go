@@ -0,0 +1,26 @@
+// Package images provides adapters for the ports.ImageGenerator interface
+// (pkg/ports in this repository), and helpers that normalize image requests
+// across providers.
+//
+// Providers support different aspect ratios, style presets and negative
+// prompts. Generators pick their closest supported ratio with
+// NearestAspectRatio, and describe styles and negative prompts in the prompt
+// with StylePrompt and NegativePrompt where the provider has no parameter
+// for them, so the same request works with any generator.
+//
+// Available implementations:
+//   - stability: Stability AI Stable Diffusion 3.5, Stable Image and SDXL
+//   - imagen: Google Imagen through the Gemini API
+//
+// Usage:
+//
+//	generator, err := stability.NewClient(os.Getenv("STABILITY_API_KEY"), "", logger)
+//
+//	resp, err := generator.Generate(ctx, ports.ImageRequest{
+//		Prompt:         "A lighthouse on a cliff at dusk",
+//		NegativePrompt: "people, text",
+//		AspectRatio:    "16:9",
+//		Style:          ports.ImageStylePhotographic,
+//	})
+//	err = os.WriteFile("lighthouse.png", resp.Images[0].Data, 0o644)
+package images
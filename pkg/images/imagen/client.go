@@ -0,0 +1,197 @@
+package imagen
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/images"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Gemini API endpoint
+	DefaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = "imagen-4.0-generate-001"
+
+	// Imagen generates at most 4 images per request
+	maxSampleCount = 4
+)
+
+// Person generation settings for SetPersonGeneration
+const (
+	PersonGenerationDontAllow  = "dont_allow"
+	PersonGenerationAllowAdult = "allow_adult"
+	PersonGenerationAllowAll   = "allow_all"
+)
+
+// Aspect ratios supported by Imagen
+var aspectRatios = []string{"1:1", "3:4", "4:3", "9:16", "16:9"}
+
+// Client implements the ports.ImageGenerator interface for Google Imagen
+// through the Gemini API
+type Client struct {
+	apiKey           string
+	baseURL          string
+	personGeneration string
+	httpClient       *http.Client
+	logger           *zap.Logger
+}
+
+// NewClient creates a new Imagen client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+// SetPersonGeneration sets whether images may show people, one of the
+// PersonGeneration constants; the API default is PersonGenerationAllowAdult
+func (c *Client) SetPersonGeneration(setting string) {
+	c.personGeneration = setting
+}
+
+type predictRequest struct {
+	Instances  []instance `json:"instances"`
+	Parameters parameters `json:"parameters"`
+}
+
+type instance struct {
+	Prompt string `json:"prompt"`
+}
+
+type parameters struct {
+	SampleCount      int    `json:"sampleCount"`
+	AspectRatio      string `json:"aspectRatio"`
+	PersonGeneration string `json:"personGeneration,omitempty"`
+}
+
+type predictResponse struct {
+	Predictions []struct {
+		BytesBase64Encoded string `json:"bytesBase64Encoded"`
+		MIMEType           string `json:"mimeType"`
+		RAIFilteredReason  string `json:"raiFilteredReason"`
+	} `json:"predictions"`
+}
+
+// Generate generates images (ports.ImageGenerator interface). Imagen has no
+// negative prompt or style parameters, so both are described in the prompt.
+// Seed and Format aren't supported by the Gemini API and are ignored.
+func (c *Client) Generate(ctx context.Context, req ports.ImageRequest) (*ports.ImageResponse, error) {
+	if req.Prompt == "" {
+		return nil, fmt.Errorf("prompt is required")
+	}
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+	aspectRatio, err := images.NearestAspectRatio(req.AspectRatio, aspectRatios)
+	if err != nil {
+		return nil, err
+	}
+	prompt := images.NegativePrompt(images.StylePrompt(req.Prompt, req.Style), req.NegativePrompt)
+	count := max(req.Count, 1)
+
+	var generated []ports.GeneratedImage
+	for remaining := count; remaining > 0; remaining -= maxSampleCount {
+		body := predictRequest{
+			Instances: []instance{{Prompt: prompt}},
+			Parameters: parameters{
+				SampleCount:      min(remaining, maxSampleCount),
+				AspectRatio:      aspectRatio,
+				PersonGeneration: c.personGeneration,
+			},
+		}
+
+		var resp predictResponse
+		if err := c.predict(ctx, model, body, &resp); err != nil {
+			return nil, err
+		}
+		for _, prediction := range resp.Predictions {
+			if prediction.BytesBase64Encoded == "" {
+				continue
+			}
+			data, err := base64.StdEncoding.DecodeString(prediction.BytesBase64Encoded)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode image: %w", err)
+			}
+			mimeType := prediction.MIMEType
+			if mimeType == "" {
+				mimeType = "image/png"
+			}
+			generated = append(generated, ports.GeneratedImage{Data: data, MIMEType: mimeType})
+		}
+	}
+	if len(generated) == 0 {
+		return nil, fmt.Errorf("%w: %d of %d images", ports.ErrImageFiltered, count, count)
+	}
+
+	c.logger.Debug("images generated",
+		zap.String("model", model),
+		zap.Int("requested", count),
+		zap.Int("generated", len(generated)))
+
+	return &ports.ImageResponse{Images: generated, Model: model}, nil
+}
+
+// predict calls the model's predict method and decodes the response
+func (c *Client) predict(ctx context.Context, model string, body predictRequest, out *predictResponse) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/models/"+model+":predict", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("x-goog-api-key", c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("API call failed: %s: %s", httpResp.Status, apiErr.Error.Message)
+		}
+		return fmt.Errorf("API call failed: %s: %s", httpResp.Status, strings.TrimSpace(string(data)))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package imagen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.ImageGenerator = (*Client)(nil)
+
+func TestGenerate(t *testing.T) {
+	var sampleCounts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/"+DefaultModel+":predict" || r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		var body predictRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Instances[0].Prompt != "A harbor, digital art. Avoid: boats" || body.Parameters.AspectRatio != "3:4" || body.Parameters.PersonGeneration != PersonGenerationDontAllow {
+			t.Errorf("body = %+v", body)
+		}
+		sampleCounts = append(sampleCounts, body.Parameters.SampleCount)
+
+		var predictions []map[string]string
+		for i := 0; i < body.Parameters.SampleCount; i++ {
+			predictions = append(predictions, map[string]string{"bytesBase64Encoded": "UE5H", "mimeType": "image/png"})
+		}
+		predictions[0] = map[string]string{"raiFilteredReason": "blocked"}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"predictions": predictions})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL+"/", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetPersonGeneration(PersonGenerationDontAllow)
+
+	resp, err := client.Generate(context.Background(), ports.ImageRequest{
+		Prompt:         "A harbor",
+		NegativePrompt: "boats",
+		AspectRatio:    "2:3",
+		Style:          ports.ImageStyleDigitalArt,
+		Count:          6,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(sampleCounts) != 2 || sampleCounts[0] != 4 || sampleCounts[1] != 2 {
+		t.Errorf("sample counts = %v", sampleCounts)
+	}
+	if len(resp.Images) != 4 || string(resp.Images[0].Data) != "PNG" || resp.Images[0].MIMEType != "image/png" || resp.Model != DefaultModel {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	filtered := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filtered {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "Imagen API is only accessible to billed users."}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Generate(context.Background(), ports.ImageRequest{Prompt: "A harbor"}); !errors.Is(err, ports.ErrImageFiltered) {
+		t.Errorf("filtered error = %v", err)
+	}
+
+	filtered = false
+	if _, err := client.Generate(context.Background(), ports.ImageRequest{Prompt: "A harbor"}); err == nil || !strings.Contains(err.Error(), "only accessible to billed users") {
+		t.Errorf("API error = %v", err)
+	}
+}
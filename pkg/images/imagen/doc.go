@@ -0,0 +1,20 @@
+// Package imagen implements ports.ImageGenerator (pkg/ports in this
+// repository) for Google Imagen through the Gemini API.
+//
+// Requests for more than four images are split into several predict calls.
+// Aspect ratios are rounded to the closest of 1:1, 3:4, 4:3, 9:16 and 16:9,
+// and styles and negative prompts are described in the prompt, since Imagen
+// has no parameters for them. Images blocked by the safety filters are left
+// out of the response.
+//
+// Usage:
+//
+//	generator, err := imagen.NewClient(os.Getenv("GEMINI_API_KEY"), "", logger)
+//	generator.SetPersonGeneration(imagen.PersonGenerationDontAllow)
+//
+//	resp, err := generator.Generate(ctx, ports.ImageRequest{
+//		Prompt:      "An isometric illustration of a data center",
+//		AspectRatio: "16:9",
+//		Count:       4,
+//	})
+package imagen
@@ -0,0 +1,94 @@
+package images
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// stylePhrases describe the ports.ImageStyle constants in prompts
+var stylePhrases = map[string]string{
+	ports.ImageStylePhotographic: "a high-quality photograph",
+	ports.ImageStyleCinematic:    "a cinematic film still",
+	ports.ImageStyleDigitalArt:   "digital art",
+	ports.ImageStyleAnime:        "anime style art",
+	ports.ImageStyleComicBook:    "a comic book illustration",
+	ports.ImageStyleFantasyArt:   "fantasy art",
+	ports.ImageStyleLineArt:      "line art",
+	ports.ImageStylePixelArt:     "pixel art",
+	ports.ImageStyle3DModel:      "a 3D render",
+}
+
+// ParseAspectRatio returns the value of a "width:height" ratio. Empty is
+// square.
+func ParseAspectRatio(ratio string) (float64, error) {
+	if ratio == "" {
+		return 1, nil
+	}
+	width, height, ok := strings.Cut(ratio, ":")
+	w, errW := strconv.ParseFloat(strings.TrimSpace(width), 64)
+	h, errH := strconv.ParseFloat(strings.TrimSpace(height), 64)
+	if !ok || errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return 0, fmt.Errorf("invalid aspect ratio %q, want width:height", ratio)
+	}
+	return w / h, nil
+}
+
+// NearestAspectRatio returns the supported ratio closest to ratio
+func NearestAspectRatio(ratio string, supported []string) (string, error) {
+	value, err := ParseAspectRatio(ratio)
+	if err != nil {
+		return "", err
+	}
+
+	nearest, distance := "", math.Inf(1)
+	for _, candidate := range supported {
+		v, err := ParseAspectRatio(candidate)
+		if err != nil {
+			return "", err
+		}
+		// Compare on a log scale, so 2:1 and 1:2 are as far from 1:1
+		if d := math.Abs(math.Log(v / value)); d < distance {
+			nearest, distance = candidate, d
+		}
+	}
+	return nearest, nil
+}
+
+// StylePrompt adds the style to a prompt, for providers without style
+// presets. Styles that aren't ports.ImageStyle constants are used as they
+// are.
+func StylePrompt(prompt, style string) string {
+	if style == "" {
+		return prompt
+	}
+	phrase, ok := stylePhrases[style]
+	if !ok {
+		phrase = style
+	}
+	return fmt.Sprintf("%s, %s", strings.TrimRight(prompt, " .,"), phrase)
+}
+
+// NegativePrompt adds a negative prompt to a prompt, for models that don't
+// take one
+func NegativePrompt(prompt, negative string) string {
+	if negative == "" {
+		return prompt
+	}
+	return fmt.Sprintf("%s. Avoid: %s", strings.TrimRight(prompt, " .,"), negative)
+}
+
+// MIMEType returns the MIME type of an image format such as "png"
+func MIMEType(format string) string {
+	switch strings.ToLower(format) {
+	case "jpeg", "jpg":
+		return "image/jpeg"
+	case "webp":
+		return "image/webp"
+	default:
+		return "image/png"
+	}
+}
@@ -0,0 +1,48 @@
+package images
+
+import (
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+func TestNearestAspectRatio(t *testing.T) {
+	supported := []string{"1:1", "3:4", "4:3", "9:16", "16:9"}
+	tests := map[string]string{
+		"":      "1:1",
+		"1:1":   "1:1",
+		"16:9":  "16:9",
+		"21:9":  "16:9",
+		"2:3":   "3:4",
+		"9:21":  "9:16",
+		"5:4":   "4:3",
+		"1.5:1": "4:3",
+	}
+	for ratio, want := range tests {
+		got, err := NearestAspectRatio(ratio, supported)
+		if err != nil || got != want {
+			t.Errorf("NearestAspectRatio(%q) = %q, %v, want %q", ratio, got, err, want)
+		}
+	}
+
+	for _, ratio := range []string{"wide", "16:0", "16x9"} {
+		if _, err := NearestAspectRatio(ratio, supported); err == nil {
+			t.Errorf("NearestAspectRatio(%q) expected an error", ratio)
+		}
+	}
+}
+
+func TestPrompts(t *testing.T) {
+	if got := StylePrompt("A lighthouse at dusk.", ports.ImageStylePixelArt); got != "A lighthouse at dusk, pixel art" {
+		t.Errorf("StylePrompt() = %q", got)
+	}
+	if got := StylePrompt("A lighthouse", "watercolor"); got != "A lighthouse, watercolor" {
+		t.Errorf("StylePrompt() = %q", got)
+	}
+	if got := StylePrompt("A lighthouse", ""); got != "A lighthouse" {
+		t.Errorf("StylePrompt() = %q", got)
+	}
+	if got := NegativePrompt("A lighthouse", "people, boats"); got != "A lighthouse. Avoid: people, boats" {
+		t.Errorf("NegativePrompt() = %q", got)
+	}
+}
@@ -0,0 +1,305 @@
+package stability
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/images"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Stability AI API endpoint
+	DefaultBaseURL = "https://api.stability.ai"
+
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = "sd3.5-large"
+
+	// ModelCore is Stable Image Core, fast and with style presets
+	ModelCore = "core"
+
+	// ModelUltra is Stable Image Ultra, the highest quality
+	ModelUltra = "ultra"
+
+	// ModelSDXL is Stable Diffusion XL 1.0, on the v1 API
+	ModelSDXL = "stable-diffusion-xl-1024-v1-0"
+)
+
+// Aspect ratios of the Stable Image (v2beta) endpoints
+var aspectRatios = []string{"1:1", "16:9", "21:9", "2:3", "3:2", "4:5", "5:4", "9:16", "9:21"}
+
+// Image sizes SDXL was trained on, as width:height
+var sdxlSizes = []string{
+	"1024:1024", "1152:896", "1216:832", "1344:768", "1536:640",
+	"896:1152", "832:1216", "768:1344", "640:1536",
+}
+
+// Client implements the ports.ImageGenerator interface for Stability AI
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Stability AI client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+// Generate generates images (ports.ImageGenerator interface). Stable
+// Diffusion 3.5 models ("sd3.5-large", "sd3.5-medium", ...), ModelCore and
+// ModelUltra use the Stable Image API, which returns one image per call;
+// Stable Diffusion models ("stable-diffusion-...") use the v1 API.
+func (c *Client) Generate(ctx context.Context, req ports.ImageRequest) (*ports.ImageResponse, error) {
+	if req.Prompt == "" {
+		return nil, fmt.Errorf("prompt is required")
+	}
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+	count := max(req.Count, 1)
+
+	var generated []ports.GeneratedImage
+	var err error
+	if strings.HasPrefix(model, "stable-diffusion") {
+		generated, err = c.generateV1(ctx, req, model, count)
+	} else {
+		generated, err = c.generateV2(ctx, req, model, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(generated) == 0 {
+		return nil, fmt.Errorf("%w: %d of %d images", ports.ErrImageFiltered, count, count)
+	}
+
+	c.logger.Debug("images generated",
+		zap.String("model", model),
+		zap.Int("requested", count),
+		zap.Int("generated", len(generated)))
+
+	return &ports.ImageResponse{Images: generated, Model: model}, nil
+}
+
+type stableImageResponse struct {
+	Image        string `json:"image"`
+	FinishReason string `json:"finish_reason"`
+	Seed         int64  `json:"seed"`
+}
+
+// generateV2 calls the Stable Image endpoint once per image
+func (c *Client) generateV2(ctx context.Context, req ports.ImageRequest, model string, count int) ([]ports.GeneratedImage, error) {
+	aspectRatio, err := images.NearestAspectRatio(req.AspectRatio, aspectRatios)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/v2beta/stable-image/generate/sd3"
+	prompt := req.Prompt
+	fields := map[string]string{"aspect_ratio": aspectRatio}
+	switch model {
+	case ModelCore:
+		path = "/v2beta/stable-image/generate/core"
+		if req.Style != "" {
+			fields["style_preset"] = req.Style
+		}
+	case ModelUltra:
+		path = "/v2beta/stable-image/generate/ultra"
+		prompt = images.StylePrompt(prompt, req.Style)
+	default:
+		fields["model"] = model
+		fields["mode"] = "text-to-image"
+		prompt = images.StylePrompt(prompt, req.Style)
+	}
+	fields["prompt"] = prompt
+	if req.NegativePrompt != "" {
+		fields["negative_prompt"] = req.NegativePrompt
+	}
+	if req.Format != "" {
+		fields["output_format"] = req.Format
+	}
+
+	var generated []ports.GeneratedImage
+	for i := 0; i < count; i++ {
+		if req.Seed != 0 {
+			fields["seed"] = strconv.FormatInt(req.Seed+int64(i), 10)
+		}
+
+		body, contentType, err := multipartBody(fields)
+		if err != nil {
+			return nil, err
+		}
+		var resp stableImageResponse
+		if err := c.post(ctx, path, contentType, body, &resp); err != nil {
+			return nil, err
+		}
+		if resp.FinishReason == "CONTENT_FILTERED" {
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(resp.Image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		generated = append(generated, ports.GeneratedImage{
+			Data:     data,
+			MIMEType: images.MIMEType(req.Format),
+			Seed:     resp.Seed,
+		})
+	}
+	return generated, nil
+}
+
+type textPrompt struct {
+	Text   string  `json:"text"`
+	Weight float64 `json:"weight"`
+}
+
+type textToImageRequest struct {
+	TextPrompts []textPrompt `json:"text_prompts"`
+	Width       int          `json:"width"`
+	Height      int          `json:"height"`
+	Samples     int          `json:"samples"`
+	Seed        int64        `json:"seed,omitempty"`
+	StylePreset string       `json:"style_preset,omitempty"`
+}
+
+type textToImageResponse struct {
+	Artifacts []struct {
+		Base64       string `json:"base64"`
+		Seed         int64  `json:"seed"`
+		FinishReason string `json:"finishReason"`
+	} `json:"artifacts"`
+}
+
+// generateV1 calls the v1 text-to-image endpoint, which returns PNG images
+func (c *Client) generateV1(ctx context.Context, req ports.ImageRequest, model string, count int) ([]ports.GeneratedImage, error) {
+	size, err := images.NearestAspectRatio(req.AspectRatio, sdxlSizes)
+	if err != nil {
+		return nil, err
+	}
+	width, height, _ := strings.Cut(size, ":")
+
+	body := textToImageRequest{
+		TextPrompts: []textPrompt{{Text: req.Prompt, Weight: 1}},
+		Samples:     count,
+		Seed:        req.Seed,
+		StylePreset: req.Style,
+	}
+	body.Width, _ = strconv.Atoi(width)
+	body.Height, _ = strconv.Atoi(height)
+	if req.NegativePrompt != "" {
+		body.TextPrompts = append(body.TextPrompts, textPrompt{Text: req.NegativePrompt, Weight: -1})
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	var resp textToImageResponse
+	if err := c.post(ctx, "/v1/generation/"+model+"/text-to-image", "application/json", payload, &resp); err != nil {
+		return nil, err
+	}
+
+	var generated []ports.GeneratedImage
+	for _, artifact := range resp.Artifacts {
+		if artifact.FinishReason == "CONTENT_FILTERED" {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(artifact.Base64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		generated = append(generated, ports.GeneratedImage{Data: data, MIMEType: "image/png", Seed: artifact.Seed})
+	}
+	return generated, nil
+}
+
+// multipartBody encodes form fields as multipart/form-data, which the
+// Stable Image API requires
+func multipartBody(fields map[string]string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// post sends a request and decodes the JSON response
+func (c *Client) post(ctx context.Context, path, contentType string, body []byte, out interface{}) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("Accept", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API call failed: %s: %s", httpResp.Status, errorMessage(data))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message of a v1 or v2 error response
+func errorMessage(body []byte) string {
+	var result struct {
+		Name    string   `json:"name"`
+		Message string   `json:"message"`
+		Errors  []string `json:"errors"`
+	}
+	if json.Unmarshal(body, &result) == nil {
+		if len(result.Errors) > 0 {
+			return result.Name + ": " + strings.Join(result.Errors, "; ")
+		}
+		if result.Message != "" {
+			return result.Name + ": " + result.Message
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
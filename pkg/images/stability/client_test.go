@@ -0,0 +1,171 @@
+package stability
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.ImageGenerator = (*Client)(nil)
+
+func TestGenerateSD3(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2beta/stable-image/generate/sd3" || r.Header.Get("Authorization") != "Bearer test-key" || r.Header.Get("Accept") != "application/json" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		form := r.MultipartForm.Value
+		if form["prompt"][0] != "A harbor, a cinematic film still" || form["negative_prompt"][0] != "boats" ||
+			form["aspect_ratio"][0] != "21:9" || form["model"][0] != DefaultModel || form["output_format"][0] != "jpeg" {
+			t.Errorf("form = %v", form)
+		}
+		if want := fmt.Sprint(42 + calls); form["seed"][0] != want {
+			t.Errorf("seed = %s, want %s", form["seed"][0], want)
+		}
+		calls++
+
+		finishReason := "SUCCESS"
+		if calls == 2 {
+			finishReason = "CONTENT_FILTERED"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"image":         base64.StdEncoding.EncodeToString([]byte("JPEG")),
+			"finish_reason": finishReason,
+			"seed":          41 + calls,
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Generate(context.Background(), ports.ImageRequest{
+		Prompt:         "A harbor",
+		NegativePrompt: "boats",
+		AspectRatio:    "2.4:1",
+		Style:          ports.ImageStyleCinematic,
+		Count:          3,
+		Seed:           42,
+		Format:         "jpeg",
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if calls != 3 || len(resp.Images) != 2 || resp.Model != DefaultModel {
+		t.Fatalf("calls = %d, resp = %+v", calls, resp)
+	}
+	if img := resp.Images[1]; string(img.Data) != "JPEG" || img.MIMEType != "image/jpeg" || img.Seed != 44 {
+		t.Errorf("image = %+v", img)
+	}
+}
+
+func TestGenerateCoreStylePreset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2beta/stable-image/generate/core" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_ = r.ParseMultipartForm(1 << 20)
+		form := r.MultipartForm.Value
+		if form["prompt"][0] != "A harbor" || form["style_preset"][0] != "anime" || form["aspect_ratio"][0] != "1:1" || form["model"] != nil {
+			t.Errorf("form = %v", form)
+		}
+		_, _ = w.Write([]byte(`{"image": "UE5H", "finish_reason": "SUCCESS", "seed": 7}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Generate(context.Background(), ports.ImageRequest{Prompt: "A harbor", Model: ModelCore, Style: ports.ImageStyleAnime})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(resp.Images) != 1 || string(resp.Images[0].Data) != "PNG" || resp.Images[0].MIMEType != "image/png" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestGenerateSDXL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/generation/"+ModelSDXL+"/text-to-image" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		var body textToImageRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Width != 1344 || body.Height != 768 || body.Samples != 2 || body.StylePreset != "pixel-art" ||
+			len(body.TextPrompts) != 2 || body.TextPrompts[1].Text != "blur" || body.TextPrompts[1].Weight != -1 {
+			t.Errorf("body = %+v", body)
+		}
+		_, _ = w.Write([]byte(`{"artifacts": [
+			{"base64": "UE5H", "seed": 1, "finishReason": "SUCCESS"},
+			{"base64": "", "seed": 2, "finishReason": "CONTENT_FILTERED"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Generate(context.Background(), ports.ImageRequest{
+		Prompt:         "A harbor",
+		NegativePrompt: "blur",
+		Model:          ModelSDXL,
+		AspectRatio:    "16:9",
+		Style:          ports.ImageStylePixelArt,
+		Count:          2,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(resp.Images) != 1 || resp.Images[0].Seed != 1 {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	filtered := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filtered {
+			_, _ = w.Write([]byte(`{"image": "", "finish_reason": "CONTENT_FILTERED"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"id": "abc", "name": "bad_request", "errors": ["prompt: too long"]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Generate(context.Background(), ports.ImageRequest{Prompt: "A harbor"}); !errors.Is(err, ports.ErrImageFiltered) {
+		t.Errorf("filtered error = %v", err)
+	}
+
+	filtered = false
+	if _, err := client.Generate(context.Background(), ports.ImageRequest{Prompt: "A harbor"}); err == nil || !strings.Contains(err.Error(), "bad_request: prompt: too long") {
+		t.Errorf("API error = %v", err)
+	}
+	if _, err := client.Generate(context.Background(), ports.ImageRequest{Prompt: "A harbor", AspectRatio: "wide"}); err == nil {
+		t.Error("expected an error for an invalid aspect ratio")
+	}
+}
@@ -0,0 +1,22 @@
+// Package stability implements ports.ImageGenerator (pkg/ports in this
+// repository) for Stability AI.
+//
+// Stable Diffusion 3.5 models, Stable Image Core and Stable Image Ultra are
+// called through the Stable Image API, one image per call; SDXL is called
+// through the v1 API with the closest size it was trained on. Styles are
+// sent as style presets to Core and SDXL and described in the prompt for the
+// other models. Images blocked by the content filter are left out of the
+// response.
+//
+// Usage:
+//
+//	generator, err := stability.NewClient(os.Getenv("STABILITY_API_KEY"), "", logger)
+//
+//	resp, err := generator.Generate(ctx, ports.ImageRequest{
+//		Prompt:         "A watercolor map of a harbor town",
+//		NegativePrompt: "text, labels",
+//		Model:          stability.ModelCore,
+//		AspectRatio:    "3:2",
+//		Style:          ports.ImageStyleFantasyArt,
+//	})
+package stability
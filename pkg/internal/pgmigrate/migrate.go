@@ -0,0 +1,93 @@
+// Package pgmigrate applies the embedded SQL migrations of the PostgreSQL
+// adapters. Their versions are recorded in one table shared by all of them,
+// so each adapter's migrations need names no other adapter uses.
+package pgmigrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// Table tracking which migrations have been applied
+	migrationsTable = "dago_schema_migrations"
+
+	// Advisory lock key used to serialize concurrent Migrate calls
+	migrationLockKey = 0x6461676f // "dago"
+)
+
+// Migrate applies the migrations of fsys whose names start with prefix and
+// end in .sql, e.g. "migrations/", in name order, skipping those already
+// applied. A migration's version is its name without prefix and extension.
+// It is safe to call concurrently from several processes.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, prefix string, logger *zap.Logger) error {
+	if _, err := pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS `+migrationsTable+` (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	files, err := fs.Glob(fsys, prefix+"*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		version := strings.TrimSuffix(strings.TrimPrefix(file, prefix), ".sql")
+
+		applied, err := applyMigration(ctx, pool, fsys, version, file)
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		}
+		if applied {
+			logger.Info("applied migration", zap.String("version", version))
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs the script in file and records version in one
+// transaction, holding the advisory lock, unless version was applied
+func applyMigration(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, version, file string) (bool, error) {
+	script, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockKey); err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM `+migrationsTable+` WHERE version = $1)`, version).Scan(&exists); err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, string(script)); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO `+migrationsTable+` (version) VALUES ($1)`, version); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,105 @@
+// Package retry tells the errors of the LLM and embedding adapters worth
+// retrying from those that fail the same way every time. Provider SDKs each
+// report HTTP statuses in their own error type; they are recognized here so
+// that the retry middleware of pkg/llm and pkg/embeddings agree.
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/ollama/ollama/api"
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxRetryAfter is the longest RetryAfter the retry middleware waits for.
+// Providers asking for longer, e.g. until a daily limit resets, fail the call
+// at once rather than block it for hours.
+const MaxRetryAfter = time.Minute
+
+// Delay returns how long to wait before retrying a call that failed with
+// err: the RetryAfter of a *ports.RateLimitError, or backoff. It returns
+// false when the RetryAfter is over MaxRetryAfter.
+func Delay(err error, backoff time.Duration) (time.Duration, bool) {
+	delay := ports.RetryDelay(err, backoff)
+	var rateErr *ports.RateLimitError
+	if errors.As(err, &rateErr) && rateErr.RetryAfter > MaxRetryAfter {
+		return 0, false
+	}
+	return delay, true
+}
+
+// Retryable reports whether a call that failed with err may succeed if made
+// again: rate limits, server errors (5xx), timeouts and network failures.
+// Invalid requests, unsupported methods, authentication failures and other
+// client errors are not, nor are errors of a context that ended.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ports.ErrInvalidRequest) || errors.Is(err, ports.ErrNotImplemented) {
+		return false
+	}
+
+	var rateErr *ports.RateLimitError
+	if errors.As(err, &rateErr) {
+		return true
+	}
+	if code, ok := StatusCode(err); ok {
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.DeadlineExceeded:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// StatusCode returns the HTTP status of the provider response err reports,
+// if any
+func StatusCode(err error) (int, bool) {
+	var (
+		coded      interface{ HTTPStatusCode() int }
+		openaiErr  *openai.APIError
+		requestErr *openai.RequestError
+		claudeErr  *anthropic.Error
+		googleErr  *googleapi.Error
+		ollamaErr  api.StatusError
+	)
+	switch {
+	case errors.As(err, &coded):
+		return coded.HTTPStatusCode(), true
+	case errors.As(err, &openaiErr) && openaiErr.HTTPStatusCode != 0:
+		return openaiErr.HTTPStatusCode, true
+	case errors.As(err, &requestErr) && requestErr.HTTPStatusCode != 0:
+		return requestErr.HTTPStatusCode, true
+	case errors.As(err, &claudeErr):
+		return claudeErr.StatusCode, true
+	case errors.As(err, &googleErr):
+		return googleErr.Code, true
+	case errors.As(err, &ollamaErr):
+		return ollamaErr.StatusCode, true
+	}
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/ollama/ollama/api"
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   time.Duration
+		wantOK bool
+	}{
+		{"backoff", &ports.StatusError{StatusCode: 503}, time.Second, true},
+		{"retry after", fmt.Errorf("call: %w", &ports.RateLimitError{RetryAfter: 5 * time.Second}), 5 * time.Second, true},
+		{"no retry after", &ports.RateLimitError{}, time.Second, true},
+		{"too long", &ports.RateLimitError{RetryAfter: 7 * time.Hour}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := Delay(tt.err, time.Second); got != tt.want || ok != tt.wantOK {
+				t.Errorf("Delay() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"invalid request", fmt.Errorf("%w: no messages", ports.ErrInvalidRequest), false},
+		{"not implemented", ports.ErrNotImplemented, false},
+		{"canceled", fmt.Errorf("API call failed: %w", context.Canceled), false},
+		{"deadline", &url.Error{Op: "Post", URL: "http://api", Err: context.DeadlineExceeded}, false},
+		{"unknown", errors.New("unexpected response"), false},
+		{"rate limited", fmt.Errorf("API call failed: %w", &ports.RateLimitError{Message: "slow down"}), true},
+		{"status 503", &ports.StatusError{StatusCode: 503}, true},
+		{"status 429", &ports.StatusError{StatusCode: 429}, true},
+		{"status 401", &ports.StatusError{StatusCode: 401}, false},
+		{"status 400", &ports.StatusError{StatusCode: 400}, false},
+		{"openai 500", &openai.APIError{HTTPStatusCode: 500}, true},
+		{"openai 403", &openai.APIError{HTTPStatusCode: 403}, false},
+		{"openai request 502", &openai.RequestError{HTTPStatusCode: 502}, true},
+		{"anthropic 529", &anthropic.Error{StatusCode: 529}, true},
+		{"anthropic 401", &anthropic.Error{StatusCode: 401}, false},
+		{"google 503", &googleapi.Error{Code: 503}, true},
+		{"ollama 404", api.StatusError{StatusCode: 404}, false},
+		{"grpc unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"grpc invalid argument", status.Error(codes.InvalidArgument, "bad"), false},
+		{"connection refused", &url.Error{Op: "Post", URL: "http://api", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"truncated", fmt.Errorf("decode: %w", io.ErrUnexpectedEOF), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Retryable(tt.err); got != tt.want {
+				t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
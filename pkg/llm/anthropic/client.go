@@ -2,10 +2,13 @@ package anthropic
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/aescanero/dago-adapters/pkg/ports"
 	"github.com/aescanero/dago-libs/pkg/domain"
-	"github.com/aescanero/dago-libs/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
 	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/anthropics/anthropic-sdk-go/packages/param"
@@ -15,7 +18,10 @@ import (
 // Client implements the LLMClient interface for Anthropic Claude
 type Client struct {
 	client anthropicsdk.Client
+	apiKey string
 	logger *zap.Logger
+
+	thinkingBudget int
 }
 
 // NewClient creates a new Anthropic client
@@ -30,73 +36,154 @@ func NewClient(apiKey string, logger *zap.Logger) (*Client, error) {
 
 	return &Client{
 		client: client,
+		apiKey: apiKey,
 		logger: logger,
 	}, nil
 }
 
-// Complete performs a standard text completion (ports.LLMClient interface)
-func (c *Client) Complete(ctx context.Context, req ports.CompletionRequest) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+// SetBaseURL sets the API endpoint, e.g. a proxy or a test server
+func (c *Client) SetBaseURL(baseURL string) {
+	c.client = anthropicsdk.NewClient(
+		option.WithAPIKey(c.apiKey),
+		option.WithBaseURL(baseURL),
+	)
 }
 
-// CompleteWithTools performs a completion with tool calling support (ports.LLMClient interface)
-func (c *Client) CompleteWithTools(ctx context.Context, req ports.CompletionRequest, tools []ports.Tool) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+// SetThinkingBudget enables extended thinking, letting Claude reason for up
+// to budgetTokens tokens (at least 1024) before answering. Thinking tokens
+// count towards the request's max tokens, which are raised to leave room for
+// the answer, and temperatures are ignored as the API requires. Stream with
+// StreamEvents to receive the reasoning. Zero disables thinking.
+func (c *Client) SetThinkingBudget(budgetTokens int) {
+	c.thinkingBudget = budgetTokens
 }
 
-// CompleteStructured performs a completion with guaranteed JSON schema conformance (ports.LLMClient interface)
-func (c *Client) CompleteStructured(ctx context.Context, req ports.CompletionRequest, schema ports.JSONSchema) (*ports.StructuredResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
 }
 
-// GenerateCompletion generates a completion using domain.LLMRequest (compatibility method)
-func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
-	// Type assert the request
-	llmReq, ok := req.(*domain.LLMRequest)
-	if !ok {
-		return nil, fmt.Errorf("invalid request type")
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface). Tool calls and results of earlier turns, in
+// messages built with ports.ToolCallsMessage and ports.ToolResultMessage,
+// are sent as tool_use and tool_result blocks.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
 	}
 
-	c.logger.Debug("generating completion",
-		zap.String("model", llmReq.Model),
-		zap.Int("message_count", len(llmReq.Messages)))
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
 
-	// Convert messages to Anthropic format
-	messages := make([]anthropicsdk.MessageParam, 0, len(llmReq.Messages))
-	for _, msg := range llmReq.Messages {
-		// Create text block for message content
-		switch msg.Role {
-		case "user":
-			messages = append(messages, anthropicsdk.NewUserMessage(
-				anthropicsdk.NewTextBlock(msg.Content),
-			))
-		case "assistant":
-			messages = append(messages, anthropicsdk.NewAssistantMessage(
-				anthropicsdk.NewTextBlock(msg.Content),
-			))
-		}
+	params, err := c.completionParams(req, tools, c.thinkingBudget)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Messages.New(ctx, params)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	completion := toCompletionResponse(resp)
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens))
+
+	return completion, nil
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance (ports.LLMClient interface). Claude has no JSON mode: the
+// schema is the input schema of a single tool Claude is forced to call, and
+// the call's input is the data returned. Forced tool use is incompatible
+// with extended thinking, which is disabled for these requests.
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
 	}
 
-	// Build request parameters
-	maxTokens := int64(llmReq.MaxTokens)
-	if maxTokens == 0 {
-		maxTokens = 1024
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
+
+	description, _ := schema["description"].(string)
+	if description == "" {
+		description = "Respond with data conforming to the input schema"
 	}
+	tool := libports.Tool{Name: structuredOutputTool, Description: description, Parameters: schema}
 
-	params := anthropicsdk.MessageNewParams{
-		Model:     anthropicsdk.Model(llmReq.Model),
-		Messages:  messages,
-		MaxTokens: maxTokens,
+	// The API rejects forced tool use with extended thinking
+	params, err := c.completionParams(req, []libports.Tool{tool}, 0)
+	if err != nil {
+		return nil, err
 	}
+	params.ToolChoice = anthropicsdk.ToolChoiceParamOfTool(structuredOutputTool)
 
-	if llmReq.System != "" {
-		params.System = []anthropicsdk.TextBlockParam{
-			{Text: llmReq.System},
+	resp, err := c.client.Messages.New(ctx, params)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	structured := &libports.StructuredResponse{
+		Usage: libports.UsageInfo{
+			PromptTokens:     int(resp.Usage.InputTokens),
+			CompletionTokens: int(resp.Usage.OutputTokens),
+			TotalTokens:      int(resp.Usage.InputTokens + resp.Usage.OutputTokens),
+		},
+		CreatedAt: time.Now(),
+	}
+	for _, call := range extractToolCalls(resp) {
+		if call.Name == structuredOutputTool {
+			structured.Data = call.Input
+			break
 		}
 	}
+	if structured.Data == nil {
+		return nil, fmt.Errorf("no structured output in response (stop reason %q)", resp.StopReason)
+	}
+
+	c.logger.Debug("structured completion generated",
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
 
-	if llmReq.Temperature > 0 {
-		params.Temperature = param.NewOpt(llmReq.Temperature)
+	return structured, nil
+}
+
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
+func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Generate generates a completion of a domain request
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion",
+		zap.String("model", llmReq.Model),
+		zap.Int("message_count", len(llmReq.Messages)))
+
+	params, err := c.newParams(llmReq)
+	if err != nil {
+		return nil, err
 	}
 
 	// Call API
@@ -108,8 +195,9 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 
 	// Convert response
 	llmResp := &domain.LLMResponse{
-		Content: extractContent(resp),
-		Model:   string(resp.Model),
+		Content:   extractContent(resp),
+		Model:     string(resp.Model),
+		ToolCalls: extractToolCalls(resp),
 		Usage: domain.Usage{
 			InputTokens:  int(resp.Usage.InputTokens),
 			OutputTokens: int(resp.Usage.OutputTokens),
@@ -123,6 +211,243 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 	return llmResp, nil
 }
 
+// Max tokens of requests that don't set them
+const defaultMaxTokens = 1024
+
+// Name of the tool CompleteStructured forces Claude to call with its answer
+const structuredOutputTool = "structured_output"
+
+// newParams builds the Messages API parameters of llmReq
+func (c *Client) newParams(llmReq *domain.LLMRequest) (anthropicsdk.MessageNewParams, error) {
+	system, messages := c.convertMessages(llmReq)
+	if len(messages) == 0 {
+		return anthropicsdk.MessageNewParams{}, fmt.Errorf("%w: only system messages", ports.ErrInvalidRequest)
+	}
+
+	params := anthropicsdk.MessageNewParams{
+		Model:    anthropicsdk.Model(llmReq.Model),
+		Messages: messages,
+	}
+
+	if len(system) > 0 {
+		params.System = system
+	}
+
+	if len(llmReq.Tools) > 0 {
+		tools := make([]libports.Tool, 0, len(llmReq.Tools))
+		for _, tool := range llmReq.Tools {
+			tools = append(tools, libports.Tool(tool))
+		}
+		params.Tools = convertTools(tools)
+	}
+
+	setSampling(&params, llmReq.MaxTokens, llmReq.Temperature, c.thinkingBudget)
+	return params, nil
+}
+
+// completionParams returns the parameters of a completion request with
+// tools, thinking up to thinkingBudget tokens when it isn't zero
+func (c *Client) completionParams(req libports.CompletionRequest, tools []libports.Tool, thinkingBudget int) (anthropicsdk.MessageNewParams, error) {
+	system, messages := c.convertCompletionMessages(req.Messages)
+	if len(messages) == 0 {
+		return anthropicsdk.MessageNewParams{}, fmt.Errorf("%w: only system messages", ports.ErrInvalidRequest)
+	}
+	params := anthropicsdk.MessageNewParams{
+		Model:    anthropicsdk.Model(req.Model),
+		Messages: messages,
+	}
+	if len(system) > 0 {
+		params.System = system
+	}
+	if len(tools) > 0 {
+		params.Tools = convertTools(tools)
+	}
+	if len(req.Stop) > 0 {
+		params.StopSequences = req.Stop
+	}
+	setSampling(&params, req.MaxTokens, req.Temperature, thinkingBudget)
+	if req.TopP > 0 && thinkingBudget == 0 {
+		params.TopP = param.NewOpt(req.TopP)
+	}
+	return params, nil
+}
+
+// setSampling sets the max tokens and temperature of params, and extended
+// thinking when thinkingBudget isn't zero
+func setSampling(params *anthropicsdk.MessageNewParams, maxTokens int, temperature float64, thinkingBudget int) {
+	params.MaxTokens = int64(maxTokens)
+	if params.MaxTokens == 0 {
+		params.MaxTokens = defaultMaxTokens
+	}
+
+	if thinkingBudget > 0 {
+		// Thinking tokens count towards max_tokens, which must leave room
+		// for the answer, and the API rejects a temperature with thinking
+		budget := int64(thinkingBudget)
+		params.Thinking = anthropicsdk.ThinkingConfigParamOfEnabled(budget)
+		if params.MaxTokens <= budget {
+			params.MaxTokens += budget
+		}
+		return
+	}
+
+	if temperature > 0 {
+		params.Temperature = param.NewOpt(temperature)
+	}
+}
+
+// convertTools converts tools to Anthropic format. Parameters are the JSON
+// schema of the tool input; its properties and required fields are mapped,
+// anything else is passed through.
+func convertTools(tools []libports.Tool) []anthropicsdk.ToolUnionParam {
+	converted := make([]anthropicsdk.ToolUnionParam, 0, len(tools))
+	for _, tool := range tools {
+		schema := anthropicsdk.ToolInputSchemaParam{}
+		for key, value := range tool.Parameters {
+			switch key {
+			case "type":
+			case "properties":
+				schema.Properties = value
+			case "required":
+				schema.Required = toStrings(value)
+			default:
+				if schema.ExtraFields == nil {
+					schema.ExtraFields = make(map[string]any)
+				}
+				schema.ExtraFields[key] = value
+			}
+		}
+
+		toolParam := anthropicsdk.ToolParam{Name: tool.Name, InputSchema: schema}
+		if tool.Description != "" {
+			toolParam.Description = param.NewOpt(tool.Description)
+		}
+		converted = append(converted, anthropicsdk.ToolUnionParam{OfTool: &toolParam})
+	}
+	return converted
+}
+
+// toStrings returns the strings of a JSON schema list, decoded from JSON
+// ([]interface{}) or built in Go ([]string)
+func toStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var strs []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
+
+// convertMessages converts the messages of a domain request to Anthropic
+// format, tool calls and results included, like convertCompletionMessages
+func (c *Client) convertMessages(llmReq *domain.LLMRequest) ([]anthropicsdk.TextBlockParam, []anthropicsdk.MessageParam) {
+	return c.convertCompletionMessages(ports.CompletionMessages(llmReq))
+}
+
+// convertCompletionMessages converts messages to Anthropic format. The API
+// takes the system prompt apart, so system messages are appended to it;
+// unknown roles are sent as user messages. Messages built with
+// ports.ToolCallsMessage become assistant messages with tool_use blocks,
+// and results built with ports.ToolResultMessage tool_result blocks;
+// consecutive results are sent in one user message, as the API expects the
+// results of all the calls of a turn together.
+func (c *Client) convertCompletionMessages(msgs []libports.Message) ([]anthropicsdk.TextBlockParam, []anthropicsdk.MessageParam) {
+	var system []anthropicsdk.TextBlockParam
+	messages := make([]anthropicsdk.MessageParam, 0, len(msgs))
+	results := false // whether the last message holds tool results
+
+	for _, msg := range msgs {
+		if content, calls, ok := ports.ParseToolCallsMessage(msg); ok {
+			var blocks []anthropicsdk.ContentBlockParamUnion
+			if content != "" {
+				blocks = append(blocks, anthropicsdk.NewTextBlock(content))
+			}
+			for _, call := range calls {
+				input := call.Arguments
+				if input == nil {
+					input = map[string]interface{}{}
+				}
+				blocks = append(blocks, anthropicsdk.NewToolUseBlock(call.ID, input, call.Name))
+			}
+			messages = append(messages, anthropicsdk.NewAssistantMessage(blocks...))
+			results = false
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			block := anthropicsdk.NewToolResultBlock(result.ToolCallID, result.Content, result.IsError)
+			if results {
+				last := &messages[len(messages)-1]
+				last.Content = append(last.Content, block)
+			} else {
+				messages = append(messages, anthropicsdk.NewUserMessage(block))
+			}
+			results = true
+			continue
+		}
+
+		results = false
+		switch msg.Role {
+		case "user":
+			messages = append(messages, anthropicsdk.NewUserMessage(anthropicsdk.NewTextBlock(msg.Content)))
+		case "assistant":
+			messages = append(messages, anthropicsdk.NewAssistantMessage(anthropicsdk.NewTextBlock(msg.Content)))
+		case "system":
+			system = append(system, anthropicsdk.TextBlockParam{Text: msg.Content})
+		default:
+			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
+			messages = append(messages, anthropicsdk.NewUserMessage(anthropicsdk.NewTextBlock(msg.Content)))
+		}
+	}
+
+	return system, messages
+}
+
+// toCompletionResponse converts a response to the port's representation,
+// with the stop reason in the OpenAI vocabulary the gateway and other
+// adapters use
+func toCompletionResponse(resp *anthropicsdk.Message) *libports.CompletionResponse {
+	completion := &libports.CompletionResponse{
+		ID:           resp.ID,
+		Model:        string(resp.Model),
+		Message:      libports.Message{Role: "assistant", Content: extractContent(resp)},
+		FinishReason: finishReason(resp.StopReason),
+		Usage: libports.UsageInfo{
+			PromptTokens:     int(resp.Usage.InputTokens),
+			CompletionTokens: int(resp.Usage.OutputTokens),
+			TotalTokens:      int(resp.Usage.InputTokens + resp.Usage.OutputTokens),
+		},
+		CreatedAt: time.Now(),
+	}
+	for _, call := range extractToolCalls(resp) {
+		completion.ToolCalls = append(completion.ToolCalls, libports.ToolCall{ID: call.ID, Name: call.Name, Arguments: call.Input})
+	}
+	return completion
+}
+
+// finishReason maps a stop reason to its OpenAI equivalent; reasons
+// without one are kept
+func finishReason(reason anthropicsdk.StopReason) string {
+	switch reason {
+	case anthropicsdk.StopReasonEndTurn, anthropicsdk.StopReasonStopSequence:
+		return "stop"
+	case anthropicsdk.StopReasonMaxTokens:
+		return "length"
+	case anthropicsdk.StopReasonToolUse:
+		return "tool_calls"
+	default:
+		return string(reason)
+	}
+}
+
 // extractContent extracts text content from response
 func extractContent(resp *anthropicsdk.Message) string {
 	if len(resp.Content) == 0 {
@@ -138,3 +463,17 @@ func extractContent(resp *anthropicsdk.Message) string {
 
 	return ""
 }
+
+// extractToolCalls extracts the tool calls of a response
+func extractToolCalls(resp *anthropicsdk.Message) []domain.ToolCall {
+	var calls []domain.ToolCall
+	for _, block := range resp.Content {
+		if block.Type != "tool_use" {
+			continue
+		}
+		var input map[string]interface{}
+		_ = json.Unmarshal(block.Input, &input)
+		calls = append(calls, domain.ToolCall{ID: block.ID, Name: block.Name, Input: input})
+	}
+	return calls
+}
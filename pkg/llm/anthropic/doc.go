@@ -17,10 +17,48 @@
 //		log.Fatal(err)
 //	}
 //
-//	resp, err := client.GenerateCompletion(ctx, &domain.LLMRequest{
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
 //		Model: "claude-sonnet-4-20250514",
 //		Messages: []domain.Message{
 //			{Role: "user", Content: "Hello!"},
 //		},
 //	})
+//
+// CompleteWithTools takes the port's requests, with the tool calls and
+// results of earlier turns encoded by ports.ToolCallsMessage and
+// ports.ToolResultMessage, so it can drive agent.RunToolLoop:
+//
+//	resp, err := client.CompleteWithTools(ctx, req, tools)
+//	if len(resp.ToolCalls) > 0 {
+//		req.Messages = append(req.Messages, ports.ToolCallsMessage(resp))
+//		for _, call := range resp.ToolCalls {
+//			req.Messages = append(req.Messages, ports.ToolResultMessage(run(call)))
+//		}
+//		resp, err = client.CompleteWithTools(ctx, req, tools)
+//	}
+//
+// CompleteStructured gets JSON conforming to a schema by making it the
+// input schema of a tool Claude is forced to call (tool_choice), and
+// returns the call's input. Extended thinking doesn't allow forced tool
+// use, so structured requests are made without it.
+//
+// StreamEvents streams a response as typed events: thinking deltas (with
+// extended thinking enabled by SetThinkingBudget), text deltas, tool call
+// start, input deltas and stop, and a final message stop with the stop
+// reason and usage:
+//
+//	client.SetThinkingBudget(4096)
+//	events, err := client.StreamEvents(ctx, req)
+//	for event := range events {
+//		switch event.Type {
+//		case anthropic.StreamEventThinkingDelta:
+//			ui.Reasoning(event.Delta)
+//		case anthropic.StreamEventTextDelta:
+//			ui.Answer(event.Delta)
+//		case anthropic.StreamEventToolCallStop:
+//			run(event.ToolCall)
+//		case anthropic.StreamEventError:
+//			return event.Err
+//		}
+//	}
 package anthropic
@@ -0,0 +1,323 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
+	"go.uber.org/zap"
+)
+
+// StreamEventType is the kind of a StreamEvent
+type StreamEventType string
+
+const (
+	// StreamEventThinkingDelta carries reasoning text, with extended
+	// thinking enabled
+	StreamEventThinkingDelta StreamEventType = "thinking_delta"
+
+	// StreamEventTextDelta carries answer text
+	StreamEventTextDelta StreamEventType = "text_delta"
+
+	// StreamEventToolCallStart announces a tool call, with its ID and name
+	StreamEventToolCallStart StreamEventType = "tool_call_start"
+
+	// StreamEventToolCallDelta carries a fragment of a tool call's JSON input
+	StreamEventToolCallDelta StreamEventType = "tool_call_delta"
+
+	// StreamEventToolCallStop completes a tool call, with its parsed input
+	StreamEventToolCallStop StreamEventType = "tool_call_stop"
+
+	// StreamEventMessageStop ends a successful stream, with the stop reason
+	// and usage
+	StreamEventMessageStop StreamEventType = "message_stop"
+
+	// StreamEventError ends a failed stream
+	StreamEventError StreamEventType = "error"
+)
+
+// StreamEvent is an event of a streamed response. Events of the same
+// content block, e.g. the deltas of a tool call, share its Index.
+type StreamEvent struct {
+	Type  StreamEventType
+	Index int
+
+	// Delta is the text of thinking and text deltas, and the JSON fragment
+	// of tool call deltas
+	Delta string
+
+	// ToolCall is set on tool call start (ID and name) and stop (ID, name
+	// and input)
+	ToolCall *domain.ToolCall
+
+	// StopReason and Usage are set on message stop
+	StopReason string
+	Usage      *domain.Usage
+
+	// Err is set on error events
+	Err error
+}
+
+// StreamEvents streams a completion as typed events, keeping reasoning, text
+// and tool call progress apart so UIs can render them distinctly. The
+// channel is closed after a message stop or error event, or when ctx is
+// cancelled.
+func (c *Client) StreamEvents(ctx context.Context, llmReq *domain.LLMRequest) (<-chan StreamEvent, error) {
+	if len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	params, err := c.newParams(llmReq)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", llmReq.Model),
+		zap.Int("message_count", len(llmReq.Messages)))
+
+	stream := c.client.Messages.NewStreaming(ctx, params)
+	events := make(chan StreamEvent)
+
+	go func() {
+		defer close(events)
+		defer stream.Close()
+
+		send := func(event StreamEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var (
+			usage      domain.Usage
+			stopReason string
+			// Tool calls in progress and their input so far, by block index
+			toolCalls = make(map[int]*domain.ToolCall)
+			toolInput = make(map[int][]byte)
+		)
+
+		for stream.Next() {
+			switch event := stream.Current().AsAny().(type) {
+			case anthropicsdk.MessageStartEvent:
+				usage.InputTokens = int(event.Message.Usage.InputTokens)
+
+			case anthropicsdk.ContentBlockStartEvent:
+				index := int(event.Index)
+				if block, ok := event.ContentBlock.AsAny().(anthropicsdk.ToolUseBlock); ok {
+					call := &domain.ToolCall{ID: block.ID, Name: block.Name}
+					toolCalls[index] = call
+					if !send(StreamEvent{Type: StreamEventToolCallStart, Index: index, ToolCall: &domain.ToolCall{ID: call.ID, Name: call.Name}}) {
+						return
+					}
+				}
+
+			case anthropicsdk.ContentBlockDeltaEvent:
+				index := int(event.Index)
+				var out StreamEvent
+				switch delta := event.Delta.AsAny().(type) {
+				case anthropicsdk.TextDelta:
+					out = StreamEvent{Type: StreamEventTextDelta, Index: index, Delta: delta.Text}
+				case anthropicsdk.ThinkingDelta:
+					out = StreamEvent{Type: StreamEventThinkingDelta, Index: index, Delta: delta.Thinking}
+				case anthropicsdk.InputJSONDelta:
+					toolInput[index] = append(toolInput[index], delta.PartialJSON...)
+					out = StreamEvent{Type: StreamEventToolCallDelta, Index: index, Delta: delta.PartialJSON}
+				default:
+					// Signatures and citations aren't surfaced
+					continue
+				}
+				if !send(out) {
+					return
+				}
+
+			case anthropicsdk.ContentBlockStopEvent:
+				index := int(event.Index)
+				call, ok := toolCalls[index]
+				if !ok {
+					continue
+				}
+				if input := toolInput[index]; len(input) > 0 {
+					if err := json.Unmarshal(input, &call.Input); err != nil {
+						send(StreamEvent{Type: StreamEventError, Index: index, Err: fmt.Errorf("invalid input of tool call %s: %w", call.Name, err)})
+						return
+					}
+				}
+				if call.Input == nil {
+					call.Input = map[string]interface{}{}
+				}
+				delete(toolCalls, index)
+				if !send(StreamEvent{Type: StreamEventToolCallStop, Index: index, ToolCall: call}) {
+					return
+				}
+
+			case anthropicsdk.MessageDeltaEvent:
+				stopReason = string(event.Delta.StopReason)
+				usage.OutputTokens = int(event.Usage.OutputTokens)
+
+			case anthropicsdk.MessageStopEvent:
+				c.logger.Debug("completion streamed",
+					zap.Int("input_tokens", usage.InputTokens),
+					zap.Int("output_tokens", usage.OutputTokens))
+				send(StreamEvent{Type: StreamEventMessageStop, StopReason: stopReason, Usage: &usage})
+				return
+			}
+		}
+
+		err := stream.Err()
+		if err == nil {
+			err = fmt.Errorf("stream ended without message_stop")
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		c.logger.Error("API call failed", zap.Error(err))
+		send(StreamEvent{Type: StreamEventError, Err: fmt.Errorf("API call failed: %w", err)})
+	}()
+
+	return events, nil
+}
+
+// CompleteStream streams a completion with tools, if any
+// (ports.LLMStreamer interface). Text deltas and the input_json_delta
+// fragments of tool calls are sent as they arrive; the text, parsed tool
+// calls, finish reason and usage on the last chunk. Cancelling ctx closes
+// the stream, and ends it with the text generated so far.
+func (c *Client) CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan ports.StreamChunk, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	params, err := c.completionParams(req, tools, c.thinkingBudget)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	// The request is sent by the first Next, so errors before the stream
+	// starts are returned
+	stream := c.client.Messages.NewStreaming(ctx, params)
+	if !stream.Next() {
+		err := stream.Err()
+		if err == nil {
+			err = fmt.Errorf("empty stream")
+		}
+		_ = stream.Close()
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	chunks := make(chan ports.StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+
+		var content strings.Builder
+		send := func(chunk ports.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// The last chunk is sent even after a cancellation, with the text
+		// so far
+		finish := func(chunk ports.StreamChunk) {
+			chunk.Content = content.String()
+			chunk.Done = true
+			chunks <- chunk
+		}
+		cancelled := func() {
+			c.logger.Debug("stream cancelled", zap.Int("content_length", content.Len()))
+			finish(ports.StreamChunk{Cancelled: true})
+		}
+
+		var message anthropicsdk.Message
+		// Positions of the tool calls among the calls, by block index
+		toolCalls := make(map[int64]int)
+		for next := true; next; next = stream.Next() {
+			event := stream.Current()
+			if err := message.Accumulate(event); err != nil {
+				finish(ports.StreamChunk{Err: fmt.Errorf("invalid stream: %w", err)})
+				return
+			}
+
+			switch event := event.AsAny().(type) {
+			case anthropicsdk.ContentBlockStartEvent:
+				if block, ok := event.ContentBlock.AsAny().(anthropicsdk.ToolUseBlock); ok {
+					toolCalls[event.Index] = len(toolCalls)
+					delta := &ports.ToolCallDelta{Index: toolCalls[event.Index], ID: block.ID, Name: block.Name}
+					if !send(ports.StreamChunk{ToolCallDelta: delta}) {
+						cancelled()
+						return
+					}
+				}
+
+			case anthropicsdk.ContentBlockDeltaEvent:
+				var chunk ports.StreamChunk
+				switch delta := event.Delta.AsAny().(type) {
+				case anthropicsdk.TextDelta:
+					chunk.Delta = delta.Text
+					content.WriteString(delta.Text)
+				case anthropicsdk.InputJSONDelta:
+					if index, ok := toolCalls[event.Index]; ok && delta.PartialJSON != "" {
+						chunk.ToolCallDelta = &ports.ToolCallDelta{Index: index, Arguments: delta.PartialJSON}
+					}
+				}
+				if (chunk.Delta != "" || chunk.ToolCallDelta != nil) && !send(chunk) {
+					cancelled()
+					return
+				}
+
+			case anthropicsdk.MessageStopEvent:
+				completion := toCompletionResponse(&message)
+				c.logger.Debug("completion streamed",
+					zap.Int("tool_calls", len(completion.ToolCalls)),
+					zap.Int("input_tokens", completion.Usage.PromptTokens),
+					zap.Int("output_tokens", completion.Usage.CompletionTokens))
+				finish(ports.StreamChunk{
+					ToolCalls:    completion.ToolCalls,
+					FinishReason: completion.FinishReason,
+					Usage:        &completion.Usage,
+				})
+				return
+			}
+		}
+
+		if ctx.Err() != nil {
+			cancelled()
+			return
+		}
+		err := stream.Err()
+		if err == nil {
+			err = fmt.Errorf("stream ended without message_stop")
+		}
+		c.logger.Error("API call failed", zap.Error(err))
+		finish(ports.StreamChunk{Err: fmt.Errorf("API call failed: %w", err)})
+	}()
+
+	return chunks, nil
+}
+
+// StreamComplete streams the text of a completion, as libports'
+// CompletionChunk, through CompleteStream
+func (c *Client) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks, err := c.CompleteStream(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ports.CompletionChunks(ctx, chunks), nil
+}
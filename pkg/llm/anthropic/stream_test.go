@@ -0,0 +1,268 @@
+package anthropic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func newStreamClient(t *testing.T) (*Client, *testutil.AnthropicServer) {
+	t.Helper()
+	srv := testutil.NewAnthropicServer(t)
+	client, _ := NewClient("test-key", zap.NewNop())
+	client.SetBaseURL(srv.URL)
+	return client, srv
+}
+
+func collectEvents(t *testing.T, events <-chan StreamEvent) []StreamEvent {
+	t.Helper()
+	var collected []StreamEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return collected
+			}
+			collected = append(collected, event)
+		case <-timeout:
+			t.Fatalf("stream not closed after %d events", len(collected))
+		}
+	}
+}
+
+var streamRequest = &domain.LLMRequest{
+	Model:    "claude-sonnet-4-20250514",
+	Messages: []domain.Message{{Role: "user", Content: "What's the weather in Paris?"}},
+	Tools: []domain.Tool{{
+		Name:        "get_weather",
+		Description: "Get the weather of a city",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+			"required":   []string{"city"},
+		},
+	}},
+}
+
+func TestStreamEvents(t *testing.T) {
+	client, srv := newStreamClient(t)
+	srv.Reply(testutil.Reply{
+		Thinking:     []string{"The user wants ", "the weather."},
+		Chunks:       []string{"Let me ", "check."},
+		ToolCalls:    []testutil.ToolCall{{ID: "toolu_1", Name: "get_weather", Arguments: `{"city": "Paris"}`}},
+		InputTokens:  20,
+		OutputTokens: 15,
+	})
+
+	events, err := client.StreamEvents(context.Background(), streamRequest)
+	if err != nil {
+		t.Fatalf("StreamEvents() error = %v", err)
+	}
+	collected := collectEvents(t, events)
+
+	var types []StreamEventType
+	var thinking, text, input string
+	for _, event := range collected {
+		types = append(types, event.Type)
+		switch event.Type {
+		case StreamEventThinkingDelta:
+			thinking += event.Delta
+		case StreamEventTextDelta:
+			text += event.Delta
+		case StreamEventToolCallDelta:
+			input += event.Delta
+		}
+	}
+
+	want := []StreamEventType{
+		StreamEventThinkingDelta, StreamEventThinkingDelta,
+		StreamEventTextDelta, StreamEventTextDelta,
+		StreamEventToolCallStart, StreamEventToolCallDelta, StreamEventToolCallDelta, StreamEventToolCallStop,
+		StreamEventMessageStop,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("event types = %v, want %v", types, want)
+		}
+	}
+
+	if thinking != "The user wants the weather." || text != "Let me check." || input != `{"city": "Paris"}` {
+		t.Errorf("thinking = %q, text = %q, tool input = %q", thinking, text, input)
+	}
+
+	start := collected[4]
+	if start.ToolCall == nil || start.ToolCall.ID != "toolu_1" || start.ToolCall.Name != "get_weather" {
+		t.Errorf("tool call start = %+v, want toolu_1 get_weather", start.ToolCall)
+	}
+	stop := collected[7]
+	if stop.Index != start.Index || stop.ToolCall == nil || stop.ToolCall.Input["city"] != "Paris" {
+		t.Errorf("tool call stop = %+v (index %d), want Paris input at index %d", stop.ToolCall, stop.Index, start.Index)
+	}
+
+	end := collected[len(collected)-1]
+	if end.StopReason != "tool_use" || end.Usage == nil || end.Usage.InputTokens != 20 || end.Usage.OutputTokens != 15 {
+		t.Errorf("message stop = %+v, usage %+v, want tool_use with 20 input and 15 output tokens", end, end.Usage)
+	}
+
+	// Tools are sent with their input schema
+	last, _ := srv.LastRequest()
+	var body struct {
+		Stream bool `json:"stream"`
+		Tools  []struct {
+			Name        string `json:"name"`
+			InputSchema struct {
+				Type     string   `json:"type"`
+				Required []string `json:"required"`
+			} `json:"input_schema"`
+		} `json:"tools"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if !body.Stream || len(body.Tools) != 1 || body.Tools[0].Name != "get_weather" ||
+		body.Tools[0].InputSchema.Type != "object" || len(body.Tools[0].InputSchema.Required) != 1 {
+		t.Errorf("request = %+v, want a streamed request with the get_weather tool", body)
+	}
+}
+
+func TestStreamEventsError(t *testing.T) {
+	client, srv := newStreamClient(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Partial"}, ErrorType: "overloaded_error", Error: "Overloaded"})
+
+	events, err := client.StreamEvents(context.Background(), streamRequest)
+	if err != nil {
+		t.Fatalf("StreamEvents() error = %v", err)
+	}
+	collected := collectEvents(t, events)
+
+	if len(collected) != 2 || collected[0].Type != StreamEventTextDelta {
+		t.Fatalf("events = %+v, want a text delta and an error", collected)
+	}
+	if last := collected[1]; last.Type != StreamEventError || last.Err == nil {
+		t.Errorf("last event = %+v, want an error", last)
+	}
+}
+
+func TestStreamEventsCancel(t *testing.T) {
+	client, srv := newStreamClient(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"a", "b", "c", "d"}, Delay: 50 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.StreamEvents(ctx, streamRequest)
+	if err != nil {
+		t.Fatalf("StreamEvents() error = %v", err)
+	}
+
+	first := <-events
+	if first.Type != StreamEventTextDelta {
+		t.Fatalf("first event = %+v, want a text delta", first)
+	}
+	cancel()
+
+	// The channel closes without a message stop
+	for _, event := range collectEvents(t, events) {
+		if event.Type == StreamEventMessageStop {
+			t.Errorf("received %+v after cancel", event)
+		}
+	}
+}
+
+func TestStreamEventsInvalidRequest(t *testing.T) {
+	client, _ := newStreamClient(t)
+
+	if _, err := client.StreamEvents(context.Background(), &domain.LLMRequest{Model: "claude-sonnet-4-20250514"}); err == nil {
+		t.Error("StreamEvents() error = nil, want an error for a request without messages")
+	}
+}
+
+func TestCompleteStream(t *testing.T) {
+	client, srv := newStreamClient(t)
+	srv.Reply(testutil.Reply{
+		Chunks:       []string{"Let me ", "check."},
+		ToolCalls:    []testutil.ToolCall{{ID: "toolu_1", Name: "get_weather", Arguments: `{"city": "Paris"}`}},
+		InputTokens:  20,
+		OutputTokens: 15,
+	})
+
+	req := libports.CompletionRequest{Model: streamRequest.Model, Messages: []libports.Message{{Role: "user", Content: "What's the weather in Paris?"}}}
+	tools := []libports.Tool{libports.Tool(streamRequest.Tools[0])}
+	chunks, err := client.CompleteStream(context.Background(), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var text string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		text += chunk.Delta
+		last = chunk
+	}
+	if text != "Let me check." {
+		t.Errorf("text = %q, want the chunks", text)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "tool_calls" {
+		t.Fatalf("last chunk = %+v, want a tool_calls finish", last)
+	}
+	if len(last.ToolCalls) != 1 || last.ToolCalls[0].ID != "toolu_1" || last.ToolCalls[0].Arguments["city"] != "Paris" {
+		t.Errorf("tool calls = %+v, want get_weather(Paris)", last.ToolCalls)
+	}
+	if last.Usage == nil || last.Usage.PromptTokens != 20 || last.Usage.CompletionTokens != 15 || last.Usage.TotalTokens != 35 {
+		t.Errorf("usage = %+v, want 20 + 15 tokens", last.Usage)
+	}
+
+	srv.Reply(testutil.Reply{Status: 400, Error: "prompt is too long"})
+	if _, err := client.CompleteStream(context.Background(), req, nil); err == nil {
+		t.Error("CompleteStream() with a 400 reply succeeded")
+	}
+}
+
+func TestSetThinkingBudget(t *testing.T) {
+	client, srv := newStreamClient(t)
+	client.SetThinkingBudget(2048)
+	srv.Reply(testutil.Reply{Thinking: []string{"Hmm."}, Chunks: []string{"42"}})
+
+	resp, err := client.GenerateCompletion(context.Background(), &domain.LLMRequest{
+		Model:       "claude-sonnet-4-20250514",
+		Messages:    []domain.Message{{Role: "user", Content: "What is the answer?"}},
+		MaxTokens:   1000,
+		Temperature: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+	if content := resp.(*domain.LLMResponse).Content; content != "42" {
+		t.Errorf("Content = %q, want the text without thinking", content)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		MaxTokens   int      `json:"max_tokens"`
+		Temperature *float64 `json:"temperature"`
+		Thinking    struct {
+			Type         string `json:"type"`
+			BudgetTokens int    `json:"budget_tokens"`
+		} `json:"thinking"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.Thinking.Type != "enabled" || body.Thinking.BudgetTokens != 2048 {
+		t.Errorf("thinking = %+v, want enabled with 2048 tokens", body.Thinking)
+	}
+	if body.MaxTokens != 3048 {
+		t.Errorf("max_tokens = %d, want the budget plus the requested 1000", body.MaxTokens)
+	}
+	if body.Temperature != nil {
+		t.Errorf("temperature = %v, want none with thinking", *body.Temperature)
+	}
+}
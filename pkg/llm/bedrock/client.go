@@ -0,0 +1,561 @@
+package bedrock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"go.uber.org/zap"
+)
+
+// Runtime is the subset of the Bedrock Runtime API used by Client.
+// *bedrockruntime.Client implements it.
+type Runtime interface {
+	Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+	ConverseStream(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error)
+}
+
+// Client implements the LLMClient interface for the models of Amazon
+// Bedrock, through the Converse API
+type Client struct {
+	runtime   Runtime
+	logger    *zap.Logger
+	guardrail Guardrail
+}
+
+// Guardrail is an Amazon Bedrock guardrail checking the input and output of
+// requests
+type Guardrail struct {
+	// Identifier is the ID or ARN of the guardrail
+	Identifier string
+
+	// Version is the version of the guardrail, e.g. "1" or "DRAFT"
+	Version string
+
+	// Trace returns the guardrail's assessment with responses
+	Trace bool
+}
+
+// GuardrailResult is what the guardrail of a request did
+type GuardrailResult struct {
+	// Intervened is set when the guardrail blocked or masked the input or
+	// the output; the message of the completion is then the guardrail's
+	Intervened bool
+
+	// ActionReason explains the guardrail's action, with Trace enabled
+	ActionReason string
+
+	// Assessment is the guardrail's assessment of the input and output,
+	// with Trace enabled
+	Assessment *types.GuardrailTraceAssessment
+}
+
+// Completion is a completion with the result of the client's guardrail
+type Completion struct {
+	*libports.CompletionResponse
+
+	// Guardrail is nil without a guardrail
+	Guardrail *GuardrailResult
+}
+
+// NewClient creates a new Bedrock client
+func NewClient(runtime Runtime, logger *zap.Logger) *Client {
+	return &Client{
+		runtime: runtime,
+		logger:  logger,
+	}
+}
+
+// SetGuardrail applies guardrail to the requests of the client, whose
+// interventions end completions with the content_filter finish reason.
+// CompleteWithGuardrail returns its result. The zero Guardrail removes it.
+func (c *Client) SetGuardrail(guardrail Guardrail) {
+	c.guardrail = guardrail
+}
+
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
+}
+
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface), for models supporting tool use on Bedrock
+// (Claude, Llama 3.1 and later, Mistral Large, ...). Tool calls and results
+// of earlier turns, in messages built with ports.ToolCallsMessage and
+// ports.ToolResultMessage, are sent as toolUse and toolResult blocks.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	completion, err := c.CompleteWithGuardrail(ctx, req, tools)
+	if err != nil {
+		return nil, err
+	}
+	return completion.CompletionResponse, nil
+}
+
+// CompleteWithGuardrail performs a completion with tools, like
+// CompleteWithTools, returning the result of the guardrail set with
+// SetGuardrail along with it
+func (c *Client) CompleteWithGuardrail(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*Completion, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	input, err := c.converseInput(req, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.converse(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := toCompletionResponse(req.Model, out)
+	if err != nil {
+		return nil, err
+	}
+	completion := &Completion{CompletionResponse: resp}
+	if c.guardrail.Identifier != "" {
+		var trace *types.GuardrailTraceAssessment
+		if out.Trace != nil {
+			trace = out.Trace.Guardrail
+		}
+		completion.Guardrail = guardrailResult(out.StopReason, trace)
+	}
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens))
+
+	return completion, nil
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance (ports.LLMClient interface). The Converse API has no JSON
+// mode: the schema is the input schema of a single tool the model is
+// forced to call, and the call's input is the data returned. Only models
+// supporting a specific tool choice, such as Claude, accept it.
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
+
+	description, _ := schema["description"].(string)
+	if description == "" {
+		description = "Respond with data conforming to the input schema"
+	}
+	tool := libports.Tool{Name: structuredOutputTool, Description: description, Parameters: schema}
+
+	input, err := c.converseInput(req, []libports.Tool{tool})
+	if err != nil {
+		return nil, err
+	}
+	input.ToolConfig.ToolChoice = &types.ToolChoiceMemberTool{Value: types.SpecificToolChoice{Name: aws.String(structuredOutputTool)}}
+
+	out, err := c.converse(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	completion, err := toCompletionResponse(req.Model, out)
+	if err != nil {
+		return nil, err
+	}
+	structured := &libports.StructuredResponse{Usage: completion.Usage, CreatedAt: completion.CreatedAt}
+	for _, call := range completion.ToolCalls {
+		if call.Name == structuredOutputTool {
+			structured.Data = call.Arguments
+			break
+		}
+	}
+	if structured.Data == nil {
+		return nil, fmt.Errorf("no structured output in response (stop reason %q)", out.StopReason)
+	}
+
+	c.logger.Debug("structured completion generated",
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
+
+	return structured, nil
+}
+
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
+func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Generate generates a completion of a domain request
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion",
+		zap.String("model", llmReq.Model),
+		zap.Int("message_count", len(llmReq.Messages)))
+
+	req := libports.CompletionRequest{
+		Model:       llmReq.Model,
+		Messages:    ports.CompletionMessages(llmReq),
+		MaxTokens:   llmReq.MaxTokens,
+		Temperature: llmReq.Temperature,
+	}
+	tools := make([]libports.Tool, 0, len(llmReq.Tools))
+	for _, tool := range llmReq.Tools {
+		tools = append(tools, libports.Tool(tool))
+	}
+	input, err := c.converseInput(req, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.converse(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	completion, err := toCompletionResponse(llmReq.Model, out)
+	if err != nil {
+		return nil, err
+	}
+	llmResp := &domain.LLMResponse{
+		Content: completion.Message.Content,
+		Model:   llmReq.Model,
+		Usage: domain.Usage{
+			InputTokens:  completion.Usage.PromptTokens,
+			OutputTokens: completion.Usage.CompletionTokens,
+		},
+	}
+	for _, call := range completion.ToolCalls {
+		llmResp.ToolCalls = append(llmResp.ToolCalls, domain.ToolCall{ID: call.ID, Name: call.Name, Input: call.Arguments})
+	}
+
+	c.logger.Debug("completion generated",
+		zap.Int("input_tokens", llmResp.Usage.InputTokens),
+		zap.Int("output_tokens", llmResp.Usage.OutputTokens))
+
+	return llmResp, nil
+}
+
+// converse sends a Converse request, logging guardrail interventions
+func (c *Client) converse(ctx context.Context, input *bedrockruntime.ConverseInput) (*bedrockruntime.ConverseOutput, error) {
+	out, err := c.runtime.Converse(ctx, input)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	if out.StopReason == types.StopReasonGuardrailIntervened {
+		c.logger.Warn("guardrail intervened",
+			zap.String("model", aws.ToString(input.ModelId)),
+			zap.String("guardrail", c.guardrail.Identifier))
+	}
+	return out, nil
+}
+
+// guardrailResult returns the result of a guardrail from the stop reason
+// and trace of a response
+func guardrailResult(reason types.StopReason, trace *types.GuardrailTraceAssessment) *GuardrailResult {
+	result := &GuardrailResult{
+		Intervened: reason == types.StopReasonGuardrailIntervened,
+		Assessment: trace,
+	}
+	if trace != nil {
+		result.ActionReason = aws.ToString(trace.ActionReason)
+	}
+	return result
+}
+
+// Name of the tool CompleteStructured forces the model to call with its
+// answer
+const structuredOutputTool = "structured_output"
+
+// converseInput builds the Converse request of req with tools
+func (c *Client) converseInput(req libports.CompletionRequest, tools []libports.Tool) (*bedrockruntime.ConverseInput, error) {
+	system, messages := c.convertCompletionMessages(req.Messages)
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("%w: only system messages", ports.ErrInvalidRequest)
+	}
+
+	input := &bedrockruntime.ConverseInput{
+		ModelId:  aws.String(req.Model),
+		Messages: messages,
+	}
+	if len(system) > 0 {
+		if supportsSystemPrompt(req.Model) {
+			input.System = system
+		} else {
+			prependSystemPrompt(input, system)
+		}
+	}
+
+	if req.MaxTokens > 0 || req.Temperature > 0 || req.TopP > 0 || len(req.Stop) > 0 {
+		input.InferenceConfig = &types.InferenceConfiguration{StopSequences: req.Stop}
+		if req.MaxTokens > 0 {
+			input.InferenceConfig.MaxTokens = aws.Int32(int32(req.MaxTokens))
+		}
+		if req.Temperature > 0 {
+			input.InferenceConfig.Temperature = aws.Float32(float32(req.Temperature))
+		}
+		if req.TopP > 0 {
+			input.InferenceConfig.TopP = aws.Float32(float32(req.TopP))
+		}
+	}
+
+	if len(tools) > 0 {
+		input.ToolConfig = &types.ToolConfiguration{Tools: convertTools(tools)}
+	}
+
+	if c.guardrail.Identifier != "" {
+		input.GuardrailConfig = &types.GuardrailConfiguration{
+			GuardrailIdentifier: aws.String(c.guardrail.Identifier),
+			GuardrailVersion:    aws.String(c.guardrail.Version),
+			Trace:               guardrailTrace(c.guardrail.Trace),
+		}
+	}
+	return input, nil
+}
+
+// guardrailTrace returns the trace setting of a guardrail
+func guardrailTrace(enabled bool) types.GuardrailTrace {
+	if enabled {
+		return types.GuardrailTraceEnabled
+	}
+	return types.GuardrailTraceDisabled
+}
+
+// Geographic prefixes of the IDs of cross-region inference profiles
+var inferenceProfileRegions = []string{"us.", "us-gov.", "eu.", "apac.", "jp.", "au.", "ca.", "global."}
+
+// foundationModel returns the ID of the foundation model of model, which may
+// be a model ID or ARN, or the ID or ARN of a cross-region inference
+// profile, e.g. us.anthropic.claude-3-5-sonnet-20240620-v1:0. The ARN of an
+// application inference profile names no model and is returned as is.
+func foundationModel(model string) string {
+	if strings.HasPrefix(model, "arn:") {
+		for _, resource := range []string{":foundation-model/", ":inference-profile/"} {
+			if _, id, ok := strings.Cut(model, resource); ok {
+				model = id
+				break
+			}
+		}
+	}
+	for _, prefix := range inferenceProfileRegions {
+		if id, ok := strings.CutPrefix(model, prefix); ok {
+			return id
+		}
+	}
+	return model
+}
+
+// supportsSystemPrompt reports whether model takes a system prompt; Titan
+// text models don't
+func supportsSystemPrompt(model string) bool {
+	return !strings.HasPrefix(foundationModel(model), "amazon.titan-text")
+}
+
+// prependSystemPrompt sends the system prompt of models without one at the
+// start of the first message, a user message
+func prependSystemPrompt(input *bedrockruntime.ConverseInput, system []types.SystemContentBlock) {
+	var texts []string
+	for _, block := range system {
+		if text, ok := block.(*types.SystemContentBlockMemberText); ok {
+			texts = append(texts, text.Value)
+		}
+	}
+	first := &input.Messages[0]
+	first.Content = append([]types.ContentBlock{&types.ContentBlockMemberText{Value: strings.Join(texts, "\n\n")}}, first.Content...)
+}
+
+// convertTools converts tools to Converse tool specifications, with their
+// parameters as the JSON schema of the tool input
+func convertTools(tools []libports.Tool) []types.Tool {
+	converted := make([]types.Tool, 0, len(tools))
+	for _, tool := range tools {
+		schema := map[string]interface{}(tool.Parameters)
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		spec := types.ToolSpecification{
+			Name:        aws.String(tool.Name),
+			InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(schema)},
+		}
+		if tool.Description != "" {
+			spec.Description = aws.String(tool.Description)
+		}
+		converted = append(converted, &types.ToolMemberToolSpec{Value: spec})
+	}
+	return converted
+}
+
+// convertCompletionMessages converts messages to Converse system prompt
+// and messages. System messages make up the system prompt; unknown roles
+// are sent as user messages. Messages built with ports.ToolCallsMessage
+// become assistant messages with toolUse blocks, and results built with
+// ports.ToolResultMessage toolResult blocks. Consecutive messages of the
+// same role are merged, as the API expects roles to alternate and the
+// results of all the calls of a turn together, and empty text is dropped,
+// as the API rejects blank text blocks.
+func (c *Client) convertCompletionMessages(msgs []libports.Message) ([]types.SystemContentBlock, []types.Message) {
+	var system []types.SystemContentBlock
+	messages := make([]types.Message, 0, len(msgs))
+
+	add := func(role types.ConversationRole, blocks ...types.ContentBlock) {
+		if len(blocks) == 0 {
+			return
+		}
+		if n := len(messages); n > 0 && messages[n-1].Role == role {
+			messages[n-1].Content = append(messages[n-1].Content, blocks...)
+			return
+		}
+		messages = append(messages, types.Message{Role: role, Content: blocks})
+	}
+	text := func(content string) []types.ContentBlock {
+		if strings.TrimSpace(content) == "" {
+			return nil
+		}
+		return []types.ContentBlock{&types.ContentBlockMemberText{Value: content}}
+	}
+
+	for _, msg := range msgs {
+		if content, calls, ok := ports.ParseToolCallsMessage(msg); ok {
+			blocks := text(content)
+			for _, call := range calls {
+				input := call.Arguments
+				if input == nil {
+					input = map[string]interface{}{}
+				}
+				blocks = append(blocks, &types.ContentBlockMemberToolUse{Value: types.ToolUseBlock{
+					ToolUseId: aws.String(call.ID),
+					Name:      aws.String(call.Name),
+					Input:     document.NewLazyDocument(input),
+				}})
+			}
+			add(types.ConversationRoleAssistant, blocks...)
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			block := types.ToolResultBlock{
+				ToolUseId: aws.String(result.ToolCallID),
+				Content:   []types.ToolResultContentBlock{&types.ToolResultContentBlockMemberText{Value: result.Content}},
+				Status:    types.ToolResultStatusSuccess,
+			}
+			if result.IsError {
+				block.Status = types.ToolResultStatusError
+			}
+			add(types.ConversationRoleUser, &types.ContentBlockMemberToolResult{Value: block})
+			continue
+		}
+
+		switch msg.Role {
+		case "user":
+			add(types.ConversationRoleUser, text(msg.Content)...)
+		case "assistant":
+			add(types.ConversationRoleAssistant, text(msg.Content)...)
+		case "system":
+			if strings.TrimSpace(msg.Content) != "" {
+				system = append(system, &types.SystemContentBlockMemberText{Value: msg.Content})
+			}
+		default:
+			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
+			add(types.ConversationRoleUser, text(msg.Content)...)
+		}
+	}
+
+	return system, messages
+}
+
+// toCompletionResponse converts a Converse response to the port's
+// representation, with the stop reason in the OpenAI vocabulary the gateway
+// and other adapters use
+func toCompletionResponse(model string, out *bedrockruntime.ConverseOutput) (*libports.CompletionResponse, error) {
+	completion := &libports.CompletionResponse{
+		Model:        model,
+		Message:      libports.Message{Role: "assistant"},
+		FinishReason: finishReason(out.StopReason),
+		Usage:        usage(out.Usage),
+		CreatedAt:    time.Now(),
+	}
+
+	message, ok := out.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return completion, nil
+	}
+	var content strings.Builder
+	for _, block := range message.Value.Content {
+		switch block := block.(type) {
+		case *types.ContentBlockMemberText:
+			content.WriteString(block.Value)
+		case *types.ContentBlockMemberToolUse:
+			var arguments map[string]interface{}
+			if block.Value.Input != nil {
+				if err := block.Value.Input.UnmarshalSmithyDocument(&arguments); err != nil {
+					return nil, fmt.Errorf("invalid input of tool call %s: %w", aws.ToString(block.Value.Name), err)
+				}
+			}
+			completion.ToolCalls = append(completion.ToolCalls, libports.ToolCall{
+				ID:        aws.ToString(block.Value.ToolUseId),
+				Name:      aws.ToString(block.Value.Name),
+				Arguments: arguments,
+			})
+		}
+	}
+	completion.Message.Content = content.String()
+	return completion, nil
+}
+
+// usage converts the token usage of a response
+func usage(u *types.TokenUsage) libports.UsageInfo {
+	if u == nil {
+		return libports.UsageInfo{}
+	}
+	return libports.UsageInfo{
+		PromptTokens:     int(aws.ToInt32(u.InputTokens)),
+		CompletionTokens: int(aws.ToInt32(u.OutputTokens)),
+		TotalTokens:      int(aws.ToInt32(u.InputTokens) + aws.ToInt32(u.OutputTokens)),
+	}
+}
+
+// finishReason maps a stop reason to its OpenAI equivalent; reasons
+// without one are kept
+func finishReason(reason types.StopReason) string {
+	switch reason {
+	case types.StopReasonEndTurn, types.StopReasonStopSequence:
+		return "stop"
+	case types.StopReasonMaxTokens:
+		return "length"
+	case types.StopReasonToolUse:
+		return "tool_calls"
+	case types.StopReasonContentFiltered, types.StopReasonGuardrailIntervened:
+		return "content_filter"
+	default:
+		return string(reason)
+	}
+}
@@ -0,0 +1,547 @@
+package bedrock
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"go.uber.org/zap"
+)
+
+const testModel = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+
+// newTestClient returns a client of the fake Bedrock Runtime API srv
+func newTestClient(srv *testutil.BedrockServer) *Client {
+	runtime := bedrockruntime.New(bedrockruntime.Options{
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDTEST", "test-secret", ""),
+		BaseEndpoint: aws.String(srv.URL),
+	})
+	return NewClient(runtime, zap.NewNop())
+}
+
+func TestGenerateCompletion(t *testing.T) {
+	t.Run("invalid request type", func(t *testing.T) {
+		client := newTestClient(testutil.NewBedrockServer(t))
+
+		_, err := client.GenerateCompletion(context.Background(), "invalid")
+		if err == nil {
+			t.Error("GenerateCompletion() expected error for invalid request type")
+		}
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		srv := testutil.NewBedrockServer(t)
+		srv.Reply(testutil.Reply{Chunks: []string{"Hello, World!"}, InputTokens: 12, OutputTokens: 4})
+		client := newTestClient(srv)
+
+		req := &domain.LLMRequest{
+			Model:       testModel,
+			System:      "Be brief",
+			Messages:    []domain.Message{{Role: "user", Content: "Hello"}},
+			MaxTokens:   100,
+			Temperature: 0.5,
+		}
+
+		resp, err := client.GenerateCompletion(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		llmResp := resp.(*domain.LLMResponse)
+		if llmResp.Content != "Hello, World!" {
+			t.Errorf("Content = %q, want %q", llmResp.Content, "Hello, World!")
+		}
+		if llmResp.Usage.InputTokens != 12 || llmResp.Usage.OutputTokens != 4 {
+			t.Errorf("Usage = %+v, want 12 input and 4 output tokens", llmResp.Usage)
+		}
+
+		last, ok := srv.LastRequest()
+		if !ok {
+			t.Fatal("no request received")
+		}
+		if got := last.Header.Get("Authorization"); !strings.Contains(got, "Credential=AKIDTEST/") {
+			t.Errorf("Authorization = %q, want a SigV4 signature with the access key", got)
+		}
+		if want := "/model/" + testModel + "/converse"; last.Path != want {
+			t.Errorf("path = %s, want %s", last.Path, want)
+		}
+		var body struct {
+			System []struct {
+				Text string `json:"text"`
+			} `json:"system"`
+			InferenceConfig struct {
+				MaxTokens   int     `json:"maxTokens"`
+				Temperature float64 `json:"temperature"`
+			} `json:"inferenceConfig"`
+		}
+		if err := last.JSON(&body); err != nil {
+			t.Fatalf("request body: %v", err)
+		}
+		if len(body.System) != 1 || body.System[0].Text != "Be brief" {
+			t.Errorf("system = %+v, want Be brief", body.System)
+		}
+		if body.InferenceConfig.MaxTokens != 100 || body.InferenceConfig.Temperature != 0.5 {
+			t.Errorf("inferenceConfig = %+v", body.InferenceConfig)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := testutil.NewBedrockServer(t)
+		srv.Reply(testutil.Reply{Status: http.StatusBadRequest, Error: "maxTokens: must be positive"})
+		client := newTestClient(srv)
+
+		req := &domain.LLMRequest{
+			Model:    testModel,
+			Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		}
+		_, err := client.GenerateCompletion(context.Background(), req)
+		if err == nil || !strings.Contains(err.Error(), "maxTokens: must be positive") {
+			t.Errorf("GenerateCompletion() error = %v, want the API error", err)
+		}
+	})
+}
+
+func TestConformance(t *testing.T) {
+	srv := testutil.NewBedrockServer(t)
+	client := newTestClient(srv)
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: testModel, Reply: srv.Reply})
+}
+
+// Integration test - only runs with BEDROCK_TEST set, with credentials from
+// the default AWS chain
+func TestGenerateCompletion_Integration(t *testing.T) {
+	if os.Getenv("BEDROCK_TEST") == "" {
+		t.Skip("BEDROCK_TEST not set, skipping integration test (set BEDROCK_TEST=1 to enable)")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to load AWS config: %v", err)
+	}
+	client := NewClient(bedrockruntime.NewFromConfig(cfg), zap.NewNop())
+
+	req := &domain.LLMRequest{
+		Model: testModel,
+		Messages: []domain.Message{
+			{Role: "user", Content: "Say 'Hello, World!' and nothing else."},
+		},
+		MaxTokens:   50,
+		Temperature: 0.0,
+	}
+
+	resp, err := client.GenerateCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+
+	llmResp, ok := resp.(*domain.LLMResponse)
+	if !ok {
+		t.Fatal("Response is not *domain.LLMResponse")
+	}
+
+	if llmResp.Content == "" {
+		t.Error("Response content is empty")
+	}
+
+	if llmResp.Usage.InputTokens == 0 {
+		t.Error("Input tokens is 0")
+	}
+
+	t.Logf("Response: %s", llmResp.Content)
+	t.Logf("Usage: %d input tokens, %d output tokens",
+		llmResp.Usage.InputTokens,
+		llmResp.Usage.OutputTokens)
+}
+
+func FuzzConvertMessages(f *testing.F) {
+	llmtest.AddFuzzSeeds(f)
+	client := NewClient(nil, zap.NewNop())
+
+	f.Fuzz(func(t *testing.T, system, messages string) {
+		req := llmtest.FuzzRequest(system, messages)
+		systemBlocks, params := client.convertCompletionMessages(ports.CompletionMessages(req))
+
+		var converted []libports.Message
+		for _, block := range systemBlocks {
+			converted = append(converted, libports.Message{Role: "system", Content: block.(*types.SystemContentBlockMemberText).Value})
+		}
+		for i, param := range params {
+			if i > 0 && param.Role == params[i-1].Role {
+				t.Fatalf("messages %d and %d are both %s messages", i-1, i, param.Role)
+			}
+			for _, block := range param.Content {
+				text, ok := block.(*types.ContentBlockMemberText)
+				if !ok {
+					t.Fatalf("message content = %+v, want text blocks", param.Content)
+				}
+				converted = append(converted, libports.Message{Role: string(param.Role), Content: text.Value})
+			}
+		}
+
+		// Blank messages are dropped, as the API rejects blank text
+		kept := &domain.LLMRequest{}
+		if strings.TrimSpace(req.System) != "" {
+			kept.System = req.System
+		}
+		for _, msg := range req.Messages {
+			if strings.TrimSpace(msg.Content) != "" {
+				kept.Messages = append(kept.Messages, msg)
+			}
+		}
+		llmtest.CheckConversion(t, kept, converted, "system", "user", "assistant")
+	})
+}
+
+func TestRequestGolden(t *testing.T) {
+	for _, tc := range llmtest.GoldenCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := testutil.NewBedrockServer(t)
+			client := newTestClient(srv)
+
+			if _, err := client.GenerateCompletion(context.Background(), tc.Request); err != nil {
+				t.Fatalf("GenerateCompletion() error = %v", err)
+			}
+			last, ok := srv.LastRequest()
+			if !ok {
+				t.Fatal("no request received")
+			}
+			testutil.GoldenJSON(t, tc.Name, last.Body)
+		})
+	}
+}
+
+func TestCompleteWithToolsMultiTurn(t *testing.T) {
+	srv := testutil.NewBedrockServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Sunny in Paris, rainy in London."}, InputTokens: 40, OutputTokens: 9})
+	client := newTestClient(srv)
+
+	first := &libports.CompletionResponse{
+		Message: libports.Message{Role: "assistant", Content: "Checking both."},
+		ToolCalls: []libports.ToolCall{
+			{ID: "tooluse_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}},
+			{ID: "tooluse_2", Name: "get_weather", Arguments: map[string]interface{}{"city": "London"}},
+		},
+	}
+	req := libports.CompletionRequest{
+		Model: testModel,
+		Messages: []libports.Message{
+			{Role: "system", Content: "Be brief"},
+			{Role: "user", Content: "Weather in Paris and London?"},
+			ports.ToolCallsMessage(first),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "tooluse_1", Name: "get_weather", Content: "sunny"}),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "tooluse_2", Name: "get_weather", Content: "service down", IsError: true}),
+		},
+	}
+	tools := []libports.Tool{{Name: "get_weather", Description: "Current weather", Parameters: map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+		"required":   []interface{}{"city"},
+	}}}
+
+	resp, err := client.CompleteWithTools(context.Background(), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Sunny in Paris, rainy in London." || resp.FinishReason != "stop" || len(resp.ToolCalls) != 0 {
+		t.Errorf("response = %+v, want the final answer", resp)
+	}
+	if resp.Usage.TotalTokens != 49 {
+		t.Errorf("Usage = %+v, want 49 total tokens", resp.Usage)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content []struct {
+				Text    *string `json:"text"`
+				ToolUse *struct {
+					ToolUseID string                 `json:"toolUseId"`
+					Name      string                 `json:"name"`
+					Input     map[string]interface{} `json:"input"`
+				} `json:"toolUse"`
+				ToolResult *struct {
+					ToolUseID string `json:"toolUseId"`
+					Status    string `json:"status"`
+					Content   []struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"toolResult"`
+			} `json:"content"`
+		} `json:"messages"`
+		ToolConfig struct {
+			Tools []struct {
+				ToolSpec struct {
+					Name        string `json:"name"`
+					InputSchema struct {
+						JSON struct {
+							Required []string `json:"required"`
+						} `json:"json"`
+					} `json:"inputSchema"`
+				} `json:"toolSpec"`
+			} `json:"tools"`
+		} `json:"toolConfig"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if tools := body.ToolConfig.Tools; len(tools) != 1 || tools[0].ToolSpec.Name != "get_weather" || len(tools[0].ToolSpec.InputSchema.JSON.Required) != 1 {
+		t.Errorf("tools = %+v, want get_weather", tools)
+	}
+	if len(body.Messages) != 3 {
+		t.Fatalf("got %d messages, want user, assistant and the results together", len(body.Messages))
+	}
+
+	assistant := body.Messages[1]
+	if assistant.Role != "assistant" || len(assistant.Content) != 3 {
+		t.Fatalf("assistant message = %+v, want text and two toolUse blocks", assistant)
+	}
+	if use := assistant.Content[1].ToolUse; use == nil || use.ToolUseID != "tooluse_1" || use.Input["city"] != "Paris" {
+		t.Errorf("first toolUse = %+v", use)
+	}
+
+	results := body.Messages[2]
+	if results.Role != "user" || len(results.Content) != 2 {
+		t.Fatalf("results message = %+v, want two toolResult blocks", results)
+	}
+	if r := results.Content[1].ToolResult; r == nil || r.ToolUseID != "tooluse_2" || r.Status != "error" || r.Content[0].Text != "service down" {
+		t.Errorf("second toolResult = %+v, want an error result for tooluse_2", r)
+	}
+}
+
+func TestTitanSystemPrompt(t *testing.T) {
+	srv := testutil.NewBedrockServer(t)
+	client := newTestClient(srv)
+
+	_, err := client.Generate(context.Background(), &domain.LLMRequest{
+		Model:    "amazon.titan-text-premier-v1:0",
+		System:   "Be brief",
+		Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		System   []interface{} `json:"system"`
+		Messages []struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.System != nil {
+		t.Errorf("system = %+v, want none for Titan", body.System)
+	}
+	if len(body.Messages) != 1 || len(body.Messages[0].Content) != 2 || body.Messages[0].Content[0].Text != "Be brief" {
+		t.Errorf("messages = %+v, want the system prompt before the user message", body.Messages)
+	}
+}
+
+func TestCompleteStructured(t *testing.T) {
+	srv := testutil.NewBedrockServer(t)
+	srv.Reply(
+		testutil.Reply{ToolCalls: []testutil.ToolCall{{Name: structuredOutputTool, Arguments: `{"name":"Ada Lovelace","born":1815}`}}, InputTokens: 30, OutputTokens: 12},
+		testutil.Reply{StopReason: "max_tokens", Chunks: []string{"Ada"}, ToolCalls: []testutil.ToolCall{{Name: "other"}}},
+	)
+	client := newTestClient(srv)
+
+	schema := libports.JSONSchema{
+		"type":       "object",
+		"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "born": map[string]interface{}{"type": "integer"}},
+		"required":   []string{"name", "born"},
+	}
+	req := libports.CompletionRequest{
+		Model:    testModel,
+		Messages: []libports.Message{{Role: "user", Content: "Who wrote the first program?"}},
+	}
+
+	resp, err := client.CompleteStructured(context.Background(), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if resp.Data["name"] != "Ada Lovelace" || resp.Data["born"] != float64(1815) {
+		t.Errorf("Data = %+v", resp.Data)
+	}
+	if resp.Usage.TotalTokens != 42 {
+		t.Errorf("Usage = %+v, want 42 total tokens", resp.Usage)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		ToolConfig struct {
+			ToolChoice struct {
+				Tool struct {
+					Name string `json:"name"`
+				} `json:"tool"`
+			} `json:"toolChoice"`
+		} `json:"toolConfig"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.ToolConfig.ToolChoice.Tool.Name != structuredOutputTool {
+		t.Errorf("toolChoice = %+v, want the structured output tool", body.ToolConfig.ToolChoice)
+	}
+
+	_, err = client.CompleteStructured(context.Background(), req, schema)
+	if err == nil || !strings.Contains(err.Error(), "max_tokens") {
+		t.Errorf("CompleteStructured() without the tool call error = %v, want the stop reason", err)
+	}
+}
+
+func TestFinishReason(t *testing.T) {
+	tests := map[types.StopReason]string{
+		types.StopReasonEndTurn:             "stop",
+		types.StopReasonStopSequence:        "stop",
+		types.StopReasonMaxTokens:           "length",
+		types.StopReasonToolUse:             "tool_calls",
+		types.StopReasonGuardrailIntervened: "content_filter",
+		"model_context_window_exceeded":     "model_context_window_exceeded",
+	}
+	for reason, want := range tests {
+		if got := finishReason(reason); got != want {
+			t.Errorf("finishReason(%q) = %q, want %q", reason, got, want)
+		}
+	}
+}
+
+func TestGuardrail(t *testing.T) {
+	srv := testutil.NewBedrockServer(t)
+	srv.Reply(
+		testutil.Reply{Chunks: []string{"Hello!"}},
+		testutil.Reply{StopReason: "guardrail_intervened", Chunks: []string{"Sorry, I can't help with that."}},
+	)
+	client := newTestClient(srv)
+	client.SetGuardrail(Guardrail{Identifier: "gr-test", Version: "1", Trace: true})
+
+	req := libports.CompletionRequest{Model: testModel, Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+	completion, err := client.CompleteWithGuardrail(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteWithGuardrail() error = %v", err)
+	}
+	if completion.Message.Content != "Hello!" || completion.FinishReason != "stop" {
+		t.Errorf("completion = %+v, want the reply", completion.CompletionResponse)
+	}
+	if g := completion.Guardrail; g == nil || g.Intervened || g.ActionReason != "No action." || g.Assessment == nil {
+		t.Errorf("Guardrail = %+v, want an assessment without intervention", g)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		GuardrailConfig struct {
+			GuardrailIdentifier string `json:"guardrailIdentifier"`
+			GuardrailVersion    string `json:"guardrailVersion"`
+			Trace               string `json:"trace"`
+		} `json:"guardrailConfig"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if g := body.GuardrailConfig; g.GuardrailIdentifier != "gr-test" || g.GuardrailVersion != "1" || g.Trace != "enabled" {
+		t.Errorf("guardrailConfig = %+v, want gr-test version 1 with its trace", g)
+	}
+
+	completion, err = client.CompleteWithGuardrail(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteWithGuardrail() error = %v", err)
+	}
+	if completion.FinishReason != "content_filter" || completion.Message.Content != "Sorry, I can't help with that." {
+		t.Errorf("completion = %+v, want the guardrail's message", completion.CompletionResponse)
+	}
+	g := completion.Guardrail
+	if g == nil || !g.Intervened || g.ActionReason != "Guardrail blocked." {
+		t.Fatalf("Guardrail = %+v, want an intervention", g)
+	}
+	policy := g.Assessment.InputAssessment["gr-test"].ContentPolicy
+	if policy == nil || len(policy.Filters) != 1 || policy.Filters[0].Action != types.GuardrailContentPolicyActionBlocked {
+		t.Errorf("content policy = %+v, want a blocking filter", policy)
+	}
+
+	// Without a guardrail, there's neither configuration nor result
+	client.SetGuardrail(Guardrail{})
+	completion, err = client.CompleteWithGuardrail(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteWithGuardrail() error = %v", err)
+	}
+	if completion.Guardrail != nil {
+		t.Errorf("Guardrail = %+v, want nil without a guardrail", completion.Guardrail)
+	}
+	last, _ = srv.LastRequest()
+	if strings.Contains(string(last.Body), "guardrailConfig") {
+		t.Errorf("request body = %s, want no guardrailConfig", last.Body)
+	}
+}
+
+func TestInferenceProfiles(t *testing.T) {
+	tests := map[string]string{
+		"profile ID":  "us.anthropic.claude-3-5-sonnet-20240620-v1:0",
+		"profile ARN": "arn:aws:bedrock:us-east-1:123456789012:inference-profile/us.anthropic.claude-3-5-sonnet-20240620-v1:0",
+	}
+	for name, model := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := testutil.NewBedrockServer(t)
+			srv.Reply(testutil.Reply{Chunks: []string{"Hello!"}})
+			client := newTestClient(srv)
+
+			resp, err := client.Complete(context.Background(), libports.CompletionRequest{
+				Model:    model,
+				Messages: []libports.Message{{Role: "system", Content: "Be brief"}, {Role: "user", Content: "Hello"}},
+			})
+			if err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if resp.Message.Content != "Hello!" || resp.Model != model {
+				t.Errorf("response = %+v, want the reply of %s", resp, model)
+			}
+
+			last, _ := srv.LastRequest()
+			if want := "/model/" + model + "/converse"; last.Path != want {
+				t.Errorf("path = %s, want %s", last.Path, want)
+			}
+			var body struct {
+				System []struct {
+					Text string `json:"text"`
+				} `json:"system"`
+			}
+			if err := last.JSON(&body); err != nil {
+				t.Fatalf("request body: %v", err)
+			}
+			if len(body.System) != 1 || body.System[0].Text != "Be brief" {
+				t.Errorf("system = %+v, want Be brief", body.System)
+			}
+		})
+	}
+}
+
+func TestFoundationModel(t *testing.T) {
+	tests := map[string]string{
+		testModel:                           testModel,
+		"eu.meta.llama3-2-3b-instruct-v1:0": "meta.llama3-2-3b-instruct-v1:0",
+		"arn:aws:bedrock:us-east-1::foundation-model/amazon.titan-text-premier-v1:0":        "amazon.titan-text-premier-v1:0",
+		"arn:aws:bedrock:eu-west-1:123456789012:inference-profile/eu.amazon.nova-pro-v1:0":  "amazon.nova-pro-v1:0",
+		"arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/a1b2c3d4e5f6": "arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/a1b2c3d4e5f6",
+	}
+	for model, want := range tests {
+		if got := foundationModel(model); got != want {
+			t.Errorf("foundationModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Package bedrock implements the LLM client adapter for Amazon Bedrock.
+//
+// This adapter implements the ports.LLMClient interface defined in dago-libs
+// through the Bedrock Runtime Converse API, which takes the same requests
+// for all the text models of Bedrock, e.g.:
+//   - anthropic.claude-3-5-sonnet-20240620-v1:0
+//   - meta.llama3-1-70b-instruct-v1:0
+//   - amazon.titan-text-premier-v1:0
+//
+// Models may also be given as ARNs, or as the IDs or ARNs of inference
+// profiles, which route requests across regions, e.g.
+// us.anthropic.claude-3-5-sonnet-20240620-v1:0.
+//
+// Titan text models take no system prompt: it is sent at the start of the
+// first user message instead.
+//
+// Usage, with credentials from the standard AWS chain (environment, shared
+// config and credentials files, IAM roles):
+//
+//	import "github.com/aescanero/dago-adapters/pkg/llm/bedrock"
+//
+//	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client := bedrock.NewClient(bedrockruntime.NewFromConfig(cfg), logger)
+//
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
+//		Model: "anthropic.claude-3-5-sonnet-20240620-v1:0",
+//		Messages: []domain.Message{
+//			{Role: "user", Content: "Hello!"},
+//		},
+//	})
+//
+// CompleteWithTools takes the port's requests, with the tool calls and
+// results of earlier turns encoded by ports.ToolCallsMessage and
+// ports.ToolResultMessage, sent as toolUse and toolResult blocks. Tool use
+// depends on the model: Claude, Llama 3.1 and later, and Mistral Large
+// support it; Titan text models don't.
+//
+// CompleteStructured gets JSON conforming to a schema by making it the
+// input schema of a tool the model is forced to call, and returns the
+// call's input. Forcing a specific tool is supported by Claude models.
+//
+// CompleteStream streams a response through ConverseStream, with text
+// deltas and tool call input fragments.
+//
+// SetGuardrail applies a Bedrock guardrail to all requests. Its
+// interventions end completions with the content_filter finish reason, and
+// the guardrail's message; CompleteWithGuardrail also returns whether it
+// intervened, and its assessment when its trace is enabled:
+//
+//	client.SetGuardrail(bedrock.Guardrail{Identifier: "gr-abc123", Version: "1", Trace: true})
+//	completion, err := client.CompleteWithGuardrail(ctx, req, nil)
+//	if err == nil && completion.Guardrail.Intervened {
+//		log.Printf("blocked: %s", completion.Guardrail.ActionReason)
+//	}
+package bedrock
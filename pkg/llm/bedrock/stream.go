@@ -0,0 +1,190 @@
+package bedrock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"go.uber.org/zap"
+)
+
+// CompleteStream streams a completion with tools, if any
+// (ports.LLMStreamer interface), through ConverseStream. Text deltas and the
+// input fragments of tool calls are sent as they arrive; the text, parsed
+// tool calls, finish reason and usage on the last chunk. The interventions
+// of the client's guardrail end the stream with the content_filter finish
+// reason. Cancelling ctx closes the stream, and ends it with the text
+// generated so far.
+func (c *Client) CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan ports.StreamChunk, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	input, err := c.converseInput(req, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	streamInput := &bedrockruntime.ConverseStreamInput{
+		ModelId:         input.ModelId,
+		Messages:        input.Messages,
+		System:          input.System,
+		InferenceConfig: input.InferenceConfig,
+		ToolConfig:      input.ToolConfig,
+	}
+	if guardrail := input.GuardrailConfig; guardrail != nil {
+		streamInput.GuardrailConfig = &types.GuardrailStreamConfiguration{
+			GuardrailIdentifier: guardrail.GuardrailIdentifier,
+			GuardrailVersion:    guardrail.GuardrailVersion,
+			Trace:               guardrail.Trace,
+		}
+	}
+
+	out, err := c.runtime.ConverseStream(ctx, streamInput)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	stream := out.GetStream()
+	chunks := make(chan ports.StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+
+		var content strings.Builder
+		send := func(chunk ports.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// The last chunk is sent even after a cancellation, with the text
+		// so far
+		finish := func(chunk ports.StreamChunk) {
+			chunk.Content = content.String()
+			chunk.Done = true
+			chunks <- chunk
+		}
+		cancelled := func() {
+			c.logger.Debug("stream cancelled", zap.Int("content_length", content.Len()))
+			finish(ports.StreamChunk{Cancelled: true})
+		}
+
+		var calls []libports.ToolCall
+		var arguments []strings.Builder
+		// Positions of the tool calls among the calls, by block index
+		toolCalls := make(map[int32]int)
+		var stopReason types.StopReason
+		var usageInfo *libports.UsageInfo
+		for event := range stream.Events() {
+			var chunk ports.StreamChunk
+			switch event := event.(type) {
+			case *types.ConverseStreamOutputMemberContentBlockStart:
+				start, ok := event.Value.Start.(*types.ContentBlockStartMemberToolUse)
+				if !ok {
+					continue
+				}
+				index := len(calls)
+				toolCalls[aws.ToInt32(event.Value.ContentBlockIndex)] = index
+				calls = append(calls, libports.ToolCall{
+					ID:   aws.ToString(start.Value.ToolUseId),
+					Name: aws.ToString(start.Value.Name),
+				})
+				arguments = append(arguments, strings.Builder{})
+				chunk.ToolCallDelta = &ports.ToolCallDelta{Index: index, ID: calls[index].ID, Name: calls[index].Name}
+
+			case *types.ConverseStreamOutputMemberContentBlockDelta:
+				switch delta := event.Value.Delta.(type) {
+				case *types.ContentBlockDeltaMemberText:
+					chunk.Delta = delta.Value
+					content.WriteString(delta.Value)
+				case *types.ContentBlockDeltaMemberToolUse:
+					index, ok := toolCalls[aws.ToInt32(event.Value.ContentBlockIndex)]
+					if part := aws.ToString(delta.Value.Input); ok && part != "" {
+						arguments[index].WriteString(part)
+						chunk.ToolCallDelta = &ports.ToolCallDelta{Index: index, Arguments: part}
+					}
+				}
+
+			case *types.ConverseStreamOutputMemberMessageStop:
+				stopReason = event.Value.StopReason
+
+			case *types.ConverseStreamOutputMemberMetadata:
+				u := usage(event.Value.Usage)
+				usageInfo = &u
+			}
+
+			if (chunk.Delta != "" || chunk.ToolCallDelta != nil) && !send(chunk) {
+				cancelled()
+				return
+			}
+		}
+
+		if ctx.Err() != nil {
+			cancelled()
+			return
+		}
+		err := stream.Err()
+		if err == nil && stopReason == "" {
+			err = fmt.Errorf("stream ended without messageStop")
+		}
+		if err != nil {
+			c.logger.Error("API call failed", zap.Error(err))
+			finish(ports.StreamChunk{Err: fmt.Errorf("API call failed: %w", err)})
+			return
+		}
+
+		for i := range calls {
+			if arguments[i].Len() == 0 {
+				continue
+			}
+			if err := json.Unmarshal([]byte(arguments[i].String()), &calls[i].Arguments); err != nil {
+				finish(ports.StreamChunk{Err: fmt.Errorf("invalid input of tool call %s: %w", calls[i].Name, err)})
+				return
+			}
+		}
+		if usageInfo == nil {
+			usageInfo = &libports.UsageInfo{}
+		}
+		if stopReason == types.StopReasonGuardrailIntervened {
+			c.logger.Warn("guardrail intervened",
+				zap.String("model", req.Model),
+				zap.String("guardrail", c.guardrail.Identifier))
+		}
+		c.logger.Debug("completion streamed",
+			zap.Int("tool_calls", len(calls)),
+			zap.Int("input_tokens", usageInfo.PromptTokens),
+			zap.Int("output_tokens", usageInfo.CompletionTokens))
+		finish(ports.StreamChunk{
+			ToolCalls:    calls,
+			FinishReason: finishReason(stopReason),
+			Usage:        usageInfo,
+		})
+	}()
+
+	return chunks, nil
+}
+
+// StreamComplete streams the text of a completion, as libports'
+// CompletionChunk, through CompleteStream
+func (c *Client) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks, err := c.CompleteStream(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ports.CompletionChunks(ctx, chunks), nil
+}
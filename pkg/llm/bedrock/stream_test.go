@@ -0,0 +1,103 @@
+package bedrock
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+func TestCompleteStream(t *testing.T) {
+	srv := testutil.NewBedrockServer(t)
+	srv.Reply(testutil.Reply{
+		Chunks: []string{"Checking ", "both."},
+		ToolCalls: []testutil.ToolCall{
+			{Name: "get_weather", Arguments: `{"city": "Paris"}`},
+			{Name: "get_weather", Arguments: `{"city": "London"}`},
+		},
+		InputTokens:  25,
+		OutputTokens: 18,
+	})
+	client := newTestClient(srv)
+
+	req := libports.CompletionRequest{Model: testModel, Messages: []libports.Message{{Role: "user", Content: "Weather in Paris and London?"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var text string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		text += chunk.Delta
+		last = chunk
+	}
+	if text != "Checking both." {
+		t.Errorf("text = %q, want the chunks", text)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "tool_calls" {
+		t.Fatalf("last chunk = %+v, want a tool_calls finish", last)
+	}
+	if len(last.ToolCalls) != 2 || last.ToolCalls[0].ID != "tooluse_test_0" || last.ToolCalls[1].Arguments["city"] != "London" {
+		t.Errorf("tool calls = %+v, want the two calls with their arguments", last.ToolCalls)
+	}
+	if last.Usage == nil || last.Usage.PromptTokens != 25 || last.Usage.CompletionTokens != 18 || last.Usage.TotalTokens != 43 {
+		t.Errorf("usage = %+v, want 25 + 18 tokens", last.Usage)
+	}
+
+	sent, _ := srv.LastRequest()
+	if want := "/model/" + testModel + "/converse-stream"; sent.Path != want {
+		t.Errorf("path = %s, want %s", sent.Path, want)
+	}
+
+	// Exceptions after the stream started end it
+	srv.Reply(testutil.Reply{Chunks: []string{"Hel"}, Error: "overloaded"})
+	chunks, err = client.CompleteStream(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+	for chunk := range chunks {
+		last = chunk
+	}
+	if !last.Done || last.Err == nil || !strings.Contains(last.Err.Error(), "overloaded") {
+		t.Errorf("last chunk = %+v, want the exception", last)
+	}
+}
+
+func TestCompleteStreamGuardrail(t *testing.T) {
+	srv := testutil.NewBedrockServer(t)
+	srv.Reply(testutil.Reply{StopReason: "guardrail_intervened", Chunks: []string{"Sorry, I can't help with that."}})
+	client := newTestClient(srv)
+	client.SetGuardrail(Guardrail{Identifier: "gr-test", Version: "DRAFT"})
+
+	req := libports.CompletionRequest{Model: testModel, Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		last = chunk
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "content_filter" {
+		t.Errorf("last chunk = %+v, want a content_filter finish", last)
+	}
+
+	sent, _ := srv.LastRequest()
+	var body struct {
+		GuardrailConfig struct {
+			GuardrailIdentifier string `json:"guardrailIdentifier"`
+			GuardrailVersion    string `json:"guardrailVersion"`
+			Trace               string `json:"trace"`
+		} `json:"guardrailConfig"`
+	}
+	if err := sent.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if g := body.GuardrailConfig; g.GuardrailIdentifier != "gr-test" || g.GuardrailVersion != "DRAFT" || g.Trace != "disabled" {
+		t.Errorf("guardrailConfig = %+v, want gr-test DRAFT without trace", g)
+	}
+}
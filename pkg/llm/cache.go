@@ -0,0 +1,132 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/cache"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// CachedClient wraps an LLM client, answering repeated requests from a
+// cache. Requests are keyed by their full content (messages, model,
+// sampling parameters, tools and schema), so only identical requests hit.
+// Cache sampled completions only where a repeated answer is acceptable,
+// e.g. with Temperature zero. GenerateCompletion is not cached.
+type CachedClient struct {
+	client libports.LLMClient
+	cache  ports.Cache
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+// NewCachedClient creates a client caching the responses of client in c
+// for ttl; zero keeps them until evicted
+func NewCachedClient(client libports.LLMClient, c ports.Cache, ttl time.Duration, logger *zap.Logger) *CachedClient {
+	return &CachedClient{
+		client: client,
+		cache:  c,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Complete performs a standard text completion, from the cache when the
+// request was seen before (ports.LLMClient interface)
+func (c *CachedClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	var resp libports.CompletionResponse
+	err := c.load(ctx, "llm:complete:", req, &resp, func(ctx context.Context) (interface{}, error) {
+		return c.client.Complete(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CompleteWithTools performs a completion with tool calling support, from
+// the cache when the request and tools were seen before (ports.LLMClient
+// interface)
+func (c *CachedClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	key := struct {
+		Request libports.CompletionRequest `json:"request"`
+		Tools   []libports.Tool            `json:"tools"`
+	}{req, tools}
+
+	var resp libports.CompletionResponse
+	err := c.load(ctx, "llm:tools:", key, &resp, func(ctx context.Context) (interface{}, error) {
+		return c.client.CompleteWithTools(ctx, req, tools)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance, from the cache when the request and schema were seen before
+// (ports.LLMClient interface)
+func (c *CachedClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	key := struct {
+		Request libports.CompletionRequest `json:"request"`
+		Schema  libports.JSONSchema        `json:"schema"`
+	}{req, schema}
+
+	var resp libports.StructuredResponse
+	err := c.load(ctx, "llm:structured:", key, &resp, func(ctx context.Context) (interface{}, error) {
+		return c.client.CompleteStructured(ctx, req, schema)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GenerateCompletion generates a completion using domain.LLMRequest
+// (compatibility method), without caching
+func (c *CachedClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	return c.client.GenerateCompletion(ctx, req)
+}
+
+// Close closes the wrapped client if it has a Close method
+func (c *CachedClient) Close() error {
+	if closer, ok := c.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// load decodes the cached response to a request into out, calling
+// complete on a miss
+func (c *CachedClient) load(ctx context.Context, prefix string, request, out interface{}, complete func(ctx context.Context) (interface{}, error)) error {
+	key, err := cache.Key(prefix, request)
+	if err != nil {
+		return err
+	}
+
+	hit := true
+	data, err := c.cache.GetOrLoad(ctx, key, c.ttl, func(ctx context.Context) ([]byte, error) {
+		hit = false
+		resp, err := complete(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal cached response: %w", err)
+	}
+
+	if hit {
+		c.logger.Debug("LLM response served from cache", zap.String("key", key))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/cache/lru"
+	rediscache "github.com/aescanero/dago-adapters/pkg/cache/redis"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// benchRequest returns a conversation of n messages of size bytes
+func benchRequest(n, size int) libports.CompletionRequest {
+	req := libports.CompletionRequest{Model: "gpt-4o-mini"}
+	for i := 0; i < n; i++ {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		req.Messages = append(req.Messages, libports.Message{Role: role, Content: strings.Repeat("x", size)})
+	}
+	return req
+}
+
+// BenchmarkCachedClient measures the overhead CachedClient adds over the
+// wrapped client: hashing the request into a key, and on hits reading and
+// decoding the cached response instead of calling the provider. The wrapped
+// client answers instantly, so the numbers are pure overhead.
+func BenchmarkCachedClient(b *testing.B) {
+	ctx := context.Background()
+
+	mr := miniredis.RunT(b)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	b.Cleanup(func() { _ = client.Close() })
+
+	caches := []struct {
+		name  string
+		cache func() *CachedClient
+	}{
+		{"lru", func() *CachedClient {
+			return NewCachedClient(&countingClient{}, lru.NewCache(1000), 0, zap.NewNop())
+		}},
+		{"redis", func() *CachedClient {
+			return NewCachedClient(&countingClient{}, rediscache.NewCache(client, zap.NewNop()), 0, zap.NewNop())
+		}},
+	}
+
+	for _, size := range []struct{ messages, bytes int }{{1, 100}, {20, 2000}} {
+		req := benchRequest(size.messages, size.bytes)
+		name := fmt.Sprintf("%dx%dB", size.messages, size.bytes)
+
+		b.Run("direct/"+name, func(b *testing.B) {
+			inner := &countingClient{}
+			for b.Loop() {
+				if _, err := inner.Complete(ctx, req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		for _, c := range caches {
+			b.Run(c.name+"/hit/"+name, func(b *testing.B) {
+				cached := c.cache()
+				if _, err := cached.Complete(ctx, req); err != nil {
+					b.Fatal(err)
+				}
+				for b.Loop() {
+					if _, err := cached.Complete(ctx, req); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+
+			b.Run(c.name+"/miss/"+name, func(b *testing.B) {
+				cached := c.cache()
+				req := benchRequest(size.messages, size.bytes)
+				i := 0
+				for b.Loop() {
+					// A different model misses without copying the messages
+					req.Model = fmt.Sprintf("model-%d", i)
+					i++
+					if _, err := cached.Complete(ctx, req); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/cache/lru"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// countingClient answers every completion with the number of calls so far
+type countingClient struct {
+	libports.LLMClient
+	calls int
+}
+
+func (c *countingClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	c.calls++
+	return &libports.CompletionResponse{
+		Model:   req.Model,
+		Message: libports.Message{Role: "assistant", Content: req.Messages[0].Content},
+		Usage:   libports.UsageInfo{TotalTokens: c.calls},
+	}, nil
+}
+
+func (c *countingClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	c.calls++
+	return &libports.StructuredResponse{Data: map[string]interface{}{"type": schema["type"]}}, nil
+}
+
+func TestCachedClient(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingClient{}
+	client := NewCachedClient(inner, lru.NewCache(10), 0, zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "gpt-4o-mini", Messages: []libports.Message{{Role: "user", Content: "hi"}}}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Complete(ctx, req)
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		if resp.Message.Content != "hi" || resp.Usage.TotalTokens != 1 {
+			t.Errorf("Complete() = %+v", resp)
+		}
+	}
+
+	// Any difference in the request misses
+	req.Temperature = 0.5
+	if _, err := client.Complete(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("client called %d times, want 2", inner.calls)
+	}
+
+	for _, schemaType := range []string{"object", "object", "array"} {
+		resp, err := client.CompleteStructured(ctx, req, libports.JSONSchema{"type": schemaType})
+		if err != nil || resp.Data["type"] != schemaType {
+			t.Errorf("CompleteStructured() = %+v, %v", resp, err)
+		}
+	}
+	if inner.calls != 4 {
+		t.Errorf("client called %d times, want 4", inner.calls)
+	}
+}
@@ -0,0 +1,545 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// DefaultBaseURL is the endpoint of the Cohere Chat API, v1
+const DefaultBaseURL = "https://api.cohere.com/v1"
+
+// Roles of the chat history
+const (
+	roleUser    = "USER"
+	roleChatbot = "CHATBOT"
+	roleSystem  = "SYSTEM"
+	roleTool    = "TOOL"
+)
+
+// Client implements the LLMClient interface for the Cohere Command models,
+// through the v1 Chat API, as Cohere has no Go SDK
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Cohere client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+// chatRequest is a request of the Chat API. The last turn is sent as
+// Message, or as ToolResults when it answers tool calls, after ChatHistory.
+type chatRequest struct {
+	Model            string          `json:"model,omitempty"`
+	Message          string          `json:"message"`
+	Preamble         string          `json:"preamble,omitempty"`
+	ChatHistory      []chatMessage   `json:"chat_history,omitempty"`
+	Tools            []tool          `json:"tools,omitempty"`
+	ToolResults      []toolResult    `json:"tool_results,omitempty"`
+	ResponseFormat   *responseFormat `json:"response_format,omitempty"`
+	Stream           bool            `json:"stream,omitempty"`
+	MaxTokens        int             `json:"max_tokens,omitempty"`
+	Temperature      float64         `json:"temperature,omitempty"`
+	P                float64         `json:"p,omitempty"`
+	StopSequences    []string        `json:"stop_sequences,omitempty"`
+	PresencePenalty  float64         `json:"presence_penalty,omitempty"`
+	FrequencyPenalty float64         `json:"frequency_penalty,omitempty"`
+}
+
+// chatMessage is a turn of the chat history
+type chatMessage struct {
+	Role        string       `json:"role"`
+	Message     string       `json:"message,omitempty"`
+	ToolCalls   []toolCall   `json:"tool_calls,omitempty"`
+	ToolResults []toolResult `json:"tool_results,omitempty"`
+}
+
+// toolCall is a call of a tool. Cohere doesn't identify calls: results
+// repeat the call they answer.
+type toolCall struct {
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+type toolResult struct {
+	Call    toolCall                 `json:"call"`
+	Outputs []map[string]interface{} `json:"outputs"`
+}
+
+type tool struct {
+	Name                 string                         `json:"name"`
+	Description          string                         `json:"description"`
+	ParameterDefinitions map[string]parameterDefinition `json:"parameter_definitions,omitempty"`
+}
+
+type parameterDefinition struct {
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type responseFormat struct {
+	Type   string              `json:"type"`
+	Schema libports.JSONSchema `json:"schema,omitempty"`
+}
+
+// chatResponse is a response of the Chat API, also the response of the
+// stream-end event when streaming
+type chatResponse struct {
+	ResponseID   string     `json:"response_id"`
+	GenerationID string     `json:"generation_id"`
+	Text         string     `json:"text"`
+	FinishReason string     `json:"finish_reason"`
+	ToolCalls    []toolCall `json:"tool_calls"`
+	Meta         struct {
+		BilledUnits struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
+}
+
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface). Tool calls and results of earlier turns, in
+// messages built with ports.ToolCallsMessage and ports.ToolResultMessage,
+// are sent as CHATBOT tool calls and TOOL results, those answering the last
+// calls as the request's tool results. Cohere doesn't identify tool calls,
+// so the tool calls returned are given random IDs.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Tools = convertTools(tools)
+
+	resp, err := c.chat(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := toCompletionResponse(req.Model, resp)
+
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens))
+
+	return completion, nil
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance (ports.LLMClient interface), with a json_object response
+// format constrained by the schema
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.ResponseFormat = &responseFormat{Type: "json_object", Schema: schema}
+
+	resp, err := c.chat(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := toCompletionResponse(req.Model, resp)
+	structured := &libports.StructuredResponse{
+		Usage:     completion.Usage,
+		CreatedAt: completion.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(completion.Message.Content), &structured.Data); err != nil {
+		return nil, fmt.Errorf("invalid structured response (finish reason %q): %w", completion.FinishReason, err)
+	}
+
+	c.logger.Debug("structured completion generated",
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
+
+	return structured, nil
+}
+
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
+func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Generate generates a completion of a domain request
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion",
+		zap.String("model", llmReq.Model),
+		zap.Int("message_count", len(llmReq.Messages)))
+
+	chatReq := c.chatRequest(libports.CompletionRequest{
+		Model:       llmReq.Model,
+		Messages:    ports.CompletionMessages(llmReq),
+		MaxTokens:   llmReq.MaxTokens,
+		Temperature: llmReq.Temperature,
+	})
+	if len(llmReq.Tools) > 0 {
+		tools := make([]libports.Tool, 0, len(llmReq.Tools))
+		for _, tool := range llmReq.Tools {
+			tools = append(tools, libports.Tool(tool))
+		}
+		chatReq.Tools = convertTools(tools)
+	}
+
+	resp, err := c.chat(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := toCompletionResponse(llmReq.Model, resp)
+	llmResp := &domain.LLMResponse{
+		Content: completion.Message.Content,
+		Model:   completion.Model,
+		Usage: domain.Usage{
+			InputTokens:  completion.Usage.PromptTokens,
+			OutputTokens: completion.Usage.CompletionTokens,
+		},
+	}
+	for _, call := range completion.ToolCalls {
+		llmResp.ToolCalls = append(llmResp.ToolCalls, domain.ToolCall{ID: call.ID, Name: call.Name, Input: call.Arguments})
+	}
+
+	c.logger.Debug("completion generated",
+		zap.Int("input_tokens", llmResp.Usage.InputTokens),
+		zap.Int("output_tokens", llmResp.Usage.OutputTokens))
+
+	return llmResp, nil
+}
+
+// chatRequest returns the chat request of req, without tools or response
+// format. The last turn of the history is taken out as the message, or the
+// tool results, sent; the message is empty when the conversation doesn't
+// end with a user turn.
+func (c *Client) chatRequest(req libports.CompletionRequest) chatRequest {
+	preamble, history := c.convertCompletionMessages(req.Messages)
+	chatReq := chatRequest{
+		Model:            req.Model,
+		Preamble:         preamble,
+		MaxTokens:        req.MaxTokens,
+		Temperature:      req.Temperature,
+		P:                req.TopP,
+		StopSequences:    req.Stop,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+	}
+	if n := len(history); n > 0 {
+		switch last := history[n-1]; last.Role {
+		case roleUser:
+			chatReq.Message = last.Message
+			history = history[:n-1]
+		case roleTool:
+			chatReq.ToolResults = last.ToolResults
+			history = history[:n-1]
+		}
+	}
+	chatReq.ChatHistory = history
+	return chatReq
+}
+
+// chat sends a chat request, and decodes its response
+func (c *Client) chat(ctx context.Context, chatReq chatRequest) (*chatResponse, error) {
+	httpResp, err := c.post(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	var resp chatResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &resp, nil
+}
+
+// post sends a chat request, returning the response of a successful one
+// with its body to be closed
+func (c *Client) post(ctx context.Context, chatReq chatRequest) (*http.Response, error) {
+	data, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer func() { _ = httpResp.Body.Close() }()
+		body, _ := io.ReadAll(httpResp.Body)
+		err := fmt.Errorf("API call failed: %w", &ports.StatusError{StatusCode: httpResp.StatusCode, Message: strings.TrimSpace(string(body))})
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, err
+	}
+	return httpResp, nil
+}
+
+// convertCompletionMessages converts messages to a Cohere preamble and chat
+// history. A leading system message is the preamble, and later ones SYSTEM
+// turns. Messages built with ports.ToolCallsMessage become CHATBOT turns
+// with tool calls, and consecutive results built with
+// ports.ToolResultMessage a TOOL turn, each result repeating its call and
+// holding the content as its "result" output, or "error" if marked as an
+// error. Unknown roles are sent as user messages.
+func (c *Client) convertCompletionMessages(msgs []libports.Message) (string, []chatMessage) {
+	var preamble string
+	var history []chatMessage
+
+	calls := map[string]toolCall{}
+	for i, msg := range msgs {
+		if content, toolCalls, ok := ports.ParseToolCallsMessage(msg); ok {
+			turn := chatMessage{Role: roleChatbot, Message: content}
+			for _, call := range toolCalls {
+				converted := toolCall{Name: call.Name, Parameters: call.Arguments}
+				if converted.Parameters == nil {
+					converted.Parameters = map[string]interface{}{}
+				}
+				calls[call.ID] = converted
+				turn.ToolCalls = append(turn.ToolCalls, converted)
+			}
+			history = append(history, turn)
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			call, ok := calls[result.ToolCallID]
+			if !ok {
+				call = toolCall{Name: result.Name, Parameters: map[string]interface{}{}}
+			}
+			output := map[string]interface{}{"result": result.Content}
+			if result.IsError {
+				output = map[string]interface{}{"error": result.Content}
+			}
+			converted := toolResult{Call: call, Outputs: []map[string]interface{}{output}}
+
+			if n := len(history); n > 0 && history[n-1].Role == roleTool {
+				history[n-1].ToolResults = append(history[n-1].ToolResults, converted)
+			} else {
+				history = append(history, chatMessage{Role: roleTool, ToolResults: []toolResult{converted}})
+			}
+			continue
+		}
+
+		switch msg.Role {
+		case "user":
+			history = append(history, chatMessage{Role: roleUser, Message: msg.Content})
+		case "assistant":
+			history = append(history, chatMessage{Role: roleChatbot, Message: msg.Content})
+		case "system":
+			if i == 0 && msg.Content != "" {
+				preamble = msg.Content
+				continue
+			}
+			history = append(history, chatMessage{Role: roleSystem, Message: msg.Content})
+		default:
+			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
+			history = append(history, chatMessage{Role: roleUser, Message: msg.Content})
+		}
+	}
+	return preamble, history
+}
+
+// convertTools converts tools to Cohere tools, whose parameters are the
+// properties of the schema, typed with Python type names. Nested
+// schemas are reduced to their type, as Cohere doesn't take them.
+func convertTools(tools []libports.Tool) []tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	converted := make([]tool, 0, len(tools))
+	for _, t := range tools {
+		converted = append(converted, tool{
+			Name:                 t.Name,
+			Description:          t.Description,
+			ParameterDefinitions: parameterDefinitions(t.Parameters),
+		})
+	}
+	return converted
+}
+
+// parameterDefinitions converts the properties of an object schema
+func parameterDefinitions(schema map[string]interface{}) map[string]parameterDefinition {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return nil
+	}
+
+	required := map[string]bool{}
+	switch names := schema["required"].(type) {
+	case []string:
+		for _, name := range names {
+			required[name] = true
+		}
+	case []interface{}:
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	definitions := make(map[string]parameterDefinition, len(properties))
+	for name, property := range properties {
+		property, _ := property.(map[string]interface{})
+		description, _ := property["description"].(string)
+		definitions[name] = parameterDefinition{
+			Description: description,
+			Type:        pythonType(property),
+			Required:    required[name],
+		}
+	}
+	return definitions
+}
+
+// pythonType returns the Python type name of a JSON schema, "str" if it
+// has no known type
+func pythonType(schema map[string]interface{}) string {
+	switch schema["type"] {
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "object":
+		return "Dict"
+	case "array":
+		if items, ok := schema["items"].(map[string]interface{}); ok && items["type"] != nil {
+			return "List[" + pythonType(items) + "]"
+		}
+		return "List"
+	default:
+		return "str"
+	}
+}
+
+// toCompletionResponse converts a Cohere response, giving its tool calls
+// random IDs
+func toCompletionResponse(model string, resp *chatResponse) *libports.CompletionResponse {
+	completion := &libports.CompletionResponse{
+		ID:        resp.ResponseID,
+		Model:     model,
+		Message:   libports.Message{Role: "assistant", Content: resp.Text},
+		Usage:     usageInfo(resp),
+		CreatedAt: time.Now(),
+	}
+	for _, call := range resp.ToolCalls {
+		completion.ToolCalls = append(completion.ToolCalls, libports.ToolCall{
+			ID:        newToolCallID(),
+			Name:      call.Name,
+			Arguments: call.Parameters,
+		})
+	}
+	completion.FinishReason = finishReason(resp.FinishReason, len(completion.ToolCalls) > 0)
+	return completion
+}
+
+// usageInfo returns the billed tokens of a response
+func usageInfo(resp *chatResponse) libports.UsageInfo {
+	units := resp.Meta.BilledUnits
+	return libports.UsageInfo{
+		PromptTokens:     units.InputTokens,
+		CompletionTokens: units.OutputTokens,
+		TotalTokens:      units.InputTokens + units.OutputTokens,
+	}
+}
+
+// finishReason maps Cohere finish reasons to the OpenAI ones used across
+// adapters. Cohere completes responses calling tools, which are
+// "tool_calls"; reasons OpenAI has no equivalent for are "other".
+func finishReason(reason string, toolCalls bool) string {
+	switch reason {
+	case "COMPLETE":
+		if toolCalls {
+			return "tool_calls"
+		}
+		return "stop"
+	case "STOP_SEQUENCE":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "ERROR_TOXIC":
+		return "content_filter"
+	case "":
+		return ""
+	default:
+		return "other"
+	}
+}
+
+// newToolCallID returns a random tool call ID
+func newToolCallID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "call_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,368 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client == nil {
+		t.Error("NewClient() returned nil client")
+	}
+}
+
+func TestGenerateCompletion(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("invalid request type", func(t *testing.T) {
+		client, _ := NewClient("test-key", "", logger)
+
+		_, err := client.GenerateCompletion(context.Background(), "invalid")
+		if err == nil {
+			t.Error("GenerateCompletion() expected error for invalid request type")
+		}
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		srv := testutil.NewCohereServer(t)
+		srv.Reply(testutil.Reply{Chunks: []string{"Bonjour !"}, InputTokens: 12, OutputTokens: 4})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
+
+		req := &domain.LLMRequest{
+			Model:     "command-r-plus",
+			System:    "Be brief",
+			Messages:  []domain.Message{{Role: "user", Content: "Hello"}},
+			MaxTokens: 100,
+		}
+		resp, err := client.GenerateCompletion(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		llmResp := resp.(*domain.LLMResponse)
+		if llmResp.Content != "Bonjour !" || llmResp.Model != "command-r-plus" {
+			t.Errorf("response = %+v", llmResp)
+		}
+		if llmResp.Usage.InputTokens != 12 || llmResp.Usage.OutputTokens != 4 {
+			t.Errorf("Usage = %+v, want 12 input and 4 output tokens", llmResp.Usage)
+		}
+
+		last, _ := srv.LastRequest()
+		if got := last.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q, want the API key", got)
+		}
+		var body struct {
+			MaxTokens   int               `json:"max_tokens"`
+			Preamble    string            `json:"preamble"`
+			Message     string            `json:"message"`
+			ChatHistory []json.RawMessage `json:"chat_history"`
+		}
+		if err := last.JSON(&body); err != nil {
+			t.Fatalf("request body: %v", err)
+		}
+		if body.MaxTokens != 100 || body.Preamble != "Be brief" || body.Message != "Hello" || len(body.ChatHistory) != 0 {
+			t.Errorf("request = %+v", body)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := testutil.NewCohereServer(t)
+		srv.Reply(testutil.Reply{Status: http.StatusBadRequest, Error: "invalid request: message must be at least 1 token long"})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
+
+		_, err := client.GenerateCompletion(context.Background(), &domain.LLMRequest{
+			Model:    "command-r-plus",
+			Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "message must be at least 1 token long") {
+			t.Errorf("GenerateCompletion() error = %v, want the API error", err)
+		}
+	})
+}
+
+func TestConformance(t *testing.T) {
+	srv := testutil.NewCohereServer(t)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "command-r-plus", Reply: srv.Reply})
+}
+
+// Integration test - only runs with COHERE_API_KEY environment variable
+func TestGenerateCompletion_Integration(t *testing.T) {
+	apiKey := os.Getenv("COHERE_API_KEY")
+	if apiKey == "" {
+		t.Skip("COHERE_API_KEY not set, skipping integration test")
+	}
+
+	client, err := NewClient(apiKey, "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := &domain.LLMRequest{
+		Model: "command-r",
+		Messages: []domain.Message{
+			{Role: "user", Content: "Say 'Hello, World!' and nothing else."},
+		},
+		MaxTokens:   50,
+		Temperature: 0.0,
+	}
+
+	resp, err := client.GenerateCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+
+	llmResp, ok := resp.(*domain.LLMResponse)
+	if !ok {
+		t.Fatal("Response is not *domain.LLMResponse")
+	}
+
+	if llmResp.Content == "" {
+		t.Error("Response content is empty")
+	}
+
+	if llmResp.Usage.InputTokens == 0 {
+		t.Error("Input tokens is 0")
+	}
+
+	t.Logf("Response: %s", llmResp.Content)
+	t.Logf("Usage: %d input tokens, %d output tokens",
+		llmResp.Usage.InputTokens,
+		llmResp.Usage.OutputTokens)
+}
+
+func FuzzConvertMessages(f *testing.F) {
+	llmtest.AddFuzzSeeds(f)
+	client, _ := NewClient("test-key", "", zap.NewNop())
+
+	f.Fuzz(func(t *testing.T, system, messages string) {
+		req := llmtest.FuzzRequest(system, messages)
+		preamble, history := client.convertCompletionMessages(ports.CompletionMessages(req))
+
+		var converted []libports.Message
+		if preamble != "" {
+			converted = append(converted, libports.Message{Role: roleSystem, Content: preamble})
+		}
+		for _, msg := range history {
+			converted = append(converted, libports.Message{Role: msg.Role, Content: msg.Message})
+		}
+		llmtest.CheckConversion(t, req, converted, roleSystem, roleUser, roleChatbot)
+	})
+}
+
+func TestRequestGolden(t *testing.T) {
+	for _, tc := range llmtest.GoldenCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := testutil.NewCohereServer(t)
+			client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+			if _, err := client.GenerateCompletion(context.Background(), tc.Request); err != nil {
+				t.Fatalf("GenerateCompletion() error = %v", err)
+			}
+			last, ok := srv.LastRequest()
+			if !ok {
+				t.Fatal("no request received")
+			}
+			testutil.GoldenJSON(t, tc.Name, last.Body)
+		})
+	}
+}
+
+func TestCompleteWithToolsMultiTurn(t *testing.T) {
+	srv := testutil.NewCohereServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Sunny in Paris, unknown in London."}, InputTokens: 40, OutputTokens: 7})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	first := &libports.CompletionResponse{
+		Message: libports.Message{Content: "I will check both cities."},
+		ToolCalls: []libports.ToolCall{
+			{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}},
+			{ID: "call_2", Name: "get_weather", Arguments: map[string]interface{}{"city": "London"}},
+		},
+	}
+	req := libports.CompletionRequest{
+		Model: "command-r-plus",
+		Messages: []libports.Message{
+			{Role: "system", Content: "You report the weather."},
+			{Role: "user", Content: "Weather in Paris and London?"},
+			ports.ToolCallsMessage(first),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_1", Name: "get_weather", Content: "sunny"}),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_2", Name: "get_weather", Content: "service down", IsError: true}),
+		},
+	}
+	tools := []libports.Tool{{
+		Name:        "get_weather",
+		Description: "Current weather",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city":  map[string]interface{}{"type": "string", "description": "City name"},
+				"days":  map[string]interface{}{"type": "integer"},
+				"units": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+			"required": []interface{}{"city"},
+		},
+	}}
+
+	resp, err := client.CompleteWithTools(context.Background(), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Sunny in Paris, unknown in London." || resp.FinishReason != "stop" || len(resp.ToolCalls) != 0 {
+		t.Errorf("response = %+v, want the final answer", resp)
+	}
+	if resp.Usage.PromptTokens != 40 || resp.Usage.TotalTokens != 47 {
+		t.Errorf("Usage = %+v, want the billed tokens", resp.Usage)
+	}
+
+	last, _ := srv.LastRequest()
+	var body chatRequest
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.Preamble != "You report the weather." || body.Message != "" {
+		t.Errorf("preamble = %q, message = %q, want the system prompt and no message", body.Preamble, body.Message)
+	}
+	if len(body.ChatHistory) != 2 || body.ChatHistory[0].Role != roleUser || body.ChatHistory[1].Role != roleChatbot {
+		t.Fatalf("chat history = %+v, want the user turn and the tool calls", body.ChatHistory)
+	}
+	if calls := body.ChatHistory[1].ToolCalls; body.ChatHistory[1].Message != "I will check both cities." || len(calls) != 2 || calls[1].Parameters["city"] != "London" {
+		t.Errorf("tool calls turn = %+v", body.ChatHistory[1])
+	}
+	if len(body.ToolResults) != 2 {
+		t.Fatalf("tool results = %+v, want both results", body.ToolResults)
+	}
+	if r := body.ToolResults[1]; r.Call.Name != "get_weather" || r.Call.Parameters["city"] != "London" || len(r.Outputs) != 1 || r.Outputs[0]["error"] != "service down" {
+		t.Errorf("second result = %+v, want the London call with an error output", r)
+	}
+
+	definitions := body.Tools[0].ParameterDefinitions
+	want := map[string]parameterDefinition{
+		"city":  {Description: "City name", Type: "str", Required: true},
+		"days":  {Type: "int"},
+		"units": {Type: "List[str]"},
+	}
+	if len(definitions) != len(want) {
+		t.Fatalf("parameter definitions = %+v, want %+v", definitions, want)
+	}
+	for name, definition := range want {
+		if definitions[name] != definition {
+			t.Errorf("parameter %s = %+v, want %+v", name, definitions[name], definition)
+		}
+	}
+}
+
+func TestCompleteWithToolsHistory(t *testing.T) {
+	srv := testutil.NewCohereServer(t)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	// Results of earlier calls stay in the history, as a TOOL turn
+	first := &libports.CompletionResponse{
+		ToolCalls: []libports.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}}},
+	}
+	req := libports.CompletionRequest{
+		Model: "command-r-plus",
+		Messages: []libports.Message{
+			{Role: "user", Content: "Weather in Paris?"},
+			ports.ToolCallsMessage(first),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_1", Name: "get_weather", Content: "sunny"}),
+			{Role: "assistant", Content: "Sunny."},
+			{Role: "user", Content: "Thanks!"},
+		},
+	}
+	if _, err := client.Complete(context.Background(), req); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	last, _ := srv.LastRequest()
+	var body chatRequest
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.Message != "Thanks!" || len(body.ToolResults) != 0 || len(body.ChatHistory) != 4 {
+		t.Fatalf("request = %+v, want the last message after a history of 4 turns", body)
+	}
+	turn := body.ChatHistory[2]
+	if turn.Role != roleTool || len(turn.ToolResults) != 1 || turn.ToolResults[0].Outputs[0]["result"] != "sunny" {
+		t.Errorf("tool turn = %+v, want the result", turn)
+	}
+}
+
+func TestCompleteStructured(t *testing.T) {
+	srv := testutil.NewCohereServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace","born":1815}`}})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	schema := libports.JSONSchema{
+		"type":       "object",
+		"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "born": map[string]interface{}{"type": "integer"}},
+		"required":   []string{"name", "born"},
+	}
+	req := libports.CompletionRequest{Model: "command-r-plus", Messages: []libports.Message{{Role: "user", Content: "Who was Ada Lovelace?"}}}
+
+	resp, err := client.CompleteStructured(context.Background(), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if resp.Data["born"] != float64(1815) {
+		t.Errorf("Data = %v", resp.Data)
+	}
+
+	last, _ := srv.LastRequest()
+	var body chatRequest
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.ResponseFormat == nil || body.ResponseFormat.Type != "json_object" || body.ResponseFormat.Schema["required"] == nil {
+		t.Errorf("response_format = %+v, want a json_object with the schema", body.ResponseFormat)
+	}
+
+	srv.Reply(testutil.Reply{Chunks: []string{"Ada Lovelace, born 1815"}})
+	if _, err := client.CompleteStructured(context.Background(), req, schema); err == nil || !strings.Contains(err.Error(), "invalid structured response") {
+		t.Errorf("CompleteStructured() error = %v, want an invalid response", err)
+	}
+}
+
+func TestFinishReason(t *testing.T) {
+	tests := []struct {
+		reason    string
+		toolCalls bool
+		want      string
+	}{
+		{"COMPLETE", false, "stop"},
+		{"COMPLETE", true, "tool_calls"},
+		{"STOP_SEQUENCE", false, "stop"},
+		{"MAX_TOKENS", true, "length"},
+		{"ERROR_TOXIC", false, "content_filter"},
+		{"USER_CANCEL", false, "other"},
+		{"", false, ""},
+	}
+	for _, tt := range tests {
+		if got := finishReason(tt.reason, tt.toolCalls); got != tt.want {
+			t.Errorf("finishReason(%q, %v) = %q, want %q", tt.reason, tt.toolCalls, got, tt.want)
+		}
+	}
+}
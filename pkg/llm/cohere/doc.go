@@ -0,0 +1,42 @@
+// Package cohere implements the LLM client adapter for the Cohere Command
+// models.
+//
+// This adapter implements the ports.LLMClient interface defined in dago-libs,
+// through the v1 Chat API of Cohere, called over HTTP as Cohere has no Go
+// SDK. Conversations are sent as Cohere expects them: a leading system
+// message as the preamble, the last user message as the message, and the
+// turns before it as the chat history, with USER, CHATBOT and SYSTEM roles.
+//
+// Supported models:
+//   - command-r-plus
+//   - command-r
+//   - command-r7b-12-2024
+//   - command-a-03-2025
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/llm/cohere"
+//
+//	client, err := cohere.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
+//		Model: "command-r-plus",
+//		Messages: []domain.Message{
+//			{Role: "user", Content: "Hello!"},
+//		},
+//	})
+//
+// CompleteWithTools sends tools with their parameter definitions, the
+// properties of their schema, and returns the model's tool calls. Cohere
+// doesn't identify calls: they are given random IDs, and earlier turns
+// encoded with ports.ToolCallsMessage and ports.ToolResultMessage are sent
+// as CHATBOT tool calls and TOOL results repeating their call, so it can
+// drive agent.RunToolLoop. Results answering the last calls are sent as the
+// request's tool results, without a message.
+//
+// CompleteStructured uses a json_object response format constrained by the
+// schema. CompleteStream streams completions, tool calls included.
+package cohere
@@ -0,0 +1,211 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// streamEvent is an event of a streamed chat response, one JSON object per
+// line
+type streamEvent struct {
+	EventType     string `json:"event_type"`
+	Text          string `json:"text"`
+	ToolCallDelta *struct {
+		Index      int    `json:"index"`
+		Name       string `json:"name"`
+		Parameters string `json:"parameters"`
+		Text       string `json:"text"`
+	} `json:"tool_call_delta"`
+	ToolCalls    []toolCall    `json:"tool_calls"`
+	FinishReason string        `json:"finish_reason"`
+	Response     *chatResponse `json:"response"`
+}
+
+// CompleteStream streams a completion with tools, if any
+// (ports.LLMStreamer interface). Text deltas and tool call fragments are
+// sent as they arrive, the first fragment of each call with a random ID;
+// the text, parsed tool calls, finish reason and usage on the last chunk.
+// Cancelling ctx closes the stream, and ends it with the text generated so
+// far.
+func (c *Client) CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan ports.StreamChunk, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Tools = convertTools(tools)
+	chatReq.Stream = true
+
+	httpResp, err := c.post(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(chan ports.StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer func() { _ = httpResp.Body.Close() }()
+
+		var content strings.Builder
+		send := func(chunk ports.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// The last chunk is sent even after a cancellation, with the text
+		// so far
+		finish := func(chunk ports.StreamChunk) {
+			chunk.Content = content.String()
+			chunk.Done = true
+			chunks <- chunk
+		}
+		cancelled := func() {
+			c.logger.Debug("stream cancelled", zap.Int("content_length", content.Len()))
+			finish(ports.StreamChunk{Cancelled: true})
+		}
+		text := func(delta string) bool {
+			content.WriteString(delta)
+			return delta == "" || send(ports.StreamChunk{Delta: delta})
+		}
+
+		// Tool calls in progress, in the order of their index, with
+		// their JSON arguments
+		var toolCalls []libports.ToolCall
+		var arguments []string
+
+		decoder := json.NewDecoder(httpResp.Body)
+		for {
+			var event streamEvent
+			err := decoder.Decode(&event)
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("stream ended without a stream-end event")
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					cancelled()
+					return
+				}
+				c.logger.Error("API call failed", zap.Error(err))
+				finish(ports.StreamChunk{Err: fmt.Errorf("API call failed: %w", err)})
+				return
+			}
+
+			switch event.EventType {
+			case "text-generation":
+				if !text(event.Text) {
+					cancelled()
+					return
+				}
+
+			case "tool-calls-chunk":
+				delta := event.ToolCallDelta
+				if delta == nil {
+					continue
+				}
+				// Text of tool call chunks is the model's plan
+				if !text(delta.Text) {
+					cancelled()
+					return
+				}
+				if delta.Name == "" && delta.Parameters == "" {
+					continue
+				}
+				fragment := &ports.ToolCallDelta{Index: delta.Index, Name: delta.Name, Arguments: delta.Parameters}
+				for len(toolCalls) <= delta.Index {
+					toolCalls = append(toolCalls, libports.ToolCall{})
+					arguments = append(arguments, "")
+				}
+				if toolCalls[delta.Index].ID == "" {
+					toolCalls[delta.Index].ID = newToolCallID()
+					fragment.ID = toolCalls[delta.Index].ID
+				}
+				toolCalls[delta.Index].Name += delta.Name
+				arguments[delta.Index] += delta.Parameters
+				if !send(ports.StreamChunk{ToolCallDelta: fragment}) {
+					cancelled()
+					return
+				}
+
+			case "tool-calls-generation":
+				// Calls that weren't streamed in chunks are sent whole
+				for index := len(toolCalls); index < len(event.ToolCalls); index++ {
+					call := libports.ToolCall{ID: newToolCallID(), Name: event.ToolCalls[index].Name, Arguments: event.ToolCalls[index].Parameters}
+					delta := ports.WholeToolCallDelta(index, call)
+					toolCalls = append(toolCalls, call)
+					arguments = append(arguments, delta.Arguments)
+					if !send(ports.StreamChunk{ToolCallDelta: delta}) {
+						cancelled()
+						return
+					}
+				}
+
+			case "stream-end":
+				if event.FinishReason == "ERROR" || event.FinishReason == "ERROR_LIMIT" {
+					err := fmt.Errorf("API call failed: generation ended with %s", event.FinishReason)
+					c.logger.Error("API call failed", zap.Error(err))
+					finish(ports.StreamChunk{Err: err})
+					return
+				}
+
+				calls := c.parseToolCalls(toolCalls, arguments)
+				chunk := ports.StreamChunk{
+					ToolCalls:    calls,
+					FinishReason: finishReason(event.FinishReason, len(calls) > 0),
+				}
+				if event.Response != nil {
+					usage := usageInfo(event.Response)
+					chunk.Usage = &usage
+				}
+				c.logger.Debug("completion streamed", zap.Int("tool_calls", len(calls)), zap.Bool("usage", chunk.Usage != nil))
+				finish(chunk)
+				return
+			}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// StreamComplete streams the text of a completion, as libports'
+// CompletionChunk, through CompleteStream
+func (c *Client) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks, err := c.CompleteStream(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ports.CompletionChunks(ctx, chunks), nil
+}
+
+// parseToolCalls decodes the JSON arguments of streamed tool calls.
+// Arguments that aren't a JSON object are logged and left empty, for the
+// tool to reject.
+func (c *Client) parseToolCalls(calls []libports.ToolCall, arguments []string) []libports.ToolCall {
+	for i := range calls {
+		if arguments[i] == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(arguments[i]), &calls[i].Arguments); err != nil {
+			c.logger.Warn("invalid tool call arguments",
+				zap.String("tool", calls[i].Name),
+				zap.String("arguments", arguments[i]),
+				zap.Error(err))
+		}
+	}
+	return calls
+}
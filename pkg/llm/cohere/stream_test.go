@@ -0,0 +1,74 @@
+package cohere
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestCompleteStream(t *testing.T) {
+	srv := testutil.NewCohereServer(t)
+	srv.Reply(testutil.Reply{
+		Chunks:       []string{"Checking ", "both."},
+		ToolCalls:    []testutil.ToolCall{{Name: "get_weather", Arguments: `{"city": "Paris"}`}, {Name: "get_weather", Arguments: `{"city": "London"}`}},
+		InputTokens:  20,
+		OutputTokens: 12,
+	})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "command-r-plus", Messages: []libports.Message{{Role: "user", Content: "Weather in Paris and London?"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var text string
+	var ids []string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		text += chunk.Delta
+		if delta := chunk.ToolCallDelta; delta != nil && delta.ID != "" {
+			ids = append(ids, delta.ID)
+		}
+		last = chunk
+	}
+	if text != "Checking both." || last.Content != text {
+		t.Errorf("text = %q, content = %q, want the chunks", text, last.Content)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "tool_calls" {
+		t.Fatalf("last chunk = %+v, want a tool_calls finish", last)
+	}
+	if len(last.ToolCalls) != 2 || last.ToolCalls[1].Arguments["city"] != "London" {
+		t.Fatalf("tool calls = %+v, want both calls with their arguments", last.ToolCalls)
+	}
+	if len(ids) != 2 || ids[0] != last.ToolCalls[0].ID || ids[1] != last.ToolCalls[1].ID || ids[0] == ids[1] {
+		t.Errorf("fragment IDs = %q, want one distinct ID per call", ids)
+	}
+	if last.Usage == nil || last.Usage.PromptTokens != 20 || last.Usage.TotalTokens != 32 {
+		t.Errorf("Usage = %+v, want the billed tokens", last.Usage)
+	}
+}
+
+func TestCompleteStream_GenerationError(t *testing.T) {
+	srv := testutil.NewCohereServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Partial"}, Error: "generation failed"})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "command-r-plus", Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		last = chunk
+	}
+	if last.Err == nil || !strings.Contains(last.Err.Error(), "ERROR") || last.Content != "Partial" {
+		t.Errorf("last chunk = %+v, want the error after the partial text", last)
+	}
+}
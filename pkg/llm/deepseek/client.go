@@ -0,0 +1,521 @@
+package deepseek
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// DefaultBaseURL is the endpoint of the DeepSeek API
+const DefaultBaseURL = "https://api.deepseek.com"
+
+// Client implements the LLMClient interface for the DeepSeek models,
+// through DeepSeek's OpenAI-compatible chat completions API. It is called
+// over HTTP, as the OpenAI SDK doesn't decode the reasoning_content of
+// deepseek-reasoner.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new DeepSeek client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+// Completion is a completion with the reasoning the model did before
+// answering
+type Completion struct {
+	*libports.CompletionResponse
+
+	// Reasoning is the chain of thought of deepseek-reasoner, which isn't
+	// part of the answer in Message; it is empty for deepseek-chat
+	Reasoning string
+
+	// ReasoningTokens are the completion tokens spent on the reasoning
+	ReasoningTokens int
+}
+
+// chatRequest is a request of the chat completions API
+type chatRequest struct {
+	Model            string          `json:"model"`
+	Messages         []chatMessage   `json:"messages"`
+	Tools            []tool          `json:"tools,omitempty"`
+	ResponseFormat   *responseFormat `json:"response_format,omitempty"`
+	Stream           bool            `json:"stream,omitempty"`
+	StreamOptions    *streamOptions  `json:"stream_options,omitempty"`
+	MaxTokens        int             `json:"max_tokens,omitempty"`
+	Temperature      float64         `json:"temperature,omitempty"`
+	TopP             float64         `json:"top_p,omitempty"`
+	Stop             []string        `json:"stop,omitempty"`
+	PresencePenalty  float64         `json:"presence_penalty,omitempty"`
+	FrequencyPenalty float64         `json:"frequency_penalty,omitempty"`
+}
+
+// chatMessage is a message sent to the API. Reasoning is never sent back:
+// the API rejects messages with a reasoning_content.
+type chatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	Name       string     `json:"name,omitempty"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+type toolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function functionCall `json:"function"`
+}
+
+type functionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type tool struct {
+	Type     string   `json:"type"`
+	Function function `json:"function"`
+}
+
+type function struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// chatResponse is a response of the chat completions API
+type chatResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Created int64  `json:"created"`
+	Choices []struct {
+		Message struct {
+			Content          string     `json:"content"`
+			ReasoningContent string     `json:"reasoning_content"`
+			ToolCalls        []toolCall `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usage `json:"usage"`
+}
+
+type usage struct {
+	PromptTokens            int `json:"prompt_tokens"`
+	CompletionTokens        int `json:"completion_tokens"`
+	TotalTokens             int `json:"total_tokens"`
+	CompletionTokensDetails *struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details"`
+}
+
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
+}
+
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface). Tool calls and results of earlier turns, in
+// messages built with ports.ToolCallsMessage and ports.ToolResultMessage,
+// are sent as assistant tool_calls and "tool" role messages. The reasoning
+// of deepseek-reasoner is left out; CompleteWithReasoning returns it.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	completion, err := c.CompleteWithReasoning(ctx, req, tools)
+	if err != nil {
+		return nil, err
+	}
+	return completion.CompletionResponse, nil
+}
+
+// CompleteWithReasoning performs a completion like CompleteWithTools,
+// returning the reasoning of deepseek-reasoner apart from its answer, e.g.
+// to log the chain of thought without showing it
+func (c *Client) CompleteWithReasoning(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*Completion, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Tools = convertTools(tools)
+
+	resp, err := c.chat(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := c.toCompletion(resp)
+
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens),
+		zap.Int("reasoning_tokens", completion.ReasoningTokens))
+
+	return completion, nil
+}
+
+// CompleteStructured performs a completion returning JSON data
+// (ports.LLMClient interface), in JSON mode with the schema in a system
+// message, as DeepSeek has no structured outputs. JSON mode guarantees
+// JSON but not its conformance.
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %v", ports.ErrInvalidRequest, err)
+	}
+
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Messages = append([]chatMessage{{
+		Role:    "system",
+		Content: "Respond with a JSON object conforming to this JSON schema:\n" + string(data),
+	}}, chatReq.Messages...)
+	chatReq.ResponseFormat = &responseFormat{Type: "json_object"}
+
+	resp, err := c.chat(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := c.toCompletion(resp)
+	structured := &libports.StructuredResponse{
+		Usage:     completion.Usage,
+		CreatedAt: completion.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(completion.Message.Content), &structured.Data); err != nil {
+		return nil, fmt.Errorf("invalid structured response (finish reason %q): %w", completion.FinishReason, err)
+	}
+
+	c.logger.Debug("structured completion generated",
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
+
+	return structured, nil
+}
+
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
+func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Generate generates a completion of a domain request. The reasoning of
+// deepseek-reasoner is left out of the response's content.
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion",
+		zap.String("model", llmReq.Model),
+		zap.Int("message_count", len(llmReq.Messages)))
+
+	chatReq := c.chatRequest(libports.CompletionRequest{
+		Model:       llmReq.Model,
+		Messages:    ports.CompletionMessages(llmReq),
+		MaxTokens:   llmReq.MaxTokens,
+		Temperature: llmReq.Temperature,
+	})
+	if len(llmReq.Tools) > 0 {
+		tools := make([]libports.Tool, 0, len(llmReq.Tools))
+		for _, tool := range llmReq.Tools {
+			tools = append(tools, libports.Tool(tool))
+		}
+		chatReq.Tools = convertTools(tools)
+	}
+
+	resp, err := c.chat(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := c.toCompletion(resp)
+	llmResp := &domain.LLMResponse{
+		Content: completion.Message.Content,
+		Model:   completion.Model,
+		Usage: domain.Usage{
+			InputTokens:  completion.Usage.PromptTokens,
+			OutputTokens: completion.Usage.CompletionTokens,
+		},
+	}
+	for _, call := range completion.ToolCalls {
+		llmResp.ToolCalls = append(llmResp.ToolCalls, domain.ToolCall{ID: call.ID, Name: call.Name, Input: call.Arguments})
+	}
+
+	c.logger.Debug("completion generated",
+		zap.Int("input_tokens", llmResp.Usage.InputTokens),
+		zap.Int("output_tokens", llmResp.Usage.OutputTokens),
+		zap.Int("reasoning_tokens", completion.ReasoningTokens))
+
+	return llmResp, nil
+}
+
+// chatRequest returns the chat completion request of req, without tools or
+// response format
+func (c *Client) chatRequest(req libports.CompletionRequest) chatRequest {
+	return chatRequest{
+		Model:            req.Model,
+		Messages:         c.convertCompletionMessages(req.Messages),
+		MaxTokens:        req.MaxTokens,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		Stop:             req.Stop,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+	}
+}
+
+// chat sends a chat completion request, and decodes its response
+func (c *Client) chat(ctx context.Context, chatReq chatRequest) (*chatResponse, error) {
+	httpResp, err := c.post(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	var resp chatResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("API call failed: no choices in response")
+	}
+	return &resp, nil
+}
+
+// post sends a chat completion request, returning the response of a
+// successful one with its body to be closed
+func (c *Client) post(ctx context.Context, chatReq chatRequest) (*http.Response, error) {
+	data, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	if chatReq.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	} else {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer func() { _ = httpResp.Body.Close() }()
+		body, _ := io.ReadAll(httpResp.Body)
+		err := fmt.Errorf("API call failed: %w", &ports.StatusError{StatusCode: httpResp.StatusCode, Message: errorMessage(body)})
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, err
+	}
+	return httpResp, nil
+}
+
+// errorMessage returns the message of an error body, or the body itself
+func errorMessage(body []byte) string {
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+		return apiErr.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// convertCompletionMessages converts messages to the OpenAI format of
+// DeepSeek. Messages built with ports.ToolCallsMessage become assistant
+// messages with tool calls, and results built with ports.ToolResultMessage
+// "tool" messages; results marked as errors are prefixed with "Error: ", as
+// the API has no field for it. Unknown roles are sent as user messages.
+func (c *Client) convertCompletionMessages(msgs []libports.Message) []chatMessage {
+	messages := make([]chatMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if content, calls, ok := ports.ParseToolCallsMessage(msg); ok {
+			message := chatMessage{Role: "assistant", Content: content}
+			for _, call := range calls {
+				arguments, _ := json.Marshal(call.Arguments)
+				if call.Arguments == nil {
+					arguments = []byte("{}")
+				}
+				message.ToolCalls = append(message.ToolCalls, toolCall{
+					ID:       call.ID,
+					Type:     "function",
+					Function: functionCall{Name: call.Name, Arguments: string(arguments)},
+				})
+			}
+			messages = append(messages, message)
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			content := result.Content
+			if result.IsError {
+				content = "Error: " + content
+			}
+			messages = append(messages, chatMessage{Role: "tool", Content: content, ToolCallID: result.ToolCallID})
+			continue
+		}
+
+		role := msg.Role
+		switch role {
+		case "user", "assistant", "system":
+		default:
+			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
+			role = "user"
+		}
+		messages = append(messages, chatMessage{Role: role, Content: msg.Content, Name: msg.Name})
+	}
+	return messages
+}
+
+// convertTools converts tools to function tools. Tools without parameters
+// take an empty object, as the API requires a schema.
+func convertTools(tools []libports.Tool) []tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	converted := make([]tool, 0, len(tools))
+	for _, t := range tools {
+		parameters := t.Parameters
+		if parameters == nil {
+			parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		converted = append(converted, tool{
+			Type:     "function",
+			Function: function{Name: t.Name, Description: t.Description, Parameters: parameters},
+		})
+	}
+	return converted
+}
+
+// toCompletion converts the first choice of a response
+func (c *Client) toCompletion(resp *chatResponse) *Completion {
+	choice := resp.Choices[0]
+	completion := &Completion{
+		CompletionResponse: &libports.CompletionResponse{
+			ID:           resp.ID,
+			Model:        resp.Model,
+			Message:      libports.Message{Role: "assistant", Content: choice.Message.Content},
+			ToolCalls:    c.parseToolCalls(choice.Message.ToolCalls),
+			FinishReason: finishReason(choice.FinishReason),
+			CreatedAt:    time.Unix(resp.Created, 0),
+		},
+		Reasoning: choice.Message.ReasoningContent,
+	}
+	if resp.Usage != nil {
+		completion.Usage = usageInfo(resp.Usage)
+		completion.ReasoningTokens = reasoningTokens(resp.Usage)
+	}
+	return completion
+}
+
+// parseToolCalls converts the tool calls of a response, decoding their
+// JSON arguments. Arguments that aren't a JSON object are logged and left
+// empty, for the tool to reject.
+func (c *Client) parseToolCalls(calls []toolCall) []libports.ToolCall {
+	var parsed []libports.ToolCall
+	for _, call := range calls {
+		var arguments map[string]interface{}
+		if call.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+				c.logger.Warn("invalid tool call arguments",
+					zap.String("tool", call.Function.Name),
+					zap.String("arguments", call.Function.Arguments),
+					zap.Error(err))
+			}
+		}
+		parsed = append(parsed, libports.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments})
+	}
+	return parsed
+}
+
+// usageInfo converts the token usage of a response
+func usageInfo(u *usage) libports.UsageInfo {
+	return libports.UsageInfo{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+// reasoningTokens returns the completion tokens of a usage spent on the
+// reasoning
+func reasoningTokens(u *usage) int {
+	if u.CompletionTokensDetails == nil {
+		return 0
+	}
+	return u.CompletionTokensDetails.ReasoningTokens
+}
+
+// finishReason returns the OpenAI finish reason of a DeepSeek one. They
+// are the same but for insufficient_system_resource, DeepSeek's own when
+// generation is interrupted for lack of capacity, which is "other".
+func finishReason(reason string) string {
+	if reason == "insufficient_system_resource" {
+		return "other"
+	}
+	return reason
+}
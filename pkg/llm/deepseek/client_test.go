@@ -0,0 +1,314 @@
+package deepseek
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client == nil {
+		t.Error("NewClient() returned nil client")
+	}
+}
+
+func TestGenerateCompletion(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("invalid request type", func(t *testing.T) {
+		client, _ := NewClient("test-key", "", logger)
+
+		_, err := client.GenerateCompletion(context.Background(), "invalid")
+		if err == nil {
+			t.Error("GenerateCompletion() expected error for invalid request type")
+		}
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		srv := testutil.NewOpenAIServer(t)
+		srv.Reply(testutil.Reply{Thinking: []string{"A greeting."}, Chunks: []string{"Hello!"}, InputTokens: 12, OutputTokens: 9})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
+
+		req := &domain.LLMRequest{
+			Model:     "deepseek-reasoner",
+			System:    "Be brief",
+			Messages:  []domain.Message{{Role: "user", Content: "Hello"}},
+			MaxTokens: 100,
+		}
+		resp, err := client.GenerateCompletion(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		llmResp := resp.(*domain.LLMResponse)
+		if llmResp.Content != "Hello!" || llmResp.Model != "deepseek-reasoner" {
+			t.Errorf("response = %+v, want the answer without the reasoning", llmResp)
+		}
+		if llmResp.Usage.InputTokens != 12 || llmResp.Usage.OutputTokens != 9 {
+			t.Errorf("Usage = %+v, want 12 input and 9 output tokens", llmResp.Usage)
+		}
+
+		last, _ := srv.LastRequest()
+		if got := last.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q, want the API key", got)
+		}
+		var body chatRequest
+		if err := last.JSON(&body); err != nil {
+			t.Fatalf("request body: %v", err)
+		}
+		if body.MaxTokens != 100 || len(body.Messages) != 2 || body.Messages[0].Role != "system" || body.Messages[1].Content != "Hello" {
+			t.Errorf("request = %+v", body)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := testutil.NewOpenAIServer(t)
+		srv.Reply(testutil.Reply{Status: http.StatusPaymentRequired, Error: "Insufficient Balance"})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
+
+		_, err := client.GenerateCompletion(context.Background(), &domain.LLMRequest{
+			Model:    "deepseek-chat",
+			Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "402 Payment Required: Insufficient Balance") {
+			t.Errorf("GenerateCompletion() error = %v, want the API error", err)
+		}
+	})
+}
+
+func TestConformance(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "deepseek-chat", Reply: srv.Reply})
+}
+
+// Integration test - only runs with DEEPSEEK_API_KEY environment variable
+func TestGenerateCompletion_Integration(t *testing.T) {
+	apiKey := os.Getenv("DEEPSEEK_API_KEY")
+	if apiKey == "" {
+		t.Skip("DEEPSEEK_API_KEY not set, skipping integration test")
+	}
+
+	client, err := NewClient(apiKey, "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := libports.CompletionRequest{
+		Model: "deepseek-reasoner",
+		Messages: []libports.Message{
+			{Role: "user", Content: "Say 'Hello, World!' and nothing else."},
+		},
+		MaxTokens: 500,
+	}
+
+	completion, err := client.CompleteWithReasoning(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteWithReasoning() error = %v", err)
+	}
+
+	if completion.Message.Content == "" {
+		t.Error("Response content is empty")
+	}
+
+	if completion.Reasoning == "" {
+		t.Error("Reasoning is empty")
+	}
+
+	if completion.Usage.PromptTokens == 0 {
+		t.Error("Input tokens is 0")
+	}
+
+	t.Logf("Reasoning: %s", completion.Reasoning)
+	t.Logf("Response: %s", completion.Message.Content)
+	t.Logf("Usage: %d input tokens, %d output tokens, %d reasoning tokens",
+		completion.Usage.PromptTokens,
+		completion.Usage.CompletionTokens,
+		completion.ReasoningTokens)
+}
+
+func FuzzConvertMessages(f *testing.F) {
+	llmtest.AddFuzzSeeds(f)
+	client, _ := NewClient("test-key", "", zap.NewNop())
+
+	f.Fuzz(func(t *testing.T, system, messages string) {
+		req := llmtest.FuzzRequest(system, messages)
+
+		var converted []libports.Message
+		for _, msg := range client.convertCompletionMessages(ports.CompletionMessages(req)) {
+			converted = append(converted, libports.Message{Role: msg.Role, Content: msg.Content})
+		}
+		llmtest.CheckConversion(t, req, converted, "system", "user", "assistant")
+	})
+}
+
+func TestRequestGolden(t *testing.T) {
+	for _, tc := range llmtest.GoldenCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := testutil.NewOpenAIServer(t)
+			client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+			if _, err := client.GenerateCompletion(context.Background(), tc.Request); err != nil {
+				t.Fatalf("GenerateCompletion() error = %v", err)
+			}
+			last, ok := srv.LastRequest()
+			if !ok {
+				t.Fatal("no request received")
+			}
+			testutil.GoldenJSON(t, tc.Name, last.Body)
+		})
+	}
+}
+
+func TestCompleteWithReasoning(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{
+		Thinking:     []string{"Paris is the capital ", "of France."},
+		Chunks:       []string{"Paris."},
+		InputTokens:  10,
+		OutputTokens: 20,
+	})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "deepseek-reasoner", Messages: []libports.Message{{Role: "user", Content: "Capital of France?"}}}
+	completion, err := client.CompleteWithReasoning(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteWithReasoning() error = %v", err)
+	}
+	if completion.Reasoning != "Paris is the capital of France." || completion.Message.Content != "Paris." {
+		t.Errorf("reasoning = %q, content = %q, want them apart", completion.Reasoning, completion.Message.Content)
+	}
+	if completion.FinishReason != "stop" || completion.Usage.TotalTokens != 30 {
+		t.Errorf("completion = %+v", completion.CompletionResponse)
+	}
+
+	// The reasoning isn't part of the completion of CompleteWithTools, nor
+	// sent back with the conversation
+	srv.Reply(testutil.Reply{Thinking: []string{"Again."}, Chunks: []string{"Paris."}})
+	resp, err := client.CompleteWithTools(context.Background(), libports.CompletionRequest{
+		Model: "deepseek-reasoner",
+		Messages: []libports.Message{
+			{Role: "user", Content: "Capital of France?"},
+			completion.Message,
+			{Role: "user", Content: "Sure?"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Paris." {
+		t.Errorf("content = %q, want the answer alone", resp.Message.Content)
+	}
+	last, _ := srv.LastRequest()
+	if strings.Contains(string(last.Body), "reasoning_content") {
+		t.Errorf("request = %s, want no reasoning", last.Body)
+	}
+}
+
+func TestCompleteWithToolsMultiTurn(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Sunny in Paris, unknown in London."}})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	first := &libports.CompletionResponse{
+		ToolCalls: []libports.ToolCall{
+			{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}},
+			{ID: "call_2", Name: "get_weather", Arguments: map[string]interface{}{"city": "London"}},
+		},
+	}
+	req := libports.CompletionRequest{
+		Model: "deepseek-chat",
+		Messages: []libports.Message{
+			{Role: "user", Content: "Weather in Paris and London?"},
+			ports.ToolCallsMessage(first),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_1", Name: "get_weather", Content: "sunny"}),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_2", Name: "get_weather", Content: "service down", IsError: true}),
+		},
+	}
+
+	resp, err := client.CompleteWithTools(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Sunny in Paris, unknown in London." {
+		t.Errorf("content = %q", resp.Message.Content)
+	}
+
+	last, _ := srv.LastRequest()
+	var body chatRequest
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if len(body.Messages) != 4 {
+		t.Fatalf("messages = %+v, want 4", body.Messages)
+	}
+	calls := body.Messages[1].ToolCalls
+	if len(calls) != 2 || calls[1].ID != "call_2" || calls[1].Function.Arguments != `{"city":"London"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if msg := body.Messages[3]; msg.Role != "tool" || msg.ToolCallID != "call_2" || msg.Content != "Error: service down" {
+		t.Errorf("second result = %+v, want an error result", msg)
+	}
+	if tool := body.Tools[0]; tool.Type != "function" || tool.Function.Parameters["type"] != "object" {
+		t.Errorf("tool = %+v, want a function with an empty object schema", tool)
+	}
+}
+
+func TestCompleteStructured(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace","born":1815}`}})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	schema := libports.JSONSchema{
+		"type":       "object",
+		"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "born": map[string]interface{}{"type": "integer"}},
+		"required":   []string{"name", "born"},
+	}
+	req := libports.CompletionRequest{Model: "deepseek-chat", Messages: []libports.Message{{Role: "user", Content: "Who was Ada Lovelace?"}}}
+
+	resp, err := client.CompleteStructured(context.Background(), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if resp.Data["born"] != float64(1815) {
+		t.Errorf("Data = %v", resp.Data)
+	}
+
+	last, _ := srv.LastRequest()
+	var body chatRequest
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.ResponseFormat == nil || body.ResponseFormat.Type != "json_object" {
+		t.Errorf("response_format = %+v, want json_object", body.ResponseFormat)
+	}
+	if len(body.Messages) != 2 || !strings.Contains(body.Messages[0].Content, `"born"`) {
+		t.Errorf("messages = %+v, want the schema in a system message", body.Messages)
+	}
+
+	srv.Reply(testutil.Reply{Chunks: []string{"Ada Lovelace, born 1815"}})
+	if _, err := client.CompleteStructured(context.Background(), req, schema); err == nil || !strings.Contains(err.Error(), "invalid structured response") {
+		t.Errorf("CompleteStructured() error = %v, want an invalid response", err)
+	}
+}
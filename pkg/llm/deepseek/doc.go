@@ -0,0 +1,48 @@
+// Package deepseek implements the LLM client adapter for the DeepSeek
+// models.
+//
+// This adapter implements the ports.LLMClient interface defined in dago-libs,
+// through the OpenAI-compatible chat completions API of DeepSeek, called
+// over HTTP to read the reasoning the OpenAI SDK doesn't decode.
+//
+// Supported models:
+//   - deepseek-chat
+//   - deepseek-reasoner
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/llm/deepseek"
+//
+//	client, err := deepseek.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
+//		Model: "deepseek-chat",
+//		Messages: []domain.Message{
+//			{Role: "user", Content: "Hello!"},
+//		},
+//	})
+//
+// deepseek-reasoner thinks before answering, and returns its chain of
+// thought in a reasoning_content field apart from the answer. The answer
+// alone is the content of responses; CompleteWithReasoning also returns the
+// reasoning, and CompleteStream streams it in the ReasoningDelta of its
+// chunks, so that it can be logged without being shown or sent back in the
+// conversation, which the API rejects:
+//
+//	completion, err := client.CompleteWithReasoning(ctx, req, tools)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	logger.Debug("reasoning", zap.String("reasoning", completion.Reasoning))
+//	fmt.Println(completion.Message.Content)
+//
+// CompleteWithTools sends tools as function definitions and returns the
+// model's tool calls, sending earlier turns encoded with
+// ports.ToolCallsMessage and ports.ToolResultMessage as assistant tool_calls
+// and "tool" messages, so it can drive agent.RunToolLoop. CompleteStructured
+// uses JSON mode with the schema in a system message, as DeepSeek has no
+// structured outputs.
+package deepseek
@@ -0,0 +1,207 @@
+package deepseek
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Limit of the size of a server-sent event line
+const maxEventSize = 1 << 20
+
+// streamChunk is a chunk of a streamed response, the data of a server-sent
+// event, or the error the API sends when generation fails midway
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+			ToolCalls        []struct {
+				Index    int          `json:"index"`
+				ID       string       `json:"id"`
+				Function functionCall `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CompleteStream streams a completion with tools, if any
+// (ports.LLMStreamer interface). The reasoning of deepseek-reasoner arrives
+// in ReasoningDelta, before the answer's text deltas and tool call
+// fragments; the text, reasoning, parsed tool calls, finish reason and
+// usage on the last chunk. Cancelling ctx closes the stream, and ends it
+// with the text generated so far.
+func (c *Client) CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan ports.StreamChunk, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Tools = convertTools(tools)
+	chatReq.Stream = true
+	chatReq.StreamOptions = &streamOptions{IncludeUsage: true}
+
+	httpResp, err := c.post(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(chan ports.StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer func() { _ = httpResp.Body.Close() }()
+
+		var content, reasoning strings.Builder
+		send := func(chunk ports.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// The last chunk is sent even after a cancellation, with the text
+		// so far
+		finish := func(chunk ports.StreamChunk) {
+			chunk.Content = content.String()
+			chunk.Reasoning = reasoning.String()
+			chunk.Done = true
+			chunks <- chunk
+		}
+		cancelled := func() {
+			c.logger.Debug("stream cancelled", zap.Int("content_length", content.Len()))
+			finish(ports.StreamChunk{Cancelled: true})
+		}
+		failed := func(err error) {
+			if ctx.Err() != nil {
+				cancelled()
+				return
+			}
+			c.logger.Error("API call failed", zap.Error(err))
+			finish(ports.StreamChunk{Err: fmt.Errorf("API call failed: %w", err)})
+		}
+
+		var (
+			reason   string
+			streamed *usage
+			// Tool calls in progress, in the order of their index
+			toolCalls []toolCall
+		)
+
+		scanner := bufio.NewScanner(httpResp.Body)
+		scanner.Buffer(make([]byte, 0, 64<<10), maxEventSize)
+		for scanner.Scan() {
+			// Other lines are event separators and keep-alive comments
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				calls := c.parseToolCalls(toolCalls)
+				chunk := ports.StreamChunk{ToolCalls: calls, FinishReason: reason}
+				if streamed != nil {
+					usage := usageInfo(streamed)
+					chunk.Usage = &usage
+				}
+				c.logger.Debug("completion streamed",
+					zap.Int("tool_calls", len(calls)),
+					zap.Bool("usage", chunk.Usage != nil),
+					zap.Int("reasoning_length", reasoning.Len()))
+				finish(chunk)
+				return
+			}
+
+			var event streamChunk
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				failed(fmt.Errorf("invalid stream chunk: %w", err))
+				return
+			}
+			if event.Error != nil {
+				failed(errors.New(event.Error.Message))
+				return
+			}
+			if event.Usage != nil {
+				streamed = event.Usage
+			}
+			if len(event.Choices) == 0 {
+				continue
+			}
+			choice := event.Choices[0]
+			if choice.FinishReason != "" {
+				reason = finishReason(choice.FinishReason)
+			}
+
+			if delta := choice.Delta.ReasoningContent; delta != "" {
+				reasoning.WriteString(delta)
+				if !send(ports.StreamChunk{ReasoningDelta: delta}) {
+					cancelled()
+					return
+				}
+			}
+			if delta := choice.Delta.Content; delta != "" {
+				content.WriteString(delta)
+				if !send(ports.StreamChunk{Delta: delta}) {
+					cancelled()
+					return
+				}
+			}
+			for _, delta := range choice.Delta.ToolCalls {
+				for len(toolCalls) <= delta.Index {
+					toolCalls = append(toolCalls, toolCall{Type: "function"})
+				}
+				call := &toolCalls[delta.Index]
+				if delta.ID != "" {
+					call.ID = delta.ID
+				}
+				call.Function.Name += delta.Function.Name
+				call.Function.Arguments += delta.Function.Arguments
+				fragment := &ports.ToolCallDelta{
+					Index:     delta.Index,
+					ID:        delta.ID,
+					Name:      delta.Function.Name,
+					Arguments: delta.Function.Arguments,
+				}
+				if !send(ports.StreamChunk{ToolCallDelta: fragment}) {
+					cancelled()
+					return
+				}
+			}
+		}
+
+		err := scanner.Err()
+		if err == nil {
+			err = fmt.Errorf("stream ended without [DONE]")
+		}
+		failed(err)
+	}()
+
+	return chunks, nil
+}
+
+// StreamComplete streams the text of a completion, as libports'
+// CompletionChunk, through CompleteStream. The reasoning isn't streamed.
+func (c *Client) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks, err := c.CompleteStream(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ports.CompletionChunks(ctx, chunks), nil
+}
@@ -0,0 +1,75 @@
+package deepseek
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestCompleteStream(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{
+		Thinking:     []string{"Two cities, ", "two calls."},
+		Chunks:       []string{"Checking ", "both."},
+		ToolCalls:    []testutil.ToolCall{{Name: "get_weather", Arguments: `{"city": "Paris"}`}, {Name: "get_weather", Arguments: `{"city": "London"}`}},
+		InputTokens:  20,
+		OutputTokens: 12,
+	})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "deepseek-reasoner", Messages: []libports.Message{{Role: "user", Content: "Weather in Paris and London?"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var text, reasoning string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		if chunk.ReasoningDelta != "" && text != "" {
+			t.Errorf("reasoning %q after the text", chunk.ReasoningDelta)
+		}
+		text += chunk.Delta
+		reasoning += chunk.ReasoningDelta
+		last = chunk
+	}
+	if text != "Checking both." || last.Content != text {
+		t.Errorf("text = %q, content = %q, want the chunks", text, last.Content)
+	}
+	if reasoning != "Two cities, two calls." || last.Reasoning != reasoning {
+		t.Errorf("reasoning = %q, last = %q, want the thinking", reasoning, last.Reasoning)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "tool_calls" {
+		t.Fatalf("last chunk = %+v, want a tool_calls finish", last)
+	}
+	if len(last.ToolCalls) != 2 || last.ToolCalls[1].Arguments["city"] != "London" {
+		t.Fatalf("tool calls = %+v, want both calls with their arguments", last.ToolCalls)
+	}
+	if last.Usage == nil || last.Usage.PromptTokens != 20 || last.Usage.TotalTokens != 32 {
+		t.Errorf("Usage = %+v, want the streamed usage", last.Usage)
+	}
+}
+
+func TestCompleteStream_GenerationError(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Partial"}, Error: "generation failed"})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "deepseek-chat", Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		last = chunk
+	}
+	if last.Err == nil || !strings.Contains(last.Err.Error(), "generation failed") || last.Content != "Partial" {
+		t.Errorf("last chunk = %+v, want the error after the partial text", last)
+	}
+}
@@ -1,7 +1,10 @@
 // Package llm provides LLM (Large Language Model) client adapters.
 //
 // This package contains implementations of the ports.LLMClient interface
-// for various LLM providers including Anthropic, OpenAI, Gemini, and Ollama.
+// for various LLM providers including Anthropic, OpenAI, Gemini, Ollama,
+// Mistral, Cohere, Groq, DeepSeek and Amazon Bedrock. The Bedrock client
+// takes its credentials from the default AWS chain and its region from
+// Config.Region, or the chain's.
 //
 // All adapters implement the same interface defined in dago-libs/pkg/ports/llm.go,
 // making them interchangeable.
@@ -19,4 +22,80 @@
 //
 //	// Use the client
 //	resp, err := client.Complete(ctx, req)
+//
+// Generate sends a domain.LLMRequest to any client, through the typed
+// Generate method of the adapters:
+//
+//	resp, err := llm.Generate(ctx, client, &domain.LLMRequest{Model: "gpt-4o", Messages: messages})
+//
+// Tool calls and results of earlier turns are appended to its messages with
+// ports.ToolCallsDomainMessage and ports.ToolResultDomainMessage, and sent
+// by every adapter in its provider's format, as those of CompleteWithTools.
+//
+// API keys can instead be read from a secret manager (see pkg/secrets). With
+// Config.APIKeySecret, the key is resolved before every call and the
+// provider client recreated when it was rotated:
+//
+//	client, err := llm.NewClient(&llm.Config{
+//		Provider:       "openai",
+//		APIKeySecret:   "dago/llm#openai",
+//		SecretResolver: llm.ResolveFrom(secrets.NewCachedProvider(provider, 5*time.Minute, logger)),
+//	})
+//
+// NewCachedClient answers repeated requests from a ports.Cache (pkg/cache),
+// e.g. Redis shared by all replicas:
+//
+//	cached := llm.NewCachedClient(client, redis.NewCache(rdb, logger), 24*time.Hour, logger)
+//
+// Config.Middleware wraps the client with the hooks the embeddings factory
+// has, WithRetry, WithMetrics and WithCache, first entry outermost:
+//
+//	client, err := llm.NewClient(&llm.Config{
+//		Provider: "openai",
+//		APIKey:   "your-api-key",
+//		Middleware: []llm.Middleware{
+//			llm.WithMetrics("openai"),
+//			llm.WithRetry(3, time.Second),
+//		},
+//	})
+//
+// NewReloadingClient reads the provider settings from a ports.ConfigStore key
+// (pkg/config) and recreates the client whenever the key changes:
+//
+//	client, err := llm.NewReloadingClient(ctx, etcd.NewStore(etcdClient, logger), "llm/default", &llm.Config{
+//		APIKeySecret:   "dago/llm#openai",
+//		SecretResolver: resolver,
+//		Logger:         logger,
+//	})
+//
+// Adapters living outside this module are made available to NewClient with
+// RegisterProvider, typically from an init function, and can be checked
+// against the same contract as the built-in ones with llmtest.RunConformance:
+//
+//	func init() {
+//		llm.RegisterProvider("acme", func(cfg *llm.Config, apiKey string) (ports.LLMClient, error) {
+//			return acme.NewClient(apiKey, cfg.BaseURL, cfg.Logger)
+//		})
+//	}
+//
+// The chunks of StreamComplete are consumed with the stream helpers:
+// WriteStream pipes them to an io.Writer such as an HTTP response, StreamTo
+// calls a function for each one, BufferStream reads ahead for slow
+// consumers and CollectStream returns the aggregated response:
+//
+//	chunks, err := streamer.StreamComplete(ctx, req)
+//	if err != nil {
+//		return err
+//	}
+//	_, err = llm.WriteStream(ctx, w, chunks)
+//
+// The adapters also implement ports.LLMStreamer: CompleteStream streams a
+// completion with tools as ports.StreamChunk, text deltas and tool call
+// fragments (ports.ToolCallDelta) as they arrive and a final chunk, with
+// Done set, holding the tool calls, finish reason and usage, or the error
+// that ended the stream. Tool call arguments can so be checked before
+// generation completes. Cancelling the context closes the provider's stream
+// and ends it with a Cancelled chunk holding the text generated so far,
+// e.g. to checkpoint it; read the channel until it's closed. StreamComplete
+// is built on it.
 package llm
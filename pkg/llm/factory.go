@@ -1,23 +1,96 @@
 package llm
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/aescanero/dago-adapters/pkg/llm/anthropic"
+	"github.com/aescanero/dago-adapters/pkg/llm/bedrock"
+	"github.com/aescanero/dago-adapters/pkg/llm/cohere"
+	"github.com/aescanero/dago-adapters/pkg/llm/deepseek"
 	"github.com/aescanero/dago-adapters/pkg/llm/gemini"
+	"github.com/aescanero/dago-adapters/pkg/llm/groq"
+	"github.com/aescanero/dago-adapters/pkg/llm/mistral"
 	"github.com/aescanero/dago-adapters/pkg/llm/ollama"
 	"github.com/aescanero/dago-adapters/pkg/llm/openai"
 	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
 	"go.uber.org/zap"
+	"golang.org/x/oauth2"
 )
 
 // Config holds LLM client configuration
 type Config struct {
 	Provider string
 	APIKey   string
-	BaseURL  string // For Ollama
+	BaseURL  string // For Ollama, OpenAI-compatible servers, proxies and tests
+	Region   string // AWS region of Bedrock, the default chain's otherwise
 	Timeout  int    // Timeout in seconds
 	Logger   *zap.Logger
+
+	// APIKeySecret names the secret holding the API key, used instead of
+	// APIKey. It is resolved with SecretResolver on every call, so rotated
+	// keys are picked up without restarting.
+	APIKeySecret   string
+	SecretResolver SecretResolver
+
+	// TokenSource authenticates OpenAI and Azure OpenAI clients with bearer
+	// tokens, refreshed before they expire, instead of an API key, e.g.
+	// openai.ClientCredentials or openai.WorkloadIdentity
+	TokenSource oauth2.TokenSource
+
+	// Middleware wraps the client, first entry outermost (e.g. WithMetrics,
+	// WithRetry, WithCache)
+	Middleware []Middleware
+}
+
+// ProviderFactory creates the client of a provider registered with
+// RegisterProvider. apiKey is Config.APIKey, or the key resolved from
+// Config.APIKeySecret.
+type ProviderFactory func(cfg *Config, apiKey string) (ports.LLMClient, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]ProviderFactory{}
+)
+
+// builtinProviders are the provider names NewClient handles itself
+var builtinProviders = map[string]bool{
+	"anthropic": true, "claude": true,
+	"openai": true, "gpt": true,
+	"azure":  true,
+	"gemini": true, "google": true,
+	"ollama": true, "local": true,
+	"bedrock":  true,
+	"mistral":  true,
+	"cohere":   true,
+	"groq":     true,
+	"deepseek": true,
+}
+
+// RegisterProvider makes NewClient create clients of provider name with
+// factory, so adapters living outside this module can be configured like
+// the built-in ones. It is meant to be called from init functions and
+// panics if name is empty, built in or already registered.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if name == "" || factory == nil {
+		panic("llm: RegisterProvider requires a name and a factory")
+	}
+	if builtinProviders[name] {
+		panic("llm: provider " + name + " is built in")
+	}
+	if _, ok := providers[name]; ok {
+		panic("llm: provider " + name + " already registered")
+	}
+	providers[name] = factory
 }
 
 // NewClient creates a new LLM client based on provider
@@ -26,15 +99,56 @@ func NewClient(cfg *Config) (ports.LLMClient, error) {
 		cfg.Logger = zap.NewNop()
 	}
 
+	var client ports.LLMClient
+	var err error
+	if cfg.APIKeySecret != "" {
+		client, err = newSecretClient(cfg)
+	} else {
+		client, err = newProviderClient(cfg, cfg.APIKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Chain(client, cfg.Middleware...), nil
+}
+
+// newProviderClient creates the client of cfg.Provider with apiKey
+func newProviderClient(cfg *Config, apiKey string) (ports.LLMClient, error) {
 	switch cfg.Provider {
 	case "anthropic", "claude":
-		return anthropic.NewClient(cfg.APIKey, cfg.Logger)
+		client, err := anthropic.NewClient(apiKey, cfg.Logger)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.BaseURL != "" {
+			client.SetBaseURL(cfg.BaseURL)
+		}
+		return client, nil
 
 	case "openai", "gpt":
-		return openai.NewClient(cfg.APIKey, cfg.BaseURL, cfg.Logger)
+		if cfg.TokenSource != nil {
+			return openai.NewClientWithTokenSource(cfg.TokenSource, cfg.BaseURL, cfg.Logger)
+		}
+		return openai.NewClient(apiKey, cfg.BaseURL, cfg.Logger)
+
+	case "azure":
+		// BaseURL is the resource endpoint, e.g. https://my-resource.openai.azure.com
+		if cfg.TokenSource != nil {
+			return openai.NewAzureClientWithTokenSource(cfg.BaseURL, cfg.TokenSource, cfg.Logger)
+		}
+		return openai.NewAzureClient(cfg.BaseURL, apiKey, cfg.Logger)
 
 	case "gemini", "google":
-		return gemini.NewClient(cfg.APIKey, cfg.Logger)
+		client, err := gemini.NewClient(apiKey, cfg.Logger)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.BaseURL != "" {
+			if err := client.SetBaseURL(cfg.BaseURL); err != nil {
+				return nil, err
+			}
+		}
+		return client, nil
 
 	case "ollama", "local":
 		endpoint := cfg.BaseURL
@@ -43,8 +157,40 @@ func NewClient(cfg *Config) (ports.LLMClient, error) {
 		}
 		return ollama.NewClient(endpoint, cfg.Logger)
 
+	case "bedrock":
+		// Credentials come from the default AWS chain, not APIKey
+		awsCfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(cfg.Region))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		runtime := bedrockruntime.NewFromConfig(awsCfg, func(o *bedrockruntime.Options) {
+			if cfg.BaseURL != "" {
+				o.BaseEndpoint = aws.String(cfg.BaseURL)
+			}
+		})
+		return bedrock.NewClient(runtime, cfg.Logger), nil
+
+	case "mistral":
+		return mistral.NewClient(apiKey, cfg.BaseURL, cfg.Logger)
+
+	case "cohere":
+		return cohere.NewClient(apiKey, cfg.BaseURL, cfg.Logger)
+
+	case "groq":
+		return groq.NewClient(apiKey, cfg.BaseURL, cfg.Logger)
+
+	case "deepseek":
+		return deepseek.NewClient(apiKey, cfg.BaseURL, cfg.Logger)
+
 	default:
-		return nil, fmt.Errorf("unsupported LLM provider: %s (supported: anthropic, openai, gemini, ollama)", cfg.Provider)
+		providersMu.RLock()
+		factory, ok := providers[cfg.Provider]
+		providersMu.RUnlock()
+		if ok {
+			return factory(cfg, apiKey)
+		}
+
+		return nil, fmt.Errorf("unsupported LLM provider: %s (supported: %s)", cfg.Provider, strings.Join(ListSupportedProviders(), ", "))
 	}
 }
 
@@ -53,23 +199,49 @@ func GetDefaultModel(provider string) string {
 	switch provider {
 	case "anthropic", "claude":
 		return "claude-sonnet-4-20250514"
-	case "openai", "gpt":
+	case "openai", "gpt", "azure":
 		return "gpt-4o"
 	case "gemini", "google":
 		return "gemini-2.0-flash-exp"
 	case "ollama", "local":
 		return "llama3.1"
+	case "bedrock":
+		return "anthropic.claude-3-5-sonnet-20240620-v1:0"
+	case "mistral":
+		return "mistral-large-latest"
+	case "cohere":
+		return "command-r-plus"
+	case "groq":
+		return "llama-3.3-70b-versatile"
+	case "deepseek":
+		return "deepseek-chat"
 	default:
 		return ""
 	}
 }
 
-// ListSupportedProviders returns a list of supported LLM providers
+// ListSupportedProviders returns a list of supported LLM providers, the
+// built-in ones followed by those registered with RegisterProvider
 func ListSupportedProviders() []string {
-	return []string{
+	supported := []string{
 		"anthropic",
 		"openai",
+		"azure",
 		"gemini",
 		"ollama",
+		"bedrock",
+		"mistral",
+		"cohere",
+		"groq",
+		"deepseek",
+	}
+
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	registered := make([]string, 0, len(providers))
+	for name := range providers {
+		registered = append(registered, name)
 	}
+	sort.Strings(registered)
+	return append(supported, registered...)
 }
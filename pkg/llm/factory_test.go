@@ -1,9 +1,19 @@
 package llm
 
 import (
+	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/llm/ollama"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/ollama/ollama/api"
 	"go.uber.org/zap"
+	"golang.org/x/oauth2"
 )
 
 func TestNewClient(t *testing.T) {
@@ -39,6 +49,12 @@ func TestNewClient(t *testing.T) {
 			apiKey:   "test-key",
 			wantErr:  false,
 		},
+		{
+			name:     "azure without endpoint",
+			provider: "azure",
+			apiKey:   "test-key",
+			wantErr:  true,
+		},
 		{
 			name:     "gemini with api key",
 			provider: "gemini",
@@ -51,6 +67,54 @@ func TestNewClient(t *testing.T) {
 			apiKey:   "",
 			wantErr:  false,
 		},
+		{
+			name:     "mistral with api key",
+			provider: "mistral",
+			apiKey:   "test-key",
+			wantErr:  false,
+		},
+		{
+			name:     "mistral without api key",
+			provider: "mistral",
+			apiKey:   "",
+			wantErr:  true,
+		},
+		{
+			name:     "cohere with api key",
+			provider: "cohere",
+			apiKey:   "test-key",
+			wantErr:  false,
+		},
+		{
+			name:     "cohere without api key",
+			provider: "cohere",
+			apiKey:   "",
+			wantErr:  true,
+		},
+		{
+			name:     "groq with api key",
+			provider: "groq",
+			apiKey:   "test-key",
+			wantErr:  false,
+		},
+		{
+			name:     "groq without api key",
+			provider: "groq",
+			apiKey:   "",
+			wantErr:  true,
+		},
+		{
+			name:     "deepseek with api key",
+			provider: "deepseek",
+			apiKey:   "test-key",
+			wantErr:  false,
+		},
+		{
+			name:     "deepseek without api key",
+			provider: "deepseek",
+			apiKey:   "",
+			wantErr:  true,
+		},
 		{
 			name:     "unsupported provider",
 			provider: "unsupported",
@@ -80,6 +144,41 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_Bedrock(t *testing.T) {
+	// Credentials and region come from the environment, as with the default
+	// AWS chain in production
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", dir+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", dir+"/credentials")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	srv := testutil.NewBedrockServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Hello from Bedrock"}})
+
+	client, err := NewClient(&Config{Provider: "bedrock", BaseURL: srv.URL, Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	resp, err := client.CompleteWithTools(context.Background(), ports.CompletionRequest{
+		Model:    GetDefaultModel("bedrock"),
+		Messages: []ports.Message{{Role: "user", Content: "Hello"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Hello from Bedrock" {
+		t.Errorf("Content = %q, want the reply", resp.Message.Content)
+	}
+
+	last, _ := srv.LastRequest()
+	auth := last.Header.Get("Authorization")
+	if !strings.Contains(auth, "Credential=AKIDTEST/") || !strings.Contains(auth, "/us-east-1/bedrock/") {
+		t.Errorf("Authorization = %q, want the environment's key signing for the configured region", auth)
+	}
+}
+
 func TestGetDefaultModel(t *testing.T) {
 	tests := []struct {
 		provider string
@@ -89,10 +188,16 @@ func TestGetDefaultModel(t *testing.T) {
 		{"claude", "claude-sonnet-4-20250514"},
 		{"openai", "gpt-4o"},
 		{"gpt", "gpt-4o"},
+		{"azure", "gpt-4o"},
 		{"gemini", "gemini-2.0-flash-exp"},
 		{"google", "gemini-2.0-flash-exp"},
 		{"ollama", "llama3.1"},
 		{"local", "llama3.1"},
+		{"bedrock", "anthropic.claude-3-5-sonnet-20240620-v1:0"},
+		{"mistral", "mistral-large-latest"},
+		{"cohere", "command-r-plus"},
+		{"groq", "llama-3.3-70b-versatile"},
+		{"deepseek", "deepseek-chat"},
 		{"unknown", ""},
 	}
 
@@ -116,8 +221,14 @@ func TestListSupportedProviders(t *testing.T) {
 	expectedProviders := map[string]bool{
 		"anthropic": true,
 		"openai":    true,
+		"azure":     true,
 		"gemini":    true,
 		"ollama":    true,
+		"bedrock":   true,
+		"mistral":   true,
+		"cohere":    true,
+		"groq":      true,
+		"deepseek":  true,
 	}
 
 	for _, provider := range providers {
@@ -131,3 +242,73 @@ func TestListSupportedProviders(t *testing.T) {
 		t.Errorf("Missing providers: %v", expectedProviders)
 	}
 }
+
+func TestRegisterProvider(t *testing.T) {
+	var gotKey string
+	RegisterProvider("echo", func(cfg *Config, apiKey string) (ports.LLMClient, error) {
+		gotKey = apiKey
+		return ollama.NewClient(cfg.BaseURL, cfg.Logger)
+	})
+	t.Cleanup(func() {
+		providersMu.Lock()
+		delete(providers, "echo")
+		providersMu.Unlock()
+	})
+
+	srv := testutil.NewOllamaServer(t)
+	client, err := NewClient(&Config{Provider: "echo", APIKey: "test-key", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if gotKey != "test-key" {
+		t.Errorf("factory got API key %q, want test-key", gotKey)
+	}
+	if got := ListSupportedProviders(); got[len(got)-1] != "echo" {
+		t.Errorf("ListSupportedProviders() = %v, want echo last", got)
+	}
+
+	// Registered providers are held to the same contract
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "llama3.1", Reply: func(replies ...testutil.Reply) {
+		for _, reply := range replies {
+			ollamaReply := testutil.OllamaReply{Chunks: reply.Chunks, Status: reply.Status, Error: reply.Error, Delay: reply.Delay}
+			for _, call := range reply.ToolCalls {
+				var args api.ToolCallFunctionArguments
+				_ = json.Unmarshal([]byte(call.Arguments), &args)
+				ollamaReply.ToolCalls = append(ollamaReply.ToolCalls, api.ToolCall{
+					Function: api.ToolCallFunction{Name: call.Name, Arguments: args},
+				})
+			}
+			srv.ReplyChat(ollamaReply)
+		}
+	}})
+
+	for _, name := range []string{"", "openai", "echo"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterProvider(%q) didn't panic", name)
+				}
+			}()
+			RegisterProvider(name, func(cfg *Config, apiKey string) (ports.LLMClient, error) { return nil, nil })
+		}()
+	}
+}
+
+func TestNewClientTokenSource(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	tokens := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "aad-token"})
+
+	client, err := NewClient(&Config{Provider: "openai", BaseURL: srv.BaseURL(), TokenSource: tokens})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	req := &domain.LLMRequest{Model: "gpt-4o", Messages: []domain.Message{{Role: "user", Content: "Hello"}}}
+	if _, err := client.GenerateCompletion(context.Background(), req); err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+	last, _ := srv.LastRequest()
+	if got := last.Header.Get("Authorization"); got != "Bearer aad-token" {
+		t.Errorf("Authorization = %q, want the token", got)
+	}
+}
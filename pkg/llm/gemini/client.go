@@ -2,10 +2,15 @@ package gemini
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/aescanero/dago-adapters/pkg/ports"
 	"github.com/aescanero/dago-libs/pkg/domain"
-	"github.com/aescanero/dago-libs/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
 	"github.com/google/generative-ai-go/genai"
 	"go.uber.org/zap"
 	"google.golang.org/api/option"
@@ -14,6 +19,7 @@ import (
 // Client implements the LLMClient interface for Google Gemini models
 type Client struct {
 	client *genai.Client
+	apiKey string
 	logger *zap.Logger
 }
 
@@ -31,91 +37,138 @@ func NewClient(apiKey string, logger *zap.Logger) (*Client, error) {
 
 	return &Client{
 		client: client,
+		apiKey: apiKey,
 		logger: logger,
 	}, nil
 }
 
+// SetBaseURL sets the API endpoint, e.g. a proxy or a test server. The
+// current client is closed and replaced.
+func (c *Client) SetBaseURL(baseURL string) error {
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey(c.apiKey), option.WithEndpoint(baseURL))
+	if err != nil {
+		return fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	_ = c.client.Close()
+	c.client = client
+	return nil
+}
+
 // Close closes the Gemini client
 func (c *Client) Close() error {
 	return c.client.Close()
 }
 
 // Complete performs a standard text completion (ports.LLMClient interface)
-func (c *Client) Complete(ctx context.Context, req ports.CompletionRequest) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
 }
 
-// CompleteWithTools performs a completion with tool calling support (ports.LLMClient interface)
-func (c *Client) CompleteWithTools(ctx context.Context, req ports.CompletionRequest, tools []ports.Tool) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-}
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface). Tool calls and results of earlier turns, in
+// messages built with ports.ToolCallsMessage and ports.ToolResultMessage,
+// are sent as function call and function response parts. Gemini doesn't
+// identify function calls, so the tool calls returned are given random IDs.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	model := c.generativeModel(req)
+	model.Tools = convertTools(tools)
+
+	resp, err := c.send(ctx, model, req.Messages)
+	if err != nil {
+		return nil, err
+	}
 
-// CompleteStructured performs a completion with guaranteed JSON schema conformance (ports.LLMClient interface)
-func (c *Client) CompleteStructured(ctx context.Context, req ports.CompletionRequest, schema ports.JSONSchema) (*ports.StructuredResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+	completion := toCompletionResponse(req.Model, resp)
+
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens))
+
+	return completion, nil
 }
 
-// GenerateCompletion generates a completion using domain.LLMRequest (compatibility method)
-func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
-	// Type assert the request
-	llmReq, ok := req.(*domain.LLMRequest)
-	if !ok {
-		return nil, fmt.Errorf("invalid request type")
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance (ports.LLMClient interface), asking for an application/json
+// response constrained by the schema, converted to the OpenAPI subset
+// Gemini accepts as responseSchema.
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
 	}
 
-	c.logger.Debug("generating completion",
-		zap.String("model", llmReq.Model),
-		zap.Int("message_count", len(llmReq.Messages)))
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
 
-	// Create model
-	model := c.client.GenerativeModel(llmReq.Model)
+	model := c.generativeModel(req)
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = convertSchema(schema)
 
-	// Configure generation parameters
-	if llmReq.Temperature > 0 {
-		temp := float32(llmReq.Temperature)
-		model.Temperature = &temp
+	resp, err := c.send(ctx, model, req.Messages)
+	if err != nil {
+		return nil, err
 	}
 
-	if llmReq.MaxTokens > 0 {
-		maxTokens := int32(llmReq.MaxTokens)
-		model.MaxOutputTokens = &maxTokens
+	completion := toCompletionResponse(req.Model, resp)
+	structured := &libports.StructuredResponse{
+		Usage:     completion.Usage,
+		CreatedAt: completion.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(completion.Message.Content), &structured.Data); err != nil {
+		return nil, fmt.Errorf("invalid structured response (finish reason %q): %w", completion.FinishReason, err)
 	}
 
-	// Start chat session
-	chat := model.StartChat()
+	c.logger.Debug("structured completion generated",
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
 
-	// Add system message as first user message if present
-	if llmReq.System != "" {
-		chat.History = append(chat.History, &genai.Content{
-			Parts: []genai.Part{
-				genai.Text(llmReq.System),
-			},
-			Role: "user",
-		})
-	}
+	return structured, nil
+}
 
-	// Add history (all messages except the last one)
-	for i := 0; i < len(llmReq.Messages)-1; i++ {
-		msg := llmReq.Messages[i]
-		role := "user"
-		if msg.Role == "assistant" {
-			role = "model" // Gemini uses "model" instead of "assistant"
-		}
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
+func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
 
-		chat.History = append(chat.History, &genai.Content{
-			Parts: []genai.Part{
-				genai.Text(msg.Content),
-			},
-			Role: role,
-		})
+// Generate generates a completion of a domain request
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
 	}
 
-	// Send the last message
-	lastMsg := llmReq.Messages[len(llmReq.Messages)-1]
-	resp, err := chat.SendMessage(ctx, genai.Text(lastMsg.Content))
+	c.logger.Debug("generating completion",
+		zap.String("model", llmReq.Model),
+		zap.Int("message_count", len(llmReq.Messages)))
+
+	model := c.generativeModel(libports.CompletionRequest{
+		Model:       llmReq.Model,
+		MaxTokens:   llmReq.MaxTokens,
+		Temperature: llmReq.Temperature,
+	})
+	resp, err := c.send(ctx, model, ports.CompletionMessages(llmReq))
 	if err != nil {
-		c.logger.Error("API call failed", zap.Error(err))
-		return nil, fmt.Errorf("API call failed: %w", err)
+		return nil, err
 	}
 
 	// Extract content
@@ -126,16 +179,12 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 		}
 	}
 
-	// Calculate token usage from candidates
+	// Token usage
 	inputTokens := 0
 	outputTokens := 0
-	if len(resp.Candidates) > 0 && resp.Candidates[0].TokenCount > 0 {
-		outputTokens = int(resp.Candidates[0].TokenCount)
-		// Estimate input tokens (Gemini v0.8.0 doesn't provide separate input count)
-		// Approximate based on message sizes
-		for _, msg := range llmReq.Messages {
-			inputTokens += len(msg.Content) / 4 // Rough approximation
-		}
+	if resp.UsageMetadata != nil {
+		inputTokens = int(resp.UsageMetadata.PromptTokenCount)
+		outputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
 	}
 
 	// Convert response
@@ -154,3 +203,189 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 
 	return llmResp, nil
 }
+
+// generativeModel returns the model of req, with its generation config
+func (c *Client) generativeModel(req libports.CompletionRequest) *genai.GenerativeModel {
+	model := c.client.GenerativeModel(req.Model)
+	if req.Temperature > 0 {
+		model.SetTemperature(float32(req.Temperature))
+	}
+	if req.MaxTokens > 0 {
+		model.SetMaxOutputTokens(int32(req.MaxTokens))
+	}
+	if req.TopP > 0 {
+		model.SetTopP(float32(req.TopP))
+	}
+	model.StopSequences = req.Stop
+	return model
+}
+
+// send sends msgs to model, the last turn as a chat message after the
+// others
+func (c *Client) send(ctx context.Context, model *genai.GenerativeModel, msgs []libports.Message) (*genai.GenerateContentResponse, error) {
+	session, last := startChat(model, msgs)
+	resp, err := session.SendMessage(ctx, last...)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	return resp, nil
+}
+
+// startChat starts a chat on model with all the turns of msgs but the last,
+// whose parts are returned to be sent
+func startChat(model *genai.GenerativeModel, msgs []libports.Message) (*genai.ChatSession, []genai.Part) {
+	system, contents := convertCompletionMessages(msgs)
+	model.SystemInstruction = system
+
+	session := model.StartChat()
+	session.History = contents[:len(contents)-1]
+	return session, contents[len(contents)-1].Parts
+}
+
+// convertCompletionMessages converts messages to a Gemini system
+// instruction and contents. System messages make up the system instruction.
+// Messages built with ports.ToolCallsMessage become model function calls,
+// and results built with ports.ToolResultMessage user function responses,
+// named after their call when the result has no name. Consecutive messages
+// of the same role are merged, as Gemini expects turns to alternate, and
+// the contents always end with a user turn, the one sent.
+func convertCompletionMessages(msgs []libports.Message) (*genai.Content, []*genai.Content) {
+	var system *genai.Content
+	var contents []*genai.Content
+	add := func(role string, parts ...genai.Part) {
+		if n := len(contents); n > 0 && contents[n-1].Role == role {
+			contents[n-1].Parts = append(contents[n-1].Parts, parts...)
+			return
+		}
+		contents = append(contents, &genai.Content{Role: role, Parts: parts})
+	}
+
+	names := map[string]string{}
+	for _, msg := range msgs {
+		if content, calls, ok := ports.ParseToolCallsMessage(msg); ok {
+			var parts []genai.Part
+			if content != "" {
+				parts = append(parts, genai.Text(content))
+			}
+			for _, call := range calls {
+				names[call.ID] = call.Name
+				parts = append(parts, genai.FunctionCall{Name: call.Name, Args: call.Arguments})
+			}
+			add("model", parts...)
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			name := result.Name
+			if name == "" {
+				name = names[result.ToolCallID]
+			}
+			response := map[string]any{"content": result.Content}
+			if result.IsError {
+				response = map[string]any{"error": result.Content}
+			}
+			add("user", genai.FunctionResponse{Name: name, Response: response})
+			continue
+		}
+
+		switch msg.Role {
+		case "system":
+			if system == nil {
+				system = &genai.Content{}
+			}
+			system.Parts = append(system.Parts, genai.Text(msg.Content))
+		case "assistant":
+			add("model", genai.Text(msg.Content))
+		default:
+			add("user", genai.Text(msg.Content))
+		}
+	}
+
+	if len(contents) == 0 || contents[len(contents)-1].Role != "user" {
+		contents = append(contents, &genai.Content{Role: "user", Parts: []genai.Part{genai.Text("")}})
+	}
+	return system, contents
+}
+
+// convertTools converts tools to Gemini function declarations, all in one
+// tool as the API expects
+func convertTools(tools []libports.Tool) []*genai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	tool := &genai.Tool{}
+	for _, t := range tools {
+		tool.FunctionDeclarations = append(tool.FunctionDeclarations, &genai.FunctionDeclaration{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  convertSchema(t.Parameters),
+		})
+	}
+	return []*genai.Tool{tool}
+}
+
+// toCompletionResponse converts the first candidate of a Gemini response,
+// giving its function calls random IDs
+func toCompletionResponse(model string, resp *genai.GenerateContentResponse) *libports.CompletionResponse {
+	completion := &libports.CompletionResponse{
+		Model:     model,
+		Message:   libports.Message{Role: "assistant"},
+		CreatedAt: time.Now(),
+	}
+	if resp.UsageMetadata != nil {
+		completion.Usage = libports.UsageInfo{
+			PromptTokens:     int(resp.UsageMetadata.PromptTokenCount),
+			CompletionTokens: int(resp.UsageMetadata.CandidatesTokenCount),
+			TotalTokens:      int(resp.UsageMetadata.TotalTokenCount),
+		}
+	}
+	if len(resp.Candidates) == 0 {
+		return completion
+	}
+
+	candidate := resp.Candidates[0]
+	if candidate.Content != nil {
+		for _, part := range candidate.Content.Parts {
+			switch part := part.(type) {
+			case genai.Text:
+				completion.Message.Content += string(part)
+			case genai.FunctionCall:
+				completion.ToolCalls = append(completion.ToolCalls, libports.ToolCall{
+					ID:        newToolCallID(),
+					Name:      part.Name,
+					Arguments: part.Args,
+				})
+			}
+		}
+	}
+	completion.FinishReason = finishReason(candidate.FinishReason)
+	if len(completion.ToolCalls) > 0 && candidate.FinishReason == genai.FinishReasonStop {
+		completion.FinishReason = "tool_calls"
+	}
+	return completion
+}
+
+// finishReason maps Gemini finish reasons to the OpenAI ones used across
+// adapters. Reasons OpenAI has no equivalent for are "other".
+func finishReason(reason genai.FinishReason) string {
+	switch reason {
+	case genai.FinishReasonStop:
+		return "stop"
+	case genai.FinishReasonMaxTokens:
+		return "length"
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation:
+		return "content_filter"
+	case genai.FinishReasonUnspecified:
+		return ""
+	default:
+		return "other"
+	}
+}
+
+// newToolCallID returns a random tool call ID
+func newToolCallID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "call_" + hex.EncodeToString(b)
+}
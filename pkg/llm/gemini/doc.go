@@ -18,7 +18,7 @@
 //	}
 //	defer client.Close()
 //
-//	resp, err := client.GenerateCompletion(ctx, &domain.LLMRequest{
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
 //		Model: "gemini-2.0-flash-exp",
 //		Messages: []domain.Message{
 //			{Role: "user", Content: "Hello!"},
@@ -27,4 +27,17 @@
 //
 // Note: Gemini uses "model" role instead of "assistant" role.
 // This adapter handles the conversion automatically.
+//
+// CompleteWithTools sends tools as function declarations, converting their
+// JSON schemas to the OpenAPI subset Gemini accepts, and system messages as
+// the system instruction. Earlier turns encoded with ports.ToolCallsMessage
+// and ports.ToolResultMessage are sent as function call and function
+// response parts, so it can drive agent.RunToolLoop. Gemini doesn't
+// identify function calls: the tool calls returned get random IDs, and
+// results are matched to their call by name.
+//
+// CompleteStructured asks for an application/json response constrained by
+// the schema (responseSchema), converted like tool parameters: keywords
+// outside Gemini's OpenAPI subset, such as additionalProperties, are
+// dropped.
 package gemini
@@ -0,0 +1,80 @@
+package gemini
+
+import (
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// schemaTypes maps JSON schema types to Gemini schema types
+var schemaTypes = map[string]genai.Type{
+	"string":  genai.TypeString,
+	"number":  genai.TypeNumber,
+	"integer": genai.TypeInteger,
+	"boolean": genai.TypeBoolean,
+	"array":   genai.TypeArray,
+	"object":  genai.TypeObject,
+}
+
+// convertSchema converts a JSON schema to the OpenAPI subset Gemini
+// accepts: type, format, description, enum, items, properties and required.
+// A type list with "null" sets Nullable; other keywords are dropped, as the
+// API rejects them. A nil schema converts to nil.
+func convertSchema(schema map[string]interface{}) *genai.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	converted := &genai.Schema{}
+	switch t := schema["type"].(type) {
+	case string:
+		converted.Type = schemaTypes[t]
+	case []interface{}:
+		for _, name := range t {
+			if name == "null" {
+				converted.Nullable = true
+			} else if s, ok := name.(string); ok && converted.Type == genai.TypeUnspecified {
+				converted.Type = schemaTypes[s]
+			}
+		}
+	}
+	if nullable, ok := schema["nullable"].(bool); ok {
+		converted.Nullable = converted.Nullable || nullable
+	}
+	converted.Format, _ = schema["format"].(string)
+	converted.Description, _ = schema["description"].(string)
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, value := range enum {
+			converted.Enum = append(converted.Enum, fmt.Sprint(value))
+		}
+		if converted.Type == genai.TypeUnspecified {
+			converted.Type = genai.TypeString
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		converted.Items = convertSchema(items)
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		converted.Properties = make(map[string]*genai.Schema, len(properties))
+		for name, property := range properties {
+			if property, ok := property.(map[string]interface{}); ok {
+				converted.Properties[name] = convertSchema(property)
+			}
+		}
+		if converted.Type == genai.TypeUnspecified {
+			converted.Type = genai.TypeObject
+		}
+	}
+	switch required := schema["required"].(type) {
+	case []string:
+		converted.Required = append(converted.Required, required...)
+	case []interface{}:
+		for _, name := range required {
+			if s, ok := name.(string); ok {
+				converted.Required = append(converted.Required, s)
+			}
+		}
+	}
+	return converted
+}
@@ -0,0 +1,127 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+)
+
+// CompleteStream streams a completion with tools, if any
+// (ports.LLMStreamer interface). Text deltas are sent as they arrive, and
+// function calls, with random IDs, as single fragments when they are
+// complete; the text, tool calls, finish reason and usage on the last
+// chunk. Cancelling ctx ends the stream with the text generated so far.
+func (c *Client) CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan ports.StreamChunk, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	model := c.generativeModel(req)
+	model.Tools = convertTools(tools)
+	session, last := startChat(model, req.Messages)
+
+	// The request is sent by the first Next, so errors before the stream
+	// starts are returned
+	responses := session.SendMessageStream(ctx, last...)
+	first, err := responses.Next()
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	chunks := make(chan ports.StreamChunk)
+
+	go func() {
+		defer close(chunks)
+
+		var content strings.Builder
+		send := func(chunk ports.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// The last chunk is sent even after a cancellation, with the text
+		// so far
+		finish := func(chunk ports.StreamChunk) {
+			chunk.Content = content.String()
+			chunk.Done = true
+			chunks <- chunk
+		}
+		cancelled := func() {
+			c.logger.Debug("stream cancelled", zap.Int("content_length", content.Len()))
+			finish(ports.StreamChunk{Cancelled: true})
+		}
+
+		var final ports.StreamChunk
+		resp := first
+		for {
+			completion := toCompletionResponse(req.Model, resp)
+			if completion.FinishReason != "" {
+				final.FinishReason = completion.FinishReason
+			}
+			if resp.UsageMetadata != nil {
+				final.Usage = &completion.Usage
+			}
+			content.WriteString(completion.Message.Content)
+			if completion.Message.Content != "" && !send(ports.StreamChunk{Delta: completion.Message.Content}) {
+				cancelled()
+				return
+			}
+			for _, call := range completion.ToolCalls {
+				final.ToolCalls = append(final.ToolCalls, call)
+				delta := ports.WholeToolCallDelta(len(final.ToolCalls)-1, call)
+				if !send(ports.StreamChunk{ToolCallDelta: delta}) {
+					cancelled()
+					return
+				}
+			}
+
+			var err error
+			resp, err = responses.Next()
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					cancelled()
+					return
+				}
+				c.logger.Error("API call failed", zap.Error(err))
+				finish(ports.StreamChunk{Err: fmt.Errorf("API call failed: %w", err)})
+				return
+			}
+		}
+
+		// Function calls end with a stop
+		if len(final.ToolCalls) > 0 && final.FinishReason == "stop" {
+			final.FinishReason = "tool_calls"
+		}
+		c.logger.Debug("completion streamed", zap.Int("tool_calls", len(final.ToolCalls)))
+		finish(final)
+	}()
+
+	return chunks, nil
+}
+
+// StreamComplete streams the text of a completion, as libports'
+// CompletionChunk, through CompleteStream
+func (c *Client) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks, err := c.CompleteStream(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ports.CompletionChunks(ctx, chunks), nil
+}
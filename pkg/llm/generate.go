@@ -0,0 +1,38 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Generator is implemented by the clients with a typed GenerateCompletion,
+// which all the adapters of this module are
+type Generator interface {
+	Generate(ctx context.Context, req *domain.LLMRequest) (*domain.LLMResponse, error)
+}
+
+// Generate generates a completion of a domain request with client, through
+// its Generate method when it is a Generator, or else GenerateCompletion,
+// whose response must then be a *domain.LLMResponse.
+func Generate(ctx context.Context, client libports.LLMClient, req *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if g, ok := client.(Generator); ok {
+		return g.Generate(ctx, req)
+	}
+	if req == nil {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	resp, err := client.GenerateCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	llmResp, ok := resp.(*domain.LLMResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type %T", resp)
+	}
+	return llmResp, nil
+}
@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// untypedClient only has GenerateCompletion, answering with resp
+type untypedClient struct {
+	libports.LLMClient
+	resp interface{}
+}
+
+func (c *untypedClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	return c.resp, nil
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	req := &domain.LLMRequest{Model: "gpt-4o", Messages: []domain.Message{{Role: "user", Content: "Hello"}}}
+
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Hi"}, InputTokens: 3, OutputTokens: 1})
+	client, err := NewClient(&Config{Provider: "openai", APIKey: "test-key", BaseURL: srv.BaseURL()})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, ok := client.(Generator); !ok {
+		t.Fatalf("%T is not a Generator", client)
+	}
+	resp, err := Generate(ctx, client, req)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Content != "Hi" || resp.Usage.InputTokens != 3 {
+		t.Errorf("Generate() = %+v, want the reply", resp)
+	}
+	if _, err := Generate(ctx, client, nil); !errors.Is(err, ports.ErrInvalidRequest) {
+		t.Errorf("Generate(nil) error = %v, want ErrInvalidRequest", err)
+	}
+
+	// Other clients fall back to GenerateCompletion
+	resp, err = Generate(ctx, &untypedClient{resp: &domain.LLMResponse{Content: "Hey"}}, req)
+	if err != nil || resp.Content != "Hey" {
+		t.Errorf("Generate(untyped) = %+v, %v, want its response", resp, err)
+	}
+	if _, err := Generate(ctx, &untypedClient{resp: "Hey"}, req); err == nil {
+		t.Error("Generate() of a client answering a string succeeded")
+	}
+}
@@ -0,0 +1,38 @@
+package groq
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/openai"
+	"go.uber.org/zap"
+)
+
+// DefaultBaseURL is the OpenAI-compatible endpoint of the Groq API
+const DefaultBaseURL = "https://api.groq.com/openai/v1"
+
+// Client implements the LLMClient interface for the models hosted by Groq.
+// The Groq API is OpenAI-compatible, so requests are made by the OpenAI
+// adapter; calls rejected for exceeding Groq's rate limits fail with a
+// *ports.RateLimitError.
+type Client struct {
+	*openai.Client
+}
+
+// NewClient creates a new Groq client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	httpClient := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}}
+	client, err := openai.NewClientWithHTTPClient(apiKey, baseURL, httpClient, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: client}, nil
+}
@@ -0,0 +1,155 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client == nil {
+		t.Error("NewClient() returned nil client")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "llama-3.3-70b-versatile", Reply: srv.Reply})
+}
+
+// Integration test - only runs with GROQ_API_KEY environment variable
+func TestGenerateCompletion_Integration(t *testing.T) {
+	apiKey := os.Getenv("GROQ_API_KEY")
+	if apiKey == "" {
+		t.Skip("GROQ_API_KEY not set, skipping integration test")
+	}
+
+	client, err := NewClient(apiKey, "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := &domain.LLMRequest{
+		Model: "llama-3.1-8b-instant",
+		Messages: []domain.Message{
+			{Role: "user", Content: "Say 'Hello, World!' and nothing else."},
+		},
+		MaxTokens:   50,
+		Temperature: 0.0,
+	}
+
+	resp, err := client.GenerateCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+
+	llmResp, ok := resp.(*domain.LLMResponse)
+	if !ok {
+		t.Fatal("Response is not *domain.LLMResponse")
+	}
+
+	if llmResp.Content == "" {
+		t.Error("Response content is empty")
+	}
+
+	if llmResp.Usage.InputTokens == 0 {
+		t.Error("Input tokens is 0")
+	}
+
+	t.Logf("Response: %s", llmResp.Content)
+	t.Logf("Usage: %d input tokens, %d output tokens",
+		llmResp.Usage.InputTokens,
+		llmResp.Usage.OutputTokens)
+}
+
+func TestRateLimitError(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+	req := libports.CompletionRequest{Model: "llama-3.3-70b-versatile", Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+
+	srv.Reply(testutil.Reply{
+		Status: http.StatusTooManyRequests,
+		Error:  "Rate limit reached for model `llama-3.3-70b-versatile` on tokens per minute (TPM)",
+		Header: http.Header{
+			"Retry-After":                    {"7"},
+			"X-Ratelimit-Limit-Requests":     {"14400"},
+			"X-Ratelimit-Limit-Tokens":       {"6000"},
+			"X-Ratelimit-Remaining-Requests": {"14370"},
+			"X-Ratelimit-Remaining-Tokens":   {"0"},
+			"X-Ratelimit-Reset-Requests":     {"2m59.56s"},
+			"X-Ratelimit-Reset-Tokens":       {"7.66s"},
+		},
+	})
+	_, err := client.CompleteWithTools(context.Background(), req, nil)
+
+	var rateErr *ports.RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("CompleteWithTools() error = %v, want a RateLimitError", err)
+	}
+	if rateErr.RetryAfter != 7*time.Second || !strings.Contains(rateErr.Message, "tokens per minute") {
+		t.Errorf("RateLimitError = %+v, want the retry-after and message", rateErr)
+	}
+	want := ports.RateLimits{
+		LimitRequests:     14400,
+		LimitTokens:       6000,
+		RemainingRequests: 14370,
+		RemainingTokens:   0,
+		ResetRequests:     2*time.Minute + 59560*time.Millisecond,
+		ResetTokens:       7660 * time.Millisecond,
+	}
+	if rateErr.Limits != want {
+		t.Errorf("Limits = %+v, want %+v", rateErr.Limits, want)
+	}
+
+	// Streams fail before starting, and other errors are left alone
+	srv.Reply(testutil.Reply{Status: http.StatusTooManyRequests, Error: "slow down"})
+	if _, err := client.CompleteStream(context.Background(), req, nil); !errors.As(err, &rateErr) || rateErr.Message != "slow down" {
+		t.Errorf("CompleteStream() error = %v, want a RateLimitError", err)
+	}
+	srv.Reply(testutil.Reply{Status: http.StatusServiceUnavailable, Error: "over capacity"})
+	if _, err := client.Complete(context.Background(), req); err == nil || errors.As(err, &rateErr) {
+		t.Errorf("Complete() error = %v, want a plain API error", err)
+	}
+}
+
+func TestRateLimitError_Reset(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"retry-after", http.Header{"Retry-After": {"2"}, "X-Ratelimit-Remaining-Tokens": {"0"}, "X-Ratelimit-Reset-Tokens": {"30s"}}, 2 * time.Second},
+		{"tokens exhausted", http.Header{"X-Ratelimit-Remaining-Tokens": {"0"}, "X-Ratelimit-Reset-Tokens": {"7.66s"}, "X-Ratelimit-Reset-Requests": {"1h"}}, 7660 * time.Millisecond},
+		{"both exhausted", http.Header{"X-Ratelimit-Remaining-Requests": {"0"}, "X-Ratelimit-Reset-Requests": {"1m"}, "X-Ratelimit-Remaining-Tokens": {"0"}, "X-Ratelimit-Reset-Tokens": {"7s"}}, 7 * time.Second},
+		{"requests exhausted", http.Header{"X-Ratelimit-Remaining-Requests": {"0"}, "X-Ratelimit-Reset-Requests": {"7h12m"}}, 0},
+		{"unknown", http.Header{"Retry-After": {"soon"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateLimitError(tt.header, []byte("too many requests")).RetryAfter; got != tt.want {
+				t.Errorf("RetryAfter = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Package groq implements the LLM client adapter for the models hosted by
+// Groq, whose LPU inference serves open models at very low latency.
+//
+// This adapter implements the ports.LLMClient interface defined in dago-libs.
+// The Groq API is OpenAI-compatible: completions, tool calling, structured
+// output and streaming are those of the openai adapter, sent to Groq.
+//
+// Supported models:
+//   - llama-3.3-70b-versatile
+//   - llama-3.1-8b-instant
+//   - openai/gpt-oss-120b
+//   - qwen/qwen3-32b
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/llm/groq"
+//
+//	client, err := groq.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
+//		Model: "llama-3.3-70b-versatile",
+//		Messages: []domain.Message{
+//			{Role: "user", Content: "Hello!"},
+//		},
+//	})
+//
+// Groq enforces per-model limits of requests per day and tokens per minute.
+// Calls exceeding them fail with a *ports.RateLimitError holding the limits
+// reported in Groq's x-ratelimit headers and the wait before retrying: the
+// retry-after header, or the time until the tokens per minute reset. The
+// daily requests limit gives no wait: its reset is in Limits.ResetRequests.
+// llm.WithRetry waits for RetryAfter before retrying, unless it is over
+// a minute; callers retrying themselves can read it:
+//
+//	var rateErr *ports.RateLimitError
+//	if errors.As(err, &rateErr) {
+//		time.Sleep(rateErr.RetryAfter)
+//	}
+package groq
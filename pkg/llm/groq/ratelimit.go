@@ -0,0 +1,101 @@
+package groq
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// Limit of the error bodies read
+const maxErrorBody = 64 << 10
+
+// rateLimitTransport fails requests answered with 429 Too Many Requests
+// with a *ports.RateLimitError, holding the rate limit headers of Groq.
+// Responses are otherwise left for the OpenAI SDK to handle.
+type rateLimitTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return nil, rateLimitError(resp.Header, body)
+}
+
+// rateLimitError returns the error of a 429 response. Groq sends how long
+// to wait in retry-after; without it, the wait is until the tokens per
+// minute reset, when they are exhausted. The requests reset is a daily
+// window, too far off to be a retry hint, so exhausted requests leave
+// RetryAfter zero.
+func rateLimitError(header http.Header, body []byte) *ports.RateLimitError {
+	limits := rateLimits(header)
+	rateErr := &ports.RateLimitError{
+		RetryAfter: retryAfter(header.Get("retry-after")),
+		Limits:     limits,
+		Message:    errorMessage(body),
+	}
+	if rateErr.RetryAfter == 0 && header.Get("x-ratelimit-remaining-tokens") == "0" {
+		rateErr.RetryAfter = limits.ResetTokens
+	}
+	return rateErr
+}
+
+// rateLimits parses the x-ratelimit headers of Groq: requests per day and
+// tokens per minute, with the time until they reset, e.g. "2m59.56s"
+func rateLimits(header http.Header) ports.RateLimits {
+	number := func(name string) int {
+		n, _ := strconv.Atoi(header.Get(name))
+		return n
+	}
+	duration := func(name string) time.Duration {
+		d, _ := time.ParseDuration(header.Get(name))
+		return d
+	}
+	return ports.RateLimits{
+		LimitRequests:     number("x-ratelimit-limit-requests"),
+		LimitTokens:       number("x-ratelimit-limit-tokens"),
+		RemainingRequests: number("x-ratelimit-remaining-requests"),
+		RemainingTokens:   number("x-ratelimit-remaining-tokens"),
+		ResetRequests:     duration("x-ratelimit-reset-requests"),
+		ResetTokens:       duration("x-ratelimit-reset-tokens"),
+	}
+}
+
+// retryAfter parses a retry-after header, in seconds or an HTTP date, zero
+// if it is missing or invalid
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// errorMessage returns the message of an OpenAI-style error body, or the
+// body itself
+func errorMessage(body []byte) string {
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+		return apiErr.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
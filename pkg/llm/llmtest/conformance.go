@@ -0,0 +1,588 @@
+package llmtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Harness tells RunConformance how to drive the client under test
+type Harness struct {
+	// Model is sent in every request
+	Model string
+
+	// Reply, if set, queues the reply of the backend to the next request,
+	// e.g. with the Reply method of a testutil server. Responses are then
+	// checked exactly, and error handling and cancellation midway through a
+	// request are checked too. Without it, the suite runs against a real
+	// model, prompted to produce the expected answers.
+	Reply func(replies ...testutil.Reply)
+
+	// Timeout bounds each call, 30s by default
+	Timeout time.Duration
+}
+
+// Streamer is implemented by clients supporting streamed completions, with
+// the signature reserved by ports.LLMClient. RunConformance checks it when
+// the client under test implements it.
+type Streamer interface {
+	StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error)
+}
+
+const (
+	conformanceText  = "Hello, World!"
+	conformanceError = "conformance test error"
+)
+
+// RunConformance checks that client honours the ports.LLMClient contract:
+//
+//   - GenerateCompletion returns the content of the reply, and its token
+//     usage when reported
+//   - Complete, CompleteWithTools and CompleteStructured return the reply,
+//     tool calls and schema-conformant data, unless they return
+//     ports.ErrNotImplemented
+//   - StreamComplete, when implemented, streams the reply and closes the
+//     channel after a single final chunk
+//   - CompleteStream, for clients implementing ports.LLMStreamer, streams
+//     tool call fragments that add up to the tool calls of its last chunk,
+//     and ends with the partial text when cancelled midway
+//   - requests of the wrong type or without messages fail with
+//     ports.ErrInvalidRequest, before reaching the provider
+//   - provider errors are returned with their message, and the client
+//     keeps working after them
+//   - cancelled and expired contexts fail with context.Canceled and
+//     context.DeadlineExceeded, promptly
+//
+// Each check runs as a subtest.
+func RunConformance(t *testing.T, client libports.LLMClient, h Harness) {
+	t.Helper()
+	if h.Timeout == 0 {
+		h.Timeout = 30 * time.Second
+	}
+	s := &suite{client: client, h: h}
+
+	t.Run("GenerateCompletion", s.testGenerateCompletion)
+	t.Run("InvalidRequest", s.testInvalidRequest)
+	t.Run("Complete", s.testComplete)
+	t.Run("CompleteWithTools", s.testCompleteWithTools)
+	t.Run("CompleteStructured", s.testCompleteStructured)
+	t.Run("StreamComplete", s.testStreamComplete)
+	t.Run("CompleteStream", s.testCompleteStream)
+	t.Run("ProviderErrors", s.testProviderErrors)
+	t.Run("Cancellation", s.testCancellation)
+}
+
+type suite struct {
+	client libports.LLMClient
+	h      Harness
+}
+
+// reply queues reply when the backend is scripted
+func (s *suite) reply(reply testutil.Reply) {
+	if s.h.Reply != nil {
+		s.h.Reply(reply)
+	}
+}
+
+func (s *suite) context(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), s.h.Timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func (s *suite) llmRequest(prompt string) *domain.LLMRequest {
+	return &domain.LLMRequest{
+		Model:     s.h.Model,
+		Messages:  []domain.Message{{Role: "user", Content: prompt}},
+		MaxTokens: 256,
+	}
+}
+
+func (s *suite) completionRequest(prompt string) libports.CompletionRequest {
+	return libports.CompletionRequest{
+		Model:     s.h.Model,
+		Messages:  []libports.Message{{Role: "user", Content: prompt}},
+		MaxTokens: 256,
+	}
+}
+
+// call is a client method taking a single prompt, used by the checks
+// common to all methods
+type call struct {
+	name string
+	do   func(ctx context.Context, prompt string) error
+}
+
+// calls returns the methods of the client that are implemented
+func (s *suite) calls(t *testing.T) []call {
+	all := []call{
+		{"GenerateCompletion", func(ctx context.Context, prompt string) error {
+			_, err := s.client.GenerateCompletion(ctx, s.llmRequest(prompt))
+			return err
+		}},
+		{"Complete", func(ctx context.Context, prompt string) error {
+			_, err := s.client.Complete(ctx, s.completionRequest(prompt))
+			return err
+		}},
+	}
+	if streamer, ok := s.client.(Streamer); ok {
+		all = append(all, call{"StreamComplete", func(ctx context.Context, prompt string) error {
+			chunks, err := streamer.StreamComplete(ctx, s.completionRequest(prompt))
+			return drain(ctx, chunks, err)
+		}})
+	}
+	if streamer, ok := s.client.(ports.LLMStreamer); ok {
+		all = append(all, call{"CompleteStream", func(ctx context.Context, prompt string) error {
+			chunks, err := streamer.CompleteStream(ctx, s.completionRequest(prompt), nil)
+			if err != nil {
+				return err
+			}
+			return drain(ctx, ports.CompletionChunks(ctx, chunks), nil)
+		}})
+	}
+
+	// Probing with a cancelled context doesn't consume scripted replies
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var implemented []call
+	for _, c := range all {
+		if errors.Is(c.do(ctx, "ping"), ports.ErrNotImplemented) {
+			t.Logf("%s not implemented", c.name)
+			continue
+		}
+		implemented = append(implemented, c)
+	}
+	return implemented
+}
+
+// skipIfNotImplemented ends the test when do fails with
+// ports.ErrNotImplemented. do is called with a cancelled context, so that
+// implemented methods fail without consuming scripted replies.
+func skipIfNotImplemented(t *testing.T, do func(ctx context.Context) error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := do(ctx); errors.Is(err, ports.ErrNotImplemented) {
+		t.Skip(err)
+	}
+}
+
+func (s *suite) testGenerateCompletion(t *testing.T) {
+	req := s.llmRequest("Say 'Hello, World!' and nothing else.")
+	skipIfNotImplemented(t, func(ctx context.Context) error {
+		_, err := s.client.GenerateCompletion(ctx, req)
+		return err
+	})
+	s.reply(testutil.Reply{Chunks: []string{"Hello", ", World!"}})
+
+	resp, err := s.client.GenerateCompletion(s.context(t), req)
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+	llmResp, ok := resp.(*domain.LLMResponse)
+	if !ok {
+		t.Fatalf("GenerateCompletion() returned %T, want *domain.LLMResponse", resp)
+	}
+	s.checkText(t, llmResp.Content)
+
+	// Usage is optional here, not all SDKs report it
+	usage := llmResp.Usage
+	if usage.InputTokens < 0 || usage.OutputTokens < 0 || (usage.InputTokens == 0) != (usage.OutputTokens == 0) {
+		t.Errorf("Usage = %+v, want input and output tokens, or none", usage)
+	}
+}
+
+// checkText checks the content of a reply to the hello world prompt
+func (s *suite) checkText(t *testing.T, content string) {
+	t.Helper()
+	if s.h.Reply != nil && content != conformanceText {
+		t.Errorf("content = %q, want %q", content, conformanceText)
+	}
+	if !strings.Contains(strings.ToLower(content), "hello") {
+		t.Errorf("content = %q, want a greeting", content)
+	}
+}
+
+func (s *suite) testInvalidRequest(t *testing.T) {
+	ctx := s.context(t)
+
+	_, err := s.client.GenerateCompletion(ctx, "not a request")
+	if !errors.Is(err, ports.ErrInvalidRequest) {
+		t.Errorf("GenerateCompletion(string) error = %v, want ErrInvalidRequest", err)
+	}
+
+	_, err = s.client.GenerateCompletion(ctx, &domain.LLMRequest{Model: s.h.Model})
+	if !errors.Is(err, ports.ErrInvalidRequest) {
+		t.Errorf("GenerateCompletion(no messages) error = %v, want ErrInvalidRequest", err)
+	}
+
+	_, err = s.client.Complete(ctx, libports.CompletionRequest{Model: s.h.Model})
+	if err == nil {
+		t.Error("Complete(no messages) succeeded, want an error")
+	} else if !errors.Is(err, ports.ErrNotImplemented) && !errors.Is(err, ports.ErrInvalidRequest) {
+		t.Errorf("Complete(no messages) error = %v, want ErrInvalidRequest", err)
+	}
+}
+
+func (s *suite) testComplete(t *testing.T) {
+	req := s.completionRequest("Say 'Hello, World!' and nothing else.")
+	skipIfNotImplemented(t, func(ctx context.Context) error {
+		_, err := s.client.Complete(ctx, req)
+		return err
+	})
+	s.reply(testutil.Reply{Chunks: []string{"Hello", ", World!"}})
+
+	resp, err := s.client.Complete(s.context(t), req)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	s.checkText(t, resp.Message.Content)
+	if resp.Message.Role != "assistant" {
+		t.Errorf("Message.Role = %q, want assistant", resp.Message.Role)
+	}
+	if resp.FinishReason == "" {
+		t.Error("FinishReason is empty")
+	}
+	checkUsage(t, resp.Usage)
+}
+
+func checkUsage(t *testing.T, usage libports.UsageInfo) {
+	t.Helper()
+	if usage.PromptTokens <= 0 || usage.CompletionTokens <= 0 {
+		t.Errorf("Usage = %+v, want prompt and completion tokens", usage)
+	}
+	if usage.TotalTokens != usage.PromptTokens+usage.CompletionTokens {
+		t.Errorf("Usage.TotalTokens = %d, want %d", usage.TotalTokens, usage.PromptTokens+usage.CompletionTokens)
+	}
+}
+
+func (s *suite) testCompleteWithTools(t *testing.T) {
+	tools := []libports.Tool{{
+		Name:        "get_weather",
+		Description: "Returns the current weather in a city",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city": map[string]interface{}{"type": "string", "description": "City name"},
+			},
+			"required": []string{"city"},
+		},
+	}}
+	req := s.completionRequest("What is the weather in Paris? Use the get_weather tool.")
+	skipIfNotImplemented(t, func(ctx context.Context) error {
+		_, err := s.client.CompleteWithTools(ctx, req, tools)
+		return err
+	})
+	s.reply(testutil.Reply{ToolCalls: []testutil.ToolCall{{Name: "get_weather", Arguments: `{"city":"Paris"}`}}})
+
+	resp, err := s.client.CompleteWithTools(s.context(t), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if len(resp.ToolCalls) == 0 {
+		t.Fatalf("ToolCalls is empty, want a get_weather call (content %q)", resp.Message.Content)
+	}
+	call := resp.ToolCalls[0]
+	if call.Name != "get_weather" {
+		t.Errorf("ToolCalls[0].Name = %q, want get_weather", call.Name)
+	}
+	if call.ID == "" {
+		t.Error("ToolCalls[0].ID is empty")
+	}
+	if city, _ := call.Arguments["city"].(string); !strings.EqualFold(city, "Paris") {
+		t.Errorf("ToolCalls[0].Arguments = %v, want city Paris", call.Arguments)
+	}
+}
+
+func (s *suite) testCompleteStructured(t *testing.T) {
+	schema := libports.JSONSchema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"born": map[string]interface{}{"type": "integer"},
+		},
+		"required":             []string{"name", "born"},
+		"additionalProperties": false,
+	}
+	req := s.completionRequest("Who wrote the first computer program? Give the name and birth year.")
+	skipIfNotImplemented(t, func(ctx context.Context) error {
+		_, err := s.client.CompleteStructured(ctx, req, schema)
+		return err
+	})
+	s.reply(testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace",`, `"born":1815}`}})
+
+	resp, err := s.client.CompleteStructured(s.context(t), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if name, ok := resp.Data["name"].(string); !ok || name == "" {
+		t.Errorf("Data = %v, want a name", resp.Data)
+	}
+	born, ok := resp.Data["born"].(float64)
+	if !ok {
+		t.Errorf("Data = %v, want a birth year", resp.Data)
+	}
+	if s.h.Reply != nil && born != 1815 {
+		t.Errorf("Data = %v, want born 1815", resp.Data)
+	}
+}
+
+func (s *suite) testStreamComplete(t *testing.T) {
+	streamer, ok := s.client.(Streamer)
+	if !ok {
+		t.Skip("client doesn't implement StreamComplete")
+	}
+	req := s.completionRequest("Say 'Hello, World!' and nothing else.")
+	skipIfNotImplemented(t, func(ctx context.Context) error {
+		chunks, err := streamer.StreamComplete(ctx, req)
+		return drain(ctx, chunks, err)
+	})
+	s.reply(testutil.Reply{Chunks: []string{"Hello", ",", " World!"}})
+
+	chunks, err := streamer.StreamComplete(s.context(t), req)
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+
+	var content strings.Builder
+	finals := 0
+	for chunk := range chunks {
+		if finals > 0 {
+			t.Errorf("chunk %+v after the final chunk", chunk)
+		}
+		content.WriteString(chunk.Delta)
+		if chunk.IsFinal {
+			finals++
+		}
+	}
+	if finals != 1 {
+		t.Errorf("got %d final chunks, want 1", finals)
+	}
+	s.checkText(t, content.String())
+}
+
+func (s *suite) testCompleteStream(t *testing.T) {
+	streamer, ok := s.client.(ports.LLMStreamer)
+	if !ok {
+		t.Skip("client doesn't implement ports.LLMStreamer")
+	}
+	tools := []libports.Tool{{
+		Name:        "get_weather",
+		Description: "Returns the current weather in a city",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city": map[string]interface{}{"type": "string", "description": "City name"},
+			},
+			"required": []string{"city"},
+		},
+	}}
+	req := s.completionRequest("What is the weather in Paris? Use the get_weather tool.")
+	skipIfNotImplemented(t, func(ctx context.Context) error {
+		_, err := streamer.CompleteStream(ctx, req, tools)
+		return err
+	})
+	s.reply(testutil.Reply{
+		Chunks:    []string{"Let me ", "check."},
+		ToolCalls: []testutil.ToolCall{{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+	})
+
+	chunks, err := streamer.CompleteStream(s.context(t), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var (
+		content strings.Builder
+		// The tool calls put together from their fragments
+		fragments []ports.ToolCallDelta
+		last      ports.StreamChunk
+		finals    int
+	)
+	for chunk := range chunks {
+		if finals > 0 {
+			t.Errorf("chunk %+v after the last chunk", chunk)
+		}
+		content.WriteString(chunk.Delta)
+		if delta := chunk.ToolCallDelta; delta != nil {
+			for len(fragments) <= delta.Index {
+				fragments = append(fragments, ports.ToolCallDelta{Index: len(fragments)})
+			}
+			fragment := &fragments[delta.Index]
+			fragment.ID += delta.ID
+			fragment.Name += delta.Name
+			fragment.Arguments += delta.Arguments
+		}
+		if chunk.Done {
+			finals++
+			last = chunk
+		}
+	}
+	if finals != 1 {
+		t.Fatalf("got %d last chunks, want 1", finals)
+	}
+	if last.Err != nil {
+		t.Fatalf("CompleteStream() stream error = %v", last.Err)
+	}
+	if s.h.Reply != nil && content.String() != "Let me check." {
+		t.Errorf("content = %q, want %q", content.String(), "Let me check.")
+	}
+	if len(last.ToolCalls) == 0 {
+		t.Fatalf("ToolCalls is empty, want a get_weather call (content %q)", content.String())
+	}
+	if last.FinishReason != "tool_calls" {
+		t.Errorf("FinishReason = %q, want tool_calls", last.FinishReason)
+	}
+	if len(fragments) != len(last.ToolCalls) {
+		t.Fatalf("fragments of %d tool calls, want %d", len(fragments), len(last.ToolCalls))
+	}
+	for i, call := range last.ToolCalls {
+		fragment := fragments[i]
+		var arguments map[string]interface{}
+		if err := json.Unmarshal([]byte(fragment.Arguments), &arguments); err != nil {
+			t.Errorf("arguments of fragments %d %q: %v", i, fragment.Arguments, err)
+		}
+		if fragment.ID != call.ID || fragment.Name != call.Name || !reflect.DeepEqual(arguments, call.Arguments) {
+			t.Errorf("fragments of call %d = %+v, want %+v", i, fragment, call)
+		}
+	}
+	if city, _ := last.ToolCalls[0].Arguments["city"].(string); last.ToolCalls[0].Name != "get_weather" || !strings.EqualFold(city, "Paris") {
+		t.Errorf("ToolCalls[0] = %+v, want get_weather(Paris)", last.ToolCalls[0])
+	}
+	if s.h.Reply != nil && last.Content != "Let me check." {
+		t.Errorf("Content = %q, want %q", last.Content, "Let me check.")
+	}
+
+	if s.h.Reply != nil {
+		s.testCompleteStreamCancellation(t, streamer)
+	}
+}
+
+// testCompleteStreamCancellation cancels a stream after its first delta,
+// which must end it with a Cancelled chunk holding the text so far
+func (s *suite) testCompleteStreamCancellation(t *testing.T, streamer ports.LLMStreamer) {
+	s.reply(testutil.Reply{Chunks: []string{"Hello", ",", " World", "!"}, Delay: 100 * time.Millisecond})
+	ctx, cancel := context.WithCancel(s.context(t))
+	defer cancel()
+	chunks, err := streamer.CompleteStream(ctx, s.completionRequest("Hello"), nil)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var received string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		received += chunk.Delta
+		if received != "" {
+			cancel()
+		}
+		last = chunk
+	}
+	if !last.Done || !last.Cancelled || last.Err != nil {
+		t.Fatalf("last chunk after cancellation = %+v, want it cancelled without error", last)
+	}
+	if received == "" || !strings.HasPrefix(last.Content, received) || last.Content == conformanceText {
+		t.Errorf("Content = %q after receiving %q, want the partial text", last.Content, received)
+	}
+}
+
+// drain reads a stream until it's closed. A stream cut before its final
+// chunk fails, with ctx's error if it's done.
+func drain(ctx context.Context, chunks <-chan libports.CompletionChunk, err error) error {
+	if err != nil {
+		return err
+	}
+	final := false
+	for chunk := range chunks {
+		final = final || chunk.IsFinal
+	}
+	if final {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.New("stream closed before its final chunk")
+}
+
+func (s *suite) testProviderErrors(t *testing.T) {
+	if s.h.Reply == nil {
+		t.Skip("provider errors are only checked against a scripted backend")
+	}
+
+	// Statuses the SDKs don't retry, so a single scripted reply is used
+	for _, call := range s.calls(t) {
+		for _, status := range []int{400, 401, 404} {
+			s.reply(testutil.Reply{Status: status, Error: conformanceError})
+
+			err := call.do(s.context(t), "Hello")
+			if err == nil {
+				t.Errorf("%s() with a %d reply succeeded, want an error", call.name, status)
+				continue
+			}
+			if !strings.Contains(err.Error(), conformanceError) {
+				t.Errorf("%s() with a %d reply: error = %v, want the provider message", call.name, status, err)
+			}
+			if errors.Is(err, ports.ErrNotImplemented) || errors.Is(err, ports.ErrInvalidRequest) {
+				t.Errorf("%s() with a %d reply: error = %v, wrongly classified", call.name, status, err)
+			}
+		}
+
+		// The client keeps working
+		if err := call.do(s.context(t), "Hello"); err != nil {
+			t.Errorf("%s() after errors: %v", call.name, err)
+		}
+	}
+}
+
+func (s *suite) testCancellation(t *testing.T) {
+	for _, call := range s.calls(t) {
+		t.Run(call.name, func(t *testing.T) {
+			// Already cancelled
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := call.do(ctx, "Hello"); !errors.Is(err, context.Canceled) {
+				t.Errorf("cancelled context: error = %v, want context.Canceled", err)
+			}
+
+			if s.h.Reply == nil {
+				// A real model can't answer within a millisecond
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				defer cancel()
+				if err := call.do(ctx, "Hello"); !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expired context: error = %v, want context.DeadlineExceeded", err)
+				}
+				return
+			}
+
+			// Cancelled while waiting for the reply
+			s.reply(testutil.Reply{Chunks: []string{"late"}, Delay: time.Minute})
+			ctx, cancel = context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			start := time.Now()
+			if err := call.do(ctx, "Hello"); !errors.Is(err, context.Canceled) {
+				t.Errorf("cancelled midway: error = %v, want context.Canceled", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("cancelled midway: returned after %s", elapsed)
+			}
+
+			// Deadline expiring while waiting for the reply
+			s.reply(testutil.Reply{Chunks: []string{"late"}, Delay: time.Minute})
+			ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if err := call.do(ctx, "Hello"); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expired midway: error = %v, want context.DeadlineExceeded", err)
+			}
+		})
+	}
+}
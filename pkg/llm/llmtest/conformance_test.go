@@ -0,0 +1,177 @@
+package llmtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+var (
+	_ libports.LLMClient = (*fakeClient)(nil)
+	_ Streamer           = (*fakeClient)(nil)
+)
+
+// fakeClient answers with scripted replies, implementing the whole contract
+type fakeClient struct {
+	mu      sync.Mutex
+	replies []testutil.Reply
+}
+
+func (c *fakeClient) Reply(replies ...testutil.Reply) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replies = append(c.replies, replies...)
+}
+
+// next waits for the next reply, echoing prompt when none is queued
+func (c *fakeClient) next(ctx context.Context, prompt string) (testutil.Reply, error) {
+	if err := ctx.Err(); err != nil {
+		return testutil.Reply{}, err
+	}
+	if prompt == "" {
+		return testutil.Reply{}, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.mu.Lock()
+	reply := testutil.Reply{Chunks: []string{prompt}}
+	if len(c.replies) > 0 {
+		reply, c.replies = c.replies[0], c.replies[1:]
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-time.After(reply.Delay):
+	case <-ctx.Done():
+		return testutil.Reply{}, ctx.Err()
+	}
+	if reply.Status != 0 {
+		return testutil.Reply{}, fmt.Errorf("API call failed: status %d: %s", reply.Status, reply.Error)
+	}
+	return reply, nil
+}
+
+func lastPrompt(messages []libports.Message) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	return messages[len(messages)-1].Content
+}
+
+func (c *fakeClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	reply, err := c.next(ctx, lastPrompt(req.Messages))
+	if err != nil {
+		return nil, err
+	}
+	resp := &libports.CompletionResponse{
+		Message:      libports.Message{Role: "assistant", Content: strings.Join(reply.Chunks, "")},
+		FinishReason: "stop",
+		Usage:        libports.UsageInfo{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+	}
+	for i, call := range reply.ToolCalls {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return nil, err
+		}
+		resp.ToolCalls = append(resp.ToolCalls, libports.ToolCall{ID: fmt.Sprintf("call_%d", i), Name: call.Name, Arguments: args})
+	}
+	return resp, nil
+}
+
+func (c *fakeClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	return c.Complete(ctx, req)
+}
+
+func (c *fakeClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	resp, err := c.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	structured := &libports.StructuredResponse{Usage: resp.Usage}
+	if err := json.Unmarshal([]byte(resp.Message.Content), &structured.Data); err != nil {
+		return nil, err
+	}
+	return structured, nil
+}
+
+func (c *fakeClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	reply, err := c.next(ctx, lastPrompt(req.Messages))
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(chan libports.CompletionChunk, len(reply.Chunks))
+	for i, chunk := range reply.Chunks {
+		chunks <- libports.CompletionChunk{Delta: chunk, IsFinal: i == len(reply.Chunks)-1}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+func (c *fakeClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	var messages []libports.Message
+	for _, msg := range llmReq.Messages {
+		messages = append(messages, libports.Message{Role: msg.Role, Content: msg.Content})
+	}
+	resp, err := c.Complete(ctx, libports.CompletionRequest{Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LLMResponse{
+		Content: resp.Message.Content,
+		Usage:   domain.Usage{InputTokens: resp.Usage.PromptTokens, OutputTokens: resp.Usage.CompletionTokens},
+	}, nil
+}
+
+// stubClient implements nothing but GenerateCompletion
+type stubClient struct {
+	*fakeClient
+}
+
+func (c stubClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return nil, fmt.Errorf("%w: Complete", ports.ErrNotImplemented)
+}
+
+func (c stubClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	return nil, fmt.Errorf("%w: CompleteWithTools", ports.ErrNotImplemented)
+}
+
+func (c stubClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	return nil, fmt.Errorf("%w: CompleteStructured", ports.ErrNotImplemented)
+}
+
+func (c stubClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	return nil, fmt.Errorf("%w: StreamComplete", ports.ErrNotImplemented)
+}
+
+func TestRunConformance(t *testing.T) {
+	t.Run("complete client", func(t *testing.T) {
+		client := &fakeClient{}
+		RunConformance(t, client, Harness{Model: "fake", Reply: client.Reply})
+
+		if len(client.replies) != 0 {
+			t.Errorf("%d scripted replies left", len(client.replies))
+		}
+	})
+
+	t.Run("partial client", func(t *testing.T) {
+		client := stubClient{&fakeClient{}}
+		RunConformance(t, client, Harness{Model: "fake", Reply: client.Reply})
+
+		// Replies are only scripted for implemented methods
+		if len(client.replies) != 0 {
+			t.Errorf("%d scripted replies left", len(client.replies))
+		}
+	})
+}
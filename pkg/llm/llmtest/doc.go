@@ -0,0 +1,36 @@
+// Package llmtest provides a conformance suite for ports.LLMClient
+// implementations, so that every provider adapter, in this repository or
+// registered from outside it with llm.RegisterProvider, is held to the same
+// contract: responses, tool calls, structured output, streaming, error
+// classification (ports.ErrInvalidRequest, ports.ErrNotImplemented) and
+// cancellation.
+//
+// Methods returning ports.ErrNotImplemented are skipped. Streaming is checked
+// for clients implementing Streamer.
+//
+// Usage, against a testutil fake of the provider API:
+//
+//	func TestConformance(t *testing.T) {
+//		srv := testutil.NewOpenAIServer(t)
+//		client, _ := openai.NewClient("test-key", srv.BaseURL(), zap.NewNop())
+//
+//		llmtest.RunConformance(t, client, llmtest.Harness{Model: "gpt-4o", Reply: srv.Reply})
+//	}
+//
+// Without Harness.Reply, the suite prompts a real model instead, e.g. in
+// integration tests gated on an API key.
+//
+// GoldenCases are the canonical requests each adapter serializes and compares
+// with its golden files (testutil.GoldenJSON).
+//
+// AddFuzzSeeds, FuzzRequest and CheckConversion are the building blocks of
+// the adapters' message conversion fuzz targets:
+//
+//	func FuzzConvertMessages(f *testing.F) {
+//		llmtest.AddFuzzSeeds(f)
+//		f.Fuzz(func(t *testing.T, system, messages string) {
+//			req := llmtest.FuzzRequest(system, messages)
+//			llmtest.CheckConversion(t, req, toPortsMessages(convertMessages(req)), "system", "user", "assistant")
+//		})
+//	}
+package llmtest
@@ -0,0 +1,99 @@
+package llmtest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Separators of the messages fuzz targets pass to FuzzRequest
+const (
+	fuzzMessageSeparator = "\x1e"
+	fuzzRoleSeparator    = "\x1f"
+)
+
+// AddFuzzSeeds adds the seed corpus of message conversion fuzz targets,
+// whose arguments are the system prompt and the messages given to
+// FuzzRequest: unknown and oddly cased roles, empty messages and prompts,
+// huge multi-byte content and invalid UTF-8
+func AddFuzzSeeds(f *testing.F) {
+	f.Helper()
+	msg := func(role, content string) string { return role + fuzzRoleSeparator + content }
+	messages := func(msgs ...string) string { return strings.Join(msgs, fuzzMessageSeparator) }
+
+	f.Add("", messages(msg("user", "Hello")))
+	f.Add("You are terse.", messages(msg("user", "Hi"), msg("assistant", "Hello"), msg("user", "Bye")))
+	f.Add("", messages(msg("system", "Be brief."), msg("user", "Hi")))
+	f.Add("", messages(msg("user", "Hi"), msg("system", "Switch to French."), msg("user", "Bonjour")))
+	f.Add("", messages(msg("tool", `{"result":42}`), msg("function", "ok"), msg("", "no role")))
+	f.Add("", messages(msg("USER", "Hi"), msg("Assistant", "Hello"), msg(" user ", "padded")))
+	f.Add("", messages(msg("user", ""), msg("assistant", ""), msg("user", "")))
+	f.Add("", messages(msg("assistant", "Only the model speaks")))
+	f.Add(strings.Repeat("系统", 1000), messages(msg("user", strings.Repeat("🦀 héllo ", 10000))))
+	f.Add("\xff\xfe", messages(msg("user\x00", "\xc3\x28 invalid \xed\xa0\x80 utf-8")))
+}
+
+// FuzzRequest builds the request of a message conversion fuzz target.
+// Messages are separated by '\x1e', and a message's role from its content by
+// '\x1f'; without it, the message has no role. Messages encoding tool calls
+// or results are left out, as adapters convert them to their provider's
+// structures rather than text.
+func FuzzRequest(system, messages string) *domain.LLMRequest {
+	req := &domain.LLMRequest{Model: "fuzz", System: system}
+	for _, part := range strings.Split(messages, fuzzMessageSeparator) {
+		role, content, found := strings.Cut(part, fuzzRoleSeparator)
+		if !found {
+			role, content = "", part
+		}
+		msg := domain.Message{Role: role, Content: content}
+		if isToolMessage(msg) {
+			continue
+		}
+		req.Messages = append(req.Messages, msg)
+	}
+	return req
+}
+
+// isToolMessage reports whether msg encodes tool calls or a tool result
+func isToolMessage(msg domain.Message) bool {
+	converted := ports.CompletionMessages(&domain.LLMRequest{Messages: []domain.Message{msg}})[0]
+	if _, _, ok := ports.ParseToolCallsMessage(converted); ok {
+		return true
+	}
+	_, ok := ports.ParseToolResultMessage(converted)
+	return ok
+}
+
+// CheckConversion fails the test if converted, the messages an adapter built
+// from req in its provider's format, lost or altered text: each message and
+// the system prompt, if any, must appear exactly once, in a message of one of
+// roles. Adapters may move system messages, so order isn't checked.
+func CheckConversion(t *testing.T, req *domain.LLMRequest, converted []libports.Message, roles ...string) {
+	t.Helper()
+
+	var want []string
+	if req.System != "" {
+		want = append(want, req.System)
+	}
+	for _, msg := range req.Messages {
+		want = append(want, msg.Content)
+	}
+
+	got := make([]string, 0, len(converted))
+	for _, msg := range converted {
+		if !slices.Contains(roles, msg.Role) {
+			t.Errorf("converted message has role %q, want one of %q", msg.Role, roles)
+		}
+		got = append(got, msg.Content)
+	}
+
+	slices.Sort(want)
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("converted %d messages to %d, contents %q, want %q", len(want), len(got), got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package llmtest
+
+import (
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/domain"
+)
+
+// GoldenCase is a canonical request whose serialization adapters compare
+// with a golden file, named after the case
+type GoldenCase struct {
+	Name    string
+	Request *domain.LLMRequest
+}
+
+// GoldenCases returns the canonical requests adapters check their wire
+// format against with testutil.GoldenJSON, so that SDK upgrades changing
+// what is sent show up as golden file diffs
+func GoldenCases() []GoldenCase {
+	return []GoldenCase{
+		{"simple", &domain.LLMRequest{
+			Model:    "test-model",
+			Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		}},
+		{"system_prompt", &domain.LLMRequest{
+			Model:    "test-model",
+			System:   "You are a concise assistant.",
+			Messages: []domain.Message{{Role: "user", Content: "Summarize Go in one sentence."}},
+		}},
+		{"multi_turn", &domain.LLMRequest{
+			Model: "test-model",
+			Messages: []domain.Message{
+				{Role: "user", Content: "What is 2 + 2?"},
+				{Role: "assistant", Content: "4"},
+				{Role: "user", Content: "And times 3?"},
+			},
+		}},
+		{"sampling", &domain.LLMRequest{
+			Model:       "test-model",
+			Messages:    []domain.Message{{Role: "user", Content: "Write a haiku."}},
+			Temperature: 0.7,
+			MaxTokens:   256,
+		}},
+		{"roles", &domain.LLMRequest{
+			Model:  "test-model",
+			System: "Answer in English.",
+			Messages: []domain.Message{
+				{Role: "user", Content: "Hi"},
+				{Role: "system", Content: "Switch to French."},
+				{Role: "tool", Content: `{"temperature": 21}`},
+				{Role: "user", Content: "Quel temps fait-il ?"},
+			},
+		}},
+		{"tool_results", &domain.LLMRequest{
+			Model: "test-model",
+			Messages: []domain.Message{
+				{Role: "user", Content: "What's the weather in Paris and London?"},
+				ports.ToolCallsDomainMessage(&domain.LLMResponse{ToolCalls: []domain.ToolCall{
+					{ID: "call_1", Name: "get_weather", Input: map[string]interface{}{"city": "Paris"}},
+					{ID: "call_2", Name: "get_weather", Input: map[string]interface{}{"city": "London"}},
+				}}),
+				ports.ToolResultDomainMessage(&ports.ToolResult{ToolCallID: "call_1", Name: "get_weather", Content: "21°C, sunny"}),
+				ports.ToolResultDomainMessage(&ports.ToolResult{ToolCallID: "call_2", Name: "get_weather", Content: "unknown city", IsError: true}),
+			},
+		}},
+		{"unicode", &domain.LLMRequest{
+			Model:    "test-model",
+			Messages: []domain.Message{{Role: "user", Content: "Traduis « 你好 » 🦀 \"quoted\" <tag> & \\ backslash"}},
+		}},
+	}
+}
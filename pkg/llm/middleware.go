@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/internal/retry"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+const instrumentationName = "github.com/aescanero/dago-adapters/pkg/llm"
+
+// Middleware wraps an LLM client with cross-cutting behavior. The clients
+// it returns only have the ports.LLMClient methods: streaming and the
+// provider-specific methods of the adapters are reached on the unwrapped
+// client.
+type Middleware func(libports.LLMClient) libports.LLMClient
+
+// Chain wraps client with the middleware, the first one outermost
+func Chain(client libports.LLMClient, middleware ...Middleware) libports.LLMClient {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		client = middleware[i](client)
+	}
+	return client
+}
+
+// WithRetry retries calls that failed transiently (rate limits, server
+// errors and network failures) up to maxAttempts in total, doubling the wait
+// from backoff between attempts. Other errors, e.g. ports.ErrInvalidRequest
+// or an authentication failure, are returned at once. Calls rejected with a
+// *ports.RateLimitError are retried after its RetryAfter instead, when the
+// provider said how long to wait, or returned at once when that is over a
+// minute. It gives up early when ctx ends.
+func WithRetry(maxAttempts int, backoff time.Duration) Middleware {
+	return func(next libports.LLMClient) libports.LLMClient {
+		return &interceptClient{
+			client: next,
+			around: func(ctx context.Context, method string, call func(ctx context.Context) (libports.UsageInfo, error)) error {
+				wait := backoff
+
+				for attempt := 1; ; attempt++ {
+					_, err := call(ctx)
+					if err == nil || !retry.Retryable(err) || attempt >= maxAttempts || ctx.Err() != nil {
+						return err
+					}
+
+					delay, ok := retry.Delay(err, wait)
+					if !ok {
+						return err
+					}
+
+					timer := time.NewTimer(delay)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return err
+					case <-timer.C:
+					}
+					wait *= 2
+				}
+			},
+		}
+	}
+}
+
+// WithMetrics reports calls through the global OpenTelemetry MeterProvider:
+// dago.llm.requests (by provider, method and result), .duration and .tokens.
+func WithMetrics(provider string) Middleware {
+	meter := otel.Meter(instrumentationName)
+
+	// Instruments are still usable (no-op) when creation fails
+	requests, _ := meter.Int64Counter("dago.llm.requests",
+		metric.WithDescription("LLM requests"))
+	duration, _ := meter.Float64Histogram("dago.llm.duration",
+		metric.WithDescription("Duration of LLM requests"),
+		metric.WithUnit("s"))
+	tokens, _ := meter.Int64Counter("dago.llm.tokens",
+		metric.WithDescription("Tokens billed for LLM requests"))
+
+	return func(next libports.LLMClient) libports.LLMClient {
+		return &interceptClient{
+			client: next,
+			around: func(ctx context.Context, method string, call func(ctx context.Context) (libports.UsageInfo, error)) error {
+				start := time.Now()
+				usage, err := call(ctx)
+
+				result := "ok"
+				if err != nil {
+					result = "error"
+				}
+				attrs := metric.WithAttributes(
+					attribute.String("provider", provider),
+					attribute.String("method", method),
+					attribute.String("result", result))
+
+				requests.Add(ctx, 1, attrs)
+				duration.Record(ctx, time.Since(start).Seconds(), attrs)
+				if err == nil {
+					tokens.Add(ctx, int64(usage.TotalTokens), metric.WithAttributes(
+						attribute.String("provider", provider),
+						attribute.String("method", method)))
+				}
+
+				return err
+			},
+		}
+	}
+}
+
+// WithCache answers repeated requests from c, for ttl, as NewCachedClient
+func WithCache(c ports.Cache, ttl time.Duration) Middleware {
+	return func(next libports.LLMClient) libports.LLMClient {
+		return NewCachedClient(next, c, ttl, zap.NewNop())
+	}
+}
+
+// interceptClient runs every call of client through around, with the name
+// of the method called. call returns the usage of the response it got.
+type interceptClient struct {
+	client libports.LLMClient
+	around func(ctx context.Context, method string, call func(ctx context.Context) (libports.UsageInfo, error)) error
+}
+
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *interceptClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	var resp *libports.CompletionResponse
+	err := c.around(ctx, "complete", func(ctx context.Context) (libports.UsageInfo, error) {
+		var err error
+		resp, err = c.client.Complete(ctx, req)
+		if err != nil {
+			return libports.UsageInfo{}, err
+		}
+		return resp.Usage, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CompleteWithTools performs a completion with tool calling support (ports.LLMClient interface)
+func (c *interceptClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	var resp *libports.CompletionResponse
+	err := c.around(ctx, "complete_with_tools", func(ctx context.Context) (libports.UsageInfo, error) {
+		var err error
+		resp, err = c.client.CompleteWithTools(ctx, req, tools)
+		if err != nil {
+			return libports.UsageInfo{}, err
+		}
+		return resp.Usage, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema conformance (ports.LLMClient interface)
+func (c *interceptClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	var resp *libports.StructuredResponse
+	err := c.around(ctx, "complete_structured", func(ctx context.Context) (libports.UsageInfo, error) {
+		var err error
+		resp, err = c.client.CompleteStructured(ctx, req, schema)
+		if err != nil {
+			return libports.UsageInfo{}, err
+		}
+		return resp.Usage, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GenerateCompletion generates a completion using domain.LLMRequest
+// (compatibility method). Its usage isn't reported.
+func (c *interceptClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	var resp interface{}
+	err := c.around(ctx, "generate_completion", func(ctx context.Context) (libports.UsageInfo, error) {
+		var err error
+		resp, err = c.client.GenerateCompletion(ctx, req)
+		return libports.UsageInfo{}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Close closes the wrapped client if it has a Close method
+func (c *interceptClient) Close() error {
+	if closer, ok := c.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/cache/lru"
+	"github.com/aescanero/dago-adapters/pkg/llm/groq"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// flakyClient fails its first calls, with err or else a 503, then echoes
+// the request
+type flakyClient struct {
+	libports.LLMClient
+	calls int
+	fail  int
+	err   error
+}
+
+func (c *flakyClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	c.calls++
+	if c.fail > 0 {
+		c.fail--
+		if c.err != nil {
+			return nil, c.err
+		}
+		return nil, &ports.StatusError{StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+	}
+	return &libports.CompletionResponse{Model: req.Model, Usage: libports.UsageInfo{TotalTokens: 3}}, nil
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	req := libports.CompletionRequest{Model: "gpt-4o-mini"}
+
+	inner := &flakyClient{fail: 2}
+	if _, err := WithRetry(3, 0)(inner).Complete(ctx, req); err != nil {
+		t.Errorf("Complete() error = %v, want success on third attempt", err)
+	}
+
+	inner = &flakyClient{fail: 3}
+	resp, err := WithRetry(3, 0)(inner).Complete(ctx, req)
+	if err == nil || resp != nil {
+		t.Errorf("Complete() = %+v, %v, want an error after exhausting attempts", resp, err)
+	}
+	if inner.calls != 3 {
+		t.Errorf("made %d attempts, want 3", inner.calls)
+	}
+}
+
+func TestWithRetry_NotRetryable(t *testing.T) {
+	req := libports.CompletionRequest{Model: "gpt-4o-mini"}
+
+	for _, err := range []error{
+		fmt.Errorf("%w: no messages", ports.ErrInvalidRequest),
+		fmt.Errorf("complete: %w", ports.ErrNotImplemented),
+		fmt.Errorf("API call failed: %w", &ports.StatusError{StatusCode: http.StatusUnauthorized, Message: "invalid API key"}),
+		errors.New("unexpected response"),
+	} {
+		inner := &flakyClient{fail: 3, err: err}
+		if _, got := WithRetry(3, time.Hour)(inner).Complete(context.Background(), req); !errors.Is(got, err) {
+			t.Errorf("Complete() error = %v, want %v", got, err)
+		}
+		if inner.calls != 1 {
+			t.Errorf("made %d attempts for %v, want 1", inner.calls, err)
+		}
+	}
+}
+
+func TestWithRetry_RetryAfter(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	client, _ := groq.NewClient("test-key", srv.BaseURL(), zap.NewNop())
+	req := libports.CompletionRequest{Model: "llama-3.3-70b-versatile", Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+
+	// The backoff would outlast the test; Groq's retry-after is waited instead
+	srv.Reply(testutil.Reply{
+		Status: http.StatusTooManyRequests,
+		Error:  "Rate limit reached",
+		Header: http.Header{"Retry-After": {"0.05"}},
+	}, testutil.TextReply("Hi"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	resp, err := WithRetry(2, time.Hour)(client).Complete(ctx, req)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Message.Content != "Hi" {
+		t.Errorf("Content = %q, want %q", resp.Message.Content, "Hi")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("retried after %s, want at least the 50ms of retry-after", elapsed)
+	}
+}
+
+func TestWithRetry_LongRetryAfter(t *testing.T) {
+	req := libports.CompletionRequest{Model: "llama-3.3-70b-versatile"}
+
+	// A daily limit isn't waited out: the call fails at once
+	rateErr := &ports.RateLimitError{RetryAfter: 7 * time.Hour, Message: "requests per day"}
+	inner := &flakyClient{fail: 1, err: rateErr}
+	done := make(chan error, 1)
+	go func() {
+		_, err := WithRetry(3, 0)(inner).Complete(context.Background(), req)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, rateErr) {
+			t.Errorf("Complete() error = %v, want the RateLimitError", err)
+		}
+		if inner.calls != 1 {
+			t.Errorf("made %d attempts, want 1", inner.calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Complete() is waiting out a 7h RetryAfter")
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next libports.LLMClient) libports.LLMClient {
+			return &interceptClient{
+				client: next,
+				around: func(ctx context.Context, method string, call func(ctx context.Context) (libports.UsageInfo, error)) error {
+					order = append(order, name+":"+method)
+					_, err := call(ctx)
+					return err
+				},
+			}
+		}
+	}
+
+	inner := &flakyClient{}
+	client := Chain(inner, trace("outer"), trace("inner"), WithCache(lru.NewCache(10), 0))
+	req := libports.CompletionRequest{Model: "gpt-4o-mini", Messages: []libports.Message{{Role: "user", Content: "hi"}}}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Complete(context.Background(), req); err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+	}
+
+	if !slices.Equal(order, []string{"outer:complete", "inner:complete", "outer:complete", "inner:complete"}) {
+		t.Errorf("middleware ran in order %v", order)
+	}
+	if inner.calls != 1 {
+		t.Errorf("client called %d times, want the second call cached", inner.calls)
+	}
+}
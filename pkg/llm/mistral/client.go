@@ -0,0 +1,401 @@
+package mistral
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	jsonschema "github.com/aescanero/dago-adapters/pkg/schema"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	openai "github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Mistral API (La Plateforme) endpoint
+	DefaultBaseURL = "https://api.mistral.ai/v1"
+
+	// DefaultStructuredRetries is the number of times CompleteStructured
+	// retries responses that don't conform to the schema
+	DefaultStructuredRetries = 2
+)
+
+// Client implements the LLMClient interface for the hosted Mistral models.
+// The chat completions API of Mistral is close enough to OpenAI's to be
+// called with its SDK.
+type Client struct {
+	client *openai.Client
+	logger *zap.Logger
+
+	structuredRetries int
+}
+
+// NewClient creates a new Mistral client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	config := openai.DefaultConfig(apiKey)
+	config.BaseURL = strings.TrimSuffix(baseURL, "/")
+
+	return &Client{
+		client:            openai.NewClientWithConfig(config),
+		logger:            logger,
+		structuredRetries: DefaultStructuredRetries,
+	}, nil
+}
+
+// SetStructuredRetries sets the number of times CompleteStructured retries
+// responses that don't conform to the schema. Zero disables retries.
+func (c *Client) SetStructuredRetries(retries int) {
+	c.structuredRetries = retries
+}
+
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
+}
+
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface). Tool calls and results of earlier turns, in
+// messages built with ports.ToolCallsMessage and ports.ToolResultMessage,
+// are sent as assistant tool_calls and "tool" role messages.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Tools = convertTools(tools)
+
+	resp, err := c.client.CreateChatCompletion(ctx, chatReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	completion := &libports.CompletionResponse{
+		ID:        resp.ID,
+		Model:     resp.Model,
+		Usage:     usageInfo(resp.Usage),
+		CreatedAt: time.Unix(resp.Created, 0),
+	}
+	completion.Message.Role = openai.ChatMessageRoleAssistant
+	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		completion.Message.Content = choice.Message.Content
+		completion.ToolCalls = c.parseToolCalls(choice.Message.ToolCalls)
+		completion.FinishReason = string(choice.FinishReason)
+	}
+
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens))
+
+	return completion, nil
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance (ports.LLMClient interface), using JSON mode: the response
+// format is json_object, and the schema is given in a system message. JSON
+// mode guarantees JSON but not its conformance, so responses are validated
+// against the schema; invalid ones are sent back with the violations and
+// retried, up to the retries set with SetStructuredRetries.
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %v", ports.ErrInvalidRequest, err)
+	}
+
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Messages = append([]openai.ChatCompletionMessage{{
+		Role:    openai.ChatMessageRoleSystem,
+		Content: "Respond with a JSON object conforming to this JSON schema:\n" + string(data),
+	}}, chatReq.Messages...)
+	chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+
+	structured := &libports.StructuredResponse{}
+	for attempt := 0; ; attempt++ {
+		resp, err := c.client.CreateChatCompletion(ctx, chatReq)
+		if err != nil {
+			c.logger.Error("API call failed", zap.Error(err))
+			return nil, fmt.Errorf("API call failed: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("API call failed: no choices in response")
+		}
+		structured.Usage.PromptTokens += resp.Usage.PromptTokens
+		structured.Usage.CompletionTokens += resp.Usage.CompletionTokens
+		structured.Usage.TotalTokens += resp.Usage.TotalTokens
+
+		content := resp.Choices[0].Message.Content
+		var data map[string]interface{}
+		err = json.Unmarshal([]byte(content), &data)
+		if err == nil {
+			err = jsonschema.Validate(schema, data)
+		}
+		if err == nil {
+			structured.Data = data
+			structured.CreatedAt = time.Unix(resp.Created, 0)
+			break
+		}
+		if attempt >= c.structuredRetries {
+			return nil, fmt.Errorf("invalid structured response after %d attempts: %w", attempt+1, err)
+		}
+
+		c.logger.Warn("invalid structured response, retrying",
+			zap.String("model", req.Model),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err))
+		chatReq.Messages = append(chatReq.Messages,
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf("Your response doesn't conform to the JSON schema: %v. Respond again with only the corrected JSON object.", err)},
+		)
+	}
+
+	c.logger.Debug("structured completion generated",
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
+
+	return structured, nil
+}
+
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
+func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Generate generates a completion of a domain request
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion",
+		zap.String("model", llmReq.Model),
+		zap.Int("message_count", len(llmReq.Messages)))
+
+	chatReq := openai.ChatCompletionRequest{
+		Model:       llmReq.Model,
+		Messages:    c.convertMessages(llmReq),
+		MaxTokens:   llmReq.MaxTokens,
+		Temperature: float32(llmReq.Temperature),
+	}
+	if len(llmReq.Tools) > 0 {
+		tools := make([]libports.Tool, 0, len(llmReq.Tools))
+		for _, tool := range llmReq.Tools {
+			tools = append(tools, libports.Tool(tool))
+		}
+		chatReq.Tools = convertTools(tools)
+	}
+
+	resp, err := c.client.CreateChatCompletion(ctx, chatReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	llmResp := &domain.LLMResponse{
+		Model: resp.Model,
+		Usage: domain.Usage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		},
+	}
+	if len(resp.Choices) > 0 {
+		llmResp.Content = resp.Choices[0].Message.Content
+		for _, call := range c.parseToolCalls(resp.Choices[0].Message.ToolCalls) {
+			llmResp.ToolCalls = append(llmResp.ToolCalls, domain.ToolCall{ID: call.ID, Name: call.Name, Input: call.Arguments})
+		}
+	}
+
+	c.logger.Debug("completion generated",
+		zap.Int("input_tokens", llmResp.Usage.InputTokens),
+		zap.Int("output_tokens", llmResp.Usage.OutputTokens))
+
+	return llmResp, nil
+}
+
+// convertMessages converts the messages of a domain request to Mistral
+// format, tool calls and results included, like convertCompletionMessages
+func (c *Client) convertMessages(llmReq *domain.LLMRequest) []openai.ChatCompletionMessage {
+	return c.convertCompletionMessages(ports.CompletionMessages(llmReq))
+}
+
+// chatRequest returns the chat completion request of req, without tools or
+// response format. Mistral has no user field, and rejects unknown fields.
+func (c *Client) chatRequest(req libports.CompletionRequest) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model:            req.Model,
+		Messages:         c.convertCompletionMessages(req.Messages),
+		MaxTokens:        req.MaxTokens,
+		Temperature:      float32(req.Temperature),
+		TopP:             float32(req.TopP),
+		Stop:             req.Stop,
+		PresencePenalty:  float32(req.PresencePenalty),
+		FrequencyPenalty: float32(req.FrequencyPenalty),
+	}
+}
+
+// usageInfo converts the token usage of a response
+func usageInfo(usage openai.Usage) libports.UsageInfo {
+	return libports.UsageInfo{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
+
+// convertCompletionMessages converts messages to Mistral format. Messages
+// built with ports.ToolCallsMessage become assistant messages with tool
+// calls, and results built with ports.ToolResultMessage "tool" messages
+// naming their tool; results marked as errors are prefixed with "Error: ",
+// as the API has no field for it. Unknown roles are sent as user messages.
+func (c *Client) convertCompletionMessages(msgs []libports.Message) []openai.ChatCompletionMessage {
+	messages := make([]openai.ChatCompletionMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if content, calls, ok := ports.ParseToolCallsMessage(msg); ok {
+			message := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content}
+			for _, call := range calls {
+				arguments, _ := json.Marshal(call.Arguments)
+				if call.Arguments == nil {
+					arguments = []byte("{}")
+				}
+				message.ToolCalls = append(message.ToolCalls, openai.ToolCall{
+					ID:       toolCallID(call.ID),
+					Type:     openai.ToolTypeFunction,
+					Function: openai.FunctionCall{Name: call.Name, Arguments: string(arguments)},
+				})
+			}
+			messages = append(messages, message)
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			content := result.Content
+			if result.IsError {
+				content = "Error: " + content
+			}
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    content,
+				Name:       result.Name,
+				ToolCallID: toolCallID(result.ToolCallID),
+			})
+			continue
+		}
+
+		role := ""
+		switch msg.Role {
+		case "user":
+			role = openai.ChatMessageRoleUser
+		case "assistant":
+			role = openai.ChatMessageRoleAssistant
+		case "system":
+			role = openai.ChatMessageRoleSystem
+		default:
+			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
+			role = openai.ChatMessageRoleUser
+		}
+		messages = append(messages, openai.ChatCompletionMessage{Role: role, Content: msg.Content})
+	}
+	return messages
+}
+
+// toolCallID returns id if it is a Mistral tool call ID, nine letters and
+// digits, or one derived from it. IDs of calls made by other providers,
+// e.g. in a conversation started with another model, are rejected by the
+// API; calls and their results are mapped to the same ID.
+func toolCallID(id string) string {
+	valid := len(id) == 9
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return id
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:9]
+}
+
+// convertTools converts tools to function tools. Tools without parameters
+// take an empty object, as the API requires a schema.
+func convertTools(tools []libports.Tool) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	converted := make([]openai.Tool, 0, len(tools))
+	for _, tool := range tools {
+		var parameters any = tool.Parameters
+		if tool.Parameters == nil {
+			parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		converted = append(converted, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  parameters,
+			},
+		})
+	}
+	return converted
+}
+
+// parseToolCalls converts the tool calls of a response, decoding their
+// JSON arguments. Arguments that aren't a JSON object are logged and left
+// empty, for the tool to reject.
+func (c *Client) parseToolCalls(calls []openai.ToolCall) []libports.ToolCall {
+	var parsed []libports.ToolCall
+	for _, call := range calls {
+		var arguments map[string]interface{}
+		if call.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+				c.logger.Warn("invalid tool call arguments",
+					zap.String("tool", call.Function.Name),
+					zap.String("arguments", call.Function.Arguments),
+					zap.Error(err))
+			}
+		}
+		parsed = append(parsed, libports.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments})
+	}
+	return parsed
+}
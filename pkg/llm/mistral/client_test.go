@@ -0,0 +1,332 @@
+package mistral
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client == nil {
+		t.Error("NewClient() returned nil client")
+	}
+}
+
+func TestGenerateCompletion(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("invalid request type", func(t *testing.T) {
+		client, _ := NewClient("test-key", "", logger)
+
+		_, err := client.GenerateCompletion(context.Background(), "invalid")
+		if err == nil {
+			t.Error("GenerateCompletion() expected error for invalid request type")
+		}
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		srv := testutil.NewOpenAIServer(t)
+		srv.Reply(testutil.Reply{Chunks: []string{"Bonjour !"}, InputTokens: 12, OutputTokens: 4})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
+
+		req := &domain.LLMRequest{
+			Model:     "mistral-large-latest",
+			System:    "Be brief",
+			Messages:  []domain.Message{{Role: "user", Content: "Hello"}},
+			MaxTokens: 100,
+		}
+		resp, err := client.GenerateCompletion(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		llmResp := resp.(*domain.LLMResponse)
+		if llmResp.Content != "Bonjour !" || llmResp.Model != "mistral-large-latest" {
+			t.Errorf("response = %+v", llmResp)
+		}
+		if llmResp.Usage.InputTokens != 12 || llmResp.Usage.OutputTokens != 4 {
+			t.Errorf("Usage = %+v, want 12 input and 4 output tokens", llmResp.Usage)
+		}
+
+		last, _ := srv.LastRequest()
+		if got := last.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q, want the API key", got)
+		}
+		var body struct {
+			MaxTokens int `json:"max_tokens"`
+			Messages  []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := last.JSON(&body); err != nil {
+			t.Fatalf("request body: %v", err)
+		}
+		if body.MaxTokens != 100 || len(body.Messages) != 2 || body.Messages[0].Role != "system" || body.Messages[0].Content != "Be brief" {
+			t.Errorf("request = %+v", body)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := testutil.NewOpenAIServer(t)
+		srv.Reply(testutil.Reply{Status: http.StatusUnprocessableEntity, Error: "Extra inputs are not permitted"})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
+
+		_, err := client.GenerateCompletion(context.Background(), &domain.LLMRequest{
+			Model:    "mistral-large-latest",
+			Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "Extra inputs are not permitted") {
+			t.Errorf("GenerateCompletion() error = %v, want the API error", err)
+		}
+	})
+}
+
+func TestConformance(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "mistral-large-latest", Reply: srv.Reply})
+}
+
+// Integration test - only runs with MISTRAL_API_KEY environment variable
+func TestGenerateCompletion_Integration(t *testing.T) {
+	apiKey := os.Getenv("MISTRAL_API_KEY")
+	if apiKey == "" {
+		t.Skip("MISTRAL_API_KEY not set, skipping integration test")
+	}
+
+	client, err := NewClient(apiKey, "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := &domain.LLMRequest{
+		Model: "mistral-small-latest",
+		Messages: []domain.Message{
+			{Role: "user", Content: "Say 'Hello, World!' and nothing else."},
+		},
+		MaxTokens:   50,
+		Temperature: 0.0,
+	}
+
+	resp, err := client.GenerateCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+
+	llmResp, ok := resp.(*domain.LLMResponse)
+	if !ok {
+		t.Fatal("Response is not *domain.LLMResponse")
+	}
+
+	if llmResp.Content == "" {
+		t.Error("Response content is empty")
+	}
+
+	if llmResp.Usage.InputTokens == 0 {
+		t.Error("Input tokens is 0")
+	}
+
+	t.Logf("Response: %s", llmResp.Content)
+	t.Logf("Usage: %d input tokens, %d output tokens",
+		llmResp.Usage.InputTokens,
+		llmResp.Usage.OutputTokens)
+}
+
+func FuzzConvertMessages(f *testing.F) {
+	llmtest.AddFuzzSeeds(f)
+	client, _ := NewClient("test-key", "", zap.NewNop())
+
+	f.Fuzz(func(t *testing.T, system, messages string) {
+		req := llmtest.FuzzRequest(system, messages)
+
+		var converted []libports.Message
+		for _, msg := range client.convertMessages(req) {
+			converted = append(converted, libports.Message{Role: msg.Role, Content: msg.Content})
+		}
+		llmtest.CheckConversion(t, req, converted, "system", "user", "assistant")
+	})
+}
+
+func TestRequestGolden(t *testing.T) {
+	for _, tc := range llmtest.GoldenCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := testutil.NewOpenAIServer(t)
+			client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+			if _, err := client.GenerateCompletion(context.Background(), tc.Request); err != nil {
+				t.Fatalf("GenerateCompletion() error = %v", err)
+			}
+			last, ok := srv.LastRequest()
+			if !ok {
+				t.Fatal("no request received")
+			}
+			testutil.GoldenJSON(t, tc.Name, last.Body)
+		})
+	}
+}
+
+func TestCompleteWithToolsMultiTurn(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Sunny in Paris, unknown in London."}, InputTokens: 40, OutputTokens: 7})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	// A Mistral call ID, and one of a call made by another provider
+	first := &libports.CompletionResponse{
+		ToolCalls: []libports.ToolCall{
+			{ID: "D681PevKs", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}},
+			{ID: "call_2", Name: "get_weather", Arguments: map[string]interface{}{"city": "London"}},
+		},
+	}
+	req := libports.CompletionRequest{
+		Model: "mistral-large-latest",
+		Messages: []libports.Message{
+			{Role: "user", Content: "Weather in Paris and London?"},
+			ports.ToolCallsMessage(first),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "D681PevKs", Name: "get_weather", Content: "sunny"}),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_2", Name: "get_weather", Content: "service down", IsError: true}),
+		},
+	}
+	tools := []libports.Tool{{Name: "get_weather", Description: "Current weather"}}
+
+	resp, err := client.CompleteWithTools(context.Background(), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Sunny in Paris, unknown in London." || resp.FinishReason != "stop" || len(resp.ToolCalls) != 0 {
+		t.Errorf("response = %+v, want the final answer", resp)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		Messages []struct {
+			Role       string `json:"role"`
+			Content    string `json:"content"`
+			Name       string `json:"name"`
+			ToolCallID string `json:"tool_call_id"`
+			ToolCalls  []struct {
+				ID       string `json:"id"`
+				Function struct {
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"messages"`
+		Tools []struct {
+			Function struct {
+				Parameters map[string]interface{} `json:"parameters"`
+			} `json:"function"`
+		} `json:"tools"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if len(body.Tools) != 1 || body.Tools[0].Function.Parameters["type"] != "object" {
+		t.Errorf("tools = %+v, want get_weather with an empty object schema", body.Tools)
+	}
+	if len(body.Messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(body.Messages))
+	}
+	calls := body.Messages[1].ToolCalls
+	if len(calls) != 2 || calls[0].ID != "D681PevKs" || calls[1].Function.Arguments != `{"city":"London"}` {
+		t.Fatalf("assistant tool calls = %+v", calls)
+	}
+	if id := calls[1].ID; id == "call_2" || id != toolCallID(id) {
+		t.Errorf("foreign call ID sent as %q, want a Mistral ID", id)
+	}
+	if m := body.Messages[3]; m.Role != "tool" || m.ToolCallID != calls[1].ID || m.Name != "get_weather" || m.Content != "Error: service down" {
+		t.Errorf("second result = %+v, want an error tool message for %s", m, calls[1].ID)
+	}
+}
+
+func TestCompleteStructuredRetries(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(
+		testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace","born":"1815"}`}, InputTokens: 30, OutputTokens: 10},
+		testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace","born":1815}`}, InputTokens: 50, OutputTokens: 9},
+	)
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	schema := libports.JSONSchema{
+		"type":       "object",
+		"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "born": map[string]interface{}{"type": "integer"}},
+		"required":   []string{"name", "born"},
+	}
+	req := libports.CompletionRequest{Model: "mistral-large-latest", Messages: []libports.Message{{Role: "user", Content: "Who was Ada Lovelace?"}}}
+
+	resp, err := client.CompleteStructured(context.Background(), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if resp.Data["born"] != float64(1815) || resp.Usage.PromptTokens != 80 || resp.Usage.TotalTokens != 99 {
+		t.Errorf("response = %+v, want the retried data and both attempts' usage", resp)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+		ResponseFormat struct {
+			Type string `json:"type"`
+		} `json:"response_format"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.ResponseFormat.Type != "json_object" {
+		t.Errorf("response_format = %+v, want JSON mode", body.ResponseFormat)
+	}
+	if len(body.Messages) != 4 || body.Messages[0].Role != "system" || !strings.Contains(body.Messages[0].Content, `"properties"`) {
+		t.Fatalf("messages = %+v, want the schema, prompt, invalid response and correction", body.Messages)
+	}
+	if m := body.Messages[3]; m.Role != "user" || !strings.Contains(m.Content, `$.born: "1815" is not of type "integer"`) {
+		t.Errorf("correction = %+v, want the violation", m)
+	}
+
+	// Without retries, the violation is returned
+	client.SetStructuredRetries(0)
+	srv.Reply(testutil.Reply{Chunks: []string{"Ada Lovelace, born 1815"}})
+	if _, err := client.CompleteStructured(context.Background(), req, schema); err == nil || !strings.Contains(err.Error(), "invalid structured response") {
+		t.Errorf("CompleteStructured() error = %v, want an invalid response", err)
+	}
+}
+
+func TestToolCallID(t *testing.T) {
+	if got := toolCallID("D681PevKs"); got != "D681PevKs" {
+		t.Errorf("toolCallID(D681PevKs) = %s, want it kept", got)
+	}
+	for _, id := range []string{"call_1", "toolu_01A09q90qw90lq917835lq9", "", "D681-evKs"} {
+		got := toolCallID(id)
+		if len(got) != 9 || toolCallID(got) != got {
+			t.Errorf("toolCallID(%q) = %q, want nine letters and digits", id, got)
+		}
+		if got != toolCallID(id) {
+			t.Errorf("toolCallID(%q) isn't stable", id)
+		}
+	}
+	if toolCallID("call_1") == toolCallID("call_2") {
+		t.Error("different IDs map to the same ID")
+	}
+}
@@ -0,0 +1,43 @@
+// Package mistral implements the LLM client adapter for the hosted Mistral
+// API (La Plateforme).
+//
+// This adapter implements the ports.LLMClient interface defined in dago-libs,
+// through the Mistral chat completions API, which is called with the OpenAI
+// SDK.
+//
+// Supported models:
+//   - mistral-large-latest
+//   - mistral-small-latest
+//   - codestral-latest
+//   - pixtral-large-latest
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/llm/mistral"
+//
+//	client, err := mistral.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
+//		Model: "mistral-large-latest",
+//		Messages: []domain.Message{
+//			{Role: "user", Content: "Hello!"},
+//		},
+//	})
+//
+// CompleteWithTools sends tools as function definitions and returns the
+// model's tool calls with their decoded arguments. Earlier turns encoded
+// with ports.ToolCallsMessage and ports.ToolResultMessage are sent as
+// assistant tool_calls and "tool" messages, so it can drive
+// agent.RunToolLoop. Mistral only accepts tool call IDs of nine letters and
+// digits: IDs of calls made by other providers are mapped to such IDs.
+//
+// CompleteStructured uses JSON mode, with the schema given in a system
+// message. Responses are validated against the schema and retried with the
+// violations, up to SetStructuredRetries times.
+//
+// CompleteStream streams completions; the last chunk has the usage Mistral
+// reports with the finish reason.
+package mistral
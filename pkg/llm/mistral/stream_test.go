@@ -0,0 +1,52 @@
+package mistral
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestCompleteStream(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{
+		Chunks:    []string{"Checking ", "both."},
+		ToolCalls: []testutil.ToolCall{{ID: "D681PevKs", Name: "get_weather", Arguments: `{"city": "Paris"}`}},
+	})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "mistral-large-latest", Messages: []libports.Message{{Role: "user", Content: "Weather in Paris?"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var text string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		text += chunk.Delta
+		last = chunk
+	}
+	if text != "Checking both." || last.Content != text {
+		t.Errorf("text = %q, content = %q, want the chunks", text, last.Content)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "tool_calls" {
+		t.Fatalf("last chunk = %+v, want a tool_calls finish", last)
+	}
+	if len(last.ToolCalls) != 1 || last.ToolCalls[0].ID != "D681PevKs" || last.ToolCalls[0].Arguments["city"] != "Paris" {
+		t.Errorf("tool calls = %+v, want the call with its arguments", last.ToolCalls)
+	}
+
+	// Mistral rejects stream_options, and reports usage without it
+	var body map[string]interface{}
+	sent, _ := srv.LastRequest()
+	if err := sent.JSON(&body); err != nil || body["stream"] != true {
+		t.Fatalf("request = %v, %v, want a stream", body, err)
+	}
+	if _, ok := body["stream_options"]; ok {
+		t.Errorf("request = %v, want no stream_options", body)
+	}
+}
@@ -2,19 +2,33 @@ package ollama
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	jsonschema "github.com/aescanero/dago-adapters/pkg/schema"
 	"github.com/aescanero/dago-libs/pkg/domain"
-	"github.com/aescanero/dago-libs/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
 	"github.com/ollama/ollama/api"
 	"go.uber.org/zap"
 )
 
+// DefaultStructuredRetries is the number of times CompleteStructured
+// retries responses that don't conform to the schema
+const DefaultStructuredRetries = 2
+
 // Client implements the LLMClient interface for Ollama local models
 type Client struct {
 	client   *api.Client
 	endpoint string
 	logger   *zap.Logger
+
+	structuredRetries int
 }
 
 // NewClient creates a new Ollama client
@@ -24,63 +38,192 @@ func NewClient(endpoint string, logger *zap.Logger) (*Client, error) {
 		endpoint = "http://localhost:11434"
 	}
 
-	client, err := api.ClientFromEnvironment()
+	base, err := url.Parse(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Ollama client: %w", err)
+		return nil, fmt.Errorf("invalid Ollama endpoint: %w", err)
 	}
 
 	return &Client{
-		client:   client,
-		endpoint: endpoint,
-		logger:   logger,
+		client:            api.NewClient(base, http.DefaultClient),
+		endpoint:          endpoint,
+		logger:            logger,
+		structuredRetries: DefaultStructuredRetries,
 	}, nil
 }
 
+// SetStructuredRetries sets the number of times CompleteStructured retries
+// responses that don't conform to the schema. Zero disables retries.
+func (c *Client) SetStructuredRetries(retries int) {
+	c.structuredRetries = retries
+}
+
 // Complete performs a standard text completion (ports.LLMClient interface)
-func (c *Client) Complete(ctx context.Context, req ports.CompletionRequest) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
 }
 
-// CompleteWithTools performs a completion with tool calling support (ports.LLMClient interface)
-func (c *Client) CompleteWithTools(ctx context.Context, req ports.CompletionRequest, tools []ports.Tool) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface), for models whose template supports tools,
+// such as llama3.1 or mistral-nemo. Tool calls and results of earlier turns,
+// in messages built with ports.ToolCallsMessage and ports.ToolResultMessage,
+// are sent as assistant tool_calls and "tool" role messages. Ollama doesn't
+// identify tool calls, so the tool calls returned are given random IDs.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	// Tool calls are only parsed from whole responses
+	stream := false
+	chatReq := &api.ChatRequest{
+		Model:    req.Model,
+		Messages: c.convertCompletionMessages(req.Messages),
+		Stream:   &stream,
+		Tools:    convertTools(tools),
+		Options:  completionOptions(req),
+	}
+
+	response, err := c.chat(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := &libports.CompletionResponse{
+		Model:        response.Model,
+		Message:      libports.Message{Role: "assistant", Content: response.Message.Content},
+		FinishReason: response.DoneReason,
+		Usage: libports.UsageInfo{
+			PromptTokens:     response.PromptEvalCount,
+			CompletionTokens: response.EvalCount,
+			TotalTokens:      response.PromptEvalCount + response.EvalCount,
+		},
+		CreatedAt: response.CreatedAt,
+	}
+	for _, call := range response.Message.ToolCalls {
+		completion.ToolCalls = append(completion.ToolCalls, libports.ToolCall{
+			ID:        newToolCallID(),
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	if len(completion.ToolCalls) > 0 {
+		completion.FinishReason = "tool_calls"
+	}
+
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens))
+
+	return completion, nil
 }
 
-// CompleteStructured performs a completion with guaranteed JSON schema conformance (ports.LLMClient interface)
-func (c *Client) CompleteStructured(ctx context.Context, req ports.CompletionRequest, schema ports.JSONSchema) (*ports.StructuredResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance (ports.LLMClient interface). The schema is sent as the
+// request format, constraining the output of models that support it, and
+// in a system message to ground the others. Responses are validated against
+// the schema; invalid ones are sent back with the violations and retried,
+// up to the retries set with SetStructuredRetries.
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+	format, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %v", ports.ErrInvalidRequest, err)
+	}
+
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
+
+	messages := append([]api.Message{{
+		Role:    "system",
+		Content: "Respond with a JSON object conforming to this JSON schema:\n" + string(format),
+	}}, c.convertCompletionMessages(req.Messages)...)
+
+	structured := &libports.StructuredResponse{}
+	stream := false
+	for attempt := 0; ; attempt++ {
+		chatReq := &api.ChatRequest{
+			Model:    req.Model,
+			Messages: messages,
+			Stream:   &stream,
+			Format:   format,
+			Options:  completionOptions(req),
+		}
+
+		response, err := c.chat(ctx, chatReq)
+		if err != nil {
+			return nil, err
+		}
+		structured.Usage.PromptTokens += response.PromptEvalCount
+		structured.Usage.CompletionTokens += response.EvalCount
+		structured.Usage.TotalTokens += response.PromptEvalCount + response.EvalCount
+
+		var data map[string]interface{}
+		err = json.Unmarshal([]byte(response.Message.Content), &data)
+		if err == nil {
+			err = jsonschema.Validate(schema, data)
+		}
+		if err == nil {
+			structured.Data = data
+			structured.CreatedAt = response.CreatedAt
+			break
+		}
+		if attempt >= c.structuredRetries {
+			return nil, fmt.Errorf("invalid structured response after %d attempts: %w", attempt+1, err)
+		}
+
+		c.logger.Warn("invalid structured response, retrying",
+			zap.String("model", req.Model),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err))
+		messages = append(messages,
+			api.Message{Role: "assistant", Content: response.Message.Content},
+			api.Message{Role: "user", Content: fmt.Sprintf("Your response doesn't conform to the JSON schema: %v. Respond again with only the corrected JSON object.", err)},
+		)
+	}
+
+	c.logger.Debug("structured completion generated",
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
+
+	return structured, nil
 }
 
-// GenerateCompletion generates a completion using domain.LLMRequest (compatibility method)
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
 func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
-	// Type assert the request
 	llmReq, ok := req.(*domain.LLMRequest)
 	if !ok {
-		return nil, fmt.Errorf("invalid request type")
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Generate generates a completion of a domain request
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
 	}
 
 	c.logger.Debug("generating completion",
 		zap.String("model", llmReq.Model),
 		zap.Int("message_count", len(llmReq.Messages)))
 
-	// Build messages for Ollama
-	messages := make([]api.Message, 0, len(llmReq.Messages))
-
-	// Add system message if present
-	if llmReq.System != "" {
-		messages = append(messages, api.Message{
-			Role:    "system",
-			Content: llmReq.System,
-		})
-	}
-
-	// Add conversation messages
-	for _, msg := range llmReq.Messages {
-		messages = append(messages, api.Message{
-			Role:    msg.Role,
-			Content: msg.Content,
-		})
-	}
+	messages := c.convertMessages(llmReq)
 
 	// Build chat request
 	chatReq := &api.ChatRequest{
@@ -103,15 +246,9 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 	}
 
 	// Make the API call
-	var response api.ChatResponse
-	err := c.client.Chat(ctx, chatReq, func(resp api.ChatResponse) error {
-		response = resp
-		return nil
-	})
-
+	response, err := c.chat(ctx, chatReq)
 	if err != nil {
-		c.logger.Error("API call failed", zap.Error(err))
-		return nil, fmt.Errorf("API call failed: %w", err)
+		return nil, err
 	}
 
 	// Ollama provides token counts in the response
@@ -141,3 +278,173 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 
 	return llmResp, nil
 }
+
+// chat sends chatReq and returns its whole response. The server streams
+// responses unless chatReq disables it, and may stream anyway: the content
+// and tool calls of the chunks are accumulated into the last one, which
+// carries the token counts and done reason.
+func (c *Client) chat(ctx context.Context, chatReq *api.ChatRequest) (api.ChatResponse, error) {
+	var response api.ChatResponse
+	var content strings.Builder
+	var toolCalls []api.ToolCall
+	err := c.client.Chat(ctx, chatReq, func(resp api.ChatResponse) error {
+		content.WriteString(resp.Message.Content)
+		toolCalls = append(toolCalls, resp.Message.ToolCalls...)
+		response = resp
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return api.ChatResponse{}, fmt.Errorf("API call failed: %w", err)
+	}
+
+	response.Message.Content = content.String()
+	response.Message.ToolCalls = toolCalls
+	return response, nil
+}
+
+// convertMessages converts the messages of a domain request to Ollama
+// format, tool calls and results included, like convertCompletionMessages
+func (c *Client) convertMessages(llmReq *domain.LLMRequest) []api.Message {
+	return c.convertCompletionMessages(ports.CompletionMessages(llmReq))
+}
+
+// convertCompletionMessages converts messages to Ollama format. Messages
+// built with ports.ToolCallsMessage become assistant messages with tool
+// calls, and results built with ports.ToolResultMessage "tool" messages;
+// results marked as errors are prefixed with "Error: ", as the API has no
+// field for it. Other "tool" messages are sent as they are, as Ollama doesn't
+// identify tool calls; unknown roles are sent as user messages.
+func (c *Client) convertCompletionMessages(msgs []libports.Message) []api.Message {
+	messages := make([]api.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if content, calls, ok := ports.ParseToolCallsMessage(msg); ok {
+			message := api.Message{Role: "assistant", Content: content}
+			for _, call := range calls {
+				message.ToolCalls = append(message.ToolCalls, api.ToolCall{
+					Function: api.ToolCallFunction{Name: call.Name, Arguments: call.Arguments},
+				})
+			}
+			messages = append(messages, message)
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			content := result.Content
+			if result.IsError {
+				content = "Error: " + content
+			}
+			messages = append(messages, api.Message{Role: "tool", Content: content})
+			continue
+		}
+
+		role := msg.Role
+		switch role {
+		case "system", "user", "assistant", "tool":
+		default:
+			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
+			role = "user"
+		}
+		messages = append(messages, api.Message{Role: role, Content: msg.Content})
+	}
+	return messages
+}
+
+// completionOptions returns the model options of a request, nil if it sets
+// none
+func completionOptions(req libports.CompletionRequest) map[string]interface{} {
+	options := map[string]interface{}{}
+	if req.Temperature > 0 {
+		options["temperature"] = req.Temperature
+	}
+	if req.MaxTokens > 0 {
+		options["num_predict"] = req.MaxTokens
+	}
+	if req.TopP > 0 {
+		options["top_p"] = req.TopP
+	}
+	if len(req.Stop) > 0 {
+		options["stop"] = req.Stop
+	}
+	if req.PresencePenalty != 0 {
+		options["presence_penalty"] = req.PresencePenalty
+	}
+	if req.FrequencyPenalty != 0 {
+		options["frequency_penalty"] = req.FrequencyPenalty
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
+
+// convertTools converts tools to Ollama function tools. Ollama's API only
+// describes the top-level properties of parameters, with a type,
+// description and enum: nested schemas are reduced to their type, and a
+// type list to its first type other than "null".
+func convertTools(tools []libports.Tool) api.Tools {
+	if len(tools) == 0 {
+		return nil
+	}
+	converted := make(api.Tools, 0, len(tools))
+	for _, tool := range tools {
+		function := api.ToolFunction{Name: tool.Name, Description: tool.Description}
+		function.Parameters.Type = "object"
+		function.Parameters.Properties = map[string]struct {
+			Type        string   `json:"type"`
+			Description string   `json:"description"`
+			Enum        []string `json:"enum,omitempty"`
+		}{}
+
+		if properties, ok := tool.Parameters["properties"].(map[string]interface{}); ok {
+			for name, property := range properties {
+				property, _ := property.(map[string]interface{})
+				p := function.Parameters.Properties[name]
+				p.Type = schemaType(property["type"])
+				p.Description, _ = property["description"].(string)
+				if enum, ok := property["enum"].([]interface{}); ok {
+					for _, value := range enum {
+						p.Enum = append(p.Enum, fmt.Sprint(value))
+					}
+				}
+				function.Parameters.Properties[name] = p
+			}
+		}
+		switch required := tool.Parameters["required"].(type) {
+		case []string:
+			function.Parameters.Required = required
+		case []interface{}:
+			for _, name := range required {
+				if s, ok := name.(string); ok {
+					function.Parameters.Required = append(function.Parameters.Required, s)
+				}
+			}
+		}
+
+		converted = append(converted, api.Tool{Type: "function", Function: function})
+	}
+	return converted
+}
+
+// schemaType returns the type of a JSON schema "type" keyword, the first
+// one other than "null" of a list
+func schemaType(t interface{}) string {
+	switch t := t.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// newToolCallID returns a random tool call ID
+func newToolCallID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "call_" + hex.EncodeToString(b)
+}
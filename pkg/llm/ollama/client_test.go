@@ -2,10 +2,17 @@ package ollama
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
 	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/ollama/ollama/api"
 	"go.uber.org/zap"
 )
 
@@ -55,11 +62,14 @@ func TestGenerateCompletion(t *testing.T) {
 		}
 	})
 
-	t.Run("valid request structure", func(t *testing.T) {
-		client, _ := NewClient("http://localhost:11434", logger)
+	t.Run("valid request", func(t *testing.T) {
+		srv := testutil.NewOllamaServer(t)
+		srv.ReplyChat(testutil.OllamaReply{Chunks: []string{"Hello", ", World!"}, PromptTokens: 12})
+		client, _ := NewClient(srv.URL, logger)
 
 		req := &domain.LLMRequest{
-			Model: "llama3.1",
+			Model:  "llama3.1",
+			System: "Be brief.",
 			Messages: []domain.Message{
 				{Role: "user", Content: "Hello"},
 			},
@@ -67,14 +77,66 @@ func TestGenerateCompletion(t *testing.T) {
 			Temperature: 0.7,
 		}
 
-		// This will fail if Ollama is not running
-		_, err := client.GenerateCompletion(context.Background(), req)
+		resp, err := client.GenerateCompletion(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		llmResp := resp.(*domain.LLMResponse)
+		if llmResp.Usage.InputTokens != 12 || llmResp.Usage.OutputTokens != 2 {
+			t.Errorf("Usage = %+v, want 12 input and 2 output tokens", llmResp.Usage)
+		}
+
+		sent := srv.LastChatRequest()
+		if sent == nil || len(sent.Messages) != 2 || sent.Messages[0].Role != "system" {
+			t.Fatalf("request = %+v, want system and user messages", sent)
+		}
+		if sent.Options["num_predict"] != float64(100) || sent.Options["temperature"] != 0.7 {
+			t.Errorf("options = %v, want num_predict and temperature", sent.Options)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := testutil.NewOllamaServer(t)
+		srv.ReplyChat(testutil.OllamaReply{Status: 404, Error: "model \"llama3.1\" not found"})
+		client, _ := NewClient(srv.URL, logger)
+
+		_, err := client.GenerateCompletion(context.Background(), &domain.LLMRequest{
+			Model:    "llama3.1",
+			Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		})
 		if err == nil {
-			t.Log("Note: API call succeeded (Ollama is running)")
+			t.Error("GenerateCompletion() expected error")
 		}
 	})
 }
 
+func TestConformance(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	client, _ := NewClient(srv.URL, zap.NewNop())
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "llama3.1", Reply: func(replies ...testutil.Reply) {
+		for _, reply := range replies {
+			ollamaReply := testutil.OllamaReply{
+				Chunks:       reply.Chunks,
+				PromptTokens: reply.InputTokens,
+				OutputTokens: reply.OutputTokens,
+				DoneReason:   reply.StopReason,
+				Status:       reply.Status,
+				Error:        reply.Error,
+				Delay:        reply.Delay,
+			}
+			for _, call := range reply.ToolCalls {
+				var args api.ToolCallFunctionArguments
+				_ = json.Unmarshal([]byte(call.Arguments), &args)
+				ollamaReply.ToolCalls = append(ollamaReply.ToolCalls, api.ToolCall{
+					Function: api.ToolCallFunction{Name: call.Name, Arguments: args},
+				})
+			}
+			srv.ReplyChat(ollamaReply)
+		}
+	}})
+}
+
 // Integration test - only runs with OLLAMA_ENDPOINT environment variable
 func TestGenerateCompletion_Integration(t *testing.T) {
 	endpoint := os.Getenv("OLLAMA_ENDPOINT")
@@ -120,3 +182,182 @@ func TestGenerateCompletion_Integration(t *testing.T) {
 		llmResp.Usage.InputTokens,
 		llmResp.Usage.OutputTokens)
 }
+
+func FuzzConvertMessages(f *testing.F) {
+	llmtest.AddFuzzSeeds(f)
+	client, _ := NewClient("", zap.NewNop())
+
+	f.Fuzz(func(t *testing.T, system, messages string) {
+		req := llmtest.FuzzRequest(system, messages)
+
+		var converted []libports.Message
+		for _, msg := range client.convertMessages(req) {
+			converted = append(converted, libports.Message{Role: msg.Role, Content: msg.Content})
+		}
+		llmtest.CheckConversion(t, req, converted, "system", "user", "assistant", "tool")
+	})
+}
+
+func TestRequestGolden(t *testing.T) {
+	for _, tc := range llmtest.GoldenCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := testutil.NewOllamaServer(t)
+			client, _ := NewClient(srv.URL, zap.NewNop())
+
+			if _, err := client.GenerateCompletion(context.Background(), tc.Request); err != nil {
+				t.Fatalf("GenerateCompletion() error = %v", err)
+			}
+			last, ok := srv.LastRequest()
+			if !ok {
+				t.Fatal("no request received")
+			}
+			testutil.GoldenJSON(t, tc.Name, last.Body)
+		})
+	}
+}
+
+func TestCompleteWithToolsMultiTurn(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	srv.ReplyChat(testutil.OllamaReply{Chunks: []string{"Sunny in Paris, unknown in London."}, PromptTokens: 40, OutputTokens: 7})
+
+	client, _ := NewClient(srv.URL, zap.NewNop())
+
+	first := &libports.CompletionResponse{
+		ToolCalls: []libports.ToolCall{
+			{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}},
+			{ID: "call_2", Name: "get_weather", Arguments: map[string]interface{}{"city": "London"}},
+		},
+	}
+	req := libports.CompletionRequest{
+		Model: "llama3.1",
+		Messages: []libports.Message{
+			{Role: "user", Content: "Weather in Paris and London?"},
+			ports.ToolCallsMessage(first),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_1", Name: "get_weather", Content: "sunny"}),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_2", Name: "get_weather", Content: "service down", IsError: true}),
+		},
+		Temperature: 0.2,
+	}
+	tools := []libports.Tool{{
+		Name:        "get_weather",
+		Description: "Current weather",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city":  map[string]interface{}{"type": "string", "description": "City name"},
+				"units": map[string]interface{}{"type": []interface{}{"string", "null"}, "enum": []interface{}{"metric", "imperial"}},
+			},
+			"required": []interface{}{"city"},
+		},
+	}}
+
+	resp, err := client.CompleteWithTools(context.Background(), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Sunny in Paris, unknown in London." || resp.FinishReason != "stop" || len(resp.ToolCalls) != 0 {
+		t.Errorf("response = %+v, want the final answer", resp)
+	}
+	if resp.Usage.PromptTokens != 40 || resp.Usage.CompletionTokens != 7 || resp.Usage.TotalTokens != 47 {
+		t.Errorf("Usage = %+v, want 40 prompt and 7 completion tokens", resp.Usage)
+	}
+
+	last := srv.LastChatRequest()
+	if last.Stream == nil || *last.Stream || last.Options["temperature"] != 0.2 {
+		t.Errorf("stream = %v, options = %v, want an unstreamed request at 0.2", last.Stream, last.Options)
+	}
+	if len(last.Tools) != 1 {
+		t.Fatalf("tools = %+v, want get_weather", last.Tools)
+	}
+	params := last.Tools[0].Function.Parameters
+	if units := params.Properties["units"]; units.Type != "string" || len(units.Enum) != 2 || len(params.Required) != 1 {
+		t.Errorf("parameters = %+v, want a string units enum and city required", params)
+	}
+	if len(last.Messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(last.Messages))
+	}
+	if calls := last.Messages[1].ToolCalls; len(calls) != 2 || calls[1].Function.Arguments["city"] != "London" {
+		t.Errorf("assistant tool calls = %+v", calls)
+	}
+	if m := last.Messages[3]; m.Role != "tool" || m.Content != "Error: service down" {
+		t.Errorf("second result = %+v, want an error tool message", m)
+	}
+}
+
+func TestStreamedResponses(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	client, _ := NewClient(srv.URL, zap.NewNop())
+	req := libports.CompletionRequest{Model: "llama3.1", Messages: []libports.Message{{Role: "user", Content: "Weather in Paris?"}}}
+
+	// Servers ignoring stream:false answer in chunks, which are accumulated
+	srv.ReplyChat(testutil.OllamaReply{
+		Chunks:       []string{"Let me ", "check ", "Paris."},
+		ToolCalls:    []api.ToolCall{{Function: api.ToolCallFunction{Name: "get_weather", Arguments: api.ToolCallFunctionArguments{"city": "Paris"}}}},
+		PromptTokens: 12,
+		OutputTokens: 9,
+		AlwaysStream: true,
+	})
+	resp, err := client.CompleteWithTools(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Let me check Paris." || len(resp.ToolCalls) != 1 || resp.FinishReason != "tool_calls" {
+		t.Errorf("response = %+v, want the whole content and the tool call", resp)
+	}
+	if resp.Usage.PromptTokens != 12 || resp.Usage.CompletionTokens != 9 {
+		t.Errorf("Usage = %+v, want 12 prompt and 9 completion tokens", resp.Usage)
+	}
+
+	srv.ReplyChat(testutil.OllamaReply{Chunks: []string{`{"city":`, `"Paris"}`}, AlwaysStream: true})
+	structured, err := client.CompleteStructured(context.Background(), req, libports.JSONSchema{"type": "object"})
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if structured.Data["city"] != "Paris" {
+		t.Errorf("Data = %v, want the whole object", structured.Data)
+	}
+}
+
+func TestCompleteStructuredRetries(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	srv.ReplyChat(
+		testutil.OllamaReply{Chunks: []string{`{"name":"Ada Lovelace","born":"1815"}`}, PromptTokens: 30, OutputTokens: 10},
+		testutil.OllamaReply{Chunks: []string{`{"name":"Ada Lovelace","born":1815}`}, PromptTokens: 50, OutputTokens: 9},
+	)
+
+	client, _ := NewClient(srv.URL, zap.NewNop())
+
+	schema := libports.JSONSchema{
+		"type":       "object",
+		"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "born": map[string]interface{}{"type": "integer"}},
+		"required":   []string{"name", "born"},
+	}
+	req := libports.CompletionRequest{Model: "llama3.1", Messages: []libports.Message{{Role: "user", Content: "Who was Ada Lovelace?"}}}
+
+	resp, err := client.CompleteStructured(context.Background(), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if resp.Data["born"] != float64(1815) || resp.Usage.PromptTokens != 80 || resp.Usage.TotalTokens != 99 {
+		t.Errorf("response = %+v, want the retried data and both attempts' usage", resp)
+	}
+
+	last := srv.LastChatRequest()
+	var format map[string]interface{}
+	if err := json.Unmarshal(last.Format, &format); err != nil || format["type"] != "object" {
+		t.Errorf("format = %s, want the schema", last.Format)
+	}
+	if len(last.Messages) != 4 || last.Messages[0].Role != "system" {
+		t.Fatalf("messages = %+v, want the schema, prompt, invalid response and correction", last.Messages)
+	}
+	if m := last.Messages[3]; m.Role != "user" || !strings.Contains(m.Content, `$.born: "1815" is not of type "integer"`) {
+		t.Errorf("correction = %+v, want the violation", m)
+	}
+
+	// Without retries, the violation is returned
+	client.SetStructuredRetries(0)
+	srv.ReplyChat(testutil.OllamaReply{Chunks: []string{"Ada Lovelace, born 1815"}})
+	if _, err := client.CompleteStructured(context.Background(), req, schema); err == nil || !strings.Contains(err.Error(), "invalid structured response") {
+		t.Errorf("CompleteStructured() error = %v, want an invalid response", err)
+	}
+}
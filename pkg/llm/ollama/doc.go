@@ -20,13 +20,32 @@
 //		log.Fatal(err)
 //	}
 //
-//	resp, err := client.GenerateCompletion(ctx, &domain.LLMRequest{
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
 //		Model: "llama3.1",
 //		Messages: []domain.Message{
 //			{Role: "user", Content: "Hello!"},
 //		},
 //	})
 //
+// CompleteWithTools sends tools as function definitions to models whose
+// template supports them (llama3.1, mistral-nemo, qwen2.5, ...). Earlier
+// turns encoded with ports.ToolCallsMessage and ports.ToolResultMessage are
+// sent as assistant tool_calls and "tool" messages, so it can drive
+// agent.RunToolLoop. Ollama doesn't identify tool calls: the tool calls
+// returned get random IDs, and results are matched to their call by order.
+// Only the top-level properties of tool parameters are described to the
+// model.
+//
+// CompleteStructured sends the schema as the request format, which recent
+// Ollama versions use to constrain decoding, and validates the response
+// with schema.Validate: responses of models that ignore the format are sent
+// back with their violations and retried (SetStructuredRetries).
+//
+// CompleteStream streams the chunks of a chat as they are generated. The
+// other methods return whole responses; they accumulate the content, tool
+// calls and token counts of every chunk, as servers may stream even
+// requests that disable streaming.
+//
 // Note: Ollama must be running locally or accessible at the specified endpoint.
 // The default endpoint is http://localhost:11434
 package ollama
@@ -0,0 +1,141 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/ollama/ollama/api"
+	"go.uber.org/zap"
+)
+
+// CompleteStream streams a completion with tools, if any
+// (ports.LLMStreamer interface). Text deltas are sent as they arrive, and
+// tool calls, with random IDs, as single fragments when they are complete;
+// the text, tool calls, finish reason and usage on the last chunk.
+// Cancelling ctx closes the stream, and ends it with the text generated so
+// far.
+func (c *Client) CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan ports.StreamChunk, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := &api.ChatRequest{
+		Model:    req.Model,
+		Messages: c.convertCompletionMessages(req.Messages),
+		Tools:    convertTools(tools),
+		Options:  completionOptions(req),
+	}
+
+	chunks := make(chan ports.StreamChunk)
+	// The first response starts the stream; errors before it are returned
+	started := make(chan struct{})
+	failed := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+
+		var content strings.Builder
+		send := func(chunk ports.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// The last chunk is sent even after a cancellation, with the text
+		// so far
+		finish := func(chunk ports.StreamChunk) {
+			chunk.Content = content.String()
+			chunk.Done = true
+			chunks <- chunk
+		}
+
+		first, done := true, false
+		var calls []libports.ToolCall
+		err := c.client.Chat(ctx, chatReq, func(resp api.ChatResponse) error {
+			if first {
+				first = false
+				close(started)
+			}
+			content.WriteString(resp.Message.Content)
+			if resp.Message.Content != "" && !send(ports.StreamChunk{Delta: resp.Message.Content}) {
+				return ctx.Err()
+			}
+			for _, call := range resp.Message.ToolCalls {
+				calls = append(calls, libports.ToolCall{
+					ID:        newToolCallID(),
+					Name:      call.Function.Name,
+					Arguments: call.Function.Arguments,
+				})
+				delta := ports.WholeToolCallDelta(len(calls)-1, calls[len(calls)-1])
+				if !send(ports.StreamChunk{ToolCallDelta: delta}) {
+					return ctx.Err()
+				}
+			}
+			if !resp.Done {
+				return nil
+			}
+
+			done = true
+			finishReason := resp.DoneReason
+			if len(calls) > 0 {
+				finishReason = "tool_calls"
+			}
+			c.logger.Debug("completion streamed",
+				zap.Int("tool_calls", len(calls)),
+				zap.Int("input_tokens", resp.PromptEvalCount),
+				zap.Int("output_tokens", resp.EvalCount))
+			finish(ports.StreamChunk{
+				ToolCalls:    calls,
+				FinishReason: finishReason,
+				Usage: &libports.UsageInfo{
+					PromptTokens:     resp.PromptEvalCount,
+					CompletionTokens: resp.EvalCount,
+					TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+				},
+			})
+			return nil
+		})
+
+		if err == nil && !done {
+			err = fmt.Errorf("stream ended without a done response")
+		}
+		switch {
+		case first:
+			c.logger.Error("API call failed", zap.Error(err))
+			failed <- fmt.Errorf("API call failed: %w", err)
+		case err != nil && ctx.Err() != nil:
+			c.logger.Debug("stream cancelled", zap.Int("content_length", content.Len()))
+			finish(ports.StreamChunk{Cancelled: true})
+		case err != nil:
+			c.logger.Error("API call failed", zap.Error(err))
+			finish(ports.StreamChunk{Err: fmt.Errorf("API call failed: %w", err)})
+		}
+	}()
+
+	select {
+	case <-started:
+		return chunks, nil
+	case err := <-failed:
+		return nil, err
+	}
+}
+
+// StreamComplete streams the text of a completion, as libports'
+// CompletionChunk, through CompleteStream
+func (c *Client) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks, err := c.CompleteStream(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ports.CompletionChunks(ctx, chunks), nil
+}
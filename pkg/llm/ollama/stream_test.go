@@ -0,0 +1,58 @@
+package ollama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/ollama/ollama/api"
+	"go.uber.org/zap"
+)
+
+func TestCompleteStream(t *testing.T) {
+	srv := testutil.NewOllamaServer(t)
+	srv.ReplyChat(testutil.OllamaReply{
+		Chunks: []string{"Checking ", "Paris."},
+		ToolCalls: []api.ToolCall{
+			{Function: api.ToolCallFunction{Name: "get_weather", Arguments: api.ToolCallFunctionArguments{"city": "Paris"}}},
+		},
+		PromptTokens: 30,
+		OutputTokens: 12,
+	})
+	client, _ := NewClient(srv.URL, zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "llama3.1", Messages: []libports.Message{{Role: "user", Content: "Weather in Paris?"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var text string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		text += chunk.Delta
+		last = chunk
+	}
+	if text != "Checking Paris." {
+		t.Errorf("text = %q, want the chunks", text)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "tool_calls" {
+		t.Fatalf("last chunk = %+v, want a tool_calls finish", last)
+	}
+	if len(last.ToolCalls) != 1 || last.ToolCalls[0].ID == "" || last.ToolCalls[0].Arguments["city"] != "Paris" {
+		t.Errorf("tool calls = %+v, want get_weather(Paris) with an ID", last.ToolCalls)
+	}
+	if last.Usage == nil || last.Usage.PromptTokens != 30 || last.Usage.CompletionTokens != 12 || last.Usage.TotalTokens != 42 {
+		t.Errorf("usage = %+v, want 30 + 12 tokens", last.Usage)
+	}
+	if sent := srv.LastChatRequest(); (sent.Stream != nil && !*sent.Stream) || len(sent.Tools) != 1 {
+		t.Errorf("request = %+v, want a stream with the tool", sent)
+	}
+
+	srv.ReplyChat(testutil.OllamaReply{Status: 404, Error: `model "llama3.1" not found`})
+	if _, err := client.CompleteStream(context.Background(), req, nil); err == nil {
+		t.Error("CompleteStream() with a 404 reply succeeded")
+	}
+}
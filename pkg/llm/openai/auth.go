@@ -0,0 +1,166 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AzureOpenAIScope is the scope of Azure AD tokens for Azure OpenAI
+const AzureOpenAIScope = "https://cognitiveservices.azure.com/.default"
+
+// Azure AD authority used when AZURE_AUTHORITY_HOST is unset
+const defaultAzureAuthority = "https://login.microsoftonline.com/"
+
+// NewClientWithTokenSource creates an OpenAI client authenticating with
+// bearer tokens from tokens instead of a static API key, for gateways and
+// OpenAI-compatible servers behind OIDC. Tokens are cached and refreshed
+// shortly before they expire. baseURL is optional, as for NewClient.
+func NewClientWithTokenSource(tokens oauth2.TokenSource, baseURL string, logger *zap.Logger) (*Client, error) {
+	if tokens == nil {
+		return nil, fmt.Errorf("token source is required")
+	}
+
+	config := openai.DefaultConfig("")
+	if baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	config.HTTPClient = tokenHTTPClient(tokens)
+
+	return &Client{
+		client: openai.NewClientWithConfig(config),
+		logger: logger,
+	}, nil
+}
+
+// NewAzureClient creates a client of an Azure OpenAI resource, e.g.
+// https://my-resource.openai.azure.com, authenticating with its API key.
+// Models are deployment names.
+func NewAzureClient(endpoint, apiKey string, logger *zap.Logger) (*Client, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	return &Client{
+		client: openai.NewClientWithConfig(openai.DefaultAzureConfig(apiKey, endpoint)),
+		logger: logger,
+	}, nil
+}
+
+// NewAzureClientWithTokenSource creates a client of an Azure OpenAI resource
+// authenticating with Azure AD tokens from tokens, e.g. ClientCredentials or
+// WorkloadIdentity with AzureOpenAIScope, for tenants that disable API keys.
+// Tokens are cached and refreshed shortly before they expire.
+func NewAzureClientWithTokenSource(endpoint string, tokens oauth2.TokenSource, logger *zap.Logger) (*Client, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+	if tokens == nil {
+		return nil, fmt.Errorf("token source is required")
+	}
+
+	config := openai.DefaultAzureConfig("", endpoint)
+	config.APIType = openai.APITypeAzureAD
+	config.HTTPClient = tokenHTTPClient(tokens)
+
+	return &Client{
+		client: openai.NewClientWithConfig(config),
+		logger: logger,
+	}, nil
+}
+
+// tokenHTTPClient returns an HTTP client setting the Authorization header of
+// every request from tokens, reused until they are about to expire
+func tokenHTTPClient(tokens oauth2.TokenSource) *http.Client {
+	return &http.Client{
+		Transport: &oauth2.Transport{Source: oauth2.ReuseTokenSource(nil, tokens)},
+	}
+}
+
+// ClientCredentials returns a token source of the OAuth2 client credentials
+// flow, for any OIDC provider. For Azure AD, tokenURL is AzureTokenURL of the
+// tenant and the scope AzureOpenAIScope.
+func ClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) oauth2.TokenSource {
+	config := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       scopes,
+	}
+	return config.TokenSource(context.Background())
+}
+
+// AzureTokenURL returns the token endpoint of an Azure AD tenant
+func AzureTokenURL(tenantID string) string {
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = defaultAzureAuthority
+	}
+	return strings.TrimSuffix(authority, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+}
+
+// WorkloadIdentity returns a token source of Azure AD workload identity: the
+// Kubernetes service account token in tokenFile is exchanged for an Azure AD
+// token of clientID. The file is read on every exchange, since the kubelet
+// rotates it.
+func WorkloadIdentity(tenantID, clientID, tokenFile string, scopes ...string) oauth2.TokenSource {
+	return &workloadIdentity{
+		tokenURL:  AzureTokenURL(tenantID),
+		clientID:  clientID,
+		tokenFile: tokenFile,
+		scopes:    scopes,
+	}
+}
+
+// WorkloadIdentityFromEnv returns the workload identity token source
+// configured by the Azure workload identity webhook, from AZURE_TENANT_ID,
+// AZURE_CLIENT_ID and AZURE_FEDERATED_TOKEN_FILE, with AzureOpenAIScope
+func WorkloadIdentityFromEnv() (oauth2.TokenSource, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	if tenantID == "" || clientID == "" || tokenFile == "" {
+		return nil, fmt.Errorf("AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_FEDERATED_TOKEN_FILE are required for workload identity")
+	}
+	return WorkloadIdentity(tenantID, clientID, tokenFile, AzureOpenAIScope), nil
+}
+
+// workloadIdentity exchanges a federated token for an Azure AD token with
+// the client credentials flow, the federated token as client assertion
+type workloadIdentity struct {
+	tokenURL  string
+	clientID  string
+	tokenFile string
+	scopes    []string
+}
+
+// Token reads the federated token and exchanges it
+func (w *workloadIdentity) Token() (*oauth2.Token, error) {
+	assertion, err := os.ReadFile(w.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read federated token: %w", err)
+	}
+
+	config := &clientcredentials.Config{
+		ClientID: w.clientID,
+		TokenURL: w.tokenURL,
+		Scopes:   w.scopes,
+		EndpointParams: url.Values{
+			"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+			"client_assertion":      {strings.TrimSpace(string(assertion))},
+		},
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+	return config.Token(context.Background())
+}
@@ -0,0 +1,247 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+var authRequest = &domain.LLMRequest{
+	Model:    "gpt-4o",
+	Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+}
+
+// tokenServer is a fake OAuth2 token endpoint issuing tokens valid for
+// expiresIn seconds, and recording the forms it received
+type tokenServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	forms []map[string]string
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	t.Helper()
+	s := &tokenServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		form := map[string]string{}
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+
+		s.mu.Lock()
+		s.forms = append(s.forms, form)
+		n := len(s.forms)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) Forms() []map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]string(nil), s.forms...)
+}
+
+func TestNewClientWithTokenSource(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	tokens := newTokenServer(t, 3600)
+
+	client, err := NewClientWithTokenSource(ClientCredentials(tokens.URL, "client-id", "client-secret", "api"), srv.BaseURL(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClientWithTokenSource() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GenerateCompletion(context.Background(), authRequest); err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+	}
+
+	for _, req := range srv.Requests() {
+		if got := req.Header.Get("Authorization"); got != "Bearer token-1" {
+			t.Errorf("Authorization = %q, want Bearer token-1", got)
+		}
+	}
+	forms := tokens.Forms()
+	if len(forms) != 1 {
+		t.Fatalf("token requests = %d, want 1 reused token", len(forms))
+	}
+	if forms[0]["grant_type"] != "client_credentials" || forms[0]["scope"] != "api" {
+		t.Errorf("token request = %v, want client credentials for scope api", forms[0])
+	}
+}
+
+func TestNewClientWithTokenSourceRefreshes(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	// Tokens expiring within the refresh margin are renewed on every call
+	tokens := newTokenServer(t, 1)
+
+	client, _ := NewClientWithTokenSource(ClientCredentials(tokens.URL, "client-id", "client-secret"), srv.BaseURL(), zap.NewNop())
+	for i := 0; i < 2; i++ {
+		if _, err := client.GenerateCompletion(context.Background(), authRequest); err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+	}
+
+	requests := srv.Requests()
+	if got := requests[1].Header.Get("Authorization"); got != "Bearer token-2" {
+		t.Errorf("second Authorization = %q, want a refreshed token", got)
+	}
+}
+
+func TestNewClientWithTokenSourceError(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "invalid_client"}`, http.StatusUnauthorized)
+	}))
+	t.Cleanup(tokens.Close)
+
+	client, _ := NewClientWithTokenSource(ClientCredentials(tokens.URL, "client-id", "wrong"), srv.BaseURL(), zap.NewNop())
+	if _, err := client.GenerateCompletion(context.Background(), authRequest); err == nil {
+		t.Error("GenerateCompletion() error = nil, want the token error")
+	}
+	if len(srv.Requests()) != 0 {
+		t.Error("request sent without a token")
+	}
+
+	if _, err := NewClientWithTokenSource(nil, "", zap.NewNop()); err == nil {
+		t.Error("NewClientWithTokenSource(nil) error = nil")
+	}
+}
+
+// azureServer answers Azure OpenAI chat completions, recording the path and
+// headers of the last request
+func azureServer(t *testing.T) (*httptest.Server, *http.Request) {
+	t.Helper()
+	var last http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = *r.Clone(context.Background())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "chatcmpl-1", "model": "gpt-4o", "choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 1, "completion_tokens": 1}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &last
+}
+
+func TestNewAzureClient(t *testing.T) {
+	t.Run("api key", func(t *testing.T) {
+		srv, last := azureServer(t)
+		client, err := NewAzureClient(srv.URL, "azure-key", zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewAzureClient() error = %v", err)
+		}
+		if _, err := client.GenerateCompletion(context.Background(), authRequest); err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		if last.URL.Path != "/openai/deployments/gpt-4o/chat/completions" || last.URL.Query().Get("api-version") == "" {
+			t.Errorf("request URL = %s, want the gpt-4o deployment with an API version", last.URL)
+		}
+		if last.Header.Get("api-key") != "azure-key" || last.Header.Get("Authorization") != "" {
+			t.Errorf("headers = %v, want the api-key header only", last.Header)
+		}
+	})
+
+	t.Run("token source", func(t *testing.T) {
+		srv, last := azureServer(t)
+		tokens := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "aad-token", Expiry: time.Now().Add(time.Hour)})
+		client, err := NewAzureClientWithTokenSource(srv.URL, tokens, zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewAzureClientWithTokenSource() error = %v", err)
+		}
+		if _, err := client.GenerateCompletion(context.Background(), authRequest); err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		if last.Header.Get("Authorization") != "Bearer aad-token" || last.Header.Get("api-key") != "" {
+			t.Errorf("headers = %v, want the bearer token only", last.Header)
+		}
+	})
+
+	t.Run("missing endpoint", func(t *testing.T) {
+		if _, err := NewAzureClient("", "azure-key", zap.NewNop()); err == nil {
+			t.Error("NewAzureClient() error = nil, want missing endpoint")
+		}
+	})
+}
+
+func TestWorkloadIdentity(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	t.Setenv("AZURE_AUTHORITY_HOST", tokens.URL)
+
+	tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+	if err := os.WriteFile(tokenFile, []byte("federated-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := WorkloadIdentity("tenant-id", "client-id", tokenFile, AzureOpenAIScope)
+	if _, err := source.Token(); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	// The rotated service account token is used for the next exchange
+	if err := os.WriteFile(tokenFile, []byte("federated-2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	token, err := source.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token.AccessToken != "token-2" {
+		t.Errorf("AccessToken = %q, want token-2", token.AccessToken)
+	}
+
+	forms := tokens.Forms()
+	for i, want := range []string{"federated-1", "federated-2"} {
+		form := forms[i]
+		if form["client_assertion"] != want || form["client_id"] != "client-id" ||
+			form["client_assertion_type"] != "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" ||
+			form["scope"] != AzureOpenAIScope {
+			t.Errorf("token request %d = %v, want assertion %s", i, form, want)
+		}
+	}
+}
+
+func TestWorkloadIdentityFromEnv(t *testing.T) {
+	t.Setenv("AZURE_TENANT_ID", "")
+	if _, err := WorkloadIdentityFromEnv(); err == nil {
+		t.Error("WorkloadIdentityFromEnv() error = nil without environment")
+	}
+
+	t.Setenv("AZURE_TENANT_ID", "tenant-id")
+	t.Setenv("AZURE_CLIENT_ID", "client-id")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "/var/run/secrets/azure/tokens/azure-identity-token")
+	if _, err := WorkloadIdentityFromEnv(); err != nil {
+		t.Errorf("WorkloadIdentityFromEnv() error = %v", err)
+	}
+}
+
+func TestAzureTokenURL(t *testing.T) {
+	t.Setenv("AZURE_AUTHORITY_HOST", "")
+	if got, want := AzureTokenURL("tenant-id"), "https://login.microsoftonline.com/tenant-id/oauth2/v2.0/token"; got != want {
+		t.Errorf("AzureTokenURL() = %q, want %q", got, want)
+	}
+}
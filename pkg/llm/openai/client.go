@@ -2,10 +2,17 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/aescanero/dago-adapters/pkg/ports"
 	"github.com/aescanero/dago-libs/pkg/domain"
-	"github.com/aescanero/dago-libs/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
 	openai "github.com/sashabaranov/go-openai"
 	"go.uber.org/zap"
 )
@@ -14,6 +21,14 @@ import (
 type Client struct {
 	client *openai.Client
 	logger *zap.Logger
+
+	// jsonModeModels are the models found not to support strict JSON
+	// schemas, asked for JSON mode directly
+	jsonModeModels sync.Map
+
+	// jsonModeSchemas are the model and schema pairs strict mode rejected,
+	// also asked for JSON mode directly
+	jsonModeSchemas sync.Map
 }
 
 // NewClient creates a new OpenAI client
@@ -40,64 +55,199 @@ func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
 	}, nil
 }
 
+// NewClientWithHTTPClient creates an OpenAI client sending its requests
+// with httpClient, e.g. one whose transport handles the headers of an
+// OpenAI-compatible provider. baseURL is optional, as for NewClient.
+func NewClientWithHTTPClient(apiKey, baseURL string, httpClient *http.Client, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	config := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	config.HTTPClient = httpClient
+
+	return &Client{
+		client: openai.NewClientWithConfig(config),
+		logger: logger,
+	}, nil
+}
+
 // Complete performs a standard text completion (ports.LLMClient interface)
-func (c *Client) Complete(ctx context.Context, req ports.CompletionRequest) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+func (c *Client) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.CompleteWithTools(ctx, req, nil)
 }
 
-// CompleteWithTools performs a completion with tool calling support (ports.LLMClient interface)
-func (c *Client) CompleteWithTools(ctx context.Context, req ports.CompletionRequest, tools []ports.Tool) (*ports.CompletionResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+// CompleteWithTools performs a completion with tool calling support
+// (ports.LLMClient interface). Tool calls and results of earlier turns, in
+// messages built with ports.ToolCallsMessage and ports.ToolResultMessage,
+// are sent as assistant tool_calls and "tool" role messages.
+func (c *Client) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("generating completion with tools",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Tools = convertTools(tools)
+
+	resp, err := c.client.CreateChatCompletion(ctx, chatReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	completion := &libports.CompletionResponse{
+		ID:        resp.ID,
+		Model:     resp.Model,
+		Usage:     usageInfo(resp.Usage),
+		CreatedAt: time.Unix(resp.Created, 0),
+	}
+	completion.Message.Role = openai.ChatMessageRoleAssistant
+	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		completion.Message.Content = choice.Message.Content
+		completion.ToolCalls = c.parseToolCalls(choice.Message.ToolCalls)
+		completion.FinishReason = string(choice.FinishReason)
+	}
+
+	c.logger.Debug("completion generated",
+		zap.Int("tool_calls", len(completion.ToolCalls)),
+		zap.Int("input_tokens", completion.Usage.PromptTokens),
+		zap.Int("output_tokens", completion.Usage.CompletionTokens))
+
+	return completion, nil
 }
 
-// CompleteStructured performs a completion with guaranteed JSON schema conformance (ports.LLMClient interface)
-func (c *Client) CompleteStructured(ctx context.Context, req ports.CompletionRequest, schema ports.JSONSchema) (*ports.StructuredResponse, error) {
-	return nil, fmt.Errorf("not implemented")
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance (ports.LLMClient interface), using structured outputs: the
+// schema is sent as a strict json_schema response format. Strict schemas
+// must list every property as required and set additionalProperties to
+// false on every object, so optional properties are sent as required and
+// nullable, and the nulls returned for them dropped from the data. Models
+// that don't support strict schemas, and schemas the API rejects, fall back
+// to JSON mode with the schema in a system message, which guarantees JSON
+// but not its conformance; both are remembered and asked for JSON mode
+// directly.
+func (c *Client) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %v", ports.ErrInvalidRequest, err)
+	}
+	strict, err := json.Marshal(strictSchema(schema))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %v", ports.ErrInvalidRequest, err)
+	}
+
+	c.logger.Debug("generating structured completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)))
+
+	var resp openai.ChatCompletionResponse
+	schemaKey := req.Model + "\x00" + string(data)
+	_, jsonMode := c.jsonModeModels.Load(req.Model)
+	if !jsonMode {
+		_, jsonMode = c.jsonModeSchemas.Load(schemaKey)
+	}
+	if !jsonMode {
+		chatReq := c.chatRequest(req)
+		chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   schemaName(schema),
+				Schema: json.RawMessage(strict),
+				Strict: true,
+			},
+		}
+		resp, err = c.client.CreateChatCompletion(ctx, chatReq)
+
+		var apiErr *openai.APIError
+		if errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusBadRequest && strings.Contains(apiErr.Message, "response_format") {
+			c.logger.Info("strict JSON schema rejected, falling back to JSON mode",
+				zap.String("model", req.Model),
+				zap.String("reason", apiErr.Message))
+			if strings.Contains(apiErr.Message, "not supported") {
+				c.jsonModeModels.Store(req.Model, struct{}{})
+			} else {
+				c.jsonModeSchemas.Store(schemaKey, struct{}{})
+			}
+			jsonMode = true
+		}
+	}
+	if jsonMode {
+		chatReq := c.chatRequest(req)
+		chatReq.Messages = append([]openai.ChatCompletionMessage{{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: "Respond with a JSON object conforming to this JSON schema:\n" + string(data),
+		}}, chatReq.Messages...)
+		chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+		resp, err = c.client.CreateChatCompletion(ctx, chatReq)
+	}
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("API call failed: no choices in response")
+	}
+
+	message := resp.Choices[0].Message
+	if message.Refusal != "" {
+		return nil, fmt.Errorf("model refused to answer: %s", message.Refusal)
+	}
+	structured := &libports.StructuredResponse{
+		Usage:     usageInfo(resp.Usage),
+		CreatedAt: time.Unix(resp.Created, 0),
+	}
+	if err := json.Unmarshal([]byte(message.Content), &structured.Data); err != nil {
+		return nil, fmt.Errorf("invalid structured response: %w", err)
+	}
+	dropNulls(schema, structured.Data)
+
+	c.logger.Debug("structured completion generated",
+		zap.Bool("json_mode", jsonMode),
+		zap.Int("input_tokens", structured.Usage.PromptTokens),
+		zap.Int("output_tokens", structured.Usage.CompletionTokens))
+
+	return structured, nil
 }
 
-// GenerateCompletion generates a completion using domain.LLMRequest (compatibility method)
+// GenerateCompletion generates a completion of a *domain.LLMRequest,
+// returning a *domain.LLMResponse.
+//
+// Deprecated: use Generate, which is typed.
 func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
-	// Type assert the request
 	llmReq, ok := req.(*domain.LLMRequest)
 	if !ok {
-		return nil, fmt.Errorf("invalid request type")
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	resp, err := c.Generate(ctx, llmReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Generate generates a completion of a domain request
+func (c *Client) Generate(ctx context.Context, llmReq *domain.LLMRequest) (*domain.LLMResponse, error) {
+	if llmReq == nil || len(llmReq.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
 	}
 
 	c.logger.Debug("generating completion",
 		zap.String("model", llmReq.Model),
 		zap.Int("message_count", len(llmReq.Messages)))
 
-	// Convert messages to OpenAI format
-	messages := make([]openai.ChatCompletionMessage, 0, len(llmReq.Messages))
-
-	// Add system message if present
-	if llmReq.System != "" {
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    openai.ChatMessageRoleSystem,
-			Content: llmReq.System,
-		})
-	}
-
-	// Add conversation messages
-	for _, msg := range llmReq.Messages {
-		role := ""
-		switch msg.Role {
-		case "user":
-			role = openai.ChatMessageRoleUser
-		case "assistant":
-			role = openai.ChatMessageRoleAssistant
-		case "system":
-			role = openai.ChatMessageRoleSystem
-		default:
-			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
-			role = openai.ChatMessageRoleUser
-		}
-
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    role,
-			Content: msg.Content,
-		})
-	}
+	messages := c.convertMessages(llmReq)
 
 	// Build request
 	chatReq := openai.ChatCompletionRequest{
@@ -113,6 +263,14 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 		chatReq.Temperature = float32(llmReq.Temperature)
 	}
 
+	if len(llmReq.Tools) > 0 {
+		tools := make([]libports.Tool, 0, len(llmReq.Tools))
+		for _, tool := range llmReq.Tools {
+			tools = append(tools, libports.Tool(tool))
+		}
+		chatReq.Tools = convertTools(tools)
+	}
+
 	// Call API
 	resp, err := c.client.CreateChatCompletion(ctx, chatReq)
 	if err != nil {
@@ -120,21 +278,20 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 		return nil, fmt.Errorf("API call failed: %w", err)
 	}
 
-	// Extract content
-	content := ""
-	if len(resp.Choices) > 0 {
-		content = resp.Choices[0].Message.Content
-	}
-
 	// Convert response
 	llmResp := &domain.LLMResponse{
-		Content: content,
-		Model:   resp.Model,
+		Model: resp.Model,
 		Usage: domain.Usage{
 			InputTokens:  resp.Usage.PromptTokens,
 			OutputTokens: resp.Usage.CompletionTokens,
 		},
 	}
+	if len(resp.Choices) > 0 {
+		llmResp.Content = resp.Choices[0].Message.Content
+		for _, call := range c.parseToolCalls(resp.Choices[0].Message.ToolCalls) {
+			llmResp.ToolCalls = append(llmResp.ToolCalls, domain.ToolCall{ID: call.ID, Name: call.Name, Input: call.Arguments})
+		}
+	}
 
 	c.logger.Debug("completion generated",
 		zap.Int("input_tokens", llmResp.Usage.InputTokens),
@@ -142,3 +299,155 @@ func (c *Client) GenerateCompletion(ctx context.Context, req interface{}) (inter
 
 	return llmResp, nil
 }
+
+// convertMessages converts the messages of a domain request to OpenAI
+// format, tool calls and results included, like convertCompletionMessages
+func (c *Client) convertMessages(llmReq *domain.LLMRequest) []openai.ChatCompletionMessage {
+	return c.convertCompletionMessages(ports.CompletionMessages(llmReq))
+}
+
+// chatRequest returns the chat completion request of req, without tools or
+// response format
+func (c *Client) chatRequest(req libports.CompletionRequest) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model:            req.Model,
+		Messages:         c.convertCompletionMessages(req.Messages),
+		MaxTokens:        req.MaxTokens,
+		Temperature:      float32(req.Temperature),
+		TopP:             float32(req.TopP),
+		Stop:             req.Stop,
+		PresencePenalty:  float32(req.PresencePenalty),
+		FrequencyPenalty: float32(req.FrequencyPenalty),
+		User:             req.User,
+	}
+}
+
+// usageInfo converts the token usage of a response
+func usageInfo(usage openai.Usage) libports.UsageInfo {
+	return libports.UsageInfo{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
+
+// schemaName returns the name of a response format: the schema's title,
+// stripped of the characters the API doesn't accept, or "response"
+func schemaName(schema libports.JSONSchema) string {
+	title, _ := schema["title"].(string)
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		case r == ' ':
+			return '_'
+		}
+		return -1
+	}, title)
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	if name == "" {
+		return "response"
+	}
+	return name
+}
+
+// convertCompletionMessages converts messages to OpenAI format. Messages
+// built with ports.ToolCallsMessage become assistant messages with tool
+// calls, and results built with ports.ToolResultMessage "tool" messages;
+// results marked as errors are prefixed with "Error: ", as the API has no
+// field for it. Unknown roles are sent as user messages.
+func (c *Client) convertCompletionMessages(msgs []libports.Message) []openai.ChatCompletionMessage {
+	messages := make([]openai.ChatCompletionMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if content, calls, ok := ports.ParseToolCallsMessage(msg); ok {
+			message := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content}
+			for _, call := range calls {
+				arguments, _ := json.Marshal(call.Arguments)
+				if call.Arguments == nil {
+					arguments = []byte("{}")
+				}
+				message.ToolCalls = append(message.ToolCalls, openai.ToolCall{
+					ID:       call.ID,
+					Type:     openai.ToolTypeFunction,
+					Function: openai.FunctionCall{Name: call.Name, Arguments: string(arguments)},
+				})
+			}
+			messages = append(messages, message)
+			continue
+		}
+
+		if result, ok := ports.ParseToolResultMessage(msg); ok {
+			content := result.Content
+			if result.IsError {
+				content = "Error: " + content
+			}
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    content,
+				ToolCallID: result.ToolCallID,
+			})
+			continue
+		}
+
+		role := ""
+		switch msg.Role {
+		case "user":
+			role = openai.ChatMessageRoleUser
+		case "assistant":
+			role = openai.ChatMessageRoleAssistant
+		case "system":
+			role = openai.ChatMessageRoleSystem
+		default:
+			c.logger.Warn("unknown message role, defaulting to user", zap.String("role", msg.Role))
+			role = openai.ChatMessageRoleUser
+		}
+		messages = append(messages, openai.ChatCompletionMessage{Role: role, Content: msg.Content, Name: msg.Name})
+	}
+	return messages
+}
+
+// convertTools converts tools to OpenAI function tools. Tools without
+// parameters take an empty object, as the API requires a schema.
+func convertTools(tools []libports.Tool) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	converted := make([]openai.Tool, 0, len(tools))
+	for _, tool := range tools {
+		var parameters any = tool.Parameters
+		if tool.Parameters == nil {
+			parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		converted = append(converted, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  parameters,
+			},
+		})
+	}
+	return converted
+}
+
+// parseToolCalls converts the tool calls of a response, decoding their
+// JSON arguments. Arguments that aren't a JSON object, which models
+// occasionally produce, are logged and left empty, for the tool to reject.
+func (c *Client) parseToolCalls(calls []openai.ToolCall) []libports.ToolCall {
+	var parsed []libports.ToolCall
+	for _, call := range calls {
+		var arguments map[string]interface{}
+		if call.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+				c.logger.Warn("invalid tool call arguments",
+					zap.String("tool", call.Function.Name),
+					zap.String("arguments", call.Function.Arguments),
+					zap.Error(err))
+			}
+		}
+		parsed = append(parsed, libports.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments})
+	}
+	return parsed
+}
@@ -2,10 +2,18 @@ package openai
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/schema"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
 	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
 	"go.uber.org/zap"
 )
 
@@ -31,7 +39,7 @@ func TestNewClient(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewClient(tt.apiKey, logger)
+			client, err := NewClient(tt.apiKey, "", logger)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -47,7 +55,7 @@ func TestGenerateCompletion(t *testing.T) {
 	logger := zap.NewNop()
 
 	t.Run("invalid request type", func(t *testing.T) {
-		client, _ := NewClient("test-key", logger)
+		client, _ := NewClient("test-key", "", logger)
 
 		_, err := client.GenerateCompletion(context.Background(), "invalid")
 		if err == nil {
@@ -55,11 +63,15 @@ func TestGenerateCompletion(t *testing.T) {
 		}
 	})
 
-	t.Run("valid request structure", func(t *testing.T) {
-		client, _ := NewClient("test-key", logger)
+	t.Run("valid request", func(t *testing.T) {
+		srv := testutil.NewOpenAIServer(t)
+		srv.Reply(testutil.Reply{Chunks: []string{"Hello, World!"}, InputTokens: 12, OutputTokens: 4})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
 
 		req := &domain.LLMRequest{
-			Model: "gpt-4o",
+			Model:  "gpt-4o",
+			System: "Be brief",
 			Messages: []domain.Message{
 				{Role: "user", Content: "Hello"},
 			},
@@ -67,14 +79,68 @@ func TestGenerateCompletion(t *testing.T) {
 			Temperature: 0.7,
 		}
 
-		// This will fail with API error since we don't have a real key
+		resp, err := client.GenerateCompletion(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GenerateCompletion() error = %v", err)
+		}
+		llmResp := resp.(*domain.LLMResponse)
+		if llmResp.Content != "Hello, World!" {
+			t.Errorf("Content = %q, want %q", llmResp.Content, "Hello, World!")
+		}
+		if llmResp.Usage.InputTokens != 12 || llmResp.Usage.OutputTokens != 4 {
+			t.Errorf("Usage = %+v, want 12 input and 4 output tokens", llmResp.Usage)
+		}
+
+		last, ok := srv.LastRequest()
+		if !ok {
+			t.Fatal("no request received")
+		}
+		if got := last.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q, want Bearer test-key", got)
+		}
+		var body struct {
+			Model     string `json:"model"`
+			MaxTokens int    `json:"max_tokens"`
+			Messages  []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := last.JSON(&body); err != nil {
+			t.Fatalf("request body: %v", err)
+		}
+		if body.Model != req.Model || body.MaxTokens != 100 {
+			t.Errorf("request = %+v", body)
+		}
+		if len(body.Messages) != 2 || body.Messages[0].Role != "system" || body.Messages[0].Content != "Be brief" {
+			t.Errorf("messages = %+v, want the system prompt first", body.Messages)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := testutil.NewOpenAIServer(t)
+		srv.Reply(testutil.Reply{Status: http.StatusBadRequest, Error: "max_tokens is too large"})
+
+		client, _ := NewClient("test-key", srv.BaseURL(), logger)
+
+		req := &domain.LLMRequest{
+			Model:    "gpt-4o",
+			Messages: []domain.Message{{Role: "user", Content: "Hello"}},
+		}
 		_, err := client.GenerateCompletion(context.Background(), req)
-		if err == nil {
-			t.Log("Note: API call succeeded (real API key present?)")
+		if err == nil || !strings.Contains(err.Error(), "max_tokens is too large") {
+			t.Errorf("GenerateCompletion() error = %v, want the API error", err)
 		}
 	})
 }
 
+func TestConformance(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "gpt-4o", Reply: srv.Reply})
+}
+
 // Integration test - only runs with OPENAI_API_KEY environment variable
 func TestGenerateCompletion_Integration(t *testing.T) {
 	apiKey := os.Getenv("OPENAI_API_KEY")
@@ -83,7 +149,7 @@ func TestGenerateCompletion_Integration(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	client, err := NewClient(apiKey, logger)
+	client, err := NewClient(apiKey, "", logger)
 	if err != nil {
 		t.Fatalf("Failed to create client: %v", err)
 	}
@@ -124,3 +190,310 @@ func TestGenerateCompletion_Integration(t *testing.T) {
 		llmResp.Usage.InputTokens,
 		llmResp.Usage.OutputTokens)
 }
+
+func FuzzConvertMessages(f *testing.F) {
+	llmtest.AddFuzzSeeds(f)
+	client, _ := NewClient("test-key", "", zap.NewNop())
+
+	f.Fuzz(func(t *testing.T, system, messages string) {
+		req := llmtest.FuzzRequest(system, messages)
+
+		var converted []libports.Message
+		for _, msg := range client.convertMessages(req) {
+			converted = append(converted, libports.Message{Role: msg.Role, Content: msg.Content})
+		}
+		llmtest.CheckConversion(t, req, converted, "system", "user", "assistant")
+	})
+}
+
+func TestRequestGolden(t *testing.T) {
+	for _, tc := range llmtest.GoldenCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := testutil.NewOpenAIServer(t)
+			client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+			if _, err := client.GenerateCompletion(context.Background(), tc.Request); err != nil {
+				t.Fatalf("GenerateCompletion() error = %v", err)
+			}
+			last, ok := srv.LastRequest()
+			if !ok {
+				t.Fatal("no request received")
+			}
+			testutil.GoldenJSON(t, tc.Name, last.Body)
+		})
+	}
+}
+
+func TestGenerateCompletionToolCalls(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{ToolCalls: []testutil.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"city": "Paris"}`}}})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	resp, err := client.GenerateCompletion(context.Background(), &domain.LLMRequest{
+		Model:    "gpt-4o",
+		Messages: []domain.Message{{Role: "user", Content: "What's the weather in Paris?"}},
+		Tools:    []domain.Tool{{Name: "get_weather", Parameters: map[string]interface{}{"type": "object"}}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+
+	calls := resp.(*domain.LLMResponse).ToolCalls
+	if len(calls) != 1 || calls[0].ID != "call_1" || calls[0].Name != "get_weather" || calls[0].Input["city"] != "Paris" {
+		t.Errorf("ToolCalls = %+v, want get_weather in Paris", calls)
+	}
+}
+
+func TestCompleteWithToolsMultiTurn(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Sunny in Paris, unknown in London."}, InputTokens: 40, OutputTokens: 7})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	first := &libports.CompletionResponse{
+		ToolCalls: []libports.ToolCall{
+			{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}},
+			{ID: "call_2", Name: "get_weather", Arguments: map[string]interface{}{"city": "London"}},
+		},
+	}
+	req := libports.CompletionRequest{
+		Model: "gpt-4o",
+		Messages: []libports.Message{
+			{Role: "user", Content: "Weather in Paris and London?"},
+			ports.ToolCallsMessage(first),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_1", Name: "get_weather", Content: "sunny"}),
+			ports.ToolResultMessage(&ports.ToolResult{ToolCallID: "call_2", Name: "get_weather", Content: "service down", IsError: true}),
+		},
+	}
+	tools := []libports.Tool{{Name: "get_weather", Description: "Current weather"}}
+
+	resp, err := client.CompleteWithTools(context.Background(), req, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if resp.Message.Content != "Sunny in Paris, unknown in London." || resp.FinishReason != "stop" || len(resp.ToolCalls) != 0 {
+		t.Errorf("response = %+v, want the final answer", resp)
+	}
+	if resp.Usage.PromptTokens != 40 || resp.Usage.CompletionTokens != 7 {
+		t.Errorf("Usage = %+v, want 40 prompt and 7 completion tokens", resp.Usage)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		Messages []struct {
+			Role       string `json:"role"`
+			Content    string `json:"content"`
+			ToolCallID string `json:"tool_call_id"`
+			ToolCalls  []struct {
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"messages"`
+		Tools []struct {
+			Type     string `json:"type"`
+			Function struct {
+				Name       string                 `json:"name"`
+				Parameters map[string]interface{} `json:"parameters"`
+			} `json:"function"`
+		} `json:"tools"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if len(body.Tools) != 1 || body.Tools[0].Type != "function" || body.Tools[0].Function.Parameters["type"] != "object" {
+		t.Errorf("tools = %+v, want get_weather with an empty object schema", body.Tools)
+	}
+	if len(body.Messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(body.Messages))
+	}
+	if calls := body.Messages[1].ToolCalls; len(calls) != 2 || calls[1].ID != "call_2" || calls[1].Function.Arguments != `{"city":"London"}` {
+		t.Errorf("assistant tool calls = %+v", calls)
+	}
+	if m := body.Messages[3]; m.Role != "tool" || m.ToolCallID != "call_2" || m.Content != "Error: service down" {
+		t.Errorf("second result = %+v, want an error tool message for call_2", m)
+	}
+}
+
+func TestCompleteStructured(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace","born":1815}`}, InputTokens: 20, OutputTokens: 9})
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	schema := libports.JSONSchema{
+		"title":                "Person record",
+		"type":                 "object",
+		"properties":           map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "born": map[string]interface{}{"type": "integer"}},
+		"required":             []string{"name", "born"},
+		"additionalProperties": false,
+	}
+	req := libports.CompletionRequest{Model: "gpt-4o", Messages: []libports.Message{{Role: "user", Content: "Who was Ada Lovelace?"}}}
+
+	resp, err := client.CompleteStructured(context.Background(), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if resp.Data["name"] != "Ada Lovelace" || resp.Data["born"] != float64(1815) || resp.Usage.TotalTokens != 29 {
+		t.Errorf("response = %+v", resp)
+	}
+
+	last, _ := srv.LastRequest()
+	var body struct {
+		ResponseFormat struct {
+			Type       string `json:"type"`
+			JSONSchema struct {
+				Name   string                 `json:"name"`
+				Strict bool                   `json:"strict"`
+				Schema map[string]interface{} `json:"schema"`
+			} `json:"json_schema"`
+		} `json:"response_format"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	format := body.ResponseFormat
+	if format.Type != "json_schema" || !format.JSONSchema.Strict || format.JSONSchema.Name != "Person_record" || format.JSONSchema.Schema["type"] != "object" {
+		t.Errorf("response_format = %+v, want a strict json_schema", format)
+	}
+}
+
+func TestCompleteStructuredOptionalFields(t *testing.T) {
+	type person struct {
+		Name     string   `json:"name"`
+		Nickname string   `json:"nickname,omitempty"`
+		Role     string   `json:"role,omitempty" enum:"author,engineer"`
+		Tags     []string `json:"tags,omitempty"`
+	}
+	s, err := schema.For[person]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace","nickname":null,"role":"author","tags":null}`}})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+	req := libports.CompletionRequest{Model: "gpt-4o", Messages: []libports.Message{{Role: "user", Content: "Who was Ada Lovelace?"}}}
+
+	resp, err := client.CompleteStructured(context.Background(), req, s)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if _, ok := resp.Data["nickname"]; ok || resp.Data["role"] != "author" {
+		t.Errorf("Data = %v, want the null nickname dropped", resp.Data)
+	}
+	if err := schema.Validate(s, resp.Data); err != nil {
+		t.Errorf("Data doesn't conform to the schema: %v", err)
+	}
+
+	// Strict mode gets every property as required, the optional ones nullable
+	last, _ := srv.LastRequest()
+	var body struct {
+		ResponseFormat struct {
+			JSONSchema struct {
+				Schema struct {
+					Required   []string                          `json:"required"`
+					Properties map[string]map[string]interface{} `json:"properties"`
+				} `json:"schema"`
+			} `json:"json_schema"`
+		} `json:"response_format"`
+	}
+	if err := last.JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	sent := body.ResponseFormat.JSONSchema.Schema
+	if strings.Join(sent.Required, ",") != "name,nickname,role,tags" {
+		t.Errorf("required = %v, want every property", sent.Required)
+	}
+	if got := fmt.Sprint(sent.Properties["name"]["type"]); got != "string" {
+		t.Errorf("name type = %s, want string", got)
+	}
+	if got := fmt.Sprint(sent.Properties["nickname"]["type"]); got != "[string null]" {
+		t.Errorf("nickname type = %s, want [string null]", got)
+	}
+	if got := fmt.Sprint(sent.Properties["role"]["type"], sent.Properties["role"]["enum"]); got != "[string null] [author engineer <nil>]" {
+		t.Errorf("role type and enum = %s, want them nullable", got)
+	}
+	if got := fmt.Sprint(sent.Properties["tags"]["type"]); got != "[array null]" {
+		t.Errorf("tags type = %s, want [array null]", got)
+	}
+}
+
+func TestCompleteStructuredRejectedSchema(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(
+		testutil.Reply{Status: http.StatusBadRequest, Error: "Invalid schema for response_format 'Person': 'pattern' is not permitted."},
+		testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace"}`}},
+		testutil.Reply{Chunks: []string{`{"name":"Alan Turing"}`}},
+	)
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	s := libports.JSONSchema{"title": "Person", "type": "object", "properties": map[string]interface{}{"name": map[string]interface{}{"type": "string", "pattern": "^[A-Z]"}}}
+	req := libports.CompletionRequest{Model: "gpt-4o", Messages: []libports.Message{{Role: "user", Content: "Name a computing pioneer"}}}
+	for _, want := range []string{"Ada Lovelace", "Alan Turing"} {
+		resp, err := client.CompleteStructured(context.Background(), req, s)
+		if err != nil || resp.Data["name"] != want {
+			t.Fatalf("CompleteStructured() = %v, %v, want %s", resp, err, want)
+		}
+	}
+
+	// The rejected schema is remembered and asked for JSON mode directly
+	if got := len(srv.Requests()); got != 3 {
+		t.Errorf("got %d requests, want the rejected one and two in JSON mode", got)
+	}
+}
+
+func TestCompleteStructuredJSONModeFallback(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(
+		testutil.Reply{Status: http.StatusBadRequest, Error: "Invalid parameter: 'response_format' of type 'json_schema' is not supported with this model."},
+		testutil.Reply{Chunks: []string{`{"name":"Ada Lovelace"}`}},
+		testutil.Reply{Chunks: []string{`{"name":"Alan Turing"}`}},
+	)
+
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	schema := libports.JSONSchema{"type": "object", "properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}}}
+	req := libports.CompletionRequest{Model: "gpt-3.5-turbo", Messages: []libports.Message{{Role: "user", Content: "Name a computing pioneer"}}}
+
+	resp, err := client.CompleteStructured(context.Background(), req, schema)
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	if resp.Data["name"] != "Ada Lovelace" {
+		t.Errorf("Data = %v", resp.Data)
+	}
+
+	type jsonModeBody struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+		ResponseFormat struct {
+			Type string `json:"type"`
+		} `json:"response_format"`
+	}
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want the strict one and its fallback", len(requests))
+	}
+	var body jsonModeBody
+	if err := requests[1].JSON(&body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body.ResponseFormat.Type != "json_object" || len(body.Messages) != 2 || body.Messages[0].Role != "system" || !strings.Contains(body.Messages[0].Content, `"properties"`) {
+		t.Errorf("fallback request = %+v, want JSON mode with the schema", body)
+	}
+
+	// The model is remembered, and asked for JSON mode directly
+	if resp, err = client.CompleteStructured(context.Background(), req, schema); err != nil || resp.Data["name"] != "Alan Turing" {
+		t.Fatalf("second CompleteStructured() = %v, %v", resp, err)
+	}
+	if got := len(srv.Requests()); got != 3 {
+		t.Errorf("got %d requests, want one more", got)
+	}
+}
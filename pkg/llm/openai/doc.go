@@ -19,10 +19,40 @@
 //		log.Fatal(err)
 //	}
 //
-//	resp, err := client.GenerateCompletion(ctx, &domain.LLMRequest{
+//	resp, err := client.Generate(ctx, &domain.LLMRequest{
 //		Model: "gpt-4o",
 //		Messages: []domain.Message{
 //			{Role: "user", Content: "Hello!"},
 //		},
 //	})
+//
+// CompleteWithTools sends tools as function definitions and returns the
+// model's tool calls with their decoded arguments. Earlier turns encoded
+// with ports.ToolCallsMessage and ports.ToolResultMessage are sent as
+// assistant tool_calls and "tool" messages, so it can drive
+// agent.RunToolLoop.
+//
+// CompleteStructured asks for a strict json_schema response format, whose
+// output the API guarantees to conform to the schema; strict schemas list
+// every property as required and forbid additional properties, so optional
+// properties, such as omitempty fields of schema.For, are sent as required
+// and nullable, and the nulls returned for them dropped. Models without
+// structured outputs, and schemas strict mode rejects, fall back to JSON
+// mode with the schema given in a system message.
+//
+// CompleteStream streams completions with stream_options.include_usage, so
+// the last chunk reports the usage of the whole response along with the
+// tool calls assembled from their streamed fragments.
+//
+// Tenants that disable static API keys authenticate with bearer tokens from
+// an oauth2.TokenSource, refreshed before they expire: ClientCredentials
+// for any OIDC provider, or WorkloadIdentity for Azure AD workload identity
+// on Kubernetes. Azure OpenAI resources take their endpoint, and models are
+// deployment names:
+//
+//	tokens, err := openai.WorkloadIdentityFromEnv()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := openai.NewAzureClientWithTokenSource("https://my-resource.openai.azure.com", tokens, logger)
 package openai
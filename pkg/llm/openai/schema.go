@@ -0,0 +1,172 @@
+package openai
+
+import "sort"
+
+// strictSchema returns a copy of schema that strict mode accepts. Strict
+// mode wants every property of an object listed in required, so the
+// optional ones, e.g. the omitempty fields of schema.For, are made required
+// and nullable instead: their type gets "null", their enum null, and those
+// without a type are wrapped in an anyOf with the null type. Objects without
+// additionalProperties get it set to false.
+func strictSchema(schema map[string]interface{}) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+
+	strict := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		strict[key] = value
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		required := requiredSet(schema["required"])
+		names := make([]string, 0, len(properties))
+		strictProperties := make(map[string]interface{}, len(properties))
+		for name, property := range properties {
+			names = append(names, name)
+			sub, ok := property.(map[string]interface{})
+			if !ok {
+				strictProperties[name] = property
+				continue
+			}
+			sub = strictSchema(sub)
+			if !required[name] {
+				sub = nullable(sub)
+			}
+			strictProperties[name] = sub
+		}
+		sort.Strings(names)
+
+		strict["properties"] = strictProperties
+		strict["required"] = names
+		if _, ok := schema["additionalProperties"]; !ok {
+			strict["additionalProperties"] = false
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		strict["items"] = strictSchema(items)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		strict["additionalProperties"] = strictSchema(additional)
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf"} {
+		if list, ok := schema[keyword].([]interface{}); ok {
+			strictList := make([]interface{}, len(list))
+			for i, sub := range list {
+				if sub, ok := sub.(map[string]interface{}); ok {
+					strictList[i] = strictSchema(sub)
+				} else {
+					strictList[i] = sub
+				}
+			}
+			strict[keyword] = strictList
+		}
+	}
+	for _, keyword := range []string{"$defs", "definitions"} {
+		if defs, ok := schema[keyword].(map[string]interface{}); ok {
+			strictDefs := make(map[string]interface{}, len(defs))
+			for name, sub := range defs {
+				if sub, ok := sub.(map[string]interface{}); ok {
+					strictDefs[name] = strictSchema(sub)
+				} else {
+					strictDefs[name] = sub
+				}
+			}
+			strict[keyword] = strictDefs
+		}
+	}
+	return strict
+}
+
+// nullable returns schema, a copy made by strictSchema, also accepting null
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsNil(enum) {
+		schema["enum"] = append(append([]interface{}{}, enum...), nil)
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		if t != "null" {
+			schema["type"] = []interface{}{t, "null"}
+		}
+		return schema
+	case []interface{}:
+		for _, name := range t {
+			if name == "null" {
+				return schema
+			}
+		}
+		schema["type"] = append(append([]interface{}{}, t...), "null")
+		return schema
+	case []string:
+		types := make([]interface{}, 0, len(t)+1)
+		for _, name := range t {
+			if name == "null" {
+				return schema
+			}
+			types = append(types, name)
+		}
+		schema["type"] = append(types, "null")
+		return schema
+	}
+
+	return map[string]interface{}{
+		"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}},
+	}
+}
+
+// dropNulls removes from data the null values strict mode returns for the
+// optional properties of schema, so that it conforms to schema again
+func dropNulls(schema map[string]interface{}, data interface{}) {
+	switch data := data.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		required := requiredSet(schema["required"])
+		for name, value := range data {
+			sub, ok := properties[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if value == nil && !required[name] {
+				delete(data, name)
+				continue
+			}
+			dropNulls(sub, value)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for _, item := range data {
+				dropNulls(items, item)
+			}
+		}
+	}
+}
+
+// requiredSet returns the names of a required keyword, []string as schema.For
+// generates it or []interface{} as decoded from JSON
+func requiredSet(required interface{}) map[string]bool {
+	set := map[string]bool{}
+	switch required := required.(type) {
+	case []string:
+		for _, name := range required {
+			set[name] = true
+		}
+	case []interface{}:
+		for _, name := range required {
+			if s, ok := name.(string); ok {
+				set[s] = true
+			}
+		}
+	}
+	return set
+}
+
+func containsNil(values []interface{}) bool {
+	for _, value := range values {
+		if value == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+)
+
+// CompleteStream streams a completion with tools, if any
+// (ports.LLMStreamer interface). Text deltas and the fragments of tool calls
+// are sent as they arrive; the text, parsed tool calls, finish reason and
+// usage on the last chunk. Cancelling ctx closes the stream, and ends it
+// with the text generated so far. Usage is requested with stream_options, and
+// sent by the API in a chunk of its own after the finish reason.
+func (c *Client) CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan ports.StreamChunk, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.logger.Debug("streaming completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(tools)))
+
+	chatReq := c.chatRequest(req)
+	chatReq.Tools = convertTools(tools)
+	chatReq.Stream = true
+	chatReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	stream, err := c.client.CreateChatCompletionStream(ctx, chatReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	chunks := make(chan ports.StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer func() { _ = stream.Close() }()
+
+		var content strings.Builder
+		send := func(chunk ports.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// The last chunk is sent even after a cancellation, with the text
+		// so far
+		finish := func(chunk ports.StreamChunk) {
+			chunk.Content = content.String()
+			chunk.Done = true
+			chunks <- chunk
+		}
+		cancelled := func() {
+			c.logger.Debug("stream cancelled", zap.Int("content_length", content.Len()))
+			finish(ports.StreamChunk{Cancelled: true})
+		}
+
+		var (
+			finishReason string
+			usage        *libports.UsageInfo
+			// Tool calls in progress, in the order of their index
+			toolCalls []openai.ToolCall
+		)
+
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				calls := c.parseToolCalls(toolCalls)
+				c.logger.Debug("completion streamed", zap.Int("tool_calls", len(calls)), zap.Bool("usage", usage != nil))
+				finish(ports.StreamChunk{ToolCalls: calls, FinishReason: finishReason, Usage: usage})
+				return
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					cancelled()
+					return
+				}
+				c.logger.Error("API call failed", zap.Error(err))
+				finish(ports.StreamChunk{Err: fmt.Errorf("API call failed: %w", err)})
+				return
+			}
+
+			if resp.Usage != nil {
+				usage = &libports.UsageInfo{
+					PromptTokens:     resp.Usage.PromptTokens,
+					CompletionTokens: resp.Usage.CompletionTokens,
+					TotalTokens:      resp.Usage.TotalTokens,
+				}
+			}
+			if len(resp.Choices) == 0 {
+				continue
+			}
+			choice := resp.Choices[0]
+			if choice.FinishReason != "" {
+				finishReason = string(choice.FinishReason)
+			}
+			content.WriteString(choice.Delta.Content)
+			if choice.Delta.Content != "" && !send(ports.StreamChunk{Delta: choice.Delta.Content}) {
+				cancelled()
+				return
+			}
+			for _, delta := range choice.Delta.ToolCalls {
+				var index int
+				toolCalls, index = appendToolCallDelta(toolCalls, delta)
+				fragment := &ports.ToolCallDelta{
+					Index:     index,
+					ID:        delta.ID,
+					Name:      delta.Function.Name,
+					Arguments: delta.Function.Arguments,
+				}
+				if !send(ports.StreamChunk{ToolCallDelta: fragment}) {
+					cancelled()
+					return
+				}
+			}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// StreamComplete streams the text of a completion, as libports'
+// CompletionChunk, through CompleteStream
+func (c *Client) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	chunks, err := c.CompleteStream(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ports.CompletionChunks(ctx, chunks), nil
+}
+
+// appendToolCallDelta merges a tool call fragment of a stream chunk into
+// calls, and returns them with the index of its call: the first fragment of
+// a call has its ID and name, and the following ones the next part of its
+// arguments, at the same index
+func appendToolCallDelta(calls []openai.ToolCall, delta openai.ToolCall) ([]openai.ToolCall, int) {
+	// Servers without indexes start each call with its ID
+	index := len(calls) - 1
+	switch {
+	case delta.Index != nil:
+		index = *delta.Index
+	case delta.ID != "":
+		index = len(calls)
+	}
+	index = max(index, 0)
+	for len(calls) <= index {
+		calls = append(calls, openai.ToolCall{Type: openai.ToolTypeFunction})
+	}
+	call := &calls[index]
+	if delta.ID != "" {
+		call.ID = delta.ID
+	}
+	call.Function.Name += delta.Function.Name
+	call.Function.Arguments += delta.Function.Arguments
+	return calls, index
+}
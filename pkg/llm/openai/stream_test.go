@@ -0,0 +1,79 @@
+package openai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestCompleteStream(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{
+		Chunks: []string{"Checking ", "both."},
+		ToolCalls: []testutil.ToolCall{
+			{Name: "get_weather", Arguments: `{"city": "Paris"}`},
+			{Name: "get_weather", Arguments: `{"city": "London"}`},
+		},
+		InputTokens:  25,
+		OutputTokens: 18,
+	})
+	client, _ := NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	req := libports.CompletionRequest{Model: "gpt-4o", Messages: []libports.Message{{Role: "user", Content: "Weather in Paris and London?"}}}
+	chunks, err := client.CompleteStream(context.Background(), req, []libports.Tool{{Name: "get_weather"}})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+
+	var text string
+	var last ports.StreamChunk
+	for chunk := range chunks {
+		text += chunk.Delta
+		last = chunk
+	}
+	if text != "Checking both." {
+		t.Errorf("text = %q, want the chunks", text)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != "tool_calls" {
+		t.Fatalf("last chunk = %+v, want a tool_calls finish", last)
+	}
+	if len(last.ToolCalls) != 2 || last.ToolCalls[0].ID != "call_test_0" || last.ToolCalls[1].Arguments["city"] != "London" {
+		t.Errorf("tool calls = %+v, want the two calls with their arguments", last.ToolCalls)
+	}
+	if last.Usage == nil || last.Usage.PromptTokens != 25 || last.Usage.CompletionTokens != 18 || last.Usage.TotalTokens != 43 {
+		t.Errorf("usage = %+v, want 25 + 18 tokens", last.Usage)
+	}
+
+	var body struct {
+		Stream        bool `json:"stream"`
+		StreamOptions struct {
+			IncludeUsage bool `json:"include_usage"`
+		} `json:"stream_options"`
+		Tools []struct {
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		} `json:"tools"`
+	}
+	sent, _ := srv.LastRequest()
+	if err := sent.JSON(&body); err != nil || !body.Stream || !body.StreamOptions.IncludeUsage || len(body.Tools) != 1 {
+		t.Errorf("request = %+v, %v, want a stream with usage and the tool", body, err)
+	}
+
+	// Errors after the stream started end it
+	srv.Reply(testutil.Reply{Chunks: []string{"Hel"}, Error: "overloaded"})
+	chunks, err = client.CompleteStream(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+	for chunk := range chunks {
+		last = chunk
+	}
+	if !last.Done || last.Err == nil {
+		t.Errorf("last chunk = %+v, want the error", last)
+	}
+}
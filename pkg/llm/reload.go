@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/aescanero/dago-adapters/pkg/config"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// ReloadConfig is the part of Config read from a ports.ConfigStore key by
+// NewReloadingClient, e.g.
+//
+//	{"provider": "openai", "api_key_secret": "dago/llm#openai"}
+//
+// API keys themselves don't belong in the config store: they come from the
+// base Config's APIKey or, with APIKeySecret, its SecretResolver.
+type ReloadConfig struct {
+	Provider     string `json:"provider"`
+	BaseURL      string `json:"base_url,omitempty"`
+	Region       string `json:"region,omitempty"`
+	Timeout      int    `json:"timeout,omitempty"`
+	APIKeySecret string `json:"api_key_secret,omitempty"`
+}
+
+// ReloadingClient is an LLMClient whose provider settings are read from a
+// config store key and applied, by recreating the provider client, as soon
+// as the key changes
+type ReloadingClient struct {
+	base   Config
+	logger *zap.Logger
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	client libports.LLMClient
+}
+
+// NewReloadingClient creates a client from the settings in key, falling back
+// to base's while the key doesn't exist, and watches key until ctx is
+// cancelled or the client is closed. Settings that fail to create a client
+// are logged and the previous client keeps being used.
+func NewReloadingClient(ctx context.Context, store ports.ConfigStore, key string, base *Config) (*ReloadingClient, error) {
+	c := &ReloadingClient{base: *base, logger: base.Logger}
+	if c.logger == nil {
+		c.logger = zap.NewNop()
+		c.base.Logger = c.logger
+	}
+
+	def := ReloadConfig{
+		Provider:     base.Provider,
+		BaseURL:      base.BaseURL,
+		Region:       base.Region,
+		Timeout:      base.Timeout,
+		APIKeySecret: base.APIKeySecret,
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	value, err := config.NewValue(ctx, store, key, def, c.logger)
+	if err != nil {
+		c.cancel()
+		return nil, err
+	}
+
+	c.client, err = c.newClient(value.Load())
+	if err != nil {
+		c.cancel()
+		return nil, err
+	}
+
+	value.OnChange(func(rc ReloadConfig) {
+		client, err := c.newClient(rc)
+		if err != nil {
+			c.logger.Error("failed to apply LLM config, keeping the previous client",
+				zap.String("key", key),
+				zap.Error(err))
+			return
+		}
+
+		// The previous client may still serve in-flight calls, so it is not closed
+		c.mu.Lock()
+		c.client = client
+		c.mu.Unlock()
+		c.logger.Info("LLM config changed, recreated LLM client",
+			zap.String("key", key),
+			zap.String("provider", rc.Provider))
+	})
+	return c, nil
+}
+
+func (c *ReloadingClient) newClient(rc ReloadConfig) (libports.LLMClient, error) {
+	cfg := c.base
+	cfg.Provider = rc.Provider
+	cfg.BaseURL = rc.BaseURL
+	cfg.Region = rc.Region
+	cfg.Timeout = rc.Timeout
+	cfg.APIKeySecret = rc.APIKeySecret
+	return NewClient(&cfg)
+}
+
+func (c *ReloadingClient) current() libports.LLMClient {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *ReloadingClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return c.current().Complete(ctx, req)
+}
+
+// CompleteWithTools performs a completion with tool calling support (ports.LLMClient interface)
+func (c *ReloadingClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	return c.current().CompleteWithTools(ctx, req, tools)
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema conformance (ports.LLMClient interface)
+func (c *ReloadingClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	return c.current().CompleteStructured(ctx, req, schema)
+}
+
+// GenerateCompletion generates a completion using domain.LLMRequest (compatibility method)
+func (c *ReloadingClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	return c.current().GenerateCompletion(ctx, req)
+}
+
+// Close stops watching the config and closes the current provider client
+// if it has a Close method
+func (c *ReloadingClient) Close() error {
+	c.cancel()
+	if closer, ok := c.current().(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
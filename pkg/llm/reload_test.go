@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/ollama"
+	"github.com/aescanero/dago-adapters/pkg/llm/openai"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ libports.LLMClient = (*ReloadingClient)(nil)
+
+// configStore is a ports.ConfigStore holding a single key
+type configStore struct {
+	mu       sync.Mutex
+	value    *ports.ConfigValue
+	revision int64
+	events   chan ports.ConfigEvent
+}
+
+func (s *configStore) Get(ctx context.Context, key string) (*ports.ConfigValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.value == nil {
+		return nil, fmt.Errorf("%w: %s", ports.ErrConfigNotFound, key)
+	}
+	value := *s.value
+	return &value, nil
+}
+
+func (s *configStore) Put(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revision++
+	s.value = &ports.ConfigValue{Key: key, Value: value, Revision: s.revision}
+	s.events <- ports.ConfigEvent{ConfigValue: *s.value}
+	return nil
+}
+
+func (s *configStore) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func (s *configStore) List(ctx context.Context, prefix string) ([]ports.ConfigValue, error) {
+	return nil, nil
+}
+
+func (s *configStore) Watch(ctx context.Context, prefix string) (<-chan ports.ConfigEvent, error) {
+	return s.events, nil
+}
+
+func TestReloadingClient(t *testing.T) {
+	ctx := context.Background()
+	store := &configStore{events: make(chan ports.ConfigEvent, 4)}
+
+	c, err := NewReloadingClient(ctx, store, "llm/default", &Config{Provider: "ollama", APIKey: "test-key", Logger: zap.NewNop()})
+	if err != nil {
+		t.Fatalf("NewReloadingClient() error = %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if _, ok := c.current().(*ollama.Client); !ok {
+		t.Fatalf("client = %T, want the base config's ollama client", c.current())
+	}
+
+	// A provider that can't be created keeps the previous client
+	first := c.current()
+	_ = store.Put(ctx, "llm/default", []byte(`{"provider": "unknown"}`))
+	_ = store.Put(ctx, "llm/default", []byte(`{"provider": "openai", "base_url": "http://localhost:8000/v1"}`))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for c.current() == first {
+		if time.Now().After(deadline) {
+			t.Fatal("client not recreated")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, ok := c.current().(*openai.Client); !ok {
+		t.Errorf("client = %T, want openai", c.current())
+	}
+}
@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Timeout of the secret lookup made by NewClient when Config.Timeout is unset
+const defaultSecretTimeout = 30 * time.Second
+
+// SecretResolver returns the current value of a named secret
+type SecretResolver func(ctx context.Context, name string) (string, error)
+
+// ResolveFrom returns a SecretResolver reading secrets from provider. Wrap
+// the provider in secrets.NewCachedProvider: the resolver is called on every
+// completion.
+func ResolveFrom(provider ports.SecretProvider) SecretResolver {
+	return func(ctx context.Context, name string) (string, error) {
+		secret, err := provider.GetSecret(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return secret.Value, nil
+	}
+}
+
+// secretClient is the client NewClient returns for Config.APIKeySecret. It
+// resolves the key before every call and replaces the provider client when
+// the key was rotated.
+type secretClient struct {
+	cfg    Config
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	apiKey string
+	client libports.LLMClient
+}
+
+// newSecretClient resolves the API key once, so that a missing secret or
+// resolver fails NewClient like a missing key does
+func newSecretClient(cfg *Config) (libports.LLMClient, error) {
+	if cfg.SecretResolver == nil {
+		return nil, fmt.Errorf("SecretResolver is required with APIKeySecret")
+	}
+
+	timeout := defaultSecretTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	c := &secretClient{cfg: *cfg, logger: cfg.Logger}
+	if _, err := c.current(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// current returns the provider client for the current API key. If the
+// secret can't be resolved, the last known key keeps being used.
+func (c *secretClient) current(ctx context.Context) (libports.LLMClient, error) {
+	apiKey, err := c.cfg.SecretResolver(ctx, c.cfg.APIKeySecret)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		if c.client == nil {
+			return nil, fmt.Errorf("failed to resolve API key secret %s: %w", c.cfg.APIKeySecret, err)
+		}
+		c.logger.Warn("failed to resolve API key secret, using the previous key",
+			zap.String("secret", c.cfg.APIKeySecret),
+			zap.Error(err))
+		return c.client, nil
+	}
+	if c.client != nil && apiKey == c.apiKey {
+		return c.client, nil
+	}
+
+	client, err := newProviderClient(&c.cfg, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if c.client != nil {
+		// The previous client may still serve in-flight calls, so it is not closed
+		c.logger.Info("API key rotated, recreated LLM client",
+			zap.String("provider", c.cfg.Provider),
+			zap.String("secret", c.cfg.APIKeySecret))
+	}
+	c.apiKey = apiKey
+	c.client = client
+	return client, nil
+}
+
+// Complete performs a standard text completion (ports.LLMClient interface)
+func (c *secretClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	client, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Complete(ctx, req)
+}
+
+// CompleteWithTools performs a completion with tool calling support (ports.LLMClient interface)
+func (c *secretClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	client, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.CompleteWithTools(ctx, req, tools)
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema conformance (ports.LLMClient interface)
+func (c *secretClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	client, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.CompleteStructured(ctx, req, schema)
+}
+
+// GenerateCompletion generates a completion using domain.LLMRequest (compatibility method)
+func (c *secretClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	client, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.GenerateCompletion(ctx, req)
+}
+
+// Close closes the current provider client if it has a Close method
+func (c *secretClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if closer, ok := c.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewClient_APIKeySecret(t *testing.T) {
+	apiKey := "key-1"
+	var failure error
+	resolver := func(ctx context.Context, name string) (string, error) {
+		if name != "llm/anthropic#api_key" {
+			t.Errorf("resolver called with %q", name)
+		}
+		return apiKey, failure
+	}
+
+	if _, err := NewClient(&Config{Provider: "anthropic", APIKeySecret: "llm/anthropic#api_key"}); err == nil {
+		t.Error("NewClient() without SecretResolver expected error")
+	}
+
+	failure = errors.New("vault sealed")
+	if _, err := NewClient(&Config{
+		Provider:       "anthropic",
+		APIKeySecret:   "llm/anthropic#api_key",
+		SecretResolver: resolver,
+	}); err == nil {
+		t.Error("NewClient() with an unresolvable secret expected error")
+	}
+	failure = nil
+
+	client, err := NewClient(&Config{
+		Provider:       "anthropic",
+		APIKeySecret:   "llm/anthropic#api_key",
+		SecretResolver: resolver,
+		Logger:         zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	sc := client.(*secretClient)
+	ctx := context.Background()
+	first, _ := sc.current(ctx)
+	if same, _ := sc.current(ctx); same != first {
+		t.Error("current() recreated the client without a key change")
+	}
+
+	apiKey = "key-2"
+	rotated, err := sc.current(ctx)
+	if err != nil || rotated == first {
+		t.Errorf("current() after rotation = %v, %v, want a new client", rotated, err)
+	}
+
+	// Resolver outage: keep the last client
+	failure = errors.New("vault sealed")
+	if kept, err := sc.current(ctx); err != nil || kept != rotated {
+		t.Errorf("current() during outage = %v, %v, want the previous client", kept, err)
+	}
+}
@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// ErrStreamIncomplete is returned when a completion stream closes before
+// its final chunk, e.g. because the provider connection dropped
+var ErrStreamIncomplete = errors.New("stream closed before final chunk")
+
+// WriteStream writes the deltas of a completion stream to w as they arrive,
+// flushing after each one when w is an http.Flusher, e.g. the
+// ResponseWriter of an SSE or chunked HTTP handler. It returns the number
+// of bytes written.
+func WriteStream(ctx context.Context, w io.Writer, chunks <-chan libports.CompletionChunk) (int64, error) {
+	flusher, _ := w.(http.Flusher)
+	var written int64
+
+	err := StreamTo(ctx, chunks, func(chunk libports.CompletionChunk) error {
+		if chunk.Delta == "" {
+			return nil
+		}
+		n, err := io.WriteString(w, chunk.Delta)
+		written += int64(n)
+		if err != nil {
+			return fmt.Errorf("failed to write stream: %w", err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	return written, err
+}
+
+// StreamTo calls fn with each chunk of a completion stream, including the
+// final one. The next chunk isn't read until fn returns, so a slow fn
+// slows the provider down instead of buffering. If fn returns an error or
+// ctx is cancelled, the rest of the stream is drained in the background so
+// the adapter's goroutine can exit, and the error is returned.
+func StreamTo(ctx context.Context, chunks <-chan libports.CompletionChunk, fn func(libports.CompletionChunk) error) error {
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				return ErrStreamIncomplete
+			}
+			if err := fn(chunk); err != nil {
+				drain(chunks)
+				return err
+			}
+			if chunk.IsFinal {
+				drain(chunks)
+				return nil
+			}
+		case <-ctx.Done():
+			drain(chunks)
+			return ctx.Err()
+		}
+	}
+}
+
+// BufferStream decouples a completion stream from a slow consumer, reading
+// ahead up to size chunks. The returned channel is closed after the final
+// chunk, when the stream closes or when ctx is cancelled.
+func BufferStream(ctx context.Context, chunks <-chan libports.CompletionChunk, size int) <-chan libports.CompletionChunk {
+	out := make(chan libports.CompletionChunk, size)
+	go func() {
+		defer close(out)
+		_ = StreamTo(ctx, chunks, func(chunk libports.CompletionChunk) error {
+			select {
+			case out <- chunk:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return out
+}
+
+// CollectStream reads a completion stream to its end and returns the
+// aggregated response. If the stream is cut or ctx is cancelled, the
+// response holds the text received so far, with an empty finish reason,
+// and the error is returned with it.
+func CollectStream(ctx context.Context, chunks <-chan libports.CompletionChunk) (*libports.CompletionResponse, error) {
+	var content strings.Builder
+	finished := false
+
+	err := StreamTo(ctx, chunks, func(chunk libports.CompletionChunk) error {
+		content.WriteString(chunk.Delta)
+		finished = chunk.IsFinal
+		return nil
+	})
+
+	resp := &libports.CompletionResponse{
+		Message:   libports.Message{Role: "assistant", Content: content.String()},
+		CreatedAt: time.Now(),
+	}
+	if finished {
+		resp.FinishReason = "stop"
+	}
+	return resp, err
+}
+
+// drain discards the rest of a stream in the background
+func drain(chunks <-chan libports.CompletionChunk) {
+	go func() {
+		for range chunks {
+		}
+	}()
+}
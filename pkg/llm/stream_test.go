@@ -0,0 +1,170 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// testStream streams the deltas, then a final chunk unless cut, closing
+// the channel when done or when ctx is cancelled
+func testStream(ctx context.Context, cut bool, deltas ...string) <-chan libports.CompletionChunk {
+	chunks := make(chan libports.CompletionChunk)
+	go func() {
+		defer close(chunks)
+		all := make([]libports.CompletionChunk, 0, len(deltas)+1)
+		for _, delta := range deltas {
+			all = append(all, libports.CompletionChunk{Delta: delta})
+		}
+		if !cut {
+			all = append(all, libports.CompletionChunk{IsFinal: true})
+		}
+		for _, chunk := range all {
+			select {
+			case chunks <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return chunks
+}
+
+func TestCollectStream(t *testing.T) {
+	ctx := context.Background()
+
+	resp, err := CollectStream(ctx, testStream(ctx, false, "Hello", ", ", "World"))
+	if err != nil {
+		t.Fatalf("CollectStream() error = %v", err)
+	}
+	if resp.Message.Content != "Hello, World" || resp.Message.Role != "assistant" || resp.FinishReason != "stop" {
+		t.Errorf("CollectStream() = %+v, want the whole text", resp)
+	}
+
+	resp, err = CollectStream(ctx, testStream(ctx, true, "Hel", "lo"))
+	if !errors.Is(err, ErrStreamIncomplete) {
+		t.Errorf("CollectStream() error = %v, want ErrStreamIncomplete", err)
+	}
+	if resp.Message.Content != "Hello" || resp.FinishReason != "" {
+		t.Errorf("CollectStream() = %+v, want the partial text without finish reason", resp)
+	}
+}
+
+func TestCollectStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// A stream that never ends
+	chunks := make(chan libports.CompletionChunk, 1)
+	chunks <- libports.CompletionChunk{Delta: "partial"}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	resp, err := CollectStream(ctx, chunks)
+	if !errors.Is(err, context.Canceled) || resp.Message.Content != "partial" {
+		t.Errorf("CollectStream() = %q, %v, want the partial text and context.Canceled", resp.Message.Content, err)
+	}
+}
+
+func TestWriteStream(t *testing.T) {
+	ctx := context.Background()
+	rec := httptest.NewRecorder()
+
+	n, err := WriteStream(ctx, rec, testStream(ctx, false, "data: a\n\n", "data: b\n\n"))
+	if err != nil {
+		t.Fatalf("WriteStream() error = %v", err)
+	}
+	if rec.Body.String() != "data: a\n\ndata: b\n\n" || n != int64(rec.Body.Len()) {
+		t.Errorf("WriteStream() wrote %d bytes: %q", n, rec.Body.String())
+	}
+	if !rec.Flushed {
+		t.Error("WriteStream() didn't flush the response")
+	}
+}
+
+// failingWriter fails after its first write
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > 1 {
+		return 0, errors.New("connection reset")
+	}
+	return len(p), nil
+}
+
+func TestWriteStreamWriteError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	chunks := testStream(ctx, false, "a", "b", "c", "d")
+
+	var w failingWriter
+	if _, err := WriteStream(ctx, &w, chunks); err == nil {
+		t.Fatal("WriteStream() error = nil, want the write error")
+	}
+
+	// The rest of the stream is drained, letting the producer finish
+	select {
+	case <-waitClosed(chunks):
+	case <-time.After(time.Second):
+		t.Error("stream not drained after the write error")
+	}
+}
+
+func waitClosed(chunks <-chan libports.CompletionChunk) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range chunks {
+		}
+		close(done)
+	}()
+	return done
+}
+
+func TestStreamToBackpressure(t *testing.T) {
+	ctx := context.Background()
+	var got []string
+
+	err := StreamTo(ctx, testStream(ctx, false, "a", "b"), func(chunk libports.CompletionChunk) error {
+		time.Sleep(5 * time.Millisecond)
+		got = append(got, chunk.Delta)
+		return nil
+	})
+	if err != nil || len(got) != 3 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("StreamTo() = %v, %q, want the 2 deltas and the final chunk", err, got)
+	}
+}
+
+func TestBufferStream(t *testing.T) {
+	ctx := context.Background()
+	source := make(chan libports.CompletionChunk)
+	sent := make(chan struct{})
+	go func() {
+		defer close(source)
+		for _, delta := range []string{"a", "b", "c"} {
+			source <- libports.CompletionChunk{Delta: delta}
+		}
+		source <- libports.CompletionChunk{IsFinal: true}
+		close(sent)
+	}()
+
+	buffered := BufferStream(ctx, source, 10)
+	// The whole stream is read ahead without a consumer
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("BufferStream() didn't read ahead")
+	}
+
+	resp, err := CollectStream(ctx, buffered)
+	if err != nil || resp.Message.Content != "abc" {
+		t.Errorf("CollectStream(buffered) = %q, %v, want abc", resp.Message.Content, err)
+	}
+}
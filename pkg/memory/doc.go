@@ -0,0 +1,25 @@
+// Package memory provides adapters for the ports.Memory interface (pkg/ports
+// in this repository), which keeps agent memory across runs, and a
+// Summarizer that maintains long-term memory with an LLM.
+//
+// Memory has three layers. Episodic memories are appended as they happen
+// and embedded with a ports.Embedder. Recall returns the ones most similar
+// in meaning to a text, e.g. the user's latest message. The long-term
+// summary condenses them; Summarizer rewrites it with the memories appended
+// since it was last updated, and is meant to run at the end of each run or
+// periodically.
+//
+// Available implementations:
+//   - redis: Memories as hashes, scored by cosine similarity in the client
+//   - postgres: Memories in a table, searched with the pgvector extension
+//
+// Usage:
+//
+//	store := redis.NewStore(client, embedder, logger)
+//	summarizer := memory.NewSummarizer(store, llm, logger)
+//
+//	_, err := store.Append(ctx, ports.MemoryEntry{Scope: userID, Content: "Prefers reports as PDF"})
+//
+//	summary, err := summarizer.Summarize(ctx, userID)
+//	recalled, err := store.Recall(ctx, ports.MemoryQuery{Scope: userID, Text: message, MinScore: 0.3})
+package memory
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// DefaultRecallLimit is the number of memories recalled when the query
+// doesn't set a limit
+const DefaultRecallLimit = 5
+
+// NewID returns a random memory ID
+func NewID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Embed returns the embedding of one text
+func Embed(ctx context.Context, embedder ports.Embedder, text string, inputType ports.EmbeddingInputType) ([]float32, error) {
+	resp, err := embedder.Embed(ctx, ports.EmbeddingRequest{Texts: []string{text}, InputType: inputType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed memory: %w", err)
+	}
+	if len(resp.Embeddings) != 1 {
+		return nil, fmt.Errorf("expected 1 embedding, got %d", len(resp.Embeddings))
+	}
+	return resp.Embeddings[0], nil
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors, or
+// zero if their lengths differ or either is zero
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// Rank sorts memories most relevant first and keeps those the query asks
+// for
+func Rank(memories []ports.RecalledMemory, query ports.MemoryQuery) []ports.RecalledMemory {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultRecallLimit
+	}
+
+	kept := memories[:0]
+	for _, m := range memories {
+		if m.Score >= query.MinScore {
+			kept = append(kept, m)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].Score != kept[j].Score {
+			return kept[i].Score > kept[j].Score
+		}
+		return kept[i].CreatedAt.After(kept[j].CreatedAt)
+	})
+	if len(kept) > limit {
+		kept = kept[:limit]
+	}
+	return kept
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/openai"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b []float32
+		want float64
+	}{
+		{[]float32{1, 0}, []float32{1, 0}, 1},
+		{[]float32{1, 0}, []float32{0, 2}, 0},
+		{[]float32{1, 1}, []float32{-1, -1}, -1},
+		{[]float32{1, 0}, []float32{1, 0, 0}, 0},
+		{[]float32{0, 0}, []float32{1, 0}, 0},
+	}
+	for _, tt := range tests {
+		if got := CosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("CosineSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	now := time.Now()
+	memories := []ports.RecalledMemory{
+		{MemoryEntry: ports.MemoryEntry{ID: "low"}, Score: 0.1},
+		{MemoryEntry: ports.MemoryEntry{ID: "old", CreatedAt: now.Add(-time.Hour)}, Score: 0.8},
+		{MemoryEntry: ports.MemoryEntry{ID: "best"}, Score: 0.9},
+		{MemoryEntry: ports.MemoryEntry{ID: "new", CreatedAt: now}, Score: 0.8},
+	}
+
+	ranked := Rank(memories, ports.MemoryQuery{Limit: 3, MinScore: 0.5})
+	var ids []string
+	for _, m := range ranked {
+		ids = append(ids, m.ID)
+	}
+	if strings.Join(ids, ",") != "best,new,old" {
+		t.Errorf("Rank() = %v", ids)
+	}
+}
+
+// sliceMemory is a ports.Memory holding one scope in slices
+type sliceMemory struct {
+	ports.Memory
+	entries []ports.MemoryEntry
+	summary *ports.MemorySummary
+}
+
+func (m *sliceMemory) Recent(ctx context.Context, scope string, limit int) ([]ports.MemoryEntry, error) {
+	var recent []ports.MemoryEntry
+	for i := len(m.entries) - 1; i >= 0 && len(recent) < limit; i-- {
+		recent = append(recent, m.entries[i])
+	}
+	return recent, nil
+}
+
+func (m *sliceMemory) Summary(ctx context.Context, scope string) (*ports.MemorySummary, error) {
+	if m.summary == nil {
+		return nil, ports.ErrMemoryNotFound
+	}
+	return m.summary, nil
+}
+
+func (m *sliceMemory) SaveSummary(ctx context.Context, summary ports.MemorySummary) error {
+	m.summary = &summary
+	return nil
+}
+
+// echoLLM answers with the number of new memories it was given
+type echoLLM struct {
+	libports.LLMClient
+	prompts []string
+}
+
+func (l *echoLLM) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	prompt := req.Messages[len(req.Messages)-1].Content
+	l.prompts = append(l.prompts, prompt)
+	return &libports.CompletionResponse{Message: libports.Message{
+		Role:    "assistant",
+		Content: fmt.Sprintf(" summary of %d\n", strings.Count(prompt, "\n- ")),
+	}}, nil
+}
+
+func TestSummarizer(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mem := &sliceMemory{}
+	for i, content := range []string{"likes tea", "lives in Madrid", "asked for a report"} {
+		mem.entries = append(mem.entries, ports.MemoryEntry{Content: content, CreatedAt: start.Add(time.Duration(i) * time.Minute)})
+	}
+	llm := &echoLLM{}
+	summarizer := NewSummarizer(mem, llm, zap.NewNop())
+
+	summary, err := summarizer.Summarize(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if summary.Content != "summary of 3" || !summary.Through.Equal(start.Add(2*time.Minute)) || mem.summary == nil {
+		t.Errorf("summary = %+v", summary)
+	}
+	if !strings.Contains(llm.prompts[0], "(empty)") || strings.Index(llm.prompts[0], "likes tea") > strings.Index(llm.prompts[0], "asked for a report") {
+		t.Errorf("prompt = %q", llm.prompts[0])
+	}
+
+	// Nothing new: no completion
+	if _, err := summarizer.Summarize(ctx, "user-1"); err != nil || len(llm.prompts) != 1 {
+		t.Errorf("Summarize() without new memories = %v, %d completions", err, len(llm.prompts))
+	}
+
+	mem.entries = append(mem.entries, ports.MemoryEntry{Content: "prefers PDF", CreatedAt: start.Add(time.Hour)})
+	summary, err = summarizer.Summarize(ctx, "user-1")
+	if err != nil || summary.Content != "summary of 1" || !strings.Contains(llm.prompts[1], "summary of 3") {
+		t.Errorf("Summarize() = %+v, %v, prompt %q", summary, err, llm.prompts[1])
+	}
+
+	if _, err := NewSummarizer(&sliceMemory{}, llm, zap.NewNop()).Summarize(ctx, "nobody"); !errors.Is(err, ports.ErrMemoryNotFound) {
+		t.Errorf("Summarize() of an empty scope error = %v", err)
+	}
+}
+
+func TestSummarizer_Adapter(t *testing.T) {
+	srv := testutil.NewOpenAIServer(t)
+	srv.Reply(testutil.Reply{Chunks: []string{"Likes tea, lives in Madrid."}})
+	llm, _ := openai.NewClient("test-key", srv.BaseURL(), zap.NewNop())
+
+	mem := &sliceMemory{entries: []ports.MemoryEntry{
+		{Content: "likes tea", CreatedAt: time.Now().Add(-time.Minute)},
+		{Content: "lives in Madrid", CreatedAt: time.Now()},
+	}}
+	summary, err := NewSummarizer(mem, llm, zap.NewNop()).Summarize(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if summary.Content != "Likes tea, lives in Madrid." {
+		t.Errorf("summary = %+v, want the completion", summary)
+	}
+}
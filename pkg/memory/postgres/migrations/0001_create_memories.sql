@@ -0,0 +1,19 @@
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE IF NOT EXISTS dago_memories (
+    id         TEXT PRIMARY KEY,
+    scope      TEXT NOT NULL,
+    content    TEXT NOT NULL,
+    metadata   JSONB,
+    embedding  vector NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS dago_memories_scope_idx ON dago_memories (scope, created_at);
+
+CREATE TABLE IF NOT EXISTS dago_memory_summaries (
+    scope      TEXT PRIMARY KEY,
+    content    TEXT NOT NULL,
+    through    TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/internal/pgmigrate"
+	"github.com/aescanero/dago-adapters/pkg/memory"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// Columns selected for every memory read, in scanEntry order
+	entryColumns = `id, scope, content, metadata, created_at`
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Store implements ports.Memory using PostgreSQL with the pgvector
+// extension
+type Store struct {
+	pool     *pgxpool.Pool
+	embedder ports.Embedder
+	logger   *zap.Logger
+}
+
+// NewStore creates a new PostgreSQL memory store embedding memories with
+// embedder
+func NewStore(pool *pgxpool.Pool, embedder ports.Embedder, logger *zap.Logger) *Store {
+	return &Store{
+		pool:     pool,
+		embedder: embedder,
+		logger:   logger,
+	}
+}
+
+// Migrate applies any pending schema migrations, creating the vector
+// extension if needed. It is safe to call concurrently from several
+// processes.
+func (s *Store) Migrate(ctx context.Context) error {
+	return pgmigrate.Migrate(ctx, s.pool, migrationFS, "migrations/", s.logger)
+}
+
+// Append embeds and stores a memory (ports.Memory interface)
+func (s *Store) Append(ctx context.Context, entry ports.MemoryEntry) (*ports.MemoryEntry, error) {
+	embedding, err := memory.Embed(ctx, s.embedder, entry.Content, ports.EmbeddingInputDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.ID = memory.NewID()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO dago_memories (id, scope, content, metadata, embedding, created_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5::text::vector, $6)`,
+		entry.ID, entry.Scope, entry.Content, string(metadata), vectorLiteral(embedding), entry.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to append memory: %w", err)
+	}
+
+	s.logger.Debug("memory appended",
+		zap.String("scope", entry.Scope),
+		zap.String("id", entry.ID))
+
+	return &entry, nil
+}
+
+// Recall returns the memories nearest to the query by cosine distance
+// (ports.Memory interface)
+func (s *Store) Recall(ctx context.Context, query ports.MemoryQuery) ([]ports.RecalledMemory, error) {
+	embedding, err := memory.Embed(ctx, s.embedder, query.Text, ports.EmbeddingInputQuery)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = memory.DefaultRecallLimit
+	}
+
+	// Memories embedded by another model can't be compared, so they are
+	// skipped rather than failing the query
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+entryColumns+`, 1 - (embedding <=> $2::text::vector) AS score
+		FROM dago_memories
+		WHERE scope = $1 AND vector_dims(embedding) = $3
+		ORDER BY embedding <=> $2::text::vector
+		LIMIT $4`,
+		query.Scope, vectorLiteral(embedding), len(embedding), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recall memories: %w", err)
+	}
+	defer rows.Close()
+
+	var recalled []ports.RecalledMemory
+	for rows.Next() {
+		var m ports.RecalledMemory
+		var metadata []byte
+		if err := rows.Scan(&m.ID, &m.Scope, &m.Content, &metadata, &m.CreatedAt, &m.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan memory: %w", err)
+		}
+		if err := json.Unmarshal(metadata, &m.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata of memory %s: %w", m.ID, err)
+		}
+		recalled = append(recalled, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to recall memories: %w", err)
+	}
+
+	return memory.Rank(recalled, query), nil
+}
+
+// Recent returns the scope's newest memories (ports.Memory interface)
+func (s *Store) Recent(ctx context.Context, scope string, limit int) ([]ports.MemoryEntry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+entryColumns+` FROM dago_memories
+		WHERE scope = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2`,
+		scope, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memories: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []ports.MemoryEntry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan memory: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list memories: %w", err)
+	}
+	return entries, nil
+}
+
+// Summary returns the scope's long-term summary (ports.Memory interface)
+func (s *Store) Summary(ctx context.Context, scope string) (*ports.MemorySummary, error) {
+	summary := ports.MemorySummary{Scope: scope}
+	err := s.pool.QueryRow(ctx,
+		`SELECT content, through, updated_at FROM dago_memory_summaries WHERE scope = $1`,
+		scope).Scan(&summary.Content, &summary.Through, &summary.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ports.ErrMemoryNotFound, scope)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load memory summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// SaveSummary replaces the scope's long-term summary (ports.Memory
+// interface)
+func (s *Store) SaveSummary(ctx context.Context, summary ports.MemorySummary) error {
+	if summary.UpdatedAt.IsZero() {
+		summary.UpdatedAt = time.Now()
+	}
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO dago_memory_summaries (scope, content, through, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope) DO UPDATE SET
+			content    = EXCLUDED.content,
+			through    = EXCLUDED.through,
+			updated_at = EXCLUDED.updated_at`,
+		summary.Scope, summary.Content, summary.Through, summary.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save memory summary: %w", err)
+	}
+	return nil
+}
+
+// Forget deletes the scope's memories and summary (ports.Memory interface)
+func (s *Store) Forget(ctx context.Context, scope string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to forget memories: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `DELETE FROM dago_memories WHERE scope = $1`, scope)
+	if err != nil {
+		return fmt.Errorf("failed to forget memories: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM dago_memory_summaries WHERE scope = $1`, scope); err != nil {
+		return fmt.Errorf("failed to forget memories: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to forget memories: %w", err)
+	}
+
+	s.logger.Debug("memories forgotten",
+		zap.String("scope", scope),
+		zap.Int64("count", tag.RowsAffected()))
+
+	return nil
+}
+
+// vectorLiteral formats an embedding as a pgvector literal, e.g. "[1,2.5]"
+func vectorLiteral(embedding []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range embedding {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// scanEntry scans a row selected with entryColumns
+func scanEntry(row pgx.Row) (*ports.MemoryEntry, error) {
+	var entry ports.MemoryEntry
+	var metadata []byte
+	if err := row.Scan(&entry.ID, &entry.Scope, &entry.Content, &metadata, &entry.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(metadata, &entry.Metadata); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+var _ ports.Memory = (*Store)(nil)
+
+func TestVectorLiteral(t *testing.T) {
+	if got := vectorLiteral([]float32{1, 0.25, -3}); got != "[1,0.25,-3]" {
+		t.Errorf("vectorLiteral() = %q", got)
+	}
+	if got := vectorLiteral(nil); got != "[]" {
+		t.Errorf("vectorLiteral(nil) = %q", got)
+	}
+}
+
+// keywordEmbedder embeds texts as counts of a few keywords
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	resp := &ports.EmbeddingResponse{Model: "keywords"}
+	for _, text := range req.Texts {
+		text = strings.ToLower(text)
+		var embedding []float32
+		for _, keyword := range []string{"tea", "coffee", "madrid", "report"} {
+			embedding = append(embedding, float32(strings.Count(text, keyword))+0.01)
+		}
+		resp.Embeddings = append(resp.Embeddings, embedding)
+	}
+	return resp, nil
+}
+
+// Integration test - only runs with POSTGRES_DSN environment variable, on a
+// server with the pgvector extension
+func TestStore_Integration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN not set, skipping integration test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer pool.Close()
+
+	store := NewStore(pool, keywordEmbedder{}, zap.NewNop())
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	scope := "memory-pg-integration"
+	_ = store.Forget(ctx, scope)
+	defer func() { _ = store.Forget(ctx, scope) }()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	for i, content := range []string{"Drinks tea every morning", "Lives in Madrid", "Tea with lemon, never coffee"} {
+		if _, err := store.Append(ctx, ports.MemoryEntry{
+			Scope:     scope,
+			Content:   content,
+			Metadata:  map[string]string{"run_id": "run-1"},
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	recalled, err := store.Recall(ctx, ports.MemoryQuery{Scope: scope, Text: "tea", Limit: 2})
+	if err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if len(recalled) != 2 || recalled[0].Content != "Drinks tea every morning" || recalled[0].Metadata["run_id"] != "run-1" {
+		t.Errorf("Recall() = %+v", recalled)
+	}
+
+	recent, err := store.Recent(ctx, scope, 1)
+	if err != nil || len(recent) != 1 || recent[0].Content != "Tea with lemon, never coffee" {
+		t.Errorf("Recent() = %+v, %v", recent, err)
+	}
+
+	if err := store.SaveSummary(ctx, ports.MemorySummary{Scope: scope, Content: "Drinks tea.", Through: start}); err != nil {
+		t.Fatalf("SaveSummary() error = %v", err)
+	}
+	if summary, err := store.Summary(ctx, scope); err != nil || summary.Content != "Drinks tea." {
+		t.Errorf("Summary() = %+v, %v", summary, err)
+	}
+
+	if err := store.Forget(ctx, scope); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	if _, err := store.Summary(ctx, scope); !errors.Is(err, ports.ErrMemoryNotFound) {
+		t.Errorf("Summary() after Forget() error = %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+// Package redis provides a Redis implementation of the Memory interface
+// (pkg/ports in this repository).
+//
+// Key Design:
+//   - Each memory is a hash with its content, metadata and embedding, the
+//     embedding as little-endian float32s
+//   - Each scope is a sorted set of its memory IDs scored by creation time
+//   - Recall scores the scope's newest memories (1000 by default, see
+//     SetScanLimit) by cosine similarity in the client, so it needs no
+//     search module; older memories live on through the summary
+//   - The summary is a hash per scope
+//
+// Usage:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	store := memredis.NewStore(client, embedder, logger)
+//
+//	recalled, err := store.Recall(ctx, ports.MemoryQuery{Scope: userID, Text: message})
+package redis
@@ -0,0 +1,273 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/memory"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default prefix of memory keys
+	defaultPrefix = "dago:memory:"
+
+	// DefaultScanLimit is the number of newest memories scored by Recall
+	DefaultScanLimit = 1000
+
+	// Memory hash fields
+	fieldContent   = "content"
+	fieldMetadata  = "metadata"
+	fieldCreatedAt = "created_at"
+	fieldEmbedding = "embedding"
+	fieldThrough   = "through"
+	fieldUpdatedAt = "updated_at"
+
+	// Keys deleted per DEL command by Forget
+	deleteBatchSize = 500
+)
+
+// Store implements ports.Memory using Redis. Each memory is a hash holding
+// its embedding, and each scope a sorted set of its memory IDs scored by
+// creation time. Recall scores the scope's newest memories by cosine
+// similarity in the client, so it needs no search module and works with any
+// Redis deployment.
+type Store struct {
+	client    redis.UniversalClient
+	embedder  ports.Embedder
+	logger    *zap.Logger
+	prefix    string
+	scanLimit int64
+}
+
+// NewStore creates a new Redis memory store embedding memories with embedder
+func NewStore(client redis.UniversalClient, embedder ports.Embedder, logger *zap.Logger) *Store {
+	return &Store{
+		client:    client,
+		embedder:  embedder,
+		logger:    logger,
+		prefix:    defaultPrefix,
+		scanLimit: DefaultScanLimit,
+	}
+}
+
+// SetPrefix sets the prefix of memory keys, e.g. to isolate tenants
+func (s *Store) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+// SetScanLimit sets how many of a scope's newest memories Recall scores.
+// Older memories are only reachable through the summary.
+func (s *Store) SetScanLimit(n int) {
+	s.scanLimit = int64(n)
+}
+
+// Append embeds and stores a memory (ports.Memory interface)
+func (s *Store) Append(ctx context.Context, entry ports.MemoryEntry) (*ports.MemoryEntry, error) {
+	embedding, err := memory.Embed(ctx, s.embedder, entry.Content, ports.EmbeddingInputDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.ID = memory.NewID()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.entryKey(entry.Scope, entry.ID),
+			fieldContent, entry.Content,
+			fieldMetadata, metadata,
+			fieldCreatedAt, entry.CreatedAt.UnixMilli(),
+			fieldEmbedding, encodeEmbedding(embedding))
+		pipe.ZAdd(ctx, s.indexKey(entry.Scope), redis.Z{Score: float64(entry.CreatedAt.UnixMilli()), Member: entry.ID})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to append memory: %w", err)
+	}
+
+	s.logger.Debug("memory appended",
+		zap.String("scope", entry.Scope),
+		zap.String("id", entry.ID))
+
+	return &entry, nil
+}
+
+// Recall returns the memories most similar to the query (ports.Memory
+// interface)
+func (s *Store) Recall(ctx context.Context, query ports.MemoryQuery) ([]ports.RecalledMemory, error) {
+	embedding, err := memory.Embed(ctx, s.embedder, query.Text, ports.EmbeddingInputQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, embeddings, err := s.newest(ctx, query.Scope, s.scanLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recall memories: %w", err)
+	}
+
+	recalled := make([]ports.RecalledMemory, len(entries))
+	for i, entry := range entries {
+		recalled[i] = ports.RecalledMemory{
+			MemoryEntry: entry,
+			Score:       memory.CosineSimilarity(embedding, embeddings[i]),
+		}
+	}
+	return memory.Rank(recalled, query), nil
+}
+
+// Recent returns the scope's newest memories (ports.Memory interface)
+func (s *Store) Recent(ctx context.Context, scope string, limit int) ([]ports.MemoryEntry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	entries, _, err := s.newest(ctx, scope, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memories: %w", err)
+	}
+	return entries, nil
+}
+
+// Summary returns the scope's long-term summary (ports.Memory interface)
+func (s *Store) Summary(ctx context.Context, scope string) (*ports.MemorySummary, error) {
+	values, err := s.client.HGetAll(ctx, s.summaryKey(scope)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load memory summary: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: %s", ports.ErrMemoryNotFound, scope)
+	}
+
+	through, _ := strconv.ParseInt(values[fieldThrough], 10, 64)
+	updatedAt, _ := strconv.ParseInt(values[fieldUpdatedAt], 10, 64)
+	return &ports.MemorySummary{
+		Scope:     scope,
+		Content:   values[fieldContent],
+		Through:   time.UnixMilli(through),
+		UpdatedAt: time.UnixMilli(updatedAt),
+	}, nil
+}
+
+// SaveSummary replaces the scope's long-term summary (ports.Memory
+// interface)
+func (s *Store) SaveSummary(ctx context.Context, summary ports.MemorySummary) error {
+	if summary.UpdatedAt.IsZero() {
+		summary.UpdatedAt = time.Now()
+	}
+	err := s.client.HSet(ctx, s.summaryKey(summary.Scope),
+		fieldContent, summary.Content,
+		fieldThrough, summary.Through.UnixMilli(),
+		fieldUpdatedAt, summary.UpdatedAt.UnixMilli()).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save memory summary: %w", err)
+	}
+	return nil
+}
+
+// Forget deletes the scope's memories and summary (ports.Memory interface)
+func (s *Store) Forget(ctx context.Context, scope string) error {
+	ids, err := s.client.ZRange(ctx, s.indexKey(scope), 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to forget memories: %w", err)
+	}
+
+	keys := []string{s.indexKey(scope), s.summaryKey(scope)}
+	for _, id := range ids {
+		keys = append(keys, s.entryKey(scope, id))
+	}
+	for start := 0; start < len(keys); start += deleteBatchSize {
+		end := min(start+deleteBatchSize, len(keys))
+		if err := s.client.Del(ctx, keys[start:end]...).Err(); err != nil {
+			return fmt.Errorf("failed to forget memories: %w", err)
+		}
+	}
+
+	s.logger.Debug("memories forgotten",
+		zap.String("scope", scope),
+		zap.Int("count", len(ids)))
+
+	return nil
+}
+
+// newest loads up to limit of the scope's newest memories and their
+// embeddings
+func (s *Store) newest(ctx context.Context, scope string, limit int64) ([]ports.MemoryEntry, [][]float32, error) {
+	ids, err := s.client.ZRevRange(ctx, s.indexKey(scope), 0, limit-1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, nil, err
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, s.entryKey(scope, id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, nil, err
+	}
+
+	entries := make([]ports.MemoryEntry, 0, len(ids))
+	embeddings := make([][]float32, 0, len(ids))
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		if len(values) == 0 {
+			continue
+		}
+		createdAt, _ := strconv.ParseInt(values[fieldCreatedAt], 10, 64)
+		entry := ports.MemoryEntry{
+			ID:        ids[i],
+			Scope:     scope,
+			Content:   values[fieldContent],
+			CreatedAt: time.UnixMilli(createdAt),
+		}
+		if err := json.Unmarshal([]byte(values[fieldMetadata]), &entry.Metadata); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal metadata of memory %s: %w", ids[i], err)
+		}
+		entries = append(entries, entry)
+		embeddings = append(embeddings, decodeEmbedding([]byte(values[fieldEmbedding])))
+	}
+	return entries, embeddings, nil
+}
+
+func (s *Store) indexKey(scope string) string {
+	return s.prefix + "index:" + scope
+}
+
+func (s *Store) entryKey(scope, id string) string {
+	return s.prefix + "entry:" + scope + ":" + id
+}
+
+func (s *Store) summaryKey(scope string) string {
+	return s.prefix + "summary:" + scope
+}
+
+// encodeEmbedding encodes a vector as little-endian float32s, the layout
+// used by Redis vector search
+func encodeEmbedding(embedding []float32) []byte {
+	buf := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeEmbedding(buf []byte) []float32 {
+	embedding := make([]float32, len(buf)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return embedding
+}
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ ports.Memory = (*Store)(nil)
+
+// keywordEmbedder embeds texts as counts of a few keywords
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) Embed(ctx context.Context, req ports.EmbeddingRequest) (*ports.EmbeddingResponse, error) {
+	resp := &ports.EmbeddingResponse{Model: "keywords"}
+	for _, text := range req.Texts {
+		text = strings.ToLower(text)
+		var embedding []float32
+		for _, keyword := range []string{"tea", "coffee", "madrid", "report"} {
+			embedding = append(embedding, float32(strings.Count(text, keyword)))
+		}
+		resp.Embeddings = append(resp.Embeddings, embedding)
+	}
+	return resp, nil
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewStore(client, keywordEmbedder{}, zap.NewNop())
+}
+
+func TestStore_AppendRecall(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, content := range []string{"Drinks tea every morning", "Lives in Madrid", "Wants the report on Fridays", "Tea with lemon, never coffee"} {
+		entry, err := store.Append(ctx, ports.MemoryEntry{
+			Scope:     "user-1",
+			Content:   content,
+			Metadata:  map[string]string{"run_id": "run-1"},
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if entry.ID == "" {
+			t.Error("Append() assigned no ID")
+		}
+	}
+	if _, err := store.Append(ctx, ports.MemoryEntry{Scope: "user-2", Content: "Tea drinker"}); err != nil {
+		t.Fatal(err)
+	}
+
+	recalled, err := store.Recall(ctx, ports.MemoryQuery{Scope: "user-1", Text: "What tea?", Limit: 3, MinScore: 0.1})
+	if err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if len(recalled) != 2 || recalled[0].Content != "Drinks tea every morning" || recalled[0].Score < 0.99 ||
+		recalled[1].Content != "Tea with lemon, never coffee" || recalled[1].Metadata["run_id"] != "run-1" {
+		t.Errorf("Recall() = %+v", recalled)
+	}
+
+	recent, err := store.Recent(ctx, "user-1", 2)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(recent) != 2 || recent[0].Content != "Tea with lemon, never coffee" || !recent[1].CreatedAt.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("Recent() = %+v", recent)
+	}
+
+	// Only the newest memory is scored
+	store.SetScanLimit(1)
+	if recalled, _ := store.Recall(ctx, ports.MemoryQuery{Scope: "user-1", Text: "tea", MinScore: 0.1}); len(recalled) != 1 {
+		t.Errorf("Recall() with a scan limit of 1 = %+v", recalled)
+	}
+}
+
+func TestStore_SummaryAndForget(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	if _, err := store.Summary(ctx, "user-1"); !errors.Is(err, ports.ErrMemoryNotFound) {
+		t.Errorf("Summary() error = %v, want not found", err)
+	}
+
+	through := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.SaveSummary(ctx, ports.MemorySummary{Scope: "user-1", Content: "Drinks tea.", Through: through}); err != nil {
+		t.Fatalf("SaveSummary() error = %v", err)
+	}
+	summary, err := store.Summary(ctx, "user-1")
+	if err != nil || summary.Content != "Drinks tea." || !summary.Through.Equal(through) || summary.UpdatedAt.IsZero() {
+		t.Errorf("Summary() = %+v, %v", summary, err)
+	}
+
+	if _, err := store.Append(ctx, ports.MemoryEntry{Scope: "user-1", Content: "Lives in Madrid"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Forget(ctx, "user-1"); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	if recent, _ := store.Recent(ctx, "user-1", 10); len(recent) != 0 {
+		t.Errorf("Recent() after Forget() = %+v", recent)
+	}
+	if _, err := store.Summary(ctx, "user-1"); !errors.Is(err, ports.ErrMemoryNotFound) {
+		t.Errorf("Summary() after Forget() error = %v", err)
+	}
+	if keys := store.client.Keys(ctx, "*").Val(); len(keys) != 0 {
+		t.Errorf("keys left after Forget() = %v", keys)
+	}
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxEpisodes is the number of newest memories read by a
+	// Summarize call
+	DefaultMaxEpisodes = 50
+
+	summarizerPrompt = `You maintain the long-term memory of an AI agent.
+Rewrite the current summary to include what the new memories add: facts about
+the user and the world, decisions, preferences and unfinished tasks. Drop
+details that no longer matter and resolve contradictions in favor of the newer
+memory. Answer with the updated summary only, in plain prose.`
+)
+
+// Summarizer folds episodic memories into a scope's long-term summary with
+// an LLM
+type Summarizer struct {
+	memory      ports.Memory
+	llm         libports.LLMClient
+	model       string
+	maxEpisodes int
+	logger      *zap.Logger
+}
+
+// NewSummarizer creates a summarizer of the memories in memory
+func NewSummarizer(memory ports.Memory, llm libports.LLMClient, logger *zap.Logger) *Summarizer {
+	return &Summarizer{
+		memory:      memory,
+		llm:         llm,
+		maxEpisodes: DefaultMaxEpisodes,
+		logger:      logger,
+	}
+}
+
+// SetModel sets the model of summary completions; empty uses the client's
+// default
+func (s *Summarizer) SetModel(model string) {
+	s.model = model
+}
+
+// SetMaxEpisodes sets how many of the newest memories a Summarize call
+// reads. Memories older than those are never summarized, so call Summarize
+// before more than n are appended.
+func (s *Summarizer) SetMaxEpisodes(n int) {
+	s.maxEpisodes = n
+}
+
+// Summarize updates the scope's summary with the memories appended since it
+// was last updated, and returns it. With no new memories the summary is
+// returned as is, or ErrMemoryNotFound if there is none.
+func (s *Summarizer) Summarize(ctx context.Context, scope string) (*ports.MemorySummary, error) {
+	current, err := s.memory.Summary(ctx, scope)
+	if err != nil && !errors.Is(err, ports.ErrMemoryNotFound) {
+		return nil, err
+	}
+
+	recent, err := s.memory.Recent(ctx, scope, s.maxEpisodes)
+	if err != nil {
+		return nil, err
+	}
+	var episodes []ports.MemoryEntry
+	for i := len(recent) - 1; i >= 0; i-- {
+		if current == nil || recent[i].CreatedAt.After(current.Through) {
+			episodes = append(episodes, recent[i])
+		}
+	}
+	if len(episodes) == 0 {
+		if current == nil {
+			return nil, fmt.Errorf("%w: %s", ports.ErrMemoryNotFound, scope)
+		}
+		return current, nil
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("Current summary:\n")
+	if current != nil && current.Content != "" {
+		prompt.WriteString(current.Content)
+	} else {
+		prompt.WriteString("(empty)")
+	}
+	prompt.WriteString("\n\nNew memories, oldest first:\n")
+	for _, episode := range episodes {
+		fmt.Fprintf(&prompt, "- [%s] %s\n", episode.CreatedAt.UTC().Format(time.RFC3339), episode.Content)
+	}
+
+	resp, err := s.llm.Complete(ctx, libports.CompletionRequest{
+		Model: s.model,
+		Messages: []libports.Message{
+			{Role: "system", Content: summarizerPrompt},
+			{Role: "user", Content: prompt.String()},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize memories: %w", err)
+	}
+
+	summary := ports.MemorySummary{
+		Scope:     scope,
+		Content:   strings.TrimSpace(resp.Message.Content),
+		Through:   episodes[len(episodes)-1].CreatedAt,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.memory.SaveSummary(ctx, summary); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("memory summarized",
+		zap.String("scope", scope),
+		zap.Int("episodes", len(episodes)))
+
+	return &summary, nil
+}
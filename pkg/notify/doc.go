@@ -0,0 +1,29 @@
+// Package notify provides adapters for the ports.Notifier interface (pkg/ports
+// in this repository), which sends notifications to people, and helpers to
+// notify of workflow events.
+//
+// EventNotifier renders a notification for each event type with a template
+// (DefaultTemplates cover completed and failed workflows and failed nodes)
+// and is a libports.EventHandler, so it can subscribe to the event bus.
+// RateLimited caps how often a channel is notified, and Multi sends to
+// several channels.
+//
+// Available implementations:
+//   - slack: Slack incoming webhooks, with colored attachments
+//   - webhook: JSON POSTed to any URL, optionally signed with HMAC-SHA256
+//   - smtp: Plain text email through an SMTP server
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/notify/slack"
+//
+//	channel, err := slack.NewClient(os.Getenv("SLACK_WEBHOOK_URL"), logger)
+//	notifier, err := notify.NewEventNotifier(notify.NewRateLimited(channel, time.Minute, 5, logger), logger)
+//	err = notifier.SetTemplate(libports.EventTypeGraphFailed, notify.Template{
+//		Title: "Workflow {{.ExecutionID}} failed",
+//		URL:   "https://dash.example.com/runs/{{.ExecutionID}}",
+//		Level: ports.NotificationError,
+//	})
+//
+//	err = bus.Subscribe(ctx, "events.graph.*", notifier.Handle)
+package notify
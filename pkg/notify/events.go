@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Template renders a notification from an event. Each string is a
+// text/template executed with the libports.Event, e.g.
+// "Workflow {{.ExecutionID}} failed" or "{{with .Data.error}}{{.}}{{end}}".
+type Template struct {
+	Title string
+	Text  string
+	URL   string
+	Level ports.NotificationLevel
+}
+
+// DefaultTemplates notify of finished workflows and failed nodes
+var DefaultTemplates = map[libports.EventType]Template{
+	libports.EventTypeGraphCompleted: {
+		Title: "Workflow {{.ExecutionID}} completed",
+		Level: ports.NotificationSuccess,
+	},
+	libports.EventTypeGraphFailed: {
+		Title: "Workflow {{.ExecutionID}} failed",
+		Text:  "{{with .Data.error}}{{.}}{{end}}",
+		Level: ports.NotificationError,
+	},
+	libports.EventTypeNodeFailed: {
+		Title: "Node {{.NodeID}} of workflow {{.ExecutionID}} failed",
+		Text:  "{{with .Data.error}}{{.}}{{end}}",
+		Level: ports.NotificationError,
+	},
+}
+
+// compiled is a parsed Template
+type compiled struct {
+	title, text, url *template.Template
+	level            ports.NotificationLevel
+}
+
+// EventNotifier sends a notification for each event that has a template.
+// Its Handle method is a libports.EventHandler, so it can subscribe to the
+// event bus directly.
+type EventNotifier struct {
+	notifier ports.Notifier
+	logger   *zap.Logger
+
+	mu        sync.RWMutex
+	templates map[libports.EventType]*compiled
+}
+
+// NewEventNotifier creates an event notifier sending through notifier with
+// DefaultTemplates. It fails if one of them doesn't parse.
+func NewEventNotifier(notifier ports.Notifier, logger *zap.Logger) (*EventNotifier, error) {
+	n := &EventNotifier{
+		notifier:  notifier,
+		logger:    logger,
+		templates: make(map[libports.EventType]*compiled),
+	}
+	for eventType, tmpl := range DefaultTemplates {
+		if err := n.SetTemplate(eventType, tmpl); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// SetTemplate sets the template of an event type, replacing the default one
+func (n *EventNotifier) SetTemplate(eventType libports.EventType, tmpl Template) error {
+	parse := func(field, text string) (*template.Template, error) {
+		t, err := template.New(string(eventType) + "." + field).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template for %s: %w", field, eventType, err)
+		}
+		return t, nil
+	}
+
+	c := &compiled{level: tmpl.Level}
+	var err error
+	if c.title, err = parse("title", tmpl.Title); err != nil {
+		return err
+	}
+	if c.text, err = parse("text", tmpl.Text); err != nil {
+		return err
+	}
+	if c.url, err = parse("url", tmpl.URL); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.templates[eventType] = c
+	return nil
+}
+
+// RemoveTemplate stops notifying of an event type
+func (n *EventNotifier) RemoveTemplate(eventType libports.EventType) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.templates, eventType)
+}
+
+// Render returns the notification of an event, or nil if its type has no
+// template
+func (n *EventNotifier) Render(event libports.Event) (*ports.Notification, error) {
+	n.mu.RLock()
+	c := n.templates[event.Type]
+	n.mu.RUnlock()
+	if c == nil {
+		return nil, nil
+	}
+
+	execute := func(t *template.Template) (string, error) {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, event); err != nil {
+			return "", fmt.Errorf("failed to render notification: %w", err)
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+
+	notification := &ports.Notification{Level: c.level}
+	var err error
+	if notification.Title, err = execute(c.title); err != nil {
+		return nil, err
+	}
+	if notification.Text, err = execute(c.text); err != nil {
+		return nil, err
+	}
+	if notification.URL, err = execute(c.url); err != nil {
+		return nil, err
+	}
+
+	if event.ExecutionID != "" {
+		notification.Fields = append(notification.Fields, ports.NotificationField{Name: "Execution", Value: event.ExecutionID})
+	}
+	if event.NodeID != "" {
+		notification.Fields = append(notification.Fields, ports.NotificationField{Name: "Node", Value: event.NodeID})
+	}
+	return notification, nil
+}
+
+// Handle notifies of an event (libports.EventHandler). Notifications dropped
+// by a rate limit are not errors, so that the event isn't redelivered.
+func (n *EventNotifier) Handle(ctx context.Context, event libports.Event) error {
+	notification, err := n.Render(event)
+	if err != nil || notification == nil {
+		return err
+	}
+
+	err = n.notifier.Notify(ctx, *notification)
+	if errors.Is(err, ports.ErrNotificationRateLimited) {
+		n.logger.Debug("event notification rate limited",
+			zap.String("event_type", string(event.Type)),
+			zap.String("execution_id", event.ExecutionID))
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// Multi is a ports.Notifier sending every notification to several channels
+type Multi []ports.Notifier
+
+// Notify sends the notification to every channel, even if some fail, and
+// returns their joined errors (ports.Notifier interface)
+func (m Multi) Notify(ctx context.Context, notification ports.Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var (
+	_ ports.Notifier = (*RateLimited)(nil)
+	_ ports.Notifier = Multi(nil)
+)
+
+type fakeNotifier struct {
+	mu   sync.Mutex
+	sent []ports.Notification
+	err  error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n ports.Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestEventNotifier_DefaultTemplates(t *testing.T) {
+	fake := &fakeNotifier{}
+	n, err := NewEventNotifier(fake, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEventNotifier() error = %v", err)
+	}
+	ctx := context.Background()
+
+	_ = n.Handle(ctx, libports.Event{Type: libports.EventTypeNodeStarted, ExecutionID: "exec-1"})
+	if len(fake.sent) != 0 {
+		t.Fatalf("notified of an event without template: %+v", fake.sent)
+	}
+
+	err = n.Handle(ctx, libports.Event{
+		Type:        libports.EventTypeNodeFailed,
+		ExecutionID: "exec-1",
+		NodeID:      "fetch",
+		Data:        map[string]interface{}{"error": "timeout"},
+	})
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	_ = n.Handle(ctx, libports.Event{Type: libports.EventTypeGraphFailed, ExecutionID: "exec-1"})
+
+	if len(fake.sent) != 2 {
+		t.Fatalf("sent %d notifications, want 2", len(fake.sent))
+	}
+	got := fake.sent[0]
+	if got.Title != "Node fetch of workflow exec-1 failed" || got.Text != "timeout" || got.Level != ports.NotificationError {
+		t.Errorf("notification = %+v", got)
+	}
+	if len(got.Fields) != 2 || got.Fields[0].Value != "exec-1" || got.Fields[1].Value != "fetch" {
+		t.Errorf("fields = %+v", got.Fields)
+	}
+	if fake.sent[1].Text != "" {
+		t.Errorf("text without error = %q, want empty", fake.sent[1].Text)
+	}
+}
+
+func TestEventNotifier_SetTemplate(t *testing.T) {
+	fake := &fakeNotifier{}
+	n, err := NewEventNotifier(fake, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEventNotifier() error = %v", err)
+	}
+
+	if err := n.SetTemplate(libports.EventTypeGraphCompleted, Template{Title: "{{.ExecutionID"}); err == nil {
+		t.Error("SetTemplate() with an invalid template succeeded")
+	}
+	err = n.SetTemplate(libports.EventTypeGraphCompleted, Template{
+		Title: "Report ready",
+		URL:   "https://dash.example.com/runs/{{.ExecutionID}}",
+	})
+	if err != nil {
+		t.Fatalf("SetTemplate() error = %v", err)
+	}
+	n.RemoveTemplate(libports.EventTypeGraphFailed)
+
+	ctx := context.Background()
+	_ = n.Handle(ctx, libports.Event{Type: libports.EventTypeGraphCompleted, ExecutionID: "exec-2"})
+	_ = n.Handle(ctx, libports.Event{Type: libports.EventTypeGraphFailed, ExecutionID: "exec-2"})
+
+	if len(fake.sent) != 1 {
+		t.Fatalf("sent %d notifications, want 1", len(fake.sent))
+	}
+	if got := fake.sent[0]; got.Title != "Report ready" || got.URL != "https://dash.example.com/runs/exec-2" {
+		t.Errorf("notification = %+v", got)
+	}
+}
+
+func TestNewEventNotifier_InvalidDefault(t *testing.T) {
+	defaults := DefaultTemplates
+	t.Cleanup(func() { DefaultTemplates = defaults })
+	DefaultTemplates = map[libports.EventType]Template{
+		libports.EventTypeGraphFailed: {Title: "{{.ExecutionID"},
+	}
+
+	if n, err := NewEventNotifier(&fakeNotifier{}, zap.NewNop()); err == nil || n != nil {
+		t.Errorf("NewEventNotifier() = %v, %v, want an error for the invalid default", n, err)
+	}
+}
+
+func TestEventNotifier_RateLimitedIsNotAnError(t *testing.T) {
+	fake := &fakeNotifier{err: ports.ErrNotificationRateLimited}
+	n, err := NewEventNotifier(fake, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEventNotifier() error = %v", err)
+	}
+	if err := n.Handle(context.Background(), libports.Event{Type: libports.EventTypeGraphFailed}); err != nil {
+		t.Errorf("Handle() error = %v, want nil", err)
+	}
+
+	fake.err = errors.New("unreachable")
+	if err := n.Handle(context.Background(), libports.Event{Type: libports.EventTypeGraphFailed}); err == nil {
+		t.Error("Handle() didn't return the notifier error")
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	fake := &fakeNotifier{}
+	r := NewRateLimited(fake, time.Minute, 2, zap.NewNop())
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		err := r.Notify(ctx, ports.Notification{Title: "failed"})
+		if wantLimited := i >= 2; errors.Is(err, ports.ErrNotificationRateLimited) != wantLimited {
+			t.Errorf("Notify() #%d error = %v", i, err)
+		}
+	}
+	if len(fake.sent) != 2 {
+		t.Fatalf("sent %d notifications, want 2", len(fake.sent))
+	}
+
+	now = now.Add(time.Minute)
+	if err := r.Notify(ctx, ports.Notification{Title: "failed"}); err != nil {
+		t.Fatalf("Notify() after interval error = %v", err)
+	}
+	last := fake.sent[2]
+	if len(last.Fields) != 1 || last.Fields[0].Value != "3 earlier notifications" {
+		t.Errorf("fields = %+v, want the suppressed count", last.Fields)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	ok, failing := &fakeNotifier{}, &fakeNotifier{err: errors.New("down")}
+	err := Multi{failing, ok}.Notify(context.Background(), ports.Notification{Title: "t"})
+	if err == nil {
+		t.Error("Notify() error = nil, want the failing channel's error")
+	}
+	if len(ok.sent) != 1 {
+		t.Error("a failing channel stopped delivery to the others")
+	}
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// RateLimited is a ports.Notifier that drops notifications sent faster than
+// a limit, so that a failure storm doesn't flood a channel or get it
+// throttled. The number of dropped notifications is added to the next one
+// sent.
+type RateLimited struct {
+	notifier ports.Notifier
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu         sync.Mutex
+	limiter    *rate.Limiter
+	suppressed int
+}
+
+// NewRateLimited wraps notifier to send at most one notification per
+// interval on average, in bursts of up to burst
+func NewRateLimited(notifier ports.Notifier, interval time.Duration, burst int, logger *zap.Logger) *RateLimited {
+	return &RateLimited{
+		notifier: notifier,
+		logger:   logger,
+		now:      time.Now,
+		limiter:  rate.NewLimiter(rate.Every(interval), burst),
+	}
+}
+
+// Notify sends the notification, or returns ErrNotificationRateLimited if
+// the limit was reached (ports.Notifier interface)
+func (r *RateLimited) Notify(ctx context.Context, notification ports.Notification) error {
+	r.mu.Lock()
+	if !r.limiter.AllowN(r.now(), 1) {
+		r.suppressed++
+		r.mu.Unlock()
+		r.logger.Warn("notification rate limited", zap.String("title", notification.Title))
+		return fmt.Errorf("%w: %s", ports.ErrNotificationRateLimited, notification.Title)
+	}
+	suppressed := r.suppressed
+	r.suppressed = 0
+	r.mu.Unlock()
+
+	if suppressed > 0 {
+		notification.Fields = append(notification.Fields[:len(notification.Fields):len(notification.Fields)],
+			ports.NotificationField{Name: "Suppressed", Value: strconv.Itoa(suppressed) + " earlier notifications"})
+	}
+	return r.notifier.Notify(ctx, notification)
+}
@@ -0,0 +1,109 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Attachment colors by notification level
+var colors = map[ports.NotificationLevel]string{
+	ports.NotificationInfo:    "#439FE0",
+	ports.NotificationSuccess: "good",
+	ports.NotificationWarning: "warning",
+	ports.NotificationError:   "danger",
+}
+
+// Client implements the ports.Notifier interface for Slack incoming webhooks
+type Client struct {
+	webhookURL string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Slack client posting to an incoming webhook URL,
+// which determines the channel
+func NewClient(webhookURL string, logger *zap.Logger) (*Client, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+
+	return &Client{
+		webhookURL: webhookURL,
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+type message struct {
+	Text        string       `json:"text"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type attachment struct {
+	Fallback  string  `json:"fallback"`
+	Color     string  `json:"color,omitempty"`
+	Title     string  `json:"title"`
+	TitleLink string  `json:"title_link,omitempty"`
+	Text      string  `json:"text,omitempty"`
+	Fields    []field `json:"fields,omitempty"`
+}
+
+type field struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Notify posts the notification as a message with a colored attachment
+// (ports.Notifier interface)
+func (c *Client) Notify(ctx context.Context, notification ports.Notification) error {
+	level := notification.Level
+	if level == "" {
+		level = ports.NotificationInfo
+	}
+
+	a := attachment{
+		Fallback:  notification.Title,
+		Color:     colors[level],
+		Title:     notification.Title,
+		TitleLink: notification.URL,
+		Text:      notification.Text,
+	}
+	for _, f := range notification.Fields {
+		a.Fields = append(a.Fields, field{Title: f.Name, Value: f.Value, Short: len(f.Value) <= 40})
+	}
+
+	body, err := json.Marshal(message{Text: notification.Title, Attachments: []attachment{a}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API call failed: %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+
+	c.logger.Debug("notification sent",
+		zap.String("channel", "slack"),
+		zap.String("title", notification.Title))
+	return nil
+}
@@ -0,0 +1,72 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.Notifier = (*Client)(nil)
+
+func TestNotify(t *testing.T) {
+	var got message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %v", r.Method, r.Header)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Notify(context.Background(), ports.Notification{
+		Title:  "Workflow exec-1 failed",
+		Text:   "timeout",
+		Level:  ports.NotificationError,
+		URL:    "https://dash.example.com/runs/exec-1",
+		Fields: []ports.NotificationField{{Name: "Execution", Value: "exec-1"}},
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if got.Text != "Workflow exec-1 failed" || len(got.Attachments) != 1 {
+		t.Fatalf("message = %+v", got)
+	}
+	a := got.Attachments[0]
+	if a.Color != "danger" || a.Text != "timeout" || a.TitleLink != "https://dash.example.com/runs/exec-1" {
+		t.Errorf("attachment = %+v", a)
+	}
+	if len(a.Fields) != 1 || a.Fields[0].Title != "Execution" || !a.Fields[0].Short {
+		t.Errorf("fields = %+v", a.Fields)
+	}
+}
+
+func TestNotify_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, zap.NewNop())
+	err := client.Notify(context.Background(), ports.Notification{Title: "t"})
+	if err == nil || !strings.Contains(err.Error(), "invalid_payload") {
+		t.Errorf("Notify() error = %v, want the Slack error", err)
+	}
+}
+
+func TestNewClient_RequiresURL(t *testing.T) {
+	if _, err := NewClient("", zap.NewNop()); err == nil {
+		t.Error("NewClient() without URL succeeded")
+	}
+}
@@ -0,0 +1,12 @@
+// Package slack implements ports.Notifier (pkg/ports in this repository) for
+// Slack incoming webhooks.
+//
+// Each notification is a message with one attachment, colored by level, that
+// links the title to the notification URL and lists its fields. The channel
+// is the one the webhook was created for.
+//
+// Usage:
+//
+//	notifier, err := slack.NewClient(os.Getenv("SLACK_WEBHOOK_URL"), logger)
+//	err = notifier.Notify(ctx, ports.Notification{Title: "Nightly import failed", Level: ports.NotificationError})
+package slack
@@ -0,0 +1,222 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Client implements the ports.Notifier interface by sending plain text
+// email through an SMTP server
+type Client struct {
+	addr        string
+	host        string
+	from        *mail.Address
+	to          []*mail.Address
+	auth        smtp.Auth
+	implicitTLS bool
+	logger      *zap.Logger
+	now         func() time.Time
+
+	// send delivers a message; replaced in tests
+	send func(ctx context.Context, from string, to []string, msg []byte) error
+}
+
+// NewClient creates a new SMTP client sending from one address to the
+// recipients through the server at addr ("host:port"). STARTTLS is used
+// when the server offers it.
+func NewClient(addr, from string, to []string, logger *zap.Logger) (*Client, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", addr, err)
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required")
+	}
+	recipients := make([]*mail.Address, 0, len(to))
+	for _, address := range to {
+		recipient, err := mail.ParseAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", address, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	c := &Client{
+		addr:   addr,
+		host:   host,
+		from:   sender,
+		to:     recipients,
+		logger: logger,
+		now:    time.Now,
+	}
+	c.send = c.sendMail
+	return c, nil
+}
+
+// SetAuth authenticates with PLAIN, which the server must offer over TLS
+func (c *Client) SetAuth(username, password string) {
+	c.auth = smtp.PlainAuth("", username, password, c.host)
+}
+
+// SetImplicitTLS connects with TLS from the start, as on port 465, instead
+// of upgrading with STARTTLS
+func (c *Client) SetImplicitTLS(implicit bool) {
+	c.implicitTLS = implicit
+}
+
+// Notify emails the notification to every recipient (ports.Notifier
+// interface)
+func (c *Client) Notify(ctx context.Context, notification ports.Notification) error {
+	msg, err := c.message(notification)
+	if err != nil {
+		return err
+	}
+
+	to := make([]string, len(c.to))
+	for i, recipient := range c.to {
+		to[i] = recipient.Address
+	}
+	if err := c.send(ctx, c.from.Address, to, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	c.logger.Debug("notification sent",
+		zap.String("channel", "smtp"),
+		zap.String("title", notification.Title),
+		zap.Int("recipients", len(to)))
+	return nil
+}
+
+// message formats the notification as a quoted-printable text email
+func (c *Client) message(notification ports.Notification) ([]byte, error) {
+	level := notification.Level
+	if level == "" {
+		level = ports.NotificationInfo
+	}
+
+	to := make([]string, len(c.to))
+	for i, recipient := range c.to {
+		to[i] = recipient.String()
+	}
+
+	// Header values can't span lines
+	title := strings.Join(strings.Fields(notification.Title), " ")
+
+	var msg bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", key, value)
+	}
+	header("From", c.from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", title))
+	header("Date", c.now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+messageID()+"@"+c.host+">")
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	header("X-Dago-Level", string(level))
+	msg.WriteString("\r\n")
+
+	var body strings.Builder
+	if notification.Text != "" {
+		body.WriteString(notification.Text + "\n\n")
+	}
+	for _, f := range notification.Fields {
+		body.WriteString(f.Name + ": " + f.Value + "\n")
+	}
+	if notification.URL != "" {
+		body.WriteString("\n" + notification.URL + "\n")
+	}
+	if body.Len() == 0 {
+		body.WriteString(title + "\n")
+	}
+
+	w := quotedprintable.NewWriter(&msg)
+	if _, err := w.Write([]byte(strings.ReplaceAll(body.String(), "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("failed to encode email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email: %w", err)
+	}
+	return msg.Bytes(), nil
+}
+
+// sendMail delivers msg through the server, like smtp.SendMail but with
+// ctx and implicit TLS
+func (c *Client) sendMail(ctx context.Context, from string, to []string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	if c.implicitTLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: c.host})
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if !c.implicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: c.host}); err != nil {
+				return err
+			}
+		}
+	}
+	if c.auth != nil {
+		if err := client.Auth(c.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// messageID returns a random Message-ID local part
+func messageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,152 @@
+package smtp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.Notifier = (*Client)(nil)
+
+// fakeServer accepts one SMTP session and records its envelope and data
+type fakeServer struct {
+	addr string
+	from string
+	to   []string
+	data chan string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &fakeServer{addr: ln.Addr().String(), data: make(chan string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				s.from = strings.Trim(strings.TrimPrefix(cmd, "MAIL FROM:"), "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				s.to = append(s.to, strings.Trim(strings.TrimPrefix(cmd, "RCPT TO:"), "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 Go ahead")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				s.data <- data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+	return s
+}
+
+func TestNotify(t *testing.T) {
+	server := newFakeServer(t)
+	client, err := NewClient(server.addr, "Dago <dago@example.com>", []string{"ops@example.com", "oncall@example.com"}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Notify(context.Background(), ports.Notification{
+		Title:  "Workflow exec-1 failed\r\nBcc: evil@example.com",
+		Text:   "Connection r\u00e9fused",
+		Level:  ports.NotificationError,
+		Fields: []ports.NotificationField{{Name: "Execution", Value: "exec-1"}},
+		URL:    "https://dash.example.com/runs/exec-1",
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if server.from != "dago@example.com" || len(server.to) != 2 || server.to[1] != "oncall@example.com" {
+		t.Errorf("envelope = %q -> %v", server.from, server.to)
+	}
+
+	var data string
+	select {
+	case data = <-server.data:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message received")
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	if msg.Header.Get("Bcc") != "" {
+		t.Error("title injected a header")
+	}
+	if got := msg.Header.Get("Subject"); got != "Workflow exec-1 failed Bcc: evil@example.com" {
+		t.Errorf("Subject = %q", got)
+	}
+	if msg.Header.Get("X-Dago-Level") != "error" || msg.Header.Get("To") != "<ops@example.com>, <oncall@example.com>" {
+		t.Errorf("headers = %v", msg.Header)
+	}
+
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	for _, want := range []string{"Connection r\u00e9fused", "Execution: exec-1", "https://dash.example.com/runs/exec-1"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body %q doesn't contain %q", body, want)
+		}
+	}
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	tests := []struct {
+		name, addr, from string
+		to               []string
+	}{
+		{"no port", "smtp.example.com", "a@example.com", []string{"b@example.com"}},
+		{"bad sender", "smtp.example.com:587", "nobody", []string{"b@example.com"}},
+		{"no recipients", "smtp.example.com:587", "a@example.com", nil},
+		{"bad recipient", "smtp.example.com:587", "a@example.com", []string{"b@"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient(tt.addr, tt.from, tt.to, zap.NewNop()); err == nil {
+				t.Error("NewClient() succeeded")
+			}
+		})
+	}
+}
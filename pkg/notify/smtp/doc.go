@@ -0,0 +1,14 @@
+// Package smtp implements ports.Notifier (pkg/ports in this repository) by
+// sending plain text email.
+//
+// The notification title is the subject, and the text, fields and URL the
+// body. Connections are upgraded with STARTTLS when the server offers it, or
+// use TLS from the start with SetImplicitTLS. The notification level is in
+// the X-Dago-Level header for mail filters.
+//
+// Usage:
+//
+//	notifier, err := smtp.NewClient("smtp.example.com:587", "Dago <dago@example.com>", []string{"ops@example.com"}, logger)
+//	notifier.SetAuth(os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+//	err = notifier.Notify(ctx, notification)
+package smtp
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of
+	// TimestampHeader, ".", and the body, when a secret is set
+	SignatureHeader = "X-Dago-Signature"
+
+	// TimestampHeader carries the Unix time the notification was sent, so
+	// receivers can reject replays
+	TimestampHeader = "X-Dago-Timestamp"
+)
+
+// Client implements the ports.Notifier interface by posting notifications
+// as JSON to any URL
+type Client struct {
+	url        string
+	secret     []byte
+	headers    http.Header
+	httpClient *http.Client
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewClient creates a new webhook client posting to url
+func NewClient(url string, logger *zap.Logger) (*Client, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+
+	return &Client{
+		url:        url,
+		headers:    make(http.Header),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+		now:        time.Now,
+	}, nil
+}
+
+// SetSecret signs requests with secret (see SignatureHeader)
+func (c *Client) SetSecret(secret string) {
+	c.secret = []byte(secret)
+}
+
+// SetHeader sets a header sent with every request, e.g. Authorization
+func (c *Client) SetHeader(key, value string) {
+	c.headers.Set(key, value)
+}
+
+// Sign returns the signature of a body sent at timestamp, as receivers
+// should compute it to verify SignatureHeader
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify posts the notification as JSON (ports.Notifier interface). Any 2xx
+// response is a success.
+func (c *Client) Notify(ctx context.Context, notification ports.Notification) error {
+	if notification.Level == "" {
+		notification.Level = ports.NotificationInfo
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range c.headers {
+		httpReq.Header[key] = values
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	timestamp := strconv.FormatInt(c.now().Unix(), 10)
+	httpReq.Header.Set(TimestampHeader, timestamp)
+	if len(c.secret) > 0 {
+		httpReq.Header.Set(SignatureHeader, Sign(c.secret, timestamp, body))
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("API call failed: %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+
+	c.logger.Debug("notification sent",
+		zap.String("channel", "webhook"),
+		zap.String("title", notification.Title))
+	return nil
+}
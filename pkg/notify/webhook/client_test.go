@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.Notifier = (*Client)(nil)
+
+func TestNotify(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.now = func() time.Time { return time.Unix(1700000000, 0) }
+	client.SetSecret("s3cret")
+	client.SetHeader("Authorization", "Bearer token")
+
+	if err := client.Notify(context.Background(), ports.Notification{Title: "Workflow exec-1 completed"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	var got ports.Notification
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Workflow exec-1 completed" || got.Level != ports.NotificationInfo {
+		t.Errorf("body = %+v", got)
+	}
+	if header.Get("Authorization") != "Bearer token" || header.Get(TimestampHeader) != "1700000000" {
+		t.Errorf("headers = %v", header)
+	}
+	if want := Sign([]byte("s3cret"), "1700000000", body); header.Get(SignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", header.Get(SignatureHeader), want)
+	}
+}
+
+func TestNotify_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, zap.NewNop())
+	if err := client.Notify(context.Background(), ports.Notification{Title: "t"}); err == nil {
+		t.Error("Notify() succeeded on a 500 response")
+	}
+	if _, err := NewClient("", zap.NewNop()); err == nil {
+		t.Error("NewClient() without URL succeeded")
+	}
+}
@@ -0,0 +1,14 @@
+// Package webhook implements ports.Notifier (pkg/ports in this repository)
+// by POSTing notifications as JSON to any URL, e.g. an incident tool or an
+// internal service.
+//
+// With SetSecret, requests carry an HMAC-SHA256 signature of the timestamp
+// and body in the X-Dago-Signature header; receivers verify it with Sign and
+// reject stale X-Dago-Timestamp values.
+//
+// Usage:
+//
+//	notifier, err := webhook.NewClient("https://hooks.example.com/dago", logger)
+//	notifier.SetSecret(os.Getenv("WEBHOOK_SECRET"))
+//	err = notifier.Notify(ctx, notification)
+package webhook
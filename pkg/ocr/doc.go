@@ -0,0 +1,21 @@
+// Package ocr provides adapters for the ports.OCREngine interface (pkg/ports
+// in this repository), which recognizes text in images and scanned
+// documents, and Loader, which turns an engine into a ports.DocumentLoader
+// so scans go through the same chunking and embedding as text documents.
+//
+// Available implementations:
+//   - tesseract: Local recognition with the tesseract CLI
+//   - vision: Google Cloud Vision API, including multi-page PDF and TIFF files
+//   - textract: Amazon Textract synchronous text detection
+//
+// Usage:
+//
+//	engine := tesseract.NewEngine(logger)
+//	engine.SetDefaultLanguages([]string{"eng", "deu"})
+//
+//	loader := documents.NewRouter(
+//		pdf.NewLoader(logger),
+//		ocr.NewLoader(engine, []string{"image/png", "image/jpeg", "image/tiff"}, logger),
+//	)
+//	doc, err := loader.LoadFile(ctx, "scan.png")
+package ocr
@@ -0,0 +1,94 @@
+package ocr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/aescanero/dago-adapters/pkg/documents"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// MetaConfidence is the Document.Metadata key of the OCR confidence
+const MetaConfidence = "ocr_confidence"
+
+// Loader implements the ports.DocumentLoader interface for scanned
+// documents and images by recognizing their text with an OCR engine
+type Loader struct {
+	engine    ports.OCREngine
+	types     []string
+	languages []string
+	logger    *zap.Logger
+}
+
+// NewLoader creates a loader of images through engine. types are the
+// formats the engine reads, e.g. "image/png", "image/jpeg", "image/tiff".
+func NewLoader(engine ports.OCREngine, types []string, logger *zap.Logger) *Loader {
+	return &Loader{
+		engine: engine,
+		types:  types,
+		logger: logger,
+	}
+}
+
+// SetLanguages sets the language hints passed to the engine
+func (l *Loader) SetLanguages(languages []string) {
+	l.languages = languages
+}
+
+// MIMETypes returns the image formats of the engine (ports.DocumentLoader
+// interface)
+func (l *Loader) MIMETypes() []string {
+	return append([]string(nil), l.types...)
+}
+
+// Load recognizes the text of an image, starting a section per page
+// (ports.DocumentLoader interface). The mean confidence is recorded in the
+// MetaConfidence metadata.
+func (l *Loader) Load(ctx context.Context, r io.Reader, source string) (*ports.Document, error) {
+	image, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	mimeType := documents.DetectMIMEType(source, image)
+	if mimeType == "" {
+		mimeType = http.DetectContentType(image)
+	}
+
+	result, err := l.engine.Recognize(ctx, ports.OCRRequest{
+		Image:     image,
+		MIMEType:  mimeType,
+		Languages: l.languages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to recognize %s: %w", source, err)
+	}
+
+	var builder documents.Builder
+	for _, page := range result.Pages {
+		builder.Page(page.Number)
+		builder.Preformatted(page.Text)
+	}
+
+	doc := &ports.Document{
+		Source:   source,
+		MIMEType: mimeType,
+		Content:  builder.Content(),
+		Sections: builder.Sections(),
+		Metadata: map[string]string{
+			ports.DocumentMetaPages: strconv.Itoa(len(result.Pages)),
+			MetaConfidence:          strconv.FormatFloat(result.Confidence, 'f', 2, 64),
+		},
+	}
+
+	l.logger.Debug("image recognized",
+		zap.String("source", source),
+		zap.Int("pages", len(result.Pages)),
+		zap.Float64("confidence", result.Confidence))
+
+	return doc, nil
+}
@@ -0,0 +1,49 @@
+package ocr
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.DocumentLoader = (*Loader)(nil)
+
+type fakeEngine struct {
+	req ports.OCRRequest
+}
+
+func (f *fakeEngine) Recognize(ctx context.Context, req ports.OCRRequest) (*ports.OCRResult, error) {
+	f.req = req
+	return NewResult([]ports.OCRPage{
+		{Number: 1, Text: "First  page\r\n\r\n\r\ntext", Confidence: 0.9},
+		{Number: 2, Text: "  ", Confidence: 0},
+		{Number: 3, Text: "Last", Confidence: 0.5},
+	}), nil
+}
+
+func TestLoader(t *testing.T) {
+	engine := &fakeEngine{}
+	loader := NewLoader(engine, []string{"image/tiff"}, zap.NewNop())
+	loader.SetLanguages([]string{"de"})
+
+	doc, err := loader.Load(context.Background(), strings.NewReader("II*\x00tiff"), "scan.tiff")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if engine.req.MIMEType != "image/tiff" || engine.req.Languages[0] != "de" {
+		t.Errorf("request = %+v", engine.req)
+	}
+	if doc.Content != "First  page\n\ntext\n\nLast" {
+		t.Errorf("Content = %q", doc.Content)
+	}
+	if len(doc.Sections) != 3 || doc.Sections[2].Page != 3 || doc.Content[doc.Sections[2].Offset:] != "Last" {
+		t.Errorf("Sections = %+v", doc.Sections)
+	}
+	if doc.Metadata[ports.DocumentMetaPages] != "3" || doc.Metadata[MetaConfidence] != "0.82" {
+		t.Errorf("Metadata = %v", doc.Metadata)
+	}
+}
@@ -0,0 +1,33 @@
+package ocr
+
+import (
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/documents"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// NewResult builds a result from its pages: the text joins the page texts,
+// and the confidence averages the page confidences weighted by text length
+func NewResult(pages []ports.OCRPage) *ports.OCRResult {
+	result := &ports.OCRResult{Pages: pages}
+
+	texts := make([]string, 0, len(pages))
+	var weighted float64
+	var total int
+	for i := range pages {
+		pages[i].Text = documents.NormalizeText(pages[i].Text)
+		if pages[i].Text == "" {
+			continue
+		}
+		texts = append(texts, pages[i].Text)
+		weighted += pages[i].Confidence * float64(len(pages[i].Text))
+		total += len(pages[i].Text)
+	}
+
+	result.Text = strings.Join(texts, "\n\n")
+	if total > 0 {
+		result.Confidence = weighted / float64(total)
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+// Package tesseract implements ports.OCREngine (pkg/ports in this
+// repository) with the tesseract CLI, so documents never leave the worker.
+//
+// Images are piped to tesseract, and the page texts are rebuilt from its
+// TSV output with per-word confidences. Language hints are mapped from ISO
+// 639-1 codes to Tesseract's (e.g. "de" to "deu"); other values, such as
+// "frk" or "chi_tra", are passed through. The traineddata of every language
+// used must be installed.
+//
+// Usage:
+//
+//	engine := tesseract.NewEngine(logger)
+//	result, err := engine.Recognize(ctx, ports.OCRRequest{Image: png, Languages: []string{"en", "es"}})
+package tesseract
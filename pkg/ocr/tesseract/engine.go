@@ -0,0 +1,221 @@
+package tesseract
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ocr"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// DefaultLanguages are the Tesseract languages used when a request has no
+// hints
+var DefaultLanguages = []string{"eng"}
+
+// languageCodes maps ISO 639-1 codes to Tesseract's traineddata names
+var languageCodes = map[string]string{
+	"ar": "ara", "bg": "bul", "ca": "cat", "cs": "ces", "da": "dan",
+	"de": "deu", "el": "ell", "en": "eng", "es": "spa", "et": "est",
+	"eu": "eus", "fi": "fin", "fr": "fra", "ga": "gle", "gl": "glg",
+	"he": "heb", "hi": "hin", "hr": "hrv", "hu": "hun", "id": "ind",
+	"it": "ita", "ja": "jpn", "ko": "kor", "lt": "lit", "lv": "lav",
+	"nl": "nld", "no": "nor", "pl": "pol", "pt": "por", "ro": "ron",
+	"ru": "rus", "sk": "slk", "sl": "slv", "sr": "srp", "sv": "swe",
+	"th": "tha", "tr": "tur", "uk": "ukr", "vi": "vie", "zh": "chi_sim",
+}
+
+// Formats read by Tesseract through Leptonica
+var supportedTypes = map[string]bool{
+	"image/png": true, "image/jpeg": true, "image/tiff": true,
+	"image/bmp": true, "image/gif": true, "image/webp": true,
+}
+
+// Engine implements the ports.OCREngine interface with the tesseract CLI,
+// recognizing text locally without sending documents to a cloud service
+type Engine struct {
+	binary    string
+	languages []string
+	logger    *zap.Logger
+
+	// run executes the tesseract CLI; replaced in tests
+	run func(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+// NewEngine creates a new Tesseract OCR engine using the tesseract binary
+// from PATH and DefaultLanguages
+func NewEngine(logger *zap.Logger) *Engine {
+	e := &Engine{
+		binary:    "tesseract",
+		languages: DefaultLanguages,
+		logger:    logger,
+	}
+	e.run = e.exec
+	return e
+}
+
+// SetBinary sets the path of the tesseract binary
+func (e *Engine) SetBinary(binary string) {
+	e.binary = binary
+}
+
+// SetDefaultLanguages sets the Tesseract languages of requests without
+// hints, e.g. []string{"eng", "spa"}. Their traineddata must be installed.
+func (e *Engine) SetDefaultLanguages(languages []string) {
+	e.languages = languages
+}
+
+// Recognize runs tesseract on the image (ports.OCREngine interface).
+// Multi-page TIFF files produce a page each; PDF files are not supported.
+func (e *Engine) Recognize(ctx context.Context, req ports.OCRRequest) (*ports.OCRResult, error) {
+	mimeType := req.MIMEType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(req.Image)
+	}
+	if !supportedTypes[mimeType] {
+		return nil, fmt.Errorf("%w: %s", ports.ErrUnsupportedImage, mimeType)
+	}
+
+	languages := e.languages
+	if len(req.Languages) > 0 {
+		languages = make([]string, 0, len(req.Languages))
+		for _, language := range req.Languages {
+			if code, ok := languageCodes[strings.ToLower(language)]; ok {
+				language = code
+			}
+			languages = append(languages, language)
+		}
+	}
+
+	args := []string{"stdin", "stdout", "-l", strings.Join(languages, "+"), "tsv"}
+
+	var stdout, stderr bytes.Buffer
+	if err := e.run(ctx, args, bytes.NewReader(req.Image), &stdout, &stderr); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		e.logger.Error("tesseract failed", zap.String("stderr", strings.TrimSpace(stderr.String())), zap.Error(err))
+		return nil, fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	pages, err := parseTSV(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tesseract output: %w", err)
+	}
+	return ocr.NewResult(pages), nil
+}
+
+// exec runs the tesseract CLI
+func (e *Engine) exec(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, e.binary, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// Columns of tesseract's TSV output
+const (
+	colLevel = iota
+	colPage
+	colBlock
+	colParagraph
+	colLine
+	colWord
+	colLeft
+	colTop
+	colWidth
+	colHeight
+	colConfidence
+	colText
+	columns
+)
+
+// Level of word rows in the TSV output
+const wordLevel = 5
+
+// parseTSV rebuilds the page texts from the words of tesseract's TSV
+// output: words of a line are joined by spaces, lines by newlines and
+// blocks and paragraphs by blank lines
+func parseTSV(r io.Reader) ([]ports.OCRPage, error) {
+	var pages []ports.OCRPage
+	var text strings.Builder
+	var confidence float64
+	var words int
+	var lastPage, lastParagraph, lastLine string
+
+	flush := func() {
+		if len(pages) == 0 {
+			return
+		}
+		page := &pages[len(pages)-1]
+		page.Text = text.String()
+		if words > 0 {
+			page.Confidence = confidence / float64(words) / 100
+		}
+		text.Reset()
+		confidence, words = 0, 0
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	header := true
+	for scanner.Scan() {
+		if header {
+			header = false
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < columns-1 {
+			continue
+		}
+
+		if fields[colPage] != lastPage {
+			flush()
+			number, err := strconv.Atoi(fields[colPage])
+			if err != nil {
+				return nil, fmt.Errorf("invalid page number %q", fields[colPage])
+			}
+			pages = append(pages, ports.OCRPage{Number: number})
+			lastPage, lastParagraph, lastLine = fields[colPage], "", ""
+		}
+
+		if level, _ := strconv.Atoi(fields[colLevel]); level != wordLevel || len(fields) < columns {
+			continue
+		}
+		word := strings.TrimSpace(fields[colText])
+		conf, err := strconv.ParseFloat(fields[colConfidence], 64)
+		if word == "" || err != nil || conf < 0 {
+			continue
+		}
+
+		paragraph := fields[colBlock] + "." + fields[colParagraph]
+		line := paragraph + "." + fields[colLine]
+		switch {
+		case text.Len() == 0:
+		case paragraph != lastParagraph:
+			text.WriteString("\n\n")
+		case line != lastLine:
+			text.WriteByte('\n')
+		default:
+			text.WriteByte(' ')
+		}
+		text.WriteString(word)
+		lastParagraph, lastLine = paragraph, line
+
+		confidence += conf
+		words++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return pages, nil
+}
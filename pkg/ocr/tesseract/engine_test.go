@@ -0,0 +1,114 @@
+package tesseract
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.OCREngine = (*Engine)(nil)
+
+// pngHeader is enough for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+const tsv = "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0\t0\t800\t600\t-1\t\n" +
+	"2\t1\t1\t0\t0\t0\t10\t10\t300\t40\t-1\t\n" +
+	"5\t1\t1\t1\t1\t1\t10\t10\t50\t20\t96.5\tInvoice\n" +
+	"5\t1\t1\t1\t1\t2\t70\t10\t50\t20\t91.5\t#42\n" +
+	"5\t1\t1\t1\t2\t1\t10\t40\t50\t20\t90\tTotal:\n" +
+	"5\t1\t1\t1\t2\t2\t70\t40\t50\t20\t-1\t \n" +
+	"5\t1\t2\t1\t1\t1\t10\t90\t50\t20\t82\tThanks\n" +
+	"1\t2\t0\t0\t0\t0\t0\t0\t800\t600\t-1\t\n" +
+	"5\t2\t1\t1\t1\t1\t10\t10\t50\t20\t80\tPage\n" +
+	"5\t2\t1\t1\t1\t2\t70\t10\t50\t20\t70\ttwo\n"
+
+func TestRecognize(t *testing.T) {
+	engine := NewEngine(zap.NewNop())
+
+	var gotArgs []string
+	engine.run = func(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+		gotArgs = args
+		_, _ = io.WriteString(stdout, tsv)
+		return nil
+	}
+
+	result, err := engine.Recognize(context.Background(), ports.OCRRequest{
+		Image:     pngHeader,
+		Languages: []string{"en", "ES", "frk"},
+	})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v", err)
+	}
+
+	wantArgs := []string{"stdin", "stdout", "-l", "eng+spa+frk", "tsv"}
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("args = %v, want %v", gotArgs, wantArgs)
+	}
+
+	if len(result.Pages) != 2 {
+		t.Fatalf("Pages = %+v", result.Pages)
+	}
+	if result.Pages[0].Text != "Invoice #42\nTotal:\n\nThanks" {
+		t.Errorf("page 1 text = %q", result.Pages[0].Text)
+	}
+	if got := result.Pages[0].Confidence; got < 0.899 || got > 0.901 {
+		t.Errorf("page 1 confidence = %v, want 0.9", got)
+	}
+	if result.Pages[1].Number != 2 || result.Pages[1].Text != "Page two" {
+		t.Errorf("page 2 = %+v", result.Pages[1])
+	}
+	if result.Text != "Invoice #42\nTotal:\n\nThanks\n\nPage two" {
+		t.Errorf("Text = %q", result.Text)
+	}
+}
+
+func TestRecognizeErrors(t *testing.T) {
+	engine := NewEngine(zap.NewNop())
+	engine.run = func(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+		_, _ = io.WriteString(stderr, "Failed loading language 'xyz'")
+		return errors.New("exit status 1")
+	}
+
+	_, err := engine.Recognize(context.Background(), ports.OCRRequest{Image: []byte("%PDF-1.4"), MIMEType: "application/pdf"})
+	if !errors.Is(err, ports.ErrUnsupportedImage) {
+		t.Errorf("Recognize(pdf) error = %v, want ErrUnsupportedImage", err)
+	}
+
+	_, err = engine.Recognize(context.Background(), ports.OCRRequest{Image: pngHeader})
+	if err == nil || !strings.Contains(err.Error(), "Failed loading language") {
+		t.Errorf("Recognize() error = %v, want tesseract stderr", err)
+	}
+}
+
+// TestRecognizeIntegration runs the real binary when TESSERACT_IMAGE points
+// to an image with text
+func TestRecognizeIntegration(t *testing.T) {
+	path := os.Getenv("TESSERACT_IMAGE")
+	if path == "" {
+		t.Skip("TESSERACT_IMAGE not set")
+	}
+	if _, err := exec.LookPath("tesseract"); err != nil {
+		t.Skip("tesseract not installed")
+	}
+
+	image, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewEngine(zap.NewNop()).Recognize(context.Background(), ports.OCRRequest{Image: image})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v", err)
+	}
+	if result.Text == "" {
+		t.Error("no text recognized")
+	}
+}
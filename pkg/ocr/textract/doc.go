@@ -0,0 +1,12 @@
+// Package textract implements ports.OCREngine (pkg/ports in this
+// repository) with Amazon Textract's DetectDocumentText API.
+//
+// Lines of text are grouped by page in reading order. Synchronous detection
+// reads PNG and JPEG images and single-page PDF and TIFF files.
+//
+// Usage:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	engine := textract.NewEngine(awstextract.NewFromConfig(cfg), logger)
+//	result, err := engine.Recognize(ctx, ports.OCRRequest{Image: jpeg})
+package textract
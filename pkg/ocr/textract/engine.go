@@ -0,0 +1,116 @@
+package textract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ocr"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstextract "github.com/aws/aws-sdk-go-v2/service/textract"
+	"github.com/aws/aws-sdk-go-v2/service/textract/types"
+	"go.uber.org/zap"
+)
+
+// Formats read by DetectDocumentText
+var supportedTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/tiff":      true,
+	"application/pdf": true,
+}
+
+// Client is the subset of the Textract API used by Engine.
+// *textract.Client implements it.
+type Client interface {
+	DetectDocumentText(ctx context.Context, params *awstextract.DetectDocumentTextInput, optFns ...func(*awstextract.Options)) (*awstextract.DetectDocumentTextOutput, error)
+}
+
+// Engine implements the ports.OCREngine interface with Amazon Textract's
+// synchronous text detection
+type Engine struct {
+	client Client
+	logger *zap.Logger
+}
+
+// NewEngine creates a new Textract OCR engine
+func NewEngine(client Client, logger *zap.Logger) *Engine {
+	return &Engine{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Recognize detects the lines of text in a document (ports.OCREngine
+// interface). Synchronous detection reads single-page PDF and TIFF files;
+// start an asynchronous job from S3 for longer documents. Textract detects
+// the language itself, so language hints are ignored.
+func (e *Engine) Recognize(ctx context.Context, req ports.OCRRequest) (*ports.OCRResult, error) {
+	mimeType := req.MIMEType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(req.Image)
+	}
+	if !supportedTypes[mimeType] {
+		return nil, fmt.Errorf("%w: %s", ports.ErrUnsupportedImage, mimeType)
+	}
+
+	out, err := e.client.DetectDocumentText(ctx, &awstextract.DetectDocumentTextInput{
+		Document: &types.Document{Bytes: req.Image},
+	})
+	if err != nil {
+		var unsupported *types.UnsupportedDocumentException
+		if errors.As(err, &unsupported) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrUnsupportedImage, unsupported.ErrorMessage())
+		}
+		e.logger.Error("Textract request failed", zap.Error(err))
+		return nil, fmt.Errorf("Textract request failed: %w", err)
+	}
+
+	return ocr.NewResult(linePages(out.Blocks)), nil
+}
+
+// linePages groups LINE blocks by page, in reading order
+func linePages(blocks []types.Block) []ports.OCRPage {
+	type page struct {
+		lines      []string
+		confidence float64
+	}
+	var order []int
+	pages := map[int]*page{}
+
+	for _, block := range blocks {
+		number := 1
+		if block.Page != nil {
+			number = int(*block.Page)
+		}
+		if _, ok := pages[number]; !ok && (block.BlockType == types.BlockTypePage || block.BlockType == types.BlockTypeLine) {
+			pages[number] = &page{}
+			order = append(order, number)
+		}
+		if block.BlockType != types.BlockTypeLine {
+			continue
+		}
+
+		text := strings.TrimSpace(aws.ToString(block.Text))
+		if text == "" {
+			continue
+		}
+		p := pages[number]
+		p.lines = append(p.lines, text)
+		p.confidence += float64(aws.ToFloat32(block.Confidence))
+	}
+
+	result := make([]ports.OCRPage, 0, len(order))
+	for _, number := range order {
+		p := pages[number]
+		page := ports.OCRPage{Number: number, Text: strings.Join(p.lines, "\n")}
+		if len(p.lines) > 0 {
+			page.Confidence = p.confidence / float64(len(p.lines)) / 100
+		}
+		result = append(result, page)
+	}
+	return result
+}
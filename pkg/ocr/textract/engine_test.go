@@ -0,0 +1,75 @@
+package textract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstextract "github.com/aws/aws-sdk-go-v2/service/textract"
+	"github.com/aws/aws-sdk-go-v2/service/textract/types"
+	"go.uber.org/zap"
+)
+
+var (
+	_ ports.OCREngine = (*Engine)(nil)
+	_ Client          = (*awstextract.Client)(nil)
+)
+
+type fakeClient struct {
+	input  *awstextract.DetectDocumentTextInput
+	output *awstextract.DetectDocumentTextOutput
+	err    error
+}
+
+func (f *fakeClient) DetectDocumentText(ctx context.Context, params *awstextract.DetectDocumentTextInput, optFns ...func(*awstextract.Options)) (*awstextract.DetectDocumentTextOutput, error) {
+	f.input = params
+	return f.output, f.err
+}
+
+func line(text string, confidence float32) types.Block {
+	return types.Block{BlockType: types.BlockTypeLine, Text: aws.String(text), Confidence: aws.Float32(confidence), Page: aws.Int32(1)}
+}
+
+func TestRecognize(t *testing.T) {
+	client := &fakeClient{output: &awstextract.DetectDocumentTextOutput{
+		Blocks: []types.Block{
+			{BlockType: types.BlockTypePage, Page: aws.Int32(1)},
+			line("Delivery note", 99),
+			{BlockType: types.BlockTypeWord, Text: aws.String("Delivery"), Confidence: aws.Float32(99), Page: aws.Int32(1)},
+			line("Ref 1234", 95),
+		},
+	}}
+	engine := NewEngine(client, zap.NewNop())
+
+	image := []byte("\xff\xd8\xff\xe0jpeg")
+	result, err := engine.Recognize(context.Background(), ports.OCRRequest{Image: image})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v", err)
+	}
+	if string(client.input.Document.Bytes) != string(image) {
+		t.Error("image not sent")
+	}
+	if result.Text != "Delivery note\nRef 1234" || len(result.Pages) != 1 || result.Pages[0].Number != 1 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Confidence < 0.969 || result.Confidence > 0.971 {
+		t.Errorf("Confidence = %v, want 0.97", result.Confidence)
+	}
+}
+
+func TestRecognizeErrors(t *testing.T) {
+	client := &fakeClient{err: &types.UnsupportedDocumentException{Message: aws.String("Request has unsupported document format")}}
+	engine := NewEngine(client, zap.NewNop())
+
+	_, err := engine.Recognize(context.Background(), ports.OCRRequest{Image: []byte("%PDF-1.4")})
+	if !errors.Is(err, ports.ErrUnsupportedImage) {
+		t.Errorf("Recognize() error = %v, want ErrUnsupportedImage", err)
+	}
+
+	_, err = engine.Recognize(context.Background(), ports.OCRRequest{Image: []byte("GIF89a"), MIMEType: "image/gif"})
+	if !errors.Is(err, ports.ErrUnsupportedImage) {
+		t.Errorf("Recognize(gif) error = %v, want ErrUnsupportedImage", err)
+	}
+}
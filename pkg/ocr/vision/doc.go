@@ -0,0 +1,19 @@
+// Package vision implements ports.OCREngine (pkg/ports in this repository)
+// with the Google Cloud Vision API over HTTP.
+//
+// Images are sent to images:annotate. PDF, TIFF and GIF files are sent to
+// files:annotate five pages at a time, the API limit, until all pages are
+// read. DOCUMENT_TEXT_DETECTION is used by default; SetFeature switches to
+// TEXT_DETECTION for photos with sparse text.
+//
+// Usage:
+//
+//	engine := vision.NewEngine(os.Getenv("GOOGLE_VISION_API_KEY"), logger)
+//	result, err := engine.Recognize(ctx, ports.OCRRequest{Image: pdf, MIMEType: "application/pdf"})
+//
+// Without an API key, authenticate with application default credentials:
+//
+//	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-vision")
+//	engine := vision.NewEngine("", logger)
+//	engine.SetHTTPClient(client)
+package vision
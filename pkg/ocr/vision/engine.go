@@ -0,0 +1,265 @@
+package vision
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ocr"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Cloud Vision API endpoint
+	DefaultBaseURL = "https://vision.googleapis.com"
+
+	// FeatureDocumentText is tuned for dense text such as scanned documents
+	FeatureDocumentText = "DOCUMENT_TEXT_DETECTION"
+
+	// FeatureText is tuned for sparse text in photos, such as signs
+	FeatureText = "TEXT_DETECTION"
+
+	// Pages per files:annotate request allowed by the API
+	filePagesPerRequest = 5
+)
+
+// Formats annotated as files, page by page, rather than as images
+var fileTypes = map[string]bool{
+	"application/pdf": true,
+	"image/tiff":      true,
+	"image/gif":       true,
+}
+
+// Formats annotated as images
+var imageTypes = map[string]bool{
+	"image/png": true, "image/jpeg": true, "image/bmp": true,
+	"image/webp": true, "image/x-icon": true, "image/vnd.microsoft.icon": true,
+}
+
+// Engine implements the ports.OCREngine interface with the Google Cloud
+// Vision API
+type Engine struct {
+	apiKey     string
+	baseURL    string
+	feature    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewEngine creates a new Cloud Vision OCR engine authenticating with an
+// API key. With an empty key, set an HTTP client that adds OAuth
+// credentials with SetHTTPClient, e.g. from google.DefaultClient.
+func NewEngine(apiKey string, logger *zap.Logger) *Engine {
+	return &Engine{
+		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
+		feature:    FeatureDocumentText,
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}
+}
+
+// SetBaseURL sets the API endpoint, e.g. a regional one such as
+// https://eu-vision.googleapis.com
+func (e *Engine) SetBaseURL(baseURL string) {
+	e.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetFeature sets the detection feature, FeatureDocumentText by default
+func (e *Engine) SetFeature(feature string) {
+	e.feature = feature
+}
+
+// SetHTTPClient sets the HTTP client, e.g. one with OAuth credentials
+func (e *Engine) SetHTTPClient(client *http.Client) {
+	e.httpClient = client
+}
+
+type feature struct {
+	Type string `json:"type"`
+}
+
+type imageContext struct {
+	LanguageHints []string `json:"languageHints,omitempty"`
+}
+
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type annotateImageResponse struct {
+	FullTextAnnotation *struct {
+		Text  string `json:"text"`
+		Pages []struct {
+			Confidence float64 `json:"confidence"`
+		} `json:"pages"`
+	} `json:"fullTextAnnotation"`
+	Context *struct {
+		PageNumber int `json:"pageNumber"`
+	} `json:"context"`
+	Error *apiError `json:"error"`
+}
+
+// Recognize annotates the image (ports.OCREngine interface). PDF, TIFF and
+// GIF files are read page by page.
+func (e *Engine) Recognize(ctx context.Context, req ports.OCRRequest) (*ports.OCRResult, error) {
+	mimeType := req.MIMEType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(req.Image)
+	}
+
+	switch {
+	case fileTypes[mimeType]:
+		return e.recognizeFile(ctx, req, mimeType)
+	case imageTypes[mimeType]:
+		return e.recognizeImage(ctx, req)
+	default:
+		return nil, fmt.Errorf("%w: %s", ports.ErrUnsupportedImage, mimeType)
+	}
+}
+
+// recognizeImage calls images:annotate
+func (e *Engine) recognizeImage(ctx context.Context, req ports.OCRRequest) (*ports.OCRResult, error) {
+	body := map[string]interface{}{
+		"requests": []interface{}{map[string]interface{}{
+			"image":        map[string]interface{}{"content": req.Image},
+			"features":     []feature{{Type: e.feature}},
+			"imageContext": imageContext{LanguageHints: req.Languages},
+		}},
+	}
+
+	var resp struct {
+		Responses []annotateImageResponse `json:"responses"`
+	}
+	if err := e.post(ctx, "/v1/images:annotate", body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Responses) != 1 {
+		return nil, fmt.Errorf("Vision API returned %d responses, want 1", len(resp.Responses))
+	}
+
+	page, err := toPage(resp.Responses[0], 1)
+	if err != nil {
+		return nil, err
+	}
+	return ocr.NewResult([]ports.OCRPage{page}), nil
+}
+
+// recognizeFile calls files:annotate for each batch of pages
+func (e *Engine) recognizeFile(ctx context.Context, req ports.OCRRequest, mimeType string) (*ports.OCRResult, error) {
+	var pages []ports.OCRPage
+	for first, total := 1, 1; first <= total; first += filePagesPerRequest {
+		request := map[string]interface{}{
+			"inputConfig":  map[string]interface{}{"content": req.Image, "mimeType": mimeType},
+			"features":     []feature{{Type: e.feature}},
+			"imageContext": imageContext{LanguageHints: req.Languages},
+		}
+		// Without pages the API reads the first batch and tells the total
+		if first > 1 {
+			var numbers []int
+			for n := first; n < first+filePagesPerRequest && n <= total; n++ {
+				numbers = append(numbers, n)
+			}
+			request["pages"] = numbers
+		}
+		body := map[string]interface{}{"requests": []interface{}{request}}
+
+		var resp struct {
+			Responses []struct {
+				Responses  []annotateImageResponse `json:"responses"`
+				TotalPages int                     `json:"totalPages"`
+				Error      *apiError               `json:"error"`
+			} `json:"responses"`
+		}
+		if err := e.post(ctx, "/v1/files:annotate", body, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Responses) != 1 {
+			return nil, fmt.Errorf("Vision API returned %d responses, want 1", len(resp.Responses))
+		}
+		file := resp.Responses[0]
+		if file.Error != nil {
+			return nil, fmt.Errorf("Vision API error %d: %s", file.Error.Code, file.Error.Message)
+		}
+		total = file.TotalPages
+
+		for i, image := range file.Responses {
+			number := first + i
+			if image.Context != nil && image.Context.PageNumber > 0 {
+				number = image.Context.PageNumber
+			}
+			page, err := toPage(image, number)
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, page)
+		}
+	}
+	return ocr.NewResult(pages), nil
+}
+
+// toPage converts an image annotation to a page
+func toPage(resp annotateImageResponse, number int) (ports.OCRPage, error) {
+	if resp.Error != nil {
+		return ports.OCRPage{}, fmt.Errorf("Vision API error on page %d: %d %s", number, resp.Error.Code, resp.Error.Message)
+	}
+	page := ports.OCRPage{Number: number}
+	if resp.FullTextAnnotation != nil {
+		page.Text = resp.FullTextAnnotation.Text
+		for _, p := range resp.FullTextAnnotation.Pages {
+			page.Confidence += p.Confidence / float64(len(resp.FullTextAnnotation.Pages))
+		}
+	}
+	return page, nil
+}
+
+// post sends a JSON request to the API and decodes the response
+func (e *Engine) post(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := e.baseURL + path
+	if e.apiKey != "" {
+		endpoint += "?key=" + url.QueryEscape(e.apiKey)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("Vision API request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read Vision API response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error apiError `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			e.logger.Error("Vision API request failed", zap.Int("status", resp.StatusCode), zap.String("error", apiErr.Error.Message))
+			return fmt.Errorf("Vision API error %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("Vision API error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode Vision API response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package vision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.OCREngine = (*Engine)(nil)
+
+func TestRecognizeImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/images:annotate" || r.URL.Query().Get("key") != "test-key" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+
+		var body struct {
+			Requests []struct {
+				Image struct {
+					Content []byte `json:"content"`
+				} `json:"image"`
+				Features     []feature    `json:"features"`
+				ImageContext imageContext `json:"imageContext"`
+			} `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		req := body.Requests[0]
+		if string(req.Image.Content) != "\x89PNG\r\n\x1a\nimage" || req.Features[0].Type != FeatureDocumentText || req.ImageContext.LanguageHints[0] != "es" {
+			t.Errorf("request = %+v", req)
+		}
+
+		_, _ = w.Write([]byte(`{"responses": [{"fullTextAnnotation": {"text": "Hola\nmundo\n", "pages": [{"confidence": 0.95}]}}]}`))
+	}))
+	defer server.Close()
+
+	engine := NewEngine("test-key", zap.NewNop())
+	engine.SetBaseURL(server.URL)
+
+	result, err := engine.Recognize(context.Background(), ports.OCRRequest{
+		Image:     []byte("\x89PNG\r\n\x1a\nimage"),
+		Languages: []string{"es"},
+	})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v", err)
+	}
+	if result.Text != "Hola\nmundo" || result.Confidence != 0.95 || len(result.Pages) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestRecognizeFile(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Requests []struct {
+				InputConfig struct {
+					MIMEType string `json:"mimeType"`
+				} `json:"inputConfig"`
+				Pages []int `json:"pages"`
+			} `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		req := body.Requests[0]
+		if r.URL.Path != "/v1/files:annotate" || req.InputConfig.MIMEType != "application/pdf" {
+			t.Errorf("unexpected request %s %+v", r.URL.Path, req)
+		}
+
+		var pages []map[string]interface{}
+		numbers := req.Pages
+		if numbers == nil {
+			numbers = []int{1, 2, 3, 4, 5}
+		}
+		for _, n := range numbers {
+			pages = append(pages, map[string]interface{}{
+				"fullTextAnnotation": map[string]interface{}{"text": "page " + string(rune('0'+n)), "pages": []interface{}{map[string]interface{}{"confidence": 0.9}}},
+				"context":            map[string]interface{}{"pageNumber": n},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"responses": []interface{}{map[string]interface{}{"responses": pages, "totalPages": 7}},
+		})
+	}))
+	defer server.Close()
+
+	engine := NewEngine("test-key", zap.NewNop())
+	engine.SetBaseURL(server.URL)
+
+	result, err := engine.Recognize(context.Background(), ports.OCRRequest{Image: []byte("%PDF-1.4")})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v", err)
+	}
+	if calls != 2 || len(result.Pages) != 7 {
+		t.Fatalf("calls = %d, pages = %+v", calls, result.Pages)
+	}
+	if result.Pages[6].Number != 7 || result.Pages[6].Text != "page 7" {
+		t.Errorf("last page = %+v", result.Pages[6])
+	}
+}
+
+func TestRecognizeErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error": {"code": 403, "message": "API key not valid"}}`))
+	}))
+	defer server.Close()
+
+	engine := NewEngine("bad-key", zap.NewNop())
+	engine.SetBaseURL(server.URL)
+
+	_, err := engine.Recognize(context.Background(), ports.OCRRequest{Image: []byte("\xff\xd8\xff\xe0jpeg")})
+	if err == nil || !strings.Contains(err.Error(), "API key not valid") {
+		t.Errorf("Recognize() error = %v, want API error", err)
+	}
+
+	_, err = engine.Recognize(context.Background(), ports.OCRRequest{Image: []byte("text"), MIMEType: "text/plain"})
+	if !errors.Is(err, ports.ErrUnsupportedImage) {
+		t.Errorf("Recognize(text) error = %v, want ErrUnsupportedImage", err)
+	}
+}
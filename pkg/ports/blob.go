@@ -0,0 +1,77 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrBlobNotFound is returned when a blob doesn't exist.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// ErrPresignNotSupported is returned by stores that can't issue presigned URLs.
+var ErrPresignNotSupported = errors.New("presigned URLs not supported")
+
+// BlobInfo describes a stored blob.
+type BlobInfo struct {
+	// Key identifies the blob within the store, e.g. "executions/exec-1/report.pdf".
+	Key string `json:"key"`
+
+	// Size is the blob size in bytes.
+	Size int64 `json:"size"`
+
+	// ContentType is the blob's MIME type, if known.
+	ContentType string `json:"content_type,omitempty"`
+
+	// ETag identifies the blob's content as reported by the store.
+	ETag string `json:"etag,omitempty"`
+
+	// Metadata holds user-defined key-value pairs stored with the blob.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// ModifiedAt is when the blob was last written.
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// PutOptions configures how a blob is stored.
+type PutOptions struct {
+	// ContentType is the blob's MIME type.
+	ContentType string
+
+	// Metadata holds user-defined key-value pairs stored with the blob.
+	Metadata map[string]string
+}
+
+// BlobStore defines the interface for storing large payloads such as
+// prompts, documents and generated files, so workers can pass them between
+// DAG steps by key instead of by value.
+type BlobStore interface {
+	// Put stores the content read from r under key, replacing any blob
+	// already stored there.
+	Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (*BlobInfo, error)
+
+	// Get opens the blob for streaming. The caller must close the reader.
+	// It returns ErrBlobNotFound if the blob doesn't exist.
+	Get(ctx context.Context, key string) (io.ReadCloser, *BlobInfo, error)
+
+	// Stat returns the blob's description without its content.
+	// It returns ErrBlobNotFound if the blob doesn't exist.
+	Stat(ctx context.Context, key string) (*BlobInfo, error)
+
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the blobs whose keys start with prefix, ordered by key.
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+
+	// PresignGet returns a URL that downloads the blob without credentials
+	// until expiry elapses. It returns ErrPresignNotSupported if the store
+	// can't issue one.
+	PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error)
+
+	// PresignPut returns a URL that uploads the blob with an HTTP PUT without
+	// credentials until expiry elapses. It returns ErrPresignNotSupported if
+	// the store can't issue one.
+	PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
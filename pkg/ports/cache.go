@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCacheMiss is returned by Cache.Get when the key is missing or expired.
+var ErrCacheMiss = errors.New("cache miss")
+
+// CacheLoader produces the value of a missing cache key.
+type CacheLoader func(ctx context.Context) ([]byte, error)
+
+// Cache defines the interface for a key/value cache with expiry, shared by
+// the adapters that cache results (LLM responses, embeddings, worker
+// listings) so each of them can run in-process or on a shared cache.
+type Cache interface {
+	// Get returns the value of key, or ErrCacheMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value under key. A positive ttl expires it after that
+	// long; zero keeps it until it is evicted or deleted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// GetOrLoad returns the value of key, calling load and storing its
+	// result for ttl on a miss. Concurrent misses of the same key in a
+	// process share one load. Errors from load are returned, not cached.
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, load CacheLoader) ([]byte, error)
+}
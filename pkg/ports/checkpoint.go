@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned when a step has no checkpoint.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// ErrCheckpointMismatch is returned when a step is checkpointed with an
+// input other than the one it was first checkpointed with, so its stored
+// output can't be replayed.
+var ErrCheckpointMismatch = errors.New("checkpoint input mismatch")
+
+// Checkpoint is the output of one completed step of a workflow run.
+type Checkpoint struct {
+	// RunID identifies the run.
+	RunID string `json:"run_id"`
+
+	// StepID identifies the step within the run, e.g. the node ID.
+	StepID string `json:"step_id"`
+
+	// InputHash fingerprints the step's input. A stored output is only
+	// replayed for the same input.
+	InputHash string `json:"input_hash"`
+
+	// Output is the step's result, opaque to the checkpointer.
+	Output []byte `json:"output"`
+
+	// CreatedAt is when the checkpoint was first saved.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Checkpointer defines the interface for persisting the outputs of
+// workflow steps, so that an executor resuming a crashed run replays
+// completed steps instead of running them, and their model calls, again.
+type Checkpointer interface {
+	// Save stores the checkpoint and returns the stored one. Checkpoints
+	// are written once: saving a step again with the same input hash
+	// returns the first checkpoint unchanged, and with another input hash
+	// fails with ErrCheckpointMismatch.
+	Save(ctx context.Context, checkpoint Checkpoint) (*Checkpoint, error)
+
+	// Load returns a step's checkpoint, or ErrCheckpointNotFound.
+	Load(ctx context.Context, runID, stepID string) (*Checkpoint, error)
+
+	// List returns the run's checkpoints, oldest first.
+	List(ctx context.Context, runID string) ([]Checkpoint, error)
+
+	// Delete removes the run's checkpoints. Deleting a run without
+	// checkpoints is not an error.
+	Delete(ctx context.Context, runID string) error
+}
@@ -0,0 +1,91 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnsupportedLanguage is returned when a code runner has no runtime for
+// the requested language.
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// CodeFile is a file made available to the code, next to it in its working
+// directory.
+type CodeFile struct {
+	// Path is relative to the working directory, e.g. "data/input.csv".
+	Path string `json:"path"`
+
+	// Content is the file content.
+	Content []byte `json:"content"`
+}
+
+// CodeLimits bounds the resources of one run. Zero fields use the runner's
+// defaults.
+type CodeLimits struct {
+	// CPUs is the number of CPUs the code may use, e.g. 0.5.
+	CPUs float64 `json:"cpus,omitempty"`
+
+	// MemoryBytes is the memory limit; exceeding it kills the code.
+	MemoryBytes int64 `json:"memory_bytes,omitempty"`
+
+	// Timeout is the wall-clock limit of the run.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// MaxOutputBytes bounds each of stdout and stderr; the rest is dropped.
+	MaxOutputBytes int64 `json:"max_output_bytes,omitempty"`
+}
+
+// CodeRequest is a program to run in a sandbox.
+type CodeRequest struct {
+	// Language selects the runtime, e.g. "python".
+	Language string `json:"language"`
+
+	// Code is the program source.
+	Code string `json:"code"`
+
+	// Stdin is passed to the program's standard input.
+	Stdin string `json:"stdin,omitempty"`
+
+	// Files are placed in the program's working directory.
+	Files []CodeFile `json:"files,omitempty"`
+
+	// Env sets environment variables of the program.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Limits overrides the runner's default limits.
+	Limits CodeLimits `json:"limits,omitempty"`
+}
+
+// CodeResult is the outcome of a run. A program that fails, crashes or
+// times out still produces a result; see ExitCode and TimedOut.
+type CodeResult struct {
+	// Stdout is the program's standard output, up to the output limit.
+	Stdout string `json:"stdout"`
+
+	// Stderr is the program's standard error, up to the output limit.
+	Stderr string `json:"stderr"`
+
+	// ExitCode is the program's exit status.
+	ExitCode int `json:"exit_code"`
+
+	// TimedOut is set when the program was killed at the timeout.
+	TimedOut bool `json:"timed_out,omitempty"`
+
+	// Truncated is set when output beyond the limit was dropped.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Duration is how long the run took.
+	Duration time.Duration `json:"duration"`
+}
+
+// CodeRunner defines the interface for running untrusted code, such as code
+// interpreter tool calls, isolated from the worker running it.
+type CodeRunner interface {
+	// Run executes the program and returns its result. Errors are reserved
+	// for runs that couldn't start, e.g. ErrUnsupportedLanguage.
+	Run(ctx context.Context, req CodeRequest) (*CodeResult, error)
+
+	// Languages returns the languages Run accepts.
+	Languages() []string
+}
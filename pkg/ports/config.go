@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrConfigNotFound is returned when a config key doesn't exist.
+var ErrConfigNotFound = errors.New("config key not found")
+
+// ConfigValue is a config key and its raw value.
+type ConfigValue struct {
+	// Key is relative to the store's prefix, e.g. "llm/default".
+	Key string `json:"key"`
+
+	// Value is the raw value, typically JSON.
+	Value []byte `json:"value"`
+
+	// Revision increases every time the key is written, e.g. the etcd mod
+	// revision or the Consul modify index.
+	Revision int64 `json:"revision"`
+}
+
+// ConfigEvent is a change to a watched config key.
+type ConfigEvent struct {
+	ConfigValue
+
+	// Deleted is true when the key was removed; Value is then empty.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// ConfigStore defines the interface for reading configuration shared by
+// all instances, such as LLM settings or routing rules, and watching it
+// for changes so they are applied without restarting.
+type ConfigStore interface {
+	// Get returns a key's value, or ErrConfigNotFound.
+	Get(ctx context.Context, key string) (*ConfigValue, error)
+
+	// Put writes a key's value.
+	Put(ctx context.Context, key string, value []byte) error
+
+	// Delete removes a key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the keys starting with prefix, ordered by key.
+	List(ctx context.Context, prefix string) ([]ConfigValue, error)
+
+	// Watch streams changes to the keys starting with prefix, made after
+	// the call, until ctx is cancelled. The returned channel is closed then,
+	// or earlier if the watch fails for good; callers watch again.
+	Watch(ctx context.Context, prefix string) (<-chan ConfigEvent, error)
+}
@@ -0,0 +1,7 @@
+// Package ports defines port interfaces that adapters in this repository
+// implement but that dago-libs does not define yet.
+//
+// They follow the conventions of dago-libs/pkg/ports and are meant to move
+// there; until then, import this package alongside it, aliasing one of the
+// two (e.g. libports) in files that need both.
+package ports
@@ -0,0 +1,69 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrUnsupportedFormat is returned when no loader handles a document's format.
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// Common Document.Metadata keys, set by loaders when the source has them.
+const (
+	DocumentMetaAuthor      = "author"
+	DocumentMetaDescription = "description"
+	DocumentMetaLanguage    = "language"
+	DocumentMetaCreated     = "created"
+	DocumentMetaModified    = "modified"
+	DocumentMetaPages       = "pages"
+)
+
+// DocumentSection marks where a heading or page starts in Document.Content,
+// so that chunkers can split on structure and answers can cite it.
+type DocumentSection struct {
+	// Heading is the section title; empty for PDF pages.
+	Heading string `json:"heading,omitempty"`
+
+	// Level is the heading level, 1 for top-level headings.
+	Level int `json:"level,omitempty"`
+
+	// Page is the 1-based page number, for paginated formats.
+	Page int `json:"page,omitempty"`
+
+	// Offset is the byte offset of the section in Content.
+	Offset int `json:"offset"`
+}
+
+// Document is the normalized text of a loaded file.
+type Document struct {
+	// Source identifies where the document was loaded from, e.g. a path or URL.
+	Source string `json:"source"`
+
+	// Title is the document title from its metadata or first heading.
+	Title string `json:"title,omitempty"`
+
+	// MIMEType is the format the document was loaded from.
+	MIMEType string `json:"mime_type"`
+
+	// Content is the text: paragraphs separated by blank lines, single
+	// spaces, LF line endings and no boilerplate such as scripts or menus.
+	Content string `json:"content"`
+
+	// Sections are the document's headings or pages in order.
+	Sections []DocumentSection `json:"sections,omitempty"`
+
+	// Metadata holds source metadata, see the DocumentMeta keys.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// DocumentLoader defines the interface for extracting text from files for
+// ingestion (e.g. chunking and embedding for retrieval).
+type DocumentLoader interface {
+	// Load reads a document. source is recorded in the Document and may be
+	// used to detect the format.
+	Load(ctx context.Context, r io.Reader, source string) (*Document, error)
+
+	// MIMETypes returns the formats the loader handles.
+	MIMETypes() []string
+}
@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"context"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// EmbeddingInputType tells retrieval-tuned models what the texts are used
+// for. Queries and the documents they are matched against are embedded
+// differently.
+type EmbeddingInputType string
+
+const (
+	// EmbeddingInputNone leaves the input type unspecified.
+	EmbeddingInputNone EmbeddingInputType = ""
+
+	// EmbeddingInputDocument is for texts stored and searched over.
+	EmbeddingInputDocument EmbeddingInputType = "document"
+
+	// EmbeddingInputQuery is for search queries.
+	EmbeddingInputQuery EmbeddingInputType = "query"
+)
+
+// EmbeddingRequest is a batch of texts to embed.
+type EmbeddingRequest struct {
+	// Texts are embedded in order. Implementations split large batches into
+	// as many API calls as the provider requires.
+	Texts []string `json:"texts"`
+
+	// Model is the provider's model identifier; empty uses the adapter default.
+	Model string `json:"model,omitempty"`
+
+	// InputType is the purpose of the texts.
+	InputType EmbeddingInputType `json:"input_type,omitempty"`
+}
+
+// EmbeddingResponse holds one embedding per input text, in input order.
+type EmbeddingResponse struct {
+	// Embeddings are the vectors, one per input text.
+	Embeddings [][]float32 `json:"embeddings"`
+
+	// Model is the model that produced the embeddings.
+	Model string `json:"model"`
+
+	// Usage counts input tokens as PromptTokens and TotalTokens.
+	Usage libports.UsageInfo `json:"usage"`
+}
+
+// Embedder defines the interface for turning texts into vectors.
+// Implementations handle provider-specific details (Voyage, Cohere, etc).
+type Embedder interface {
+	// Embed returns the embeddings of the request's texts.
+	Embed(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error)
+}
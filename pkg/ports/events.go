@@ -0,0 +1,39 @@
+package ports
+
+import (
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Lifecycle event types complementing the graph and node events of
+// dago-libs/pkg/ports. They are published on libports.EventBus like any
+// other event.
+const (
+	// EventTypeTaskStarted is emitted when a worker picks up a task.
+	EventTypeTaskStarted libports.EventType = "task.started"
+
+	// EventTypeTaskFinished is emitted when a task completes successfully.
+	EventTypeTaskFinished libports.EventType = "task.finished"
+
+	// EventTypeTaskFailed is emitted when a task fails.
+	EventTypeTaskFailed libports.EventType = "task.failed"
+
+	// EventTypeWorkerJoined is emitted when a worker registers.
+	EventTypeWorkerJoined libports.EventType = "worker.joined"
+
+	// EventTypeWorkerLeft is emitted when a worker unregisters or expires.
+	EventTypeWorkerLeft libports.EventType = "worker.left"
+)
+
+// Topic patterns accepted by libports.EventBus Subscribe in this repository.
+//
+// Topics are dot-separated tokens, e.g. "workflow.summarize.task.failed".
+// In a pattern, a "*" token matches exactly one token and a trailing ">"
+// token matches one or more, so "workflow.*.task.>" matches every task event
+// of every workflow. Publish only accepts topics without wildcards.
+const (
+	// TopicWildcardToken matches exactly one topic token.
+	TopicWildcardToken = "*"
+
+	// TopicTailToken, as the last token, matches all remaining tokens.
+	TopicTailToken = ">"
+)
@@ -0,0 +1,29 @@
+package ports
+
+import "context"
+
+// FlagContext identifies what a feature flag is evaluated for.
+type FlagContext struct {
+	// TenantID selects tenant overrides and the tenant's bucket in
+	// percentage rollouts; empty evaluates the flag's default.
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// Attributes are passed to providers with targeting rules, such as
+	// OpenFeature providers.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// FeatureFlags defines the interface for evaluating feature flags, so that
+// risky behaviors can be rolled out gradually per tenant. Evaluation never
+// fails: a missing flag, an invalid value or an unreachable backend yields
+// the default value.
+type FeatureFlags interface {
+	// Bool evaluates a boolean flag.
+	Bool(ctx context.Context, flag string, defaultValue bool, fc FlagContext) bool
+
+	// String evaluates a string flag, e.g. a variant name.
+	String(ctx context.Context, flag string, defaultValue string, fc FlagContext) string
+
+	// Int evaluates an integer flag, e.g. a limit.
+	Int(ctx context.Context, flag string, defaultValue int64, fc FlagContext) int64
+}
@@ -0,0 +1,84 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrImageFiltered is returned when every generated image was blocked by the
+// provider's content filter.
+var ErrImageFiltered = errors.New("image blocked by content filter")
+
+// Image styles understood by every ImageGenerator. Providers with style
+// presets map them to a preset; others describe the style in the prompt.
+const (
+	ImageStylePhotographic = "photographic"
+	ImageStyleCinematic    = "cinematic"
+	ImageStyleDigitalArt   = "digital-art"
+	ImageStyleAnime        = "anime"
+	ImageStyleComicBook    = "comic-book"
+	ImageStyleFantasyArt   = "fantasy-art"
+	ImageStyleLineArt      = "line-art"
+	ImageStylePixelArt     = "pixel-art"
+	ImageStyle3DModel      = "3d-model"
+)
+
+// ImageRequest describes the images to generate.
+type ImageRequest struct {
+	// Prompt describes the image.
+	Prompt string `json:"prompt"`
+
+	// NegativePrompt describes what the image must not contain.
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+
+	// Model is the provider's model; empty uses the generator's default.
+	Model string `json:"model,omitempty"`
+
+	// AspectRatio is the width to height ratio, e.g. "16:9"; empty is
+	// square. Generators use their closest supported ratio.
+	AspectRatio string `json:"aspect_ratio,omitempty"`
+
+	// Style is one of the ImageStyle constants or a provider-specific
+	// style; empty leaves the style to the prompt.
+	Style string `json:"style,omitempty"`
+
+	// Count is the number of images; zero generates one.
+	Count int `json:"count,omitempty"`
+
+	// Seed makes generation reproducible where supported; zero is random.
+	Seed int64 `json:"seed,omitempty"`
+
+	// Format is the image format, "png", "jpeg" or "webp"; empty uses
+	// the generator's default.
+	Format string `json:"format,omitempty"`
+}
+
+// GeneratedImage is one generated image.
+type GeneratedImage struct {
+	// Data is the encoded image.
+	Data []byte `json:"data"`
+
+	// MIMEType is the format of Data, e.g. "image/png".
+	MIMEType string `json:"mime_type"`
+
+	// Seed is the seed the image was generated with, when reported.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// ImageResponse holds the generated images.
+type ImageResponse struct {
+	// Images are the generated images. Images blocked by content filters
+	// are left out, so there may be fewer than requested.
+	Images []GeneratedImage `json:"images"`
+
+	// Model is the model that generated the images.
+	Model string `json:"model"`
+}
+
+// ImageGenerator defines the interface for generating images from text
+// prompts.
+type ImageGenerator interface {
+	// Generate returns images for the request, or ErrImageFiltered if
+	// they were all blocked.
+	Generate(ctx context.Context, req ImageRequest) (*ImageResponse, error)
+}
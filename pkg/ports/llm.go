@@ -0,0 +1,212 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// ErrNotImplemented is returned by ports.LLMClient methods a provider adapter
+// doesn't support (yet).
+var ErrNotImplemented = errors.New("not implemented")
+
+// ErrInvalidRequest is returned by LLM clients for requests rejected before
+// reaching the provider, e.g. of the wrong type or without messages.
+var ErrInvalidRequest = errors.New("invalid LLM request")
+
+// RateLimitError is returned by LLM clients whose provider rejected a call
+// for exceeding its rate limits (HTTP 429), with what the provider said
+// about them, so that retries can wait for as long as asked instead of
+// guessing a backoff
+type RateLimitError struct {
+	// RetryAfter is how long to wait before retrying, zero if the provider
+	// didn't say
+	RetryAfter time.Duration
+
+	// Limits are the provider's rate limits when the call was rejected
+	Limits RateLimits
+
+	// Message is the provider's error message
+	Message string
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited (status 429), retry after %s: %s", e.RetryAfter, e.Message)
+	}
+	return "rate limited (status 429): " + e.Message
+}
+
+// StatusError is returned by clients whose provider answered a call with an
+// HTTP error status, when no more specific error applies
+type StatusError struct {
+	StatusCode int
+
+	// Message is the provider's error message
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// HTTPStatusCode returns the status of the response, like the errors of the
+// AWS SDK
+func (e *StatusError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// RetryDelay returns how long to wait before retrying a call that failed
+// with err: the RetryAfter of a *RateLimitError, or fallback when err isn't
+// one or the provider didn't say
+func RetryDelay(err error, fallback time.Duration) time.Duration {
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) && rateErr.RetryAfter > 0 {
+		return rateErr.RetryAfter
+	}
+	return fallback
+}
+
+// RateLimits are the request and token limits a provider reports in its
+// response headers. Fields the provider didn't report are zero.
+type RateLimits struct {
+	// LimitRequests and LimitTokens are the requests and tokens allowed in
+	// the provider's windows
+	LimitRequests int
+	LimitTokens   int
+
+	// RemainingRequests and RemainingTokens are what is left of them
+	RemainingRequests int
+	RemainingTokens   int
+
+	// ResetRequests and ResetTokens are the time until they are restored
+	ResetRequests time.Duration
+	ResetTokens   time.Duration
+}
+
+// StreamChunk is a chunk of a completion streamed by CompleteStream. Text
+// arrives in the Delta of each chunk and tool calls in ToolCallDelta, as
+// they are generated; the last one has Done set, with the text, tool calls,
+// finish reason and usage of the response, Cancelled and the text so far if
+// the stream was cancelled, or Err if it failed.
+type StreamChunk struct {
+	// Delta is the text generated since the previous chunk
+	Delta string
+
+	// ReasoningDelta is the reasoning generated since the previous chunk,
+	// by models streaming it apart from the answer, e.g. deepseek-reasoner.
+	// It isn't part of Delta or Content.
+	ReasoningDelta string
+
+	// ToolCallDelta is a fragment of a tool call being generated
+	ToolCallDelta *ToolCallDelta
+
+	// ToolCalls are the tool calls of the response, set on the last chunk
+	ToolCalls []libports.ToolCall
+
+	// FinishReason is why generation stopped, set on the last chunk, in
+	// the OpenAI vocabulary ("stop", "length", "tool_calls", ...)
+	FinishReason string
+
+	// Usage is the token usage of the response, set on the last chunk when
+	// the provider reports it
+	Usage *libports.UsageInfo
+
+	// Content is the text of the response, set on the last chunk: the text
+	// generated until the cancellation when Cancelled is set
+	Content string
+
+	// Reasoning is the reasoning of the response, set on the last chunk
+	// when the model streamed it in ReasoningDelta
+	Reasoning string
+
+	// Done marks the last chunk, after which the channel is closed
+	Done bool
+
+	// Cancelled is set on the last chunk of a stream whose context was
+	// cancelled, e.g. to checkpoint the partial generation in Content
+	Cancelled bool
+
+	// Err is set on the last chunk of a failed stream
+	Err error
+}
+
+// ToolCallDelta is a fragment of a streamed tool call. The first fragment of
+// a call has its ID and name; the arguments, a JSON object, arrive in parts
+// to be concatenated, so they can be checked before the call is complete.
+// Providers returning whole calls send them in a single fragment.
+type ToolCallDelta struct {
+	// Index is the position of the call in the ToolCalls of the last chunk
+	Index int
+
+	// ID and Name are set on the first fragment of a call
+	ID   string
+	Name string
+
+	// Arguments is the next part of the call's JSON arguments
+	Arguments string
+}
+
+// WholeToolCallDelta returns a call of a provider returning whole tool
+// calls as the single fragment of the call at index
+func WholeToolCallDelta(index int, call libports.ToolCall) *ToolCallDelta {
+	arguments := call.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	data, _ := json.Marshal(arguments)
+	return &ToolCallDelta{Index: index, ID: call.ID, Name: call.Name, Arguments: string(data)}
+}
+
+// LLMStreamer is implemented by LLM clients streaming completions.
+// libports.CompletionChunk only carries text, so streams are defined here
+// with the rest of a response.
+type LLMStreamer interface {
+	// CompleteStream streams a completion, with tools if any. Errors before
+	// the stream starts are returned; later ones end it with a chunk
+	// holding Err. Cancelling ctx midway closes the provider's stream and
+	// ends it with a Cancelled chunk. The channel is closed after the last
+	// chunk, which is always sent: callers must read it until it's closed.
+	CompleteStream(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (<-chan StreamChunk, error)
+}
+
+// CompletionChunks converts a stream of CompleteStream to the chunks of
+// libports' StreamComplete: text deltas, then a final chunk unless the
+// stream failed or was cancelled, in which case the channel is closed
+// without one.
+func CompletionChunks(ctx context.Context, chunks <-chan StreamChunk) <-chan libports.CompletionChunk {
+	out := make(chan libports.CompletionChunk)
+	go func() {
+		defer close(out)
+		defer func() {
+			// Let the adapter's goroutine exit
+			go func() {
+				for range chunks {
+				}
+			}()
+		}()
+
+		for chunk := range chunks {
+			if chunk.Err != nil || chunk.Cancelled {
+				return
+			}
+			if chunk.Delta == "" && !chunk.Done {
+				continue
+			}
+			select {
+			case out <- libports.CompletionChunk{Delta: chunk.Delta, IsFinal: chunk.Done}:
+			case <-ctx.Done():
+				return
+			}
+			if chunk.Done {
+				return
+			}
+		}
+	}()
+	return out
+}
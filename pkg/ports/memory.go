@@ -0,0 +1,95 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMemoryNotFound is returned when a scope has no long-term summary.
+var ErrMemoryNotFound = errors.New("memory not found")
+
+// MemoryEntry is one episodic memory: something an agent saw, did or was
+// told.
+type MemoryEntry struct {
+	// ID identifies the entry; it is assigned by Append.
+	ID string `json:"id"`
+
+	// Scope is whose memory this is, e.g. an agent, user or conversation
+	// ID. Memories are only recalled within their scope.
+	Scope string `json:"scope"`
+
+	// Content is the memory text, embedded for semantic recall.
+	Content string `json:"content"`
+
+	// Metadata holds application-defined labels, e.g. the run ID.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// CreatedAt is when the memory was appended; Append sets it if zero.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecalledMemory is a memory with its relevance to a recall query.
+type RecalledMemory struct {
+	MemoryEntry
+
+	// Score is the cosine similarity of the memory to the query, from -1
+	// to 1; higher is more relevant.
+	Score float64 `json:"score"`
+}
+
+// MemoryQuery selects memories relevant to a text.
+type MemoryQuery struct {
+	// Scope is the memory to search.
+	Scope string `json:"scope"`
+
+	// Text is what the memories should be relevant to, e.g. the user's
+	// latest message.
+	Text string `json:"text"`
+
+	// Limit is the maximum number of memories; zero returns 5.
+	Limit int `json:"limit,omitempty"`
+
+	// MinScore leaves out memories scoring lower.
+	MinScore float64 `json:"min_score,omitempty"`
+}
+
+// MemorySummary is the long-term memory of a scope: a summary of its
+// episodic memories, rewritten as new ones are appended.
+type MemorySummary struct {
+	// Scope is whose memory this is.
+	Scope string `json:"scope"`
+
+	// Content is the summary text.
+	Content string `json:"content"`
+
+	// Through is the creation time of the newest memory summarized.
+	Through time.Time `json:"through"`
+
+	// UpdatedAt is when the summary was saved.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Memory defines the interface for agent memory that persists across runs:
+// episodic memories appended as they happen, recalled by meaning, and
+// summarized into long-term memory.
+type Memory interface {
+	// Append stores a memory and returns it with its ID and creation time.
+	Append(ctx context.Context, entry MemoryEntry) (*MemoryEntry, error)
+
+	// Recall returns the memories most relevant to the query, most
+	// relevant first.
+	Recall(ctx context.Context, query MemoryQuery) ([]RecalledMemory, error)
+
+	// Recent returns up to limit of the scope's memories, newest first.
+	Recent(ctx context.Context, scope string, limit int) ([]MemoryEntry, error)
+
+	// Summary returns the scope's long-term summary, or ErrMemoryNotFound.
+	Summary(ctx context.Context, scope string) (*MemorySummary, error)
+
+	// SaveSummary replaces the scope's long-term summary.
+	SaveSummary(ctx context.Context, summary MemorySummary) error
+
+	// Forget deletes all of the scope's memories and its summary.
+	Forget(ctx context.Context, scope string) error
+}
@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotificationRateLimited is returned when a notification was dropped
+// because too many were sent recently.
+var ErrNotificationRateLimited = errors.New("notification rate limited")
+
+// NotificationLevel is the severity of a notification, which channels show
+// e.g. as a color.
+type NotificationLevel string
+
+const (
+	NotificationInfo    NotificationLevel = "info"
+	NotificationSuccess NotificationLevel = "success"
+	NotificationWarning NotificationLevel = "warning"
+	NotificationError   NotificationLevel = "error"
+)
+
+// NotificationField is a labelled value shown with a notification, such as
+// the execution ID.
+type NotificationField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Notification is a message for people, e.g. that a workflow failed.
+type Notification struct {
+	// Title is a one-line summary, used as the email subject.
+	Title string `json:"title"`
+
+	// Text is the message body in plain text.
+	Text string `json:"text,omitempty"`
+
+	// Level is the severity; empty is NotificationInfo.
+	Level NotificationLevel `json:"level,omitempty"`
+
+	// Fields are shown in order after the text.
+	Fields []NotificationField `json:"fields,omitempty"`
+
+	// URL links to details, e.g. the run in a dashboard.
+	URL string `json:"url,omitempty"`
+}
+
+// Notifier defines the interface for sending notifications to a channel
+// such as Slack or email.
+type Notifier interface {
+	// Notify sends a notification.
+	Notify(ctx context.Context, notification Notification) error
+}
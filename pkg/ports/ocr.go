@@ -0,0 +1,56 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnsupportedImage is returned when an OCR engine can't read an image's
+// format.
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// OCRRequest is an image or scanned document to recognize text in.
+type OCRRequest struct {
+	// Image is the file content, e.g. PNG, JPEG, TIFF or a scanned PDF.
+	Image []byte `json:"image"`
+
+	// MIMEType is the format of Image; empty detects it from the content.
+	MIMEType string `json:"mime_type,omitempty"`
+
+	// Languages are hints of the text languages as ISO 639-1 codes, e.g.
+	// "en"; empty uses the engine's default or automatic detection.
+	Languages []string `json:"languages,omitempty"`
+}
+
+// OCRPage is the text recognized on one page.
+type OCRPage struct {
+	// Number is the 1-based page number.
+	Number int `json:"number"`
+
+	// Text is the page text, lines separated by newlines and blocks by
+	// blank lines.
+	Text string `json:"text"`
+
+	// Confidence is the mean recognition confidence, from 0 to 1.
+	Confidence float64 `json:"confidence"`
+}
+
+// OCRResult is the text recognized in an image or document.
+type OCRResult struct {
+	// Text is the text of all pages, separated by blank lines.
+	Text string `json:"text"`
+
+	// Pages holds the text of each page in order.
+	Pages []OCRPage `json:"pages"`
+
+	// Confidence is the mean recognition confidence, from 0 to 1.
+	Confidence float64 `json:"confidence"`
+}
+
+// OCREngine defines the interface for recognizing printed or handwritten
+// text in images, so that scanned documents can be chunked and embedded
+// like text documents.
+type OCREngine interface {
+	// Recognize returns the text of the image's pages.
+	Recognize(ctx context.Context, req OCRRequest) (*OCRResult, error)
+}
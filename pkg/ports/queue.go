@@ -0,0 +1,101 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Task is a unit of work on a queue.
+type Task struct {
+	// ID identifies the task. It is assigned by the queue on Publish.
+	ID string `json:"id"`
+
+	// Queue is the queue the task was read from.
+	Queue string `json:"queue"`
+
+	// Payload is the task body, opaque to the queue.
+	Payload []byte `json:"payload"`
+
+	// Headers carry metadata such as trace context or the task type.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Deliveries is how many times the task has been delivered, including
+	// this one, when the queue tracks it.
+	Deliveries int64 `json:"deliveries,omitempty"`
+
+	// EnqueuedAt is when the task was published.
+	EnqueuedAt time.Time `json:"enqueued_at"`
+
+	// Receipt is what Ack needs to acknowledge this delivery. It equals ID on
+	// queues that acknowledge by ID.
+	Receipt string `json:"receipt,omitempty"`
+}
+
+// ConsumeRequest selects tasks for a consumer.
+type ConsumeRequest struct {
+	// Queue is the queue to read from.
+	Queue string
+
+	// Group is the consumer group. Each task is delivered to one consumer of
+	// each group.
+	Group string
+
+	// Consumer identifies the reader within the group, typically the worker ID.
+	Consumer string
+
+	// Count is the maximum number of tasks returned.
+	Count int
+
+	// Block is how long to wait for tasks when none are available; zero
+	// returns immediately.
+	Block time.Duration
+}
+
+// ClaimRequest takes over tasks delivered to other consumers but never
+// acknowledged, e.g. because the consumer crashed.
+type ClaimRequest struct {
+	Queue    string
+	Group    string
+	Consumer string
+
+	// MinIdle is how long a task must have gone unacknowledged.
+	MinIdle time.Duration
+
+	// Count is the maximum number of tasks claimed.
+	Count int
+}
+
+// TaskQueue defines the interface for distributing work to workers with
+// at-least-once delivery: tasks stay pending until acknowledged and can be
+// claimed by another consumer when their consumer goes away.
+type TaskQueue interface {
+	// Publish appends a task to the queue and returns its ID.
+	Publish(ctx context.Context, queue string, task Task) (string, error)
+
+	// Consume returns new tasks for the consumer, waiting up to Block for
+	// them. It returns no tasks and no error when the wait times out.
+	Consume(ctx context.Context, req ConsumeRequest) ([]Task, error)
+
+	// Ack acknowledges delivered tasks by receipt, removing them from the
+	// group's pending tasks.
+	Ack(ctx context.Context, queue, group string, receipts ...string) error
+
+	// ClaimPending transfers idle pending tasks to the consumer and returns
+	// them, with Deliveries incremented.
+	ClaimPending(ctx context.Context, req ClaimRequest) ([]Task, error)
+}
+
+// DeadLetterQueue is implemented by task queues that move tasks exceeding a
+// maximum number of deliveries to a dead-letter queue, so a task that keeps
+// failing stops being retried and can be inspected and requeued once fixed.
+type DeadLetterQueue interface {
+	// ListDeadLetters returns up to count dead-lettered tasks of the queue,
+	// oldest first. Deliveries is how often each was delivered before being
+	// dead-lettered.
+	ListDeadLetters(ctx context.Context, queue string, count int) ([]Task, error)
+
+	// Requeue publishes dead-lettered tasks, as returned by ListDeadLetters,
+	// to their queue again as new tasks and removes them from the
+	// dead-letter queue.
+	Requeue(ctx context.Context, tasks ...Task) error
+}
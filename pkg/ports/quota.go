@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a tenant has used up a quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaUsage is what a tenant used of a model within a rolling window.
+type QuotaUsage struct {
+	// Tenant is the tenant the usage is counted for.
+	Tenant string `json:"tenant"`
+
+	// Model is the model the usage is counted for; empty for the usage of
+	// all the tenant's models.
+	Model string `json:"model,omitempty"`
+
+	// Requests is the number of requests made.
+	Requests int64 `json:"requests"`
+
+	// Tokens is the number of prompt and completion tokens used.
+	Tokens int64 `json:"tokens"`
+}
+
+// QuotaStore keeps rolling-window request and token counters per tenant
+// and model, shared by every process enforcing the same quotas.
+type QuotaStore interface {
+	// Add counts requests and tokens for tenant and model, now.
+	Add(ctx context.Context, tenant, model string, requests, tokens int64) error
+
+	// Usage returns the usage of tenant and model over the last window. An
+	// empty model sums all the tenant's models.
+	Usage(ctx context.Context, tenant, model string, window time.Duration) (*QuotaUsage, error)
+
+	// List returns the usage over the last window of every tenant and model
+	// that has some.
+	List(ctx context.Context, window time.Duration) ([]QuotaUsage, error)
+
+	// Reset clears the counters of tenant and model. An empty model clears
+	// all the tenant's counters.
+	Reset(ctx context.Context, tenant, model string) error
+}
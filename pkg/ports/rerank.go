@@ -0,0 +1,24 @@
+package ports
+
+import "context"
+
+// RerankResult is a document's relevance to a query.
+type RerankResult struct {
+	// Index is the position of the document in the input slice.
+	Index int `json:"index"`
+
+	// Document is the document text.
+	Document string `json:"document"`
+
+	// Score is the relevance score; higher is more relevant. Scales differ
+	// between providers, so compare scores from the same reranker only.
+	Score float64 `json:"score"`
+}
+
+// Reranker defines the interface for reordering retrieved documents by
+// relevance to a query, typically applied to vector search results.
+type Reranker interface {
+	// Rerank scores every document against the query and returns them most
+	// relevant first.
+	Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error)
+}
@@ -0,0 +1,93 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrScheduleNotFound is returned when a schedule doesn't exist.
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// MissedRunPolicy decides what happens to runs that were due while no
+// scheduler was running, e.g. during an outage or a deploy.
+type MissedRunPolicy string
+
+const (
+	// MissedRunSkip drops missed runs; the schedule resumes at its next
+	// time.
+	MissedRunSkip MissedRunPolicy = "skip"
+
+	// MissedRunOnce fires a single run, at the latest missed time, for any
+	// number of missed runs.
+	MissedRunOnce MissedRunPolicy = "once"
+
+	// MissedRunAll fires every missed run, oldest first, up to the
+	// scheduler's catch-up limit.
+	MissedRunAll MissedRunPolicy = "all"
+)
+
+// Schedule triggers a workflow on a cron schedule.
+type Schedule struct {
+	// ID identifies the schedule.
+	ID string `json:"id"`
+
+	// Cron is a standard five-field cron expression ("0 9 * * 1-5") or a
+	// descriptor such as "@hourly" or "@every 15m".
+	Cron string `json:"cron"`
+
+	// Timezone is the IANA zone Cron is evaluated in; empty is UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Payload is passed to the handler with every run, e.g. the workflow
+	// to start and its input.
+	Payload []byte `json:"payload,omitempty"`
+
+	// MissedRuns is the policy for missed runs; empty is MissedRunSkip.
+	MissedRuns MissedRunPolicy `json:"missed_runs,omitempty"`
+
+	// NextRun is when the schedule fires next; set by the scheduler.
+	NextRun time.Time `json:"next_run,omitempty"`
+
+	// LastRun is when the schedule last fired; set by the scheduler.
+	LastRun time.Time `json:"last_run,omitempty"`
+}
+
+// ScheduledRun is one firing of a schedule.
+type ScheduledRun struct {
+	// ScheduleID is the schedule that fired.
+	ScheduleID string `json:"schedule_id"`
+
+	// ScheduledAt is the time the run was due, which is earlier than now
+	// for caught-up missed runs.
+	ScheduledAt time.Time `json:"scheduled_at"`
+
+	// Payload is the schedule's payload.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// ScheduleHandler is called for each run. It should return quickly, e.g.
+// after publishing a task that starts the workflow.
+type ScheduleHandler func(ctx context.Context, run ScheduledRun) error
+
+// Scheduler defines the interface for triggering workflows on cron
+// schedules. Schedules are shared by every instance; only one fires each
+// run.
+type Scheduler interface {
+	// Put creates or replaces a schedule, computing its next run.
+	Put(ctx context.Context, schedule Schedule) error
+
+	// Get returns a schedule, or ErrScheduleNotFound.
+	Get(ctx context.Context, id string) (*Schedule, error)
+
+	// List returns all schedules ordered by ID.
+	List(ctx context.Context) ([]Schedule, error)
+
+	// Remove deletes a schedule. Removing a missing schedule is not an
+	// error.
+	Remove(ctx context.Context, id string) error
+
+	// Run fires due schedules with handler until ctx is cancelled, then
+	// returns nil.
+	Run(ctx context.Context, handler ScheduleHandler) error
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSecretNotFound is returned when a secret, or the field selected in it,
+// doesn't exist.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Secret is the current value of a secret.
+type Secret struct {
+	// Name is the name the secret was requested by.
+	Name string `json:"name"`
+
+	// Value is the secret value. Never log it.
+	Value string `json:"-"`
+
+	// Version identifies the value, changing when the secret is rotated.
+	// Empty if the provider doesn't version secrets.
+	Version string `json:"version,omitempty"`
+
+	// FetchedAt is when the value was read from the provider.
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// SecretProvider defines the interface for reading credentials, such as LLM
+// provider API keys, from a secret manager instead of the environment.
+//
+// Names are provider-specific paths. Secrets holding several values (a Vault
+// KV entry, a JSON secret) take a "#field" suffix selecting one, e.g.
+// "llm/anthropic#api_key".
+type SecretProvider interface {
+	// GetSecret returns the current value of the named secret, or
+	// ErrSecretNotFound.
+	GetSecret(ctx context.Context, name string) (*Secret, error)
+}
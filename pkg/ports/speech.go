@@ -0,0 +1,230 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// TranscriptionRequest is recorded audio to transcribe.
+type TranscriptionRequest struct {
+	// Audio is the file content, e.g. WAV, MP3, FLAC, OGG or WebM.
+	Audio []byte `json:"audio"`
+
+	// MIMEType is the format of Audio, e.g. "audio/wav"; empty lets the
+	// provider detect it.
+	MIMEType string `json:"mime_type,omitempty"`
+
+	// Language is the spoken language as a BCP 47 tag, e.g. "en" or
+	// "pt-BR"; empty detects it.
+	Language string `json:"language,omitempty"`
+
+	// Model is the provider's model identifier; empty uses the adapter default.
+	Model string `json:"model,omitempty"`
+
+	// Prompt lists terms likely to be spoken, such as names and jargon, to
+	// improve their recognition. Not every provider supports it.
+	Prompt string `json:"prompt,omitempty"`
+
+	// Diarize labels segments with the speaker who said them.
+	Diarize bool `json:"diarize,omitempty"`
+}
+
+// TranscriptSegment is a stretch of transcribed speech.
+type TranscriptSegment struct {
+	// Text is what was said.
+	Text string `json:"text"`
+
+	// Start and End are the time span of the segment in the audio.
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+
+	// Speaker identifies the speaker when diarization was requested.
+	Speaker string `json:"speaker,omitempty"`
+
+	// Confidence is the recognition confidence, from 0 to 1, when the
+	// provider reports it.
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// Transcript is the text of recorded audio.
+type Transcript struct {
+	// Text is the full transcript.
+	Text string `json:"text"`
+
+	// Segments split the transcript by utterance or speaker, with timings.
+	Segments []TranscriptSegment `json:"segments,omitempty"`
+
+	// Language is the spoken language, as requested or detected.
+	Language string `json:"language,omitempty"`
+
+	// Duration is the length of the audio.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Model is the model that produced the transcript.
+	Model string `json:"model,omitempty"`
+}
+
+// SpeechToText defines the interface for transcribing recorded audio.
+// Implementations handle provider-specific details (Deepgram, Whisper, etc).
+type SpeechToText interface {
+	// Transcribe returns the transcript of the request's audio.
+	Transcribe(ctx context.Context, req TranscriptionRequest) (*Transcript, error)
+}
+
+// StreamingTranscriptionRequest describes live audio to transcribe.
+type StreamingTranscriptionRequest struct {
+	// Encoding is the audio encoding, e.g. "linear16" (raw 16-bit PCM),
+	// "opus" or "mulaw"; empty lets the provider detect containerized
+	// audio.
+	Encoding string `json:"encoding,omitempty"`
+
+	// SampleRate is the sample rate in Hz, required for raw encodings.
+	SampleRate int `json:"sample_rate,omitempty"`
+
+	// Channels is the number of audio channels, 1 when zero.
+	Channels int `json:"channels,omitempty"`
+
+	// Language is the spoken language as a BCP 47 tag; empty uses the
+	// provider default.
+	Language string `json:"language,omitempty"`
+
+	// Model is the provider's model identifier; empty uses the adapter default.
+	Model string `json:"model,omitempty"`
+
+	// Diarize labels segments with the speaker who said them.
+	Diarize bool `json:"diarize,omitempty"`
+
+	// InterimResults asks for provisional transcripts while the speaker
+	// is still talking, for low-latency voice agents.
+	InterimResults bool `json:"interim_results,omitempty"`
+}
+
+// TranscriptEvent is a result of a streaming transcription.
+type TranscriptEvent struct {
+	// Segment is the transcribed speech.
+	Segment TranscriptSegment `json:"segment"`
+
+	// IsFinal is set when the segment won't be revised; interim events
+	// are replaced by later ones for the same span.
+	IsFinal bool `json:"is_final"`
+
+	// EndOfUtterance is set on the final segment before a pause in speech,
+	// when the speaker's turn has likely ended.
+	EndOfUtterance bool `json:"end_of_utterance,omitempty"`
+
+	// Err is set on the last event when the stream failed.
+	Err error `json:"-"`
+}
+
+// StreamingSpeechToText is implemented by providers that transcribe live
+// audio as it is spoken.
+type StreamingSpeechToText interface {
+	SpeechToText
+
+	// TranscribeStream sends audio from r until EOF and streams results.
+	// The channel is closed once the last result was delivered, or when
+	// ctx is cancelled.
+	TranscribeStream(ctx context.Context, audio io.Reader, req StreamingTranscriptionRequest) (<-chan TranscriptEvent, error)
+}
+
+// Audio encodings of synthesized speech.
+const (
+	// SpeechFormatMP3 is MP3 audio.
+	SpeechFormatMP3 = "mp3"
+
+	// SpeechFormatPCM is raw 16-bit little-endian mono PCM, for telephony
+	// and real-time playback.
+	SpeechFormatPCM = "pcm"
+
+	// SpeechFormatULaw is 8 kHz mu-law, used by phone systems.
+	SpeechFormatULaw = "ulaw"
+
+	// SpeechFormatOpus is Opus audio in an Ogg container.
+	SpeechFormatOpus = "opus"
+)
+
+// SpeechRequest is text to synthesize.
+type SpeechRequest struct {
+	// Text is what is spoken.
+	Text string `json:"text"`
+
+	// Voice is the provider's voice ID or name; empty uses the adapter
+	// default.
+	Voice string `json:"voice,omitempty"`
+
+	// Model is the provider's model identifier; empty uses the adapter default.
+	Model string `json:"model,omitempty"`
+
+	// Format is the audio encoding, a SpeechFormat constant; empty is MP3.
+	Format string `json:"format,omitempty"`
+
+	// SampleRate is the sample rate in Hz; zero uses the format's default.
+	SampleRate int `json:"sample_rate,omitempty"`
+
+	// Language is a BCP 47 tag enforcing the spoken language, for
+	// multilingual models; empty infers it from the text.
+	Language string `json:"language,omitempty"`
+
+	// Speed scales the speaking rate, 1 being normal; zero is normal.
+	Speed float64 `json:"speed,omitempty"`
+}
+
+// SpeechUsage counts what a synthesis is billed for.
+type SpeechUsage struct {
+	// Characters is the number of characters billed.
+	Characters int `json:"characters"`
+}
+
+// Speech is synthesized audio.
+type Speech struct {
+	// Audio is the encoded audio.
+	Audio []byte `json:"audio"`
+
+	// MIMEType is the audio format, e.g. "audio/mpeg".
+	MIMEType string `json:"mime_type"`
+
+	// Usage is what the synthesis was billed for.
+	Usage SpeechUsage `json:"usage"`
+}
+
+// SpeechStream is audio delivered while it is synthesized.
+type SpeechStream struct {
+	// Audio yields the encoded audio as it is generated; it must be closed.
+	Audio io.ReadCloser
+
+	// MIMEType is the audio format, e.g. "audio/mpeg".
+	MIMEType string
+
+	// Usage is what the synthesis is billed for.
+	Usage SpeechUsage
+}
+
+// Voice is a voice a TextToSpeech provider can speak with.
+type Voice struct {
+	// ID identifies the voice in SpeechRequest.Voice.
+	ID string `json:"id"`
+
+	// Name is the display name.
+	Name string `json:"name"`
+
+	// Labels describe the voice, e.g. "gender", "accent" or "language".
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// PreviewURL is a sample of the voice.
+	PreviewURL string `json:"preview_url,omitempty"`
+}
+
+// TextToSpeech defines the interface for synthesizing speech, e.g. the
+// replies of voice agents.
+type TextToSpeech interface {
+	// Synthesize returns the audio of the request's text.
+	Synthesize(ctx context.Context, req SpeechRequest) (*Speech, error)
+
+	// SynthesizeStream returns the audio as it is generated, so playback
+	// can start before synthesis finishes.
+	SynthesizeStream(ctx context.Context, req SpeechRequest) (*SpeechStream, error)
+
+	// Voices lists the available voices.
+	Voices(ctx context.Context) ([]Voice, error)
+}
@@ -0,0 +1,63 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRunNotFound is returned when a run has no stored state, or it expired.
+var ErrRunNotFound = errors.New("run state not found")
+
+// ErrRunRevisionMismatch is returned by CompareAndSwap when the run state was
+// written since the revision the caller read.
+var ErrRunRevisionMismatch = errors.New("run state revision mismatch")
+
+// RunState is the checkpoint of one workflow run.
+type RunState struct {
+	// RunID identifies the run.
+	RunID string `json:"run_id"`
+
+	// WorkflowID is the workflow (graph) the run executes; runs are listed by it.
+	WorkflowID string `json:"workflow_id"`
+
+	// Status is the run's status as set by the orchestrator, e.g. "running".
+	Status string `json:"status,omitempty"`
+
+	// Data is the checkpoint itself, opaque to the store.
+	Data []byte `json:"data"`
+
+	// Revision is incremented by every write, starting at 1.
+	Revision int64 `json:"revision"`
+
+	// UpdatedAt is when the state was last written.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// ExpiresAt is when the state expires; zero if it is kept until deleted.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// StateStore defines the interface for persisting workflow run checkpoints
+// with optimistic concurrency, so that several orchestrator instances can
+// resume runs without overwriting each other's progress.
+type StateStore interface {
+	// Save writes the run state unconditionally and returns its new
+	// revision. A positive ttl expires it after that long; zero keeps it.
+	Save(ctx context.Context, state RunState, ttl time.Duration) (int64, error)
+
+	// Load returns the run state, or ErrRunNotFound.
+	Load(ctx context.Context, runID string) (*RunState, error)
+
+	// CompareAndSwap writes the run state only if it is still at revision,
+	// and returns its new revision. Revision zero creates the state only if
+	// it doesn't exist. It returns ErrRunRevisionMismatch if another writer
+	// got there first, and ErrRunNotFound if the state to update is gone.
+	CompareAndSwap(ctx context.Context, state RunState, revision int64, ttl time.Duration) (int64, error)
+
+	// Delete removes the run state. Deleting a missing run is not an error.
+	Delete(ctx context.Context, runID string) error
+
+	// ListByWorkflow returns the states of the workflow's runs, least
+	// recently updated first.
+	ListByWorkflow(ctx context.Context, workflowID string) ([]RunState, error)
+}
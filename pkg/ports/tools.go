@@ -0,0 +1,165 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// ErrToolNotFound is returned when a tool call names a tool the executor
+// doesn't provide.
+var ErrToolNotFound = errors.New("tool not found")
+
+// ToolResult is the outcome of one tool call, to be sent back to the model.
+type ToolResult struct {
+	// ToolCallID is the ID of the tool call this result answers.
+	ToolCallID string `json:"tool_call_id"`
+
+	// Name is the tool that was called.
+	Name string `json:"name"`
+
+	// Content is the tool output as the model should see it.
+	Content string `json:"content"`
+
+	// IsError marks Content as describing a failure (e.g. an HTTP 500), which
+	// the model may recover from by retrying or changing its arguments.
+	IsError bool `json:"is_error,omitempty"`
+}
+
+// ToolExecutor defines the interface for executing the tool calls returned
+// by CompleteWithTools.
+type ToolExecutor interface {
+	// Tools returns the definitions of the tools the executor provides, to
+	// be passed to CompleteWithTools.
+	Tools() []libports.Tool
+
+	// Execute runs a tool call. Failures of the tool itself are returned as
+	// a result with IsError set; errors are reserved for calls that couldn't
+	// be run, such as ErrToolNotFound or invalid arguments.
+	Execute(ctx context.Context, call libports.ToolCall) (*ToolResult, error)
+}
+
+// Conversation messages of a tool loop. libports.Message has no fields for
+// tool calls and results, so they are carried as JSON in Content: assistant
+// messages requesting tools are named ToolCallsName, and results have the
+// RoleTool role. Adapters implementing CompleteWithTools decode them with
+// ParseToolCallsMessage and ParseToolResultMessage into their provider's
+// representation.
+const (
+	// RoleTool is the role of tool result messages
+	RoleTool = "tool"
+
+	// ToolCallsName is the name of assistant messages requesting tool calls
+	ToolCallsName = "tool_calls"
+)
+
+// toolCallsContent is the Content of an assistant tool calls message
+type toolCallsContent struct {
+	Content   string              `json:"content,omitempty"`
+	ToolCalls []libports.ToolCall `json:"tool_calls"`
+}
+
+// ToolCallsMessage returns the assistant message of a response requesting
+// tool calls, to append to the conversation before the results.
+func ToolCallsMessage(resp *libports.CompletionResponse) libports.Message {
+	content, _ := json.Marshal(toolCallsContent{
+		Content:   resp.Message.Content,
+		ToolCalls: resp.ToolCalls,
+	})
+	return libports.Message{Role: "assistant", Name: ToolCallsName, Content: string(content)}
+}
+
+// ParseToolCallsMessage returns the text and tool calls of a message built by
+// ToolCallsMessage. ok is false for any other message.
+func ParseToolCallsMessage(msg libports.Message) (content string, calls []libports.ToolCall, ok bool) {
+	if msg.Role != "assistant" || msg.Name != ToolCallsName {
+		return "", nil, false
+	}
+	var decoded toolCallsContent
+	if err := json.Unmarshal([]byte(msg.Content), &decoded); err != nil {
+		return "", nil, false
+	}
+	return decoded.Content, decoded.ToolCalls, true
+}
+
+// ToolResultMessage returns the message sending a tool result back to the
+// model
+func ToolResultMessage(result *ToolResult) libports.Message {
+	content, _ := json.Marshal(result)
+	return libports.Message{Role: RoleTool, Name: result.Name, Content: string(content)}
+}
+
+// ParseToolResultMessage returns the result of a message built by
+// ToolResultMessage. ok is false for any other message.
+func ParseToolResultMessage(msg libports.Message) (result *ToolResult, ok bool) {
+	if msg.Role != RoleTool {
+		return nil, false
+	}
+	if err := json.Unmarshal([]byte(msg.Content), &result); err != nil || result == nil || result.ToolCallID == "" {
+		return nil, false
+	}
+	return result, true
+}
+
+// ToolCallsDomainMessage returns the assistant message of a GenerateCompletion
+// response requesting tool calls, like ToolCallsMessage. domain.Message has
+// no name, so CompletionMessages recognizes it by its content.
+func ToolCallsDomainMessage(resp *domain.LLMResponse) domain.Message {
+	calls := make([]libports.ToolCall, 0, len(resp.ToolCalls))
+	for _, call := range resp.ToolCalls {
+		calls = append(calls, libports.ToolCall{ID: call.ID, Name: call.Name, Arguments: call.Input})
+	}
+	content, _ := json.Marshal(toolCallsContent{Content: resp.Content, ToolCalls: calls})
+	return domain.Message{Role: "assistant", Content: string(content)}
+}
+
+// ToolResultDomainMessage returns the domain message sending a tool result
+// back to the model, like ToolResultMessage
+func ToolResultDomainMessage(result *ToolResult) domain.Message {
+	msg := ToolResultMessage(result)
+	return domain.Message{Role: msg.Role, Content: msg.Content}
+}
+
+// CompletionMessages converts the messages of a domain request, its system
+// prompt first, so adapters convert them like those of a CompletionRequest.
+// Messages built by ToolCallsDomainMessage and ToolResultDomainMessage are
+// decoded by ParseToolCallsMessage and ParseToolResultMessage.
+func CompletionMessages(req *domain.LLMRequest) []libports.Message {
+	msgs := make([]libports.Message, 0, len(req.Messages)+1)
+	if req.System != "" {
+		msgs = append(msgs, libports.Message{Role: "system", Content: req.System})
+	}
+	for _, msg := range req.Messages {
+		converted := libports.Message{Role: msg.Role, Content: msg.Content}
+		if msg.Role == "assistant" && isToolCallsContent(msg.Content) {
+			converted.Name = ToolCallsName
+		}
+		msgs = append(msgs, converted)
+	}
+	return msgs
+}
+
+// isToolCallsContent reports whether content was encoded by
+// ToolCallsDomainMessage: only the fields of toolCallsContent, with at
+// least one call, each with an ID and a name
+func isToolCallsContent(content string) bool {
+	if len(content) == 0 || content[0] != '{' {
+		return false
+	}
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.DisallowUnknownFields()
+	var decoded toolCallsContent
+	if err := decoder.Decode(&decoded); err != nil || len(decoded.ToolCalls) == 0 {
+		return false
+	}
+	for _, call := range decoded.ToolCalls {
+		if call.ID == "" || call.Name == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+// Package propagation carries OpenTelemetry trace context and workflow
+// correlation IDs across the task queues and event buses of this
+// repository, so that one trace spans router, queue, executor and LLM call.
+//
+// Inject and Extract move the context through string headers: the W3C
+// traceparent, tracestate and baggage keys (or those of the global
+// propagator, if one is set), plus the workflow and run IDs. InjectTask and
+// ExtractTask use ports.Task headers, and InjectEvent and ExtractEvent use
+// event metadata. Fields turns the context into zap fields, so logs carry
+// the same IDs as traces.
+//
+// NewTracingQueue and NewTracingEventBus do this on every publish, under a
+// producer span; StartTaskSpan starts the consumer span of a task.
+//
+// Usage:
+//
+//	queue := propagation.NewTracingQueue(redisQueue)
+//
+//	// Router
+//	ctx = propagation.WithCorrelation(ctx, propagation.Correlation{WorkflowID: "summarize", RunID: runID})
+//	_, err := queue.Publish(ctx, "llm", task)
+//
+//	// Executor
+//	for _, task := range tasks {
+//		ctx, span := propagation.StartTaskSpan(ctx, task)
+//		logger.Info("processing task", propagation.Fields(ctx)...)
+//		resp, err := client.Complete(ctx, req)
+//		span.End()
+//	}
+package propagation
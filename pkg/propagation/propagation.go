@@ -0,0 +1,158 @@
+package propagation
+
+import (
+	"context"
+	"maps"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Header keys of correlation IDs in task headers and event metadata. Trace
+// context uses the W3C keys ("traceparent", "tracestate", "baggage"). Task
+// IDs aren't propagated: each task has its own.
+const (
+	HeaderWorkflowID = "dago-workflow-id"
+	HeaderRunID      = "dago-run-id"
+)
+
+// Used when no global propagator is configured
+var defaultPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Correlation identifies the workflow run and task a piece of work belongs
+// to, so logs of every worker involved can be joined.
+type Correlation struct {
+	WorkflowID string `json:"workflow_id,omitempty"`
+	RunID      string `json:"run_id,omitempty"`
+	TaskID     string `json:"task_id,omitempty"`
+}
+
+type correlationKey struct{}
+
+// WithCorrelation returns a context carrying the correlation IDs. Empty IDs
+// keep those already in ctx.
+func WithCorrelation(ctx context.Context, c Correlation) context.Context {
+	current := CorrelationFrom(ctx)
+	if c.WorkflowID == "" {
+		c.WorkflowID = current.WorkflowID
+	}
+	if c.RunID == "" {
+		c.RunID = current.RunID
+	}
+	if c.TaskID == "" {
+		c.TaskID = current.TaskID
+	}
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// CorrelationFrom returns the correlation IDs carried by ctx
+func CorrelationFrom(ctx context.Context) Correlation {
+	c, _ := ctx.Value(correlationKey{}).(Correlation)
+	return c
+}
+
+// Propagator returns the global OpenTelemetry propagator, or W3C trace
+// context and baggage if none is configured
+func Propagator() propagation.TextMapPropagator {
+	p := otel.GetTextMapPropagator()
+	if len(p.Fields()) == 0 {
+		return defaultPropagator
+	}
+	return p
+}
+
+// Inject writes the trace context and correlation IDs of ctx into headers
+func Inject(ctx context.Context, headers map[string]string) {
+	Propagator().Inject(ctx, propagation.MapCarrier(headers))
+
+	c := CorrelationFrom(ctx)
+	if c.WorkflowID != "" {
+		headers[HeaderWorkflowID] = c.WorkflowID
+	}
+	if c.RunID != "" {
+		headers[HeaderRunID] = c.RunID
+	}
+}
+
+// Extract returns ctx with the trace context and correlation IDs read from
+// headers. The trace context becomes the remote parent of new spans.
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	ctx = Propagator().Extract(ctx, propagation.MapCarrier(headers))
+	return WithCorrelation(ctx, Correlation{
+		WorkflowID: headers[HeaderWorkflowID],
+		RunID:      headers[HeaderRunID],
+	})
+}
+
+// InjectTask writes the trace context and correlation IDs of ctx into the
+// task's headers, copying them so the caller's map is left unchanged
+func InjectTask(ctx context.Context, task *ports.Task) {
+	headers := make(map[string]string, len(task.Headers)+4)
+	maps.Copy(headers, task.Headers)
+	Inject(ctx, headers)
+	task.Headers = headers
+}
+
+// ExtractTask returns ctx with the trace context and correlation IDs of a
+// consumed task, and the task's ID
+func ExtractTask(ctx context.Context, task ports.Task) context.Context {
+	ctx = Extract(ctx, task.Headers)
+	return WithCorrelation(ctx, Correlation{TaskID: task.ID})
+}
+
+// InjectEvent writes the trace context and correlation IDs of ctx into the
+// event's metadata, copying it so the caller's map is left unchanged
+func InjectEvent(ctx context.Context, event *libports.Event) {
+	headers := make(map[string]string)
+	Inject(ctx, headers)
+	if len(headers) == 0 {
+		return
+	}
+
+	metadata := make(map[string]interface{}, len(event.Metadata)+len(headers))
+	maps.Copy(metadata, event.Metadata)
+	for key, value := range headers {
+		metadata[key] = value
+	}
+	event.Metadata = metadata
+}
+
+// ExtractEvent returns ctx with the trace context and correlation IDs of a
+// received event. The run ID is the event's execution ID unless the
+// metadata carries one.
+func ExtractEvent(ctx context.Context, event libports.Event) context.Context {
+	headers := make(map[string]string)
+	for key, value := range event.Metadata {
+		if s, ok := value.(string); ok {
+			headers[key] = s
+		}
+	}
+	ctx = WithCorrelation(ctx, Correlation{RunID: event.ExecutionID})
+	return Extract(ctx, headers)
+}
+
+// Fields returns zap fields with the trace, span and correlation IDs of
+// ctx, for logs that can be joined with traces
+func Fields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", sc.TraceID().String()),
+			zap.String("span_id", sc.SpanID().String()))
+	}
+	c := CorrelationFrom(ctx)
+	if c.WorkflowID != "" {
+		fields = append(fields, zap.String("workflow_id", c.WorkflowID))
+	}
+	if c.RunID != "" {
+		fields = append(fields, zap.String("run_id", c.RunID))
+	}
+	if c.TaskID != "" {
+		fields = append(fields, zap.String("task_id", c.TaskID))
+	}
+	return fields
+}
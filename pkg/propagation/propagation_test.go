@@ -0,0 +1,149 @@
+package propagation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedContext returns a context with a sampled remote span
+func tracedContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+	return trace.ContextWithSpanContext(context.Background(), sc), sc
+}
+
+func TestTaskRoundTrip(t *testing.T) {
+	ctx, sc := tracedContext(t)
+	ctx = WithCorrelation(ctx, Correlation{WorkflowID: "summarize", RunID: "run-1", TaskID: "task-0"})
+
+	headers := map[string]string{"type": "llm"}
+	task := ports.Task{Headers: headers}
+	InjectTask(ctx, &task)
+
+	if !strings.Contains(task.Headers["traceparent"], sc.TraceID().String()) || task.Headers[HeaderWorkflowID] != "summarize" ||
+		task.Headers[HeaderRunID] != "run-1" || task.Headers["type"] != "llm" {
+		t.Errorf("headers = %v", task.Headers)
+	}
+	if len(headers) != 1 {
+		t.Errorf("caller's headers modified: %v", headers)
+	}
+
+	task.ID = "task-1"
+	consumed := ExtractTask(context.Background(), task)
+	if got := trace.SpanContextFromContext(consumed); got.TraceID() != sc.TraceID() || !got.IsRemote() {
+		t.Errorf("extracted span context = %+v", got)
+	}
+	if got := CorrelationFrom(consumed); got != (Correlation{WorkflowID: "summarize", RunID: "run-1", TaskID: "task-1"}) {
+		t.Errorf("CorrelationFrom() = %+v", got)
+	}
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	ctx, sc := tracedContext(t)
+	ctx = WithCorrelation(ctx, Correlation{WorkflowID: "summarize"})
+
+	event := libports.Event{ID: "evt-1", ExecutionID: "run-2", Metadata: map[string]interface{}{"attempt": 2}}
+	InjectEvent(ctx, &event)
+	if event.Metadata["attempt"] != 2 || event.Metadata["traceparent"] == nil {
+		t.Errorf("metadata = %v", event.Metadata)
+	}
+
+	received := ExtractEvent(context.Background(), event)
+	if trace.SpanContextFromContext(received).TraceID() != sc.TraceID() {
+		t.Error("trace context not extracted from event")
+	}
+	if got := CorrelationFrom(received); got.WorkflowID != "summarize" || got.RunID != "run-2" {
+		t.Errorf("CorrelationFrom() = %+v", got)
+	}
+}
+
+func TestFields(t *testing.T) {
+	if fields := Fields(context.Background()); len(fields) != 0 {
+		t.Errorf("Fields() of an empty context = %v", fields)
+	}
+
+	ctx, _ := tracedContext(t)
+	ctx = WithCorrelation(ctx, Correlation{RunID: "run-1"})
+	ctx = WithCorrelation(ctx, Correlation{TaskID: "task-1"})
+
+	got := map[string]string{}
+	for _, f := range Fields(ctx) {
+		got[f.Key] = f.String
+	}
+	if got["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || got["span_id"] != "00f067aa0ba902b7" ||
+		got["run_id"] != "run-1" || got["task_id"] != "task-1" || len(got) != 4 {
+		t.Errorf("Fields() = %v", got)
+	}
+}
+
+// recordingQueue records published tasks
+type recordingQueue struct {
+	ports.TaskQueue
+	published []ports.Task
+}
+
+func (q *recordingQueue) Publish(ctx context.Context, queue string, task ports.Task) (string, error) {
+	q.published = append(q.published, task)
+	return "task-1", nil
+}
+
+func TestTracingQueue(t *testing.T) {
+	ctx, sc := tracedContext(t)
+	inner := &recordingQueue{}
+	queue := NewTracingQueue(inner)
+
+	if _, err := queue.Publish(ctx, "llm", ports.Task{Payload: []byte("{}")}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	task := inner.published[0]
+	task.ID, task.Queue = "task-1", "llm"
+
+	ctx, span := StartTaskSpan(context.Background(), task)
+	defer span.End()
+	if trace.SpanContextFromContext(ctx).TraceID() != sc.TraceID() || CorrelationFrom(ctx).TaskID != "task-1" {
+		t.Errorf("consumer context = %+v, %+v", trace.SpanContextFromContext(ctx), CorrelationFrom(ctx))
+	}
+}
+
+// handlerBus calls subscribed handlers synchronously on Publish
+type handlerBus struct {
+	libports.EventBus
+	handlers map[string]libports.EventHandler
+}
+
+func (b *handlerBus) Publish(ctx context.Context, topic string, event libports.Event) error {
+	return b.handlers[topic](context.Background(), event)
+}
+
+func (b *handlerBus) Subscribe(ctx context.Context, topic string, handler libports.EventHandler) error {
+	b.handlers[topic] = handler
+	return nil
+}
+
+func TestTracingEventBus(t *testing.T) {
+	ctx, sc := tracedContext(t)
+	bus := NewTracingEventBus(&handlerBus{handlers: map[string]libports.EventHandler{}})
+
+	var handled context.Context
+	err := bus.Subscribe(ctx, "task.finished", func(ctx context.Context, event libports.Event) error {
+		handled = ctx
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Publish(WithCorrelation(ctx, Correlation{WorkflowID: "summarize"}), "task.finished", libports.Event{ID: "evt-1"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if trace.SpanContextFromContext(handled).TraceID() != sc.TraceID() || CorrelationFrom(handled).WorkflowID != "summarize" {
+		t.Errorf("handler context = %+v, %+v", trace.SpanContextFromContext(handled), CorrelationFrom(handled))
+	}
+}
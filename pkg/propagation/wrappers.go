@@ -0,0 +1,110 @@
+package propagation
+
+import (
+	"context"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation scope of producer and consumer spans
+const instrumentationName = "github.com/aescanero/dago-adapters/pkg/propagation"
+
+// Span attribute keys
+const (
+	attrQueue   = attribute.Key("messaging.destination.name")
+	attrTaskID  = attribute.Key("messaging.message.id")
+	attrTopic   = attribute.Key("dago.event.topic")
+	attrEventID = attribute.Key("dago.event.id")
+)
+
+// TracingQueue wraps a task queue so that every published task carries the
+// publisher's trace context and correlation IDs, under a producer span.
+// Other methods pass through; consumers call StartTaskSpan for each task.
+type TracingQueue struct {
+	ports.TaskQueue
+}
+
+// NewTracingQueue wraps queue
+func NewTracingQueue(queue ports.TaskQueue) *TracingQueue {
+	return &TracingQueue{TaskQueue: queue}
+}
+
+// Publish publishes the task with trace context headers (ports.TaskQueue
+// interface)
+func (q *TracingQueue) Publish(ctx context.Context, queue string, task ports.Task) (string, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "publish "+queue,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrQueue.String(queue)))
+
+	InjectTask(ctx, &task)
+	id, err := q.TaskQueue.Publish(ctx, queue, task)
+	if err == nil {
+		span.SetAttributes(attrTaskID.String(id))
+	}
+	endSpan(span, err)
+	return id, err
+}
+
+// StartTaskSpan starts a consumer span for a consumed task, child of the
+// span that published it, and returns a context carrying the span and the
+// task's correlation IDs. End the span when the task is done.
+func StartTaskSpan(ctx context.Context, task ports.Task) (context.Context, trace.Span) {
+	ctx = ExtractTask(ctx, task)
+	return otel.Tracer(instrumentationName).Start(ctx, "process "+task.Queue,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrQueue.String(task.Queue), attrTaskID.String(task.ID)))
+}
+
+// TracingEventBus wraps an event bus so that published events carry the
+// publisher's trace context and correlation IDs, and handlers are called
+// with them under a consumer span
+type TracingEventBus struct {
+	libports.EventBus
+}
+
+// NewTracingEventBus wraps bus
+func NewTracingEventBus(bus libports.EventBus) *TracingEventBus {
+	return &TracingEventBus{EventBus: bus}
+}
+
+// Publish publishes the event with trace context metadata
+// (ports.EventBus interface)
+func (b *TracingEventBus) Publish(ctx context.Context, topic string, event libports.Event) error {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrTopic.String(topic), attrEventID.String(event.ID)))
+
+	InjectEvent(ctx, &event)
+	err := b.EventBus.Publish(ctx, topic, event)
+	endSpan(span, err)
+	return err
+}
+
+// Subscribe registers a handler called with each event's trace context and
+// correlation IDs, under a consumer span (ports.EventBus interface)
+func (b *TracingEventBus) Subscribe(ctx context.Context, topic string, handler libports.EventHandler) error {
+	return b.EventBus.Subscribe(ctx, topic, func(ctx context.Context, event libports.Event) error {
+		ctx = ExtractEvent(ctx, event)
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, "process "+topic,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(attrTopic.String(topic), attrEventID.String(event.ID)))
+
+		err := handler(ctx, event)
+		endSpan(span, err)
+		return err
+	})
+}
+
+// endSpan records err, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,9 @@
+// Package queue provides adapters for the ports.TaskQueue interface (pkg/ports
+// in this repository), which carries tasks from the orchestrator to executor
+// and router workers. Queues implementing ports.DeadLetterQueue move tasks
+// that exceed a maximum number of deliveries aside, to be listed and requeued.
+//
+// Available implementations:
+//   - redis: Uses Redis Streams with consumer groups
+//   - sqs: Uses Amazon SQS standard and FIFO queues
+package queue
@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Suffix of the dead-letter stream of a queue
+const deadLetterSuffix = ".dead"
+
+// Dead-letter entry fields, next to the task's payload and headers
+const (
+	fieldOriginalID = "original_id"
+	fieldGroup      = "group"
+	fieldDeliveries = "deliveries"
+)
+
+// DeadLetterStream returns the name of the stream holding a queue's
+// dead-lettered tasks, e.g. executor.work.dead
+func DeadLetterStream(queue string) string {
+	return queue + deadLetterSuffix
+}
+
+// ListDeadLetters returns up to count dead-lettered tasks of the queue,
+// oldest first (ports.DeadLetterQueue interface). Their ID and Receipt are
+// those of the dead-letter entry, EnqueuedAt is when the task was first
+// published.
+func (q *Queue) ListDeadLetters(ctx context.Context, queue string, count int) ([]ports.Task, error) {
+	messages, err := q.client.XRangeN(ctx, DeadLetterStream(queue), "-", "+", int64(count)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered tasks: %w", err)
+	}
+
+	tasks := make([]ports.Task, 0, len(messages))
+	for _, msg := range messages {
+		task := taskFromMessage(queue, msg)
+
+		if deliveries, ok := msg.Values[fieldDeliveries].(string); ok {
+			task.Deliveries, _ = strconv.ParseInt(deliveries, 10, 64)
+		}
+		if id, ok := msg.Values[fieldOriginalID].(string); ok {
+			ms, _, _ := strings.Cut(id, "-")
+			if millis, err := strconv.ParseInt(ms, 10, 64); err == nil {
+				task.EnqueuedAt = time.UnixMilli(millis)
+			}
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// Requeue appends dead-lettered tasks to their queue's stream again and
+// deletes them from the dead-letter stream (ports.DeadLetterQueue interface).
+// Requeued tasks are new entries, delivered to every consumer group of the
+// queue and not only the one they failed in.
+func (q *Queue) Requeue(ctx context.Context, tasks ...ports.Task) error {
+	for _, task := range tasks {
+		values, err := taskValues(task)
+		if err != nil {
+			return err
+		}
+
+		if err := q.client.XAdd(ctx, q.addArgs(task.Queue, values)).Err(); err != nil {
+			return fmt.Errorf("failed to requeue task: %w", err)
+		}
+		if err := q.client.XDel(ctx, DeadLetterStream(task.Queue), task.Receipt).Err(); err != nil {
+			return fmt.Errorf("failed to delete dead-lettered task: %w", err)
+		}
+	}
+
+	if len(tasks) > 0 {
+		q.logger.Info("dead-lettered tasks requeued", zap.Int("count", len(tasks)))
+	}
+	return nil
+}
+
+// deadLetter moves claimed tasks of a group to their dead-letter stream and
+// acknowledges them. Tasks are acknowledged only once they were copied, so a
+// failure leaves them pending.
+func (q *Queue) deadLetter(ctx context.Context, group string, tasks []ports.Task) error {
+	for _, task := range tasks {
+		values, err := taskValues(task)
+		if err != nil {
+			return err
+		}
+		values[fieldOriginalID] = task.ID
+		values[fieldGroup] = group
+		values[fieldDeliveries] = task.Deliveries
+
+		// The dead-letter stream is not trimmed: it only grows with failures
+		err = q.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: DeadLetterStream(task.Queue),
+			Values: values,
+		}).Err()
+		if err != nil {
+			return fmt.Errorf("failed to dead-letter task: %w", err)
+		}
+		if err := q.client.XAck(ctx, task.Queue, group, task.Receipt).Err(); err != nil {
+			return fmt.Errorf("failed to ack dead-lettered task: %w", err)
+		}
+
+		q.logger.Warn("task dead-lettered",
+			zap.String("queue", task.Queue),
+			zap.String("group", group),
+			zap.String("task_id", task.ID),
+			zap.Int64("deliveries", task.Deliveries))
+	}
+
+	return nil
+}
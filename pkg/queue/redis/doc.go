@@ -0,0 +1,40 @@
+// Package redis implements ports.TaskQueue (pkg/ports in this repository)
+// using Redis Streams.
+//
+// Storage structure:
+//   - Each queue is a stream named after it, e.g. executor.work. The default
+//     stream names match the worker registry's Keys.Streams, so the pending
+//     counts the registry reports are those of this queue.
+//   - Each entry holds the payload and, if any, JSON-encoded headers
+//   - Consumer groups are created on first use (XGROUP CREATE MKSTREAM),
+//     starting at the beginning of the stream
+//
+// Delivery is at least once: Consume reads with XREADGROUP, Ack removes
+// tasks from the group's pending entries list, and ClaimPending moves tasks
+// that a crashed consumer never acknowledged to another one with XCLAIM,
+// reporting how often each was delivered.
+//
+// With SetMaxDeliveries, ClaimPending moves tasks that exhausted their
+// deliveries to the queue's dead-letter stream (e.g. executor.work.dead),
+// recording the group and delivery count, and acknowledges them so they stop
+// clogging the group's pending entries. ListDeadLetters and Requeue inspect
+// them and append them to the queue again once the cause is fixed.
+//
+// Usage:
+//
+//	queue := redis.NewQueue(client, logger)
+//
+//	id, err := queue.Publish(ctx, "executor.work", ports.Task{Payload: body})
+//
+//	tasks, err := queue.Consume(ctx, ports.ConsumeRequest{
+//		Queue:    "executor.work",
+//		Group:    "executor-workers",
+//		Consumer: workerID,
+//		Count:    10,
+//		Block:    5 * time.Second,
+//	})
+//	for _, task := range tasks {
+//		process(task)
+//		_ = queue.Ack(ctx, task.Queue, "executor-workers", task.Receipt)
+//	}
+package redis
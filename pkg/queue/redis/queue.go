@@ -0,0 +1,309 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Stream entry fields
+const (
+	fieldPayload = "payload"
+	fieldHeaders = "headers"
+)
+
+// Queue implements ports.TaskQueue using Redis Streams. Each queue is a
+// stream and each consumer group a stream consumer group.
+// It works with standalone, Sentinel (failover), Cluster and Ring clients.
+type Queue struct {
+	client redis.UniversalClient
+	logger *zap.Logger
+
+	// Approximate stream length cap, see SetMaxLen
+	maxLen int64
+
+	// Deliveries after which tasks are dead-lettered, see SetMaxDeliveries
+	maxDeliveries int64
+
+	// Consumer groups known to exist
+	mu     sync.Mutex
+	groups map[string]struct{}
+}
+
+// NewQueue creates a new Redis Streams task queue
+func NewQueue(client redis.UniversalClient, logger *zap.Logger) *Queue {
+	return &Queue{
+		client: client,
+		logger: logger,
+		groups: make(map[string]struct{}),
+	}
+}
+
+// SetMaxLen caps every stream at about maxLen entries on Publish; zero keeps
+// everything. Trimming drops the oldest entries even if they are still
+// pending, so size the cap well above the expected backlog. Call it before
+// the queue is in use.
+func (q *Queue) SetMaxLen(maxLen int64) {
+	q.maxLen = maxLen
+}
+
+// SetMaxDeliveries dead-letters tasks that were delivered maxDeliveries
+// times without being acknowledged: instead of claiming them again,
+// ClaimPending moves them to the queue's dead-letter stream. Zero, the
+// default, retries forever. Call it before the queue is in use.
+func (q *Queue) SetMaxDeliveries(maxDeliveries int64) {
+	q.maxDeliveries = maxDeliveries
+}
+
+// Publish appends a task to the queue's stream (ports.TaskQueue interface)
+func (q *Queue) Publish(ctx context.Context, queue string, task ports.Task) (string, error) {
+	values, err := taskValues(task)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := q.client.XAdd(ctx, q.addArgs(queue, values)).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to publish task: %w", err)
+	}
+
+	q.logger.Debug("task published",
+		zap.String("queue", queue),
+		zap.String("task_id", id))
+
+	return id, nil
+}
+
+// Consume reads new tasks for the consumer (ports.TaskQueue interface). The
+// consumer group is created on first use and starts at the beginning of the
+// stream, so tasks published before any consumer existed are delivered.
+func (q *Queue) Consume(ctx context.Context, req ports.ConsumeRequest) ([]ports.Task, error) {
+	if err := q.ensureGroup(ctx, req.Queue, req.Group); err != nil {
+		return nil, err
+	}
+
+	// go-redis blocks forever on zero; a negative value doesn't block
+	block := req.Block
+	if block <= 0 {
+		block = -1
+	}
+
+	args := &redis.XReadGroupArgs{
+		Group:    req.Group,
+		Consumer: req.Consumer,
+		Streams:  []string{req.Queue, ">"},
+		Count:    int64(req.Count),
+		Block:    block,
+	}
+
+	streams, err := q.client.XReadGroup(ctx, args).Result()
+	if isNoGroup(err) {
+		// The stream was deleted with its groups; recreate and retry once
+		q.forgetGroup(req.Queue, req.Group)
+		if err := q.ensureGroup(ctx, req.Queue, req.Group); err != nil {
+			return nil, err
+		}
+		streams, err = q.client.XReadGroup(ctx, args).Result()
+	}
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume tasks: %w", err)
+	}
+
+	var tasks []ports.Task
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			task := taskFromMessage(req.Queue, msg)
+			task.Deliveries = 1
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
+
+// Ack acknowledges tasks by ID (ports.TaskQueue interface)
+func (q *Queue) Ack(ctx context.Context, queue, group string, receipts ...string) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	if err := q.client.XAck(ctx, queue, group, receipts...).Err(); err != nil {
+		return fmt.Errorf("failed to ack tasks: %w", err)
+	}
+	return nil
+}
+
+// ClaimPending takes over tasks that other consumers left unacknowledged for
+// at least MinIdle (ports.TaskQueue interface). Pending entries whose task
+// was trimmed from the stream are skipped, and tasks that reached the maximum
+// number of deliveries are dead-lettered instead of returned.
+func (q *Queue) ClaimPending(ctx context.Context, req ports.ClaimRequest) ([]ports.Task, error) {
+	if err := q.ensureGroup(ctx, req.Queue, req.Group); err != nil {
+		return nil, err
+	}
+
+	pending, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: req.Queue,
+		Group:  req.Group,
+		Idle:   req.MinIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  int64(req.Count),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pending tasks: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(pending))
+	deliveries := make(map[string]int64, len(pending))
+	for i, p := range pending {
+		ids[i] = p.ID
+		deliveries[p.ID] = p.RetryCount
+	}
+
+	messages, err := q.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   req.Queue,
+		Group:    req.Group,
+		Consumer: req.Consumer,
+		MinIdle:  req.MinIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim tasks: %w", err)
+	}
+
+	tasks := make([]ports.Task, 0, len(messages))
+	var dead []ports.Task
+	for _, msg := range messages {
+		if msg.Values == nil {
+			continue
+		}
+
+		task := taskFromMessage(req.Queue, msg)
+		task.Deliveries = deliveries[msg.ID] + 1
+		if q.maxDeliveries > 0 && task.Deliveries > q.maxDeliveries {
+			task.Deliveries--
+			dead = append(dead, task)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	if len(dead) > 0 {
+		if err := q.deadLetter(ctx, req.Group, dead); err != nil {
+			// They stay pending and are dead-lettered by a later claim
+			q.logger.Warn("failed to dead-letter tasks",
+				zap.String("queue", req.Queue),
+				zap.String("group", req.Group),
+				zap.Error(err))
+		}
+	}
+
+	if len(tasks) > 0 {
+		q.logger.Info("claimed pending tasks",
+			zap.String("queue", req.Queue),
+			zap.String("group", req.Group),
+			zap.String("consumer", req.Consumer),
+			zap.Int("count", len(tasks)))
+	}
+
+	return tasks, nil
+}
+
+// ensureGroup creates the consumer group and its stream if needed
+func (q *Queue) ensureGroup(ctx context.Context, queue, group string) error {
+	key := queue + "\x00" + group
+
+	q.mu.Lock()
+	_, ok := q.groups[key]
+	q.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	err := q.client.XGroupCreateMkStream(ctx, queue, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	q.mu.Lock()
+	q.groups[key] = struct{}{}
+	q.mu.Unlock()
+	return nil
+}
+
+func (q *Queue) forgetGroup(queue, group string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.groups, queue+"\x00"+group)
+}
+
+// addArgs returns the XADD arguments of an entry, capped by SetMaxLen
+func (q *Queue) addArgs(stream string, values map[string]interface{}) *redis.XAddArgs {
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}
+	if q.maxLen > 0 {
+		args.MaxLen = q.maxLen
+		args.Approx = true
+	}
+	return args
+}
+
+func isNoGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+// taskValues returns the stream entry fields of a task
+func taskValues(task ports.Task) (map[string]interface{}, error) {
+	values := map[string]interface{}{
+		fieldPayload: task.Payload,
+	}
+	if len(task.Headers) > 0 {
+		headers, err := json.Marshal(task.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal task headers: %w", err)
+		}
+		values[fieldHeaders] = headers
+	}
+	return values, nil
+}
+
+func taskFromMessage(queue string, msg redis.XMessage) ports.Task {
+	task := ports.Task{
+		ID:      msg.ID,
+		Queue:   queue,
+		Receipt: msg.ID,
+	}
+
+	if payload, ok := msg.Values[fieldPayload].(string); ok {
+		task.Payload = []byte(payload)
+	}
+	if headers, ok := msg.Values[fieldHeaders].(string); ok {
+		_ = json.Unmarshal([]byte(headers), &task.Headers)
+	}
+
+	// Stream IDs are <unix ms>-<sequence>
+	ms, _, _ := strings.Cut(msg.ID, "-")
+	if millis, err := strconv.ParseInt(ms, 10, 64); err == nil {
+		task.EnqueuedAt = time.UnixMilli(millis)
+	}
+
+	return task
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewQueue(client, zap.NewNop())
+}
+
+func TestQueue_PublishConsumeAck(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+
+	// Published before any consumer group exists
+	id, err := q.Publish(ctx, "executor.work", ports.Task{
+		Payload: []byte(`{"node":"summarize"}`),
+		Headers: map[string]string{"traceparent": "00-abc-def-01"},
+	})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	req := ports.ConsumeRequest{Queue: "executor.work", Group: "executor-workers", Consumer: "executor-1", Count: 10}
+	tasks, err := q.Consume(ctx, req)
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Consume() returned %d tasks, want 1", len(tasks))
+	}
+
+	task := tasks[0]
+	if task.ID != id || string(task.Payload) != `{"node":"summarize"}` || task.Headers["traceparent"] != "00-abc-def-01" {
+		t.Errorf("Consume() task = %+v, want published task", task)
+	}
+	if task.Deliveries != 1 || task.EnqueuedAt.IsZero() {
+		t.Errorf("Consume() deliveries = %d, enqueued at %v", task.Deliveries, task.EnqueuedAt)
+	}
+
+	// Nothing new for the group, also after waiting
+	if tasks, err := q.Consume(ctx, req); err != nil || len(tasks) != 0 {
+		t.Errorf("Consume() = %v, %v, want no tasks", tasks, err)
+	}
+	req.Block = 10 * time.Millisecond
+	if tasks, err := q.Consume(ctx, req); err != nil || len(tasks) != 0 {
+		t.Errorf("Consume() with block = %v, %v, want no tasks", tasks, err)
+	}
+
+	if err := q.Ack(ctx, "executor.work", "executor-workers", task.Receipt); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	claimed, err := q.ClaimPending(ctx, ports.ClaimRequest{Queue: "executor.work", Group: "executor-workers", Consumer: "executor-2", Count: 10})
+	if err != nil || len(claimed) != 0 {
+		t.Errorf("ClaimPending() after ack = %v, %v, want nothing pending", claimed, err)
+	}
+}
+
+func TestQueue_ClaimPending(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+
+	if _, err := q.Publish(ctx, "router.work", ports.Task{Payload: []byte("route")}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// router-1 takes the task and crashes without acknowledging it
+	tasks, err := q.Consume(ctx, ports.ConsumeRequest{Queue: "router.work", Group: "router-workers", Consumer: "router-1", Count: 1})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("Consume() = %v, %v, want one task", tasks, err)
+	}
+
+	claim := ports.ClaimRequest{Queue: "router.work", Group: "router-workers", Consumer: "router-2", MinIdle: time.Hour, Count: 10}
+	if claimed, err := q.ClaimPending(ctx, claim); err != nil || len(claimed) != 0 {
+		t.Errorf("ClaimPending() = %v, %v, want nothing idle long enough", claimed, err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	claim.MinIdle = time.Millisecond
+	claimed, err := q.ClaimPending(ctx, claim)
+	if err != nil {
+		t.Fatalf("ClaimPending() error = %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != tasks[0].ID || string(claimed[0].Payload) != "route" {
+		t.Fatalf("ClaimPending() = %+v, want the abandoned task", claimed)
+	}
+	if claimed[0].Deliveries != 2 {
+		t.Errorf("ClaimPending() deliveries = %d, want 2", claimed[0].Deliveries)
+	}
+}
+
+func TestQueue_DeadLetter(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+	q.SetMaxDeliveries(2)
+
+	if _, err := q.Publish(ctx, "executor.work", ports.Task{
+		Payload: []byte("poison"),
+		Headers: map[string]string{"type": "execute"},
+	}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	consume := ports.ConsumeRequest{Queue: "executor.work", Group: "executor-workers", Consumer: "executor-1", Count: 1}
+	if tasks, err := q.Consume(ctx, consume); err != nil || len(tasks) != 1 {
+		t.Fatalf("Consume() = %v, %v, want one task", tasks, err)
+	}
+
+	// The second delivery is allowed, the third dead-letters the task
+	claim := ports.ClaimRequest{Queue: "executor.work", Group: "executor-workers", Consumer: "executor-2", Count: 10}
+	if claimed, err := q.ClaimPending(ctx, claim); err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimPending() = %v, %v, want one task", claimed, err)
+	}
+	if claimed, err := q.ClaimPending(ctx, claim); err != nil || len(claimed) != 0 {
+		t.Fatalf("ClaimPending() = %v, %v, want the task dead-lettered", claimed, err)
+	}
+	if claimed, err := q.ClaimPending(ctx, claim); err != nil || len(claimed) != 0 {
+		t.Fatalf("ClaimPending() = %v, %v, want nothing pending", claimed, err)
+	}
+
+	dead, err := q.ListDeadLetters(ctx, "executor.work", 10)
+	if err != nil {
+		t.Fatalf("ListDeadLetters() error = %v", err)
+	}
+	if len(dead) != 1 || string(dead[0].Payload) != "poison" || dead[0].Headers["type"] != "execute" {
+		t.Fatalf("ListDeadLetters() = %+v, want the poison task", dead)
+	}
+	if dead[0].Deliveries != 2 || dead[0].Queue != "executor.work" || dead[0].EnqueuedAt.IsZero() {
+		t.Errorf("ListDeadLetters() task = %+v", dead[0])
+	}
+
+	if err := q.Requeue(ctx, dead...); err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+	if dead, err := q.ListDeadLetters(ctx, "executor.work", 10); err != nil || len(dead) != 0 {
+		t.Errorf("ListDeadLetters() after requeue = %v, %v, want none", dead, err)
+	}
+	tasks, err := q.Consume(ctx, consume)
+	if err != nil || len(tasks) != 1 || string(tasks[0].Payload) != "poison" || tasks[0].Deliveries != 1 {
+		t.Errorf("Consume() after requeue = %+v, %v, want the task as new", tasks, err)
+	}
+}
@@ -0,0 +1,129 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// Suffix of the dead-letter queue name, before .fifo
+	deadLetterSuffix = "-dead"
+
+	// How long tasks listed by ListDeadLetters stay hidden, so that Requeue
+	// can still use their receipt handles
+	listVisibilityTimeout = time.Minute
+)
+
+// DeadLetterQueueName returns the name, or URL, of the queue holding a
+// queue's dead-lettered tasks, e.g. executor-work-dead or
+// executor-work-dead.fifo. The dead-letter queue of a FIFO queue must be a
+// FIFO queue. It can also serve as the target of the queue's redrive policy.
+func DeadLetterQueueName(queue string) string {
+	if name, ok := strings.CutSuffix(queue, ".fifo"); ok {
+		return name + deadLetterSuffix + ".fifo"
+	}
+	return queue + deadLetterSuffix
+}
+
+// ListDeadLetters receives up to count dead-lettered tasks of the queue
+// (ports.DeadLetterQueue interface). SQS can't peek at messages: listed tasks
+// are hidden for a minute, during which Requeue can act on them and further
+// listings skip them. Order is best effort, except on FIFO queues.
+func (q *Queue) ListDeadLetters(ctx context.Context, queue string, count int) ([]ports.Task, error) {
+	url, err := q.queueURL(ctx, DeadLetterQueueName(queue))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := q.receive(ctx, url, queue, count, 0, listVisibilityTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// Requeue sends dead-lettered tasks to their queue again and deletes them
+// from the dead-letter queue (ports.DeadLetterQueue interface). Tasks must
+// be requeued within a minute of being listed.
+func (q *Queue) Requeue(ctx context.Context, tasks ...ports.Task) error {
+	byQueue := make(map[string][]ports.Task)
+	for _, task := range tasks {
+		byQueue[task.Queue] = append(byQueue[task.Queue], task)
+	}
+
+	for queue, tasks := range byQueue {
+		deadURL, err := q.queueURL(ctx, DeadLetterQueueName(queue))
+		if err != nil {
+			return err
+		}
+
+		// Delete only what was sent; the rest reappears in the dead-letter queue
+		ids, sendErr := q.PublishBatch(ctx, queue, tasks)
+		var receipts []string
+		for i, id := range ids {
+			if id != "" {
+				receipts = append(receipts, tasks[i].Receipt)
+			}
+		}
+		if len(receipts) > 0 {
+			if err := q.delete(ctx, deadURL, receipts); err != nil {
+				return fmt.Errorf("failed to delete dead-lettered tasks: %w", err)
+			}
+		}
+		if sendErr != nil {
+			return fmt.Errorf("failed to requeue tasks: %w", sendErr)
+		}
+
+		q.logger.Info("dead-lettered tasks requeued",
+			zap.String("queue", queue),
+			zap.Int("count", len(receipts)))
+	}
+
+	return nil
+}
+
+// deadLetter sends received tasks to the dead-letter queue and deletes them
+// from the queue at url. Tasks are deleted only once they were sent, so a
+// failure leaves them to be received again.
+func (q *Queue) deadLetter(ctx context.Context, url, queue string, tasks []ports.Task) error {
+	deadURL, err := q.queueURL(ctx, DeadLetterQueueName(queue))
+	if err != nil {
+		return err
+	}
+
+	entries := make([]types.SendMessageBatchRequestEntry, len(tasks))
+	for i, task := range tasks {
+		if entries[i], err = sendEntry(DeadLetterQueueName(queue), strconv.Itoa(i), task); err != nil {
+			return err
+		}
+		entries[i].MessageAttributes[attrDeliveries] = stringAttribute(strconv.FormatInt(task.Deliveries, 10))
+	}
+
+	ids, sendErr := q.send(ctx, deadURL, entries)
+	var receipts []string
+	for i, id := range ids {
+		if id == "" {
+			continue
+		}
+		receipts = append(receipts, tasks[i].Receipt)
+
+		q.logger.Warn("task dead-lettered",
+			zap.String("queue", queue),
+			zap.String("task_id", tasks[i].ID),
+			zap.Int64("deliveries", tasks[i].Deliveries))
+	}
+
+	if len(receipts) > 0 {
+		if err := q.delete(ctx, url, receipts); err != nil {
+			return fmt.Errorf("failed to delete dead-lettered tasks: %w", err)
+		}
+	}
+	return sendErr
+}
@@ -0,0 +1,46 @@
+// Package sqs implements ports.TaskQueue (pkg/ports in this repository) using
+// Amazon SQS standard and FIFO queues, for worker fleets running on AWS
+// Lambda, ECS or EKS.
+//
+// Message structure:
+//   - The message body is the payload. Payloads SQS doesn't accept as text
+//     are sent base64-encoded, flagged by an "encoding" message attribute.
+//   - Headers, if any, are a JSON-encoded "headers" message attribute
+//   - On FIFO queues (names ending in .fifo), the HeaderMessageGroupID and
+//     HeaderDeduplicationID headers set the message group and deduplication ID
+//
+// Delivery is at least once and relies on the visibility timeout: Consume
+// receives up to ten tasks per ReceiveMessage call, long polling for up to
+// twenty seconds, and hides them from other consumers. Ack deletes them;
+// tasks not acknowledged in time are delivered again by SQS itself, so
+// ClaimPending has nothing to do. ChangeVisibility extends the timeout of a
+// long-running task, or hands a task back right away.
+//
+// Tasks that keep failing are moved to a dead-letter queue named after the
+// queue (see DeadLetterQueueName), either by Consume once they exceed
+// SetMaxDeliveries, or by a redrive policy configured on the queue with the
+// same target. ListDeadLetters and Requeue inspect them and send them back
+// once the cause is fixed.
+//
+// SQS has no consumer groups. All consumers of a queue share its tasks, so
+// ConsumeRequest.Group and Consumer are ignored; fan out to several groups by
+// subscribing one queue per group to an SNS topic.
+//
+// Usage:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	queue := sqs.NewQueue(awssqs.NewFromConfig(cfg), logger)
+//	queue.SetVisibilityTimeout(5 * time.Minute)
+//
+//	ids, err := queue.PublishBatch(ctx, "executor-work", tasks)
+//
+//	tasks, err := queue.Consume(ctx, ports.ConsumeRequest{
+//		Queue: "executor-work",
+//		Count: 10,
+//		Block: 20 * time.Second,
+//	})
+//	for _, task := range tasks {
+//		process(task)
+//		_ = queue.Ack(ctx, task.Queue, "", task.Receipt)
+//	}
+package sqs
@@ -0,0 +1,492 @@
+package sqs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// Maximum entries per SendMessageBatch, ReceiveMessage and DeleteMessageBatch
+	maxBatchSize = 10
+
+	// Maximum long polling wait of ReceiveMessage
+	maxWaitTime = 20 * time.Second
+
+	// Message group of FIFO tasks that don't set HeaderMessageGroupID
+	defaultMessageGroupID = "default"
+
+	// Message attribute names
+	attrHeaders    = "headers"
+	attrEncoding   = "encoding"
+	attrDeliveries = "deliveries"
+
+	// Encodings of payloads that aren't valid message bodies
+	encodingBase64 = "base64"
+	encodingEmpty  = "empty"
+	emptyBody      = "-"
+)
+
+// Task headers that control FIFO delivery. They are passed to SQS as message
+// parameters and not delivered with the task.
+const (
+	// HeaderMessageGroupID orders tasks: tasks of the same group are delivered
+	// in order, one group at a time. Defaults to a single group.
+	HeaderMessageGroupID = "sqs-message-group-id"
+
+	// HeaderDeduplicationID drops tasks published again with the same ID
+	// within five minutes. Defaults to a random ID, i.e. no deduplication.
+	HeaderDeduplicationID = "sqs-deduplication-id"
+)
+
+// Client is the subset of the SQS API used by the queue.
+// It is satisfied by *sqs.Client.
+type Client interface {
+	GetQueueUrl(ctx context.Context, params *awssqs.GetQueueUrlInput, optFns ...func(*awssqs.Options)) (*awssqs.GetQueueUrlOutput, error)
+	SendMessageBatch(ctx context.Context, params *awssqs.SendMessageBatchInput, optFns ...func(*awssqs.Options)) (*awssqs.SendMessageBatchOutput, error)
+	ReceiveMessage(ctx context.Context, params *awssqs.ReceiveMessageInput, optFns ...func(*awssqs.Options)) (*awssqs.ReceiveMessageOutput, error)
+	DeleteMessageBatch(ctx context.Context, params *awssqs.DeleteMessageBatchInput, optFns ...func(*awssqs.Options)) (*awssqs.DeleteMessageBatchOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *awssqs.ChangeMessageVisibilityInput, optFns ...func(*awssqs.Options)) (*awssqs.ChangeMessageVisibilityOutput, error)
+}
+
+// Queue implements ports.TaskQueue using Amazon SQS standard and FIFO queues.
+// Queues are addressed by name, resolved to their URL on first use, or
+// directly by URL. FIFO queues are recognized by their .fifo suffix.
+type Queue struct {
+	client Client
+	logger *zap.Logger
+
+	// Visibility timeout of received tasks, see SetVisibilityTimeout
+	visibilityTimeout time.Duration
+
+	// Deliveries after which tasks are dead-lettered, see SetMaxDeliveries
+	maxDeliveries int64
+
+	// Resolved queue URLs by name
+	mu   sync.Mutex
+	urls map[string]string
+}
+
+// NewQueue creates a new SQS task queue
+func NewQueue(client Client, logger *zap.Logger) *Queue {
+	return &Queue{
+		client: client,
+		logger: logger,
+		urls:   make(map[string]string),
+	}
+}
+
+// SetVisibilityTimeout sets how long received tasks stay hidden from other
+// consumers before they are delivered again; zero uses the queue's setting.
+// SQS accepts whole seconds up to 12 hours. Call it before the queue is in use.
+func (q *Queue) SetVisibilityTimeout(timeout time.Duration) {
+	q.visibilityTimeout = timeout
+}
+
+// SetMaxDeliveries dead-letters tasks that were received maxDeliveries times
+// without being acknowledged: instead of returning them again, Consume moves
+// them to the queue's dead-letter queue, which must exist (see
+// DeadLetterQueueName). Zero, the default, leaves redelivery to the queue's
+// redrive policy, if any. Call it before the queue is in use.
+func (q *Queue) SetMaxDeliveries(maxDeliveries int64) {
+	q.maxDeliveries = maxDeliveries
+}
+
+// Publish sends a task to the queue (ports.TaskQueue interface)
+func (q *Queue) Publish(ctx context.Context, queue string, task ports.Task) (string, error) {
+	ids, err := q.PublishBatch(ctx, queue, []ports.Task{task})
+	if err != nil {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// PublishBatch sends tasks to the queue in batches of ten, and returns their
+// IDs in order. Tasks that failed have an empty ID and are reported in the
+// error.
+func (q *Queue) PublishBatch(ctx context.Context, queue string, tasks []ports.Task) ([]string, error) {
+	url, err := q.queueURL(ctx, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]types.SendMessageBatchRequestEntry, len(tasks))
+	for i, task := range tasks {
+		if entries[i], err = sendEntry(queue, strconv.Itoa(i), task); err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := q.send(ctx, url, entries)
+
+	q.logger.Debug("tasks published",
+		zap.String("queue", queue),
+		zap.Int("count", len(tasks)))
+
+	return ids, err
+}
+
+// Consume receives up to Count tasks (ports.TaskQueue interface), long polling
+// for up to Block, at most 20 seconds. SQS has no consumer groups: every
+// consumer of a queue competes for its tasks, so Group and Consumer are
+// ignored and each group needs a queue of its own, e.g. fanned out by SNS.
+// Tasks that reached the maximum number of deliveries are dead-lettered
+// instead of returned.
+func (q *Queue) Consume(ctx context.Context, req ports.ConsumeRequest) ([]ports.Task, error) {
+	url, err := q.queueURL(ctx, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+
+	received, err := q.receive(ctx, url, req.Queue, req.Count, req.Block, q.visibilityTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume tasks: %w", err)
+	}
+	if q.maxDeliveries <= 0 {
+		return received, nil
+	}
+
+	tasks := make([]ports.Task, 0, len(received))
+	var dead []ports.Task
+	for _, task := range received {
+		if task.Deliveries > q.maxDeliveries {
+			task.Deliveries--
+			dead = append(dead, task)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	if len(dead) > 0 {
+		if err := q.deadLetter(ctx, url, req.Queue, dead); err != nil {
+			// They are received again once their visibility timeout expires
+			q.logger.Warn("failed to dead-letter tasks",
+				zap.String("queue", req.Queue),
+				zap.Error(err))
+		}
+	}
+
+	return tasks, nil
+}
+
+// Ack deletes delivered tasks by receipt handle (ports.TaskQueue interface).
+// The group is ignored.
+func (q *Queue) Ack(ctx context.Context, queue, group string, receipts ...string) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	url, err := q.queueURL(ctx, queue)
+	if err != nil {
+		return err
+	}
+
+	if err := q.delete(ctx, url, receipts); err != nil {
+		return fmt.Errorf("failed to ack tasks: %w", err)
+	}
+	return nil
+}
+
+// ClaimPending returns no tasks (ports.TaskQueue interface). SQS redelivers
+// tasks left unacknowledged by itself once their visibility timeout expires,
+// and Consume returns them with Deliveries incremented.
+func (q *Queue) ClaimPending(ctx context.Context, req ports.ClaimRequest) ([]ports.Task, error) {
+	return nil, nil
+}
+
+// ChangeVisibility hides a delivered task from other consumers for timeout
+// from now, extending the deadline of a long-running task. Zero makes it
+// visible again immediately, handing it back for another consumer.
+func (q *Queue) ChangeVisibility(ctx context.Context, queue, receipt string, timeout time.Duration) error {
+	url, err := q.queueURL(ctx, queue)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.ChangeMessageVisibility(ctx, &awssqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(url),
+		ReceiptHandle:     aws.String(receipt),
+		VisibilityTimeout: int32(timeout / time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change task visibility: %w", err)
+	}
+	return nil
+}
+
+// send sends entries in batches of ten and returns the message IDs in order
+func (q *Queue) send(ctx context.Context, url string, entries []types.SendMessageBatchRequestEntry) ([]string, error) {
+	ids := make([]string, len(entries))
+	index := make(map[string]int, len(entries))
+	var errs []error
+
+	for start := 0; start < len(entries); start += maxBatchSize {
+		batch := entries[start:min(start+maxBatchSize, len(entries))]
+		for i, entry := range batch {
+			index[aws.ToString(entry.Id)] = start + i
+		}
+
+		out, err := q.client.SendMessageBatch(ctx, &awssqs.SendMessageBatchInput{
+			QueueUrl: aws.String(url),
+			Entries:  batch,
+		})
+		if err != nil {
+			return ids, fmt.Errorf("failed to publish tasks: %w", err)
+		}
+
+		for _, result := range out.Successful {
+			if i, ok := index[aws.ToString(result.Id)]; ok {
+				ids[i] = aws.ToString(result.MessageId)
+			}
+		}
+		for _, failed := range out.Failed {
+			errs = append(errs, batchError(failed))
+		}
+	}
+
+	if len(errs) > 0 {
+		return ids, fmt.Errorf("failed to publish tasks: %w", errors.Join(errs...))
+	}
+	return ids, nil
+}
+
+// delete deletes messages by receipt handle in batches of ten
+func (q *Queue) delete(ctx context.Context, url string, receipts []string) error {
+	var errs []error
+	for start := 0; start < len(receipts); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(receipts))
+
+		entries := make([]types.DeleteMessageBatchRequestEntry, 0, end-start)
+		for i := start; i < end; i++ {
+			entries = append(entries, types.DeleteMessageBatchRequestEntry{
+				Id:            aws.String(strconv.Itoa(i)),
+				ReceiptHandle: aws.String(receipts[i]),
+			})
+		}
+
+		out, err := q.client.DeleteMessageBatch(ctx, &awssqs.DeleteMessageBatchInput{
+			QueueUrl: aws.String(url),
+			Entries:  entries,
+		})
+		if err != nil {
+			return err
+		}
+		for _, failed := range out.Failed {
+			errs = append(errs, batchError(failed))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// receive receives up to count tasks, waiting up to wait for the first ones
+func (q *Queue) receive(ctx context.Context, url, queue string, count int, wait, visibilityTimeout time.Duration) ([]ports.Task, error) {
+	if count <= 0 {
+		count = 1
+	}
+	wait = max(min(wait, maxWaitTime), 0)
+
+	var tasks []ports.Task
+	for len(tasks) < count {
+		batch := int32(min(count-len(tasks), maxBatchSize))
+
+		out, err := q.client.ReceiveMessage(ctx, &awssqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(url),
+			MaxNumberOfMessages: batch,
+			WaitTimeSeconds:     int32((wait + time.Second - 1) / time.Second),
+			VisibilityTimeout:   int32(visibilityTimeout / time.Second),
+			MessageAttributeNames: []string{
+				attrHeaders,
+				attrEncoding,
+				attrDeliveries,
+			},
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+				types.MessageSystemAttributeNameApproximateReceiveCount,
+				types.MessageSystemAttributeNameSentTimestamp,
+			},
+		})
+		if err != nil {
+			if len(tasks) > 0 {
+				// Return what was received; it is hidden from other consumers
+				break
+			}
+			return nil, err
+		}
+
+		for _, msg := range out.Messages {
+			tasks = append(tasks, taskFromMessage(queue, msg))
+		}
+
+		// Only wait for the first batch, then drain what is already there
+		if len(out.Messages) < int(batch) {
+			break
+		}
+		wait = 0
+	}
+
+	return tasks, nil
+}
+
+// queueURL resolves a queue name to its URL; URLs are used as is
+func (q *Queue) queueURL(ctx context.Context, queue string) (string, error) {
+	if strings.HasPrefix(queue, "https://") || strings.HasPrefix(queue, "http://") {
+		return queue, nil
+	}
+
+	q.mu.Lock()
+	url, ok := q.urls[queue]
+	q.mu.Unlock()
+	if ok {
+		return url, nil
+	}
+
+	out, err := q.client.GetQueueUrl(ctx, &awssqs.GetQueueUrlInput{
+		QueueName: aws.String(queue),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve queue URL: %w", err)
+	}
+	url = aws.ToString(out.QueueUrl)
+
+	q.mu.Lock()
+	q.urls[queue] = url
+	q.mu.Unlock()
+	return url, nil
+}
+
+// sendEntry builds the batch entry of a task. Payloads that SQS doesn't
+// accept as a message body are sent base64-encoded.
+func sendEntry(queue, id string, task ports.Task) (types.SendMessageBatchRequestEntry, error) {
+	entry := types.SendMessageBatchRequestEntry{
+		Id:                aws.String(id),
+		MessageAttributes: make(map[string]types.MessageAttributeValue),
+	}
+
+	body := string(task.Payload)
+	switch {
+	case body == "":
+		// SQS rejects empty bodies
+		body = emptyBody
+		entry.MessageAttributes[attrEncoding] = stringAttribute(encodingEmpty)
+	case !validBody(body):
+		body = base64.StdEncoding.EncodeToString(task.Payload)
+		entry.MessageAttributes[attrEncoding] = stringAttribute(encodingBase64)
+	}
+	entry.MessageBody = aws.String(body)
+
+	headers := make(map[string]string, len(task.Headers))
+	for k, v := range task.Headers {
+		if k != HeaderMessageGroupID && k != HeaderDeduplicationID {
+			headers[k] = v
+		}
+	}
+	if len(headers) > 0 {
+		encoded, err := json.Marshal(headers)
+		if err != nil {
+			return entry, fmt.Errorf("failed to marshal task headers: %w", err)
+		}
+		entry.MessageAttributes[attrHeaders] = stringAttribute(string(encoded))
+	}
+
+	if isFIFO(queue) {
+		groupID := task.Headers[HeaderMessageGroupID]
+		if groupID == "" {
+			groupID = defaultMessageGroupID
+		}
+		dedupID := task.Headers[HeaderDeduplicationID]
+		if dedupID == "" {
+			dedupID = randomID()
+		}
+		entry.MessageGroupId = aws.String(groupID)
+		entry.MessageDeduplicationId = aws.String(dedupID)
+	}
+
+	return entry, nil
+}
+
+func taskFromMessage(queue string, msg types.Message) ports.Task {
+	task := ports.Task{
+		ID:      aws.ToString(msg.MessageId),
+		Queue:   queue,
+		Payload: []byte(aws.ToString(msg.Body)),
+		Receipt: aws.ToString(msg.ReceiptHandle),
+	}
+
+	switch aws.ToString(msg.MessageAttributes[attrEncoding].StringValue) {
+	case encodingBase64:
+		if payload, err := base64.StdEncoding.DecodeString(aws.ToString(msg.Body)); err == nil {
+			task.Payload = payload
+		}
+	case encodingEmpty:
+		task.Payload = nil
+	}
+	if attr, ok := msg.MessageAttributes[attrHeaders]; ok {
+		_ = json.Unmarshal([]byte(aws.ToString(attr.StringValue)), &task.Headers)
+	}
+
+	if count, err := strconv.ParseInt(msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)], 10, 64); err == nil {
+		task.Deliveries = count
+	}
+	// Dead-lettered tasks report the deliveries before they were dead-lettered
+	if count, err := strconv.ParseInt(aws.ToString(msg.MessageAttributes[attrDeliveries].StringValue), 10, 64); err == nil {
+		task.Deliveries = count
+	}
+	if millis, err := strconv.ParseInt(msg.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+		task.EnqueuedAt = time.UnixMilli(millis)
+	}
+
+	return task
+}
+
+// validBody reports whether SQS accepts s as a message body: Unicode text without control characters other than tab, newline and
+// carriage return
+func validBody(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return false
+		}
+		if r == 0xFFFE || r == 0xFFFF {
+			return false
+		}
+	}
+	return true
+}
+
+func isFIFO(queue string) bool {
+	return strings.HasSuffix(queue, ".fifo")
+}
+
+func stringAttribute(value string) types.MessageAttributeValue {
+	return types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func batchError(entry types.BatchResultErrorEntry) error {
+	return fmt.Errorf("entry %s: %s: %s", aws.ToString(entry.Id), aws.ToString(entry.Code), aws.ToString(entry.Message))
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
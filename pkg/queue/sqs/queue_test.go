@@ -0,0 +1,352 @@
+package sqs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.uber.org/zap"
+)
+
+var _ Client = (*awssqs.Client)(nil)
+
+// fakeMessage is a message held by fakeClient
+type fakeMessage struct {
+	id        string
+	entry     types.SendMessageBatchRequestEntry
+	sentAt    time.Time
+	receives  int
+	visibleAt time.Time
+	receipt   string
+	deleted   bool
+}
+
+// fakeClient is an in-memory SQS with visibility timeouts
+type fakeClient struct {
+	mu      sync.Mutex
+	queues  map[string][]*fakeMessage
+	next    int
+	sends   int
+	lookups int
+}
+
+func (f *fakeClient) GetQueueUrl(ctx context.Context, params *awssqs.GetQueueUrlInput, optFns ...func(*awssqs.Options)) (*awssqs.GetQueueUrlOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lookups++
+	return &awssqs.GetQueueUrlOutput{QueueUrl: aws.String("https://sqs.local/000000000000/" + aws.ToString(params.QueueName))}, nil
+}
+
+func (f *fakeClient) SendMessageBatch(ctx context.Context, params *awssqs.SendMessageBatchInput, optFns ...func(*awssqs.Options)) (*awssqs.SendMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(params.Entries) > maxBatchSize {
+		return nil, fmt.Errorf("too many entries: %d", len(params.Entries))
+	}
+	f.sends++
+
+	if f.queues == nil {
+		f.queues = make(map[string][]*fakeMessage)
+	}
+	url := aws.ToString(params.QueueUrl)
+
+	out := &awssqs.SendMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		id := "msg-" + strconv.Itoa(f.next)
+		f.next++
+		f.queues[url] = append(f.queues[url], &fakeMessage{id: id, entry: entry, sentAt: time.Now()})
+		out.Successful = append(out.Successful, types.SendMessageBatchResultEntry{Id: entry.Id, MessageId: aws.String(id)})
+	}
+	return out, nil
+}
+
+func (f *fakeClient) ReceiveMessage(ctx context.Context, params *awssqs.ReceiveMessageInput, optFns ...func(*awssqs.Options)) (*awssqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	out := &awssqs.ReceiveMessageOutput{}
+	for _, msg := range f.queues[aws.ToString(params.QueueUrl)] {
+		if len(out.Messages) == int(params.MaxNumberOfMessages) {
+			break
+		}
+		if msg.deleted || now.Before(msg.visibleAt) {
+			continue
+		}
+
+		msg.receives++
+		msg.receipt = fmt.Sprintf("%s/%d", msg.id, msg.receives)
+		msg.visibleAt = now.Add(time.Duration(params.VisibilityTimeout) * time.Second)
+		if params.VisibilityTimeout == 0 {
+			msg.visibleAt = now.Add(30 * time.Second)
+		}
+
+		out.Messages = append(out.Messages, types.Message{
+			MessageId:         aws.String(msg.id),
+			Body:              msg.entry.MessageBody,
+			ReceiptHandle:     aws.String(msg.receipt),
+			MessageAttributes: msg.entry.MessageAttributes,
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": strconv.Itoa(msg.receives),
+				"SentTimestamp":           strconv.FormatInt(msg.sentAt.UnixMilli(), 10),
+			},
+		})
+	}
+	return out, nil
+}
+
+func (f *fakeClient) DeleteMessageBatch(ctx context.Context, params *awssqs.DeleteMessageBatchInput, optFns ...func(*awssqs.Options)) (*awssqs.DeleteMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &awssqs.DeleteMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		if msg := f.byReceipt(aws.ToString(params.QueueUrl), aws.ToString(entry.ReceiptHandle)); msg != nil {
+			msg.deleted = true
+			continue
+		}
+		out.Failed = append(out.Failed, types.BatchResultErrorEntry{
+			Id:      entry.Id,
+			Code:    aws.String("ReceiptHandleIsInvalid"),
+			Message: aws.String("invalid receipt handle"),
+		})
+	}
+	return out, nil
+}
+
+func (f *fakeClient) ChangeMessageVisibility(ctx context.Context, params *awssqs.ChangeMessageVisibilityInput, optFns ...func(*awssqs.Options)) (*awssqs.ChangeMessageVisibilityOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	msg := f.byReceipt(aws.ToString(params.QueueUrl), aws.ToString(params.ReceiptHandle))
+	if msg == nil {
+		return nil, fmt.Errorf("invalid receipt handle")
+	}
+	msg.visibleAt = time.Now().Add(time.Duration(params.VisibilityTimeout) * time.Second)
+	return &awssqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeClient) byReceipt(url, receipt string) *fakeMessage {
+	for _, msg := range f.queues[url] {
+		if msg.receipt == receipt && !msg.deleted {
+			return msg
+		}
+	}
+	return nil
+}
+
+func TestQueue_PublishConsumeAck(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{}
+	q := NewQueue(client, zap.NewNop())
+
+	payloads := [][]byte{
+		[]byte(`{"execution_id":"exec-1"}`),
+		{0x00, 0xff, 0x10},
+		nil,
+	}
+	for i, payload := range payloads {
+		_, err := q.Publish(ctx, "executor-work", ports.Task{
+			Payload: payload,
+			Headers: map[string]string{"type": "execute", "index": strconv.Itoa(i)},
+		})
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if client.lookups != 1 {
+		t.Errorf("queue URL resolved %d times, want 1", client.lookups)
+	}
+
+	tasks, err := q.Consume(ctx, ports.ConsumeRequest{Queue: "executor-work", Count: 10})
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if len(tasks) != len(payloads) {
+		t.Fatalf("Consume() returned %d tasks, want %d", len(tasks), len(payloads))
+	}
+	for i, task := range tasks {
+		if !bytes.Equal(task.Payload, payloads[i]) {
+			t.Errorf("task %d payload = %q, want %q", i, task.Payload, payloads[i])
+		}
+		if task.Headers["index"] != strconv.Itoa(i) || task.Deliveries != 1 || task.EnqueuedAt.IsZero() {
+			t.Errorf("task %d = %+v", i, task)
+		}
+	}
+
+	receipts := make([]string, len(tasks))
+	for i, task := range tasks {
+		receipts[i] = task.Receipt
+	}
+	if err := q.Ack(ctx, "executor-work", "", receipts...); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	if err := q.Ack(ctx, "executor-work", "", receipts[0]); err == nil {
+		t.Error("Ack() of a deleted task succeeded")
+	}
+}
+
+func TestQueue_Batches(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{}
+	q := NewQueue(client, zap.NewNop())
+
+	tasks := make([]ports.Task, 25)
+	for i := range tasks {
+		tasks[i].Payload = []byte(strconv.Itoa(i))
+	}
+
+	ids, err := q.PublishBatch(ctx, "executor-work", tasks)
+	if err != nil {
+		t.Fatalf("PublishBatch() error = %v", err)
+	}
+	if client.sends != 3 || ids[24] != "msg-24" {
+		t.Errorf("PublishBatch() sent %d batches, ids[24] = %q", client.sends, ids[24])
+	}
+
+	consumed, err := q.Consume(ctx, ports.ConsumeRequest{Queue: "executor-work", Count: 25})
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if len(consumed) != 25 {
+		t.Errorf("Consume() returned %d tasks, want 25", len(consumed))
+	}
+}
+
+func TestQueue_Redelivery(t *testing.T) {
+	ctx := context.Background()
+	q := NewQueue(&fakeClient{}, zap.NewNop())
+	q.SetVisibilityTimeout(time.Minute)
+
+	if _, err := q.Publish(ctx, "executor-work", ports.Task{Payload: []byte("task")}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	first, err := q.Consume(ctx, ports.ConsumeRequest{Queue: "executor-work", Count: 1})
+	if err != nil || len(first) != 1 {
+		t.Fatalf("Consume() = %v, %v", first, err)
+	}
+
+	// Hidden while the first consumer holds it
+	if again, _ := q.Consume(ctx, ports.ConsumeRequest{Queue: "executor-work", Count: 1}); len(again) != 0 {
+		t.Fatalf("Consume() redelivered a task within its visibility timeout")
+	}
+
+	// Handing it back makes it visible to the next consumer
+	if err := q.ChangeVisibility(ctx, "executor-work", first[0].Receipt, 0); err != nil {
+		t.Fatalf("ChangeVisibility() error = %v", err)
+	}
+	second, err := q.Consume(ctx, ports.ConsumeRequest{Queue: "executor-work", Count: 1})
+	if err != nil || len(second) != 1 {
+		t.Fatalf("Consume() = %v, %v", second, err)
+	}
+	if second[0].Deliveries != 2 || second[0].ID != first[0].ID {
+		t.Errorf("redelivered task = %+v, want second delivery of %s", second[0], first[0].ID)
+	}
+}
+
+func TestSendEntry_FIFO(t *testing.T) {
+	task := ports.Task{
+		Payload: []byte("task"),
+		Headers: map[string]string{
+			HeaderMessageGroupID:  "exec-1",
+			HeaderDeduplicationID: "task-1",
+			"type":                "execute",
+		},
+	}
+
+	entry, err := sendEntry("executor-work.fifo", "0", task)
+	if err != nil {
+		t.Fatalf("sendEntry() error = %v", err)
+	}
+	if aws.ToString(entry.MessageGroupId) != "exec-1" || aws.ToString(entry.MessageDeduplicationId) != "task-1" {
+		t.Errorf("sendEntry() group = %v, dedup = %v", aws.ToString(entry.MessageGroupId), aws.ToString(entry.MessageDeduplicationId))
+	}
+	if headers := aws.ToString(entry.MessageAttributes[attrHeaders].StringValue); headers != `{"type":"execute"}` {
+		t.Errorf("sendEntry() headers = %s, want FIFO headers stripped", headers)
+	}
+
+	// Defaults keep FIFO tasks in one group without deduplicating them
+	a, _ := sendEntry("executor-work.fifo", "0", ports.Task{Payload: []byte("task")})
+	b, _ := sendEntry("executor-work.fifo", "1", ports.Task{Payload: []byte("task")})
+	if aws.ToString(a.MessageGroupId) != defaultMessageGroupID || aws.ToString(a.MessageDeduplicationId) == aws.ToString(b.MessageDeduplicationId) {
+		t.Errorf("sendEntry() defaults = %v/%v and %v", aws.ToString(a.MessageGroupId), aws.ToString(a.MessageDeduplicationId), aws.ToString(b.MessageDeduplicationId))
+	}
+
+	standard, _ := sendEntry("executor-work", "0", task)
+	if standard.MessageGroupId != nil || standard.MessageDeduplicationId != nil {
+		t.Error("sendEntry() set FIFO parameters on a standard queue")
+	}
+}
+
+func TestQueue_DeadLetter(t *testing.T) {
+	ctx := context.Background()
+	q := NewQueue(&fakeClient{}, zap.NewNop())
+	q.SetMaxDeliveries(2)
+
+	if _, err := q.Publish(ctx, "executor-work", ports.Task{
+		Payload: []byte("poison"),
+		Headers: map[string]string{"type": "execute"},
+	}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// Each consumer gives the task back; the third receive dead-letters it
+	consume := ports.ConsumeRequest{Queue: "executor-work", Count: 1}
+	for delivery := 1; delivery <= 3; delivery++ {
+		tasks, err := q.Consume(ctx, consume)
+		if err != nil {
+			t.Fatalf("Consume() error = %v", err)
+		}
+		if delivery == 3 {
+			if len(tasks) != 0 {
+				t.Fatalf("Consume() = %+v, want the task dead-lettered", tasks)
+			}
+			break
+		}
+		if len(tasks) != 1 || tasks[0].Deliveries != int64(delivery) {
+			t.Fatalf("Consume() = %+v, want delivery %d", tasks, delivery)
+		}
+		if err := q.ChangeVisibility(ctx, "executor-work", tasks[0].Receipt, 0); err != nil {
+			t.Fatalf("ChangeVisibility() error = %v", err)
+		}
+	}
+
+	dead, err := q.ListDeadLetters(ctx, "executor-work", 10)
+	if err != nil {
+		t.Fatalf("ListDeadLetters() error = %v", err)
+	}
+	if len(dead) != 1 || string(dead[0].Payload) != "poison" || dead[0].Headers["type"] != "execute" || dead[0].Deliveries != 2 {
+		t.Fatalf("ListDeadLetters() = %+v, want the poison task delivered twice", dead)
+	}
+
+	if err := q.Requeue(ctx, dead...); err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+	tasks, err := q.Consume(ctx, consume)
+	if err != nil || len(tasks) != 1 || string(tasks[0].Payload) != "poison" || tasks[0].Deliveries != 1 {
+		t.Errorf("Consume() after requeue = %+v, %v, want the task as new", tasks, err)
+	}
+}
+
+func TestDeadLetterQueueName(t *testing.T) {
+	tests := map[string]string{
+		"executor-work":      "executor-work-dead",
+		"executor-work.fifo": "executor-work-dead.fifo",
+		"https://sqs.eu-west-1.amazonaws.com/123456789012/router.fifo": "https://sqs.eu-west-1.amazonaws.com/123456789012/router-dead.fifo",
+	}
+	for queue, want := range tests {
+		if got := DeadLetterQueueName(queue); got != want {
+			t.Errorf("DeadLetterQueueName(%q) = %q, want %q", queue, got, want)
+		}
+	}
+}
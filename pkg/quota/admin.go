@@ -0,0 +1,193 @@
+package quota
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Default window of GET /usage, if no rule sets one
+const defaultListWindow = time.Hour
+
+// AdminHandler serves an HTTP API to inspect and reset the usage counted by
+// an enforcer:
+//
+//   - GET /usage?window=1h, the usage of every tenant and model over the
+//     window (by default the longest window of the rules)
+//   - GET /usage/{tenant}?model=m, the tenant's usage against each of its
+//     rules
+//   - DELETE /usage/{tenant}?model=m, clearing the tenant's counters of
+//     model, or of all models without one
+//
+// Mount it under a prefix with http.StripPrefix, and keep it off the
+// network the gateway serves: it reveals and resets every tenant's usage.
+type AdminHandler struct {
+	enforcer *Enforcer
+	apiKeys  [][]byte
+	mux      *http.ServeMux
+	logger   *zap.Logger
+}
+
+// NewAdminHandler creates the admin API of enforcer. Without SetAPIKeys, it
+// accepts unauthenticated requests.
+func NewAdminHandler(enforcer *Enforcer, logger *zap.Logger) *AdminHandler {
+	h := &AdminHandler{
+		enforcer: enforcer,
+		mux:      http.NewServeMux(),
+		logger:   logger,
+	}
+	h.mux.HandleFunc("GET /usage", h.listUsage)
+	h.mux.HandleFunc("GET /usage/{tenant}", h.tenantUsage)
+	h.mux.HandleFunc("DELETE /usage/{tenant}", h.resetUsage)
+	return h
+}
+
+// SetAPIKeys sets the keys accepted as bearer tokens. No keys disables
+// authentication.
+func (h *AdminHandler) SetAPIKeys(keys ...string) {
+	h.apiKeys = nil
+	for _, key := range keys {
+		h.apiKeys = append(h.apiKeys, []byte(key))
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorized checks the bearer token against the API keys in constant time
+func (h *AdminHandler) authorized(r *http.Request) bool {
+	if len(h.apiKeys) == 0 {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	match := 0
+	for _, key := range h.apiKeys {
+		match |= subtle.ConstantTimeCompare([]byte(token), key)
+	}
+	return match == 1
+}
+
+// usageList is the response of GET /usage
+type usageList struct {
+	Window string             `json:"window"`
+	Usage  []ports.QuotaUsage `json:"usage"`
+}
+
+// tenantStatus is the response of GET /usage/{tenant}
+type tenantStatus struct {
+	Tenant string        `json:"tenant"`
+	Model  string        `json:"model,omitempty"`
+	Quotas []quotaStatus `json:"quotas"`
+}
+
+// quotaStatus is the usage of a tenant against a rule
+type quotaStatus struct {
+	Model         string `json:"model,omitempty"`
+	Window        string `json:"window"`
+	Requests      int64  `json:"requests"`
+	Tokens        int64  `json:"tokens"`
+	RequestsLimit int64  `json:"requests_limit,omitempty"`
+	TokensLimit   int64  `json:"tokens_limit,omitempty"`
+	Exceeded      bool   `json:"exceeded"`
+}
+
+func (h *AdminHandler) listUsage(w http.ResponseWriter, r *http.Request) {
+	window := h.listWindow()
+	if s := r.URL.Query().Get("window"); s != "" {
+		var err error
+		if window, err = time.ParseDuration(s); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid window: %v", err))
+			return
+		}
+	}
+
+	usage, err := h.enforcer.Store().List(r.Context(), window)
+	if err != nil {
+		h.writeStoreError(w, err)
+		return
+	}
+	if usage == nil {
+		usage = []ports.QuotaUsage{}
+	}
+	writeJSON(w, http.StatusOK, usageList{Window: window.String(), Usage: usage})
+}
+
+func (h *AdminHandler) tenantUsage(w http.ResponseWriter, r *http.Request) {
+	tenant, model := r.PathValue("tenant"), r.URL.Query().Get("model")
+	statuses, err := h.enforcer.Status(r.Context(), tenant, model)
+	if err != nil {
+		h.writeStoreError(w, err)
+		return
+	}
+
+	resp := tenantStatus{Tenant: tenant, Model: model, Quotas: []quotaStatus{}}
+	for _, s := range statuses {
+		resp.Quotas = append(resp.Quotas, quotaStatus{
+			Model:         s.Rule.Model,
+			Window:        s.Rule.Window.String(),
+			Requests:      s.Usage.Requests,
+			Tokens:        s.Usage.Tokens,
+			RequestsLimit: s.Rule.Requests,
+			TokensLimit:   s.Rule.Tokens,
+			Exceeded:      s.Exceeded,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) resetUsage(w http.ResponseWriter, r *http.Request) {
+	tenant, model := r.PathValue("tenant"), r.URL.Query().Get("model")
+	if err := h.enforcer.Store().Reset(r.Context(), tenant, model); err != nil {
+		h.writeStoreError(w, err)
+		return
+	}
+	h.logger.Info("quota usage reset", zap.String("tenant", tenant), zap.String("model", model))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listWindow is the longest window of the rules
+func (h *AdminHandler) listWindow() time.Duration {
+	var window time.Duration
+	for _, rule := range h.enforcer.Rules() {
+		window = max(window, rule.Window)
+	}
+	if window == 0 {
+		return defaultListWindow
+	}
+	return window
+}
+
+func (h *AdminHandler) writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ports.ErrInvalidRequest) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.logger.Error("quota store failed", zap.Error(err))
+	writeError(w, http.StatusBadGateway, err.Error())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
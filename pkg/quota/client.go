@@ -0,0 +1,135 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// LLMClient wraps an LLM client, refusing calls of tenants over their
+// quotas and counting the requests and tokens of the others. The tenant of
+// a call is read from its context (see WithTenant).
+type LLMClient struct {
+	client   libports.LLMClient
+	enforcer *Enforcer
+}
+
+// NewLLMClient wraps client, enforcing the quotas of enforcer
+func NewLLMClient(client libports.LLMClient, enforcer *Enforcer) *LLMClient {
+	return &LLMClient{
+		client:   client,
+		enforcer: enforcer,
+	}
+}
+
+// Complete performs a standard text completion, within quota
+// (ports.LLMClient interface)
+func (c *LLMClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	tenant, err := c.check(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Complete(ctx, req)
+	var usage libports.UsageInfo
+	if resp != nil {
+		usage = resp.Usage
+	}
+	c.record(ctx, tenant, req.Model, usage)
+	return resp, err
+}
+
+// CompleteWithTools performs a completion with tool calling support,
+// within quota (ports.LLMClient interface)
+func (c *LLMClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	tenant, err := c.check(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.CompleteWithTools(ctx, req, tools)
+	var usage libports.UsageInfo
+	if resp != nil {
+		usage = resp.Usage
+	}
+	c.record(ctx, tenant, req.Model, usage)
+	return resp, err
+}
+
+// CompleteStructured performs a completion with guaranteed JSON schema
+// conformance, within quota (ports.LLMClient interface)
+func (c *LLMClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	tenant, err := c.check(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.CompleteStructured(ctx, req, schema)
+	var usage libports.UsageInfo
+	if resp != nil {
+		usage = resp.Usage
+	}
+	c.record(ctx, tenant, req.Model, usage)
+	return resp, err
+}
+
+// GenerateCompletion generates a completion using domain.LLMRequest
+// (compatibility method), within quota. Its requests and responses are
+// opaque, so it is counted as a request to DefaultModel, without tokens.
+func (c *LLMClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	tenant, err := c.check(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.GenerateCompletion(ctx, req)
+	c.record(ctx, tenant, "", libports.UsageInfo{})
+	return resp, err
+}
+
+// StreamComplete streams a completion from the wrapped client, within
+// quota. Streams don't report usage, so it is counted as a request without
+// tokens.
+func (c *LLMClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	streamer, ok := c.client.(interface {
+		StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("%w: streaming", ports.ErrNotImplemented)
+	}
+
+	tenant, err := c.check(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := streamer.StreamComplete(ctx, req)
+	c.record(ctx, tenant, req.Model, libports.UsageInfo{})
+	return chunks, err
+}
+
+// Close closes the wrapped client if it has a Close method
+func (c *LLMClient) Close() error {
+	if closer, ok := c.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// check returns the tenant of ctx, or an error if it is over a quota of
+// model
+func (c *LLMClient) check(ctx context.Context, model string) (string, error) {
+	tenant := c.enforcer.Tenant(ctx)
+	if err := c.enforcer.Check(ctx, tenant, model); err != nil {
+		return "", err
+	}
+	return tenant, nil
+}
+
+// record counts a call, failed ones included: they still cost the
+// provider's capacity. It is recorded even if the caller went away.
+func (c *LLMClient) record(ctx context.Context, tenant, model string, usage libports.UsageInfo) {
+	tokens := usage.TotalTokens
+	if tokens == 0 {
+		tokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	c.enforcer.Record(context.WithoutCancel(ctx), tenant, model, int64(tokens))
+}
@@ -0,0 +1,34 @@
+// Package quota enforces per-tenant quotas of requests and tokens over
+// rolling windows on LLM clients, so tenants of a shared deployment get a
+// fair share of the providers' capacity.
+//
+// Usage is counted in a ports.QuotaStore (pkg/quota/redis keeps it in
+// Redis, shared by every replica) by an Enforcer, which refuses the calls
+// of tenants over a rule's limit with errors wrapping
+// ports.ErrQuotaExceeded. LLMClient wraps an LLM client with an enforcer;
+// the tenant of each call is read from its context, set with WithTenant,
+// and AdminHandler serves an HTTP API to inspect and reset usage.
+//
+// Usage:
+//
+//	store := redisquota.NewStore(redisClient, logger)
+//	enforcer := quota.NewEnforcer(store, logger)
+//	enforcer.SetRules(
+//		quota.Rule{Limit: quota.Limit{Window: 24 * time.Hour, Tokens: 2_000_000}},
+//		quota.Rule{Model: quota.EachModel, Limit: quota.Limit{Window: time.Minute, Requests: 60}},
+//		quota.Rule{Tenant: "batch", Limit: quota.Limit{Window: time.Hour, Requests: 100}},
+//	)
+//	client := quota.NewLLMClient(provider, enforcer)
+//
+//	ctx = quota.WithTenant(ctx, tenantID)
+//	resp, err := client.Complete(ctx, req)
+//	if errors.Is(err, ports.ErrQuotaExceeded) {
+//		// try later
+//	}
+//
+//	go http.ListenAndServe("localhost:9090", quota.NewAdminHandler(enforcer, logger))
+//
+// The OpenAI-compatible gateway (pkg/server) answers ErrQuotaExceeded with
+// 429; set each request's tenant in a middleware wrapping it, e.g. from the
+// caller's API key.
+package quota
@@ -0,0 +1,234 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// DefaultTenant is the tenant of calls whose context carries none
+const DefaultTenant = "default"
+
+// DefaultModel is the model usage of requests without a model is counted
+// under, i.e. the provider's default model
+const DefaultModel = "default"
+
+// EachModel as a rule's model applies the rule's limit to each model
+// separately
+const EachModel = "*"
+
+type tenantKey struct{}
+
+// WithTenant returns a context whose calls are counted for tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns the tenant carried by ctx, or "" if it carries none
+func TenantFrom(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// Limit caps the usage within a rolling window. Zero caps are unlimited.
+type Limit struct {
+	Window   time.Duration
+	Requests int64
+	Tokens   int64
+}
+
+// Rule applies a limit to tenants and models
+type Rule struct {
+	// Tenant is the tenant the rule applies to; empty applies it to every
+	// tenant, each counted separately
+	Tenant string
+
+	// Model is the model the rule applies to; empty applies it to all the
+	// tenant's models together, and EachModel to each model separately
+	Model string
+
+	Limit
+}
+
+// matches reports whether r applies to tenant's calls to model
+func (r *Rule) matches(tenant, model string) bool {
+	if r.Tenant != "" && r.Tenant != tenant {
+		return false
+	}
+	return r.Model == "" || r.Model == EachModel || r.Model == model
+}
+
+// counted returns the model whose usage r limits for calls to model, ""
+// for all models
+func (r *Rule) counted(model string) string {
+	if r.Model == "" {
+		return ""
+	}
+	return model
+}
+
+// Status is the usage of a tenant against a rule
+type Status struct {
+	Rule  Rule
+	Usage ports.QuotaUsage
+
+	// Exceeded reports whether further calls are refused
+	Exceeded bool
+}
+
+// Enforcer checks calls against rules, with usage counted in a
+// ports.QuotaStore. Every rule matching a call applies, so a tenant can have
+// a token budget over all models and a request rate per model.
+//
+// Quotas are checked before calls, so concurrent calls and the tokens of
+// the last call allowed can overshoot a limit; the calls after are refused
+// until the window has moved past enough usage. If the store fails, calls
+// are allowed, and the failure logged: an outage of Redis doesn't take the
+// providers down with it.
+type Enforcer struct {
+	store         ports.QuotaStore
+	mu            sync.RWMutex
+	rules         []Rule
+	defaultTenant string
+	logger        *zap.Logger
+}
+
+// NewEnforcer creates an enforcer counting usage in store. Without rules,
+// usage is counted but no call is refused.
+func NewEnforcer(store ports.QuotaStore, logger *zap.Logger) *Enforcer {
+	return &Enforcer{
+		store:         store,
+		defaultTenant: DefaultTenant,
+		logger:        logger,
+	}
+}
+
+// SetRules replaces the rules; it is safe to call while calls are checked
+func (e *Enforcer) SetRules(rules ...Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = append([]Rule(nil), rules...)
+}
+
+// Rules returns the rules
+func (e *Enforcer) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]Rule(nil), e.rules...)
+}
+
+// SetDefaultTenant sets the tenant of calls whose context carries none
+func (e *Enforcer) SetDefaultTenant(tenant string) {
+	e.defaultTenant = tenant
+}
+
+// Store returns the store usage is counted in
+func (e *Enforcer) Store() ports.QuotaStore {
+	return e.store
+}
+
+// Tenant returns the tenant ctx's calls are counted for
+func (e *Enforcer) Tenant(ctx context.Context) string {
+	if tenant := TenantFrom(ctx); tenant != "" {
+		return tenant
+	}
+	return e.defaultTenant
+}
+
+// Check returns an error wrapping ports.ErrQuotaExceeded if tenant has used
+// up a quota applying to model
+func (e *Enforcer) Check(ctx context.Context, tenant, model string) error {
+	model = modelOrDefault(model)
+	for _, rule := range e.Rules() {
+		if !rule.matches(tenant, model) {
+			continue
+		}
+		usage, err := e.store.Usage(ctx, tenant, rule.counted(model), rule.Window)
+		if err != nil {
+			e.logger.Warn("failed to check quota, allowing call",
+				zap.String("tenant", tenant),
+				zap.String("model", model),
+				zap.Error(err))
+			continue
+		}
+		if err := exceeded(&rule, usage); err != nil {
+			e.logger.Debug("quota exceeded",
+				zap.String("tenant", tenant),
+				zap.String("model", model),
+				zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// Record counts a call of tenant to model that used tokens. Failures are
+// logged.
+func (e *Enforcer) Record(ctx context.Context, tenant, model string, tokens int64) {
+	model = modelOrDefault(model)
+	if err := e.store.Add(ctx, tenant, model, 1, tokens); err != nil {
+		e.logger.Warn("failed to record quota usage",
+			zap.String("tenant", tenant),
+			zap.String("model", model),
+			zap.Error(err))
+	}
+}
+
+// Status returns tenant's usage against each rule applying to its calls to
+// model. An empty model reports every rule of the tenant; rules applying to
+// each model separately then report the usage of all models, and are never
+// exceeded.
+func (e *Enforcer) Status(ctx context.Context, tenant, model string) ([]Status, error) {
+	var statuses []Status
+	for _, rule := range e.Rules() {
+		counted := ""
+		switch {
+		case rule.Tenant != "" && rule.Tenant != tenant:
+			continue
+		case model == "" && rule.Model != EachModel:
+			counted = rule.Model
+		case model != "":
+			if !rule.matches(tenant, model) {
+				continue
+			}
+			counted = rule.counted(model)
+		}
+
+		usage, err := e.store.Usage(ctx, tenant, counted, rule.Window)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, Status{
+			Rule:     rule,
+			Usage:    *usage,
+			Exceeded: (model != "" || rule.Model != EachModel) && exceeded(&rule, usage) != nil,
+		})
+	}
+	return statuses, nil
+}
+
+// exceeded returns the error of a call refused by rule, or nil
+func exceeded(rule *Rule, usage *ports.QuotaUsage) error {
+	scope := usage.Tenant
+	if usage.Model != "" {
+		scope += "/" + usage.Model
+	}
+	switch {
+	case rule.Requests > 0 && usage.Requests >= rule.Requests:
+		return fmt.Errorf("%w: %s made %d of %d requests in %s", ports.ErrQuotaExceeded, scope, usage.Requests, rule.Requests, rule.Window)
+	case rule.Tokens > 0 && usage.Tokens >= rule.Tokens:
+		return fmt.Errorf("%w: %s used %d of %d tokens in %s", ports.ErrQuotaExceeded, scope, usage.Tokens, rule.Tokens, rule.Window)
+	}
+	return nil
+}
+
+func modelOrDefault(model string) string {
+	if model == "" {
+		return DefaultModel
+	}
+	return model
+}
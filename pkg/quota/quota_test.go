@@ -0,0 +1,238 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// memoryStore counts usage in memory, without windows
+type memoryStore struct {
+	mu    sync.Mutex
+	usage map[[2]string]*ports.QuotaUsage
+	err   error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{usage: map[[2]string]*ports.QuotaUsage{}}
+}
+
+func (s *memoryStore) Add(ctx context.Context, tenant, model string, requests, tokens int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	u, ok := s.usage[[2]string{tenant, model}]
+	if !ok {
+		u = &ports.QuotaUsage{Tenant: tenant, Model: model}
+		s.usage[[2]string{tenant, model}] = u
+	}
+	u.Requests += requests
+	u.Tokens += tokens
+	return nil
+}
+
+func (s *memoryStore) Usage(ctx context.Context, tenant, model string, window time.Duration) (*ports.QuotaUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	usage := &ports.QuotaUsage{Tenant: tenant, Model: model}
+	for key, u := range s.usage {
+		if key[0] == tenant && (model == "" || key[1] == model) {
+			usage.Requests += u.Requests
+			usage.Tokens += u.Tokens
+		}
+	}
+	return usage, nil
+}
+
+func (s *memoryStore) List(ctx context.Context, window time.Duration) ([]ports.QuotaUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []ports.QuotaUsage
+	for _, u := range s.usage {
+		list = append(list, *u)
+	}
+	return list, nil
+}
+
+func (s *memoryStore) Reset(ctx context.Context, tenant, model string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.usage {
+		if key[0] == tenant && (model == "" || key[1] == model) {
+			delete(s.usage, key)
+		}
+	}
+	return nil
+}
+
+// usageClient answers every call with 10 prompt and 5 completion tokens
+type usageClient struct {
+	libports.LLMClient
+	err error
+}
+
+func (c *usageClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &libports.CompletionResponse{
+		Message: libports.Message{Role: "assistant", Content: "Hi"},
+		Usage:   libports.UsageInfo{PromptTokens: 10, CompletionTokens: 5},
+	}, nil
+}
+
+func (c *usageClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	return &libports.StructuredResponse{Usage: libports.UsageInfo{TotalTokens: 20}}, nil
+}
+
+func request(model string) libports.CompletionRequest {
+	return libports.CompletionRequest{Model: model, Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+}
+
+func TestLLMClient_Quotas(t *testing.T) {
+	store := newMemoryStore()
+	enforcer := NewEnforcer(store, zap.NewNop())
+	enforcer.SetRules(
+		Rule{Limit: Limit{Window: time.Hour, Tokens: 50}},
+		Rule{Model: EachModel, Limit: Limit{Window: time.Minute, Requests: 2}},
+	)
+	client := NewLLMClient(&usageClient{}, enforcer)
+	acme := WithTenant(context.Background(), "acme")
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Complete(acme, request("gpt-4o")); err != nil {
+			t.Fatalf("Complete() %d error = %v", i, err)
+		}
+	}
+	_, err := client.Complete(acme, request("gpt-4o"))
+	if !errors.Is(err, ports.ErrQuotaExceeded) {
+		t.Fatalf("third Complete(gpt-4o) error = %v, want quota exceeded", err)
+	}
+
+	// The request rate applies per model, the token budget to all of them
+	if _, err := client.CompleteStructured(acme, request("claude"), libports.JSONSchema{}); err != nil {
+		t.Fatalf("CompleteStructured(claude) error = %v", err)
+	}
+	if _, err := client.Complete(acme, request("claude")); !errors.Is(err, ports.ErrQuotaExceeded) {
+		t.Errorf("Complete(claude) over the token budget error = %v, want quota exceeded", err)
+	}
+
+	// Other tenants are counted separately
+	if _, err := client.Complete(context.Background(), request("gpt-4o")); err != nil {
+		t.Errorf("Complete() of the default tenant error = %v", err)
+	}
+
+	usage, _ := store.Usage(context.Background(), "acme", "", time.Hour)
+	if usage.Requests != 3 || usage.Tokens != 50 {
+		t.Errorf("acme usage = %+v, want 3 requests, 50 tokens", usage)
+	}
+	usage, _ = store.Usage(context.Background(), DefaultTenant, "gpt-4o", time.Hour)
+	if usage.Requests != 1 {
+		t.Errorf("default tenant usage = %+v, want 1 request", usage)
+	}
+}
+
+func TestLLMClient_CountsFailedCalls(t *testing.T) {
+	store := newMemoryStore()
+	client := NewLLMClient(&usageClient{err: errors.New("overloaded")}, NewEnforcer(store, zap.NewNop()))
+
+	if _, err := client.Complete(context.Background(), request("")); err == nil {
+		t.Fatal("Complete() succeeded")
+	}
+	usage, _ := store.Usage(context.Background(), DefaultTenant, DefaultModel, time.Hour)
+	if usage.Requests != 1 || usage.Tokens != 0 {
+		t.Errorf("usage = %+v, want the failed request", usage)
+	}
+
+	if _, err := client.StreamComplete(context.Background(), request("")); !errors.Is(err, ports.ErrNotImplemented) {
+		t.Errorf("StreamComplete() error = %v, want not implemented", err)
+	}
+}
+
+func TestEnforcer_FailsOpen(t *testing.T) {
+	store := newMemoryStore()
+	store.err = errors.New("connection refused")
+	enforcer := NewEnforcer(store, zap.NewNop())
+	enforcer.SetRules(Rule{Limit: Limit{Window: time.Minute, Requests: 1}})
+
+	client := NewLLMClient(&usageClient{}, enforcer)
+	for i := 0; i < 3; i++ {
+		if _, err := client.Complete(context.Background(), request("gpt-4o")); err != nil {
+			t.Fatalf("Complete() %d without a store error = %v", i, err)
+		}
+	}
+}
+
+func TestAdminHandler(t *testing.T) {
+	store := newMemoryStore()
+	enforcer := NewEnforcer(store, zap.NewNop())
+	enforcer.SetRules(
+		Rule{Tenant: "acme", Limit: Limit{Window: time.Hour, Tokens: 15}},
+		Rule{Tenant: "globex", Limit: Limit{Window: time.Hour, Tokens: 1000}},
+		Rule{Model: EachModel, Limit: Limit{Window: time.Minute, Requests: 100}},
+	)
+	_, _ = NewLLMClient(&usageClient{}, enforcer).Complete(WithTenant(context.Background(), "acme"), request("gpt-4o"))
+
+	h := NewAdminHandler(enforcer, zap.NewNop())
+	h.SetAPIKeys("admin-key")
+	serve := func(method, target, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(http.MethodGet, "/usage", "wrong-key"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("GET /usage with a wrong key status = %d, want 401", rec.Code)
+	}
+
+	rec := serve(http.MethodGet, "/usage", "admin-key")
+	var list usageList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /usage = %d %s", rec.Code, rec.Body.String())
+	}
+	if list.Window != "1h0m0s" || len(list.Usage) != 1 || list.Usage[0].Tokens != 15 {
+		t.Errorf("GET /usage = %+v, want acme's usage over the longest window", list)
+	}
+
+	rec = serve(http.MethodGet, "/usage/acme?model=gpt-4o", "admin-key")
+	var status tenantStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /usage/acme = %d %s", rec.Code, rec.Body.String())
+	}
+	if len(status.Quotas) != 2 {
+		t.Fatalf("GET /usage/acme quotas = %+v, want acme's two rules", status.Quotas)
+	}
+	if q := status.Quotas[0]; !q.Exceeded || q.Tokens != 15 || q.TokensLimit != 15 {
+		t.Errorf("token quota = %+v, want it exceeded", q)
+	}
+	if q := status.Quotas[1]; q.Exceeded || q.Requests != 1 || q.Model != EachModel {
+		t.Errorf("request quota = %+v, want 1 of 100 requests", q)
+	}
+
+	if rec := serve(http.MethodDelete, "/usage/acme", "admin-key"); rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE /usage/acme = %d %s", rec.Code, rec.Body.String())
+	}
+	if err := enforcer.Check(context.Background(), "acme", "gpt-4o"); err != nil {
+		t.Errorf("Check() after reset error = %v", err)
+	}
+
+	if rec := serve(http.MethodGet, "/usage?window=soon", "admin-key"); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /usage with an invalid window status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,15 @@
+// Package redis provides a Redis implementation of the QuotaStore interface
+// (pkg/ports in this repository), for quota.Enforcer.
+//
+// Usage is counted in time buckets (a minute by default, see SetBuckets),
+// as hash fields under a key prefix ("dago:quota:" by default, see
+// SetPrefix): one hash per tenant and model, expired by Redis once its
+// last bucket is past retention (24 hours by default). A rolling window
+// sums the buckets overlapping it, so it is precise to a bucket.
+//
+// Usage:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	store := redisquota.NewStore(client, logger)
+//	enforcer := quota.NewEnforcer(store, logger)
+package redis
@@ -0,0 +1,297 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Default prefix of quota keys
+const defaultPrefix = "dago:quota:"
+
+// DefaultBucketSize is the default size of counter buckets, the precision
+// of rolling windows
+const DefaultBucketSize = time.Minute
+
+// DefaultRetention is how long counters are kept by default, the longest
+// window that can be queried
+const DefaultRetention = 24 * time.Hour
+
+// Store implements ports.QuotaStore with Redis hashes of counters, one per
+// tenant and model, holding a request and a token counter per time bucket.
+// Counters are shared by every process using the same Redis, so replicas
+// enforce quotas together.
+type Store struct {
+	client     redis.UniversalClient
+	prefix     string
+	bucketSize time.Duration
+	retention  time.Duration
+	now        func() time.Time
+	logger     *zap.Logger
+}
+
+// NewStore creates a new Redis quota store
+func NewStore(client redis.UniversalClient, logger *zap.Logger) *Store {
+	return &Store{
+		client:     client,
+		prefix:     defaultPrefix,
+		bucketSize: DefaultBucketSize,
+		retention:  DefaultRetention,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// SetPrefix sets the prefix of quota keys, e.g. to isolate deployments
+// sharing a Redis
+func (s *Store) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+// SetBuckets sets the size of counter buckets and how long counters are
+// kept. Windows are rounded to whole buckets, and can't be longer than
+// retention. Changing them doesn't migrate existing counters.
+func (s *Store) SetBuckets(size, retention time.Duration) {
+	if size > 0 {
+		s.bucketSize = size
+	}
+	if retention > 0 {
+		s.retention = retention
+	}
+}
+
+// Add counts requests and tokens for tenant and model in the current
+// bucket (ports.QuotaStore interface)
+func (s *Store) Add(ctx context.Context, tenant, model string, requests, tokens int64) error {
+	bucket := s.bucket(s.now())
+	key := s.usageKey(tenant, model)
+	ttl := s.retention + s.bucketSize
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if requests != 0 {
+			pipe.HIncrBy(ctx, key, bucket+":r", requests)
+		}
+		if tokens != 0 {
+			pipe.HIncrBy(ctx, key, bucket+":t", tokens)
+		}
+		pipe.PExpire(ctx, key, ttl)
+		pipe.SAdd(ctx, s.modelsKey(tenant), model)
+		pipe.PExpire(ctx, s.modelsKey(tenant), ttl)
+		pipe.SAdd(ctx, s.prefix+"tenants", tenant)
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("failed to add quota usage",
+			zap.String("tenant", tenant),
+			zap.String("model", model),
+			zap.Error(err))
+		return fmt.Errorf("failed to add quota usage: %w", err)
+	}
+	return nil
+}
+
+// Usage returns the usage of tenant and model over the last window; an
+// empty model sums all the tenant's models (ports.QuotaStore interface)
+func (s *Store) Usage(ctx context.Context, tenant, model string, window time.Duration) (*ports.QuotaUsage, error) {
+	if err := s.checkWindow(window); err != nil {
+		return nil, err
+	}
+
+	models := []string{model}
+	if model == "" {
+		var err error
+		models, err = s.client.SMembers(ctx, s.modelsKey(tenant)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read quota usage: %w", err)
+		}
+	}
+
+	usage := &ports.QuotaUsage{Tenant: tenant, Model: model}
+	counters, err := s.read(ctx, tenant, models, window)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counters {
+		usage.Requests += c.Requests
+		usage.Tokens += c.Tokens
+	}
+	return usage, nil
+}
+
+// List returns the usage over the last window of every tenant and model
+// that has some, sorted by tenant and model (ports.QuotaStore interface).
+// Tenants and models whose counters have expired are dropped from the
+// index.
+func (s *Store) List(ctx context.Context, window time.Duration) ([]ports.QuotaUsage, error) {
+	if err := s.checkWindow(window); err != nil {
+		return nil, err
+	}
+
+	tenants, err := s.client.SMembers(ctx, s.prefix+"tenants").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quota tenants: %w", err)
+	}
+	sort.Strings(tenants)
+
+	var list []ports.QuotaUsage
+	for _, tenant := range tenants {
+		models, err := s.client.SMembers(ctx, s.modelsKey(tenant)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list quota models: %w", err)
+		}
+		if len(models) == 0 {
+			// The models set expired with the tenant's last counters
+			s.client.SRem(ctx, s.prefix+"tenants", tenant)
+			continue
+		}
+		sort.Strings(models)
+
+		counters, err := s.read(ctx, tenant, models, window)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range counters {
+			if c.Requests != 0 || c.Tokens != 0 {
+				list = append(list, c)
+			}
+		}
+	}
+	return list, nil
+}
+
+// Reset clears the counters of tenant and model; an empty model clears all
+// the tenant's counters (ports.QuotaStore interface)
+func (s *Store) Reset(ctx context.Context, tenant, model string) error {
+	if model != "" {
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, s.usageKey(tenant, model))
+			pipe.SRem(ctx, s.modelsKey(tenant), model)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reset quota usage: %w", err)
+		}
+		return nil
+	}
+
+	models, err := s.client.SMembers(ctx, s.modelsKey(tenant)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to reset quota usage: %w", err)
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, m := range models {
+			pipe.Del(ctx, s.usageKey(tenant, m))
+		}
+		pipe.Del(ctx, s.modelsKey(tenant))
+		pipe.SRem(ctx, s.prefix+"tenants", tenant)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reset quota usage: %w", err)
+	}
+	return nil
+}
+
+// read sums the counters of tenant's models over the last window,
+// deleting buckets past retention
+func (s *Store) read(ctx context.Context, tenant string, models []string, window time.Duration) ([]ports.QuotaUsage, error) {
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(models))
+	for i, model := range models {
+		cmds[i] = pipe.HGetAll(ctx, s.usageKey(tenant, model))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("failed to read quota usage", zap.String("tenant", tenant), zap.Error(err))
+		return nil, fmt.Errorf("failed to read quota usage: %w", err)
+	}
+
+	now := s.now()
+	since := now.Add(-window)
+	expired := now.Add(-s.retention)
+
+	usage := make([]ports.QuotaUsage, len(models))
+	for i, model := range models {
+		usage[i] = ports.QuotaUsage{Tenant: tenant, Model: model}
+		fields := cmds[i].Val()
+		if len(fields) == 0 {
+			// The counters expired; drop the model from the index. An Add
+			// racing with this re-adds it on its next call.
+			s.client.SRem(ctx, s.modelsKey(tenant), model)
+			continue
+		}
+
+		var stale []string
+		for field, value := range fields {
+			start, kind, ok := s.parseField(field)
+			if !ok {
+				continue
+			}
+			end := start.Add(s.bucketSize)
+			if !end.After(expired) {
+				stale = append(stale, field)
+				continue
+			}
+			if !end.After(since) {
+				continue
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			if kind == "r" {
+				usage[i].Requests += n
+			} else {
+				usage[i].Tokens += n
+			}
+		}
+		if len(stale) > 0 {
+			s.client.HDel(ctx, s.usageKey(tenant, model), stale...)
+		}
+	}
+	return usage, nil
+}
+
+// checkWindow checks window can be answered from the retained counters
+func (s *Store) checkWindow(window time.Duration) error {
+	if window <= 0 || window > s.retention {
+		return fmt.Errorf("%w: window %s must be positive and at most the retention of %s", ports.ErrInvalidRequest, window, s.retention)
+	}
+	return nil
+}
+
+// bucket returns the field prefix of the bucket holding t
+func (s *Store) bucket(t time.Time) string {
+	return strconv.FormatInt(t.Truncate(s.bucketSize).Unix(), 10)
+}
+
+// parseField returns the start and kind ("r" or "t") of a counter field
+func (s *Store) parseField(field string) (time.Time, string, bool) {
+	unix, kind, ok := strings.Cut(field, ":")
+	if !ok || (kind != "r" && kind != "t") {
+		return time.Time{}, "", false
+	}
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return time.Unix(seconds, 0), kind, true
+}
+
+// usageKey is the key of the counters of tenant and model. Names are
+// escaped, so a colon in them can't make two keys collide.
+func (s *Store) usageKey(tenant, model string) string {
+	return s.prefix + "usage:" + url.QueryEscape(tenant) + ":" + url.QueryEscape(model)
+}
+
+// modelsKey is the key of the set of tenant's models
+func (s *Store) modelsKey(tenant string) string {
+	return s.prefix + "models:" + url.QueryEscape(tenant)
+}
@@ -0,0 +1,176 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ ports.QuotaStore = (*Store)(nil)
+
+// newTestStore returns a store on miniredis, with a clock advanced by
+// the returned function
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis, func(time.Duration)) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	now := time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC)
+	s := NewStore(client, zap.NewNop())
+	s.now = func() time.Time { return now }
+	advance := func(d time.Duration) {
+		now = now.Add(d)
+		mr.FastForward(d)
+	}
+	return s, mr, advance
+}
+
+func TestStore_RollingWindow(t *testing.T) {
+	ctx := context.Background()
+	s, _, advance := newTestStore(t)
+
+	_ = s.Add(ctx, "acme", "gpt-4o", 1, 100)
+	advance(2 * time.Minute)
+	_ = s.Add(ctx, "acme", "gpt-4o", 1, 50)
+	_ = s.Add(ctx, "acme", "claude", 2, 10)
+
+	usage, err := s.Usage(ctx, "acme", "gpt-4o", time.Hour)
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	if usage.Requests != 2 || usage.Tokens != 150 {
+		t.Errorf("Usage(gpt-4o, 1h) = %+v, want 2 requests, 150 tokens", usage)
+	}
+
+	// Only the current bucket overlaps the last minute
+	usage, _ = s.Usage(ctx, "acme", "gpt-4o", time.Minute)
+	if usage.Requests != 1 || usage.Tokens != 50 {
+		t.Errorf("Usage(gpt-4o, 1m) = %+v, want 1 request, 50 tokens", usage)
+	}
+
+	usage, _ = s.Usage(ctx, "acme", "", time.Hour)
+	if usage.Requests != 4 || usage.Tokens != 160 || usage.Model != "" {
+		t.Errorf("Usage(all models) = %+v, want 4 requests, 160 tokens", usage)
+	}
+
+	// Windows are precise to a bucket: the last one still overlaps
+	advance(time.Hour)
+	usage, _ = s.Usage(ctx, "acme", "", time.Hour)
+	if usage.Requests != 3 {
+		t.Errorf("Usage() an hour later = %+v, want the last bucket's", usage)
+	}
+	advance(time.Minute)
+	usage, _ = s.Usage(ctx, "acme", "", time.Hour)
+	if usage.Requests != 0 || usage.Tokens != 0 {
+		t.Errorf("Usage() after the window = %+v, want none", usage)
+	}
+}
+
+func TestStore_ListAndReset(t *testing.T) {
+	ctx := context.Background()
+	s, mr, _ := newTestStore(t)
+	s.SetPrefix("staging:")
+
+	_ = s.Add(ctx, "globex", "gpt-4o", 1, 5)
+	_ = s.Add(ctx, "acme", "claude", 1, 7)
+	_ = s.Add(ctx, "acme", "gpt-4o", 1, 3)
+	_ = s.Add(ctx, "a:b", "c", 1, 1)
+	_ = s.Add(ctx, "a", "b:c", 1, 2)
+
+	list, err := s.List(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []ports.QuotaUsage{
+		{Tenant: "a", Model: "b:c", Requests: 1, Tokens: 2},
+		{Tenant: "a:b", Model: "c", Requests: 1, Tokens: 1},
+		{Tenant: "acme", Model: "claude", Requests: 1, Tokens: 7},
+		{Tenant: "acme", Model: "gpt-4o", Requests: 1, Tokens: 3},
+		{Tenant: "globex", Model: "gpt-4o", Requests: 1, Tokens: 5},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", list, want)
+	}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Errorf("List()[%d] = %+v, want %+v", i, list[i], want[i])
+		}
+	}
+	if !mr.Exists("staging:tenants") {
+		t.Error("keys not stored under the prefix")
+	}
+
+	if err := s.Reset(ctx, "acme", "claude"); err != nil {
+		t.Fatalf("Reset(model) error = %v", err)
+	}
+	usage, _ := s.Usage(ctx, "acme", "", time.Hour)
+	if usage.Requests != 1 || usage.Tokens != 3 {
+		t.Errorf("Usage() after Reset(claude) = %+v, want gpt-4o's", usage)
+	}
+
+	if err := s.Reset(ctx, "acme", ""); err != nil {
+		t.Fatalf("Reset(tenant) error = %v", err)
+	}
+	list, _ = s.List(ctx, time.Hour)
+	for _, u := range list {
+		if u.Tenant == "acme" {
+			t.Errorf("List() after Reset(acme) has %+v", u)
+		}
+	}
+	if len(list) != 3 {
+		t.Errorf("List() after Reset(acme) = %+v, want the other tenants", list)
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	ctx := context.Background()
+	s, mr, advance := newTestStore(t)
+	s.SetBuckets(time.Minute, time.Hour)
+
+	_ = s.Add(ctx, "acme", "gpt-4o", 1, 100)
+	advance(30 * time.Minute)
+	_ = s.Add(ctx, "acme", "gpt-4o", 1, 10)
+	advance(45 * time.Minute)
+
+	// The first bucket is past retention: it is deleted on read
+	usage, _ := s.Usage(ctx, "acme", "gpt-4o", time.Hour)
+	if usage.Requests != 1 || usage.Tokens != 10 {
+		t.Errorf("Usage() = %+v, want the second call only", usage)
+	}
+	if fields, _ := mr.HKeys("dago:quota:usage:acme:gpt-4o"); len(fields) != 2 {
+		t.Errorf("fields = %v, want the second bucket's only", fields)
+	}
+
+	// Idle counters expire, and List drops them from the index
+	advance(time.Hour)
+	if list, _ := s.List(ctx, time.Hour); len(list) != 0 {
+		t.Errorf("List() after expiry = %+v, want none", list)
+	}
+	if members, _ := mr.Members("dago:quota:tenants"); len(members) != 0 {
+		t.Errorf("tenants = %v, want none", members)
+	}
+
+	if _, err := s.Usage(ctx, "acme", "", 2*time.Hour); !errors.Is(err, ports.ErrInvalidRequest) {
+		t.Errorf("Usage(window over retention) error = %v, want invalid request", err)
+	}
+}
+
+func TestStore_Unavailable(t *testing.T) {
+	ctx := context.Background()
+	s, mr, _ := newTestStore(t)
+	mr.Close()
+
+	if err := s.Add(ctx, "acme", "gpt-4o", 1, 1); err == nil {
+		t.Error("Add() succeeded without Redis")
+	}
+	if _, err := s.Usage(ctx, "acme", "gpt-4o", time.Hour); err == nil {
+		t.Error("Usage() succeeded without Redis")
+	}
+}
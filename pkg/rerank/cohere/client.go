@@ -0,0 +1,133 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Cohere API endpoint
+	DefaultBaseURL = "https://api.cohere.com/v2"
+
+	// DefaultModel is the reranking model used by NewClient
+	DefaultModel = "rerank-v3.5"
+)
+
+// Client implements the ports.Reranker interface for Cohere
+type Client struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Cohere reranker using DefaultModel
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	return NewClientWithModel(apiKey, baseURL, DefaultModel, logger)
+}
+
+// NewClientWithModel creates a new Cohere reranker with a custom model
+func NewClientWithModel(apiKey, baseURL, model string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// Rerank scores the documents against the query (ports.Reranker interface)
+func (c *Client) Rerank(ctx context.Context, query string, documents []string) ([]ports.RerankResult, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(rerankRequest{
+		Model:     c.model,
+		Query:     query,
+		Documents: documents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rerank", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API call failed: %s: %s", httpResp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var result rerankResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	results := make([]ports.RerankResult, 0, len(result.Results))
+	for _, r := range result.Results {
+		if r.Index < 0 || r.Index >= len(documents) {
+			return nil, fmt.Errorf("result index %d out of range", r.Index)
+		}
+		results = append(results, ports.RerankResult{
+			Index:    r.Index,
+			Document: documents[r.Index],
+			Score:    r.RelevanceScore,
+		})
+	}
+
+	// Providers already sort, but don't rely on it
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+	c.logger.Debug("documents reranked",
+		zap.String("model", c.model),
+		zap.Int("document_count", len(documents)))
+
+	return results, nil
+}
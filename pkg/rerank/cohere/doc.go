@@ -0,0 +1,21 @@
+// Package cohere implements the reranker adapter for Cohere Rerank.
+//
+// This adapter implements the ports.Reranker interface (pkg/ports in this
+// repository) using Cohere's v2 rerank API.
+//
+// Supported models (examples):
+//   - rerank-v3.5 (default)
+//   - rerank-english-v3.0
+//   - rerank-multilingual-v3.0
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/rerank/cohere"
+//
+//	reranker, err := cohere.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	results, err := reranker.Rerank(ctx, query, documents)
+package cohere
@@ -0,0 +1,24 @@
+// Package rerank provides adapters for the ports.Reranker interface (pkg/ports
+// in this repository).
+//
+// A reranker scores retrieved documents against the query with a
+// cross-encoder, which is slower than vector similarity but more precise;
+// retrieval pipelines fetch a generous candidate set and keep the top of the
+// reranked list.
+//
+// Available implementations:
+//   - cohere: Cohere Rerank (rerank-v3.5)
+//   - jina: Jina AI rerankers (jina-reranker-v2-base-multilingual)
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/rerank/cohere"
+//
+//	reranker, err := cohere.NewClient(apiKey, "", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	results, err := reranker.Rerank(ctx, "Which workers have a GPU?", candidates)
+//	best := results[0].Document
+package rerank
@@ -0,0 +1,80 @@
+package jina
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("NewClient() expected error for empty API key")
+	}
+
+	client, err := NewClient("test-key", "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.baseURL != DefaultBaseURL || client.model != DefaultModel {
+		t.Errorf("NewClient() = %s %s, want defaults", client.baseURL, client.model)
+	}
+}
+
+func TestRerank(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rerankRequest
+		if r.URL.Path != "/rerank" || r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "custom-model" {
+			http.Error(w, "unexpected body", http.StatusBadRequest)
+			return
+		}
+
+		// Unsorted on purpose
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{
+				{"index": 0, "relevance_score": 0.1},
+				{"index": 2, "relevance_score": 0.9},
+				{"index": 1, "relevance_score": 0.5},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := NewClientWithModel("test-key", srv.URL, "custom-model", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClientWithModel() error = %v", err)
+	}
+
+	results, err := client.Rerank(context.Background(), "gpu workers", []string{"cpu", "small gpu", "big gpu"})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+
+	want := []string{"big gpu", "small gpu", "cpu"}
+	if len(results) != len(want) {
+		t.Fatalf("Rerank() returned %d results, want %d", len(results), len(want))
+	}
+	for i, doc := range want {
+		if results[i].Document != doc {
+			t.Errorf("results[%d] = %+v, want %s", i, results[i], doc)
+		}
+	}
+	if results[0].Index != 2 || results[0].Score != 0.9 {
+		t.Errorf("results[0] = %+v, want index 2 with score 0.9", results[0])
+	}
+}
+
+func TestRerank_NoDocuments(t *testing.T) {
+	client, _ := NewClient("test-key", "http://127.0.0.1:1", zap.NewNop())
+	results, err := client.Rerank(context.Background(), "query", nil)
+	if err != nil || len(results) != 0 {
+		t.Errorf("Rerank() = %v, %v, want no results without a call", results, err)
+	}
+}
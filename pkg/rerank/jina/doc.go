@@ -0,0 +1,21 @@
+// Package jina implements the reranker adapter for Jina AI rerankers.
+//
+// This adapter implements the ports.Reranker interface (pkg/ports in this
+// repository) using Jina's rerank API.
+//
+// Supported models (examples):
+//   - jina-reranker-v2-base-multilingual (default)
+//   - jina-reranker-v1-base-en
+//   - jina-colbert-v2
+//
+// Usage:
+//
+//	import "github.com/aescanero/dago-adapters/pkg/rerank/jina"
+//
+//	reranker, err := jina.NewClientWithModel(apiKey, "", "jina-colbert-v2", logger)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	results, err := reranker.Rerank(ctx, query, documents)
+package jina
@@ -0,0 +1,8 @@
+// Package adapterspb holds the protobuf messages and gRPC services of
+// adapters.proto, for clients in other languages and for package rpc.
+//
+// The Go code is generated with protoc-gen-go and protoc-gen-go-grpc:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//		--go-grpc_out=. --go-grpc_opt=paths=source_relative adapters.proto
+package adapterspb
@@ -0,0 +1,302 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/rpc/adapterspb"
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	_ libports.LLMClient      = (*LLMClient)(nil)
+	_ streamer                = (*LLMClient)(nil)
+	_ libports.WorkerRegistry = (*WorkerRegistry)(nil)
+	_ registry.Watcher        = (*WorkerRegistry)(nil)
+)
+
+// LLMClient is an LLM client calling a remote LLMService
+type LLMClient struct {
+	client adapterspb.LLMServiceClient
+	logger *zap.Logger
+}
+
+// NewLLMClient creates a client calling the LLMService served on conn
+func NewLLMClient(conn grpc.ClientConnInterface, logger *zap.Logger) *LLMClient {
+	return &LLMClient{
+		client: adapterspb.NewLLMServiceClient(conn),
+		logger: logger,
+	}
+}
+
+// GenerateCompletion implements ports.LLMClient
+func (c *LLMClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T, want *domain.LLMRequest", ports.ErrInvalidRequest, req)
+	}
+	pbReq, err := toPBGenerateRequest(llmReq)
+	if err != nil {
+		return nil, err
+	}
+
+	pb, err := c.client.GenerateCompletion(ctx, pbReq)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	resp := &domain.LLMResponse{
+		Content: pb.GetContent(),
+		Model:   pb.GetModel(),
+		Usage: domain.Usage{
+			InputTokens:  int(pb.GetInputTokens()),
+			OutputTokens: int(pb.GetOutputTokens()),
+		},
+	}
+	for _, call := range pb.GetToolCalls() {
+		resp.ToolCalls = append(resp.ToolCalls, domain.ToolCall{ID: call.GetId(), Name: call.GetName(), Input: fromStruct(call.GetArguments())})
+	}
+	return resp, nil
+}
+
+// Complete implements ports.LLMClient
+func (c *LLMClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	pb, err := c.client.Complete(ctx, &adapterspb.CompleteRequest{Request: toPBRequest(req)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromPBResponse(pb), nil
+}
+
+// CompleteWithTools implements ports.LLMClient
+func (c *LLMClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	pbTools, err := toPBTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	pb, err := c.client.CompleteWithTools(ctx, &adapterspb.CompleteWithToolsRequest{Request: toPBRequest(req), Tools: pbTools})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromPBResponse(pb), nil
+}
+
+// CompleteStructured implements ports.LLMClient
+func (c *LLMClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	pbSchema, err := toStruct(schema)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	pb, err := c.client.CompleteStructured(ctx, &adapterspb.CompleteStructuredRequest{Request: toPBRequest(req), Schema: pbSchema})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return &libports.StructuredResponse{
+		Data:      fromStruct(pb.GetData()),
+		Usage:     fromPBUsage(pb.GetUsage()),
+		CreatedAt: fromTimestamp(pb.GetCreatedAt()),
+	}, nil
+}
+
+// StreamComplete streams a completion. Errors before the first chunk,
+// including ports.ErrNotImplemented if the remote client doesn't stream,
+// are returned; later ones close the channel without a final chunk.
+func (c *LLMClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.StreamComplete(ctx, &adapterspb.CompleteRequest{Request: toPBRequest(req)})
+	if err != nil {
+		cancel()
+		return nil, fromStatus(err)
+	}
+	if err := started(stream); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	chunks := make(chan libports.CompletionChunk)
+	go func() {
+		defer close(chunks)
+		defer cancel()
+		for {
+			pb, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					c.logger.Warn("Completion stream failed", zap.Error(fromStatus(err)))
+				}
+				return
+			}
+			select {
+			case chunks <- libports.CompletionChunk{Delta: pb.GetDelta(), IsFinal: pb.GetIsFinal()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return chunks, nil
+}
+
+// started waits for the server to start stream, returning the error it was
+// rejected with otherwise
+func started(stream grpc.ClientStream) error {
+	header, err := stream.Header()
+	if err != nil {
+		return fromStatus(err)
+	}
+	if len(header.Get(streamStartedHeader)) > 0 {
+		return nil
+	}
+
+	// Without headers the call ended right away, with its status
+	if err := stream.RecvMsg(new(emptypb.Empty)); err != nil && err != io.EOF {
+		return fromStatus(err)
+	}
+	return errors.New("stream ended before it started")
+}
+
+// WorkerRegistry is a worker registry calling a remote
+// WorkerRegistryService. It implements worker_registry.Watcher; watching
+// fails with ports.ErrNotImplemented if the remote registry doesn't.
+type WorkerRegistry struct {
+	client adapterspb.WorkerRegistryServiceClient
+	logger *zap.Logger
+}
+
+// NewWorkerRegistry creates a registry calling the WorkerRegistryService
+// served on conn
+func NewWorkerRegistry(conn grpc.ClientConnInterface, logger *zap.Logger) *WorkerRegistry {
+	return &WorkerRegistry{
+		client: adapterspb.NewWorkerRegistryServiceClient(conn),
+		logger: logger,
+	}
+}
+
+// Register implements ports.WorkerRegistry
+func (r *WorkerRegistry) Register(ctx context.Context, worker libports.WorkerInfo) error {
+	pb, err := toPBWorker(worker)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.Register(ctx, &adapterspb.RegisterRequest{Worker: pb})
+	return fromStatus(err)
+}
+
+// Unregister implements ports.WorkerRegistry
+func (r *WorkerRegistry) Unregister(ctx context.Context, workerID string) error {
+	_, err := r.client.Unregister(ctx, &adapterspb.UnregisterRequest{WorkerId: workerID})
+	return fromStatus(err)
+}
+
+// Heartbeat implements ports.WorkerRegistry
+func (r *WorkerRegistry) Heartbeat(ctx context.Context, workerID string, status libports.WorkerStatus, currentTask string) error {
+	_, err := r.client.Heartbeat(ctx, &adapterspb.HeartbeatRequest{
+		WorkerId:    workerID,
+		Status:      string(status),
+		CurrentTask: currentTask,
+	})
+	return fromStatus(err)
+}
+
+// GetWorker implements ports.WorkerRegistry
+func (r *WorkerRegistry) GetWorker(ctx context.Context, workerID string) (*libports.WorkerInfo, error) {
+	pb, err := r.client.GetWorker(ctx, &adapterspb.GetWorkerRequest{WorkerId: workerID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	worker := fromPBWorker(pb)
+	return &worker, nil
+}
+
+// ListWorkers implements ports.WorkerRegistry
+func (r *WorkerRegistry) ListWorkers(ctx context.Context, filter libports.WorkerFilter) ([]libports.WorkerInfo, error) {
+	resp, err := r.client.ListWorkers(ctx, &adapterspb.ListWorkersRequest{Filter: toPBFilter(filter)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	workers := make([]libports.WorkerInfo, 0, len(resp.GetWorkers()))
+	for _, pb := range resp.GetWorkers() {
+		workers = append(workers, fromPBWorker(pb))
+	}
+	return workers, nil
+}
+
+// GetWorkerStats implements ports.WorkerRegistry
+func (r *WorkerRegistry) GetWorkerStats(ctx context.Context, workerType libports.WorkerType) (*libports.WorkerStats, error) {
+	pb, err := r.client.GetWorkerStats(ctx, &adapterspb.GetWorkerStatsRequest{Type: string(workerType)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return &libports.WorkerStats{
+		Type:              libports.WorkerType(pb.GetType()),
+		TotalWorkers:      int(pb.GetTotalWorkers()),
+		IdleWorkers:       int(pb.GetIdleWorkers()),
+		BusyWorkers:       int(pb.GetBusyWorkers()),
+		UnhealthyWorkers:  int(pb.GetUnhealthyWorkers()),
+		TotalPendingTasks: int(pb.GetTotalPendingTasks()),
+	}, nil
+}
+
+// CleanupStaleWorkers implements ports.WorkerRegistry
+func (r *WorkerRegistry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	resp, err := r.client.CleanupStaleWorkers(ctx, &adapterspb.CleanupStaleWorkersRequest{Timeout: durationpb.New(timeout)})
+	if err != nil {
+		return 0, fromStatus(err)
+	}
+	return int(resp.GetRemoved()), nil
+}
+
+// Watch implements worker_registry.Watcher. The channel is closed when ctx
+// is cancelled or the stream fails.
+func (r *WorkerRegistry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := r.client.Watch(ctx, &adapterspb.WatchRequest{})
+	if err != nil {
+		cancel()
+		return nil, fromStatus(err)
+	}
+	if err := started(stream); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	events := make(chan registry.WatchEvent)
+	go func() {
+		defer close(events)
+		defer cancel()
+		for {
+			pb, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					r.logger.Warn("Worker watch failed", zap.Error(fromStatus(err)))
+				}
+				return
+			}
+			event := registry.WatchEvent{
+				Type:     registry.WatchEventType(pb.GetType()),
+				WorkerID: pb.GetWorkerId(),
+			}
+			if pb.GetWorker() != nil {
+				worker := fromPBWorker(pb.GetWorker())
+				event.Worker = &worker
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,330 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/rpc/adapterspb"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Header sent by the server once a stream is started, so clients can
+// tell a started stream from one rejected before its first message
+const streamStartedHeader = "dago-stream"
+
+// toStatus returns the gRPC status error of an adapter error
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, ports.ErrNotImplemented):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, ports.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ports.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+// remoteError is an error returned by the server, wrapping the sentinel
+// of its status code so errors.Is works as with a local adapter
+type remoteError struct {
+	message  string
+	sentinel error
+}
+
+func (e *remoteError) Error() string {
+	return e.message
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.sentinel
+}
+
+// fromStatus returns the adapter error of a gRPC status error
+func fromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var sentinel error
+	switch st.Code() {
+	case codes.Unimplemented:
+		sentinel = ports.ErrNotImplemented
+	case codes.InvalidArgument:
+		sentinel = ports.ErrInvalidRequest
+	case codes.ResourceExhausted:
+		sentinel = ports.ErrQuotaExceeded
+	case codes.Canceled:
+		sentinel = context.Canceled
+	case codes.DeadlineExceeded:
+		sentinel = context.DeadlineExceeded
+	default:
+		return errors.New(st.Message())
+	}
+	return &remoteError{message: st.Message(), sentinel: sentinel}
+}
+
+// toStruct converts a JSON object, normalizing its values through JSON
+// since structpb only accepts the types encoding/json decodes to
+func toStruct(m map[string]interface{}) (*structpb.Struct, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ports.ErrInvalidRequest, err)
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("%w: %v", ports.ErrInvalidRequest, err)
+	}
+	return structpb.NewStruct(normalized)
+}
+
+func fromStruct(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toPBRequest(req libports.CompletionRequest) *adapterspb.CompletionRequest {
+	pb := &adapterspb.CompletionRequest{
+		Model:            req.Model,
+		Temperature:      req.Temperature,
+		MaxTokens:        int32(req.MaxTokens),
+		TopP:             req.TopP,
+		Stop:             req.Stop,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+		User:             req.User,
+	}
+	for _, m := range req.Messages {
+		pb.Messages = append(pb.Messages, &adapterspb.Message{Role: m.Role, Content: m.Content, Name: m.Name})
+	}
+	return pb
+}
+
+func fromPBRequest(pb *adapterspb.CompletionRequest) libports.CompletionRequest {
+	req := libports.CompletionRequest{
+		Model:            pb.GetModel(),
+		Temperature:      pb.GetTemperature(),
+		MaxTokens:        int(pb.GetMaxTokens()),
+		TopP:             pb.GetTopP(),
+		Stop:             pb.GetStop(),
+		PresencePenalty:  pb.GetPresencePenalty(),
+		FrequencyPenalty: pb.GetFrequencyPenalty(),
+		User:             pb.GetUser(),
+	}
+	for _, m := range pb.GetMessages() {
+		req.Messages = append(req.Messages, libports.Message{Role: m.GetRole(), Content: m.GetContent(), Name: m.GetName()})
+	}
+	return req
+}
+
+func toPBTools(tools []libports.Tool) ([]*adapterspb.Tool, error) {
+	var pb []*adapterspb.Tool
+	for _, tool := range tools {
+		parameters, err := toStruct(tool.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("parameters of tool %s: %w", tool.Name, err)
+		}
+		pb = append(pb, &adapterspb.Tool{Name: tool.Name, Description: tool.Description, Parameters: parameters})
+	}
+	return pb, nil
+}
+
+func fromPBTools(pb []*adapterspb.Tool) []libports.Tool {
+	var tools []libports.Tool
+	for _, tool := range pb {
+		tools = append(tools, libports.Tool{Name: tool.GetName(), Description: tool.GetDescription(), Parameters: fromStruct(tool.GetParameters())})
+	}
+	return tools
+}
+
+func toPBToolCall(id, name string, args map[string]interface{}) (*adapterspb.ToolCall, error) {
+	arguments, err := toStruct(args)
+	if err != nil {
+		return nil, fmt.Errorf("arguments of tool call %s: %w", name, err)
+	}
+	return &adapterspb.ToolCall{Id: id, Name: name, Arguments: arguments}, nil
+}
+
+func toPBUsage(usage libports.UsageInfo) *adapterspb.Usage {
+	return &adapterspb.Usage{
+		PromptTokens:     int32(usage.PromptTokens),
+		CompletionTokens: int32(usage.CompletionTokens),
+		TotalTokens:      int32(usage.TotalTokens),
+	}
+}
+
+func fromPBUsage(pb *adapterspb.Usage) libports.UsageInfo {
+	return libports.UsageInfo{
+		PromptTokens:     int(pb.GetPromptTokens()),
+		CompletionTokens: int(pb.GetCompletionTokens()),
+		TotalTokens:      int(pb.GetTotalTokens()),
+	}
+}
+
+func toPBResponse(resp *libports.CompletionResponse) (*adapterspb.CompletionResponse, error) {
+	pb := &adapterspb.CompletionResponse{
+		Id:           resp.ID,
+		Model:        resp.Model,
+		Message:      &adapterspb.Message{Role: resp.Message.Role, Content: resp.Message.Content, Name: resp.Message.Name},
+		FinishReason: resp.FinishReason,
+		Usage:        toPBUsage(resp.Usage),
+		CreatedAt:    toTimestamp(resp.CreatedAt),
+	}
+	for _, call := range resp.ToolCalls {
+		pbCall, err := toPBToolCall(call.ID, call.Name, call.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		pb.ToolCalls = append(pb.ToolCalls, pbCall)
+	}
+	return pb, nil
+}
+
+func fromPBResponse(pb *adapterspb.CompletionResponse) *libports.CompletionResponse {
+	resp := &libports.CompletionResponse{
+		ID:    pb.GetId(),
+		Model: pb.GetModel(),
+		Message: libports.Message{
+			Role:    pb.GetMessage().GetRole(),
+			Content: pb.GetMessage().GetContent(),
+			Name:    pb.GetMessage().GetName(),
+		},
+		FinishReason: pb.GetFinishReason(),
+		Usage:        fromPBUsage(pb.GetUsage()),
+		CreatedAt:    fromTimestamp(pb.GetCreatedAt()),
+	}
+	for _, call := range pb.GetToolCalls() {
+		resp.ToolCalls = append(resp.ToolCalls, libports.ToolCall{ID: call.GetId(), Name: call.GetName(), Arguments: fromStruct(call.GetArguments())})
+	}
+	return resp
+}
+
+func toPBGenerateRequest(req *domain.LLMRequest) (*adapterspb.GenerateRequest, error) {
+	pb := &adapterspb.GenerateRequest{
+		Model:       req.Model,
+		System:      req.System,
+		MaxTokens:   int32(req.MaxTokens),
+		Temperature: req.Temperature,
+	}
+	for _, m := range req.Messages {
+		pb.Messages = append(pb.Messages, &adapterspb.Message{Role: m.Role, Content: m.Content})
+	}
+	for _, tool := range req.Tools {
+		parameters, err := toStruct(tool.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("parameters of tool %s: %w", tool.Name, err)
+		}
+		pb.Tools = append(pb.Tools, &adapterspb.Tool{Name: tool.Name, Description: tool.Description, Parameters: parameters})
+	}
+	return pb, nil
+}
+
+func fromPBGenerateRequest(pb *adapterspb.GenerateRequest) *domain.LLMRequest {
+	req := &domain.LLMRequest{
+		Model:       pb.GetModel(),
+		System:      pb.GetSystem(),
+		MaxTokens:   int(pb.GetMaxTokens()),
+		Temperature: pb.GetTemperature(),
+	}
+	for _, m := range pb.GetMessages() {
+		req.Messages = append(req.Messages, domain.Message{Role: m.GetRole(), Content: m.GetContent()})
+	}
+	for _, tool := range pb.GetTools() {
+		req.Tools = append(req.Tools, domain.Tool{Name: tool.GetName(), Description: tool.GetDescription(), Parameters: fromStruct(tool.GetParameters())})
+	}
+	return req
+}
+
+func toPBWorker(worker libports.WorkerInfo) (*adapterspb.Worker, error) {
+	metadata, err := toStruct(worker.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("metadata of worker %s: %w", worker.ID, err)
+	}
+	return &adapterspb.Worker{
+		Id:            worker.ID,
+		Type:          string(worker.Type),
+		Status:        string(worker.Status),
+		RegisteredAt:  toTimestamp(worker.RegisteredAt),
+		LastHeartbeat: toTimestamp(worker.LastHeartbeat),
+		CurrentTask:   worker.CurrentTask,
+		PendingTasks:  int32(worker.PendingTasks),
+		Version:       worker.Version,
+		Metadata:      metadata,
+	}, nil
+}
+
+func fromPBWorker(pb *adapterspb.Worker) libports.WorkerInfo {
+	return libports.WorkerInfo{
+		ID:            pb.GetId(),
+		Type:          libports.WorkerType(pb.GetType()),
+		Status:        libports.WorkerStatus(pb.GetStatus()),
+		RegisteredAt:  fromTimestamp(pb.GetRegisteredAt()),
+		LastHeartbeat: fromTimestamp(pb.GetLastHeartbeat()),
+		CurrentTask:   pb.GetCurrentTask(),
+		PendingTasks:  int(pb.GetPendingTasks()),
+		Version:       pb.GetVersion(),
+		Metadata:      fromStruct(pb.GetMetadata()),
+	}
+}
+
+func toPBFilter(filter libports.WorkerFilter) *adapterspb.WorkerFilter {
+	pb := &adapterspb.WorkerFilter{HealthyOnly: filter.HealthyOnly}
+	for _, t := range filter.Types {
+		pb.Types = append(pb.Types, string(t))
+	}
+	for _, s := range filter.Statuses {
+		pb.Statuses = append(pb.Statuses, string(s))
+	}
+	return pb
+}
+
+func fromPBFilter(pb *adapterspb.WorkerFilter) libports.WorkerFilter {
+	filter := libports.WorkerFilter{HealthyOnly: pb.GetHealthyOnly()}
+	for _, t := range pb.GetTypes() {
+		filter.Types = append(filter.Types, libports.WorkerType(t))
+	}
+	for _, s := range pb.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, libports.WorkerStatus(s))
+	}
+	return filter
+}
@@ -0,0 +1,35 @@
+// Package rpc serves LLM clients and worker registries over gRPC, and
+// provides the clients to consume them remotely, so services other than
+// the one holding the provider keys or the registry backend, including
+// non-Go ones, can use them. The services are defined in
+// adapterspb/adapters.proto.
+//
+// LLMServer serves any ports.LLMClient; streamed completions, for clients
+// implementing StreamComplete, are server streams. RegistryServer serves
+// any ports.WorkerRegistry; registries implementing worker_registry.Watcher
+// can also be watched, as a server stream. LLMClient and WorkerRegistry
+// implement the same interfaces on a client connection, so remote ones can
+// be used in place of local ones.
+//
+// Errors cross the wire as status codes: ports.ErrNotImplemented,
+// ports.ErrInvalidRequest, ports.ErrQuotaExceeded and context errors are
+// restored on the client side, so errors.Is works as with local adapters.
+// Other errors keep their message only.
+//
+// Usage:
+//
+//	srv := grpc.NewServer()
+//	adapterspb.RegisterLLMServiceServer(srv, rpc.NewLLMServer(client, logger))
+//	adapterspb.RegisterWorkerRegistryServiceServer(srv, rpc.NewRegistryServer(workers, logger))
+//	log.Fatal(srv.Serve(lis))
+//
+// and, in another service:
+//
+//	conn, _ := grpc.NewClient("adapters:9090", grpc.WithTransportCredentials(creds))
+//	client := rpc.NewLLMClient(conn, logger)
+//	workers := rpc.NewWorkerRegistry(conn, logger)
+//
+// A remote client can't tell whether a method is implemented without
+// calling the server, so calls with an already cancelled context fail with
+// context.Canceled rather than ports.ErrNotImplemented.
+package rpc
@@ -0,0 +1,304 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/llmtest"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/rpc/adapterspb"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/memory"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/registrytest"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves the services registered by register in memory and returns a
+// connection to them
+func dial(t *testing.T, register func(s *grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func dialLLM(t *testing.T, client libports.LLMClient) *LLMClient {
+	conn := dial(t, func(s *grpc.Server) {
+		adapterspb.RegisterLLMServiceServer(s, NewLLMServer(client, zap.NewNop()))
+	})
+	return NewLLMClient(conn, zap.NewNop())
+}
+
+func dialRegistry(t *testing.T, workers libports.WorkerRegistry) *WorkerRegistry {
+	conn := dial(t, func(s *grpc.Server) {
+		adapterspb.RegisterWorkerRegistryServiceServer(s, NewRegistryServer(workers, zap.NewNop()))
+	})
+	return NewWorkerRegistry(conn, zap.NewNop())
+}
+
+// fakeClient answers with scripted replies, implementing the whole contract
+type fakeClient struct {
+	mu      sync.Mutex
+	replies []testutil.Reply
+}
+
+func (c *fakeClient) Reply(replies ...testutil.Reply) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replies = append(c.replies, replies...)
+}
+
+func (c *fakeClient) next(ctx context.Context, messages []libports.Message) (testutil.Reply, error) {
+	if len(messages) == 0 {
+		return testutil.Reply{}, fmt.Errorf("%w: no messages", ports.ErrInvalidRequest)
+	}
+
+	c.mu.Lock()
+	reply := testutil.Reply{Chunks: []string{messages[len(messages)-1].Content}}
+	if len(c.replies) > 0 {
+		reply, c.replies = c.replies[0], c.replies[1:]
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-time.After(reply.Delay):
+	case <-ctx.Done():
+		return testutil.Reply{}, ctx.Err()
+	}
+	if reply.Status != 0 {
+		return testutil.Reply{}, fmt.Errorf("API call failed: status %d: %s", reply.Status, reply.Error)
+	}
+	return reply, nil
+}
+
+func (c *fakeClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	reply, err := c.next(ctx, req.Messages)
+	if err != nil {
+		return nil, err
+	}
+	resp := &libports.CompletionResponse{
+		ID:           "resp-1",
+		Model:        req.Model,
+		Message:      libports.Message{Role: "assistant", Content: strings.Join(reply.Chunks, "")},
+		FinishReason: "stop",
+		Usage:        libports.UsageInfo{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		CreatedAt:    time.Now(),
+	}
+	for i, call := range reply.ToolCalls {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return nil, err
+		}
+		resp.ToolCalls = append(resp.ToolCalls, libports.ToolCall{ID: fmt.Sprintf("call_%d", i), Name: call.Name, Arguments: args})
+	}
+	return resp, nil
+}
+
+func (c *fakeClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	if len(tools) == 0 || tools[0].Parameters["type"] != "object" {
+		return nil, fmt.Errorf("%w: tools = %+v", ports.ErrInvalidRequest, tools)
+	}
+	return c.Complete(ctx, req)
+}
+
+func (c *fakeClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	if schema["type"] != "object" {
+		return nil, fmt.Errorf("%w: schema = %v", ports.ErrInvalidRequest, schema)
+	}
+	resp, err := c.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	structured := &libports.StructuredResponse{Usage: resp.Usage, CreatedAt: resp.CreatedAt}
+	if err := json.Unmarshal([]byte(resp.Message.Content), &structured.Data); err != nil {
+		return nil, err
+	}
+	return structured, nil
+}
+
+func (c *fakeClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	reply, err := c.next(ctx, req.Messages)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(chan libports.CompletionChunk, len(reply.Chunks))
+	for i, chunk := range reply.Chunks {
+		chunks <- libports.CompletionChunk{Delta: chunk, IsFinal: i == len(reply.Chunks)-1}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+func (c *fakeClient) GenerateCompletion(ctx context.Context, req interface{}) (interface{}, error) {
+	llmReq, ok := req.(*domain.LLMRequest)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected type %T", ports.ErrInvalidRequest, req)
+	}
+	var messages []libports.Message
+	for _, msg := range llmReq.Messages {
+		messages = append(messages, libports.Message{Role: msg.Role, Content: msg.Content})
+	}
+	resp, err := c.Complete(ctx, libports.CompletionRequest{Model: llmReq.Model, Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LLMResponse{
+		Content: resp.Message.Content,
+		Model:   resp.Model,
+		Usage:   domain.Usage{InputTokens: resp.Usage.PromptTokens, OutputTokens: resp.Usage.CompletionTokens},
+	}, nil
+}
+
+func TestLLMConformance(t *testing.T) {
+	backend := &fakeClient{}
+	client := dialLLM(t, backend)
+
+	llmtest.RunConformance(t, client, llmtest.Harness{Model: "fake", Reply: backend.Reply})
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if len(backend.replies) != 0 {
+		t.Errorf("%d scripted replies left", len(backend.replies))
+	}
+}
+
+// stubClient implements nothing but GenerateCompletion, and doesn't stream
+type stubClient struct {
+	libports.LLMClient
+}
+
+func (stubClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	return nil, fmt.Errorf("%w: Complete", ports.ErrNotImplemented)
+}
+
+func TestLLMClient_NotImplemented(t *testing.T) {
+	client := dialLLM(t, stubClient{&fakeClient{}})
+	ctx := context.Background()
+	req := libports.CompletionRequest{Messages: []libports.Message{{Role: "user", Content: "Hello"}}}
+
+	_, err := client.Complete(ctx, req)
+	if !errors.Is(err, ports.ErrNotImplemented) {
+		t.Errorf("Complete() error = %v, want ErrNotImplemented", err)
+	}
+	if err != nil && err.Error() != "not implemented: Complete" {
+		t.Errorf("Complete() error = %q, want the server's message", err)
+	}
+
+	_, err = client.StreamComplete(ctx, req)
+	if !errors.Is(err, ports.ErrNotImplemented) {
+		t.Errorf("StreamComplete() error = %v, want ErrNotImplemented", err)
+	}
+
+	// The connection keeps working
+	resp, err := client.GenerateCompletion(ctx, &domain.LLMRequest{Messages: []domain.Message{{Role: "user", Content: "Hello"}}})
+	if err != nil {
+		t.Fatalf("GenerateCompletion() error = %v", err)
+	}
+	if got := resp.(*domain.LLMResponse).Content; got != "Hello" {
+		t.Errorf("Content = %q, want Hello", got)
+	}
+}
+
+func TestLLMClient_ToolCallsAndStructuredData(t *testing.T) {
+	backend := &fakeClient{}
+	client := dialLLM(t, backend)
+	ctx := context.Background()
+	req := libports.CompletionRequest{Model: "fake", Messages: []libports.Message{{Role: "user", Content: "Plan a trip"}}}
+
+	tools := []libports.Tool{{
+		Name: "book",
+		Parameters: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"city", "nights"},
+		},
+	}}
+	backend.Reply(testutil.Reply{ToolCalls: []testutil.ToolCall{{Name: "book", Arguments: `{"city":"Paris","nights":3,"tags":["museum"]}`}}})
+	resp, err := client.CompleteWithTools(ctx, req, tools)
+	if err != nil {
+		t.Fatalf("CompleteWithTools() error = %v", err)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("ToolCalls = %+v, want one call", resp.ToolCalls)
+	}
+	args := resp.ToolCalls[0].Arguments
+	if args["city"] != "Paris" || args["nights"] != float64(3) || fmt.Sprint(args["tags"]) != "[museum]" {
+		t.Errorf("Arguments = %v, want the JSON arguments", args)
+	}
+	if resp.ID != "resp-1" || resp.Model != "fake" || resp.CreatedAt.IsZero() {
+		t.Errorf("response = %+v, want ID, model and creation time", resp)
+	}
+
+	backend.Reply(testutil.Reply{Chunks: []string{`{"nested":{"ok":true},"list":[1,"two",null]}`}})
+	structured, err := client.CompleteStructured(ctx, req, libports.JSONSchema{"type": "object"})
+	if err != nil {
+		t.Fatalf("CompleteStructured() error = %v", err)
+	}
+	data, _ := json.Marshal(structured.Data)
+	if string(data) != `{"list":[1,"two",null],"nested":{"ok":true}}` {
+		t.Errorf("Data = %s, want the JSON object", data)
+	}
+	if structured.Usage.TotalTokens != 5 {
+		t.Errorf("Usage = %+v, want 5 total tokens", structured.Usage)
+	}
+}
+
+func TestRegistryConformance(t *testing.T) {
+	registrytest.RunConformance(t, registrytest.Harness{
+		New: func(t *testing.T, ttl time.Duration) libports.WorkerRegistry {
+			return dialRegistry(t, memory.NewRegistryWithTTL(ttl, zap.NewNop()))
+		},
+		TTL: 500 * time.Millisecond,
+	})
+}
+
+func TestWorkerRegistry_WatchNotImplemented(t *testing.T) {
+	// Hides the Watch method of the memory registry
+	workers := struct{ libports.WorkerRegistry }{memory.NewRegistry(zap.NewNop())}
+	client := dialRegistry(t, workers)
+
+	_, err := client.Watch(context.Background())
+	if !errors.Is(err, ports.ErrNotImplemented) {
+		t.Errorf("Watch() error = %v, want ErrNotImplemented", err)
+	}
+}
+
+func TestCleanupStaleWorkers_InvalidTimeout(t *testing.T) {
+	server := NewRegistryServer(memory.NewRegistry(zap.NewNop()), zap.NewNop())
+	_, err := server.CleanupStaleWorkers(context.Background(), &adapterspb.CleanupStaleWorkersRequest{})
+	if !errors.Is(fromStatus(err), ports.ErrInvalidRequest) {
+		t.Errorf("CleanupStaleWorkers(no timeout) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestStatus_QuotaExceeded(t *testing.T) {
+	err := fromStatus(toStatus(fmt.Errorf("%w: tenant-a used 10 of 10 tokens in 1h0m0s", ports.ErrQuotaExceeded)))
+	if !errors.Is(err, ports.ErrQuotaExceeded) {
+		t.Errorf("error = %v, want quota exceeded", err)
+	}
+}
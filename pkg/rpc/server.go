@@ -0,0 +1,285 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aescanero/dago-adapters/pkg/llm"
+	"github.com/aescanero/dago-adapters/pkg/rpc/adapterspb"
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/domain"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// streamer is implemented by clients supporting streamed completions
+type streamer interface {
+	StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error)
+}
+
+// LLMServer serves an LLM client as an adapterspb.LLMServiceServer
+type LLMServer struct {
+	adapterspb.UnimplementedLLMServiceServer
+
+	client libports.LLMClient
+	logger *zap.Logger
+}
+
+// NewLLMServer creates a server for client
+func NewLLMServer(client libports.LLMClient, logger *zap.Logger) *LLMServer {
+	return &LLMServer{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GenerateCompletion implements adapterspb.LLMServiceServer
+func (s *LLMServer) GenerateCompletion(ctx context.Context, req *adapterspb.GenerateRequest) (*adapterspb.GenerateResponse, error) {
+	resp, err := s.client.GenerateCompletion(ctx, fromPBGenerateRequest(req))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	llmResp, ok := resp.(*domain.LLMResponse)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "client returned %T, want *domain.LLMResponse", resp)
+	}
+
+	pb := &adapterspb.GenerateResponse{
+		Content:      llmResp.Content,
+		Model:        llmResp.Model,
+		InputTokens:  int32(llmResp.Usage.InputTokens),
+		OutputTokens: int32(llmResp.Usage.OutputTokens),
+	}
+	for _, call := range llmResp.ToolCalls {
+		pbCall, err := toPBToolCall(call.ID, call.Name, call.Input)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		pb.ToolCalls = append(pb.ToolCalls, pbCall)
+	}
+	return pb, nil
+}
+
+// Complete implements adapterspb.LLMServiceServer
+func (s *LLMServer) Complete(ctx context.Context, req *adapterspb.CompleteRequest) (*adapterspb.CompletionResponse, error) {
+	resp, err := s.client.Complete(ctx, fromPBRequest(req.GetRequest()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.response(resp)
+}
+
+// CompleteWithTools implements adapterspb.LLMServiceServer
+func (s *LLMServer) CompleteWithTools(ctx context.Context, req *adapterspb.CompleteWithToolsRequest) (*adapterspb.CompletionResponse, error) {
+	resp, err := s.client.CompleteWithTools(ctx, fromPBRequest(req.GetRequest()), fromPBTools(req.GetTools()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.response(resp)
+}
+
+func (s *LLMServer) response(resp *libports.CompletionResponse) (*adapterspb.CompletionResponse, error) {
+	pb, err := toPBResponse(resp)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return pb, nil
+}
+
+// CompleteStructured implements adapterspb.LLMServiceServer
+func (s *LLMServer) CompleteStructured(ctx context.Context, req *adapterspb.CompleteStructuredRequest) (*adapterspb.StructuredResponse, error) {
+	resp, err := s.client.CompleteStructured(ctx, fromPBRequest(req.GetRequest()), libports.JSONSchema(fromStruct(req.GetSchema())))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	data, err := toStruct(resp.Data)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "structured data: %v", err)
+	}
+	return &adapterspb.StructuredResponse{
+		Data:      data,
+		Usage:     toPBUsage(resp.Usage),
+		CreatedAt: toTimestamp(resp.CreatedAt),
+	}, nil
+}
+
+// StreamComplete implements adapterspb.LLMServiceServer. A stream ending
+// without a final chunk fails with the Aborted code.
+func (s *LLMServer) StreamComplete(req *adapterspb.CompleteRequest, stream adapterspb.LLMService_StreamCompleteServer) error {
+	client, ok := s.client.(streamer)
+	if !ok {
+		return status.Error(codes.Unimplemented, "client doesn't support streaming")
+	}
+
+	ctx := stream.Context()
+	chunks, err := client.StreamComplete(ctx, fromPBRequest(req.GetRequest()))
+	if err != nil {
+		return toStatus(err)
+	}
+	if err := stream.SendHeader(metadata.Pairs(streamStartedHeader, "true")); err != nil {
+		// Returning cancels ctx, ending the client's stream
+		return err
+	}
+
+	err = llm.StreamTo(ctx, chunks, func(chunk libports.CompletionChunk) error {
+		return stream.Send(&adapterspb.CompletionChunk{Delta: chunk.Delta, IsFinal: chunk.IsFinal})
+	})
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return toStatus(ctx.Err())
+	case errors.Is(err, llm.ErrStreamIncomplete):
+		s.logger.Warn("Completion stream ended early")
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		s.logger.Warn("Failed to send completion chunk", zap.Error(err))
+		return err
+	}
+}
+
+// RegistryServer serves a worker registry as an
+// adapterspb.WorkerRegistryServiceServer
+type RegistryServer struct {
+	adapterspb.UnimplementedWorkerRegistryServiceServer
+
+	registry libports.WorkerRegistry
+	logger   *zap.Logger
+}
+
+// NewRegistryServer creates a server for registry
+func NewRegistryServer(registry libports.WorkerRegistry, logger *zap.Logger) *RegistryServer {
+	return &RegistryServer{
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// Register implements adapterspb.WorkerRegistryServiceServer
+func (s *RegistryServer) Register(ctx context.Context, req *adapterspb.RegisterRequest) (*emptypb.Empty, error) {
+	if err := s.registry.Register(ctx, fromPBWorker(req.GetWorker())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Unregister implements adapterspb.WorkerRegistryServiceServer
+func (s *RegistryServer) Unregister(ctx context.Context, req *adapterspb.UnregisterRequest) (*emptypb.Empty, error) {
+	if err := s.registry.Unregister(ctx, req.GetWorkerId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Heartbeat implements adapterspb.WorkerRegistryServiceServer
+func (s *RegistryServer) Heartbeat(ctx context.Context, req *adapterspb.HeartbeatRequest) (*emptypb.Empty, error) {
+	err := s.registry.Heartbeat(ctx, req.GetWorkerId(), libports.WorkerStatus(req.GetStatus()), req.GetCurrentTask())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetWorker implements adapterspb.WorkerRegistryServiceServer
+func (s *RegistryServer) GetWorker(ctx context.Context, req *adapterspb.GetWorkerRequest) (*adapterspb.Worker, error) {
+	worker, err := s.registry.GetWorker(ctx, req.GetWorkerId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.worker(*worker)
+}
+
+func (s *RegistryServer) worker(worker libports.WorkerInfo) (*adapterspb.Worker, error) {
+	pb, err := toPBWorker(worker)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return pb, nil
+}
+
+// ListWorkers implements adapterspb.WorkerRegistryServiceServer
+func (s *RegistryServer) ListWorkers(ctx context.Context, req *adapterspb.ListWorkersRequest) (*adapterspb.ListWorkersResponse, error) {
+	workers, err := s.registry.ListWorkers(ctx, fromPBFilter(req.GetFilter()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &adapterspb.ListWorkersResponse{}
+	for _, worker := range workers {
+		pb, err := s.worker(worker)
+		if err != nil {
+			return nil, err
+		}
+		resp.Workers = append(resp.Workers, pb)
+	}
+	return resp, nil
+}
+
+// GetWorkerStats implements adapterspb.WorkerRegistryServiceServer
+func (s *RegistryServer) GetWorkerStats(ctx context.Context, req *adapterspb.GetWorkerStatsRequest) (*adapterspb.WorkerStats, error) {
+	stats, err := s.registry.GetWorkerStats(ctx, libports.WorkerType(req.GetType()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adapterspb.WorkerStats{
+		Type:              string(stats.Type),
+		TotalWorkers:      int32(stats.TotalWorkers),
+		IdleWorkers:       int32(stats.IdleWorkers),
+		BusyWorkers:       int32(stats.BusyWorkers),
+		UnhealthyWorkers:  int32(stats.UnhealthyWorkers),
+		TotalPendingTasks: int32(stats.TotalPendingTasks),
+	}, nil
+}
+
+// CleanupStaleWorkers implements adapterspb.WorkerRegistryServiceServer
+func (s *RegistryServer) CleanupStaleWorkers(ctx context.Context, req *adapterspb.CleanupStaleWorkersRequest) (*adapterspb.CleanupStaleWorkersResponse, error) {
+	if err := req.GetTimeout().CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "timeout: %v", err)
+	}
+	removed, err := s.registry.CleanupStaleWorkers(ctx, req.GetTimeout().AsDuration())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adapterspb.CleanupStaleWorkersResponse{Removed: int32(removed)}, nil
+}
+
+// Watch implements adapterspb.WorkerRegistryServiceServer, for registries
+// implementing worker_registry.Watcher
+func (s *RegistryServer) Watch(_ *adapterspb.WatchRequest, stream adapterspb.WorkerRegistryService_WatchServer) error {
+	watcher, ok := s.registry.(registry.Watcher)
+	if !ok {
+		return status.Error(codes.Unimplemented, "registry doesn't support watching")
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	events, err := watcher.Watch(ctx)
+	if err != nil {
+		return toStatus(err)
+	}
+	if err := stream.SendHeader(metadata.Pairs(streamStartedHeader, "true")); err != nil {
+		return err
+	}
+
+	for event := range events {
+		pb := &adapterspb.WatchEvent{Type: string(event.Type), WorkerId: event.WorkerID}
+		if event.Worker != nil {
+			worker, err := s.worker(*event.Worker)
+			if err != nil {
+				return err
+			}
+			pb.Worker = worker
+		}
+		if err := stream.Send(pb); err != nil {
+			s.logger.Warn("Failed to send watch event", zap.Error(err))
+			return err
+		}
+	}
+	if err := stream.Context().Err(); err != nil {
+		return toStatus(err)
+	}
+	return status.Error(codes.Unavailable, "registry watch ended")
+}
@@ -0,0 +1,30 @@
+// Package scheduler provides adapters for the ports.Scheduler interface
+// (pkg/ports in this repository), which triggers workflows on cron schedules
+// without an external cron calling an API.
+//
+// Schedules are stored centrally and every replica runs the scheduler; a
+// leader elected through the store fires due runs, and another replica takes
+// over if it goes away. Runs that fell due while no leader was running are
+// handled by each schedule's ports.MissedRunPolicy: skipped, fired once, or
+// all fired up to a catch-up limit.
+//
+// Available implementations:
+//   - redis: Schedules in a hash and a sorted set of next runs, with a lease lock
+//
+// Usage:
+//
+//	sched := redis.NewScheduler(client, logger)
+//
+//	err := sched.Put(ctx, ports.Schedule{
+//		ID:         "daily-report",
+//		Cron:       "0 9 * * 1-5",
+//		Timezone:   "Europe/Madrid",
+//		Payload:    []byte(`{"graph_id":"report"}`),
+//		MissedRuns: ports.MissedRunOnce,
+//	})
+//
+//	err = sched.Run(ctx, func(ctx context.Context, run ports.ScheduledRun) error {
+//		_, err := queue.Publish(ctx, "workflows", ports.Task{Payload: run.Payload})
+//		return err
+//	})
+package scheduler
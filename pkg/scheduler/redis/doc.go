@@ -0,0 +1,19 @@
+// Package redis provides a Redis implementation of ports.Scheduler.
+//
+// Schedules are stored as JSON in a hash, and their IDs in a sorted set
+// scored by next run. Every replica runs the scheduler; the one holding a
+// lease key fires due runs, moving each schedule to its next run with a
+// compare-and-set script so that no run fires twice.
+//
+// Keys (with the default prefix):
+//   - dago:scheduler:schedules - Hash of schedule ID to schedule JSON
+//   - dago:scheduler:due - Sorted set of schedule IDs by next run
+//   - dago:scheduler:leader - Lease of the instance firing runs
+//
+// Usage:
+//
+//	sched := redis.NewScheduler(client, logger)
+//	err := sched.Put(ctx, ports.Schedule{ID: "nightly", Cron: "@daily", Payload: payload})
+//
+//	go sched.Run(ctx, handler)
+package redis
@@ -0,0 +1,385 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/scheduler"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default prefix of scheduler keys
+	defaultPrefix = "dago:scheduler:"
+
+	// DefaultPollInterval is how often due schedules are checked
+	DefaultPollInterval = time.Second
+
+	// DefaultLeaseTTL is how long leadership lasts without renewal, and so
+	// how long runs wait when the leader goes away
+	DefaultLeaseTTL = 15 * time.Second
+
+	// DefaultGracePeriod is how late a run may fire before it counts as
+	// missed
+	DefaultGracePeriod = time.Minute
+
+	// DefaultCatchUpLimit is the most missed runs fired per schedule with
+	// ports.MissedRunAll
+	DefaultCatchUpLimit = 100
+)
+
+// acquireLeaseScript takes the leader lease if it is free, or renews it if
+// this instance already holds it.
+//
+// KEYS[1] = lease key
+// ARGV[1] = holder ID, ARGV[2] = lease TTL in milliseconds
+var acquireLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseLeaseScript deletes the leader lease only if this instance holds it.
+//
+// KEYS[1] = lease key
+// ARGV[1] = holder ID
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// advanceScript moves a schedule to its next run if it wasn't changed since
+// it was read, so that a run is fired at most once even when a former leader
+// is still ticking.
+//
+// KEYS[1] = schedules hash, KEYS[2] = due sorted set
+// ARGV[1] = schedule ID, ARGV[2] = schedule JSON as read,
+// ARGV[3] = advanced schedule JSON, ARGV[4] = next run in Unix milliseconds
+var advanceScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[3])
+redis.call('ZADD', KEYS[2], ARGV[4], ARGV[1])
+return 1
+`)
+
+// Scheduler implements ports.Scheduler using Redis. Schedules are JSON in a
+// hash, and their IDs in a sorted set scored by next run. Every replica can
+// Run the scheduler: a lease in Redis elects the one that fires due runs.
+type Scheduler struct {
+	client redis.UniversalClient
+	logger *zap.Logger
+	prefix string
+	holder string
+	now    func() time.Time
+
+	pollInterval time.Duration
+	leaseTTL     time.Duration
+	grace        time.Duration
+	catchUpLimit int
+
+	leader atomic.Bool
+}
+
+// NewScheduler creates a new Redis scheduler
+func NewScheduler(client redis.UniversalClient, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		client:       client,
+		logger:       logger,
+		prefix:       defaultPrefix,
+		holder:       holderID(),
+		now:          time.Now,
+		pollInterval: DefaultPollInterval,
+		leaseTTL:     DefaultLeaseTTL,
+		grace:        DefaultGracePeriod,
+		catchUpLimit: DefaultCatchUpLimit,
+	}
+}
+
+// SetPrefix sets the prefix of scheduler keys, e.g. to isolate tenants
+func (s *Scheduler) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+// SetPollInterval sets how often due schedules are checked. Call it before
+// Run.
+func (s *Scheduler) SetPollInterval(interval time.Duration) {
+	s.pollInterval = interval
+}
+
+// SetLeaseTTL sets how long leadership lasts without renewal. It must be
+// longer than the poll interval; a standby takes over at most this long
+// after the leader goes away. Call it before Run.
+func (s *Scheduler) SetLeaseTTL(ttl time.Duration) {
+	s.leaseTTL = ttl
+}
+
+// SetGracePeriod sets how late a run may fire before it counts as missed
+// and is handled by the schedule's missed-run policy
+func (s *Scheduler) SetGracePeriod(grace time.Duration) {
+	s.grace = grace
+}
+
+// SetCatchUpLimit sets the most missed runs fired per schedule with
+// ports.MissedRunAll; older ones are dropped
+func (s *Scheduler) SetCatchUpLimit(n int) {
+	s.catchUpLimit = n
+}
+
+// IsLeader reports whether this instance fires due runs
+func (s *Scheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+func (s *Scheduler) schedulesKey() string { return s.prefix + "schedules" }
+func (s *Scheduler) dueKey() string       { return s.prefix + "due" }
+func (s *Scheduler) leaseKey() string     { return s.prefix + "leader" }
+
+// Put creates or replaces a schedule, computing its next run (ports.Scheduler
+// interface). The last run of a replaced schedule is kept.
+func (s *Scheduler) Put(ctx context.Context, schedule ports.Schedule) error {
+	spec, err := scheduler.Parse(schedule)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.Get(ctx, schedule.ID)
+	switch {
+	case err == nil:
+		schedule.LastRun = existing.LastRun
+	case !errors.Is(err, ports.ErrScheduleNotFound):
+		return err
+	}
+	schedule.NextRun = spec.Next(s.now())
+
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, s.schedulesKey(), schedule.ID, data)
+	pipe.ZAdd(ctx, s.dueKey(), redis.Z{Score: float64(schedule.NextRun.UnixMilli()), Member: schedule.ID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store schedule: %w", err)
+	}
+
+	s.logger.Debug("schedule stored",
+		zap.String("schedule_id", schedule.ID),
+		zap.String("cron", schedule.Cron),
+		zap.Time("next_run", schedule.NextRun))
+	return nil
+}
+
+// Get returns a schedule (ports.Scheduler interface)
+func (s *Scheduler) Get(ctx context.Context, id string) (*ports.Schedule, error) {
+	data, err := s.client.HGet(ctx, s.schedulesKey(), id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: %s", ports.ErrScheduleNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	var schedule ports.Schedule
+	if err := json.Unmarshal([]byte(data), &schedule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// List returns all schedules ordered by ID (ports.Scheduler interface)
+func (s *Scheduler) List(ctx context.Context) ([]ports.Schedule, error) {
+	all, err := s.client.HGetAll(ctx, s.schedulesKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	schedules := make([]ports.Schedule, 0, len(all))
+	for id, data := range all {
+		var schedule ports.Schedule
+		if err := json.Unmarshal([]byte(data), &schedule); err != nil {
+			s.logger.Warn("skipping invalid schedule", zap.String("schedule_id", id), zap.Error(err))
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	return schedules, nil
+}
+
+// Remove deletes a schedule (ports.Scheduler interface)
+func (s *Scheduler) Remove(ctx context.Context, id string) error {
+	pipe := s.client.TxPipeline()
+	pipe.HDel(ctx, s.schedulesKey(), id)
+	pipe.ZRem(ctx, s.dueKey(), id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove schedule: %w", err)
+	}
+	return nil
+}
+
+// Run fires due schedules with handler until ctx is cancelled, then returns
+// nil (ports.Scheduler interface). Only the instance holding the leader
+// lease fires runs; the others stand by to take over.
+//
+// A schedule is moved to its next run before its handler is called, so runs
+// are fired at most once: a handler that fails, or a leader that crashes
+// before calling it, loses the run. Handlers are called one at a time and
+// should return quickly, e.g. after publishing a task.
+func (s *Scheduler) Run(ctx context.Context, handler ports.ScheduleHandler) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		if s.leader.Load() {
+			// Hand over immediately instead of waiting for the lease to expire
+			releaseCtx := context.WithoutCancel(ctx)
+			if err := releaseLeaseScript.Run(releaseCtx, s.client, []string{s.leaseKey()}, s.holder).Err(); err != nil {
+				s.logger.Warn("failed to release scheduler lease", zap.Error(err))
+			}
+			s.setLeader(false)
+		}
+	}()
+
+	for {
+		acquired, err := acquireLeaseScript.Run(ctx, s.client, []string{s.leaseKey()},
+			s.holder, s.leaseTTL.Milliseconds()).Int()
+		if err != nil && ctx.Err() == nil {
+			s.logger.Warn("failed to acquire scheduler lease", zap.Error(err))
+		}
+		s.setLeader(err == nil && acquired == 1)
+
+		if s.leader.Load() {
+			if err := s.tick(ctx, handler); err != nil && ctx.Err() == nil {
+				s.logger.Warn("failed to fire due schedules", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) setLeader(leader bool) {
+	if s.leader.Swap(leader) != leader {
+		s.logger.Info("scheduler leadership changed",
+			zap.String("holder", s.holder),
+			zap.Bool("leader", leader))
+	}
+}
+
+// tick fires the runs of every schedule that is due
+func (s *Scheduler) tick(ctx context.Context, handler ports.ScheduleHandler) error {
+	now := s.now()
+	ids, err := s.client.ZRangeByScore(ctx, s.dueKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read due schedules: %w", err)
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.fire(ctx, id, now, handler); err != nil {
+			s.logger.Warn("failed to fire schedule", zap.String("schedule_id", id), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// fire advances a due schedule and calls handler for its runs
+func (s *Scheduler) fire(ctx context.Context, id string, now time.Time, handler ports.ScheduleHandler) error {
+	data, err := s.client.HGet(ctx, s.schedulesKey(), id).Result()
+	if errors.Is(err, redis.Nil) {
+		// Removed since it was read
+		return s.client.ZRem(ctx, s.dueKey(), id).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	var schedule ports.Schedule
+	if err := json.Unmarshal([]byte(data), &schedule); err != nil {
+		return fmt.Errorf("failed to unmarshal schedule: %w", err)
+	}
+	spec, err := scheduler.Parse(schedule)
+	if err != nil {
+		return err
+	}
+
+	runs, next := spec.Due(schedule.NextRun, now, schedule.MissedRuns, s.grace, s.catchUpLimit)
+	advanced := schedule
+	advanced.NextRun = next
+	if len(runs) > 0 {
+		advanced.LastRun = runs[len(runs)-1]
+	}
+	newData, err := json.Marshal(advanced)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	ok, err := advanceScript.Run(ctx, s.client, []string{s.schedulesKey(), s.dueKey()},
+		id, data, newData, next.UnixMilli()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to advance schedule: %w", err)
+	}
+	if ok == 0 {
+		// Replaced, removed or fired by another instance since it was read
+		return nil
+	}
+
+	if len(runs) == 0 {
+		s.logger.Info("skipped missed runs",
+			zap.String("schedule_id", id),
+			zap.Time("due", schedule.NextRun),
+			zap.Time("next_run", next))
+	}
+	for _, at := range runs {
+		run := ports.ScheduledRun{ScheduleID: id, ScheduledAt: at, Payload: schedule.Payload}
+		if err := handler(ctx, run); err != nil {
+			s.logger.Warn("schedule handler failed",
+				zap.String("schedule_id", id),
+				zap.Time("scheduled_at", at),
+				zap.Error(err))
+			continue
+		}
+		s.logger.Debug("schedule fired",
+			zap.String("schedule_id", id),
+			zap.Time("scheduled_at", at))
+	}
+	return nil
+}
+
+// holderID identifies this instance as lease holder
+func holderID() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,265 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ ports.Scheduler = (*Scheduler)(nil)
+
+// clock is a settable time source shared by test schedulers
+type clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *clock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+// recorder is a handler recording the runs it was called with
+type recorder struct {
+	mu   sync.Mutex
+	runs []ports.ScheduledRun
+}
+
+func (r *recorder) handle(ctx context.Context, run ports.ScheduledRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *recorder) times() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var times []time.Time
+	for _, run := range r.runs {
+		times = append(times, run.ScheduledAt.UTC())
+	}
+	return times
+}
+
+func newTestScheduler(t *testing.T, mr *miniredis.Miniredis, c *clock) *Scheduler {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	s := NewScheduler(client, zap.NewNop())
+	s.now = c.now
+	return s
+}
+
+func hour(h int) time.Time {
+	return time.Date(2026, 1, 10, h, 0, 0, 0, time.UTC)
+}
+
+func TestScheduler_PutGetListRemove(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	s := newTestScheduler(t, mr, &clock{t: hour(1).Add(10 * time.Minute)})
+
+	if err := s.Put(ctx, ports.Schedule{ID: "b", Cron: "not a cron"}); err == nil {
+		t.Error("Put() with an invalid cron expression succeeded")
+	}
+
+	for _, id := range []string{"b", "a"} {
+		if err := s.Put(ctx, ports.Schedule{ID: id, Cron: "@hourly", Payload: []byte(id)}); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	got, err := s.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !got.NextRun.Equal(hour(2)) || string(got.Payload) != "a" {
+		t.Errorf("Get() = %+v, want next run %v", got, hour(2))
+	}
+	if score, _ := mr.ZScore("dago:scheduler:due", "a"); int64(score) != hour(2).UnixMilli() {
+		t.Errorf("due score = %v, want %d", score, hour(2).UnixMilli())
+	}
+
+	list, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "a" || list[1].ID != "b" {
+		t.Errorf("List() = %+v, want a and b", list)
+	}
+
+	if err := s.Remove(ctx, "a"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := s.Get(ctx, "a"); !errors.Is(err, ports.ErrScheduleNotFound) {
+		t.Errorf("Get() after Remove() error = %v, want not found", err)
+	}
+	if err := s.Remove(ctx, "a"); err != nil {
+		t.Errorf("Remove() of a missing schedule error = %v", err)
+	}
+}
+
+func TestScheduler_TickFiresDueRuns(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := &clock{t: hour(1).Add(10 * time.Minute)}
+	s := newTestScheduler(t, mr, c)
+	rec := &recorder{}
+
+	if err := s.Put(ctx, ports.Schedule{ID: "a", Cron: "@hourly", Payload: []byte("p")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.tick(ctx, rec.handle); err != nil {
+		t.Fatalf("tick() error = %v", err)
+	}
+	if len(rec.times()) != 0 {
+		t.Fatalf("fired before due: %v", rec.times())
+	}
+
+	c.set(hour(2).Add(2 * time.Second))
+	_ = s.tick(ctx, rec.handle)
+	_ = s.tick(ctx, rec.handle)
+	times := rec.times()
+	if len(times) != 1 || !times[0].Equal(hour(2)) {
+		t.Fatalf("runs = %v, want one at %v", times, hour(2))
+	}
+	if string(rec.runs[0].Payload) != "p" || rec.runs[0].ScheduleID != "a" {
+		t.Errorf("run = %+v", rec.runs[0])
+	}
+
+	got, _ := s.Get(ctx, "a")
+	if !got.NextRun.Equal(hour(3)) || !got.LastRun.Equal(hour(2)) {
+		t.Errorf("schedule = next %v, last %v; want %v, %v", got.NextRun, got.LastRun, hour(3), hour(2))
+	}
+}
+
+func TestScheduler_MissedRuns(t *testing.T) {
+	tests := []struct {
+		policy ports.MissedRunPolicy
+		want   []time.Time
+	}{
+		{ports.MissedRunSkip, nil},
+		{ports.MissedRunOnce, []time.Time{hour(4)}},
+		{ports.MissedRunAll, []time.Time{hour(3), hour(4)}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			ctx := context.Background()
+			mr := miniredis.RunT(t)
+			c := &clock{t: hour(1).Add(10 * time.Minute)}
+			s := newTestScheduler(t, mr, c)
+			s.SetCatchUpLimit(2)
+			rec := &recorder{}
+
+			if err := s.Put(ctx, ports.Schedule{ID: "a", Cron: "@hourly", MissedRuns: tt.policy}); err != nil {
+				t.Fatal(err)
+			}
+
+			// Nobody ran between 02:00 and 04:30
+			c.set(hour(4).Add(30 * time.Minute))
+			_ = s.tick(ctx, rec.handle)
+
+			times := rec.times()
+			if len(times) != len(tt.want) {
+				t.Fatalf("runs = %v, want %v", times, tt.want)
+			}
+			for i := range times {
+				if !times[i].Equal(tt.want[i]) {
+					t.Errorf("runs[%d] = %v, want %v", i, times[i], tt.want[i])
+				}
+			}
+			if got, _ := s.Get(ctx, "a"); !got.NextRun.Equal(hour(5)) {
+				t.Errorf("next run = %v, want %v", got.NextRun, hour(5))
+			}
+		})
+	}
+}
+
+func TestScheduler_FiresOnceAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := &clock{t: hour(1).Add(10 * time.Minute)}
+	a, b := newTestScheduler(t, mr, c), newTestScheduler(t, mr, c)
+	rec := &recorder{}
+
+	if err := a.Put(ctx, ports.Schedule{ID: "a", Cron: "@hourly"}); err != nil {
+		t.Fatal(err)
+	}
+	c.set(hour(2))
+
+	// A former leader still ticking doesn't fire the run again
+	var wg sync.WaitGroup
+	for _, s := range []*Scheduler{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = s.tick(ctx, rec.handle)
+		}()
+	}
+	wg.Wait()
+
+	if times := rec.times(); len(times) != 1 {
+		t.Errorf("runs = %v, want one", times)
+	}
+}
+
+func TestScheduler_RunElectsLeader(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := &clock{t: hour(1)}
+	a, b := newTestScheduler(t, mr, c), newTestScheduler(t, mr, c)
+	for _, s := range []*Scheduler{a, b} {
+		s.SetPollInterval(10 * time.Millisecond)
+	}
+	rec := &recorder{}
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	doneA := make(chan struct{})
+	go func() {
+		_ = a.Run(ctxA, rec.handle)
+		close(doneA)
+	}()
+	waitFor(t, a.IsLeader)
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+	go func() { _ = b.Run(ctxB, rec.handle) }()
+	time.Sleep(50 * time.Millisecond)
+	if b.IsLeader() {
+		t.Fatal("both instances are leaders")
+	}
+
+	// The leader hands over when it stops
+	cancelA()
+	<-doneA
+	if a.IsLeader() {
+		t.Error("stopped instance is still leader")
+	}
+	waitFor(t, b.IsLeader)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/robfig/cron/v3"
+)
+
+// Parser of Schedule.Cron: five fields and descriptors
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Spec is a parsed schedule
+type Spec struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// Parse validates a schedule's cron expression, timezone and missed-run
+// policy
+func Parse(schedule ports.Schedule) (*Spec, error) {
+	if schedule.ID == "" {
+		return nil, fmt.Errorf("schedule ID is required")
+	}
+	parsed, err := parser.Parse(schedule.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", schedule.Cron, err)
+	}
+	location := time.UTC
+	if schedule.Timezone != "" {
+		if location, err = time.LoadLocation(schedule.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", schedule.Timezone, err)
+		}
+	}
+	switch schedule.MissedRuns {
+	case "", ports.MissedRunSkip, ports.MissedRunOnce, ports.MissedRunAll:
+	default:
+		return nil, fmt.Errorf("invalid missed-run policy %q", schedule.MissedRuns)
+	}
+	return &Spec{schedule: parsed, location: location}, nil
+}
+
+// Next returns the first run after t
+func (s *Spec) Next(t time.Time) time.Time {
+	return s.schedule.Next(t.In(s.location))
+}
+
+// Due returns the runs to fire at now for a schedule whose next run was
+// due, and the next run after now. Runs due within grace of now are fired
+// as they are; older ones were missed and are handled by policy.
+// MissedRunAll fires at most limit missed runs, the newest ones.
+func (s *Spec) Due(due, now time.Time, policy ports.MissedRunPolicy, grace time.Duration, limit int) ([]time.Time, time.Time) {
+	var missed, onTime []time.Time
+	for t := due; !t.IsZero() && !t.After(now); t = s.Next(t) {
+		if now.Sub(t) <= grace {
+			onTime = append(onTime, t)
+			continue
+		}
+		switch policy {
+		case ports.MissedRunAll:
+			// Keep the newest limit runs of long outages
+			missed = append(missed, t)
+			if len(missed) > limit {
+				missed = missed[1:]
+			}
+		case ports.MissedRunOnce:
+			missed = append(missed[:0], t)
+		}
+	}
+	return append(missed, onTime...), s.Next(now)
+}
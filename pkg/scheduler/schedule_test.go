@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule ports.Schedule
+		wantErr  bool
+	}{
+		{"five fields", ports.Schedule{ID: "a", Cron: "0 9 * * 1-5"}, false},
+		{"descriptor", ports.Schedule{ID: "a", Cron: "@every 15m"}, false},
+		{"timezone", ports.Schedule{ID: "a", Cron: "@daily", Timezone: "Europe/Madrid"}, false},
+		{"policy", ports.Schedule{ID: "a", Cron: "@daily", MissedRuns: ports.MissedRunAll}, false},
+		{"missing ID", ports.Schedule{Cron: "@daily"}, true},
+		{"seconds field", ports.Schedule{ID: "a", Cron: "0 0 9 * * *"}, true},
+		{"bad timezone", ports.Schedule{ID: "a", Cron: "@daily", Timezone: "Mars/Olympus"}, true},
+		{"bad policy", ports.Schedule{ID: "a", Cron: "@daily", MissedRuns: "sometimes"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.schedule); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpec_NextTimezone(t *testing.T) {
+	spec, err := Parse(ports.Schedule{ID: "a", Cron: "0 9 * * *", Timezone: "America/New_York"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := spec.Next(time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 1, 10, 14, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Next() = %v, want %v", next.UTC(), want)
+	}
+}
+
+func TestSpec_Due(t *testing.T) {
+	spec, err := Parse(ports.Schedule{ID: "a", Cron: "@hourly"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := func(h int) time.Time { return time.Date(2026, 1, 10, h, 0, 0, 0, time.UTC) }
+	// Due at 01:00, checked at 05:00:30 after an outage: 01-04 were missed,
+	// 05 is on time
+	due, now := hour(1), hour(5).Add(30*time.Second)
+
+	tests := []struct {
+		policy ports.MissedRunPolicy
+		limit  int
+		want   []time.Time
+	}{
+		{ports.MissedRunSkip, 100, []time.Time{hour(5)}},
+		{"", 100, []time.Time{hour(5)}},
+		{ports.MissedRunOnce, 100, []time.Time{hour(4), hour(5)}},
+		{ports.MissedRunAll, 100, []time.Time{hour(1), hour(2), hour(3), hour(4), hour(5)}},
+		{ports.MissedRunAll, 2, []time.Time{hour(3), hour(4), hour(5)}},
+	}
+	for _, tt := range tests {
+		runs, next := spec.Due(due, now, tt.policy, time.Minute, tt.limit)
+		if len(runs) != len(tt.want) {
+			t.Errorf("Due(%q, %d) = %v, want %v", tt.policy, tt.limit, runs, tt.want)
+			continue
+		}
+		for i := range runs {
+			if !runs[i].Equal(tt.want[i]) {
+				t.Errorf("Due(%q, %d)[%d] = %v, want %v", tt.policy, tt.limit, i, runs[i], tt.want[i])
+			}
+		}
+		if !next.Equal(hour(6)) {
+			t.Errorf("Due(%q) next = %v, want %v", tt.policy, next, hour(6))
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package schema generates the JSON schemas of CompleteStructured from Go
+// structs and decodes structured responses back into them, so callers of
+// structured output work with typed values instead of map[string]interface{}.
+//
+// Properties follow encoding/json: they are named by the json tag, fields
+// tagged "-" and unexported fields are left out, and embedded structs are
+// flattened. Fields are required unless tagged omitempty or omitzero, and
+// objects don't allow additional properties, as providers' strict
+// structured output modes expect. Two more tags describe fields to the
+// model:
+//
+//   - description: the property description
+//   - enum: the comma-separated allowed values, of a string or number field
+//
+// Usage:
+//
+//	type Ticket struct {
+//		Title    string   `json:"title" description:"One-line summary of the issue"`
+//		Priority string   `json:"priority" enum:"low,medium,high"`
+//		Labels   []string `json:"labels,omitempty"`
+//	}
+//
+//	ticket, resp, err := schema.Complete[Ticket](ctx, client, req)
+//	fmt.Println(ticket.Priority, resp.Usage.TotalTokens)
+//
+// Validate checks decoded JSON against a schema, for providers whose
+// structured output isn't guaranteed to conform, listing each violation
+// with its path.
+//
+// Recursive types are rejected, since the structured output modes of most
+// providers don't support schema references.
+package schema
@@ -0,0 +1,254 @@
+package schema
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// For returns the JSON schema of T, which must be a struct or a pointer to
+// one. See the package documentation for the tags it reads.
+func For[T any]() (libports.JSONSchema, error) {
+	return Generate(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Generate returns the JSON schema of a struct type
+func Generate(t reflect.Type) (libports.JSONSchema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema of %s: structured output must be an object, got %s", t, t.Kind())
+	}
+
+	g := &generator{visiting: make(map[reflect.Type]bool)}
+	s, err := g.schema(t)
+	if err != nil {
+		return nil, fmt.Errorf("schema of %s: %w", t, err)
+	}
+	return libports.JSONSchema(s), nil
+}
+
+// Decode unmarshals the data of a structured response into v, a pointer to
+// the struct its schema was generated from
+func Decode(resp *libports.StructuredResponse, v interface{}) error {
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode structured data: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("structured data doesn't match %T: %w", v, err)
+	}
+	return nil
+}
+
+// Complete calls CompleteStructured with the schema of T and decodes the
+// data into a T. The response is returned for its usage.
+func Complete[T any](ctx context.Context, client libports.LLMClient, req libports.CompletionRequest) (*T, *libports.StructuredResponse, error) {
+	s, err := For[T]()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := client.CompleteStructured(ctx, req, s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v T
+	if err := Decode(resp, &v); err != nil {
+		return nil, resp, err
+	}
+	return &v, resp, nil
+}
+
+// generator builds schemas, rejecting recursive types, which can't be
+// expressed without references that providers' structured output modes
+// don't support
+type generator struct {
+	visiting map[reflect.Type]bool
+}
+
+func (g *generator) schema(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == rawMessageType:
+		return map[string]interface{}{}, nil
+	case reflect.PointerTo(t).Implements(textMarshalerType) && t.Kind() != reflect.Struct:
+		return map[string]interface{}{"type": "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			s["minItems"] = t.Len()
+			s["maxItems"] = t.Len()
+		}
+		return s, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+
+	case reflect.Struct:
+		if g.visiting[t] {
+			return nil, fmt.Errorf("recursive type %s", t)
+		}
+		g.visiting[t] = true
+		defer delete(g.visiting, t)
+
+		s := map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		}
+		required := []string{}
+		if err := g.fields(t, s["properties"].(map[string]interface{}), &required); err != nil {
+			return nil, err
+		}
+		s["required"] = required
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// fields adds the properties of the exported fields of struct t, and of its
+// embedded structs as encoding/json flattens them
+func (g *generator) fields(t reflect.Type, properties map[string]interface{}, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := g.fields(embedded, properties, required); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		s, err := g.schema(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			s["description"] = description
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values, err := enumValues(field.Type, enum)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			s["enum"] = values
+		}
+
+		properties[name] = s
+		if !hasOption(options, "omitempty") && !hasOption(options, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+	return nil
+}
+
+// enumValues parses the comma-separated values of an enum tag as the field's
+// type
+func enumValues(t reflect.Type, enum string) ([]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var values []interface{}
+	for _, raw := range strings.Split(enum, ",") {
+		raw = strings.TrimSpace(raw)
+		var (
+			value interface{}
+			err   error
+		)
+		switch t.Kind() {
+		case reflect.String:
+			value = raw
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value, err = strconv.ParseInt(raw, 10, 64)
+		case reflect.Float32, reflect.Float64:
+			value, err = strconv.ParseFloat(raw, 64)
+		default:
+			return nil, fmt.Errorf("enum of unsupported type %s", t)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid enum value %q: %w", raw, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func hasOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
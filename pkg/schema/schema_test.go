@@ -0,0 +1,151 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+type Address struct {
+	City    string `json:"city"`
+	Country string `json:"country,omitempty" description:"ISO 3166-1 alpha-2 code"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Person struct {
+	Timestamps
+
+	Name     string            `json:"name" description:"Full name"`
+	Born     int               `json:"born"`
+	Height   *float64          `json:"height,omitempty"`
+	Role     string            `json:"role" enum:"author, editor"`
+	Rating   int               `json:"rating" enum:"1,2,3"`
+	Address  Address           `json:"address"`
+	Aliases  []string          `json:"aliases"`
+	Links    map[string]string `json:"links,omitempty"`
+	Verified bool
+	Internal string `json:"-"`
+	secret   string
+}
+
+func TestFor(t *testing.T) {
+	got, err := For[Person]()
+	if err != nil {
+		t.Fatalf("For() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"created_at": map[string]interface{}{"type": "string", "format": "date-time"},
+			"name":       map[string]interface{}{"type": "string", "description": "Full name"},
+			"born":       map[string]interface{}{"type": "integer"},
+			"height":     map[string]interface{}{"type": "number"},
+			"role":       map[string]interface{}{"type": "string", "enum": []interface{}{"author", "editor"}},
+			"rating":     map[string]interface{}{"type": "integer", "enum": []interface{}{1, 2, 3}},
+			"address": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"city":    map[string]interface{}{"type": "string"},
+					"country": map[string]interface{}{"type": "string", "description": "ISO 3166-1 alpha-2 code"},
+				},
+				"required":             []interface{}{"city"},
+				"additionalProperties": false,
+			},
+			"aliases":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"links":    map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+			"Verified": map[string]interface{}{"type": "boolean"},
+		},
+		"required":             []interface{}{"created_at", "name", "born", "role", "rating", "address", "aliases", "Verified"},
+		"additionalProperties": false,
+	}
+
+	// Compare as JSON, as providers receive it
+	if normalize(t, got) != normalize(t, want) {
+		t.Errorf("For() = %s\nwant %s", normalize(t, got), normalize(t, want))
+	}
+}
+
+func normalize(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded interface{}
+	_ = json.Unmarshal(data, &decoded)
+	data, _ = json.Marshal(decoded)
+	return string(data)
+}
+
+type Node struct {
+	Value    string  `json:"value"`
+	Children []*Node `json:"children"`
+}
+
+func TestForErrors(t *testing.T) {
+	if _, err := For[Node](); err == nil {
+		t.Error("For[Node]() error = nil, want a recursive type error")
+	}
+	if _, err := For[string](); err == nil {
+		t.Error("For[string]() error = nil, want an object error")
+	}
+	if _, err := For[struct {
+		Counts map[int]int `json:"counts"`
+	}](); err == nil {
+		t.Error("For() error = nil, want an unsupported map key error")
+	}
+	if _, err := For[struct {
+		Level int `json:"level" enum:"low"`
+	}](); err == nil {
+		t.Error("For() error = nil, want an invalid enum error")
+	}
+}
+
+// structuredClient answers CompleteStructured with fixed data
+type structuredClient struct {
+	libports.LLMClient
+	data   map[string]interface{}
+	schema libports.JSONSchema
+}
+
+func (c *structuredClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	c.schema = schema
+	return &libports.StructuredResponse{Data: c.data, Usage: libports.UsageInfo{TotalTokens: 42}}, nil
+}
+
+func TestComplete(t *testing.T) {
+	client := &structuredClient{data: map[string]interface{}{
+		"city":    "London",
+		"country": "GB",
+	}}
+
+	address, resp, err := Complete[Address](context.Background(), client, libports.CompletionRequest{})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if *address != (Address{City: "London", Country: "GB"}) || resp.Usage.TotalTokens != 42 {
+		t.Errorf("Complete() = %+v, %+v", address, resp)
+	}
+
+	want, _ := For[Address]()
+	if !reflect.DeepEqual(client.schema, want) {
+		t.Errorf("schema sent = %v, want %v", client.schema, want)
+	}
+}
+
+func TestDecodeMismatch(t *testing.T) {
+	resp := &libports.StructuredResponse{Data: map[string]interface{}{"city": 42}}
+
+	var address Address
+	if err := Decode(resp, &address); err == nil {
+		t.Error("Decode() error = nil, want a type mismatch")
+	}
+}
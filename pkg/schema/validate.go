@@ -0,0 +1,227 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Validate checks data, as decoded by encoding/json, against a JSON schema,
+// and returns an error listing every violation with its path, e.g.
+// `$.priority: "urgent" is not one of ["low","medium","high"]`. It supports
+// the keywords of the schemas For generates and of typical structured
+// output schemas: type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, minimum and maximum.
+// Other keywords are ignored.
+func Validate(s libports.JSONSchema, data interface{}) error {
+	v := &validator{}
+	v.validate(s, data, "$")
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(v.errors, "; "))
+}
+
+type validator struct {
+	errors []string
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) validate(s map[string]interface{}, data interface{}, path string) {
+	if t, ok := s["type"]; ok && !matchesType(t, data) {
+		v.fail(path, "%s is not of type %s", describe(data), encode(t))
+		return
+	}
+	if enum, ok := s["enum"]; ok && !contains(enum, data) {
+		v.fail(path, "%s is not one of %s", encode(data), encode(enum))
+	}
+	if value, ok := s["const"]; ok && !equal(value, data) {
+		v.fail(path, "%s is not %s", encode(data), encode(value))
+	}
+
+	switch data := data.(type) {
+	case map[string]interface{}:
+		v.validateObject(s, data, path)
+	case []interface{}:
+		if n, ok := number(s["minItems"]); ok && float64(len(data)) < n {
+			v.fail(path, "has %d items, want at least %v", len(data), n)
+		}
+		if n, ok := number(s["maxItems"]); ok && float64(len(data)) > n {
+			v.fail(path, "has %d items, want at most %v", len(data), n)
+		}
+		if items, ok := subschema(s["items"]); ok {
+			for i, item := range data {
+				v.validate(items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case float64:
+		if n, ok := number(s["minimum"]); ok && data < n {
+			v.fail(path, "%v is less than the minimum %v", data, n)
+		}
+		if n, ok := number(s["maximum"]); ok && data > n {
+			v.fail(path, "%v is greater than the maximum %v", data, n)
+		}
+	}
+}
+
+func (v *validator) validateObject(s map[string]interface{}, data map[string]interface{}, path string) {
+	for _, name := range stringList(s["required"]) {
+		if _, ok := data[name]; !ok {
+			v.fail(path, "missing required property %q", name)
+		}
+	}
+
+	properties, _ := subschema(s["properties"])
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertyPath := path + "." + name
+		if property, ok := subschema(properties[name]); ok {
+			v.validate(property, data[name], propertyPath)
+			continue
+		}
+		if additional, ok := subschema(s["additionalProperties"]); ok {
+			v.validate(additional, data[name], propertyPath)
+		} else if allowed, ok := s["additionalProperties"].(bool); ok && !allowed {
+			v.fail(path, "unexpected property %q", name)
+		}
+	}
+}
+
+// subschema returns a nested schema, a map or a libports.JSONSchema
+func subschema(value interface{}) (map[string]interface{}, bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return value, true
+	case libports.JSONSchema:
+		return value, true
+	}
+	return nil, false
+}
+
+// matchesType reports whether data is of the type, or one of the types,
+// of a "type" keyword
+func matchesType(t interface{}, data interface{}) bool {
+	types := stringList(t)
+	for _, name := range types {
+		switch name {
+		case "object":
+			if _, ok := data.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := data.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := data.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := data.(float64); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := data.(float64); ok && n == math.Trunc(n) {
+				return true
+			}
+		case "boolean":
+			if _, ok := data.(bool); ok {
+				return true
+			}
+		case "null":
+			if data == nil {
+				return true
+			}
+		}
+	}
+	return len(types) == 0
+}
+
+// stringList returns the strings of a keyword holding a string or a list
+// of them
+func stringList(value interface{}) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		var list []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// contains reports whether an enum holds data
+func contains(enum interface{}, data interface{}) bool {
+	values := reflect.ValueOf(enum)
+	if values.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < values.Len(); i++ {
+		if equal(values.Index(i).Interface(), data) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares a schema value with decoded data, numbers by value
+// whatever their Go type
+func equal(value, data interface{}) bool {
+	if a, ok := number(value); ok {
+		b, ok := data.(float64)
+		return ok && a == b
+	}
+	return reflect.DeepEqual(value, data)
+}
+
+// number returns a schema value as a float64, if it is a number
+func number(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// describe names the JSON type of data, for errors
+func describe(data interface{}) string {
+	switch data.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case nil:
+		return "null"
+	}
+	return encode(data)
+}
+
+func encode(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	s, err := For[Person]()
+	if err != nil {
+		t.Fatalf("For() error = %v", err)
+	}
+	decode := func(data string) interface{} {
+		var v interface{}
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	valid := `{
+		"created_at": "2025-06-01T12:00:00Z",
+		"name": "Ada Lovelace",
+		"born": 1815,
+		"role": "author",
+		"rating": 3,
+		"address": {"city": "London"},
+		"aliases": ["Countess of Lovelace"],
+		"links": {"wiki": "https://en.wikipedia.org/wiki/Ada_Lovelace"},
+		"Verified": true
+	}`
+	if err := Validate(s, decode(valid)); err != nil {
+		t.Errorf("Validate(valid) error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "not an object",
+			data: `["Ada"]`,
+			want: []string{`$: array is not of type "object"`},
+		},
+		{
+			name: "violations",
+			data: `{
+				"created_at": "2025-06-01T12:00:00Z",
+				"name": "Ada Lovelace",
+				"born": 1815.5,
+				"role": "reviewer",
+				"rating": 4,
+				"address": {"city": "London", "zip": "W1"},
+				"aliases": [1],
+				"links": {"wiki": 2},
+				"Verified": true,
+				"age": 36
+			}`,
+			want: []string{
+				`$.address: unexpected property "zip"`,
+				`$.aliases[0]: 1 is not of type "string"`,
+				`$.born: 1815.5 is not of type "integer"`,
+				`$.links.wiki: 2 is not of type "string"`,
+				`$.rating: 4 is not one of [1,2,3]`,
+				`$.role: "reviewer" is not one of ["author","editor"]`,
+				`$: unexpected property "age"`,
+			},
+		},
+		{
+			name: "missing properties",
+			data: `{"name": "Ada Lovelace"}`,
+			want: []string{`$: missing required property "born"`, `$: missing required property "address"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(s, decode(tt.data))
+			if err == nil {
+				t.Fatal("Validate() error = nil")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKeywords(t *testing.T) {
+	s := map[string]interface{}{
+		"type":     "array",
+		"items":    map[string]interface{}{"type": []interface{}{"number", "null"}, "minimum": 0, "maximum": 10},
+		"minItems": 1,
+		"maxItems": 2,
+	}
+	if err := Validate(s, []interface{}{0.5, nil}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	err := Validate(s, []interface{}{-1.0, 11.0, 3.0})
+	for _, want := range []string{"has 3 items, want at most 2", "$[0]: -1 is less than the minimum 0", "$[1]: 11 is greater than the maximum 10"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error = %v, want %q", err, want)
+		}
+	}
+	if err := Validate(map[string]interface{}{"const": "yes"}, "no"); err == nil {
+		t.Error("Validate(const) error = nil")
+	}
+}
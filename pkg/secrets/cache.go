@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// SplitName splits a secret name into its path and the "#field" selector,
+// which is empty if there is none
+func SplitName(name string) (path, field string) {
+	path, field, _ = strings.Cut(name, "#")
+	return path, field
+}
+
+// CachedProvider wraps a SecretProvider, keeping each secret for a TTL so
+// that resolving a secret on every call is cheap while rotations are still
+// picked up. When a refresh fails, the stale value keeps being served (and
+// the error logged) unless the secret was deleted.
+type CachedProvider struct {
+	provider ports.SecretProvider
+	ttl      time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	entries map[string]*ports.Secret
+}
+
+// NewCachedProvider creates a caching SecretProvider
+func NewCachedProvider(provider ports.SecretProvider, ttl time.Duration, logger *zap.Logger) *CachedProvider {
+	return &CachedProvider{
+		provider: provider,
+		ttl:      ttl,
+		logger:   logger,
+		entries:  make(map[string]*ports.Secret),
+	}
+}
+
+// GetSecret returns the cached secret, refreshing it once it is older than
+// the TTL (ports.SecretProvider interface)
+func (c *CachedProvider) GetSecret(ctx context.Context, name string) (*ports.Secret, error) {
+	c.mu.Lock()
+	cached, ok := c.entries[name]
+	c.mu.Unlock()
+	if ok && time.Since(cached.FetchedAt) < c.ttl {
+		return copySecret(cached), nil
+	}
+
+	secret, err := c.provider.GetSecret(ctx, name)
+	if err != nil {
+		if ok && !errors.Is(err, ports.ErrSecretNotFound) {
+			c.logger.Warn("failed to refresh secret, using cached value",
+				zap.String("name", name),
+				zap.Error(err))
+			return copySecret(cached), nil
+		}
+		c.Invalidate(name)
+		return nil, err
+	}
+
+	if ok && secret.Version != cached.Version {
+		c.logger.Info("secret rotated",
+			zap.String("name", name),
+			zap.String("version", secret.Version))
+	}
+
+	c.mu.Lock()
+	c.entries[name] = copySecret(secret)
+	c.mu.Unlock()
+	return secret, nil
+}
+
+// Invalidate drops the cached secret so that the next GetSecret reads it
+// from the provider, e.g. after an API call was rejected with its value
+func (c *CachedProvider) Invalidate(name string) {
+	c.mu.Lock()
+	delete(c.entries, name)
+	c.mu.Unlock()
+}
+
+func copySecret(secret *ports.Secret) *ports.Secret {
+	s := *secret
+	return &s
+}
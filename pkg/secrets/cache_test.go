@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+type fakeProvider struct {
+	value string
+	err   error
+	calls int
+}
+
+func (f *fakeProvider) GetSecret(ctx context.Context, name string) (*ports.Secret, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &ports.Secret{Name: name, Value: f.value, Version: f.value, FetchedAt: time.Now()}, nil
+}
+
+func TestCachedProvider(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeProvider{value: "key-1"}
+	cache := NewCachedProvider(fake, 50*time.Millisecond, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		secret, err := cache.GetSecret(ctx, "llm/anthropic")
+		if err != nil || secret.Value != "key-1" {
+			t.Fatalf("GetSecret() = %+v, %v, want key-1", secret, err)
+		}
+	}
+	if fake.calls != 1 {
+		t.Errorf("provider called %d times, want 1", fake.calls)
+	}
+
+	// Rotated after the TTL
+	fake.value = "key-2"
+	time.Sleep(60 * time.Millisecond)
+	if secret, _ := cache.GetSecret(ctx, "llm/anthropic"); secret.Value != "key-2" {
+		t.Errorf("GetSecret() after TTL = %q, want key-2", secret.Value)
+	}
+
+	// Provider outage: the stale value is served
+	fake.err = errors.New("connection refused")
+	time.Sleep(60 * time.Millisecond)
+	if secret, err := cache.GetSecret(ctx, "llm/anthropic"); err != nil || secret.Value != "key-2" {
+		t.Errorf("GetSecret() during outage = %+v, %v, want stale key-2", secret, err)
+	}
+
+	// Deleted secrets are not
+	fake.err = ports.ErrSecretNotFound
+	if _, err := cache.GetSecret(ctx, "llm/anthropic"); !errors.Is(err, ports.ErrSecretNotFound) {
+		t.Errorf("GetSecret() of a deleted secret error = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestSelectField(t *testing.T) {
+	values := map[string]interface{}{"api_key": "sk-1", "org": "acme", "limits": map[string]interface{}{"rpm": 60}}
+
+	if got, err := SelectField("llm", values, "api_key"); err != nil || got != "sk-1" {
+		t.Errorf("SelectField(api_key) = %q, %v", got, err)
+	}
+	if got, err := SelectField("llm", values, "limits"); err != nil || got != `{"rpm":60}` {
+		t.Errorf("SelectField(limits) = %q, %v", got, err)
+	}
+	if _, err := SelectField("llm", values, "missing"); !errors.Is(err, ports.ErrSecretNotFound) {
+		t.Errorf("SelectField(missing) error = %v, want ErrSecretNotFound", err)
+	}
+	if _, err := SelectField("llm", values, ""); err == nil {
+		t.Error("SelectField() without field on a multi-value secret expected error")
+	}
+	if got, err := SelectField("llm", map[string]interface{}{"value": "sk-2"}, ""); err != nil || got != "sk-2" {
+		t.Errorf("SelectField() of a single-value secret = %q, %v", got, err)
+	}
+
+	if path, field := SplitName("llm/openai#api_key"); path != "llm/openai" || field != "api_key" {
+		t.Errorf("SplitName() = %q, %q", path, field)
+	}
+}
@@ -0,0 +1,24 @@
+// Package secrets provides adapters for the ports.SecretProvider interface
+// (pkg/ports in this repository), which reads credentials such as LLM
+// provider API keys from a secret manager, and the helpers they share.
+//
+// Available implementations:
+//   - vault: HashiCorp Vault KV version 2
+//   - secretsmanager: AWS Secrets Manager
+//
+// CachedProvider wraps any of them so that secrets can be resolved on every
+// call, as the LLM factory does for Config.APIKeySecret, while rotations are
+// picked up within the cache TTL.
+//
+// Usage:
+//
+//	vaultProvider, err := vault.NewProvider("https://vault:8200", token, logger)
+//	provider := secrets.NewCachedProvider(vaultProvider, 5*time.Minute, logger)
+//
+//	client, err := llm.NewClient(&llm.Config{
+//		Provider:       "anthropic",
+//		APIKeySecret:   "llm/anthropic#api_key",
+//		SecretResolver: llm.ResolveFrom(provider),
+//		Logger:         logger,
+//	})
+package secrets
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+)
+
+// SelectField returns the field of a secret holding several values, such as
+// a Vault KV entry or a JSON secret. Without a field, the secret must hold
+// exactly one value. Non-string values are returned JSON-encoded.
+func SelectField(name string, values map[string]interface{}, field string) (string, error) {
+	if field == "" {
+		if len(values) != 1 {
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return "", fmt.Errorf("secret %s holds %d values (%s), select one with #field",
+				name, len(values), strings.Join(keys, ", "))
+		}
+		for key := range values {
+			field = key
+		}
+	}
+
+	value, ok := values[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s has no field %q", ports.ErrSecretNotFound, name, field)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field %q of secret %s: %w", field, name, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,16 @@
+// Package secretsmanager implements ports.SecretProvider (pkg/ports in this
+// repository) for AWS Secrets Manager.
+//
+// Names are secret names or ARNs. A "#field" suffix reads one field of a
+// secret stored as a JSON object, the format the console uses for
+// key/value secrets, e.g. "dago/llm#anthropic". The AWSCURRENT version is
+// read, and its version ID reported, so rotations by a Lambda rotation
+// function are picked up.
+//
+// Usage:
+//
+//	cfg, _ := config.LoadDefaultConfig(ctx)
+//	provider := secretsmanager.NewProvider(awssm.NewFromConfig(cfg), logger)
+//
+//	secret, err := provider.GetSecret(ctx, "dago/llm#anthropic")
+package secretsmanager
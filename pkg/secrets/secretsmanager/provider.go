@@ -0,0 +1,82 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/secrets"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssm "github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"go.uber.org/zap"
+)
+
+// Client is the subset of the Secrets Manager API used by Provider.
+// *secretsmanager.Client implements it.
+type Client interface {
+	GetSecretValue(ctx context.Context, params *awssm.GetSecretValueInput, optFns ...func(*awssm.Options)) (*awssm.GetSecretValueOutput, error)
+}
+
+// Provider implements the ports.SecretProvider interface for AWS Secrets
+// Manager
+type Provider struct {
+	client Client
+	logger *zap.Logger
+}
+
+// NewProvider creates a new Secrets Manager secret provider
+func NewProvider(client Client, logger *zap.Logger) *Provider {
+	return &Provider{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetSecret reads the AWSCURRENT version of a secret (ports.SecretProvider
+// interface). name is the secret name or ARN; a "#field" suffix selects a
+// field of a JSON object secret.
+func (p *Provider) GetSecret(ctx context.Context, name string) (*ports.Secret, error) {
+	id, field := secrets.SplitName(name)
+
+	out, err := p.client.GetSecretValue(ctx, &awssm.GetSecretValueInput{
+		SecretId: aws.String(id),
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrSecretNotFound, id)
+		}
+		p.logger.Error("Secrets Manager request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to get secret %s: %w", id, err)
+	}
+
+	value := aws.ToString(out.SecretString)
+	if out.SecretString == nil {
+		value = string(out.SecretBinary)
+	}
+
+	if field != "" {
+		var values map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &values); err != nil {
+			return nil, fmt.Errorf("secret %s is not a JSON object, cannot select #%s", id, field)
+		}
+		if value, err = secrets.SelectField(id, values, field); err != nil {
+			return nil, err
+		}
+	}
+
+	p.logger.Debug("secret read from Secrets Manager",
+		zap.String("secret_id", id),
+		zap.String("version", aws.ToString(out.VersionId)))
+
+	return &ports.Secret{
+		Name:      name,
+		Value:     value,
+		Version:   aws.ToString(out.VersionId),
+		FetchedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssm "github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"go.uber.org/zap"
+)
+
+var _ Client = (*awssm.Client)(nil)
+
+type fakeClient struct {
+	secrets map[string]string
+}
+
+func (f *fakeClient) GetSecretValue(ctx context.Context, params *awssm.GetSecretValueInput, optFns ...func(*awssm.Options)) (*awssm.GetSecretValueOutput, error) {
+	value, ok := f.secrets[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Secrets Manager can't find the specified secret.")}
+	}
+	return &awssm.GetSecretValueOutput{
+		Name:         params.SecretId,
+		SecretString: aws.String(value),
+		VersionId:    aws.String("v-" + value[:2]),
+	}, nil
+}
+
+func TestProvider_GetSecret(t *testing.T) {
+	ctx := context.Background()
+	provider := NewProvider(&fakeClient{secrets: map[string]string{
+		"dago/openai": "sk-openai",
+		"dago/llm":    `{"anthropic":"sk-ant","gemini":"gm-key"}`,
+	}}, zap.NewNop())
+
+	secret, err := provider.GetSecret(ctx, "dago/openai")
+	if err != nil || secret.Value != "sk-openai" || secret.Version != "v-sk" {
+		t.Errorf("GetSecret() = %+v, %v", secret, err)
+	}
+
+	secret, err = provider.GetSecret(ctx, "dago/llm#gemini")
+	if err != nil || secret.Value != "gm-key" {
+		t.Errorf("GetSecret(#gemini) = %+v, %v", secret, err)
+	}
+
+	if _, err := provider.GetSecret(ctx, "dago/openai#key"); err == nil {
+		t.Error("GetSecret() of a field of a plain secret expected error")
+	}
+	if _, err := provider.GetSecret(ctx, "dago/missing"); !errors.Is(err, ports.ErrSecretNotFound) {
+		t.Errorf("GetSecret() of a missing secret error = %v, want ErrSecretNotFound", err)
+	}
+}
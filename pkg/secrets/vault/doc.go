@@ -0,0 +1,20 @@
+// Package vault implements ports.SecretProvider (pkg/ports in this
+// repository) for HashiCorp Vault's KV version 2 secrets engine.
+//
+// Secrets are read with the HTTP API (GET /v1/{mount}/data/{path}), so no
+// Vault SDK is needed. Names are entry paths below the mount, with a
+// "#field" suffix selecting one key of the entry, e.g.
+// "llm/anthropic#api_key". The returned version is the KV version, which
+// changes on every write.
+//
+// The provider authenticates with a token; renewing it, or obtaining it
+// through Kubernetes or AppRole auth, is left to the caller (e.g. Vault
+// Agent writing a token file).
+//
+// Usage:
+//
+//	provider, err := vault.NewProvider("https://vault:8200", os.Getenv("VAULT_TOKEN"), logger)
+//	provider.SetMount("kv")
+//
+//	secret, err := provider.GetSecret(ctx, "llm/anthropic#api_key")
+package vault
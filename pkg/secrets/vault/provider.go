@@ -0,0 +1,141 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/secrets"
+	"go.uber.org/zap"
+)
+
+// DefaultMount is the KV version 2 secrets engine mount read by NewProvider
+const DefaultMount = "secret"
+
+// Provider implements the ports.SecretProvider interface for HashiCorp
+// Vault's KV version 2 secrets engine, using the HTTP API
+type Provider struct {
+	address    string
+	token      string
+	mount      string
+	namespace  string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewProvider creates a new Vault secret provider reading from DefaultMount.
+// address is the Vault server URL, e.g. https://vault.example.com:8200.
+func NewProvider(address, token string, logger *zap.Logger) (*Provider, error) {
+	if address == "" {
+		return nil, fmt.Errorf("Vault address is required")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Vault token is required")
+	}
+
+	return &Provider{
+		address:    strings.TrimSuffix(address, "/"),
+		token:      token,
+		mount:      DefaultMount,
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+// SetMount sets the path the KV version 2 engine is mounted at
+func (p *Provider) SetMount(mount string) {
+	p.mount = strings.Trim(mount, "/")
+}
+
+// SetNamespace sets the Vault Enterprise namespace requests are made in
+func (p *Provider) SetNamespace(namespace string) {
+	p.namespace = namespace
+}
+
+// SetHTTPClient sets the HTTP client, e.g. one trusting the Vault CA
+func (p *Provider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+type kvResponse struct {
+	Data struct {
+		Data     map[string]interface{} `json:"data"`
+		Metadata struct {
+			Version int64 `json:"version"`
+		} `json:"metadata"`
+	} `json:"data"`
+}
+
+// GetSecret reads the latest version of a KV entry (ports.SecretProvider
+// interface). name is the entry path below the mount, with a "#field"
+// suffix unless the entry holds a single value.
+func (p *Provider) GetSecret(ctx context.Context, name string) (*ports.Secret, error) {
+	path, field := secrets.SplitName(name)
+
+	endpoint := p.address + "/v1/" + p.mount + "/data/" + escapePath(path)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		httpReq.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	httpResp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		p.logger.Error("Vault request failed", zap.Error(err))
+		return nil, fmt.Errorf("Vault request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// Also returned for deleted and destroyed versions
+		return nil, fmt.Errorf("%w: %s", ports.ErrSecretNotFound, path)
+	default:
+		return nil, fmt.Errorf("Vault request failed: %s: %s", httpResp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var result kvResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	value, err := secrets.SelectField(path, result.Data.Data, field)
+	if err != nil {
+		return nil, err
+	}
+
+	p.logger.Debug("secret read from Vault",
+		zap.String("path", path),
+		zap.Int64("version", result.Data.Metadata.Version))
+
+	return &ports.Secret{
+		Name:      name,
+		Value:     value,
+		Version:   strconv.FormatInt(result.Data.Metadata.Version, 10),
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// escapePath escapes each segment of a KV path
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
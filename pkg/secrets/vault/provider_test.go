@@ -0,0 +1,56 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestProvider_GetSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" || r.Header.Get("X-Vault-Namespace") != "team-a" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/llm/anthropic" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"api_key":"sk-ant","org":"acme"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(server.URL, "s.token", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	provider.SetMount("/kv/")
+	provider.SetNamespace("team-a")
+
+	ctx := context.Background()
+	secret, err := provider.GetSecret(ctx, "llm/anthropic#api_key")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if secret.Value != "sk-ant" || secret.Version != "3" || secret.Name != "llm/anthropic#api_key" {
+		t.Errorf("GetSecret() = %+v", secret)
+	}
+
+	if _, err := provider.GetSecret(ctx, "llm/anthropic"); err == nil {
+		t.Error("GetSecret() without field on a multi-value entry expected error")
+	}
+	if _, err := provider.GetSecret(ctx, "llm/openai#api_key"); !errors.Is(err, ports.ErrSecretNotFound) {
+		t.Errorf("GetSecret() of a missing entry error = %v, want ErrSecretNotFound", err)
+	}
+
+	provider.SetNamespace("")
+	if _, err := provider.GetSecret(ctx, "llm/anthropic#api_key"); err == nil || errors.Is(err, ports.ErrSecretNotFound) {
+		t.Errorf("GetSecret() when forbidden error = %v, want a request error", err)
+	}
+}
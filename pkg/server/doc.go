@@ -0,0 +1,24 @@
+// Package server exposes any ports.LLMClient behind an OpenAI-compatible
+// HTTP API, so services in other languages and existing OpenAI SDKs can use
+// the adapters and the middleware of this repository (caching, secrets,
+// reloading, fault injection) without a Go client.
+//
+// Handler serves POST /v1/chat/completions, with tools, JSON schema
+// response formats and SSE streaming, and GET /v1/models. Requests are
+// authenticated with bearer API keys set with SetAPIKeys. Tool calls and
+// tool results of the conversation are passed to the client encoded with
+// ports.ToolCallsMessage and ports.ToolResultMessage.
+//
+// Usage:
+//
+//	client, _ := llm.NewClient(&llm.Config{Provider: "anthropic", APIKey: apiKey, Logger: logger})
+//	cached := llm.NewCachedClient(client, cache, time.Hour, logger)
+//
+//	gateway := server.NewHandler(cached, logger)
+//	gateway.SetAPIKeys(os.Getenv("GATEWAY_API_KEY"))
+//	gateway.SetModels("claude-sonnet-4-20250514")
+//	log.Fatal(http.ListenAndServe(":8080", gateway))
+//
+// Any OpenAI SDK then works against it with base URL http://host:8080/v1.
+// Content parts other than text, such as images, are rejected.
+package server
@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/llm"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxBodyBytes is the largest request body accepted by default
+const DefaultMaxBodyBytes = 10 << 20
+
+// Handler serves an LLM client behind the OpenAI chat completions API:
+//
+//   - POST /v1/chat/completions, with tools, JSON schema response formats
+//     and SSE streaming
+//   - GET /v1/models, listing the models set with SetModels
+//
+// Requests with tools are answered with CompleteWithTools and requests with
+// a JSON response format with CompleteStructured. Streamed requests use the
+// client's StreamComplete when it has one; otherwise the whole answer is
+// sent as a single SSE chunk.
+type Handler struct {
+	client       libports.LLMClient
+	apiKeys      [][]byte
+	models       []string
+	maxBodyBytes int64
+	mux          *http.ServeMux
+	logger       *zap.Logger
+}
+
+// streamer is implemented by clients supporting StreamComplete
+type streamer interface {
+	StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error)
+}
+
+// NewHandler creates a gateway to client. Without SetAPIKeys, it accepts
+// unauthenticated requests.
+func NewHandler(client libports.LLMClient, logger *zap.Logger) *Handler {
+	h := &Handler{
+		client:       client,
+		maxBodyBytes: DefaultMaxBodyBytes,
+		mux:          http.NewServeMux(),
+		logger:       logger,
+	}
+	h.mux.HandleFunc("POST /v1/chat/completions", h.chatCompletions)
+	h.mux.HandleFunc("GET /v1/models", h.listModels)
+	return h
+}
+
+// SetAPIKeys sets the keys accepted as bearer tokens. No keys disables
+// authentication.
+func (h *Handler) SetAPIKeys(keys ...string) {
+	h.apiKeys = nil
+	for _, key := range keys {
+		h.apiKeys = append(h.apiKeys, []byte(key))
+	}
+}
+
+// SetModels sets the models listed by /v1/models
+func (h *Handler) SetModels(models ...string) {
+	h.models = models
+}
+
+// SetMaxBodyBytes sets the largest request body accepted
+func (h *Handler) SetMaxBodyBytes(n int64) {
+	h.maxBodyBytes = n
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid_request_error", "invalid_api_key", "Invalid API key")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorized checks the bearer token against the API keys in constant time
+func (h *Handler) authorized(r *http.Request) bool {
+	if len(h.apiKeys) == 0 {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	match := 0
+	for _, key := range h.apiKeys {
+		match |= subtle.ConstantTimeCompare([]byte(token), key)
+	}
+	return match == 1
+}
+
+func (h *Handler) listModels(w http.ResponseWriter, r *http.Request) {
+	list := modelList{Object: "list", Data: []modelInfo{}}
+	for _, model := range h.models {
+		list.Data = append(list.Data, modelInfo{ID: model, Object: "model", OwnedBy: "dago"})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (h *Handler) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	var chatReq chatRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes)).Decode(&chatReq); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+
+	req, err := toCompletionRequest(&chatReq)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", err.Error())
+		return
+	}
+
+	h.logger.Debug("chat completion",
+		zap.String("model", req.Model),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Int("tool_count", len(chatReq.Tools)),
+		zap.Bool("stream", chatReq.Stream))
+
+	ctx := r.Context()
+	if s, ok := h.client.(streamer); ok && chatReq.Stream && len(chatReq.Tools) == 0 && chatReq.ResponseFormat == nil {
+		h.stream(ctx, w, s, req)
+		return
+	}
+
+	resp, err := h.complete(ctx, &chatReq, req)
+	if err != nil {
+		h.writeClientError(ctx, w, err)
+		return
+	}
+
+	if resp.Model == "" {
+		resp.Model = req.Model
+	}
+	if chatReq.Stream {
+		h.streamResponse(w, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, toChatResponse(resp))
+}
+
+// complete answers a request with the client method its options call for
+func (h *Handler) complete(ctx context.Context, chatReq *chatRequest, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	if len(chatReq.Tools) > 0 {
+		tools := make([]libports.Tool, 0, len(chatReq.Tools))
+		for _, tool := range chatReq.Tools {
+			tools = append(tools, libports.Tool{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+			})
+		}
+		return h.client.CompleteWithTools(ctx, req, tools)
+	}
+
+	if format := chatReq.ResponseFormat; format != nil && format.Type != "text" {
+		schema := libports.JSONSchema{"type": "object"}
+		if format.JSONSchema != nil && format.JSONSchema.Schema != nil {
+			schema = format.JSONSchema.Schema
+		}
+		structured, err := h.client.CompleteStructured(ctx, req, schema)
+		if err != nil {
+			return nil, err
+		}
+		content, err := json.Marshal(structured.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode structured data: %w", err)
+		}
+		return &libports.CompletionResponse{
+			Message:      libports.Message{Role: "assistant", Content: string(content)},
+			FinishReason: "stop",
+			Usage:        structured.Usage,
+			CreatedAt:    structured.CreatedAt,
+		}, nil
+	}
+
+	return h.client.Complete(ctx, req)
+}
+
+// stream relays the chunks of the client's stream as SSE chunks
+func (h *Handler) stream(ctx context.Context, w http.ResponseWriter, s streamer, req libports.CompletionRequest) {
+	chunks, err := s.StreamComplete(ctx, req)
+	if err != nil {
+		h.writeClientError(ctx, w, err)
+		return
+	}
+
+	sse := newSSEWriter(w, req.Model)
+	sse.chunk(&responseMessage{Role: "assistant", Content: new(string)}, nil)
+
+	err = llm.StreamTo(ctx, chunks, func(chunk libports.CompletionChunk) error {
+		if chunk.Delta == "" {
+			return nil
+		}
+		delta := chunk.Delta
+		return sse.chunk(&responseMessage{Content: &delta}, nil)
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			h.logger.Warn("stream failed", zap.Error(err))
+			sse.event(errorResponse{Error: errorBody{Message: err.Error(), Type: "api_error"}})
+		}
+		return
+	}
+
+	stop := "stop"
+	sse.chunk(&responseMessage{}, &stop)
+	sse.done()
+}
+
+// streamResponse sends a complete response as an SSE stream, for clients
+// without StreamComplete and for tool and structured requests
+func (h *Handler) streamResponse(w http.ResponseWriter, resp *libports.CompletionResponse) {
+	sse := newSSEWriter(w, resp.Model)
+	message := toResponseMessage(resp)
+	for i := range message.ToolCalls {
+		index := i
+		message.ToolCalls[i].Index = &index
+	}
+	sse.chunk(message, nil)
+
+	finish := finishReason(resp)
+	sse.chunk(&responseMessage{}, &finish)
+	sse.done()
+}
+
+// writeClientError answers with the OpenAI error matching a client error
+func (h *Handler) writeClientError(ctx context.Context, w http.ResponseWriter, err error) {
+	if ctx.Err() != nil {
+		// The caller went away
+		return
+	}
+
+	switch {
+	case errors.Is(err, ports.ErrInvalidRequest):
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", err.Error())
+	case errors.Is(err, ports.ErrQuotaExceeded):
+		writeError(w, http.StatusTooManyRequests, "rate_limit_error", "quota_exceeded", err.Error())
+	case errors.Is(err, ports.ErrNotImplemented):
+		writeError(w, http.StatusNotImplemented, "invalid_request_error", "not_implemented", err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusGatewayTimeout, "api_error", "timeout", err.Error())
+	default:
+		h.logger.Error("completion failed", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "api_error", "", err.Error())
+	}
+}
+
+// toCompletionRequest converts a chat request, encoding tool calls and
+// results with ports.ToolCallsMessage and ports.ToolResultMessage
+func toCompletionRequest(chatReq *chatRequest) (libports.CompletionRequest, error) {
+	if len(chatReq.Messages) == 0 {
+		return libports.CompletionRequest{}, fmt.Errorf("messages must not be empty")
+	}
+
+	req := libports.CompletionRequest{
+		Model:            chatReq.Model,
+		Temperature:      chatReq.Temperature,
+		MaxTokens:        chatReq.MaxTokens,
+		TopP:             chatReq.TopP,
+		Stop:             chatReq.Stop,
+		PresencePenalty:  chatReq.PresencePenalty,
+		FrequencyPenalty: chatReq.FrequencyPenalty,
+		User:             chatReq.User,
+	}
+	if chatReq.MaxCompletionTokens > 0 {
+		req.MaxTokens = chatReq.MaxCompletionTokens
+	}
+
+	for i, m := range chatReq.Messages {
+		switch {
+		case m.Role == "tool":
+			if m.ToolCallID == "" {
+				return req, fmt.Errorf("messages[%d]: tool message without tool_call_id", i)
+			}
+			req.Messages = append(req.Messages, ports.ToolResultMessage(&ports.ToolResult{
+				ToolCallID: m.ToolCallID,
+				Name:       m.Name,
+				Content:    string(m.Content),
+			}))
+
+		case m.Role == "assistant" && len(m.ToolCalls) > 0:
+			resp := &libports.CompletionResponse{Message: libports.Message{Role: "assistant", Content: string(m.Content)}}
+			for _, call := range m.ToolCalls {
+				var args map[string]interface{}
+				if call.Function.Arguments != "" {
+					if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+						return req, fmt.Errorf("messages[%d]: invalid arguments of tool call %s: %w", i, call.ID, err)
+					}
+				}
+				resp.ToolCalls = append(resp.ToolCalls, libports.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: args})
+			}
+			req.Messages = append(req.Messages, ports.ToolCallsMessage(resp))
+
+		case m.Role == "developer":
+			// OpenAI's newer name of system messages
+			req.Messages = append(req.Messages, libports.Message{Role: "system", Content: string(m.Content), Name: m.Name})
+
+		default:
+			req.Messages = append(req.Messages, libports.Message{Role: m.Role, Content: string(m.Content), Name: m.Name})
+		}
+	}
+
+	return req, nil
+}
+
+func toChatResponse(resp *libports.CompletionResponse) chatResponse {
+	finish := finishReason(resp)
+	created := resp.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	id := resp.ID
+	if id == "" {
+		id = newID()
+	}
+	return chatResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created.Unix(),
+		Model:   resp.Model,
+		Choices: []chatChoice{{Message: toResponseMessage(resp), FinishReason: &finish}},
+		Usage: &chatUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+	}
+}
+
+func toResponseMessage(resp *libports.CompletionResponse) *responseMessage {
+	content := resp.Message.Content
+	message := &responseMessage{Role: "assistant", Content: &content}
+	for _, call := range resp.ToolCalls {
+		args, _ := json.Marshal(call.Arguments)
+		if call.Arguments == nil {
+			args = []byte("{}")
+		}
+		message.ToolCalls = append(message.ToolCalls, chatToolCall{
+			ID:       call.ID,
+			Type:     "function",
+			Function: chatFunctionCall{Name: call.Name, Arguments: string(args)},
+		})
+	}
+	return message
+}
+
+func finishReason(resp *libports.CompletionResponse) string {
+	switch {
+	case len(resp.ToolCalls) > 0:
+		return "tool_calls"
+	case resp.FinishReason != "":
+		return resp.FinishReason
+	default:
+		return "stop"
+	}
+}
+
+// sseWriter writes the chunks of a streamed chat completion
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	id      string
+	model   string
+	created int64
+}
+
+func newSSEWriter(w http.ResponseWriter, model string) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	return &sseWriter{w: w, flusher: flusher, id: newID(), model: model, created: time.Now().Unix()}
+}
+
+func (s *sseWriter) chunk(delta *responseMessage, finish *string) error {
+	return s.event(chatResponse{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: s.created,
+		Model:   s.model,
+		Choices: []chatChoice{{Delta: delta, FinishReason: finish}},
+	})
+}
+
+func (s *sseWriter) event(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.write("data: " + string(data) + "\n\n")
+}
+
+func (s *sseWriter) done() {
+	_ = s.write("data: [DONE]\n\n")
+}
+
+func (s *sseWriter) write(event string) error {
+	if _, err := s.w.Write([]byte(event)); err != nil {
+		return fmt.Errorf("failed to write stream: %w", err)
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, errType, code, message string) {
+	body := errorBody{Message: message, Type: errType}
+	if code != "" {
+		body.Code = &code
+	}
+	writeJSON(w, status, errorResponse{Error: body})
+}
+
+// newID returns a random chat completion ID
+func newID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "chatcmpl-" + hex.EncodeToString(b)
+}
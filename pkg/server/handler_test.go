@@ -0,0 +1,359 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/llm/anthropic"
+	"github.com/aescanero/dago-adapters/pkg/llm/ollama"
+	llmopenai "github.com/aescanero/dago-adapters/pkg/llm/openai"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/testutil"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	openai "github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+)
+
+// fakeClient answers with fixed responses, recording the last request
+type fakeClient struct {
+	libports.LLMClient
+	reply    string
+	calls    []libports.ToolCall
+	data     map[string]interface{}
+	err      error
+	last     libports.CompletionRequest
+	tools    []libports.Tool
+	schema   libports.JSONSchema
+	method   string
+	chunks   []string
+	streamed bool
+}
+
+func (c *fakeClient) Complete(ctx context.Context, req libports.CompletionRequest) (*libports.CompletionResponse, error) {
+	c.last, c.method = req, "Complete"
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &libports.CompletionResponse{
+		Model:        "fake-model",
+		Message:      libports.Message{Role: "assistant", Content: c.reply},
+		FinishReason: "stop",
+		Usage:        libports.UsageInfo{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func (c *fakeClient) CompleteWithTools(ctx context.Context, req libports.CompletionRequest, tools []libports.Tool) (*libports.CompletionResponse, error) {
+	c.last, c.tools, c.method = req, tools, "CompleteWithTools"
+	return &libports.CompletionResponse{Message: libports.Message{Role: "assistant"}, ToolCalls: c.calls}, nil
+}
+
+func (c *fakeClient) CompleteStructured(ctx context.Context, req libports.CompletionRequest, schema libports.JSONSchema) (*libports.StructuredResponse, error) {
+	c.last, c.schema, c.method = req, schema, "CompleteStructured"
+	return &libports.StructuredResponse{Data: c.data}, nil
+}
+
+// streamingClient is a fakeClient with StreamComplete
+type streamingClient struct {
+	*fakeClient
+}
+
+func (c streamingClient) StreamComplete(ctx context.Context, req libports.CompletionRequest) (<-chan libports.CompletionChunk, error) {
+	c.last, c.streamed = req, true
+	chunks := make(chan libports.CompletionChunk, len(c.chunks)+1)
+	for _, delta := range c.chunks {
+		chunks <- libports.CompletionChunk{Delta: delta}
+	}
+	if c.err == nil {
+		chunks <- libports.CompletionChunk{IsFinal: true}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+func newGateway(t *testing.T, client libports.LLMClient) (*Handler, *openai.Client) {
+	t.Helper()
+	h := NewHandler(client, zap.NewNop())
+	h.SetAPIKeys("gateway-key")
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	config := openai.DefaultConfig("gateway-key")
+	config.BaseURL = srv.URL + "/v1"
+	return h, openai.NewClientWithConfig(config)
+}
+
+func TestChatCompletion(t *testing.T) {
+	client := &fakeClient{reply: "Hello!"}
+	_, sdk := newGateway(t, client)
+
+	resp, err := sdk.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model: "gpt-4o",
+		Messages: []openai.ChatCompletionMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", MultiContent: []openai.ChatMessagePart{{Type: "text", Text: "Hi"}}},
+		},
+		MaxTokens:   100,
+		Temperature: 0.2,
+		Stop:        []string{"\n"},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	if resp.Choices[0].Message.Content != "Hello!" || resp.Choices[0].FinishReason != "stop" ||
+		resp.Model != "fake-model" || resp.Usage.TotalTokens != 15 || !strings.HasPrefix(resp.ID, "chatcmpl-") {
+		t.Errorf("response = %+v, want Hello! with usage", resp)
+	}
+
+	req := client.last
+	if req.Model != "gpt-4o" || req.MaxTokens != 100 || req.Temperature != 0.2 || len(req.Stop) != 1 ||
+		len(req.Messages) != 2 || req.Messages[1].Content != "Hi" {
+		t.Errorf("request = %+v, want the converted chat request", req)
+	}
+}
+
+// TestChatCompletionAdapters serves plain requests with built-in adapters,
+// backed by fake upstreams, rather than fakeClient
+func TestChatCompletionAdapters(t *testing.T) {
+	tests := []struct {
+		name   string
+		client func(t *testing.T) libports.LLMClient
+	}{
+		{"anthropic", func(t *testing.T) libports.LLMClient {
+			srv := testutil.NewAnthropicServer(t)
+			srv.Reply(testutil.Reply{Chunks: []string{"Hello!"}})
+			client, _ := anthropic.NewClient("test-key", zap.NewNop())
+			client.SetBaseURL(srv.URL)
+			return client
+		}},
+		{"openai", func(t *testing.T) libports.LLMClient {
+			srv := testutil.NewOpenAIServer(t)
+			srv.Reply(testutil.Reply{Chunks: []string{"Hello!"}})
+			client, _ := llmopenai.NewClient("test-key", srv.BaseURL(), zap.NewNop())
+			return client
+		}},
+		{"ollama", func(t *testing.T) libports.LLMClient {
+			srv := testutil.NewOllamaServer(t)
+			srv.ReplyChat(testutil.OllamaReply{Chunks: []string{"Hello!"}})
+			client, _ := ollama.NewClient(srv.URL, zap.NewNop())
+			return client
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sdk := newGateway(t, tt.client(t))
+
+			resp, err := sdk.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+				Model:    "test-model",
+				Messages: []openai.ChatCompletionMessage{{Role: "user", Content: "Hi"}},
+			})
+			if err != nil {
+				t.Fatalf("CreateChatCompletion() error = %v", err)
+			}
+			if resp.Choices[0].Message.Content != "Hello!" {
+				t.Errorf("response = %+v, want the upstream's reply", resp)
+			}
+		})
+	}
+}
+
+func TestChatCompletionTools(t *testing.T) {
+	client := &fakeClient{calls: []libports.ToolCall{{ID: "call_2", Name: "get_weather", Arguments: map[string]interface{}{"city": "Madrid"}}}}
+	_, sdk := newGateway(t, client)
+
+	resp, err := sdk.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model: "gpt-4o",
+		Messages: []openai.ChatCompletionMessage{
+			{Role: "user", Content: "Weather in Paris and Madrid?"},
+			{Role: "assistant", ToolCalls: []openai.ToolCall{{
+				ID: "call_1", Type: "function",
+				Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Content: "21°C"},
+		},
+		Tools: []openai.Tool{{Type: "function", Function: &openai.FunctionDefinition{
+			Name:       "get_weather",
+			Parameters: map[string]interface{}{"type": "object"},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	choice := resp.Choices[0]
+	if choice.FinishReason != "tool_calls" || len(choice.Message.ToolCalls) != 1 ||
+		choice.Message.ToolCalls[0].Function.Arguments != `{"city":"Madrid"}` {
+		t.Errorf("choice = %+v, want the Madrid tool call", choice)
+	}
+
+	if client.method != "CompleteWithTools" || len(client.tools) != 1 || client.tools[0].Name != "get_weather" {
+		t.Errorf("method = %s, tools = %+v, want CompleteWithTools with get_weather", client.method, client.tools)
+	}
+	_, calls, ok := ports.ParseToolCallsMessage(client.last.Messages[1])
+	if !ok || len(calls) != 1 || calls[0].Arguments["city"] != "Paris" {
+		t.Errorf("message 1 = %+v, want the encoded Paris tool call", client.last.Messages[1])
+	}
+	result, ok := ports.ParseToolResultMessage(client.last.Messages[2])
+	if !ok || result.ToolCallID != "call_1" || result.Content != "21°C" {
+		t.Errorf("message 2 = %+v, want the encoded tool result", client.last.Messages[2])
+	}
+}
+
+func TestChatCompletionStructured(t *testing.T) {
+	client := &fakeClient{data: map[string]interface{}{"name": "Ada Lovelace"}}
+	_, sdk := newGateway(t, client)
+
+	resp, err := sdk.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []openai.ChatCompletionMessage{{Role: "user", Content: "Who?"}},
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "person",
+				Schema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	if resp.Choices[0].Message.Content != `{"name":"Ada Lovelace"}` {
+		t.Errorf("content = %q, want the structured data", resp.Choices[0].Message.Content)
+	}
+	if client.method != "CompleteStructured" || client.schema["properties"] == nil {
+		t.Errorf("method = %s, schema = %v, want CompleteStructured with the schema", client.method, client.schema)
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		client       libports.LLMClient
+		wantStreamed bool
+	}{
+		{"StreamComplete", streamingClient{&fakeClient{chunks: []string{"Hel", "lo", "!"}}}, true},
+		{"buffered", &fakeClient{reply: "Hello!"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sdk := newGateway(t, tt.client)
+
+			stream, err := sdk.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+				Model:    "gpt-4o",
+				Messages: []openai.ChatCompletionMessage{{Role: "user", Content: "Hi"}},
+				Stream:   true,
+			})
+			if err != nil {
+				t.Fatalf("CreateChatCompletionStream() error = %v", err)
+			}
+			defer stream.Close()
+
+			var content, finish string
+			for {
+				chunk, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Recv() error = %v", err)
+				}
+				content += chunk.Choices[0].Delta.Content
+				if reason := chunk.Choices[0].FinishReason; reason != "" {
+					finish = string(reason)
+				}
+			}
+
+			if content != "Hello!" || finish != "stop" {
+				t.Errorf("streamed %q, finish %q, want Hello! and stop", content, finish)
+			}
+			if s, ok := tt.client.(streamingClient); ok != tt.wantStreamed || (ok && !s.streamed) {
+				t.Errorf("StreamComplete used = %v, want %v", ok && s.streamed, tt.wantStreamed)
+			}
+		})
+	}
+}
+
+func TestChatCompletionStreamCut(t *testing.T) {
+	client := streamingClient{&fakeClient{chunks: []string{"Hel"}, err: errors.New("cut")}}
+	_, sdk := newGateway(t, client)
+
+	stream, err := sdk.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []openai.ChatCompletionMessage{{Role: "user", Content: "Hi"}},
+		Stream:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	for {
+		_, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			t.Fatal("stream ended normally, want an error")
+		}
+		if err != nil {
+			break
+		}
+	}
+}
+
+func TestChatCompletionErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		key        string
+		body       string
+		wantStatus int
+	}{
+		{"unauthorized", nil, "wrong-key", `{"model":"m","messages":[{"role":"user","content":"Hi"}]}`, http.StatusUnauthorized},
+		{"invalid body", nil, "gateway-key", `{"messages": 42}`, http.StatusBadRequest},
+		{"no messages", nil, "gateway-key", `{"model":"m","messages":[]}`, http.StatusBadRequest},
+		{"image content", nil, "gateway-key", `{"model":"m","messages":[{"role":"user","content":[{"type":"image_url"}]}]}`, http.StatusBadRequest},
+		{"not implemented", fmt.Errorf("%w: Complete", ports.ErrNotImplemented), "gateway-key", `{"model":"m","messages":[{"role":"user","content":"Hi"}]}`, http.StatusNotImplemented},
+		{"quota exceeded", fmt.Errorf("%w: tenant-a made 60 of 60 requests in 1m0s", ports.ErrQuotaExceeded), "gateway-key", `{"model":"m","messages":[{"role":"user","content":"Hi"}]}`, http.StatusTooManyRequests},
+		{"provider error", errors.New("upstream down"), "gateway-key", `{"model":"m","messages":[{"role":"user","content":"Hi"}]}`, http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&fakeClient{err: tt.err}, zap.NewNop())
+			h.SetAPIKeys("gateway-key")
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.key)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			var body errorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Message == "" {
+				t.Errorf("body = %s, want an OpenAI error", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestListModels(t *testing.T) {
+	h, sdk := newGateway(t, &fakeClient{})
+	h.SetModels("gpt-4o", "claude-sonnet-4-20250514")
+
+	models, err := sdk.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels() error = %v", err)
+	}
+	if len(models.Models) != 2 || models.Models[1].ID != "claude-sonnet-4-20250514" {
+		t.Errorf("ListModels() = %+v, want the 2 models", models.Models)
+	}
+}
@@ -0,0 +1,407 @@
+package deepgram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/coder/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the Deepgram API endpoint
+	DefaultBaseURL = "https://api.deepgram.com/v1"
+
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = "nova-3"
+
+	// Size of the audio frames sent to the streaming API
+	frameSize = 8 << 10
+
+	// Deepgram closes streams that receive nothing for 10 seconds
+	keepAliveInterval = 5 * time.Second
+)
+
+// Client implements the ports.StreamingSpeechToText interface for Deepgram
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Deepgram client
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+type word struct {
+	Word           string  `json:"word"`
+	PunctuatedWord string  `json:"punctuated_word"`
+	Start          float64 `json:"start"`
+	End            float64 `json:"end"`
+	Confidence     float64 `json:"confidence"`
+	Speaker        *int    `json:"speaker"`
+}
+
+type alternative struct {
+	Transcript string  `json:"transcript"`
+	Confidence float64 `json:"confidence"`
+	Words      []word  `json:"words"`
+}
+
+type listenResponse struct {
+	Metadata struct {
+		Duration  float64 `json:"duration"`
+		ModelInfo map[string]struct {
+			Name string `json:"name"`
+		} `json:"model_info"`
+	} `json:"metadata"`
+	Results struct {
+		Channels []struct {
+			DetectedLanguage string        `json:"detected_language"`
+			Alternatives     []alternative `json:"alternatives"`
+		} `json:"channels"`
+		Utterances []struct {
+			Start      float64 `json:"start"`
+			End        float64 `json:"end"`
+			Confidence float64 `json:"confidence"`
+			Transcript string  `json:"transcript"`
+			Speaker    *int    `json:"speaker"`
+		} `json:"utterances"`
+	} `json:"results"`
+}
+
+// Transcribe transcribes recorded audio (ports.SpeechToText interface).
+// Segments are Deepgram's utterances, split at pauses and speaker changes.
+func (c *Client) Transcribe(ctx context.Context, req ports.TranscriptionRequest) (*ports.Transcript, error) {
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+
+	query := url.Values{
+		"model":        {model},
+		"smart_format": {"true"},
+		"utterances":   {"true"},
+	}
+	if req.Language != "" {
+		query.Set("language", req.Language)
+	} else {
+		query.Set("detect_language", "true")
+	}
+	if req.Diarize {
+		query.Set("diarize", "true")
+	}
+	for _, term := range strings.Fields(req.Prompt) {
+		query.Add("keyterm", term)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/listen?"+query.Encode(), bytes.NewReader(req.Audio))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Token "+c.apiKey)
+	if req.MIMEType != "" {
+		httpReq.Header.Set("Content-Type", req.MIMEType)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API call failed: %s: %s", httpResp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var result listenResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	transcript := &ports.Transcript{
+		Language: req.Language,
+		Duration: seconds(result.Metadata.Duration),
+		Model:    model,
+	}
+	for _, info := range result.Metadata.ModelInfo {
+		transcript.Model = info.Name
+	}
+	if len(result.Results.Channels) > 0 {
+		channel := result.Results.Channels[0]
+		if channel.DetectedLanguage != "" {
+			transcript.Language = channel.DetectedLanguage
+		}
+		if len(channel.Alternatives) > 0 {
+			transcript.Text = channel.Alternatives[0].Transcript
+		}
+	}
+	for _, utterance := range result.Results.Utterances {
+		transcript.Segments = append(transcript.Segments, ports.TranscriptSegment{
+			Text:       utterance.Transcript,
+			Start:      seconds(utterance.Start),
+			End:        seconds(utterance.End),
+			Speaker:    speaker(utterance.Speaker, req.Diarize),
+			Confidence: utterance.Confidence,
+		})
+	}
+
+	c.logger.Debug("audio transcribed",
+		zap.String("model", transcript.Model),
+		zap.Duration("duration", transcript.Duration),
+		zap.Int("segments", len(transcript.Segments)))
+
+	return transcript, nil
+}
+
+type streamMessage struct {
+	Type        string  `json:"type"`
+	Start       float64 `json:"start"`
+	Duration    float64 `json:"duration"`
+	IsFinal     bool    `json:"is_final"`
+	SpeechFinal bool    `json:"speech_final"`
+	Channel     struct {
+		Alternatives []alternative `json:"alternatives"`
+	} `json:"channel"`
+	Description string `json:"description"`
+}
+
+// TranscribeStream streams audio to Deepgram's live API and results back
+// (ports.StreamingSpeechToText interface). Keep-alive messages hold the
+// connection open while r has no audio; at EOF the stream is closed and
+// the remaining results are delivered.
+func (c *Client) TranscribeStream(ctx context.Context, audio io.Reader, req ports.StreamingTranscriptionRequest) (<-chan ports.TranscriptEvent, error) {
+	endpoint, err := c.streamURL(req)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, resp, err := websocket.Dial(ctx, endpoint, &websocket.DialOptions{
+		HTTPClient: c.httpClient,
+		HTTPHeader: http.Header{"Authorization": {"Token " + c.apiKey}},
+	})
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to open stream: %s: %w", resp.Status, err)
+		}
+		return nil, fmt.Errorf("failed to open stream: %w", err)
+	}
+	// Results are small, but the default 32 KiB limit can be reached by
+	// long utterances with word timings
+	conn.SetReadLimit(1 << 20)
+
+	events := make(chan ports.TranscriptEvent)
+	sendDone := make(chan error, 1)
+
+	go func() {
+		sendDone <- c.sendAudio(ctx, conn, audio)
+	}()
+
+	go func() {
+		defer close(events)
+		defer func() { _ = conn.CloseNow() }()
+
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				if websocket.CloseStatus(err) == websocket.StatusNormalClosure || ctx.Err() != nil {
+					return
+				}
+				// A failed upload explains a dropped connection better
+				select {
+				case sendErr := <-sendDone:
+					if sendErr != nil {
+						err = sendErr
+					}
+				default:
+				}
+				c.send(ctx, events, ports.TranscriptEvent{Err: fmt.Errorf("stream failed: %w", err)})
+				return
+			}
+
+			var msg streamMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				c.logger.Warn("invalid stream message", zap.Error(err))
+				continue
+			}
+
+			switch msg.Type {
+			case "Results":
+				if len(msg.Channel.Alternatives) == 0 || msg.Channel.Alternatives[0].Transcript == "" {
+					continue
+				}
+				alt := msg.Channel.Alternatives[0]
+				event := ports.TranscriptEvent{
+					Segment: ports.TranscriptSegment{
+						Text:       alt.Transcript,
+						Start:      seconds(msg.Start),
+						End:        seconds(msg.Start + msg.Duration),
+						Confidence: alt.Confidence,
+					},
+					IsFinal:        msg.IsFinal,
+					EndOfUtterance: msg.SpeechFinal,
+				}
+				if len(alt.Words) > 0 {
+					event.Segment.Speaker = speaker(alt.Words[0].Speaker, req.Diarize)
+				}
+				if !c.send(ctx, events, event) {
+					return
+				}
+			case "Error":
+				c.send(ctx, events, ports.TranscriptEvent{Err: fmt.Errorf("stream failed: %s", msg.Description)})
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// sendAudio writes audio frames until EOF, then asks Deepgram to finish
+func (c *Client) sendAudio(ctx context.Context, conn *websocket.Conn, audio io.Reader) error {
+	frames := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(frames)
+		for {
+			buf := make([]byte, frameSize)
+			n, err := audio.Read(buf)
+			if n > 0 {
+				select {
+				case frames <- buf[:n]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					readErr <- err
+				}
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-keepAlive.C:
+			if err := conn.Write(ctx, websocket.MessageText, []byte(`{"type":"KeepAlive"}`)); err != nil {
+				return err
+			}
+		case frame, ok := <-frames:
+			if !ok {
+				select {
+				case err := <-readErr:
+					c.logger.Warn("failed to read audio", zap.Error(err))
+					_ = conn.Close(websocket.StatusInternalError, "audio read failed")
+					return fmt.Errorf("failed to read audio: %w", err)
+				default:
+				}
+				return conn.Write(ctx, websocket.MessageText, []byte(`{"type":"CloseStream"}`))
+			}
+			if err := conn.Write(ctx, websocket.MessageBinary, frame); err != nil {
+				return err
+			}
+			keepAlive.Reset(keepAliveInterval)
+		}
+	}
+}
+
+// streamURL returns the WebSocket URL of the live API for a request
+func (c *Client) streamURL(req ports.StreamingTranscriptionRequest) (string, error) {
+	u, err := url.Parse(c.baseURL + "/listen")
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+
+	model := req.Model
+	if model == "" {
+		model = DefaultModel
+	}
+	query := url.Values{
+		"model":        {model},
+		"smart_format": {"true"},
+	}
+	if req.Encoding != "" {
+		query.Set("encoding", req.Encoding)
+	}
+	if req.SampleRate > 0 {
+		query.Set("sample_rate", strconv.Itoa(req.SampleRate))
+	}
+	if req.Channels > 0 {
+		query.Set("channels", strconv.Itoa(req.Channels))
+	}
+	if req.Language != "" {
+		query.Set("language", req.Language)
+	}
+	if req.Diarize {
+		query.Set("diarize", "true")
+	}
+	if req.InterimResults {
+		query.Set("interim_results", "true")
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// send delivers an event unless ctx is cancelled first
+func (c *Client) send(ctx context.Context, events chan<- ports.TranscriptEvent, event ports.TranscriptEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func speaker(n *int, diarize bool) string {
+	if n == nil || !diarize {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
@@ -0,0 +1,199 @@
+package deepgram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/coder/websocket"
+	"go.uber.org/zap"
+)
+
+var _ ports.StreamingSpeechToText = (*Client)(nil)
+
+func TestTranscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/listen" || r.Header.Get("Authorization") != "Token test-key" || r.Header.Get("Content-Type") != "audio/wav" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		query := r.URL.Query()
+		if query.Get("model") != DefaultModel || query.Get("diarize") != "true" || query.Get("detect_language") != "true" || len(query["keyterm"]) != 2 {
+			t.Errorf("query = %v", query)
+		}
+		if body, _ := io.ReadAll(r.Body); string(body) != "RIFF" {
+			t.Errorf("body = %q", body)
+		}
+
+		_, _ = w.Write([]byte(`{
+			"metadata": {"duration": 4.5, "model_info": {"abc": {"name": "general-nova-3"}}},
+			"results": {
+				"channels": [{"detected_language": "en", "alternatives": [{"transcript": "Hello there. Hi!", "confidence": 0.98}]}],
+				"utterances": [
+					{"start": 0.1, "end": 1.5, "confidence": 0.97, "transcript": "Hello there.", "speaker": 0},
+					{"start": 2.0, "end": 2.5, "confidence": 0.99, "transcript": "Hi!", "speaker": 1}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcript, err := client.Transcribe(context.Background(), ports.TranscriptionRequest{
+		Audio:    []byte("RIFF"),
+		MIMEType: "audio/wav",
+		Prompt:   "dago Kubernetes",
+		Diarize:  true,
+	})
+	if err != nil {
+		t.Fatalf("Transcribe() error = %v", err)
+	}
+
+	if transcript.Text != "Hello there. Hi!" || transcript.Language != "en" || transcript.Model != "general-nova-3" || transcript.Duration != 4500*time.Millisecond {
+		t.Errorf("transcript = %+v", transcript)
+	}
+	if len(transcript.Segments) != 2 {
+		t.Fatalf("Segments = %+v", transcript.Segments)
+	}
+	want := ports.TranscriptSegment{Text: "Hi!", Start: 2 * time.Second, End: 2500 * time.Millisecond, Speaker: "1", Confidence: 0.99}
+	if transcript.Segments[1] != want {
+		t.Errorf("Segments[1] = %+v, want %+v", transcript.Segments[1], want)
+	}
+}
+
+func TestTranscribeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"err_code":"INVALID_AUTH"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("bad-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Transcribe(context.Background(), ports.TranscriptionRequest{Audio: []byte("x")}); err == nil || !strings.Contains(err.Error(), "INVALID_AUTH") {
+		t.Errorf("Transcribe() error = %v", err)
+	}
+}
+
+func TestTranscribeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Header.Get("Authorization") != "Token test-key" || query.Get("encoding") != "linear16" || query.Get("sample_rate") != "16000" || query.Get("interim_results") != "true" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() { _ = conn.CloseNow() }()
+		ctx := r.Context()
+
+		var audio []byte
+		for {
+			typ, data, err := conn.Read(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if typ == websocket.MessageBinary {
+				audio = append(audio, data...)
+				continue
+			}
+			if strings.Contains(string(data), "CloseStream") {
+				break
+			}
+		}
+		if string(audio) != strings.Repeat("a", 20000) {
+			t.Errorf("received %d bytes of audio", len(audio))
+		}
+
+		for _, msg := range []map[string]interface{}{
+			{"type": "Results", "start": 0.0, "duration": 1.0, "is_final": false, "channel": map[string]interface{}{"alternatives": []interface{}{map[string]interface{}{"transcript": "book a", "confidence": 0.7}}}},
+			{"type": "Results", "start": 0.0, "duration": 2.0, "is_final": true, "speech_final": true, "channel": map[string]interface{}{"alternatives": []interface{}{map[string]interface{}{"transcript": "Book a flight.", "confidence": 0.95}}}},
+			{"type": "Results", "start": 2.0, "duration": 1.0, "is_final": true, "channel": map[string]interface{}{"alternatives": []interface{}{map[string]interface{}{"transcript": ""}}}},
+			{"type": "Metadata"},
+		} {
+			data, _ := json.Marshal(msg)
+			if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		_ = conn.Close(websocket.StatusNormalClosure, "")
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := client.TranscribeStream(ctx, strings.NewReader(strings.Repeat("a", 20000)), ports.StreamingTranscriptionRequest{
+		Encoding:       "linear16",
+		SampleRate:     16000,
+		InterimResults: true,
+	})
+	if err != nil {
+		t.Fatalf("TranscribeStream() error = %v", err)
+	}
+
+	var got []ports.TranscriptEvent
+	for event := range events {
+		got = append(got, event)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("events = %+v", got)
+	}
+	if got[0].IsFinal || got[0].Segment.Text != "book a" {
+		t.Errorf("interim event = %+v", got[0])
+	}
+	final := got[1]
+	if !final.IsFinal || !final.EndOfUtterance || final.Segment.Text != "Book a flight." || final.Segment.End != 2*time.Second || final.Err != nil {
+		t.Errorf("final event = %+v", final)
+	}
+}
+
+func TestTranscribeStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.Close(websocket.StatusPolicyViolation, "insufficient credits")
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := client.TranscribeStream(context.Background(), strings.NewReader("audio"), ports.StreamingTranscriptionRequest{})
+	if err != nil {
+		t.Fatalf("TranscribeStream() error = %v", err)
+	}
+
+	var last ports.TranscriptEvent
+	for event := range events {
+		last = event
+	}
+	if last.Err == nil || !strings.Contains(last.Err.Error(), "insufficient credits") {
+		t.Errorf("last event error = %v", last.Err)
+	}
+}
@@ -0,0 +1,19 @@
+// Package deepgram implements ports.StreamingSpeechToText (pkg/ports in
+// this repository) for Deepgram.
+//
+// Transcribe sends recorded audio to the /listen endpoint with smart
+// formatting and returns utterances as segments. TranscribeStream opens a
+// WebSocket to the live API, streams audio frames from a reader (sending
+// keep-alives while it has none), and delivers interim and final results
+// with end-of-utterance markers. Prompt terms are sent as keyterms.
+//
+// Usage:
+//
+//	client, err := deepgram.NewClient(os.Getenv("DEEPGRAM_API_KEY"), "", logger)
+//
+//	transcript, err := client.Transcribe(ctx, ports.TranscriptionRequest{
+//		Audio:    recording,
+//		MIMEType: "audio/wav",
+//		Diarize:  true,
+//	})
+package deepgram
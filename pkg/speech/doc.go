@@ -0,0 +1,28 @@
+// Package speech provides adapters for the speech ports in pkg/ports of
+// this repository: ports.SpeechToText, which transcribes recorded audio,
+// ports.StreamingSpeechToText, which transcribes live audio for voice
+// agents, and ports.TextToSpeech, which speaks their answers.
+//
+// Available implementations:
+//   - deepgram: Deepgram, recorded and streaming, with diarization
+//   - whispercpp: A whisper.cpp server, for transcription fully on-premises
+//   - elevenlabs: ElevenLabs text to speech, with streamed audio
+//
+// Usage:
+//
+//	stt, err := deepgram.NewClient(os.Getenv("DEEPGRAM_API_KEY"), "", logger)
+//
+//	events, err := stt.TranscribeStream(ctx, microphone, ports.StreamingTranscriptionRequest{
+//		Encoding:       "linear16",
+//		SampleRate:     16000,
+//		InterimResults: true,
+//	})
+//	for event := range events {
+//		if event.Err != nil {
+//			return event.Err
+//		}
+//		if event.EndOfUtterance {
+//			// The user finished speaking, answer
+//		}
+//	}
+package speech
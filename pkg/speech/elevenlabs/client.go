@@ -0,0 +1,414 @@
+package elevenlabs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the ElevenLabs API endpoint
+	DefaultBaseURL = "https://api.elevenlabs.io/v1"
+
+	// DefaultModel is used when the request doesn't name one
+	DefaultModel = "eleven_multilingual_v2"
+
+	// DefaultVoice is used when the request doesn't name one (Rachel)
+	DefaultVoice = "21m00Tcm4TlvDq8ikWAM"
+)
+
+// voiceID matches ElevenLabs voice IDs, as opposed to voice names
+var voiceID = regexp.MustCompile(`^[a-zA-Z0-9]{20}$`)
+
+// VoiceSettings tune how voices speak. Zero values use the voice's stored
+// settings.
+type VoiceSettings struct {
+	// Stability from 0 to 1; lower is more expressive, higher more monotone.
+	Stability float64 `json:"stability,omitempty"`
+
+	// SimilarityBoost from 0 to 1 is how closely to follow the original voice.
+	SimilarityBoost float64 `json:"similarity_boost,omitempty"`
+
+	// Style from 0 to 1 exaggerates the voice's style, adding latency.
+	Style float64 `json:"style,omitempty"`
+
+	// UseSpeakerBoost increases similarity to the original speaker.
+	UseSpeakerBoost bool `json:"use_speaker_boost,omitempty"`
+}
+
+// Subscription is the character quota of the account
+type Subscription struct {
+	// CharacterCount is the number of characters used in this period.
+	CharacterCount int64
+
+	// CharacterLimit is the number of characters available per period.
+	CharacterLimit int64
+
+	// NextReset is when the count goes back to zero.
+	NextReset time.Time
+}
+
+// Client implements the ports.TextToSpeech interface for ElevenLabs
+type Client struct {
+	apiKey     string
+	baseURL    string
+	voice      string
+	model      string
+	settings   *VoiceSettings
+	httpClient *http.Client
+	logger     *zap.Logger
+
+	// characters billed by this client, for Usage
+	characters atomic.Int64
+
+	// voiceIDs caches voice names to IDs
+	mu       sync.Mutex
+	voiceIDs map[string]string
+}
+
+// NewClient creates a new ElevenLabs client speaking with DefaultVoice and
+// DefaultModel
+// baseURL is optional and defaults to DefaultBaseURL
+func NewClient(apiKey, baseURL string, logger *zap.Logger) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		voice:      DefaultVoice,
+		model:      DefaultModel,
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+// SetVoice sets the voice of requests without one, by ID or name
+func (c *Client) SetVoice(voice string) {
+	c.voice = voice
+}
+
+// SetModel sets the model of requests without one, e.g. "eleven_flash_v2_5"
+// for the lowest latency
+func (c *Client) SetModel(model string) {
+	c.model = model
+}
+
+// SetVoiceSettings overrides the stored settings of voices
+func (c *Client) SetVoiceSettings(settings VoiceSettings) {
+	c.settings = &settings
+}
+
+// Usage returns the characters billed for speech synthesized by this
+// client since it was created
+func (c *Client) Usage() ports.SpeechUsage {
+	return ports.SpeechUsage{Characters: int(c.characters.Load())}
+}
+
+type speechRequest struct {
+	Text          string                 `json:"text"`
+	ModelID       string                 `json:"model_id"`
+	LanguageCode  string                 `json:"language_code,omitempty"`
+	VoiceSettings map[string]interface{} `json:"voice_settings,omitempty"`
+}
+
+// Synthesize returns the audio of the text (ports.TextToSpeech interface)
+func (c *Client) Synthesize(ctx context.Context, req ports.SpeechRequest) (*ports.Speech, error) {
+	stream, err := c.synthesize(ctx, req, "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.Audio.Close() }()
+
+	audio, err := io.ReadAll(stream.Audio)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+
+	return &ports.Speech{
+		Audio:    audio,
+		MIMEType: stream.MIMEType,
+		Usage:    stream.Usage,
+	}, nil
+}
+
+// SynthesizeStream returns the audio as it is generated (ports.TextToSpeech
+// interface)
+func (c *Client) SynthesizeStream(ctx context.Context, req ports.SpeechRequest) (*ports.SpeechStream, error) {
+	return c.synthesize(ctx, req, "/stream")
+}
+
+func (c *Client) synthesize(ctx context.Context, req ports.SpeechRequest, suffix string) (*ports.SpeechStream, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text is required")
+	}
+	outputFormat, mimeType, err := outputFormat(req.Format, req.SampleRate)
+	if err != nil {
+		return nil, err
+	}
+
+	voice := req.Voice
+	if voice == "" {
+		voice = c.voice
+	}
+	id, err := c.resolveVoice(ctx, voice)
+	if err != nil {
+		return nil, err
+	}
+
+	body := speechRequest{
+		Text:         req.Text,
+		ModelID:      req.Model,
+		LanguageCode: strings.ToLower(strings.SplitN(req.Language, "-", 2)[0]),
+	}
+	if body.ModelID == "" {
+		body.ModelID = c.model
+	}
+	if settings := c.voiceSettings(req.Speed); len(settings) > 0 {
+		body.VoiceSettings = settings
+	}
+
+	endpoint := fmt.Sprintf("%s/text-to-speech/%s%s?output_format=%s", c.baseURL, url.PathEscape(id), suffix, url.QueryEscape(outputFormat))
+	httpResp, err := c.do(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := ports.SpeechUsage{Characters: characterCost(httpResp.Header, req.Text)}
+	c.characters.Add(int64(usage.Characters))
+
+	c.logger.Debug("speech synthesized",
+		zap.String("voice", id),
+		zap.String("model", body.ModelID),
+		zap.Int("characters", usage.Characters))
+
+	return &ports.SpeechStream{
+		Audio:    httpResp.Body,
+		MIMEType: mimeType,
+		Usage:    usage,
+	}, nil
+}
+
+// voiceSettings returns the voice_settings of a request, or nil to use the
+// voice's stored settings
+func (c *Client) voiceSettings(speed float64) map[string]interface{} {
+	settings := map[string]interface{}{}
+	if c.settings != nil {
+		data, _ := json.Marshal(c.settings)
+		_ = json.Unmarshal(data, &settings)
+	}
+	if speed > 0 {
+		settings["speed"] = speed
+	}
+	return settings
+}
+
+type voicesResponse struct {
+	Voices []struct {
+		VoiceID    string            `json:"voice_id"`
+		Name       string            `json:"name"`
+		Labels     map[string]string `json:"labels"`
+		PreviewURL string            `json:"preview_url"`
+	} `json:"voices"`
+}
+
+// Voices lists the account's voices (ports.TextToSpeech interface)
+func (c *Client) Voices(ctx context.Context) ([]ports.Voice, error) {
+	httpResp, err := c.do(ctx, http.MethodGet, c.baseURL+"/voices", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	var result voicesResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	voices := make([]ports.Voice, 0, len(result.Voices))
+	ids := make(map[string]string, len(result.Voices))
+	for _, v := range result.Voices {
+		voices = append(voices, ports.Voice{
+			ID:         v.VoiceID,
+			Name:       v.Name,
+			Labels:     v.Labels,
+			PreviewURL: v.PreviewURL,
+		})
+		ids[strings.ToLower(v.Name)] = v.VoiceID
+	}
+
+	c.mu.Lock()
+	c.voiceIDs = ids
+	c.mu.Unlock()
+
+	return voices, nil
+}
+
+// resolveVoice returns the ID of a voice given by ID or name. Names are
+// looked up in the voice list, fetched once.
+func (c *Client) resolveVoice(ctx context.Context, voice string) (string, error) {
+	c.mu.Lock()
+	id, ok := c.voiceIDs[strings.ToLower(voice)]
+	loaded := c.voiceIDs != nil
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+	if voiceID.MatchString(voice) {
+		return voice, nil
+	}
+	if loaded {
+		return "", fmt.Errorf("voice %q not found", voice)
+	}
+
+	if _, err := c.Voices(ctx); err != nil {
+		return "", fmt.Errorf("failed to look up voice %q: %w", voice, err)
+	}
+	c.mu.Lock()
+	id, ok = c.voiceIDs[strings.ToLower(voice)]
+	c.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("voice %q not found", voice)
+	}
+	return id, nil
+}
+
+type subscriptionResponse struct {
+	CharacterCount              int64 `json:"character_count"`
+	CharacterLimit              int64 `json:"character_limit"`
+	NextCharacterCountResetUnix int64 `json:"next_character_count_reset_unix"`
+}
+
+// Subscription returns the account's character quota, e.g. to alert before
+// it runs out
+func (c *Client) Subscription(ctx context.Context) (*Subscription, error) {
+	httpResp, err := c.do(ctx, http.MethodGet, c.baseURL+"/user/subscription", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	var result subscriptionResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	subscription := &Subscription{
+		CharacterCount: result.CharacterCount,
+		CharacterLimit: result.CharacterLimit,
+	}
+	if result.NextCharacterCountResetUnix > 0 {
+		subscription.NextReset = time.Unix(result.NextCharacterCountResetUnix, 0).UTC()
+	}
+	return subscription, nil
+}
+
+// do sends a request and returns the response if it succeeded. The caller
+// closes the body.
+func (c *Client) do(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("xi-api-key", c.apiKey)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer func() { _ = httpResp.Body.Close() }()
+		respBody, _ := io.ReadAll(io.LimitReader(httpResp.Body, 64<<10))
+		return nil, fmt.Errorf("API call failed: %s: %s", httpResp.Status, errorMessage(respBody))
+	}
+	return httpResp, nil
+}
+
+// errorMessage extracts the message of an error response
+func errorMessage(body []byte) string {
+	var result struct {
+		Detail struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"detail"`
+	}
+	if json.Unmarshal(body, &result) == nil && result.Detail.Message != "" {
+		return result.Detail.Status + ": " + result.Detail.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// characterCost returns the characters billed for a request, from the
+// response headers or else the text length
+func characterCost(header http.Header, text string) int {
+	for _, name := range []string{"Character-Cost", "X-Character-Count"} {
+		if n, err := strconv.Atoi(header.Get(name)); err == nil {
+			return n
+		}
+	}
+	return utf8.RuneCountInString(text)
+}
+
+// outputFormat maps a format and sample rate to ElevenLabs' output_format
+// and the MIME type of the audio
+func outputFormat(format string, sampleRate int) (string, string, error) {
+	switch format {
+	case "", ports.SpeechFormatMP3:
+		if sampleRate == 0 {
+			sampleRate = 44100
+		}
+		bitrate := 128
+		if sampleRate < 44100 {
+			bitrate = 32
+		}
+		return fmt.Sprintf("mp3_%d_%d", sampleRate, bitrate), "audio/mpeg", nil
+	case ports.SpeechFormatPCM:
+		if sampleRate == 0 {
+			sampleRate = 24000
+		}
+		return fmt.Sprintf("pcm_%d", sampleRate), fmt.Sprintf("audio/L16;rate=%d", sampleRate), nil
+	case ports.SpeechFormatULaw:
+		if sampleRate != 0 && sampleRate != 8000 {
+			return "", "", fmt.Errorf("mu-law audio is only available at 8000 Hz")
+		}
+		return "ulaw_8000", "audio/basic", nil
+	case ports.SpeechFormatOpus:
+		if sampleRate != 0 && sampleRate != 48000 {
+			return "", "", fmt.Errorf("Opus audio is only available at 48000 Hz")
+		}
+		return "opus_48000_64", "audio/ogg", nil
+	default:
+		return "", "", fmt.Errorf("unsupported audio format %q", format)
+	}
+}
@@ -0,0 +1,163 @@
+package elevenlabs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.TextToSpeech = (*Client)(nil)
+
+func TestSynthesize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/text-to-speech/"+DefaultVoice || r.Header.Get("xi-api-key") != "test-key" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		if got := r.URL.Query().Get("output_format"); got != "pcm_16000" {
+			t.Errorf("output_format = %q", got)
+		}
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		settings, _ := body["voice_settings"].(map[string]interface{})
+		if body["text"] != "Hola" || body["model_id"] != DefaultModel || body["language_code"] != "es" || settings["speed"] != 1.1 || settings["stability"] != 0.5 {
+			t.Errorf("body = %v", body)
+		}
+
+		w.Header().Set("Character-Cost", "7")
+		_, _ = w.Write([]byte("PCM"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetVoiceSettings(VoiceSettings{Stability: 0.5})
+
+	speech, err := client.Synthesize(context.Background(), ports.SpeechRequest{
+		Text:       "Hola",
+		Format:     ports.SpeechFormatPCM,
+		SampleRate: 16000,
+		Language:   "es-ES",
+		Speed:      1.1,
+	})
+	if err != nil {
+		t.Fatalf("Synthesize() error = %v", err)
+	}
+	if string(speech.Audio) != "PCM" || speech.MIMEType != "audio/L16;rate=16000" || speech.Usage.Characters != 7 {
+		t.Errorf("speech = %+v", speech)
+	}
+	if client.Usage().Characters != 7 {
+		t.Errorf("Usage() = %+v", client.Usage())
+	}
+}
+
+func TestSynthesizeStreamByVoiceName(t *testing.T) {
+	voiceLists := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/voices":
+			voiceLists++
+			_, _ = w.Write([]byte(`{"voices": [
+				{"voice_id": "pNInz6obpgDQGcFmaJgB", "name": "Adam", "labels": {"accent": "american"}, "preview_url": "https://example.com/adam.mp3"}
+			]}`))
+		case "/text-to-speech/pNInz6obpgDQGcFmaJgB/stream":
+			if got := r.URL.Query().Get("output_format"); got != "mp3_44100_128" {
+				t.Errorf("output_format = %q", got)
+			}
+			_, _ = w.Write([]byte("ID3"))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL+"/", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		stream, err := client.SynthesizeStream(context.Background(), ports.SpeechRequest{Text: "Hello", Voice: "adam"})
+		if err != nil {
+			t.Fatalf("SynthesizeStream() error = %v", err)
+		}
+		audio, _ := io.ReadAll(stream.Audio)
+		_ = stream.Audio.Close()
+		if string(audio) != "ID3" || stream.MIMEType != "audio/mpeg" || stream.Usage.Characters != 5 {
+			t.Errorf("stream = %+v, audio = %q", stream, audio)
+		}
+	}
+	if voiceLists != 1 {
+		t.Errorf("voices listed %d times, want 1", voiceLists)
+	}
+	if client.Usage().Characters != 10 {
+		t.Errorf("Usage() = %+v", client.Usage())
+	}
+
+	if _, err := client.Synthesize(context.Background(), ports.SpeechRequest{Text: "Hello", Voice: "Nobody"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unknown voice error = %v", err)
+	}
+}
+
+func TestSynthesizeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"detail": {"status": "quota_exceeded", "message": "This request exceeds your quota."}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Synthesize(context.Background(), ports.SpeechRequest{Text: "Hello"})
+	if err == nil || !strings.Contains(err.Error(), "quota_exceeded: This request exceeds your quota.") {
+		t.Errorf("Synthesize() error = %v", err)
+	}
+	if client.Usage().Characters != 0 {
+		t.Errorf("Usage() = %+v", client.Usage())
+	}
+
+	if _, err := client.Synthesize(context.Background(), ports.SpeechRequest{Text: "Hello", Format: "flac"}); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestSubscription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/subscription" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"character_count": 1200, "character_limit": 30000, "next_character_count_reset_unix": 1767225600}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscription, err := client.Subscription(context.Background())
+	if err != nil {
+		t.Fatalf("Subscription() error = %v", err)
+	}
+	if subscription.CharacterCount != 1200 || subscription.CharacterLimit != 30000 || subscription.NextReset.Year() != 2026 {
+		t.Errorf("subscription = %+v", subscription)
+	}
+}
+
+func TestNewClientRequiresAPIKey(t *testing.T) {
+	if _, err := NewClient("", "", zap.NewNop()); err == nil {
+		t.Error("expected an error without an API key")
+	}
+}
@@ -0,0 +1,23 @@
+// Package elevenlabs implements ports.TextToSpeech (pkg/ports in this
+// repository) for ElevenLabs.
+//
+// Voices are given by ID or by name; names are resolved, case-insensitively,
+// against the account's voice list, which is fetched once. SynthesizeStream
+// returns the audio as it is generated, so voice agents can start playing
+// before the whole answer is spoken. The characters billed for each request
+// are reported in its usage and accumulated by Usage; Subscription returns
+// the account's remaining quota.
+//
+// Usage:
+//
+//	tts, err := elevenlabs.NewClient(os.Getenv("ELEVENLABS_API_KEY"), "", logger)
+//	tts.SetVoice("Adam")
+//
+//	stream, err := tts.SynthesizeStream(ctx, ports.SpeechRequest{
+//		Text:       answer,
+//		Format:     ports.SpeechFormatPCM,
+//		SampleRate: 16000,
+//	})
+//	defer stream.Audio.Close()
+//	_, err = io.Copy(speaker, stream.Audio)
+package elevenlabs
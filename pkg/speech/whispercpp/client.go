@@ -0,0 +1,163 @@
+package whispercpp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// Client implements the ports.SpeechToText interface for a whisper.cpp
+// server (whisper-server), for transcription without leaving the cluster
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new whisper.cpp client
+// endpoint is the server URL (e.g., "http://localhost:8080")
+func NewClient(endpoint string, logger *zap.Logger) (*Client, error) {
+	if endpoint == "" {
+		endpoint = "http://localhost:8080"
+	}
+
+	return &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: http.DefaultClient,
+		logger:     logger,
+	}, nil
+}
+
+type inferenceResponse struct {
+	Language string  `json:"language"`
+	Duration float64 `json:"duration"`
+	Text     string  `json:"text"`
+	Segments []struct {
+		Text       string  `json:"text"`
+		Start      float64 `json:"start"`
+		End        float64 `json:"end"`
+		AvgLogprob float64 `json:"avg_logprob"`
+	} `json:"segments"`
+	Error string `json:"error"`
+}
+
+// Transcribe transcribes recorded audio (ports.SpeechToText interface).
+// The server transcribes with the model it was started with, so Model is
+// ignored, and whisper doesn't diarize. Audio other than 16 kHz WAV needs
+// a server started with --convert (ffmpeg).
+func (c *Client) Transcribe(ctx context.Context, req ports.TranscriptionRequest) (*ports.Transcript, error) {
+	// Whisper takes ISO 639-1 codes
+	language := "auto"
+	if req.Language != "" {
+		language = strings.ToLower(strings.SplitN(req.Language, "-", 2)[0])
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "audio"+extension(req.MIMEType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if _, err := file.Write(req.Audio); err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	fields := map[string]string{
+		"response_format": "verbose_json",
+		"language":        language,
+		"temperature":     "0.0",
+	}
+	if req.Prompt != "" {
+		fields["prompt"] = req.Prompt
+	}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/inference", &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("API call failed", zap.Error(err))
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API call failed: %s: %s", httpResp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var result inferenceResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	// The server reports some failures, e.g. undecodable audio, with 200
+	if result.Error != "" {
+		return nil, fmt.Errorf("transcription failed: %s", result.Error)
+	}
+
+	transcript := &ports.Transcript{
+		Text:     strings.TrimSpace(result.Text),
+		Language: req.Language,
+		Duration: seconds(result.Duration),
+	}
+	if transcript.Language == "" {
+		transcript.Language = result.Language
+	}
+	for _, segment := range result.Segments {
+		transcript.Segments = append(transcript.Segments, ports.TranscriptSegment{
+			Text:       strings.TrimSpace(segment.Text),
+			Start:      seconds(segment.Start),
+			End:        seconds(segment.End),
+			Confidence: math.Exp(segment.AvgLogprob),
+		})
+	}
+
+	c.logger.Debug("audio transcribed",
+		zap.Duration("duration", transcript.Duration),
+		zap.Int("segments", len(transcript.Segments)))
+
+	return transcript, nil
+}
+
+// extension returns the file extension of an audio MIME type, which the
+// server uses to pick a decoder
+func extension(mimeType string) string {
+	switch mimeType {
+	case "", "audio/wav", "audio/x-wav", "audio/wave":
+		return ".wav"
+	case "audio/mpeg":
+		return ".mp3"
+	}
+	if extensions, err := mime.ExtensionsByType(mimeType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ""
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,83 @@
+package whispercpp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.SpeechToText = (*Client)(nil)
+
+func TestTranscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/inference" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		audio, _ := io.ReadAll(file)
+		if string(audio) != "ID3" || header.Filename != "audio.mp3" {
+			t.Errorf("file %s = %q", header.Filename, audio)
+		}
+		if r.FormValue("language") != "pt" || r.FormValue("response_format") != "verbose_json" || r.FormValue("prompt") != "Lisboa" {
+			t.Errorf("form = %v", r.Form)
+		}
+
+		_, _ = w.Write([]byte(`{
+			"task": "transcribe", "language": "portuguese", "duration": 3.2,
+			"text": " Bom dia. Até logo.",
+			"segments": [
+				{"id": 0, "text": " Bom dia.", "start": 0.0, "end": 1.2, "avg_logprob": 0},
+				{"id": 1, "text": " Até logo.", "start": 1.2, "end": 3.2, "avg_logprob": -0.5}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcript, err := client.Transcribe(context.Background(), ports.TranscriptionRequest{
+		Audio:    []byte("ID3"),
+		MIMEType: "audio/mpeg",
+		Language: "pt-BR",
+		Prompt:   "Lisboa",
+	})
+	if err != nil {
+		t.Fatalf("Transcribe() error = %v", err)
+	}
+
+	if transcript.Text != "Bom dia. Até logo." || transcript.Language != "pt-BR" || transcript.Duration != 3200*time.Millisecond {
+		t.Errorf("transcript = %+v", transcript)
+	}
+	if len(transcript.Segments) != 2 || transcript.Segments[1].Text != "Até logo." || transcript.Segments[0].Confidence != 1 {
+		t.Errorf("Segments = %+v", transcript.Segments)
+	}
+}
+
+func TestTranscribeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error": "failed to read audio data"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Transcribe(context.Background(), ports.TranscriptionRequest{Audio: []byte("x")})
+	if err == nil || !strings.Contains(err.Error(), "failed to read audio data") {
+		t.Errorf("Transcribe() error = %v", err)
+	}
+}
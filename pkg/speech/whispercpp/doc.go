@@ -0,0 +1,20 @@
+// Package whispercpp implements ports.SpeechToText (pkg/ports in this
+// repository) for whisper.cpp's HTTP server, so audio can be transcribed on
+// the same on-premises nodes as Ollama.
+//
+// Audio is posted to the server's /inference endpoint and the verbose JSON
+// response is mapped to timed segments, with the confidence derived from
+// each segment's average log probability. Start the server with --convert
+// to accept formats other than 16 kHz WAV.
+//
+// Usage:
+//
+//	// whisper-server -m models/ggml-large-v3-turbo.bin --host 0.0.0.0 --port 8080 --convert
+//	client, err := whispercpp.NewClient("http://whisper:8080", logger)
+//
+//	transcript, err := client.Transcribe(ctx, ports.TranscriptionRequest{
+//		Audio:    recording,
+//		MIMEType: "audio/mpeg",
+//		Language: "es",
+//	})
+package whispercpp
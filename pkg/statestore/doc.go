@@ -0,0 +1,10 @@
+// Package statestore provides adapters for the ports.StateStore interface
+// (pkg/ports in this repository), which persists workflow run checkpoints.
+// Every write increments the run's revision, and CompareAndSwap writes only
+// if the revision is unchanged, so an orchestrator resuming a run can't
+// overwrite progress made by another instance.
+//
+// Available implementations:
+//   - redis: One hash per run, expired by Redis
+//   - postgres: One row per run, for deployments already running PostgreSQL
+package statestore
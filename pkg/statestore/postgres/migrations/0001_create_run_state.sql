@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS dago_run_state (
+    run_id      TEXT PRIMARY KEY,
+    workflow_id TEXT NOT NULL,
+    status      TEXT NOT NULL DEFAULT '',
+    data        BYTEA,
+    revision    BIGINT NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL,
+    expires_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS dago_run_state_workflow_id_idx ON dago_run_state (workflow_id, updated_at);
+CREATE INDEX IF NOT EXISTS dago_run_state_expires_at_idx ON dago_run_state (expires_at);
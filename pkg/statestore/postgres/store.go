@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/internal/pgmigrate"
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// Columns selected for every run state read, in scanState order
+	stateColumns = `run_id, workflow_id, status, data, revision, updated_at, expires_at`
+
+	// Condition matching rows that haven't expired as of $1
+	liveCondition = `(expires_at IS NULL OR expires_at > $1)`
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Store implements ports.StateStore using PostgreSQL
+type Store struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+}
+
+// NewStore creates a new PostgreSQL run state store
+func NewStore(pool *pgxpool.Pool, logger *zap.Logger) *Store {
+	return &Store{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+// Migrate applies any pending schema migrations.
+// It is safe to call concurrently from several processes.
+func (s *Store) Migrate(ctx context.Context) error {
+	return pgmigrate.Migrate(ctx, s.pool, migrationFS, "migrations/", s.logger)
+}
+
+// Save writes the run state unconditionally (ports.StateStore interface).
+// An expired row is replaced as if it didn't exist, restarting at revision 1.
+func (s *Store) Save(ctx context.Context, state ports.RunState, ttl time.Duration) (int64, error) {
+	now := time.Now()
+
+	var revision int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO dago_run_state
+			(run_id, workflow_id, status, data, revision, updated_at, expires_at)
+		VALUES ($2, $3, $4, $5, 1, $1, $6)
+		ON CONFLICT (run_id) DO UPDATE SET
+			workflow_id = EXCLUDED.workflow_id,
+			status      = EXCLUDED.status,
+			data        = EXCLUDED.data,
+			revision    = CASE WHEN dago_run_state.expires_at <= $1 THEN 1
+			                   ELSE dago_run_state.revision + 1 END,
+			updated_at  = EXCLUDED.updated_at,
+			expires_at  = EXCLUDED.expires_at
+		RETURNING revision`,
+		now, state.RunID, state.WorkflowID, state.Status, state.Data, expiresAt(now, ttl)).Scan(&revision)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save run state: %w", err)
+	}
+
+	s.logSaved(state, revision)
+	return revision, nil
+}
+
+// CompareAndSwap writes the run state if it is still at revision
+// (ports.StateStore interface)
+func (s *Store) CompareAndSwap(ctx context.Context, state ports.RunState, revision int64, ttl time.Duration) (int64, error) {
+	now := time.Now()
+
+	var row pgx.Row
+	if revision == 0 {
+		// Create, or replace an expired row
+		row = s.pool.QueryRow(ctx, `
+			INSERT INTO dago_run_state
+				(run_id, workflow_id, status, data, revision, updated_at, expires_at)
+			VALUES ($2, $3, $4, $5, 1, $1, $6)
+			ON CONFLICT (run_id) DO UPDATE SET
+				workflow_id = EXCLUDED.workflow_id,
+				status      = EXCLUDED.status,
+				data        = EXCLUDED.data,
+				revision    = 1,
+				updated_at  = EXCLUDED.updated_at,
+				expires_at  = EXCLUDED.expires_at
+			WHERE dago_run_state.expires_at <= $1
+			RETURNING revision`,
+			now, state.RunID, state.WorkflowID, state.Status, state.Data, expiresAt(now, ttl))
+	} else {
+		row = s.pool.QueryRow(ctx, `
+			UPDATE dago_run_state SET
+				workflow_id = $3,
+				status      = $4,
+				data        = $5,
+				revision    = revision + 1,
+				updated_at  = $1,
+				expires_at  = $6
+			WHERE run_id = $2 AND revision = $7 AND `+liveCondition+`
+			RETURNING revision`,
+			now, state.RunID, state.WorkflowID, state.Status, state.Data, expiresAt(now, ttl), revision)
+	}
+
+	var written int64
+	err := row.Scan(&written)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.conflict(ctx, state.RunID, revision, now)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to save run state: %w", err)
+	}
+
+	s.logSaved(state, written)
+	return written, nil
+}
+
+// Load returns the run state (ports.StateStore interface)
+func (s *Store) Load(ctx context.Context, runID string) (*ports.RunState, error) {
+	row := s.pool.QueryRow(ctx,
+		`SELECT `+stateColumns+` FROM dago_run_state WHERE run_id = $2 AND `+liveCondition,
+		time.Now(), runID)
+
+	state, err := scanState(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ports.ErrRunNotFound, runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run state: %w", err)
+	}
+	return state, nil
+}
+
+// Delete removes the run state (ports.StateStore interface)
+func (s *Store) Delete(ctx context.Context, runID string) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM dago_run_state WHERE run_id = $1`, runID); err != nil {
+		return fmt.Errorf("failed to delete run state: %w", err)
+	}
+	return nil
+}
+
+// ListByWorkflow returns the workflow's runs, least recently updated first
+// (ports.StateStore interface)
+func (s *Store) ListByWorkflow(ctx context.Context, workflowID string) ([]ports.RunState, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+stateColumns+` FROM dago_run_state
+		WHERE workflow_id = $2 AND `+liveCondition+`
+		ORDER BY updated_at, run_id`,
+		time.Now(), workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var states []ports.RunState
+	for rows.Next() {
+		state, err := scanState(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run state: %w", err)
+		}
+		states = append(states, *state)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	return states, nil
+}
+
+// CleanupExpired deletes expired run states and returns how many it removed.
+// Expired rows are already invisible to reads; this only reclaims space, and
+// is meant to be called periodically.
+func (s *Store) CleanupExpired(ctx context.Context) (int, error) {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM dago_run_state WHERE expires_at <= $1`, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired run states: %w", err)
+	}
+
+	cleaned := int(tag.RowsAffected())
+	if cleaned > 0 {
+		s.logger.Info("cleaned up expired run states", zap.Int("count", cleaned))
+	}
+	return cleaned, nil
+}
+
+// conflict reports why a CompareAndSwap wrote nothing
+func (s *Store) conflict(ctx context.Context, runID string, revision int64, now time.Time) (int64, error) {
+	var current int64
+	err := s.pool.QueryRow(ctx,
+		`SELECT revision FROM dago_run_state WHERE run_id = $2 AND `+liveCondition,
+		now, runID).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("%w: %s", ports.ErrRunNotFound, runID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to save run state: %w", err)
+	}
+
+	return current, fmt.Errorf("%w: %s is at revision %d, not %d",
+		ports.ErrRunRevisionMismatch, runID, current, revision)
+}
+
+func (s *Store) logSaved(state ports.RunState, revision int64) {
+	s.logger.Debug("run state saved",
+		zap.String("run_id", state.RunID),
+		zap.String("workflow_id", state.WorkflowID),
+		zap.Int64("revision", revision))
+}
+
+// expiresAt returns the expiry of a state written at now, or nil for none
+func expiresAt(now time.Time, ttl time.Duration) *time.Time {
+	if ttl <= 0 {
+		return nil
+	}
+	t := now.Add(ttl)
+	return &t
+}
+
+// scanState scans a row selected with stateColumns
+func scanState(row pgx.Row) (*ports.RunState, error) {
+	var state ports.RunState
+	var expires *time.Time
+	if err := row.Scan(&state.RunID, &state.WorkflowID, &state.Status, &state.Data,
+		&state.Revision, &state.UpdatedAt, &expires); err != nil {
+		return nil, err
+	}
+	if expires != nil {
+		state.ExpiresAt = *expires
+	}
+	return &state, nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+func TestExpiresAt(t *testing.T) {
+	now := time.Now()
+	if got := expiresAt(now, 0); got != nil {
+		t.Errorf("expiresAt(0) = %v, want nil", got)
+	}
+	if got := expiresAt(now, time.Minute); got == nil || !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("expiresAt(1m) = %v, want now+1m", got)
+	}
+}
+
+// Integration test - only runs with POSTGRES_DSN environment variable
+func TestStore_Integration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN not set, skipping integration test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer pool.Close()
+
+	store := NewStore(pool, zap.NewNop())
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	// Running migrations twice must be a no-op
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() second run error = %v", err)
+	}
+
+	runID := "run-pg-integration"
+	workflowID := "workflow-pg-integration"
+	defer func() { _ = store.Delete(ctx, runID) }()
+	_ = store.Delete(ctx, runID)
+
+	state := ports.RunState{RunID: runID, WorkflowID: workflowID, Status: "running", Data: []byte("step-1")}
+	revision, err := store.CompareAndSwap(ctx, state, 0, 0)
+	if err != nil || revision != 1 {
+		t.Fatalf("CompareAndSwap(create) = %d, %v, want revision 1", revision, err)
+	}
+	if _, err := store.CompareAndSwap(ctx, state, 0, 0); !errors.Is(err, ports.ErrRunRevisionMismatch) {
+		t.Errorf("CompareAndSwap(create) of an existing run error = %v, want mismatch", err)
+	}
+
+	state.Data = []byte("step-2")
+	if revision, err := store.CompareAndSwap(ctx, state, 1, 0); err != nil || revision != 2 {
+		t.Fatalf("CompareAndSwap() = %d, %v, want revision 2", revision, err)
+	}
+	if revision, err := store.CompareAndSwap(ctx, state, 1, 0); !errors.Is(err, ports.ErrRunRevisionMismatch) || revision != 2 {
+		t.Errorf("CompareAndSwap() from a stale revision = %d, %v, want mismatch at 2", revision, err)
+	}
+
+	loaded, err := store.Load(ctx, runID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if string(loaded.Data) != "step-2" || loaded.Revision != 2 || !loaded.ExpiresAt.IsZero() {
+		t.Errorf("Load() = %+v", loaded)
+	}
+
+	runs, err := store.ListByWorkflow(ctx, workflowID)
+	if err != nil || len(runs) != 1 || runs[0].RunID != runID {
+		t.Errorf("ListByWorkflow() = %+v, %v", runs, err)
+	}
+
+	// Let the run expire
+	if _, err := store.Save(ctx, state, time.Second); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+
+	if _, err := store.Load(ctx, runID); !errors.Is(err, ports.ErrRunNotFound) {
+		t.Errorf("Load() of an expired run error = %v, want not found", err)
+	}
+	if _, err := store.CompareAndSwap(ctx, state, 3, 0); !errors.Is(err, ports.ErrRunNotFound) {
+		t.Errorf("CompareAndSwap() of an expired run error = %v, want not found", err)
+	}
+	if revision, err := store.Save(ctx, state, time.Second); err != nil || revision != 1 {
+		t.Errorf("Save() over an expired run = %d, %v, want revision 1", revision, err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+
+	cleaned, err := store.CleanupExpired(ctx)
+	if err != nil {
+		t.Fatalf("CleanupExpired() error = %v", err)
+	}
+	if cleaned < 1 {
+		t.Errorf("CleanupExpired() = %d, want at least 1", cleaned)
+	}
+}
@@ -0,0 +1,34 @@
+// Package redis implements ports.StateStore (pkg/ports in this repository)
+// using Redis.
+//
+// Storage structure:
+//   - dago:run_state:{run_id}: Hash with workflow_id, status, data, revision
+//     and updated_at (Unix milliseconds), expired by Redis when saved with a TTL
+//   - dago:run_index:{workflow_id}: Sorted set of run IDs scored by last update
+//
+// Writes go through a Lua script that checks and bumps the revision
+// atomically, so CompareAndSwap is safe across orchestrator instances. The
+// index is not expired with the runs: entries of expired or deleted runs are
+// pruned when the workflow is listed.
+//
+// NewStoreWithNamespace isolates keys under dago:ns:{namespace}:, like the
+// worker registry does.
+//
+// Usage:
+//
+//	store := redis.NewStore(client, logger)
+//
+//	revision, err := store.CompareAndSwap(ctx, ports.RunState{
+//		RunID:      runID,
+//		WorkflowID: "summarize",
+//		Status:     "running",
+//		Data:       checkpoint,
+//	}, 0, 24*time.Hour)
+//
+//	// Later, after resuming from the loaded state
+//	state, err := store.Load(ctx, runID)
+//	_, err = store.CompareAndSwap(ctx, next, state.Revision, 24*time.Hour)
+//	if errors.Is(err, ports.ErrRunRevisionMismatch) {
+//		// Another instance progressed the run; reload and retry or give up
+//	}
+package redis
@@ -0,0 +1,271 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default prefixes of run state hashes and workflow indexes
+	defaultStatePrefix = "dago:run_state:"
+	defaultIndexPrefix = "dago:run_index:"
+
+	// Run state hash fields
+	fieldWorkflowID = "workflow_id"
+	fieldStatus     = "status"
+	fieldData       = "data"
+	fieldRevision   = "revision"
+	fieldUpdatedAt  = "updated_at"
+
+	// Expected revision passed to writeScript for unconditional writes
+	anyRevision = -1
+)
+
+// writeScript writes a run state hash if its revision matches, bumping the
+// revision and setting or clearing its TTL.
+//
+// KEYS[1] = run state key
+// ARGV[1] = expected revision (-1 for any, 0 for none), ARGV[2] = workflow ID,
+// ARGV[3] = status, ARGV[4] = data, ARGV[5] = updated at in Unix milliseconds,
+// ARGV[6] = TTL in milliseconds (0 to keep)
+//
+// Returns {written, revision}: written is 0 on a mismatch, revision is then
+// the current one (0 if the state doesn't exist).
+var writeScript = redis.NewScript(`
+local current = tonumber(redis.call('HGET', KEYS[1], 'revision') or '0')
+local expected = tonumber(ARGV[1])
+if expected >= 0 and current ~= expected then
+	return {0, current}
+end
+
+local revision = current + 1
+redis.call('HSET', KEYS[1],
+	'workflow_id', ARGV[2],
+	'status', ARGV[3],
+	'data', ARGV[4],
+	'updated_at', ARGV[5],
+	'revision', revision)
+
+local ttl = tonumber(ARGV[6])
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+else
+	redis.call('PERSIST', KEYS[1])
+end
+return {1, revision}
+`)
+
+// Store implements ports.StateStore using Redis. Each run is a hash, and
+// each workflow a sorted set of its run IDs scored by last update. Runs
+// expire with their hash; index entries of expired runs are dropped when the
+// workflow is listed. It works with standalone, Sentinel, Cluster and Ring
+// clients, as scripts touch a single key.
+type Store struct {
+	client      redis.UniversalClient
+	logger      *zap.Logger
+	statePrefix string
+	indexPrefix string
+}
+
+// NewStore creates a new Redis run state store
+func NewStore(client redis.UniversalClient, logger *zap.Logger) *Store {
+	return &Store{
+		client:      client,
+		logger:      logger,
+		statePrefix: defaultStatePrefix,
+		indexPrefix: defaultIndexPrefix,
+	}
+}
+
+// NewStoreWithNamespace creates a Redis run state store whose keys are
+// isolated under namespace, e.g. a tenant or environment name
+func NewStoreWithNamespace(client redis.UniversalClient, namespace string, logger *zap.Logger) *Store {
+	prefix := "dago:ns:" + namespace + ":"
+	return &Store{
+		client:      client,
+		logger:      logger,
+		statePrefix: prefix + "run_state:",
+		indexPrefix: prefix + "run_index:",
+	}
+}
+
+// Save writes the run state unconditionally (ports.StateStore interface)
+func (s *Store) Save(ctx context.Context, state ports.RunState, ttl time.Duration) (int64, error) {
+	revision, _, err := s.write(ctx, state, anyRevision, ttl)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save run state: %w", err)
+	}
+	return revision, nil
+}
+
+// CompareAndSwap writes the run state if it is still at revision
+// (ports.StateStore interface)
+func (s *Store) CompareAndSwap(ctx context.Context, state ports.RunState, revision int64, ttl time.Duration) (int64, error) {
+	current, written, err := s.write(ctx, state, revision, ttl)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save run state: %w", err)
+	}
+	if !written {
+		if current == 0 {
+			return 0, fmt.Errorf("%w: %s", ports.ErrRunNotFound, state.RunID)
+		}
+		return current, fmt.Errorf("%w: %s is at revision %d, not %d",
+			ports.ErrRunRevisionMismatch, state.RunID, current, revision)
+	}
+	return current, nil
+}
+
+// Load returns the run state (ports.StateStore interface)
+func (s *Store) Load(ctx context.Context, runID string) (*ports.RunState, error) {
+	states, err := s.load(ctx, []string{runID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run state: %w", err)
+	}
+	if states[0] == nil {
+		return nil, fmt.Errorf("%w: %s", ports.ErrRunNotFound, runID)
+	}
+	return states[0], nil
+}
+
+// Delete removes the run state and its index entry (ports.StateStore interface)
+func (s *Store) Delete(ctx context.Context, runID string) error {
+	workflowID, err := s.client.HGet(ctx, s.stateKey(runID), fieldWorkflowID).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to delete run state: %w", err)
+	}
+
+	if err := s.client.Del(ctx, s.stateKey(runID)).Err(); err != nil {
+		return fmt.Errorf("failed to delete run state: %w", err)
+	}
+	if workflowID != "" {
+		if err := s.client.ZRem(ctx, s.indexKey(workflowID), runID).Err(); err != nil {
+			return fmt.Errorf("failed to delete run state: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListByWorkflow returns the workflow's runs, least recently updated first
+// (ports.StateStore interface)
+func (s *Store) ListByWorkflow(ctx context.Context, workflowID string) ([]ports.RunState, error) {
+	runIDs, err := s.client.ZRange(ctx, s.indexKey(workflowID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	if len(runIDs) == 0 {
+		return nil, nil
+	}
+
+	states, err := s.load(ctx, runIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	var result []ports.RunState
+	var stale []interface{}
+	for i, state := range states {
+		// Expired, or moved to another workflow
+		if state == nil || state.WorkflowID != workflowID {
+			stale = append(stale, runIDs[i])
+			continue
+		}
+		result = append(result, *state)
+	}
+
+	if len(stale) > 0 {
+		if err := s.client.ZRem(ctx, s.indexKey(workflowID), stale...).Err(); err != nil {
+			s.logger.Warn("failed to prune run index",
+				zap.String("workflow_id", workflowID),
+				zap.Error(err))
+		}
+	}
+
+	return result, nil
+}
+
+// write runs writeScript and indexes the run. The index is updated after
+// the state; a run missing from it until the next write is the worst case.
+func (s *Store) write(ctx context.Context, state ports.RunState, revision int64, ttl time.Duration) (int64, bool, error) {
+	now := time.Now()
+
+	res, err := writeScript.Run(ctx, s.client, []string{s.stateKey(state.RunID)},
+		revision, state.WorkflowID, state.Status, state.Data, now.UnixMilli(), ttl.Milliseconds()).Slice()
+	if err != nil {
+		return 0, false, err
+	}
+
+	written, _ := res[0].(int64)
+	current, _ := res[1].(int64)
+	if written == 0 {
+		return current, false, nil
+	}
+
+	err = s.client.ZAdd(ctx, s.indexKey(state.WorkflowID), redis.Z{
+		Score:  float64(now.UnixMilli()),
+		Member: state.RunID,
+	}).Err()
+	if err != nil {
+		return 0, false, err
+	}
+
+	s.logger.Debug("run state saved",
+		zap.String("run_id", state.RunID),
+		zap.String("workflow_id", state.WorkflowID),
+		zap.Int64("revision", current))
+
+	return current, true, nil
+}
+
+// load reads run states in one round trip; missing runs are nil
+func (s *Store) load(ctx context.Context, runIDs []string) ([]*ports.RunState, error) {
+	pipe := s.client.Pipeline()
+	hashes := make([]*redis.MapStringStringCmd, len(runIDs))
+	ttls := make([]*redis.DurationCmd, len(runIDs))
+	for i, runID := range runIDs {
+		hashes[i] = pipe.HGetAll(ctx, s.stateKey(runID))
+		ttls[i] = pipe.PTTL(ctx, s.stateKey(runID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	states := make([]*ports.RunState, len(runIDs))
+	for i, runID := range runIDs {
+		fields := hashes[i].Val()
+		if len(fields) == 0 {
+			continue
+		}
+
+		state := &ports.RunState{
+			RunID:      runID,
+			WorkflowID: fields[fieldWorkflowID],
+			Status:     fields[fieldStatus],
+			Data:       []byte(fields[fieldData]),
+		}
+		state.Revision, _ = strconv.ParseInt(fields[fieldRevision], 10, 64)
+		if millis, err := strconv.ParseInt(fields[fieldUpdatedAt], 10, 64); err == nil {
+			state.UpdatedAt = time.UnixMilli(millis)
+		}
+		if ttl := ttls[i].Val(); ttl > 0 {
+			state.ExpiresAt = time.Now().Add(ttl)
+		}
+		states[i] = state
+	}
+
+	return states, nil
+}
+
+func (s *Store) stateKey(runID string) string {
+	return s.statePrefix + runID
+}
+
+func (s *Store) indexKey(workflowID string) string {
+	return s.indexPrefix + workflowID
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewStore(client, zap.NewNop()), mr
+}
+
+func TestStore_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	state := ports.RunState{RunID: "run-1", WorkflowID: "summarize", Status: "running", Data: []byte(`{"step":1}`)}
+
+	revision, err := store.CompareAndSwap(ctx, state, 0, 0)
+	if err != nil || revision != 1 {
+		t.Fatalf("CompareAndSwap(create) = %d, %v, want revision 1", revision, err)
+	}
+	if _, err := store.CompareAndSwap(ctx, state, 0, 0); !errors.Is(err, ports.ErrRunRevisionMismatch) {
+		t.Errorf("CompareAndSwap(create) of an existing run error = %v, want mismatch", err)
+	}
+
+	loaded, err := store.Load(ctx, "run-1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if string(loaded.Data) != `{"step":1}` || loaded.Revision != 1 || loaded.Status != "running" || loaded.UpdatedAt.IsZero() {
+		t.Errorf("Load() = %+v", loaded)
+	}
+
+	// Two instances resume from revision 1; only the first one wins
+	state.Data = []byte(`{"step":2}`)
+	if revision, err := store.CompareAndSwap(ctx, state, loaded.Revision, 0); err != nil || revision != 2 {
+		t.Fatalf("CompareAndSwap() = %d, %v, want revision 2", revision, err)
+	}
+	state.Data = []byte(`{"step":"stale"}`)
+	revision, err = store.CompareAndSwap(ctx, state, loaded.Revision, 0)
+	if !errors.Is(err, ports.ErrRunRevisionMismatch) || revision != 2 {
+		t.Errorf("CompareAndSwap() from a stale revision = %d, %v, want mismatch at 2", revision, err)
+	}
+
+	// Save ignores revisions
+	if revision, err := store.Save(ctx, state, 0); err != nil || revision != 3 {
+		t.Errorf("Save() = %d, %v, want revision 3", revision, err)
+	}
+
+	if _, err := store.CompareAndSwap(ctx, ports.RunState{RunID: "run-missing"}, 4, 0); !errors.Is(err, ports.ErrRunNotFound) {
+		t.Errorf("CompareAndSwap() of a missing run error = %v, want not found", err)
+	}
+}
+
+func TestStore_ListAndExpire(t *testing.T) {
+	ctx := context.Background()
+	store, mr := newTestStore(t)
+
+	for _, runID := range []string{"run-1", "run-2"} {
+		if _, err := store.Save(ctx, ports.RunState{RunID: runID, WorkflowID: "summarize"}, 0); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if _, err := store.Save(ctx, ports.RunState{RunID: "run-3", WorkflowID: "summarize"}, time.Minute); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := store.Save(ctx, ports.RunState{RunID: "run-4", WorkflowID: "translate"}, 0); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	runs, err := store.ListByWorkflow(ctx, "summarize")
+	if err != nil {
+		t.Fatalf("ListByWorkflow() error = %v", err)
+	}
+	if len(runs) != 3 || runs[2].RunID != "run-3" || runs[2].ExpiresAt.IsZero() || !runs[0].ExpiresAt.IsZero() {
+		t.Fatalf("ListByWorkflow() = %+v, want three runs, run-3 expiring", runs)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if err := store.Delete(ctx, "run-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	runs, err = store.ListByWorkflow(ctx, "summarize")
+	if err != nil {
+		t.Fatalf("ListByWorkflow() error = %v", err)
+	}
+	if len(runs) != 1 || runs[0].RunID != "run-2" {
+		t.Errorf("ListByWorkflow() = %+v, want run-2 only", runs)
+	}
+	if _, err := store.Load(ctx, "run-3"); !errors.Is(err, ports.ErrRunNotFound) {
+		t.Errorf("Load() of an expired run error = %v, want not found", err)
+	}
+	if members, _ := mr.ZMembers(defaultIndexPrefix + "summarize"); len(members) != 1 {
+		t.Errorf("index members = %v, want expired runs pruned", members)
+	}
+}
@@ -0,0 +1,317 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// AnthropicServer is a fake Anthropic Messages API (POST /v1/messages),
+// with SSE streaming and tool use, so that Anthropic clients can be tested
+// without an API key.
+//
+// Requests are answered with the replies queued with Reply, in order; once
+// the queue is empty, the last user message is echoed. When a request
+// forces a tool with tool_choice, a reply without tool calls is sent as a
+// call of that tool, its text being the input. Requests without an
+// x-api-key header are rejected like the API does.
+type AnthropicServer struct {
+	*httptest.Server
+	requestLog
+
+	replies replyQueue[Reply]
+
+	mu    sync.Mutex
+	count int
+}
+
+// NewAnthropicServer starts a fake Anthropic API, closed when the test ends.
+// Its URL is the base URL to give to clients.
+func NewAnthropicServer(t testing.TB) *AnthropicServer {
+	t.Helper()
+	s := &AnthropicServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/messages", s.handleMessages)
+
+	s.Server = httptest.NewServer(s.record(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Reply queues replies to the next requests
+func (s *AnthropicServer) Reply(replies ...Reply) {
+	s.replies.push(replies...)
+}
+
+// anthropicRequest is the part of a Messages API request the server reads
+type anthropicRequest struct {
+	Model      string `json:"model"`
+	Stream     bool   `json:"stream"`
+	ToolChoice struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"tool_choice"`
+	Messages []struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"messages"`
+}
+
+// prompt returns the text of the last user message
+func (r *anthropicRequest) prompt() string {
+	prompt := ""
+	for _, msg := range r.Messages {
+		if msg.Role != "user" {
+			continue
+		}
+		var text string
+		if json.Unmarshal(msg.Content, &text) == nil {
+			prompt = text
+			continue
+		}
+		var blocks []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if json.Unmarshal(msg.Content, &blocks) == nil {
+			for _, block := range blocks {
+				if block.Type == "text" {
+					prompt = block.Text
+				}
+			}
+		}
+	}
+	return prompt
+}
+
+func (s *AnthropicServer) handleMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("x-api-key") == "" {
+		writeAnthropicError(w, http.StatusUnauthorized, "authentication_error", "x-api-key header is required")
+		return
+	}
+
+	var req anthropicRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAnthropicError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	prompt := req.prompt()
+	reply := s.replies.next(TextReply(prompt))
+	reply.setHeader(w)
+
+	if reply.Status != 0 {
+		writeAnthropicError(w, reply.Status, reply.ErrorType, reply.Error)
+		return
+	}
+	if req.ToolChoice.Type == "tool" && len(reply.ToolCalls) == 0 {
+		reply.ToolCalls = []ToolCall{{ID: "toolu_forced", Name: req.ToolChoice.Name, Arguments: reply.content()}}
+		reply.Chunks = nil
+	}
+
+	s.mu.Lock()
+	id := fmt.Sprintf("msg_test_%d", s.count)
+	s.count++
+	s.mu.Unlock()
+
+	input, output := reply.usage(prompt)
+	stopReason := reply.StopReason
+	if stopReason == "" {
+		stopReason = "end_turn"
+		if len(reply.ToolCalls) > 0 {
+			stopReason = "tool_use"
+		}
+	}
+
+	if !req.Stream {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		if reply.Error != "" {
+			writeAnthropicError(w, http.StatusInternalServerError, reply.ErrorType, reply.Error)
+			return
+		}
+
+		content := []interface{}{}
+		if len(reply.Thinking) > 0 {
+			content = append(content, map[string]interface{}{
+				"type":      "thinking",
+				"thinking":  strings.Join(reply.Thinking, ""),
+				"signature": anthropicSignature,
+			})
+		}
+		if text := reply.content(); text != "" || len(reply.ToolCalls) == 0 {
+			content = append(content, map[string]interface{}{"type": "text", "text": text})
+		}
+		for i, call := range reply.ToolCalls {
+			content = append(content, anthropicToolUse(i, call, true))
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":            id,
+			"type":          "message",
+			"role":          "assistant",
+			"model":         req.Model,
+			"content":       content,
+			"stop_reason":   stopReason,
+			"stop_sequence": nil,
+			"usage":         map[string]int{"input_tokens": input, "output_tokens": output},
+		})
+		return
+	}
+
+	sse := newSSEWriter(w)
+	sse.event("message_start", map[string]interface{}{
+		"type": "message_start",
+		"message": map[string]interface{}{
+			"id":            id,
+			"type":          "message",
+			"role":          "assistant",
+			"model":         req.Model,
+			"content":       []interface{}{},
+			"stop_reason":   nil,
+			"stop_sequence": nil,
+			"usage":         map[string]int{"input_tokens": input, "output_tokens": 1},
+		},
+	})
+
+	index := 0
+	if len(reply.Thinking) > 0 {
+		sse.event("content_block_start", map[string]interface{}{
+			"type":          "content_block_start",
+			"index":         index,
+			"content_block": map[string]interface{}{"type": "thinking", "thinking": "", "signature": ""},
+		})
+		for _, chunk := range reply.Thinking {
+			if !wait(r, reply.Delay) {
+				return
+			}
+			sse.event("content_block_delta", map[string]interface{}{
+				"type":  "content_block_delta",
+				"index": index,
+				"delta": map[string]string{"type": "thinking_delta", "thinking": chunk},
+			})
+		}
+		sse.event("content_block_delta", map[string]interface{}{
+			"type":  "content_block_delta",
+			"index": index,
+			"delta": map[string]string{"type": "signature_delta", "signature": anthropicSignature},
+		})
+		sse.event("content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": index})
+		index++
+	}
+
+	if len(reply.Chunks) > 0 {
+		sse.event("content_block_start", map[string]interface{}{
+			"type":          "content_block_start",
+			"index":         index,
+			"content_block": map[string]interface{}{"type": "text", "text": ""},
+		})
+		sse.event("ping", map[string]string{"type": "ping"})
+		for _, chunk := range reply.Chunks {
+			if !wait(r, reply.Delay) {
+				return
+			}
+			sse.event("content_block_delta", map[string]interface{}{
+				"type":  "content_block_delta",
+				"index": index,
+				"delta": map[string]string{"type": "text_delta", "text": chunk},
+			})
+		}
+		sse.event("content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": index})
+		index++
+	}
+
+	if reply.Error != "" {
+		errorType := reply.ErrorType
+		if errorType == "" {
+			errorType = "overloaded_error"
+		}
+		sse.event("error", map[string]interface{}{
+			"type":  "error",
+			"error": map[string]string{"type": errorType, "message": reply.Error},
+		})
+		return
+	}
+
+	for i, call := range reply.ToolCalls {
+		sse.event("content_block_start", map[string]interface{}{
+			"type":          "content_block_start",
+			"index":         index,
+			"content_block": anthropicToolUse(i, call, false),
+		})
+		for _, part := range splitArguments(call.Arguments) {
+			sse.event("content_block_delta", map[string]interface{}{
+				"type":  "content_block_delta",
+				"index": index,
+				"delta": map[string]string{"type": "input_json_delta", "partial_json": part},
+			})
+		}
+		sse.event("content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": index})
+		index++
+	}
+
+	sse.event("message_delta", map[string]interface{}{
+		"type":  "message_delta",
+		"delta": map[string]interface{}{"stop_reason": stopReason, "stop_sequence": nil},
+		"usage": map[string]int{"output_tokens": output},
+	})
+	sse.event("message_stop", map[string]string{"type": "message_stop"})
+}
+
+// Signature of thinking blocks, which clients send back unchanged
+const anthropicSignature = "c2lnbmF0dXJl"
+
+// anthropicToolUse returns the tool_use block of call, with its input when
+// complete is set or empty as in content_block_start events
+func anthropicToolUse(i int, call ToolCall, complete bool) map[string]interface{} {
+	id := call.ID
+	if id == "" {
+		id = fmt.Sprintf("toolu_test_%d", i)
+	}
+	input := json.RawMessage("{}")
+	if complete && call.Arguments != "" {
+		input = json.RawMessage(call.Arguments)
+	}
+	return map[string]interface{}{
+		"type":  "tool_use",
+		"id":    id,
+		"name":  call.Name,
+		"input": input,
+	}
+}
+
+func writeAnthropicError(w http.ResponseWriter, status int, errorType, message string) {
+	if errorType == "" {
+		errorType = anthropicErrorType(status)
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"type":  "error",
+		"error": map[string]string{"type": errorType, "message": message},
+	})
+}
+
+// anthropicErrorType returns the error type the API uses for status
+func anthropicErrorType(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return "authentication_error"
+	case http.StatusForbidden:
+		return "permission_error"
+	case http.StatusNotFound:
+		return "not_found_error"
+	case http.StatusRequestEntityTooLarge:
+		return "request_too_large"
+	case http.StatusTooManyRequests:
+		return "rate_limit_error"
+	case 529:
+		return "overloaded_error"
+	case http.StatusInternalServerError:
+		return "api_error"
+	default:
+		return "invalid_request_error"
+	}
+}
@@ -0,0 +1,331 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go/eventstream"
+)
+
+// BedrockServer is a fake Amazon Bedrock Runtime Converse API
+// (POST /model/{modelId}/converse and /converse-stream), with event stream
+// responses and tool use, so that Bedrock clients can be tested without
+// AWS credentials.
+//
+// Requests are answered with the replies queued with Reply, in order; once
+// the queue is empty, the last user message is echoed. When a request
+// forces a tool with toolChoice, a reply without tool calls is sent as a
+// call of that tool, its text being the input. Requests with a
+// guardrailConfig enabling its trace get the guardrail's assessment, which
+// blocks the input when the reply's StopReason is guardrail_intervened.
+// Requests without a SigV4 signature are rejected like the API does; any
+// credentials are accepted.
+type BedrockServer struct {
+	*httptest.Server
+	requestLog
+
+	replies replyQueue[Reply]
+}
+
+// NewBedrockServer starts a fake Bedrock Runtime API, closed when the test
+// ends. Give its URL to clients as the base endpoint.
+func NewBedrockServer(t testing.TB) *BedrockServer {
+	t.Helper()
+	s := &BedrockServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /model/{model}/converse", func(w http.ResponseWriter, r *http.Request) {
+		s.handleConverse(w, r, false)
+	})
+	mux.HandleFunc("POST /model/{model}/converse-stream", func(w http.ResponseWriter, r *http.Request) {
+		s.handleConverse(w, r, true)
+	})
+
+	s.Server = httptest.NewServer(s.record(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Reply queues replies to the next requests
+func (s *BedrockServer) Reply(replies ...Reply) {
+	s.replies.push(replies...)
+}
+
+// bedrockRequest is the part of a Converse request the server reads
+type bedrockRequest struct {
+	Messages []struct {
+		Role    string `json:"role"`
+		Content []struct {
+			Text *string `json:"text"`
+		} `json:"content"`
+	} `json:"messages"`
+	ToolConfig struct {
+		ToolChoice struct {
+			Tool *struct {
+				Name string `json:"name"`
+			} `json:"tool"`
+		} `json:"toolChoice"`
+	} `json:"toolConfig"`
+	GuardrailConfig *struct {
+		GuardrailIdentifier string `json:"guardrailIdentifier"`
+		Trace               string `json:"trace"`
+	} `json:"guardrailConfig"`
+}
+
+// guardrailTrace returns the trace of the request's guardrail, nil without
+// one or with its trace disabled
+func (r *bedrockRequest) guardrailTrace(stopReason string) map[string]interface{} {
+	if r.GuardrailConfig == nil || r.GuardrailConfig.Trace != "enabled" {
+		return nil
+	}
+	assessment := map[string]interface{}{}
+	reason := "No action."
+	if stopReason == "guardrail_intervened" {
+		assessment["contentPolicy"] = map[string]interface{}{
+			"filters": []map[string]string{{"type": "VIOLENCE", "confidence": "HIGH", "action": "BLOCKED"}},
+		}
+		reason = "Guardrail blocked."
+	}
+	return map[string]interface{}{"guardrail": map[string]interface{}{
+		"inputAssessment": map[string]interface{}{r.GuardrailConfig.GuardrailIdentifier: assessment},
+		"actionReason":    reason,
+	}}
+}
+
+// prompt returns the text of the last user message
+func (r *bedrockRequest) prompt() string {
+	prompt := ""
+	for _, msg := range r.Messages {
+		if msg.Role != "user" {
+			continue
+		}
+		for _, block := range msg.Content {
+			if block.Text != nil {
+				prompt = *block.Text
+			}
+		}
+	}
+	return prompt
+}
+
+func (s *BedrockServer) handleConverse(w http.ResponseWriter, r *http.Request, stream bool) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		writeBedrockError(w, http.StatusForbidden, "MissingAuthenticationTokenException", "Missing Authentication Token")
+		return
+	}
+
+	var req bedrockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBedrockError(w, http.StatusBadRequest, "ValidationException", err.Error())
+		return
+	}
+	prompt := req.prompt()
+	reply := s.replies.next(TextReply(prompt))
+	reply.setHeader(w)
+
+	if reply.Status != 0 {
+		writeBedrockError(w, reply.Status, reply.ErrorType, reply.Error)
+		return
+	}
+	if forced := req.ToolConfig.ToolChoice.Tool; forced != nil && len(reply.ToolCalls) == 0 {
+		reply.ToolCalls = []ToolCall{{ID: "tooluse_forced", Name: forced.Name, Arguments: reply.content()}}
+		reply.Chunks = nil
+	}
+
+	input, output := reply.usage(prompt)
+	usage := map[string]int{
+		"inputTokens":  input,
+		"outputTokens": output,
+		"totalTokens":  input + output,
+	}
+	stopReason := reply.StopReason
+	if stopReason == "" {
+		stopReason = "end_turn"
+		if len(reply.ToolCalls) > 0 {
+			stopReason = "tool_use"
+		}
+	}
+
+	if !stream {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		if reply.Error != "" {
+			writeBedrockError(w, http.StatusInternalServerError, reply.ErrorType, reply.Error)
+			return
+		}
+
+		var content []interface{}
+		if text := reply.content(); text != "" || len(reply.ToolCalls) == 0 {
+			content = append(content, map[string]string{"text": text})
+		}
+		for i, call := range reply.ToolCalls {
+			arguments := call.Arguments
+			if arguments == "" {
+				arguments = "{}"
+			}
+			content = append(content, map[string]interface{}{"toolUse": map[string]interface{}{
+				"toolUseId": bedrockToolUseID(i, call),
+				"name":      call.Name,
+				"input":     json.RawMessage(arguments),
+			}})
+		}
+		resp := map[string]interface{}{
+			"output": map[string]interface{}{
+				"message": map[string]interface{}{"role": "assistant", "content": content},
+			},
+			"stopReason": stopReason,
+			"usage":      usage,
+			"metrics":    map[string]int{"latencyMs": 1},
+		}
+		if trace := req.guardrailTrace(stopReason); trace != nil {
+			resp["trace"] = trace
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	events := newBedrockEventWriter(w)
+	events.event("messageStart", map[string]string{"role": "assistant"})
+	for _, text := range reply.Chunks {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		events.event("contentBlockDelta", map[string]interface{}{
+			"contentBlockIndex": 0,
+			"delta":             map[string]string{"text": text},
+		})
+	}
+
+	if reply.Error != "" {
+		errorType := reply.ErrorType
+		if errorType == "" {
+			errorType = "modelStreamErrorException"
+		}
+		events.exception(errorType, reply.Error)
+		return
+	}
+
+	index := 0
+	if len(reply.Chunks) > 0 {
+		events.event("contentBlockStop", map[string]int{"contentBlockIndex": index})
+		index++
+	}
+	for i, call := range reply.ToolCalls {
+		events.event("contentBlockStart", map[string]interface{}{
+			"contentBlockIndex": index,
+			"start": map[string]interface{}{"toolUse": map[string]string{
+				"toolUseId": bedrockToolUseID(i, call),
+				"name":      call.Name,
+			}},
+		})
+		for _, part := range splitArguments(call.Arguments) {
+			events.event("contentBlockDelta", map[string]interface{}{
+				"contentBlockIndex": index,
+				"delta":             map[string]interface{}{"toolUse": map[string]string{"input": part}},
+			})
+		}
+		events.event("contentBlockStop", map[string]int{"contentBlockIndex": index})
+		index++
+	}
+
+	events.event("messageStop", map[string]string{"stopReason": stopReason})
+	metadata := map[string]interface{}{
+		"usage":   usage,
+		"metrics": map[string]int{"latencyMs": 1},
+	}
+	if trace := req.guardrailTrace(stopReason); trace != nil {
+		metadata["trace"] = trace
+	}
+	events.event("metadata", metadata)
+}
+
+func bedrockToolUseID(i int, call ToolCall) string {
+	if call.ID != "" {
+		return call.ID
+	}
+	return fmt.Sprintf("tooluse_test_%d", i)
+}
+
+// bedrockEventWriter writes the messages of an AWS event stream
+type bedrockEventWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	encoder *eventstream.Encoder
+}
+
+func newBedrockEventWriter(w http.ResponseWriter) *bedrockEventWriter {
+	w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	return &bedrockEventWriter{w: w, flusher: flusher, encoder: eventstream.NewEncoder()}
+}
+
+// event writes an event of the type with data encoded as JSON
+func (e *bedrockEventWriter) event(eventType string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	e.write(eventstream.Message{
+		Headers: eventstream.Headers{
+			{Name: eventstream.MessageTypeHeader, Value: eventstream.StringValue(eventstream.EventMessageType)},
+			{Name: eventstream.EventTypeHeader, Value: eventstream.StringValue(eventType)},
+			{Name: eventstream.ContentTypeHeader, Value: eventstream.StringValue("application/json")},
+		},
+		Payload: payload,
+	})
+}
+
+// exception writes an exception ending the stream, e.g. a
+// modelStreamErrorException
+func (e *bedrockEventWriter) exception(exceptionType, message string) {
+	payload, _ := json.Marshal(map[string]string{"message": message})
+	e.write(eventstream.Message{
+		Headers: eventstream.Headers{
+			{Name: eventstream.MessageTypeHeader, Value: eventstream.StringValue(eventstream.ExceptionMessageType)},
+			{Name: eventstream.ExceptionTypeHeader, Value: eventstream.StringValue(exceptionType)},
+			{Name: eventstream.ContentTypeHeader, Value: eventstream.StringValue("application/json")},
+		},
+		Payload: payload,
+	})
+}
+
+func (e *bedrockEventWriter) write(msg eventstream.Message) {
+	var buf bytes.Buffer
+	_ = e.encoder.Encode(&buf, msg)
+	_, _ = e.w.Write(buf.Bytes())
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+}
+
+func writeBedrockError(w http.ResponseWriter, status int, errorType, message string) {
+	if errorType == "" {
+		errorType = bedrockErrorType(status)
+	}
+	w.Header().Set("X-Amzn-Errortype", errorType)
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+// bedrockErrorType returns the exception the API returns with status
+func bedrockErrorType(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return "UnrecognizedClientException"
+	case http.StatusForbidden:
+		return "AccessDeniedException"
+	case http.StatusNotFound:
+		return "ResourceNotFoundException"
+	case http.StatusTooManyRequests:
+		return "ThrottlingException"
+	case http.StatusServiceUnavailable:
+		return "ServiceUnavailableException"
+	}
+	if status >= http.StatusInternalServerError {
+		return "InternalServerException"
+	}
+	return "ValidationException"
+}
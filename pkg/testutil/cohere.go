@@ -0,0 +1,206 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// CohereServer is a fake Cohere Chat API, v1 (POST /v1/chat), with
+// newline-delimited JSON streaming and tool calls, so that Cohere clients
+// can be tested without an API key.
+//
+// Requests are answered with the replies queued with Reply, in order; once
+// the queue is empty, the message is echoed. Requests without a bearer
+// token are rejected like the API does. Cohere doesn't identify tool calls,
+// so the IDs of scripted calls are ignored. An Error without Status ends the
+// stream with an ERROR finish reason, or ErrorType if set, as the API
+// reports no message with it.
+type CohereServer struct {
+	*httptest.Server
+	requestLog
+
+	replies replyQueue[Reply]
+
+	mu    sync.Mutex
+	count int
+}
+
+// NewCohereServer starts a fake Cohere API, closed when the test ends.
+// BaseURL is the base URL to give to clients.
+func NewCohereServer(t testing.TB) *CohereServer {
+	t.Helper()
+	s := &CohereServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat", s.handleChat)
+
+	s.Server = httptest.NewServer(s.record(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// BaseURL returns the server's URL with the /v1 path clients expect
+func (s *CohereServer) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// Reply queues replies to the next requests
+func (s *CohereServer) Reply(replies ...Reply) {
+	s.replies.push(replies...)
+}
+
+// cohereRequest is the part of a chat request the server reads
+type cohereRequest struct {
+	Message     string `json:"message"`
+	Stream      bool   `json:"stream"`
+	ChatHistory []struct {
+		Role    string `json:"role"`
+		Message string `json:"message"`
+	} `json:"chat_history"`
+}
+
+// prompt returns the message, or the last user message of the history when
+// the request only sends tool results
+func (r *cohereRequest) prompt() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	prompt := ""
+	for _, msg := range r.ChatHistory {
+		if msg.Role == "USER" {
+			prompt = msg.Message
+		}
+	}
+	return prompt
+}
+
+func (s *CohereServer) handleChat(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeCohereError(w, http.StatusUnauthorized, "no api key supplied")
+		return
+	}
+
+	var req cohereRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeCohereError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	prompt := req.prompt()
+	reply := s.replies.next(TextReply(prompt))
+	reply.setHeader(w)
+
+	if reply.Status != 0 {
+		writeCohereError(w, reply.Status, reply.Error)
+		return
+	}
+
+	s.mu.Lock()
+	id := fmt.Sprintf("test-%d", s.count)
+	s.count++
+	s.mu.Unlock()
+
+	input, output := reply.usage(prompt)
+	finishReason := reply.StopReason
+	if finishReason == "" {
+		finishReason = "COMPLETE"
+	}
+	var calls []interface{}
+	for _, call := range reply.ToolCalls {
+		arguments := call.Arguments
+		if arguments == "" {
+			arguments = "{}"
+		}
+		calls = append(calls, map[string]interface{}{"name": call.Name, "parameters": json.RawMessage(arguments)})
+	}
+	response := func(text, finishReason string) map[string]interface{} {
+		resp := map[string]interface{}{
+			"response_id":   "resp-" + id,
+			"generation_id": "gen-" + id,
+			"text":          text,
+			"finish_reason": finishReason,
+			"meta": map[string]interface{}{
+				"api_version":  map[string]string{"version": "1"},
+				"billed_units": map[string]int{"input_tokens": input, "output_tokens": output},
+				"tokens":       map[string]int{"input_tokens": input, "output_tokens": output},
+			},
+		}
+		if len(calls) > 0 {
+			resp["tool_calls"] = calls
+		}
+		return resp
+	}
+
+	if !req.Stream {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		if reply.Error != "" {
+			writeCohereError(w, http.StatusInternalServerError, reply.Error)
+			return
+		}
+		writeJSON(w, http.StatusOK, response(reply.content(), finishReason))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/stream+json")
+	flusher, _ := w.(http.Flusher)
+	event := func(eventType string, fields map[string]interface{}) {
+		if fields == nil {
+			fields = map[string]interface{}{}
+		}
+		fields["event_type"] = eventType
+		fields["is_finished"] = eventType == "stream-end"
+		data, _ := json.Marshal(fields)
+		_, _ = w.Write(append(data, '\n'))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	event("stream-start", map[string]interface{}{"generation_id": "gen-" + id})
+	for _, text := range reply.Chunks {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		event("text-generation", map[string]interface{}{"text": text})
+	}
+
+	if reply.Error != "" {
+		errorReason := reply.ErrorType
+		if errorReason == "" {
+			errorReason = "ERROR"
+		}
+		event("stream-end", map[string]interface{}{
+			"finish_reason": errorReason,
+			"response":      response(reply.content(), errorReason),
+		})
+		return
+	}
+
+	for i, call := range reply.ToolCalls {
+		event("tool-calls-chunk", map[string]interface{}{
+			"tool_call_delta": map[string]interface{}{"index": i, "name": call.Name},
+		})
+		for _, part := range splitArguments(call.Arguments) {
+			event("tool-calls-chunk", map[string]interface{}{
+				"tool_call_delta": map[string]interface{}{"index": i, "parameters": part},
+			})
+		}
+	}
+	if len(calls) > 0 {
+		event("tool-calls-generation", map[string]interface{}{"text": reply.content(), "tool_calls": calls})
+	}
+	event("stream-end", map[string]interface{}{
+		"finish_reason": finishReason,
+		"response":      response(reply.content(), finishReason),
+	})
+}
+
+func writeCohereError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
@@ -0,0 +1,38 @@
+// Package testutil provides fake servers for testing adapters and their
+// consumers hermetically, without API keys or locally running services.
+//
+// Available fakes:
+//   - OllamaServer: Ollama chat, generate, tags and embed endpoints, with
+//     scripted replies and NDJSON streaming
+//   - AnthropicServer: Anthropic Messages API, with SSE streaming and tool use
+//   - OpenAIServer: OpenAI Chat Completions API, with SSE streaming and tool
+//     calls
+//   - GeminiServer: Gemini generateContent and streamGenerateContent, with
+//     function calls
+//   - BedrockServer: Amazon Bedrock Converse and ConverseStream, with event
+//     stream responses and tool use
+//   - CohereServer: Cohere Chat API v1, with newline-delimited JSON streaming
+//     and tool calls
+//
+// The Anthropic, OpenAI, Gemini, Bedrock and Cohere servers share the Reply
+// type to script responses, and record every Request so tests can assert
+// what clients send. GoldenJSON compares a recorded request body with a golden file under
+// testdata/golden, rewritten with go test -update, to catch changes in what
+// clients send.
+//
+// Usage:
+//
+//	srv := testutil.NewOllamaServer(t)
+//	srv.ReplyChat(testutil.OllamaReply{Chunks: []string{"Hello", ", World!"}})
+//
+//	client, err := ollama.NewClient(srv.URL, zap.NewNop())
+//	resp, err := client.GenerateCompletion(ctx, req)
+//
+//	if got := srv.LastChatRequest(); got.Model != "llama3.1" {
+//		t.Errorf("model = %s", got.Model)
+//	}
+//
+//	api := testutil.NewAnthropicServer(t)
+//	api.Reply(testutil.Reply{ToolCalls: []testutil.ToolCall{{Name: "search", Arguments: `{"q":"go"}`}}})
+//	client.SetBaseURL(api.URL)
+package testutil
@@ -0,0 +1,250 @@
+package testutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// GeminiServer is a fake Gemini API (POST /{version}/models/{model}:generateContent
+// and :streamGenerateContent), with function calls, so that Gemini clients
+// can be tested without an API key. Streams are sent as SSE with alt=sse,
+// as the REST API does, and as a JSON array otherwise.
+//
+// Requests are answered with the replies queued with Reply, in order; once
+// the queue is empty, the last user text is echoed. Requests without an API
+// key (x-goog-api-key header or key parameter) are rejected like the API
+// does.
+type GeminiServer struct {
+	*httptest.Server
+	requestLog
+
+	replies replyQueue[Reply]
+}
+
+// NewGeminiServer starts a fake Gemini API, closed when the test ends. Its
+// URL is the endpoint to give to clients.
+func NewGeminiServer(t testing.TB) *GeminiServer {
+	t.Helper()
+	s := &GeminiServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /{version}/models/{action}", s.handleModels)
+
+	s.Server = httptest.NewServer(s.record(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Reply queues replies to the next requests
+func (s *GeminiServer) Reply(replies ...Reply) {
+	s.replies.push(replies...)
+}
+
+// geminiRequest is the part of a generateContent request the server reads
+type geminiRequest struct {
+	Contents []struct {
+		Role  string `json:"role"`
+		Parts []struct {
+			Text string `json:"text"`
+		} `json:"parts"`
+	} `json:"contents"`
+}
+
+// prompt returns the last text sent by the user
+func (r *geminiRequest) prompt() string {
+	prompt := ""
+	for _, content := range r.Contents {
+		if content.Role != "" && content.Role != "user" {
+			continue
+		}
+		for _, part := range content.Parts {
+			if part.Text != "" {
+				prompt = part.Text
+			}
+		}
+	}
+	return prompt
+}
+
+func (s *GeminiServer) handleModels(w http.ResponseWriter, r *http.Request) {
+	model, method, _ := strings.Cut(r.PathValue("action"), ":")
+	if method != "generateContent" && method != "streamGenerateContent" {
+		writeGeminiError(w, http.StatusNotFound, "", "method not found: "+method)
+		return
+	}
+	if r.Header.Get("x-goog-api-key") == "" && r.URL.Query().Get("key") == "" {
+		writeGeminiError(w, http.StatusForbidden, "", "Method doesn't allow unregistered callers")
+		return
+	}
+
+	var req geminiRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeGeminiError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	prompt := req.prompt()
+	reply := s.replies.next(TextReply(prompt))
+	reply.setHeader(w)
+
+	if reply.Status != 0 {
+		writeGeminiError(w, reply.Status, reply.ErrorType, reply.Error)
+		return
+	}
+
+	input, output := reply.usage(prompt)
+	finishReason := reply.StopReason
+	if finishReason == "" {
+		finishReason = "STOP"
+	}
+	response := func(parts []interface{}, last bool) map[string]interface{} {
+		candidate := map[string]interface{}{
+			"content": map[string]interface{}{"parts": parts, "role": "model"},
+			"index":   0,
+		}
+		resp := map[string]interface{}{
+			"candidates":   []interface{}{candidate},
+			"modelVersion": model,
+		}
+		if last {
+			candidate["finishReason"] = finishReason
+			resp["usageMetadata"] = map[string]int{
+				"promptTokenCount":     input,
+				"candidatesTokenCount": output,
+				"totalTokenCount":      input + output,
+			}
+		}
+		return resp
+	}
+	functionCalls := func() []interface{} {
+		var parts []interface{}
+		for _, call := range reply.ToolCalls {
+			args := json.RawMessage("{}")
+			if call.Arguments != "" {
+				args = json.RawMessage(call.Arguments)
+			}
+			parts = append(parts, map[string]interface{}{
+				"functionCall": map[string]interface{}{"name": call.Name, "args": args},
+			})
+		}
+		return parts
+	}
+
+	if method == "generateContent" {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		if reply.Error != "" {
+			writeGeminiError(w, http.StatusInternalServerError, reply.ErrorType, reply.Error)
+			return
+		}
+
+		var parts []interface{}
+		if text := reply.content(); text != "" || len(reply.ToolCalls) == 0 {
+			parts = append(parts, map[string]string{"text": text})
+		}
+		writeJSON(w, http.StatusOK, response(append(parts, functionCalls()...), true))
+		return
+	}
+
+	stream := newGeminiStream(w, r.URL.Query().Get("alt") == "sse")
+	defer stream.close()
+
+	for i, chunk := range reply.Chunks {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		last := i == len(reply.Chunks)-1 && len(reply.ToolCalls) == 0 && reply.Error == ""
+		stream.send(response([]interface{}{map[string]string{"text": chunk}}, last))
+	}
+	if reply.Error != "" {
+		stream.send(geminiError(http.StatusInternalServerError, reply.ErrorType, reply.Error))
+		return
+	}
+	if len(reply.ToolCalls) > 0 || len(reply.Chunks) == 0 {
+		stream.send(response(functionCalls(), true))
+	}
+}
+
+// geminiStream writes streamed responses as SSE or as a JSON array
+type geminiStream struct {
+	w     http.ResponseWriter
+	sse   *sseWriter
+	count int
+}
+
+func newGeminiStream(w http.ResponseWriter, sse bool) *geminiStream {
+	if sse {
+		return &geminiStream{w: w, sse: newSSEWriter(w)}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return &geminiStream{w: w}
+}
+
+func (s *geminiStream) send(v interface{}) {
+	if s.sse != nil {
+		s.sse.event("", v)
+		return
+	}
+
+	separator := "["
+	if s.count > 0 {
+		separator = "\n,\r\n"
+	}
+	data, _ := json.Marshal(v)
+	_, _ = s.w.Write(append([]byte(separator), data...))
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	s.count++
+}
+
+func (s *geminiStream) close() {
+	if s.sse != nil {
+		return
+	}
+	if s.count == 0 {
+		_, _ = s.w.Write([]byte("["))
+	}
+	_, _ = s.w.Write([]byte("]"))
+}
+
+func writeGeminiError(w http.ResponseWriter, status int, errorStatus, message string) {
+	writeJSON(w, status, geminiError(status, errorStatus, message))
+}
+
+// geminiError returns an error in the Google API format
+func geminiError(code int, status, message string) map[string]interface{} {
+	if status == "" {
+		status = geminiErrorStatus(code)
+	}
+	return map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"status":  status,
+		},
+	}
+}
+
+// geminiErrorStatus returns the status the API uses for an HTTP code
+func geminiErrorStatus(code int) string {
+	switch code {
+	case http.StatusBadRequest:
+		return "INVALID_ARGUMENT"
+	case http.StatusForbidden:
+		return "PERMISSION_DENIED"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		return "RESOURCE_EXHAUSTED"
+	case http.StatusServiceUnavailable:
+		return "UNAVAILABLE"
+	case http.StatusGatewayTimeout:
+		return "DEADLINE_EXCEEDED"
+	default:
+		return "INTERNAL"
+	}
+}
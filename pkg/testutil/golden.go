@@ -0,0 +1,69 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// GoldenJSON compares got, a JSON document, with the golden file
+// testdata/golden/{name}.json of the test's package. Both are indented the
+// same way, so only content differences fail and golden diffs stay readable
+// in review. Run the tests with -update to write the files instead.
+func GoldenJSON(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, got, "", "  "); err != nil {
+		t.Fatalf("golden %s: invalid JSON: %v\n%s", name, err, got)
+	}
+	indented.WriteByte('\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden %s: %v (run with -update to create it)", name, err)
+	}
+	if !bytes.Equal(indented.Bytes(), want) {
+		t.Errorf("golden %s: request differs from %s (run with -update if the change is intended)\n%s",
+			name, path, lineDiff(string(want), indented.String()))
+	}
+}
+
+// lineDiff lists the lines of want and got that differ, by position, which
+// is enough for the small documents golden files hold
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	var diff strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			diff.WriteString("- " + w + "\n+ " + g + "\n")
+		}
+	}
+	return diff.String()
+}
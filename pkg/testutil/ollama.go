@@ -0,0 +1,322 @@
+package testutil
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// Dimensions of the embeddings returned by default by OllamaServer
+const DefaultEmbeddingDimensions = 8
+
+// OllamaReply scripts one response of OllamaServer to a chat or generate
+// request
+type OllamaReply struct {
+	// Chunks are streamed one per line, and joined when the request
+	// disables streaming
+	Chunks []string
+
+	// ToolCalls are sent with the last chunk (chat only)
+	ToolCalls []api.ToolCall
+
+	// PromptTokens and OutputTokens are reported in the final response;
+	// when zero, they are the number of words of the prompt and output
+	PromptTokens int
+	OutputTokens int
+
+	// DoneReason defaults to "stop"
+	DoneReason string
+
+	// Status and Error make the reply an error response, e.g.
+	// {Status: 404, Error: "model not found"}. With only Error set, the
+	// error is sent in the stream after the chunks, as Ollama does when
+	// generation fails midway.
+	Status int
+	Error  string
+
+	// Delay is waited before each chunk, e.g. to test cancellation
+	Delay time.Duration
+
+	// AlwaysStream streams the reply even to requests disabling streaming,
+	// as some Ollama versions and proxies do
+	AlwaysStream bool
+}
+
+// OllamaText returns a reply with content streamed as a single chunk
+func OllamaText(content string) OllamaReply {
+	return OllamaReply{Chunks: []string{content}}
+}
+
+// OllamaServer is a fake Ollama HTTP server implementing /api/chat,
+// /api/generate, /api/tags and /api/embed, so that Ollama clients can be
+// tested without a running Ollama.
+//
+// Chat and generate requests are answered with the replies queued with
+// ReplyChat and ReplyGenerate, in order; once the queue is empty, the last
+// user message or the prompt is echoed. Embeddings are derived from a hash
+// of the text unless SetEmbedder is called.
+type OllamaServer struct {
+	*httptest.Server
+	requestLog
+
+	chat     replyQueue[OllamaReply]
+	generate replyQueue[OllamaReply]
+
+	mu       sync.Mutex
+	models   []string
+	embedder func(model, text string) []float32
+}
+
+// NewOllamaServer starts a fake Ollama server, closed when the test ends.
+// Its URL is the endpoint to give to clients.
+func NewOllamaServer(t testing.TB) *OllamaServer {
+	t.Helper()
+	s := &OllamaServer{
+		models:   []string{"llama3.1:latest"},
+		embedder: hashEmbedding,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/chat", s.handleChat)
+	mux.HandleFunc("POST /api/generate", s.handleGenerate)
+	mux.HandleFunc("GET /api/tags", s.handleTags)
+	mux.HandleFunc("POST /api/embed", s.handleEmbed)
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "Ollama is running")
+	})
+
+	s.Server = httptest.NewServer(s.record(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// ReplyChat queues replies to the next chat requests
+func (s *OllamaServer) ReplyChat(replies ...OllamaReply) {
+	s.chat.push(replies...)
+}
+
+// ReplyGenerate queues replies to the next generate requests
+func (s *OllamaServer) ReplyGenerate(replies ...OllamaReply) {
+	s.generate.push(replies...)
+}
+
+// SetModels sets the models listed by /api/tags
+func (s *OllamaServer) SetModels(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.models = names
+}
+
+// SetEmbedder sets the function computing the embedding of each input text
+func (s *OllamaServer) SetEmbedder(embedder func(model, text string) []float32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.embedder = embedder
+}
+
+// LastChatRequest decodes the last chat request received, or returns nil
+func (s *OllamaServer) LastChatRequest() *api.ChatRequest {
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Path == "/api/chat" {
+			var req api.ChatRequest
+			if json.Unmarshal(requests[i].Body, &req) == nil {
+				return &req
+			}
+		}
+	}
+	return nil
+}
+
+func (s *OllamaServer) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req api.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOllamaError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prompt := ""
+	for _, msg := range req.Messages {
+		if msg.Role == "user" {
+			prompt = msg.Content
+		}
+	}
+	reply := s.chat.next(OllamaText(prompt))
+
+	s.stream(w, r, reply, prompt, req.Stream, func(chunk string, last bool) interface{} {
+		resp := api.ChatResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+			Message:   api.Message{Role: "assistant", Content: chunk},
+		}
+		if last {
+			resp.Message.ToolCalls = reply.ToolCalls
+		}
+		return &resp
+	}, func(resp interface{}, metrics api.Metrics, doneReason string) {
+		chat := resp.(*api.ChatResponse)
+		chat.Done = true
+		chat.DoneReason = doneReason
+		chat.Metrics = metrics
+	})
+}
+
+func (s *OllamaServer) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req api.GenerateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOllamaError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	reply := s.generate.next(OllamaText(req.Prompt))
+
+	s.stream(w, r, reply, req.Prompt, req.Stream, func(chunk string, last bool) interface{} {
+		return &api.GenerateResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+			Response:  chunk,
+		}
+	}, func(resp interface{}, metrics api.Metrics, doneReason string) {
+		generate := resp.(*api.GenerateResponse)
+		generate.Done = true
+		generate.DoneReason = doneReason
+		generate.Metrics = metrics
+	})
+}
+
+func (s *OllamaServer) handleTags(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	models := append([]string(nil), s.models...)
+	s.mu.Unlock()
+
+	var resp api.ListResponse
+	for _, name := range models {
+		resp.Models = append(resp.Models, api.ListModelResponse{
+			Name:  name,
+			Model: name,
+		})
+	}
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+func (s *OllamaServer) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	var req api.EmbedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOllamaError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var texts []string
+	switch input := req.Input.(type) {
+	case string:
+		texts = []string{input}
+	case []interface{}:
+		for _, text := range input {
+			str, ok := text.(string)
+			if !ok {
+				writeOllamaError(w, http.StatusBadRequest, "invalid input type")
+				return
+			}
+			texts = append(texts, str)
+		}
+	default:
+		writeOllamaError(w, http.StatusBadRequest, "invalid input type")
+		return
+	}
+
+	s.mu.Lock()
+	embedder := s.embedder
+	s.mu.Unlock()
+
+	resp := api.EmbedResponse{Model: req.Model}
+	for _, text := range texts {
+		resp.Embeddings = append(resp.Embeddings, embedder(req.Model, text))
+		resp.PromptEvalCount += len(strings.Fields(text))
+	}
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+// stream writes reply as NDJSON, or as a single object when stream is
+// false. chunkResponse builds the response carrying a chunk and done
+// completes the final one.
+func (s *OllamaServer) stream(w http.ResponseWriter, r *http.Request, reply OllamaReply, prompt string, stream *bool,
+	chunkResponse func(chunk string, last bool) interface{},
+	done func(resp interface{}, metrics api.Metrics, doneReason string)) {
+	if reply.Status != 0 {
+		writeOllamaError(w, reply.Status, reply.Error)
+		return
+	}
+
+	content := strings.Join(reply.Chunks, "")
+	metrics := api.Metrics{
+		PromptEvalCount: reply.PromptTokens,
+		EvalCount:       reply.OutputTokens,
+	}
+	if metrics.PromptEvalCount == 0 {
+		metrics.PromptEvalCount = len(strings.Fields(prompt))
+	}
+	if metrics.EvalCount == 0 {
+		metrics.EvalCount = len(strings.Fields(content))
+	}
+	doneReason := reply.DoneReason
+	if doneReason == "" {
+		doneReason = "stop"
+	}
+
+	if stream != nil && !*stream && !reply.AlwaysStream {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		if reply.Error != "" {
+			writeOllamaError(w, http.StatusInternalServerError, reply.Error)
+			return
+		}
+		resp := chunkResponse(content, true)
+		done(resp, metrics, doneReason)
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	for i, chunk := range reply.Chunks {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		_ = enc.Encode(chunkResponse(chunk, i == len(reply.Chunks)-1 && reply.Error == ""))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if reply.Error != "" {
+		_ = enc.Encode(map[string]string{"error": reply.Error})
+		return
+	}
+
+	final := chunkResponse("", len(reply.Chunks) == 0)
+	done(final, metrics, doneReason)
+	_ = enc.Encode(final)
+}
+
+func writeOllamaError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// hashEmbedding returns a deterministic embedding of text
+func hashEmbedding(model, text string) []float32 {
+	embedding := make([]float32, DefaultEmbeddingDimensions)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(word))
+		embedding[h.Sum32()%DefaultEmbeddingDimensions]++
+	}
+	return embedding
+}
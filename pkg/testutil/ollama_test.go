@@ -0,0 +1,152 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+func newOllamaClient(t *testing.T, srv *OllamaServer) *api.Client {
+	t.Helper()
+	base, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api.NewClient(base, http.DefaultClient)
+}
+
+func TestOllamaServer_ChatStreaming(t *testing.T) {
+	srv := NewOllamaServer(t)
+	client := newOllamaClient(t, srv)
+	toolCall := api.ToolCall{Function: api.ToolCallFunction{
+		Name:      "search",
+		Arguments: api.ToolCallFunctionArguments{"query": "dago"},
+	}}
+	srv.ReplyChat(OllamaReply{Chunks: []string{"Hello", ", World!"}, ToolCalls: []api.ToolCall{toolCall}, PromptTokens: 7})
+
+	var chunks []string
+	var final api.ChatResponse
+	err := client.Chat(context.Background(), &api.ChatRequest{
+		Model:    "llama3.1",
+		Messages: []api.Message{{Role: "user", Content: "Say hello"}},
+	}, func(resp api.ChatResponse) error {
+		chunks = append(chunks, resp.Message.Content)
+		if len(resp.Message.ToolCalls) > 0 {
+			final.Message.ToolCalls = resp.Message.ToolCalls
+		}
+		if resp.Done {
+			final.Done, final.Metrics, final.DoneReason = true, resp.Metrics, resp.DoneReason
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+
+	if strings.Join(chunks, "") != "Hello, World!" || len(chunks) != 3 {
+		t.Errorf("chunks = %q, want two chunks and the final response", chunks)
+	}
+	if !final.Done || final.DoneReason != "stop" || final.PromptEvalCount != 7 || final.EvalCount != 2 {
+		t.Errorf("final = %+v, want done with 7 prompt and 2 output tokens", final)
+	}
+	if len(final.Message.ToolCalls) != 1 || final.Message.ToolCalls[0].Function.Name != "search" {
+		t.Errorf("tool calls = %+v, want search", final.Message.ToolCalls)
+	}
+	if req := srv.LastChatRequest(); req == nil || req.Model != "llama3.1" {
+		t.Errorf("LastChatRequest() = %+v, want the llama3.1 request", req)
+	}
+}
+
+func TestOllamaServer_ChatNotStreamingEchoes(t *testing.T) {
+	srv := NewOllamaServer(t)
+	client := newOllamaClient(t, srv)
+	stream := false
+
+	var responses []api.ChatResponse
+	err := client.Chat(context.Background(), &api.ChatRequest{
+		Model:    "llama3.1",
+		Messages: []api.Message{{Role: "user", Content: "echo this"}},
+		Stream:   &stream,
+	}, func(resp api.ChatResponse) error {
+		responses = append(responses, resp)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if len(responses) != 1 || responses[0].Message.Content != "echo this" || !responses[0].Done {
+		t.Errorf("responses = %+v, want one echoing the prompt", responses)
+	}
+}
+
+func TestOllamaServer_Errors(t *testing.T) {
+	srv := NewOllamaServer(t)
+	client := newOllamaClient(t, srv)
+	srv.ReplyGenerate(
+		OllamaReply{Status: http.StatusNotFound, Error: "model not found"},
+		OllamaReply{Chunks: []string{"partial"}, Error: "out of memory"},
+	)
+	req := &api.GenerateRequest{Model: "missing", Prompt: "hi"}
+	noop := func(api.GenerateResponse) error { return nil }
+
+	if err := client.Generate(context.Background(), req, noop); err == nil || err.Error() != "model not found" {
+		t.Errorf("Generate() error = %v, want model not found", err)
+	}
+	if got := srv.Requests(); len(got) != 1 || got[0].Path != "/api/generate" {
+		t.Errorf("Requests() = %+v, want the generate request", got)
+	}
+
+	if err := client.Generate(context.Background(), req, noop); err == nil || !strings.Contains(err.Error(), "out of memory") {
+		t.Errorf("Generate() error = %v, want the streamed error", err)
+	}
+}
+
+func TestOllamaServer_Cancellation(t *testing.T) {
+	srv := NewOllamaServer(t)
+	client := newOllamaClient(t, srv)
+	srv.ReplyGenerate(OllamaReply{Chunks: []string{"slow"}, Delay: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.Generate(ctx, &api.GenerateRequest{Model: "llama3.1", Prompt: "hi"}, func(api.GenerateResponse) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Generate() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestOllamaServer_TagsAndEmbed(t *testing.T) {
+	srv := NewOllamaServer(t)
+	client := newOllamaClient(t, srv)
+	ctx := context.Background()
+
+	srv.SetModels("llama3.1:latest", "nomic-embed-text:latest")
+	list, err := client.List(ctx)
+	if err != nil || len(list.Models) != 2 || list.Models[1].Name != "nomic-embed-text:latest" {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+
+	resp, err := client.Embed(ctx, &api.EmbedRequest{Model: "nomic-embed-text", Input: []string{"a b", "a b", "c"}})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(resp.Embeddings) != 3 || len(resp.Embeddings[0]) != DefaultEmbeddingDimensions {
+		t.Fatalf("Embed() = %+v, want three embeddings", resp)
+	}
+	for i := range resp.Embeddings[0] {
+		if resp.Embeddings[0][i] != resp.Embeddings[1][i] {
+			t.Fatal("Embed() is not deterministic")
+		}
+	}
+
+	srv.SetEmbedder(func(model, text string) []float32 { return []float32{float32(len(text))} })
+	resp, err = client.Embed(ctx, &api.EmbedRequest{Model: "nomic-embed-text", Input: "abc"})
+	if err != nil || len(resp.Embeddings) != 1 || resp.Embeddings[0][0] != 3 {
+		t.Errorf("Embed() with a custom embedder = %+v, %v", resp, err)
+	}
+}
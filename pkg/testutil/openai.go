@@ -0,0 +1,282 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// OpenAIServer is a fake OpenAI Chat Completions API
+// (POST /v1/chat/completions), with SSE streaming and tool calls, so that
+// OpenAI and OpenAI-compatible clients can be tested without an API key.
+//
+// Requests are answered with the replies queued with Reply, in order; once
+// the queue is empty, the last user message is echoed. Requests without a
+// bearer token are rejected like the API does. Thinking is sent as the
+// reasoning_content of DeepSeek, which OpenAI clients ignore.
+type OpenAIServer struct {
+	*httptest.Server
+	requestLog
+
+	replies replyQueue[Reply]
+
+	mu    sync.Mutex
+	count int
+}
+
+// NewOpenAIServer starts a fake OpenAI API, closed when the test ends.
+// BaseURL is the base URL to give to clients.
+func NewOpenAIServer(t testing.TB) *OpenAIServer {
+	t.Helper()
+	s := &OpenAIServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", s.handleChatCompletions)
+
+	s.Server = httptest.NewServer(s.record(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// BaseURL returns the server's URL with the /v1 path clients expect
+func (s *OpenAIServer) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// Reply queues replies to the next requests
+func (s *OpenAIServer) Reply(replies ...Reply) {
+	s.replies.push(replies...)
+}
+
+// openAIRequest is the part of a Chat Completions request the server reads
+type openAIRequest struct {
+	Model         string `json:"model"`
+	Stream        bool   `json:"stream"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options"`
+	Messages []struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"messages"`
+}
+
+// prompt returns the text of the last user message
+func (r *openAIRequest) prompt() string {
+	prompt := ""
+	for _, msg := range r.Messages {
+		if msg.Role != "user" {
+			continue
+		}
+		var text string
+		if json.Unmarshal(msg.Content, &text) == nil {
+			prompt = text
+			continue
+		}
+		var parts []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if json.Unmarshal(msg.Content, &parts) == nil {
+			for _, part := range parts {
+				if part.Type == "text" {
+					prompt = part.Text
+				}
+			}
+		}
+	}
+	return prompt
+}
+
+func (s *OpenAIServer) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeOpenAIError(w, http.StatusUnauthorized, "invalid_request_error", "You didn't provide an API key.")
+		return
+	}
+
+	var req openAIRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	prompt := req.prompt()
+	reply := s.replies.next(TextReply(prompt))
+	reply.setHeader(w)
+
+	if reply.Status != 0 {
+		writeOpenAIError(w, reply.Status, reply.ErrorType, reply.Error)
+		return
+	}
+
+	s.mu.Lock()
+	id := fmt.Sprintf("chatcmpl-test-%d", s.count)
+	s.count++
+	s.mu.Unlock()
+
+	created := time.Now().Unix()
+	input, output := reply.usage(prompt)
+	usage := map[string]int{
+		"prompt_tokens":     input,
+		"completion_tokens": output,
+		"total_tokens":      input + output,
+	}
+	finishReason := reply.StopReason
+	if finishReason == "" {
+		finishReason = "stop"
+		if len(reply.ToolCalls) > 0 {
+			finishReason = "tool_calls"
+		}
+	}
+
+	if !req.Stream {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		if reply.Error != "" {
+			writeOpenAIError(w, http.StatusInternalServerError, reply.ErrorType, reply.Error)
+			return
+		}
+
+		message := map[string]interface{}{"role": "assistant", "content": nil}
+		if len(reply.Thinking) > 0 {
+			message["reasoning_content"] = strings.Join(reply.Thinking, "")
+		}
+		if text := reply.content(); text != "" || len(reply.ToolCalls) == 0 {
+			message["content"] = text
+		}
+		if len(reply.ToolCalls) > 0 {
+			var calls []interface{}
+			for i, call := range reply.ToolCalls {
+				arguments := call.Arguments
+				if arguments == "" {
+					arguments = "{}"
+				}
+				calls = append(calls, map[string]interface{}{
+					"id":       openAIToolCallID(i, call),
+					"type":     "function",
+					"function": map[string]string{"name": call.Name, "arguments": arguments},
+				})
+			}
+			message["tool_calls"] = calls
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion",
+			"created": created,
+			"model":   req.Model,
+			"choices": []interface{}{map[string]interface{}{
+				"index":         0,
+				"message":       message,
+				"finish_reason": finishReason,
+			}},
+			"usage": usage,
+		})
+		return
+	}
+
+	sse := newSSEWriter(w)
+	chunk := func(delta map[string]interface{}, finishReason interface{}) {
+		sse.event("", map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"model":   req.Model,
+			"choices": []interface{}{map[string]interface{}{
+				"index":         0,
+				"delta":         delta,
+				"finish_reason": finishReason,
+			}},
+		})
+	}
+
+	chunk(map[string]interface{}{"role": "assistant", "content": ""}, nil)
+	for _, text := range reply.Thinking {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		chunk(map[string]interface{}{"reasoning_content": text}, nil)
+	}
+	for _, text := range reply.Chunks {
+		if !wait(r, reply.Delay) {
+			return
+		}
+		chunk(map[string]interface{}{"content": text}, nil)
+	}
+
+	if reply.Error != "" {
+		errorType := reply.ErrorType
+		if errorType == "" {
+			errorType = "server_error"
+		}
+		sse.event("", map[string]interface{}{
+			"error": map[string]interface{}{"message": reply.Error, "type": errorType, "param": nil, "code": nil},
+		})
+		return
+	}
+
+	for i, call := range reply.ToolCalls {
+		for j, part := range splitArguments(call.Arguments) {
+			toolCall := map[string]interface{}{
+				"index":    i,
+				"function": map[string]string{"arguments": part},
+			}
+			if j == 0 {
+				toolCall["id"] = openAIToolCallID(i, call)
+				toolCall["type"] = "function"
+				toolCall["function"] = map[string]string{"name": call.Name, "arguments": part}
+			}
+			chunk(map[string]interface{}{"tool_calls": []interface{}{toolCall}}, nil)
+		}
+	}
+
+	chunk(map[string]interface{}{}, finishReason)
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		sse.event("", map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"model":   req.Model,
+			"choices": []interface{}{},
+			"usage":   usage,
+		})
+	}
+	sse.event("", "[DONE]")
+}
+
+func openAIToolCallID(i int, call ToolCall) string {
+	if call.ID != "" {
+		return call.ID
+	}
+	return fmt.Sprintf("call_test_%d", i)
+}
+
+func writeOpenAIError(w http.ResponseWriter, status int, errorType, message string) {
+	if errorType == "" {
+		errorType = openAIErrorType(status)
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": message,
+			"type":    errorType,
+			"param":   nil,
+			"code":    nil,
+		},
+	})
+}
+
+// openAIErrorType returns the error type the API uses for status
+func openAIErrorType(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_exceeded"
+	case status >= http.StatusInternalServerError:
+		return "server_error"
+	default:
+		return "invalid_request_error"
+	}
+}
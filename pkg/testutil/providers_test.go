@@ -0,0 +1,626 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
+	anthropicoption "github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/google/generative-ai-go/genai"
+	openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/api/option"
+)
+
+var searchCall = ToolCall{Name: "search", Arguments: `{"query":"dago"}`}
+
+func newAnthropicClient(srv *AnthropicServer, apiKey string) anthropicsdk.Client {
+	return anthropicsdk.NewClient(
+		anthropicoption.WithAPIKey(apiKey),
+		anthropicoption.WithBaseURL(srv.URL),
+		anthropicoption.WithMaxRetries(0),
+	)
+}
+
+func anthropicParams(prompt string) anthropicsdk.MessageNewParams {
+	return anthropicsdk.MessageNewParams{
+		Model:     "claude-sonnet-4-20250514",
+		MaxTokens: 100,
+		Messages:  []anthropicsdk.MessageParam{anthropicsdk.NewUserMessage(anthropicsdk.NewTextBlock(prompt))},
+	}
+}
+
+func TestAnthropicServer(t *testing.T) {
+	ctx := context.Background()
+	srv := NewAnthropicServer(t)
+	client := newAnthropicClient(srv, "test-key")
+
+	msg, err := client.Messages.New(ctx, anthropicParams("echo me"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if len(msg.Content) != 1 || msg.Content[0].Text != "echo me" || msg.StopReason != "end_turn" {
+		t.Errorf("New() = %+v, want the prompt echoed", msg)
+	}
+	if msg.Usage.InputTokens != 2 || msg.Usage.OutputTokens != 2 {
+		t.Errorf("Usage = %+v, want 2 and 2 tokens", msg.Usage)
+	}
+
+	req, _ := srv.LastRequest()
+	var body map[string]interface{}
+	if err := req.JSON(&body); err != nil || body["model"] != "claude-sonnet-4-20250514" || body["max_tokens"] != float64(100) {
+		t.Errorf("request body = %s, %v", req.Body, err)
+	}
+	if req.Header.Get("x-api-key") != "test-key" || req.Header.Get("anthropic-version") == "" {
+		t.Errorf("request headers = %v", req.Header)
+	}
+}
+
+func TestAnthropicServer_Streaming(t *testing.T) {
+	srv := NewAnthropicServer(t)
+	client := newAnthropicClient(srv, "test-key")
+	srv.Reply(Reply{Chunks: []string{"Let me ", "search."}, ToolCalls: []ToolCall{searchCall}, InputTokens: 9})
+
+	stream := client.Messages.NewStreaming(context.Background(), anthropicParams("find dago"))
+	var msg anthropicsdk.Message
+	var deltas []string
+	for stream.Next() {
+		event := stream.Current()
+		if err := msg.Accumulate(event); err != nil {
+			t.Fatalf("Accumulate() error = %v", err)
+		}
+		if delta, ok := event.AsAny().(anthropicsdk.ContentBlockDeltaEvent); ok && delta.Delta.Type == "text_delta" {
+			deltas = append(deltas, delta.Delta.Text)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+
+	if strings.Join(deltas, "|") != "Let me |search." {
+		t.Errorf("deltas = %q", deltas)
+	}
+	if len(msg.Content) != 2 || msg.Content[0].Text != "Let me search." {
+		t.Fatalf("content = %+v, want text and tool use", msg.Content)
+	}
+	if tool := msg.Content[1]; tool.Type != "tool_use" || tool.Name != "search" || string(tool.Input) != `{"query":"dago"}` {
+		t.Errorf("tool use = %+v", tool)
+	}
+	if msg.StopReason != "tool_use" || msg.Usage.InputTokens != 9 || msg.Usage.OutputTokens != 3 {
+		t.Errorf("message = stop %s, usage %+v", msg.StopReason, msg.Usage)
+	}
+}
+
+func TestAnthropicServer_Errors(t *testing.T) {
+	ctx := context.Background()
+	srv := NewAnthropicServer(t)
+
+	unauthenticated := newAnthropicClient(srv, "")
+	_, err := unauthenticated.Messages.New(ctx, anthropicParams("hi"))
+	var apiErr *anthropicsdk.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Errorf("New() without API key error = %v, want 401", err)
+	}
+
+	client := newAnthropicClient(srv, "test-key")
+	srv.Reply(Reply{Status: 429, Error: "slow down"})
+	_, err = client.Messages.New(ctx, anthropicParams("hi"))
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 429 || !strings.Contains(apiErr.RawJSON(), "rate_limit_error") {
+		t.Errorf("New() error = %v, want a 429 rate limit error", err)
+	}
+
+	srv.Reply(Reply{Chunks: []string{"par"}, Error: "Overloaded"})
+	stream := client.Messages.NewStreaming(ctx, anthropicParams("hi"))
+	for stream.Next() {
+	}
+	if err := stream.Err(); err == nil || !strings.Contains(err.Error(), "Overloaded") {
+		t.Errorf("stream error = %v, want the overloaded error", err)
+	}
+}
+
+func newOpenAIClient(srv *OpenAIServer) *openai.Client {
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = srv.BaseURL()
+	return openai.NewClientWithConfig(config)
+}
+
+func TestOpenAIServer(t *testing.T) {
+	ctx := context.Background()
+	srv := NewOpenAIServer(t)
+	client := newOpenAIClient(srv)
+	srv.Reply(Reply{ToolCalls: []ToolCall{searchCall}})
+
+	resp, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "find dago"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	choice := resp.Choices[0]
+	if choice.FinishReason != openai.FinishReasonToolCalls || len(choice.Message.ToolCalls) != 1 {
+		t.Fatalf("choice = %+v, want a tool call", choice)
+	}
+	if call := choice.Message.ToolCalls[0]; call.Function.Name != "search" || call.Function.Arguments != `{"query":"dago"}` || call.ID == "" {
+		t.Errorf("tool call = %+v", call)
+	}
+	if resp.Usage.PromptTokens != 2 || resp.Usage.TotalTokens != 2 {
+		t.Errorf("Usage = %+v, want 2 prompt tokens", resp.Usage)
+	}
+
+	req, _ := srv.LastRequest()
+	if req.Path != "/v1/chat/completions" || req.Header.Get("Authorization") != "Bearer test-key" {
+		t.Errorf("request = %s %v", req.Path, req.Header)
+	}
+}
+
+func TestOpenAIServer_Streaming(t *testing.T) {
+	srv := NewOpenAIServer(t)
+	client := newOpenAIClient(srv)
+	srv.Reply(Reply{Chunks: []string{"Hello", ", World!"}, ToolCalls: []ToolCall{searchCall}, InputTokens: 5})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:         "gpt-4o",
+		Messages:      []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+		Stream:        true,
+		StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream() error = %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	var content, arguments strings.Builder
+	var finish openai.FinishReason
+	var usage *openai.Usage
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		if resp.Usage != nil {
+			usage = resp.Usage
+		}
+		for _, choice := range resp.Choices {
+			content.WriteString(choice.Delta.Content)
+			for _, call := range choice.Delta.ToolCalls {
+				arguments.WriteString(call.Function.Arguments)
+			}
+			if choice.FinishReason != "" {
+				finish = choice.FinishReason
+			}
+		}
+	}
+
+	if content.String() != "Hello, World!" || arguments.String() != `{"query":"dago"}` {
+		t.Errorf("content = %q, arguments = %q", content.String(), arguments.String())
+	}
+	if finish != openai.FinishReasonToolCalls || usage == nil || usage.PromptTokens != 5 || usage.CompletionTokens != 2 {
+		t.Errorf("finish = %s, usage = %+v", finish, usage)
+	}
+}
+
+func TestOpenAIServer_Errors(t *testing.T) {
+	srv := NewOpenAIServer(t)
+	client := newOpenAIClient(srv)
+	srv.Reply(Reply{Status: 429, Error: "Rate limit reached"})
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+	})
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != 429 || apiErr.Type != "rate_limit_exceeded" {
+		t.Errorf("CreateChatCompletion() error = %v, want a 429 rate limit error", err)
+	}
+}
+
+func newGeminiModel(t *testing.T, srv *GeminiServer) *genai.GenerativeModel {
+	t.Helper()
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey("test-key"), option.WithEndpoint(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client.GenerativeModel("gemini-2.0-flash")
+}
+
+func TestGeminiServer(t *testing.T) {
+	srv := NewGeminiServer(t)
+	model := newGeminiModel(t, srv)
+
+	resp, err := model.GenerateContent(context.Background(), genai.Text("echo me"))
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	parts := resp.Candidates[0].Content.Parts
+	if len(parts) != 1 || parts[0] != genai.Text("echo me") || resp.Candidates[0].FinishReason != genai.FinishReasonStop {
+		t.Errorf("candidate = %+v, want the prompt echoed", resp.Candidates[0])
+	}
+
+	req, _ := srv.LastRequest()
+	var body struct {
+		Contents []struct {
+			Role  string            `json:"role"`
+			Parts []json.RawMessage `json:"parts"`
+		} `json:"contents"`
+	}
+	if err := req.JSON(&body); err != nil || len(body.Contents) != 1 || body.Contents[0].Role != "user" {
+		t.Errorf("request body = %s, %v", req.Body, err)
+	}
+	if req.Path != "/v1beta/models/gemini-2.0-flash:generateContent" {
+		t.Errorf("request path = %s", req.Path)
+	}
+}
+
+func TestGeminiServer_FunctionCalls(t *testing.T) {
+	srv := NewGeminiServer(t)
+	srv.Reply(Reply{Chunks: []string{"Searching"}, ToolCalls: []ToolCall{searchCall}, InputTokens: 4})
+
+	model := newGeminiModel(t, srv)
+
+	resp, err := model.GenerateContent(context.Background(), genai.Text("find dago"))
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	parts := resp.Candidates[0].Content.Parts
+	if len(parts) != 2 || parts[0] != genai.Text("Searching") {
+		t.Fatalf("parts = %+v, want text and a function call", parts)
+	}
+	if call, ok := parts[1].(genai.FunctionCall); !ok || call.Name != "search" || call.Args["query"] != "dago" {
+		t.Errorf("function call = %+v", parts[1])
+	}
+	if resp.Candidates[0].FinishReason != genai.FinishReasonStop || resp.UsageMetadata.PromptTokenCount != 4 || resp.UsageMetadata.CandidatesTokenCount != 1 {
+		t.Errorf("finish = %s, usage = %+v", resp.Candidates[0].FinishReason, resp.UsageMetadata)
+	}
+}
+
+func TestGeminiServer_Streaming(t *testing.T) {
+	srv := NewGeminiServer(t)
+	srv.Reply(Reply{Chunks: []string{"Hello", ", World!"}})
+
+	// Without alt=sse, the stream is a JSON array of responses
+	body := `{"contents":[{"parts":[{"text":"hi"}]}]}`
+	resp, err := http.Post(srv.URL+"/v1/models/gemini-2.0-flash:streamGenerateContent?key=test-key", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var responses []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
+		t.Fatalf("stream is not a JSON array: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("responses = %v, want two", responses)
+	}
+	if _, ok := responses[0]["usageMetadata"]; ok {
+		t.Error("first response has usage, want it in the last one only")
+	}
+	if _, ok := responses[1]["usageMetadata"]; !ok {
+		t.Error("last response has no usage")
+	}
+}
+
+func TestGeminiServer_SSE(t *testing.T) {
+	srv := NewGeminiServer(t)
+	srv.Reply(Reply{Chunks: []string{"Hello", ", World!"}})
+
+	body := `{"contents":[{"parts":[{"text":"hi"}]}]}`
+	resp, err := http.Post(srv.URL+"/v1beta/models/gemini-2.0-flash:streamGenerateContent?alt=sse&key=test-key", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, _ := io.ReadAll(resp.Body)
+	events := strings.Split(strings.TrimSpace(string(data)), "\n\n")
+	if resp.Header.Get("Content-Type") != "text/event-stream" || len(events) != 2 {
+		t.Fatalf("stream = %s, want two events", data)
+	}
+	if !strings.Contains(events[1], `"finishReason":"STOP"`) || !strings.Contains(events[1], `"totalTokenCount":3`) {
+		t.Errorf("last event = %s, want the finish reason and usage", events[1])
+	}
+}
+
+func TestGeminiServer_Errors(t *testing.T) {
+	srv := NewGeminiServer(t)
+	model := newGeminiModel(t, srv)
+	srv.Reply(Reply{Status: 429, Error: "Resource has been exhausted"})
+
+	_, err := model.GenerateContent(context.Background(), genai.Text("hi"))
+	if err == nil || !strings.Contains(err.Error(), "Resource has been exhausted") {
+		t.Errorf("GenerateContent() error = %v, want the quota error", err)
+	}
+}
+
+func newBedrockClient(srv *BedrockServer) *bedrockruntime.Client {
+	return bedrockruntime.New(bedrockruntime.Options{
+		Region:           "us-east-1",
+		Credentials:      credentials.NewStaticCredentialsProvider("AKIDTEST", "test-secret", ""),
+		BaseEndpoint:     aws.String(srv.URL),
+		RetryMaxAttempts: 1,
+	})
+}
+
+func bedrockInput(prompt string) *bedrockruntime.ConverseInput {
+	return &bedrockruntime.ConverseInput{
+		ModelId: aws.String("anthropic.claude-3-5-sonnet-20240620-v1:0"),
+		Messages: []bedrocktypes.Message{{
+			Role:    bedrocktypes.ConversationRoleUser,
+			Content: []bedrocktypes.ContentBlock{&bedrocktypes.ContentBlockMemberText{Value: prompt}},
+		}},
+	}
+}
+
+func TestBedrockServer(t *testing.T) {
+	srv := NewBedrockServer(t)
+	client := newBedrockClient(srv)
+
+	out, err := client.Converse(context.Background(), bedrockInput("echo me"))
+	if err != nil {
+		t.Fatalf("Converse() error = %v", err)
+	}
+	msg, ok := out.Output.(*bedrocktypes.ConverseOutputMemberMessage)
+	if !ok || len(msg.Value.Content) != 1 {
+		t.Fatalf("Output = %+v, want a message", out.Output)
+	}
+	if text, ok := msg.Value.Content[0].(*bedrocktypes.ContentBlockMemberText); !ok || text.Value != "echo me" || out.StopReason != "end_turn" {
+		t.Errorf("Converse() = %+v, want the prompt echoed", msg.Value.Content[0])
+	}
+	if aws.ToInt32(out.Usage.InputTokens) != 2 || aws.ToInt32(out.Usage.OutputTokens) != 2 {
+		t.Errorf("Usage = %+v, want 2 and 2 tokens", out.Usage)
+	}
+
+	srv.Reply(Reply{ToolCalls: []ToolCall{searchCall}})
+	out, err = client.Converse(context.Background(), bedrockInput("find dago"))
+	if err != nil {
+		t.Fatalf("Converse() error = %v", err)
+	}
+	msg, _ = out.Output.(*bedrocktypes.ConverseOutputMemberMessage)
+	use, ok := msg.Value.Content[0].(*bedrocktypes.ContentBlockMemberToolUse)
+	if !ok || aws.ToString(use.Value.Name) != "search" || out.StopReason != "tool_use" {
+		t.Fatalf("Converse() = %+v, want the search tool use", msg.Value.Content)
+	}
+	var input map[string]interface{}
+	if err := use.Value.Input.UnmarshalSmithyDocument(&input); err != nil || input["query"] != "dago" {
+		t.Errorf("tool input = %v, %v", input, err)
+	}
+
+	req, _ := srv.LastRequest()
+	if req.Path != "/model/anthropic.claude-3-5-sonnet-20240620-v1:0/converse" || !strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		t.Errorf("request = %s %v", req.Path, req.Header)
+	}
+}
+
+func TestBedrockServer_Streaming(t *testing.T) {
+	srv := NewBedrockServer(t)
+	client := newBedrockClient(srv)
+	srv.Reply(Reply{Chunks: []string{"Let me ", "search."}, ToolCalls: []ToolCall{searchCall}, InputTokens: 9})
+
+	out, err := client.ConverseStream(context.Background(), bedrockStreamInput("find dago"))
+	if err != nil {
+		t.Fatalf("ConverseStream() error = %v", err)
+	}
+	stream := out.GetStream()
+	defer stream.Close()
+
+	var deltas []string
+	var input, stopReason string
+	var usage *bedrocktypes.TokenUsage
+	for event := range stream.Events() {
+		switch event := event.(type) {
+		case *bedrocktypes.ConverseStreamOutputMemberContentBlockDelta:
+			switch delta := event.Value.Delta.(type) {
+			case *bedrocktypes.ContentBlockDeltaMemberText:
+				deltas = append(deltas, delta.Value)
+			case *bedrocktypes.ContentBlockDeltaMemberToolUse:
+				input += aws.ToString(delta.Value.Input)
+			}
+		case *bedrocktypes.ConverseStreamOutputMemberMessageStop:
+			stopReason = string(event.Value.StopReason)
+		case *bedrocktypes.ConverseStreamOutputMemberMetadata:
+			usage = event.Value.Usage
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+
+	if strings.Join(deltas, "|") != "Let me |search." {
+		t.Errorf("deltas = %q", deltas)
+	}
+	if input != `{"query":"dago"}` {
+		t.Errorf("tool input = %s", input)
+	}
+	if stopReason != "tool_use" || usage == nil || aws.ToInt32(usage.InputTokens) != 9 || aws.ToInt32(usage.OutputTokens) != 3 {
+		t.Errorf("stream = stop %s, usage %+v", stopReason, usage)
+	}
+}
+
+func TestBedrockServer_Errors(t *testing.T) {
+	ctx := context.Background()
+	srv := NewBedrockServer(t)
+
+	resp, err := http.Post(srv.URL+"/model/titan/converse", "application/json", strings.NewReader(`{"messages":[]}`))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("X-Amzn-Errortype") != "MissingAuthenticationTokenException" {
+		t.Errorf("unsigned request = %d %v, want 403 MissingAuthenticationTokenException", resp.StatusCode, resp.Header)
+	}
+
+	client := newBedrockClient(srv)
+	srv.Reply(Reply{Status: 429, Error: "slow down"})
+	_, err = client.Converse(ctx, bedrockInput("hi"))
+	var throttling *bedrocktypes.ThrottlingException
+	if !errors.As(err, &throttling) || throttling.ErrorMessage() != "slow down" {
+		t.Errorf("Converse() error = %v, want a throttling exception", err)
+	}
+
+	srv.Reply(Reply{Chunks: []string{"par"}, Error: "Overloaded"})
+	out, err := client.ConverseStream(ctx, bedrockStreamInput("hi"))
+	if err != nil {
+		t.Fatalf("ConverseStream() error = %v", err)
+	}
+	stream := out.GetStream()
+	defer stream.Close()
+	for range stream.Events() {
+	}
+	var streamErr *bedrocktypes.ModelStreamErrorException
+	if err := stream.Err(); !errors.As(err, &streamErr) || !strings.Contains(err.Error(), "Overloaded") {
+		t.Errorf("stream error = %v, want the model stream error", err)
+	}
+}
+
+func bedrockStreamInput(prompt string) *bedrockruntime.ConverseStreamInput {
+	input := bedrockInput(prompt)
+	return &bedrockruntime.ConverseStreamInput{ModelId: input.ModelId, Messages: input.Messages}
+}
+
+// postCohere posts a chat request to srv, returning the response body
+func postCohere(t *testing.T, srv *CohereServer, body string) (*http.Response, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, srv.BaseURL()+"/chat", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+func TestCohereServer(t *testing.T) {
+	srv := NewCohereServer(t)
+
+	var chat struct {
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason"`
+		ToolCalls    []struct {
+			Name       string                 `json:"name"`
+			Parameters map[string]interface{} `json:"parameters"`
+		} `json:"tool_calls"`
+		Meta struct {
+			BilledUnits struct {
+				InputTokens  int `json:"input_tokens"`
+				OutputTokens int `json:"output_tokens"`
+			} `json:"billed_units"`
+		} `json:"meta"`
+	}
+	_, body := postCohere(t, srv, `{"model":"command-r-plus","message":"echo me"}`)
+	if err := json.Unmarshal([]byte(body), &chat); err != nil {
+		t.Fatalf("response = %s: %v", body, err)
+	}
+	if chat.Text != "echo me" || chat.FinishReason != "COMPLETE" || chat.Meta.BilledUnits.InputTokens != 2 || chat.Meta.BilledUnits.OutputTokens != 2 {
+		t.Errorf("chat = %+v, want the message echoed", chat)
+	}
+
+	// Requests answering tool calls have no message
+	srv.Reply(Reply{ToolCalls: []ToolCall{searchCall}})
+	_, body = postCohere(t, srv, `{"message":"","chat_history":[{"role":"USER","message":"find dago"}],"tool_results":[]}`)
+	chat.ToolCalls = nil
+	if err := json.Unmarshal([]byte(body), &chat); err != nil {
+		t.Fatalf("response = %s: %v", body, err)
+	}
+	if len(chat.ToolCalls) != 1 || chat.ToolCalls[0].Name != "search" || chat.ToolCalls[0].Parameters["query"] != "dago" {
+		t.Errorf("tool calls = %+v, want the search call", chat.ToolCalls)
+	}
+	if chat.Meta.BilledUnits.InputTokens != 2 {
+		t.Errorf("input tokens = %d, want those of the last user message", chat.Meta.BilledUnits.InputTokens)
+	}
+}
+
+func TestCohereServer_Streaming(t *testing.T) {
+	srv := NewCohereServer(t)
+	srv.Reply(Reply{Chunks: []string{"Let me ", "search."}, ToolCalls: []ToolCall{searchCall}, InputTokens: 9})
+
+	resp, body := postCohere(t, srv, `{"message":"find dago","stream":true}`)
+	if resp.Header.Get("Content-Type") != "application/stream+json" {
+		t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+
+	var types, deltas []string
+	var parameters string
+	var end struct {
+		FinishReason string `json:"finish_reason"`
+		Response     struct {
+			Meta struct {
+				BilledUnits struct {
+					InputTokens int `json:"input_tokens"`
+				} `json:"billed_units"`
+			} `json:"meta"`
+		} `json:"response"`
+	}
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		var event struct {
+			EventType     string `json:"event_type"`
+			Text          string `json:"text"`
+			ToolCallDelta struct {
+				Parameters string `json:"parameters"`
+			} `json:"tool_call_delta"`
+		}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("event %q: %v", line, err)
+		}
+		types = append(types, event.EventType)
+		switch event.EventType {
+		case "text-generation":
+			deltas = append(deltas, event.Text)
+		case "tool-calls-chunk":
+			parameters += event.ToolCallDelta.Parameters
+		case "stream-end":
+			_ = json.Unmarshal([]byte(line), &end)
+		}
+	}
+
+	want := "stream-start text-generation text-generation tool-calls-chunk tool-calls-chunk tool-calls-chunk tool-calls-generation stream-end"
+	if strings.Join(types, " ") != want {
+		t.Errorf("events = %v, want %s", types, want)
+	}
+	if strings.Join(deltas, "|") != "Let me |search." || parameters != `{"query":"dago"}` {
+		t.Errorf("deltas = %q, parameters = %s", deltas, parameters)
+	}
+	if end.FinishReason != "COMPLETE" || end.Response.Meta.BilledUnits.InputTokens != 9 {
+		t.Errorf("stream-end = %+v", end)
+	}
+}
+
+func TestCohereServer_Errors(t *testing.T) {
+	srv := NewCohereServer(t)
+
+	resp, err := http.Post(srv.BaseURL()+"/chat", "application/json", strings.NewReader(`{"message":"hi"}`))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("request without a key = %d, want 401", resp.StatusCode)
+	}
+
+	srv.Reply(Reply{Status: 429, Error: "too many requests"})
+	resp, body := postCohere(t, srv, `{"message":"hi"}`)
+	if resp.StatusCode != 429 || !strings.Contains(body, `"message":"too many requests"`) {
+		t.Errorf("error response = %d %s", resp.StatusCode, body)
+	}
+
+	srv.Reply(Reply{Chunks: []string{"par"}, Error: "Overloaded"})
+	_, body = postCohere(t, srv, `{"message":"hi","stream":true}`)
+	if !strings.Contains(body, `"finish_reason":"ERROR"`) {
+		t.Errorf("stream = %s, want an ERROR finish", body)
+	}
+}
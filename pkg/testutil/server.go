@@ -0,0 +1,234 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request is a request received by a fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the request body into v
+func (r Request) JSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// ToolCall is a tool call made by the model in a Reply
+type ToolCall struct {
+	// ID defaults to a provider-style ID, e.g. "toolu_test_0"
+	ID   string
+	Name string
+
+	// Arguments is a JSON object, e.g. {"query": "dago"}
+	Arguments string
+}
+
+// Reply scripts one response of AnthropicServer, OpenAIServer or
+// GeminiServer
+type Reply struct {
+	// Chunks are streamed one per event, and joined when the request isn't
+	// streamed
+	Chunks []string
+
+	// Thinking chunks are sent before the text: as a thinking block by
+	// AnthropicServer, its extended thinking, and as reasoning_content by
+	// OpenAIServer, as DeepSeek's reasoner does; other servers ignore them
+	Thinking []string
+
+	// ToolCalls are sent after the text, with their arguments streamed in
+	// two parts
+	ToolCalls []ToolCall
+
+	// InputTokens and OutputTokens are reported as usage; when zero, they
+	// are the number of words of the prompt and output
+	InputTokens  int
+	OutputTokens int
+
+	// StopReason overrides the provider's stop reason, e.g. "max_tokens"
+	// (Anthropic), "length" (OpenAI) or "MAX_TOKENS" (Gemini)
+	StopReason string
+
+	// Status, ErrorType and Error make the reply an error response in the
+	// provider's format, e.g. {Status: 429, ErrorType: "rate_limit_error"}.
+	// With Status unset and Error set, the error is sent in the stream
+	// after the chunks, as providers do when generation fails midway.
+	Status    int
+	ErrorType string
+	Error     string
+
+	// Delay is waited before each chunk, e.g. to test cancellation
+	Delay time.Duration
+
+	// Header is added to the response, e.g. the rate limit headers of a
+	// provider
+	Header http.Header
+}
+
+// TextReply returns a reply with content streamed as a single chunk
+func TextReply(content string) Reply {
+	return Reply{Chunks: []string{content}}
+}
+
+// content returns the reply's full text
+func (r Reply) content() string {
+	return strings.Join(r.Chunks, "")
+}
+
+// setHeader adds the reply's headers to the response
+func (r Reply) setHeader(w http.ResponseWriter) {
+	for name, values := range r.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+}
+
+// usage returns the reply's token counts, counting words when unset
+func (r Reply) usage(prompt string) (input, output int) {
+	input, output = r.InputTokens, r.OutputTokens
+	if input == 0 {
+		input = len(strings.Fields(prompt))
+	}
+	if output == 0 {
+		output = len(strings.Fields(r.content()))
+	}
+	return input, output
+}
+
+// splitArguments splits a tool call's arguments into the two parts streamed
+func splitArguments(arguments string) []string {
+	if arguments == "" {
+		arguments = "{}"
+	}
+	half := len(arguments) / 2
+	return []string{arguments[:half], arguments[half:]}
+}
+
+// requestLog keeps the requests received by a fake server
+type requestLog struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+// record keeps a copy of every request before serving it
+func (l *requestLog) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		l.mu.Lock()
+		l.requests = append(l.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		l.mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Requests returns the requests received so far
+func (l *requestLog) Requests() []Request {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Request(nil), l.requests...)
+}
+
+// LastRequest returns the last request received, or false if there was none
+func (l *requestLog) LastRequest() (Request, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.requests) == 0 {
+		return Request{}, false
+	}
+	return l.requests[len(l.requests)-1], true
+}
+
+// replyQueue is a queue of scripted replies
+type replyQueue[R any] struct {
+	mu      sync.Mutex
+	replies []R
+}
+
+func (q *replyQueue[R]) push(replies ...R) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.replies = append(q.replies, replies...)
+}
+
+// next pops the next reply, or returns fallback if the queue is empty
+func (q *replyQueue[R]) next(fallback R) R {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.replies) == 0 {
+		return fallback
+	}
+	reply := q.replies[0]
+	q.replies = q.replies[1:]
+	return reply
+}
+
+// sseWriter writes server-sent events
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	return &sseWriter{w: w, flusher: flusher}
+}
+
+// event writes an event with data encoded as JSON, or as is if it is a
+// string; an empty name omits the event line
+func (s *sseWriter) event(name string, data interface{}) {
+	var payload []byte
+	if text, ok := data.(string); ok {
+		payload = []byte(text)
+	} else {
+		payload, _ = json.Marshal(data)
+	}
+	if name != "" {
+		_, _ = fmt.Fprintf(s.w, "event: %s\n", name)
+	}
+	_, _ = fmt.Fprintf(s.w, "data: %s\n\n", payload)
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+// wait waits for delay, returning false if the request was cancelled
+func wait(r *http.Request, delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+	select {
+	case <-time.After(delay):
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
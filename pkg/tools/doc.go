@@ -0,0 +1,26 @@
+// Package tools provides adapters for the ports.ToolExecutor interface
+// (pkg/ports in this repository), which runs the tool calls a model returns
+// from CompleteWithTools. Executors list their tools with Tools, to pass to
+// CompleteWithTools, and turn each call into a ports.ToolResult to send back.
+//
+// Available implementations:
+//   - rest: REST endpoints, configured directly or from an OpenAPI 3 spec
+//   - Funcs: Go functions of the application
+//
+// AddFunc adds a function taking its arguments as a struct, whose JSON
+// schema is the tool's parameters; calls with invalid arguments aren't run:
+//
+//	funcs := tools.NewFuncs()
+//	err := tools.AddFunc(funcs, "get_weather", "Get the weather of a city",
+//		func(ctx context.Context, args struct {
+//			City string `json:"city" description:"City name"`
+//		}) (string, error) {
+//			return weather.Current(ctx, args.City)
+//		})
+//	result, err := agent.RunToolLoop(ctx, client, funcs, req, agent.ToolLoopOptions{})
+//
+// Registry combines executors into one, for agents using tools of several
+// kinds:
+//
+//	registry, err := tools.NewRegistry(restExecutor, funcs, coderun.NewToolExecutor(runner, limits, logger))
+package tools
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-adapters/pkg/schema"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Func runs a tool call with its arguments, returning the content of the
+// result. Errors are sent back to the model as error results.
+type Func func(ctx context.Context, args map[string]interface{}) (string, error)
+
+// Funcs is a ports.ToolExecutor of Go functions, so tools implemented in
+// the application can be run by agent.RunToolLoop, alone or combined with
+// other executors in a Registry.
+type Funcs struct {
+	mu    sync.RWMutex
+	funcs map[string]funcTool
+	tools []libports.Tool
+}
+
+// funcTool is a function and the check of its arguments, if any
+type funcTool struct {
+	fn    Func
+	check func(args map[string]interface{}) error
+}
+
+// NewFuncs creates an executor without tools
+func NewFuncs() *Funcs {
+	return &Funcs{funcs: make(map[string]funcTool)}
+}
+
+// Add adds a tool run by fn. Tool names must be unique.
+func (f *Funcs) Add(tool libports.Tool, fn Func) error {
+	return f.add(tool, funcTool{fn: fn})
+}
+
+func (f *Funcs) add(tool libports.Tool, ft funcTool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool has no name")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.funcs[tool.Name]; ok {
+		return fmt.Errorf("duplicate tool name %q", tool.Name)
+	}
+	f.funcs[tool.Name] = ft
+	f.tools = append(f.tools, tool)
+	return nil
+}
+
+// AddFunc adds a tool run by fn with its arguments decoded into a T,
+// typically a struct, whose JSON schema (see schema.For) is the tool's
+// parameters. Calls with arguments not matching it aren't run: Execute
+// returns an error, which RunToolLoop sends back for the model to correct.
+func AddFunc[T any](f *Funcs, name, description string, fn func(ctx context.Context, args T) (string, error)) error {
+	parameters, err := schema.For[T]()
+	if err != nil {
+		return fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	tool := libports.Tool{Name: name, Description: description, Parameters: parameters}
+	return f.add(tool, funcTool{
+		fn: func(ctx context.Context, args map[string]interface{}) (string, error) {
+			var decoded T
+			data, _ := json.Marshal(args)
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			return fn(ctx, decoded)
+		},
+		check: func(args map[string]interface{}) error {
+			if args == nil {
+				args = map[string]interface{}{}
+			}
+			return schema.Validate(parameters, args)
+		},
+	})
+}
+
+// Tools returns the tools added (ports.ToolExecutor interface)
+func (f *Funcs) Tools() []libports.Tool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return append([]libports.Tool(nil), f.tools...)
+}
+
+// Execute runs a tool call with its function (ports.ToolExecutor interface)
+func (f *Funcs) Execute(ctx context.Context, call libports.ToolCall) (*ports.ToolResult, error) {
+	f.mu.RLock()
+	ft, ok := f.funcs[call.Name]
+	f.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrToolNotFound, call.Name)
+	}
+	if ft.check != nil {
+		if err := ft.check(call.Arguments); err != nil {
+			return nil, fmt.Errorf("tool %s: invalid arguments: %w", call.Name, err)
+		}
+	}
+
+	content, err := ft.fn(ctx, call.Arguments)
+	if err != nil {
+		return &ports.ToolResult{ToolCallID: call.ID, Name: call.Name, Content: err.Error(), IsError: true}, nil
+	}
+	return &ports.ToolResult{ToolCallID: call.ID, Name: call.Name, Content: content}, nil
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+var _ ports.ToolExecutor = (*Funcs)(nil)
+
+type weatherArgs struct {
+	City  string `json:"city" description:"City name"`
+	Units string `json:"units,omitempty" enum:"celsius,fahrenheit"`
+}
+
+func TestFuncs(t *testing.T) {
+	funcs := NewFuncs()
+	err := AddFunc(funcs, "get_weather", "Get the weather of a city", func(ctx context.Context, args weatherArgs) (string, error) {
+		if args.City == "Atlantis" {
+			return "", errors.New("unknown city")
+		}
+		return fmt.Sprintf("21 %s in %s", args.Units, args.City), nil
+	})
+	if err != nil {
+		t.Fatalf("AddFunc() error = %v", err)
+	}
+	err = funcs.Add(libports.Tool{Name: "get_time"}, func(ctx context.Context, args map[string]interface{}) (string, error) {
+		return "12:00", nil
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	tools := funcs.Tools()
+	if len(tools) != 2 || tools[0].Name != "get_weather" || tools[0].Parameters["type"] != "object" {
+		t.Fatalf("Tools() = %+v, want get_weather with its parameters, then get_time", tools)
+	}
+
+	ctx := context.Background()
+	call := libports.ToolCall{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris", "units": "celsius"}}
+	result, err := funcs.Execute(ctx, call)
+	if err != nil || result.Content != "21 celsius in Paris" || result.ToolCallID != "call_1" || result.IsError {
+		t.Errorf("Execute(Paris) = %+v, %v, want the weather", result, err)
+	}
+
+	call.Arguments = map[string]interface{}{"city": "Atlantis"}
+	result, err = funcs.Execute(ctx, call)
+	if err != nil || !result.IsError || result.Content != "unknown city" {
+		t.Errorf("Execute(Atlantis) = %+v, %v, want an error result", result, err)
+	}
+
+	call.Arguments = map[string]interface{}{"units": "kelvin"}
+	if _, err := funcs.Execute(ctx, call); err == nil || !strings.Contains(err.Error(), `missing required property "city"`) {
+		t.Errorf("Execute(invalid arguments) error = %v, want the violations", err)
+	}
+
+	if result, err := funcs.Execute(ctx, libports.ToolCall{Name: "get_time"}); err != nil || result.Content != "12:00" {
+		t.Errorf("Execute(get_time) = %+v, %v", result, err)
+	}
+	if _, err := funcs.Execute(ctx, libports.ToolCall{Name: "search"}); !errors.Is(err, ports.ErrToolNotFound) {
+		t.Errorf("Execute(search) error = %v, want ErrToolNotFound", err)
+	}
+	if err := funcs.Add(libports.Tool{Name: "get_time"}, nil); err == nil {
+		t.Error("Add() of a duplicate tool succeeded")
+	}
+}
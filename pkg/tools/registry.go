@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Registry combines tool executors into one ports.ToolExecutor, dispatching
+// each tool call to the executor providing the tool, so an agent can use
+// REST tools, the code interpreter and others together.
+type Registry struct {
+	mu        sync.RWMutex
+	executors map[string]ports.ToolExecutor
+	tools     []libports.Tool
+}
+
+// NewRegistry creates a registry of the tools of executors
+func NewRegistry(executors ...ports.ToolExecutor) (*Registry, error) {
+	r := &Registry{executors: make(map[string]ports.ToolExecutor)}
+	for _, executor := range executors {
+		if err := r.Register(executor); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds the tools of executor. Tool names must be unique across
+// executors.
+func (r *Registry) Register(executor ports.ToolExecutor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tools := executor.Tools()
+	for _, tool := range tools {
+		if _, ok := r.executors[tool.Name]; ok {
+			return fmt.Errorf("duplicate tool name %q", tool.Name)
+		}
+	}
+	for _, tool := range tools {
+		r.executors[tool.Name] = executor
+		r.tools = append(r.tools, tool)
+	}
+	return nil
+}
+
+// Tools returns the tools of all executors (ports.ToolExecutor interface)
+func (r *Registry) Tools() []libports.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]libports.Tool(nil), r.tools...)
+}
+
+// Execute runs a tool call with the executor providing the tool
+// (ports.ToolExecutor interface)
+func (r *Registry) Execute(ctx context.Context, call libports.ToolCall) (*ports.ToolResult, error) {
+	r.mu.RLock()
+	executor, ok := r.executors[call.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrToolNotFound, call.Name)
+	}
+	return executor.Execute(ctx, call)
+}
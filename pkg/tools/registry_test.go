@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+)
+
+var _ ports.ToolExecutor = (*Registry)(nil)
+
+// namedExecutor provides tools answering with the executor's name
+type namedExecutor struct {
+	name  string
+	tools []string
+}
+
+func (e *namedExecutor) Tools() []libports.Tool {
+	var tools []libports.Tool
+	for _, name := range e.tools {
+		tools = append(tools, libports.Tool{Name: name})
+	}
+	return tools
+}
+
+func (e *namedExecutor) Execute(ctx context.Context, call libports.ToolCall) (*ports.ToolResult, error) {
+	return &ports.ToolResult{ToolCallID: call.ID, Name: call.Name, Content: e.name}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := NewRegistry(
+		&namedExecutor{name: "http", tools: []string{"get_weather", "get_time"}},
+		&namedExecutor{name: "code", tools: []string{"run_code"}},
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	if tools := registry.Tools(); len(tools) != 3 || tools[2].Name != "run_code" {
+		t.Errorf("Tools() = %+v, want the 3 tools in order", tools)
+	}
+
+	for tool, want := range map[string]string{"get_time": "http", "run_code": "code"} {
+		result, err := registry.Execute(context.Background(), libports.ToolCall{ID: "call_1", Name: tool})
+		if err != nil || result.Content != want {
+			t.Errorf("Execute(%s) = %+v, %v, want the %s executor", tool, result, err, want)
+		}
+	}
+
+	if _, err := registry.Execute(context.Background(), libports.ToolCall{Name: "search"}); !errors.Is(err, ports.ErrToolNotFound) {
+		t.Errorf("Execute(search) error = %v, want ErrToolNotFound", err)
+	}
+}
+
+func TestRegistryDuplicate(t *testing.T) {
+	registry, _ := NewRegistry(&namedExecutor{name: "http", tools: []string{"get_weather"}})
+
+	err := registry.Register(&namedExecutor{name: "other", tools: []string{"search", "get_weather"}})
+	if err == nil {
+		t.Fatal("Register() error = nil, want a duplicate tool")
+	}
+	// Nothing of the rejected executor is registered
+	if len(registry.Tools()) != 1 {
+		t.Errorf("Tools() = %+v, want only get_weather", registry.Tools())
+	}
+}
@@ -0,0 +1,28 @@
+// Package rest implements ports.ToolExecutor (pkg/ports in this repository)
+// by mapping tool calls to REST calls, so that webhooks and internal APIs
+// can be exposed to models without custom worker code.
+//
+// Each Endpoint is one tool. Arguments fill {param} placeholders of its
+// URL; the rest become query parameters for GET, HEAD and DELETE and a JSON
+// object body otherwise. EndpointsFromOpenAPI builds endpoints from an
+// OpenAPI 3 spec, one per operation.
+//
+// Calls are bounded by a timeout (DefaultTimeout unless set per endpoint or
+// with SetTimeout) and responses by a size limit (DefaultMaxResponseBytes,
+// SetMaxResponseBytes). HTTP errors, timeouts and oversized responses are
+// returned as results with IsError set, so the model can react to them.
+//
+// Usage:
+//
+//	endpoints, err := rest.EndpointsFromOpenAPI(spec, "https://crm.internal")
+//	for i := range endpoints {
+//		endpoints[i].Auth = rest.BearerAuth(token)
+//	}
+//	executor, err := rest.NewExecutor(endpoints, logger)
+//
+//	resp, err := client.CompleteWithTools(ctx, req, executor.Tools())
+//	for _, call := range resp.ToolCalls {
+//		result, err := executor.Execute(ctx, call)
+//		// Append result.Content to the conversation
+//	}
+package rest
@@ -0,0 +1,345 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTimeout bounds each call of endpoints without their own Timeout
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxResponseBytes is the largest response body returned to the model
+	DefaultMaxResponseBytes = 1 << 20
+)
+
+// pathParamPattern matches {param} placeholders in endpoint URLs
+var pathParamPattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// Auth adds credentials to a request
+type Auth func(req *http.Request) error
+
+// BearerAuth sends token in the Authorization header
+func BearerAuth(token string) Auth {
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// BasicAuth sends HTTP basic credentials
+func BasicAuth(username, password string) Auth {
+	return func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	}
+}
+
+// HeaderAuth sends an API key in a custom header, e.g. X-API-Key
+func HeaderAuth(header, value string) Auth {
+	return func(req *http.Request) error {
+		req.Header.Set(header, value)
+		return nil
+	}
+}
+
+// Endpoint maps a tool to a REST call. Arguments named after {param}
+// placeholders of URL fill them; the others go to the query string for GET,
+// HEAD and DELETE, and to a JSON object body otherwise.
+type Endpoint struct {
+	// Name is the tool name the model calls.
+	Name string
+
+	// Description tells the model what the tool does.
+	Description string
+
+	// Method is the HTTP method; empty is GET.
+	Method string
+
+	// URL is the endpoint URL, e.g. https://api.example.com/users/{id}.
+	URL string
+
+	// Parameters is the JSON schema of the arguments. If nil, every path
+	// placeholder becomes a required string parameter.
+	Parameters map[string]interface{}
+
+	// QueryParams are arguments sent in the query string even when the
+	// method has a body.
+	QueryParams []string
+
+	// BodyParam, if set, is the argument sent as the whole request body
+	// instead of an object of the remaining arguments.
+	BodyParam string
+
+	// Headers are sent with every call.
+	Headers map[string]string
+
+	// Auth adds credentials to every call.
+	Auth Auth
+
+	// Timeout bounds each call; zero uses the executor's timeout.
+	Timeout time.Duration
+}
+
+// Executor implements the ports.ToolExecutor interface by calling REST
+// endpoints
+type Executor struct {
+	endpoints        map[string]Endpoint
+	tools            []libports.Tool
+	httpClient       *http.Client
+	timeout          time.Duration
+	maxResponseBytes int64
+	logger           *zap.Logger
+}
+
+// NewExecutor creates a new REST tool executor for endpoints
+func NewExecutor(endpoints []Endpoint, logger *zap.Logger) (*Executor, error) {
+	e := &Executor{
+		endpoints:        make(map[string]Endpoint, len(endpoints)),
+		httpClient:       http.DefaultClient,
+		timeout:          DefaultTimeout,
+		maxResponseBytes: DefaultMaxResponseBytes,
+		logger:           logger,
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Name == "" {
+			return nil, fmt.Errorf("endpoint %s has no name", endpoint.URL)
+		}
+		if _, ok := e.endpoints[endpoint.Name]; ok {
+			return nil, fmt.Errorf("duplicate tool name %q", endpoint.Name)
+		}
+		if _, err := url.Parse(endpoint.URL); err != nil || endpoint.URL == "" {
+			return nil, fmt.Errorf("endpoint %s has an invalid URL %q", endpoint.Name, endpoint.URL)
+		}
+		if endpoint.Method == "" {
+			endpoint.Method = http.MethodGet
+		}
+		endpoint.Method = strings.ToUpper(endpoint.Method)
+		if endpoint.Parameters == nil {
+			endpoint.Parameters = pathParameters(endpoint.URL)
+		}
+
+		e.endpoints[endpoint.Name] = endpoint
+		e.tools = append(e.tools, libports.Tool{
+			Name:        endpoint.Name,
+			Description: endpoint.Description,
+			Parameters:  endpoint.Parameters,
+		})
+	}
+
+	return e, nil
+}
+
+// SetHTTPClient sets the HTTP client used for calls
+func (e *Executor) SetHTTPClient(client *http.Client) {
+	e.httpClient = client
+}
+
+// SetTimeout sets the timeout of endpoints without their own
+func (e *Executor) SetTimeout(timeout time.Duration) {
+	e.timeout = timeout
+}
+
+// SetMaxResponseBytes sets the largest response body returned to the model.
+// Larger responses produce an error result rather than truncated JSON.
+func (e *Executor) SetMaxResponseBytes(n int64) {
+	e.maxResponseBytes = n
+}
+
+// Tools returns the endpoints as tool definitions (ports.ToolExecutor interface)
+func (e *Executor) Tools() []libports.Tool {
+	return append([]libports.Tool(nil), e.tools...)
+}
+
+// Execute calls the endpoint of a tool call (ports.ToolExecutor interface)
+func (e *Executor) Execute(ctx context.Context, call libports.ToolCall) (*ports.ToolResult, error) {
+	endpoint, ok := e.endpoints[call.Name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrToolNotFound, call.Name)
+	}
+
+	httpReq, err := e.buildRequest(ctx, endpoint, call.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", call.Name, err)
+	}
+
+	timeout := endpoint.Timeout
+	if timeout == 0 {
+		timeout = e.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := &ports.ToolResult{ToolCallID: call.ID, Name: call.Name}
+	start := time.Now()
+
+	httpResp, err := e.httpClient.Do(httpReq.WithContext(ctx))
+	if err != nil {
+		e.logger.Warn("tool call failed",
+			zap.String("tool", call.Name),
+			zap.Error(err))
+		result.Content = fmt.Sprintf("request failed: %v", err)
+		result.IsError = true
+		return result, nil
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, e.maxResponseBytes+1))
+	if err != nil {
+		result.Content = fmt.Sprintf("failed to read response: %v", err)
+		result.IsError = true
+		return result, nil
+	}
+
+	e.logger.Debug("tool called",
+		zap.String("tool", call.Name),
+		zap.Int("status", httpResp.StatusCode),
+		zap.Duration("duration", time.Since(start)))
+
+	switch {
+	case int64(len(body)) > e.maxResponseBytes:
+		result.Content = fmt.Sprintf("response exceeds %d bytes", e.maxResponseBytes)
+		result.IsError = true
+	case httpResp.StatusCode >= 400:
+		result.Content = fmt.Sprintf("HTTP %s: %s", httpResp.Status, strings.TrimSpace(string(body)))
+		result.IsError = true
+	default:
+		result.Content = string(body)
+	}
+	return result, nil
+}
+
+// buildRequest maps the arguments onto the endpoint's URL and body
+func (e *Executor) buildRequest(ctx context.Context, endpoint Endpoint, args map[string]interface{}) (*http.Request, error) {
+	remaining := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		remaining[name] = value
+	}
+
+	var missing []string
+	target := pathParamPattern.ReplaceAllStringFunc(endpoint.URL, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		value, ok := remaining[name]
+		if !ok {
+			missing = append(missing, name)
+			return placeholder
+		}
+		delete(remaining, name)
+		return url.PathEscape(stringValue(value))
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing path arguments: %s", strings.Join(missing, ", "))
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	query := u.Query()
+
+	hasBody := endpoint.Method != http.MethodGet && endpoint.Method != http.MethodHead && endpoint.Method != http.MethodDelete
+	for _, name := range endpoint.QueryParams {
+		if value, ok := remaining[name]; ok {
+			addQuery(query, name, value)
+			delete(remaining, name)
+		}
+	}
+
+	var body io.Reader
+	if hasBody {
+		var payload interface{} = remaining
+		if endpoint.BodyParam != "" {
+			payload = remaining[endpoint.BodyParam]
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		body = bytes.NewReader(data)
+	} else {
+		names := make([]string, 0, len(remaining))
+		for name := range remaining {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			addQuery(query, name, remaining[name])
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, endpoint.Method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if hasBody {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	for name, value := range endpoint.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	if endpoint.Auth != nil {
+		if err := endpoint.Auth(httpReq); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+	return httpReq, nil
+}
+
+// addQuery adds an argument to the query string, repeating arrays
+func addQuery(query url.Values, name string, value interface{}) {
+	if values, ok := value.([]interface{}); ok {
+		for _, v := range values {
+			query.Add(name, stringValue(v))
+		}
+		return
+	}
+	query.Add(name, stringValue(value))
+}
+
+// stringValue formats an argument for a URL: scalars as text, the rest as JSON
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case bool, float64, float32, int, int64, json.Number:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// pathParameters builds the parameter schema of an endpoint without one
+func pathParameters(rawURL string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(rawURL, -1) {
+		properties[match[1]] = map[string]interface{}{"type": "string"}
+		required = append(required, match[1])
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
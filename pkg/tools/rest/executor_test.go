@@ -0,0 +1,147 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/ports"
+	libports "github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+var _ ports.ToolExecutor = (*Executor)(nil)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users/42":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":     "42",
+				"fields": r.URL.Query()["fields"],
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/tickets":
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"notify":"` + r.URL.Query().Get("notify") + `","body":` + string(body) + `}`))
+		case r.URL.Path == "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case r.URL.Path == "/large":
+			_, _ = w.Write([]byte(strings.Repeat("x", 2048)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"no such user"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	server := newTestServer(t)
+	auth := BearerAuth("secret")
+
+	executor, err := NewExecutor([]Endpoint{
+		{Name: "get_user", URL: server.URL + "/users/{id}", Auth: auth},
+		{Name: "create_ticket", Method: "post", URL: server.URL + "/tickets", QueryParams: []string{"notify"}, Auth: auth},
+		{Name: "slow", URL: server.URL + "/slow", Auth: auth, Timeout: 50 * time.Millisecond},
+		{Name: "large", URL: server.URL + "/large", Auth: auth},
+		{Name: "no_auth", URL: server.URL + "/users/{id}"},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewExecutor() error = %v", err)
+	}
+	executor.SetMaxResponseBytes(1024)
+
+	tools := executor.Tools()
+	if len(tools) != 5 || tools[0].Name != "get_user" {
+		t.Fatalf("Tools() = %+v", tools)
+	}
+	if required := tools[0].Parameters["required"].([]string); len(required) != 1 || required[0] != "id" {
+		t.Errorf("get_user parameters = %v, want id required", tools[0].Parameters)
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		call        libports.ToolCall
+		wantContent string
+		wantError   bool
+	}{
+		{
+			name:        "path and repeated query arguments",
+			call:        libports.ToolCall{ID: "call-1", Name: "get_user", Arguments: map[string]interface{}{"id": 42.0, "fields": []interface{}{"name", "email"}}},
+			wantContent: `{"fields":["name","email"],"id":"42"}`,
+		},
+		{
+			name:        "body and query arguments",
+			call:        libports.ToolCall{ID: "call-2", Name: "create_ticket", Arguments: map[string]interface{}{"title": "Broken", "notify": true}},
+			wantContent: `{"notify":"true","body":{"title":"Broken"}}`,
+		},
+		{
+			name:        "not found",
+			call:        libports.ToolCall{ID: "call-3", Name: "get_user", Arguments: map[string]interface{}{"id": "7"}},
+			wantContent: `HTTP 404 Not Found: {"error":"no such user"}`,
+			wantError:   true,
+		},
+		{
+			name:      "timeout",
+			call:      libports.ToolCall{ID: "call-4", Name: "slow"},
+			wantError: true,
+		},
+		{
+			name:        "response too large",
+			call:        libports.ToolCall{ID: "call-5", Name: "large"},
+			wantContent: "response exceeds 1024 bytes",
+			wantError:   true,
+		},
+		{
+			name:      "unauthenticated",
+			call:      libports.ToolCall{ID: "call-6", Name: "no_auth", Arguments: map[string]interface{}{"id": "42"}},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := executor.Execute(ctx, tt.call)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.ToolCallID != tt.call.ID || result.IsError != tt.wantError {
+				t.Errorf("Execute() = %+v, want IsError %v", result, tt.wantError)
+			}
+			if tt.wantContent != "" && strings.TrimSpace(result.Content) != tt.wantContent {
+				t.Errorf("Execute() content = %q, want %q", result.Content, tt.wantContent)
+			}
+		})
+	}
+
+	if _, err := executor.Execute(ctx, libports.ToolCall{Name: "missing"}); !errors.Is(err, ports.ErrToolNotFound) {
+		t.Errorf("Execute() of an unknown tool error = %v, want ErrToolNotFound", err)
+	}
+	if _, err := executor.Execute(ctx, libports.ToolCall{Name: "get_user"}); err == nil {
+		t.Error("Execute() without a path argument expected error")
+	}
+}
+
+func TestNewExecutor_DuplicateName(t *testing.T) {
+	_, err := NewExecutor([]Endpoint{
+		{Name: "get_user", URL: "https://api.example.com/users/{id}"},
+		{Name: "get_user", URL: "https://api.example.com/v2/users/{id}"},
+	}, zap.NewNop())
+	if err == nil {
+		t.Error("NewExecutor() with duplicate names expected error")
+	}
+}
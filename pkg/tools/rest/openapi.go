@@ -0,0 +1,280 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Methods read from OpenAPI path items, in the order endpoints are returned
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// invalidNameChars matches characters not allowed in tool names by the
+// Anthropic and OpenAI APIs
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+type openAPISpec struct {
+	OpenAPI string `json:"openapi"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas    map[string]interface{}      `json:"schemas"`
+		Parameters map[string]openAPIParameter `json:"parameters"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `json:"operationId"`
+	Summary     string             `json:"summary"`
+	Description string             `json:"description"`
+	Parameters  []openAPIParameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema map[string]interface{} `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+type openAPIParameter struct {
+	Ref         string                 `json:"$ref"`
+	Name        string                 `json:"name"`
+	In          string                 `json:"in"`
+	Description string                 `json:"description"`
+	Required    bool                   `json:"required"`
+	Schema      map[string]interface{} `json:"schema"`
+}
+
+// EndpointsFromOpenAPI returns an endpoint per operation of an OpenAPI 3
+// spec, in JSON or YAML. Tools are named after operationId (or method and
+// path when missing), and take the operation's path and query parameters
+// and the properties of its JSON request body as arguments. baseURL
+// overrides the spec's first server URL.
+//
+// Header and cookie parameters are not mapped; set them with Headers and
+// Auth on the returned endpoints.
+func EndpointsFromOpenAPI(spec []byte, baseURL string) ([]Endpoint, error) {
+	data, err := yaml.YAMLToJSON(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	var doc openAPISpec
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, want 3.x", doc.OpenAPI)
+	}
+
+	if baseURL == "" && len(doc.Servers) > 0 {
+		baseURL = doc.Servers[0].URL
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("OpenAPI spec has no servers, a base URL is required")
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	resolver := &schemaResolver{schemas: doc.Components.Schemas}
+	var endpoints []Endpoint
+	for _, path := range paths {
+		item := doc.Paths[path]
+
+		var shared []openAPIParameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("invalid parameters of %s: %w", path, err)
+			}
+		}
+
+		for _, method := range openAPIMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op openAPIOperation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", method, path, err)
+			}
+
+			endpoint, err := operationEndpoint(doc, resolver, baseURL, method, path, shared, op)
+			if err != nil {
+				return nil, fmt.Errorf("operation %s %s: %w", method, path, err)
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("OpenAPI spec has no operations")
+	}
+	return endpoints, nil
+}
+
+// operationEndpoint maps one OpenAPI operation to an endpoint
+func operationEndpoint(doc openAPISpec, resolver *schemaResolver, baseURL, method, path string, shared []openAPIParameter, op openAPIOperation) (Endpoint, error) {
+	name := op.OperationID
+	if name == "" {
+		name = method + "_" + strings.Trim(path, "/")
+	}
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	description := op.Summary
+	if op.Description != "" {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += op.Description
+	}
+
+	endpoint := Endpoint{
+		Name:        name,
+		Description: description,
+		Method:      strings.ToUpper(method),
+		URL:         baseURL + path,
+	}
+
+	properties := map[string]interface{}{}
+	var required []string
+
+	// Operation parameters override path item parameters of the same name
+	params := map[string]openAPIParameter{}
+	var order []string
+	for _, param := range append(append([]openAPIParameter(nil), shared...), op.Parameters...) {
+		if param.Ref != "" {
+			ref, ok := doc.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+			if !ok {
+				return Endpoint{}, fmt.Errorf("unresolved parameter %s", param.Ref)
+			}
+			param = ref
+		}
+		if param.In != "path" && param.In != "query" {
+			continue
+		}
+		if _, ok := params[param.Name]; !ok {
+			order = append(order, param.Name)
+		}
+		params[param.Name] = param
+	}
+
+	for _, paramName := range order {
+		param := params[paramName]
+		schema := resolver.resolve(param.Schema)
+		if schema == nil {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		if param.Description != "" {
+			schema["description"] = param.Description
+		}
+		properties[param.Name] = schema
+		if param.Required || param.In == "path" {
+			required = append(required, param.Name)
+		}
+		if param.In == "query" && endpoint.Method != http.MethodGet && endpoint.Method != http.MethodDelete {
+			endpoint.QueryParams = append(endpoint.QueryParams, param.Name)
+		}
+	}
+
+	if op.RequestBody != nil {
+		content, ok := op.RequestBody.Content["application/json"]
+		if !ok {
+			return Endpoint{}, fmt.Errorf("request body is not application/json")
+		}
+		body := resolver.resolve(content.Schema)
+
+		bodyProperties, isObject := body["properties"].(map[string]interface{})
+		if isObject {
+			for name, schema := range bodyProperties {
+				properties[name] = schema
+			}
+			if bodyRequired, ok := body["required"].([]interface{}); ok {
+				for _, name := range bodyRequired {
+					if s, ok := name.(string); ok {
+						required = append(required, s)
+					}
+				}
+			}
+		} else {
+			endpoint.BodyParam = "body"
+			properties["body"] = body
+			if op.RequestBody.Required {
+				required = append(required, "body")
+			}
+		}
+	}
+
+	parameters := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		parameters["required"] = required
+	}
+	endpoint.Parameters = parameters
+
+	return endpoint, nil
+}
+
+// schemaResolver inlines #/components/schemas references, as models are
+// given each tool's schema on its own
+type schemaResolver struct {
+	schemas map[string]interface{}
+}
+
+// resolve returns a copy of schema with references inlined. Recursive
+// references are replaced by an untyped object.
+func (r *schemaResolver) resolve(schema map[string]interface{}) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	resolved, _ := r.inline(schema, map[string]bool{}).(map[string]interface{})
+	return resolved
+}
+
+func (r *schemaResolver) inline(value interface{}, visiting map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/components/schemas/")
+			target, ok := r.schemas[name]
+			if !ok || visiting[name] {
+				return map[string]interface{}{"type": "object"}
+			}
+			visiting[name] = true
+			defer delete(visiting, name)
+			return r.inline(target, visiting)
+		}
+
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = r.inline(item, visiting)
+		}
+		return out
+
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = r.inline(item, visiting)
+		}
+		return out
+
+	default:
+		return v
+	}
+}
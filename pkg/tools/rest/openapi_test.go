@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"testing"
+)
+
+const petstoreSpec = `
+openapi: 3.0.3
+servers:
+  - url: https://petstore.example.com/v1/
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - $ref: '#/components/parameters/limit'
+    post:
+      summary: Create a pet
+      parameters:
+        - name: dry_run
+          in: query
+          schema:
+            type: boolean
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        description: The pet to act on
+        schema:
+          type: string
+    get:
+      operationId: showPetById
+      parameters:
+        - name: X-Request-ID
+          in: header
+          schema:
+            type: string
+    put:
+      operationId: renamePet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: string
+components:
+  parameters:
+    limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+        pets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+func TestEndpointsFromOpenAPI(t *testing.T) {
+	endpoints, err := EndpointsFromOpenAPI([]byte(petstoreSpec), "")
+	if err != nil {
+		t.Fatalf("EndpointsFromOpenAPI() error = %v", err)
+	}
+
+	byName := map[string]Endpoint{}
+	for _, endpoint := range endpoints {
+		byName[endpoint.Name] = endpoint
+	}
+	if len(byName) != 4 {
+		t.Fatalf("EndpointsFromOpenAPI() = %d endpoints %v, want 4", len(endpoints), byName)
+	}
+
+	list := byName["listPets"]
+	if list.Method != "GET" || list.URL != "https://petstore.example.com/v1/pets" || list.Description != "List all pets" {
+		t.Errorf("listPets = %+v", list)
+	}
+	if limit := list.Parameters["properties"].(map[string]interface{})["limit"]; limit == nil {
+		t.Errorf("listPets parameters = %v, want limit", list.Parameters)
+	}
+
+	create, ok := byName["post_pets"]
+	if !ok {
+		t.Fatalf("no endpoint named after method and path: %v", byName)
+	}
+	properties := create.Parameters["properties"].(map[string]interface{})
+	if properties["name"] == nil || properties["dry_run"] == nil || len(create.QueryParams) != 1 {
+		t.Errorf("post_pets = %+v", create)
+	}
+	// The recursive reference is cut instead of looping
+	owner := properties["owner"].(map[string]interface{})
+	items := owner["properties"].(map[string]interface{})["pets"].(map[string]interface{})["items"].(map[string]interface{})
+	if items["type"] != "object" || items["properties"] != nil {
+		t.Errorf("recursive reference inlined as %v", items)
+	}
+
+	show := byName["showPetById"]
+	showProperties := show.Parameters["properties"].(map[string]interface{})
+	if showProperties["petId"] == nil || showProperties["X-Request-ID"] != nil {
+		t.Errorf("showPetById parameters = %v, want petId and no header", show.Parameters)
+	}
+
+	if rename := byName["renamePet"]; rename.BodyParam != "body" {
+		t.Errorf("renamePet = %+v, want a body argument", rename)
+	}
+
+	if _, err := EndpointsFromOpenAPI([]byte(`{"swagger":"2.0","paths":{}}`), "https://api"); err == nil {
+		t.Error("EndpointsFromOpenAPI() of a Swagger 2.0 spec expected error")
+	}
+}
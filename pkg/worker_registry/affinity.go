@@ -0,0 +1,70 @@
+package worker_registry
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// MetadataAffinity is the metadata key holding a worker's affinity keys:
+// opaque strings naming state that makes the worker a better fit for some
+// tasks, such as a loaded model ("model:llama3.1") or a warm cache
+// ("cache:session-42").
+const MetadataAffinity = "affinity_keys"
+
+// SetAffinityKeys stores the worker's affinity keys, replacing any existing
+// ones. Workers re-register as their state changes, e.g. after loading or
+// evicting a model.
+func SetAffinityKeys(worker *ports.WorkerInfo, keys []string) {
+	setMetadata(worker, MetadataAffinity, keys)
+}
+
+// AffinityKeys returns the worker's affinity keys, converted like Labels.
+func AffinityKeys(worker ports.WorkerInfo) []string {
+	var keys []string
+	decodeMetadata(worker, MetadataAffinity, &keys)
+	return keys
+}
+
+// HasAffinity reports whether the worker advertises the affinity key.
+func HasAffinity(worker ports.WorkerInfo, key string) bool {
+	return slices.Contains(AffinityKeys(worker), key)
+}
+
+// ListWorkersWithAffinity lists workers matching the filter that advertise the
+// affinity key. Routers use it to keep follow-up tasks on a worker that already
+// holds the required state, falling back to any worker when it returns none.
+func ListWorkersWithAffinity(ctx context.Context, registry ports.WorkerRegistry, filter ports.WorkerFilter, key string) ([]ports.WorkerInfo, error) {
+	workers, err := registry.ListWorkers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []ports.WorkerInfo
+	for _, worker := range workers {
+		if HasAffinity(worker, key) {
+			matched = append(matched, worker)
+		}
+	}
+
+	return matched, nil
+}
+
+// PreferAffinity returns the workers reordered so that those advertising the
+// affinity key come first, keeping the relative order within both groups.
+// It suits routers that pick the first available worker from a list.
+func PreferAffinity(workers []ports.WorkerInfo, key string) []ports.WorkerInfo {
+	preferred := make([]ports.WorkerInfo, 0, len(workers))
+	var others []ports.WorkerInfo
+
+	for _, worker := range workers {
+		if HasAffinity(worker, key) {
+			preferred = append(preferred, worker)
+		} else {
+			others = append(others, worker)
+		}
+	}
+
+	return append(preferred, others...)
+}
@@ -0,0 +1,53 @@
+package worker_registry
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+func TestAffinityKeys(t *testing.T) {
+	worker := ports.WorkerInfo{ID: "executor-1"}
+	if HasAffinity(worker, "model:llama3.1") {
+		t.Error("HasAffinity() = true for worker without affinity keys")
+	}
+
+	SetAffinityKeys(&worker, []string{"model:llama3.1", "cache:session-42"})
+	if !HasAffinity(worker, "model:llama3.1") {
+		t.Error("HasAffinity() = false for advertised key")
+	}
+
+	// Stored by a backend and decoded back as generic JSON values
+	worker.Metadata[MetadataAffinity] = []interface{}{"model:mistral"}
+	if !HasAffinity(worker, "model:mistral") || HasAffinity(worker, "model:llama3.1") {
+		t.Errorf("AffinityKeys() after decoding = %v, want [model:mistral]", AffinityKeys(worker))
+	}
+}
+
+func TestPreferAffinity(t *testing.T) {
+	newWorker := func(id string, keys ...string) ports.WorkerInfo {
+		worker := ports.WorkerInfo{ID: id}
+		if len(keys) > 0 {
+			SetAffinityKeys(&worker, keys)
+		}
+		return worker
+	}
+
+	workers := []ports.WorkerInfo{
+		newWorker("executor-1"),
+		newWorker("executor-2", "model:llama3.1"),
+		newWorker("executor-3", "model:mistral"),
+		newWorker("executor-4", "model:llama3.1", "cache:session-42"),
+	}
+
+	var got []string
+	for _, worker := range PreferAffinity(workers, "model:llama3.1") {
+		got = append(got, worker.ID)
+	}
+
+	want := []string{"executor-2", "executor-4", "executor-1", "executor-3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("PreferAffinity() = %v, want %v", got, want)
+	}
+}
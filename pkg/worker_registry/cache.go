@@ -0,0 +1,181 @@
+package worker_registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/cache"
+	localports "github.com/aescanero/dago-adapters/pkg/ports"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"golang.org/x/sync/singleflight"
+)
+
+// Key of the worker listing in a shared cache
+const workersCacheKey = "worker_registry:workers"
+
+// CachedRegistry wraps a registry with a short-lived cache of ListWorkers
+// results, for routers that list workers on every task. The full worker list
+// is fetched at most once per TTL, concurrent misses share a single fetch, and
+// filters are applied locally with MatchesFilter.
+//
+// Writes made through the cache invalidate it. Once started, changes reported
+// by the underlying registry's Watch invalidate it too, so the TTL only bounds
+// staleness for backends that can't watch. Other methods pass through.
+//
+// Workers returned from the cache share their Metadata maps and must not be
+// modified.
+type CachedRegistry struct {
+	ports.WorkerRegistry
+
+	ttl    time.Duration
+	group  singleflight.Group
+	shared localports.Cache
+
+	mu         sync.Mutex
+	workers    []ports.WorkerInfo
+	fetchedAt  time.Time
+	generation uint64
+}
+
+// NewCachedRegistry wraps registry, caching worker listings for ttl
+func NewCachedRegistry(registry ports.WorkerRegistry, ttl time.Duration) *CachedRegistry {
+	return &CachedRegistry{
+		WorkerRegistry: registry,
+		ttl:            ttl,
+	}
+}
+
+// SetCache keeps listings in a shared cache, e.g. Redis, instead of in
+// process, so router replicas share one fetch per TTL. Invalidations delete
+// the shared listing, so a replica's writes are seen by the others.
+func (c *CachedRegistry) SetCache(shared localports.Cache) {
+	c.shared = shared
+}
+
+// Start invalidates the cache on every change reported by the underlying
+// registry, until ctx is cancelled. It returns immediately, and does nothing
+// if the registry doesn't implement Watcher.
+func (c *CachedRegistry) Start(ctx context.Context) error {
+	watcher, ok := c.WorkerRegistry.(Watcher)
+	if !ok {
+		return nil
+	}
+
+	events, err := watcher.Watch(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for range events {
+			c.Invalidate()
+		}
+		// Changes may have been missed if the watch ended early
+		c.Invalidate()
+	}()
+
+	return nil
+}
+
+// Invalidate drops the cached listing; the next ListWorkers fetches it again.
+func (c *CachedRegistry) Invalidate() {
+	c.mu.Lock()
+	c.workers = nil
+	c.generation++
+	c.mu.Unlock()
+
+	if c.shared != nil {
+		_ = c.shared.Delete(context.Background(), workersCacheKey)
+	}
+}
+
+// Register registers a worker and invalidates the cache
+func (c *CachedRegistry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	defer c.Invalidate()
+	return c.WorkerRegistry.Register(ctx, worker)
+}
+
+// Unregister removes a worker and invalidates the cache
+func (c *CachedRegistry) Unregister(ctx context.Context, workerID string) error {
+	defer c.Invalidate()
+	return c.WorkerRegistry.Unregister(ctx, workerID)
+}
+
+// Heartbeat updates a worker's status and invalidates the cache
+func (c *CachedRegistry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	defer c.Invalidate()
+	return c.WorkerRegistry.Heartbeat(ctx, workerID, status, currentTask)
+}
+
+// CleanupStaleWorkers removes stale workers and invalidates the cache
+func (c *CachedRegistry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	defer c.Invalidate()
+	return c.WorkerRegistry.CleanupStaleWorkers(ctx, timeout)
+}
+
+// ListWorkers returns workers matching the filter from the cache, fetching
+// the full list when it is missing or older than the TTL. Workers count as
+// healthy unless their status is unhealthy.
+func (c *CachedRegistry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	workers, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ports.WorkerInfo
+	for _, worker := range workers {
+		isHealthy := worker.Status != ports.WorkerStatusUnhealthy
+		if MatchesFilter(worker, filter, isHealthy) {
+			result = append(result, worker)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *CachedRegistry) list(ctx context.Context) ([]ports.WorkerInfo, error) {
+	if c.shared != nil {
+		return cache.GetOrLoadJSON(ctx, c.shared, workersCacheKey, c.ttl, func(ctx context.Context) ([]ports.WorkerInfo, error) {
+			return c.WorkerRegistry.ListWorkers(ctx, ports.WorkerFilter{})
+		})
+	}
+
+	c.mu.Lock()
+	if c.workers != nil && time.Since(c.fetchedAt) < c.ttl {
+		workers := c.workers
+		c.mu.Unlock()
+		return workers, nil
+	}
+	c.mu.Unlock()
+
+	v, err, _ := c.group.Do("workers", func() (interface{}, error) {
+		c.mu.Lock()
+		generation := c.generation
+		c.mu.Unlock()
+
+		fetchedAt := time.Now()
+		workers, err := c.WorkerRegistry.ListWorkers(ctx, ports.WorkerFilter{})
+		if err != nil {
+			return nil, err
+		}
+		if workers == nil {
+			workers = []ports.WorkerInfo{}
+		}
+
+		// Don't cache a listing that an invalidation during the fetch may have outdated
+		c.mu.Lock()
+		if c.generation == generation {
+			c.workers = workers
+			c.fetchedAt = fetchedAt
+		}
+		c.mu.Unlock()
+
+		return workers, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]ports.WorkerInfo), nil
+}
@@ -0,0 +1,154 @@
+package worker_registry_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/cache/lru"
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/memory"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// countingRegistry counts ListWorkers calls reaching the wrapped registry
+type countingRegistry struct {
+	*memory.Registry
+	lists atomic.Int32
+}
+
+func (c *countingRegistry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	c.lists.Add(1)
+	return c.Registry.ListWorkers(ctx, filter)
+}
+
+func TestCachedRegistry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inner := &countingRegistry{Registry: memory.NewRegistry(zap.NewNop())}
+	cache := registry.NewCachedRegistry(inner, time.Hour)
+	if err := cache.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	register := func(r ports.WorkerRegistry, id string, workerType ports.WorkerType) {
+		t.Helper()
+		err := r.Register(ctx, ports.WorkerInfo{
+			ID:            id,
+			Type:          workerType,
+			Status:        ports.WorkerStatusIdle,
+			RegisteredAt:  time.Now(),
+			LastHeartbeat: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	list := func(filter ports.WorkerFilter) []ports.WorkerInfo {
+		t.Helper()
+		workers, err := cache.ListWorkers(ctx, filter)
+		if err != nil {
+			t.Fatalf("ListWorkers() error = %v", err)
+		}
+		return workers
+	}
+
+	register(cache, "executor-1", ports.WorkerTypeExecutor)
+	register(cache, "router-1", ports.WorkerTypeRouter)
+
+	if got := len(list(ports.WorkerFilter{})); got != 2 {
+		t.Fatalf("ListWorkers() returned %d workers, want 2", got)
+	}
+	executors := list(ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}})
+	if len(executors) != 1 || executors[0].ID != "executor-1" {
+		t.Fatalf("ListWorkers(executor) = %v, want [executor-1]", executors)
+	}
+	if got := inner.lists.Load(); got != 1 {
+		t.Errorf("backend listed %d times, want 1", got)
+	}
+
+	// A change made behind the cache's back is picked up through Watch
+	register(inner, "executor-2", ports.WorkerTypeExecutor)
+	deadline := time.Now().Add(time.Second)
+	for len(list(ports.WorkerFilter{})) != 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("cache was not invalidated by watch event")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Writes through the cache invalidate it directly
+	if err := cache.Heartbeat(ctx, "executor-2", ports.WorkerStatusUnhealthy, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if got := len(list(ports.WorkerFilter{HealthyOnly: true})); got != 2 {
+		t.Errorf("ListWorkers(HealthyOnly) returned %d workers, want 2", got)
+	}
+}
+
+func TestCachedRegistry_TTL(t *testing.T) {
+	ctx := context.Background()
+
+	inner := &countingRegistry{Registry: memory.NewRegistry(zap.NewNop())}
+	cache := registry.NewCachedRegistry(inner, 20*time.Millisecond)
+
+	for range 3 {
+		if _, err := cache.ListWorkers(ctx, ports.WorkerFilter{}); err != nil {
+			t.Fatalf("ListWorkers() error = %v", err)
+		}
+	}
+	if got := inner.lists.Load(); got != 1 {
+		t.Fatalf("backend listed %d times, want 1", got)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cache.ListWorkers(ctx, ports.WorkerFilter{}); err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if got := inner.lists.Load(); got != 2 {
+		t.Errorf("backend listed %d times after TTL, want 2", got)
+	}
+}
+
+func TestCachedRegistry_SharedCache(t *testing.T) {
+	ctx := context.Background()
+
+	inner := &countingRegistry{Registry: memory.NewRegistry(zap.NewNop())}
+	shared := lru.NewCache(10)
+	replicas := []*registry.CachedRegistry{
+		registry.NewCachedRegistry(inner, time.Hour),
+		registry.NewCachedRegistry(inner, time.Hour),
+	}
+	for _, replica := range replicas {
+		replica.SetCache(shared)
+	}
+
+	for _, replica := range replicas {
+		if _, err := replica.ListWorkers(ctx, ports.WorkerFilter{}); err != nil {
+			t.Fatalf("ListWorkers() error = %v", err)
+		}
+	}
+	if got := inner.lists.Load(); got != 1 {
+		t.Fatalf("backend listed %d times, want 1 for both replicas", got)
+	}
+
+	// A registration through one replica is seen by the other
+	err := replicas[0].Register(ctx, ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  time.Now(),
+		LastHeartbeat: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	workers, err := replicas[1].ListWorkers(ctx, ports.WorkerFilter{})
+	if err != nil || len(workers) != 1 || workers[0].ID != "executor-1" {
+		t.Errorf("ListWorkers() on the other replica = %v, %v", workers, err)
+	}
+}
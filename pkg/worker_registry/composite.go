@@ -0,0 +1,362 @@
+package worker_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// MetadataSource is the metadata key where CompositeRegistry records the name
+// of the source a worker was read from. Setting it before Register selects
+// the source the worker is registered in.
+const MetadataSource = "registry_source"
+
+// Source is a named registry federated by CompositeRegistry, e.g. the Redis
+// registry of one region.
+type Source struct {
+	Name     string
+	Registry ports.WorkerRegistry
+}
+
+// SourceStatus is the health of a source as seen by its last call.
+type SourceStatus struct {
+	Name        string
+	Healthy     bool
+	LastError   error
+	LastChecked time.Time
+}
+
+// CompositeRegistry merges several registries into one view, so a global
+// orchestrator sees workers across regions or backends. Reads fan out to all
+// sources concurrently; a worker found in several sources is reported once,
+// from the source with its latest heartbeat. Reads succeed as long as one
+// source answers, and SourceHealth tells which ones didn't.
+//
+// Register goes to the source named in the worker's MetadataSource, or the
+// first source. Heartbeat goes to the source holding the worker, Unregister
+// and CleanupStaleWorkers to all of them.
+type CompositeRegistry struct {
+	sources []Source
+	logger  *zap.Logger
+
+	mu     sync.RWMutex
+	health map[string]SourceStatus
+}
+
+// NewCompositeRegistry federates the sources. The first one receives
+// registrations that don't name a source.
+func NewCompositeRegistry(sources []Source, logger *zap.Logger) *CompositeRegistry {
+	health := make(map[string]SourceStatus, len(sources))
+	for _, source := range sources {
+		health[source.Name] = SourceStatus{Name: source.Name, Healthy: true}
+	}
+
+	return &CompositeRegistry{
+		sources: sources,
+		logger:  logger,
+		health:  health,
+	}
+}
+
+// SourceHealth returns the status of every source, in configuration order
+func (c *CompositeRegistry) SourceHealth() []SourceStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make([]SourceStatus, len(c.sources))
+	for i, source := range c.sources {
+		statuses[i] = c.health[source.Name]
+	}
+	return statuses
+}
+
+// Register registers the worker in the source named in its metadata, or the
+// first source
+func (c *CompositeRegistry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	source, err := c.sourceFor(worker)
+	if err != nil {
+		return err
+	}
+
+	err = source.Registry.Register(ctx, worker)
+	c.record(source.Name, err)
+	return err
+}
+
+// Unregister removes the worker from every source
+func (c *CompositeRegistry) Unregister(ctx context.Context, workerID string) error {
+	results := c.fanOut(ctx, func(ctx context.Context, source Source) (interface{}, error) {
+		return nil, source.Registry.Unregister(ctx, workerID)
+	})
+
+	return joinErrors(results)
+}
+
+// Heartbeat forwards the heartbeat to the source holding the worker. Unknown
+// workers are auto-registered by the first source.
+func (c *CompositeRegistry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	// Without a source in the metadata this picks the first one
+	lookup := ports.WorkerInfo{ID: workerID}
+	if worker, err := c.GetWorker(ctx, workerID); err == nil {
+		lookup = *worker
+	}
+
+	source, err := c.sourceFor(lookup)
+	if err != nil {
+		return err
+	}
+
+	err = source.Registry.Heartbeat(ctx, workerID, status, currentTask)
+	c.record(source.Name, err)
+	return err
+}
+
+// GetWorker returns the worker from the source with its latest heartbeat
+func (c *CompositeRegistry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	results := c.fanOut(ctx, func(ctx context.Context, source Source) (interface{}, error) {
+		return source.Registry.GetWorker(ctx, workerID)
+	})
+
+	var found *ports.WorkerInfo
+	var errs []sourceResult
+	for _, res := range results {
+		worker, _ := res.value.(*ports.WorkerInfo)
+		if res.err != nil || worker == nil {
+			if !isNotFound(res.err) {
+				errs = append(errs, res)
+			}
+			continue
+		}
+		if found == nil || worker.LastHeartbeat.After(found.LastHeartbeat) {
+			found = tagSource(*worker, res.source)
+		}
+	}
+
+	if found != nil {
+		return found, nil
+	}
+	if len(errs) > 0 {
+		// The worker may live in a source that is down
+		return nil, fmt.Errorf("failed to get worker: %w", joinErrors(errs))
+	}
+	return nil, fmt.Errorf("worker not found: %s", workerID)
+}
+
+// ListWorkers merges the workers matching the filter from all sources,
+// ordered by ID. It fails only when every source fails.
+func (c *CompositeRegistry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	results := c.fanOut(ctx, func(ctx context.Context, source Source) (interface{}, error) {
+		return source.Registry.ListWorkers(ctx, filter)
+	})
+
+	merged := make(map[string]ports.WorkerInfo)
+	failed := 0
+
+	for _, res := range results {
+		if res.err != nil {
+			failed++
+			continue
+		}
+
+		workers, _ := res.value.([]ports.WorkerInfo)
+		for _, worker := range workers {
+			if existing, ok := merged[worker.ID]; ok && !worker.LastHeartbeat.After(existing.LastHeartbeat) {
+				continue
+			}
+			merged[worker.ID] = *tagSource(worker, res.source)
+		}
+	}
+
+	if failed == len(results) && failed > 0 {
+		return nil, fmt.Errorf("failed to list workers: %w", joinErrors(results))
+	}
+
+	workers := make([]ports.WorkerInfo, 0, len(merged))
+	for _, worker := range merged {
+		workers = append(workers, worker)
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].ID < workers[j].ID })
+
+	return workers, nil
+}
+
+// GetWorkerStats returns statistics over the merged, de-duplicated workers
+func (c *CompositeRegistry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := c.ListWorkers(ctx, ports.WorkerFilter{Types: []ports.WorkerType{workerType}})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats, ok := ComputeWorkerStats(workers).ByType[workerType]; ok {
+		return stats, nil
+	}
+	return &ports.WorkerStats{Type: workerType}, nil
+}
+
+// CleanupStaleWorkers cleans up every source and returns the total removed
+func (c *CompositeRegistry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	results := c.fanOut(ctx, func(ctx context.Context, source Source) (interface{}, error) {
+		return source.Registry.CleanupStaleWorkers(ctx, timeout)
+	})
+
+	cleaned := 0
+	for _, res := range results {
+		if n, ok := res.value.(int); ok {
+			cleaned += n
+		}
+	}
+
+	return cleaned, joinErrors(results)
+}
+
+// Watch merges the changes of every source that implements Watcher, until ctx
+// is cancelled. Put events carry the source in the worker's metadata.
+func (c *CompositeRegistry) Watch(ctx context.Context) (<-chan WatchEvent, error) {
+	events := make(chan WatchEvent)
+	var wg sync.WaitGroup
+
+	for _, source := range c.sources {
+		watcher, ok := source.Registry.(Watcher)
+		if !ok {
+			continue
+		}
+
+		sourceEvents, err := watcher.Watch(ctx)
+		if err != nil {
+			c.record(source.Name, err)
+			c.logger.Warn("failed to watch registry source",
+				zap.String("source", source.Name),
+				zap.Error(err))
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			for event := range sourceEvents {
+				if event.Worker != nil {
+					event.Worker = tagSource(*event.Worker, name)
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(source.Name)
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	return events, nil
+}
+
+// sourceResult is the outcome of a call on one source
+type sourceResult struct {
+	source string
+	value  interface{}
+	err    error
+}
+
+// fanOut calls every source concurrently, recording their health
+func (c *CompositeRegistry) fanOut(ctx context.Context, call func(context.Context, Source) (interface{}, error)) []sourceResult {
+	results := make([]sourceResult, len(c.sources))
+	var wg sync.WaitGroup
+
+	for i, source := range c.sources {
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+
+			value, err := call(ctx, source)
+			results[i] = sourceResult{source: source.Name, value: value, err: err}
+		}(i, source)
+	}
+	wg.Wait()
+
+	for _, res := range results {
+		c.record(res.source, res.err)
+	}
+	return results
+}
+
+// record updates a source's health. Only errors from the backend count:
+// lookups of workers a source doesn't hold fail without the source being down.
+func (c *CompositeRegistry) record(name string, err error) {
+	healthy := err == nil || isNotFound(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.health[name]
+	if previous.Healthy && !healthy {
+		c.logger.Warn("worker registry source unavailable",
+			zap.String("source", name),
+			zap.Error(err))
+	} else if !previous.Healthy && healthy {
+		c.logger.Info("worker registry source recovered", zap.String("source", name))
+	}
+
+	status := SourceStatus{Name: name, Healthy: healthy, LastChecked: time.Now()}
+	if !healthy {
+		status.LastError = err
+	}
+	c.health[name] = status
+}
+
+func (c *CompositeRegistry) sourceFor(worker ports.WorkerInfo) (Source, error) {
+	if len(c.sources) == 0 {
+		return Source{}, errors.New("composite registry has no sources")
+	}
+
+	var name string
+	decodeMetadata(worker, MetadataSource, &name)
+	if name == "" {
+		return c.sources[0], nil
+	}
+
+	for _, source := range c.sources {
+		if source.Name == name {
+			return source, nil
+		}
+	}
+	return Source{}, fmt.Errorf("unknown registry source: %s", name)
+}
+
+// tagSource returns a copy of the worker whose metadata names its source,
+// without modifying metadata shared with the source registry
+func tagSource(worker ports.WorkerInfo, name string) *ports.WorkerInfo {
+	metadata := make(map[string]interface{}, len(worker.Metadata)+1)
+	for k, v := range worker.Metadata {
+		metadata[k] = v
+	}
+	worker.Metadata = metadata
+	setMetadata(&worker, MetadataSource, name)
+	return &worker
+}
+
+// isNotFound recognizes the "worker not found" errors every backend returns
+func isNotFound(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "worker not found")
+}
+
+func joinErrors(results []sourceResult) error {
+	var errs []error
+	for _, res := range results {
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.source, res.err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package worker_registry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/memory"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// downRegistry fails every call, like an unreachable region
+type downRegistry struct {
+	*memory.Registry
+}
+
+var errDown = errors.New("connection refused")
+
+func (downRegistry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	return nil, errDown
+}
+
+func (downRegistry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	return nil, errDown
+}
+
+func TestCompositeRegistry(t *testing.T) {
+	ctx := context.Background()
+	eu := memory.NewRegistry(zap.NewNop())
+	us := memory.NewRegistry(zap.NewNop())
+
+	composite := registry.NewCompositeRegistry([]registry.Source{
+		{Name: "eu", Registry: eu},
+		{Name: "us", Registry: us},
+	}, zap.NewNop())
+
+	now := time.Now()
+	register := func(r ports.WorkerRegistry, id string, lastHeartbeat time.Time) {
+		t.Helper()
+		err := r.Register(ctx, ports.WorkerInfo{
+			ID:            id,
+			Type:          ports.WorkerTypeExecutor,
+			Status:        ports.WorkerStatusIdle,
+			RegisteredAt:  lastHeartbeat,
+			LastHeartbeat: lastHeartbeat,
+		})
+		if err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	register(eu, "executor-eu", now)
+	register(us, "executor-us", now)
+
+	// Registered in both regions; the latest heartbeat wins
+	register(eu, "executor-moved", now.Add(-5*time.Second))
+	register(us, "executor-moved", now)
+
+	workers, err := composite.ListWorkers(ctx, ports.WorkerFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+
+	sources := make(map[string]string)
+	for _, worker := range workers {
+		sources[worker.ID], _ = worker.Metadata[registry.MetadataSource].(string)
+	}
+	want := map[string]string{"executor-eu": "eu", "executor-moved": "us", "executor-us": "us"}
+	if len(sources) != len(want) || len(workers) != len(want) {
+		t.Fatalf("ListWorkers() sources = %v, want %v", sources, want)
+	}
+	for id, source := range want {
+		if sources[id] != source {
+			t.Errorf("worker %s from source %q, want %q", id, sources[id], source)
+		}
+	}
+
+	// Heartbeats go to the source holding the worker
+	if err := composite.Heartbeat(ctx, "executor-us", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if worker, err := us.GetWorker(ctx, "executor-us"); err != nil || worker.Status != ports.WorkerStatusBusy {
+		t.Errorf("us.GetWorker() = %+v, %v, want busy", worker, err)
+	}
+	if _, err := eu.GetWorker(ctx, "executor-us"); err == nil {
+		t.Error("heartbeat auto-registered the worker in the wrong source")
+	}
+
+	stats, err := composite.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	if stats.TotalWorkers != 3 {
+		t.Errorf("GetWorkerStats() total = %d, want 3", stats.TotalWorkers)
+	}
+}
+
+func TestCompositeRegistry_SourceDown(t *testing.T) {
+	ctx := context.Background()
+	eu := memory.NewRegistry(zap.NewNop())
+	us := downRegistry{memory.NewRegistry(zap.NewNop())}
+
+	composite := registry.NewCompositeRegistry([]registry.Source{
+		{Name: "eu", Registry: eu},
+		{Name: "us", Registry: us},
+	}, zap.NewNop())
+
+	err := eu.Register(ctx, ports.WorkerInfo{
+		ID:            "executor-eu",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		LastHeartbeat: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// A partial view is better than none
+	workers, err := composite.ListWorkers(ctx, ports.WorkerFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if len(workers) != 1 {
+		t.Errorf("ListWorkers() returned %d workers, want 1", len(workers))
+	}
+
+	health := composite.SourceHealth()
+	if len(health) != 2 || !health[0].Healthy || health[1].Healthy || !errors.Is(health[1].LastError, errDown) {
+		t.Errorf("SourceHealth() = %+v, want eu healthy and us down", health)
+	}
+
+	// A worker missing from the healthy source may be in the one that is down
+	if _, err := composite.GetWorker(ctx, "executor-us"); !errors.Is(err, errDown) {
+		t.Errorf("GetWorker() error = %v, want source error", err)
+	}
+}
@@ -0,0 +1,38 @@
+// Package consul provides a Consul implementation of the WorkerRegistry interface.
+//
+// Workers are registered as instances of a Consul service (default name
+// "dago-worker") with a TTL health check, so existing Consul-based service
+// discovery tooling (UI, DNS, consul-template, ...) sees dago workers
+// alongside every other service.
+//
+// Key Design:
+//   - Each worker is a service instance whose ID is the worker ID
+//   - The worker type is added as a service tag (executor, router)
+//   - The worker record, metadata included, is stored as JSON in the KV
+//     store under dago/workers/{worker_id}; the service Meta, whose values
+//     Consul caps at 512 characters, only carries type, status, version and
+//     last heartbeat for filtering
+//   - Heartbeats update the record with a check-and-set, pass the TTL check
+//     and refresh the Meta
+//   - Workers whose check stays critical are deregistered by Consul after
+//     DeregisterCriticalServiceAfter (at least one minute); their records
+//     are deleted by CleanupStaleWorkers
+//
+// Register, Heartbeat and Unregister talk to the Consul agent the client is
+// configured with, so workers should use their local agent. Reads go
+// through the health endpoint and therefore see workers on every node.
+//
+// Usage:
+//
+//	client, _ := api.NewClient(api.DefaultConfig())
+//	registry := consul.NewRegistry(client, logger)
+//
+//	// Register a worker
+//	registry.Register(ctx, worker)
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+//
+//	// List all healthy workers
+//	workers, _ := registry.ListWorkers(ctx, ports.WorkerFilter{HealthyOnly: true})
+package consul
@@ -0,0 +1,453 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/hashicorp/consul/api"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default TTL for the worker health check (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// Consul service name under which all workers are registered
+	defaultServiceName = "dago-worker"
+
+	// Consul refuses DeregisterCriticalServiceAfter values below one minute
+	minDeregisterAfter = time.Minute
+
+	// Prefix of the KV keys holding worker records, followed by the service ID
+	recordPrefix = "dago/workers/"
+
+	// Attempts at Heartbeat's check-and-set before giving up
+	maxHeartbeatAttempts = 10
+
+	// Service meta keys. Meta values are limited to 512 characters, so only
+	// small fields useful for filtering are kept there; the worker record is
+	// in the KV store.
+	metaType          = "type"
+	metaStatus        = "status"
+	metaLastHeartbeat = "last_heartbeat"
+	metaVersion       = "version"
+)
+
+// Registry implements ports.WorkerRegistry using Consul services and TTL checks
+type Registry struct {
+	client      *api.Client
+	logger      *zap.Logger
+	ttl         time.Duration
+	serviceName string
+}
+
+// NewRegistry creates a new Consul worker registry
+func NewRegistry(client *api.Client, logger *zap.Logger) *Registry {
+	return &Registry{
+		client:      client,
+		logger:      logger,
+		ttl:         defaultWorkerTTL,
+		serviceName: defaultServiceName,
+	}
+}
+
+// NewRegistryWithTTL creates a new Consul worker registry with custom TTL
+func NewRegistryWithTTL(client *api.Client, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		client:      client,
+		logger:      logger,
+		ttl:         ttl,
+		serviceName: defaultServiceName,
+	}
+}
+
+// Register stores the worker's record and registers it as a Consul service
+// instance
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	if err := r.putRecord(ctx, worker); err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+	if err := r.registerService(ctx, worker); err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.String("service", r.serviceName),
+		zap.Duration("ttl", registry.WorkerTTL(worker, r.ttl)))
+
+	return nil
+}
+
+// Unregister removes the worker's service instance from the local agent, and
+// its record
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+	if err := r.client.Agent().ServiceDeregisterOpts(workerID, q); err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+	if _, err := r.client.KV().Delete(r.recordKey(workerID), (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the worker's record, passes its TTL check and refreshes
+// its service meta. The record is updated with a check-and-set, retried if
+// another write got in between, so concurrent writes aren't lost.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	var worker ports.WorkerInfo
+	for attempt := 1; ; attempt++ {
+		var (
+			updated bool
+			err     error
+		)
+		worker, updated, err = r.updateRecord(ctx, workerID, status, currentTask)
+		if err != nil {
+			return fmt.Errorf("failed to update heartbeat: %w", err)
+		}
+		if updated {
+			break
+		}
+		if attempt == maxHeartbeatAttempts {
+			return fmt.Errorf("failed to update heartbeat: worker %s modified concurrently", workerID)
+		}
+	}
+
+	// Re-registering is idempotent and updates the meta in place
+	if err := r.registerService(ctx, worker); err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	output := fmt.Sprintf("status: %s", status)
+	if err := r.client.Agent().UpdateTTLOpts(r.checkID(workerID), output, api.HealthPassing, q); err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	q := (&api.QueryOptions{Filter: fmt.Sprintf("Service.ID == %q", workerID)}).WithContext(ctx)
+
+	entries, _, err := r.client.Health().Service(r.serviceName, "", false, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("worker not found: %s", workerID)
+	}
+
+	record, _, err := r.client.KV().Get(r.recordKey(workerID), (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	worker, _, err := r.workerFromEntry(entries[0], record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	entries, _, err := r.client.Health().Service(r.serviceName, "", filter.HealthyOnly, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	records, err := r.listRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	var workers []ports.WorkerInfo
+
+	for _, entry := range entries {
+		worker, isHealthy, err := r.workerFromEntry(entry, records[entry.Service.ID])
+		if err != nil {
+			r.logger.Warn("failed to decode worker",
+				zap.String("service_id", entry.Service.ID),
+				zap.Error(err))
+			continue
+		}
+
+		// Apply filters
+		if !registry.MatchesFilter(worker, filter, isHealthy) {
+			continue
+		}
+
+		workers = append(workers, worker)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers deregisters workers that haven't sent a heartbeat within the timeout.
+// Workers are deregistered through the catalog so that instances owned by
+// other agents can be removed too. Stale records left by workers Consul
+// deregistered on its own are deleted as well.
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	entries, _, err := r.client.Health().Service(r.serviceName, "", false, q)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workers: %w", err)
+	}
+	records, err := r.listRecords(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	cleaned := 0
+
+	for _, entry := range entries {
+		record := records[entry.Service.ID]
+		delete(records, entry.Service.ID)
+
+		worker, _, err := r.workerFromEntry(entry, record)
+		if err != nil {
+			continue
+		}
+
+		// Check if worker is stale
+		if time.Since(worker.LastHeartbeat) <= timeout {
+			continue
+		}
+
+		_, err = r.client.Catalog().Deregister(&api.CatalogDeregistration{
+			Node:      entry.Node.Node,
+			ServiceID: entry.Service.ID,
+		}, (&api.WriteOptions{}).WithContext(ctx))
+		if err != nil {
+			r.logger.Warn("failed to delete stale worker",
+				zap.String("worker_id", worker.ID),
+				zap.Error(err))
+			continue
+		}
+		if record != nil {
+			r.deleteRecord(ctx, worker.ID)
+		}
+
+		r.logger.Info("cleaned up stale worker",
+			zap.String("worker_id", worker.ID),
+			zap.Duration("idle_time", time.Since(worker.LastHeartbeat)))
+		cleaned++
+	}
+
+	// Records whose service is gone
+	for workerID, record := range records {
+		worker, err := workerFromRecord(workerID, record)
+		if err == nil && time.Since(worker.LastHeartbeat) <= timeout {
+			continue
+		}
+		r.deleteRecord(ctx, workerID)
+	}
+
+	return cleaned, nil
+}
+
+// Helper methods
+
+func (r *Registry) checkID(workerID string) string {
+	return "dago-worker:" + workerID
+}
+
+func (r *Registry) recordKey(workerID string) string {
+	return recordPrefix + workerID
+}
+
+// putRecord stores the worker's record unconditionally
+func (r *Registry) putRecord(ctx context.Context, worker ports.WorkerInfo) error {
+	data, err := json.Marshal(worker)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+
+	pair := &api.KVPair{Key: r.recordKey(worker.ID), Value: data}
+	_, err = r.client.KV().Put(pair, (&api.WriteOptions{}).WithContext(ctx))
+	return err
+}
+
+// updateRecord applies a heartbeat to the worker's record with a
+// check-and-set on the index it read. It reports false if the record was
+// modified in between.
+func (r *Registry) updateRecord(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) (ports.WorkerInfo, bool, error) {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	pair, _, err := r.client.KV().Get(r.recordKey(workerID), q)
+	if err != nil {
+		return ports.WorkerInfo{}, false, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	var (
+		worker ports.WorkerInfo
+		index  uint64
+	)
+	if pair != nil {
+		if worker, err = workerFromRecord(workerID, pair); err != nil {
+			return worker, false, err
+		}
+		index = pair.ModifyIndex
+	} else {
+		// Worker not found, this shouldn't happen but we can recover
+		r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+			zap.String("worker_id", workerID))
+
+		worker = ports.WorkerInfo{
+			ID:           workerID,
+			Type:         registry.InferWorkerType(workerID),
+			RegisteredAt: time.Now(),
+		}
+	}
+
+	worker.Status = status
+	worker.LastHeartbeat = time.Now()
+	worker.CurrentTask = currentTask
+
+	data, err := json.Marshal(worker)
+	if err != nil {
+		return worker, false, fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+
+	// An index of 0 only creates the record if there is none
+	next := &api.KVPair{Key: r.recordKey(workerID), Value: data, ModifyIndex: index}
+	updated, _, err := r.client.KV().CAS(next, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return worker, false, fmt.Errorf("failed to store worker: %w", err)
+	}
+	return worker, updated, nil
+}
+
+// listRecords returns every worker record, by service ID
+func (r *Registry) listRecords(ctx context.Context) (map[string]*api.KVPair, error) {
+	pairs, _, err := r.client.KV().List(recordPrefix, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[string]*api.KVPair, len(pairs))
+	for _, pair := range pairs {
+		records[strings.TrimPrefix(pair.Key, recordPrefix)] = pair
+	}
+	return records, nil
+}
+
+func (r *Registry) deleteRecord(ctx context.Context, workerID string) {
+	if _, err := r.client.KV().Delete(r.recordKey(workerID), (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+		r.logger.Warn("failed to delete worker record",
+			zap.String("worker_id", workerID),
+			zap.Error(err))
+	}
+}
+
+func (r *Registry) registerService(ctx context.Context, worker ports.WorkerInfo) error {
+	meta := workerToMeta(worker)
+
+	// The check expires after the worker's own TTL, if it declared one
+	ttl := registry.WorkerTTL(worker, r.ttl)
+	deregisterAfter := 2 * ttl
+	if deregisterAfter < minDeregisterAfter {
+		deregisterAfter = minDeregisterAfter
+	}
+
+	registration := &api.AgentServiceRegistration{
+		ID:   worker.ID,
+		Name: r.serviceName,
+		Tags: []string{string(worker.Type)},
+		Meta: meta,
+		Check: &api.AgentServiceCheck{
+			CheckID:                        r.checkID(worker.ID),
+			Name:                           "dago worker heartbeat",
+			TTL:                            ttl.String(),
+			Status:                         api.HealthPassing,
+			DeregisterCriticalServiceAfter: deregisterAfter.String(),
+		},
+	}
+
+	opts := api.ServiceRegisterOpts{}.WithContext(ctx)
+	return r.client.Agent().ServiceRegisterOpts(registration, opts)
+}
+
+// workerFromEntry decodes a health entry and the worker's record and reports
+// whether the worker is healthy. A worker is healthy when its checks pass and
+// its last heartbeat is within its TTL.
+func (r *Registry) workerFromEntry(entry *api.ServiceEntry, record *api.KVPair) (ports.WorkerInfo, bool, error) {
+	if record == nil {
+		return ports.WorkerInfo{}, false, fmt.Errorf("worker record not found: %s", entry.Service.ID)
+	}
+
+	worker, err := workerFromRecord(entry.Service.ID, record)
+	if err != nil {
+		return worker, false, err
+	}
+
+	isHealthy := entry.Checks.AggregatedStatus() == api.HealthPassing &&
+		time.Since(worker.LastHeartbeat) <= registry.WorkerTTL(worker, r.ttl)
+	if !isHealthy {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return worker, isHealthy, nil
+}
+
+func workerToMeta(worker ports.WorkerInfo) map[string]string {
+	return map[string]string{
+		metaType:          string(worker.Type),
+		metaStatus:        string(worker.Status),
+		metaLastHeartbeat: worker.LastHeartbeat.Format(time.RFC3339Nano),
+		metaVersion:       worker.Version,
+	}
+}
+
+func workerFromRecord(workerID string, record *api.KVPair) (ports.WorkerInfo, error) {
+	var worker ports.WorkerInfo
+	if err := json.Unmarshal(record.Value, &worker); err != nil {
+		return worker, fmt.Errorf("failed to unmarshal worker info: %w", err)
+	}
+	worker.ID = workerID
+	return worker, nil
+}
@@ -0,0 +1,139 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/hashicorp/consul/api"
+	"go.uber.org/zap"
+)
+
+func TestWorkerToMeta(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	worker := ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusBusy,
+		RegisteredAt:  now.Add(-time.Hour),
+		LastHeartbeat: now,
+		CurrentTask:   strings.Repeat("t", 1000),
+		PendingTasks:  3,
+		Version:       "1.2.3",
+		Metadata:      map[string]interface{}{"labels": strings.Repeat("x", 1000)},
+	}
+
+	// Only small fields go to the meta, whose values Consul caps at 512
+	// characters; the rest is in the KV record
+	meta := workerToMeta(worker)
+	for key, value := range meta {
+		if len(value) > 512 {
+			t.Errorf("meta %s is %d characters long", key, len(value))
+		}
+	}
+
+	want := map[string]string{
+		metaType:          string(worker.Type),
+		metaStatus:        string(worker.Status),
+		metaLastHeartbeat: now.Format(time.RFC3339Nano),
+		metaVersion:       worker.Version,
+	}
+	if !maps.Equal(meta, want) {
+		t.Errorf("workerToMeta() = %v, want %v", meta, want)
+	}
+}
+
+func TestWorkerFromRecord(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	worker := ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusBusy,
+		LastHeartbeat: now,
+		CurrentTask:   "task-1",
+		Metadata:      map[string]interface{}{"labels": strings.Repeat("x", 1000)},
+	}
+	data, err := json.Marshal(worker)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := workerFromRecord("executor-1", &api.KVPair{Key: recordPrefix + "executor-1", Value: data})
+	if err != nil {
+		t.Fatalf("workerFromRecord() error = %v", err)
+	}
+	if got.ID != "executor-1" || got.CurrentTask != "task-1" || got.Metadata["labels"] != worker.Metadata["labels"] {
+		t.Errorf("workerFromRecord() = %+v, want %+v", got, worker)
+	}
+
+	if _, err := workerFromRecord("executor-1", &api.KVPair{Value: []byte("{")}); err == nil {
+		t.Error("workerFromRecord() expected error for an invalid record")
+	}
+}
+
+// Integration test - only runs with CONSUL_HTTP_ADDR environment variable
+func TestRegistry_Integration(t *testing.T) {
+	if os.Getenv("CONSUL_HTTP_ADDR") == "" {
+		t.Skip("CONSUL_HTTP_ADDR not set, skipping integration test")
+	}
+
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	r := NewRegistry(client, zap.NewNop())
+
+	workerID := "router-consul-integration"
+	defer func() { _ = r.Unregister(ctx, workerID) }()
+
+	// Metadata well over the 512 characters a meta value can hold
+	labels := strings.Repeat("x", 2000)
+	err = r.Register(ctx, ports.WorkerInfo{
+		ID:            workerID,
+		Type:          ports.WorkerTypeRouter,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  time.Now(),
+		LastHeartbeat: time.Now(),
+		Metadata:      map[string]interface{}{"labels": labels},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := r.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusBusy || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want busy on task-1", worker)
+	}
+	if worker.Metadata["labels"] != labels {
+		t.Error("GetWorker() lost the worker's metadata")
+	}
+
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeRouter}})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if len(workers) == 0 {
+		t.Error("ListWorkers() returned no routers")
+	}
+
+	if err := r.Unregister(ctx, workerID); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	if pair, _, err := client.KV().Get(recordPrefix+workerID, nil); err != nil || pair != nil {
+		t.Errorf("worker record after Unregister() = %v, %v, want none", pair, err)
+	}
+}
@@ -5,9 +5,77 @@
 //
 // Available implementations:
 //   - redis: Uses Redis for storage with automatic expiration via TTL
+//   - postgres: Uses a PostgreSQL table with upsert-based heartbeats
+//   - etcd: Uses etcd leases for expiry and native watches for membership
+//     changes
+//   - consul: Registers workers as Consul services with TTL health checks
+//   - nats: Stores workers in a NATS JetStream KV bucket with key expiry
+//   - dynamodb: Uses a DynamoDB table with TTL attributes and a GSI on worker
+//     type
+//   - mongodb: Uses a MongoDB collection with a TTL index on expires_at
+//   - kubernetes: Stores workers as Lease objects with label-based filtering
+//   - sqlite: Uses an embedded SQLite database in WAL mode for single-node
+//     installs
+//   - zookeeper: Uses ephemeral znodes so liveness follows the ZooKeeper
+//     session
+//   - memory: Keeps workers in process memory, for development and tests
+//
+// Shared helpers (MatchesFilter, InferWorkerType, GetAllWorkerStats) and the
+// optional Watcher interface live in this package so that every backend
+// behaves the same way.
+//
+// Workers can carry labels (gpu=true, region=eu) and multi-valued
+// capabilities (models=[llama3.1]) in their metadata via SetLabels and
+// SetCapabilities. ports.WorkerFilter cannot express them, so routers select
+// on them with a Selector, parsed from the Kubernetes label selector syntax
+// and applied through ListWorkersWithSelector:
+//
+//	selector, _ := worker_registry.ParseSelector("gpu=true,models in (llama3.1)")
+//	workers, _ := worker_registry.ListWorkersWithSelector(ctx, registry,
+//	    ports.WorkerFilter{HealthyOnly: true}, selector)
+//
+// For sticky assignment, workers advertise affinity keys (a loaded model, a
+// warm cache) with SetAffinityKeys. Routers look up workers holding a key with
+// ListWorkersWithAffinity, or order candidates with PreferAffinity, so that
+// follow-up tasks land where the state already is.
+//
+// Workers that heartbeat slower than the registry-wide TTL allows can declare
+// their own with SetTTL; HeartbeatInterval derives how often to heartbeat.
+// Every registry judges the health of such workers, and expires them, by
+// their own TTL; ZooKeeper, whose liveness otherwise follows the session,
+// reports them unhealthy past it. The redis and memory registries can also
+// add random jitter to TTLs (SetTTLJitter) so that workers registered
+// together during a rollout don't expire in lockstep, and expose each
+// worker's effective expiry through Deadline.
+//
+// Worker binaries run HeartbeatRunner instead of their own loop: it registers
+// the worker, heartbeats with configurable jitter using a StatusFunc for the
+// current status, backs off when the registry is unavailable and stops when
+// its context ends.
+//
+// HealthChecker wraps any registry and actively probes workers on the HTTP or
+// gRPC endpoint declared with SetHealthEndpoint, reporting workers that still
+// heartbeat but fail their probes as unhealthy.
+//
+// Routers that list workers on every task wrap the registry in a
+// CachedRegistry: listings are cached for a short TTL and filtered locally,
+// and changes reported by Watch invalidate the cache. With SetCache, the
+// listing is kept in a shared ports.Cache (pkg/cache) so router replicas
+// share it.
+//
+// CompositeRegistry federates several registries, e.g. the Redis registries
+// of several regions, into one view for a global orchestrator. Workers found
+// in more than one source are de-duplicated by latest heartbeat, reads
+// tolerate unavailable sources, and SourceHealth reports which ones failed.
+//
+// For zero-downtime rollouts, Shutdown marks a worker WorkerStatusDraining,
+// waits for its pending tasks to reach zero (or a deadline) and then
+// unregisters it. Drain only sets the status.
+//
+// New backends should pass the conformance suite in registrytest, which
+// checks them against the behavior of the Redis registry.
 //
 // Future implementations could include:
 //   - kafka: Using Kafka topics for worker state
 //   - websocket: Using WebSocket connections for real-time updates
-//   - database: Using PostgreSQL or other databases
 package worker_registry
@@ -0,0 +1,105 @@
+package worker_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// WorkerStatusDraining marks a worker that is finishing its pending tasks
+// before shutting down. Routers should not hand it new work; filtering on
+// idle or busy workers already excludes it.
+const WorkerStatusDraining ports.WorkerStatus = "draining"
+
+// Default polling interval while waiting for a draining worker
+const defaultDrainPollInterval = time.Second
+
+// ErrDrainTimeout is returned by Shutdown when the worker still had pending
+// tasks at the deadline. The worker is unregistered regardless.
+var ErrDrainTimeout = errors.New("worker still had pending tasks at drain deadline")
+
+// DrainOptions configures Shutdown.
+type DrainOptions struct {
+	// Timeout bounds the wait for pending tasks to reach zero.
+	// Zero waits until ctx is cancelled.
+	Timeout time.Duration
+
+	// PollInterval is how often the worker heartbeats and re-checks its
+	// pending tasks. Defaults to one second.
+	PollInterval time.Duration
+}
+
+// Drain marks a worker as draining, keeping its current task.
+func Drain(ctx context.Context, registry ports.WorkerRegistry, workerID string) error {
+	worker, err := registry.GetWorker(ctx, workerID)
+	if err != nil {
+		return err
+	}
+
+	if err := registry.Heartbeat(ctx, workerID, WorkerStatusDraining, worker.CurrentTask); err != nil {
+		return fmt.Errorf("failed to drain worker: %w", err)
+	}
+
+	return nil
+}
+
+// Shutdown gracefully removes a worker: it marks the worker draining, keeps
+// heartbeating until its pending tasks reach zero or the deadline passes, then
+// unregisters it. Heartbeating refreshes the pending count on backends that
+// derive it (such as Redis stream consumer info) and keeps the worker from
+// expiring while it drains.
+//
+// The final Unregister runs even if ctx was cancelled, so Shutdown can be
+// called from a signal handler with the shutdown context.
+func Shutdown(ctx context.Context, registry ports.WorkerRegistry, workerID string, opts DrainOptions) error {
+	if err := Drain(ctx, registry, workerID); err != nil {
+		return err
+	}
+
+	waitErr := waitForDrain(ctx, registry, workerID, opts)
+
+	if err := registry.Unregister(context.WithoutCancel(ctx), workerID); err != nil {
+		return err
+	}
+
+	return waitErr
+}
+
+func waitForDrain(ctx context.Context, registry ports.WorkerRegistry, workerID string, opts DrainOptions) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultDrainPollInterval
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		worker, err := registry.GetWorker(ctx, workerID)
+		if err == nil && worker.PendingTasks == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrDrainTimeout
+		case <-ticker.C:
+		}
+
+		currentTask := ""
+		if worker != nil {
+			currentTask = worker.CurrentTask
+		}
+		// A failed heartbeat is retried on the next tick
+		_ = registry.Heartbeat(ctx, workerID, WorkerStatusDraining, currentTask)
+	}
+}
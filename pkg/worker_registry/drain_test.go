@@ -0,0 +1,78 @@
+package worker_registry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/memory"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func TestShutdown(t *testing.T) {
+	tests := []struct {
+		name         string
+		pendingTasks int
+		finishTasks  bool
+		wantErr      error
+	}{
+		{name: "no pending tasks", pendingTasks: 0},
+		{name: "tasks finish while draining", pendingTasks: 2, finishTasks: true},
+		{name: "deadline with pending tasks", pendingTasks: 2, wantErr: registry.ErrDrainTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := memory.NewRegistry(zap.NewNop())
+
+			worker := ports.WorkerInfo{
+				ID:            "executor-1",
+				Type:          ports.WorkerTypeExecutor,
+				Status:        ports.WorkerStatusBusy,
+				CurrentTask:   "task-1",
+				PendingTasks:  tt.pendingTasks,
+				RegisteredAt:  time.Now(),
+				LastHeartbeat: time.Now(),
+			}
+			if err := r.Register(ctx, worker); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+
+			if err := registry.Drain(ctx, r, worker.ID); err != nil {
+				t.Fatalf("Drain() error = %v", err)
+			}
+			got, err := r.GetWorker(ctx, worker.ID)
+			if err != nil {
+				t.Fatalf("GetWorker() error = %v", err)
+			}
+			if got.Status != registry.WorkerStatusDraining || got.CurrentTask != "task-1" {
+				t.Errorf("GetWorker() after Drain = %+v, want draining on task-1", got)
+			}
+
+			if tt.finishTasks {
+				go func() {
+					time.Sleep(30 * time.Millisecond)
+					finished := *got
+					finished.PendingTasks = 0
+					_ = r.Register(ctx, finished)
+				}()
+			}
+
+			err = registry.Shutdown(ctx, r, worker.ID, registry.DrainOptions{
+				Timeout:      200 * time.Millisecond,
+				PollInterval: 10 * time.Millisecond,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Shutdown() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if _, err := r.GetWorker(ctx, worker.ID); err == nil {
+				t.Error("GetWorker() after Shutdown succeeded, want worker unregistered")
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package dynamodb provides an Amazon DynamoDB implementation of the
+// WorkerRegistry interface.
+//
+// This implementation targets serverless and AWS-native deployments where
+// running Redis is an operational burden. Each worker is one item in the
+// dago_workers table, and a global secondary index on the worker type keeps
+// per-type listings and stats from scanning the whole table.
+//
+// Key Design:
+//   - Table dago_workers, partition key worker_id
+//   - GSI type-index: partition key type, sort key last_heartbeat (unix ms),
+//     so healthy workers of a type are a single key-condition Query
+//   - expires_at (unix seconds) is the table's TTL attribute; every heartbeat
+//     pushes it forward by the registry TTL
+//   - Heartbeats are a single UpdateItem with if_not_exists for first writes
+//
+// DynamoDB deletes expired items lazily (usually within a few days), so reads
+// also filter on expires_at and treat expired items as absent, matching the
+// behaviour of the Redis implementation.
+//
+// Usage:
+//
+//	cfg, _ := config.LoadDefaultConfig(ctx)
+//	client := awsdynamodb.NewFromConfig(cfg)
+//	registry := dynamodb.NewRegistry(client, logger)
+//
+//	// Create the table, index and TTL setting if they don't exist
+//	if err := registry.CreateTable(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+//
+//	// List healthy executors (served by the type index)
+//	workers, _ := registry.ListWorkers(ctx, ports.WorkerFilter{
+//	    Types:       []ports.WorkerType{ports.WorkerTypeExecutor},
+//	    HealthyOnly: true,
+//	})
+package dynamodb
@@ -0,0 +1,537 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default TTL for worker heartbeats (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// Table and index names
+	defaultTableName = "dago_workers"
+	typeIndexName    = "type-index"
+
+	// Maximum time CreateTable waits for the table to become active
+	tableActiveTimeout = 2 * time.Minute
+
+	// Item attribute names
+	attrWorkerID      = "worker_id"
+	attrType          = "type"
+	attrStatus        = "status"
+	attrCurrentTask   = "current_task"
+	attrRegisteredAt  = "registered_at"
+	attrLastHeartbeat = "last_heartbeat"
+	attrPendingTasks  = "pending_tasks"
+	attrVersion       = "version"
+	attrMetadata      = "metadata"
+	attrExpiresAt     = "expires_at"
+	attrHeartbeatTTL  = "heartbeat_ttl"
+)
+
+// Client is the subset of the DynamoDB API used by the registry.
+// It is satisfied by *dynamodb.Client.
+type Client interface {
+	GetItem(ctx context.Context, params *ddb.GetItemInput, optFns ...func(*ddb.Options)) (*ddb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *ddb.PutItemInput, optFns ...func(*ddb.Options)) (*ddb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *ddb.UpdateItemInput, optFns ...func(*ddb.Options)) (*ddb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *ddb.DeleteItemInput, optFns ...func(*ddb.Options)) (*ddb.DeleteItemOutput, error)
+	Query(ctx context.Context, params *ddb.QueryInput, optFns ...func(*ddb.Options)) (*ddb.QueryOutput, error)
+	Scan(ctx context.Context, params *ddb.ScanInput, optFns ...func(*ddb.Options)) (*ddb.ScanOutput, error)
+	CreateTable(ctx context.Context, params *ddb.CreateTableInput, optFns ...func(*ddb.Options)) (*ddb.CreateTableOutput, error)
+	DescribeTable(ctx context.Context, params *ddb.DescribeTableInput, optFns ...func(*ddb.Options)) (*ddb.DescribeTableOutput, error)
+	DescribeTimeToLive(ctx context.Context, params *ddb.DescribeTimeToLiveInput, optFns ...func(*ddb.Options)) (*ddb.DescribeTimeToLiveOutput, error)
+	UpdateTimeToLive(ctx context.Context, params *ddb.UpdateTimeToLiveInput, optFns ...func(*ddb.Options)) (*ddb.UpdateTimeToLiveOutput, error)
+}
+
+// Registry implements ports.WorkerRegistry using a DynamoDB table
+type Registry struct {
+	client    Client
+	logger    *zap.Logger
+	ttl       time.Duration
+	tableName string
+}
+
+// NewRegistry creates a new DynamoDB worker registry
+func NewRegistry(client Client, logger *zap.Logger) *Registry {
+	return &Registry{
+		client:    client,
+		logger:    logger,
+		ttl:       defaultWorkerTTL,
+		tableName: defaultTableName,
+	}
+}
+
+// NewRegistryWithTTL creates a new DynamoDB worker registry with custom TTL
+func NewRegistryWithTTL(client Client, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		client:    client,
+		logger:    logger,
+		ttl:       ttl,
+		tableName: defaultTableName,
+	}
+}
+
+// CreateTable creates the workers table with its type index and enables TTL
+// on expires_at. Existing tables are left as they are, so it is safe to call
+// on every startup.
+func (r *Registry) CreateTable(ctx context.Context) error {
+	_, err := r.client.CreateTable(ctx, &ddb.CreateTableInput{
+		TableName:   aws.String(r.tableName),
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String(attrWorkerID), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrType), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrLastHeartbeat), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String(attrWorkerID), KeyType: types.KeyTypeHash},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName: aws.String(typeIndexName),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String(attrType), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String(attrLastHeartbeat), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}},
+	})
+	var inUse *types.ResourceInUseException
+	if err != nil && !errors.As(err, &inUse) {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	waiter := ddb.NewTableExistsWaiter(r.client)
+	if err := waiter.Wait(ctx, &ddb.DescribeTableInput{TableName: aws.String(r.tableName)}, tableActiveTimeout); err != nil {
+		return fmt.Errorf("failed to wait for table: %w", err)
+	}
+
+	ttlDesc, err := r.client.DescribeTimeToLive(ctx, &ddb.DescribeTimeToLiveInput{TableName: aws.String(r.tableName)})
+	if err != nil {
+		return fmt.Errorf("failed to describe table TTL: %w", err)
+	}
+	if d := ttlDesc.TimeToLiveDescription; d != nil &&
+		(d.TimeToLiveStatus == types.TimeToLiveStatusEnabled || d.TimeToLiveStatus == types.TimeToLiveStatusEnabling) {
+		return nil
+	}
+
+	_, err = r.client.UpdateTimeToLive(ctx, &ddb.UpdateTimeToLiveInput{
+		TableName: aws.String(r.tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attrExpiresAt),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable table TTL: %w", err)
+	}
+
+	r.logger.Info("worker table created", zap.String("table", r.tableName))
+	return nil
+}
+
+// Register registers a new worker in the system
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	item, err := workerToItem(worker, time.Now().Add(registry.WorkerTTL(worker, r.ttl)))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.PutItem(ctx, &ddb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.Duration("ttl", registry.WorkerTTL(worker, r.ttl)))
+
+	return nil
+}
+
+// Unregister removes a worker from the registry
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	_, err := r.client.DeleteItem(ctx, &ddb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       workerKey(workerID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the last heartbeat timestamp for a worker and extends its
+// expiry, by its own TTL if it declared one. Unknown workers are created in the
+// same request, with their type inferred from the ID.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	now := time.Now()
+
+	out, err := r.client.UpdateItem(ctx, &ddb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       workerKey(workerID),
+		UpdateExpression: aws.String("SET #status = :status, current_task = :task, " +
+			"last_heartbeat = :now, expires_at = if_not_exists(#ttl, :ttl) + :nowsec, " +
+			"#type = if_not_exists(#type, :type), registered_at = if_not_exists(registered_at, :now)"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": attrStatus,
+			"#type":   attrType,
+			"#ttl":    attrHeartbeatTTL,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status": stringValue(string(status)),
+			":task":   stringValue(currentTask),
+			":now":    millisValue(now),
+			":nowsec": secondsValue(now),
+			":ttl":    ttlValue(r.ttl),
+			":type":   stringValue(string(registry.InferWorkerType(workerID))),
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	if len(out.Attributes) == 0 {
+		// Worker not found, this shouldn't happen but we recovered
+		r.logger.Warn("heartbeat for unregistered worker, auto-registered",
+			zap.String("worker_id", workerID))
+	}
+
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	out, err := r.client.GetItem(ctx, &ddb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            workerKey(workerID),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+	if len(out.Item) == 0 {
+		return nil, fmt.Errorf("worker not found: %s", workerID)
+	}
+
+	worker, expiresAt, err := workerFromItem(out.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expired items may linger until DynamoDB deletes them
+	if time.Now().After(expiresAt) {
+		return nil, fmt.Errorf("worker not found: %s", workerID)
+	}
+
+	// Check if worker is healthy based on last heartbeat
+	if time.Since(worker.LastHeartbeat) > registry.WorkerTTL(worker, r.ttl) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return &worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria.
+// Type filters are served by the type index; otherwise the table is scanned.
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	now := time.Now()
+
+	var items []map[string]types.AttributeValue
+
+	if len(filter.Types) > 0 {
+		for _, workerType := range filter.Types {
+			input := r.buildQueryInput(workerType, now)
+			paginator := ddb.NewQueryPaginator(r.client, input)
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list workers: %w", err)
+				}
+				items = append(items, page.Items...)
+			}
+		}
+	} else {
+		paginator := ddb.NewScanPaginator(r.client, r.buildScanInput(now))
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list workers: %w", err)
+			}
+			items = append(items, page.Items...)
+		}
+	}
+
+	var workers []ports.WorkerInfo
+
+	for _, item := range items {
+		worker, _, err := workerFromItem(item)
+		if err != nil {
+			r.logger.Warn("failed to decode worker", zap.Error(err))
+			continue
+		}
+
+		// Check if worker is healthy
+		isHealthy := now.Sub(worker.LastHeartbeat) <= registry.WorkerTTL(worker, r.ttl)
+		if !isHealthy {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		// Apply filters
+		if !registry.MatchesFilter(worker, filter, isHealthy) {
+			continue
+		}
+
+		workers = append(workers, worker)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout.
+// Each delete is conditional, so a worker that heartbeats during cleanup is kept.
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	cutoff := millisValue(time.Now().Add(-timeout))
+
+	paginator := ddb.NewScanPaginator(r.client, &ddb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String("last_heartbeat < :cutoff"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":cutoff": cutoff},
+	})
+
+	cleaned := 0
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return cleaned, fmt.Errorf("failed to list workers: %w", err)
+		}
+
+		for _, item := range page.Items {
+			worker, _, err := workerFromItem(item)
+			if err != nil {
+				continue
+			}
+
+			_, err = r.client.DeleteItem(ctx, &ddb.DeleteItemInput{
+				TableName:                 aws.String(r.tableName),
+				Key:                       workerKey(worker.ID),
+				ConditionExpression:       aws.String("last_heartbeat < :cutoff"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{":cutoff": cutoff},
+			})
+			if err != nil {
+				var condFailed *types.ConditionalCheckFailedException
+				if !errors.As(err, &condFailed) {
+					r.logger.Warn("failed to delete stale worker",
+						zap.String("worker_id", worker.ID),
+						zap.Error(err))
+				}
+				continue
+			}
+
+			r.logger.Info("cleaned up stale worker",
+				zap.String("worker_id", worker.ID),
+				zap.Duration("idle_time", time.Since(worker.LastHeartbeat)))
+			cleaned++
+		}
+	}
+
+	return cleaned, nil
+}
+
+// Helper methods
+
+// buildQueryInput queries the type index for one worker type, skipping expired
+// items. Health isn't part of the key condition, as workers may declare TTLs
+// longer than the registry's.
+func (r *Registry) buildQueryInput(workerType ports.WorkerType, now time.Time) *ddb.QueryInput {
+	values := map[string]types.AttributeValue{
+		":type": stringValue(string(workerType)),
+		":now":  secondsValue(now),
+	}
+
+	return &ddb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String(typeIndexName),
+		KeyConditionExpression:    aws.String("#type = :type"),
+		FilterExpression:          aws.String("expires_at > :now"),
+		ExpressionAttributeNames:  map[string]string{"#type": attrType},
+		ExpressionAttributeValues: values,
+	}
+}
+
+// buildScanInput scans the whole table, skipping expired items
+func (r *Registry) buildScanInput(now time.Time) *ddb.ScanInput {
+	return &ddb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String("expires_at > :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":now": secondsValue(now)},
+	}
+}
+
+func workerKey(workerID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{attrWorkerID: stringValue(workerID)}
+}
+
+func workerToItem(worker ports.WorkerInfo, expiresAt time.Time) (map[string]types.AttributeValue, error) {
+	item := map[string]types.AttributeValue{
+		attrWorkerID:      stringValue(worker.ID),
+		attrStatus:        stringValue(string(worker.Status)),
+		attrCurrentTask:   stringValue(worker.CurrentTask),
+		attrRegisteredAt:  millisValue(worker.RegisteredAt),
+		attrLastHeartbeat: millisValue(worker.LastHeartbeat),
+		attrPendingTasks:  &types.AttributeValueMemberN{Value: strconv.Itoa(worker.PendingTasks)},
+		attrVersion:       stringValue(worker.Version),
+		attrExpiresAt:     secondsValue(expiresAt),
+	}
+
+	// Heartbeats extend the expiry by the worker's own TTL
+	if ttl := registry.WorkerTTL(worker, 0); ttl > 0 {
+		item[attrHeartbeatTTL] = ttlValue(ttl)
+	}
+
+	// Index key attributes can't be empty strings; typeless workers stay out of the index
+	if worker.Type != "" {
+		item[attrType] = stringValue(string(worker.Type))
+	}
+
+	if len(worker.Metadata) > 0 {
+		data, err := json.Marshal(worker.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal worker metadata: %w", err)
+		}
+		item[attrMetadata] = stringValue(string(data))
+	}
+
+	return item, nil
+}
+
+// workerFromItem decodes an item and returns the worker with its expiry time
+func workerFromItem(item map[string]types.AttributeValue) (ports.WorkerInfo, time.Time, error) {
+	worker := ports.WorkerInfo{
+		ID:          stringAttr(item, attrWorkerID),
+		Type:        ports.WorkerType(stringAttr(item, attrType)),
+		Status:      ports.WorkerStatus(stringAttr(item, attrStatus)),
+		CurrentTask: stringAttr(item, attrCurrentTask),
+		Version:     stringAttr(item, attrVersion),
+	}
+	if worker.ID == "" {
+		return worker, time.Time{}, fmt.Errorf("item has no %s attribute", attrWorkerID)
+	}
+
+	registeredAt, err := numberAttr(item, attrRegisteredAt)
+	if err != nil {
+		return worker, time.Time{}, err
+	}
+	lastHeartbeat, err := numberAttr(item, attrLastHeartbeat)
+	if err != nil {
+		return worker, time.Time{}, err
+	}
+	pendingTasks, err := numberAttr(item, attrPendingTasks)
+	if err != nil {
+		return worker, time.Time{}, err
+	}
+	expiresAt, err := numberAttr(item, attrExpiresAt)
+	if err != nil {
+		return worker, time.Time{}, err
+	}
+
+	worker.RegisteredAt = time.UnixMilli(registeredAt)
+	worker.LastHeartbeat = time.UnixMilli(lastHeartbeat)
+	worker.PendingTasks = int(pendingTasks)
+
+	if v := stringAttr(item, attrMetadata); v != "" {
+		if err := json.Unmarshal([]byte(v), &worker.Metadata); err != nil {
+			return worker, time.Time{}, fmt.Errorf("failed to unmarshal worker metadata: %w", err)
+		}
+	}
+
+	return worker, time.Unix(expiresAt, 0), nil
+}
+
+func stringValue(s string) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: s}
+}
+
+func millisValue(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.UnixMilli(), 10)}
+}
+
+func secondsValue(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.Unix(), 10)}
+}
+
+// ttlValue converts a TTL to whole seconds, rounding up, as expires_at holds
+// seconds
+func ttlValue(ttl time.Duration) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(int64(math.Ceil(ttl.Seconds())), 10)}
+}
+
+func stringAttr(item map[string]types.AttributeValue, name string) string {
+	if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	return ""
+}
+
+// numberAttr returns a numeric attribute, or 0 when it is missing
+func numberAttr(item map[string]types.AttributeValue, name string) (int64, error) {
+	v, ok := item[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s attribute: %w", name, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,170 @@
+package dynamodb
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+var _ Client = (*ddb.Client)(nil)
+
+func TestWorkerItemRoundTrip(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	worker := ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusBusy,
+		RegisteredAt:  now.Add(-time.Hour),
+		LastHeartbeat: now,
+		CurrentTask:   "task-1",
+		PendingTasks:  3,
+		Version:       "1.2.3",
+		Metadata:      map[string]interface{}{"zone": "eu-west-1a"},
+	}
+	expiresAt := now.Add(30 * time.Second).Truncate(time.Second)
+
+	item, err := workerToItem(worker, expiresAt)
+	if err != nil {
+		t.Fatalf("workerToItem() error = %v", err)
+	}
+
+	got, gotExpiresAt, err := workerFromItem(item)
+	if err != nil {
+		t.Fatalf("workerFromItem() error = %v", err)
+	}
+
+	if !got.RegisteredAt.Equal(worker.RegisteredAt) || !got.LastHeartbeat.Equal(worker.LastHeartbeat) {
+		t.Errorf("timestamps = %v/%v, want %v/%v",
+			got.RegisteredAt, got.LastHeartbeat, worker.RegisteredAt, worker.LastHeartbeat)
+	}
+	if !gotExpiresAt.Equal(expiresAt) {
+		t.Errorf("expiresAt = %v, want %v", gotExpiresAt, expiresAt)
+	}
+
+	got.RegisteredAt, got.LastHeartbeat = worker.RegisteredAt, worker.LastHeartbeat
+	if !reflect.DeepEqual(got, worker) {
+		t.Errorf("workerFromItem() = %+v, want %+v", got, worker)
+	}
+}
+
+func TestWorkerToItem_OmitsEmptyType(t *testing.T) {
+	item, err := workerToItem(ports.WorkerInfo{ID: "w-1"}, time.Now())
+	if err != nil {
+		t.Fatalf("workerToItem() error = %v", err)
+	}
+	if _, ok := item[attrType]; ok {
+		t.Errorf("item has %s attribute for typeless worker", attrType)
+	}
+}
+
+func TestWorkerFromItem_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		item map[string]types.AttributeValue
+	}{
+		{"missing id", map[string]types.AttributeValue{}},
+		{"bad number", map[string]types.AttributeValue{
+			attrWorkerID:     stringValue("w-1"),
+			attrPendingTasks: &types.AttributeValueMemberN{Value: "many"},
+		}},
+		{"bad metadata", map[string]types.AttributeValue{
+			attrWorkerID: stringValue("w-1"),
+			attrMetadata: stringValue("{"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := workerFromItem(tt.item); err == nil {
+				t.Error("workerFromItem() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestBuildQueryInput(t *testing.T) {
+	r := NewRegistryWithTTL(nil, 10*time.Second, zap.NewNop())
+	now := time.Unix(1700000000, 0)
+
+	input := r.buildQueryInput(ports.WorkerTypeRouter, now)
+	if got := aws.ToString(input.KeyConditionExpression); got != "#type = :type" {
+		t.Errorf("KeyConditionExpression = %q", got)
+	}
+	if got := aws.ToString(input.IndexName); got != typeIndexName {
+		t.Errorf("IndexName = %q, want %q", got, typeIndexName)
+	}
+	expiry, ok := input.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberN)
+	if !ok || expiry.Value != "1700000000" {
+		t.Errorf(":now = %+v, want 1700000000", input.ExpressionAttributeValues[":now"])
+	}
+}
+
+// Integration test - only runs with DYNAMODB_ENDPOINT environment variable
+// (for example a DynamoDB Local container at http://localhost:8000)
+func TestRegistry_Integration(t *testing.T) {
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_ENDPOINT not set, skipping integration test")
+	}
+
+	client := ddb.New(ddb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(endpoint),
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "local", SecretAccessKey: "local"}, nil
+		}),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	r := NewRegistryWithTTL(client, 5*time.Second, zap.NewNop())
+	if err := r.CreateTable(ctx); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	workerID := "executor-dynamodb-integration"
+	if err := r.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeExecutor || worker.Status != ports.WorkerStatusBusy {
+		t.Errorf("GetWorker() = %+v, want busy executor", worker)
+	}
+
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types:       []ports.WorkerType{ports.WorkerTypeExecutor},
+		HealthyOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	found := false
+	for _, w := range workers {
+		if w.ID == workerID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListWorkers() = %+v, want %s included", workers, workerID)
+	}
+
+	if err := r.Unregister(ctx, workerID); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	if _, err := r.GetWorker(ctx, workerID); err == nil {
+		t.Error("GetWorker() after Unregister error = nil, want not found")
+	}
+}
@@ -0,0 +1,33 @@
+// Package etcd provides an etcd implementation of the WorkerRegistry interface.
+//
+// This implementation gives stronger consistency for worker membership than
+// the Redis SCAN approach: every worker key is attached to its own etcd lease,
+// so expiry is enforced by the etcd cluster rather than by readers, and the
+// whole membership list is read with a single linearizable range request.
+//
+// Key Design:
+//   - Worker data is stored as JSON under key: /dago/workers/{worker_id}
+//   - Each key is bound to a lease whose TTL equals the registry TTL
+//   - Heartbeats keep the lease alive (KeepAliveOnce) and rewrite the value
+//     in a transaction on the key's mod revision, retried on conflict
+//   - Registering a worker again revokes the lease of its earlier
+//     registration
+//   - Watch streams membership changes using etcd's native watch API
+//
+// Usage:
+//
+//	client, _ := clientv3.New(clientv3.Config{Endpoints: []string{"localhost:2379"}})
+//	registry := etcd.NewRegistry(client, logger)
+//
+//	// Register a worker
+//	registry.Register(ctx, worker)
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+//
+//	// React to membership changes
+//	events, _ := registry.Watch(ctx)
+//	for event := range events {
+//	    log.Printf("%s %s", event.Type, event.WorkerID)
+//	}
+package etcd
@@ -0,0 +1,433 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default TTL for worker leases (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// Key prefix for worker data
+	workerKeyPrefix = "/dago/workers/"
+
+	// Attempts at Heartbeat's transaction before giving up, and the longest
+	// random wait between them
+	maxHeartbeatAttempts = 20
+	maxConflictBackoff   = 100 * time.Millisecond
+)
+
+// Registry implements ports.WorkerRegistry using etcd leases
+type Registry struct {
+	client *clientv3.Client
+	logger *zap.Logger
+	ttl    time.Duration
+}
+
+// NewRegistry creates a new etcd worker registry
+func NewRegistry(client *clientv3.Client, logger *zap.Logger) *Registry {
+	return &Registry{
+		client: client,
+		logger: logger,
+		ttl:    defaultWorkerTTL,
+	}
+}
+
+// NewRegistryWithTTL creates a new etcd worker registry with custom TTL
+func NewRegistryWithTTL(client *clientv3.Client, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		client: client,
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// Register registers a new worker in the system, bound to a fresh lease of
+// the worker's own TTL, if it declared one. The lease of an earlier
+// registration of the worker is revoked.
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	ttl := registry.WorkerTTL(worker, r.ttl)
+	lease, err := r.client.Grant(ctx, leaseTTL(ttl))
+	if err != nil {
+		return fmt.Errorf("failed to grant lease: %w", err)
+	}
+
+	data, err := json.Marshal(worker)
+	if err != nil {
+		r.revoke(ctx, lease.ID)
+		return fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+
+	resp, err := r.client.Put(ctx, r.getWorkerKey(worker.ID), string(data),
+		clientv3.WithLease(lease.ID), clientv3.WithPrevKV())
+	if err != nil {
+		r.revoke(ctx, lease.ID)
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	// The key is bound to the new lease now, so revoking the old one doesn't
+	// delete it
+	if resp.PrevKv != nil {
+		r.revoke(ctx, clientv3.LeaseID(resp.PrevKv.Lease))
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.Int64("lease_id", int64(lease.ID)),
+		zap.Duration("ttl", ttl))
+
+	return nil
+}
+
+// Unregister removes a worker from the registry by revoking its lease
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	if err := r.remove(ctx, workerID); err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat keeps the worker's lease alive and updates its status. The update
+// is a transaction conditional on the revision read, and retried if another
+// write got in between, so it neither overwrites a concurrent registration nor
+// brings back a worker that was just removed without noticing.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	backoff := 5 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		updated, err := r.heartbeat(ctx, workerID, status, currentTask)
+		if err != nil {
+			return fmt.Errorf("failed to update heartbeat: %w", err)
+		}
+		if updated {
+			return nil
+		}
+		if attempt == maxHeartbeatAttempts {
+			return fmt.Errorf("failed to update heartbeat: worker %s modified concurrently", workerID)
+		}
+
+		// Spread the writers that lost the race
+		timer := time.NewTimer(rand.N(backoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, maxConflictBackoff)
+	}
+}
+
+// heartbeat makes one attempt at Heartbeat, reporting false if the worker's
+// key changed since it was read
+func (r *Registry) heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) (bool, error) {
+	key := r.getWorkerKey(workerID)
+
+	resp, err := r.client.Get(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	var (
+		worker      ports.WorkerInfo
+		leaseID     clientv3.LeaseID
+		modRevision int64
+	)
+
+	if len(resp.Kvs) == 0 {
+		// Worker not found (lease expired or never registered), recover
+		r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+			zap.String("worker_id", workerID))
+
+		worker = ports.WorkerInfo{
+			ID:           workerID,
+			Type:         registry.InferWorkerType(workerID),
+			RegisteredAt: time.Now(),
+		}
+	} else {
+		if err := json.Unmarshal(resp.Kvs[0].Value, &worker); err != nil {
+			return false, fmt.Errorf("failed to unmarshal worker info: %w", err)
+		}
+		leaseID = clientv3.LeaseID(resp.Kvs[0].Lease)
+		modRevision = resp.Kvs[0].ModRevision
+	}
+
+	worker.Status = status
+	worker.LastHeartbeat = time.Now()
+	worker.CurrentTask = currentTask
+
+	data, err := json.Marshal(worker)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+
+	// Renew the existing lease; grant a new one if it has already expired
+	granted := false
+	if leaseID != clientv3.NoLease {
+		if _, err := r.client.KeepAliveOnce(ctx, leaseID); err != nil {
+			if !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+				return false, fmt.Errorf("failed to keep lease alive: %w", err)
+			}
+			leaseID = clientv3.NoLease
+		}
+	}
+	if leaseID == clientv3.NoLease {
+		lease, err := r.client.Grant(ctx, leaseTTL(registry.WorkerTTL(worker, r.ttl)))
+		if err != nil {
+			return false, fmt.Errorf("failed to grant lease: %w", err)
+		}
+		leaseID = lease.ID
+		granted = true
+	}
+
+	// A mod revision of 0 only matches a key that doesn't exist
+	txn, err := r.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", modRevision)).
+		Then(clientv3.OpPut(key, string(data), clientv3.WithLease(leaseID))).
+		Commit()
+	if err != nil || !txn.Succeeded {
+		if granted {
+			r.revoke(ctx, leaseID)
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to store worker: %w", err)
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	resp, err := r.client.Get(ctx, r.getWorkerKey(workerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("worker not found: %s", workerID)
+	}
+
+	var worker ports.WorkerInfo
+	if err := json.Unmarshal(resp.Kvs[0].Value, &worker); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal worker info: %w", err)
+	}
+
+	// Check if worker is healthy based on last heartbeat
+	if time.Since(worker.LastHeartbeat) > registry.WorkerTTL(worker, r.ttl) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return &worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	resp, err := r.client.Get(ctx, workerKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	var workers []ports.WorkerInfo
+
+	for _, kv := range resp.Kvs {
+		var worker ports.WorkerInfo
+		if err := json.Unmarshal(kv.Value, &worker); err != nil {
+			r.logger.Warn("failed to unmarshal worker",
+				zap.String("key", string(kv.Key)),
+				zap.Error(err))
+			continue
+		}
+
+		// Check if worker is healthy
+		isHealthy := time.Since(worker.LastHeartbeat) <= registry.WorkerTTL(worker, r.ttl)
+		if !isHealthy {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		// Apply filters
+		if !registry.MatchesFilter(worker, filter, isHealthy) {
+			continue
+		}
+
+		workers = append(workers, worker)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout.
+// Expired leases already remove workers automatically; this catches workers whose
+// lease is still alive but whose heartbeat is older than timeout.
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	resp, err := r.client.Get(ctx, workerKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	cleaned := 0
+
+	for _, kv := range resp.Kvs {
+		var worker ports.WorkerInfo
+		if err := json.Unmarshal(kv.Value, &worker); err != nil {
+			continue
+		}
+
+		// Check if worker is stale
+		if time.Since(worker.LastHeartbeat) > timeout {
+			if err := r.remove(ctx, worker.ID); err != nil {
+				r.logger.Warn("failed to delete stale worker",
+					zap.String("worker_id", worker.ID),
+					zap.Error(err))
+			} else {
+				r.logger.Info("cleaned up stale worker",
+					zap.String("worker_id", worker.ID),
+					zap.Duration("idle_time", time.Since(worker.LastHeartbeat)))
+				cleaned++
+			}
+		}
+	}
+
+	return cleaned, nil
+}
+
+// Watch streams worker membership changes until ctx is cancelled
+func (r *Registry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	watchChan := r.client.Watch(ctx, workerKeyPrefix, clientv3.WithPrefix())
+	events := make(chan registry.WatchEvent)
+
+	go func() {
+		defer close(events)
+
+		for resp := range watchChan {
+			if err := resp.Err(); err != nil {
+				r.logger.Warn("worker watch failed", zap.Error(err))
+				return
+			}
+
+			for _, ev := range resp.Events {
+				event := registry.WatchEvent{
+					WorkerID: strings.TrimPrefix(string(ev.Kv.Key), workerKeyPrefix),
+				}
+
+				switch ev.Type {
+				case clientv3.EventTypePut:
+					var worker ports.WorkerInfo
+					if err := json.Unmarshal(ev.Kv.Value, &worker); err != nil {
+						r.logger.Warn("failed to unmarshal watched worker",
+							zap.String("key", string(ev.Kv.Key)),
+							zap.Error(err))
+						continue
+					}
+					event.Type = registry.WatchEventPut
+					event.Worker = &worker
+				case clientv3.EventTypeDelete:
+					event.Type = registry.WatchEventDelete
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Helper methods
+
+func (r *Registry) getWorkerKey(workerID string) string {
+	return workerKeyPrefix + workerID
+}
+
+// leaseTTL converts a TTL to whole seconds, as required by etcd
+func leaseTTL(ttl time.Duration) int64 {
+	seconds := int64(math.Ceil(ttl.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// revoke revokes a lease that no longer holds the worker's key. Failures are
+// only logged: the lease expires on its own at the end of its TTL.
+func (r *Registry) revoke(ctx context.Context, leaseID clientv3.LeaseID) {
+	if leaseID == clientv3.NoLease {
+		return
+	}
+	if _, err := r.client.Revoke(ctx, leaseID); err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		r.logger.Warn("failed to revoke lease",
+			zap.Int64("lease_id", int64(leaseID)),
+			zap.Error(err))
+	}
+}
+
+// remove revokes the worker's lease (deleting the key) or deletes the key directly
+func (r *Registry) remove(ctx context.Context, workerID string) error {
+	key := r.getWorkerKey(workerID)
+
+	resp, err := r.client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil
+	}
+
+	if leaseID := clientv3.LeaseID(resp.Kvs[0].Lease); leaseID != clientv3.NoLease {
+		_, err := r.client.Revoke(ctx, leaseID)
+		if err == nil || !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return err
+		}
+	}
+
+	_, err = r.client.Delete(ctx, key)
+	return err
+}
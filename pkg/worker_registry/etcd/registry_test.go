@@ -0,0 +1,150 @@
+package etcd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/registrytest"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+func TestLeaseTTL(t *testing.T) {
+	tests := []struct {
+		ttl  time.Duration
+		want int64
+	}{
+		{30 * time.Second, 30},
+		{1500 * time.Millisecond, 2},
+		{100 * time.Millisecond, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ttl.String(), func(t *testing.T) {
+			if got := leaseTTL(tt.ttl); got != tt.want {
+				t.Errorf("leaseTTL(%s) = %d, want %d", tt.ttl, got, tt.want)
+			}
+		})
+	}
+}
+
+// Integration test - only runs with ETCD_ENDPOINTS environment variable
+func TestRegistry_Integration(t *testing.T) {
+	endpoints := os.Getenv("ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Skip("ETCD_ENDPOINTS not set, skipping integration test")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	r := NewRegistryWithTTL(client, 5*time.Second, zap.NewNop())
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	workerID := "executor-etcd-integration"
+	err = r.Register(ctx, ports.WorkerInfo{
+		ID:            workerID,
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  time.Now(),
+		LastHeartbeat: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	event := <-events
+	if event.Type != registry.WatchEventPut || event.WorkerID != workerID {
+		t.Errorf("Watch() event = %+v, want put for %s", event, workerID)
+	}
+
+	// Registering again moves the worker to a new lease and revokes the old one
+	first, err := client.Get(ctx, workerKeyPrefix+workerID)
+	if err != nil || len(first.Kvs) == 0 {
+		t.Fatalf("Get() = %v, %v, want the worker", first, err)
+	}
+	err = r.Register(ctx, ports.WorkerInfo{
+		ID:            workerID,
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  time.Now(),
+		LastHeartbeat: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Register() again error = %v", err)
+	}
+	<-events
+	lease, err := client.TimeToLive(ctx, clientv3.LeaseID(first.Kvs[0].Lease))
+	if err != nil {
+		t.Fatalf("TimeToLive() error = %v", err)
+	}
+	if lease.TTL != -1 {
+		t.Errorf("TTL of the first lease = %d, want -1 (revoked)", lease.TTL)
+	}
+
+	if err := r.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	<-events
+
+	worker, err := r.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusBusy || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want busy on task-1", worker)
+	}
+
+	if err := r.Unregister(ctx, workerID); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+
+	event = <-events
+	if event.Type != registry.WatchEventDelete || event.WorkerID != workerID {
+		t.Errorf("Watch() event = %+v, want delete for %s", event, workerID)
+	}
+}
+
+// Integration test - only runs with ETCD_ENDPOINTS environment variable
+func TestConformance(t *testing.T) {
+	endpoints := os.Getenv("ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Skip("ETCD_ENDPOINTS not set, skipping integration test")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	registrytest.RunConformance(t, registrytest.Harness{
+		New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+			if _, err := client.Delete(context.Background(), workerKeyPrefix, clientv3.WithPrefix()); err != nil {
+				t.Fatalf("Failed to clear workers: %v", err)
+			}
+			return NewRegistryWithTTL(client, ttl, zap.NewNop())
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package worker_registry
+
+import (
+	"strings"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// MatchesFilter reports whether a worker satisfies the filter criteria.
+// isHealthy is supplied by the caller since each backend decides health
+// differently (key TTL, lease, last_heartbeat column...).
+func MatchesFilter(worker ports.WorkerInfo, filter ports.WorkerFilter, isHealthy bool) bool {
+	// Filter by type
+	if len(filter.Types) > 0 {
+		typeMatch := false
+		for _, t := range filter.Types {
+			if worker.Type == t {
+				typeMatch = true
+				break
+			}
+		}
+		if !typeMatch {
+			return false
+		}
+	}
+
+	// Filter by status
+	if len(filter.Statuses) > 0 {
+		statusMatch := false
+		for _, s := range filter.Statuses {
+			if worker.Status == s {
+				statusMatch = true
+				break
+			}
+		}
+		if !statusMatch {
+			return false
+		}
+	}
+
+	// Filter by health
+	if filter.HealthyOnly && !isHealthy {
+		return false
+	}
+
+	return true
+}
+
+// InferWorkerType guesses a worker's type from its ID. It is used when a
+// heartbeat arrives for a worker that was never registered.
+func InferWorkerType(workerID string) ports.WorkerType {
+	if strings.Contains(workerID, "executor") {
+		return ports.WorkerTypeExecutor
+	}
+	if strings.Contains(workerID, "router") {
+		return ports.WorkerTypeRouter
+	}
+	// Default to executor if can't determine
+	return ports.WorkerTypeExecutor
+}
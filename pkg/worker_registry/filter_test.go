@@ -0,0 +1,80 @@
+package worker_registry
+
+import (
+	"testing"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+func TestMatchesFilter(t *testing.T) {
+	worker := ports.WorkerInfo{
+		ID:     "executor-1",
+		Type:   ports.WorkerTypeExecutor,
+		Status: ports.WorkerStatusBusy,
+	}
+
+	tests := []struct {
+		name      string
+		filter    ports.WorkerFilter
+		isHealthy bool
+		want      bool
+	}{
+		{
+			name:      "empty filter",
+			filter:    ports.WorkerFilter{},
+			isHealthy: true,
+			want:      true,
+		},
+		{
+			name:      "matching type",
+			filter:    ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeRouter, ports.WorkerTypeExecutor}},
+			isHealthy: true,
+			want:      true,
+		},
+		{
+			name:      "other type",
+			filter:    ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeRouter}},
+			isHealthy: true,
+			want:      false,
+		},
+		{
+			name:      "other status",
+			filter:    ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle}},
+			isHealthy: true,
+			want:      false,
+		},
+		{
+			name:      "healthy only with unhealthy worker",
+			filter:    ports.WorkerFilter{HealthyOnly: true},
+			isHealthy: false,
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesFilter(worker, tt.filter, tt.isHealthy); got != tt.want {
+				t.Errorf("MatchesFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferWorkerType(t *testing.T) {
+	tests := []struct {
+		workerID string
+		want     ports.WorkerType
+	}{
+		{"executor-1", ports.WorkerTypeExecutor},
+		{"router-abc", ports.WorkerTypeRouter},
+		{"worker-42", ports.WorkerTypeExecutor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.workerID, func(t *testing.T) {
+			if got := InferWorkerType(tt.workerID); got != tt.want {
+				t.Errorf("InferWorkerType(%s) = %s, want %s", tt.workerID, got, tt.want)
+			}
+		})
+	}
+}
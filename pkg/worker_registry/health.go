@@ -0,0 +1,310 @@
+package worker_registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// MetadataHealthEndpoint is the metadata key holding the URL a worker can be
+// probed on: http(s)://host:port/path for an HTTP check (any 2xx is healthy)
+// or grpc://host:port for the standard gRPC health service.
+const MetadataHealthEndpoint = "health_endpoint"
+
+const (
+	// Default timeout of a single probe
+	defaultProbeTimeout = 5 * time.Second
+
+	// Default consecutive failures before a worker is considered unhealthy
+	defaultFailureThreshold = 2
+
+	// Probes run concurrently per check round
+	maxConcurrentProbes = 16
+)
+
+// SetHealthEndpoint declares the endpoint HealthChecker probes the worker on.
+func SetHealthEndpoint(worker *ports.WorkerInfo, endpoint string) {
+	setMetadata(worker, MetadataHealthEndpoint, endpoint)
+}
+
+// HealthEndpoint returns the worker's declared health endpoint, if any.
+func HealthEndpoint(worker ports.WorkerInfo) string {
+	var endpoint string
+	decodeMetadata(worker, MetadataHealthEndpoint, &endpoint)
+	return endpoint
+}
+
+// Prober checks a single health endpoint.
+type Prober interface {
+	Probe(ctx context.Context, endpoint *url.URL) error
+}
+
+// HTTPProber probes with a GET request and expects a 2xx response.
+type HTTPProber struct {
+	Client *http.Client
+}
+
+// Probe implements Prober
+func (p HTTPProber) Probe(ctx context.Context, endpoint *url.URL) error {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("health endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// GRPCProber calls grpc.health.v1.Health/Check over plaintext and expects
+// SERVING. The URL path, if any, is used as the service name.
+type GRPCProber struct{}
+
+// Probe implements Prober
+func (GRPCProber) Probe(ctx context.Context, endpoint *url.URL) error {
+	conn, err := grpc.NewClient(endpoint.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	service := ""
+	if len(endpoint.Path) > 1 {
+		service = endpoint.Path[1:]
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health service reported %s", resp.Status)
+	}
+	return nil
+}
+
+// HealthChecker actively probes workers on their declared health endpoints,
+// catching wedged processes that still heartbeat. It wraps a registry: reads
+// through it report workers that failed their probes as unhealthy, and
+// HealthyOnly filters drop them. Writes pass through unchanged.
+//
+// Probe results are kept in memory rather than written to the registry, where
+// the worker's next heartbeat would overwrite them.
+type HealthChecker struct {
+	ports.WorkerRegistry
+
+	probers   map[string]Prober
+	timeout   time.Duration
+	threshold int
+	logger    *zap.Logger
+
+	mu       sync.RWMutex
+	failures map[string]int
+}
+
+// NewHealthChecker wraps registry with HTTP(S) and gRPC probing. A worker is
+// unhealthy after two consecutive failed probes.
+func NewHealthChecker(registry ports.WorkerRegistry, logger *zap.Logger) *HealthChecker {
+	return &HealthChecker{
+		WorkerRegistry: registry,
+		probers: map[string]Prober{
+			"http":  HTTPProber{},
+			"https": HTTPProber{},
+			"grpc":  GRPCProber{},
+		},
+		timeout:   defaultProbeTimeout,
+		threshold: defaultFailureThreshold,
+		logger:    logger,
+		failures:  make(map[string]int),
+	}
+}
+
+// SetProber registers the prober for a URL scheme, replacing any existing one.
+// Call it before the checker is in use.
+func (h *HealthChecker) SetProber(scheme string, prober Prober) {
+	h.probers[scheme] = prober
+}
+
+// Run probes all workers every interval until ctx is cancelled.
+func (h *HealthChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.CheckAll(ctx); err != nil && ctx.Err() == nil {
+			h.logger.Warn("worker health check failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll probes every registered worker that declares a health endpoint once.
+func (h *HealthChecker) CheckAll(ctx context.Context) error {
+	workers, err := h.WorkerRegistry.ListWorkers(ctx, ports.WorkerFilter{})
+	if err != nil {
+		return err
+	}
+
+	results := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentProbes)
+
+	for _, worker := range workers {
+		endpoint := HealthEndpoint(worker)
+		if endpoint == "" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(workerID, endpoint string) {
+			defer func() { <-sem; wg.Done() }()
+
+			err := h.probe(ctx, endpoint)
+			mu.Lock()
+			results[workerID] = err
+			mu.Unlock()
+		}(worker.ID, endpoint)
+	}
+	wg.Wait()
+
+	// Probes cut short by cancellation say nothing about the workers
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	h.record(results)
+	return nil
+}
+
+// IsHealthy reports whether the worker passes its probes. Workers without a
+// health endpoint, or not probed yet, are healthy.
+func (h *HealthChecker) IsHealthy(workerID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.failures[workerID] < h.threshold
+}
+
+// GetWorker retrieves a worker, marked unhealthy if it fails its probes
+func (h *HealthChecker) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	worker, err := h.WorkerRegistry.GetWorker(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !h.IsHealthy(workerID) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+	return worker, nil
+}
+
+// ListWorkers retrieves workers matching the filter, taking probe results into
+// account for both the status and HealthyOnly
+func (h *HealthChecker) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	// Status and health may change below, so only filter on type upstream
+	workers, err := h.WorkerRegistry.ListWorkers(ctx, ports.WorkerFilter{Types: filter.Types})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ports.WorkerInfo
+	for _, worker := range workers {
+		if !h.IsHealthy(worker.ID) {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		isHealthy := worker.Status != ports.WorkerStatusUnhealthy
+		if MatchesFilter(worker, filter, isHealthy) {
+			result = append(result, worker)
+		}
+	}
+
+	return result, nil
+}
+
+// GetWorkerStats returns aggregate statistics, counting workers that fail
+// their probes as unhealthy
+func (h *HealthChecker) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := h.ListWorkers(ctx, ports.WorkerFilter{Types: []ports.WorkerType{workerType}})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats, ok := ComputeWorkerStats(workers).ByType[workerType]; ok {
+		return stats, nil
+	}
+	return &ports.WorkerStats{Type: workerType}, nil
+}
+
+func (h *HealthChecker) probe(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid health endpoint: %w", err)
+	}
+
+	prober, ok := h.probers[u.Scheme]
+	if !ok {
+		return fmt.Errorf("no prober for health endpoint scheme %q", u.Scheme)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	return prober.Probe(ctx, u)
+}
+
+// record updates failure counts from a check round, forgetting workers that
+// weren't probed (gone, or no longer declaring an endpoint)
+func (h *HealthChecker) record(results map[string]error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	failures := make(map[string]int, len(results))
+	for workerID, err := range results {
+		previous := h.failures[workerID]
+
+		if err == nil {
+			if previous >= h.threshold {
+				h.logger.Info("worker health probe recovered", zap.String("worker_id", workerID))
+			}
+			continue
+		}
+
+		failures[workerID] = previous + 1
+		if failures[workerID] == h.threshold {
+			h.logger.Warn("worker health probe failed, marking unhealthy",
+				zap.String("worker_id", workerID),
+				zap.Int("consecutive_failures", failures[workerID]),
+				zap.Error(err))
+		}
+	}
+
+	h.failures = failures
+}
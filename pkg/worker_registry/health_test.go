@@ -0,0 +1,134 @@
+package worker_registry_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/memory"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthChecker(t *testing.T) {
+	ctx := context.Background()
+
+	var wedged atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wedged.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	grpcAddr, grpcHealth := startGRPCHealth(t)
+
+	inner := memory.NewRegistry(zap.NewNop())
+	for id, endpoint := range map[string]string{
+		"executor-http": srv.URL + "/healthz",
+		"executor-grpc": "grpc://" + grpcAddr,
+		"executor-none": "",
+	} {
+		worker := ports.WorkerInfo{
+			ID:            id,
+			Type:          ports.WorkerTypeExecutor,
+			Status:        ports.WorkerStatusIdle,
+			RegisteredAt:  time.Now(),
+			LastHeartbeat: time.Now(),
+		}
+		if endpoint != "" {
+			registry.SetHealthEndpoint(&worker, endpoint)
+		}
+		if err := inner.Register(ctx, worker); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	checker := registry.NewHealthChecker(inner, zap.NewNop())
+
+	healthyIDs := func() []string {
+		t.Helper()
+		workers, err := checker.ListWorkers(ctx, ports.WorkerFilter{HealthyOnly: true})
+		if err != nil {
+			t.Fatalf("ListWorkers() error = %v", err)
+		}
+		var ids []string
+		for _, w := range workers {
+			ids = append(ids, w.ID)
+		}
+		return ids
+	}
+
+	if err := checker.CheckAll(ctx); err != nil {
+		t.Fatalf("CheckAll() error = %v", err)
+	}
+	if ids := healthyIDs(); len(ids) != 3 {
+		t.Fatalf("healthy workers = %v, want all 3", ids)
+	}
+
+	// Wedge both; a single failure is tolerated, the second marks them unhealthy
+	wedged.Store(true)
+	grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	for round := 1; round <= 2; round++ {
+		if err := checker.CheckAll(ctx); err != nil {
+			t.Fatalf("CheckAll() error = %v", err)
+		}
+	}
+	if ids := healthyIDs(); len(ids) != 1 || ids[0] != "executor-none" {
+		t.Errorf("healthy workers = %v, want only executor-none", ids)
+	}
+
+	worker, err := checker.GetWorker(ctx, "executor-http")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusUnhealthy {
+		t.Errorf("GetWorker() status = %s, want unhealthy", worker.Status)
+	}
+
+	stats, err := checker.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	if stats.UnhealthyWorkers != 2 || stats.IdleWorkers != 1 {
+		t.Errorf("GetWorkerStats() = %+v, want 2 unhealthy and 1 idle", stats)
+	}
+
+	// Recovery takes a single successful probe
+	wedged.Store(false)
+	if err := checker.CheckAll(ctx); err != nil {
+		t.Fatalf("CheckAll() error = %v", err)
+	}
+	if !checker.IsHealthy("executor-http") {
+		t.Error("IsHealthy(executor-http) = false after recovery")
+	}
+}
+
+func startGRPCHealth(t *testing.T) (string, *health.Server) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String(), hs
+}
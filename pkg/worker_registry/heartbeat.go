@@ -0,0 +1,137 @@
+package worker_registry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// TTL assumed for workers that don't declare their own
+	defaultHeartbeatTTL = 30 * time.Second
+
+	// Default fraction of the interval added or removed at random, so that
+	// workers started together don't heartbeat in lockstep
+	defaultHeartbeatJitter = 0.1
+)
+
+// StatusFunc reports the worker's current status and task for each heartbeat.
+type StatusFunc func() (status ports.WorkerStatus, currentTask string)
+
+// HeartbeatRunner registers a worker and keeps it alive with periodic
+// heartbeats, so worker binaries don't each reimplement the loop.
+//
+// Heartbeats are jittered by ±10% unless set otherwise with SetJitter. When
+// the registry fails, retries back off exponentially from a quarter of the
+// interval up to the full interval.
+type HeartbeatRunner struct {
+	registry ports.WorkerRegistry
+	worker   ports.WorkerInfo
+	status   StatusFunc
+	interval time.Duration
+	jitter   float64
+	logger   *zap.Logger
+}
+
+// NewHeartbeatRunner creates a runner heartbeating at the worker's
+// HeartbeatInterval (a third of its TTL, 30s unless declared with SetTTL).
+func NewHeartbeatRunner(registry ports.WorkerRegistry, worker ports.WorkerInfo, status StatusFunc, logger *zap.Logger) *HeartbeatRunner {
+	return NewHeartbeatRunnerWithInterval(registry, worker, HeartbeatInterval(worker, defaultHeartbeatTTL), status, logger)
+}
+
+// NewHeartbeatRunnerWithInterval creates a runner with a custom heartbeat interval
+func NewHeartbeatRunnerWithInterval(registry ports.WorkerRegistry, worker ports.WorkerInfo, interval time.Duration, status StatusFunc, logger *zap.Logger) *HeartbeatRunner {
+	return &HeartbeatRunner{
+		registry: registry,
+		worker:   worker,
+		status:   status,
+		interval: interval,
+		jitter:   defaultHeartbeatJitter,
+		logger:   logger,
+	}
+}
+
+// SetJitter sets the fraction of the interval randomly added to or removed
+// from each wait, e.g. 0.2 for ±20%. Zero heartbeats at a fixed interval.
+// Call it before Run.
+func (h *HeartbeatRunner) SetJitter(jitter float64) {
+	h.jitter = jitter
+}
+
+// Run registers the worker and heartbeats until ctx is cancelled. It returns
+// nil once ctx is done; the worker is left registered so that callers can
+// drain it (see Shutdown) or let it expire.
+func (h *HeartbeatRunner) Run(ctx context.Context) error {
+	register := func(ctx context.Context) error {
+		worker := h.worker
+		worker.Status, worker.CurrentTask = h.status()
+		worker.RegisteredAt = time.Now()
+		worker.LastHeartbeat = worker.RegisteredAt
+		return h.registry.Register(ctx, worker)
+	}
+
+	heartbeat := func(ctx context.Context) error {
+		status, currentTask := h.status()
+		return h.registry.Heartbeat(ctx, h.worker.ID, status, currentTask)
+	}
+
+	if !h.retry(ctx, "register", register) {
+		return nil
+	}
+
+	for {
+		if !h.sleep(ctx, h.jittered(h.interval)) {
+			return nil
+		}
+		if !h.retry(ctx, "heartbeat", heartbeat) {
+			return nil
+		}
+	}
+}
+
+// retry runs op until it succeeds, backing off between failures. It returns
+// false if ctx was cancelled first.
+func (h *HeartbeatRunner) retry(ctx context.Context, name string, op func(context.Context) error) bool {
+	backoff := h.interval / 4
+
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		h.logger.Warn("worker heartbeat failed, retrying",
+			zap.String("worker_id", h.worker.ID),
+			zap.String("operation", name),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+
+		if !h.sleep(ctx, h.jittered(backoff)) {
+			return false
+		}
+		backoff = min(2*backoff, h.interval)
+	}
+}
+
+func (h *HeartbeatRunner) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (h *HeartbeatRunner) jittered(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (1 + h.jitter*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,73 @@
+package worker_registry_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/memory"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// flakyRegistry fails the first heartbeats it receives
+type flakyRegistry struct {
+	*memory.Registry
+	failures atomic.Int32
+}
+
+func (f *flakyRegistry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	if f.failures.Add(-1) >= 0 {
+		return errors.New("connection refused")
+	}
+	return f.Registry.Heartbeat(ctx, workerID, status, currentTask)
+}
+
+func TestHeartbeatRunner(t *testing.T) {
+	r := &flakyRegistry{Registry: memory.NewRegistry(zap.NewNop())}
+	r.failures.Store(2)
+
+	var calls atomic.Int32
+	status := func() (ports.WorkerStatus, string) {
+		if calls.Add(1) == 1 {
+			return ports.WorkerStatusIdle, ""
+		}
+		return ports.WorkerStatusBusy, "task-1"
+	}
+
+	worker := ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor}
+	runner := registry.NewHeartbeatRunnerWithInterval(r, worker, 10*time.Millisecond, status, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- runner.Run(ctx) }()
+
+	// Wait for a heartbeat to get through the failures
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, err := r.GetWorker(context.Background(), worker.ID)
+		if err == nil && got.Status == ports.WorkerStatusBusy && r.failures.Load() < 0 {
+			if got.CurrentTask != "task-1" {
+				t.Errorf("GetWorker() = %+v, want task-1", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("worker never heartbeated: %+v, %v", got, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not stop after cancel")
+	}
+}
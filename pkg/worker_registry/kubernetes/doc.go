@@ -0,0 +1,38 @@
+// Package kubernetes provides a Kubernetes-native implementation of the
+// WorkerRegistry interface backed by coordination.k8s.io/v1 Lease objects.
+//
+// Worker liveness piggybacks on the API server, so no extra datastore is
+// needed and workers show up in kubectl:
+//
+//	kubectl get leases -l app.kubernetes.io/managed-by=dago
+//
+// Key Design:
+//   - One Lease per worker, named after the worker ID, in the registry namespace
+//   - spec.renewTime is the last heartbeat, spec.leaseDurationSeconds the TTL
+//   - Worker type and status are labels, so WorkerFilter maps to label selectors
+//   - Current task, pending tasks, version and metadata are annotations
+//   - Watch streams membership changes using a Lease watch
+//
+// Kubernetes never deletes expired Leases on its own: a worker whose
+// renewTime is older than the TTL is reported as unhealthy until
+// CleanupStaleWorkers removes it. Worker IDs must be valid object names
+// (lowercase RFC 1123 subdomains).
+//
+// The service account needs get, list, watch, create, update and delete on
+// leases in the namespace.
+//
+// Usage:
+//
+//	config, _ := rest.InClusterConfig()
+//	clientset, _ := k8s.NewForConfig(config)
+//	registry := kubernetes.NewRegistry(clientset, "dago", logger)
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+//
+//	// List idle executors (served by a label selector)
+//	workers, _ := registry.ListWorkers(ctx, ports.WorkerFilter{
+//	    Types:    []ports.WorkerType{ports.WorkerTypeExecutor},
+//	    Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle},
+//	})
+package kubernetes
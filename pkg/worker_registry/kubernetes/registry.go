@@ -0,0 +1,494 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/watch"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// Default TTL for worker heartbeats (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// Labels set on every worker Lease
+	labelManagedBy    = "app.kubernetes.io/managed-by"
+	labelWorkerType   = "dago.io/worker-type"
+	labelWorkerStatus = "dago.io/worker-status"
+	managedByValue    = "dago"
+
+	// Annotations holding the remaining worker fields
+	annotationCurrentTask  = "dago.io/current-task"
+	annotationPendingTasks = "dago.io/pending-tasks"
+	annotationVersion      = "dago.io/version"
+	annotationMetadata     = "dago.io/metadata"
+)
+
+// Registry implements ports.WorkerRegistry using Kubernetes Lease objects
+type Registry struct {
+	client    k8s.Interface
+	namespace string
+	logger    *zap.Logger
+	ttl       time.Duration
+}
+
+// NewRegistry creates a new Kubernetes worker registry in the given namespace
+func NewRegistry(client k8s.Interface, namespace string, logger *zap.Logger) *Registry {
+	return &Registry{
+		client:    client,
+		namespace: namespace,
+		logger:    logger,
+		ttl:       defaultWorkerTTL,
+	}
+}
+
+// NewRegistryWithTTL creates a new Kubernetes worker registry with custom TTL
+func NewRegistryWithTTL(client k8s.Interface, namespace string, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		client:    client,
+		namespace: namespace,
+		logger:    logger,
+		ttl:       ttl,
+	}
+}
+
+// Register registers a new worker, creating or replacing its Lease
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, err := r.client.CoordinationV1().Leases(r.namespace).Get(ctx, worker.ID, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			lease, err := r.workerToLease(worker)
+			if err != nil {
+				return err
+			}
+			_, err = r.client.CoordinationV1().Leases(r.namespace).Create(ctx, lease, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		lease, err := r.workerToLease(worker)
+		if err != nil {
+			return err
+		}
+		lease.ResourceVersion = existing.ResourceVersion
+		_, err = r.client.CoordinationV1().Leases(r.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.String("namespace", r.namespace),
+		zap.Duration("ttl", registry.WorkerTTL(worker, r.ttl)))
+
+	return nil
+}
+
+// Unregister removes a worker's Lease
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	err := r.client.CoordinationV1().Leases(r.namespace).Delete(ctx, workerID, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat renews the worker's Lease and updates its status
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	leases := r.client.CoordinationV1().Leases(r.namespace)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lease, err := leases.Get(ctx, workerID, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// Worker not found, this shouldn't happen but we can recover
+			r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+				zap.String("worker_id", workerID))
+
+			now := time.Now()
+			lease, err = r.workerToLease(ports.WorkerInfo{
+				ID:            workerID,
+				Type:          registry.InferWorkerType(workerID),
+				Status:        status,
+				RegisteredAt:  now,
+				LastHeartbeat: now,
+				CurrentTask:   currentTask,
+			})
+			if err != nil {
+				return err
+			}
+			_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if lease.Labels == nil {
+			lease.Labels = map[string]string{}
+		}
+		if lease.Annotations == nil {
+			lease.Annotations = map[string]string{}
+		}
+		lease.Labels[labelWorkerStatus] = string(status)
+		lease.Annotations[annotationCurrentTask] = currentTask
+		worker, err := leaseToWorker(lease)
+		if err != nil {
+			return err
+		}
+		lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+		lease.Spec.LeaseDurationSeconds = leaseDurationSeconds(registry.WorkerTTL(worker, r.ttl))
+
+		_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	lease, err := r.client.CoordinationV1().Leases(r.namespace).Get(ctx, workerID, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("worker not found: %s", workerID)
+		}
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	worker, err := leaseToWorker(lease)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if worker is healthy based on last heartbeat
+	if time.Since(worker.LastHeartbeat) > registry.WorkerTTL(worker, r.ttl) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return &worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria.
+// Type and status filters are sent to the API server as label selectors.
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	selector, err := buildSelector(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker filter: %w", err)
+	}
+
+	list, err := r.client.CoordinationV1().Leases(r.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	var workers []ports.WorkerInfo
+
+	for i := range list.Items {
+		worker, err := leaseToWorker(&list.Items[i])
+		if err != nil {
+			r.logger.Warn("failed to decode worker",
+				zap.String("lease", list.Items[i].Name),
+				zap.Error(err))
+			continue
+		}
+
+		// Check if worker is healthy
+		isHealthy := time.Since(worker.LastHeartbeat) <= registry.WorkerTTL(worker, r.ttl)
+		if !isHealthy {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		// Apply filters (status must be re-checked against the derived status)
+		if !registry.MatchesFilter(worker, filter, isHealthy) {
+			continue
+		}
+
+		workers = append(workers, worker)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers deletes Leases whose last renewal is older than the timeout
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	leases := r.client.CoordinationV1().Leases(r.namespace)
+
+	list, err := leases.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{labelManagedBy: managedByValue}).String(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	cleaned := 0
+
+	for i := range list.Items {
+		lease := &list.Items[i]
+		worker, err := leaseToWorker(lease)
+		if err != nil {
+			continue
+		}
+
+		// Check if worker is stale
+		if time.Since(worker.LastHeartbeat) <= timeout {
+			continue
+		}
+
+		// Only delete the revision we inspected, so a concurrent heartbeat wins
+		err = leases.Delete(ctx, lease.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+		})
+		if err != nil {
+			if !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+				r.logger.Warn("failed to delete stale worker",
+					zap.String("worker_id", worker.ID),
+					zap.Error(err))
+			}
+			continue
+		}
+
+		r.logger.Info("cleaned up stale worker",
+			zap.String("worker_id", worker.ID),
+			zap.Duration("idle_time", time.Since(worker.LastHeartbeat)))
+		cleaned++
+	}
+
+	return cleaned, nil
+}
+
+// Watch streams worker membership changes until ctx is cancelled
+func (r *Registry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	watcher, err := r.client.CoordinationV1().Leases(r.namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{labelManagedBy: managedByValue}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch workers: %w", err)
+	}
+
+	events := make(chan registry.WatchEvent)
+
+	go func() {
+		defer close(events)
+		defer watcher.Stop()
+
+		for {
+			var ev watch.Event
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+				ev = e
+			}
+
+			lease, ok := ev.Object.(*coordinationv1.Lease)
+			if !ok {
+				if ev.Type == watch.Error {
+					r.logger.Warn("worker watch failed", zap.Any("status", ev.Object))
+					return
+				}
+				continue
+			}
+
+			event := registry.WatchEvent{WorkerID: lease.Name}
+
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				worker, err := leaseToWorker(lease)
+				if err != nil {
+					r.logger.Warn("failed to decode watched worker",
+						zap.String("lease", lease.Name),
+						zap.Error(err))
+					continue
+				}
+				event.Type = registry.WatchEventPut
+				event.Worker = &worker
+			case watch.Deleted:
+				event.Type = registry.WatchEventDelete
+			default:
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Helper methods
+
+// leaseDurationSeconds converts a worker's TTL to whole seconds for the Lease spec
+func leaseDurationSeconds(ttl time.Duration) *int32 {
+	seconds := int32(math.Ceil(ttl.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &seconds
+}
+
+func (r *Registry) workerToLease(worker ports.WorkerInfo) (*coordinationv1.Lease, error) {
+	annotations := map[string]string{
+		annotationCurrentTask:  worker.CurrentTask,
+		annotationPendingTasks: strconv.Itoa(worker.PendingTasks),
+		annotationVersion:      worker.Version,
+	}
+
+	if len(worker.Metadata) > 0 {
+		data, err := json.Marshal(worker.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal worker metadata: %w", err)
+		}
+		annotations[annotationMetadata] = string(data)
+	}
+
+	holder := worker.ID
+
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      worker.ID,
+			Namespace: r.namespace,
+			Labels: map[string]string{
+				labelManagedBy:    managedByValue,
+				labelWorkerType:   string(worker.Type),
+				labelWorkerStatus: string(worker.Status),
+			},
+			Annotations: annotations,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: leaseDurationSeconds(registry.WorkerTTL(worker, r.ttl)),
+			AcquireTime:          &metav1.MicroTime{Time: worker.RegisteredAt},
+			RenewTime:            &metav1.MicroTime{Time: worker.LastHeartbeat},
+		},
+	}, nil
+}
+
+func leaseToWorker(lease *coordinationv1.Lease) (ports.WorkerInfo, error) {
+	worker := ports.WorkerInfo{
+		ID:          lease.Name,
+		Type:        ports.WorkerType(lease.Labels[labelWorkerType]),
+		Status:      ports.WorkerStatus(lease.Labels[labelWorkerStatus]),
+		CurrentTask: lease.Annotations[annotationCurrentTask],
+		Version:     lease.Annotations[annotationVersion],
+	}
+
+	if lease.Spec.AcquireTime != nil {
+		worker.RegisteredAt = lease.Spec.AcquireTime.Time
+	}
+	if lease.Spec.RenewTime != nil {
+		worker.LastHeartbeat = lease.Spec.RenewTime.Time
+	}
+
+	if v := lease.Annotations[annotationPendingTasks]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return worker, fmt.Errorf("invalid %s annotation: %w", annotationPendingTasks, err)
+		}
+		worker.PendingTasks = n
+	}
+	if v := lease.Annotations[annotationMetadata]; v != "" {
+		if err := json.Unmarshal([]byte(v), &worker.Metadata); err != nil {
+			return worker, fmt.Errorf("failed to unmarshal worker metadata: %w", err)
+		}
+	}
+
+	return worker, nil
+}
+
+// buildSelector maps a WorkerFilter onto label selectors. The status selector
+// is skipped when "unhealthy" is requested, because staleness is derived from
+// renewTime rather than stored in a label.
+func buildSelector(filter ports.WorkerFilter) (labels.Selector, error) {
+	selector := labels.SelectorFromSet(labels.Set{labelManagedBy: managedByValue})
+
+	if len(filter.Types) > 0 {
+		values := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			values[i] = string(t)
+		}
+		req, err := labels.NewRequirement(labelWorkerType, selection.In, values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*req)
+	}
+
+	if len(filter.Statuses) > 0 {
+		values := make([]string, 0, len(filter.Statuses))
+		for _, s := range filter.Statuses {
+			if s == ports.WorkerStatusUnhealthy {
+				return selector, nil
+			}
+			values = append(values, string(s))
+		}
+		req, err := labels.NewRequirement(labelWorkerStatus, selection.In, values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*req)
+	}
+
+	return selector, nil
+}
@@ -0,0 +1,210 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testNamespace = "dago"
+
+func TestRegistry_RegisterAndGet(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry(fake.NewClientset(), testNamespace, zap.NewNop())
+
+	now := time.Now()
+	err := r.Register(ctx, ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  now,
+		LastHeartbeat: now,
+		PendingTasks:  4,
+		Version:       "1.0.0",
+		Metadata:      map[string]interface{}{"zone": "a"},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeExecutor || worker.Status != ports.WorkerStatusIdle {
+		t.Errorf("GetWorker() = %+v, want idle executor", worker)
+	}
+	if worker.PendingTasks != 4 || worker.Version != "1.0.0" || worker.Metadata["zone"] != "a" {
+		t.Errorf("GetWorker() annotations = %+v", worker)
+	}
+
+	lease, err := r.client.CoordinationV1().Leases(testNamespace).Get(ctx, "executor-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get lease error = %v", err)
+	}
+	if got := *lease.Spec.LeaseDurationSeconds; got != 30 {
+		t.Errorf("LeaseDurationSeconds = %d, want 30", got)
+	}
+
+	if _, err := r.GetWorker(ctx, "missing"); err == nil {
+		t.Error("GetWorker(missing) error = nil, want not found")
+	}
+}
+
+func TestRegistry_HeartbeatAutoRegisters(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry(fake.NewClientset(), testNamespace, zap.NewNop())
+
+	if err := r.Heartbeat(ctx, "router-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if err := r.Heartbeat(ctx, "router-1", ports.WorkerStatusBusy, "task-2"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, "router-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeRouter || worker.CurrentTask != "task-2" {
+		t.Errorf("GetWorker() = %+v, want router on task-2", worker)
+	}
+}
+
+func TestRegistry_ListAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistryWithTTL(fake.NewClientset(), testNamespace, 10*time.Second, zap.NewNop())
+
+	now := time.Now()
+	workers := []ports.WorkerInfo{
+		{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+		{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, LastHeartbeat: now, PendingTasks: 2},
+		{ID: "executor-3", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now.Add(-time.Minute)},
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+	}
+	for _, w := range workers {
+		if err := r.Register(ctx, w); err != nil {
+			t.Fatalf("Register(%s) error = %v", w.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter ports.WorkerFilter
+		want   int
+	}{
+		{"all", ports.WorkerFilter{}, 4},
+		{"executors", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}}, 3},
+		{"idle excludes stale", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle}}, 2},
+		{"unhealthy", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusUnhealthy}}, 1},
+		{"healthy only", ports.WorkerFilter{HealthyOnly: true}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.ListWorkers(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListWorkers() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ListWorkers() returned %d workers, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	stats, err := r.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	if stats.TotalWorkers != 3 || stats.IdleWorkers != 1 || stats.BusyWorkers != 1 ||
+		stats.UnhealthyWorkers != 1 || stats.TotalPendingTasks != 2 {
+		t.Errorf("GetWorkerStats() = %+v", stats)
+	}
+
+	cleaned, err := r.CleanupStaleWorkers(ctx, 10*time.Second)
+	if err != nil {
+		t.Fatalf("CleanupStaleWorkers() error = %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("CleanupStaleWorkers() = %d, want 1", cleaned)
+	}
+	if _, err := r.GetWorker(ctx, "executor-3"); err == nil {
+		t.Error("GetWorker(executor-3) after cleanup error = nil, want not found")
+	}
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRegistry(fake.NewClientset(), testNamespace, zap.NewNop())
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if err := r.Unregister(ctx, "executor-1"); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+
+	want := []registry.WatchEventType{registry.WatchEventPut, registry.WatchEventDelete}
+	for _, wantType := range want {
+		select {
+		case event := <-events:
+			if event.Type != wantType || event.WorkerID != "executor-1" {
+				t.Errorf("Watch() event = %+v, want %s for executor-1", event, wantType)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s event", wantType)
+		}
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestBuildSelector(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ports.WorkerFilter
+		want   string
+	}{
+		{"empty", ports.WorkerFilter{}, "app.kubernetes.io/managed-by=dago"},
+		{
+			"types and statuses",
+			ports.WorkerFilter{
+				Types:    []ports.WorkerType{ports.WorkerTypeExecutor},
+				Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle, ports.WorkerStatusBusy},
+			},
+			"app.kubernetes.io/managed-by=dago,dago.io/worker-status in (busy,idle),dago.io/worker-type in (executor)",
+		},
+		{
+			"unhealthy skips status selector",
+			ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle, ports.WorkerStatusUnhealthy}},
+			"app.kubernetes.io/managed-by=dago",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := buildSelector(tt.filter)
+			if err != nil {
+				t.Fatalf("buildSelector() error = %v", err)
+			}
+			if got := selector.String(); got != tt.want {
+				t.Errorf("buildSelector() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package worker_registry
+
+import (
+	"encoding/json"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+const (
+	// MetadataLabels is the metadata key holding a worker's labels
+	// (map of string to string, e.g. gpu=true, region=eu).
+	MetadataLabels = "labels"
+
+	// MetadataCapabilities is the metadata key holding a worker's
+	// multi-valued capabilities (e.g. models=[llama3.1, mistral]).
+	MetadataCapabilities = "capabilities"
+)
+
+// SetLabels stores labels in the worker's metadata, replacing any existing ones.
+func SetLabels(worker *ports.WorkerInfo, labels map[string]string) {
+	setMetadata(worker, MetadataLabels, labels)
+}
+
+// SetCapabilities stores capabilities in the worker's metadata, replacing any
+// existing ones.
+func SetCapabilities(worker *ports.WorkerInfo, capabilities map[string][]string) {
+	setMetadata(worker, MetadataCapabilities, capabilities)
+}
+
+// Labels returns the worker's labels. Labels that were stored by a backend and
+// decoded back as generic JSON values are converted; malformed entries yield nil.
+func Labels(worker ports.WorkerInfo) map[string]string {
+	var labels map[string]string
+	decodeMetadata(worker, MetadataLabels, &labels)
+	return labels
+}
+
+// Capabilities returns the worker's capabilities, converted like Labels.
+func Capabilities(worker ports.WorkerInfo) map[string][]string {
+	var capabilities map[string][]string
+	decodeMetadata(worker, MetadataCapabilities, &capabilities)
+	return capabilities
+}
+
+func setMetadata(worker *ports.WorkerInfo, key string, value interface{}) {
+	if worker.Metadata == nil {
+		worker.Metadata = make(map[string]interface{})
+	}
+	worker.Metadata[key] = value
+}
+
+// decodeMetadata converts a metadata value into out. Backends hand metadata
+// back in different shapes (the original Go types in memory, generic JSON or
+// BSON values elsewhere), so the value is normalized through JSON.
+func decodeMetadata(worker ports.WorkerInfo, key string, out interface{}) {
+	value, ok := worker.Metadata[key]
+	if !ok || value == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, out)
+}
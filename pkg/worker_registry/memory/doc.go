@@ -0,0 +1,21 @@
+// Package memory provides an in-memory implementation of the WorkerRegistry interface.
+//
+// This implementation keeps workers in a mutex-protected map and mimics the
+// Redis key TTL: every worker entry expires ttl after its last register or
+// heartbeat and is dropped lazily on the next read. It is intended for
+// single-process deployments, local development and unit tests of components
+// that depend on a ports.WorkerRegistry.
+//
+// Usage:
+//
+//	registry := memory.NewRegistry(logger)
+//
+//	// Register a worker
+//	registry.Register(ctx, worker)
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+//
+//	// List all healthy workers
+//	workers, _ := registry.ListWorkers(ctx, ports.WorkerFilter{HealthyOnly: true})
+package memory
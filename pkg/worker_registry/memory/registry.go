@@ -0,0 +1,315 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default TTL for worker heartbeats (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// Buffer size of each Watch channel
+	watchBufferSize = 64
+)
+
+// entry is a stored worker together with its expiry deadline
+type entry struct {
+	worker    ports.WorkerInfo
+	expiresAt time.Time
+}
+
+// Registry implements ports.WorkerRegistry in memory
+type Registry struct {
+	mu       sync.Mutex
+	workers  map[string]*entry
+	watchers map[chan registry.WatchEvent]struct{}
+	logger   *zap.Logger
+	ttl      time.Duration
+
+	// Fraction of random extra TTL, see SetTTLJitter
+	ttlJitter float64
+
+	// now is the clock, replaceable in tests
+	now func() time.Time
+}
+
+// NewRegistry creates a new in-memory worker registry
+func NewRegistry(logger *zap.Logger) *Registry {
+	return NewRegistryWithTTL(defaultWorkerTTL, logger)
+}
+
+// NewRegistryWithTTL creates a new in-memory worker registry with custom TTL
+func NewRegistryWithTTL(ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		workers:  make(map[string]*entry),
+		watchers: make(map[chan registry.WatchEvent]struct{}),
+		logger:   logger,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// SetTTLJitter extends each worker's expiry by a random fraction of its TTL,
+// up to jitter (0.1 for 10%), like the Redis registry. Call it before the
+// registry is in use.
+func (r *Registry) SetTTLJitter(jitter float64) {
+	r.ttlJitter = jitter
+}
+
+// Register registers a new worker in the system
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.put(worker)
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.Duration("ttl", registry.WorkerTTL(worker, r.ttl)))
+
+	return nil
+}
+
+// Unregister removes a worker from the registry
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(workerID)
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the last heartbeat timestamp for a worker
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.purgeExpired()
+
+	now := r.now()
+
+	var worker ports.WorkerInfo
+	if e, ok := r.workers[workerID]; ok {
+		worker = e.worker
+	} else {
+		// Worker not found, this shouldn't happen but we can recover
+		r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+			zap.String("worker_id", workerID))
+
+		worker = ports.WorkerInfo{
+			ID:           workerID,
+			Type:         registry.InferWorkerType(workerID),
+			RegisteredAt: now,
+		}
+	}
+
+	worker.Status = status
+	worker.LastHeartbeat = now
+	worker.CurrentTask = currentTask
+
+	r.put(worker)
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.purgeExpired()
+
+	e, ok := r.workers[workerID]
+	if !ok {
+		return nil, fmt.Errorf("worker not found: %s", workerID)
+	}
+
+	worker := copyWorker(e.worker)
+
+	// Check if worker is healthy based on last heartbeat
+	if r.now().Sub(worker.LastHeartbeat) > registry.WorkerTTL(worker, r.ttl) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return &worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria, ordered by ID
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.purgeExpired()
+
+	var workers []ports.WorkerInfo
+
+	for _, e := range r.workers {
+		worker := copyWorker(e.worker)
+
+		// Check if worker is healthy
+		isHealthy := r.now().Sub(worker.LastHeartbeat) <= registry.WorkerTTL(worker, r.ttl)
+		if !isHealthy {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		// Apply filters
+		if !registry.MatchesFilter(worker, filter, isHealthy) {
+			continue
+		}
+
+		workers = append(workers, worker)
+	}
+
+	sort.Slice(workers, func(i, j int) bool { return workers[i].ID < workers[j].ID })
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.purgeExpired()
+
+	cleaned := 0
+	now := r.now()
+
+	for id, e := range r.workers {
+		if now.Sub(e.worker.LastHeartbeat) > timeout {
+			r.remove(id)
+
+			r.logger.Info("cleaned up stale worker",
+				zap.String("worker_id", id),
+				zap.Duration("idle_time", now.Sub(e.worker.LastHeartbeat)))
+			cleaned++
+		}
+	}
+
+	return cleaned, nil
+}
+
+// Watch streams worker membership changes until ctx is cancelled.
+// Events are dropped for watchers that fall more than a buffer behind.
+func (r *Registry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	events := make(chan registry.WatchEvent, watchBufferSize)
+
+	r.mu.Lock()
+	r.watchers[events] = struct{}{}
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		r.mu.Lock()
+		delete(r.watchers, events)
+		close(events)
+		r.mu.Unlock()
+	}()
+
+	return events, nil
+}
+
+// Helper methods (callers must hold r.mu)
+
+func (r *Registry) put(worker ports.WorkerInfo) {
+	worker = copyWorker(worker)
+	expiresAt := r.now().Add(registry.JitteredTTL(registry.WorkerTTL(worker, r.ttl), r.ttlJitter))
+	registry.SetDeadline(&worker, expiresAt)
+
+	r.workers[worker.ID] = &entry{
+		worker:    worker,
+		expiresAt: expiresAt,
+	}
+
+	watched := copyWorker(worker)
+	r.notify(registry.WatchEvent{
+		Type:     registry.WatchEventPut,
+		WorkerID: worker.ID,
+		Worker:   &watched,
+	})
+}
+
+func (r *Registry) remove(workerID string) {
+	if _, ok := r.workers[workerID]; !ok {
+		return
+	}
+	delete(r.workers, workerID)
+
+	r.notify(registry.WatchEvent{
+		Type:     registry.WatchEventDelete,
+		WorkerID: workerID,
+	})
+}
+
+// purgeExpired drops entries whose TTL elapsed, like Redis key expiry
+func (r *Registry) purgeExpired() {
+	now := r.now()
+	for id, e := range r.workers {
+		if now.After(e.expiresAt) {
+			r.remove(id)
+		}
+	}
+}
+
+func (r *Registry) notify(event registry.WatchEvent) {
+	for watcher := range r.watchers {
+		select {
+		case watcher <- event:
+		default:
+			r.logger.Warn("dropping worker watch event for slow watcher",
+				zap.String("worker_id", event.WorkerID))
+		}
+	}
+}
+
+// copyWorker returns a copy that doesn't share the Metadata map with the stored entry
+func copyWorker(worker ports.WorkerInfo) ports.WorkerInfo {
+	if worker.Metadata != nil {
+		metadata := make(map[string]interface{}, len(worker.Metadata))
+		for k, v := range worker.Metadata {
+			metadata[k] = v
+		}
+		worker.Metadata = metadata
+	}
+	return worker
+}
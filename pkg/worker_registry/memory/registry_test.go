@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/registrytest"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+// newTestRegistry returns a registry with a manually advanced clock
+func newTestRegistry(ttl time.Duration) (*Registry, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRegistryWithTTL(ttl, zap.NewNop())
+	r.now = func() time.Time { return now }
+	return r, &now
+}
+
+func TestRegistry_RegisterAndGet(t *testing.T) {
+	ctx := context.Background()
+	r, now := newTestRegistry(30 * time.Second)
+
+	err := r.Register(ctx, ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		LastHeartbeat: *now,
+		Metadata:      map[string]interface{}{"gpu": true},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusIdle {
+		t.Errorf("GetWorker() status = %s, want idle", worker.Status)
+	}
+
+	// Mutating the returned worker must not affect the stored one
+	worker.Metadata["gpu"] = false
+	worker, _ = r.GetWorker(ctx, "executor-1")
+	if worker.Metadata["gpu"] != true {
+		t.Error("GetWorker() returned metadata shared with the registry")
+	}
+
+	if _, err := r.GetWorker(ctx, "missing"); err == nil {
+		t.Error("GetWorker() expected error for unknown worker")
+	}
+}
+
+func TestRegistry_HeartbeatAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	r, now := newTestRegistry(10 * time.Second)
+
+	// Heartbeat for an unknown worker auto-registers it
+	if err := r.Heartbeat(ctx, "router-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, "router-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeRouter || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want router on task-1", worker)
+	}
+
+	// Heartbeats renew the TTL
+	*now = now.Add(8 * time.Second)
+	if err := r.Heartbeat(ctx, "router-1", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	*now = now.Add(8 * time.Second)
+	if _, err := r.GetWorker(ctx, "router-1"); err != nil {
+		t.Errorf("GetWorker() error = %v, want worker kept alive by heartbeat", err)
+	}
+
+	// Without heartbeats the entry expires
+	*now = now.Add(11 * time.Second)
+	if _, err := r.GetWorker(ctx, "router-1"); err == nil {
+		t.Error("GetWorker() expected error after TTL expiry")
+	}
+}
+
+func TestRegistry_PerWorkerTTL(t *testing.T) {
+	ctx := context.Background()
+	r, now := newTestRegistry(10 * time.Second)
+
+	gpu := ports.WorkerInfo{
+		ID:            "executor-gpu",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  *now,
+		LastHeartbeat: *now,
+	}
+	registry.SetTTL(&gpu, time.Minute)
+
+	for _, worker := range []ports.WorkerInfo{
+		gpu,
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusIdle, LastHeartbeat: *now},
+	} {
+		if err := r.Register(ctx, worker); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	// Past the registry TTL only the worker with its own TTL is left, and healthy
+	*now = now.Add(30 * time.Second)
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{HealthyOnly: true})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if len(workers) != 1 || workers[0].ID != "executor-gpu" {
+		t.Errorf("ListWorkers() = %+v, want only executor-gpu", workers)
+	}
+
+	// Heartbeats keep the worker's own TTL
+	if err := r.Heartbeat(ctx, "executor-gpu", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	*now = now.Add(45 * time.Second)
+	if _, err := r.GetWorker(ctx, "executor-gpu"); err != nil {
+		t.Errorf("GetWorker() error = %v, want worker alive within its own TTL", err)
+	}
+}
+
+func TestRegistry_TTLJitter(t *testing.T) {
+	ctx := context.Background()
+	r, now := newTestRegistry(10 * time.Second)
+	r.SetTTLJitter(0.5)
+
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	deadline, ok := registry.Deadline(*worker)
+	if !ok {
+		t.Fatal("GetWorker() did not expose the deadline")
+	}
+	if deadline.Before(now.Add(10*time.Second)) || deadline.After(now.Add(15*time.Second)) {
+		t.Errorf("deadline = %v, want within [10s, 15s] from now", deadline.Sub(*now))
+	}
+
+	// The worker expires at its deadline, not at the registry TTL
+	*now = deadline.Add(-time.Millisecond)
+	if _, err := r.GetWorker(ctx, "executor-1"); err != nil {
+		t.Errorf("GetWorker() error = %v, want worker alive before its deadline", err)
+	}
+	*now = deadline.Add(time.Millisecond)
+	if _, err := r.GetWorker(ctx, "executor-1"); err == nil {
+		t.Error("GetWorker() expected error after the deadline")
+	}
+}
+
+func TestRegistry_ListWorkersAndStats(t *testing.T) {
+	ctx := context.Background()
+	r, now := newTestRegistry(30 * time.Second)
+
+	workers := []ports.WorkerInfo{
+		{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: *now, PendingTasks: 1},
+		{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, LastHeartbeat: *now, PendingTasks: 2},
+		{ID: "executor-3", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now.Add(-time.Minute)},
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusIdle, LastHeartbeat: *now},
+	}
+	for _, w := range workers {
+		if err := r.Register(ctx, w); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter ports.WorkerFilter
+		want   []string
+	}{
+		{"all", ports.WorkerFilter{}, []string{"executor-1", "executor-2", "executor-3", "router-1"}},
+		{"executors", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}}, []string{"executor-1", "executor-2", "executor-3"}},
+		{"idle", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle}}, []string{"executor-1", "router-1"}},
+		{"unhealthy", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusUnhealthy}}, []string{"executor-3"}},
+		{"healthy only", ports.WorkerFilter{HealthyOnly: true}, []string{"executor-1", "executor-2", "router-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.ListWorkers(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListWorkers() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListWorkers() returned %d workers, want %d", len(got), len(tt.want))
+			}
+			for i, w := range got {
+				if w.ID != tt.want[i] {
+					t.Errorf("ListWorkers()[%d] = %s, want %s", i, w.ID, tt.want[i])
+				}
+			}
+		})
+	}
+
+	stats, err := r.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	want := ports.WorkerStats{
+		Type:              ports.WorkerTypeExecutor,
+		TotalWorkers:      3,
+		IdleWorkers:       1,
+		BusyWorkers:       1,
+		UnhealthyWorkers:  1,
+		TotalPendingTasks: 3,
+	}
+	if *stats != want {
+		t.Errorf("GetWorkerStats() = %+v, want %+v", *stats, want)
+	}
+
+	cleaned, err := r.CleanupStaleWorkers(ctx, 30*time.Second)
+	if err != nil {
+		t.Fatalf("CleanupStaleWorkers() error = %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("CleanupStaleWorkers() = %d, want 1", cleaned)
+	}
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, _ := newTestRegistry(30 * time.Second)
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor})
+	_ = r.Unregister(ctx, "executor-1")
+
+	event := <-events
+	if event.Type != registry.WatchEventPut || event.WorkerID != "executor-1" || event.Worker == nil {
+		t.Errorf("Watch() first event = %+v, want put for executor-1", event)
+	}
+	event = <-events
+	if event.Type != registry.WatchEventDelete || event.WorkerID != "executor-1" {
+		t.Errorf("Watch() second event = %+v, want delete for executor-1", event)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestConformance(t *testing.T) {
+	var mu sync.Mutex
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock
+	}
+
+	registrytest.RunConformance(t, registrytest.Harness{
+		New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+			r := NewRegistryWithTTL(ttl, zap.NewNop())
+			r.now = now
+			return r
+		},
+		Now: now,
+		Sleep: func(d time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			clock = clock.Add(d)
+		},
+	})
+}
@@ -0,0 +1,33 @@
+// Package mongodb provides a MongoDB implementation of the WorkerRegistry interface.
+//
+// This implementation is intended for shops standardized on MongoDB. Each
+// worker is one document in the dago_workers collection, keyed by worker ID.
+//
+// Key Design:
+//   - A TTL index on expires_at removes workers their TTL (their own, set
+//     with worker_registry.SetTTL, or the registry's) after their last
+//     heartbeat, like Redis key expiry
+//   - Heartbeats are a single upsert, an update pipeline computing expires_at
+//   - ListWorkers filtering (type, status, health) is done in the query
+//   - Indexes are created with EnsureIndexes
+//
+// MongoDB's TTL monitor runs about once a minute, so documents can outlive
+// their TTL briefly. Reads compute health from last_heartbeat, so such
+// workers are reported as unhealthy until they are removed.
+//
+// Usage:
+//
+//	client, _ := mongo.Connect(options.Client().ApplyURI("mongodb://localhost:27017"))
+//	registry := mongodb.NewRegistry(client.Database("dago"), logger)
+//
+//	// Create the TTL and type indexes
+//	if err := registry.EnsureIndexes(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+//
+//	// List all healthy workers
+//	workers, _ := registry.ListWorkers(ctx, ports.WorkerFilter{HealthyOnly: true})
+package mongodb
@@ -0,0 +1,389 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default TTL for worker heartbeats (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// Collection holding worker documents
+	defaultCollection = "dago_workers"
+
+	// Index names
+	ttlIndexName  = "expires_at_ttl"
+	typeIndexName = "type"
+
+	// TTL index of earlier versions, expiring all workers a fixed time after
+	// their last heartbeat
+	legacyTTLIndexName = "last_heartbeat_ttl"
+
+	// Server error code returned when dropping an index that doesn't exist
+	codeIndexNotFound = 27
+)
+
+// workerDocument is the stored form of ports.WorkerInfo
+type workerDocument struct {
+	ID            string    `bson:"_id"`
+	Type          string    `bson:"type"`
+	Status        string    `bson:"status"`
+	RegisteredAt  time.Time `bson:"registered_at"`
+	LastHeartbeat time.Time `bson:"last_heartbeat"`
+	CurrentTask   string    `bson:"current_task"`
+	PendingTasks  int       `bson:"pending_tasks"`
+	Version       string    `bson:"version"`
+	Metadata      bson.Raw  `bson:"metadata,omitempty"`
+
+	// ExpiresAt is the last heartbeat plus the worker's TTL, for the TTL
+	// index
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// Registry implements ports.WorkerRegistry using a MongoDB collection
+type Registry struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+	ttl        time.Duration
+}
+
+// NewRegistry creates a new MongoDB worker registry
+func NewRegistry(db *mongo.Database, logger *zap.Logger) *Registry {
+	return &Registry{
+		collection: db.Collection(defaultCollection),
+		logger:     logger,
+		ttl:        defaultWorkerTTL,
+	}
+}
+
+// NewRegistryWithTTL creates a new MongoDB worker registry with custom TTL
+func NewRegistryWithTTL(db *mongo.Database, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		collection: db.Collection(defaultCollection),
+		logger:     logger,
+		ttl:        ttl,
+	}
+}
+
+// EnsureIndexes creates the TTL index on expires_at and the type index. The
+// TTL index on last_heartbeat of earlier versions, which expired every worker
+// after the registry TTL, is dropped.
+func (r *Registry) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName(ttlIndexName).SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create TTL index: %w", err)
+	}
+
+	if err := r.collection.Indexes().DropOne(ctx, legacyTTLIndexName); err != nil {
+		var serverErr mongo.ServerError
+		if !errors.As(err, &serverErr) || !serverErr.HasErrorCode(codeIndexNotFound) {
+			return fmt.Errorf("failed to drop legacy TTL index: %w", err)
+		}
+	}
+
+	_, err = r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "type", Value: 1}, {Key: "last_heartbeat", Value: 1}},
+		Options: options.Index().SetName(typeIndexName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create type index: %w", err)
+	}
+
+	return nil
+}
+
+// Register registers a new worker in the system
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	ttl := registry.WorkerTTL(worker, r.ttl)
+	doc, err := workerToDocument(worker, ttl)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.ReplaceOne(ctx, bson.D{{Key: "_id", Value: worker.ID}}, doc,
+		options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.Duration("ttl", ttl))
+
+	return nil
+}
+
+// Unregister removes a worker from the registry
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: workerID}}); err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the last heartbeat timestamp for a worker and extends its
+// expiry, by its own TTL if it declared one. Unknown workers are created by the
+// same upsert, an update pipeline, with their type inferred from the ID.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	now := time.Now()
+
+	// The worker's own TTL, or the registry's
+	declared := "$metadata." + registry.MetadataTTL
+	ttl := bson.D{{Key: "$cond", Value: bson.A{
+		bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "$isNumber", Value: declared}},
+			bson.D{{Key: "$gt", Value: bson.A{declared, 0}}},
+		}}},
+		declared,
+		r.ttl.Milliseconds(),
+	}}}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: workerID}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: bson.D{{Key: "$literal", Value: string(status)}}},
+			{Key: "last_heartbeat", Value: now},
+			{Key: "current_task", Value: bson.D{{Key: "$literal", Value: currentTask}}},
+			{Key: "expires_at", Value: bson.D{{Key: "$add", Value: bson.A{now, ttl}}}},
+			{Key: "type", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$type", string(registry.InferWorkerType(workerID))}}}},
+			{Key: "registered_at", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$registered_at", now}}}},
+		}}}},
+		options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	if result.UpsertedCount > 0 {
+		r.logger.Warn("heartbeat for unregistered worker, auto-registered",
+			zap.String("worker_id", workerID))
+	}
+
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	var doc workerDocument
+	err := r.collection.FindOne(ctx, bson.D{{Key: "_id", Value: workerID}}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("worker not found: %s", workerID)
+		}
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	worker, err := workerFromDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if worker is healthy based on last heartbeat
+	if time.Since(worker.LastHeartbeat) > registry.WorkerTTL(worker, r.ttl) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return &worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria, ordered by ID
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	now := time.Now()
+
+	cursor, err := r.collection.Find(ctx, buildListFilter(filter, now),
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var workers []ports.WorkerInfo
+
+	for cursor.Next(ctx) {
+		var doc workerDocument
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("failed to decode worker", zap.Error(err))
+			continue
+		}
+
+		worker, err := workerFromDocument(doc)
+		if err != nil {
+			r.logger.Warn("failed to decode worker",
+				zap.String("worker_id", doc.ID),
+				zap.Error(err))
+			continue
+		}
+
+		if now.Sub(worker.LastHeartbeat) > registry.WorkerTTL(worker, r.ttl) {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		workers = append(workers, worker)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout.
+// The TTL index already does this for the workers' TTLs; this allows a shorter timeout.
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.D{
+		{Key: "last_heartbeat", Value: bson.D{{Key: "$lt", Value: time.Now().Add(-timeout)}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup stale workers: %w", err)
+	}
+
+	if result.DeletedCount > 0 {
+		r.logger.Info("cleaned up stale workers",
+			zap.Int64("count", result.DeletedCount),
+			zap.Duration("timeout", timeout))
+	}
+
+	return int(result.DeletedCount), nil
+}
+
+// Helper methods
+
+// buildListFilter translates a WorkerFilter into a query. Statuses are matched
+// against the derived status, so stale workers, expiring before now, match
+// "unhealthy".
+func buildListFilter(filter ports.WorkerFilter, now time.Time) bson.D {
+	query := bson.D{}
+
+	// Filter by type
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		query = append(query, bson.E{Key: "type", Value: bson.D{{Key: "$in", Value: types}}})
+	}
+
+	// Filter by status
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		matchStale := false
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+			if s == ports.WorkerStatusUnhealthy {
+				matchStale = true
+			}
+		}
+
+		or := bson.A{bson.D{
+			{Key: "status", Value: bson.D{{Key: "$in", Value: statuses}}},
+			{Key: "expires_at", Value: bson.D{{Key: "$gte", Value: now}}},
+		}}
+		if matchStale {
+			or = append(or, bson.D{{Key: "expires_at", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$gte", Value: now}}}}}})
+		}
+		query = append(query, bson.E{Key: "$or", Value: or})
+	}
+
+	// Filter by health
+	if filter.HealthyOnly {
+		query = append(query, bson.E{Key: "expires_at", Value: bson.D{{Key: "$gte", Value: now}}})
+	}
+
+	return query
+}
+
+// workerToDocument converts a worker expiring ttl after its last heartbeat to
+// its stored form
+func workerToDocument(worker ports.WorkerInfo, ttl time.Duration) (workerDocument, error) {
+	doc := workerDocument{
+		ID:            worker.ID,
+		Type:          string(worker.Type),
+		Status:        string(worker.Status),
+		RegisteredAt:  worker.RegisteredAt,
+		LastHeartbeat: worker.LastHeartbeat,
+		CurrentTask:   worker.CurrentTask,
+		PendingTasks:  worker.PendingTasks,
+		Version:       worker.Version,
+		ExpiresAt:     worker.LastHeartbeat.Add(ttl),
+	}
+
+	if len(worker.Metadata) > 0 {
+		data, err := bson.Marshal(worker.Metadata)
+		if err != nil {
+			return doc, fmt.Errorf("failed to marshal worker metadata: %w", err)
+		}
+		doc.Metadata = data
+	}
+
+	return doc, nil
+}
+
+// workerFromDocument converts a stored document back to WorkerInfo.
+// Metadata is decoded into plain maps so it matches what other backends return.
+func workerFromDocument(doc workerDocument) (ports.WorkerInfo, error) {
+	worker := ports.WorkerInfo{
+		ID:            doc.ID,
+		Type:          ports.WorkerType(doc.Type),
+		Status:        ports.WorkerStatus(doc.Status),
+		RegisteredAt:  doc.RegisteredAt,
+		LastHeartbeat: doc.LastHeartbeat,
+		CurrentTask:   doc.CurrentTask,
+		PendingTasks:  doc.PendingTasks,
+		Version:       doc.Version,
+	}
+
+	if len(doc.Metadata) > 0 {
+		dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(doc.Metadata)))
+		dec.DefaultDocumentMap()
+		if err := dec.Decode(&worker.Metadata); err != nil {
+			return worker, fmt.Errorf("failed to unmarshal worker metadata: %w", err)
+		}
+	}
+
+	return worker, nil
+}
@@ -0,0 +1,162 @@
+package mongodb
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.uber.org/zap"
+)
+
+func TestBuildListFilter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	fresh := bson.D{{Key: "$gte", Value: now}}
+
+	tests := []struct {
+		name   string
+		filter ports.WorkerFilter
+		want   bson.D
+	}{
+		{
+			name:   "no filter",
+			filter: ports.WorkerFilter{},
+			want:   bson.D{},
+		},
+		{
+			name: "types and healthy only",
+			filter: ports.WorkerFilter{
+				Types:       []ports.WorkerType{ports.WorkerTypeExecutor},
+				HealthyOnly: true,
+			},
+			want: bson.D{
+				{Key: "type", Value: bson.D{{Key: "$in", Value: []string{"executor"}}}},
+				{Key: "expires_at", Value: fresh},
+			},
+		},
+		{
+			name:   "busy",
+			filter: ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusBusy}},
+			want: bson.D{
+				{Key: "$or", Value: bson.A{bson.D{
+					{Key: "status", Value: bson.D{{Key: "$in", Value: []string{"busy"}}}},
+					{Key: "expires_at", Value: fresh},
+				}}},
+			},
+		},
+		{
+			name:   "unhealthy matches stale workers",
+			filter: ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusUnhealthy}},
+			want: bson.D{
+				{Key: "$or", Value: bson.A{
+					bson.D{
+						{Key: "status", Value: bson.D{{Key: "$in", Value: []string{"unhealthy"}}}},
+						{Key: "expires_at", Value: fresh},
+					},
+					bson.D{{Key: "expires_at", Value: bson.D{{Key: "$not", Value: fresh}}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildListFilter(tt.filter, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildListFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkerDocumentRoundTrip(t *testing.T) {
+	worker := ports.WorkerInfo{
+		ID:           "router-1",
+		Type:         ports.WorkerTypeRouter,
+		Status:       ports.WorkerStatusIdle,
+		PendingTasks: 2,
+		Metadata: map[string]interface{}{
+			"zone":   "a",
+			"limits": map[string]interface{}{"cpu": "2"},
+		},
+	}
+
+	doc, err := workerToDocument(worker, 30*time.Second)
+	if err != nil {
+		t.Fatalf("workerToDocument() error = %v", err)
+	}
+	if want := worker.LastHeartbeat.Add(30 * time.Second); !doc.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", doc.ExpiresAt, want)
+	}
+
+	got, err := workerFromDocument(doc)
+	if err != nil {
+		t.Fatalf("workerFromDocument() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, worker) {
+		t.Errorf("workerFromDocument() = %+v, want %+v", got, worker)
+	}
+}
+
+// Integration test - only runs with MONGODB_URI environment variable
+func TestRegistry_Integration(t *testing.T) {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI not set, skipping integration test")
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	defer func() { _ = client.Disconnect(ctx) }()
+
+	r := NewRegistryWithTTL(client.Database("dago_test"), 5*time.Second, zap.NewNop())
+	if err := r.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("EnsureIndexes() error = %v", err)
+	}
+
+	workerID := "executor-mongodb-integration"
+	if err := r.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeExecutor || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want executor on task-1", worker)
+	}
+
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Statuses:    []ports.WorkerStatus{ports.WorkerStatusBusy},
+		HealthyOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	found := false
+	for _, w := range workers {
+		if w.ID == workerID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListWorkers() = %+v, want %s included", workers, workerID)
+	}
+
+	if err := r.Unregister(ctx, workerID); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	if _, err := r.GetWorker(ctx, workerID); err == nil {
+		t.Error("GetWorker() after Unregister error = nil, want not found")
+	}
+}
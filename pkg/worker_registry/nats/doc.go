@@ -0,0 +1,33 @@
+// Package nats provides a NATS JetStream Key-Value implementation of the
+// WorkerRegistry interface.
+//
+// This implementation is meant for deployments whose task transport is
+// already NATS and who want to drop the Redis dependency. Workers are stored
+// in a JetStream KV bucket with history 1, each write carrying a per-message
+// TTL: the worker's own (worker_registry.SetTTL) or the registry's. Every
+// heartbeat is a new revision and restarts the key's expiry, just like
+// renewing a Redis key TTL. Per-message TTLs need NATS server 2.11 or later.
+//
+// Key Design:
+//   - Worker data is stored as JSON in bucket "dago_workers", under the
+//     unpadded base64url encoding of the worker ID, as KV keys only allow
+//     [-/_=.a-zA-Z0-9]
+//   - Heartbeats write at the revision they read, retrying on conflict, so
+//     concurrent writes aren't lost
+//   - ListWorkers reads every key with a single watch instead of N GETs
+//   - Watch streams membership changes using a KV watcher; expired keys leave
+//     a marker (the bucket's LimitMarkerTTL), reported as a delete
+//
+// Usage:
+//
+//	nc, _ := nats.Connect(nats.DefaultURL)
+//	js, _ := jetstream.New(nc)
+//	kv, _ := natsregistry.EnsureBucket(ctx, js, 30*time.Second)
+//	registry := natsregistry.NewRegistry(js, kv, logger)
+//
+//	// Register a worker
+//	registry.Register(ctx, worker)
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+package nats
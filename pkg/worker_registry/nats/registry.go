@@ -0,0 +1,411 @@
+package nats
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default TTL for worker heartbeats (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// Name of the KV bucket holding worker data
+	defaultBucket = "dago_workers"
+
+	// Attempts at Heartbeat's read-modify-write before giving up, and the
+	// longest random wait between them
+	maxHeartbeatAttempts = 20
+	maxConflictBackoff   = 100 * time.Millisecond
+)
+
+// Worker IDs are encoded in keys, as KV keys only allow [-/_=.a-zA-Z0-9]
+var keyEncoding = base64.RawURLEncoding
+
+// Registry implements ports.WorkerRegistry using a JetStream KV bucket
+type Registry struct {
+	js     jetstream.JetStream
+	kv     jetstream.KeyValue
+	logger *zap.Logger
+	ttl    time.Duration
+}
+
+// EnsureBucket creates the worker bucket, or updates it if it already exists.
+// Keys expire by per-message TTLs, so the bucket has none of its own; it keeps
+// a marker for markerTTL when a key expires, which Watch reports as a delete.
+// Requires NATS server 2.11 or later.
+func EnsureBucket(ctx context.Context, js jetstream.JetStream, markerTTL time.Duration) (jetstream.KeyValue, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:         defaultBucket,
+		Description:    "dago worker registry",
+		History:        1,
+		LimitMarkerTTL: markerTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worker bucket: %w", err)
+	}
+	return kv, nil
+}
+
+// NewRegistry creates a new NATS KV worker registry on a bucket created by
+// EnsureBucket
+func NewRegistry(js jetstream.JetStream, kv jetstream.KeyValue, logger *zap.Logger) *Registry {
+	return NewRegistryWithTTL(js, kv, defaultWorkerTTL, logger)
+}
+
+// NewRegistryWithTTL creates a new NATS KV worker registry with custom TTL
+func NewRegistryWithTTL(js jetstream.JetStream, kv jetstream.KeyValue, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		js:     js,
+		kv:     kv,
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// Register registers a new worker in the system
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	if err := r.put(ctx, worker); err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.Duration("ttl", registry.WorkerTTL(worker, r.ttl)))
+
+	return nil
+}
+
+// Unregister removes a worker from the registry
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	if err := r.kv.Delete(ctx, workerKey(workerID)); err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the last heartbeat timestamp for a worker, restarting its
+// key expiry. The update is conditional on the revision read, and retried if
+// another write got in between, so concurrent writes aren't lost.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	var err error
+	backoff := 5 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err = r.heartbeat(ctx, workerID, status, currentTask)
+		if !errors.Is(err, jetstream.ErrKeyExists) || attempt == maxHeartbeatAttempts {
+			break
+		}
+
+		// Spread the writers that lost the race
+		timer := time.NewTimer(rand.N(backoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, maxConflictBackoff)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+	return nil
+}
+
+func (r *Registry) heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	worker, revision, err := r.get(ctx, workerID)
+	if err != nil {
+		if !errors.Is(err, jetstream.ErrKeyNotFound) {
+			return err
+		}
+
+		// Worker not found, this shouldn't happen but we can recover
+		r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+			zap.String("worker_id", workerID))
+
+		worker = &ports.WorkerInfo{
+			ID:           workerID,
+			Type:         registry.InferWorkerType(workerID),
+			RegisteredAt: time.Now(),
+		}
+	}
+
+	worker.Status = status
+	worker.LastHeartbeat = time.Now()
+	worker.CurrentTask = currentTask
+
+	data, err := json.Marshal(worker)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+	ttl := registry.WorkerTTL(*worker, r.ttl)
+
+	if revision == 0 {
+		// Create also replaces a deleted or expired key
+		_, err = r.kv.Create(ctx, workerKey(workerID), data, jetstream.KeyTTL(ttl))
+		return err
+	}
+	return r.publish(ctx, workerKey(workerID), data, ttl, jetstream.WithExpectLastSequencePerSubject(revision))
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	worker, _, err := r.get(ctx, workerID)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return nil, fmt.Errorf("worker not found: %s", workerID)
+		}
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	// Check if worker is healthy based on last heartbeat
+	if time.Since(worker.LastHeartbeat) > registry.WorkerTTL(*worker, r.ttl) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	all, err := r.listAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	var workers []ports.WorkerInfo
+
+	for _, worker := range all {
+		// Check if worker is healthy
+		isHealthy := time.Since(worker.LastHeartbeat) <= registry.WorkerTTL(worker, r.ttl)
+		if !isHealthy {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		// Apply filters
+		if !registry.MatchesFilter(worker, filter, isHealthy) {
+			continue
+		}
+
+		workers = append(workers, worker)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	all, err := r.listAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	cleaned := 0
+
+	for _, worker := range all {
+		// Check if worker is stale
+		if time.Since(worker.LastHeartbeat) > timeout {
+			if err := r.kv.Delete(ctx, workerKey(worker.ID)); err != nil {
+				r.logger.Warn("failed to delete stale worker",
+					zap.String("worker_id", worker.ID),
+					zap.Error(err))
+			} else {
+				r.logger.Info("cleaned up stale worker",
+					zap.String("worker_id", worker.ID),
+					zap.Duration("idle_time", time.Since(worker.LastHeartbeat)))
+				cleaned++
+			}
+		}
+	}
+
+	return cleaned, nil
+}
+
+// Watch streams worker membership changes until ctx is cancelled
+func (r *Registry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	watcher, err := r.kv.WatchAll(ctx, jetstream.UpdatesOnly())
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch workers: %w", err)
+	}
+
+	events := make(chan registry.WatchEvent)
+
+	go func() {
+		defer close(events)
+		defer func() { _ = watcher.Stop() }()
+
+		for {
+			var kve jetstream.KeyValueEntry
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-watcher.Updates():
+				if !ok {
+					return
+				}
+				kve = e
+			}
+			if kve == nil {
+				continue
+			}
+
+			workerID, err := keyWorkerID(kve.Key())
+			if err != nil {
+				r.logger.Warn("ignoring watched key that isn't a worker",
+					zap.String("key", kve.Key()),
+					zap.Error(err))
+				continue
+			}
+			event := registry.WatchEvent{WorkerID: workerID}
+
+			switch kve.Operation() {
+			case jetstream.KeyValuePut:
+				var worker ports.WorkerInfo
+				if err := json.Unmarshal(kve.Value(), &worker); err != nil {
+					r.logger.Warn("failed to unmarshal watched worker",
+						zap.String("key", kve.Key()),
+						zap.Error(err))
+					continue
+				}
+				event.Type = registry.WatchEventPut
+				event.Worker = &worker
+			default:
+				event.Type = registry.WatchEventDelete
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Helper methods
+
+// workerKey returns the KV key of a worker
+func workerKey(workerID string) string {
+	return keyEncoding.EncodeToString([]byte(workerID))
+}
+
+// keyWorkerID returns the worker ID a KV key encodes
+func keyWorkerID(key string) (string, error) {
+	id, err := keyEncoding.DecodeString(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid worker key %q: %w", key, err)
+	}
+	return string(id), nil
+}
+
+// put writes a worker unconditionally, expiring after its TTL
+func (r *Registry) put(ctx context.Context, worker ports.WorkerInfo) error {
+	data, err := json.Marshal(worker)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+
+	return r.publish(ctx, workerKey(worker.ID), data, registry.WorkerTTL(worker, r.ttl))
+}
+
+// publish writes a value to the bucket with a per-message TTL. KeyValue only
+// takes a TTL on Create, so updates are published to the key's subject.
+func (r *Registry) publish(ctx context.Context, key string, data []byte, ttl time.Duration, opts ...jetstream.PublishOpt) error {
+	msg := &nats.Msg{
+		Subject: "$KV." + r.kv.Bucket() + "." + key,
+		Data:    data,
+	}
+	_, err := r.js.PublishMsg(ctx, msg, append(opts, jetstream.WithMsgTTL(ttl))...)
+	return err
+}
+
+// get returns a worker and its revision
+func (r *Registry) get(ctx context.Context, workerID string) (*ports.WorkerInfo, uint64, error) {
+	kve, err := r.kv.Get(ctx, workerKey(workerID))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var worker ports.WorkerInfo
+	if err := json.Unmarshal(kve.Value(), &worker); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal worker info: %w", err)
+	}
+
+	return &worker, kve.Revision(), nil
+}
+
+// listAll reads the latest value of every key with a single watcher.
+// The watcher delivers a nil entry once all current values have been sent.
+func (r *Registry) listAll(ctx context.Context) ([]ports.WorkerInfo, error) {
+	watcher, err := r.kv.WatchAll(ctx, jetstream.IgnoreDeletes())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = watcher.Stop() }()
+
+	var workers []ports.WorkerInfo
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case kve, ok := <-watcher.Updates():
+			if !ok || kve == nil {
+				return workers, nil
+			}
+
+			var worker ports.WorkerInfo
+			if err := json.Unmarshal(kve.Value(), &worker); err != nil {
+				r.logger.Warn("failed to unmarshal worker",
+					zap.String("key", kve.Key()),
+					zap.Error(err))
+				continue
+			}
+			workers = append(workers, worker)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/registrytest"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+var (
+	_ ports.WorkerRegistry = (*Registry)(nil)
+	_ registry.Watcher     = (*Registry)(nil)
+)
+
+// connect returns a JetStream context on url, closed when the test ends
+func connect(t *testing.T, url string) jetstream.JetStream {
+	t.Helper()
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("failed to create JetStream context: %v", err)
+	}
+	return js
+}
+
+// runServer starts an embedded JetStream server, shut down when the test
+// ends, and returns a JetStream context connected to it
+func runServer(t *testing.T) jetstream.JetStream {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatal("server not ready")
+	}
+	return connect(t, srv.ClientURL())
+}
+
+// newRegistry returns a registry on a fresh bucket of an embedded server
+func newRegistry(t *testing.T, ttl time.Duration) *Registry {
+	t.Helper()
+	js := runServer(t)
+	kv, err := EnsureBucket(context.Background(), js, ttl)
+	if err != nil {
+		t.Fatalf("EnsureBucket() error = %v", err)
+	}
+	return NewRegistryWithTTL(js, kv, ttl, zap.NewNop())
+}
+
+func TestWorkerKey(t *testing.T) {
+	for _, id := range []string{"executor-1", "executor:gpu 1", "team/a.b*", "ünïcode", ""} {
+		key := workerKey(id)
+		if !jetstreamKeyValid(key) {
+			t.Errorf("workerKey(%q) = %q, not a valid KV key", id, key)
+		}
+		got, err := keyWorkerID(key)
+		if err != nil || got != id {
+			t.Errorf("keyWorkerID(workerKey(%q)) = %q, %v", id, got, err)
+		}
+	}
+
+	if _, err := keyWorkerID("not base64!"); err == nil {
+		t.Error("keyWorkerID() of an invalid key succeeded, want an error")
+	}
+}
+
+// jetstreamKeyValid reports whether key only has the characters KV keys allow
+func jetstreamKeyValid(key string) bool {
+	for _, c := range key {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '/', c == '_', c == '=', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func TestRegistry_WorkerIDsNeedingEncoding(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	r := newRegistry(t, 30*time.Second)
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	workerID := "executor:gpu 1"
+	now := time.Now()
+	if err := r.Register(ctx, ports.WorkerInfo{ID: workerID, Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if event := <-events; event.Type != registry.WatchEventPut || event.WorkerID != workerID {
+		t.Errorf("Watch() event = %+v, want put for %s", event, workerID)
+	}
+
+	if err := r.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	<-events
+	if worker, err := r.GetWorker(ctx, workerID); err != nil || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, %v, want busy on task-1", worker, err)
+	}
+
+	if err := r.Unregister(ctx, workerID); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	if event := <-events; event.Type != registry.WatchEventDelete || event.WorkerID != workerID {
+		t.Errorf("Watch() event = %+v, want delete for %s", event, workerID)
+	}
+}
+
+func TestRegistry_PerKeyTTL(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	r := newRegistry(t, time.Minute)
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	now := time.Now()
+	fast := ports.WorkerInfo{ID: "executor-fast", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now}
+	registry.SetTTL(&fast, time.Second)
+	for _, worker := range []ports.WorkerInfo{
+		fast,
+		{ID: "executor-slow", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now},
+	} {
+		if err := r.Register(ctx, worker); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+		<-events
+	}
+
+	// Only the key of the worker with the short TTL expires, and the marker
+	// it leaves is reported as a delete
+	select {
+	case event := <-events:
+		if event.Type != registry.WatchEventDelete || event.WorkerID != "executor-fast" {
+			t.Errorf("Watch() event = %+v, want delete for executor-fast", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for executor-fast to expire")
+	}
+	if _, err := r.GetWorker(ctx, "executor-fast"); err == nil {
+		t.Error("GetWorker() of an expired worker succeeded, want an error")
+	}
+	if _, err := r.GetWorker(ctx, "executor-slow"); err != nil {
+		t.Errorf("GetWorker() error = %v", err)
+	}
+
+	// A heartbeat recreates the expired key over its marker
+	if err := r.Heartbeat(ctx, "executor-fast", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if worker, err := r.GetWorker(ctx, "executor-fast"); err != nil || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, %v, want busy on task-1", worker, err)
+	}
+}
+
+func TestRegistry_ConcurrentHeartbeats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	r := newRegistry(t, 30*time.Second)
+
+	now := time.Now()
+	worker := ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now}
+	registry.SetTTL(&worker, 10*time.Second)
+	if err := r.Register(ctx, worker); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := range 4 {
+		wg.Go(func() {
+			errs <- r.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, fmt.Sprintf("task-%d", i))
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Heartbeat() error = %v", err)
+		}
+	}
+
+	// Every heartbeat started from the stored worker, keeping its metadata
+	got, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if got.Status != ports.WorkerStatusBusy || registry.WorkerTTL(*got, 0) != 10*time.Second {
+		t.Errorf("GetWorker() = %+v, want busy with its declared TTL", got)
+	}
+}
+
+func TestRegistry_HeartbeatConflict(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	r := newRegistry(t, 30*time.Second)
+
+	now := time.Now()
+	if err := r.Register(ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	_, revision, err := r.get(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	// A write at a stale revision is rejected rather than overwriting
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	err = r.publish(ctx, workerKey("executor-1"), []byte("{}"), time.Minute, jetstream.WithExpectLastSequencePerSubject(revision))
+	if !errors.Is(err, jetstream.ErrKeyExists) {
+		t.Errorf("publish() at a stale revision error = %v, want ErrKeyExists", err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	t.Run("embedded", func(t *testing.T) {
+		registrytest.RunConformance(t, registrytest.Harness{
+			New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+				return newRegistry(t, ttl)
+			},
+		})
+	})
+
+	// Integration test - only runs with NATS_URL environment variable
+	t.Run("nats", func(t *testing.T) {
+		url := os.Getenv("NATS_URL")
+		if url == "" {
+			t.Skip("NATS_URL not set, skipping integration test")
+		}
+
+		js := connect(t, url)
+		registrytest.RunConformance(t, registrytest.Harness{
+			New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+				// Each check starts from an empty bucket
+				ctx := context.Background()
+				if err := js.DeleteKeyValue(ctx, defaultBucket); err != nil && !errors.Is(err, jetstream.ErrBucketNotFound) {
+					t.Fatalf("failed to delete bucket: %v", err)
+				}
+				kv, err := EnsureBucket(ctx, js, ttl)
+				if err != nil {
+					t.Fatalf("EnsureBucket() error = %v", err)
+				}
+				return NewRegistryWithTTL(js, kv, ttl, zap.NewNop())
+			},
+		})
+	})
+}
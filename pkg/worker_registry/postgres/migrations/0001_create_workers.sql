@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS dago_workers (
+    id             TEXT PRIMARY KEY,
+    type           TEXT NOT NULL,
+    status         TEXT NOT NULL,
+    registered_at  TIMESTAMPTZ NOT NULL,
+    last_heartbeat TIMESTAMPTZ NOT NULL,
+    current_task   TEXT NOT NULL DEFAULT '',
+    pending_tasks  INTEGER NOT NULL DEFAULT 0,
+    version        TEXT NOT NULL DEFAULT '',
+    metadata       JSONB
+);
+
+CREATE INDEX IF NOT EXISTS dago_workers_type_idx ON dago_workers (type);
+CREATE INDEX IF NOT EXISTS dago_workers_last_heartbeat_idx ON dago_workers (last_heartbeat);
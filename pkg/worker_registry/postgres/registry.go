@@ -0,0 +1,339 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/internal/pgmigrate"
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default TTL for worker heartbeats (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// TTL of a worker in milliseconds: its own, declared in its metadata, or
+	// the registry's, $2
+	workerTTL = `COALESCE(CASE WHEN jsonb_typeof(metadata->'` + registry.MetadataTTL + `') = 'number'
+		THEN NULLIF(GREATEST((metadata->>'` + registry.MetadataTTL + `')::float8, 0), 0) END, $2::float8)`
+
+	// Condition of workers without heartbeats for longer than their TTL, $1
+	// being the current time
+	staleCondition = `last_heartbeat < $1::timestamptz - ` + workerTTL + ` * interval '1 millisecond'`
+
+	// Columns selected for every worker read, in scanWorker order.
+	// The status column is derived so that stale workers report unhealthy.
+	workerColumns = `id, type,
+		CASE WHEN ` + staleCondition + ` THEN 'unhealthy' ELSE status END AS status,
+		registered_at, last_heartbeat, current_task, pending_tasks, version, metadata`
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Registry implements ports.WorkerRegistry using PostgreSQL
+type Registry struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+	ttl    time.Duration
+}
+
+// NewRegistry creates a new PostgreSQL worker registry
+func NewRegistry(pool *pgxpool.Pool, logger *zap.Logger) *Registry {
+	return &Registry{
+		pool:   pool,
+		logger: logger,
+		ttl:    defaultWorkerTTL,
+	}
+}
+
+// NewRegistryWithTTL creates a new PostgreSQL worker registry with custom TTL
+func NewRegistryWithTTL(pool *pgxpool.Pool, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		pool:   pool,
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// Migrate applies any pending schema migrations.
+// It is safe to call concurrently from several processes.
+func (r *Registry) Migrate(ctx context.Context) error {
+	return pgmigrate.Migrate(ctx, r.pool, migrationFS, "migrations/", r.logger)
+}
+
+// Register registers a new worker in the system
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	now := time.Now()
+	if worker.RegisteredAt.IsZero() {
+		worker.RegisteredAt = now
+	}
+	if worker.LastHeartbeat.IsZero() {
+		worker.LastHeartbeat = now
+	}
+
+	metadata, err := marshalMetadata(worker.Metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO dago_workers
+			(id, type, status, registered_at, last_heartbeat, current_task, pending_tasks, version, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			type           = EXCLUDED.type,
+			status         = EXCLUDED.status,
+			registered_at  = EXCLUDED.registered_at,
+			last_heartbeat = EXCLUDED.last_heartbeat,
+			current_task   = EXCLUDED.current_task,
+			pending_tasks  = EXCLUDED.pending_tasks,
+			version        = EXCLUDED.version,
+			metadata       = EXCLUDED.metadata`,
+		worker.ID, string(worker.Type), string(worker.Status), worker.RegisteredAt, worker.LastHeartbeat,
+		worker.CurrentTask, worker.PendingTasks, worker.Version, metadata)
+	if err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.Duration("ttl", registry.WorkerTTL(worker, r.ttl)))
+
+	return nil
+}
+
+// Unregister removes a worker from the registry
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM dago_workers WHERE id = $1`, workerID); err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the last heartbeat timestamp for a worker.
+// Unknown workers are auto-registered, mirroring the Redis implementation.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	now := time.Now()
+
+	// xmax is zero only for freshly inserted rows
+	var inserted bool
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO dago_workers (id, type, status, registered_at, last_heartbeat, current_task)
+		VALUES ($1, $2, $3, $4, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			status         = EXCLUDED.status,
+			last_heartbeat = EXCLUDED.last_heartbeat,
+			current_task   = EXCLUDED.current_task
+		RETURNING (xmax = 0)`,
+		workerID, string(registry.InferWorkerType(workerID)), string(status), now, currentTask).Scan(&inserted)
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	if inserted {
+		r.logger.Warn("heartbeat for unregistered worker, auto-registered",
+			zap.String("worker_id", workerID))
+	}
+
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+workerColumns+` FROM dago_workers WHERE id = $3`,
+		time.Now(), r.ttl.Milliseconds(), workerID)
+
+	worker, err := scanWorker(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("worker not found: %s", workerID)
+		}
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	return worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	query, args := buildListQuery(filter, time.Now(), r.ttl)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	defer rows.Close()
+
+	var workers []ports.WorkerInfo
+	for rows.Next() {
+		worker, err := scanWorker(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan worker: %w", err)
+		}
+		workers = append(workers, *worker)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	stats := &ports.WorkerStats{Type: workerType}
+
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE NOT (`+staleCondition+`) AND status = 'idle'),
+			COUNT(*) FILTER (WHERE NOT (`+staleCondition+`) AND status = 'busy'),
+			COUNT(*) FILTER (WHERE `+staleCondition+` OR status = 'unhealthy'),
+			COALESCE(SUM(pending_tasks), 0)
+		FROM dago_workers
+		WHERE type = $3`,
+		time.Now(), r.ttl.Milliseconds(), string(workerType)).Scan(
+		&stats.TotalWorkers,
+		&stats.IdleWorkers,
+		&stats.BusyWorkers,
+		&stats.UnhealthyWorkers,
+		&stats.TotalPendingTasks,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worker stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	rows, err := r.pool.Query(ctx,
+		`DELETE FROM dago_workers WHERE last_heartbeat < $1 RETURNING id, last_heartbeat`,
+		time.Now().Add(-timeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale workers: %w", err)
+	}
+	defer rows.Close()
+
+	cleaned := 0
+	for rows.Next() {
+		var workerID string
+		var lastHeartbeat time.Time
+		if err := rows.Scan(&workerID, &lastHeartbeat); err != nil {
+			return cleaned, fmt.Errorf("failed to scan stale worker: %w", err)
+		}
+
+		r.logger.Info("cleaned up stale worker",
+			zap.String("worker_id", workerID),
+			zap.Duration("idle_time", time.Since(lastHeartbeat)))
+		cleaned++
+	}
+	if err := rows.Err(); err != nil {
+		return cleaned, fmt.Errorf("failed to delete stale workers: %w", err)
+	}
+
+	return cleaned, nil
+}
+
+// Helper methods
+
+// buildListQuery builds the ListWorkers query for a filter.
+// $1 and $2 are always the current time and the registry's TTL in
+// milliseconds: workers whose last heartbeat is older than their TTL are
+// unhealthy.
+func buildListQuery(filter ports.WorkerFilter, now time.Time, ttl time.Duration) (string, []interface{}) {
+	args := []interface{}{now, ttl.Milliseconds()}
+	var conditions []string
+
+	// Filter by type
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		args = append(args, types)
+		conditions = append(conditions, fmt.Sprintf("type = ANY($%d)", len(args)))
+	}
+
+	// Filter by status (using the derived status so stale workers match "unhealthy")
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+		}
+		args = append(args, statuses)
+		conditions = append(conditions, fmt.Sprintf(
+			"(CASE WHEN "+staleCondition+" THEN 'unhealthy' ELSE status END) = ANY($%d)", len(args)))
+	}
+
+	// Filter by health
+	if filter.HealthyOnly {
+		conditions = append(conditions, "NOT ("+staleCondition+")")
+	}
+
+	query := `SELECT ` + workerColumns + ` FROM dago_workers`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY id`
+
+	return query, args
+}
+
+func scanWorker(row pgx.Row) (*ports.WorkerInfo, error) {
+	var worker ports.WorkerInfo
+	var workerType, status string
+	var metadata []byte
+
+	if err := row.Scan(
+		&worker.ID,
+		&workerType,
+		&status,
+		&worker.RegisteredAt,
+		&worker.LastHeartbeat,
+		&worker.CurrentTask,
+		&worker.PendingTasks,
+		&worker.Version,
+		&metadata,
+	); err != nil {
+		return nil, err
+	}
+
+	worker.Type = ports.WorkerType(workerType)
+	worker.Status = ports.WorkerStatus(status)
+
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &worker.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal worker metadata: %w", err)
+		}
+	}
+
+	return &worker, nil
+}
+
+func marshalMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal worker metadata: %w", err)
+	}
+	return data, nil
+}
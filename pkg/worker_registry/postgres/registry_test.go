@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/registrytest"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+func TestBuildListQuery(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		filter       ports.WorkerFilter
+		wantArgs     int
+		wantContains []string
+		wantWhere    bool
+	}{
+		{
+			name:      "no filter",
+			filter:    ports.WorkerFilter{},
+			wantArgs:  2,
+			wantWhere: false,
+		},
+		{
+			name:         "type filter",
+			filter:       ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}},
+			wantArgs:     3,
+			wantContains: []string{"type = ANY($3)"},
+			wantWhere:    true,
+		},
+		{
+			name: "type and status filter",
+			filter: ports.WorkerFilter{
+				Types:    []ports.WorkerType{ports.WorkerTypeRouter},
+				Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle, ports.WorkerStatusBusy},
+			},
+			wantArgs:     4,
+			wantContains: []string{"type = ANY($3)", "= ANY($4)"},
+			wantWhere:    true,
+		},
+		{
+			name:         "healthy only",
+			filter:       ports.WorkerFilter{HealthyOnly: true},
+			wantArgs:     2,
+			wantContains: []string{"NOT (last_heartbeat < $1"},
+			wantWhere:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := buildListQuery(tt.filter, now, 30*time.Second)
+
+			if len(args) != tt.wantArgs {
+				t.Errorf("buildListQuery() args = %d, want %d", len(args), tt.wantArgs)
+			}
+			if args[0] != now || args[1] != int64(30000) {
+				t.Errorf("buildListQuery() args = %v, want the current time and TTL first", args[:2])
+			}
+			if strings.Contains(query, "WHERE") != tt.wantWhere {
+				t.Errorf("buildListQuery() WHERE presence = %v, want %v: %s", !tt.wantWhere, tt.wantWhere, query)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(query, want) {
+					t.Errorf("buildListQuery() query missing %q: %s", want, query)
+				}
+			}
+		})
+	}
+}
+
+// Integration test - only runs with POSTGRES_DSN environment variable
+func TestRegistry_Integration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN not set, skipping integration test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer pool.Close()
+
+	registry := NewRegistryWithTTL(pool, 2*time.Second, zap.NewNop())
+	if err := registry.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	// Running migrations twice must be a no-op
+	if err := registry.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() second run error = %v", err)
+	}
+
+	workerID := "executor-pg-integration"
+	defer func() { _ = registry.Unregister(ctx, workerID) }()
+
+	err = registry.Register(ctx, ports.WorkerInfo{
+		ID:       workerID,
+		Type:     ports.WorkerTypeExecutor,
+		Status:   ports.WorkerStatusIdle,
+		Metadata: map[string]interface{}{"region": "eu"},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := registry.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := registry.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusBusy || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want busy on task-1", worker)
+	}
+	if worker.Metadata["region"] != "eu" {
+		t.Errorf("GetWorker() metadata = %v, want region=eu", worker.Metadata)
+	}
+
+	workers, err := registry.ListWorkers(ctx, ports.WorkerFilter{
+		Types:       []ports.WorkerType{ports.WorkerTypeExecutor},
+		HealthyOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	found := false
+	for _, w := range workers {
+		if w.ID == workerID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListWorkers() did not return %s", workerID)
+	}
+
+	// Let the worker go stale
+	time.Sleep(2500 * time.Millisecond)
+
+	worker, err = registry.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusUnhealthy {
+		t.Errorf("GetWorker() status = %s, want unhealthy", worker.Status)
+	}
+
+	cleaned, err := registry.CleanupStaleWorkers(ctx, time.Second)
+	if err != nil {
+		t.Fatalf("CleanupStaleWorkers() error = %v", err)
+	}
+	if cleaned < 1 {
+		t.Errorf("CleanupStaleWorkers() = %d, want at least 1", cleaned)
+	}
+
+	if _, err := registry.GetWorker(ctx, workerID); err == nil {
+		t.Error("GetWorker() expected error after cleanup")
+	}
+}
+
+// Integration test - only runs with POSTGRES_DSN environment variable
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN not set, skipping integration test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	registrytest.RunConformance(t, registrytest.Harness{
+		New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+			r := NewRegistryWithTTL(pool, ttl, zap.NewNop())
+			if err := r.Migrate(ctx); err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			if _, err := pool.Exec(ctx, "TRUNCATE dago_workers"); err != nil {
+				t.Fatalf("Failed to truncate: %v", err)
+			}
+			return r
+		},
+	})
+}
@@ -0,0 +1,190 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default number of audit entries kept (trimmed approximately)
+	defaultAuditMaxLen = 10000
+
+	// Number of entries read per XRANGE page
+	auditPageSize = 500
+)
+
+// AuditEventType is the kind of change recorded in the audit stream
+type AuditEventType string
+
+const (
+	// AuditRegistered is recorded by Register
+	AuditRegistered AuditEventType = "registered"
+
+	// AuditAutoRegistered is recorded when a heartbeat arrives for an unknown worker
+	AuditAutoRegistered AuditEventType = "auto_registered"
+
+	// AuditUnregistered is recorded by Unregister
+	AuditUnregistered AuditEventType = "unregistered"
+
+	// AuditStatusChanged is recorded when a heartbeat changes a worker's status
+	AuditStatusChanged AuditEventType = "status_changed"
+
+	// AuditCleanedUp is recorded when CleanupStaleWorkers removes a worker
+	AuditCleanedUp AuditEventType = "cleaned_up"
+
+	// AuditExpired is recorded when the registry notices that a worker's key
+	// expired. The event time is when it was noticed, not the expiry itself.
+	AuditExpired AuditEventType = "expired"
+)
+
+// AuditEvent is an entry of the audit stream
+type AuditEvent struct {
+	// ID is the stream entry ID
+	ID string
+
+	Type           AuditEventType
+	WorkerID       string
+	WorkerType     ports.WorkerType
+	Status         ports.WorkerStatus
+	PreviousStatus ports.WorkerStatus
+
+	// Detail is free-form context, e.g. the idle time of a cleaned up worker
+	Detail string
+
+	Time time.Time
+}
+
+// AuditQuery selects audit events. Zero values mean unbounded.
+type AuditQuery struct {
+	WorkerID string
+	Since    time.Time
+	Until    time.Time
+	Limit    int
+}
+
+// SetAuditRetention bounds the audit stream. When maxAge is set, entries older
+// than maxAge are trimmed; otherwise about maxLen entries are kept. A zero
+// maxLen and maxAge keeps everything. Call it before the registry is in use.
+func (r *Registry) SetAuditRetention(maxLen int64, maxAge time.Duration) {
+	r.auditMaxLen = maxLen
+	r.auditMaxAge = maxAge
+}
+
+// ReadAuditEvents returns audit events in chronological order
+func (r *Registry) ReadAuditEvents(ctx context.Context, query AuditQuery) ([]AuditEvent, error) {
+	start, end := "-", "+"
+	if !query.Since.IsZero() {
+		start = strconv.FormatInt(query.Since.UnixMilli(), 10)
+	}
+	if !query.Until.IsZero() {
+		end = strconv.FormatInt(query.Until.UnixMilli(), 10)
+	}
+
+	var events []AuditEvent
+
+	for {
+		messages, err := r.client.XRangeN(ctx, r.keys.AuditStream, start, end, auditPageSize).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit events: %w", err)
+		}
+
+		for _, msg := range messages {
+			event := auditEventFromMessage(msg)
+			if query.WorkerID != "" && event.WorkerID != query.WorkerID {
+				continue
+			}
+
+			events = append(events, event)
+			if query.Limit > 0 && len(events) == query.Limit {
+				return events, nil
+			}
+		}
+
+		if len(messages) < auditPageSize {
+			return events, nil
+		}
+
+		// Continue after the last entry read
+		start = "(" + messages[len(messages)-1].ID
+	}
+}
+
+// audit appends an event to the audit stream. Failures are logged rather than
+// returned so that auditing never fails a registry operation.
+func (r *Registry) audit(ctx context.Context, event AuditEvent) {
+	values := map[string]interface{}{
+		"type":            string(event.Type),
+		"worker_id":       event.WorkerID,
+		"worker_type":     string(event.WorkerType),
+		"status":          string(event.Status),
+		"previous_status": string(event.PreviousStatus),
+		"detail":          event.Detail,
+	}
+
+	args := &redis.XAddArgs{
+		Stream: r.keys.AuditStream,
+		Values: values,
+	}
+
+	switch {
+	case r.auditMaxAge > 0:
+		args.MinID = strconv.FormatInt(time.Now().Add(-r.auditMaxAge).UnixMilli(), 10)
+		args.Approx = true
+	case r.auditMaxLen > 0:
+		args.MaxLen = r.auditMaxLen
+		args.Approx = true
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, args)
+		if r.historyMaxLen > 0 {
+			r.appendHistory(ctx, pipe, event.WorkerID, values)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Warn("failed to record audit event",
+			zap.String("worker_id", event.WorkerID),
+			zap.String("event", string(event.Type)),
+			zap.Error(err))
+	}
+}
+
+// auditExpired records workers found in the index whose keys have expired
+func (r *Registry) auditExpired(ctx context.Context, workerIDs []string) {
+	for _, id := range workerIDs {
+		r.audit(ctx, AuditEvent{Type: AuditExpired, WorkerID: id})
+	}
+}
+
+func auditEventFromMessage(msg redis.XMessage) AuditEvent {
+	field := func(name string) string {
+		v, _ := msg.Values[name].(string)
+		return v
+	}
+
+	event := AuditEvent{
+		ID:             msg.ID,
+		Type:           AuditEventType(field("type")),
+		WorkerID:       field("worker_id"),
+		WorkerType:     ports.WorkerType(field("worker_type")),
+		Status:         ports.WorkerStatus(field("status")),
+		PreviousStatus: ports.WorkerStatus(field("previous_status")),
+		Detail:         field("detail"),
+	}
+
+	// Stream IDs are <unix ms>-<sequence>
+	ms, _, _ := strings.Cut(msg.ID, "-")
+	if millis, err := strconv.ParseInt(ms, 10, 64); err == nil {
+		event.Time = time.UnixMilli(millis)
+	}
+
+	return event
+}
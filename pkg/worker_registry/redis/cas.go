@@ -0,0 +1,137 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Attempts at Register's WATCH/MULTI transaction before giving up
+const maxRegisterAttempts = 5
+
+// ErrRevisionMismatch is returned by CompareAndUpdate when the worker was
+// modified since the revision the caller read.
+var ErrRevisionMismatch = errors.New("worker revision mismatch")
+
+// record is the stored form of a worker: its info plus a revision that every
+// write increments, used for optimistic concurrency.
+type record struct {
+	ports.WorkerInfo
+	Revision int64 `json:"revision"`
+}
+
+// GetWorkerWithRevision returns a worker as stored, along with its revision,
+// for a later CompareAndUpdate. Unlike GetWorker, the status is not adjusted
+// for health, so writing it back doesn't persist a derived "unhealthy".
+func (r *Registry) GetWorkerWithRevision(ctx context.Context, workerID string) (_ *ports.WorkerInfo, _ int64, err error) {
+	ctx, span := startSpan(ctx, "GetWorkerWithRevision", attrWorkerID.String(workerID))
+	defer func() { endSpan(span, err) }()
+
+	data, err := r.client.Get(ctx, r.getWorkerKey(workerID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, 0, fmt.Errorf("worker not found: %s", workerID)
+		}
+		return nil, 0, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal worker info: %w", err)
+	}
+
+	return &rec.WorkerInfo, rec.Revision, nil
+}
+
+// CompareAndUpdate replaces a worker only if it is still at revision, and
+// returns the new revision. If another writer (a heartbeat, another
+// controller) got there first, it returns ErrRevisionMismatch and the caller
+// should re-read and retry. The worker keeps its remaining TTL.
+func (r *Registry) CompareAndUpdate(ctx context.Context, worker ports.WorkerInfo, revision int64) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "CompareAndUpdate",
+		attrWorkerID.String(worker.ID),
+		attrWorkerType.String(string(worker.Type)))
+	defer func() { endSpan(span, err) }()
+
+	data, err := json.Marshal(record{WorkerInfo: worker, Revision: revision + 1})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+
+	res, err := compareAndSetScript.Run(ctx, r.client, []string{r.getWorkerKey(worker.ID)},
+		revision, data).Slice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to update worker: %w", err)
+	}
+
+	code, _ := res[0].(int64)
+	current, _ := res[1].(int64)
+	switch code {
+	case 0:
+		return 0, fmt.Errorf("worker not found: %s", worker.ID)
+	case 1:
+		return current, fmt.Errorf("%w: %s is at revision %d, not %d", ErrRevisionMismatch, worker.ID, current, revision)
+	}
+
+	if err := r.client.ZAdd(ctx, r.keys.Index, indexMember(worker.ID, worker.LastHeartbeat)).Err(); err != nil {
+		r.logger.Warn("failed to update worker index",
+			zap.String("worker_id", worker.ID),
+			zap.Error(err))
+	}
+
+	if previous, _ := res[2].(string); previous != string(worker.Status) {
+		r.audit(ctx, AuditEvent{
+			Type:           AuditStatusChanged,
+			WorkerID:       worker.ID,
+			WorkerType:     worker.Type,
+			Status:         worker.Status,
+			PreviousStatus: ports.WorkerStatus(previous),
+		})
+	}
+
+	return current, nil
+}
+
+// storeRecord writes a worker with the revision after the stored one. WATCH
+// makes the read-increment-write atomic without re-encoding the record in Lua.
+func (r *Registry) storeRecord(ctx context.Context, key string, worker ports.WorkerInfo, ttl time.Duration) error {
+	write := func(tx *redis.Tx) error {
+		var current struct {
+			Revision int64 `json:"revision"`
+		}
+
+		data, err := tx.Get(ctx, key).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil {
+			// An unreadable record restarts at revision 1
+			_ = json.Unmarshal(data, &current)
+		}
+
+		data, err = json.Marshal(record{WorkerInfo: worker, Revision: current.Revision + 1})
+		if err != nil {
+			return fmt.Errorf("failed to marshal worker info: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, ttl)
+			return nil
+		})
+		return err
+	}
+
+	var err error
+	for attempt := 0; attempt < maxRegisterAttempts; attempt++ {
+		if err = r.client.Watch(ctx, write, key); err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return err
+}
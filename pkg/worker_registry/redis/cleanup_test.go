@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// The cleanup and prune steps below run against state read earlier, the way
+// CleanupStaleWorkers and ListWorkers interleave with concurrent writers
+
+func TestPruneIndex_KeepsMovedEntries(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	r := NewRegistry(client, zap.NewNop())
+
+	old := time.Now().Add(-time.Hour)
+	stale := []redis.Z{indexMember("executor-1", old), indexMember("executor-2", old)}
+	if err := client.ZAdd(ctx, r.keys.Index, stale...).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// executor-2 heartbeats after its missing key was read
+	if err := r.Heartbeat(ctx, "executor-2", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	if removed := r.pruneIndex(ctx, stale); !slices.Equal(removed, []string{"executor-1"}) {
+		t.Errorf("pruneIndex() = %v, want [executor-1]", removed)
+	}
+	if members, _ := client.ZRange(ctx, r.keys.Index, 0, -1).Result(); !slices.Equal(members, []string{"executor-2"}) {
+		t.Errorf("index = %v, want [executor-2]", members)
+	}
+}
+
+func TestDeleteStaleScript_KeepsRewrittenWorkers(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	r := NewRegistry(client, zap.NewNop())
+
+	old := time.Now().Add(-time.Hour)
+	worker := ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: old, LastHeartbeat: old}
+	if err := r.Register(ctx, worker); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	_, revision, err := r.GetWorkerWithRevision(ctx, worker.ID)
+	if err != nil {
+		t.Fatalf("GetWorkerWithRevision() error = %v", err)
+	}
+
+	// A heartbeat lands between the read and the delete
+	if err := r.Heartbeat(ctx, worker.ID, ports.WorkerStatusBusy, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	key := r.getWorkerKey(worker.ID)
+	if code, err := deleteStaleScript.Run(ctx, client, []string{key}, revision).Int(); err != nil || code != staleKept {
+		t.Errorf("deleteStaleScript = %d, %v, want %d", code, err, staleKept)
+	}
+	if code, err := deleteStaleScript.Run(ctx, client, []string{key}, revision+1).Int(); err != nil || code != staleDeleted {
+		t.Errorf("deleteStaleScript at the current revision = %d, %v, want %d", code, err, staleDeleted)
+	}
+	if code, err := deleteStaleScript.Run(ctx, client, []string{key}, revision+1).Int(); err != nil || code != staleGone {
+		t.Errorf("deleteStaleScript of a deleted worker = %d, %v, want %d", code, err, staleGone)
+	}
+}
@@ -6,14 +6,63 @@
 //
 // Key Design:
 //   - Worker data is stored as JSON under key: dago:workers:{worker_id}
-//   - Each key has a TTL (default 30 seconds) that is renewed on heartbeat
-//   - Pending task counts are retrieved from Redis Streams consumer info
+//   - Each key has a TTL (default 30 seconds) that is renewed on heartbeat.
+//     Workers may declare their own TTL with worker_registry.SetTTL, which
+//     is then used for both expiry and health. SetTTLJitter extends key
+//     expiry by a random fraction; the resulting deadline is stored in the
+//     worker's metadata (worker_registry.Deadline)
+//   - Worker IDs are indexed in the sorted set dago:worker_index, scored by
+//     last heartbeat, so listing and cleanup never SCAN the keyspace.
+//     Entries whose keys expired are pruned as they are encountered.
+//   - Heartbeats run as a Lua script, updating status, timestamp and current
+//     task atomically without a GET/SET race. The script patches those
+//     fields in the stored JSON, so the rest of the record (metadata numbers,
+//     empty lists) is kept exactly as registered
+//   - Every write bumps a revision stored with the record; controllers read
+//     it with GetWorkerWithRevision and write back with CompareAndUpdate,
+//     which fails with ErrRevisionMismatch instead of overwriting a
+//     concurrent update
+//   - Pending task counts are retrieved from Redis Streams consumer info, using
+//     the stream and consumer group mapped to the worker's type in
+//     Keys.Streams (or Keys.ResolveStream for custom types)
+//   - Registrations, unregistrations, status changes, cleanups and expiries
+//     are appended to the audit stream dago:worker_events (about 10000
+//     entries kept by default, see SetAuditRetention) and can be read back
+//     with ReadAuditEvents. With EnableStatusHistory, each worker's events
+//     are also kept in a capped stream under dago:worker_history:{worker_id},
+//     read with GetWorkerHistory
+//   - Watch tails the audit stream, so the registry works with
+//     worker_registry.CachedRegistry and other watchers
+//   - StartCleanupLoop removes stale workers in the background; replicas
+//     elect a single cleaner through the dago:worker_janitor lock
+//   - Operations are traced through the global OpenTelemetry TracerProvider,
+//     with worker ID and type attributes, as children of the caller's span
+//
+// The key names above are the defaults. NewRegistryWithKeys takes a Keys value,
+// typically NamespacedKeys(name), so several environments or tenants can share
+// one Redis without colliding.
+//
+// The registry accepts any redis.UniversalClient, so it runs unchanged on
+// standalone Redis, Sentinel-managed failover setups, Redis Cluster and
+// client-side sharded Rings. RebuildIndex, which populates the index for
+// workers registered before it existed, fans its SCAN out to every master (or
+// shard) in Cluster and Ring mode since SCAN only walks the node it reaches.
+//
+// Tests can use the registries redistest runs on an in-process miniredis.
 //
 // Usage:
 //
 //	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
 //	registry := redis.NewRegistry(client, logger)
 //
+//	// Or, for an HA deployment (Sentinel when MasterName is set,
+//	// Cluster when several addresses are given)
+//	client := redis.NewUniversalClient(&redis.UniversalOptions{
+//	    Addrs:      []string{"sentinel-1:26379", "sentinel-2:26379"},
+//	    MasterName: "mymaster",
+//	})
+//	registry := redis.NewRegistry(client, logger)
+//
 //	// Register a worker
 //	worker := ports.WorkerInfo{
 //	    ID:   "executor-1",
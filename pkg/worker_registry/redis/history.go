@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EnableStatusHistory keeps, for each worker, a stream of its last maxLen
+// registry events (registration, status changes, removal), so flapping workers
+// can be diagnosed with GetWorkerHistory. Each stream expires retention after
+// the worker's last event, so streams of workers that are gone don't pile up;
+// zero keeps them forever. Call it before the registry is in use.
+func (r *Registry) EnableStatusHistory(maxLen int64, retention time.Duration) {
+	r.historyMaxLen = maxLen
+	r.historyRetention = retention
+}
+
+// GetWorkerHistory returns a worker's recorded events since the given time
+// (zero for all), oldest first. It is empty unless EnableStatusHistory was
+// called.
+func (r *Registry) GetWorkerHistory(ctx context.Context, workerID string, since time.Time) (_ []AuditEvent, err error) {
+	ctx, span := startSpan(ctx, "GetWorkerHistory", attrWorkerID.String(workerID))
+	defer func() { endSpan(span, err) }()
+
+	start := "-"
+	if !since.IsZero() {
+		start = strconv.FormatInt(since.UnixMilli(), 10)
+	}
+
+	messages, err := r.client.XRange(ctx, r.historyKey(workerID), start, "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worker history: %w", err)
+	}
+
+	events := make([]AuditEvent, len(messages))
+	for i, msg := range messages {
+		events[i] = auditEventFromMessage(msg)
+	}
+
+	return events, nil
+}
+
+// appendHistory queues an event on the worker's history stream. Streams are
+// small, so they are trimmed exactly rather than approximately.
+func (r *Registry) appendHistory(ctx context.Context, pipe redis.Pipeliner, workerID string, values map[string]interface{}) {
+	key := r.historyKey(workerID)
+
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		Values: values,
+		MaxLen: r.historyMaxLen,
+	})
+	if r.historyRetention > 0 {
+		pipe.Expire(ctx, key, r.historyRetention)
+	}
+}
+
+func (r *Registry) historyKey(workerID string) string {
+	return r.keys.HistoryPrefix + workerID
+}
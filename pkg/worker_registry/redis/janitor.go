@@ -0,0 +1,172 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// Interval of the cleanup loop when StartCleanupLoop is given none
+const defaultCleanupInterval = 30 * time.Second
+
+// acquireLockScript takes the lock if it is free, or renews it if this
+// instance already holds it.
+//
+// KEYS[1] = lock key
+// ARGV[1] = holder ID, ARGV[2] = lock TTL in milliseconds
+var acquireLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseLockScript deletes the lock only if this instance holds it.
+//
+// KEYS[1] = lock key
+// ARGV[1] = holder ID
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// janitorMetrics are the cleanup loop instruments
+type janitorMetrics struct {
+	runs     metric.Int64Counter
+	removed  metric.Int64Counter
+	duration metric.Float64Histogram
+	leader   metric.Int64UpDownCounter
+}
+
+// StartCleanupLoop runs CleanupStaleWorkers every interval (30 seconds if
+// not positive) in the background until ctx is cancelled. Every replica can
+// start it: a lock in Redis elects a single leader, and another replica takes
+// over within two intervals if the leader goes away.
+//
+// The loop reports through the global OpenTelemetry MeterProvider:
+// dago.worker_registry.cleanup.runs (by result), .removed, .duration and
+// .leader (1 while this instance holds the lock).
+func (r *Registry) StartCleanupLoop(ctx context.Context, interval, timeout time.Duration) {
+	if interval <= 0 {
+		interval = defaultCleanupInterval
+	}
+
+	m := r.newJanitorMetrics()
+	holder := janitorHolderID()
+	lockTTL := 2 * interval
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		leading := false
+		setLeading := func(v bool) {
+			if v != leading {
+				leading = v
+				delta := int64(-1)
+				if v {
+					delta = 1
+				}
+				m.leader.Add(context.Background(), delta)
+				r.logger.Info("worker cleanup leadership changed",
+					zap.String("holder", holder),
+					zap.Bool("leader", v))
+			}
+		}
+
+		defer func() {
+			if leading {
+				// Hand over immediately instead of waiting for the lock to expire
+				releaseCtx := context.WithoutCancel(ctx)
+				if err := releaseLockScript.Run(releaseCtx, r.client, []string{r.keys.JanitorLock}, holder).Err(); err != nil {
+					r.logger.Warn("failed to release worker cleanup lock", zap.Error(err))
+				}
+				setLeading(false)
+			}
+		}()
+
+		for {
+			acquired, err := acquireLockScript.Run(ctx, r.client, []string{r.keys.JanitorLock},
+				holder, lockTTL.Milliseconds()).Int()
+			if err != nil && ctx.Err() == nil {
+				r.logger.Warn("failed to acquire worker cleanup lock", zap.Error(err))
+			}
+			setLeading(err == nil && acquired == 1)
+
+			if leading {
+				r.runCleanup(ctx, m, timeout)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *Registry) runCleanup(ctx context.Context, m *janitorMetrics, timeout time.Duration) {
+	start := time.Now()
+	cleaned, err := r.CleanupStaleWorkers(ctx, timeout)
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+		if ctx.Err() == nil {
+			r.logger.Warn("stale worker cleanup failed", zap.Error(err))
+		}
+	}
+
+	attrs := metric.WithAttributes(attribute.String("result", result))
+	m.runs.Add(ctx, 1, attrs)
+	m.removed.Add(ctx, int64(cleaned))
+	m.duration.Record(ctx, time.Since(start).Seconds(), attrs)
+}
+
+func (r *Registry) newJanitorMetrics() *janitorMetrics {
+	meter := otel.Meter(instrumentationName)
+	m := &janitorMetrics{}
+
+	var errs [4]error
+	m.runs, errs[0] = meter.Int64Counter("dago.worker_registry.cleanup.runs",
+		metric.WithDescription("Stale worker cleanup runs"))
+	m.removed, errs[1] = meter.Int64Counter("dago.worker_registry.cleanup.removed",
+		metric.WithDescription("Stale workers removed"))
+	m.duration, errs[2] = meter.Float64Histogram("dago.worker_registry.cleanup.duration",
+		metric.WithDescription("Duration of stale worker cleanup runs"),
+		metric.WithUnit("s"))
+	m.leader, errs[3] = meter.Int64UpDownCounter("dago.worker_registry.cleanup.leader",
+		metric.WithDescription("Whether this instance runs stale worker cleanup"))
+
+	for _, err := range errs {
+		if err != nil {
+			// Instruments are still usable (no-op) when creation fails
+			r.logger.Warn("failed to create cleanup metric", zap.Error(err))
+		}
+	}
+
+	return m
+}
+
+// janitorHolderID identifies this instance as lock holder
+func janitorHolderID() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
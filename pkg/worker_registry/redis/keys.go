@@ -0,0 +1,87 @@
+package redis
+
+import "github.com/aescanero/dago-libs/pkg/ports"
+
+// TaskStream is a task stream and the consumer group workers read it through.
+// A worker's pending tasks are its pending entries in that group.
+type TaskStream struct {
+	Stream string
+	Group  string
+}
+
+// Keys names every Redis key, stream and consumer group the registry uses.
+// Registries with different Keys can share one Redis without colliding.
+type Keys struct {
+	// WorkerPrefix is prepended to worker IDs to form worker keys. No other
+	// key may start with it, or a worker ID could name that key.
+	WorkerPrefix string
+
+	// Index is the sorted set of worker IDs
+	Index string
+
+	// AuditStream records registry changes
+	AuditStream string
+
+	// HistoryPrefix is prepended to worker IDs to form the per-worker status
+	// history streams (see EnableStatusHistory)
+	HistoryPrefix string
+
+	// JanitorLock elects the instance running the cleanup loop
+	JanitorLock string
+
+	// Streams maps worker types to the task stream their pending tasks are
+	// read from. Types not listed are passed to ResolveStream.
+	Streams map[ports.WorkerType]TaskStream
+
+	// ResolveStream, if set, maps worker types missing from Streams, e.g.
+	// custom types following a naming convention. Returning false means the
+	// type has no task stream and its pending tasks are reported as zero.
+	ResolveStream func(workerType ports.WorkerType) (TaskStream, bool)
+}
+
+// DefaultKeys returns the key names used by NewRegistry. The audit stream
+// is kept out of the worker prefix, where a worker ID could name it.
+func DefaultKeys() Keys {
+	return Keys{
+		WorkerPrefix:  "dago:workers:",
+		Index:         "dago:worker_index",
+		AuditStream:   "dago:worker_events",
+		HistoryPrefix: "dago:worker_history:",
+		JanitorLock:   "dago:worker_janitor",
+		Streams: map[ports.WorkerType]TaskStream{
+			ports.WorkerTypeExecutor: {Stream: "executor.work", Group: "executor-workers"},
+			ports.WorkerTypeRouter:   {Stream: "router.work", Group: "router-workers"},
+		},
+	}
+}
+
+// NamespacedKeys returns key names isolated under namespace, e.g. a tenant or
+// environment name. Worker records live under their own sub-prefix
+// ("dago:ns:{namespace}:workers:id:{worker_id}"), disjoint from the other keys.
+// Task streams are namespaced too ("{namespace}.executor.work"), so producers
+// and workers must use the same names.
+func NamespacedKeys(namespace string) Keys {
+	prefix := "dago:ns:" + namespace + ":"
+	return Keys{
+		WorkerPrefix:  prefix + "workers:id:",
+		Index:         prefix + "worker_index",
+		AuditStream:   prefix + "workers:events",
+		HistoryPrefix: prefix + "worker_history:",
+		JanitorLock:   prefix + "worker_janitor",
+		Streams: map[ports.WorkerType]TaskStream{
+			ports.WorkerTypeExecutor: {Stream: namespace + ".executor.work", Group: "executor-workers"},
+			ports.WorkerTypeRouter:   {Stream: namespace + ".router.work", Group: "router-workers"},
+		},
+	}
+}
+
+// taskStream returns the task stream of a worker type
+func (k Keys) taskStream(workerType ports.WorkerType) (TaskStream, bool) {
+	if stream, ok := k.Streams[workerType]; ok {
+		return stream, true
+	}
+	if k.ResolveStream != nil {
+		return k.ResolveStream(workerType)
+	}
+	return TaskStream{}, false
+}
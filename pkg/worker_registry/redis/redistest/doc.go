@@ -0,0 +1,19 @@
+// Package redistest provides hermetic Redis worker registries for tests,
+// backed by an in-process miniredis, so that the registry and code built on
+// it (routers, health checkers, caches) can be tested without a Redis server.
+//
+// miniredis doesn't expire keys on its own: FastForward advances key TTLs,
+// so workers that stop heartbeating disappear like they would in Redis.
+// AddPendingTasks fills a worker's pending entries in its task stream, which
+// heartbeats report as PendingTasks.
+//
+// Usage:
+//
+//	registry, srv := redistest.NewRegistry(t, 10*time.Second)
+//	registry.Register(ctx, worker)
+//
+//	redistest.AddPendingTasks(t, srv, redis.DefaultKeys().Streams[ports.WorkerTypeExecutor], worker.ID, 2)
+//	registry.Heartbeat(ctx, worker.ID, ports.WorkerStatusBusy, "task-1")
+//
+//	srv.FastForward(10 * time.Second) // worker expired
+package redistest
@@ -0,0 +1,67 @@
+package redistest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	redisregistry "github.com/aescanero/dago-adapters/pkg/worker_registry/redis"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NewClient starts a miniredis server and returns a client connected to it.
+// Both are closed when the test ends.
+func NewClient(t testing.TB) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return client, srv
+}
+
+// NewRegistry returns a registry with the default keys on a fresh miniredis
+// server, whose workers expire after ttl without heartbeats
+func NewRegistry(t testing.TB, ttl time.Duration) (*redisregistry.Registry, *miniredis.Miniredis) {
+	t.Helper()
+	return NewRegistryWithKeys(t, ttl, redisregistry.DefaultKeys())
+}
+
+// NewRegistryWithKeys is NewRegistry with custom key names
+func NewRegistryWithKeys(t testing.TB, ttl time.Duration, keys redisregistry.Keys) (*redisregistry.Registry, *miniredis.Miniredis) {
+	t.Helper()
+	client, srv := NewClient(t)
+	return redisregistry.NewRegistryWithKeys(client, ttl, keys, zap.NewNop()), srv
+}
+
+// AddPendingTasks adds n tasks to stream and delivers them to consumer
+// without acknowledging them, so they count as the consumer's pending tasks.
+// The stream and its consumer group are created if needed.
+func AddPendingTasks(t testing.TB, srv *miniredis.Miniredis, stream redisregistry.TaskStream, consumer string, n int) {
+	t.Helper()
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer func() { _ = client.Close() }()
+
+	if err := client.XGroupCreateMkStream(ctx, stream.Stream, stream.Group, "$").Err(); err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		t.Fatalf("failed to create consumer group: %v", err)
+	}
+	for range n {
+		if err := client.XAdd(ctx, &redis.XAddArgs{Stream: stream.Stream, Values: map[string]interface{}{"task": consumer}}).Err(); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+	if n == 0 {
+		return
+	}
+	if err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    stream.Group,
+		Consumer: consumer,
+		Streams:  []string{stream.Stream, ">"},
+		Count:    int64(n),
+		Block:    -1,
+	}).Err(); err != nil {
+		t.Fatalf("failed to deliver tasks: %v", err)
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
 	"github.com/aescanero/dago-libs/pkg/ports"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -16,132 +23,223 @@ const (
 	// Default TTL for worker heartbeats (30 seconds)
 	defaultWorkerTTL = 30 * time.Second
 
-	// Key prefix for worker data
-	workerKeyPrefix = "dago:workers:"
-
-	// Stream keys for executor and router workers
-	executorStreamKey = "executor.work"
-	routerStreamKey   = "router.work"
-
-	// Consumer group names
-	executorConsumerGroup = "executor-workers"
-	routerConsumerGroup   = "router-workers"
+	// Number of keys fetched per pipeline round trip
+	fetchBatchSize = 100
 )
 
-// Registry implements ports.WorkerRegistry using Redis
+// Registry implements ports.WorkerRegistry using Redis.
+// It works with standalone, Sentinel (failover), Cluster and Ring clients.
 type Registry struct {
-	client *redis.Client
+	client redis.UniversalClient
 	logger *zap.Logger
 	ttl    time.Duration
+	keys   Keys
+
+	// Audit stream retention, see SetAuditRetention
+	auditMaxLen int64
+	auditMaxAge time.Duration
+
+	// Per-worker status history, see EnableStatusHistory
+	historyMaxLen    int64
+	historyRetention time.Duration
+
+	// Fraction of random extra TTL, see SetTTLJitter
+	ttlJitter float64
 }
 
 // NewRegistry creates a new Redis worker registry
-func NewRegistry(client *redis.Client, logger *zap.Logger) *Registry {
-	return &Registry{
-		client: client,
-		logger: logger,
-		ttl:    defaultWorkerTTL,
-	}
+func NewRegistry(client redis.UniversalClient, logger *zap.Logger) *Registry {
+	return NewRegistryWithKeys(client, defaultWorkerTTL, DefaultKeys(), logger)
 }
 
 // NewRegistryWithTTL creates a new Redis worker registry with custom TTL
-func NewRegistryWithTTL(client *redis.Client, ttl time.Duration, logger *zap.Logger) *Registry {
+func NewRegistryWithTTL(client redis.UniversalClient, ttl time.Duration, logger *zap.Logger) *Registry {
+	return NewRegistryWithKeys(client, ttl, DefaultKeys(), logger)
+}
+
+// NewRegistryWithKeys creates a new Redis worker registry with custom TTL and
+// key names, e.g. NamespacedKeys("tenant-a")
+func NewRegistryWithKeys(client redis.UniversalClient, ttl time.Duration, keys Keys, logger *zap.Logger) *Registry {
 	return &Registry{
-		client: client,
-		logger: logger,
-		ttl:    ttl,
+		client:      client,
+		logger:      logger,
+		ttl:         ttl,
+		keys:        keys,
+		auditMaxLen: defaultAuditMaxLen,
 	}
 }
 
+// SetTTLJitter extends each worker's key expiry by a random fraction of its
+// TTL, up to jitter (0.1 for 10%), on registration and on every heartbeat.
+// Workers registered together during a rollout then expire at different
+// moments instead of all at once. Call it before the registry is in use.
+func (r *Registry) SetTTLJitter(jitter float64) {
+	r.ttlJitter = jitter
+}
+
 // Register registers a new worker in the system
-func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) (err error) {
+	ctx, span := startSpan(ctx, "Register",
+		attrWorkerID.String(worker.ID),
+		attrWorkerType.String(string(worker.Type)))
+	defer func() { endSpan(span, err) }()
+
 	key := r.getWorkerKey(worker.ID)
+	ttl := registry.JitteredTTL(registry.WorkerTTL(worker, r.ttl), r.ttlJitter)
 
-	// Serialize worker info to JSON
-	data, err := json.Marshal(worker)
-	if err != nil {
-		return fmt.Errorf("failed to marshal worker info: %w", err)
-	}
+	// Expose the deadline without touching the caller's metadata
+	worker.Metadata = maps.Clone(worker.Metadata)
+	registry.SetDeadline(&worker, time.Now().Add(ttl))
 
-	// Store in Redis with TTL
-	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+	// Store in Redis with TTL, then add to the index. Separate commands since
+	// the two keys may live in different cluster slots.
+	if err := r.storeRecord(ctx, key, worker, ttl); err != nil {
 		return fmt.Errorf("failed to register worker: %w", err)
 	}
+	if err := r.client.ZAdd(ctx, r.keys.Index, indexMember(worker.ID, worker.LastHeartbeat)).Err(); err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.audit(ctx, AuditEvent{
+		Type:       AuditRegistered,
+		WorkerID:   worker.ID,
+		WorkerType: worker.Type,
+		Status:     worker.Status,
+	})
 
 	r.logger.Info("worker registered",
 		zap.String("worker_id", worker.ID),
 		zap.String("type", string(worker.Type)),
-		zap.Duration("ttl", r.ttl))
+		zap.Duration("ttl", ttl))
 
 	return nil
 }
 
 // Unregister removes a worker from the registry
-func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+func (r *Registry) Unregister(ctx context.Context, workerID string) (err error) {
+	ctx, span := startSpan(ctx, "Unregister", attrWorkerID.String(workerID))
+	defer func() { endSpan(span, err) }()
+
 	key := r.getWorkerKey(workerID)
 
-	if err := r.client.Del(ctx, key).Err(); err != nil {
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.ZRem(ctx, r.keys.Index, workerID)
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to unregister worker: %w", err)
 	}
 
+	r.audit(ctx, AuditEvent{Type: AuditUnregistered, WorkerID: workerID})
+
 	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
 	return nil
 }
 
-// Heartbeat updates the last heartbeat timestamp for a worker
-func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+// Heartbeat updates the last heartbeat timestamp for a worker.
+// Status, timestamp and current task are updated by a single Lua script, so
+// concurrent heartbeats and updates never overwrite each other's fields.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) (err error) {
+	ctx, span := startSpan(ctx, "Heartbeat",
+		attrWorkerID.String(workerID),
+		attribute.String("dago.worker.status", string(status)))
+	defer func() { endSpan(span, err) }()
+
 	key := r.getWorkerKey(workerID)
+	now := time.Now()
+
+	ttl := r.ttl.Milliseconds()
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	// The script scales the worker's TTL by this factor; computed here since
+	// math.random isn't random inside Redis scripts
+	factor := 1 + r.ttlJitter*rand.Float64()
+
+	// Record stored if the worker turns out not to be registered, with the
+	// same TTL arithmetic as the script
+	fallbackWorker := ports.WorkerInfo{
+		ID:            workerID,
+		Type:          registry.InferWorkerType(workerID),
+		Status:        status,
+		RegisteredAt:  now,
+		LastHeartbeat: now,
+		CurrentTask:   currentTask,
+	}
+	fallbackTTL := max(int64(math.Floor(float64(ttl)*factor)), 1)
+	registry.SetDeadline(&fallbackWorker, time.UnixMilli(now.UnixMilli()+fallbackTTL))
+
+	fallback, err := json.Marshal(record{WorkerInfo: fallbackWorker, Revision: 1})
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker info: %w", err)
+	}
 
-	// Get existing worker info
-	worker, err := r.GetWorker(ctx, workerID)
+	res, err := heartbeatScript.Run(ctx, r.client, []string{key},
+		string(status), now.Format(time.RFC3339Nano), currentTask, ttl, fallback,
+		strconv.FormatFloat(factor, 'g', -1, 64), now.UnixMilli()).Slice()
 	if err != nil {
-		// Worker not found, this shouldn't happen but we can recover
-		r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	if err := r.client.ZAdd(ctx, r.keys.Index, indexMember(workerID, now)).Err(); err != nil {
+		return fmt.Errorf("failed to update worker index: %w", err)
+	}
+
+	workerType := registry.InferWorkerType(workerID)
+	if created, _ := res[0].(int64); created == 1 {
+		// Worker not found, this shouldn't happen but we recovered
+		r.logger.Warn("heartbeat for unregistered worker, auto-registered",
 			zap.String("worker_id", workerID))
 
-		// Try to determine worker type from ID
-		workerType := r.inferWorkerType(workerID)
+		r.audit(ctx, AuditEvent{
+			Type:       AuditAutoRegistered,
+			WorkerID:   workerID,
+			WorkerType: workerType,
+			Status:     status,
+		})
+	} else {
+		if t, _ := res[1].(string); t != "" {
+			workerType = ports.WorkerType(t)
+		}
 
-		worker = &ports.WorkerInfo{
-			ID:            workerID,
-			Type:          workerType,
-			Status:        status,
-			RegisteredAt:  time.Now(),
-			LastHeartbeat: time.Now(),
-			CurrentTask:   currentTask,
+		if previous, _ := res[2].(string); previous != string(status) {
+			r.audit(ctx, AuditEvent{
+				Type:           AuditStatusChanged,
+				WorkerID:       workerID,
+				WorkerType:     workerType,
+				Status:         status,
+				PreviousStatus: ports.WorkerStatus(previous),
+			})
 		}
-	} else {
-		// Update existing worker info
-		worker.Status = status
-		worker.LastHeartbeat = time.Now()
-		worker.CurrentTask = currentTask
 	}
 
+	span.SetAttributes(attrWorkerType.String(string(workerType)))
+
 	// Get pending tasks from Redis Streams consumer info
-	pendingTasks, err := r.getPendingTasksForWorker(ctx, workerID, worker.Type)
+	pendingTasks, err := r.getPendingTasksForWorker(ctx, workerID, workerType)
 	if err != nil {
 		r.logger.Warn("failed to get pending tasks",
 			zap.String("worker_id", workerID),
 			zap.Error(err))
-	} else {
-		worker.PendingTasks = pendingTasks
+		return nil
 	}
 
-	// Serialize and save with renewed TTL
-	data, err := json.Marshal(worker)
-	if err != nil {
-		return fmt.Errorf("failed to marshal worker info: %w", err)
-	}
-
-	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
-		return fmt.Errorf("failed to update heartbeat: %w", err)
+	if err := setPendingTasksScript.Run(ctx, r.client, []string{key}, pendingTasks).Err(); err != nil {
+		r.logger.Warn("failed to update pending tasks",
+			zap.String("worker_id", workerID),
+			zap.Error(err))
 	}
 
 	return nil
 }
 
 // GetWorker retrieves information about a specific worker
-func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (_ *ports.WorkerInfo, err error) {
+	ctx, span := startSpan(ctx, "GetWorker", attrWorkerID.String(workerID))
+	defer func() { endSpan(span, err) }()
+
 	key := r.getWorkerKey(workerID)
 
 	data, err := r.client.Get(ctx, key).Bytes()
@@ -158,7 +256,7 @@ func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.Worke
 	}
 
 	// Check if worker is healthy based on last heartbeat
-	if time.Since(worker.LastHeartbeat) > r.ttl {
+	if time.Since(worker.LastHeartbeat) > registry.WorkerTTL(worker, r.ttl) {
 		worker.Status = ports.WorkerStatusUnhealthy
 	}
 
@@ -166,25 +264,28 @@ func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.Worke
 }
 
 // ListWorkers retrieves all workers matching the filter criteria
-func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
-	// Scan for all worker keys
-	pattern := workerKeyPrefix + "*"
-	keys, err := r.scanKeys(ctx, pattern)
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) (_ []ports.WorkerInfo, err error) {
+	ctx, span := startSpan(ctx, "ListWorkers")
+	defer func() { endSpan(span, err) }()
+
+	entries, err := r.client.ZRangeWithScores(ctx, r.keys.Index, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worker index: %w", err)
+	}
+
+	keys := r.getWorkerKeys(indexIDs(entries))
+	values, err := r.fetchKeys(ctx, keys)
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan worker keys: %w", err)
+		return nil, fmt.Errorf("failed to get workers: %w", err)
 	}
 
 	var workers []ports.WorkerInfo
+	var expired []redis.Z
 
-	for _, key := range keys {
-		data, err := r.client.Get(ctx, key).Bytes()
-		if err != nil {
-			if err == redis.Nil {
-				continue // Key expired between scan and get
-			}
-			r.logger.Warn("failed to get worker",
-				zap.String("key", key),
-				zap.Error(err))
+	for i, key := range keys {
+		data := values[i]
+		if data == nil {
+			expired = append(expired, entries[i]) // Key expired, drop it from the index
 			continue
 		}
 
@@ -197,19 +298,22 @@ func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) (
 		}
 
 		// Check if worker is healthy
-		isHealthy := time.Since(worker.LastHeartbeat) <= r.ttl
+		isHealthy := time.Since(worker.LastHeartbeat) <= registry.WorkerTTL(worker, r.ttl)
 		if !isHealthy {
 			worker.Status = ports.WorkerStatusUnhealthy
 		}
 
 		// Apply filters
-		if !r.matchesFilter(worker, filter, isHealthy) {
+		if !registry.MatchesFilter(worker, filter, isHealthy) {
 			continue
 		}
 
 		workers = append(workers, worker)
 	}
 
+	r.auditExpired(ctx, r.pruneIndex(ctx, expired))
+
+	span.SetAttributes(attrWorkerCount.Int(len(workers)))
 	return workers, nil
 }
 
@@ -244,146 +348,292 @@ func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerTy
 	return stats, nil
 }
 
-// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout
-func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
-	// Scan for all worker keys
-	pattern := workerKeyPrefix + "*"
-	keys, err := r.scanKeys(ctx, pattern)
+// GetAllWorkerStats returns statistics for every worker type from a single listing
+func (r *Registry) GetAllWorkerStats(ctx context.Context) (*registry.AllWorkerStats, error) {
+	return registry.GetAllWorkerStats(ctx, r)
+}
+
+// CleanupStaleWorkers removes workers that haven't sent a heartbeat within the timeout.
+// Only index entries scored before the cutoff are read. Workers that heartbeat
+// or register again while the cleanup runs are kept.
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (_ int, err error) {
+	ctx, span := startSpan(ctx, "CleanupStaleWorkers")
+	defer func() { endSpan(span, err) }()
+
+	cutoff := time.Now().Add(-timeout).UnixMilli()
+	entries, err := r.client.ZRangeByScoreWithScores(ctx, r.keys.Index, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(cutoff, 10),
+	}).Result()
 	if err != nil {
-		return 0, fmt.Errorf("failed to scan worker keys: %w", err)
+		return 0, fmt.Errorf("failed to read worker index: %w", err)
 	}
 
-	cleaned := 0
+	keys := r.getWorkerKeys(indexIDs(entries))
+	values, err := r.fetchKeys(ctx, keys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get workers: %w", err)
+	}
 
-	for _, key := range keys {
-		data, err := r.client.Get(ctx, key).Bytes()
-		if err != nil {
-			if err == redis.Nil {
-				continue // Already expired
-			}
+	var expired, removed []redis.Z
+
+	for i, key := range keys {
+		data := values[i]
+		if data == nil {
+			expired = append(expired, entries[i]) // Already expired
 			continue
 		}
 
-		var worker ports.WorkerInfo
+		var worker record
 		if err := json.Unmarshal(data, &worker); err != nil {
 			continue
 		}
 
 		// Check if worker is stale
-		if time.Since(worker.LastHeartbeat) > timeout {
-			if err := r.client.Del(ctx, key).Err(); err != nil {
-				r.logger.Warn("failed to delete stale worker",
-					zap.String("worker_id", worker.ID),
-					zap.Error(err))
-			} else {
-				r.logger.Info("cleaned up stale worker",
-					zap.String("worker_id", worker.ID),
-					zap.Duration("idle_time", time.Since(worker.LastHeartbeat)))
-				cleaned++
-			}
+		if time.Since(worker.LastHeartbeat) <= timeout {
+			continue
+		}
+
+		// Deleted only if nothing was written since the read
+		code, err := deleteStaleScript.Run(ctx, r.client, []string{key}, worker.Revision).Int()
+		switch {
+		case err != nil:
+			r.logger.Warn("failed to delete stale worker",
+				zap.String("worker_id", worker.ID),
+				zap.Error(err))
+		case code == staleGone:
+			expired = append(expired, entries[i])
+		case code == staleDeleted:
+			idle := time.Since(worker.LastHeartbeat)
+			r.logger.Info("cleaned up stale worker",
+				zap.String("worker_id", worker.ID),
+				zap.Duration("idle_time", idle))
+
+			r.audit(ctx, AuditEvent{
+				Type:       AuditCleanedUp,
+				WorkerID:   worker.ID,
+				WorkerType: worker.Type,
+				Status:     worker.Status,
+				Detail:     "idle for " + idle.Round(time.Millisecond).String(),
+			})
+			removed = append(removed, entries[i])
 		}
 	}
 
-	return cleaned, nil
+	r.auditExpired(ctx, r.pruneIndex(ctx, expired))
+	r.pruneIndex(ctx, removed)
+
+	span.SetAttributes(attrWorkerCount.Int(len(removed)))
+	return len(removed), nil
+}
+
+// RebuildIndex adds every stored worker to the index. Workers registered by
+// versions that predate the index are otherwise invisible to ListWorkers until
+// their next heartbeat. It SCANs the whole keyspace, so run it once on upgrade
+// rather than routinely.
+func (r *Registry) RebuildIndex(ctx context.Context) (_ int, err error) {
+	ctx, span := startSpan(ctx, "RebuildIndex")
+	defer func() { endSpan(span, err) }()
+
+	keys, err := r.scanKeys(ctx, r.keys.WorkerPrefix+"*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan worker keys: %w", err)
+	}
+
+	values, err := r.fetchKeys(ctx, keys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get workers: %w", err)
+	}
+
+	var members []redis.Z
+	for i, key := range keys {
+		if values[i] == nil {
+			continue
+		}
+
+		var worker ports.WorkerInfo
+		if err := json.Unmarshal(values[i], &worker); err != nil {
+			r.logger.Warn("failed to unmarshal worker",
+				zap.String("key", key),
+				zap.Error(err))
+			continue
+		}
+		members = append(members, indexMember(worker.ID, worker.LastHeartbeat))
+	}
+
+	if len(members) > 0 {
+		if err := r.client.ZAdd(ctx, r.keys.Index, members...).Err(); err != nil {
+			return 0, fmt.Errorf("failed to update worker index: %w", err)
+		}
+	}
+
+	return len(members), nil
 }
 
 // Helper methods
 
 func (r *Registry) getWorkerKey(workerID string) string {
-	return workerKeyPrefix + workerID
+	return r.keys.WorkerPrefix + workerID
 }
 
-func (r *Registry) scanKeys(ctx context.Context, pattern string) ([]string, error) {
-	var keys []string
-	var cursor uint64
+func (r *Registry) getWorkerKeys(workerIDs []string) []string {
+	keys := make([]string, len(workerIDs))
+	for i, id := range workerIDs {
+		keys[i] = r.getWorkerKey(id)
+	}
+	return keys
+}
 
-	for {
-		var scanKeys []string
-		var err error
+// pruneIndex removes index entries whose worker keys are gone, and returns the
+// IDs of the workers removed. Entries whose score moved since they were read
+// are kept: the worker registered again or heartbeat in the meantime.
+func (r *Registry) pruneIndex(ctx context.Context, entries []redis.Z) []string {
+	if len(entries) == 0 {
+		return nil
+	}
 
-		scanKeys, cursor, err = r.client.Scan(ctx, cursor, pattern, 100).Result()
-		if err != nil {
-			return nil, err
-		}
+	args := make([]interface{}, 0, 2*len(entries))
+	for _, entry := range entries {
+		args = append(args, entry.Member, entry.Score)
+	}
 
-		keys = append(keys, scanKeys...)
+	removed, err := pruneIndexScript.Run(ctx, r.client, []string{r.keys.Index}, args...).StringSlice()
+	if err != nil {
+		r.logger.Warn("failed to prune worker index",
+			zap.Int("count", len(entries)),
+			zap.Error(err))
+		return nil
+	}
+	return removed
+}
 
-		if cursor == 0 {
-			break
+// indexIDs returns the worker IDs of index entries
+func indexIDs(entries []redis.Z) []string {
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i], _ = entry.Member.(string)
+	}
+	return ids
+}
+
+// indexMember is a worker's index entry, scored by its last heartbeat
+func indexMember(workerID string, lastHeartbeat time.Time) redis.Z {
+	return redis.Z{Score: float64(lastHeartbeat.UnixMilli()), Member: workerID}
+}
+
+// scanKeys returns all keys matching pattern. SCAN only walks the node it is
+// sent to, so Cluster and Ring clients scan every master/shard.
+func (r *Registry) scanKeys(ctx context.Context, pattern string) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "scan", attribute.String("dago.redis.pattern", pattern))
+	defer func() { endSpan(span, err) }()
+
+	var mu sync.Mutex
+	var keys []string
+
+	scanNode := func(ctx context.Context, node *redis.Client) error {
+		nodeKeys, err := scanClient(ctx, node, pattern)
+		if err != nil {
+			return err
 		}
+		mu.Lock()
+		keys = append(keys, nodeKeys...)
+		mu.Unlock()
+		return nil
+	}
+
+	switch c := r.client.(type) {
+	case *redis.ClusterClient:
+		err = c.ForEachMaster(ctx, scanNode)
+	case *redis.Ring:
+		err = c.ForEachShard(ctx, scanNode)
+	default:
+		keys, err = scanClient(ctx, r.client, pattern)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return keys, nil
 }
 
-func (r *Registry) matchesFilter(worker ports.WorkerInfo, filter ports.WorkerFilter, isHealthy bool) bool {
-	// Filter by type
-	if len(filter.Types) > 0 {
-		typeMatch := false
-		for _, t := range filter.Types {
-			if worker.Type == t {
-				typeMatch = true
-				break
+// fetchKeys GETs keys in pipelined batches, one round trip per batch instead
+// of one per key. Pipelines (rather than MGET) keep this working on Redis
+// Cluster, where keys hash to different slots. The result is aligned with keys;
+// missing or unreadable keys are nil.
+func (r *Registry) fetchKeys(ctx context.Context, keys []string) (_ [][]byte, err error) {
+	ctx, span := startSpan(ctx, "fetch", attrKeyCount.Int(len(keys)))
+	defer func() { endSpan(span, err) }()
+
+	values := make([][]byte, len(keys))
+
+	for start := 0; start < len(keys); start += fetchBatchSize {
+		batch := keys[start:min(start+fetchBatchSize, len(keys))]
+		cmds := make([]*redis.StringCmd, len(batch))
+
+		// Each command carries its own error, so the aggregate one is ignored
+		_, _ = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, key := range batch {
+				cmds[i] = pipe.Get(ctx, key)
 			}
+			return nil
+		})
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		if !typeMatch {
-			return false
-		}
-	}
 
-	// Filter by status
-	if len(filter.Statuses) > 0 {
-		statusMatch := false
-		for _, s := range filter.Statuses {
-			if worker.Status == s {
-				statusMatch = true
-				break
+		for i, cmd := range cmds {
+			data, err := cmd.Bytes()
+			if err != nil {
+				if err != redis.Nil {
+					r.logger.Warn("failed to get worker",
+						zap.String("key", batch[i]),
+						zap.Error(err))
+				}
+				continue
 			}
-		}
-		if !statusMatch {
-			return false
+			values[start+i] = data
 		}
 	}
 
-	// Filter by health
-	if filter.HealthyOnly && !isHealthy {
-		return false
-	}
-
-	return true
+	return values, nil
 }
 
-func (r *Registry) inferWorkerType(workerID string) ports.WorkerType {
-	// Infer worker type from worker ID
-	if strings.Contains(workerID, "executor") {
-		return ports.WorkerTypeExecutor
-	}
-	if strings.Contains(workerID, "router") {
-		return ports.WorkerTypeRouter
+// scanClient runs a full SCAN iteration against a single node
+func scanClient(ctx context.Context, client redis.Cmdable, pattern string) ([]string, error) {
+	var keys []string
+	var cursor uint64
+
+	for {
+		var scanKeys []string
+		var err error
+
+		scanKeys, cursor, err = client.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, scanKeys...)
+
+		if cursor == 0 {
+			break
+		}
 	}
-	// Default to executor if can't determine
-	return ports.WorkerTypeExecutor
+
+	return keys, nil
 }
 
 func (r *Registry) getPendingTasksForWorker(ctx context.Context, workerID string, workerType ports.WorkerType) (int, error) {
 	// Determine stream and consumer group based on worker type
-	var streamKey, consumerGroup string
-	switch workerType {
-	case ports.WorkerTypeExecutor:
-		streamKey = executorStreamKey
-		consumerGroup = executorConsumerGroup
-	case ports.WorkerTypeRouter:
-		streamKey = routerStreamKey
-		consumerGroup = routerConsumerGroup
-	default:
+	stream, ok := r.keys.taskStream(workerType)
+	if !ok {
 		return 0, nil
 	}
 
 	// Get consumer info using XINFO CONSUMERS
-	consumers, err := r.client.XInfoConsumers(ctx, streamKey, consumerGroup).Result()
+	consumers, err := r.client.XInfoConsumers(ctx, stream.Stream, stream.Group).Result()
 	if err != nil {
 		// Stream or consumer group might not exist yet
-		if strings.Contains(err.Error(), "NOGROUP") {
+		if strings.Contains(err.Error(), "NOGROUP") || strings.Contains(err.Error(), "no such key") {
 			return 0, nil
 		}
 		return 0, err
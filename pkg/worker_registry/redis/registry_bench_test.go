@@ -0,0 +1,206 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Registry sizes the benchmarks run at
+var benchSizes = []int{100, 1000, 10000}
+
+// BenchmarkListWorkers compares the indexed, pipelined fetch used by
+// ListWorkers with the designs it replaced or could be replaced by: finding
+// workers with SCAN instead of the index, fetching them with MGET (which
+// doesn't work across Cluster slots) and issuing one GET per key. Round trips
+// dominate, so the gaps widen with real network latency; run against a real
+// Redis before deciding on a redesign.
+func BenchmarkListWorkers(b *testing.B) {
+	for _, n := range benchSizes {
+		ctx := context.Background()
+		r, keys := newBenchRegistry(b, n)
+
+		b.Run(fmt.Sprintf("index/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				workers, err := r.ListWorkers(ctx, ports.WorkerFilter{})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(workers) != n {
+					b.Fatalf("ListWorkers() returned %d workers, want %d", len(workers), n)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("scan/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				found, err := r.scanKeys(ctx, r.keys.WorkerPrefix+"*")
+				if err != nil {
+					b.Fatal(err)
+				}
+				values, err := r.fetchKeys(ctx, found)
+				if err != nil {
+					b.Fatal(err)
+				}
+				decodeWorkers(b, values)
+			}
+		})
+
+		b.Run(fmt.Sprintf("mget/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				values, err := r.client.MGet(ctx, keys...).Result()
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, value := range values {
+					var worker ports.WorkerInfo
+					if err := json.Unmarshal([]byte(value.(string)), &worker); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("sequential/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for _, key := range keys {
+					var worker ports.WorkerInfo
+					data, err := r.client.Get(ctx, key).Bytes()
+					if err != nil {
+						b.Fatal(err)
+					}
+					if err := json.Unmarshal(data, &worker); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkListWorkersFiltered measures listing a small subset, which still
+// reads every worker since filters are applied after fetching
+func BenchmarkListWorkersFiltered(b *testing.B) {
+	for _, n := range benchSizes {
+		ctx := context.Background()
+		r, _ := newBenchRegistry(b, n)
+		filter := ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeRouter}, HealthyOnly: true}
+
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := r.ListWorkers(ctx, filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHeartbeat measures heartbeat throughput, from concurrent workers
+// each heartbeating its own key: the Lua script, the index update and the
+// pending task lookup
+func BenchmarkHeartbeat(b *testing.B) {
+	for _, n := range benchSizes {
+		ctx := context.Background()
+		r, _ := newBenchRegistry(b, n)
+
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			var next atomic.Int64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					id := fmt.Sprintf("executor-%d", next.Add(1)%int64(n))
+					if err := r.Heartbeat(ctx, id, ports.WorkerStatusBusy, "task-1"); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkCleanupStaleWorkers measures a cleanup pass finding nothing to
+// remove, the common case: only index entries older than the timeout are
+// read
+func BenchmarkCleanupStaleWorkers(b *testing.B) {
+	for _, n := range benchSizes {
+		ctx := context.Background()
+		r, _ := newBenchRegistry(b, n)
+
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := r.CleanupStaleWorkers(ctx, time.Minute); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// decodeWorkers decodes fetched workers, skipping missing keys and the
+// audit stream, which SCAN finds under the worker prefix
+func decodeWorkers(b *testing.B, values [][]byte) {
+	b.Helper()
+	for _, data := range values {
+		if data == nil {
+			continue
+		}
+		var worker ports.WorkerInfo
+		if err := json.Unmarshal(data, &worker); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// newBenchRegistry returns a registry of n workers, a tenth of them routers,
+// and their keys. It runs on miniredis, or on the Redis at REDIS_ADDR, in a
+// namespace deleted afterwards, to measure real round trips.
+func newBenchRegistry(b *testing.B, n int) (*Registry, []string) {
+	b.Helper()
+	ctx := context.Background()
+
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = miniredis.RunT(b).Addr()
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	b.Cleanup(func() { _ = client.Close() })
+
+	namespace := fmt.Sprintf("bench-%d", time.Now().UnixNano())
+	b.Cleanup(func() {
+		keys, _ := scanClient(ctx, client, "dago:ns:"+namespace+":*")
+		for start := 0; start < len(keys); start += fetchBatchSize {
+			_ = client.Del(ctx, keys[start:min(start+fetchBatchSize, len(keys))]...).Err()
+		}
+	})
+
+	r := NewRegistryWithKeys(client, defaultWorkerTTL, NamespacedKeys(namespace), zap.NewNop())
+
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		worker := ports.WorkerInfo{
+			ID:            fmt.Sprintf("executor-%d", i),
+			Type:          ports.WorkerTypeExecutor,
+			Status:        ports.WorkerStatusIdle,
+			RegisteredAt:  time.Now(),
+			LastHeartbeat: time.Now(),
+		}
+		if i%10 == 0 {
+			worker.Type = ports.WorkerTypeRouter
+		}
+		if err := r.Register(ctx, worker); err != nil {
+			b.Fatal(err)
+		}
+		keys = append(keys, r.getWorkerKey(worker.ID))
+	}
+
+	return r, keys
+}
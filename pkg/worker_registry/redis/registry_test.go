@@ -0,0 +1,499 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	redisregistry "github.com/aescanero/dago-adapters/pkg/worker_registry/redis"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/redis/redistest"
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/registrytest"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func workerIDs(workers []ports.WorkerInfo) []string {
+	ids := make([]string, 0, len(workers))
+	for _, worker := range workers {
+		ids = append(ids, worker.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestRegistry_RegisterAndGet(t *testing.T) {
+	ctx := context.Background()
+	r, srv := redistest.NewRegistry(t, 30*time.Second)
+
+	now := time.Now()
+	err := r.Register(ctx, ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  now,
+		LastHeartbeat: now,
+		Metadata:      map[string]interface{}{"gpu": true},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if ttl := srv.TTL("dago:workers:executor-1"); ttl != 30*time.Second {
+		t.Errorf("worker key TTL = %v, want 30s", ttl)
+	}
+	if score, err := srv.ZScore("dago:worker_index", "executor-1"); err != nil || score != float64(now.UnixMilli()) {
+		t.Errorf("index score = %v, %v, want %d", score, err, now.UnixMilli())
+	}
+
+	worker, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeExecutor || worker.Status != ports.WorkerStatusIdle || worker.Metadata["gpu"] != true {
+		t.Errorf("GetWorker() = %+v, want idle executor with gpu metadata", worker)
+	}
+	if _, ok := registry.Deadline(*worker); !ok {
+		t.Error("GetWorker() has no deadline")
+	}
+
+	if _, err := r.GetWorker(ctx, "missing"); err == nil {
+		t.Error("GetWorker() of an unknown worker succeeded, want an error")
+	}
+}
+
+func TestRegistry_HeartbeatRenewsTTL(t *testing.T) {
+	ctx := context.Background()
+	r, srv := redistest.NewRegistry(t, 10*time.Second)
+
+	now := time.Now()
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now})
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now})
+
+	srv.FastForward(6 * time.Second)
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if ttl := srv.TTL("dago:workers:executor-1"); ttl != 10*time.Second {
+		t.Errorf("worker key TTL after heartbeat = %v, want 10s", ttl)
+	}
+
+	// executor-2 expires, executor-1 is kept alive by its heartbeat
+	srv.FastForward(6 * time.Second)
+	if _, err := r.GetWorker(ctx, "executor-2"); err == nil {
+		t.Error("GetWorker() of an expired worker succeeded, want an error")
+	}
+	worker, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusBusy || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want busy on task-1", worker)
+	}
+
+	// Listing prunes expired workers from the index
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if got := workerIDs(workers); !slices.Equal(got, []string{"executor-1"}) {
+		t.Errorf("ListWorkers() = %v, want [executor-1]", got)
+	}
+	if members, _ := srv.ZMembers("dago:worker_index"); !slices.Equal(members, []string{"executor-1"}) {
+		t.Errorf("index = %v, want [executor-1]", members)
+	}
+}
+
+func TestRegistry_HeartbeatAutoRegisters(t *testing.T) {
+	ctx := context.Background()
+	r, srv := redistest.NewRegistry(t, 10*time.Second)
+
+	if err := r.Heartbeat(ctx, "router-1", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	worker, err := r.GetWorker(ctx, "router-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeRouter || worker.Status != ports.WorkerStatusIdle {
+		t.Errorf("GetWorker() = %+v, want idle router", worker)
+	}
+	if ttl := srv.TTL("dago:workers:router-1"); ttl != 10*time.Second {
+		t.Errorf("worker key TTL = %v, want 10s", ttl)
+	}
+
+	events, err := r.ReadAuditEvents(ctx, redisregistry.AuditQuery{WorkerID: "router-1"})
+	if err != nil {
+		t.Fatalf("ReadAuditEvents() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Type != redisregistry.AuditAutoRegistered {
+		t.Errorf("ReadAuditEvents() = %+v, want one auto-registration", events)
+	}
+}
+
+func TestRegistry_HeartbeatPendingTasks(t *testing.T) {
+	ctx := context.Background()
+	keys := redisregistry.NamespacedKeys("tenant-a")
+	r, srv := redistest.NewRegistryWithKeys(t, 10*time.Second, keys)
+
+	now := time.Now()
+	for _, id := range []string{"executor-1", "executor-2", "router-1"} {
+		err := r.Register(ctx, ports.WorkerInfo{ID: id, Type: registry.InferWorkerType(id), Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now})
+		if err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	pending := func(workerID string) int {
+		t.Helper()
+		if err := r.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, ""); err != nil {
+			t.Fatalf("Heartbeat() error = %v", err)
+		}
+		worker, err := r.GetWorker(ctx, workerID)
+		if err != nil {
+			t.Fatalf("GetWorker() error = %v", err)
+		}
+		return worker.PendingTasks
+	}
+
+	// No task stream yet
+	if got := pending("executor-1"); got != 0 {
+		t.Errorf("PendingTasks without a task stream = %d, want 0", got)
+	}
+
+	redistest.AddPendingTasks(t, srv, keys.Streams[ports.WorkerTypeExecutor], "executor-1", 3)
+	if got := pending("executor-1"); got != 3 {
+		t.Errorf("PendingTasks = %d, want 3", got)
+	}
+
+	// Other consumers of the group have none
+	if got := pending("executor-2"); got != 0 {
+		t.Errorf("PendingTasks of another executor = %d, want 0", got)
+	}
+
+	// Routers read their own stream, which doesn't exist
+	if got := pending("router-1"); got != 0 {
+		t.Errorf("PendingTasks of a router = %d, want 0", got)
+	}
+
+	stats, err := r.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	if stats.TotalPendingTasks != 3 {
+		t.Errorf("TotalPendingTasks = %d, want 3", stats.TotalPendingTasks)
+	}
+}
+
+func TestRegistry_HeartbeatKeepsRecord(t *testing.T) {
+	ctx := context.Background()
+	keys := redisregistry.NamespacedKeys("tenant-a")
+	r, srv := redistest.NewRegistryWithKeys(t, 10*time.Second, keys)
+
+	now := time.Now()
+	worker := ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  now,
+		LastHeartbeat: now,
+		Version:       "v1.2.3 <beta>",
+		Metadata:      map[string]interface{}{"model_bytes": int64(9007199254740993), "note": `a "quoted", {braced} value`},
+	}
+	registry.SetCapabilities(&worker, map[string][]string{"models": {}})
+	registry.SetAffinityKeys(&worker, []string{})
+	registry.SetTTL(&worker, 20*time.Second)
+	if err := r.Register(ctx, worker); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	redistest.AddPendingTasks(t, srv, keys.Streams[ports.WorkerTypeExecutor], "executor-1", 2)
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	// Fields the scripts don't own are stored exactly as registered
+	data, err := srv.Get(keys.WorkerPrefix + "executor-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	for _, want := range []string{
+		`"model_bytes":9007199254740993`,
+		`"capabilities":{"models":[]}`,
+		`"affinity_keys":[]`,
+		`"heartbeat_ttl_ms":20000`,
+		`"note":"a \"quoted\", {braced} value"`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("stored worker %s doesn't contain %s", data, want)
+		}
+	}
+
+	got, revision, err := r.GetWorkerWithRevision(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorkerWithRevision() error = %v", err)
+	}
+	if got.Status != ports.WorkerStatusBusy || got.CurrentTask != "task-1" || got.PendingTasks != 2 || got.Version != worker.Version {
+		t.Errorf("GetWorkerWithRevision() = %+v, want busy on task-1 with 2 pending tasks", got)
+	}
+	if revision != 3 {
+		t.Errorf("revision = %d, want 3 after registration, heartbeat and pending tasks", revision)
+	}
+	if deadline, ok := registry.Deadline(*got); !ok || deadline.Before(now.Add(20*time.Second).Truncate(time.Millisecond)) {
+		t.Errorf("Deadline() = %v, %v, want the worker's own 20s TTL", deadline, ok)
+	}
+	if ttl := srv.TTL(keys.WorkerPrefix + "executor-1"); ttl != 20*time.Second {
+		t.Errorf("worker key TTL = %v, want 20s", ttl)
+	}
+}
+
+func TestRegistry_ListWorkers(t *testing.T) {
+	ctx := context.Background()
+	r, _ := redistest.NewRegistry(t, 10*time.Second)
+
+	now := time.Now()
+	for _, worker := range []ports.WorkerInfo{
+		{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+		{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, LastHeartbeat: now},
+		{ID: "executor-3", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now.Add(-time.Minute)},
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+	} {
+		worker.RegisteredAt = now
+		if err := r.Register(ctx, worker); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter ports.WorkerFilter
+		want   []string
+	}{
+		{"all", ports.WorkerFilter{}, []string{"executor-1", "executor-2", "executor-3", "router-1"}},
+		{"routers", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeRouter}}, []string{"router-1"}},
+		{"busy", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusBusy}}, []string{"executor-2"}},
+		{"unhealthy", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusUnhealthy}}, []string{"executor-3"}},
+		{"healthy executors", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}, HealthyOnly: true}, []string{"executor-1", "executor-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workers, err := r.ListWorkers(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListWorkers() error = %v", err)
+			}
+			if got := workerIDs(workers); !slices.Equal(got, tt.want) {
+				t.Errorf("ListWorkers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_CleanupStaleWorkers(t *testing.T) {
+	ctx := context.Background()
+	r, srv := redistest.NewRegistry(t, 10*time.Second)
+
+	now := time.Now()
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now})
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now.Add(-time.Minute)})
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-3", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now.Add(-time.Minute)})
+
+	// executor-3 expired on its own before the cleanup
+	srv.Del("dago:workers:executor-3")
+
+	cleaned, err := r.CleanupStaleWorkers(ctx, 30*time.Second)
+	if err != nil {
+		t.Fatalf("CleanupStaleWorkers() error = %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("CleanupStaleWorkers() = %d, want 1", cleaned)
+	}
+	if srv.Exists("dago:workers:executor-2") {
+		t.Error("stale worker key still exists")
+	}
+	if members, _ := srv.ZMembers("dago:worker_index"); !slices.Equal(members, []string{"executor-1"}) {
+		t.Errorf("index = %v, want [executor-1]", members)
+	}
+
+	events, err := r.ReadAuditEvents(ctx, redisregistry.AuditQuery{})
+	if err != nil {
+		t.Fatalf("ReadAuditEvents() error = %v", err)
+	}
+	var got []string
+	for _, event := range events {
+		if event.Type != redisregistry.AuditRegistered {
+			got = append(got, fmt.Sprintf("%s %s", event.Type, event.WorkerID))
+		}
+	}
+	want := []string{
+		fmt.Sprintf("%s executor-2", redisregistry.AuditCleanedUp),
+		fmt.Sprintf("%s executor-3", redisregistry.AuditExpired),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("audit events = %v, want %v", got, want)
+	}
+}
+
+func TestRegistry_CleanupLoopDefaultsInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, srv := redistest.NewRegistry(t, 10*time.Second)
+
+	now := time.Now()
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now.Add(-time.Minute)})
+
+	// A zero interval falls back to the default instead of panicking; the
+	// first pass runs right away
+	r.StartCleanupLoop(ctx, 0, 30*time.Second)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.Exists("dago:workers:executor-1") {
+		if time.Now().After(deadline) {
+			t.Fatal("stale worker not cleaned up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRegistry_WorkerIDsDontCollideWithKeys(t *testing.T) {
+	for name, keys := range map[string]redisregistry.Keys{
+		"default":    redisregistry.DefaultKeys(),
+		"namespaced": redisregistry.NamespacedKeys("tenant-a"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r, _ := redistest.NewRegistryWithKeys(t, 30*time.Second, keys)
+
+			// Workers named after the registry's other keys don't overwrite
+			// them, and the index is rebuilt from worker keys only
+			now := time.Now()
+			for _, id := range []string{"events", "id:events", "executor-1"} {
+				if err := r.Register(ctx, ports.WorkerInfo{ID: id, Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now}); err != nil {
+					t.Fatalf("Register(%s) error = %v", id, err)
+				}
+			}
+
+			events, err := r.ReadAuditEvents(ctx, redisregistry.AuditQuery{})
+			if err != nil {
+				t.Fatalf("ReadAuditEvents() error = %v", err)
+			}
+			if len(events) != 3 {
+				t.Errorf("ReadAuditEvents() = %d events, want 3", len(events))
+			}
+
+			n, err := r.RebuildIndex(ctx)
+			if err != nil {
+				t.Fatalf("RebuildIndex() error = %v", err)
+			}
+			if n != 3 {
+				t.Errorf("RebuildIndex() = %d, want 3", n)
+			}
+			workers, err := r.ListWorkers(ctx, ports.WorkerFilter{})
+			if err != nil {
+				t.Fatalf("ListWorkers() error = %v", err)
+			}
+			if got, want := workerIDs(workers), []string{"events", "executor-1", "id:events"}; !slices.Equal(got, want) {
+				t.Errorf("ListWorkers() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRegistry_WatchSurvivesRestart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	r, srv := redistest.NewRegistry(t, 30*time.Second)
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	next := func() registry.WatchEvent {
+		t.Helper()
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatal("watch channel closed")
+			}
+			return event
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for a watch event")
+		}
+		return registry.WatchEvent{}
+	}
+
+	now := time.Now()
+	_ = r.Register(ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now})
+	if event := next(); event.WorkerID != "executor-1" {
+		t.Fatalf("event for %q, want executor-1", event.WorkerID)
+	}
+
+	// Reads fail while the server is down; the watch retries until it's back.
+	// A new server on the same address stands in for the restart, as
+	// miniredis' Restart leaves blocking commands unanswered.
+	addr := srv.Addr()
+	srv.Close()
+	time.Sleep(300 * time.Millisecond)
+	restarted := miniredis.NewMiniRedis()
+	if err := restarted.StartAddr(addr); err != nil {
+		t.Fatalf("StartAddr() error = %v", err)
+	}
+	t.Cleanup(restarted.Close)
+
+	if err := r.Register(ctx, ports.WorkerInfo{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, RegisteredAt: now, LastHeartbeat: now}); err != nil {
+		t.Fatalf("Register() after restart error = %v", err)
+	}
+	if event := next(); event.WorkerID != "executor-2" || event.Type != registry.WatchEventPut {
+		t.Errorf("event after restart = %s %q, want put executor-2", event.Type, event.WorkerID)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	t.Run("miniredis", func(t *testing.T) {
+		var srv *miniredis.Miniredis
+		registrytest.RunConformance(t, registrytest.Harness{
+			New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+				var r *redisregistry.Registry
+				r, srv = redistest.NewRegistry(t, ttl)
+				return r
+			},
+			TTL: time.Second,
+			// Health follows the wall clock, key expiry the miniredis one
+			Sleep: func(d time.Duration) {
+				time.Sleep(d)
+				srv.FastForward(d)
+			},
+		})
+	})
+
+	// Integration test - only runs with REDIS_ADDR environment variable
+	t.Run("redis", func(t *testing.T) {
+		addr := os.Getenv("REDIS_ADDR")
+		if addr == "" {
+			t.Skip("REDIS_ADDR not set, skipping integration test")
+		}
+
+		client := redis.NewClient(&redis.Options{Addr: addr})
+		t.Cleanup(func() { _ = client.Close() })
+
+		registrytest.RunConformance(t, registrytest.Harness{
+			New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+				// Each check gets its own namespace, deleted afterwards
+				namespace := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+				t.Cleanup(func() {
+					ctx := context.Background()
+					keys, _ := client.Keys(ctx, "dago:ns:"+namespace+":*").Result()
+					if len(keys) > 0 {
+						_ = client.Del(ctx, keys...).Err()
+					}
+				})
+				return redisregistry.NewRegistryWithKeys(client, ttl, redisregistry.NamespacedKeys(namespace), zap.NewNop())
+			},
+		})
+	})
+}
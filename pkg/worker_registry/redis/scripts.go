@@ -0,0 +1,252 @@
+package redis
+
+import "github.com/redis/go-redis/v9"
+
+// jsonFieldsLua defines the Lua helpers the worker scripts use to read and
+// replace single fields of a stored record. Records are patched as strings
+// rather than decoded and re-encoded with cjson, which writes numbers with 14
+// significant digits and turns empty arrays into empty objects: everything a
+// script doesn't own is kept byte for byte.
+//
+// field(obj, key) returns the first and last position of the value of key in
+// the JSON object obj, or nil when obj doesn't have it. set(obj, key, value)
+// returns obj with the value of key replaced by the JSON value, or added.
+const jsonFieldsLua = `
+local function field(obj, key)
+	local depth, i = 0, 1
+	local expectKey, name, first = false, nil, nil
+	while true do
+		i = obj:find('["{}%[%],:]', i)
+		if not i then
+			return nil
+		end
+		local c = obj:sub(i, i)
+		if c == '"' then
+			local j = i + 1
+			while true do
+				j = obj:find('["\\]', j)
+				if not j then
+					return nil
+				end
+				if obj:sub(j, j) == '"' then
+					break
+				end
+				j = j + 2
+			end
+			if depth == 1 and expectKey then
+				name = obj:sub(i + 1, j - 1)
+				expectKey = false
+			end
+			i = j
+		elseif c == ':' then
+			if depth == 1 then
+				first = obj:find('%S', i + 1)
+			end
+		elseif c == ',' or c == '}' or c == ']' then
+			if depth == 1 and name == key and first then
+				local last = i - 1
+				while obj:sub(last, last):match('%s') do
+					last = last - 1
+				end
+				return first, last
+			end
+			if c == ',' then
+				if depth == 1 then
+					expectKey, name, first = true, nil, nil
+				end
+			else
+				depth = depth - 1
+				if depth == 0 then
+					return nil
+				end
+			end
+		else
+			depth = depth + 1
+			if depth == 1 then
+				expectKey = true
+			end
+		end
+		i = i + 1
+	end
+end
+
+local function set(obj, key, value)
+	local first, last = field(obj, key)
+	if first then
+		return obj:sub(1, first - 1) .. value .. obj:sub(last + 1)
+	end
+	local close = obj:find('}%s*$')
+	local sep = ','
+	if obj:sub(1, close - 1):match('{%s*$') then
+		sep = ''
+	end
+	return obj:sub(1, close - 1) .. sep .. cjson.encode(key) .. ':' .. value .. obj:sub(close)
+end
+
+local function get(obj, key)
+	local first, last = field(obj, key)
+	if first then
+		return obj:sub(first, last)
+	end
+	return nil
+end
+`
+
+// heartbeatScript atomically updates a worker's status, last heartbeat and
+// current task, bumps its revision and renews its TTL, using the worker's own
+// TTL from its metadata when it declares one. The TTL is scaled by the jitter
+// factor and the resulting deadline is stored in the metadata. If the worker
+// doesn't exist, the JSON in ARGV[5] is stored instead.
+//
+// KEYS[1] = worker key
+// ARGV[1] = status, ARGV[2] = last heartbeat (RFC 3339), ARGV[3] = current task,
+// ARGV[4] = registry TTL in milliseconds, ARGV[5] = worker JSON used when auto-registering,
+// ARGV[6] = TTL jitter factor (>= 1), ARGV[7] = current time in Unix milliseconds
+//
+// Returns {created, type, previous status}: created is 1 when the worker was
+// auto-registered.
+var heartbeatScript = redis.NewScript(jsonFieldsLua + `
+local factor = tonumber(ARGV[6])
+
+local data = redis.call('GET', KEYS[1])
+if not data then
+	local ttl = math.max(math.floor(tonumber(ARGV[4]) * factor), 1)
+	redis.call('SET', KEYS[1], ARGV[5], 'PX', ttl)
+	return {1, '', ''}
+end
+
+local previous = get(data, 'status')
+previous = previous and cjson.decode(previous) or ''
+local workerType = get(data, 'type')
+workerType = workerType and cjson.decode(workerType) or ''
+local revision = tonumber(get(data, 'revision') or '') or 0
+
+data = set(data, 'status', cjson.encode(ARGV[1]))
+data = set(data, 'last_heartbeat', cjson.encode(ARGV[2]))
+data = set(data, 'current_task', cjson.encode(ARGV[3]))
+data = set(data, 'revision', string.format('%d', revision + 1))
+
+local metadata = get(data, 'metadata')
+if not metadata or metadata:sub(1, 1) ~= '{' then
+	metadata = '{}'
+end
+
+local ttl = tonumber(ARGV[4])
+local own = tonumber(get(metadata, 'heartbeat_ttl_ms') or '')
+if own and own >= 1 then
+	ttl = math.floor(own)
+end
+ttl = math.max(math.floor(ttl * factor), 1)
+metadata = set(metadata, 'heartbeat_deadline_ms', string.format('%d', tonumber(ARGV[7]) + ttl))
+data = set(data, 'metadata', metadata)
+
+redis.call('SET', KEYS[1], data, 'PX', ttl)
+return {0, workerType, previous}
+`)
+
+// setPendingTasksScript atomically updates a worker's pending task count and
+// bumps its revision, keeping its remaining TTL. Missing workers are left alone.
+//
+// KEYS[1] = worker key
+// ARGV[1] = pending task count
+var setPendingTasksScript = redis.NewScript(jsonFieldsLua + `
+local data = redis.call('GET', KEYS[1])
+if not data then
+	return 0
+end
+
+local pending = tonumber(ARGV[1])
+if tonumber(get(data, 'pending_tasks') or '') == pending then
+	return 0
+end
+local revision = tonumber(get(data, 'revision') or '') or 0
+data = set(data, 'pending_tasks', string.format('%d', pending))
+data = set(data, 'revision', string.format('%d', revision + 1))
+
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], data, 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], data)
+end
+return 1
+`)
+
+// compareAndSetScript replaces a worker record if its revision matches,
+// keeping the remaining TTL. The new record already carries the next revision.
+//
+// KEYS[1] = worker key
+// ARGV[1] = expected revision, ARGV[2] = new worker JSON
+//
+// Returns {code, revision, previous status}: code is 0 when the worker doesn't
+// exist, 1 on a revision mismatch (revision is then the current one) and 2
+// when the record was replaced.
+var compareAndSetScript = redis.NewScript(`
+local data = redis.call('GET', KEYS[1])
+if not data then
+	return {0, 0, ''}
+end
+
+local worker = cjson.decode(data)
+local revision = tonumber(worker['revision']) or 0
+if revision ~= tonumber(ARGV[1]) then
+	return {1, revision, ''}
+end
+
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[2])
+end
+return {2, revision + 1, worker['status'] or ''}
+`)
+
+// Results of deleteStaleScript
+const (
+	staleKept    = 0
+	staleDeleted = 1
+	staleGone    = 2
+)
+
+// deleteStaleScript deletes a worker judged stale, only if its revision is
+// still the one read: a heartbeat or registration since then keeps it.
+//
+// KEYS[1] = worker key
+// ARGV[1] = revision read
+//
+// Returns staleDeleted when the worker was deleted, staleGone when it had
+// already expired and staleKept otherwise.
+var deleteStaleScript = redis.NewScript(jsonFieldsLua + `
+local data = redis.call('GET', KEYS[1])
+if not data then
+	return 2
+end
+if (tonumber(get(data, 'revision') or '') or 0) ~= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+// pruneIndexScript removes index entries whose score is still at most the
+// one read. Worker keys may live in another cluster slot than the index, so
+// the script can't check them; instead, every writer updates the worker key
+// before its index entry, so an entry whose score hasn't moved belongs to a
+// worker that hasn't been written since its key was found gone or stale.
+//
+// KEYS[1] = index key
+// ARGV = worker ID and score read, for each entry
+//
+// Returns the IDs of the workers removed.
+var pruneIndexScript = redis.NewScript(`
+local removed = {}
+for i = 1, #ARGV, 2 do
+	local score = redis.call('ZSCORE', KEYS[1], ARGV[i])
+	if score and tonumber(score) <= tonumber(ARGV[i + 1]) then
+		redis.call('ZREM', KEYS[1], ARGV[i])
+		removed[#removed + 1] = ARGV[i]
+	end
+end
+return removed
+`)
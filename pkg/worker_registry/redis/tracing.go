@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation scope for registry spans and metrics
+const instrumentationName = "github.com/aescanero/dago-adapters/pkg/worker_registry/redis"
+
+// Span attribute keys
+const (
+	attrWorkerID    = attribute.Key("dago.worker.id")
+	attrWorkerType  = attribute.Key("dago.worker.type")
+	attrWorkerCount = attribute.Key("dago.worker.count")
+	attrKeyCount    = attribute.Key("dago.redis.key_count")
+)
+
+// startSpan starts a client span for a registry operation using the global
+// TracerProvider, so spans join the caller's trace through ctx.
+func startSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("db.system", "redis"))
+	return otel.Tracer(instrumentationName).Start(ctx, "WorkerRegistry."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// endSpan records err, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// How long a single XREAD blocks before checking ctx again
+	watchBlockTimeout = 5 * time.Second
+
+	// Entries read per XREAD
+	watchBatchSize = 100
+
+	// Wait before retrying a failed XREAD, doubled on each consecutive
+	// failure up to watchMaxBackoff
+	watchBackoff    = 100 * time.Millisecond
+	watchMaxBackoff = 5 * time.Second
+)
+
+// Watch streams worker membership changes until ctx is cancelled, by tailing
+// the audit stream. Registrations and status changes are put events carrying
+// the worker's current state; removals, cleanups and noticed expiries are
+// delete events. Heartbeats that don't change the status are not reported,
+// and keys expiring unnoticed are reported once a listing notices them.
+//
+// Failed reads, such as while Redis restarts, are retried with backoff and
+// resume after the last event delivered, so no event is skipped or repeated.
+// The channel is closed only once ctx is cancelled and the pending XREAD
+// returns, which can take up to five seconds.
+func (r *Registry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	// Start from the current end of the stream, resolved now so that changes
+	// made right after Watch returns aren't missed
+	lastID := "0-0"
+	messages, err := r.client.XRevRangeN(ctx, r.keys.AuditStream, "+", "-", 1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) > 0 {
+		lastID = messages[0].ID
+	}
+
+	events := make(chan registry.WatchEvent)
+
+	go func() {
+		defer close(events)
+
+		backoff := watchBackoff
+		for ctx.Err() == nil {
+			streams, err := r.client.XRead(ctx, &redis.XReadArgs{
+				Streams: []string{r.keys.AuditStream, lastID},
+				Count:   watchBatchSize,
+				Block:   watchBlockTimeout,
+			}).Result()
+			if errors.Is(err, redis.Nil) {
+				backoff = watchBackoff
+				continue
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				r.logger.Warn("worker watch failed, retrying",
+					zap.String("last_id", lastID),
+					zap.Duration("backoff", backoff),
+					zap.Error(err))
+				if !sleep(ctx, backoff) {
+					return
+				}
+				backoff = min(2*backoff, watchMaxBackoff)
+				continue
+			}
+			backoff = watchBackoff
+
+			for _, stream := range streams {
+				for _, msg := range stream.Messages {
+					lastID = msg.ID
+
+					event, ok := r.watchEvent(ctx, auditEventFromMessage(msg))
+					if !ok {
+						continue
+					}
+
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// watchEvent converts an audit event into a watch event. Workers that are
+// already gone by the time a put is read are skipped; their removal follows.
+func (r *Registry) watchEvent(ctx context.Context, audit AuditEvent) (registry.WatchEvent, bool) {
+	event := registry.WatchEvent{WorkerID: audit.WorkerID}
+
+	switch audit.Type {
+	case AuditRegistered, AuditAutoRegistered, AuditStatusChanged:
+		worker, err := r.GetWorker(ctx, audit.WorkerID)
+		if err != nil {
+			return event, false
+		}
+		event.Type = registry.WatchEventPut
+		event.Worker = worker
+	case AuditUnregistered, AuditCleanedUp, AuditExpired:
+		event.Type = registry.WatchEventDelete
+	default:
+		return event, false
+	}
+
+	return event, true
+}
+
+// sleep waits for d, returning false if ctx was cancelled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,591 @@
+package registrytest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Harness tells RunConformance how to create and drive the registries under
+// test
+type Harness struct {
+	// New returns an empty registry whose workers expire after ttl without
+	// heartbeats. It is called once per check; backends sharing state
+	// between registries (a database, a key prefix) must clear it.
+	New func(t *testing.T, ttl time.Duration) ports.WorkerRegistry
+
+	// TTL is passed to New. It must leave time for a few round trips to the
+	// backend; 2s by default.
+	TTL time.Duration
+
+	// Now and Sleep are the registry's clock, time.Now and time.Sleep by
+	// default. Registries with a fake clock advance it in Sleep.
+	Now   func() time.Time
+	Sleep func(d time.Duration)
+}
+
+// How long to wait for a watch event or the watch channel to close
+const watchTimeout = 10 * time.Second
+
+// RunConformance checks that the registries created by h.New behave like the
+// Redis registry:
+//
+//   - registered workers are returned by GetWorker and ListWorkers with all
+//     their fields, and unknown ones are an error
+//   - heartbeats update status, current task and last heartbeat, and
+//     auto-register unknown workers with the type inferred from their ID
+//   - workers without heartbeats for longer than the TTL stop being healthy:
+//     they are gone or reported unhealthy, and filtered out by HealthyOnly
+//   - workers declaring their own TTL with worker_registry.SetTTL are
+//     judged by it rather than the registry's
+//   - filters and stats count stale workers as unhealthy
+//   - CleanupStaleWorkers removes exactly the stale workers
+//   - Watch, for registries implementing worker_registry.Watcher, reports
+//     registrations, status changes and removals, and closes its channel
+//     once its context is cancelled
+//   - concurrent registrations, heartbeats and listings don't fail or lose
+//     updates
+//
+// Each check runs as a subtest with its own registry.
+func RunConformance(t *testing.T, h Harness) {
+	t.Helper()
+	if h.TTL == 0 {
+		h.TTL = 2 * time.Second
+	}
+	if h.Now == nil {
+		h.Now = time.Now
+	}
+	if h.Sleep == nil {
+		h.Sleep = time.Sleep
+	}
+
+	checks := []struct {
+		name string
+		run  func(t *testing.T, s *suite)
+	}{
+		{"RegisterAndGet", testRegisterAndGet},
+		{"Unregister", testUnregister},
+		{"Heartbeat", testHeartbeat},
+		{"Expiry", testExpiry},
+		{"PerWorkerTTL", testPerWorkerTTL},
+		{"Filtering", testFiltering},
+		{"Stats", testStats},
+		{"Cleanup", testCleanup},
+		{"Watch", testWatch},
+		{"Concurrency", testConcurrency},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			check.run(t, &suite{r: h.New(t, h.TTL), h: h})
+		})
+	}
+}
+
+type suite struct {
+	r ports.WorkerRegistry
+	h Harness
+}
+
+func (s *suite) register(t *testing.T, ctx context.Context, worker ports.WorkerInfo) {
+	t.Helper()
+	if worker.RegisteredAt.IsZero() {
+		worker.RegisteredAt = s.h.Now()
+	}
+	if worker.LastHeartbeat.IsZero() {
+		worker.LastHeartbeat = s.h.Now()
+	}
+	if err := s.r.Register(ctx, worker); err != nil {
+		t.Fatalf("Register(%s) error = %v", worker.ID, err)
+	}
+}
+
+func (s *suite) heartbeat(t *testing.T, ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) {
+	t.Helper()
+	if err := s.r.Heartbeat(ctx, workerID, status, currentTask); err != nil {
+		t.Fatalf("Heartbeat(%s) error = %v", workerID, err)
+	}
+}
+
+func (s *suite) get(t *testing.T, ctx context.Context, workerID string) *ports.WorkerInfo {
+	t.Helper()
+	worker, err := s.r.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker(%s) error = %v", workerID, err)
+	}
+	return worker
+}
+
+// list returns the sorted IDs of the workers matching filter
+func (s *suite) list(t *testing.T, ctx context.Context, filter ports.WorkerFilter) []string {
+	t.Helper()
+	workers, err := s.r.ListWorkers(ctx, filter)
+	if err != nil {
+		t.Fatalf("ListWorkers(%+v) error = %v", filter, err)
+	}
+	ids := make([]string, 0, len(workers))
+	for _, worker := range workers {
+		ids = append(ids, worker.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// healthy reports whether a worker is registered and not unhealthy
+func (s *suite) healthy(ctx context.Context, workerID string) bool {
+	worker, err := s.r.GetWorker(ctx, workerID)
+	return err == nil && worker.Status != ports.WorkerStatusUnhealthy
+}
+
+// closeTo reports whether two timestamps match, databases storing them with
+// microsecond precision
+func closeTo(a, b time.Time) bool {
+	d := a.Sub(b)
+	return d > -time.Millisecond && d < time.Millisecond
+}
+
+func testRegisterAndGet(t *testing.T, s *suite) {
+	ctx := context.Background()
+	now := s.h.Now()
+
+	s.register(t, ctx, ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusBusy,
+		RegisteredAt:  now.Add(-time.Second),
+		LastHeartbeat: now,
+		CurrentTask:   "task-1",
+		PendingTasks:  3,
+		Version:       "1.2.3",
+		Metadata:      map[string]interface{}{"zone": "eu-1"},
+	})
+
+	worker := s.get(t, ctx, "executor-1")
+	if worker.ID != "executor-1" || worker.Type != ports.WorkerTypeExecutor || worker.Status != ports.WorkerStatusBusy {
+		t.Errorf("GetWorker() = %+v, want busy executor-1", worker)
+	}
+	if worker.CurrentTask != "task-1" || worker.PendingTasks != 3 || worker.Version != "1.2.3" {
+		t.Errorf("GetWorker() = %+v, want task-1, 3 pending tasks and version 1.2.3", worker)
+	}
+	if !closeTo(worker.RegisteredAt, now.Add(-time.Second)) || !closeTo(worker.LastHeartbeat, now) {
+		t.Errorf("GetWorker() timestamps = %v, %v, want %v, %v", worker.RegisteredAt, worker.LastHeartbeat, now.Add(-time.Second), now)
+	}
+	if worker.Metadata["zone"] != "eu-1" {
+		t.Errorf("GetWorker() metadata = %v, want zone eu-1", worker.Metadata)
+	}
+
+	if got := s.list(t, ctx, ports.WorkerFilter{}); !slices.Equal(got, []string{"executor-1"}) {
+		t.Errorf("ListWorkers() = %v, want [executor-1]", got)
+	}
+
+	// Registering again replaces the worker
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+	if worker := s.get(t, ctx, "executor-1"); worker.Status != ports.WorkerStatusIdle || worker.CurrentTask != "" {
+		t.Errorf("GetWorker() after re-registering = %+v, want idle without task", worker)
+	}
+
+	if _, err := s.r.GetWorker(ctx, "missing"); err == nil {
+		t.Error("GetWorker() of an unknown worker succeeded, want an error")
+	}
+}
+
+func testUnregister(t *testing.T, s *suite) {
+	ctx := context.Background()
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+
+	if err := s.r.Unregister(ctx, "executor-1"); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	if _, err := s.r.GetWorker(ctx, "executor-1"); err == nil {
+		t.Error("GetWorker() of an unregistered worker succeeded, want an error")
+	}
+	if got := s.list(t, ctx, ports.WorkerFilter{}); !slices.Equal(got, []string{"executor-2"}) {
+		t.Errorf("ListWorkers() = %v, want [executor-2]", got)
+	}
+
+	// Unregistering is idempotent
+	if err := s.r.Unregister(ctx, "executor-1"); err != nil {
+		t.Errorf("Unregister() of an unknown worker error = %v", err)
+	}
+}
+
+func testHeartbeat(t *testing.T, s *suite) {
+	ctx := context.Background()
+	registered := s.h.Now().Add(-time.Second)
+	s.register(t, ctx, ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  registered,
+		LastHeartbeat: registered,
+		Version:       "1.2.3",
+	})
+
+	s.heartbeat(t, ctx, "executor-1", ports.WorkerStatusBusy, "task-1")
+	worker := s.get(t, ctx, "executor-1")
+	if worker.Status != ports.WorkerStatusBusy || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want busy on task-1", worker)
+	}
+	if !worker.LastHeartbeat.After(registered) {
+		t.Errorf("LastHeartbeat = %v, want after %v", worker.LastHeartbeat, registered)
+	}
+	if worker.Type != ports.WorkerTypeExecutor || worker.Version != "1.2.3" || !closeTo(worker.RegisteredAt, registered) {
+		t.Errorf("GetWorker() = %+v, want type, version and registration time kept", worker)
+	}
+
+	s.heartbeat(t, ctx, "executor-1", ports.WorkerStatusIdle, "")
+	if worker := s.get(t, ctx, "executor-1"); worker.Status != ports.WorkerStatusIdle || worker.CurrentTask != "" {
+		t.Errorf("GetWorker() = %+v, want idle without task", worker)
+	}
+
+	// Unknown workers are registered, with the type their ID implies
+	s.heartbeat(t, ctx, "router-auto-1", ports.WorkerStatusBusy, "task-2")
+	worker = s.get(t, ctx, "router-auto-1")
+	if worker.Type != ports.WorkerTypeRouter || worker.Status != ports.WorkerStatusBusy || worker.CurrentTask != "task-2" {
+		t.Errorf("GetWorker() of an auto-registered worker = %+v, want busy router on task-2", worker)
+	}
+}
+
+func testExpiry(t *testing.T, s *suite) {
+	ctx := context.Background()
+	ttl := s.h.TTL
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+
+	// Heartbeats keep executor-1 alive past the TTL
+	s.h.Sleep(ttl / 2)
+	s.heartbeat(t, ctx, "executor-1", ports.WorkerStatusIdle, "")
+	s.h.Sleep(ttl*3/4 + ttl/10)
+
+	if !s.healthy(ctx, "executor-1") {
+		t.Error("executor-1 isn't healthy, want it kept alive by its heartbeat")
+	}
+	if s.healthy(ctx, "executor-2") {
+		t.Error("executor-2 is healthy past its TTL")
+	}
+	if got := s.list(t, ctx, ports.WorkerFilter{HealthyOnly: true}); !slices.Equal(got, []string{"executor-1"}) {
+		t.Errorf("ListWorkers(HealthyOnly) = %v, want [executor-1]", got)
+	}
+
+	// Without heartbeats, it expires too
+	s.h.Sleep(ttl)
+	if s.healthy(ctx, "executor-1") {
+		t.Error("executor-1 is healthy past its TTL")
+	}
+	if got := s.list(t, ctx, ports.WorkerFilter{HealthyOnly: true}); len(got) != 0 {
+		t.Errorf("ListWorkers(HealthyOnly) = %v, want none", got)
+	}
+
+	// A heartbeat brings an expired worker back
+	s.heartbeat(t, ctx, "executor-2", ports.WorkerStatusBusy, "task-1")
+	if !s.healthy(ctx, "executor-2") {
+		t.Error("executor-2 isn't healthy after a heartbeat")
+	}
+}
+
+func testPerWorkerTTL(t *testing.T, s *suite) {
+	ctx := context.Background()
+	ttl := s.h.TTL
+	fast := ports.WorkerInfo{ID: "executor-fast", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle}
+	registry.SetTTL(&fast, ttl/2)
+	slow := ports.WorkerInfo{ID: "executor-slow", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle}
+	registry.SetTTL(&slow, 3*ttl)
+	s.register(t, ctx, fast)
+	s.register(t, ctx, slow)
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+
+	// Past its own TTL, but not the registry's
+	s.h.Sleep(ttl*3/4 + ttl/10)
+	if s.healthy(ctx, "executor-fast") {
+		t.Error("executor-fast is healthy past its own TTL")
+	}
+	if !s.healthy(ctx, "executor-1") || !s.healthy(ctx, "executor-slow") {
+		t.Error("executor-1 or executor-slow isn't healthy within its TTL")
+	}
+	if got := s.list(t, ctx, ports.WorkerFilter{HealthyOnly: true}); !slices.Equal(got, []string{"executor-1", "executor-slow"}) {
+		t.Errorf("ListWorkers(HealthyOnly) = %v, want [executor-1 executor-slow]", got)
+	}
+
+	// Past the registry's TTL, but not executor-slow's
+	s.h.Sleep(ttl / 2)
+	if s.healthy(ctx, "executor-1") {
+		t.Error("executor-1 is healthy past the registry's TTL")
+	}
+	if !s.healthy(ctx, "executor-slow") {
+		t.Error("executor-slow isn't healthy within its own TTL")
+	}
+	if got := s.list(t, ctx, ports.WorkerFilter{HealthyOnly: true}); !slices.Equal(got, []string{"executor-slow"}) {
+		t.Errorf("ListWorkers(HealthyOnly) = %v, want [executor-slow]", got)
+	}
+
+	// Heartbeats keep the declared TTL
+	s.heartbeat(t, ctx, "executor-slow", ports.WorkerStatusBusy, "task-1")
+	s.h.Sleep(ttl + ttl/10)
+	if worker := s.get(t, ctx, "executor-slow"); worker.Status != ports.WorkerStatusBusy {
+		t.Errorf("GetWorker(executor-slow) = %+v, want busy within its own TTL after a heartbeat", worker)
+	}
+}
+
+// registerMixed registers healthy executors and routers of every status, and
+// a stale executor whose last heartbeat is older than the TTL
+func (s *suite) registerMixed(t *testing.T, ctx context.Context) {
+	now := s.h.Now()
+	for _, worker := range []ports.WorkerInfo{
+		{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, PendingTasks: 1},
+		{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, PendingTasks: 2},
+		{ID: "executor-3", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now.Add(-2 * s.h.TTL)},
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusIdle},
+		{ID: "router-2", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusBusy, PendingTasks: 4},
+	} {
+		s.register(t, ctx, worker)
+	}
+}
+
+func testFiltering(t *testing.T, s *suite) {
+	ctx := context.Background()
+	s.registerMixed(t, ctx)
+
+	tests := []struct {
+		name   string
+		filter ports.WorkerFilter
+		want   []string
+	}{
+		{"all", ports.WorkerFilter{}, []string{"executor-1", "executor-2", "executor-3", "router-1", "router-2"}},
+		{"executors", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}}, []string{"executor-1", "executor-2", "executor-3"}},
+		{"both types", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor, ports.WorkerTypeRouter}}, []string{"executor-1", "executor-2", "executor-3", "router-1", "router-2"}},
+		{"idle", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle}}, []string{"executor-1", "router-1"}},
+		{"unhealthy", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusUnhealthy}}, []string{"executor-3"}},
+		{"idle or busy", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusIdle, ports.WorkerStatusBusy}}, []string{"executor-1", "executor-2", "router-1", "router-2"}},
+		{"healthy only", ports.WorkerFilter{HealthyOnly: true}, []string{"executor-1", "executor-2", "router-1", "router-2"}},
+		{"busy routers", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeRouter}, Statuses: []ports.WorkerStatus{ports.WorkerStatusBusy}}, []string{"router-2"}},
+		{"healthy executors", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}, HealthyOnly: true}, []string{"executor-1", "executor-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.list(t, ctx, tt.filter); !slices.Equal(got, tt.want) {
+				t.Errorf("ListWorkers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	workers, err := s.r.ListWorkers(ctx, ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeExecutor}})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	for _, worker := range workers {
+		if worker.ID == "executor-3" && worker.Status != ports.WorkerStatusUnhealthy {
+			t.Errorf("stale worker status = %s, want unhealthy", worker.Status)
+		}
+	}
+}
+
+func testStats(t *testing.T, s *suite) {
+	ctx := context.Background()
+	s.registerMixed(t, ctx)
+
+	tests := []struct {
+		workerType ports.WorkerType
+		want       ports.WorkerStats
+	}{
+		{ports.WorkerTypeExecutor, ports.WorkerStats{Type: ports.WorkerTypeExecutor, TotalWorkers: 3, IdleWorkers: 1, BusyWorkers: 1, UnhealthyWorkers: 1, TotalPendingTasks: 3}},
+		{ports.WorkerTypeRouter, ports.WorkerStats{Type: ports.WorkerTypeRouter, TotalWorkers: 2, IdleWorkers: 1, BusyWorkers: 1, TotalPendingTasks: 4}},
+	}
+	for _, tt := range tests {
+		stats, err := s.r.GetWorkerStats(ctx, tt.workerType)
+		if err != nil {
+			t.Fatalf("GetWorkerStats(%s) error = %v", tt.workerType, err)
+		}
+		if *stats != tt.want {
+			t.Errorf("GetWorkerStats(%s) = %+v, want %+v", tt.workerType, *stats, tt.want)
+		}
+	}
+}
+
+func testCleanup(t *testing.T, s *suite) {
+	ctx := context.Background()
+	s.registerMixed(t, ctx)
+
+	cleaned, err := s.r.CleanupStaleWorkers(ctx, s.h.TTL)
+	if err != nil {
+		t.Fatalf("CleanupStaleWorkers() error = %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("CleanupStaleWorkers() = %d, want 1", cleaned)
+	}
+	if _, err := s.r.GetWorker(ctx, "executor-3"); err == nil {
+		t.Error("GetWorker() of a cleaned up worker succeeded, want an error")
+	}
+	if got := s.list(t, ctx, ports.WorkerFilter{}); !slices.Equal(got, []string{"executor-1", "executor-2", "router-1", "router-2"}) {
+		t.Errorf("ListWorkers() after cleanup = %v, want the healthy workers", got)
+	}
+
+	// Nothing left to clean up
+	if cleaned, err := s.r.CleanupStaleWorkers(ctx, s.h.TTL); err != nil || cleaned != 0 {
+		t.Errorf("CleanupStaleWorkers() again = %d, %v, want 0", cleaned, err)
+	}
+}
+
+// nextEvent returns the next event about workerID, skipping others
+func nextEvent(t *testing.T, events <-chan registry.WatchEvent, workerID string) registry.WatchEvent {
+	t.Helper()
+	timeout := time.After(watchTimeout)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("watch channel closed while waiting for an event about %s", workerID)
+			}
+			if event.WorkerID == workerID {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no watch event about %s after %s", workerID, watchTimeout)
+		}
+	}
+}
+
+func testWatch(t *testing.T, s *suite) {
+	watcher, ok := s.r.(registry.Watcher)
+	if !ok {
+		t.Skip("registry doesn't implement worker_registry.Watcher")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := watcher.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+	event := nextEvent(t, events, "executor-1")
+	if event.Type != registry.WatchEventPut || event.Worker == nil || event.Worker.Status != ports.WorkerStatusIdle {
+		t.Fatalf("event after Register = %+v, want put of the idle worker", event)
+	}
+
+	// Status changes are reported with the new state; heartbeats keeping the
+	// status may or may not be
+	s.heartbeat(t, ctx, "executor-1", ports.WorkerStatusBusy, "task-1")
+	for {
+		event = nextEvent(t, events, "executor-1")
+		if event.Type != registry.WatchEventPut || event.Worker == nil {
+			t.Fatalf("event after Heartbeat = %+v, want put", event)
+		}
+		if event.Worker.Status == ports.WorkerStatusBusy {
+			break
+		}
+	}
+	if event.Worker.CurrentTask != "task-1" {
+		t.Errorf("event after Heartbeat = %+v, want task-1", event.Worker)
+	}
+
+	if err := s.r.Unregister(ctx, "executor-1"); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	for {
+		event = nextEvent(t, events, "executor-1")
+		if event.Type == registry.WatchEventDelete {
+			break
+		}
+	}
+	if event.Worker != nil {
+		t.Errorf("delete event = %+v, want no worker", event)
+	}
+
+	cancel()
+	timeout := time.After(watchTimeout)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("watch channel still open %s after cancelling", watchTimeout)
+		}
+	}
+}
+
+func testConcurrency(t *testing.T, s *suite) {
+	ctx := context.Background()
+	const workers = 8
+	const heartbeats = 10
+
+	s.register(t, ctx, ports.WorkerInfo{ID: "executor-shared", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*(heartbeats+2)+workers*heartbeats)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("executor-%d", i)
+			now := s.h.Now()
+			if err := s.r.Register(ctx, ports.WorkerInfo{
+				ID:            id,
+				Type:          ports.WorkerTypeExecutor,
+				Status:        ports.WorkerStatusIdle,
+				RegisteredAt:  now,
+				LastHeartbeat: now,
+			}); err != nil {
+				errs <- fmt.Errorf("Register(%s): %w", id, err)
+				return
+			}
+			for j := range heartbeats {
+				status := ports.WorkerStatusIdle
+				if j%2 == 0 {
+					status = ports.WorkerStatusBusy
+				}
+				if err := s.r.Heartbeat(ctx, id, status, ""); err != nil {
+					errs <- fmt.Errorf("Heartbeat(%s): %w", id, err)
+				}
+
+				// Everyone also updates the shared worker
+				task := fmt.Sprintf("task-%d-%d", i, j)
+				if err := s.r.Heartbeat(ctx, "executor-shared", ports.WorkerStatusBusy, task); err != nil {
+					errs <- fmt.Errorf("Heartbeat(executor-shared): %w", err)
+				}
+			}
+			if _, err := s.r.ListWorkers(ctx, ports.WorkerFilter{}); err != nil {
+				errs <- fmt.Errorf("ListWorkers(): %w", err)
+			}
+			if _, err := s.r.GetWorkerStats(ctx, ports.WorkerTypeExecutor); err != nil {
+				errs <- fmt.Errorf("GetWorkerStats(): %w", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// The last heartbeat of each worker set it idle
+	for i := range workers {
+		id := fmt.Sprintf("executor-%d", i)
+		if worker := s.get(t, ctx, id); worker.Status != ports.WorkerStatusIdle {
+			t.Errorf("GetWorker(%s) status = %s, want idle", id, worker.Status)
+		}
+	}
+
+	// The shared worker holds one of the heartbeats, not a mix
+	shared := s.get(t, ctx, "executor-shared")
+	var i, j int
+	if n, _ := fmt.Sscanf(shared.CurrentTask, "task-%d-%d", &i, &j); n != 2 || shared.Status != ports.WorkerStatusBusy {
+		t.Errorf("GetWorker(executor-shared) = %+v, want busy on one of the tasks", shared)
+	}
+
+	stats, err := s.r.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	if stats.TotalWorkers != workers+1 || stats.IdleWorkers != workers || stats.BusyWorkers != 1 {
+		t.Errorf("GetWorkerStats() = %+v, want %d idle workers and the busy shared one", stats, workers)
+	}
+}
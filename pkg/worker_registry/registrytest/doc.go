@@ -0,0 +1,25 @@
+// Package registrytest provides a conformance suite for ports.WorkerRegistry
+// implementations, so that every backend behaves like the Redis registry the
+// others were modeled on: registration, heartbeats and auto-registration,
+// expiry, filtering, stats, cleanup of stale workers, Watch semantics and
+// concurrent use.
+//
+// Backends differ in how expired workers disappear: Redis, etcd and the
+// memory registry drop them, Postgres and SQLite keep them as unhealthy until
+// CleanupStaleWorkers. The suite accepts both. Watch is checked for registries
+// implementing worker_registry.Watcher.
+//
+// Usage:
+//
+//	func TestConformance(t *testing.T) {
+//		registrytest.RunConformance(t, registrytest.Harness{
+//			New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+//				return memory.NewRegistryWithTTL(ttl, zap.NewNop())
+//			},
+//		})
+//	}
+//
+// Backends sharing state between the registries New returns must clear it,
+// e.g. truncate the table or use a fresh key prefix. Registries with a fake
+// clock pass it as Harness.Now and advance it in Harness.Sleep.
+package registrytest
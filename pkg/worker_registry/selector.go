@@ -0,0 +1,218 @@
+package worker_registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// Operator is the comparison applied by a selector Requirement.
+type Operator string
+
+const (
+	// OpEquals matches when the key has the value.
+	OpEquals Operator = "="
+
+	// OpNotEquals matches when the key is absent or lacks the value.
+	OpNotEquals Operator = "!="
+
+	// OpIn matches when the key has any of the values.
+	OpIn Operator = "in"
+
+	// OpNotIn matches when the key is absent or has none of the values.
+	OpNotIn Operator = "notin"
+
+	// OpExists matches when the key is present.
+	OpExists Operator = "exists"
+
+	// OpDoesNotExist matches when the key is absent.
+	OpDoesNotExist Operator = "!"
+)
+
+// Requirement is a single selector term such as gpu=true or region in (eu,us).
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector matches workers on their labels and capabilities. All requirements
+// must hold. A capability matches a value when any of its values is equal, so
+// models=llama3.1 selects workers that can serve llama3.1 among other models.
+type Selector []Requirement
+
+// ParseSelector parses a comma-separated selector in the Kubernetes label
+// selector syntax:
+//
+//	gpu=true,region!=us,models in (llama3.1,mistral),zone notin (a),ssd,!spot
+//
+// An empty string yields an empty selector, which matches every worker.
+func ParseSelector(s string) (Selector, error) {
+	var selector Selector
+
+	for _, term := range splitTerms(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		req, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, req)
+	}
+
+	return selector, nil
+}
+
+// Matches reports whether the worker satisfies every requirement.
+func (s Selector) Matches(worker ports.WorkerInfo) bool {
+	if len(s) == 0 {
+		return true
+	}
+
+	values := make(map[string][]string)
+	for k, v := range Capabilities(worker) {
+		values[k] = v
+	}
+	for k, v := range Labels(worker) {
+		values[k] = append(values[k], v)
+	}
+
+	for _, req := range s {
+		if !req.matches(values) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ListWorkersWithSelector lists workers matching both the filter and the
+// selector. Selectors are applied client-side, so any registry supports them.
+func ListWorkersWithSelector(ctx context.Context, registry ports.WorkerRegistry, filter ports.WorkerFilter, selector Selector) ([]ports.WorkerInfo, error) {
+	workers, err := registry.ListWorkers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selector) == 0 {
+		return workers, nil
+	}
+
+	var selected []ports.WorkerInfo
+	for _, worker := range workers {
+		if selector.Matches(worker) {
+			selected = append(selected, worker)
+		}
+	}
+
+	return selected, nil
+}
+
+func (req Requirement) matches(values map[string][]string) bool {
+	have, ok := values[req.Key]
+
+	switch req.Operator {
+	case OpExists:
+		return ok
+	case OpDoesNotExist:
+		return !ok
+	case OpEquals, OpIn:
+		return containsAny(have, req.Values)
+	case OpNotEquals, OpNotIn:
+		return !containsAny(have, req.Values)
+	}
+
+	return false
+}
+
+func containsAny(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitTerms splits on commas outside parentheses
+func splitTerms(s string) []string {
+	var terms []string
+	depth, start := 0, 0
+
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(terms, s[start:])
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	for _, op := range []Operator{OpNotIn, OpIn} {
+		if key, set, ok := strings.Cut(term, " "+string(op)+" "); ok {
+			values, err := parseSet(set)
+			if err != nil {
+				return Requirement{}, fmt.Errorf("invalid selector term %q: %w", term, err)
+			}
+			return newRequirement(term, key, op, values)
+		}
+	}
+
+	if key, value, ok := strings.Cut(term, "!="); ok {
+		return newRequirement(term, key, OpNotEquals, []string{strings.TrimSpace(value)})
+	}
+	if key, value, ok := strings.Cut(term, "=="); ok {
+		return newRequirement(term, key, OpEquals, []string{strings.TrimSpace(value)})
+	}
+	if key, value, ok := strings.Cut(term, "="); ok {
+		return newRequirement(term, key, OpEquals, []string{strings.TrimSpace(value)})
+	}
+	if key, ok := strings.CutPrefix(term, "!"); ok {
+		return newRequirement(term, key, OpDoesNotExist, nil)
+	}
+
+	return newRequirement(term, term, OpExists, nil)
+}
+
+func newRequirement(term, key string, op Operator, values []string) (Requirement, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, " ()=!,") {
+		return Requirement{}, fmt.Errorf("invalid selector term %q: bad key", term)
+	}
+	return Requirement{Key: key, Operator: op, Values: values}, nil
+}
+
+func parseSet(set string) ([]string, error) {
+	set = strings.TrimSpace(set)
+	if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+		return nil, fmt.Errorf("value set must be parenthesized")
+	}
+
+	var values []string
+	for _, v := range strings.Split(set[1:len(set)-1], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("value set is empty")
+	}
+
+	return values, nil
+}
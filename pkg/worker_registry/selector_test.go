@@ -0,0 +1,99 @@
+package worker_registry
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Selector
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "gpu=true", want: Selector{{Key: "gpu", Operator: OpEquals, Values: []string{"true"}}}},
+		{input: "gpu==true", want: Selector{{Key: "gpu", Operator: OpEquals, Values: []string{"true"}}}},
+		{input: "region != us", want: Selector{{Key: "region", Operator: OpNotEquals, Values: []string{"us"}}}},
+		{
+			input: "models in (llama3.1, mistral),zone notin (a)",
+			want: Selector{
+				{Key: "models", Operator: OpIn, Values: []string{"llama3.1", "mistral"}},
+				{Key: "zone", Operator: OpNotIn, Values: []string{"a"}},
+			},
+		},
+		{
+			input: "ssd, !spot",
+			want: Selector{
+				{Key: "ssd", Operator: OpExists},
+				{Key: "spot", Operator: OpDoesNotExist},
+			},
+		},
+		{input: "=true", wantErr: true},
+		{input: "models in llama3.1", wantErr: true},
+		{input: "models in ()", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSelector(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSelector() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	worker := ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor}
+	SetLabels(&worker, map[string]string{"gpu": "true", "region": "eu"})
+	SetCapabilities(&worker, map[string][]string{"models": {"llama3.1", "mistral"}})
+
+	// Backends return metadata decoded from JSON rather than the original types
+	data, err := json.Marshal(worker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ports.WorkerInfo
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"gpu=true", true},
+		{"gpu=false", false},
+		{"gpu=true,region=eu", true},
+		{"region!=us", true},
+		{"region in (us,ap)", false},
+		{"models=llama3.1", true},
+		{"models in (phi3,mistral)", true},
+		{"models notin (mistral)", false},
+		{"zone!=a", true},
+		{"ssd", false},
+		{"!spot", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseSelector() error = %v", err)
+			}
+			for name, w := range map[string]ports.WorkerInfo{"native": worker, "decoded": decoded} {
+				if got := selector.Matches(w); got != tt.want {
+					t.Errorf("%s: Matches() = %v, want %v", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Package sqlite provides an embedded SQLite implementation of the
+// WorkerRegistry interface.
+//
+// This implementation targets edge and single-node installs where running a
+// separate datastore is overkill. It uses the pure-Go modernc.org/sqlite
+// driver, so no cgo toolchain is needed.
+//
+// Key Design:
+//   - Worker data is stored in the dago_workers table, one row per worker
+//   - Timestamps are stored as Unix nanoseconds
+//   - Every write sets expires_at = now + TTL; reads ignore expired rows,
+//     matching the key expiry semantics of the Redis implementation
+//   - Open enables WAL mode and a busy timeout so several worker processes
+//     on the same host can share one database file
+//
+// Usage:
+//
+//	db, _ := sqlite.Open("/var/lib/dago/workers.db")
+//	registry := sqlite.NewRegistry(db, logger)
+//
+//	// Create the schema
+//	if err := registry.Migrate(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Send heartbeat every 10 seconds
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+//
+//	// Expired rows are hidden from reads; CleanupStaleWorkers deletes them
+//	registry.CleanupStaleWorkers(ctx, time.Minute)
+package sqlite
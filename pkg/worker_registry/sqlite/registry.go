@@ -0,0 +1,383 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// Default TTL for worker heartbeats (30 seconds)
+	defaultWorkerTTL = 30 * time.Second
+
+	// How long a connection waits for another process's write lock
+	busyTimeout = 5 * time.Second
+
+	// Columns selected for every worker read, in scanWorker order
+	workerColumns = `id, type, status, registered_at, last_heartbeat,
+		current_task, pending_tasks, version, metadata`
+)
+
+//go:embed schema.sql
+var schema string
+
+// Registry implements ports.WorkerRegistry using SQLite
+type Registry struct {
+	db     *sql.DB
+	logger *zap.Logger
+	ttl    time.Duration
+}
+
+// Open opens (or creates) a SQLite database file configured for concurrent
+// access from several processes: WAL journal mode, a busy timeout and
+// NORMAL synchronous mode.
+func Open(path string) (*sql.DB, error) {
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "journal_mode(WAL)")
+	pragmas.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	pragmas.Add("_pragma", "synchronous(NORMAL)")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+pragmas.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	return db, nil
+}
+
+// NewRegistry creates a new SQLite worker registry
+func NewRegistry(db *sql.DB, logger *zap.Logger) *Registry {
+	return &Registry{
+		db:     db,
+		logger: logger,
+		ttl:    defaultWorkerTTL,
+	}
+}
+
+// NewRegistryWithTTL creates a new SQLite worker registry with custom TTL
+func NewRegistryWithTTL(db *sql.DB, ttl time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{
+		db:     db,
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// Migrate creates the workers table and its indexes if they don't exist
+func (r *Registry) Migrate(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+	return nil
+}
+
+// Register registers a new worker in the system
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	now := time.Now()
+	if worker.RegisteredAt.IsZero() {
+		worker.RegisteredAt = now
+	}
+	if worker.LastHeartbeat.IsZero() {
+		worker.LastHeartbeat = now
+	}
+
+	metadata, err := marshalMetadata(worker.Metadata)
+	if err != nil {
+		return err
+	}
+	ttl := registry.WorkerTTL(worker, r.ttl)
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO dago_workers
+			(id, type, status, registered_at, last_heartbeat, expires_at,
+			 current_task, pending_tasks, version, metadata)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			type           = excluded.type,
+			status         = excluded.status,
+			registered_at  = excluded.registered_at,
+			last_heartbeat = excluded.last_heartbeat,
+			expires_at     = excluded.expires_at,
+			current_task   = excluded.current_task,
+			pending_tasks  = excluded.pending_tasks,
+			version        = excluded.version,
+			metadata       = excluded.metadata`,
+		worker.ID, string(worker.Type), string(worker.Status),
+		worker.RegisteredAt.UnixNano(), worker.LastHeartbeat.UnixNano(), now.Add(ttl).UnixNano(),
+		worker.CurrentTask, worker.PendingTasks, worker.Version, metadata)
+	if err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.Duration("ttl", ttl))
+
+	return nil
+}
+
+// Unregister removes a worker from the registry
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM dago_workers WHERE id = ?`, workerID); err != nil {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the last heartbeat timestamp for a worker and extends its
+// expiry by its own TTL, if it declared one. Unknown or expired workers are
+// auto-registered, mirroring the Redis implementation.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	now := time.Now()
+	expiresAt := now.Add(r.ttl).UnixNano()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE dago_workers
+		SET status = ?, last_heartbeat = ?, current_task = ?,
+			expires_at = ? + CASE
+				WHEN json_extract(metadata, '$.`+registry.MetadataTTL+`') > 0
+				THEN json_extract(metadata, '$.`+registry.MetadataTTL+`') * 1000000
+				ELSE ? END
+		WHERE id = ? AND expires_at > ?`,
+		string(status), now.UnixNano(), currentTask, now.UnixNano(), r.ttl.Nanoseconds(), workerID, now.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+	if updated > 0 {
+		return nil
+	}
+
+	// Worker not found, this shouldn't happen but we can recover
+	r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+		zap.String("worker_id", workerID))
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO dago_workers
+			(id, type, status, registered_at, last_heartbeat, expires_at, current_task)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			type           = excluded.type,
+			status         = excluded.status,
+			registered_at  = excluded.registered_at,
+			last_heartbeat = excluded.last_heartbeat,
+			expires_at     = excluded.expires_at,
+			current_task   = excluded.current_task,
+			pending_tasks  = 0,
+			version        = '',
+			metadata       = NULL`,
+		workerID, string(registry.InferWorkerType(workerID)), string(status),
+		now.UnixNano(), now.UnixNano(), expiresAt, currentTask)
+	if err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+workerColumns+` FROM dago_workers WHERE id = ? AND expires_at > ?`,
+		workerID, time.Now().UnixNano())
+
+	worker, err := scanWorker(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("worker not found: %s", workerID)
+		}
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	// Check if worker is healthy based on last heartbeat
+	if time.Since(worker.LastHeartbeat) > registry.WorkerTTL(*worker, r.ttl) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria, ordered by ID
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	query, args := buildListQuery(filter, time.Now())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var workers []ports.WorkerInfo
+
+	for rows.Next() {
+		worker, err := scanWorker(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan worker: %w", err)
+		}
+
+		// Check if worker is healthy
+		isHealthy := time.Since(worker.LastHeartbeat) <= registry.WorkerTTL(*worker, r.ttl)
+		if !isHealthy {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		// Apply remaining filters (status is checked against the derived status)
+		if !registry.MatchesFilter(*worker, filter, isHealthy) {
+			continue
+		}
+
+		workers = append(workers, *worker)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes expired workers and workers that haven't sent a
+// heartbeat within the timeout
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	now := time.Now()
+
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM dago_workers WHERE expires_at <= ? OR last_heartbeat < ?`,
+		now.UnixNano(), now.Add(-timeout).UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup stale workers: %w", err)
+	}
+
+	cleaned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup stale workers: %w", err)
+	}
+
+	if cleaned > 0 {
+		r.logger.Info("cleaned up stale workers",
+			zap.Int64("count", cleaned),
+			zap.Duration("timeout", timeout))
+	}
+
+	return int(cleaned), nil
+}
+
+// Helper methods
+
+// buildListQuery selects unexpired workers, pushing the type filter into SQL
+func buildListQuery(filter ports.WorkerFilter, now time.Time) (string, []interface{}) {
+	query := `SELECT ` + workerColumns + ` FROM dago_workers WHERE expires_at > ?`
+	args := []interface{}{now.UnixNano()}
+
+	// Filter by type
+	if len(filter.Types) > 0 {
+		placeholders := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			placeholders[i] = "?"
+			args = append(args, string(t))
+		}
+		query += ` AND type IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+
+	query += ` ORDER BY id`
+
+	return query, args
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWorker(row rowScanner) (*ports.WorkerInfo, error) {
+	var worker ports.WorkerInfo
+	var workerType, status string
+	var registeredAt, lastHeartbeat int64
+	var metadata sql.NullString
+
+	if err := row.Scan(
+		&worker.ID,
+		&workerType,
+		&status,
+		&registeredAt,
+		&lastHeartbeat,
+		&worker.CurrentTask,
+		&worker.PendingTasks,
+		&worker.Version,
+		&metadata,
+	); err != nil {
+		return nil, err
+	}
+
+	worker.Type = ports.WorkerType(workerType)
+	worker.Status = ports.WorkerStatus(status)
+	worker.RegisteredAt = time.Unix(0, registeredAt)
+	worker.LastHeartbeat = time.Unix(0, lastHeartbeat)
+
+	if metadata.Valid && metadata.String != "" {
+		if err := json.Unmarshal([]byte(metadata.String), &worker.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal worker metadata: %w", err)
+		}
+	}
+
+	return &worker, nil
+}
+
+func marshalMetadata(metadata map[string]interface{}) (interface{}, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal worker metadata: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,250 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-adapters/pkg/worker_registry/registrytest"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"go.uber.org/zap"
+)
+
+func newTestRegistry(t *testing.T, path string) (*Registry, *sql.DB) {
+	t.Helper()
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	r := NewRegistryWithTTL(db, 10*time.Second, zap.NewNop())
+	if err := r.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	return r, db
+}
+
+func TestOpen_EnablesWAL(t *testing.T) {
+	_, db := newTestRegistry(t, filepath.Join(t.TempDir(), "workers.db"))
+
+	var mode string
+	if err := db.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil {
+		t.Fatalf("PRAGMA journal_mode error = %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("journal_mode = %q, want wal", mode)
+	}
+}
+
+func TestRegistry_RegisterAndGet(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestRegistry(t, filepath.Join(t.TempDir(), "workers.db"))
+
+	now := time.Now()
+	worker := ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		RegisteredAt:  now,
+		LastHeartbeat: now,
+		PendingTasks:  2,
+		Version:       "1.0.0",
+		Metadata:      map[string]interface{}{"zone": "edge-1"},
+	}
+	if err := r.Register(ctx, worker); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	got, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if !got.RegisteredAt.Equal(now) || !got.LastHeartbeat.Equal(now) {
+		t.Errorf("GetWorker() timestamps = %v/%v, want %v", got.RegisteredAt, got.LastHeartbeat, now)
+	}
+	got.RegisteredAt, got.LastHeartbeat = worker.RegisteredAt, worker.LastHeartbeat
+	if !reflect.DeepEqual(*got, worker) {
+		t.Errorf("GetWorker() = %+v, want %+v", *got, worker)
+	}
+
+	if err := r.Unregister(ctx, "executor-1"); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	if _, err := r.GetWorker(ctx, "executor-1"); err == nil {
+		t.Error("GetWorker() after Unregister error = nil, want not found")
+	}
+}
+
+func TestRegistry_HeartbeatAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	r, db := newTestRegistry(t, filepath.Join(t.TempDir(), "workers.db"))
+
+	// Unknown workers are auto-registered
+	if err := r.Heartbeat(ctx, "router-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	got, err := r.GetWorker(ctx, "router-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if got.Type != ports.WorkerTypeRouter || got.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want router on task-1", got)
+	}
+
+	// Expired rows are invisible, like an expired Redis key
+	if _, err := db.Exec(`UPDATE dago_workers SET expires_at = ? WHERE id = ?`,
+		time.Now().Add(-time.Second).UnixNano(), "router-1"); err != nil {
+		t.Fatalf("expire row error = %v", err)
+	}
+	if _, err := r.GetWorker(ctx, "router-1"); err == nil {
+		t.Error("GetWorker() on expired worker error = nil, want not found")
+	}
+
+	// A heartbeat after expiry re-registers the worker
+	if err := r.Heartbeat(ctx, "router-1", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if _, err := r.GetWorker(ctx, "router-1"); err != nil {
+		t.Errorf("GetWorker() after heartbeat error = %v", err)
+	}
+}
+
+func TestRegistry_ListStatsAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestRegistry(t, filepath.Join(t.TempDir(), "workers.db"))
+
+	now := time.Now()
+	workers := []ports.WorkerInfo{
+		{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+		{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, LastHeartbeat: now, PendingTasks: 3},
+		{ID: "executor-3", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now.Add(-time.Minute)},
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+	}
+	for _, w := range workers {
+		if err := r.Register(ctx, w); err != nil {
+			t.Fatalf("Register(%s) error = %v", w.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter ports.WorkerFilter
+		want   []string
+	}{
+		{"all", ports.WorkerFilter{}, []string{"executor-1", "executor-2", "executor-3", "router-1"}},
+		{"routers", ports.WorkerFilter{Types: []ports.WorkerType{ports.WorkerTypeRouter}}, []string{"router-1"}},
+		{"unhealthy", ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusUnhealthy}}, []string{"executor-3"}},
+		{"healthy executors", ports.WorkerFilter{
+			Types:       []ports.WorkerType{ports.WorkerTypeExecutor},
+			HealthyOnly: true,
+		}, []string{"executor-1", "executor-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.ListWorkers(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListWorkers() error = %v", err)
+			}
+			var ids []string
+			for _, w := range got {
+				ids = append(ids, w.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ListWorkers() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	stats, err := r.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	want := &ports.WorkerStats{
+		Type:              ports.WorkerTypeExecutor,
+		TotalWorkers:      3,
+		IdleWorkers:       1,
+		BusyWorkers:       1,
+		UnhealthyWorkers:  1,
+		TotalPendingTasks: 3,
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("GetWorkerStats() = %+v, want %+v", stats, want)
+	}
+
+	cleaned, err := r.CleanupStaleWorkers(ctx, 30*time.Second)
+	if err != nil {
+		t.Fatalf("CleanupStaleWorkers() error = %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("CleanupStaleWorkers() = %d, want 1", cleaned)
+	}
+}
+
+// Two handles on the same file stand in for two worker processes on one host
+func TestRegistry_ConcurrentProcesses(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "workers.db")
+
+	r1, _ := newTestRegistry(t, path)
+	r2, _ := newTestRegistry(t, path)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+
+	for i, r := range []*Registry{r1, r2} {
+		for j := 0; j < 5; j++ {
+			wg.Add(1)
+			go func(r *Registry, id string) {
+				defer wg.Done()
+				for k := 0; k < 10; k++ {
+					if err := r.Heartbeat(ctx, id, ports.WorkerStatusBusy, fmt.Sprintf("task-%d", k)); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}(r, fmt.Sprintf("executor-%d-%d", i, j))
+		}
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Heartbeat() error = %v", err)
+	}
+
+	workers, err := r2.ListWorkers(ctx, ports.WorkerFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if len(workers) != 10 {
+		t.Errorf("ListWorkers() returned %d workers, want 10", len(workers))
+	}
+}
+
+func TestConformance(t *testing.T) {
+	registrytest.RunConformance(t, registrytest.Harness{
+		New: func(t *testing.T, ttl time.Duration) ports.WorkerRegistry {
+			db, err := Open(filepath.Join(t.TempDir(), "workers.db"))
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			t.Cleanup(func() { _ = db.Close() })
+
+			r := NewRegistryWithTTL(db, ttl, zap.NewNop())
+			if err := r.Migrate(context.Background()); err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			return r
+		},
+		TTL: time.Second,
+	})
+}
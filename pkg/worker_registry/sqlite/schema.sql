@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS dago_workers (
+    id             TEXT PRIMARY KEY,
+    type           TEXT NOT NULL,
+    status         TEXT NOT NULL,
+    registered_at  INTEGER NOT NULL,
+    last_heartbeat INTEGER NOT NULL,
+    expires_at     INTEGER NOT NULL,
+    current_task   TEXT NOT NULL DEFAULT '',
+    pending_tasks  INTEGER NOT NULL DEFAULT 0,
+    version        TEXT NOT NULL DEFAULT '',
+    metadata       TEXT
+);
+
+CREATE INDEX IF NOT EXISTS dago_workers_type_idx ON dago_workers (type);
+CREATE INDEX IF NOT EXISTS dago_workers_expires_at_idx ON dago_workers (expires_at);
@@ -0,0 +1,57 @@
+package worker_registry
+
+import (
+	"context"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// AllWorkerStats holds worker statistics for every worker type.
+type AllWorkerStats struct {
+	// ByType has an entry for each type with at least one worker.
+	ByType map[ports.WorkerType]*ports.WorkerStats `json:"by_type"`
+
+	// Total aggregates all types. Its Type is empty.
+	Total ports.WorkerStats `json:"total"`
+}
+
+// GetAllWorkerStats computes statistics for all worker types from a single
+// ListWorkers call, instead of one GetWorkerStats call per type.
+func GetAllWorkerStats(ctx context.Context, registry ports.WorkerRegistry) (*AllWorkerStats, error) {
+	workers, err := registry.ListWorkers(ctx, ports.WorkerFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeWorkerStats(workers), nil
+}
+
+// ComputeWorkerStats aggregates already listed workers by type.
+func ComputeWorkerStats(workers []ports.WorkerInfo) *AllWorkerStats {
+	stats := &AllWorkerStats{
+		ByType: make(map[ports.WorkerType]*ports.WorkerStats),
+	}
+
+	for _, worker := range workers {
+		typeStats, ok := stats.ByType[worker.Type]
+		if !ok {
+			typeStats = &ports.WorkerStats{Type: worker.Type}
+			stats.ByType[worker.Type] = typeStats
+		}
+
+		for _, s := range []*ports.WorkerStats{typeStats, &stats.Total} {
+			s.TotalWorkers++
+			switch worker.Status {
+			case ports.WorkerStatusIdle:
+				s.IdleWorkers++
+			case ports.WorkerStatusBusy:
+				s.BusyWorkers++
+			case ports.WorkerStatusUnhealthy:
+				s.UnhealthyWorkers++
+			}
+			s.TotalPendingTasks += worker.PendingTasks
+		}
+	}
+
+	return stats
+}
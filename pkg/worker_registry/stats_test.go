@@ -0,0 +1,34 @@
+package worker_registry
+
+import (
+	"testing"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+func TestComputeWorkerStats(t *testing.T) {
+	workers := []ports.WorkerInfo{
+		{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle},
+		{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, PendingTasks: 3},
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusUnhealthy, PendingTasks: 1},
+	}
+
+	stats := ComputeWorkerStats(workers)
+
+	executor := stats.ByType[ports.WorkerTypeExecutor]
+	if executor == nil || executor.TotalWorkers != 2 || executor.IdleWorkers != 1 ||
+		executor.BusyWorkers != 1 || executor.TotalPendingTasks != 3 {
+		t.Errorf("executor stats = %+v", executor)
+	}
+
+	router := stats.ByType[ports.WorkerTypeRouter]
+	if router == nil || router.TotalWorkers != 1 || router.UnhealthyWorkers != 1 {
+		t.Errorf("router stats = %+v", router)
+	}
+
+	total := stats.Total
+	if total.TotalWorkers != 3 || total.IdleWorkers != 1 || total.BusyWorkers != 1 ||
+		total.UnhealthyWorkers != 1 || total.TotalPendingTasks != 4 {
+		t.Errorf("total stats = %+v", total)
+	}
+}
@@ -0,0 +1,67 @@
+package worker_registry
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// MetadataTTL is the metadata key holding a worker's own heartbeat TTL in
+// milliseconds. Milliseconds rather than a duration string so that server-side
+// scripts (e.g. Redis Lua) can read it.
+const MetadataTTL = "heartbeat_ttl_ms"
+
+// MetadataDeadline is the metadata key where registries expose the Unix time
+// in milliseconds at which they will expire the worker unless it heartbeats.
+const MetadataDeadline = "heartbeat_deadline_ms"
+
+// SetTTL declares the worker's own TTL, overriding the registry-wide one.
+// Slow workers (e.g. GPU workers busy loading models) can heartbeat less often
+// than lightweight routers.
+func SetTTL(worker *ports.WorkerInfo, ttl time.Duration) {
+	setMetadata(worker, MetadataTTL, ttl.Milliseconds())
+}
+
+// WorkerTTL returns the worker's declared TTL, or fallback if it has none.
+func WorkerTTL(worker ports.WorkerInfo, fallback time.Duration) time.Duration {
+	var ms int64
+	decodeMetadata(worker, MetadataTTL, &ms)
+	if ms <= 0 {
+		return fallback
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// HeartbeatInterval is how often the worker should heartbeat: a third of its
+// TTL, so that a single lost heartbeat doesn't make it unhealthy.
+func HeartbeatInterval(worker ports.WorkerInfo, fallbackTTL time.Duration) time.Duration {
+	return WorkerTTL(worker, fallbackTTL) / 3
+}
+
+// JitteredTTL extends ttl by a random fraction of up to jitter (0.1 for 10%),
+// so that workers registered together don't all expire at the same moment.
+// TTLs are only ever extended, never shortened.
+func JitteredTTL(ttl time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(float64(ttl)*jitter*rand.Float64())
+}
+
+// SetDeadline records the worker's effective expiry deadline. Registries call
+// it; workers don't.
+func SetDeadline(worker *ports.WorkerInfo, deadline time.Time) {
+	setMetadata(worker, MetadataDeadline, deadline.UnixMilli())
+}
+
+// Deadline returns when the registry will expire the worker unless it
+// heartbeats, if the registry exposes it.
+func Deadline(worker ports.WorkerInfo) (time.Time, bool) {
+	var ms int64
+	decodeMetadata(worker, MetadataDeadline, &ms)
+	if ms <= 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
@@ -0,0 +1,58 @@
+package worker_registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+func TestWorkerTTL(t *testing.T) {
+	worker := ports.WorkerInfo{ID: "executor-gpu"}
+	if got := WorkerTTL(worker, 30*time.Second); got != 30*time.Second {
+		t.Errorf("WorkerTTL() without declared TTL = %v, want fallback", got)
+	}
+
+	SetTTL(&worker, 90*time.Second)
+	if got := WorkerTTL(worker, 30*time.Second); got != 90*time.Second {
+		t.Errorf("WorkerTTL() = %v, want 90s", got)
+	}
+	if got := HeartbeatInterval(worker, 30*time.Second); got != 30*time.Second {
+		t.Errorf("HeartbeatInterval() = %v, want 30s", got)
+	}
+
+	// Stored by a backend and decoded back as a JSON number
+	worker.Metadata[MetadataTTL] = float64(90000)
+	if got := WorkerTTL(worker, 30*time.Second); got != 90*time.Second {
+		t.Errorf("WorkerTTL() after decoding = %v, want 90s", got)
+	}
+}
+
+func TestJitteredTTL(t *testing.T) {
+	if got := JitteredTTL(30*time.Second, 0); got != 30*time.Second {
+		t.Errorf("JitteredTTL() without jitter = %v, want 30s", got)
+	}
+
+	for range 100 {
+		got := JitteredTTL(30*time.Second, 0.2)
+		if got < 30*time.Second || got > 36*time.Second {
+			t.Fatalf("JitteredTTL() = %v, want within [30s, 36s]", got)
+		}
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	worker := ports.WorkerInfo{ID: "executor-1"}
+	if _, ok := Deadline(worker); ok {
+		t.Error("Deadline() reported a deadline that was never set")
+	}
+
+	deadline := time.UnixMilli(1760000000123)
+	SetDeadline(&worker, deadline)
+
+	// Stored by a backend and decoded back as a JSON number
+	worker.Metadata[MetadataDeadline] = float64(deadline.UnixMilli())
+	if got, ok := Deadline(worker); !ok || !got.Equal(deadline) {
+		t.Errorf("Deadline() = %v, %v, want %v", got, ok, deadline)
+	}
+}
@@ -0,0 +1,38 @@
+package worker_registry
+
+import (
+	"context"
+
+	"github.com/aescanero/dago-libs/pkg/ports"
+)
+
+// WatchEventType describes what happened to a worker in a WatchEvent.
+type WatchEventType string
+
+const (
+	// WatchEventPut is emitted when a worker is registered or updated.
+	WatchEventPut WatchEventType = "put"
+
+	// WatchEventDelete is emitted when a worker is unregistered or expires.
+	WatchEventDelete WatchEventType = "delete"
+)
+
+// WatchEvent is a single change to worker membership.
+type WatchEvent struct {
+	// Type is the kind of change.
+	Type WatchEventType `json:"type"`
+
+	// WorkerID is the ID of the worker that changed.
+	WorkerID string `json:"worker_id"`
+
+	// Worker is the new worker state. It is nil for delete events.
+	Worker *ports.WorkerInfo `json:"worker,omitempty"`
+}
+
+// Watcher is implemented by registries that can push membership changes
+// instead of requiring callers to poll ListWorkers.
+type Watcher interface {
+	// Watch streams worker changes until ctx is cancelled, at which point
+	// the returned channel is closed.
+	Watch(ctx context.Context) (<-chan WatchEvent, error)
+}
@@ -0,0 +1,30 @@
+// Package zookeeper provides a ZooKeeper implementation of the WorkerRegistry
+// interface using ephemeral znodes.
+//
+// This implementation is meant for users who already operate ZooKeeper (for
+// example for Kafka). Each worker is an ephemeral znode owned by the worker's
+// ZooKeeper session, so liveness is tied to the session rather than to
+// heartbeat-written TTLs: when a worker process dies or loses its session,
+// ZooKeeper removes its node after the session timeout.
+//
+// Key Design:
+//   - Worker data is stored as JSON in the ephemeral znode /dago/workers/{worker_id}
+//   - A worker is healthy as long as its node exists, and, if it declared
+//     its own TTL with worker_registry.SetTTL, heartbeats within it
+//   - Heartbeats only refresh status, current task and last heartbeat
+//   - Watch reports workers joining and leaving using child watches
+//
+// Because nodes are owned by the session that created them, each worker must
+// call Register (or Heartbeat) through its own connection.
+//
+// Usage:
+//
+//	conn, _, _ := zk.Connect([]string{"localhost:2181"}, 10*time.Second)
+//	registry := zookeeper.NewRegistry(conn, logger)
+//
+//	// Register a worker (the node lives as long as conn's session)
+//	registry.Register(ctx, worker)
+//
+//	// Update status as work is picked up
+//	registry.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-123")
+package zookeeper
@@ -0,0 +1,409 @@
+package zookeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/go-zookeeper/zk"
+	"go.uber.org/zap"
+)
+
+const (
+	// Parent znode of every worker node
+	defaultRootPath = "/dago/workers"
+)
+
+// Conn is the subset of the ZooKeeper client used by the registry.
+// It is satisfied by *zk.Conn.
+type Conn interface {
+	Create(path string, data []byte, flags int32, acl []zk.ACL) (string, error)
+	Get(path string) ([]byte, *zk.Stat, error)
+	Set(path string, data []byte, version int32) (*zk.Stat, error)
+	Delete(path string, version int32) error
+	Children(path string) ([]string, *zk.Stat, error)
+	ChildrenW(path string) ([]string, *zk.Stat, <-chan zk.Event, error)
+	SessionID() int64
+}
+
+// Registry implements ports.WorkerRegistry using ZooKeeper ephemeral znodes
+type Registry struct {
+	conn     Conn
+	logger   *zap.Logger
+	rootPath string
+}
+
+// NewRegistry creates a new ZooKeeper worker registry
+func NewRegistry(conn Conn, logger *zap.Logger) *Registry {
+	return &Registry{
+		conn:     conn,
+		logger:   logger,
+		rootPath: defaultRootPath,
+	}
+}
+
+// Register registers a new worker as an ephemeral node owned by this connection's session
+func (r *Registry) Register(ctx context.Context, worker ports.WorkerInfo) error {
+	if err := r.put(worker, nil); err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	r.logger.Info("worker registered",
+		zap.String("worker_id", worker.ID),
+		zap.String("type", string(worker.Type)),
+		zap.String("path", r.getWorkerPath(worker.ID)))
+
+	return nil
+}
+
+// Unregister removes a worker's node
+func (r *Registry) Unregister(ctx context.Context, workerID string) error {
+	if err := r.conn.Delete(r.getWorkerPath(workerID), -1); err != nil && !errors.Is(err, zk.ErrNoNode) {
+		return fmt.Errorf("failed to unregister worker: %w", err)
+	}
+
+	r.logger.Info("worker unregistered", zap.String("worker_id", workerID))
+	return nil
+}
+
+// Heartbeat updates the worker's status. Liveness itself comes from the session,
+// so this only needs to be called when status or current task change.
+func (r *Registry) Heartbeat(ctx context.Context, workerID string, status ports.WorkerStatus, currentTask string) error {
+	worker, stat, err := r.get(workerID)
+	if err != nil {
+		if !errors.Is(err, zk.ErrNoNode) {
+			return fmt.Errorf("failed to get worker: %w", err)
+		}
+
+		// Worker not found (session expired or never registered), recover
+		r.logger.Warn("heartbeat for unregistered worker, auto-registering",
+			zap.String("worker_id", workerID))
+
+		worker = &ports.WorkerInfo{
+			ID:           workerID,
+			Type:         registry.InferWorkerType(workerID),
+			RegisteredAt: time.Now(),
+		}
+	}
+
+	worker.Status = status
+	worker.LastHeartbeat = time.Now()
+	worker.CurrentTask = currentTask
+
+	if err := r.put(*worker, stat); err != nil {
+		return fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorker retrieves information about a specific worker
+func (r *Registry) GetWorker(ctx context.Context, workerID string) (*ports.WorkerInfo, error) {
+	worker, _, err := r.get(workerID)
+	if err != nil {
+		if errors.Is(err, zk.ErrNoNode) {
+			return nil, fmt.Errorf("worker not found: %s", workerID)
+		}
+		return nil, fmt.Errorf("failed to get worker: %w", err)
+	}
+
+	if !isHealthy(*worker) {
+		worker.Status = ports.WorkerStatusUnhealthy
+	}
+
+	return worker, nil
+}
+
+// ListWorkers retrieves all workers matching the filter criteria, ordered by ID.
+// Listed workers are healthy, since their node only exists while their session
+// is alive, unless they declared a TTL they have gone without heartbeats for.
+func (r *Registry) ListWorkers(ctx context.Context, filter ports.WorkerFilter) ([]ports.WorkerInfo, error) {
+	children, _, err := r.conn.Children(r.rootPath)
+	if err != nil {
+		if errors.Is(err, zk.ErrNoNode) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	sort.Strings(children)
+
+	var workers []ports.WorkerInfo
+
+	for _, child := range children {
+		worker, _, err := r.get(child)
+		if err != nil {
+			// The node may have vanished between Children and Get
+			if !errors.Is(err, zk.ErrNoNode) {
+				r.logger.Warn("failed to get worker",
+					zap.String("worker_id", child),
+					zap.Error(err))
+			}
+			continue
+		}
+
+		healthy := isHealthy(*worker)
+		if !healthy {
+			worker.Status = ports.WorkerStatusUnhealthy
+		}
+
+		// Apply filters
+		if !registry.MatchesFilter(*worker, filter, healthy) {
+			continue
+		}
+
+		workers = append(workers, *worker)
+	}
+
+	return workers, nil
+}
+
+// GetWorkerStats returns aggregate statistics about workers
+func (r *Registry) GetWorkerStats(ctx context.Context, workerType ports.WorkerType) (*ports.WorkerStats, error) {
+	workers, err := r.ListWorkers(ctx, ports.WorkerFilter{
+		Types: []ports.WorkerType{workerType},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.WorkerStats{
+		Type:         workerType,
+		TotalWorkers: len(workers),
+	}
+
+	for _, worker := range workers {
+		switch worker.Status {
+		case ports.WorkerStatusIdle:
+			stats.IdleWorkers++
+		case ports.WorkerStatusBusy:
+			stats.BusyWorkers++
+		case ports.WorkerStatusUnhealthy:
+			stats.UnhealthyWorkers++
+		}
+		stats.TotalPendingTasks += worker.PendingTasks
+	}
+
+	return stats, nil
+}
+
+// CleanupStaleWorkers removes workers that haven't updated their node within the timeout.
+// Dead sessions are already cleaned up by ZooKeeper; this catches workers whose
+// session is alive but which have stopped reporting (for example a hung process).
+func (r *Registry) CleanupStaleWorkers(ctx context.Context, timeout time.Duration) (int, error) {
+	children, _, err := r.conn.Children(r.rootPath)
+	if err != nil {
+		if errors.Is(err, zk.ErrNoNode) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	cleaned := 0
+
+	for _, child := range children {
+		worker, stat, err := r.get(child)
+		if err != nil {
+			continue
+		}
+
+		// Check if worker is stale
+		if time.Since(worker.LastHeartbeat) <= timeout {
+			continue
+		}
+
+		// Delete only the version we inspected, so a concurrent heartbeat wins
+		if err := r.conn.Delete(r.getWorkerPath(child), stat.Version); err != nil {
+			if !errors.Is(err, zk.ErrNoNode) && !errors.Is(err, zk.ErrBadVersion) {
+				r.logger.Warn("failed to delete stale worker",
+					zap.String("worker_id", child),
+					zap.Error(err))
+			}
+			continue
+		}
+
+		r.logger.Info("cleaned up stale worker",
+			zap.String("worker_id", child),
+			zap.Duration("idle_time", time.Since(worker.LastHeartbeat)))
+		cleaned++
+	}
+
+	return cleaned, nil
+}
+
+// Watch streams workers joining and leaving until ctx is cancelled.
+// Status changes of existing workers are not reported.
+func (r *Registry) Watch(ctx context.Context) (<-chan registry.WatchEvent, error) {
+	if err := r.ensureRoot(); err != nil {
+		return nil, fmt.Errorf("failed to watch workers: %w", err)
+	}
+
+	children, _, zkEvents, err := r.conn.ChildrenW(r.rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch workers: %w", err)
+	}
+
+	events := make(chan registry.WatchEvent)
+
+	go func() {
+		defer close(events)
+
+		known := make(map[string]bool, len(children))
+		for _, child := range children {
+			known[child] = true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-zkEvents:
+				if ev.Err != nil {
+					r.logger.Warn("worker watch failed", zap.Error(ev.Err))
+					return
+				}
+			}
+
+			// ZooKeeper watches fire once; re-arm and diff against the last listing
+			children, _, zkEvents, err = r.conn.ChildrenW(r.rootPath)
+			if err != nil {
+				r.logger.Warn("worker watch failed", zap.Error(err))
+				return
+			}
+
+			added, removed := diffChildren(known, children)
+
+			for _, id := range added {
+				event := registry.WatchEvent{Type: registry.WatchEventPut, WorkerID: id}
+				if worker, _, err := r.get(id); err == nil {
+					event.Worker = worker
+				}
+				if !r.send(ctx, events, event) {
+					return
+				}
+			}
+			for _, id := range removed {
+				if !r.send(ctx, events, registry.WatchEvent{Type: registry.WatchEventDelete, WorkerID: id}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Helper methods
+
+func (r *Registry) getWorkerPath(workerID string) string {
+	return r.rootPath + "/" + workerID
+}
+
+// isHealthy reports whether a worker whose node exists is healthy: workers
+// declaring their own TTL with worker_registry.SetTTL also have to heartbeat
+// within it, which catches hung processes whose session is still alive
+func isHealthy(worker ports.WorkerInfo) bool {
+	ttl := registry.WorkerTTL(worker, 0)
+	return ttl == 0 || time.Since(worker.LastHeartbeat) <= ttl
+}
+
+func (r *Registry) get(workerID string) (*ports.WorkerInfo, *zk.Stat, error) {
+	data, stat, err := r.conn.Get(r.getWorkerPath(workerID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var worker ports.WorkerInfo
+	if err := json.Unmarshal(data, &worker); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal worker info: %w", err)
+	}
+
+	return &worker, stat, nil
+}
+
+// put writes the worker's node. An existing node (stat) owned by this session is
+// updated in place; otherwise the node is (re)created so that this session owns
+// it and it isn't removed when some older session expires.
+func (r *Registry) put(worker ports.WorkerInfo, stat *zk.Stat) error {
+	data, err := json.Marshal(worker)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker info: %w", err)
+	}
+
+	workerPath := r.getWorkerPath(worker.ID)
+
+	if stat != nil && stat.EphemeralOwner == r.conn.SessionID() {
+		_, err := r.conn.Set(workerPath, data, -1)
+		if !errors.Is(err, zk.ErrNoNode) {
+			return err
+		}
+	}
+
+	_, err = r.conn.Create(workerPath, data, zk.FlagEphemeral, zk.WorldACL(zk.PermAll))
+	if errors.Is(err, zk.ErrNoNode) {
+		// Parent nodes are missing, create them once and retry
+		if err := r.ensureRoot(); err != nil {
+			return err
+		}
+		_, err = r.conn.Create(workerPath, data, zk.FlagEphemeral, zk.WorldACL(zk.PermAll))
+	}
+	if errors.Is(err, zk.ErrNodeExists) {
+		// Owned by another session, take it over
+		if err := r.conn.Delete(workerPath, -1); err != nil && !errors.Is(err, zk.ErrNoNode) {
+			return err
+		}
+		_, err = r.conn.Create(workerPath, data, zk.FlagEphemeral, zk.WorldACL(zk.PermAll))
+	}
+	return err
+}
+
+// ensureRoot creates the persistent parent nodes of the worker nodes
+func (r *Registry) ensureRoot() error {
+	current := ""
+	for _, part := range strings.Split(strings.Trim(r.rootPath, "/"), "/") {
+		current = path.Join("/", current, part)
+		_, err := r.conn.Create(current, nil, 0, zk.WorldACL(zk.PermAll))
+		if err != nil && !errors.Is(err, zk.ErrNodeExists) {
+			return fmt.Errorf("failed to create %s: %w", current, err)
+		}
+	}
+	return nil
+}
+
+func (r *Registry) send(ctx context.Context, events chan<- registry.WatchEvent, event registry.WatchEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// diffChildren updates known to match children and returns the added and
+// removed worker IDs, each sorted
+func diffChildren(known map[string]bool, children []string) (added, removed []string) {
+	current := make(map[string]bool, len(children))
+	for _, child := range children {
+		current[child] = true
+		if !known[child] {
+			added = append(added, child)
+			known[child] = true
+		}
+	}
+	for id := range known {
+		if !current[id] {
+			removed = append(removed, id)
+			delete(known, id)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
@@ -0,0 +1,394 @@
+package zookeeper
+
+import (
+	"context"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	registry "github.com/aescanero/dago-adapters/pkg/worker_registry"
+	"github.com/aescanero/dago-libs/pkg/ports"
+	"github.com/go-zookeeper/zk"
+	"go.uber.org/zap"
+)
+
+var _ Conn = (*zk.Conn)(nil)
+
+// fakeNode is a znode in fakeTree
+type fakeNode struct {
+	data    []byte
+	version int32
+	owner   int64
+}
+
+// fakeTree is an in-memory ZooKeeper namespace shared by several sessions
+type fakeTree struct {
+	mu       sync.Mutex
+	nodes    map[string]*fakeNode
+	watchers map[string][]chan zk.Event
+}
+
+func newFakeTree() *fakeTree {
+	return &fakeTree{
+		nodes:    map[string]*fakeNode{"/": {}},
+		watchers: map[string][]chan zk.Event{},
+	}
+}
+
+// expireSession removes the ephemeral nodes of a session, like ZooKeeper does
+func (t *fakeTree) expireSession(session int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for p, n := range t.nodes {
+		if n.owner == session {
+			delete(t.nodes, p)
+			t.fireLocked(path.Dir(p))
+		}
+	}
+}
+
+func (t *fakeTree) fireLocked(parent string) {
+	for _, w := range t.watchers[parent] {
+		w <- zk.Event{Type: zk.EventNodeChildrenChanged, Path: parent}
+	}
+	delete(t.watchers, parent)
+}
+
+// fakeConn is one session on a fakeTree
+type fakeConn struct {
+	tree    *fakeTree
+	session int64
+}
+
+func (c *fakeConn) SessionID() int64 { return c.session }
+
+func (c *fakeConn) Create(p string, data []byte, flags int32, acl []zk.ACL) (string, error) {
+	c.tree.mu.Lock()
+	defer c.tree.mu.Unlock()
+
+	if _, ok := c.tree.nodes[p]; ok {
+		return "", zk.ErrNodeExists
+	}
+	if _, ok := c.tree.nodes[path.Dir(p)]; !ok {
+		return "", zk.ErrNoNode
+	}
+
+	n := &fakeNode{data: data}
+	if flags&zk.FlagEphemeral != 0 {
+		n.owner = c.session
+	}
+	c.tree.nodes[p] = n
+	c.tree.fireLocked(path.Dir(p))
+	return p, nil
+}
+
+func (c *fakeConn) Get(p string) ([]byte, *zk.Stat, error) {
+	c.tree.mu.Lock()
+	defer c.tree.mu.Unlock()
+
+	n, ok := c.tree.nodes[p]
+	if !ok {
+		return nil, nil, zk.ErrNoNode
+	}
+	return n.data, &zk.Stat{Version: n.version, EphemeralOwner: n.owner}, nil
+}
+
+func (c *fakeConn) Set(p string, data []byte, version int32) (*zk.Stat, error) {
+	c.tree.mu.Lock()
+	defer c.tree.mu.Unlock()
+
+	n, ok := c.tree.nodes[p]
+	if !ok {
+		return nil, zk.ErrNoNode
+	}
+	if version != -1 && version != n.version {
+		return nil, zk.ErrBadVersion
+	}
+	n.data = data
+	n.version++
+	return &zk.Stat{Version: n.version, EphemeralOwner: n.owner}, nil
+}
+
+func (c *fakeConn) Delete(p string, version int32) error {
+	c.tree.mu.Lock()
+	defer c.tree.mu.Unlock()
+
+	n, ok := c.tree.nodes[p]
+	if !ok {
+		return zk.ErrNoNode
+	}
+	if version != -1 && version != n.version {
+		return zk.ErrBadVersion
+	}
+	delete(c.tree.nodes, p)
+	c.tree.fireLocked(path.Dir(p))
+	return nil
+}
+
+func (c *fakeConn) Children(p string) ([]string, *zk.Stat, error) {
+	c.tree.mu.Lock()
+	defer c.tree.mu.Unlock()
+	return c.childrenLocked(p)
+}
+
+func (c *fakeConn) ChildrenW(p string) ([]string, *zk.Stat, <-chan zk.Event, error) {
+	c.tree.mu.Lock()
+	defer c.tree.mu.Unlock()
+
+	children, stat, err := c.childrenLocked(p)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	w := make(chan zk.Event, 1)
+	c.tree.watchers[p] = append(c.tree.watchers[p], w)
+	return children, stat, w, nil
+}
+
+func (c *fakeConn) childrenLocked(p string) ([]string, *zk.Stat, error) {
+	if _, ok := c.tree.nodes[p]; !ok {
+		return nil, nil, zk.ErrNoNode
+	}
+	var children []string
+	for np := range c.tree.nodes {
+		if np != p && path.Dir(np) == p {
+			children = append(children, strings.TrimPrefix(np, p+"/"))
+		}
+	}
+	sort.Strings(children)
+	return children, &zk.Stat{}, nil
+}
+
+func TestRegistry_RegisterAndSessionExpiry(t *testing.T) {
+	ctx := context.Background()
+	tree := newFakeTree()
+	r := NewRegistry(&fakeConn{tree: tree, session: 1}, zap.NewNop())
+
+	err := r.Register(ctx, ports.WorkerInfo{
+		ID:            "executor-1",
+		Type:          ports.WorkerTypeExecutor,
+		Status:        ports.WorkerStatusIdle,
+		LastHeartbeat: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusBusy || worker.CurrentTask != "task-1" {
+		t.Errorf("GetWorker() = %+v, want busy on task-1", worker)
+	}
+
+	// Losing the session removes the worker, no TTL involved
+	tree.expireSession(1)
+	if _, err := r.GetWorker(ctx, "executor-1"); err == nil {
+		t.Error("GetWorker() after session expiry error = nil, want not found")
+	}
+}
+
+func TestRegistry_TakesOverNodeFromOldSession(t *testing.T) {
+	ctx := context.Background()
+	tree := newFakeTree()
+
+	old := NewRegistry(&fakeConn{tree: tree, session: 1}, zap.NewNop())
+	if err := old.Heartbeat(ctx, "router-1", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	// The restarted worker gets a new session before the old one expires
+	restarted := NewRegistry(&fakeConn{tree: tree, session: 2}, zap.NewNop())
+	if err := restarted.Heartbeat(ctx, "router-1", ports.WorkerStatusBusy, "task-2"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	tree.expireSession(1)
+
+	worker, err := restarted.GetWorker(ctx, "router-1")
+	if err != nil {
+		t.Fatalf("GetWorker() after old session expiry error = %v", err)
+	}
+	if worker.Type != ports.WorkerTypeRouter || worker.CurrentTask != "task-2" {
+		t.Errorf("GetWorker() = %+v, want router on task-2", worker)
+	}
+}
+
+func TestRegistry_ListStatsAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry(&fakeConn{tree: newFakeTree(), session: 1}, zap.NewNop())
+
+	now := time.Now()
+	workers := []ports.WorkerInfo{
+		{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+		{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, LastHeartbeat: now, PendingTasks: 5},
+		{ID: "executor-3", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, LastHeartbeat: now.Add(-time.Hour)},
+		{ID: "router-1", Type: ports.WorkerTypeRouter, Status: ports.WorkerStatusIdle, LastHeartbeat: now},
+	}
+	for _, w := range workers {
+		if err := r.Register(ctx, w); err != nil {
+			t.Fatalf("Register(%s) error = %v", w.ID, err)
+		}
+	}
+
+	got, err := r.ListWorkers(ctx, ports.WorkerFilter{Statuses: []ports.WorkerStatus{ports.WorkerStatusBusy}})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	var ids []string
+	for _, w := range got {
+		ids = append(ids, w.ID)
+	}
+	if want := []string{"executor-2", "executor-3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListWorkers() = %v, want %v", ids, want)
+	}
+
+	stats, err := r.GetWorkerStats(ctx, ports.WorkerTypeExecutor)
+	if err != nil {
+		t.Fatalf("GetWorkerStats() error = %v", err)
+	}
+	if stats.TotalWorkers != 3 || stats.BusyWorkers != 2 || stats.TotalPendingTasks != 5 {
+		t.Errorf("GetWorkerStats() = %+v", stats)
+	}
+
+	cleaned, err := r.CleanupStaleWorkers(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("CleanupStaleWorkers() error = %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("CleanupStaleWorkers() = %d, want 1", cleaned)
+	}
+}
+
+func TestRegistry_DeclaredTTL(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry(&fakeConn{tree: newFakeTree(), session: 1}, zap.NewNop())
+
+	hung := ports.WorkerInfo{ID: "executor-1", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusBusy, LastHeartbeat: time.Now().Add(-time.Minute)}
+	registry.SetTTL(&hung, 30*time.Second)
+	quiet := ports.WorkerInfo{ID: "executor-2", Type: ports.WorkerTypeExecutor, Status: ports.WorkerStatusIdle, LastHeartbeat: time.Now().Add(-time.Hour)}
+	for _, w := range []ports.WorkerInfo{hung, quiet} {
+		if err := r.Register(ctx, w); err != nil {
+			t.Fatalf("Register(%s) error = %v", w.ID, err)
+		}
+	}
+
+	// Past its own TTL, the live session doesn't keep executor-1 healthy
+	worker, err := r.GetWorker(ctx, "executor-1")
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusUnhealthy {
+		t.Errorf("GetWorker() status = %s, want unhealthy past its TTL", worker.Status)
+	}
+
+	// Without a TTL, only the session counts
+	got, err := r.ListWorkers(ctx, ports.WorkerFilter{HealthyOnly: true})
+	if err != nil {
+		t.Fatalf("ListWorkers() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "executor-2" {
+		t.Errorf("ListWorkers(HealthyOnly) = %+v, want executor-2", got)
+	}
+
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if worker, _ := r.GetWorker(ctx, "executor-1"); worker == nil || worker.Status != ports.WorkerStatusBusy {
+		t.Errorf("GetWorker() after a heartbeat = %+v, want busy", worker)
+	}
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tree := newFakeTree()
+	r := NewRegistry(&fakeConn{tree: tree, session: 1}, zap.NewNop())
+
+	events, err := r.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if err := r.Heartbeat(ctx, "executor-1", ports.WorkerStatusIdle, ""); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	expectEvent(t, events, registry.WatchEventPut, "executor-1")
+
+	tree.expireSession(1)
+	expectEvent(t, events, registry.WatchEventDelete, "executor-1")
+}
+
+func expectEvent(t *testing.T, events <-chan registry.WatchEvent, wantType registry.WatchEventType, wantID string) {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		if event.Type != wantType || event.WorkerID != wantID {
+			t.Errorf("Watch() event = %+v, want %s for %s", event, wantType, wantID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s event", wantType)
+	}
+}
+
+func TestDiffChildren(t *testing.T) {
+	known := map[string]bool{"a": true, "b": true}
+
+	added, removed := diffChildren(known, []string{"b", "d", "c"})
+
+	if want := []string{"c", "d"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := []string{"a"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	if want := map[string]bool{"b": true, "c": true, "d": true}; !reflect.DeepEqual(known, want) {
+		t.Errorf("known = %v, want %v", known, want)
+	}
+}
+
+// Integration test - only runs with ZK_SERVERS environment variable
+func TestRegistry_Integration(t *testing.T) {
+	servers := os.Getenv("ZK_SERVERS")
+	if servers == "" {
+		t.Skip("ZK_SERVERS not set, skipping integration test")
+	}
+
+	conn, _, err := zk.Connect(strings.Split(servers, ","), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	r := NewRegistry(conn, zap.NewNop())
+
+	workerID := "executor-zookeeper-integration"
+	if err := r.Heartbeat(ctx, workerID, ports.WorkerStatusBusy, "task-1"); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+
+	worker, err := r.GetWorker(ctx, workerID)
+	if err != nil {
+		t.Fatalf("GetWorker() error = %v", err)
+	}
+	if worker.Status != ports.WorkerStatusBusy {
+		t.Errorf("GetWorker() = %+v, want busy", worker)
+	}
+
+	if err := r.Unregister(ctx, workerID); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+}